@@ -18,6 +18,7 @@ import React from "react"
 import { connect } from "react-redux"
 import { Dropdown } from "react-bootstrap"
 import * as browserActions from "./actions"
+import * as objectsActions from "../objects/actions"
 import web from "../web"
 import history from "../history"
 import AboutModal from "./AboutModal"
@@ -53,6 +54,22 @@ export class BrowserDropdown extends React.Component {
       showChangePasswordModal: false
     })
   }
+  setEncryptionKey(e) {
+    e.preventDefault()
+    const { ssecKey, setSSECKey } = this.props
+    const key = window.prompt(
+      "Encryption key for SSE-C encrypted objects (32 characters), leave empty to clear",
+      ssecKey
+    )
+    if (key === null) {
+      return
+    }
+    if (key !== "" && Buffer.byteLength(key) !== 32) {
+      window.alert("The encryption key must be 32 bytes long")
+      return
+    }
+    setSSECKey(key)
+  }
   componentDidMount() {
     const { fetchServerInfo } = this.props
     fetchServerInfo()
@@ -129,6 +146,15 @@ export class BrowserDropdown extends React.Component {
                 />
               )}
             </li>
+            <li>
+              <a
+                href=""
+                id="set-encryption-key"
+                onClick={this.setEncryptionKey.bind(this)}
+              >
+                Encryption Key <i className="fas fa-key" />
+              </a>
+            </li>
             <li>
               <a href="" id="logout" onClick={this.logout}>
                 Sign Out <i className="fas fa-sign-out-alt" />
@@ -143,13 +169,15 @@ export class BrowserDropdown extends React.Component {
 
 const mapStateToProps = state => {
   return {
-    serverInfo: state.browser.serverInfo
+    serverInfo: state.browser.serverInfo,
+    ssecKey: state.objects.ssecKey
   }
 }
 
 const mapDispatchToProps = dispatch => {
   return {
-    fetchServerInfo: () => dispatch(browserActions.fetchServerInfo())
+    fetchServerInfo: () => dispatch(browserActions.fetchServerInfo()),
+    setSSECKey: ssecKey => dispatch(objectsActions.setSSECKey(ssecKey))
   }
 }
 
@@ -29,10 +29,34 @@ export class Login extends React.Component {
     super(props)
     this.state = {
       accessKey: "",
-      secretKey: ""
+      secretKey: "",
+      // Credentials of the user created by accepting an invite.
+      invited: null
     }
   }
 
+  // Invite links carry the invite token in the "invite" query parameter,
+  // accepting it creates a new user and logs it in.
+  acceptInvite(token) {
+    const { showAlert } = this.props
+    web
+      .AcceptInvite({ token })
+      .then(res => {
+        this.setState({
+          invited: { accessKey: res.accessKey, secretKey: res.secretKey }
+        })
+      })
+      .catch(e => {
+        showAlert("danger", e.message)
+      })
+  }
+
+  continueAfterInvite(event) {
+    event.preventDefault()
+    const { history } = this.props
+    history.push("/")
+  }
+
   // Handle field changes
   accessKeyChange(e) {
     this.setState({
@@ -83,18 +107,65 @@ export class Login extends React.Component {
     document.body.classList.add("is-guest")
   }
 
+  componentDidMount() {
+    const { location } = this.props
+    const token = new URLSearchParams(location ? location.search : "").get(
+      "invite"
+    )
+    if (token) {
+      this.acceptInvite(token)
+    }
+  }
+
   componentWillUnmount() {
     document.body.classList.remove("is-guest")
   }
 
   render() {
     const { clearAlert, alert } = this.props
-    if (web.LoggedIn()) {
+    const { invited } = this.state
+    if (web.LoggedIn() && !invited) {
       return <Redirect to={"/"} />
     }
     let alertBox = <Alert {...alert} onDismiss={clearAlert} />
     // Make sure you don't show a fading out alert box on the initial web-page load.
     if (!alert.message) alertBox = ""
+    if (invited) {
+      return (
+        <div className="login">
+          {alertBox}
+          <div className="l-wrap">
+            <form onSubmit={this.continueAfterInvite.bind(this)}>
+              <p>
+                Your account has been created. Keep these keys to log in
+                again, they are not shown anymore.
+              </p>
+              <InputGroup
+                value={invited.accessKey}
+                className="ig-dark"
+                label="Access Key"
+                id="accessKey"
+                name="username"
+                type="text"
+                readonly={true}
+              />
+              <InputGroup
+                value={invited.secretKey}
+                className="ig-dark"
+                label="Secret Key"
+                id="secretKey"
+                name="password"
+                type="text"
+                readonly={true}
+              />
+              <button className="lw-btn" type="submit">
+                <i className="fas fa-sign-in-alt" />
+              </button>
+            </form>
+          </div>
+        </div>
+      )
+    }
     return (
       <div className="login">
         {alertBox}
@@ -60,4 +60,21 @@ describe("BrowserDropdown", () => {
     wrapper.find("#logout").simulate("click", { preventDefault: jest.fn() })
     expect(window.location.pathname.endsWith("/login")).toBeTruthy()
   })
+
+  it("should set the encryption key when Encryption Key is clicked", () => {
+    const setSSECKey = jest.fn()
+    window.prompt = jest.fn(() => "32byteslongsecretkeymustbegiven1")
+    const wrapper = shallow(
+      <BrowserDropdown
+        serverInfo={serverInfo}
+        fetchServerInfo={jest.fn()}
+        ssecKey=""
+        setSSECKey={setSSECKey}
+      />
+    )
+    wrapper
+      .find("#set-encryption-key")
+      .simulate("click", { preventDefault: jest.fn() })
+    expect(setSSECKey).toHaveBeenCalledWith("32byteslongsecretkeymustbegiven1")
+  })
 })
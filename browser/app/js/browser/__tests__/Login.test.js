@@ -23,6 +23,14 @@ jest.mock('../../web', () => ({
   Login: jest.fn(() => {
     return Promise.resolve({ token: "test", uiVersion: "2018-02-01T01:17:47Z" })
   }),
+  AcceptInvite: jest.fn(() => {
+    return Promise.resolve({
+      token: "test",
+      accessKey: "invitedKey",
+      secretKey: "invitedSecret",
+      uiVersion: "2018-02-01T01:17:47Z"
+    })
+  }),
   LoggedIn: jest.fn()
 }))
 
@@ -102,4 +110,24 @@ describe("Login", () => {
       "password": "secretKey"
     })
   })
+
+  it("should accept the invite of an invite link", done => {
+    const wrapper = mount(
+      <Login
+        dispatch={dispatchMock}
+        alert={{ show: false, type: "danger"}}
+        showAlert={showAlertMock}
+        clearAlert={clearAlertMock}
+        location={{ search: "?invite=inviteToken" }}
+      />
+    )
+    expect(web.AcceptInvite).toHaveBeenCalledWith({ token: "inviteToken" })
+    setImmediate(() => {
+      expect(wrapper.state("invited")).toEqual({
+        accessKey: "invitedKey",
+        secretKey: "invitedSecret"
+      })
+      done()
+    })
+  })
 })
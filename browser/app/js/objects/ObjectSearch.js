@@ -0,0 +1,77 @@
+/*
+ * MinIO Cloud Storage (C) 2019 MinIO, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+import React from "react"
+import { connect } from "react-redux"
+import * as actionsObjects from "./actions"
+
+export const ObjectSearch = ({
+  searchResults,
+  searchObjects,
+  selectSearchResult
+}) => (
+  <div className="objects-search">
+    <div className="input-group ig-left ig-search" style={{ display: "block" }}>
+      <input
+        className="ig-text"
+        type="text"
+        onKeyPress={e => {
+          if (e.key === "Enter") {
+            searchObjects(e.target.value)
+          }
+        }}
+        placeholder="Search Objects..."
+      />
+      <i className="ig-helpers" />
+    </div>
+    {searchResults.length > 0 && (
+      <ul className="objects-search-results">
+        {searchResults.map(object => (
+          <li key={`${object.bucketName}/${object.name}`}>
+            <a
+              href=""
+              onClick={e => {
+                e.preventDefault()
+                selectSearchResult(object)
+              }}
+            >
+              {`${object.bucketName}/${object.name}`}
+            </a>
+          </li>
+        ))}
+      </ul>
+    )}
+  </div>
+)
+
+const mapStateToProps = state => {
+  return {
+    searchResults: state.objects.searchResults
+  }
+}
+
+const mapDispatchToProps = dispatch => {
+  return {
+    searchObjects: query => dispatch(actionsObjects.searchObjects(query)),
+    selectSearchResult: object =>
+      dispatch(actionsObjects.selectSearchResult(object))
+  }
+}
+
+export default connect(
+  mapStateToProps,
+  mapDispatchToProps
+)(ObjectSearch)
@@ -17,9 +17,12 @@
 import React from "react"
 import ObjectsHeader from "./ObjectsHeader"
 import ObjectsListContainer from "./ObjectsListContainer"
+import ObjectSearch from "./ObjectSearch"
+import web from "../web"
 
 export const ObjectsSection = () => (
   <div>
+    {web.LoggedIn() && <ObjectSearch />}
     <ObjectsHeader />
     <ObjectsListContainer />
   </div>
@@ -0,0 +1,50 @@
+/*
+ * MinIO Cloud Storage (C) 2019 MinIO, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+import React from "react"
+import { shallow } from "enzyme"
+import { ObjectSearch } from "../ObjectSearch"
+
+describe("ObjectSearch", () => {
+  it("should render without crashing", () => {
+    shallow(<ObjectSearch searchResults={[]} />)
+  })
+
+  it("should call searchObjects when enter is pressed", () => {
+    const searchObjects = jest.fn()
+    const wrapper = shallow(
+      <ObjectSearch searchResults={[]} searchObjects={searchObjects} />
+    )
+    wrapper
+      .find("input")
+      .simulate("keyPress", { key: "Enter", target: { value: "invoice" } })
+    expect(searchObjects).toHaveBeenCalledWith("invoice")
+  })
+
+  it("should call selectSearchResult when a result is clicked", () => {
+    const selectSearchResult = jest.fn()
+    const object = { bucketName: "bk1", name: "2019/invoice-2019.pdf" }
+    const wrapper = shallow(
+      <ObjectSearch
+        searchResults={[object]}
+        selectSearchResult={selectSearchResult}
+      />
+    )
+    expect(wrapper.find("a").text()).toBe("bk1/2019/invoice-2019.pdf")
+    wrapper.find("a").simulate("click", { preventDefault: jest.fn() })
+    expect(selectSearchResult).toHaveBeenCalledWith(object)
+  })
+})
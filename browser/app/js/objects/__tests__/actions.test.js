@@ -51,8 +51,26 @@ jest.mock("../../web", () => ({
     if (!bucketName) {
       return Promise.reject({ message: "Invalid bucket" })
     }
+    if (objects[0] === "pre1/worm") {
+      return Promise.resolve({
+        errors: [
+          {
+            objectName: "pre1/worm",
+            reason: "Object is WORM protected and cannot be removed"
+          }
+        ]
+      })
+    }
     return Promise.resolve({})
   }),
+  Search: jest.fn(({ query }) => {
+    if (query === "denied") {
+      return Promise.reject({ message: "search index is not enabled" })
+    }
+    return Promise.resolve({
+      objects: [{ bucketName: "bk1", name: "2019/invoice-2019.pdf" }]
+    })
+  }),
   PresignedGet: jest.fn(({ bucket, object }) => {
     if (!bucket) {
       return Promise.reject({ message: "Invalid bucket" })
@@ -70,6 +88,12 @@ jest.mock("../../web", () => ({
     .mockImplementationOnce(() => {
       return Promise.resolve({ token: "test" })
     })
+    .mockImplementationOnce(() => {
+      return Promise.resolve({ token: "test" })
+    })
+    .mockImplementationOnce(() => {
+      return Promise.resolve({ token: "test" })
+    })
 }))
 
 const middlewares = [thunk]
@@ -288,6 +312,27 @@ describe("Objects actions", () => {
     })
   })
 
+  it("creates alert/SET action when the object is retained", () => {
+    const store = mockStore({
+      buckets: { currentBucket: "test" },
+      objects: { currentPrefix: "pre1/" }
+    })
+    const expectedActions = [
+      {
+        type: "alert/SET",
+        alert: {
+          type: "danger",
+          message: "Object is WORM protected and cannot be removed",
+          id: alertActions.alertId
+        }
+      }
+    ]
+    return store.dispatch(actionsObjects.deleteObject("worm")).then(() => {
+      const actions = store.getActions()
+      expect(actions).toEqual(expectedActions)
+    })
+  })
+
   it("creates objects/SET_SHARE_OBJECT action for showShareObject", () => {
     const store = mockStore()
     const expectedActions = [
@@ -507,4 +552,164 @@ describe("Objects actions", () => {
       )
     })
   })
+
+  it("creates objects/SET_SSEC_KEY action", () => {
+    const store = mockStore()
+    const expectedActions = [
+      {
+        type: "objects/SET_SSEC_KEY",
+        ssecKey: "32byteslongsecretkeymustbegiven1"
+      }
+    ]
+    store.dispatch(
+      actionsObjects.setSSECKey("32byteslongsecretkeymustbegiven1")
+    )
+    const actions = store.getActions()
+    expect(actions).toEqual(expectedActions)
+  })
+
+  it("should return SSE-C headers for the key", () => {
+    expect(
+      actionsObjects.getSSECHeaders("32byteslongsecretkeymustbegiven1")
+    ).toEqual({
+      "X-Amz-Server-Side-Encryption-Customer-Algorithm": "AES256",
+      "X-Amz-Server-Side-Encryption-Customer-Key":
+        "MzJieXRlc2xvbmdzZWNyZXRrZXltdXN0YmVnaXZlbjE=",
+      "X-Amz-Server-Side-Encryption-Customer-Key-MD5":
+        "4xSRdYsabg+s2nlsHKhgnw=="
+    })
+  })
+
+  it("should download the object with SSE-C headers", () => {
+    const open = jest.fn()
+    const send = jest.fn()
+    const setRequestHeader = jest.fn()
+    const xhrMockClass = () => ({
+      open: open,
+      send: send,
+      setRequestHeader: setRequestHeader
+    })
+    window.XMLHttpRequest = jest.fn().mockImplementation(xhrMockClass)
+
+    const store = mockStore({
+      buckets: { currentBucket: "bk1" },
+      objects: {
+        currentPrefix: "pre1/",
+        ssecKey: "32byteslongsecretkeymustbegiven1"
+      }
+    })
+    return store.dispatch(actionsObjects.downloadObject("obj1")).then(() => {
+      const requestUrl = `${
+        window.location.origin
+      }${minioBrowserPrefix}/download/bk1/${encodeURI(
+        "pre1/obj1"
+      )}?token=test`
+      expect(open).toHaveBeenCalledWith("GET", requestUrl, true)
+      expect(setRequestHeader).toHaveBeenCalledWith(
+        "X-Amz-Server-Side-Encryption-Customer-Algorithm",
+        "AES256"
+      )
+      expect(send).toHaveBeenCalledWith(null)
+    })
+  })
+
+  it("should download checked objects with SSE-C headers", () => {
+    const open = jest.fn()
+    const send = jest.fn()
+    const setRequestHeader = jest.fn()
+    const xhrMockClass = () => ({
+      open: open,
+      send: send,
+      setRequestHeader: setRequestHeader
+    })
+    window.XMLHttpRequest = jest.fn().mockImplementation(xhrMockClass)
+
+    const store = mockStore({
+      buckets: { currentBucket: "bk1" },
+      objects: {
+        currentPrefix: "pre1/",
+        checkedList: ["obj1"],
+        ssecKey: "32byteslongsecretkeymustbegiven1"
+      }
+    })
+    return store.dispatch(actionsObjects.downloadCheckedObjects()).then(() => {
+      const requestUrl = `${
+        location.origin
+      }${minioBrowserPrefix}/zip?token=test`
+      expect(open).toHaveBeenCalledWith("POST", requestUrl, true)
+      expect(setRequestHeader).toHaveBeenCalledWith(
+        "X-Amz-Server-Side-Encryption-Customer-Key-MD5",
+        "4xSRdYsabg+s2nlsHKhgnw=="
+      )
+    })
+  })
+
+  it("should search objects", () => {
+    const store = mockStore()
+    const expectedActions = [
+      {
+        type: "objects/SET_SEARCH_RESULTS",
+        searchResults: [{ bucketName: "bk1", name: "2019/invoice-2019.pdf" }]
+      }
+    ]
+    return store.dispatch(actionsObjects.searchObjects("invoice")).then(() => {
+      const actions = store.getActions()
+      expect(actions).toEqual(expectedActions)
+    })
+  })
+
+  it("should clear search results for an empty query", () => {
+    const store = mockStore()
+    const expectedActions = [
+      {
+        type: "objects/SET_SEARCH_RESULTS",
+        searchResults: []
+      }
+    ]
+    return store.dispatch(actionsObjects.searchObjects("")).then(() => {
+      const actions = store.getActions()
+      expect(actions).toEqual(expectedActions)
+    })
+  })
+
+  it("creates alert/SET action when search fails", () => {
+    const store = mockStore()
+    const expectedActions = [
+      {
+        type: "alert/SET",
+        alert: {
+          type: "danger",
+          message: "search index is not enabled",
+          id: alertActions.alertId
+        }
+      }
+    ]
+    return store.dispatch(actionsObjects.searchObjects("denied")).then(() => {
+      const actions = store.getActions()
+      expect(actions).toEqual(expectedActions)
+    })
+  })
+
+  it("should select the bucket and prefix of a search result", () => {
+    const store = mockStore({
+      buckets: { currentBucket: "bk1" },
+      objects: { currentPrefix: "2019/" }
+    })
+    const expectedActions = [
+      {
+        type: "objects/SET_SEARCH_RESULTS",
+        searchResults: []
+      },
+      { type: "buckets/SET_CURRENT_BUCKET", bucket: "bk1" },
+      { type: "objects/SET_CURRENT_PREFIX", prefix: "2019/" }
+    ]
+    store.dispatch(
+      actionsObjects.selectSearchResult({
+        bucketName: "bk1",
+        name: "2019/invoice-2019.pdf"
+      })
+    )
+    const actions = store.getActions()
+    expect(actions.slice(0, 3)).toEqual(expectedActions)
+  })
 })
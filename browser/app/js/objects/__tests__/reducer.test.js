@@ -33,7 +33,9 @@ describe("objects reducer", () => {
         object: "",
         url: ""
       },
-      checkedList: []
+      checkedList: [],
+      ssecKey: "",
+      searchResults: []
     })
   })
 
@@ -144,4 +146,20 @@ describe("objects reducer", () => {
     )
     expect(newState.checkedList).toEqual([])
   })
+
+  it("should handle SET_SSEC_KEY", () => {
+    const newState = reducer(undefined, {
+      type: actions.SET_SSEC_KEY,
+      ssecKey: "32byteslongsecretkeymustbegiven1"
+    })
+    expect(newState.ssecKey).toEqual("32byteslongsecretkeymustbegiven1")
+  })
+
+  it("should handle SET_SEARCH_RESULTS", () => {
+    const newState = reducer(undefined, {
+      type: actions.SET_SEARCH_RESULTS,
+      searchResults: [{ bucketName: "bk1", name: "obj1" }]
+    })
+    expect(newState.searchResults).toEqual([{ bucketName: "bk1", name: "obj1" }])
+  })
 })
@@ -14,6 +14,7 @@
  * limitations under the License.
  */
 
+import crypto from "crypto"
 import web from "../web"
 import history from "../history"
 import {
@@ -22,7 +23,7 @@ import {
   sortObjectsByDate
 } from "../utils"
 import { getCurrentBucket } from "../buckets/selectors"
-import { getCurrentPrefix, getCheckedList } from "./selectors"
+import { getCurrentPrefix, getCheckedList, getSSECKey } from "./selectors"
 import * as alertActions from "../alert/actions"
 import * as bucketActions from "../buckets/actions"
 import {
@@ -47,6 +48,8 @@ export const CHECKED_LIST_ADD = "objects/CHECKED_LIST_ADD"
 export const CHECKED_LIST_REMOVE = "objects/CHECKED_LIST_REMOVE"
 export const CHECKED_LIST_RESET = "objects/CHECKED_LIST_RESET"
 export const SET_LIST_LOADING = "objects/SET_LIST_LOADING"
+export const SET_SSEC_KEY = "objects/SET_SSEC_KEY"
+export const SET_SEARCH_RESULTS = "objects/SET_SEARCH_RESULTS"
 
 export const setList = objects => ({
   type: SET_LIST,
@@ -191,7 +194,20 @@ export const deleteObject = object => {
         bucketName: currentBucket,
         objects: [objectName]
       })
-      .then(() => {
+      .then(res => {
+        if (res.errors && res.errors.length > 0) {
+          // Object is retained and was not removed.
+          const err = res.errors[0]
+          dispatch(
+            alertActions.set({
+              type: "danger",
+              message: err.retainUntil
+                ? `${err.reason} until ${err.retainUntil}`
+                : err.reason
+            })
+          )
+          return
+        }
         dispatch(removeObject(object))
       })
       .catch(e => {
@@ -286,12 +302,61 @@ export const hideShareObject = (object, url) => ({
   url: ""
 })
 
+export const setSearchResults = searchResults => ({
+  type: SET_SEARCH_RESULTS,
+  searchResults
+})
+
+export const searchObjects = query => {
+  return function(dispatch) {
+    if (!query) {
+      dispatch(setSearchResults([]))
+      return Promise.resolve()
+    }
+    return web
+      .Search({ query })
+      .then(res => {
+        dispatch(setSearchResults(res.objects || []))
+      })
+      .catch(err => {
+        dispatch(
+          alertActions.set({
+            type: "danger",
+            message: err.message
+          })
+        )
+      })
+  }
+}
+
+export const selectSearchResult = object => {
+  return function(dispatch) {
+    const prefix = object.name.substring(0, object.name.lastIndexOf("/") + 1)
+    dispatch(setSearchResults([]))
+    dispatch(bucketActions.selectBucket(object.bucketName, prefix))
+  }
+}
+
+export const setSSECKey = ssecKey => ({
+  type: SET_SSEC_KEY,
+  ssecKey
+})
+
 export const downloadObject = object => {
   return function(dispatch, getState) {
     const currentBucket = getCurrentBucket(getState())
     const currentPrefix = getCurrentPrefix(getState())
+    const ssecKey = getSSECKey(getState())
     const objectName = `${currentPrefix}${object}`
     const encObjectName = encodeURI(objectName)
+    const download = url => {
+      if (ssecKey) {
+        // Headers can't be set on a navigation, fetch the object instead.
+        downloadBlob("GET", url, null, object, ssecKey)
+      } else {
+        window.location = url
+      }
+    }
     if (web.LoggedIn()) {
       return web
         .CreateURLToken()
@@ -301,7 +366,7 @@ export const downloadObject = object => {
           }${minioBrowserPrefix}/download/${currentBucket}/${encObjectName}?token=${
             res.token
           }`
-          window.location = url
+          download(url)
         })
         .catch(err => {
           dispatch(
@@ -315,7 +380,7 @@ export const downloadObject = object => {
       const url = `${
         window.location.origin
       }${minioBrowserPrefix}/download/${currentBucket}/${encObjectName}?token=`
-      window.location = url
+      download(url)
     }
   }
 }
@@ -344,7 +409,7 @@ export const downloadCheckedObjects = () => {
     }
     if (!web.LoggedIn()) {
       const requestUrl = location.origin + "/minio/zip?token="
-      downloadZip(requestUrl, req, dispatch)
+      downloadZip(requestUrl, req, getSSECKey(state), dispatch)
     } else {
       return web
         .CreateURLToken()
@@ -352,7 +417,7 @@ export const downloadCheckedObjects = () => {
           const requestUrl = `${
             location.origin
           }${minioBrowserPrefix}/zip?token=${res.token}`
-          downloadZip(requestUrl, req, dispatch)
+          downloadZip(requestUrl, req, getSSECKey(state), dispatch)
         })
         .catch(err =>
           dispatch(
@@ -366,31 +431,59 @@ export const downloadCheckedObjects = () => {
   }
 }
 
-const downloadZip = (url, req, dispatch) => {
+const downloadZip = (url, req, ssecKey, dispatch) => {
+  var separator = req.prefix.length > 1 ? "-" : ""
+  var fileName = req.bucketName + separator + req.prefix.slice(0, -1) + ".zip"
+  downloadBlob("POST", url, JSON.stringify(req), fileName, ssecKey, () =>
+    dispatch(resetCheckedList())
+  )
+}
+
+// getSSECHeaders returns the SSE-C request headers for the given
+// customer key, the key is used as is and must be 32 bytes long.
+export const getSSECHeaders = ssecKey => {
+  const key = Buffer.from(ssecKey)
+  return {
+    "X-Amz-Server-Side-Encryption-Customer-Algorithm": "AES256",
+    "X-Amz-Server-Side-Encryption-Customer-Key": key.toString("base64"),
+    "X-Amz-Server-Side-Encryption-Customer-Key-MD5": crypto
+      .createHash("md5")
+      .update(key)
+      .digest("base64")
+  }
+}
+
+const downloadBlob = (method, url, body, fileName, ssecKey, onSuccess) => {
   var anchor = document.createElement("a")
   document.body.appendChild(anchor)
 
   var xhr = new XMLHttpRequest()
-  xhr.open("POST", url, true)
+  xhr.open(method, url, true)
   xhr.responseType = "blob"
+  if (ssecKey) {
+    const headers = getSSECHeaders(ssecKey)
+    Object.keys(headers).forEach(name =>
+      xhr.setRequestHeader(name, headers[name])
+    )
+  }
 
   xhr.onload = function(e) {
     if (this.status == 200) {
-      dispatch(resetCheckedList())
+      if (onSuccess) {
+        onSuccess()
+      }
       var blob = new Blob([this.response], {
         type: "octet/stream"
       })
       var blobUrl = window.URL.createObjectURL(blob)
-      var separator = req.prefix.length > 1 ? "-" : ""
 
       anchor.href = blobUrl
-      anchor.download =
-        req.bucketName + separator + req.prefix.slice(0, -1) + ".zip"
+      anchor.download = fileName
 
       anchor.click()
       window.URL.revokeObjectURL(blobUrl)
       anchor.remove()
     }
   }
-  xhr.send(JSON.stringify(req))
+  xhr.send(body)
 }
@@ -38,7 +38,9 @@ export default (
       object: "",
       url: ""
     },
-    checkedList: []
+    checkedList: [],
+    ssecKey: "",
+    searchResults: []
   },
   action
 ) => {
@@ -111,6 +113,16 @@ export default (
         ...state,
         checkedList: []
       }
+    case actionsObjects.SET_SSEC_KEY:
+      return {
+        ...state,
+        ssecKey: action.ssecKey
+      }
+    case actionsObjects.SET_SEARCH_RESULTS:
+      return {
+        ...state,
+        searchResults: action.searchResults
+      }
     default:
       return state
   }
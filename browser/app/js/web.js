@@ -69,6 +69,13 @@ class Web {
         return res
       })
   }
+  AcceptInvite(args) {
+    return this.makeCall('AcceptInvite', args)
+      .then(res => {
+        storage.setItem('token', `${res.token}`)
+        return res
+      })
+  }
   Logout() {
     storage.removeItem('token')
   }
@@ -93,6 +100,12 @@ class Web {
   ListObjects(args) {
     return this.makeCall('ListObjects', args)
   }
+  ListObjectVersions(args) {
+    return this.makeCall('ListObjectVersions', args)
+  }
+  PrefixStat(args) {
+    return this.makeCall('PrefixStat', args)
+  }
   PresignedGet(args) {
     return this.makeCall('PresignedGet', args)
   }
@@ -102,6 +115,9 @@ class Web {
   RemoveObject(args) {
     return this.makeCall('RemoveObject', args)
   }
+  Search(args) {
+    return this.makeCall('Search', args)
+  }
   SetAuth(args) {
     return this.makeCall('SetAuth', args)
       .then(res => {
@@ -109,6 +125,9 @@ class Web {
         return res
       })
   }
+  CreateInvite(args) {
+    return this.makeCall('CreateInvite', args)
+  }
   CreateURLToken() {
     return this.makeCall('CreateURLToken')
   }
@@ -0,0 +1,108 @@
+/*
+ * MinIO Cloud Storage, (C) 2020 MinIO, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"sort"
+	"sync"
+
+	"github.com/minio/minio/pkg/madmin"
+)
+
+// accessKeyUsage - number of authenticated requests and time of the
+// last one of each access key, counted by each server separately
+// since it started.
+type accessKeyUsage struct {
+	mu   sync.Mutex
+	keys map[string]madmin.AccessKeyUsage
+}
+
+func newAccessKeyUsage() *accessKeyUsage {
+	return &accessKeyUsage{keys: make(map[string]madmin.AccessKeyUsage)}
+}
+
+// record - counts a request authenticated with accessKey.
+func (u *accessKeyUsage) record(accessKey string) {
+	if accessKey == "" {
+		return
+	}
+	u.mu.Lock()
+	defer u.mu.Unlock()
+
+	usage := u.keys[accessKey]
+	usage.AccessKey = accessKey
+	usage.Requests++
+	usage.LastUsed = UTCNow()
+	u.keys[accessKey] = usage
+}
+
+// List - returns the usage of the access keys used on this server.
+func (u *accessKeyUsage) List() []madmin.AccessKeyUsage {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+
+	usages := make([]madmin.AccessKeyUsage, 0, len(u.keys))
+	for _, usage := range u.keys {
+		usages = append(usages, usage)
+	}
+	return usages
+}
+
+// mergeAccessKeyUsage - sums up the usage of the access keys on all
+// servers, users which were never used are added with no requests.
+// The type of each access key is set, the least recently used access
+// keys are returned first.
+func mergeAccessKeyUsage(usages []madmin.AccessKeyUsage, rootAccessKey string, users map[string]madmin.UserInfo) []madmin.AccessKeyUsage {
+	merged := make(map[string]madmin.AccessKeyUsage)
+	for _, usage := range usages {
+		m := merged[usage.AccessKey]
+		m.AccessKey = usage.AccessKey
+		m.Requests += usage.Requests
+		if usage.LastUsed.After(m.LastUsed) {
+			m.LastUsed = usage.LastUsed
+		}
+		merged[usage.AccessKey] = m
+	}
+	for accessKey := range users {
+		if _, ok := merged[accessKey]; !ok {
+			merged[accessKey] = madmin.AccessKeyUsage{AccessKey: accessKey}
+		}
+	}
+	if _, ok := merged[rootAccessKey]; !ok {
+		merged[rootAccessKey] = madmin.AccessKeyUsage{AccessKey: rootAccessKey}
+	}
+
+	result := make([]madmin.AccessKeyUsage, 0, len(merged))
+	for accessKey, usage := range merged {
+		switch _, ok := users[accessKey]; {
+		case accessKey == rootAccessKey:
+			usage.Type = madmin.AccessKeyRoot
+		case ok:
+			usage.Type = madmin.AccessKeyUser
+		default:
+			usage.Type = madmin.AccessKeyTemporary
+		}
+		result = append(result, usage)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		if !result[i].LastUsed.Equal(result[j].LastUsed) {
+			return result[i].LastUsed.Before(result[j].LastUsed)
+		}
+		return result[i].AccessKey < result[j].AccessKey
+	})
+	return result
+}
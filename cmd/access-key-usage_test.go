@@ -0,0 +1,64 @@
+/*
+ * MinIO Cloud Storage, (C) 2020 MinIO, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"testing"
+
+	"github.com/minio/minio/pkg/madmin"
+)
+
+func TestAccessKeyUsage(t *testing.T) {
+	local := newAccessKeyUsage()
+	local.record("root")
+	local.record("user1")
+	local.record("user1")
+	local.record("")
+
+	peer := newAccessKeyUsage()
+	peer.record("user1")
+	peer.record("temp1")
+
+	usages := append(local.List(), peer.List()...)
+	users := map[string]madmin.UserInfo{
+		"user1": {Status: madmin.AccountEnabled},
+		"user2": {Status: madmin.AccountEnabled},
+	}
+	merged := mergeAccessKeyUsage(usages, "root", users)
+	if len(merged) != 4 {
+		t.Fatalf("Expected 4 access keys, got %v", merged)
+	}
+
+	// The unused user is listed first.
+	if merged[0].AccessKey != "user2" || merged[0].Type != madmin.AccessKeyUser || merged[0].Requests != 0 || !merged[0].LastUsed.IsZero() {
+		t.Fatalf("Unexpected usage of the unused user %+v", merged[0])
+	}
+	expected := map[string]struct {
+		typ      string
+		requests uint64
+	}{
+		"root":  {madmin.AccessKeyRoot, 1},
+		"user1": {madmin.AccessKeyUser, 3},
+		"temp1": {madmin.AccessKeyTemporary, 1},
+	}
+	for _, usage := range merged[1:] {
+		e, ok := expected[usage.AccessKey]
+		if !ok || usage.Type != e.typ || usage.Requests != e.requests || usage.LastUsed.IsZero() {
+			t.Errorf("Unexpected usage %+v", usage)
+		}
+	}
+}
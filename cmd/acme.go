@@ -0,0 +1,222 @@
+/*
+ * MinIO Cloud Storage, (C) 2020 MinIO, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"context"
+	"crypto/tls"
+	"net"
+	"path"
+	"path/filepath"
+	"strings"
+
+	xhttp "github.com/minio/minio/cmd/http"
+	"github.com/minio/minio/cmd/logger"
+	"github.com/minio/minio/pkg/certs"
+	"golang.org/x/crypto/acme"
+	"golang.org/x/crypto/acme/autocert"
+)
+
+const (
+	// Directory below the certs directory holding the certificates
+	// obtained through ACME.
+	acmeCertsDir = "acme"
+
+	// Prefix of the certificates obtained through ACME in the backend
+	// and in etcd.
+	acmeConfigPrefix = minioConfigPrefix + "/acme"
+)
+
+// acmeCache stores the ACME account key, the certificates and the
+// challenge certificates in the certs directory of the server, and
+// shares them with the other servers through etcd if configured, or
+// through the backend otherwise. Certificates are looked up locally
+// first since the backend is not available while the servers of a
+// distributed setup connect to each other at startup.
+type acmeCache struct {
+	dir autocert.DirCache
+}
+
+func newACMECache(certsDir string) acmeCache {
+	return acmeCache{dir: autocert.DirCache(filepath.Join(certsDir, acmeCertsDir))}
+}
+
+// Get returns the entry of the given key, from the local directory
+// or from the shared store.
+func (c acmeCache) Get(ctx context.Context, key string) ([]byte, error) {
+	data, err := c.dir.Get(ctx, key)
+	if err != autocert.ErrCacheMiss {
+		return data, err
+	}
+
+	data, err = c.getShared(ctx, key)
+	if err != nil {
+		if err == errConfigNotFound || err == errServerNotInitialized {
+			return nil, autocert.ErrCacheMiss
+		}
+		return nil, err
+	}
+	logger.LogIf(ctx, c.dir.Put(ctx, key, data))
+	return data, nil
+}
+
+// Put saves the entry of the given key in the local directory and in
+// the shared store if available.
+func (c acmeCache) Put(ctx context.Context, key string, data []byte) error {
+	if err := c.dir.Put(ctx, key, data); err != nil {
+		return err
+	}
+	if err := c.putShared(ctx, key, data); err != nil && err != errServerNotInitialized {
+		return err
+	}
+	return nil
+}
+
+// Delete removes the entry of the given key from the local directory
+// and from the shared store if available.
+func (c acmeCache) Delete(ctx context.Context, key string) error {
+	if err := c.dir.Delete(ctx, key); err != nil {
+		return err
+	}
+	if err := c.deleteShared(ctx, key); err != nil && err != errServerNotInitialized && err != errConfigNotFound {
+		return err
+	}
+	return nil
+}
+
+func (c acmeCache) getShared(ctx context.Context, key string) ([]byte, error) {
+	configFile := path.Join(acmeConfigPrefix, key)
+	if globalEtcdClient != nil {
+		return readKeyEtcd(ctx, globalEtcdClient, configFile)
+	}
+	objAPI := c.sharedObjectLayer()
+	if objAPI == nil {
+		return nil, errServerNotInitialized
+	}
+	return readConfig(ctx, objAPI, configFile)
+}
+
+func (c acmeCache) putShared(ctx context.Context, key string, data []byte) error {
+	configFile := path.Join(acmeConfigPrefix, key)
+	if globalEtcdClient != nil {
+		return saveKeyEtcd(ctx, globalEtcdClient, configFile, data)
+	}
+	objAPI := c.sharedObjectLayer()
+	if objAPI == nil {
+		return errServerNotInitialized
+	}
+	return saveConfig(ctx, objAPI, configFile, data)
+}
+
+func (c acmeCache) deleteShared(ctx context.Context, key string) error {
+	configFile := path.Join(acmeConfigPrefix, key)
+	if globalEtcdClient != nil {
+		return deleteKeyEtcd(ctx, globalEtcdClient, configFile)
+	}
+	objAPI := c.sharedObjectLayer()
+	if objAPI == nil {
+		return errServerNotInitialized
+	}
+	err := deleteConfig(ctx, objAPI, configFile)
+	if isErrObjectNotFound(err) {
+		return errConfigNotFound
+	}
+	return err
+}
+
+// Returns the backend holding the shared entries, nil if it is not
+// initialized yet. Gateways do not store entries in their backend.
+func (c acmeCache) sharedObjectLayer() ObjectLayer {
+	if globalIsGateway {
+		return nil
+	}
+	return newObjectLayerFn()
+}
+
+// Parses given acmeDomainsEnv of the form "example.com,www.example.com"
+// and returns the list of domains to obtain certificates for.
+func parseACMEDomains(acmeDomainsEnv string) ([]string, error) {
+	var domains []string
+	for _, domain := range strings.Split(acmeDomainsEnv, ",") {
+		domain = strings.ToLower(strings.TrimSpace(domain))
+		if net.ParseIP(domain) != nil || strings.Contains(domain, "*") || !strings.Contains(strings.Trim(domain, "."), ".") {
+			return nil, uiErrInvalidACMEDomainsValue(nil).Msg("ACME domain (%s) should be a fully qualified domain name", domain)
+		}
+		domains = append(domains, domain)
+	}
+	return domains, nil
+}
+
+// newACMEManager returns the manager obtaining and renewing the
+// certificates of the configured ACME domains, nil if no domains are
+// configured. Setting the domains accepts the terms of service of the
+// ACME CA.
+func newACMEManager() *autocert.Manager {
+	if len(globalACMEDomains) == 0 {
+		return nil
+	}
+	return &autocert.Manager{
+		Prompt:     autocert.AcceptTOS,
+		Cache:      newACMECache(globalCertsDir.Get()),
+		HostPolicy: autocert.HostWhitelist(globalACMEDomains...),
+		Email:      globalACMEEmail,
+		Client:     &acme.Client{DirectoryURL: globalACMEDirectory},
+	}
+}
+
+// getCertificateFunc returns the function serving the TLS certificates
+// of the server, nil if TLS is not enabled. Certificates of the ACME
+// domains are obtained through ACME and renewed in the background, the
+// certificates of the certs directory are served for all other server
+// names, e.g. when servers connect to each other by IP address.
+func getCertificateFunc(m *autocert.Manager, tlsCerts *certs.Manager) certs.GetCertificateFunc {
+	if m == nil {
+		if tlsCerts == nil {
+			return nil
+		}
+		return tlsCerts.GetCertificate
+	}
+	if tlsCerts == nil {
+		return m.GetCertificate
+	}
+	return func(hello *tls.ClientHelloInfo) (*tls.Certificate, error) {
+		if isACMEDomain(hello.ServerName) {
+			return m.GetCertificate(hello)
+		}
+		return tlsCerts.GetCertificate(hello)
+	}
+}
+
+// Returns true if certificates for the server name are obtained
+// through ACME.
+func isACMEDomain(serverName string) bool {
+	serverName = strings.TrimSuffix(strings.ToLower(serverName), ".")
+	for _, domain := range globalACMEDomains {
+		if domain == serverName {
+			return true
+		}
+	}
+	return false
+}
+
+// enableACMEChallenges lets the ACME CA verify the domains of the
+// server through "tls-alpn-01" challenges on the TLS listener.
+func enableACMEChallenges(s *xhttp.Server) {
+	if s.TLSConfig != nil {
+		s.TLSConfig.NextProtos = append(s.TLSConfig.NextProtos, acme.ALPNProto)
+	}
+}
@@ -0,0 +1,209 @@
+/*
+ * MinIO Cloud Storage, (C) 2020 MinIO, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"io/ioutil"
+	"math/big"
+	"os"
+	"path"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/minio/minio/pkg/certs"
+	"golang.org/x/crypto/acme/autocert"
+)
+
+func TestParseACMEDomains(t *testing.T) {
+	testCases := []struct {
+		domainsStr      string
+		expectedDomains []string
+		success         bool
+	}{
+		{"example.com", []string{"example.com"}, true},
+		{"Example.com, www.example.com", []string{"example.com", "www.example.com"}, true},
+		{"*.example.com", nil, false},
+		{"192.168.1.1", nil, false},
+		{"localhost", nil, false},
+		{"example.com,", nil, false},
+	}
+	for i, testCase := range testCases {
+		domains, err := parseACMEDomains(testCase.domainsStr)
+		if err != nil && testCase.success {
+			t.Errorf("Test %d: Expected success but failed instead %s", i+1, err)
+		}
+		if err == nil && !testCase.success {
+			t.Errorf("Test %d: Expected failure but passed instead", i+1)
+		}
+		if err == nil && !reflect.DeepEqual(domains, testCase.expectedDomains) {
+			t.Errorf("Test %d: Expected %v, got %v", i+1, testCase.expectedDomains, domains)
+		}
+	}
+}
+
+// Tests that ACME entries are stored locally and in the backend, and
+// that entries of other servers are found in the backend.
+func TestACMECache(t *testing.T) {
+	objLayer, fsDir, err := prepareFS()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(fsDir)
+	certsDir, err := ioutil.TempDir("", "minio-acme-")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(certsDir)
+
+	ctx := context.Background()
+	c := newACMECache(certsDir)
+
+	// Entries are only stored locally until the backend is initialized.
+	resetGlobalObjectAPI()
+	if err = c.Put(ctx, "local", []byte("local")); err != nil {
+		t.Fatal(err)
+	}
+	if _, err = c.Get(ctx, "missing"); err != autocert.ErrCacheMiss {
+		t.Fatalf("Expected %v, got %v", autocert.ErrCacheMiss, err)
+	}
+
+	globalObjLayerMutex.Lock()
+	globalObjectAPI = objLayer
+	globalObjLayerMutex.Unlock()
+	defer resetGlobalObjectAPI()
+
+	if err = c.Put(ctx, "shared", []byte("shared")); err != nil {
+		t.Fatal(err)
+	}
+	if _, err = readConfig(ctx, objLayer, path.Join(acmeConfigPrefix, "local")); err != errConfigNotFound {
+		t.Fatalf("Expected %v, got %v", errConfigNotFound, err)
+	}
+	data, err := readConfig(ctx, objLayer, path.Join(acmeConfigPrefix, "shared"))
+	if err != nil || string(data) != "shared" {
+		t.Fatalf("Expected shared entry in the backend, got %s, %v", data, err)
+	}
+
+	// Entries of another server are found in the backend.
+	other := newACMECache(certsDir + "-other")
+	defer os.RemoveAll(certsDir + "-other")
+	if data, err = other.Get(ctx, "shared"); err != nil || string(data) != "shared" {
+		t.Fatalf("Expected shared entry, got %s, %v", data, err)
+	}
+	if data, err = other.dir.Get(ctx, "shared"); err != nil || string(data) != "shared" {
+		t.Fatalf("Expected shared entry to be stored locally, got %s, %v", data, err)
+	}
+
+	if err = c.Delete(ctx, "shared"); err != nil {
+		t.Fatal(err)
+	}
+	if err = c.Delete(ctx, "local"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err = c.Get(ctx, "shared"); err != autocert.ErrCacheMiss {
+		t.Fatalf("Expected %v, got %v", autocert.ErrCacheMiss, err)
+	}
+}
+
+// Tests that certificates of the ACME domains are obtained through ACME
+// and the certificates of the certs directory are served otherwise.
+func TestGetCertificateFunc(t *testing.T) {
+	defer func(domains []string) { globalACMEDomains = domains }(globalACMEDomains)
+	globalACMEDomains = []string{"example.com"}
+
+	certsDir, err := ioutil.TempDir("", "minio-acme-")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(certsDir)
+
+	// Store an ACME certificate so that the CA is not contacted.
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: "example.com"},
+		DNSNames:     []string{"example.com"},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(90 * 24 * time.Hour),
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var buf bytes.Buffer
+	pem.Encode(&buf, &pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER})
+	pem.Encode(&buf, &pem.Block{Type: "CERTIFICATE", Bytes: der})
+
+	cache := newACMECache(certsDir)
+	if err = cache.dir.Put(context.Background(), "example.com", buf.Bytes()); err != nil {
+		t.Fatal(err)
+	}
+
+	if getCertificateFunc(nil, nil) != nil {
+		t.Fatal("Expected TLS to be disabled")
+	}
+
+	tlsCerts, err := certs.NewManager("../pkg/certs/server.crt", "../pkg/certs/server.key", loadX509KeyPair)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer tlsCerts.Stop()
+
+	m := &autocert.Manager{
+		Prompt:     autocert.AcceptTOS,
+		Cache:      cache,
+		HostPolicy: autocert.HostWhitelist(globalACMEDomains...),
+	}
+	getCert := getCertificateFunc(m, tlsCerts)
+
+	suites := []uint16{tls.TLS_ECDHE_ECDSA_WITH_AES_128_GCM_SHA256}
+	cert, err := getCert(&tls.ClientHelloInfo{ServerName: "Example.com", CipherSuites: suites})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(cert.Certificate[0], der) {
+		t.Fatal("Expected the ACME certificate to be served")
+	}
+
+	staticCert, err := tlsCerts.GetCertificate(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cert, err = getCert(&tls.ClientHelloInfo{CipherSuites: suites})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(cert.Certificate[0], staticCert.Certificate[0]) {
+		t.Fatal("Expected the certificate of the certs directory to be served")
+	}
+}
@@ -22,6 +22,7 @@ import (
 	"encoding/base64"
 	"encoding/json"
 	"errors"
+	"fmt"
 	"io"
 	"io/ioutil"
 	"net/http"
@@ -29,6 +30,7 @@ import (
 	"sort"
 	"strconv"
 	"strings"
+	"sync"
 	"time"
 
 	humanize "github.com/dustin/go-humanize"
@@ -187,6 +189,12 @@ type ServerProperties struct {
 	DeploymentID string        `json:"deploymentID"`
 	Region       string        `json:"region"`
 	SQSARN       []string      `json:"sqsARN"`
+
+	// Backend is only set for gateways with the circuit breaker enabled.
+	Backend *CircuitBreakerInfo `json:"backend,omitempty"`
+
+	// ReadReplicas is only set for gateways with read replicas.
+	ReadReplicas []ReadReplicaInfo `json:"readReplicas,omitempty"`
 }
 
 // ServerConnStats holds transferred bytes from/to the server
@@ -203,6 +211,17 @@ type ServerHTTPMethodStats struct {
 	AvgDuration string `json:"avgDuration"`
 }
 
+// ServerHTTPAPIStats holds the number of requests to an S3 API, the
+// requests in flight and their latency percentiles.
+type ServerHTTPAPIStats struct {
+	Count       uint64 `json:"count"`
+	InFlight    int64  `json:"inFlight"`
+	AvgDuration string `json:"avgDuration"`
+	P50Duration string `json:"p50Duration"`
+	P95Duration string `json:"p95Duration"`
+	P99Duration string `json:"p99Duration"`
+}
+
 // ServerHTTPStats holds all type of http operations performed to/from the server
 // including their average execution time.
 type ServerHTTPStats struct {
@@ -216,6 +235,8 @@ type ServerHTTPStats struct {
 	SuccessPOSTStats   ServerHTTPMethodStats `json:"successPOSTs"`
 	TotalDELETEStats   ServerHTTPMethodStats `json:"totalDELETEs"`
 	SuccessDELETEStats ServerHTTPMethodStats `json:"successDELETEs"`
+	// Stats of S3 API requests keyed by API name.
+	APIStats map[string]ServerHTTPAPIStats `json:"apiStats,omitempty"`
 }
 
 // ServerInfoData holds storage, connections and other
@@ -261,6 +282,8 @@ func (a adminAPIHandlers) ServerInfoHandler(w http.ResponseWriter, r *http.Reque
 				DeploymentID: globalDeploymentID,
 				SQSARN:       globalNotificationSys.GetARNList(),
 				Region:       globalServerConfig.GetRegion(),
+				Backend:      getBackendCircuitInfo(),
+				ReadReplicas: getReadReplicasInfo(),
 			},
 		},
 	})
@@ -286,6 +309,15 @@ type ServerDrivesPerfInfo struct {
 	Perf  []disk.Performance `json:"perf"`
 }
 
+// ServerDriveLatencyInfo holds the read and write latency histograms
+// of all drives on one server. It also reports any errors if encountered
+// while trying to reach this server.
+type ServerDriveLatencyInfo struct {
+	Addr    string         `json:"addr"`
+	Error   string         `json:"error,omitempty"`
+	Latency []disk.Latency `json:"latency"`
+}
+
 // ServerCPULoadInfo holds informantion about cpu utilization
 // of one minio node. It also reports any errors if encountered
 // while trying to reach this server.
@@ -388,6 +420,29 @@ func (a adminAPIHandlers) PerfInfoHandler(w http.ResponseWriter, r *http.Request
 		// Reply with performance information (across nodes in a
 		// distributed setup) as json.
 		writeSuccessResponseJSON(w, jsonBytes)
+	case "drivelatency":
+		info := objectAPI.StorageInfo(ctx)
+		if info.Backend.Type != BackendErasure {
+			writeErrorResponseJSON(ctx, w, errorCodes.ToAPIErr(ErrMethodNotAllowed), r.URL)
+			return
+		}
+		// Get latency histograms of the local server's drive(s)
+		dl := localEndpointsDriveLatency(globalEndpoints, r)
+
+		// Notify all other MinIO peers to report their drive latencies
+		dls := globalNotificationSys.DriveLatency()
+		dls = append(dls, dl)
+
+		// Marshal API response
+		jsonBytes, err := json.Marshal(dls)
+		if err != nil {
+			writeErrorResponseJSON(ctx, w, toAdminAPIErr(ctx, err), r.URL)
+			return
+		}
+
+		// Reply with drive latencies (across nodes in a
+		// distributed setup) as json.
+		writeSuccessResponseJSON(w, jsonBytes)
 	case "cpu":
 		// Get CPU load details from local server's cpu(s)
 		cpu := localEndpointsCPULoad(globalEndpoints, r)
@@ -509,6 +564,154 @@ func (a adminAPIHandlers) TopLocksHandler(w http.ResponseWriter, r *http.Request
 	writeSuccessResponseJSON(w, jsonBytes)
 }
 
+// TargetsHealthHandler - GET /minio/admin/v1/notification/health
+// ----------
+// Returns the health of the notification targets as last checked by
+// each server.
+func (a adminAPIHandlers) TargetsHealthHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := newContext(r, w, "TargetsHealth")
+
+	objectAPI := validateAdminReq(ctx, w, r)
+	if objectAPI == nil {
+		return
+	}
+
+	// Notify all other MinIO peers to report the health of their targets
+	targets := globalNotificationSys.TargetsHealthInfo()
+	targets = append(targets, localTargetsHealth(r))
+
+	// Marshal API response
+	jsonBytes, err := json.Marshal(targets)
+	if err != nil {
+		writeErrorResponseJSON(ctx, w, toAdminAPIErr(ctx, err), r.URL)
+		return
+	}
+
+	// Reply with the health of the targets (across nodes in a
+	// distributed setup) as json.
+	writeSuccessResponseJSON(w, jsonBytes)
+}
+
+// ListRequestsHandler - GET /minio/admin/v1/top/requests
+// ----------
+// Lists in-flight S3 requests on all servers, the longest running
+// first.
+func (a adminAPIHandlers) ListRequestsHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := newContext(r, w, "ListRequests")
+
+	objectAPI := validateAdminReq(ctx, w, r)
+	if objectAPI == nil {
+		return
+	}
+
+	requests := globalRequestRegistry.List(GetLocalPeer(globalEndpoints))
+	if globalIsDistXL {
+		requests = append(requests, globalNotificationSys.ListRequests(ctx)...)
+		sort.Slice(requests, func(i, j int) bool {
+			return requests[i].StartTime.Before(requests[j].StartTime)
+		})
+	}
+
+	jsonBytes, err := json.Marshal(requests)
+	if err != nil {
+		writeErrorResponseJSON(ctx, w, toAdminAPIErr(ctx, err), r.URL)
+		return
+	}
+
+	writeSuccessResponseJSON(w, jsonBytes)
+}
+
+// AccessKeyUsageHandler - GET /minio/admin/v1/access-key-usage
+// ----------
+// Returns the number of authenticated requests and the time of the
+// last one of the root credentials, of all IAM users and of the
+// temporary credentials used since the servers started, so that unused
+// credentials can be identified.
+func (a adminAPIHandlers) AccessKeyUsageHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := newContext(r, w, "AccessKeyUsage")
+
+	objectAPI := validateAdminReq(ctx, w, r)
+	if objectAPI == nil {
+		return
+	}
+
+	usages := globalAccessKeyUsage.List()
+	if globalIsDistXL {
+		usages = append(usages, globalNotificationSys.AccessKeyUsage(ctx)...)
+	}
+
+	users, err := globalIAMSys.ListUsers()
+	if err != nil {
+		writeErrorResponseJSON(ctx, w, toAdminAPIErr(ctx, err), r.URL)
+		return
+	}
+	// Temporary credentials are listed only once used.
+	for accessKey := range users {
+		if cred, ok := globalIAMSys.GetUser(accessKey); ok && cred.SessionToken != "" {
+			delete(users, accessKey)
+		}
+	}
+
+	usages = mergeAccessKeyUsage(usages, globalServerConfig.GetCredential().AccessKey, users)
+	jsonBytes, err := json.Marshal(usages)
+	if err != nil {
+		writeErrorResponseJSON(ctx, w, toAdminAPIErr(ctx, err), r.URL)
+		return
+	}
+
+	writeSuccessResponseJSON(w, jsonBytes)
+}
+
+// MembershipHandler - GET /minio/admin/v1/membership
+// ----------
+// Returns the liveness of all servers of a distributed setup as seen
+// by this server.
+func (a adminAPIHandlers) MembershipHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := newContext(r, w, "Membership")
+
+	objectAPI := validateAdminReq(ctx, w, r)
+	if objectAPI == nil {
+		return
+	}
+
+	jsonBytes, err := json.Marshal(globalPeerMembership.Members(GetLocalPeer(globalEndpoints)))
+	if err != nil {
+		writeErrorResponseJSON(ctx, w, toAdminAPIErr(ctx, err), r.URL)
+		return
+	}
+
+	writeSuccessResponseJSON(w, jsonBytes)
+}
+
+// CancelRequestHandler - POST /minio/admin/v1/cancel-request?id={id}
+// ----------
+// Cancels an in-flight S3 request, on whichever server is serving it.
+func (a adminAPIHandlers) CancelRequestHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := newContext(r, w, "CancelRequest")
+
+	objectAPI := validateAdminReq(ctx, w, r)
+	if objectAPI == nil {
+		return
+	}
+
+	id := mux.Vars(r)["id"]
+	if id == "" {
+		writeErrorResponseJSON(ctx, w, errorCodes.ToAPIErr(ErrAdminInvalidArgument), r.URL)
+		return
+	}
+
+	canceled := globalRequestRegistry.Cancel(id)
+	if !canceled && globalIsDistXL {
+		canceled = globalNotificationSys.CancelRequest(ctx, id)
+	}
+	if !canceled {
+		writeErrorResponseJSON(ctx, w, errorCodes.ToAPIErr(ErrAdminNoSuchRequest), r.URL)
+		return
+	}
+
+	writeSuccessResponseHeadersOnly(w)
+}
+
 // StartProfilingResult contains the status of the starting
 // profiling action in a given server
 type StartProfilingResult struct {
@@ -616,6 +819,32 @@ func (a adminAPIHandlers) DownloadProfilingHandler(w http.ResponseWriter, r *htt
 	}
 }
 
+// DiagnosticsHandler - GET /minio/admin/v1/diagnostics?node={node}
+// ----------
+// Download goroutine, heap and thread creation snapshots of a node in a
+// zip format, of the node serving the request if node is not given.
+func (a adminAPIHandlers) DiagnosticsHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := newContext(r, w, "Diagnostics")
+
+	objectAPI := validateAdminReq(ctx, w, r)
+	if objectAPI == nil {
+		return
+	}
+
+	data, err := globalNotificationSys.Diagnostics(r.URL.Query().Get("node"))
+	if err != nil {
+		if err == errDiagnosticsUnknownNode {
+			writeErrorResponseJSON(ctx, w, errorCodes.ToAPIErr(ErrAdminInvalidArgument), r.URL)
+			return
+		}
+		writeErrorResponseJSON(ctx, w, toAdminAPIErr(ctx, err), r.URL)
+		return
+	}
+
+	w.Header().Set(xhttp.ContentType, "application/zip")
+	w.Write(data)
+}
+
 // extractHealInitParams - Validates params for heal init API.
 func extractHealInitParams(r *http.Request) (bucket, objPrefix string,
 	hs madmin.HealOpts, clientToken string, forceStart bool, forceStop bool,
@@ -1461,6 +1690,47 @@ func (a adminAPIHandlers) SetPolicyForUserOrGroup(w http.ResponseWriter, r *http
 	}
 }
 
+// SimulatePolicyHandler - POST /minio/admin/v1/simulate-policy
+// Evaluates whether a request would be allowed by the current IAM and
+// bucket policies, and explains which policy statement decided.
+func (a adminAPIHandlers) SimulatePolicyHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := newContext(r, w, "SimulatePolicy")
+
+	objectAPI := validateAdminReq(ctx, w, r)
+	if objectAPI == nil {
+		return
+	}
+
+	if r.ContentLength > maxEConfigJSONSize || r.ContentLength == -1 {
+		writeErrorResponseJSON(ctx, w, errorCodes.ToAPIErr(ErrAdminConfigTooLarge), r.URL)
+		return
+	}
+
+	var sim madmin.PolicySimulation
+	if err := json.NewDecoder(io.LimitReader(r.Body, r.ContentLength)).Decode(&sim); err != nil {
+		writeErrorResponseJSON(ctx, w, errorCodes.ToAPIErr(ErrAdminConfigBadJSON), r.URL)
+		return
+	}
+
+	result, err := simulatePolicy(sim)
+	if err != nil {
+		if err == errPolicySimulationInvalidAction {
+			writeCustomErrorResponseJSON(ctx, w, errorCodes.ToAPIErr(ErrAdminInvalidArgument), err.Error(), r.URL)
+			return
+		}
+		writeErrorResponseJSON(ctx, w, toAdminAPIErr(ctx, err), r.URL)
+		return
+	}
+
+	jsonBytes, err := json.Marshal(result)
+	if err != nil {
+		writeErrorResponseJSON(ctx, w, toAdminAPIErr(ctx, err), r.URL)
+		return
+	}
+
+	writeSuccessResponseJSON(w, jsonBytes)
+}
+
 // SetConfigHandler - PUT /minio/admin/v1/config
 func (a adminAPIHandlers) SetConfigHandler(w http.ResponseWriter, r *http.Request) {
 	ctx := newContext(r, w, "SetConfigHandler")
@@ -1524,11 +1794,26 @@ func (a adminAPIHandlers) SetConfigHandler(w http.ResponseWriter, r *http.Reques
 		return
 	}
 
+	// IAM config data key is sealed with the root credentials,
+	// reseal it before new credentials are persisted.
+	if !globalServerConfig.GetCredential().Equal(config.Credential) {
+		if err = globalIAMSys.ResealConfigKey(config.Credential); err != nil {
+			writeErrorResponseJSON(ctx, w, toAdminAPIErr(ctx, err), r.URL)
+			return
+		}
+	}
+
 	if err = saveServerConfig(ctx, objectAPI, &config); err != nil {
 		writeErrorResponseJSON(ctx, w, toAdminAPIErr(ctx, err), r.URL)
 		return
 	}
 
+	// With etcd all the servers reload the new config, otherwise
+	// it takes effect once the servers are restarted.
+	if publishConfigEvent(configEventServerConfig, minioConfigFile) {
+		logger.LogIf(ctx, reloadServerConfig(objectAPI))
+	}
+
 	// Reply to the client before restarting minio server.
 	writeSuccessResponseHeadersOnly(w)
 }
@@ -1645,30 +1930,1269 @@ func (a adminAPIHandlers) SetConfigKeysHandler(w http.ResponseWriter, r *http.Re
 		}
 	}
 
+	// IAM config data key is sealed with the root credentials,
+	// reseal it before new credentials are persisted.
+	if !globalServerConfig.GetCredential().Equal(config.Credential) {
+		if err = globalIAMSys.ResealConfigKey(config.Credential); err != nil {
+			writeErrorResponseJSON(ctx, w, toAdminAPIErr(ctx, err), r.URL)
+			return
+		}
+	}
+
 	if err = saveServerConfig(ctx, objectAPI, &config); err != nil {
 		writeErrorResponseJSON(ctx, w, toAdminAPIErr(ctx, err), r.URL)
 		return
 	}
 
+	// With etcd all the servers reload the new config, otherwise
+	// it takes effect once the servers are restarted.
+	if publishConfigEvent(configEventServerConfig, minioConfigFile) {
+		logger.LogIf(ctx, reloadServerConfig(objectAPI))
+	}
+
 	// Send success response
 	writeSuccessResponseHeadersOnly(w)
 }
 
-// Returns true if the trace.Info should be traced,
-// false if certain conditions are not met.
-// - input entry is not of the type *trace.Info*
-// - errOnly entries are to be traced, not status code 2xx, 3xx.
-// - all entries to be traced, if not trace only S3 API requests.
-func mustTrace(entry interface{}, trcAll, errOnly bool) bool {
-	trcInfo, ok := entry.(trace.Info)
-	if !ok {
-		return false
+// BackupConfigHandler - GET /minio/admin/v1/config/backup
+// ----------
+// Returns an archive of the server configuration, IAM config and
+// bucket metadata encrypted with the secret key of the server.
+func (a adminAPIHandlers) BackupConfigHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := newContext(r, w, "BackupConfig")
+
+	objectAPI := validateAdminReq(ctx, w, r)
+	if objectAPI == nil {
+		return
 	}
-	trace := trcAll || !hasPrefix(trcInfo.ReqInfo.Path, minioReservedBucketPath+SlashSeparator)
-	if errOnly {
-		return trace && trcInfo.RespInfo.StatusCode >= http.StatusBadRequest
+
+	data, err := backupConfig(ctx, objectAPI)
+	if err != nil {
+		writeErrorResponseJSON(ctx, w, toAdminAPIErr(ctx, err), r.URL)
+		return
+	}
+
+	password := globalServerConfig.GetCredential().SecretKey
+	edata, err := madmin.EncryptData(password, data)
+	if err != nil {
+		writeErrorResponseJSON(ctx, w, toAdminAPIErr(ctx, err), r.URL)
+		return
+	}
+
+	writeSuccessResponseJSON(w, edata)
+}
+
+// RestoreConfigHandler - PUT /minio/admin/v1/config/restore
+// ----------
+// Restores an archive returned by BackupConfigHandler, it takes
+// effect once the servers are restarted.
+func (a adminAPIHandlers) RestoreConfigHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := newContext(r, w, "RestoreConfig")
+
+	objectAPI := validateAdminReq(ctx, w, r)
+	if objectAPI == nil {
+		return
+	}
+
+	// Deny if WORM is enabled
+	if globalWORMEnabled {
+		writeErrorResponseJSON(ctx, w, errorCodes.ToAPIErr(ErrMethodNotAllowed), r.URL)
+		return
+	}
+
+	if r.ContentLength > maxConfigBackupSize || r.ContentLength == -1 {
+		writeCustomErrorResponseJSON(ctx, w, errorCodes.ToAPIErr(ErrAdminConfigTooLarge),
+			fmt.Sprintf("Configuration backup exceeds the allowed maximum of %d bytes", maxConfigBackupSize), r.URL)
+		return
+	}
+
+	password := globalServerConfig.GetCredential().SecretKey
+	data, err := madmin.DecryptData(password, io.LimitReader(r.Body, r.ContentLength))
+	if err != nil {
+		logger.LogIf(ctx, err)
+		writeErrorResponseJSON(ctx, w, errorCodes.ToAPIErr(ErrAdminInvalidConfigBackup), r.URL)
+		return
+	}
+
+	info, err := restoreConfig(ctx, objectAPI, data)
+	if err != nil {
+		writeErrorResponseJSON(ctx, w, toAdminAPIErr(ctx, err), r.URL)
+		return
+	}
+
+	jsonBytes, err := json.Marshal(info)
+	if err != nil {
+		writeErrorResponseJSON(ctx, w, toAdminAPIErr(ctx, err), r.URL)
+		return
+	}
+
+	writeSuccessResponseJSON(w, jsonBytes)
+}
+
+// ExportBucketMetadataHandler - GET /minio/admin/v1/bucket-metadata/export?bucket={bucket}
+// ----------
+// Returns the configuration of a bucket as a bucket metadata bundle.
+func (a adminAPIHandlers) ExportBucketMetadataHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := newContext(r, w, "ExportBucketMetadata")
+
+	objectAPI := validateAdminReq(ctx, w, r)
+	if objectAPI == nil {
+		return
+	}
+
+	bucket := mux.Vars(r)["bucket"]
+	if _, err := objectAPI.GetBucketInfo(ctx, bucket); err != nil {
+		writeErrorResponseJSON(ctx, w, toAdminAPIErr(ctx, err), r.URL)
+		return
+	}
+
+	bundle, err := exportBucketMetadata(ctx, objectAPI, bucket)
+	if err != nil {
+		writeErrorResponseJSON(ctx, w, toAdminAPIErr(ctx, err), r.URL)
+		return
+	}
+
+	jsonBytes, err := json.Marshal(bundle)
+	if err != nil {
+		writeErrorResponseJSON(ctx, w, toAdminAPIErr(ctx, err), r.URL)
+		return
+	}
+
+	writeSuccessResponseJSON(w, jsonBytes)
+}
+
+// ImportBucketMetadataHandler - PUT /minio/admin/v1/bucket-metadata/import?bucket={bucket}
+// ----------
+// Applies a bucket metadata bundle returned by ExportBucketMetadataHandler
+// to a bucket, nothing is applied if any of its files is invalid.
+func (a adminAPIHandlers) ImportBucketMetadataHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := newContext(r, w, "ImportBucketMetadata")
+
+	objectAPI := validateAdminReq(ctx, w, r)
+	if objectAPI == nil {
+		return
+	}
+
+	// Deny if WORM is enabled
+	if globalWORMEnabled {
+		writeErrorResponseJSON(ctx, w, errorCodes.ToAPIErr(ErrMethodNotAllowed), r.URL)
+		return
+	}
+
+	bucket := mux.Vars(r)["bucket"]
+	if _, err := objectAPI.GetBucketInfo(ctx, bucket); err != nil {
+		writeErrorResponseJSON(ctx, w, toAdminAPIErr(ctx, err), r.URL)
+		return
+	}
+
+	if r.ContentLength > maxBucketMetadataBundleSize || r.ContentLength == -1 {
+		writeErrorResponseJSON(ctx, w, errorCodes.ToAPIErr(ErrAdminConfigTooLarge), r.URL)
+		return
+	}
+
+	var bundle madmin.BucketMetadataBundle
+	if err := json.NewDecoder(io.LimitReader(r.Body, r.ContentLength)).Decode(&bundle); err != nil {
+		writeErrorResponseJSON(ctx, w, errorCodes.ToAPIErr(ErrAdminConfigBadJSON), r.URL)
+		return
+	}
+
+	appliers, err := prepareBucketMetadataImport(ctx, objectAPI, bucket, bundle)
+	if err != nil {
+		writeCustomErrorResponseJSON(ctx, w, errorCodes.ToAPIErr(ErrAdminInvalidArgument), err.Error(), r.URL)
+		return
+	}
+
+	for _, apply := range appliers {
+		if err = apply(); err != nil {
+			writeErrorResponseJSON(ctx, w, toAdminAPIErr(ctx, err), r.URL)
+			return
+		}
+	}
+
+	writeSuccessResponseHeadersOnly(w)
+}
+
+// GetBandwidthLimitsHandler - GET /minio/admin/v1/bandwidth
+// ----------
+// Returns the bandwidth limits for background data transfers.
+func (a adminAPIHandlers) GetBandwidthLimitsHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := newContext(r, w, "GetBandwidthLimits")
+
+	objectAPI := validateAdminReq(ctx, w, r)
+	if objectAPI == nil {
+		return
+	}
+
+	jsonBytes, err := json.Marshal(globalBandwidthSys.Get())
+	if err != nil {
+		writeErrorResponseJSON(ctx, w, toAdminAPIErr(ctx, err), r.URL)
+		return
+	}
+
+	writeSuccessResponseJSON(w, jsonBytes)
+}
+
+// GetBucketModesHandler - GET /minio/admin/v1/bucket-mode
+// ----------
+// Returns the modes of the buckets which are not in read-write mode.
+func (a adminAPIHandlers) GetBucketModesHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := newContext(r, w, "GetBucketModes")
+
+	objectAPI := validateAdminReq(ctx, w, r)
+	if objectAPI == nil {
+		return
+	}
+
+	jsonBytes, err := json.Marshal(globalBucketModeSys.GetAll())
+	if err != nil {
+		writeErrorResponseJSON(ctx, w, toAdminAPIErr(ctx, err), r.URL)
+		return
+	}
+
+	writeSuccessResponseJSON(w, jsonBytes)
+}
+
+// SetBucketModeHandler - PUT /minio/admin/v1/bucket-mode?bucket={bucket}&mode={mode}
+// ----------
+// Sets the mode of a bucket on all servers, requests not allowed by the
+// mode are rejected right away.
+func (a adminAPIHandlers) SetBucketModeHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := newContext(r, w, "SetBucketMode")
+
+	objectAPI := validateAdminReq(ctx, w, r)
+	if objectAPI == nil {
+		return
+	}
+
+	vars := mux.Vars(r)
+	bucket := vars["bucket"]
+	mode := madmin.BucketMode(vars["mode"])
+	if !mode.IsValid() {
+		writeCustomErrorResponseJSON(ctx, w, errorCodes.ToAPIErr(ErrAdminInvalidArgument), "unknown bucket mode "+string(mode), r.URL)
+		return
+	}
+	if isReservedOrInvalidBucket(bucket, false) {
+		writeErrorResponseJSON(ctx, w, errorCodes.ToAPIErr(ErrInvalidBucketName), r.URL)
+		return
+	}
+	// Buckets may be removed while in read-write mode.
+	if mode != madmin.BucketModeReadWrite {
+		if _, err := objectAPI.GetBucketInfo(ctx, bucket); err != nil {
+			writeErrorResponseJSON(ctx, w, toAdminAPIErr(ctx, err), r.URL)
+			return
+		}
+	}
+
+	if err := saveBucketMode(ctx, objectAPI, bucket, mode); err != nil {
+		writeErrorResponseJSON(ctx, w, toAdminAPIErr(ctx, err), r.URL)
+		return
+	}
+
+	if err := globalBucketModeSys.Load(objectAPI); err != nil {
+		writeErrorResponseJSON(ctx, w, toAdminAPIErr(ctx, err), r.URL)
+		return
+	}
+
+	// Notify all other MinIO peers to reload the bucket modes
+	for _, nerr := range globalNotificationSys.LoadBucketModes() {
+		if nerr.Err != nil {
+			logger.GetReqInfo(ctx).SetTags("peerAddress", nerr.Host.String())
+			logger.LogIf(ctx, nerr.Err)
+		}
+	}
+
+	// Send success response
+	writeSuccessResponseHeadersOnly(w)
+}
+
+// GetBucketKeyNormalizationHandler - GET /minio/admin/v1/bucket-key-normalization
+// ----------
+// Returns the key normalization forms of the buckets normalizing their
+// object keys.
+func (a adminAPIHandlers) GetBucketKeyNormalizationHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := newContext(r, w, "GetBucketKeyNormalization")
+
+	objectAPI := validateAdminReq(ctx, w, r)
+	if objectAPI == nil {
+		return
+	}
+
+	jsonBytes, err := json.Marshal(globalBucketKeyNormalizationSys.GetAll())
+	if err != nil {
+		writeErrorResponseJSON(ctx, w, toAdminAPIErr(ctx, err), r.URL)
+		return
+	}
+
+	writeSuccessResponseJSON(w, jsonBytes)
+}
+
+// SetBucketKeyNormalizationHandler - PUT /minio/admin/v1/bucket-key-normalization?bucket={bucket}&form={form}
+// ----------
+// Sets the key normalization form of a bucket on all servers, objects
+// written before keep their keys.
+func (a adminAPIHandlers) SetBucketKeyNormalizationHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := newContext(r, w, "SetBucketKeyNormalization")
+
+	objectAPI := validateAdminReq(ctx, w, r)
+	if objectAPI == nil {
+		return
+	}
+
+	vars := mux.Vars(r)
+	bucket := vars["bucket"]
+	form := madmin.KeyNormalization(vars["form"])
+	if !form.IsValid() {
+		writeCustomErrorResponseJSON(ctx, w, errorCodes.ToAPIErr(ErrAdminInvalidArgument), "unknown key normalization form "+string(form), r.URL)
+		return
+	}
+	if isReservedOrInvalidBucket(bucket, false) {
+		writeErrorResponseJSON(ctx, w, errorCodes.ToAPIErr(ErrInvalidBucketName), r.URL)
+		return
+	}
+	// Buckets may be removed once they no longer normalize keys.
+	if form != madmin.KeyNormalizationNone {
+		if _, err := objectAPI.GetBucketInfo(ctx, bucket); err != nil {
+			writeErrorResponseJSON(ctx, w, toAdminAPIErr(ctx, err), r.URL)
+			return
+		}
+	}
+
+	if err := saveBucketKeyNormalization(ctx, objectAPI, bucket, form); err != nil {
+		writeErrorResponseJSON(ctx, w, toAdminAPIErr(ctx, err), r.URL)
+		return
+	}
+
+	if err := globalBucketKeyNormalizationSys.Load(objectAPI); err != nil {
+		writeErrorResponseJSON(ctx, w, toAdminAPIErr(ctx, err), r.URL)
+		return
+	}
+
+	// Notify all other MinIO peers to reload the key normalization forms
+	for _, nerr := range globalNotificationSys.LoadBucketKeyNormalization() {
+		if nerr.Err != nil {
+			logger.GetReqInfo(ctx).SetTags("peerAddress", nerr.Host.String())
+			logger.LogIf(ctx, nerr.Err)
+		}
+	}
+
+	// Send success response
+	writeSuccessResponseHeadersOnly(w)
+}
+
+// SetBandwidthLimitsHandler - PUT /minio/admin/v1/bandwidth
+// ----------
+// Sets the bandwidth limits for background data transfers on all
+// servers, limits are in bytes per second and zero means unlimited.
+func (a adminAPIHandlers) SetBandwidthLimitsHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := newContext(r, w, "SetBandwidthLimits")
+
+	objectAPI := validateAdminReq(ctx, w, r)
+	if objectAPI == nil {
+		return
+	}
+
+	if r.ContentLength > maxEConfigJSONSize || r.ContentLength == -1 {
+		writeErrorResponseJSON(ctx, w, errorCodes.ToAPIErr(ErrAdminConfigTooLarge), r.URL)
+		return
+	}
+
+	var limits madmin.BandwidthLimits
+	if err := json.NewDecoder(io.LimitReader(r.Body, r.ContentLength)).Decode(&limits); err != nil {
+		writeErrorResponseJSON(ctx, w, errorCodes.ToAPIErr(ErrAdminConfigBadJSON), r.URL)
+		return
+	}
+
+	if err := validateBandwidthLimits(limits); err != nil {
+		writeCustomErrorResponseJSON(ctx, w, errorCodes.ToAPIErr(ErrAdminInvalidArgument), err.Error(), r.URL)
+		return
+	}
+
+	if err := saveBandwidthConfig(ctx, objectAPI, limits); err != nil {
+		writeErrorResponseJSON(ctx, w, toAdminAPIErr(ctx, err), r.URL)
+		return
+	}
+
+	globalBandwidthSys.Set(limits)
+
+	// Notify all other MinIO peers to reload bandwidth limits
+	for _, nerr := range globalNotificationSys.LoadBandwidthLimits() {
+		if nerr.Err != nil {
+			logger.GetReqInfo(ctx).SetTags("peerAddress", nerr.Host.String())
+			logger.LogIf(ctx, nerr.Err)
+		}
+	}
+
+	// Send success response
+	writeSuccessResponseHeadersOnly(w)
+}
+
+// UpdateCacheConfigHandler - PUT /minio/admin/v1/cache/config
+// ----------
+// Adds and removes cache drives and exclude patterns on all servers
+// without a restart, objects are rehashed over the new list of drives
+// and removed drives are drained.
+func (a adminAPIHandlers) UpdateCacheConfigHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := newContext(r, w, "UpdateCacheConfig")
+
+	objectAPI := validateAdminReq(ctx, w, r)
+	if objectAPI == nil {
+		return
+	}
+
+	// Deny if WORM is enabled
+	if globalWORMEnabled {
+		writeErrorResponseJSON(ctx, w, errorCodes.ToAPIErr(ErrMethodNotAllowed), r.URL)
+		return
+	}
+
+	if r.ContentLength > maxEConfigJSONSize || r.ContentLength == -1 {
+		writeErrorResponseJSON(ctx, w, errorCodes.ToAPIErr(ErrAdminConfigTooLarge), r.URL)
+		return
+	}
+
+	var update madmin.CacheConfigUpdate
+	if err := json.NewDecoder(io.LimitReader(r.Body, r.ContentLength)).Decode(&update); err != nil {
+		writeErrorResponseJSON(ctx, w, errorCodes.ToAPIErr(ErrAdminConfigBadJSON), r.URL)
+		return
+	}
+
+	c, err := getUpdatableCache()
+	if err != nil {
+		writeErrorResponseJSON(ctx, w, toAdminAPIErr(ctx, err), r.URL)
+		return
+	}
+
+	config, err := readServerConfig(ctx, objectAPI)
+	if err != nil {
+		writeErrorResponseJSON(ctx, w, toAdminAPIErr(ctx, err), r.URL)
+		return
+	}
+
+	oldCacheConfig := config.Cache
+	config.Cache = updateCacheConfig(config.Cache, update)
+	if len(config.Cache.Drives) == 0 {
+		writeCustomErrorResponseJSON(ctx, w, errorCodes.ToAPIErr(ErrAdminInvalidArgument), "at least one cache drive is required", r.URL)
+		return
+	}
+
+	if err = c.updateConfig(ctx, config.Cache); err != nil {
+		if err == errCacheMigrating {
+			writeErrorResponseJSON(ctx, w, toAdminAPIErr(ctx, err), r.URL)
+			return
+		}
+		writeCustomErrorResponseJSON(ctx, w, errorCodes.ToAPIErr(ErrAdminInvalidArgument), err.Error(), r.URL)
+		return
+	}
+
+	if err = saveServerConfig(ctx, objectAPI, config); err != nil {
+		logger.LogIf(ctx, c.updateConfig(ctx, oldCacheConfig))
+		writeErrorResponseJSON(ctx, w, toAdminAPIErr(ctx, err), r.URL)
+		return
+	}
+
+	setGlobalCacheConfig(config.Cache)
+
+	// Notify all other MinIO peers to reload cache config
+	for _, nerr := range globalNotificationSys.LoadCacheConfig() {
+		if nerr.Err != nil {
+			logger.GetReqInfo(ctx).SetTags("peerAddress", nerr.Host.String())
+			logger.LogIf(ctx, nerr.Err)
+		}
+	}
+
+	// Send success response
+	writeSuccessResponseHeadersOnly(w)
+}
+
+// RotateKMSKeyHandler - POST /minio/admin/v1/kms/key/rotate?key-id={keyID}
+// ----------
+// Rotates the master key with the given ID at the KMS, the default
+// SSE-S3 master key if no key ID is given.
+func (a adminAPIHandlers) RotateKMSKeyHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := newContext(r, w, "RotateKMSKey")
+
+	objectAPI := validateAdminReq(ctx, w, r)
+	if objectAPI == nil {
+		return
+	}
+
+	if err := rotateKMSKey(r.URL.Query().Get("key-id")); err != nil {
+		writeErrorResponseJSON(ctx, w, toAdminAPIErr(ctx, err), r.URL)
+		return
+	}
+
+	writeSuccessResponseHeadersOnly(w)
+}
+
+// StartKMSKeySweepHandler - POST /minio/admin/v1/kms/key/sweep
+// ----------
+// Starts a background sweep on this server which re-wraps the keys
+// of all SSE-S3 encrypted objects with the current master key.
+func (a adminAPIHandlers) StartKMSKeySweepHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := newContext(r, w, "StartKMSKeySweep")
+
+	objectAPI := validateAdminReq(ctx, w, r)
+	if objectAPI == nil {
+		return
+	}
+
+	// A single server sweeps the whole namespace.
+	if globalIsDistXL {
+		for _, status := range globalNotificationSys.KMSKeySweepStatus(ctx) {
+			if status.Running {
+				writeErrorResponseJSON(ctx, w, toAdminAPIErr(ctx, errKMSKeySweepInProgress), r.URL)
+				return
+			}
+		}
+	}
+
+	if err := globalKMSKeySweep.Start(GlobalContext, objectAPI); err != nil {
+		writeErrorResponseJSON(ctx, w, toAdminAPIErr(ctx, err), r.URL)
+		return
+	}
+
+	writeSuccessResponseHeadersOnly(w)
+}
+
+// KMSKeySweepStatusHandler - GET /minio/admin/v1/kms/key/sweep/status
+// ----------
+// Returns the state of the KMS key re-encryption sweep, along with
+// its progress per bucket, on all servers.
+func (a adminAPIHandlers) KMSKeySweepStatusHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := newContext(r, w, "KMSKeySweepStatus")
+
+	objectAPI := validateAdminReq(ctx, w, r)
+	if objectAPI == nil {
+		return
+	}
+
+	statuses := []madmin.KMSKeySweepStatus{globalKMSKeySweep.Status()}
+	if globalIsDistXL {
+		statuses = append(statuses, globalNotificationSys.KMSKeySweepStatus(ctx)...)
+	}
+
+	jsonBytes, err := json.Marshal(statuses)
+	if err != nil {
+		writeErrorResponseJSON(ctx, w, toAdminAPIErr(ctx, err), r.URL)
+		return
+	}
+
+	writeSuccessResponseJSON(w, jsonBytes)
+}
+
+// StartBatchUpdateJobHandler - POST /minio/admin/v1/batch/update
+// ----------
+// Starts a background job on this server which sets metadata on all
+// objects under a prefix, returns the ID of the job.
+func (a adminAPIHandlers) StartBatchUpdateJobHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := newContext(r, w, "StartBatchUpdateJob")
+
+	objectAPI := validateAdminReq(ctx, w, r)
+	if objectAPI == nil {
+		return
+	}
+
+	// Deny if WORM is enabled
+	if globalWORMEnabled {
+		writeErrorResponseJSON(ctx, w, errorCodes.ToAPIErr(ErrMethodNotAllowed), r.URL)
+		return
+	}
+
+	if r.ContentLength > maxEConfigJSONSize || r.ContentLength == -1 {
+		writeErrorResponseJSON(ctx, w, errorCodes.ToAPIErr(ErrAdminConfigTooLarge), r.URL)
+		return
+	}
+
+	var job madmin.BatchUpdateJob
+	if err := json.NewDecoder(io.LimitReader(r.Body, r.ContentLength)).Decode(&job); err != nil {
+		writeErrorResponseJSON(ctx, w, errorCodes.ToAPIErr(ErrAdminConfigBadJSON), r.URL)
+		return
+	}
+	if job.RateLimit < 0 {
+		writeErrorResponseJSON(ctx, w, errorCodes.ToAPIErr(ErrAdminInvalidArgument), r.URL)
+		return
+	}
+
+	id, err := globalBatchJobs.StartUpdate(GlobalContext, objectAPI, job)
+	switch err {
+	case nil:
+	case errBatchJobNoMetadata, errBatchJobInvalidMetadata:
+		writeCustomErrorResponseJSON(ctx, w, errorCodes.ToAPIErr(ErrAdminInvalidArgument), err.Error(), r.URL)
+		return
+	default:
+		writeErrorResponseJSON(ctx, w, toAdminAPIErr(ctx, err), r.URL)
+		return
+	}
+
+	jsonBytes, err := json.Marshal(struct {
+		ID string `json:"id"`
+	}{id})
+	if err != nil {
+		writeErrorResponseJSON(ctx, w, toAdminAPIErr(ctx, err), r.URL)
+		return
+	}
+
+	writeSuccessResponseJSON(w, jsonBytes)
+}
+
+// StartBatchOperationJobHandler - POST /minio/admin/v1/batch/operation
+// ----------
+// Starts a background job which applies an operation to a manifest of
+// objects, the objects are split between all servers. Returns the ID
+// of the job.
+func (a adminAPIHandlers) StartBatchOperationJobHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := newContext(r, w, "StartBatchOperationJob")
+
+	objectAPI := validateAdminReq(ctx, w, r)
+	if objectAPI == nil {
+		return
+	}
+
+	// Deny if WORM is enabled
+	if globalWORMEnabled {
+		writeErrorResponseJSON(ctx, w, errorCodes.ToAPIErr(ErrMethodNotAllowed), r.URL)
+		return
+	}
+
+	if r.ContentLength > maxBatchJobJSONSize || r.ContentLength == -1 {
+		writeErrorResponseJSON(ctx, w, errorCodes.ToAPIErr(ErrAdminConfigTooLarge), r.URL)
+		return
+	}
+
+	var job madmin.BatchOperationJob
+	if err := json.NewDecoder(io.LimitReader(r.Body, r.ContentLength)).Decode(&job); err != nil {
+		writeErrorResponseJSON(ctx, w, errorCodes.ToAPIErr(ErrAdminConfigBadJSON), r.URL)
+		return
+	}
+	if job.RateLimit < 0 {
+		writeErrorResponseJSON(ctx, w, errorCodes.ToAPIErr(ErrAdminInvalidArgument), r.URL)
+		return
+	}
+
+	if err := loadBatchOperationJob(ctx, objectAPI, &job); err != nil {
+		if isBatchJobArgumentErr(err) {
+			writeCustomErrorResponseJSON(ctx, w, errorCodes.ToAPIErr(ErrAdminInvalidArgument), err.Error(), r.URL)
+			return
+		}
+		writeErrorResponseJSON(ctx, w, toAdminAPIErr(ctx, err), r.URL)
+		return
+	}
+
+	id := startBatchOperationJob(ctx, objectAPI, job)
+
+	jsonBytes, err := json.Marshal(struct {
+		ID string `json:"id"`
+	}{id})
+	if err != nil {
+		writeErrorResponseJSON(ctx, w, toAdminAPIErr(ctx, err), r.URL)
+		return
+	}
+
+	writeSuccessResponseJSON(w, jsonBytes)
+}
+
+// StartBatchCopyPrefixJobHandler - POST /minio/admin/v1/batch/copyprefix
+// ----------
+// Starts a background job which server-side copies all objects under a
+// prefix to a prefix of a target bucket. Returns the ID of the job.
+func (a adminAPIHandlers) StartBatchCopyPrefixJobHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := newContext(r, w, "StartBatchCopyPrefixJob")
+
+	objectAPI := validateAdminReq(ctx, w, r)
+	if objectAPI == nil {
+		return
+	}
+
+	// Deny if WORM is enabled
+	if globalWORMEnabled {
+		writeErrorResponseJSON(ctx, w, errorCodes.ToAPIErr(ErrMethodNotAllowed), r.URL)
+		return
+	}
+
+	if r.ContentLength > maxEConfigJSONSize || r.ContentLength == -1 {
+		writeErrorResponseJSON(ctx, w, errorCodes.ToAPIErr(ErrAdminConfigTooLarge), r.URL)
+		return
+	}
+
+	var job madmin.BatchCopyPrefixJob
+	if err := json.NewDecoder(io.LimitReader(r.Body, r.ContentLength)).Decode(&job); err != nil {
+		writeErrorResponseJSON(ctx, w, errorCodes.ToAPIErr(ErrAdminConfigBadJSON), r.URL)
+		return
+	}
+	if job.RateLimit < 0 {
+		writeErrorResponseJSON(ctx, w, errorCodes.ToAPIErr(ErrAdminInvalidArgument), r.URL)
+		return
+	}
+
+	id, err := globalBatchJobs.StartCopyPrefix(GlobalContext, objectAPI, job)
+	if err != nil {
+		if isBatchJobArgumentErr(err) {
+			writeCustomErrorResponseJSON(ctx, w, errorCodes.ToAPIErr(ErrAdminInvalidArgument), err.Error(), r.URL)
+			return
+		}
+		writeErrorResponseJSON(ctx, w, toAdminAPIErr(ctx, err), r.URL)
+		return
+	}
+
+	jsonBytes, err := json.Marshal(struct {
+		ID string `json:"id"`
+	}{id})
+	if err != nil {
+		writeErrorResponseJSON(ctx, w, toAdminAPIErr(ctx, err), r.URL)
+		return
+	}
+
+	writeSuccessResponseJSON(w, jsonBytes)
+}
+
+// StartBatchEncryptJobHandler - POST /minio/admin/v1/batch/encrypt
+// ----------
+// Starts a background job which encrypts all plaintext objects under a
+// prefix with SSE-S3. Returns the ID of the job.
+func (a adminAPIHandlers) StartBatchEncryptJobHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := newContext(r, w, "StartBatchEncryptJob")
+
+	objectAPI := validateAdminReq(ctx, w, r)
+	if objectAPI == nil {
+		return
+	}
+
+	// Deny if WORM is enabled
+	if globalWORMEnabled {
+		writeErrorResponseJSON(ctx, w, errorCodes.ToAPIErr(ErrMethodNotAllowed), r.URL)
+		return
+	}
+
+	if r.ContentLength > maxEConfigJSONSize || r.ContentLength == -1 {
+		writeErrorResponseJSON(ctx, w, errorCodes.ToAPIErr(ErrAdminConfigTooLarge), r.URL)
+		return
+	}
+
+	var job madmin.BatchEncryptJob
+	if err := json.NewDecoder(io.LimitReader(r.Body, r.ContentLength)).Decode(&job); err != nil {
+		writeErrorResponseJSON(ctx, w, errorCodes.ToAPIErr(ErrAdminConfigBadJSON), r.URL)
+		return
+	}
+	if job.RateLimit < 0 {
+		writeErrorResponseJSON(ctx, w, errorCodes.ToAPIErr(ErrAdminInvalidArgument), r.URL)
+		return
+	}
+
+	id, err := globalBatchJobs.StartEncrypt(GlobalContext, objectAPI, job)
+	if err != nil {
+		if isBatchJobArgumentErr(err) {
+			writeCustomErrorResponseJSON(ctx, w, errorCodes.ToAPIErr(ErrAdminInvalidArgument), err.Error(), r.URL)
+			return
+		}
+		writeErrorResponseJSON(ctx, w, toAdminAPIErr(ctx, err), r.URL)
+		return
+	}
+
+	jsonBytes, err := json.Marshal(struct {
+		ID string `json:"id"`
+	}{id})
+	if err != nil {
+		writeErrorResponseJSON(ctx, w, toAdminAPIErr(ctx, err), r.URL)
+		return
+	}
+
+	writeSuccessResponseJSON(w, jsonBytes)
+}
+
+// BucketEncryptionReportHandler - GET /minio/admin/v1/bucket-encryption-report?bucket={bucket}&prefix={prefix}
+// ----------
+// Scans the objects under a prefix of a bucket and returns how many of
+// them are plaintext, SSE-S3, SSE-C or SSE-KMS encrypted.
+func (a adminAPIHandlers) BucketEncryptionReportHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := newContext(r, w, "BucketEncryptionReport")
+
+	objectAPI := validateAdminReq(ctx, w, r)
+	if objectAPI == nil {
+		return
+	}
+
+	bucket := mux.Vars(r)["bucket"]
+	if isReservedOrInvalidBucket(bucket, false) {
+		writeErrorResponseJSON(ctx, w, errorCodes.ToAPIErr(ErrInvalidBucketName), r.URL)
+		return
+	}
+
+	report, err := getBucketEncryptionReport(ctx, objectAPI, bucket, r.URL.Query().Get("prefix"))
+	if err != nil {
+		writeErrorResponseJSON(ctx, w, toAdminAPIErr(ctx, err), r.URL)
+		return
+	}
+
+	jsonBytes, err := json.Marshal(report)
+	if err != nil {
+		writeErrorResponseJSON(ctx, w, toAdminAPIErr(ctx, err), r.URL)
+		return
+	}
+
+	writeSuccessResponseJSON(w, jsonBytes)
+}
+
+// BatchJobsStatusHandler - GET /minio/admin/v1/batch/jobs
+// ----------
+// Returns the progress of the batch jobs on all servers.
+func (a adminAPIHandlers) BatchJobsStatusHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := newContext(r, w, "BatchJobsStatus")
+
+	objectAPI := validateAdminReq(ctx, w, r)
+	if objectAPI == nil {
+		return
+	}
+
+	statuses := globalBatchJobs.Status()
+	if globalIsDistXL {
+		statuses = append(statuses, globalNotificationSys.BatchJobsStatus(ctx)...)
+	}
+
+	jsonBytes, err := json.Marshal(statuses)
+	if err != nil {
+		writeErrorResponseJSON(ctx, w, toAdminAPIErr(ctx, err), r.URL)
+		return
+	}
+
+	writeSuccessResponseJSON(w, jsonBytes)
+}
+
+// BatchJobReportHandler - GET /minio/admin/v1/batch/report?id={id}
+// ----------
+// Returns the progress of a batch job summed over all servers running
+// it, along with the objects which could not be processed.
+func (a adminAPIHandlers) BatchJobReportHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := newContext(r, w, "BatchJobReport")
+
+	objectAPI := validateAdminReq(ctx, w, r)
+	if objectAPI == nil {
+		return
+	}
+
+	id := mux.Vars(r)["id"]
+	if id == "" {
+		writeErrorResponseJSON(ctx, w, errorCodes.ToAPIErr(ErrAdminInvalidArgument), r.URL)
+		return
+	}
+
+	statuses := globalBatchJobs.Status()
+	if globalIsDistXL {
+		statuses = append(statuses, globalNotificationSys.BatchJobsStatus(ctx)...)
+	}
+	report, ok := getBatchJobReport(id, statuses)
+	if !ok {
+		writeCustomErrorResponseJSON(ctx, w, errorCodes.ToAPIErr(ErrAdminNoSuchBatchJob), "The specified batch job does not exist.", r.URL)
+		return
+	}
+
+	jsonBytes, err := json.Marshal(report)
+	if err != nil {
+		writeErrorResponseJSON(ctx, w, toAdminAPIErr(ctx, err), r.URL)
+		return
+	}
+
+	writeSuccessResponseJSON(w, jsonBytes)
+}
+
+// CancelBatchJobHandler - POST /minio/admin/v1/batch/cancel?id={id}
+// ----------
+// Stops a running batch job, on whichever server it runs.
+func (a adminAPIHandlers) CancelBatchJobHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := newContext(r, w, "CancelBatchJob")
+
+	objectAPI := validateAdminReq(ctx, w, r)
+	if objectAPI == nil {
+		return
+	}
+
+	id := mux.Vars(r)["id"]
+	if id == "" {
+		writeErrorResponseJSON(ctx, w, errorCodes.ToAPIErr(ErrAdminInvalidArgument), r.URL)
+		return
+	}
+
+	// Batch operation jobs run on all servers.
+	canceled := globalBatchJobs.Cancel(id)
+	if globalIsDistXL && globalNotificationSys.CancelBatchJob(ctx, id) {
+		canceled = true
+	}
+	if !canceled {
+		writeErrorResponseJSON(ctx, w, errorCodes.ToAPIErr(ErrAdminNoSuchBatchJob), r.URL)
+		return
+	}
+
+	writeSuccessResponseHeadersOnly(w)
+}
+
+// BackgroundJobsStatusHandler - GET /minio/admin/v1/background-jobs
+// ----------
+// Returns the schedule and the last run of the background jobs on all
+// servers.
+func (a adminAPIHandlers) BackgroundJobsStatusHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := newContext(r, w, "BackgroundJobsStatus")
+
+	objectAPI := validateAdminReq(ctx, w, r)
+	if objectAPI == nil {
+		return
+	}
+
+	statuses := globalBackgroundJobs.Status()
+	if globalIsDistXL {
+		statuses = append(statuses, globalNotificationSys.BackgroundJobsStatus(ctx)...)
+	}
+
+	jsonBytes, err := json.Marshal(statuses)
+	if err != nil {
+		writeErrorResponseJSON(ctx, w, toAdminAPIErr(ctx, err), r.URL)
+		return
+	}
+
+	writeSuccessResponseJSON(w, jsonBytes)
+}
+
+// BackgroundJobActionHandler - POST /minio/admin/v1/background-jobs?name={name}&action={action}
+// ----------
+// Pauses, resumes or runs right away a background job on all servers.
+func (a adminAPIHandlers) BackgroundJobActionHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := newContext(r, w, "BackgroundJobAction")
+
+	objectAPI := validateAdminReq(ctx, w, r)
+	if objectAPI == nil {
+		return
+	}
+
+	vars := mux.Vars(r)
+	name, action := vars["name"], vars["action"]
+	if err := globalBackgroundJobs.Do(name, action); err != nil {
+		if err == errBackgroundJobInvalidAction {
+			writeCustomErrorResponseJSON(ctx, w, errorCodes.ToAPIErr(ErrAdminInvalidArgument), err.Error(), r.URL)
+			return
+		}
+		writeErrorResponseJSON(ctx, w, toAdminAPIErr(ctx, err), r.URL)
+		return
+	}
+
+	// All servers run the same background jobs.
+	if globalIsDistXL {
+		for _, nerr := range globalNotificationSys.BackgroundJobAction(name, action) {
+			if nerr.Err != nil {
+				logger.GetReqInfo(ctx).SetTags("peerAddress", nerr.Host.String())
+				logger.LogIf(ctx, nerr.Err)
+			}
+		}
+	}
+
+	writeSuccessResponseHeadersOnly(w)
+}
+
+// SetBucketSnapshotConfigHandler - PUT /minio/admin/v1/snapshot/config?bucket={bucket}
+// ----------
+// Sets the schedule and the target of the snapshots of a bucket, the
+// configuration is encrypted with the admin secret key as it may
+// hold the credentials of a remote target.
+func (a adminAPIHandlers) SetBucketSnapshotConfigHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := newContext(r, w, "SetBucketSnapshotConfig")
+
+	objectAPI := validateAdminReq(ctx, w, r)
+	if objectAPI == nil {
+		return
+	}
+
+	bucket := mux.Vars(r)["bucket"]
+	if _, err := objectAPI.GetBucketInfo(ctx, bucket); err != nil {
+		writeErrorResponseJSON(ctx, w, toAdminAPIErr(ctx, err), r.URL)
+		return
+	}
+
+	if r.ContentLength > maxEConfigJSONSize || r.ContentLength == -1 {
+		writeErrorResponseJSON(ctx, w, errorCodes.ToAPIErr(ErrAdminConfigTooLarge), r.URL)
+		return
+	}
+
+	password := globalServerConfig.GetCredential().SecretKey
+	configBytes, err := madmin.DecryptData(password, io.LimitReader(r.Body, r.ContentLength))
+	if err != nil {
+		logger.LogIf(ctx, err)
+		writeErrorResponseJSON(ctx, w, errorCodes.ToAPIErr(ErrAdminConfigBadJSON), r.URL)
+		return
+	}
+
+	config, err := parseBucketSnapshotConfig(bucket, configBytes)
+	if err != nil {
+		writeCustomErrorResponseJSON(ctx, w, errorCodes.ToAPIErr(ErrAdminInvalidArgument), err.Error(), r.URL)
+		return
+	}
+
+	if err = saveBucketSnapshotConfig(ctx, objectAPI, bucket, config); err != nil {
+		writeErrorResponseJSON(ctx, w, toAdminAPIErr(ctx, err), r.URL)
+		return
+	}
+
+	// Other servers pick up the configuration on their next refresh,
+	// a snapshot is taken by a single server anyways.
+	globalBucketSnapshotSys.Set(bucket, *config)
+
+	writeSuccessResponseHeadersOnly(w)
+}
+
+// GetBucketSnapshotConfigHandler - GET /minio/admin/v1/snapshot/config?bucket={bucket}
+// ----------
+// Returns the encrypted snapshot configuration of a bucket.
+func (a adminAPIHandlers) GetBucketSnapshotConfigHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := newContext(r, w, "GetBucketSnapshotConfig")
+
+	objectAPI := validateAdminReq(ctx, w, r)
+	if objectAPI == nil {
+		return
+	}
+
+	config, err := getBucketSnapshotConfig(objectAPI, mux.Vars(r)["bucket"])
+	if err != nil {
+		writeErrorResponseJSON(ctx, w, toAdminAPIErr(ctx, err), r.URL)
+		return
+	}
+
+	data, err := json.Marshal(config)
+	if err != nil {
+		writeErrorResponseJSON(ctx, w, toAdminAPIErr(ctx, err), r.URL)
+		return
+	}
+
+	password := globalServerConfig.GetCredential().SecretKey
+	econfigData, err := madmin.EncryptData(password, data)
+	if err != nil {
+		writeErrorResponseJSON(ctx, w, toAdminAPIErr(ctx, err), r.URL)
+		return
+	}
+
+	writeSuccessResponseJSON(w, econfigData)
+}
+
+// RemoveBucketSnapshotConfigHandler - DELETE /minio/admin/v1/snapshot/config?bucket={bucket}
+// ----------
+// Stops taking snapshots of a bucket, existing snapshots are kept.
+func (a adminAPIHandlers) RemoveBucketSnapshotConfigHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := newContext(r, w, "RemoveBucketSnapshotConfig")
+
+	objectAPI := validateAdminReq(ctx, w, r)
+	if objectAPI == nil {
+		return
+	}
+
+	bucket := mux.Vars(r)["bucket"]
+	if err := removeBucketSnapshotConfig(ctx, objectAPI, bucket); err != nil {
+		writeErrorResponseJSON(ctx, w, toAdminAPIErr(ctx, err), r.URL)
+		return
+	}
+
+	globalBucketSnapshotSys.Remove(bucket)
+
+	writeSuccessResponseHeadersOnly(w)
+}
+
+// ListBucketSnapshotsHandler - GET /minio/admin/v1/snapshot/list?bucket={bucket}
+// ----------
+// Returns the snapshots of a bucket, oldest first.
+func (a adminAPIHandlers) ListBucketSnapshotsHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := newContext(r, w, "ListBucketSnapshots")
+
+	objectAPI := validateAdminReq(ctx, w, r)
+	if objectAPI == nil {
+		return
+	}
+
+	snapshots, err := listBucketSnapshots(ctx, objectAPI, mux.Vars(r)["bucket"])
+	if err != nil {
+		writeErrorResponseJSON(ctx, w, toAdminAPIErr(ctx, err), r.URL)
+		return
+	}
+
+	jsonBytes, err := json.Marshal(snapshots)
+	if err != nil {
+		writeErrorResponseJSON(ctx, w, toAdminAPIErr(ctx, err), r.URL)
+		return
+	}
+
+	writeSuccessResponseJSON(w, jsonBytes)
+}
+
+// RestoreBucketSnapshotHandler - POST /minio/admin/v1/snapshot/restore?bucket={bucket}&snapshot={snapshot}&targetBucket={targetBucket}
+// ----------
+// Copies all objects of a snapshot of a bucket to the target bucket,
+// responds once all objects are restored.
+func (a adminAPIHandlers) RestoreBucketSnapshotHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := newContext(r, w, "RestoreBucketSnapshot")
+
+	objectAPI := validateAdminReq(ctx, w, r)
+	if objectAPI == nil {
+		return
+	}
+
+	vars := mux.Vars(r)
+	restore, err := restoreBucketSnapshot(ctx, objectAPI, vars["bucket"], vars["snapshot"], vars["targetBucket"])
+	if err != nil {
+		writeErrorResponseJSON(ctx, w, toAdminAPIErr(ctx, err), r.URL)
+		return
+	}
+
+	jsonBytes, err := json.Marshal(restore)
+	if err != nil {
+		writeErrorResponseJSON(ctx, w, toAdminAPIErr(ctx, err), r.URL)
+		return
+	}
+
+	writeSuccessResponseJSON(w, jsonBytes)
+}
+
+// Returns true if the trace.Info should be traced,
+// false if certain conditions are not met.
+// - input entry is not of the type *trace.Info*
+// - errOnly entries are to be traced, not status code 2xx, 3xx.
+// - all entries to be traced, if not trace only S3 API requests.
+func mustTrace(entry interface{}, trcAll, errOnly bool) bool {
+	trcInfo, ok := entry.(trace.Info)
+	if !ok {
+		return false
+	}
+	trace := trcAll || !hasPrefix(trcInfo.ReqInfo.Path, minioReservedBucketPath+SlashSeparator)
+	if errOnly {
+		return trace && trcInfo.RespInfo.StatusCode >= http.StatusBadRequest
+	}
+	return trace
+}
+
+// CacheEventsHandler - GET /minio/admin/v1/cache/events
+// ----------
+// The handler sends the disk cache eviction and purge events of all
+// servers to the connected HTTP client.
+func (a adminAPIHandlers) CacheEventsHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := newContext(r, w, "CacheEvents")
+
+	// Validate request signature.
+	adminAPIErr := checkAdminRequestAuthType(ctx, r, "")
+	if adminAPIErr != ErrNone {
+		writeErrorResponseJSON(ctx, w, errorCodes.ToAPIErr(adminAPIErr), r.URL)
+		return
+	}
+
+	w.Header().Set(xhttp.ContentType, "text/event-stream")
+
+	doneCh := make(chan struct{})
+	defer close(doneCh)
+
+	// Cache events publisher and peer clients use nonblocking send and hence do not wait for slow receivers.
+	// Use buffered channel to take care of burst sends or slow w.Write()
+	eventCh := make(chan interface{}, 4000)
+
+	peers, err := getRestClients(getRemoteHosts(globalEndpoints))
+	if err != nil {
+		return
+	}
+
+	globalCacheEvents.Subscribe(eventCh, doneCh, func(entry interface{}) bool {
+		return true
+	})
+
+	for _, peer := range peers {
+		peer.CacheEvents(eventCh, doneCh)
+	}
+
+	keepAliveTicker := time.NewTicker(500 * time.Millisecond)
+	defer keepAliveTicker.Stop()
+
+	enc := json.NewEncoder(w)
+	for {
+		select {
+		case entry := <-eventCh:
+			if err := enc.Encode(entry); err != nil {
+				return
+			}
+			w.(http.Flusher).Flush()
+		case <-keepAliveTicker.C:
+			if _, err := w.Write([]byte(" ")); err != nil {
+				return
+			}
+			w.(http.Flusher).Flush()
+		case <-GlobalServiceDoneCh:
+			return
+		}
+	}
+}
+
+// InspectOrphansHandler - GET /minio/admin/v1/orphans?remove={bool}&olderThan={duration}
+// ----------
+// Finds the orphaned multipart parts, temporary files and bucket
+// configurations on all the disks of all the servers, optionally
+// removing them. The result of each disk is streamed as soon as the
+// disk is inspected.
+func (a adminAPIHandlers) InspectOrphansHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := newContext(r, w, "InspectOrphans")
+
+	objectAPI := validateAdminReq(ctx, w, r)
+	if objectAPI == nil {
+		return
+	}
+
+	remove := r.URL.Query().Get("remove") == "true"
+	olderThan := defaultOrphanAge
+	if v := r.URL.Query().Get("olderThan"); v != "" {
+		var err error
+		if olderThan, err = time.ParseDuration(v); err != nil || olderThan < 0 {
+			writeErrorResponseJSON(ctx, w, errorCodes.ToAPIErr(ErrAdminInvalidArgument), r.URL)
+			return
+		}
+	}
+
+	// Deny removals if WORM is enabled
+	if remove && globalWORMEnabled {
+		writeErrorResponseJSON(ctx, w, errorCodes.ToAPIErr(ErrMethodNotAllowed), r.URL)
+		return
+	}
+
+	w.Header().Set(xhttp.ContentType, "text/event-stream")
+
+	// The inspection stops once the client goes away, as ctx is
+	// canceled with the request.
+	resultCh := make(chan madmin.OrphanDiskResult)
+	go func() {
+		var wg sync.WaitGroup
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			inspectLocalOrphans(ctx, objectAPI, remove, olderThan, resultCh)
+		}()
+		if globalIsDistXL {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				globalNotificationSys.InspectOrphans(ctx, remove, olderThan, resultCh)
+			}()
+		}
+		wg.Wait()
+		close(resultCh)
+	}()
+
+	keepAliveTicker := time.NewTicker(500 * time.Millisecond)
+	defer keepAliveTicker.Stop()
+
+	enc := json.NewEncoder(w)
+	for {
+		select {
+		case result, ok := <-resultCh:
+			if !ok {
+				return
+			}
+			if err := enc.Encode(result); err != nil {
+				return
+			}
+			w.(http.Flusher).Flush()
+		case <-keepAliveTicker.C:
+			if _, err := w.Write([]byte(" ")); err != nil {
+				return
+			}
+			w.(http.Flusher).Flush()
+		}
 	}
-	return trace
 }
 
 // TraceHandler - POST /minio/admin/v1/trace
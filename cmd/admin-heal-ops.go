@@ -345,6 +345,9 @@ type healSequence struct {
 	// heal-stop API)
 	stopSignalCh chan struct{}
 
+	// ensures stopSignalCh is closed only once
+	stopOnce sync.Once
+
 	// the last result index sent to client
 	lastSentResultIndex int64
 
@@ -365,7 +368,7 @@ func newHealSequence(bucket, objPrefix, clientAddr string,
 
 	reqInfo := &logger.ReqInfo{RemoteHost: clientAddr, API: "Heal", BucketName: bucket}
 	reqInfo.AppendTags("prefix", objPrefix)
-	ctx := logger.SetReqInfo(context.Background(), reqInfo)
+	ctx := logger.SetReqInfo(GlobalContext, reqInfo)
 
 	return &healSequence{
 		bucket:         bucket,
@@ -410,11 +413,9 @@ func (h *healSequence) hasEnded() bool {
 
 // stops the heal sequence - safe to call multiple times.
 func (h *healSequence) stop() {
-	select {
-	case <-h.stopSignalCh:
-	default:
+	h.stopOnce.Do(func() {
 		close(h.stopSignalCh)
-	}
+	})
 }
 
 // pushHealResultItem - pushes a heal result item for consumption in
@@ -511,6 +512,18 @@ func (h *healSequence) healSequenceStart() {
 		go h.healFromSourceCh()
 	}
 
+	// Stop the heal sequence when the server is shutting down.
+	doneCh := make(chan struct{})
+	defer close(doneCh)
+	go func() {
+		select {
+		case <-h.ctx.Done():
+			h.stop()
+		case <-h.stopSignalCh:
+		case <-doneCh:
+		}
+	}()
+
 	select {
 	case err, ok := <-h.traverseAndHealDoneCh:
 		h.endTime = UTCNow()
@@ -545,12 +558,21 @@ func (h *healSequence) healSequenceStart() {
 }
 
 func (h *healSequence) queueHealTask(path string, healType madmin.HealItemType) error {
-	var respCh = make(chan healResult)
-	defer close(respCh)
+	// Buffered so that the heal routine never blocks on an
+	// abandoned request when the server is shutting down.
+	var respCh = make(chan healResult, 1)
 	// Send heal request
-	globalBackgroundHealing.queueHealTask(healTask{path: path, responseCh: respCh, opts: h.settings})
+	task := healTask{path: path, responseCh: respCh, opts: h.settings}
+	if err := globalBackgroundHealing.queueHealTask(h.ctx, task); err != nil {
+		return err
+	}
 	// Wait for answer and push result to the client
-	res := <-respCh
+	var res healResult
+	select {
+	case res = <-respCh:
+	case <-h.ctx.Done():
+		return h.ctx.Err()
+	}
 	if !h.reportProgress {
 		return nil
 	}
@@ -583,7 +605,17 @@ func (h *healSequence) healItemsFromSourceCh() error {
 		logger.LogIf(h.ctx, err)
 	}
 
-	for path := range h.sourceCh {
+	for {
+		var path string
+		var ok bool
+		select {
+		case path, ok = <-h.sourceCh:
+			if !ok {
+				return nil
+			}
+		case <-h.ctx.Done():
+			return h.ctx.Err()
+		}
 
 		var itemType madmin.HealItemType
 		switch {
@@ -602,8 +634,6 @@ func (h *healSequence) healItemsFromSourceCh() error {
 		h.scannedItemsCount++
 		h.lastHealActivity = UTCNow()
 	}
-
-	return nil
 }
 
 func (h *healSequence) healFromSourceCh() {
@@ -69,10 +69,15 @@ func registerAdminRouter(router *mux.Router, enableConfigOps, enableIAMOps bool)
 	// Performance command - return performance details based on input type
 	adminV1Router.Methods(http.MethodGet).Path("/performance").HandlerFunc(httpTraceAll(adminAPI.PerfInfoHandler)).Queries("perfType", "{perfType:.*}")
 
+	// Notification targets health
+	adminV1Router.Methods(http.MethodGet).Path("/notification/health").HandlerFunc(httpTraceAll(adminAPI.TargetsHealthHandler))
+
 	// Profiling operations
 	adminV1Router.Methods(http.MethodPost).Path("/profiling/start").HandlerFunc(httpTraceAll(adminAPI.StartProfilingHandler)).
 		Queries("profilerType", "{profilerType:.*}")
 	adminV1Router.Methods(http.MethodGet).Path("/profiling/download").HandlerFunc(httpTraceAll(adminAPI.DownloadProfilingHandler))
+	// Goroutine, heap and thread creation snapshots of a node
+	adminV1Router.Methods(http.MethodGet).Path("/diagnostics").HandlerFunc(httpTraceHdrs(adminAPI.DiagnosticsHandler))
 
 	/// Config operations
 	if enableConfigOps {
@@ -85,6 +90,44 @@ func registerAdminRouter(router *mux.Router, enableConfigOps, enableIAMOps bool)
 		adminV1Router.Methods(http.MethodGet).Path("/config-keys").HandlerFunc(httpTraceHdrs(adminAPI.GetConfigKeysHandler))
 		// Set config keys/values
 		adminV1Router.Methods(http.MethodPut).Path("/config-keys").HandlerFunc(httpTraceHdrs(adminAPI.SetConfigKeysHandler))
+
+		// Backup and restore the whole configuration
+		adminV1Router.Methods(http.MethodGet).Path("/config/backup").HandlerFunc(httpTraceHdrs(adminAPI.BackupConfigHandler))
+		adminV1Router.Methods(http.MethodPut).Path("/config/restore").HandlerFunc(httpTraceHdrs(adminAPI.RestoreConfigHandler))
+
+		// Export and import the configuration of a bucket
+		adminV1Router.Methods(http.MethodGet).Path("/bucket-metadata/export").HandlerFunc(httpTraceAll(adminAPI.ExportBucketMetadataHandler)).Queries("bucket", "{bucket:.*}")
+		adminV1Router.Methods(http.MethodPut).Path("/bucket-metadata/import").HandlerFunc(httpTraceAll(adminAPI.ImportBucketMetadataHandler)).Queries("bucket", "{bucket:.*}")
+
+		// Get bandwidth limits
+		adminV1Router.Methods(http.MethodGet).Path("/bandwidth").HandlerFunc(httpTraceAll(adminAPI.GetBandwidthLimitsHandler))
+		// Set bandwidth limits
+		adminV1Router.Methods(http.MethodPut).Path("/bandwidth").HandlerFunc(httpTraceHdrs(adminAPI.SetBandwidthLimitsHandler))
+
+		// Get bucket modes
+		adminV1Router.Methods(http.MethodGet).Path("/bucket-mode").HandlerFunc(httpTraceAll(adminAPI.GetBucketModesHandler))
+		// Set the mode of a bucket
+		adminV1Router.Methods(http.MethodPut).Path("/bucket-mode").HandlerFunc(httpTraceAll(adminAPI.SetBucketModeHandler)).
+			Queries("bucket", "{bucket:.*}", "mode", "{mode:.*}")
+
+		// Get bucket key normalization forms
+		adminV1Router.Methods(http.MethodGet).Path("/bucket-key-normalization").HandlerFunc(httpTraceAll(adminAPI.GetBucketKeyNormalizationHandler))
+		// Set the key normalization form of a bucket
+		adminV1Router.Methods(http.MethodPut).Path("/bucket-key-normalization").HandlerFunc(httpTraceAll(adminAPI.SetBucketKeyNormalizationHandler)).
+			Queries("bucket", "{bucket:.*}", "form", "{form:.*}")
+
+		// Add and remove cache drives and exclude patterns
+		adminV1Router.Methods(http.MethodPut).Path("/cache/config").HandlerFunc(httpTraceHdrs(adminAPI.UpdateCacheConfigHandler))
+
+		// Get, set and remove bucket snapshot config
+		adminV1Router.Methods(http.MethodGet).Path("/snapshot/config").HandlerFunc(httpTraceHdrs(adminAPI.GetBucketSnapshotConfigHandler)).Queries("bucket", "{bucket:.*}")
+		adminV1Router.Methods(http.MethodPut).Path("/snapshot/config").HandlerFunc(httpTraceHdrs(adminAPI.SetBucketSnapshotConfigHandler)).Queries("bucket", "{bucket:.*}")
+		adminV1Router.Methods(http.MethodDelete).Path("/snapshot/config").HandlerFunc(httpTraceHdrs(adminAPI.RemoveBucketSnapshotConfigHandler)).Queries("bucket", "{bucket:.*}")
+		// List bucket snapshots
+		adminV1Router.Methods(http.MethodGet).Path("/snapshot/list").HandlerFunc(httpTraceAll(adminAPI.ListBucketSnapshotsHandler)).Queries("bucket", "{bucket:.*}")
+		// Restore a bucket snapshot
+		adminV1Router.Methods(http.MethodPost).Path("/snapshot/restore").HandlerFunc(httpTraceAll(adminAPI.RestoreBucketSnapshotHandler)).
+			Queries("bucket", "{bucket:.*}", "snapshot", "{snapshot:.*}", "targetBucket", "{targetBucket:.*}")
 	}
 
 	if enableIAMOps {
@@ -130,14 +173,53 @@ func registerAdminRouter(router *mux.Router, enableConfigOps, enableIAMOps bool)
 
 		// List policies
 		adminV1Router.Methods(http.MethodGet).Path("/list-canned-policies").HandlerFunc(httpTraceHdrs(adminAPI.ListCannedPolicies))
+
+		// Simulate a request against the policies
+		adminV1Router.Methods(http.MethodPost).Path("/simulate-policy").HandlerFunc(httpTraceHdrs(adminAPI.SimulatePolicyHandler))
 	}
 
+	// -- KMS APIs --
+	adminV1Router.Methods(http.MethodPost).Path("/kms/key/rotate").HandlerFunc(httpTraceHdrs(adminAPI.RotateKMSKeyHandler))
+	adminV1Router.Methods(http.MethodPost).Path("/kms/key/sweep").HandlerFunc(httpTraceHdrs(adminAPI.StartKMSKeySweepHandler))
+	adminV1Router.Methods(http.MethodGet).Path("/kms/key/sweep/status").HandlerFunc(httpTraceHdrs(adminAPI.KMSKeySweepStatusHandler))
+
+	// -- Batch job APIs --
+	adminV1Router.Methods(http.MethodPost).Path("/batch/update").HandlerFunc(httpTraceHdrs(adminAPI.StartBatchUpdateJobHandler))
+	adminV1Router.Methods(http.MethodPost).Path("/batch/operation").HandlerFunc(httpTraceHdrs(adminAPI.StartBatchOperationJobHandler))
+	adminV1Router.Methods(http.MethodPost).Path("/batch/copyprefix").HandlerFunc(httpTraceHdrs(adminAPI.StartBatchCopyPrefixJobHandler))
+	adminV1Router.Methods(http.MethodPost).Path("/batch/encrypt").HandlerFunc(httpTraceHdrs(adminAPI.StartBatchEncryptJobHandler))
+	adminV1Router.Methods(http.MethodGet).Path("/bucket-encryption-report").HandlerFunc(httpTraceHdrs(adminAPI.BucketEncryptionReportHandler)).Queries("bucket", "{bucket:.*}")
+	adminV1Router.Methods(http.MethodGet).Path("/batch/jobs").HandlerFunc(httpTraceHdrs(adminAPI.BatchJobsStatusHandler))
+	adminV1Router.Methods(http.MethodGet).Path("/batch/report").HandlerFunc(httpTraceHdrs(adminAPI.BatchJobReportHandler)).Queries("id", "{id:.*}")
+	adminV1Router.Methods(http.MethodPost).Path("/batch/cancel").HandlerFunc(httpTraceHdrs(adminAPI.CancelBatchJobHandler)).Queries("id", "{id:.*}")
+
+	// -- Background job APIs --
+	adminV1Router.Methods(http.MethodGet).Path("/background-jobs").HandlerFunc(httpTraceHdrs(adminAPI.BackgroundJobsStatusHandler))
+	adminV1Router.Methods(http.MethodPost).Path("/background-jobs").HandlerFunc(httpTraceHdrs(adminAPI.BackgroundJobActionHandler)).
+		Queries("name", "{name:.*}", "action", "{action:.*}")
+
+	// -- Orphaned files APIs --
+	adminV1Router.Methods(http.MethodGet).Path("/orphans").HandlerFunc(httpTraceHdrs(adminAPI.InspectOrphansHandler))
+
 	// -- Top APIs --
 	// Top locks
 	adminV1Router.Methods(http.MethodGet).Path("/top/locks").HandlerFunc(httpTraceHdrs(adminAPI.TopLocksHandler))
 
+	// In-flight S3 requests
+	adminV1Router.Methods(http.MethodGet).Path("/top/requests").HandlerFunc(httpTraceHdrs(adminAPI.ListRequestsHandler))
+	adminV1Router.Methods(http.MethodPost).Path("/cancel-request").HandlerFunc(httpTraceHdrs(adminAPI.CancelRequestHandler)).Queries("id", "{id:.*}")
+
+	// Liveness of the servers
+	adminV1Router.Methods(http.MethodGet).Path("/membership").HandlerFunc(httpTraceAll(adminAPI.MembershipHandler))
+
+	// Access key usage
+	adminV1Router.Methods(http.MethodGet).Path("/access-key-usage").HandlerFunc(httpTraceHdrs(adminAPI.AccessKeyUsageHandler))
+
 	// HTTP Trace
 	adminV1Router.Methods(http.MethodGet).Path("/trace").HandlerFunc(adminAPI.TraceHandler)
+
+	// Disk cache events
+	adminV1Router.Methods(http.MethodGet).Path("/cache/events").HandlerFunc(adminAPI.CacheEventsHandler)
 	// If none of the routes match, return error.
 	adminV1Router.NotFoundHandler = http.HandlerFunc(httpTraceHdrs(notFoundHandlerJSON))
 }
@@ -63,6 +63,8 @@ type APIErrorCode int
 const (
 	ErrNone APIErrorCode = iota
 	ErrAccessDenied
+	ErrCORSNotEnabled
+	ErrCORSNotAllowed
 	ErrBadDigest
 	ErrEntityTooSmall
 	ErrEntityTooLarge
@@ -86,6 +88,7 @@ const (
 	ErrInvalidCopyDest
 	ErrInvalidPolicyDocument
 	ErrInvalidObjectState
+	ErrInvalidTargetBucketForLogging
 	ErrMalformedXML
 	ErrMissingContentLength
 	ErrMissingContentMD5
@@ -93,6 +96,8 @@ const (
 	ErrNoSuchBucket
 	ErrNoSuchBucketPolicy
 	ErrNoSuchBucketLifecycle
+	ErrNoSuchCORSConfiguration
+	ErrNoSuchWebsiteConfiguration
 	ErrNoSuchKey
 	ErrNoSuchUpload
 	ErrNoSuchVersion
@@ -188,6 +193,14 @@ const (
 	ErrStorageFull
 	ErrRequestBodyParse
 	ErrObjectExistsAsDirectory
+	ErrObjectTooManyAppends
+	ErrObjectNotCached
+	ErrBucketReadOnly
+	ErrBucketSuspended
+	ErrAnonymousUploadQuotaExceeded
+	ErrContentTypeNotAllowed
+	ErrComposeInvalidSources
+	ErrComposeEncryptedSource
 	ErrInvalidObjectName
 	ErrInvalidObjectNamePrefixSlash
 	ErrInvalidResourceName
@@ -206,6 +219,17 @@ const (
 	ErrAdminNoSuchGroup
 	ErrAdminGroupNotEmpty
 	ErrAdminNoSuchPolicy
+	ErrAdminNoSuchRequest
+	ErrAdminKMSKeyRotationNotSupported
+	ErrAdminKMSKeySweepInProgress
+	ErrAdminNoSuchBatchJob
+	ErrAdminNoSuchBackgroundJob
+	ErrAdminNoSuchBucketSnapshotConfig
+	ErrAdminNoSuchBucketSnapshot
+	ErrAdminInvalidConfigBackup
+	ErrAdminCacheNotInitialized
+	ErrAdminCacheConfigFromEnv
+	ErrAdminCacheMigrating
 	ErrAdminInvalidArgument
 	ErrAdminInvalidAccessKey
 	ErrAdminInvalidSecretKey
@@ -390,6 +414,16 @@ var errorCodes = errorCodeMap{
 		Description:    "Access Denied.",
 		HTTPStatusCode: http.StatusForbidden,
 	},
+	ErrCORSNotEnabled: {
+		Code:           "AccessForbidden",
+		Description:    "CORSResponse: CORS is not enabled for this bucket.",
+		HTTPStatusCode: http.StatusForbidden,
+	},
+	ErrCORSNotAllowed: {
+		Code:           "AccessForbidden",
+		Description:    "CORSResponse: This CORS request is not allowed. This is usually because the evaluation of Origin, request method / Access-Control-Request-Method or Access-Control-Request-Headers are not whitelisted by the resource's CORS spec.",
+		HTTPStatusCode: http.StatusForbidden,
+	},
 	ErrBadDigest: {
 		Code:           "BadDigest",
 		Description:    "The Content-Md5 you specified did not match what we received.",
@@ -440,6 +474,11 @@ var errorCodes = errorCodeMap{
 		Description:    "The requested range is not satisfiable",
 		HTTPStatusCode: http.StatusRequestedRangeNotSatisfiable,
 	},
+	ErrInvalidTargetBucketForLogging: {
+		Code:           "InvalidTargetBucketForLogging",
+		Description:    "The target bucket for logging does not exist.",
+		HTTPStatusCode: http.StatusBadRequest,
+	},
 	ErrMalformedXML: {
 		Code:           "MalformedXML",
 		Description:    "The XML you provided was not well-formed or did not validate against our published schema.",
@@ -475,6 +514,16 @@ var errorCodes = errorCodeMap{
 		Description:    "The bucket lifecycle configuration does not exist",
 		HTTPStatusCode: http.StatusNotFound,
 	},
+	ErrNoSuchCORSConfiguration: {
+		Code:           "NoSuchCORSConfiguration",
+		Description:    "The CORS configuration does not exist",
+		HTTPStatusCode: http.StatusNotFound,
+	},
+	ErrNoSuchWebsiteConfiguration: {
+		Code:           "NoSuchWebsiteConfiguration",
+		Description:    "The specified bucket does not have a website configuration",
+		HTTPStatusCode: http.StatusNotFound,
+	},
 	ErrNoSuchKey: {
 		Code:           "NoSuchKey",
 		Description:    "The specified key does not exist.",
@@ -895,6 +944,46 @@ var errorCodes = errorCodeMap{
 		Description:    "Object name already exists as a directory.",
 		HTTPStatusCode: http.StatusConflict,
 	},
+	ErrObjectTooManyAppends: {
+		Code:           "XMinioObjectTooManyAppends",
+		Description:    "The object has reached the maximum number of appends, rewrite it with PutObject to append to it again.",
+		HTTPStatusCode: http.StatusConflict,
+	},
+	ErrObjectNotCached: {
+		Code:           "XMinioObjectNotCached",
+		Description:    "The object is not cached and the request only allows cached objects.",
+		HTTPStatusCode: http.StatusGatewayTimeout,
+	},
+	ErrBucketReadOnly: {
+		Code:           "XMinioBucketReadOnly",
+		Description:    "The bucket is in read-only mode, writes and deletes are not allowed.",
+		HTTPStatusCode: http.StatusForbidden,
+	},
+	ErrBucketSuspended: {
+		Code:           "XMinioBucketSuspended",
+		Description:    "The bucket is suspended, please try again later.",
+		HTTPStatusCode: http.StatusServiceUnavailable,
+	},
+	ErrAnonymousUploadQuotaExceeded: {
+		Code:           "XMinioAnonymousUploadQuotaExceeded",
+		Description:    "The daily anonymous upload quota of the bucket is exceeded, please try again tomorrow.",
+		HTTPStatusCode: http.StatusForbidden,
+	},
+	ErrContentTypeNotAllowed: {
+		Code:           "XMinioContentTypeNotAllowed",
+		Description:    "The content type or the extension of the object is not allowed in the bucket.",
+		HTTPStatusCode: http.StatusForbidden,
+	},
+	ErrComposeInvalidSources: {
+		Code:           "XMinioComposeInvalidSources",
+		Description:    "A compose request must have between 1 and 32 source objects.",
+		HTTPStatusCode: http.StatusBadRequest,
+	},
+	ErrComposeEncryptedSource: {
+		Code:           "XMinioComposeEncryptedSource",
+		Description:    "Encrypted objects cannot be composed.",
+		HTTPStatusCode: http.StatusBadRequest,
+	},
 	ErrInvalidObjectName: {
 		Code:           "XMinioInvalidObjectName",
 		Description:    "Object name contains unsupported characters.",
@@ -940,6 +1029,61 @@ var errorCodes = errorCodeMap{
 		Description:    "The canned policy does not exist.",
 		HTTPStatusCode: http.StatusNotFound,
 	},
+	ErrAdminNoSuchRequest: {
+		Code:           "XMinioAdminNoSuchRequest",
+		Description:    "The specified request is not in progress.",
+		HTTPStatusCode: http.StatusNotFound,
+	},
+	ErrAdminKMSKeyRotationNotSupported: {
+		Code:           "XMinioAdminKMSKeyRotationNotSupported",
+		Description:    "The configured KMS does not support rotating master keys.",
+		HTTPStatusCode: http.StatusNotImplemented,
+	},
+	ErrAdminNoSuchBatchJob: {
+		Code:           "XMinioAdminNoSuchBatchJob",
+		Description:    "The specified batch job is not running.",
+		HTTPStatusCode: http.StatusNotFound,
+	},
+	ErrAdminNoSuchBackgroundJob: {
+		Code:           "XMinioAdminNoSuchBackgroundJob",
+		Description:    "The specified background job does not exist.",
+		HTTPStatusCode: http.StatusNotFound,
+	},
+	ErrAdminKMSKeySweepInProgress: {
+		Code:           "XMinioAdminKMSKeySweepInProgress",
+		Description:    "A KMS key re-encryption sweep is already in progress.",
+		HTTPStatusCode: http.StatusConflict,
+	},
+	ErrAdminNoSuchBucketSnapshotConfig: {
+		Code:           "XMinioAdminNoSuchBucketSnapshotConfig",
+		Description:    "The bucket snapshot configuration does not exist.",
+		HTTPStatusCode: http.StatusNotFound,
+	},
+	ErrAdminNoSuchBucketSnapshot: {
+		Code:           "XMinioAdminNoSuchBucketSnapshot",
+		Description:    "The specified bucket snapshot does not exist or is incomplete.",
+		HTTPStatusCode: http.StatusNotFound,
+	},
+	ErrAdminInvalidConfigBackup: {
+		Code:           "XMinioAdminInvalidConfigBackup",
+		Description:    "The configuration backup is invalid or was not taken with the same secret key.",
+		HTTPStatusCode: http.StatusBadRequest,
+	},
+	ErrAdminCacheNotInitialized: {
+		Code:           "XMinioAdminCacheNotInitialized",
+		Description:    "Disk caching was not enabled at server startup, restart the server with cache drives configured to enable it.",
+		HTTPStatusCode: http.StatusBadRequest,
+	},
+	ErrAdminCacheConfigFromEnv: {
+		Code:           "XMinioAdminCacheConfigFromEnv",
+		Description:    "The cache configuration is set through environment variables and cannot be changed at runtime.",
+		HTTPStatusCode: http.StatusBadRequest,
+	},
+	ErrAdminCacheMigrating: {
+		Code:           "XMinioAdminCacheMigrating",
+		Description:    "The cache is being migrated, please try again later.",
+		HTTPStatusCode: http.StatusConflict,
+	},
 	ErrAdminInvalidArgument: {
 		Code:           "XMinioAdminInvalidArgument",
 		Description:    "Invalid arguments specified.",
@@ -1555,6 +1699,28 @@ func toAPIErrorCode(ctx context.Context, err error) (apiErr APIErrorCode) {
 		apiErr = ErrIncompatibleEncryptionMethod
 	case errKMSNotConfigured:
 		apiErr = ErrKMSNotConfigured
+	case errKMSKeyRotationNotSupported:
+		apiErr = ErrAdminKMSKeyRotationNotSupported
+	case errKMSKeySweepInProgress:
+		apiErr = ErrAdminKMSKeySweepInProgress
+	case errBackgroundJobNotFound:
+		apiErr = ErrAdminNoSuchBackgroundJob
+	case errBucketSnapshotConfigNotFound:
+		apiErr = ErrAdminNoSuchBucketSnapshotConfig
+	case errBucketSnapshotNotFound:
+		apiErr = ErrAdminNoSuchBucketSnapshot
+	case errInvalidConfigBackup:
+		apiErr = ErrAdminInvalidConfigBackup
+	case errCacheNotInitialized:
+		apiErr = ErrAdminCacheNotInitialized
+	case errCacheConfigFromEnv:
+		apiErr = ErrAdminCacheConfigFromEnv
+	case errCacheMigrating:
+		apiErr = ErrAdminCacheMigrating
+	case errComposeInvalidSources:
+		apiErr = ErrComposeInvalidSources
+	case errComposeEncryptedSource:
+		apiErr = ErrComposeEncryptedSource
 	case crypto.ErrKMSAuthLogin:
 		apiErr = ErrKMSAuthFailure
 	case errOperationTimedOut, context.Canceled, context.DeadlineExceeded:
@@ -1633,6 +1799,8 @@ func toAPIErrorCode(ctx context.Context, err error) (apiErr APIErrorCode) {
 		apiErr = ErrNoSuchUpload
 	case PartTooSmall:
 		apiErr = ErrEntityTooSmall
+	case PreConditionFailed:
+		apiErr = ErrPreconditionFailed
 	case SignatureDoesNotMatch:
 		apiErr = ErrSignatureDoesNotMatch
 	case hash.SHA256Mismatch:
@@ -1641,6 +1809,18 @@ func toAPIErrorCode(ctx context.Context, err error) (apiErr APIErrorCode) {
 		apiErr = ErrEntityTooLarge
 	case ObjectTooSmall:
 		apiErr = ErrEntityTooSmall
+	case ObjectTooManyAppends:
+		apiErr = ErrObjectTooManyAppends
+	case ObjectNotCached:
+		apiErr = ErrObjectNotCached
+	case BucketReadOnly:
+		apiErr = ErrBucketReadOnly
+	case BucketSuspended:
+		apiErr = ErrBucketSuspended
+	case AnonymousUploadQuotaExceeded:
+		apiErr = ErrAnonymousUploadQuotaExceeded
+	case ContentTypeNotAllowed:
+		apiErr = ErrContentTypeNotAllowed
 	case NotImplemented:
 		apiErr = ErrNotImplemented
 	case PartTooBig:
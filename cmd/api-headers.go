@@ -102,20 +102,9 @@ func setObjectHeaders(w http.ResponseWriter, objInfo ObjectInfo, rs *HTTPRangeSp
 		w.Header().Set(k, v)
 	}
 
-	var totalObjectSize int64
-	switch {
-	case crypto.IsEncrypted(objInfo.UserDefined):
-		totalObjectSize, err = objInfo.DecryptedSize()
-		if err != nil {
-			return err
-		}
-	case objInfo.IsCompressed():
-		totalObjectSize = objInfo.GetActualSize()
-		if totalObjectSize < 0 {
-			return errInvalidDecompressedSize
-		}
-	default:
-		totalObjectSize = objInfo.Size
+	totalObjectSize, err := getObjectTotalSize(objInfo)
+	if err != nil {
+		return err
 	}
 
 	// for providing ranged content
@@ -133,3 +122,20 @@ func setObjectHeaders(w http.ResponseWriter, objInfo ObjectInfo, rs *HTTPRangeSp
 
 	return nil
 }
+
+// getObjectTotalSize returns the size of the object content as seen
+// by the client, i.e. after decryption and decompression.
+func getObjectTotalSize(objInfo ObjectInfo) (int64, error) {
+	switch {
+	case crypto.IsEncrypted(objInfo.UserDefined):
+		return objInfo.DecryptedSize()
+	case objInfo.IsCompressed():
+		totalObjectSize := objInfo.GetActualSize()
+		if totalObjectSize < 0 {
+			return 0, errInvalidDecompressedSize
+		}
+		return totalObjectSize, nil
+	default:
+		return objInfo.Size, nil
+	}
+}
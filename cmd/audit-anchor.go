@@ -0,0 +1,83 @@
+/*
+ * MinIO Cloud Storage, (C) 2019 MinIO, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"path"
+	"time"
+
+	"github.com/minio/minio/cmd/logger"
+	"github.com/minio/minio/cmd/logger/message/audit"
+)
+
+// Audit log anchors are saved under .minio.sys/audit/anchors/<stream>/
+const auditAnchorsPrefix = "audit/anchors"
+
+// Returns the path of an anchor, anchors of a stream sort by sequence number.
+func getAuditAnchorPath(anchor audit.Anchor) string {
+	return path.Join(auditAnchorsPrefix, anchor.Stream, fmt.Sprintf("%020d.json", anchor.Seq))
+}
+
+// publishAuditAnchors - saves the heads of the audit log chains which
+// moved since they were last published, published holds the sequence
+// numbers last published per stream.
+func publishAuditAnchors(ctx context.Context, objAPI ObjectLayer, published map[string]uint64) {
+	for _, chain := range logger.AuditChains() {
+		anchor := chain.Anchor()
+		if anchor.Seq == 0 || anchor.Seq == published[anchor.Stream] {
+			continue
+		}
+		anchor.Node = GetLocalPeer(globalEndpoints)
+		data, err := json.Marshal(anchor)
+		if err != nil {
+			logger.LogIf(ctx, err)
+			continue
+		}
+		if err = saveConfig(ctx, objAPI, getAuditAnchorPath(anchor), data); err != nil {
+			logger.LogIf(ctx, err)
+			continue
+		}
+		published[anchor.Stream] = anchor.Seq
+	}
+}
+
+// startAuditAnchorPublisher - periodically publishes the heads of the
+// audit log chains until doneCh is closed.
+func startAuditAnchorPublisher(objAPI ObjectLayer, doneCh <-chan struct{}) {
+	if len(logger.AuditChains()) == 0 {
+		return
+	}
+
+	ticker := time.NewTicker(globalAuditAnchorInterval)
+	defer ticker.Stop()
+
+	ctx := context.Background()
+	published := make(map[string]uint64)
+	for {
+		select {
+		case <-doneCh:
+			// Publish the last heads on shutdown.
+			publishAuditAnchors(ctx, objAPI, published)
+			return
+		case <-ticker.C:
+			publishAuditAnchors(ctx, objAPI, published)
+		}
+	}
+}
@@ -0,0 +1,131 @@
+/*
+ * MinIO Cloud Storage, (C) 2019 MinIO, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/minio/minio/cmd/logger"
+	"github.com/minio/minio/cmd/logger/message/audit"
+)
+
+// auditRecorder records the audit entries sent to it.
+type auditRecorder struct {
+	entries []audit.Entry
+	err     error
+}
+
+func (r *auditRecorder) Send(entry interface{}) error {
+	if r.err != nil {
+		return r.err
+	}
+	r.entries = append(r.entries, entry.(audit.Entry))
+	return nil
+}
+
+// Tests that chained audit entries are verified against published anchors.
+func TestAuditChainAnchors(t *testing.T) {
+	objLayer, fsDir, err := prepareFS()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer removeRoots([]string{fsDir})
+
+	recorder := &auditRecorder{}
+	chain := logger.NewChainTarget(recorder, mustGetUUID())
+	auditTargets := logger.AuditTargets
+	logger.AuditTargets = []logger.Target{chain}
+	defer func() { logger.AuditTargets = auditTargets }()
+
+	send := func(object string) {
+		entry := audit.NewEntry(globalDeploymentID)
+		entry.API.Name = "PutObject"
+		entry.API.Bucket = "bucket"
+		entry.API.Object = object
+		if err = chain.Send(entry); err != nil {
+			t.Fatal(err)
+		}
+	}
+	send("object1")
+	send("object2")
+
+	// Entries which could not be sent are not part of the chain.
+	recorder.err = errors.New("log buffer full")
+	if err = chain.Send(audit.NewEntry(globalDeploymentID)); err == nil {
+		t.Fatal("Expected send to fail")
+	}
+	recorder.err = nil
+	send("object3")
+
+	ctx := context.Background()
+	published := make(map[string]uint64)
+	publishAuditAnchors(ctx, objLayer, published)
+
+	anchor := chain.Anchor()
+	if anchor.Seq != 3 || published[anchor.Stream] != 3 {
+		t.Fatalf("Expected anchor at entry 3, got %d", anchor.Seq)
+	}
+	data, err := readConfig(ctx, objLayer, getAuditAnchorPath(anchor))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var saved audit.Anchor
+	if err = json.Unmarshal(data, &saved); err != nil {
+		t.Fatal(err)
+	}
+	if saved.Hash != anchor.Hash || saved.Node == "" {
+		t.Fatalf("Unexpected published anchor %v", saved)
+	}
+	anchors := []audit.Anchor{saved}
+
+	// Entries are verified once sent over the wire.
+	var entries []audit.Entry
+	for _, entry := range recorder.entries {
+		data, err = json.Marshal(entry)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var e audit.Entry
+		if err = json.Unmarshal(data, &e); err != nil {
+			t.Fatal(err)
+		}
+		entries = append(entries, e)
+	}
+	if err = audit.VerifyChain(entries, anchors); err != nil {
+		t.Fatal(err)
+	}
+	if err = audit.VerifyChain(entries[1:], anchors); err != nil {
+		t.Fatal(err)
+	}
+
+	// Truncated log.
+	if err = audit.VerifyChain(entries[:2], anchors); err == nil {
+		t.Fatal("Expected truncated audit log to be detected")
+	}
+	// Removed entry.
+	if err = audit.VerifyChain([]audit.Entry{entries[0], entries[2]}, nil); err == nil {
+		t.Fatal("Expected removed audit entry to be detected")
+	}
+	// Altered entry.
+	entries[1].API.Object = "other"
+	if err = audit.VerifyChain(entries, anchors); err == nil {
+		t.Fatal("Expected altered audit entry to be detected")
+	}
+}
@@ -126,8 +126,9 @@ func checkAdminRequestAuthType(ctx context.Context, r *http.Request, region stri
 		getRequestAuthType(r) == authTypeSigned && !skipContentSha256Cksum(r) {
 		// We only support admin credentials to access admin APIs.
 
+		var cred auth.Credentials
 		var owner bool
-		_, owner, s3Err = getReqAccessKeyV4(r, region, serviceS3)
+		cred, owner, s3Err = getReqAccessKeyV4(r, region, serviceS3)
 		if s3Err != ErrNone {
 			return s3Err
 		}
@@ -138,6 +139,9 @@ func checkAdminRequestAuthType(ctx context.Context, r *http.Request, region stri
 
 		// we only support V4 (no presign) with auth body
 		s3Err = isReqAuthenticated(ctx, r, region, serviceS3)
+		if s3Err == ErrNone {
+			globalAccessKeyUsage.record(cred.AccessKey)
+		}
 	}
 	if s3Err != ErrNone {
 		reqInfo := (&logger.ReqInfo{}).AppendTags("requestHeaders", dumpRequest(r))
@@ -253,6 +257,14 @@ func checkClaimsFromToken(r *http.Request, cred auth.Credentials) (map[string]in
 	return claims, ErrNone
 }
 
+// setRequestAccessKey - records the access key an S3 request was
+// authenticated with, anonymous requests are not recorded.
+func setRequestAccessKey(ctx context.Context, accessKey string) {
+	if req := getActiveRequest(ctx); req != nil && accessKey != "" {
+		req.setAccessKey(accessKey)
+	}
+}
+
 // Check request auth type verifies the incoming http request
 // - validates the request signature
 // - validates the policy action if anonymous tests bucket policies if any,
@@ -299,6 +311,7 @@ func checkRequestAuthTypeToAccessKey(ctx context.Context, r *http.Request, actio
 	if s3Err != ErrNone {
 		return accessKey, owner, s3Err
 	}
+	setRequestAccessKey(ctx, cred.AccessKey)
 
 	// LocationConstraint is valid only for CreateBucketAction.
 	var locationConstraint string
@@ -490,6 +503,7 @@ func isPutAllowed(atype authType, bucketName, objectName string, r *http.Request
 	if s3Err != ErrNone {
 		return s3Err
 	}
+	setRequestAccessKey(r.Context(), cred.AccessKey)
 
 	if cred.AccessKey == "" {
 		if globalPolicySys.IsAllowed(policy.Args{
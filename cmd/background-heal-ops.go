@@ -47,14 +47,20 @@ type healRoutine struct {
 	doneCh chan struct{}
 }
 
-// Add a new task in the tasks queue
-func (h *healRoutine) queueHealTask(task healTask) {
-	h.tasks <- task
+// Add a new task in the tasks queue, gives up if ctx is canceled
+// before the task could be queued.
+func (h *healRoutine) queueHealTask(ctx context.Context, task healTask) error {
+	select {
+	case h.tasks <- task:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
 }
 
 // Wait for heal requests and process them
 func (h *healRoutine) run() {
-	ctx := context.Background()
+	ctx := GlobalContext
 	for {
 		select {
 		case task, ok := <-h.tasks:
@@ -68,7 +74,10 @@ func (h *healRoutine) run() {
 				// Any requests in progress, delay the heal.
 				for globalHTTPServer.GetRequestCount() > 2 && waitCount > 0 {
 					waitCount--
-					time.Sleep(1 * time.Second)
+					if !sleepContext(ctx, time.Second) {
+						task.responseCh <- healResult{err: ctx.Err()}
+						return
+					}
 				}
 			}
 
@@ -84,9 +93,20 @@ func (h *healRoutine) run() {
 				res, err = bgHealObject(ctx, bucket, object, task.opts)
 			}
 			task.responseCh <- healResult{result: res, err: err}
+
+			// The object is locked while it is healed, throttle
+			// healing by pacing the next task with the healed bytes.
+			if object != "" && err == nil && globalBandwidthSys != nil {
+				before, after := res.GetOnlineCounts()
+				if healed := after - before; healed > 0 {
+					if globalBandwidthSys.Wait(ctx, bucket, res.ObjectSize*int64(healed)) != nil {
+						return
+					}
+				}
+			}
 		case <-h.doneCh:
 			return
-		case <-GlobalServiceDoneCh:
+		case <-ctx.Done():
 			return
 		}
 	}
@@ -0,0 +1,102 @@
+/*
+ * MinIO Cloud Storage, (C) 2019 MinIO, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/minio/minio/pkg/madmin"
+)
+
+// Tests that the heal routine quits when GlobalContext is canceled.
+func TestHealRoutineGlobalContextCanceled(t *testing.T) {
+	prevCtx, prevCancel := GlobalContext, cancelGlobalContext
+	defer func() {
+		GlobalContext, cancelGlobalContext = prevCtx, prevCancel
+	}()
+	GlobalContext, cancelGlobalContext = context.WithCancel(context.Background())
+
+	h := initHealRoutine()
+	doneCh := make(chan struct{})
+	go func() {
+		h.run()
+		close(doneCh)
+	}()
+
+	cancelGlobalContext()
+	select {
+	case <-doneCh:
+	case <-time.After(10 * time.Second):
+		t.Fatal("Heal routine did not quit after GlobalContext was canceled")
+	}
+}
+
+// Tests that queueing a heal task gives up once the context is canceled.
+func TestHealRoutineQueueCanceled(t *testing.T) {
+	h := initHealRoutine()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if err := h.queueHealTask(ctx, healTask{}); err != context.Canceled {
+		t.Fatalf("Expected %v, got %v", context.Canceled, err)
+	}
+}
+
+// Tests that a heal sequence stops waiting on heal results once its context is canceled.
+func TestHealSequenceContextCanceled(t *testing.T) {
+	prevHealing := globalBackgroundHealing
+	defer func() {
+		globalBackgroundHealing = prevHealing
+	}()
+	// Heal routine which never picks up tasks.
+	globalBackgroundHealing = initHealRoutine()
+
+	h := newHealSequence("bucket", "", "127.0.0.1", 4, madmin.HealOpts{}, false)
+	var cancel context.CancelFunc
+	h.ctx, cancel = context.WithCancel(h.ctx)
+	cancel()
+
+	if err := h.queueHealTask("bucket/object", madmin.HealItemObject); err != context.Canceled {
+		t.Fatalf("Expected %v, got %v", context.Canceled, err)
+	}
+}
+
+// Tests that a heal sequence can be stopped concurrently and repeatedly.
+func TestHealSequenceStop(t *testing.T) {
+	h := newHealSequence("bucket", "", "127.0.0.1", 4, madmin.HealOpts{}, false)
+	if h.isQuitting() {
+		t.Fatal("Expected heal sequence not to be quitting")
+	}
+
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			h.stop()
+		}()
+	}
+	wg.Wait()
+	h.stop()
+
+	if !h.isQuitting() {
+		t.Fatal("Expected heal sequence to be quitting")
+	}
+}
@@ -0,0 +1,232 @@
+/*
+ * MinIO Cloud Storage, (C) 2020 MinIO, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/minio/minio/cmd/logger"
+	"github.com/minio/minio/pkg/madmin"
+)
+
+var (
+	errBackgroundJobNotFound      = errors.New("No such background job")
+	errBackgroundJobInvalidAction = errors.New("Background job action must be one of pause, resume or run")
+)
+
+// backgroundJobFunc - runs a background job once. runNow is set when
+// the run was requested through the admin API, jobs which skip runs
+// when there is nothing to do must not skip it then.
+type backgroundJobFunc func(ctx context.Context, objAPI ObjectLayer, runNow bool) error
+
+// backgroundJob - a job run periodically by the server, on its own
+// timer so that slow jobs do not delay the others.
+type backgroundJob struct {
+	name     string
+	interval time.Duration
+	// runAtStart runs the job when the scheduler starts instead of
+	// after a first interval.
+	runAtStart bool
+	fn         backgroundJobFunc
+	runNowCh   chan struct{}
+
+	mu     sync.Mutex
+	status madmin.BackgroundJobStatus
+}
+
+// backgroundJobScheduler - holds the background jobs of this server.
+type backgroundJobScheduler struct {
+	mu   sync.Mutex
+	jobs []*backgroundJob
+}
+
+var globalBackgroundJobs = &backgroundJobScheduler{}
+
+// initBackgroundJobs - registers the periodic jobs of the server and
+// starts running them.
+func initBackgroundJobs(objAPI ObjectLayer) {
+	globalBackgroundJobs.Register("lifecycle", bgLifecycleTick, true, runDailyLifecycle)
+	globalBackgroundJobs.Register("trash-purge", trashPurgeInterval, false,
+		func(ctx context.Context, objAPI ObjectLayer, runNow bool) error {
+			return purgeTrash(ctx, objAPI)
+		})
+	globalBackgroundJobs.Register("anonymous-upload-expiry", anonymousUploadExpiryInterval, false,
+		func(ctx context.Context, objAPI ObjectLayer, runNow bool) error {
+			return expireAnonymousUploads(ctx, objAPI)
+		})
+	globalBackgroundJobs.Start(GlobalContext, objAPI, GlobalServiceDoneCh)
+}
+
+// Register - adds a job to the scheduler, jobs must be registered
+// before the scheduler is started.
+func (s *backgroundJobScheduler) Register(name string, interval time.Duration, runAtStart bool, fn backgroundJobFunc) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.jobs = append(s.jobs, &backgroundJob{
+		name:       name,
+		interval:   interval,
+		runAtStart: runAtStart,
+		fn:         fn,
+		runNowCh:   make(chan struct{}, 1),
+		status: madmin.BackgroundJobStatus{
+			Name:     name,
+			Interval: interval,
+		},
+	})
+}
+
+// Start - runs each registered job on its schedule until doneCh is
+// closed.
+func (s *backgroundJobScheduler) Start(ctx context.Context, objAPI ObjectLayer, doneCh <-chan struct{}) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, j := range s.jobs {
+		go j.schedule(ctx, objAPI, doneCh)
+	}
+}
+
+func (s *backgroundJobScheduler) get(name string) (*backgroundJob, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, j := range s.jobs {
+		if j.name == name {
+			return j, nil
+		}
+	}
+	return nil, errBackgroundJobNotFound
+}
+
+// Status - returns the status of all background jobs of this server,
+// in the order they were registered.
+func (s *backgroundJobScheduler) Status() []madmin.BackgroundJobStatus {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	node := GetLocalPeer(globalEndpoints)
+	statuses := make([]madmin.BackgroundJobStatus, 0, len(s.jobs))
+	for _, j := range s.jobs {
+		status := j.Status()
+		status.Node = node
+		statuses = append(statuses, status)
+	}
+	return statuses
+}
+
+// Do - applies one of the madmin background job actions to the job
+// with the given name.
+func (s *backgroundJobScheduler) Do(name, action string) error {
+	j, err := s.get(name)
+	if err != nil {
+		return err
+	}
+
+	switch action {
+	case madmin.BackgroundJobPause:
+		j.setPaused(true)
+	case madmin.BackgroundJobResume:
+		j.setPaused(false)
+	case madmin.BackgroundJobRunNow:
+		// A run already requested is not requested twice.
+		select {
+		case j.runNowCh <- struct{}{}:
+		default:
+		}
+	default:
+		return errBackgroundJobInvalidAction
+	}
+	return nil
+}
+
+// Status - returns the schedule and the last run of the job.
+func (j *backgroundJob) Status() madmin.BackgroundJobStatus {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	return j.status
+}
+
+func (j *backgroundJob) setPaused(paused bool) {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	j.status.Paused = paused
+}
+
+func (j *backgroundJob) setNextRun(t time.Time) {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	j.status.NextRun = t
+}
+
+// schedule - runs the job every interval, unless paused, and whenever
+// a run is requested until doneCh is closed.
+func (j *backgroundJob) schedule(ctx context.Context, objAPI ObjectLayer, doneCh <-chan struct{}) {
+	wait := j.interval
+	if j.runAtStart {
+		wait = 0
+	}
+	timer := time.NewTimer(wait)
+	defer timer.Stop()
+	j.setNextRun(UTCNow().Add(wait))
+
+	for {
+		runNow := false
+		select {
+		case <-doneCh:
+			return
+		case <-ctx.Done():
+			return
+		case <-timer.C:
+			timer.Reset(j.interval)
+			j.setNextRun(UTCNow().Add(j.interval))
+			if j.Status().Paused {
+				continue
+			}
+		case <-j.runNowCh:
+			runNow = true
+		}
+		j.run(ctx, objAPI, runNow)
+	}
+}
+
+// run - runs the job once and records the outcome.
+func (j *backgroundJob) run(ctx context.Context, objAPI ObjectLayer, runNow bool) {
+	start := UTCNow()
+	j.mu.Lock()
+	j.status.Running = true
+	j.mu.Unlock()
+
+	err := j.fn(ctx, objAPI, runNow)
+	if ctx.Err() == nil {
+		logger.LogIf(ctx, err)
+	}
+
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	j.status.Running = false
+	j.status.Runs++
+	j.status.LastRun = start
+	j.status.LastDuration = UTCNow().Sub(start)
+	j.status.LastError = ""
+	if err != nil {
+		j.status.LastError = err.Error()
+	}
+}
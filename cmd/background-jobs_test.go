@@ -0,0 +1,98 @@
+/*
+ * MinIO Cloud Storage, (C) 2020 MinIO, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/minio/minio/pkg/madmin"
+)
+
+// Tests that background jobs run on their schedule unless paused, and
+// whenever a run is requested.
+func TestBackgroundJobScheduler(t *testing.T) {
+	s := &backgroundJobScheduler{}
+	runs := make(chan bool, 10)
+	errJob := errors.New("job failed")
+	s.Register("job", 50*time.Millisecond, true, func(ctx context.Context, objAPI ObjectLayer, runNow bool) error {
+		runs <- runNow
+		return errJob
+	})
+
+	doneCh := make(chan struct{})
+	defer close(doneCh)
+	s.Start(context.Background(), nil, doneCh)
+
+	waitRun := func(expected bool) {
+		t.Helper()
+		select {
+		case runNow := <-runs:
+			if runNow != expected {
+				t.Fatalf("Expected a run with runNow %v, got %v", expected, runNow)
+			}
+		case <-time.After(5 * time.Second):
+			t.Fatal("Timed out waiting for a run of the job")
+		}
+	}
+
+	// The job runs at start, then on its schedule.
+	waitRun(false)
+	waitRun(false)
+
+	if err := s.Do("job", madmin.BackgroundJobPause); err != nil {
+		t.Fatal(err)
+	}
+	// A run may have started before the job was paused.
+	select {
+	case <-runs:
+	case <-time.After(100 * time.Millisecond):
+	}
+	select {
+	case <-runs:
+		t.Fatal("Expected a paused job not to run on its schedule")
+	case <-time.After(200 * time.Millisecond):
+	}
+
+	// Paused jobs still run when requested.
+	if err := s.Do("job", madmin.BackgroundJobRunNow); err != nil {
+		t.Fatal(err)
+	}
+	waitRun(true)
+
+	if err := s.Do("job", madmin.BackgroundJobResume); err != nil {
+		t.Fatal(err)
+	}
+	waitRun(false)
+
+	statuses := s.Status()
+	if len(statuses) != 1 || statuses[0].Name != "job" || statuses[0].Paused || statuses[0].Runs < 3 {
+		t.Fatalf("Unexpected job status %v", statuses)
+	}
+	if statuses[0].LastError != errJob.Error() {
+		t.Fatalf("Expected last error %q, got %q", errJob, statuses[0].LastError)
+	}
+
+	if err := s.Do("missing", madmin.BackgroundJobRunNow); err != errBackgroundJobNotFound {
+		t.Fatalf("Expected %v, got %v", errBackgroundJobNotFound, err)
+	}
+	if err := s.Do("job", "stop"); err != errBackgroundJobInvalidAction {
+		t.Fatalf("Expected %v, got %v", errBackgroundJobInvalidAction, err)
+	}
+}
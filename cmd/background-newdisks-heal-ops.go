@@ -0,0 +1,162 @@
+/*
+ * MinIO Cloud Storage, (C) 2020 MinIO, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"context"
+	"time"
+
+	"github.com/minio/minio/cmd/logger"
+	"github.com/minio/minio/pkg/madmin"
+)
+
+// Interval at which the local disks are checked for new disks.
+const defaultMonitorNewDiskInterval = time.Minute
+
+// initLocalDisksAutoHeal - starts healing the local disks replaced
+// while the server is running.
+func initLocalDisksAutoHeal() {
+	go monitorLocalDisksAndHeal()
+}
+
+// monitorLocalDisksAndHeal - periodically looks for new empty disks at
+// the local endpoints, such as disks replacing failed ones, formats
+// them and heals the erasure sets they belong to.
+func monitorLocalDisksAndHeal() {
+	var objAPI ObjectLayer
+	var ctx = GlobalContext
+
+	// Wait until the object layer is ready
+	for {
+		objAPI = newObjectLayerFn()
+		if objAPI == nil {
+			if !sleepContext(ctx, time.Second) {
+				return
+			}
+			continue
+		}
+		break
+	}
+
+	sets, ok := objAPI.(*xlSets)
+	if !ok {
+		return
+	}
+
+	ticker := time.NewTicker(defaultMonitorNewDiskInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			indexes := sets.getLocalUnformattedEndpoints()
+			if len(indexes) == 0 {
+				continue
+			}
+
+			setIndexes := make(map[int]struct{})
+			for _, i := range indexes {
+				logger.Info("Found a new disk at %s, formatting and healing it", sets.endpoints[i])
+				setIndexes[i/sets.drivesPerSet] = struct{}{}
+			}
+
+			// Format the new disks, the peers reload the new format.
+			if _, err := bgHealDiskFormat(ctx, madmin.HealOpts{}); err != nil {
+				logger.LogIf(ctx, err)
+				continue
+			}
+
+			for setIndex := range setIndexes {
+				if err := sets.healErasureSet(ctx, setIndex); err != nil {
+					logger.LogIf(ctx, err)
+					continue
+				}
+				logger.Info("Healing of erasure set %d is complete", setIndex+1)
+			}
+		}
+	}
+}
+
+// getLocalUnformattedEndpoints - returns the indexes of the local
+// endpoints which are not connected and hold a fresh disk without
+// format.json.
+func (s *xlSets) getLocalUnformattedEndpoints() []int {
+	var indexes []int
+	for i, endpoint := range s.endpoints {
+		if !endpoint.IsLocal || s.isConnected(endpoint) {
+			continue
+		}
+		disk, err := newStorageAPI(endpoint)
+		if err != nil {
+			continue
+		}
+		_, err = loadFormatXL(disk)
+		disk.Close()
+		if err == errUnformattedDisk {
+			indexes = append(indexes, i)
+		}
+	}
+	return indexes
+}
+
+// healErasureSet - heals the buckets, the server configuration and the
+// objects of an erasure set through the background healing routine.
+func (s *xlSets) healErasureSet(ctx context.Context, setIndex int) error {
+	buckets, err := s.ListBucketsHeal(ctx)
+	if err != nil {
+		return err
+	}
+	for _, bucket := range buckets {
+		if _, err = s.HealBucket(ctx, bucket.Name, false, false); err != nil {
+			logger.LogIf(ctx, err)
+		}
+	}
+
+	healObject := func(bucket, object string) error {
+		respCh := make(chan healResult)
+		task := healTask{
+			path:       pathJoin(bucket, object),
+			opts:       madmin.HealOpts{ScanMode: madmin.HealNormalScan},
+			responseCh: respCh,
+		}
+		if err := globalBackgroundHealing.queueHealTask(ctx, task); err != nil {
+			return err
+		}
+		select {
+		case res := <-respCh:
+			if res.err != nil && !isErrObjectNotFound(res.err) {
+				logger.LogIf(ctx, res.err)
+			}
+			return nil
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+
+	set := s.sets[setIndex]
+	if err = set.HealObjects(ctx, minioMetaBucket, minioConfigPrefix, healObject); err != nil {
+		return err
+	}
+	for _, bucket := range buckets {
+		if err = set.HealObjects(ctx, bucket.Name, "", healObject); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,72 @@
+/*
+ * MinIO Cloud Storage, (C) 2020 MinIO, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"context"
+	"os"
+	"reflect"
+	"testing"
+)
+
+// Tests that a disk replaced by a new empty disk is detected and
+// formatted again.
+func TestGetLocalUnformattedEndpoints(t *testing.T) {
+	objAPI, fsDirs, err := prepareXLSets32()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer removeRoots(fsDirs)
+
+	sets := objAPI.(*xlSets)
+	if indexes := sets.getLocalUnformattedEndpoints(); len(indexes) != 0 {
+		t.Fatalf("Expected no new disks, got %v", indexes)
+	}
+
+	// Replace the disk of the fourth endpoint by an empty disk.
+	replaced := 3
+	if err = os.RemoveAll(fsDirs[replaced]); err != nil {
+		t.Fatal(err)
+	}
+	if err = os.MkdirAll(fsDirs[replaced], 0755); err != nil {
+		t.Fatal(err)
+	}
+	sets.xlDisksMu.Lock()
+	for i := range sets.xlDisks {
+		for j, disk := range sets.xlDisks[i] {
+			if disk != nil && disk.String() == fsDirs[replaced] {
+				disk.Close()
+				sets.xlDisks[i][j] = nil
+			}
+		}
+	}
+	sets.xlDisksMu.Unlock()
+
+	if indexes := sets.getLocalUnformattedEndpoints(); !reflect.DeepEqual(indexes, []int{replaced}) {
+		t.Fatalf("Expected new disk %d, got %v", replaced, indexes)
+	}
+
+	if _, err = sets.HealFormat(context.Background(), false); err != nil {
+		t.Fatal(err)
+	}
+	if !sets.isConnected(sets.endpoints[replaced]) {
+		t.Fatal("Expected the new disk to be connected once formatted")
+	}
+	if indexes := sets.getLocalUnformattedEndpoints(); len(indexes) != 0 {
+		t.Fatalf("Expected no new disks once formatted, got %v", indexes)
+	}
+}
@@ -0,0 +1,188 @@
+/*
+ * MinIO Cloud Storage, (C) 2019 MinIO, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"path"
+	"sync"
+
+	"github.com/minio/minio/pkg/bandwidth"
+	"github.com/minio/minio/pkg/madmin"
+)
+
+const (
+	// Bandwidth limits config file.
+	bandwidthConfigFile = "bandwidth.json"
+)
+
+var errInvalidBandwidthLimit = errors.New("bandwidth limit cannot be negative")
+
+// BandwidthSys - throttles background data transfers so that they
+// do not starve foreground S3 requests.
+type BandwidthSys struct {
+	sync.RWMutex
+	global  *bandwidth.Limiter
+	buckets map[string]*bandwidth.Limiter
+}
+
+// Get - returns the current bandwidth limits.
+func (sys *BandwidthSys) Get() madmin.BandwidthLimits {
+	sys.RLock()
+	defer sys.RUnlock()
+
+	limits := madmin.BandwidthLimits{
+		Global:  sys.global.Limit(),
+		Buckets: make(map[string]int64, len(sys.buckets)),
+	}
+	for bucket, limiter := range sys.buckets {
+		limits.Buckets[bucket] = limiter.Limit()
+	}
+	return limits
+}
+
+// Set - applies the given bandwidth limits, ongoing transfers are
+// throttled with the new limits right away.
+func (sys *BandwidthSys) Set(limits madmin.BandwidthLimits) {
+	sys.Lock()
+	defer sys.Unlock()
+
+	sys.global.SetLimit(limits.Global)
+	for bucket, limiter := range sys.buckets {
+		if _, ok := limits.Buckets[bucket]; !ok {
+			limiter.SetLimit(0)
+			delete(sys.buckets, bucket)
+		}
+	}
+	for bucket, limit := range limits.Buckets {
+		if limiter, ok := sys.buckets[bucket]; ok {
+			limiter.SetLimit(limit)
+		} else {
+			sys.buckets[bucket] = bandwidth.NewLimiter(limit)
+		}
+	}
+}
+
+// NewReader - returns a reader throttled by the global and the
+// bucket bandwidth limits, to be used by background transfers.
+func (sys *BandwidthSys) NewReader(ctx context.Context, bucket string, r io.Reader) io.Reader {
+	sys.RLock()
+	defer sys.RUnlock()
+
+	limiters := []*bandwidth.Limiter{sys.global}
+	if limiter, ok := sys.buckets[bucket]; ok {
+		limiters = append(limiters, limiter)
+	}
+	return bandwidth.NewReader(ctx, r, limiters...)
+}
+
+// IsLimited - returns true if background transfers of bucket are
+// throttled by the global or the bucket bandwidth limit.
+func (sys *BandwidthSys) IsLimited(bucket string) bool {
+	sys.RLock()
+	defer sys.RUnlock()
+
+	if sys.global.Limit() > 0 {
+		return true
+	}
+	limiter, ok := sys.buckets[bucket]
+	return ok && limiter.Limit() > 0
+}
+
+// Wait - waits until n bytes of background work on bucket are allowed
+// by the global and the bucket bandwidth limits. This paces background
+// operations which can not be throttled while transferring data, e.g.
+// because they hold the object lock meanwhile.
+func (sys *BandwidthSys) Wait(ctx context.Context, bucket string, n int64) error {
+	sys.RLock()
+	limiters := []*bandwidth.Limiter{sys.global}
+	if limiter, ok := sys.buckets[bucket]; ok {
+		limiters = append(limiters, limiter)
+	}
+	sys.RUnlock()
+
+	for _, limiter := range limiters {
+		if err := limiter.WaitN(ctx, int(n)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Load - loads the bandwidth limits from the backend.
+func (sys *BandwidthSys) Load(objAPI ObjectLayer) error {
+	if objAPI == nil {
+		return errServerNotInitialized
+	}
+
+	limits, err := readBandwidthConfig(context.Background(), objAPI)
+	if err != nil {
+		return err
+	}
+	sys.Set(limits)
+	return nil
+}
+
+// NewBandwidthSys - creates new bandwidth system without any limits.
+func NewBandwidthSys() *BandwidthSys {
+	return &BandwidthSys{
+		global:  bandwidth.NewLimiter(0),
+		buckets: make(map[string]*bandwidth.Limiter),
+	}
+}
+
+// validateBandwidthLimits - returns an error if any limit is invalid.
+func validateBandwidthLimits(limits madmin.BandwidthLimits) error {
+	if limits.Global < 0 {
+		return errInvalidBandwidthLimit
+	}
+	for bucket, limit := range limits.Buckets {
+		if limit < 0 {
+			return errInvalidBandwidthLimit
+		}
+		if isReservedOrInvalidBucket(bucket, false) {
+			return errInvalidBucketName
+		}
+	}
+	return nil
+}
+
+func readBandwidthConfig(ctx context.Context, objAPI ObjectLayer) (limits madmin.BandwidthLimits, err error) {
+	configFile := path.Join(minioConfigPrefix, bandwidthConfigFile)
+	data, err := readConfig(ctx, objAPI, configFile)
+	if err != nil {
+		if err == errConfigNotFound {
+			// No limits configured.
+			return limits, nil
+		}
+		return limits, err
+	}
+	err = json.Unmarshal(data, &limits)
+	return limits, err
+}
+
+func saveBandwidthConfig(ctx context.Context, objAPI ObjectLayer, limits madmin.BandwidthLimits) error {
+	data, err := json.Marshal(limits)
+	if err != nil {
+		return err
+	}
+	configFile := path.Join(minioConfigPrefix, bandwidthConfigFile)
+	return saveConfig(ctx, objAPI, configFile, data)
+}
@@ -0,0 +1,106 @@
+/*
+ * MinIO Cloud Storage, (C) 2019 MinIO, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"context"
+	"os"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/minio/minio/pkg/madmin"
+)
+
+func TestValidateBandwidthLimits(t *testing.T) {
+	testCases := []struct {
+		limits      madmin.BandwidthLimits
+		expectedErr error
+	}{
+		{madmin.BandwidthLimits{}, nil},
+		{madmin.BandwidthLimits{Global: 1024, Buckets: map[string]int64{"mybucket": 512}}, nil},
+		{madmin.BandwidthLimits{Global: -1}, errInvalidBandwidthLimit},
+		{madmin.BandwidthLimits{Buckets: map[string]int64{"mybucket": -1}}, errInvalidBandwidthLimit},
+		{madmin.BandwidthLimits{Buckets: map[string]int64{minioMetaBucket: 1}}, errInvalidBucketName},
+	}
+
+	for i, testCase := range testCases {
+		if err := validateBandwidthLimits(testCase.limits); err != testCase.expectedErr {
+			t.Errorf("Test %d: expected %v, got %v", i+1, testCase.expectedErr, err)
+		}
+	}
+}
+
+func TestBandwidthSysLoad(t *testing.T) {
+	objLayer, fsDir, err := prepareFS()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(fsDir)
+
+	sys := NewBandwidthSys()
+	// No limits saved yet.
+	if err = sys.Load(objLayer); err != nil {
+		t.Fatal(err)
+	}
+	if limits := sys.Get(); limits.Global != 0 || len(limits.Buckets) != 0 {
+		t.Fatalf("Expected no limits, got %v", limits)
+	}
+
+	limits := madmin.BandwidthLimits{Global: 1 << 20, Buckets: map[string]int64{"mybucket": 1 << 10}}
+	if err = saveBandwidthConfig(context.Background(), objLayer, limits); err != nil {
+		t.Fatal(err)
+	}
+	if err = sys.Load(objLayer); err != nil {
+		t.Fatal(err)
+	}
+	if got := sys.Get(); !reflect.DeepEqual(got, limits) {
+		t.Fatalf("Expected %v, got %v", limits, got)
+	}
+
+	// Removed bucket limits are dropped.
+	sys.Set(madmin.BandwidthLimits{Global: 1 << 20})
+	if got := sys.Get(); len(got.Buckets) != 0 {
+		t.Fatalf("Expected no bucket limits, got %v", got.Buckets)
+	}
+}
+
+func TestBandwidthSysWait(t *testing.T) {
+	sys := NewBandwidthSys()
+	if sys.IsLimited("mybucket") {
+		t.Fatal("Expected no limits")
+	}
+	// Unlimited background work never waits.
+	if err := sys.Wait(context.Background(), "mybucket", 1<<30); err != nil {
+		t.Fatal(err)
+	}
+
+	sys.Set(madmin.BandwidthLimits{Buckets: map[string]int64{"mybucket": 1 << 10}})
+	if !sys.IsLimited("mybucket") || sys.IsLimited("otherbucket") {
+		t.Fatal("Expected only mybucket to be limited")
+	}
+
+	// Work beyond the limit waits until ctx is canceled.
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	if err := sys.Wait(ctx, "mybucket", 1<<20); err != context.DeadlineExceeded {
+		t.Fatalf("Expected %v, got %v", context.DeadlineExceeded, err)
+	}
+	if err := sys.Wait(ctx, "otherbucket", 1<<20); err != nil {
+		t.Fatal(err)
+	}
+}
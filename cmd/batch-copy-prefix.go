@@ -0,0 +1,254 @@
+/*
+ * MinIO Cloud Storage, (C) 2020 MinIO, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/minio/minio/cmd/crypto"
+	"github.com/minio/minio/cmd/logger"
+	"github.com/minio/minio/pkg/hash"
+	"github.com/minio/minio/pkg/madmin"
+)
+
+var errBatchJobOverlappingPrefix = errors.New("Batch copy prefix job source and target prefixes must not overlap")
+
+// batchCopyPrefixJob - copies all objects under a prefix to a prefix
+// of a target bucket through server-side copies.
+type batchCopyPrefixJob struct {
+	job    madmin.BatchCopyPrefixJob
+	cancel context.CancelFunc
+
+	mu     sync.Mutex
+	status madmin.BatchJobStatus
+}
+
+// checkBatchCopyPrefixJob - validates a batch copy prefix job.
+func checkBatchCopyPrefixJob(ctx context.Context, objAPI ObjectLayer, job madmin.BatchCopyPrefixJob) error {
+	if isReservedOrInvalidBucket(job.Bucket, false) {
+		return errBatchJobInvalidBucket
+	}
+	if isReservedOrInvalidBucket(job.TargetBucket, false) {
+		return errBatchJobNoTarget
+	}
+	// Copies must not be listed again as objects to copy.
+	if job.Bucket == job.TargetBucket &&
+		(strings.HasPrefix(job.Prefix, job.TargetPrefix) || strings.HasPrefix(job.TargetPrefix, job.Prefix)) {
+		return errBatchJobOverlappingPrefix
+	}
+	if _, err := objAPI.GetBucketInfo(ctx, job.Bucket); err != nil {
+		return err
+	}
+	_, err := objAPI.GetBucketInfo(ctx, job.TargetBucket)
+	return err
+}
+
+// StartCopyPrefix - starts a batch copy prefix job in the background,
+// returns the job ID.
+func (b *batchJobs) StartCopyPrefix(ctx context.Context, objAPI ObjectLayer, job madmin.BatchCopyPrefixJob) (string, error) {
+	if err := checkBatchCopyPrefixJob(ctx, objAPI, job); err != nil {
+		return "", err
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	j := &batchCopyPrefixJob{
+		job:    job,
+		cancel: cancel,
+		status: madmin.BatchJobStatus{
+			ID:        mustGetUUID(),
+			Node:      GetLocalPeer(globalEndpoints),
+			Operation: madmin.BatchOperationCopy,
+			Bucket:    job.Bucket,
+			Prefix:    job.Prefix,
+			Running:   true,
+			StartTime: UTCNow(),
+		},
+	}
+
+	b.mu.Lock()
+	b.jobs[j.status.ID] = j
+	b.pruneFinished()
+	b.mu.Unlock()
+
+	go j.run(ctx, objAPI)
+	return j.status.ID, nil
+}
+
+// Status - returns the progress of the job.
+func (j *batchCopyPrefixJob) Status() madmin.BatchJobStatus {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	status := j.status
+	status.Failures = append([]madmin.BatchJobFailure(nil), j.status.Failures...)
+	return status
+}
+
+// Cancel - stops the job.
+func (j *batchCopyPrefixJob) Cancel() {
+	j.cancel()
+}
+
+func (j *batchCopyPrefixJob) run(ctx context.Context, objAPI ObjectLayer) {
+	defer j.cancel()
+
+	err := j.copy(ctx, objAPI)
+	canceled := err == context.Canceled
+	if !canceled {
+		logger.LogIf(ctx, err)
+	}
+
+	j.mu.Lock()
+	defer j.mu.Unlock()
+
+	j.status.Running = false
+	j.status.Canceled = canceled
+	j.status.EndTime = UTCNow()
+	if err != nil && !canceled {
+		j.status.Error = err.Error()
+	}
+}
+
+func (j *batchCopyPrefixJob) copy(ctx context.Context, objAPI ObjectLayer) error {
+	// Throttle the server-side copies if a rate limit is set.
+	var throttle <-chan time.Time
+	if j.job.RateLimit > 0 {
+		ticker := time.NewTicker(time.Second / time.Duration(j.job.RateLimit))
+		defer ticker.Stop()
+		throttle = ticker.C
+	}
+
+	marker := ""
+	for {
+		result, err := objAPI.ListObjects(ctx, j.job.Bucket, j.job.Prefix, marker, "", maxObjectList)
+		if err != nil {
+			return err
+		}
+		for _, obj := range result.Objects {
+			if throttle != nil {
+				select {
+				case <-ctx.Done():
+					return ctx.Err()
+				case <-throttle:
+				}
+			} else if err = ctx.Err(); err != nil {
+				return err
+			}
+
+			target := j.job.TargetPrefix + strings.TrimPrefix(obj.Name, j.job.Prefix)
+			err = copyPrefixObject(ctx, objAPI, j.job.Bucket, obj.Name, j.job.TargetBucket, target,
+				j.job.PreserveMetadata, j.job.PreserveEncryption)
+			if err != nil && !isErrObjectNotFound(err) && err != errBatchJobEncryptedObject {
+				logger.LogIf(ctx, err)
+			}
+			j.record(obj.Name, err)
+		}
+		if !result.IsTruncated {
+			return nil
+		}
+		marker = result.NextMarker
+	}
+}
+
+// record - records the outcome for an object of the job, objects
+// removed since they were listed are not failures.
+func (j *batchCopyPrefixJob) record(object string, err error) {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+
+	j.status.Scanned++
+	switch {
+	case err == nil:
+		j.status.Updated++
+	case !isErrObjectNotFound(err):
+		j.status.Failed++
+		if len(j.status.Failures) < maxBatchJobFailures {
+			j.status.Failures = append(j.status.Failures, madmin.BatchJobFailure{
+				Bucket: j.job.Bucket,
+				Object: object,
+				Error:  err.Error(),
+			})
+		}
+	}
+}
+
+// copyPrefixObject - copies the data and content type of an object to
+// another bucket, along with its user metadata if preserveMetadata is
+// set. SSE-S3 objects are re-encrypted under their new name if
+// preserveEncryption is set, other encrypted objects are not copied.
+func copyPrefixObject(ctx context.Context, objAPI ObjectLayer, srcBucket, srcObject, dstBucket, dstObject string,
+	preserveMetadata, preserveEncryption bool) error {
+	objInfo, err := objAPI.GetObjectInfo(ctx, srcBucket, srcObject, ObjectOptions{})
+	if err != nil {
+		return err
+	}
+	sseS3 := crypto.S3.IsEncrypted(objInfo.UserDefined)
+	if crypto.IsEncrypted(objInfo.UserDefined) && (!sseS3 || !preserveEncryption) {
+		return errBatchJobEncryptedObject
+	}
+
+	gr, err := objAPI.GetObjectNInfo(ctx, srcBucket, srcObject, nil, http.Header{}, readLock, ObjectOptions{})
+	if err != nil {
+		return err
+	}
+	defer gr.Close()
+
+	var metadata map[string]string
+	if preserveMetadata {
+		metadata = bucketSnapshotMetadata(gr.ObjInfo)
+	} else {
+		metadata = make(map[string]string)
+		if gr.ObjInfo.ContentType != "" {
+			metadata["content-type"] = gr.ObjInfo.ContentType
+		}
+	}
+
+	size := gr.ObjInfo.GetActualSize()
+	if sseS3 {
+		// The size of the encrypted copy depends on the plain size.
+		if size, err = objInfo.DecryptedSize(); err != nil {
+			return err
+		}
+	}
+	hashReader, err := hash.NewReader(gr, size, "", "", size, globalCLIContext.StrictS3Compat)
+	if err != nil {
+		return err
+	}
+	pReader := NewPutObjReader(hashReader, nil, nil)
+	if sseS3 {
+		reader, objectEncryptionKey, err := newEncryptReader(hashReader, nil, dstBucket, dstObject, metadata, true)
+		if err != nil {
+			return err
+		}
+		info := ObjectInfo{Size: size}
+		encReader, err := hash.NewReader(reader, info.EncryptedSize(), "", "", size, globalCLIContext.StrictS3Compat)
+		if err != nil {
+			return err
+		}
+		pReader = NewPutObjReader(hashReader, encReader, objectEncryptionKey)
+	}
+
+	// Ensure that metadata does not contain sensitive information
+	crypto.RemoveSensitiveEntries(metadata)
+
+	_, err = objAPI.PutObject(ctx, dstBucket, dstObject, pReader, ObjectOptions{UserDefined: metadata})
+	return err
+}
@@ -0,0 +1,122 @@
+/*
+ * MinIO Cloud Storage, (C) 2020 MinIO, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/minio/minio/pkg/madmin"
+)
+
+// Tests that a batch copy prefix job copies the objects under the
+// prefix only, with their user metadata only if it is preserved.
+func TestBatchCopyPrefixJob(t *testing.T) {
+	obj, fsDir, err := prepareFS()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(fsDir)
+
+	ctx := context.Background()
+	for _, bucket := range []string{"bucket", "target"} {
+		if err = obj.MakeBucketWithLocation(ctx, bucket, ""); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	data := []byte("hello")
+	for _, object := range []string{"media/a.mp4", "media/b/c.mp4", "other"} {
+		meta := map[string]string{"content-type": "video/mp4", "X-Amz-Meta-Owner": "alice"}
+		if _, err = obj.PutObject(ctx, "bucket", object, mustGetPutObjReader(t, bytes.NewReader(data), int64(len(data)), "", ""), ObjectOptions{UserDefined: meta}); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	jobs := &batchJobs{jobs: make(map[string]batchJob)}
+	for _, preserveMetadata := range []bool{false, true} {
+		id, err := jobs.StartCopyPrefix(ctx, obj, madmin.BatchCopyPrefixJob{
+			Bucket:           "bucket",
+			Prefix:           "media/",
+			TargetBucket:     "target",
+			TargetPrefix:     "copy/",
+			PreserveMetadata: preserveMetadata,
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		for jobs.Status()[0].Running {
+			time.Sleep(10 * time.Millisecond)
+		}
+
+		status := jobs.Status()[0]
+		if status.ID != id || status.Error != "" || status.Scanned != 2 || status.Updated != 2 || status.Failed != 0 {
+			t.Fatalf("Unexpected job status %v", status)
+		}
+
+		owner := ""
+		if preserveMetadata {
+			owner = "alice"
+		}
+		for _, object := range []string{"copy/a.mp4", "copy/b/c.mp4"} {
+			objInfo, err := obj.GetObjectInfo(ctx, "target", object, ObjectOptions{})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if objInfo.ContentType != "video/mp4" || objInfo.UserDefined["X-Amz-Meta-Owner"] != owner || objInfo.Size != int64(len(data)) {
+				t.Errorf("%s: unexpected object info %v", object, objInfo)
+			}
+		}
+	}
+	if _, err = obj.GetObjectInfo(ctx, "target", "copy/other", ObjectOptions{}); !isErrObjectNotFound(err) {
+		t.Fatalf("Expected objects outside the prefix not to be copied, got %v", err)
+	}
+}
+
+// Tests that invalid batch copy prefix jobs are rejected.
+func TestCheckBatchCopyPrefixJob(t *testing.T) {
+	obj, fsDir, err := prepareFS()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(fsDir)
+
+	ctx := context.Background()
+	if err = obj.MakeBucketWithLocation(ctx, "bucket", ""); err != nil {
+		t.Fatal(err)
+	}
+
+	testCases := []struct {
+		job         madmin.BatchCopyPrefixJob
+		expectedErr error
+	}{
+		{madmin.BatchCopyPrefixJob{Bucket: "bucket", Prefix: "a/", TargetBucket: "bucket", TargetPrefix: "b/"}, nil},
+		{madmin.BatchCopyPrefixJob{Bucket: minioMetaBucket, TargetBucket: "bucket"}, errBatchJobInvalidBucket},
+		{madmin.BatchCopyPrefixJob{Bucket: "bucket", TargetBucket: ""}, errBatchJobNoTarget},
+		{madmin.BatchCopyPrefixJob{Bucket: "bucket", Prefix: "a/", TargetBucket: "bucket", TargetPrefix: "a/b/"}, errBatchJobOverlappingPrefix},
+		{madmin.BatchCopyPrefixJob{Bucket: "bucket", Prefix: "a/b/", TargetBucket: "bucket", TargetPrefix: "a/"}, errBatchJobOverlappingPrefix},
+		{madmin.BatchCopyPrefixJob{Bucket: "bucket", TargetBucket: "missing"}, BucketNotFound{Bucket: "missing"}},
+	}
+	for i, testCase := range testCases {
+		if err = checkBatchCopyPrefixJob(ctx, obj, testCase.job); err != testCase.expectedErr {
+			t.Errorf("Test %d: expected %v, got %v", i+1, testCase.expectedErr, err)
+		}
+	}
+}
@@ -0,0 +1,235 @@
+/*
+ * MinIO Cloud Storage, (C) 2020 MinIO, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"context"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/minio/minio/cmd/crypto"
+	"github.com/minio/minio/cmd/logger"
+	"github.com/minio/minio/pkg/hash"
+	"github.com/minio/minio/pkg/madmin"
+)
+
+// batchEncryptJob - encrypts all plaintext objects under a prefix with
+// SSE-S3, objects are rewritten in place the same way as by a CopyObject
+// request onto themselves.
+type batchEncryptJob struct {
+	job    madmin.BatchEncryptJob
+	cancel context.CancelFunc
+
+	mu     sync.Mutex
+	status madmin.BatchJobStatus
+}
+
+// checkBatchEncryptJob - validates a batch encrypt job.
+func checkBatchEncryptJob(ctx context.Context, objAPI ObjectLayer, job madmin.BatchEncryptJob) error {
+	if isReservedOrInvalidBucket(job.Bucket, false) {
+		return errBatchJobInvalidBucket
+	}
+	if GlobalKMS == nil {
+		return errKMSNotConfigured
+	}
+	if !objAPI.IsEncryptionSupported() {
+		return NotImplemented{}
+	}
+	_, err := objAPI.GetBucketInfo(ctx, job.Bucket)
+	return err
+}
+
+// StartEncrypt - starts a batch encrypt job in the background, returns
+// the job ID.
+func (b *batchJobs) StartEncrypt(ctx context.Context, objAPI ObjectLayer, job madmin.BatchEncryptJob) (string, error) {
+	if err := checkBatchEncryptJob(ctx, objAPI, job); err != nil {
+		return "", err
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	j := &batchEncryptJob{
+		job:    job,
+		cancel: cancel,
+		status: madmin.BatchJobStatus{
+			ID:        mustGetUUID(),
+			Node:      GetLocalPeer(globalEndpoints),
+			Operation: madmin.BatchOperationEncrypt,
+			Bucket:    job.Bucket,
+			Prefix:    job.Prefix,
+			Running:   true,
+			StartTime: UTCNow(),
+		},
+	}
+
+	b.mu.Lock()
+	b.jobs[j.status.ID] = j
+	b.pruneFinished()
+	b.mu.Unlock()
+
+	go j.run(ctx, objAPI)
+	return j.status.ID, nil
+}
+
+// Status - returns the progress of the job.
+func (j *batchEncryptJob) Status() madmin.BatchJobStatus {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	status := j.status
+	status.Failures = append([]madmin.BatchJobFailure(nil), j.status.Failures...)
+	return status
+}
+
+// Cancel - stops the job, objects already encrypted stay encrypted.
+func (j *batchEncryptJob) Cancel() {
+	j.cancel()
+}
+
+func (j *batchEncryptJob) run(ctx context.Context, objAPI ObjectLayer) {
+	defer j.cancel()
+
+	err := j.encrypt(ctx, objAPI)
+	canceled := err == context.Canceled
+	if !canceled {
+		logger.LogIf(ctx, err)
+	}
+
+	j.mu.Lock()
+	defer j.mu.Unlock()
+
+	j.status.Running = false
+	j.status.Canceled = canceled
+	j.status.EndTime = UTCNow()
+	if err != nil && !canceled {
+		j.status.Error = err.Error()
+	}
+}
+
+func (j *batchEncryptJob) encrypt(ctx context.Context, objAPI ObjectLayer) error {
+	// Throttle the rewrites if a rate limit is set.
+	var throttle <-chan time.Time
+	if j.job.RateLimit > 0 {
+		ticker := time.NewTicker(time.Second / time.Duration(j.job.RateLimit))
+		defer ticker.Stop()
+		throttle = ticker.C
+	}
+
+	marker := ""
+	for {
+		result, err := objAPI.ListObjects(ctx, j.job.Bucket, j.job.Prefix, marker, "", maxObjectList)
+		if err != nil {
+			return err
+		}
+		for _, obj := range result.Objects {
+			// Encrypted objects are only counted as scanned.
+			if crypto.IsEncrypted(obj.UserDefined) {
+				j.record(obj.Name, false, nil)
+				continue
+			}
+
+			if throttle != nil {
+				select {
+				case <-ctx.Done():
+					return ctx.Err()
+				case <-throttle:
+				}
+			} else if err = ctx.Err(); err != nil {
+				return err
+			}
+
+			encrypted, err := encryptPlaintextObject(ctx, objAPI, j.job.Bucket, obj.Name)
+			if err != nil && !isErrObjectNotFound(err) {
+				logger.LogIf(ctx, err)
+			}
+			j.record(obj.Name, encrypted, err)
+		}
+		if !result.IsTruncated {
+			return nil
+		}
+		marker = result.NextMarker
+	}
+}
+
+// record - records the outcome for an object of the job, objects
+// removed since they were listed are not failures.
+func (j *batchEncryptJob) record(object string, encrypted bool, err error) {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+
+	j.status.Scanned++
+	switch {
+	case err == nil:
+		if encrypted {
+			j.status.Updated++
+		}
+	case !isErrObjectNotFound(err):
+		j.status.Failed++
+		if len(j.status.Failures) < maxBatchJobFailures {
+			j.status.Failures = append(j.status.Failures, madmin.BatchJobFailure{
+				Bucket: j.job.Bucket,
+				Object: object,
+				Error:  err.Error(),
+			})
+		}
+	}
+}
+
+// encryptPlaintextObject - rewrites a plaintext object in place
+// encrypted with SSE-S3, keeping its metadata. Compressed objects are
+// stored uncompressed, as encrypted objects are never compressed.
+// Returns false if the object is encrypted already.
+func encryptPlaintextObject(ctx context.Context, objAPI ObjectLayer, bucket, object string) (bool, error) {
+	// The object is locked for writing by CopyObject.
+	gr, err := objAPI.GetObjectNInfo(ctx, bucket, object, nil, http.Header{}, noLock, ObjectOptions{})
+	if err != nil {
+		return false, err
+	}
+	defer gr.Close()
+
+	srcInfo := gr.ObjInfo
+	if crypto.IsEncrypted(srcInfo.UserDefined) {
+		return false, nil
+	}
+
+	metadata := make(map[string]string, len(srcInfo.UserDefined))
+	for k, v := range srcInfo.UserDefined {
+		metadata[k] = v
+	}
+	delete(metadata, ReservedMetadataPrefix+"compression")
+	delete(metadata, ReservedMetadataPrefix+"actual-size")
+	delete(metadata, compressionDictionaryKey)
+
+	size := srcInfo.GetActualSize()
+	hashReader, err := hash.NewReader(gr, size, "", "", size, globalCLIContext.StrictS3Compat)
+	if err != nil {
+		return false, err
+	}
+	reader, objectEncryptionKey, err := newEncryptReader(hashReader, nil, bucket, object, metadata, true)
+	if err != nil {
+		return false, err
+	}
+	info := ObjectInfo{Size: size}
+	encReader, err := hash.NewReader(reader, info.EncryptedSize(), "", "", size, globalCLIContext.StrictS3Compat)
+	if err != nil {
+		return false, err
+	}
+
+	srcInfo.UserDefined = metadata
+	srcInfo.PutObjReader = NewPutObjReader(hashReader, encReader, objectEncryptionKey)
+	_, err = objAPI.CopyObject(ctx, bucket, object, bucket, object, srcInfo, ObjectOptions{}, ObjectOptions{})
+	return err == nil, err
+}
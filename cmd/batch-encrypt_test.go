@@ -0,0 +1,117 @@
+/*
+ * MinIO Cloud Storage, (C) 2020 MinIO, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/minio/minio/cmd/crypto"
+	"github.com/minio/minio/pkg/madmin"
+)
+
+// Tests that a batch encrypt job encrypts the plaintext objects under
+// the prefix with SSE-S3 and leaves encrypted objects alone.
+func TestBatchEncryptJob(t *testing.T) {
+	prevKMS := GlobalKMS
+	defer func() {
+		GlobalKMS = prevKMS
+	}()
+	GlobalKMS = nil
+
+	obj, fsDir, err := prepareFS()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(fsDir)
+
+	ctx := context.Background()
+	if err = obj.MakeBucketWithLocation(ctx, "bucket", ""); err != nil {
+		t.Fatal(err)
+	}
+
+	jobs := &batchJobs{jobs: make(map[string]batchJob)}
+	if _, err = jobs.StartEncrypt(ctx, obj, madmin.BatchEncryptJob{Bucket: "bucket"}); err != errKMSNotConfigured {
+		t.Fatalf("Expected %v, got %v", errKMSNotConfigured, err)
+	}
+	GlobalKMS = crypto.NewKMS([32]byte{1})
+
+	data := []byte("hello")
+	metadata := map[string]string{}
+	if _, err = newEncryptMetadata(nil, "bucket", "docs/encrypted", metadata, true); err != nil {
+		t.Fatal(err)
+	}
+	for object, meta := range map[string]map[string]string{
+		"docs/encrypted": metadata,
+		"docs/a.txt":     {"content-type": "text/plain"},
+		"docs/b/c.txt":   nil,
+		"other":          nil,
+	} {
+		if _, err = obj.PutObject(ctx, "bucket", object, mustGetPutObjReader(t, bytes.NewReader(data), int64(len(data)), "", ""), ObjectOptions{UserDefined: meta}); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	id, err := jobs.StartEncrypt(ctx, obj, madmin.BatchEncryptJob{Bucket: "bucket", Prefix: "docs/"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for jobs.Status()[0].Running {
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	status := jobs.Status()[0]
+	if status.ID != id || status.Error != "" || status.Scanned != 3 || status.Updated != 2 || status.Failed != 0 {
+		t.Fatalf("Unexpected job status %v", status)
+	}
+
+	for _, object := range []string{"docs/a.txt", "docs/b/c.txt"} {
+		gr, err := obj.GetObjectNInfo(ctx, "bucket", object, nil, http.Header{}, readLock, ObjectOptions{})
+		if err != nil {
+			t.Fatal(err)
+		}
+		content, err := ioutil.ReadAll(gr)
+		gr.Close()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !crypto.S3.IsEncrypted(gr.ObjInfo.UserDefined) {
+			t.Errorf("%s: expected the object to be encrypted", object)
+		}
+		if !bytes.Equal(content, data) {
+			t.Errorf("%s: unexpected content %q", object, content)
+		}
+	}
+	objInfo, err := obj.GetObjectInfo(ctx, "bucket", "docs/a.txt", ObjectOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if objInfo.ContentType != "text/plain" {
+		t.Errorf("Expected the content type to be kept, got %q", objInfo.ContentType)
+	}
+	if objInfo, err = obj.GetObjectInfo(ctx, "bucket", "other", ObjectOptions{}); err != nil {
+		t.Fatal(err)
+	}
+	if crypto.IsEncrypted(objInfo.UserDefined) {
+		t.Error("Expected objects outside of the prefix to be left plaintext")
+	}
+}
@@ -0,0 +1,375 @@
+/*
+ * MinIO Cloud Storage, (C) 2020 MinIO, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"context"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"sync"
+	"time"
+
+	"github.com/minio/minio/cmd/crypto"
+	"github.com/minio/minio/cmd/logger"
+	"github.com/minio/minio/pkg/madmin"
+)
+
+const (
+	// Maximum number of objects of a batch operation job.
+	maxBatchJobObjects = 100000
+	// Maximum number of failed objects kept in the status of a job.
+	maxBatchJobFailures = 1000
+	// Number of objects processed concurrently by default.
+	defaultBatchJobWorkers = 4
+	// Maximum number of objects processed concurrently.
+	maxBatchJobWorkers = 64
+	// Maximum size of a batch operation job request.
+	maxBatchJobJSONSize = 16 << 20
+
+	// Metadata holding the tags set by batch tag jobs.
+	amzObjectTagging = "X-Amz-Tagging"
+)
+
+var (
+	errBatchJobInvalidOperation = errors.New("Batch operation job requires one of the copy, tag, delete or restore operations")
+	errBatchJobNoObjects        = errors.New("Batch operation job requires either objects or a manifest")
+	errBatchJobTooManyObjects   = fmt.Errorf("Batch operation job has more than %d objects", maxBatchJobObjects)
+	errBatchJobInvalidBucket    = errors.New("Batch operation job objects must be in valid buckets")
+	errBatchJobInvalidManifest  = errors.New("Batch operation job manifest rows must be bucket,object")
+	errBatchJobNoTarget         = errors.New("Batch copy job requires a valid target bucket")
+	errBatchJobNoTags           = errors.New("Batch tag job requires tags to set")
+	errBatchJobInvalidWorkers   = fmt.Errorf("Batch operation job workers must be between 0 and %d", maxBatchJobWorkers)
+	errBatchJobEncryptedObject  = errors.New("Encrypted objects are not copied by batch jobs")
+)
+
+// isBatchJobArgumentErr - returns true if err is caused by an invalid
+// batch operation or copy prefix job.
+func isBatchJobArgumentErr(err error) bool {
+	switch err {
+	case errBatchJobInvalidOperation, errBatchJobNoObjects, errBatchJobTooManyObjects,
+		errBatchJobInvalidBucket, errBatchJobInvalidManifest, errBatchJobNoTarget,
+		errBatchJobNoTags, errBatchJobInvalidWorkers, errBatchJobOverlappingPrefix:
+		return true
+	}
+	return false
+}
+
+// batchOperationJob - applies an operation to the share of the objects
+// of a batch operation job assigned to this server.
+type batchOperationJob struct {
+	job     madmin.BatchOperationJob
+	tagging string
+	cancel  context.CancelFunc
+
+	mu     sync.Mutex
+	status madmin.BatchJobStatus
+}
+
+// loadBatchOperationJob - validates a batch operation job, the objects
+// listed in the manifest object of the job are read into job.Objects.
+func loadBatchOperationJob(ctx context.Context, objAPI ObjectLayer, job *madmin.BatchOperationJob) error {
+	switch job.Operation {
+	case madmin.BatchOperationCopy:
+		if isReservedOrInvalidBucket(job.TargetBucket, false) {
+			return errBatchJobNoTarget
+		}
+		if _, err := objAPI.GetBucketInfo(ctx, job.TargetBucket); err != nil {
+			return err
+		}
+	case madmin.BatchOperationTag:
+		if len(job.Tags) == 0 {
+			return errBatchJobNoTags
+		}
+	case madmin.BatchOperationDelete, madmin.BatchOperationRestore:
+	default:
+		return errBatchJobInvalidOperation
+	}
+	if job.Workers < 0 || job.Workers > maxBatchJobWorkers {
+		return errBatchJobInvalidWorkers
+	}
+
+	if (len(job.Objects) == 0) == (job.Manifest == nil) {
+		return errBatchJobNoObjects
+	}
+	if job.Manifest != nil {
+		objects, err := readBatchJobManifest(ctx, objAPI, *job.Manifest)
+		if err != nil {
+			return err
+		}
+		job.Objects = objects
+		job.Manifest = nil
+	}
+	if len(job.Objects) > maxBatchJobObjects {
+		return errBatchJobTooManyObjects
+	}
+	for _, o := range job.Objects {
+		if isReservedOrInvalidBucket(o.Bucket, false) {
+			return errBatchJobInvalidBucket
+		}
+	}
+	return nil
+}
+
+// readBatchJobManifest - reads the objects listed in a CSV manifest
+// object, one "bucket,object" row per object.
+func readBatchJobManifest(ctx context.Context, objAPI ObjectLayer, manifest madmin.BatchManifest) ([]madmin.BatchObject, error) {
+	gr, err := objAPI.GetObjectNInfo(ctx, manifest.Bucket, manifest.Object, nil, http.Header{}, readLock, ObjectOptions{})
+	if err != nil {
+		return nil, err
+	}
+	defer gr.Close()
+
+	var objects []madmin.BatchObject
+	r := csv.NewReader(gr)
+	r.FieldsPerRecord = 2
+	for {
+		record, err := r.Read()
+		if err == io.EOF {
+			return objects, nil
+		}
+		if err != nil {
+			if _, ok := err.(*csv.ParseError); ok {
+				return nil, errBatchJobInvalidManifest
+			}
+			return nil, err
+		}
+		if len(objects) == maxBatchJobObjects {
+			return nil, errBatchJobTooManyObjects
+		}
+		objects = append(objects, madmin.BatchObject{Bucket: record[0], Object: record[1]})
+	}
+}
+
+// splitBatchJobObjects - splits the objects in n shares of about the
+// same size.
+func splitBatchJobObjects(objects []madmin.BatchObject, n int) [][]madmin.BatchObject {
+	shares := make([][]madmin.BatchObject, n)
+	for i, o := range objects {
+		shares[i%n] = append(shares[i%n], o)
+	}
+	return shares
+}
+
+// startBatchOperationJob - splits the objects of a validated batch
+// operation job between this server and its peers and starts the job
+// on all of them, returns the ID of the job. The share of a peer which
+// could not start the job is processed by this server.
+func startBatchOperationJob(ctx context.Context, objAPI ObjectLayer, job madmin.BatchOperationJob) string {
+	id := mustGetUUID()
+
+	local := job.Objects
+	if globalIsDistXL {
+		shares := splitBatchJobObjects(job.Objects, len(globalNotificationSys.peerClients)+1)
+		local = shares[0]
+		for idx, nerr := range globalNotificationSys.StartBatchOperationJob(id, job, shares[1:]) {
+			if nerr.Err != nil {
+				logger.GetReqInfo(ctx).SetTags("peerAddress", nerr.Host.String())
+				logger.LogIf(ctx, nerr.Err)
+			}
+			if nerr.Err != nil || globalNotificationSys.peerClients[idx] == nil {
+				local = append(local, shares[idx+1]...)
+			}
+		}
+	}
+
+	job.Objects = local
+	globalBatchJobs.StartOperation(GlobalContext, objAPI, id, job)
+	return id
+}
+
+// StartOperation - starts the share of a batch operation job assigned
+// to this server in the background.
+func (b *batchJobs) StartOperation(ctx context.Context, objAPI ObjectLayer, id string, job madmin.BatchOperationJob) {
+	tags := make(url.Values, len(job.Tags))
+	for k, v := range job.Tags {
+		tags.Set(k, v)
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	j := &batchOperationJob{
+		job:     job,
+		tagging: tags.Encode(),
+		cancel:  cancel,
+		status: madmin.BatchJobStatus{
+			ID:        id,
+			Node:      GetLocalPeer(globalEndpoints),
+			Operation: job.Operation,
+			Running:   true,
+			StartTime: UTCNow(),
+			Total:     int64(len(job.Objects)),
+		},
+	}
+
+	b.mu.Lock()
+	b.jobs[id] = j
+	b.pruneFinished()
+	b.mu.Unlock()
+
+	go j.run(ctx, objAPI)
+}
+
+// Status - returns the progress of the job.
+func (j *batchOperationJob) Status() madmin.BatchJobStatus {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	status := j.status
+	status.Failures = append([]madmin.BatchJobFailure(nil), j.status.Failures...)
+	return status
+}
+
+// Cancel - stops the job, objects being processed are completed.
+func (j *batchOperationJob) Cancel() {
+	j.cancel()
+}
+
+func (j *batchOperationJob) run(ctx context.Context, objAPI ObjectLayer) {
+	defer j.cancel()
+
+	// Throttle the objects if a rate limit is set.
+	var throttle <-chan time.Time
+	if j.job.RateLimit > 0 {
+		ticker := time.NewTicker(time.Second / time.Duration(j.job.RateLimit))
+		defer ticker.Stop()
+		throttle = ticker.C
+	}
+
+	workers := j.job.Workers
+	if workers == 0 {
+		workers = defaultBatchJobWorkers
+	}
+	objects := make(chan madmin.BatchObject)
+	var wg sync.WaitGroup
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for o := range objects {
+				// Objects are processed to completion once
+				// started, even if the job is canceled.
+				j.record(o, j.apply(GlobalContext, objAPI, o))
+			}
+		}()
+	}
+
+	canceled := false
+	for _, o := range j.job.Objects {
+		if throttle != nil {
+			select {
+			case <-ctx.Done():
+			case <-throttle:
+			}
+		}
+		select {
+		case <-ctx.Done():
+			canceled = true
+		case objects <- o:
+		}
+		if canceled {
+			break
+		}
+	}
+	close(objects)
+	wg.Wait()
+
+	j.mu.Lock()
+	defer j.mu.Unlock()
+
+	j.status.Running = false
+	j.status.Canceled = canceled
+	j.status.EndTime = UTCNow()
+}
+
+// apply - applies the operation of the job to an object.
+func (j *batchOperationJob) apply(ctx context.Context, objAPI ObjectLayer, o madmin.BatchObject) error {
+	switch j.job.Operation {
+	case madmin.BatchOperationCopy:
+		objInfo, err := objAPI.GetObjectInfo(ctx, o.Bucket, o.Object, ObjectOptions{})
+		if err != nil {
+			return err
+		}
+		if crypto.IsEncrypted(objInfo.UserDefined) {
+			return errBatchJobEncryptedObject
+		}
+		_, err = copyObjectData(ctx, objAPI, o.Bucket, o.Object, j.job.TargetBucket, j.job.TargetPrefix+o.Object)
+		return err
+	case madmin.BatchOperationTag:
+		return updateObjectMetadata(ctx, objAPI, o.Bucket, o.Object, map[string]string{amzObjectTagging: j.tagging})
+	case madmin.BatchOperationDelete:
+		return objAPI.DeleteObject(ctx, o.Bucket, o.Object)
+	case madmin.BatchOperationRestore:
+		_, err := restoreFromTrash(ctx, objAPI, o.Bucket, o.Object)
+		return err
+	}
+	return errBatchJobInvalidOperation
+}
+
+// record - records the outcome for an object of the job.
+func (j *batchOperationJob) record(o madmin.BatchObject, err error) {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+
+	j.status.Scanned++
+	if err == nil {
+		j.status.Updated++
+		return
+	}
+	j.status.Failed++
+	if len(j.status.Failures) < maxBatchJobFailures {
+		j.status.Failures = append(j.status.Failures, madmin.BatchJobFailure{
+			Bucket: o.Bucket,
+			Object: o.Object,
+			Error:  err.Error(),
+		})
+	}
+}
+
+// getBatchJobReport - sums up the statuses of the job with the given
+// ID on all the servers, returns false if no server has the job.
+func getBatchJobReport(id string, statuses []madmin.BatchJobStatus) (madmin.BatchJobReport, bool) {
+	report := madmin.BatchJobReport{ID: id}
+	for _, status := range statuses {
+		if status.ID != id {
+			continue
+		}
+		if len(report.Nodes) == 0 || status.StartTime.Before(report.StartTime) {
+			report.StartTime = status.StartTime
+		}
+		if status.EndTime.After(report.EndTime) {
+			report.EndTime = status.EndTime
+		}
+		report.Operation = status.Operation
+		report.Running = report.Running || status.Running
+		report.Canceled = report.Canceled || status.Canceled
+		report.Total += status.Total
+		report.Scanned += status.Scanned
+		report.Updated += status.Updated
+		report.Failed += status.Failed
+		report.Failures = append(report.Failures, status.Failures...)
+
+		// Failures are reported once for the whole job.
+		status.Failures = nil
+		report.Nodes = append(report.Nodes, status)
+	}
+	if report.Running {
+		report.EndTime = time.Time{}
+	}
+	return report, len(report.Nodes) > 0
+}
@@ -0,0 +1,233 @@
+/*
+ * MinIO Cloud Storage, (C) 2020 MinIO, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/minio/minio/pkg/madmin"
+)
+
+// Tests that batch operation jobs copy, tag, delete and restore the
+// objects of their manifest.
+func TestBatchOperationJob(t *testing.T) {
+	obj, fsDir, err := prepareFS()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(fsDir)
+
+	ctx := context.Background()
+	for _, bucket := range []string{"bucket", "archive", "manifests"} {
+		if err = obj.MakeBucketWithLocation(ctx, bucket, ""); err != nil {
+			t.Fatal(err)
+		}
+	}
+	data := []byte("hello")
+	putObject := func(bucket, object string, data []byte) {
+		meta := map[string]string{"content-type": "video/mp4"}
+		if _, err = obj.PutObject(ctx, bucket, object, mustGetPutObjReader(t, bytes.NewReader(data), int64(len(data)), "", ""), ObjectOptions{UserDefined: meta}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	for _, object := range []string{"a", "b/c", "d"} {
+		putObject("bucket", object, data)
+	}
+	putObject("manifests", "copy.csv", []byte("bucket,a\nbucket,b/c\nbucket,missing\n"))
+
+	jobs := &batchJobs{jobs: make(map[string]batchJob)}
+	runJob := func(id string, job madmin.BatchOperationJob) madmin.BatchJobReport {
+		if err := loadBatchOperationJob(ctx, obj, &job); err != nil {
+			t.Fatal(err)
+		}
+		jobs.StartOperation(ctx, obj, id, job)
+		for {
+			report, ok := getBatchJobReport(id, jobs.Status())
+			if !ok {
+				t.Fatalf("Job %s not found", id)
+			}
+			if !report.Running {
+				return report
+			}
+			time.Sleep(10 * time.Millisecond)
+		}
+	}
+
+	status := runJob("copy", madmin.BatchOperationJob{
+		Operation:    madmin.BatchOperationCopy,
+		Manifest:     &madmin.BatchManifest{Bucket: "manifests", Object: "copy.csv"},
+		TargetBucket: "archive",
+		TargetPrefix: "2020/",
+		Workers:      2,
+	})
+	if status.Total != 3 || status.Scanned != 3 || status.Updated != 2 || status.Failed != 1 ||
+		len(status.Failures) != 1 || status.Failures[0].Object != "missing" {
+		t.Fatalf("Unexpected copy job status %v", status)
+	}
+	for _, object := range []string{"2020/a", "2020/b/c"} {
+		objInfo, err := obj.GetObjectInfo(ctx, "archive", object, ObjectOptions{})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if objInfo.ContentType != "video/mp4" || objInfo.Size != int64(len(data)) {
+			t.Errorf("%s: unexpected object info %v", object, objInfo)
+		}
+	}
+
+	status = runJob("tag", madmin.BatchOperationJob{
+		Operation: madmin.BatchOperationTag,
+		Objects:   []madmin.BatchObject{{Bucket: "bucket", Object: "a"}},
+		Tags:      map[string]string{"project": "x y"},
+		RateLimit: 100,
+	})
+	if status.Updated != 1 || status.Failed != 0 {
+		t.Fatalf("Unexpected tag job status %v", status)
+	}
+	objInfo, err := obj.GetObjectInfo(ctx, "bucket", "a", ObjectOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if objInfo.UserDefined[amzObjectTagging] != "project=x+y" || objInfo.ContentType != "video/mp4" {
+		t.Fatalf("Unexpected object info %v", objInfo)
+	}
+
+	if err = moveToTrash(ctx, obj, "bucket", "d"); err != nil {
+		t.Fatal(err)
+	}
+	status = runJob("delete", madmin.BatchOperationJob{
+		Operation: madmin.BatchOperationDelete,
+		Objects:   []madmin.BatchObject{{Bucket: "bucket", Object: "d"}, {Bucket: "bucket", Object: "b/c"}},
+	})
+	if status.Updated != 2 || status.Failed != 0 {
+		t.Fatalf("Unexpected delete job status %v", status)
+	}
+	for _, object := range []string{"d", "b/c"} {
+		if _, err = obj.GetObjectInfo(ctx, "bucket", object, ObjectOptions{}); !isErrObjectNotFound(err) {
+			t.Fatalf("%s: expected object to be deleted, got %v", object, err)
+		}
+	}
+
+	status = runJob("restore", madmin.BatchOperationJob{
+		Operation: madmin.BatchOperationRestore,
+		Objects:   []madmin.BatchObject{{Bucket: "bucket", Object: "d"}, {Bucket: "bucket", Object: "b/c"}},
+	})
+	if status.Updated != 1 || status.Failed != 1 || status.Failures[0].Object != "b/c" {
+		t.Fatalf("Unexpected restore job status %v", status)
+	}
+	if _, err = obj.GetObjectInfo(ctx, "bucket", "d", ObjectOptions{}); err != nil {
+		t.Fatal(err)
+	}
+
+	if jobs.Cancel("copy") {
+		t.Fatal("Expected a finished job not to be canceled")
+	}
+}
+
+// Tests the validation of batch operation jobs.
+func TestLoadBatchOperationJob(t *testing.T) {
+	obj, fsDir, err := prepareFS()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(fsDir)
+
+	ctx := context.Background()
+	if err = obj.MakeBucketWithLocation(ctx, "bucket", ""); err != nil {
+		t.Fatal(err)
+	}
+	manifest := []byte("bucket,a\nbucket\n")
+	if _, err = obj.PutObject(ctx, "bucket", "bad.csv", mustGetPutObjReader(t, bytes.NewReader(manifest), int64(len(manifest)), "", ""), ObjectOptions{}); err != nil {
+		t.Fatal(err)
+	}
+
+	objects := []madmin.BatchObject{{Bucket: "bucket", Object: "a"}}
+	testCases := []struct {
+		job         madmin.BatchOperationJob
+		expectedErr error
+	}{
+		{madmin.BatchOperationJob{Operation: madmin.BatchOperationDelete, Objects: objects}, nil},
+		{madmin.BatchOperationJob{Operation: "move", Objects: objects}, errBatchJobInvalidOperation},
+		{madmin.BatchOperationJob{Operation: madmin.BatchOperationCopy, Objects: objects}, errBatchJobNoTarget},
+		{madmin.BatchOperationJob{Operation: madmin.BatchOperationCopy, Objects: objects, TargetBucket: "none"}, BucketNotFound{Bucket: "none"}},
+		{madmin.BatchOperationJob{Operation: madmin.BatchOperationTag, Objects: objects}, errBatchJobNoTags},
+		{madmin.BatchOperationJob{Operation: madmin.BatchOperationDelete}, errBatchJobNoObjects},
+		{madmin.BatchOperationJob{Operation: madmin.BatchOperationDelete, Objects: objects,
+			Manifest: &madmin.BatchManifest{Bucket: "bucket", Object: "bad.csv"}}, errBatchJobNoObjects},
+		{madmin.BatchOperationJob{Operation: madmin.BatchOperationDelete,
+			Manifest: &madmin.BatchManifest{Bucket: "bucket", Object: "bad.csv"}}, errBatchJobInvalidManifest},
+		{madmin.BatchOperationJob{Operation: madmin.BatchOperationDelete, Objects: objects, Workers: maxBatchJobWorkers + 1}, errBatchJobInvalidWorkers},
+		{madmin.BatchOperationJob{Operation: madmin.BatchOperationDelete,
+			Objects: []madmin.BatchObject{{Bucket: minioMetaBucket, Object: "config/config.json"}}}, errBatchJobInvalidBucket},
+	}
+	for i, testCase := range testCases {
+		if err := loadBatchOperationJob(ctx, obj, &testCase.job); err != testCase.expectedErr {
+			t.Errorf("Test %d: expected %v, got %v", i+1, testCase.expectedErr, err)
+		}
+	}
+}
+
+// Tests that the statuses of a job on all servers are summed up.
+func TestGetBatchJobReport(t *testing.T) {
+	start := UTCNow()
+	statuses := []madmin.BatchJobStatus{
+		{ID: "job", Node: "node1", StartTime: start, EndTime: start.Add(time.Minute), Total: 2, Scanned: 2, Updated: 2},
+		{ID: "other", Node: "node1", StartTime: start, Running: true},
+		{ID: "job", Node: "node2", StartTime: start.Add(-time.Second), Running: true, Total: 3, Scanned: 1, Failed: 1,
+			Failures: []madmin.BatchJobFailure{{Bucket: "bucket", Object: "a", Error: "error"}}},
+	}
+
+	report, ok := getBatchJobReport("job", statuses)
+	if !ok {
+		t.Fatal("Expected job to be found")
+	}
+	if !report.Running || !report.EndTime.IsZero() || !report.StartTime.Equal(start.Add(-time.Second)) ||
+		report.Total != 5 || report.Scanned != 3 || report.Updated != 2 || report.Failed != 1 ||
+		len(report.Failures) != 1 || len(report.Nodes) != 2 || report.Nodes[1].Failures != nil {
+		t.Fatalf("Unexpected report %v", report)
+	}
+
+	if _, ok = getBatchJobReport("none", statuses); ok {
+		t.Fatal("Expected job not to be found")
+	}
+}
+
+func TestSplitBatchJobObjects(t *testing.T) {
+	var objects []madmin.BatchObject
+	for i := 0; i < 10; i++ {
+		objects = append(objects, madmin.BatchObject{Bucket: "bucket", Object: string(rune('a' + i))})
+	}
+	shares := splitBatchJobObjects(objects, 3)
+	if len(shares) != 3 || len(shares[0]) != 4 || len(shares[1]) != 3 || len(shares[2]) != 3 {
+		t.Fatalf("Unexpected shares %v", shares)
+	}
+	seen := make(map[string]bool)
+	for _, share := range shares {
+		for _, o := range share {
+			if seen[o.Object] {
+				t.Fatalf("Object %s assigned twice", o.Object)
+			}
+			seen[o.Object] = true
+		}
+	}
+	if len(seen) != len(objects) {
+		t.Fatalf("Expected all %d objects to be assigned, got %d", len(objects), len(seen))
+	}
+}
@@ -0,0 +1,274 @@
+/*
+ * MinIO Cloud Storage, (C) 2019 MinIO, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/minio/minio/cmd/logger"
+	"github.com/minio/minio/pkg/madmin"
+)
+
+// Number of finished batch jobs whose status is kept.
+const maxFinishedBatchJobs = 100
+
+var (
+	errBatchJobNoMetadata      = errors.New("Batch update job requires metadata to set")
+	errBatchJobInvalidMetadata = errors.New("Batch update job metadata must be user metadata or a supported header")
+)
+
+// batchUpdateJob - sets metadata on all objects under a prefix, objects
+// are updated in place through metadata only server-side copies.
+type batchUpdateJob struct {
+	job    madmin.BatchUpdateJob
+	cancel context.CancelFunc
+
+	mu     sync.Mutex
+	status madmin.BatchJobStatus
+}
+
+// batchJob - a batch job running in the background.
+type batchJob interface {
+	Status() madmin.BatchJobStatus
+	Cancel()
+}
+
+// batchJobs - holds the batch jobs started on this server.
+type batchJobs struct {
+	mu   sync.Mutex
+	jobs map[string]batchJob
+}
+
+var globalBatchJobs = &batchJobs{jobs: make(map[string]batchJob)}
+
+// parseBatchUpdateMetadata - validates the metadata of a batch update
+// job, returns it keyed the same way as metadata of PutObject requests.
+func parseBatchUpdateMetadata(ctx context.Context, metadata map[string]string) (map[string]string, error) {
+	if len(metadata) == 0 {
+		return nil, errBatchJobNoMetadata
+	}
+
+	header := make(http.Header, len(metadata))
+	for k, v := range metadata {
+		header.Set(k, v)
+	}
+	m := make(map[string]string, len(metadata))
+	if err := extractMetadataFromMap(ctx, header, m); err != nil {
+		return nil, err
+	}
+	if _, ok := m[amzStorageClass]; ok || len(m) != len(header) {
+		return nil, errBatchJobInvalidMetadata
+	}
+	return m, nil
+}
+
+// StartUpdate - starts a batch update job in the background, returns
+// the job ID.
+func (b *batchJobs) StartUpdate(ctx context.Context, objAPI ObjectLayer, job madmin.BatchUpdateJob) (string, error) {
+	metadata, err := parseBatchUpdateMetadata(ctx, job.Metadata)
+	if err != nil {
+		return "", err
+	}
+	if _, err = objAPI.GetBucketInfo(ctx, job.Bucket); err != nil {
+		return "", err
+	}
+	job.Metadata = metadata
+
+	ctx, cancel := context.WithCancel(ctx)
+	j := &batchUpdateJob{
+		job:    job,
+		cancel: cancel,
+		status: madmin.BatchJobStatus{
+			ID:        mustGetUUID(),
+			Node:      GetLocalPeer(globalEndpoints),
+			Bucket:    job.Bucket,
+			Prefix:    job.Prefix,
+			Running:   true,
+			StartTime: UTCNow(),
+		},
+	}
+
+	b.mu.Lock()
+	b.jobs[j.status.ID] = j
+	b.pruneFinished()
+	b.mu.Unlock()
+
+	go j.run(ctx, objAPI)
+	return j.status.ID, nil
+}
+
+// pruneFinished - drops the oldest finished jobs beyond
+// maxFinishedBatchJobs, must be called with b.mu held.
+func (b *batchJobs) pruneFinished() {
+	var finished []madmin.BatchJobStatus
+	for _, j := range b.jobs {
+		if status := j.Status(); !status.Running {
+			finished = append(finished, status)
+		}
+	}
+	if len(finished) <= maxFinishedBatchJobs {
+		return
+	}
+	sort.Slice(finished, func(i, j int) bool {
+		return finished[i].EndTime.Before(finished[j].EndTime)
+	})
+	for _, status := range finished[:len(finished)-maxFinishedBatchJobs] {
+		delete(b.jobs, status.ID)
+	}
+}
+
+// Status - returns the status of all batch jobs of this server, most
+// recently started first.
+func (b *batchJobs) Status() []madmin.BatchJobStatus {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	statuses := make([]madmin.BatchJobStatus, 0, len(b.jobs))
+	for _, j := range b.jobs {
+		statuses = append(statuses, j.Status())
+	}
+	sort.Slice(statuses, func(i, j int) bool {
+		return statuses[i].StartTime.After(statuses[j].StartTime)
+	})
+	return statuses
+}
+
+// Cancel - stops the running job with the given ID, returns false if
+// no such job is running on this server.
+func (b *batchJobs) Cancel(id string) bool {
+	b.mu.Lock()
+	j, ok := b.jobs[id]
+	b.mu.Unlock()
+	if !ok || !j.Status().Running {
+		return false
+	}
+	j.Cancel()
+	return true
+}
+
+// Status - returns the progress of the job.
+func (j *batchUpdateJob) Status() madmin.BatchJobStatus {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	return j.status
+}
+
+// Cancel - stops the job.
+func (j *batchUpdateJob) Cancel() {
+	j.cancel()
+}
+
+func (j *batchUpdateJob) run(ctx context.Context, objAPI ObjectLayer) {
+	defer j.cancel()
+
+	err := j.update(ctx, objAPI)
+	canceled := err == context.Canceled
+	if !canceled {
+		logger.LogIf(ctx, err)
+	}
+
+	j.mu.Lock()
+	defer j.mu.Unlock()
+
+	j.status.Running = false
+	j.status.Canceled = canceled
+	j.status.EndTime = UTCNow()
+	if err != nil && !canceled {
+		j.status.Error = err.Error()
+	}
+}
+
+func (j *batchUpdateJob) update(ctx context.Context, objAPI ObjectLayer) error {
+	// Throttle the server-side copies if a rate limit is set.
+	var throttle <-chan time.Time
+	if j.job.RateLimit > 0 {
+		ticker := time.NewTicker(time.Second / time.Duration(j.job.RateLimit))
+		defer ticker.Stop()
+		throttle = ticker.C
+	}
+
+	marker := ""
+	for {
+		result, err := objAPI.ListObjects(ctx, j.job.Bucket, j.job.Prefix, marker, "", maxObjectList)
+		if err != nil {
+			return err
+		}
+		for _, obj := range result.Objects {
+			if throttle != nil {
+				select {
+				case <-ctx.Done():
+					return ctx.Err()
+				case <-throttle:
+				}
+			} else if err = ctx.Err(); err != nil {
+				return err
+			}
+
+			err = updateObjectMetadata(ctx, objAPI, j.job.Bucket, obj.Name, j.job.Metadata)
+			if err != nil && !isErrObjectNotFound(err) {
+				logger.LogIf(ctx, err)
+			}
+			j.record(err)
+		}
+		if !result.IsTruncated {
+			return nil
+		}
+		marker = result.NextMarker
+	}
+}
+
+// record - records the outcome for an object of the job.
+func (j *batchUpdateJob) record(err error) {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+
+	j.status.Scanned++
+	switch {
+	case err == nil:
+		j.status.Updated++
+	case !isErrObjectNotFound(err):
+		j.status.Failed++
+	}
+}
+
+// updateObjectMetadata - sets the given metadata on an object, keeping
+// its other metadata, only the object metadata is rewritten.
+func updateObjectMetadata(ctx context.Context, objAPI ObjectLayer, bucket, object string, metadata map[string]string) error {
+	objInfo, err := objAPI.GetObjectInfo(ctx, bucket, object, ObjectOptions{})
+	if err != nil {
+		return err
+	}
+
+	userDefined := make(map[string]string, len(objInfo.UserDefined)+len(metadata))
+	for k, v := range objInfo.UserDefined {
+		userDefined[k] = v
+	}
+	for k, v := range metadata {
+		userDefined[k] = v
+	}
+
+	// Same as a metadata REPLACE through CopyObject onto itself.
+	objInfo.UserDefined = userDefined
+	objInfo.metadataOnly = true
+	_, err = objAPI.CopyObject(ctx, bucket, object, bucket, object, objInfo, ObjectOptions{}, ObjectOptions{})
+	return err
+}
@@ -0,0 +1,116 @@
+/*
+ * MinIO Cloud Storage, (C) 2019 MinIO, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/minio/minio/pkg/madmin"
+)
+
+// Tests that a batch update job sets metadata on the objects under
+// the prefix only, keeping their content and other metadata.
+func TestBatchUpdateJob(t *testing.T) {
+	obj, fsDir, err := prepareFS()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(fsDir)
+
+	ctx := context.Background()
+	bucket := "bucket"
+	if err = obj.MakeBucketWithLocation(ctx, bucket, ""); err != nil {
+		t.Fatal(err)
+	}
+
+	data := []byte("hello")
+	for _, object := range []string{"media/a.mp4", "media/b/c.mp4", "other"} {
+		meta := map[string]string{"content-type": "video/mp4", "X-Amz-Meta-Owner": "alice"}
+		if _, err = obj.PutObject(ctx, bucket, object, mustGetPutObjReader(t, bytes.NewReader(data), int64(len(data)), "", ""), ObjectOptions{UserDefined: meta}); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	jobs := &batchJobs{jobs: make(map[string]batchJob)}
+	id, err := jobs.StartUpdate(ctx, obj, madmin.BatchUpdateJob{
+		Bucket:    bucket,
+		Prefix:    "media/",
+		Metadata:  map[string]string{"Cache-Control": "max-age=3600"},
+		RateLimit: 100,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for jobs.Status()[0].Running {
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	status := jobs.Status()[0]
+	if status.ID != id || status.Error != "" || status.Scanned != 2 || status.Updated != 2 || status.Failed != 0 {
+		t.Fatalf("Unexpected job status %v", status)
+	}
+
+	for object, cacheControl := range map[string]string{"media/a.mp4": "max-age=3600", "media/b/c.mp4": "max-age=3600", "other": ""} {
+		objInfo, err := obj.GetObjectInfo(ctx, bucket, object, ObjectOptions{})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if objInfo.UserDefined["cache-control"] != cacheControl {
+			t.Errorf("%s: expected cache-control %q, got %q", object, cacheControl, objInfo.UserDefined["cache-control"])
+		}
+		if objInfo.ContentType != "video/mp4" || objInfo.UserDefined["X-Amz-Meta-Owner"] != "alice" || objInfo.Size != int64(len(data)) {
+			t.Errorf("%s: unexpected object info %v", object, objInfo)
+		}
+	}
+
+	if jobs.Cancel(id) {
+		t.Fatal("Expected a finished job not to be canceled")
+	}
+}
+
+// Tests that only user metadata and supported headers can be set.
+func TestParseBatchUpdateMetadata(t *testing.T) {
+	testCases := []struct {
+		metadata    map[string]string
+		expected    map[string]string
+		expectedErr error
+	}{
+		{nil, nil, errBatchJobNoMetadata},
+		{map[string]string{"Cache-Control": "no-cache", "x-amz-meta-owner": "alice"},
+			map[string]string{"cache-control": "no-cache", "X-Amz-Meta-Owner": "alice"}, nil},
+		{map[string]string{"X-Amz-Storage-Class": "STANDARD"}, nil, errBatchJobInvalidMetadata},
+		{map[string]string{"Etag": "abc"}, nil, errBatchJobInvalidMetadata},
+	}
+	for i, testCase := range testCases {
+		metadata, err := parseBatchUpdateMetadata(context.Background(), testCase.metadata)
+		if err != testCase.expectedErr {
+			t.Fatalf("Test %d: expected %v, got %v", i+1, testCase.expectedErr, err)
+		}
+		if err == nil && len(metadata) != len(testCase.expected) {
+			t.Fatalf("Test %d: expected %v, got %v", i+1, testCase.expected, metadata)
+		}
+		for k, v := range testCase.expected {
+			if metadata[k] != v {
+				t.Fatalf("Test %d: expected %v, got %v", i+1, testCase.expected, metadata)
+			}
+		}
+	}
+}
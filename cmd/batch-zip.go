@@ -0,0 +1,313 @@
+/*
+ * MinIO Cloud Storage, (C) 2020 MinIO, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	humanize "github.com/dustin/go-humanize"
+	"github.com/minio/minio/cmd/crypto"
+	"github.com/minio/minio/cmd/logger"
+	"github.com/minio/minio/pkg/hash"
+	"github.com/minio/minio/pkg/madmin"
+)
+
+const (
+	// Archives of download zip jobs are written to this prefix of the
+	// bucket of the zipped objects.
+	downloadZipJobPrefix = ".minio-zip/"
+
+	// Archives of download zip jobs are removed once expired.
+	downloadZipJobExpiry = 24 * time.Hour
+
+	// Size of the parts the archive of a download zip job is uploaded
+	// in, the last part may be smaller.
+	downloadZipJobPartSize = 64 * humanize.MiByte
+)
+
+var errDownloadZipJobSSECObject = errors.New("Objects encrypted with SSE-C are not added to archives of download zip jobs")
+
+// batchZipJob - writes the objects of a DownloadZip request to an
+// archive object in the background.
+type batchZipJob struct {
+	args   DownloadZipArgs
+	cancel context.CancelFunc
+
+	mu     sync.Mutex
+	status madmin.BatchJobStatus
+}
+
+// getDownloadZipJobObject - returns the name of the archive object of
+// a download zip job.
+func getDownloadZipJobObject(id string) string {
+	return downloadZipJobPrefix + id + ".zip"
+}
+
+// StartZip - starts a download zip job in the background, returns the
+// job ID.
+func (b *batchJobs) StartZip(ctx context.Context, objAPI ObjectLayer, args DownloadZipArgs) (string, error) {
+	if _, err := objAPI.GetBucketInfo(ctx, args.BucketName); err != nil {
+		return "", err
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	j := &batchZipJob{
+		args:   args,
+		cancel: cancel,
+		status: madmin.BatchJobStatus{
+			ID:        mustGetUUID(),
+			Node:      GetLocalPeer(globalEndpoints),
+			Operation: madmin.BatchOperationZip,
+			Bucket:    args.BucketName,
+			Prefix:    args.Prefix,
+			Running:   true,
+			StartTime: UTCNow(),
+		},
+	}
+
+	b.mu.Lock()
+	b.jobs[j.status.ID] = j
+	b.pruneFinished()
+	b.mu.Unlock()
+
+	go j.run(ctx, objAPI)
+	return j.status.ID, nil
+}
+
+// Status - returns the progress of the job.
+func (j *batchZipJob) Status() madmin.BatchJobStatus {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	status := j.status
+	status.Failures = append([]madmin.BatchJobFailure(nil), j.status.Failures...)
+	return status
+}
+
+// Cancel - stops the job, the archive is not written.
+func (j *batchZipJob) Cancel() {
+	j.cancel()
+}
+
+func (j *batchZipJob) run(ctx context.Context, objAPI ObjectLayer) {
+	defer j.cancel()
+
+	err := j.zip(ctx, objAPI)
+	canceled := err == context.Canceled
+	if !canceled {
+		logger.LogIf(ctx, err)
+	}
+
+	j.mu.Lock()
+	defer j.mu.Unlock()
+
+	j.status.Running = false
+	j.status.Canceled = canceled
+	j.status.EndTime = UTCNow()
+	if err != nil && !canceled {
+		j.status.Error = err.Error()
+	}
+}
+
+// zip - writes the archive object through a multipart upload, the
+// upload is aborted if the archive cannot be written entirely.
+func (j *batchZipJob) zip(ctx context.Context, objAPI ObjectLayer) error {
+	bucket := j.args.BucketName
+	object := getDownloadZipJobObject(j.status.ID)
+	opts := ObjectOptions{UserDefined: map[string]string{"content-type": "application/zip"}}
+	uploadID, err := objAPI.NewMultipartUpload(ctx, bucket, object, opts)
+	if err != nil {
+		return err
+	}
+
+	pw := &zipPartWriter{
+		ctx:      ctx,
+		objAPI:   objAPI,
+		bucket:   bucket,
+		object:   object,
+		uploadID: uploadID,
+	}
+	archive := zip.NewWriter(pw)
+	if err = j.addObjects(ctx, objAPI, archive); err == nil {
+		if err = archive.Close(); err == nil {
+			err = pw.flush(pw.buf.Len())
+		}
+	}
+	if err == nil {
+		_, err = objAPI.CompleteMultipartUpload(ctx, bucket, object, uploadID, pw.parts, ObjectOptions{})
+	}
+	if err != nil {
+		// The job context may be canceled already.
+		logger.LogIf(ctx, objAPI.AbortMultipartUpload(context.Background(), bucket, object, uploadID))
+		return err
+	}
+
+	time.AfterFunc(downloadZipJobExpiry, func() {
+		err := objAPI.DeleteObject(GlobalContext, bucket, object)
+		if err != nil && !isErrObjectNotFound(err) {
+			logger.LogIf(GlobalContext, err)
+		}
+	})
+	return nil
+}
+
+// addObjects - adds the objects of the request to the archive in order,
+// directories are added recursively. Objects which cannot be read are
+// recorded as failures and left out of the archive.
+func (j *batchZipJob) addObjects(ctx context.Context, objAPI ObjectLayer, archive *zip.Writer) error {
+	add := func(objectName string) error {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		gr, err := openZipJobObject(ctx, objAPI, j.args.BucketName, objectName)
+		if err != nil {
+			j.record(objectName, err)
+			return nil
+		}
+		defer gr.Close()
+
+		header := &zip.FileHeader{
+			Name:   strings.TrimPrefix(objectName, j.args.Prefix),
+			Method: zip.Deflate,
+		}
+		header.SetModTime(gr.ObjInfo.ModTime)
+		zipWriter, err := archive.CreateHeader(header)
+		if err != nil {
+			return err
+		}
+		// The archive is corrupted by partially written objects.
+		if _, err = io.Copy(zipWriter, gr); err != nil {
+			return err
+		}
+		j.record(objectName, nil)
+		return nil
+	}
+
+	for _, object := range j.args.Objects {
+		if !hasSuffix(object, SlashSeparator) {
+			if err := add(pathJoin(j.args.Prefix, object)); err != nil {
+				return err
+			}
+			continue
+		}
+
+		marker := ""
+		for {
+			lo, err := objAPI.ListObjects(ctx, j.args.BucketName, pathJoin(j.args.Prefix, object), marker, "", maxObjectList)
+			if err != nil {
+				return err
+			}
+			for _, obj := range lo.Objects {
+				// Archives of earlier jobs are not archived again.
+				if strings.HasPrefix(obj.Name, downloadZipJobPrefix) {
+					continue
+				}
+				if err = add(obj.Name); err != nil {
+					return err
+				}
+			}
+			if !lo.IsTruncated {
+				break
+			}
+			marker = lo.NextMarker
+		}
+	}
+	return nil
+}
+
+// openZipJobObject - opens an object to be added to the archive of a
+// download zip job, SSE-S3 objects are decrypted, SSE-C objects cannot
+// be as their keys are not kept by the job.
+func openZipJobObject(ctx context.Context, objAPI ObjectLayer, bucket, object string) (*GetObjectReader, error) {
+	objInfo, err := objAPI.GetObjectInfo(ctx, bucket, object, ObjectOptions{})
+	if err != nil {
+		return nil, err
+	}
+	if crypto.SSEC.IsEncrypted(objInfo.UserDefined) {
+		return nil, errDownloadZipJobSSECObject
+	}
+	return objAPI.GetObjectNInfo(ctx, bucket, object, nil, http.Header{}, readLock, ObjectOptions{})
+}
+
+// record - records the outcome for an object of the job, objects
+// removed since they were listed are not failures.
+func (j *batchZipJob) record(object string, err error) {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+
+	j.status.Scanned++
+	switch {
+	case err == nil:
+		j.status.Updated++
+	case !isErrObjectNotFound(err):
+		j.status.Failed++
+		if len(j.status.Failures) < maxBatchJobFailures {
+			j.status.Failures = append(j.status.Failures, madmin.BatchJobFailure{
+				Bucket: j.args.BucketName,
+				Object: object,
+				Error:  err.Error(),
+			})
+		}
+	}
+}
+
+// zipPartWriter - uploads the data written to it as the parts of a
+// multipart upload of downloadZipJobPartSize bytes each, the remaining
+// data is uploaded by a last call to flush.
+type zipPartWriter struct {
+	ctx      context.Context
+	objAPI   ObjectLayer
+	bucket   string
+	object   string
+	uploadID string
+
+	buf   bytes.Buffer
+	parts []CompletePart
+}
+
+func (p *zipPartWriter) Write(b []byte) (int, error) {
+	n, _ := p.buf.Write(b)
+	for p.buf.Len() >= downloadZipJobPartSize {
+		if err := p.flush(downloadZipJobPartSize); err != nil {
+			return 0, err
+		}
+	}
+	return n, nil
+}
+
+// flush - uploads the next size bytes of buffered data as a part.
+func (p *zipPartWriter) flush(size int) error {
+	data := p.buf.Next(size)
+	hashReader, err := hash.NewReader(bytes.NewReader(data), int64(len(data)), "", "", int64(len(data)), globalCLIContext.StrictS3Compat)
+	if err != nil {
+		return err
+	}
+	partID := len(p.parts) + 1
+	info, err := p.objAPI.PutObjectPart(p.ctx, p.bucket, p.object, p.uploadID, partID, NewPutObjReader(hashReader, nil, nil), ObjectOptions{})
+	if err != nil {
+		return err
+	}
+	p.parts = append(p.parts, CompletePart{PartNumber: partID, ETag: info.ETag})
+	return nil
+}
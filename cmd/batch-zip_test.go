@@ -0,0 +1,102 @@
+/*
+ * MinIO Cloud Storage, (C) 2020 MinIO, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"io/ioutil"
+	"os"
+	"reflect"
+	"sort"
+	"testing"
+	"time"
+)
+
+// Tests that a download zip job writes the requested objects to its
+// archive object, directories recursively.
+func TestBatchZipJob(t *testing.T) {
+	obj, fsDir, err := prepareFS()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(fsDir)
+
+	ctx := context.Background()
+	if err = obj.MakeBucketWithLocation(ctx, "bucket", ""); err != nil {
+		t.Fatal(err)
+	}
+
+	data := []byte("hello")
+	for _, object := range []string{"photos/a.jpg", "photos/b/c.jpg", "photos/d.jpg", "other"} {
+		if _, err = obj.PutObject(ctx, "bucket", object, mustGetPutObjReader(t, bytes.NewReader(data), int64(len(data)), "", ""), ObjectOptions{}); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	jobs := &batchJobs{jobs: make(map[string]batchJob)}
+	id, err := jobs.StartZip(ctx, obj, DownloadZipArgs{
+		BucketName: "bucket",
+		Prefix:     "photos/",
+		Objects:    []string{"b/", "a.jpg", "missing.jpg"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for jobs.Status()[0].Running {
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	status := jobs.Status()[0]
+	if status.ID != id || status.Error != "" || status.Scanned != 3 || status.Updated != 2 || status.Failed != 0 {
+		t.Fatalf("Unexpected job status %v", status)
+	}
+
+	var buf bytes.Buffer
+	if err = obj.GetObject(ctx, "bucket", getDownloadZipJobObject(id), 0, -1, &buf, "", ObjectOptions{}); err != nil {
+		t.Fatal(err)
+	}
+	archive, err := zip.NewReader(bytes.NewReader(buf.Bytes()), int64(buf.Len()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var names []string
+	for _, file := range archive.File {
+		names = append(names, file.Name)
+		rc, err := file.Open()
+		if err != nil {
+			t.Fatal(err)
+		}
+		content, err := ioutil.ReadAll(rc)
+		rc.Close()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !bytes.Equal(content, data) {
+			t.Errorf("%s: unexpected content %q", file.Name, content)
+		}
+	}
+	sort.Strings(names)
+	if expected := []string{"a.jpg", "b/c.jpg"}; !reflect.DeepEqual(names, expected) {
+		t.Fatalf("Expected archive entries %v, got %v", expected, names)
+	}
+
+	if _, err = jobs.StartZip(ctx, obj, DownloadZipArgs{BucketName: "missing"}); err == nil {
+		t.Fatal("Expected a job of a missing bucket to fail")
+	}
+}
@@ -0,0 +1,231 @@
+/*
+ * MinIO Cloud Storage, (C) 2020 MinIO, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"path"
+	"time"
+
+	jwtgo "github.com/dgrijalva/jwt-go"
+	"github.com/minio/minio/cmd/logger"
+	"github.com/minio/minio/pkg/auth"
+	iampolicy "github.com/minio/minio/pkg/iam/policy"
+	"github.com/minio/minio/pkg/madmin"
+	"github.com/minio/minio/pkg/policy"
+	"github.com/minio/minio/pkg/policy/condition"
+)
+
+const (
+	// Invites are valid for a week by default.
+	defaultInviteExpiry = 7 * 24 * time.Hour
+
+	// Invites are valid for at most a month.
+	maxInviteExpiry = 30 * 24 * time.Hour
+
+	// Audience of invite tokens, tells them apart from login tokens.
+	inviteAudience = "minio-browser-invite"
+
+	// Accepted invites are recorded under this prefix, each invite
+	// may only be accepted once.
+	inviteConfigPrefix = minioConfigPrefix + "/invites"
+)
+
+var (
+	errInvalidInvite       = errors.New("The invite link is invalid or has expired")
+	errInviteAccepted      = errors.New("The invite link has already been used")
+	errInvalidInvitePolicy = errors.New("Invites grant one of the readonly, writeonly or readwrite policies")
+)
+
+// inviteClaims - claims of invite tokens, granting the policy on the
+// bucket to a new user.
+type inviteClaims struct {
+	Bucket string `json:"bucket"`
+	Policy string `json:"policy"`
+	jwtgo.StandardClaims
+}
+
+// inviteRecord - records the user created by an accepted invite.
+type inviteRecord struct {
+	Bucket     string    `json:"bucket"`
+	Policy     string    `json:"policy"`
+	AccessKey  string    `json:"accessKey"`
+	AcceptedAt time.Time `json:"acceptedAt"`
+}
+
+// bucketInvitePolicy - returns the canned policy named policyName
+// restricted to the bucket. Users of the browser need to list the
+// objects they can read, so readonly also allows listing. None of the
+// policies allow changing the configuration of the bucket.
+func bucketInvitePolicy(bucket, policyName string) (iampolicy.Policy, error) {
+	var actions iampolicy.ActionSet
+	switch policyName {
+	case "readonly":
+		actions = iampolicy.NewActionSet(iampolicy.GetBucketLocationAction, iampolicy.ListBucketAction,
+			iampolicy.GetObjectAction)
+	case "writeonly":
+		actions = iampolicy.NewActionSet(iampolicy.GetBucketLocationAction, iampolicy.PutObjectAction)
+	case "readwrite":
+		actions = iampolicy.NewActionSet(iampolicy.GetBucketLocationAction, iampolicy.ListBucketAction,
+			iampolicy.ListBucketMultipartUploadsAction, iampolicy.GetObjectAction, iampolicy.PutObjectAction,
+			iampolicy.DeleteObjectAction, iampolicy.AbortMultipartUploadAction, iampolicy.ListMultipartUploadPartsAction)
+	default:
+		return iampolicy.Policy{}, errInvalidInvitePolicy
+	}
+
+	return iampolicy.Policy{
+		Version: iampolicy.DefaultVersion,
+		Statements: []iampolicy.Statement{
+			iampolicy.NewStatement(
+				policy.Allow,
+				actions,
+				iampolicy.NewResourceSet(
+					iampolicy.NewResource(bucket, ""),
+					iampolicy.NewResource(bucket, "*"),
+				),
+				condition.NewFunctions(),
+			),
+		},
+	}, nil
+}
+
+// bucketInvitePolicyName - name of the IAM policy attached to the users
+// invited to the bucket with policyName.
+func bucketInvitePolicyName(bucket, policyName string) string {
+	return fmt.Sprintf("invite-%s-%s", bucket, policyName)
+}
+
+// newInviteToken - returns a token inviting a new user to the bucket
+// with the canned policy, signed with the credentials of the owner.
+func newInviteToken(bucket, policyName string, expiry time.Duration) (string, error) {
+	if _, err := bucketInvitePolicy(bucket, policyName); err != nil {
+		return "", err
+	}
+	if expiry <= 0 || expiry > maxInviteExpiry {
+		return "", errInvalidArgument
+	}
+
+	jwt := jwtgo.NewWithClaims(jwtgo.SigningMethodHS512, inviteClaims{
+		Bucket: bucket,
+		Policy: policyName,
+		StandardClaims: jwtgo.StandardClaims{
+			Audience:  inviteAudience,
+			ExpiresAt: UTCNow().Add(expiry).Unix(),
+			Id:        mustGetUUID(),
+		},
+	})
+	return jwt.SignedString([]byte(globalServerConfig.GetCredential().SecretKey))
+}
+
+// parseInviteToken - verifies an invite token and returns its claims.
+func parseInviteToken(token string) (inviteClaims, error) {
+	var claims inviteClaims
+	jwtToken, err := jwtgo.ParseWithClaims(token, &claims, func(jwtToken *jwtgo.Token) (interface{}, error) {
+		if _, ok := jwtToken.Method.(*jwtgo.SigningMethodHMAC); !ok {
+			return nil, errInvalidInvite
+		}
+		return []byte(globalServerConfig.GetCredential().SecretKey), nil
+	})
+	if err != nil || !jwtToken.Valid || !claims.VerifyAudience(inviteAudience, true) ||
+		claims.Id == "" || claims.Bucket == "" {
+		return inviteClaims{}, errInvalidInvite
+	}
+	return claims, nil
+}
+
+// acceptInvite - creates a new user with the policy of the invite on
+// its bucket, returns the credentials of the user. An invite can only
+// be accepted once.
+func acceptInvite(ctx context.Context, objAPI ObjectLayer, token string) (auth.Credentials, error) {
+	claims, err := parseInviteToken(token)
+	if err != nil {
+		return auth.Credentials{}, err
+	}
+	p, err := bucketInvitePolicy(claims.Bucket, claims.Policy)
+	if err != nil {
+		return auth.Credentials{}, errInvalidInvite
+	}
+	if _, err = objAPI.GetBucketInfo(ctx, claims.Bucket); err != nil {
+		return auth.Credentials{}, err
+	}
+
+	configFile := path.Join(inviteConfigPrefix, claims.Id+".json")
+	inviteLock := globalNSMutex.NewNSLock(ctx, minioMetaBucket, configFile)
+	if err = inviteLock.GetLock(globalOperationTimeout); err != nil {
+		return auth.Credentials{}, err
+	}
+	defer inviteLock.Unlock()
+
+	if _, err = readConfig(ctx, objAPI, configFile); err != errConfigNotFound {
+		if err == nil {
+			err = errInviteAccepted
+		}
+		return auth.Credentials{}, err
+	}
+
+	cred, err := auth.GetNewCredentials()
+	if err != nil {
+		return auth.Credentials{}, err
+	}
+
+	// The invite is recorded as accepted before the user is created,
+	// so that it cannot create more than one user.
+	data, err := json.Marshal(inviteRecord{
+		Bucket:     claims.Bucket,
+		Policy:     claims.Policy,
+		AccessKey:  cred.AccessKey,
+		AcceptedAt: UTCNow(),
+	})
+	if err != nil {
+		return auth.Credentials{}, err
+	}
+	if err = saveConfig(ctx, objAPI, configFile, data); err != nil {
+		return auth.Credentials{}, err
+	}
+
+	policyName := bucketInvitePolicyName(claims.Bucket, claims.Policy)
+	if err = globalIAMSys.SetPolicy(policyName, p); err == nil {
+		err = globalIAMSys.SetUser(cred.AccessKey, madmin.UserInfo{
+			SecretKey:  cred.SecretKey,
+			PolicyName: policyName,
+			Status:     madmin.AccountEnabled,
+		})
+	}
+	if err != nil {
+		// No user was created, the invite may be accepted again.
+		logger.LogIf(ctx, deleteConfig(ctx, objAPI, configFile))
+		return auth.Credentials{}, err
+	}
+
+	// Notify all other MinIO peers to load the policy and the user.
+	for _, nerr := range globalNotificationSys.LoadPolicy(policyName) {
+		if nerr.Err != nil {
+			logger.GetReqInfo(ctx).SetTags("peerAddress", nerr.Host.String())
+			logger.LogIf(ctx, nerr.Err)
+		}
+	}
+	for _, nerr := range globalNotificationSys.LoadUser(cred.AccessKey, false) {
+		if nerr.Err != nil {
+			logger.GetReqInfo(ctx).SetTags("peerAddress", nerr.Host.String())
+			logger.LogIf(ctx, nerr.Err)
+		}
+	}
+	return cred, nil
+}
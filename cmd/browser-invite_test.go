@@ -0,0 +1,102 @@
+/*
+ * MinIO Cloud Storage, (C) 2020 MinIO, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"os"
+	"testing"
+	"time"
+
+	iampolicy "github.com/minio/minio/pkg/iam/policy"
+)
+
+func TestInviteToken(t *testing.T) {
+	obj, fsDir, err := prepareFS()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(fsDir)
+	if err = newTestConfig(globalMinioDefaultRegion, obj); err != nil {
+		t.Fatal(err)
+	}
+
+	token, err := newInviteToken("bucket", "readonly", time.Hour)
+	if err != nil {
+		t.Fatal(err)
+	}
+	claims, err := parseInviteToken(token)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if claims.Bucket != "bucket" || claims.Policy != "readonly" || claims.Id == "" {
+		t.Errorf("Unexpected invite claims %v", claims)
+	}
+
+	for _, testCase := range []struct {
+		policy string
+		expiry time.Duration
+	}{
+		{"consoleAdmin", time.Hour},
+		{"readwrite", 0},
+		{"readwrite", 2 * maxInviteExpiry},
+	} {
+		if _, err = newInviteToken("bucket", testCase.policy, testCase.expiry); err == nil {
+			t.Errorf("Expected an error for the invite %v", testCase)
+		}
+	}
+
+	// Login tokens are not invites, and invites are not login tokens.
+	cred := globalServerConfig.GetCredential()
+	loginToken, err := authenticateWeb(cred.AccessKey, cred.SecretKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err = parseInviteToken(loginToken); err != errInvalidInvite {
+		t.Errorf("Expected login tokens to be invalid invites, got %v", err)
+	}
+	if isAuthTokenValid(token) {
+		t.Errorf("Expected invites to be invalid login tokens")
+	}
+}
+
+func TestBucketInvitePolicy(t *testing.T) {
+	testCases := []struct {
+		policy  string
+		args    iampolicy.Args
+		allowed bool
+	}{
+		{"readonly", iampolicy.Args{Action: iampolicy.ListBucketAction, BucketName: "bucket"}, true},
+		{"readonly", iampolicy.Args{Action: iampolicy.GetObjectAction, BucketName: "bucket", ObjectName: "a/b"}, true},
+		{"readonly", iampolicy.Args{Action: iampolicy.PutObjectAction, BucketName: "bucket", ObjectName: "a/b"}, false},
+		{"readonly", iampolicy.Args{Action: iampolicy.GetObjectAction, BucketName: "other", ObjectName: "a/b"}, false},
+		{"writeonly", iampolicy.Args{Action: iampolicy.PutObjectAction, BucketName: "bucket", ObjectName: "a/b"}, true},
+		{"writeonly", iampolicy.Args{Action: iampolicy.GetObjectAction, BucketName: "bucket", ObjectName: "a/b"}, false},
+		{"readwrite", iampolicy.Args{Action: iampolicy.DeleteObjectAction, BucketName: "bucket", ObjectName: "a/b"}, true},
+		{"readwrite", iampolicy.Args{Action: iampolicy.ListAllMyBucketsAction}, false},
+		{"readwrite", iampolicy.Args{Action: iampolicy.PutBucketPolicyAction, BucketName: "bucket"}, false},
+		{"readwrite", iampolicy.Args{Action: iampolicy.DeleteBucketAction, BucketName: "bucket"}, false},
+	}
+	for i, testCase := range testCases {
+		p, err := bucketInvitePolicy("bucket", testCase.policy)
+		if err != nil {
+			t.Fatalf("Test %d: %v", i+1, err)
+		}
+		if allowed := p.IsAllowed(testCase.args); allowed != testCase.allowed {
+			t.Errorf("Test %d: expected allowed %t, got %t", i+1, testCase.allowed, allowed)
+		}
+	}
+}
@@ -0,0 +1,240 @@
+/*
+ * MinIO Cloud Storage, (C) 2020 MinIO, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"path"
+	"time"
+
+	"github.com/minio/minio/cmd/logger"
+)
+
+// Anonymous uploads to a bucket with anonymous upload limits, such as a
+// drop-box bucket, are limited in size and in bytes uploaded per day.
+// They are marked as uploaded anonymously and removed once they expire.
+
+const (
+	// Bucket anonymous upload configuration file.
+	bucketAnonymousUploadConfig = "anonymous-upload.json"
+
+	// Bytes uploaded anonymously to a bucket today.
+	bucketAnonymousUploadUsage = "anonymous-upload-usage.json"
+
+	// Metadata marking objects uploaded anonymously.
+	anonymousUploadKey = ReservedMetadataPrefix + "anonymous-upload"
+
+	// Interval at which expired anonymous uploads are removed.
+	anonymousUploadExpiryInterval = time.Hour
+)
+
+// BucketAnonymousUploadConfig - limits of the anonymous uploads to a
+// bucket, a zero value is no limit.
+type BucketAnonymousUploadConfig struct {
+	// Maximum size of an object uploaded anonymously.
+	MaxObjectSize int64 `json:"maxObjectSize,omitempty"`
+	// Maximum number of bytes uploaded anonymously per day.
+	DailyQuota int64 `json:"dailyQuota,omitempty"`
+	// Number of days after which objects uploaded anonymously are
+	// removed.
+	ExpiryDays int `json:"expiryDays,omitempty"`
+}
+
+func saveBucketAnonymousUploadConfig(ctx context.Context, objAPI ObjectLayer, bucketName string, config BucketAnonymousUploadConfig) error {
+	data, err := json.Marshal(config)
+	if err != nil {
+		return err
+	}
+
+	// Construct path to anonymous-upload.json for the given bucket.
+	configFile := path.Join(bucketConfigPrefix, bucketName, bucketAnonymousUploadConfig)
+	return saveConfig(ctx, objAPI, configFile, data)
+}
+
+// getBucketAnonymousUploadConfig - get bucket anonymous upload config
+// for given bucket name, returns errConfigNotFound if anonymous uploads
+// are not limited.
+func getBucketAnonymousUploadConfig(ctx context.Context, objAPI ObjectLayer, bucketName string) (*BucketAnonymousUploadConfig, error) {
+	// Construct path to anonymous-upload.json for the given bucket.
+	configFile := path.Join(bucketConfigPrefix, bucketName, bucketAnonymousUploadConfig)
+	configData, err := readConfig(ctx, objAPI, configFile)
+	if err != nil {
+		return nil, err
+	}
+
+	var config BucketAnonymousUploadConfig
+	if err = json.Unmarshal(configData, &config); err != nil {
+		return nil, err
+	}
+	return &config, nil
+}
+
+func removeBucketAnonymousUploadConfig(ctx context.Context, objAPI ObjectLayer, bucketName string) error {
+	// Construct path to anonymous-upload.json for the given bucket.
+	configFile := path.Join(bucketConfigPrefix, bucketName, bucketAnonymousUploadConfig)
+
+	if err := objAPI.DeleteObject(ctx, minioMetaBucket, configFile); err != nil {
+		if _, ok := err.(ObjectNotFound); !ok {
+			return err
+		}
+	}
+	return nil
+}
+
+// anonymousUploadUsage - bytes uploaded anonymously to a bucket on
+// day, kept in the bucket metadata so that all servers count against
+// the same quota.
+type anonymousUploadUsage struct {
+	Day   string `json:"day"`
+	Bytes int64  `json:"bytes"`
+}
+
+// updateAnonymousUploadUsage - calls update with the usage of bucket
+// today under a namespace lock, saves the usage if update returns true.
+func updateAnonymousUploadUsage(ctx context.Context, objAPI ObjectLayer, bucket string, update func(usage *anonymousUploadUsage) bool) error {
+	// Construct path to anonymous-upload-usage.json for the given bucket.
+	usageFile := path.Join(bucketConfigPrefix, bucket, bucketAnonymousUploadUsage)
+	usageLock := globalNSMutex.NewNSLock(ctx, minioMetaBucket, usageFile)
+	if err := usageLock.GetLock(globalOperationTimeout); err != nil {
+		return err
+	}
+	defer usageLock.Unlock()
+
+	var usage anonymousUploadUsage
+	data, err := readConfig(ctx, objAPI, usageFile)
+	switch err {
+	case nil:
+		if err = json.Unmarshal(data, &usage); err != nil {
+			return err
+		}
+	case errConfigNotFound:
+	default:
+		return err
+	}
+	if day := UTCNow().Format("2006-01-02"); day != usage.Day {
+		usage = anonymousUploadUsage{Day: day}
+	}
+	if !update(&usage) {
+		return nil
+	}
+
+	if data, err = json.Marshal(usage); err != nil {
+		return err
+	}
+	return saveConfig(ctx, objAPI, usageFile, data)
+}
+
+// checkAnonymousUpload - checks an anonymous upload of size bytes
+// against the anonymous upload limits of bucket, if any, and marks it
+// as uploaded anonymously in metadata. The returned function must be
+// called with the outcome of the upload.
+func checkAnonymousUpload(ctx context.Context, objAPI ObjectLayer, bucket string, size int64, metadata map[string]string) (func(err error), error) {
+	done := func(err error) {}
+
+	config, err := getBucketAnonymousUploadConfig(ctx, objAPI, bucket)
+	if err != nil {
+		if err == errConfigNotFound {
+			return done, nil
+		}
+		return nil, err
+	}
+
+	if config.MaxObjectSize > 0 && size > config.MaxObjectSize {
+		return nil, ObjectTooLarge{Bucket: bucket}
+	}
+	if config.DailyQuota > 0 {
+		var day string
+		reserved := false
+		err = updateAnonymousUploadUsage(ctx, objAPI, bucket, func(usage *anonymousUploadUsage) bool {
+			if usage.Bytes+size > config.DailyQuota {
+				return false
+			}
+			day = usage.Day
+			usage.Bytes += size
+			reserved = true
+			return true
+		})
+		if err != nil {
+			return nil, err
+		}
+		if !reserved {
+			return nil, AnonymousUploadQuotaExceeded{Bucket: bucket}
+		}
+		// Bytes of failed uploads are given back to the quota of the
+		// day they were reserved on.
+		done = func(err error) {
+			if err == nil {
+				return
+			}
+			logger.LogIf(ctx, updateAnonymousUploadUsage(ctx, objAPI, bucket, func(usage *anonymousUploadUsage) bool {
+				if usage.Day != day || usage.Bytes < size {
+					return false
+				}
+				usage.Bytes -= size
+				return true
+			}))
+		}
+	}
+	metadata[anonymousUploadKey] = "true"
+	return done, nil
+}
+
+// expireAnonymousUploads - removes the objects uploaded anonymously to
+// buckets with an anonymous upload expiry once they expire.
+func expireAnonymousUploads(ctx context.Context, objAPI ObjectLayer) error {
+	buckets, err := objAPI.ListBuckets(ctx)
+	if err != nil {
+		return err
+	}
+
+	for _, bucket := range buckets {
+		config, err := getBucketAnonymousUploadConfig(ctx, objAPI, bucket.Name)
+		if err != nil {
+			if err != errConfigNotFound {
+				logger.LogIf(ctx, err)
+			}
+			continue
+		}
+		if config.ExpiryDays <= 0 {
+			continue
+		}
+		expiry := UTCNow().AddDate(0, 0, -config.ExpiryDays)
+
+		marker := ""
+		for {
+			result, err := objAPI.ListObjects(ctx, bucket.Name, "", marker, "", maxObjectList)
+			if err != nil {
+				return err
+			}
+			for _, obj := range result.Objects {
+				if obj.ModTime.After(expiry) || obj.UserDefined[anonymousUploadKey] == "" {
+					continue
+				}
+				err = objAPI.DeleteObject(ctx, bucket.Name, obj.Name)
+				if err != nil && !isErrObjectNotFound(err) {
+					return err
+				}
+			}
+			if !result.IsTruncated {
+				break
+			}
+			marker = result.NextMarker
+		}
+	}
+	return nil
+}
@@ -0,0 +1,102 @@
+/*
+ * MinIO Cloud Storage, (C) 2020 MinIO, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"os"
+	"path"
+	"testing"
+)
+
+func TestCheckAnonymousUpload(t *testing.T) {
+	obj, fsDir, err := prepareFS()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(fsDir)
+
+	ctx := context.Background()
+	if err = obj.MakeBucketWithLocation(ctx, "dropbox", ""); err != nil {
+		t.Fatal(err)
+	}
+
+	// Anonymous uploads are not limited by default.
+	metadata := make(map[string]string)
+	done, err := checkAnonymousUpload(ctx, obj, "dropbox", 1<<30, metadata)
+	if err != nil {
+		t.Fatal(err)
+	}
+	done(nil)
+	if metadata[anonymousUploadKey] != "" {
+		t.Fatal("Expected uploads to buckets without limits not to be marked")
+	}
+
+	config := BucketAnonymousUploadConfig{MaxObjectSize: 100, DailyQuota: 150, ExpiryDays: 1}
+	if err = saveBucketAnonymousUploadConfig(ctx, obj, "dropbox", config); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err = checkAnonymousUpload(ctx, obj, "dropbox", 101, metadata); err != (ObjectTooLarge{Bucket: "dropbox"}) {
+		t.Fatalf("Expected ObjectTooLarge, got %v", err)
+	}
+
+	done, err = checkAnonymousUpload(ctx, obj, "dropbox", 100, metadata)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if metadata[anonymousUploadKey] != "true" {
+		t.Fatal("Expected the upload to be marked as anonymous")
+	}
+	// Failed uploads do not count against the quota.
+	done(errors.New("upload failed"))
+
+	done, err = checkAnonymousUpload(ctx, obj, "dropbox", 100, metadata)
+	if err != nil {
+		t.Fatal(err)
+	}
+	done(nil)
+
+	if _, err = checkAnonymousUpload(ctx, obj, "dropbox", 51, metadata); err != (AnonymousUploadQuotaExceeded{Bucket: "dropbox"}) {
+		t.Fatalf("Expected AnonymousUploadQuotaExceeded, got %v", err)
+	}
+	if _, err = checkAnonymousUpload(ctx, obj, "dropbox", 50, metadata); err != nil {
+		t.Fatal(err)
+	}
+
+	// The usage is kept in the bucket metadata, shared by all servers.
+	data, err := readConfig(ctx, obj, path.Join(bucketConfigPrefix, "dropbox", bucketAnonymousUploadUsage))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var usage anonymousUploadUsage
+	if err = json.Unmarshal(data, &usage); err != nil {
+		t.Fatal(err)
+	}
+	if usage.Day != UTCNow().Format("2006-01-02") || usage.Bytes != 150 {
+		t.Fatalf("Unexpected usage %v", usage)
+	}
+
+	if err = removeBucketAnonymousUploadConfig(ctx, obj, "dropbox"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err = getBucketAnonymousUploadConfig(ctx, obj, "dropbox"); err != errConfigNotFound {
+		t.Fatalf("Expected errConfigNotFound, got %v", err)
+	}
+}
@@ -0,0 +1,156 @@
+/*
+ * MinIO Cloud Storage, (C) 2020 MinIO, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"mime"
+	"path"
+	"strings"
+
+	"github.com/minio/minio/pkg/mimedb"
+)
+
+// Uploads to a bucket with a content type configuration are restricted
+// to the content types and file extensions it allows.
+
+const (
+	// Bucket content type configuration file.
+	bucketContentTypeConfig = "content-type.json"
+)
+
+// BucketContentTypeConfig - content types and file extensions of the
+// objects which may be uploaded to a bucket, an upload is allowed if it
+// matches either of them.
+type BucketContentTypeConfig struct {
+	// Allowed content types, e.g. "image/png", or "image/*" for all
+	// subtypes of a type.
+	ContentTypes []string `json:"contentTypes,omitempty"`
+	// Allowed file extensions of object names, e.g. ".png".
+	Extensions []string `json:"extensions,omitempty"`
+}
+
+// IsEmpty - returns true if the configuration does not restrict
+// uploads.
+func (config BucketContentTypeConfig) IsEmpty() bool {
+	return len(config.ContentTypes) == 0 && len(config.Extensions) == 0
+}
+
+// Validate - returns an error if an allowed content type or extension
+// is malformed.
+func (config BucketContentTypeConfig) Validate() error {
+	for _, contentType := range config.ContentTypes {
+		parts := strings.Split(contentType, SlashSeparator)
+		if len(parts) != 2 || parts[0] == "" || parts[0] == "*" || parts[1] == "" {
+			return errInvalidArgument
+		}
+	}
+	for _, ext := range config.Extensions {
+		if strings.TrimPrefix(ext, ".") == "" || strings.Contains(ext, SlashSeparator) {
+			return errInvalidArgument
+		}
+	}
+	return nil
+}
+
+// IsAllowed - returns true if an object of contentType may be uploaded
+// as object, content types and extensions are compared case-insensitively.
+func (config BucketContentTypeConfig) IsAllowed(object, contentType string) bool {
+	if mediaType, _, err := mime.ParseMediaType(contentType); err == nil {
+		contentType = mediaType
+	}
+	for _, allowed := range config.ContentTypes {
+		allowed = strings.ToLower(allowed)
+		if allowed == contentType {
+			return true
+		}
+		if strings.HasSuffix(allowed, "/*") && strings.HasPrefix(contentType, strings.TrimSuffix(allowed, "*")) {
+			return true
+		}
+	}
+	ext := strings.ToLower(path.Ext(object))
+	for _, allowed := range config.Extensions {
+		if ext != "" && ext == "."+strings.ToLower(strings.TrimPrefix(allowed, ".")) {
+			return true
+		}
+	}
+	return false
+}
+
+func saveBucketContentTypeConfig(ctx context.Context, objAPI ObjectLayer, bucketName string, config BucketContentTypeConfig) error {
+	data, err := json.Marshal(config)
+	if err != nil {
+		return err
+	}
+
+	// Construct path to content-type.json for the given bucket.
+	configFile := path.Join(bucketConfigPrefix, bucketName, bucketContentTypeConfig)
+	return saveConfig(ctx, objAPI, configFile, data)
+}
+
+// getBucketContentTypeConfig - get bucket content type config for given
+// bucket name, returns errConfigNotFound if uploads are not restricted.
+func getBucketContentTypeConfig(ctx context.Context, objAPI ObjectLayer, bucketName string) (*BucketContentTypeConfig, error) {
+	// Construct path to content-type.json for the given bucket.
+	configFile := path.Join(bucketConfigPrefix, bucketName, bucketContentTypeConfig)
+	configData, err := readConfig(ctx, objAPI, configFile)
+	if err != nil {
+		return nil, err
+	}
+
+	var config BucketContentTypeConfig
+	if err = json.Unmarshal(configData, &config); err != nil {
+		return nil, err
+	}
+	return &config, nil
+}
+
+func removeBucketContentTypeConfig(ctx context.Context, objAPI ObjectLayer, bucketName string) error {
+	// Construct path to content-type.json for the given bucket.
+	configFile := path.Join(bucketConfigPrefix, bucketName, bucketContentTypeConfig)
+
+	if err := objAPI.DeleteObject(ctx, minioMetaBucket, configFile); err != nil {
+		if _, ok := err.(ObjectNotFound); !ok {
+			return err
+		}
+	}
+	return nil
+}
+
+// checkUploadContentType - checks an upload of object with the content
+// type in metadata against the content type configuration of bucket, if
+// any. Objects without a content type are checked against the content
+// type the object layer will set from their extension.
+func checkUploadContentType(ctx context.Context, objAPI ObjectLayer, bucket, object string, metadata map[string]string) error {
+	config, err := getBucketContentTypeConfig(ctx, objAPI, bucket)
+	if err != nil {
+		if err == errConfigNotFound {
+			return nil
+		}
+		return err
+	}
+
+	contentType := strings.ToLower(metadata["content-type"])
+	if contentType == "" {
+		contentType = mimedb.TypeByExtension(path.Ext(object))
+	}
+	if !config.IsAllowed(object, contentType) {
+		return ContentTypeNotAllowed{Bucket: bucket, Object: object}
+	}
+	return nil
+}
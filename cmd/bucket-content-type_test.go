@@ -0,0 +1,110 @@
+/*
+ * MinIO Cloud Storage, (C) 2020 MinIO, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"context"
+	"os"
+	"testing"
+)
+
+func TestBucketContentTypeConfigIsAllowed(t *testing.T) {
+	config := BucketContentTypeConfig{
+		ContentTypes: []string{"image/*", "Application/PDF"},
+		Extensions:   []string{".TXT", "md"},
+	}
+	testCases := []struct {
+		object      string
+		contentType string
+		allowed     bool
+	}{
+		{"photo.jpg", "image/jpeg", true},
+		{"photo", "IMAGE/PNG", true},
+		{"doc.pdf", "application/pdf; charset=binary", true},
+		{"notes.txt", "application/octet-stream", true},
+		{"README.MD", "application/octet-stream", true},
+		{"video.mp4", "video/mp4", false},
+		{"imagefile", "imagery/png", false},
+		{"txt", "application/octet-stream", false},
+	}
+	for i, testCase := range testCases {
+		if allowed := config.IsAllowed(testCase.object, testCase.contentType); allowed != testCase.allowed {
+			t.Errorf("Test %d: expected %v, got %v", i+1, testCase.allowed, allowed)
+		}
+	}
+}
+
+func TestBucketContentTypeConfigValidate(t *testing.T) {
+	testCases := []struct {
+		config BucketContentTypeConfig
+		valid  bool
+	}{
+		{BucketContentTypeConfig{}, true},
+		{BucketContentTypeConfig{ContentTypes: []string{"image/*", "text/plain"}, Extensions: []string{".png"}}, true},
+		{BucketContentTypeConfig{ContentTypes: []string{"image"}}, false},
+		{BucketContentTypeConfig{ContentTypes: []string{"*/*"}}, false},
+		{BucketContentTypeConfig{ContentTypes: []string{"image/"}}, false},
+		{BucketContentTypeConfig{Extensions: []string{"."}}, false},
+		{BucketContentTypeConfig{Extensions: []string{"a/.png"}}, false},
+	}
+	for i, testCase := range testCases {
+		if err := testCase.config.Validate(); (err == nil) != testCase.valid {
+			t.Errorf("Test %d: expected valid %v, got %v", i+1, testCase.valid, err)
+		}
+	}
+}
+
+func TestCheckUploadContentType(t *testing.T) {
+	obj, fsDir, err := prepareFS()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(fsDir)
+
+	ctx := context.Background()
+	if err = obj.MakeBucketWithLocation(ctx, "images", ""); err != nil {
+		t.Fatal(err)
+	}
+
+	// Uploads are not restricted by default.
+	if err = checkUploadContentType(ctx, obj, "images", "video.mp4", map[string]string{"content-type": "video/mp4"}); err != nil {
+		t.Fatal(err)
+	}
+
+	config := BucketContentTypeConfig{ContentTypes: []string{"image/*"}}
+	if err = saveBucketContentTypeConfig(ctx, obj, "images", config); err != nil {
+		t.Fatal(err)
+	}
+
+	if err = checkUploadContentType(ctx, obj, "images", "photo.png", map[string]string{"content-type": "image/png"}); err != nil {
+		t.Fatal(err)
+	}
+	// Without a content type, the content type of the extension is checked.
+	if err = checkUploadContentType(ctx, obj, "images", "photo.png", map[string]string{}); err != nil {
+		t.Fatal(err)
+	}
+	if err = checkUploadContentType(ctx, obj, "images", "video.mp4", map[string]string{"content-type": "video/mp4"}); err != (ContentTypeNotAllowed{Bucket: "images", Object: "video.mp4"}) {
+		t.Fatalf("Expected ContentTypeNotAllowed, got %v", err)
+	}
+
+	if err = removeBucketContentTypeConfig(ctx, obj, "images"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err = getBucketContentTypeConfig(ctx, obj, "images"); err != errConfigNotFound {
+		t.Fatalf("Expected errConfigNotFound, got %v", err)
+	}
+}
@@ -0,0 +1,196 @@
+/*
+ * MinIO Cloud Storage, (C) 2019 MinIO, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"encoding/xml"
+	"path"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/minio/minio-go/pkg/set"
+	"github.com/minio/minio/cmd/logger"
+	"github.com/minio/minio/pkg/cors"
+)
+
+const (
+	// Bucket CORS configuration file.
+	bucketCORSConfig = "cors.xml"
+)
+
+func saveBucketCORSConfig(ctx context.Context, objAPI ObjectLayer, bucketName string, config *cors.Config) error {
+	data, err := xml.Marshal(config)
+	if err != nil {
+		return err
+	}
+
+	// Construct path to cors.xml for the given bucket.
+	configFile := path.Join(bucketConfigPrefix, bucketName, bucketCORSConfig)
+	return saveConfig(ctx, objAPI, configFile, data)
+}
+
+// getBucketCORSConfig - get bucket CORS config for given bucket name.
+func getBucketCORSConfig(objAPI ObjectLayer, bucketName string) (*cors.Config, error) {
+	// Construct path to cors.xml for the given bucket.
+	configFile := path.Join(bucketConfigPrefix, bucketName, bucketCORSConfig)
+	configData, err := readConfig(context.Background(), objAPI, configFile)
+	if err != nil {
+		return nil, err
+	}
+
+	return cors.ParseConfig(bytes.NewReader(configData))
+}
+
+func removeBucketCORSConfig(ctx context.Context, objAPI ObjectLayer, bucketName string) error {
+	// Construct path to cors.xml for the given bucket.
+	configFile := path.Join(bucketConfigPrefix, bucketName, bucketCORSConfig)
+
+	if err := objAPI.DeleteObject(ctx, minioMetaBucket, configFile); err != nil {
+		if _, ok := err.(ObjectNotFound); !ok {
+			return err
+		}
+	}
+	return nil
+}
+
+// BucketCORSSys - Bucket CORS subsystem, holds the CORS
+// configuration of the buckets enforced by the CORS handler.
+type BucketCORSSys struct {
+	sync.RWMutex
+	bucketCORSMap map[string]cors.Config
+}
+
+// Set - sets CORS config to given bucket name.
+func (sys *BucketCORSSys) Set(bucketName string, config cors.Config) {
+	sys.Lock()
+	defer sys.Unlock()
+
+	sys.bucketCORSMap[bucketName] = config
+}
+
+// Get - gets CORS config associated to a given bucket name.
+func (sys *BucketCORSSys) Get(bucketName string) (config cors.Config, ok bool) {
+	sys.RLock()
+	defer sys.RUnlock()
+
+	c, ok := sys.bucketCORSMap[bucketName]
+	return c, ok
+}
+
+// Remove - removes CORS config for given bucket name.
+func (sys *BucketCORSSys) Remove(bucketName string) {
+	sys.Lock()
+	defer sys.Unlock()
+
+	delete(sys.bucketCORSMap, bucketName)
+}
+
+// NewBucketCORSSys - creates new bucket CORS system.
+func NewBucketCORSSys() *BucketCORSSys {
+	return &BucketCORSSys{
+		bucketCORSMap: make(map[string]cors.Config),
+	}
+}
+
+// Init - initializes bucket CORS system from cors.xml of all buckets.
+func (sys *BucketCORSSys) Init(objAPI ObjectLayer) error {
+	if objAPI == nil {
+		return errServerNotInitialized
+	}
+
+	defer func() {
+		// Refresh BucketCORSSys in background.
+		go func() {
+			ticker := time.NewTicker(globalRefreshBucketCORSInterval)
+			defer ticker.Stop()
+			for {
+				select {
+				case <-GlobalServiceDoneCh:
+					return
+				case <-ticker.C:
+					sys.refresh(objAPI)
+				}
+			}
+		}()
+	}()
+
+	doneCh := make(chan struct{})
+	defer close(doneCh)
+
+	// Initializing bucket CORS needs a retry mechanism for
+	// the following reasons:
+	//  - Read quorum is lost just after the initialization
+	//    of the object layer.
+	for range newRetryTimerSimple(doneCh) {
+		// Load BucketCORSSys once during boot.
+		if err := sys.refresh(objAPI); err != nil {
+			if err == errDiskNotFound ||
+				strings.Contains(err.Error(), InsufficientReadQuorum{}.Error()) ||
+				strings.Contains(err.Error(), InsufficientWriteQuorum{}.Error()) {
+				logger.Info("Waiting for bucket CORS subsystem to be initialized..")
+				continue
+			}
+			return err
+		}
+		break
+	}
+	return nil
+}
+
+// Refresh BucketCORSSys.
+func (sys *BucketCORSSys) refresh(objAPI ObjectLayer) error {
+	buckets, err := objAPI.ListBuckets(context.Background())
+	if err != nil {
+		logger.LogIf(context.Background(), err)
+		return err
+	}
+	sys.removeDeletedBuckets(buckets)
+	for _, bucket := range buckets {
+		config, err := getBucketCORSConfig(objAPI, bucket.Name)
+		if err != nil {
+			if err == errConfigNotFound {
+				sys.Remove(bucket.Name)
+			}
+			continue
+		}
+
+		sys.Set(bucket.Name, *config)
+	}
+
+	return nil
+}
+
+// removeDeletedBuckets - to handle a corner case where we have cached the CORS config
+// for a deleted bucket. i.e if we miss a delete-bucket notification we should delete the
+// corresponding bucket CORS config during sys.refresh()
+func (sys *BucketCORSSys) removeDeletedBuckets(bucketInfos []BucketInfo) {
+	buckets := set.NewStringSet()
+	for _, info := range bucketInfos {
+		buckets.Add(info.Name)
+	}
+	sys.Lock()
+	defer sys.Unlock()
+
+	for bucket := range sys.bucketCORSMap {
+		if !buckets.Contains(bucket) {
+			delete(sys.bucketCORSMap, bucket)
+		}
+	}
+}
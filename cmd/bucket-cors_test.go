@@ -0,0 +1,183 @@
+/*
+ * MinIO Cloud Storage, (C) 2019 MinIO, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/minio/minio/pkg/cors"
+)
+
+// Tests that bucket CORS configuration is saved, loaded by refresh and removed.
+func TestBucketCORSSysRefresh(t *testing.T) {
+	objLayer, fsDir, err := prepareFS()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer removeRoots([]string{fsDir})
+
+	if err = objLayer.MakeBucketWithLocation(context.Background(), "bucket", ""); err != nil {
+		t.Fatal(err)
+	}
+
+	config := &cors.Config{
+		Rules: []cors.Rule{{AllowedOrigins: []string{"*"}, AllowedMethods: []string{http.MethodGet}}},
+	}
+	if err = saveBucketCORSConfig(context.Background(), objLayer, "bucket", config); err != nil {
+		t.Fatal(err)
+	}
+
+	sys := NewBucketCORSSys()
+	if err = sys.refresh(objLayer); err != nil {
+		t.Fatal(err)
+	}
+	if c, ok := sys.Get("bucket"); !ok || len(c.Rules) != 1 || c.Rules[0].AllowedOrigins[0] != "*" {
+		t.Fatalf("Expected the saved CORS config, got %v (found %t)", c, ok)
+	}
+
+	if err = removeBucketCORSConfig(context.Background(), objLayer, "bucket"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err = getBucketCORSConfig(objLayer, "bucket"); err != errConfigNotFound {
+		t.Fatalf("Expected errConfigNotFound, got %v", err)
+	}
+	if err = sys.refresh(objLayer); err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := sys.Get("bucket"); ok {
+		t.Fatal("Expected the removed CORS config to be dropped")
+	}
+}
+
+// Tests that cross-origin requests to buckets are checked against their CORS configuration.
+func TestCorsHandler(t *testing.T) {
+	prevBucketCORSSys := globalBucketCORSSys
+	defer func() { globalBucketCORSSys = prevBucketCORSSys }()
+
+	globalBucketCORSSys = NewBucketCORSSys()
+	globalBucketCORSSys.Set("bucket", cors.Config{
+		Rules: []cors.Rule{
+			{
+				AllowedOrigins: []string{"https://*.example.com"},
+				AllowedMethods: []string{http.MethodPut},
+				AllowedHeaders: []string{"content-type"},
+				ExposeHeaders:  []string{"ETag"},
+				MaxAgeSeconds:  3000,
+			},
+			{
+				AllowedOrigins: []string{"*"},
+				AllowedMethods: []string{http.MethodGet},
+			},
+		},
+	})
+
+	handler := setCorsHandler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNoContent)
+	}))
+
+	testCases := []struct {
+		method         string
+		path           string
+		headers        map[string]string
+		expectedStatus int
+		allowOrigin    string
+		allowMethods   string
+		exposeHeaders  string
+	}{
+		// Preflight allowed by the first rule.
+		{
+			method: http.MethodOptions,
+			path:   "/bucket/object",
+			headers: map[string]string{
+				"Origin":                         "https://app.example.com",
+				"Access-Control-Request-Method":  http.MethodPut,
+				"Access-Control-Request-Headers": "Content-Type",
+			},
+			expectedStatus: http.StatusOK,
+			allowOrigin:    "https://app.example.com",
+			allowMethods:   http.MethodPut,
+			exposeHeaders:  "ETag",
+		},
+		// Preflight with a header which is not allowed.
+		{
+			method: http.MethodOptions,
+			path:   "/bucket/object",
+			headers: map[string]string{
+				"Origin":                         "https://app.example.com",
+				"Access-Control-Request-Method":  http.MethodPut,
+				"Access-Control-Request-Headers": "x-amz-meta-owner",
+			},
+			expectedStatus: http.StatusForbidden,
+		},
+		// Preflight to a bucket without CORS configuration.
+		{
+			method: http.MethodOptions,
+			path:   "/other/object",
+			headers: map[string]string{
+				"Origin":                        "https://app.example.com",
+				"Access-Control-Request-Method": http.MethodGet,
+			},
+			expectedStatus: http.StatusForbidden,
+		},
+		// Actual request allowed by the second rule.
+		{
+			method:         http.MethodGet,
+			path:           "/bucket/object",
+			headers:        map[string]string{"Origin": "https://app.example.org"},
+			expectedStatus: http.StatusNoContent,
+			allowOrigin:    "*",
+		},
+		// Actual request which is not allowed is served without CORS headers.
+		{
+			method:         http.MethodPut,
+			path:           "/bucket/object",
+			headers:        map[string]string{"Origin": "https://app.example.org"},
+			expectedStatus: http.StatusNoContent,
+		},
+	}
+	for i, testCase := range testCases {
+		req := httptest.NewRequest(testCase.method, "http://127.0.0.1:9000"+testCase.path, nil)
+		for k, v := range testCase.headers {
+			req.Header.Set(k, v)
+		}
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, req)
+		if rec.Code != testCase.expectedStatus {
+			t.Errorf("Test %d: expected status %d, got %d", i+1, testCase.expectedStatus, rec.Code)
+		}
+		if got := rec.Header().Get("Access-Control-Allow-Origin"); got != testCase.allowOrigin {
+			t.Errorf("Test %d: expected allowed origin %q, got %q", i+1, testCase.allowOrigin, got)
+		}
+		if got := rec.Header().Get("Access-Control-Allow-Methods"); got != testCase.allowMethods {
+			t.Errorf("Test %d: expected allowed methods %q, got %q", i+1, testCase.allowMethods, got)
+		}
+		if got := rec.Header().Get("Access-Control-Expose-Headers"); got != testCase.exposeHeaders {
+			t.Errorf("Test %d: expected exposed headers %q, got %q", i+1, testCase.exposeHeaders, got)
+		}
+	}
+	// Requests outside of buckets allow all origins.
+	req := httptest.NewRequest(http.MethodGet, "http://127.0.0.1:9000/", nil)
+	req.Header.Set("Origin", "https://app.example.org")
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+	if got := rec.Header().Get("Access-Control-Allow-Origin"); got != "*" {
+		t.Errorf("Expected all origins to be allowed outside of buckets, got %q", got)
+	}
+}
@@ -0,0 +1,62 @@
+/*
+ * MinIO Cloud Storage, (C) 2020 MinIO, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"context"
+
+	"github.com/minio/minio/cmd/crypto"
+	"github.com/minio/minio/pkg/madmin"
+)
+
+// getBucketEncryptionReport - lists the objects under a prefix of a
+// bucket and sums them up by server-side encryption type. SSE-KMS
+// objects are counted as such only, not as SSE-S3 objects.
+func getBucketEncryptionReport(ctx context.Context, objAPI ObjectLayer, bucket, prefix string) (report madmin.BucketEncryptionReport, err error) {
+	if _, err = objAPI.GetBucketInfo(ctx, bucket); err != nil {
+		return report, err
+	}
+
+	report.Bucket = bucket
+	report.Prefix = prefix
+	marker := ""
+	for {
+		result, err := objAPI.ListObjects(ctx, bucket, prefix, marker, "", maxObjectList)
+		if err != nil {
+			return report, err
+		}
+		for _, obj := range result.Objects {
+			var stats *madmin.EncryptionStats
+			switch {
+			case crypto.S3KMS.IsEncrypted(obj.UserDefined):
+				stats = &report.SSEKMS
+			case crypto.S3.IsEncrypted(obj.UserDefined):
+				stats = &report.SSES3
+			case crypto.SSEC.IsEncrypted(obj.UserDefined):
+				stats = &report.SSEC
+			default:
+				stats = &report.Plaintext
+			}
+			stats.Objects++
+			stats.Size += obj.Size
+		}
+		if !result.IsTruncated {
+			return report, nil
+		}
+		marker = result.NextMarker
+	}
+}
@@ -0,0 +1,71 @@
+/*
+ * MinIO Cloud Storage, (C) 2020 MinIO, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"testing"
+
+	"github.com/minio/minio/cmd/crypto"
+	"github.com/minio/minio/pkg/madmin"
+)
+
+// Tests that the encryption report counts SSE-KMS objects apart from
+// SSE-S3 objects.
+func TestBucketEncryptionReport(t *testing.T) {
+	obj, fsDir, err := prepareFS()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(fsDir)
+
+	ctx := context.Background()
+	if err = obj.MakeBucketWithLocation(ctx, "bucket", ""); err != nil {
+		t.Fatal(err)
+	}
+
+	data := []byte("hello")
+	for object, meta := range map[string]map[string]string{
+		"plain":  nil,
+		"sse-s3": {crypto.S3SealedKey: "key"},
+		"sse-kms": {
+			crypto.S3KMSKeyID:     "my-key",
+			crypto.S3KMSSealedKey: "key",
+			crypto.S3KMSRequested: "",
+		},
+		"sse-c": {crypto.SSECSealedKey: "key"},
+	} {
+		if _, err = obj.PutObject(ctx, "bucket", object, mustGetPutObjReader(t, bytes.NewReader(data), int64(len(data)), "", ""), ObjectOptions{UserDefined: meta}); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	report, err := getBucketEncryptionReport(ctx, obj, "bucket", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	stats := madmin.EncryptionStats{Objects: 1, Size: int64(len(data))}
+	if report.Bucket != "bucket" || report.Plaintext != stats || report.SSES3 != stats || report.SSEC != stats || report.SSEKMS != stats {
+		t.Fatalf("Unexpected report %v", report)
+	}
+
+	if _, err = getBucketEncryptionReport(ctx, obj, "missing", ""); err == nil {
+		t.Fatal("Expected a report of a missing bucket to fail")
+	}
+}
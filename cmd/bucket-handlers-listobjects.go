@@ -49,6 +49,24 @@ func validateListObjectsArgs(prefix, marker, delimiter, encodingType string, max
 	return ErrNone
 }
 
+// isFastListRequest - returns true if the listing is requested in fast
+// mode, meant for bulk listings such as those of Hadoop S3A. The ETags
+// of encrypted objects are then not decrypted, which requires the key
+// of each object to be unsealed, and are returned as stored instead.
+func isFastListRequest(r *http.Request) bool {
+	return r.URL.Query().Get("minio-fast-list") == "true"
+}
+
+// getFastListETag - returns the ETag of an encrypted object listed in
+// fast mode, the stored ETag of a single part object is shortened to
+// the length of a content MD5 as done for SSE-C objects.
+func getFastListETag(objInfo ObjectInfo) string {
+	if len(objInfo.ETag) <= 32 || crypto.IsMultiPart(objInfo.UserDefined) {
+		return objInfo.ETag
+	}
+	return objInfo.ETag[len(objInfo.ETag)-32:]
+}
+
 // ListObjectsV2Handler - GET Bucket (List Objects) Version 2.
 // --------------------------
 // This implementation of the GET operation returns some or all (up to 1000)
@@ -97,12 +115,14 @@ func (api objectAPIHandlers) ListObjectsV2Handler(w http.ResponseWriter, r *http
 	// Inititate a list objects operation based on the input params.
 	// On success would return back ListObjectsInfo object to be
 	// marshaled into S3 compatible XML header.
-	listObjectsV2Info, err := listObjectsV2(ctx, bucket, prefix, token, delimiter, maxKeys, fetchOwner, startAfter)
+	listObjectsV2Info, err := listObjectsV2(ctx, bucket, globalBucketKeyNormalizationSys.Normalize(bucket, prefix), token, delimiter, maxKeys, fetchOwner,
+		globalBucketKeyNormalizationSys.Normalize(bucket, startAfter))
 	if err != nil {
 		writeErrorResponse(ctx, w, toAPIError(ctx, err), r.URL, guessIsBrowserReq(r))
 		return
 	}
 
+	fastList := isFastListRequest(r)
 	for i := range listObjectsV2Info.Objects {
 		var actualSize int64
 		if listObjectsV2Info.Objects[i].IsCompressed() {
@@ -115,7 +135,11 @@ func (api objectAPIHandlers) ListObjectsV2Handler(w http.ResponseWriter, r *http
 			// Set the info.Size to the actualSize.
 			listObjectsV2Info.Objects[i].Size = actualSize
 		} else if crypto.IsEncrypted(listObjectsV2Info.Objects[i].UserDefined) {
-			listObjectsV2Info.Objects[i].ETag = getDecryptedETag(r.Header, listObjectsV2Info.Objects[i], false)
+			if fastList {
+				listObjectsV2Info.Objects[i].ETag = getFastListETag(listObjectsV2Info.Objects[i])
+			} else {
+				listObjectsV2Info.Objects[i].ETag = getDecryptedETag(r.Header, listObjectsV2Info.Objects[i], false)
+			}
 			listObjectsV2Info.Objects[i].Size, err = listObjectsV2Info.Objects[i].DecryptedSize()
 			if err != nil {
 				writeErrorResponse(ctx, w, toAPIError(ctx, err), r.URL, guessIsBrowserReq(r))
@@ -174,12 +198,14 @@ func (api objectAPIHandlers) ListObjectsV1Handler(w http.ResponseWriter, r *http
 	// Inititate a list objects operation based on the input params.
 	// On success would return back ListObjectsInfo object to be
 	// marshaled into S3 compatible XML header.
-	listObjectsInfo, err := listObjects(ctx, bucket, prefix, marker, delimiter, maxKeys)
+	listObjectsInfo, err := listObjects(ctx, bucket, globalBucketKeyNormalizationSys.Normalize(bucket, prefix),
+		globalBucketKeyNormalizationSys.Normalize(bucket, marker), delimiter, maxKeys)
 	if err != nil {
 		writeErrorResponse(ctx, w, toAPIError(ctx, err), r.URL, guessIsBrowserReq(r))
 		return
 	}
 
+	fastList := isFastListRequest(r)
 	for i := range listObjectsInfo.Objects {
 		var actualSize int64
 		if listObjectsInfo.Objects[i].IsCompressed() {
@@ -192,7 +218,11 @@ func (api objectAPIHandlers) ListObjectsV1Handler(w http.ResponseWriter, r *http
 			// Set the info.Size to the actualSize.
 			listObjectsInfo.Objects[i].Size = actualSize
 		} else if crypto.IsEncrypted(listObjectsInfo.Objects[i].UserDefined) {
-			listObjectsInfo.Objects[i].ETag = getDecryptedETag(r.Header, listObjectsInfo.Objects[i], false)
+			if fastList {
+				listObjectsInfo.Objects[i].ETag = getFastListETag(listObjectsInfo.Objects[i])
+			} else {
+				listObjectsInfo.Objects[i].ETag = getDecryptedETag(r.Header, listObjectsInfo.Objects[i], false)
+			}
 			listObjectsInfo.Objects[i].Size, err = listObjectsInfo.Objects[i].DecryptedSize()
 			if err != nil {
 				writeErrorResponse(ctx, w, toAPIError(ctx, err), r.URL, guessIsBrowserReq(r))
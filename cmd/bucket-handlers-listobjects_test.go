@@ -0,0 +1,61 @@
+/*
+ * MinIO Cloud Storage, (C) 2020 MinIO, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/minio/minio/cmd/crypto"
+)
+
+func TestFastListRequest(t *testing.T) {
+	testCases := []struct {
+		url      string
+		fastList bool
+	}{
+		{"/bucket?list-type=2", false},
+		{"/bucket?list-type=2&minio-fast-list=true", true},
+		{"/bucket?minio-fast-list=false", false},
+	}
+	for i, testCase := range testCases {
+		r, err := http.NewRequest(http.MethodGet, testCase.url, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if fastList := isFastListRequest(r); fastList != testCase.fastList {
+			t.Errorf("Test %d: expected %v, got %v", i+1, testCase.fastList, fastList)
+		}
+	}
+}
+
+func TestGetFastListETag(t *testing.T) {
+	sealedETag := "20000f00f27834c9a2654927546df57f9e998187496394d4ee80f3d9978f85f3c7d81f72600cdbe03d80dc5a13d69354"
+	testCases := []struct {
+		objInfo ObjectInfo
+		etag    string
+	}{
+		{ObjectInfo{ETag: "9e998187496394d4ee80f3d9978f85f3"}, "9e998187496394d4ee80f3d9978f85f3"},
+		{ObjectInfo{ETag: sealedETag}, "c7d81f72600cdbe03d80dc5a13d69354"},
+		{ObjectInfo{ETag: "f0a9d1b1c6f2e0a3b5d4c7e8f9a0b1c2-3", UserDefined: map[string]string{crypto.SSEMultipart: ""}}, "f0a9d1b1c6f2e0a3b5d4c7e8f9a0b1c2-3"},
+	}
+	for i, testCase := range testCases {
+		if etag := getFastListETag(testCase.objInfo); etag != testCase.etag {
+			t.Errorf("Test %d: expected %s, got %s", i+1, testCase.etag, etag)
+		}
+	}
+}
@@ -393,7 +393,7 @@ func (api objectAPIHandlers) DeleteMultipleObjectsHandler(w http.ResponseWriter,
 			continue
 		}
 
-		objectsToDelete = append(objectsToDelete, delObj{index, object.ObjectName})
+		objectsToDelete = append(objectsToDelete, delObj{index, globalBucketKeyNormalizationSys.Normalize(bucket, object.ObjectName)})
 	}
 
 	toNames := func(input []delObj) (output []string) {
@@ -625,7 +625,7 @@ func (api objectAPIHandlers) PostPolicyBucketHandler(w http.ResponseWriter, r *h
 		// by the filename attribute passed in multipart
 		formValues.Set("Key", strings.Replace(formValues.Get("Key"), "${filename}", fileName, -1))
 	}
-	object := formValues.Get("Key")
+	object := globalBucketKeyNormalizationSys.Normalize(bucket, formValues.Get("Key"))
 
 	successRedirect := formValues.Get("success_action_redirect")
 	successStatus := formValues.Get("success_action_status")
@@ -859,6 +859,16 @@ func (api objectAPIHandlers) DeleteBucketHandler(w http.ResponseWriter, r *http.
 	globalNotificationSys.DeleteBucket(ctx, bucket)
 	globalLifecycleSys.Remove(bucket)
 	globalNotificationSys.RemoveBucketLifecycle(ctx, bucket)
+	globalBucketLoggingSys.Remove(bucket)
+	globalNotificationSys.RemoveBucketLogging(ctx, bucket)
+	logger.LogIf(ctx, removeBucketCORSConfig(ctx, objectAPI, bucket))
+	globalBucketCORSSys.Remove(bucket)
+	globalNotificationSys.RemoveBucketCORS(ctx, bucket)
+	logger.LogIf(ctx, removeBucketWebsiteConfig(ctx, objectAPI, bucket))
+	globalBucketWebsiteSys.Remove(bucket)
+	globalNotificationSys.RemoveBucketWebsite(ctx, bucket)
+	logger.LogIf(ctx, removeBucketSnapshotConfig(ctx, objectAPI, bucket))
+	globalBucketSnapshotSys.Remove(bucket)
 
 	// Write success response.
 	writeSuccessNoContent(w)
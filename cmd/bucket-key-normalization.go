@@ -0,0 +1,176 @@
+/*
+ * MinIO Cloud Storage, (C) 2020 MinIO, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"path"
+	"sync"
+
+	"github.com/gorilla/mux"
+	"github.com/minio/minio/pkg/madmin"
+	"golang.org/x/text/unicode/norm"
+)
+
+const (
+	// Bucket key normalization config file.
+	bucketKeyNormalizationConfigFile = "bucket-key-normalization.json"
+)
+
+// BucketKeyNormalizationSys - holds the Unicode normalization forms of
+// the buckets normalizing their object keys. Keys are normalized when
+// objects are written, read, deleted and listed, so that clients
+// sending the same name composed differently see a single object.
+type BucketKeyNormalizationSys struct {
+	sync.RWMutex
+	forms map[string]madmin.KeyNormalization
+}
+
+// Get - returns the key normalization form of bucket.
+func (sys *BucketKeyNormalizationSys) Get(bucket string) madmin.KeyNormalization {
+	sys.RLock()
+	defer sys.RUnlock()
+
+	if form, ok := sys.forms[bucket]; ok {
+		return form
+	}
+	return madmin.KeyNormalizationNone
+}
+
+// GetAll - returns the key normalization forms of the buckets
+// normalizing their object keys.
+func (sys *BucketKeyNormalizationSys) GetAll() map[string]madmin.KeyNormalization {
+	sys.RLock()
+	defer sys.RUnlock()
+
+	forms := make(map[string]madmin.KeyNormalization, len(sys.forms))
+	for bucket, form := range sys.forms {
+		forms[bucket] = form
+	}
+	return forms
+}
+
+// Set - replaces the key normalization forms of the buckets.
+func (sys *BucketKeyNormalizationSys) Set(forms map[string]madmin.KeyNormalization) {
+	sys.Lock()
+	defer sys.Unlock()
+
+	sys.forms = make(map[string]madmin.KeyNormalization, len(forms))
+	for bucket, form := range forms {
+		if form != madmin.KeyNormalizationNone {
+			sys.forms[bucket] = form
+		}
+	}
+}
+
+// Normalize - returns the object key converted to the key
+// normalization form of bucket.
+func (sys *BucketKeyNormalizationSys) Normalize(bucket, key string) string {
+	if sys == nil || key == "" {
+		return key
+	}
+	switch sys.Get(bucket) {
+	case madmin.KeyNormalizationNFC:
+		return norm.NFC.String(key)
+	case madmin.KeyNormalizationNFD:
+		return norm.NFD.String(key)
+	}
+	return key
+}
+
+// Load - loads the bucket key normalization forms from the backend.
+func (sys *BucketKeyNormalizationSys) Load(objAPI ObjectLayer) error {
+	if objAPI == nil {
+		return errServerNotInitialized
+	}
+
+	forms, err := readBucketKeyNormalizationConfig(context.Background(), objAPI)
+	if err != nil {
+		return err
+	}
+	sys.Set(forms)
+	return nil
+}
+
+// NewBucketKeyNormalizationSys - creates new bucket key normalization
+// system, object keys are not normalized.
+func NewBucketKeyNormalizationSys() *BucketKeyNormalizationSys {
+	return &BucketKeyNormalizationSys{
+		forms: make(map[string]madmin.KeyNormalization),
+	}
+}
+
+func readBucketKeyNormalizationConfig(ctx context.Context, objAPI ObjectLayer) (map[string]madmin.KeyNormalization, error) {
+	configFile := path.Join(minioConfigPrefix, bucketKeyNormalizationConfigFile)
+	data, err := readConfig(ctx, objAPI, configFile)
+	if err != nil {
+		if err == errConfigNotFound {
+			// Object keys of all buckets are kept as sent.
+			return nil, nil
+		}
+		return nil, err
+	}
+	var forms map[string]madmin.KeyNormalization
+	err = json.Unmarshal(data, &forms)
+	return forms, err
+}
+
+// saveBucketKeyNormalization - saves the key normalization form of
+// bucket along with the forms of the other buckets.
+func saveBucketKeyNormalization(ctx context.Context, objAPI ObjectLayer, bucket string, form madmin.KeyNormalization) error {
+	configFile := path.Join(minioConfigPrefix, bucketKeyNormalizationConfigFile)
+	formsLock := globalNSMutex.NewNSLock(ctx, minioMetaBucket, configFile)
+	if err := formsLock.GetLock(globalOperationTimeout); err != nil {
+		return err
+	}
+	defer formsLock.Unlock()
+
+	forms, err := readBucketKeyNormalizationConfig(ctx, objAPI)
+	if err != nil {
+		return err
+	}
+	if forms == nil {
+		forms = make(map[string]madmin.KeyNormalization)
+	}
+	if form == madmin.KeyNormalizationNone {
+		delete(forms, bucket)
+	} else {
+		forms[bucket] = form
+	}
+
+	data, err := json.Marshal(forms)
+	if err != nil {
+		return err
+	}
+	return saveConfig(ctx, objAPI, configFile, data)
+}
+
+// normalizeObjectKeyHandler - router middleware normalizing the object
+// key of the matched route as per the key normalization form of its
+// bucket. Request signatures are computed over the request URL, which
+// is left unchanged.
+func normalizeObjectKeyHandler(h http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		vars := mux.Vars(r)
+		if object, ok := vars["object"]; ok {
+			vars["object"] = globalBucketKeyNormalizationSys.Normalize(vars["bucket"], object)
+		}
+		h.ServeHTTP(w, r)
+	})
+}
@@ -0,0 +1,87 @@
+/*
+ * MinIO Cloud Storage, (C) 2020 MinIO, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gorilla/mux"
+	"github.com/minio/minio/pkg/madmin"
+)
+
+func TestBucketKeyNormalizationSysNormalize(t *testing.T) {
+	sys := NewBucketKeyNormalizationSys()
+	sys.Set(map[string]madmin.KeyNormalization{
+		"nfc":  madmin.KeyNormalizationNFC,
+		"nfd":  madmin.KeyNormalizationNFD,
+		"none": madmin.KeyNormalizationNone,
+	})
+
+	if forms := sys.GetAll(); len(forms) != 2 {
+		t.Fatalf("Expected 2 bucket key normalization forms, got %v", forms)
+	}
+
+	const (
+		composed   = "caf\u00e9/r\u00e9sum\u00e9.txt"
+		decomposed = "cafe\u0301/re\u0301sume\u0301.txt"
+	)
+	testCases := []struct {
+		bucket   string
+		key      string
+		expected string
+	}{
+		{"nfc", composed, composed},
+		{"nfc", decomposed, composed},
+		{"nfd", composed, decomposed},
+		{"nfd", decomposed, decomposed},
+		{"none", decomposed, decomposed},
+		{"unknown", composed, composed},
+		{"nfc", "", ""},
+	}
+	for i, testCase := range testCases {
+		if got := sys.Normalize(testCase.bucket, testCase.key); got != testCase.expected {
+			t.Errorf("Test %d: expected %q, got %q", i+1, testCase.expected, got)
+		}
+	}
+
+	var nilSys *BucketKeyNormalizationSys
+	if got := nilSys.Normalize("nfc", decomposed); got != decomposed {
+		t.Errorf("Expected keys to be kept without key normalization, got %q", got)
+	}
+}
+
+func TestNormalizeObjectKeyHandler(t *testing.T) {
+	globalBucketKeyNormalizationSys = NewBucketKeyNormalizationSys()
+	defer func() { globalBucketKeyNormalizationSys = nil }()
+	globalBucketKeyNormalizationSys.Set(map[string]madmin.KeyNormalization{
+		"bucket": madmin.KeyNormalizationNFC,
+	})
+
+	var object string
+	router := mux.NewRouter()
+	router.Use(normalizeObjectKeyHandler)
+	router.Path("/{bucket}/{object:.+}").HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		object = mux.Vars(r)["object"]
+	})
+
+	router.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/bucket/cafe%CC%81", nil))
+	if object != "caf\u00e9" {
+		t.Fatalf("Expected the object key to be normalized, got %q", object)
+	}
+}
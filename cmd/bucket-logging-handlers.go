@@ -0,0 +1,180 @@
+/*
+ * MinIO Cloud Storage, (C) 2019 MinIO, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"encoding/xml"
+	"io"
+	"net/http"
+
+	"github.com/gorilla/mux"
+	"github.com/minio/minio/cmd/logger"
+	iampolicy "github.com/minio/minio/pkg/iam/policy"
+	"github.com/minio/minio/pkg/policy"
+)
+
+// PutBucketLoggingHandler - This HTTP handler stores given bucket logging configuration as per
+// https://docs.aws.amazon.com/AmazonS3/latest/API/RESTBucketPUTlogging.html
+func (api objectAPIHandlers) PutBucketLoggingHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := newContext(r, w, "PutBucketLogging")
+
+	defer logger.AuditLog(w, r, "PutBucketLogging", mustGetClaimsFromToken(r))
+
+	objAPI := api.ObjectAPI()
+	if objAPI == nil {
+		writeErrorResponse(ctx, w, errorCodes.ToAPIErr(ErrServerNotInitialized), r.URL, guessIsBrowserReq(r))
+		return
+	}
+
+	// Bucket logging configuration is stored on the backend
+	// which is not available in gateway mode.
+	if globalIsGateway {
+		writeErrorResponse(ctx, w, errorCodes.ToAPIErr(ErrNotImplemented), r.URL, guessIsBrowserReq(r))
+		return
+	}
+
+	vars := mux.Vars(r)
+	bucket := vars["bucket"]
+
+	accessKey, owner, s3Error := checkRequestAuthTypeToAccessKey(ctx, r, policy.PutBucketLoggingAction, bucket, "")
+	if s3Error != ErrNone {
+		writeErrorResponse(ctx, w, errorCodes.ToAPIErr(s3Error), r.URL, guessIsBrowserReq(r))
+		return
+	}
+
+	// Check if bucket exists.
+	if _, err := objAPI.GetBucketInfo(ctx, bucket); err != nil {
+		writeErrorResponse(ctx, w, toAPIError(ctx, err), r.URL, guessIsBrowserReq(r))
+		return
+	}
+
+	status, err := parseBucketLoggingStatus(io.LimitReader(r.Body, r.ContentLength))
+	if err != nil {
+		writeErrorResponse(ctx, w, errorCodes.ToAPIErr(ErrMalformedXML), r.URL, guessIsBrowserReq(r))
+		return
+	}
+
+	// Empty logging status disables logging.
+	if status.LoggingEnabled == nil {
+		if err = removeBucketLoggingConfig(ctx, objAPI, bucket); err != nil {
+			writeErrorResponse(ctx, w, toAPIError(ctx, err), r.URL, guessIsBrowserReq(r))
+			return
+		}
+		globalBucketLoggingSys.Remove(bucket)
+		globalNotificationSys.RemoveBucketLogging(ctx, bucket)
+		writeSuccessResponseHeadersOnly(w)
+		return
+	}
+
+	// Check if target bucket exists.
+	if _, err = objAPI.GetBucketInfo(ctx, status.LoggingEnabled.TargetBucket); err != nil {
+		writeErrorResponse(ctx, w, errorCodes.ToAPIErr(ErrInvalidTargetBucketForLogging), r.URL, guessIsBrowserReq(r))
+		return
+	}
+
+	// Access log records are written with the privileges of the
+	// server, the requester must be allowed to write them itself.
+	if !isBucketLoggingTargetAllowed(r, accessKey, owner, *status.LoggingEnabled) {
+		writeErrorResponse(ctx, w, errorCodes.ToAPIErr(ErrAccessDenied), r.URL, guessIsBrowserReq(r))
+		return
+	}
+
+	if err = saveBucketLoggingConfig(ctx, objAPI, bucket, status); err != nil {
+		writeErrorResponse(ctx, w, toAPIError(ctx, err), r.URL, guessIsBrowserReq(r))
+		return
+	}
+
+	globalBucketLoggingSys.Set(bucket, *status.LoggingEnabled)
+	globalNotificationSys.SetBucketLogging(ctx, bucket, *status.LoggingEnabled)
+
+	// Success.
+	writeSuccessResponseHeadersOnly(w)
+}
+
+// isBucketLoggingTargetAllowed - returns true if the requester is
+// allowed to put objects under the target prefix of the target bucket.
+func isBucketLoggingTargetAllowed(r *http.Request, accessKey string, owner bool, target LoggingEnabled) bool {
+	if accessKey == "" {
+		return globalPolicySys.IsAllowed(policy.Args{
+			Action:          policy.PutObjectAction,
+			BucketName:      target.TargetBucket,
+			ConditionValues: getConditionValues(r, "", ""),
+			ObjectName:      target.TargetPrefix,
+		})
+	}
+
+	return globalIAMSys.IsAllowed(iampolicy.Args{
+		AccountName:     accessKey,
+		Action:          iampolicy.PutObjectAction,
+		BucketName:      target.TargetBucket,
+		ConditionValues: getConditionValues(r, "", accessKey),
+		ObjectName:      target.TargetPrefix,
+		IsOwner:         owner,
+		Claims:          mustGetClaimsFromToken(r),
+	})
+}
+
+// GetBucketLoggingHandler - This HTTP handler returns bucket logging configuration.
+func (api objectAPIHandlers) GetBucketLoggingHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := newContext(r, w, "GetBucketLogging")
+
+	defer logger.AuditLog(w, r, "GetBucketLogging", mustGetClaimsFromToken(r))
+
+	objAPI := api.ObjectAPI()
+	if objAPI == nil {
+		writeErrorResponse(ctx, w, errorCodes.ToAPIErr(ErrServerNotInitialized), r.URL, guessIsBrowserReq(r))
+		return
+	}
+
+	vars := mux.Vars(r)
+	bucket := vars["bucket"]
+
+	if s3Error := checkRequestAuthType(ctx, r, policy.GetBucketLoggingAction, bucket, ""); s3Error != ErrNone {
+		writeErrorResponse(ctx, w, errorCodes.ToAPIErr(s3Error), r.URL, guessIsBrowserReq(r))
+		return
+	}
+
+	// Check if bucket exists.
+	if _, err := objAPI.GetBucketInfo(ctx, bucket); err != nil {
+		writeErrorResponse(ctx, w, toAPIError(ctx, err), r.URL, guessIsBrowserReq(r))
+		return
+	}
+
+	// Logging is disabled unless configured, which
+	// is reported as an empty logging status.
+	status := &BucketLoggingStatus{}
+	if !globalIsGateway {
+		var err error
+		if status, err = getBucketLoggingConfig(objAPI, bucket); err != nil {
+			if err != errConfigNotFound {
+				writeErrorResponse(ctx, w, toAPIError(ctx, err), r.URL, guessIsBrowserReq(r))
+				return
+			}
+			status = &BucketLoggingStatus{}
+		}
+	}
+	status.XMLNS = "http://s3.amazonaws.com/doc/2006-03-01/"
+
+	loggingData, err := xml.Marshal(status)
+	if err != nil {
+		writeErrorResponse(ctx, w, toAPIError(ctx, err), r.URL, guessIsBrowserReq(r))
+		return
+	}
+
+	// Write logging configuration to client.
+	writeSuccessResponseXML(w, loggingData)
+}
@@ -0,0 +1,351 @@
+/*
+ * MinIO Cloud Storage, (C) 2019 MinIO, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"path"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/minio/minio-go/pkg/set"
+	"github.com/minio/minio/cmd/logger"
+	"github.com/minio/minio/cmd/logger/message/audit"
+	"github.com/minio/minio/pkg/hash"
+)
+
+const (
+	// Bucket logging configuration file.
+	bucketLoggingConfig = "logging.xml"
+
+	// Maximum number of access log records buffered per
+	// target before they are written out early.
+	maxBucketLoggingRecords = 10000
+
+	// Maximum number of access log records kept per target
+	// while the target bucket is not writable, older records
+	// are dropped beyond this limit.
+	maxBucketLoggingBacklog = 10 * maxBucketLoggingRecords
+)
+
+// LoggingEnabled - holds the target bucket and prefix access
+// log records of a bucket are written to.
+type LoggingEnabled struct {
+	TargetBucket string `xml:"TargetBucket"`
+	TargetPrefix string `xml:"TargetPrefix"`
+}
+
+// BucketLoggingStatus - bucket logging configuration as per
+// https://docs.aws.amazon.com/AmazonS3/latest/API/RESTBucketPUTlogging.html
+// An empty LoggingEnabled disables logging for the bucket.
+type BucketLoggingStatus struct {
+	XMLName        xml.Name        `xml:"BucketLoggingStatus"`
+	XMLNS          string          `xml:"xmlns,attr,omitempty"`
+	LoggingEnabled *LoggingEnabled `xml:"LoggingEnabled,omitempty"`
+}
+
+// parseBucketLoggingStatus - parses and validates bucket logging configuration.
+func parseBucketLoggingStatus(reader io.Reader) (*BucketLoggingStatus, error) {
+	var status BucketLoggingStatus
+	if err := xml.NewDecoder(reader).Decode(&status); err != nil {
+		return nil, err
+	}
+	if status.LoggingEnabled != nil && !IsValidBucketName(status.LoggingEnabled.TargetBucket) {
+		return nil, BucketNameInvalid{Bucket: status.LoggingEnabled.TargetBucket}
+	}
+	return &status, nil
+}
+
+func saveBucketLoggingConfig(ctx context.Context, objAPI ObjectLayer, bucketName string, status *BucketLoggingStatus) error {
+	data, err := xml.Marshal(status)
+	if err != nil {
+		return err
+	}
+
+	// Construct path to logging.xml for the given bucket.
+	configFile := path.Join(bucketConfigPrefix, bucketName, bucketLoggingConfig)
+	return saveConfig(ctx, objAPI, configFile, data)
+}
+
+// getBucketLoggingConfig - get bucket logging config for given bucket name.
+func getBucketLoggingConfig(objAPI ObjectLayer, bucketName string) (*BucketLoggingStatus, error) {
+	// Construct path to logging.xml for the given bucket.
+	configFile := path.Join(bucketConfigPrefix, bucketName, bucketLoggingConfig)
+	configData, err := readConfig(context.Background(), objAPI, configFile)
+	if err != nil {
+		return nil, err
+	}
+
+	return parseBucketLoggingStatus(bytes.NewReader(configData))
+}
+
+func removeBucketLoggingConfig(ctx context.Context, objAPI ObjectLayer, bucketName string) error {
+	// Construct path to logging.xml for the given bucket.
+	configFile := path.Join(bucketConfigPrefix, bucketName, bucketLoggingConfig)
+
+	if err := objAPI.DeleteObject(ctx, minioMetaBucket, configFile); err != nil {
+		if _, ok := err.(ObjectNotFound); !ok {
+			return err
+		}
+	}
+	return nil
+}
+
+// BucketLoggingSys - Bucket access logging subsystem. It is
+// registered as an audit target and buffers an access log record
+// for every request to a bucket with logging enabled, records are
+// written in batches to the configured target bucket.
+type BucketLoggingSys struct {
+	sync.RWMutex
+	bucketLoggingMap map[string]LoggingEnabled
+
+	recordsMu sync.Mutex
+	records   map[LoggingEnabled][]string
+	flushCh   chan struct{}
+}
+
+// Set - sets logging config to given bucket name.
+func (sys *BucketLoggingSys) Set(bucketName string, loggingEnabled LoggingEnabled) {
+	sys.Lock()
+	defer sys.Unlock()
+
+	sys.bucketLoggingMap[bucketName] = loggingEnabled
+}
+
+// Get - gets logging config associated to a given bucket name.
+func (sys *BucketLoggingSys) Get(bucketName string) (loggingEnabled LoggingEnabled, ok bool) {
+	sys.RLock()
+	defer sys.RUnlock()
+
+	l, ok := sys.bucketLoggingMap[bucketName]
+	return l, ok
+}
+
+// Remove - removes logging config for given bucket name.
+func (sys *BucketLoggingSys) Remove(bucketName string) {
+	sys.Lock()
+	defer sys.Unlock()
+
+	delete(sys.bucketLoggingMap, bucketName)
+}
+
+// accessLogRecord - formats an audit entry as an access log record,
+// fields which are not known are logged as "-".
+//
+// bucket [time] remote-ip requester request-id operation key http-status total-time turn-around-time "user-agent"
+func accessLogRecord(entry audit.Entry) string {
+	orDash := func(s string) string {
+		if s == "" {
+			return "-"
+		}
+		return s
+	}
+	var requester string
+	if accessKey, ok := entry.ReqClaims["accessKey"].(string); ok {
+		requester = accessKey
+	}
+	return fmt.Sprintf("%s [%s] %s %s %s %s %s %d %s %s %q",
+		entry.API.Bucket, entry.Time, orDash(entry.RemoteHost), orDash(requester),
+		orDash(entry.RequestID), orDash(entry.API.Name), orDash(entry.API.Object),
+		entry.API.StatusCode, orDash(entry.API.TimeToResponse),
+		orDash(entry.API.TimeToFirstByte), entry.UserAgent)
+}
+
+// Send - buffers an access log record for the request described by
+// entry, if logging is enabled for its bucket. Implements logger.Target.
+func (sys *BucketLoggingSys) Send(e interface{}) error {
+	entry, ok := e.(audit.Entry)
+	if !ok || entry.API.Bucket == "" {
+		return nil
+	}
+	loggingEnabled, ok := sys.Get(entry.API.Bucket)
+	if !ok {
+		return nil
+	}
+
+	sys.recordsMu.Lock()
+	sys.records[loggingEnabled] = append(sys.records[loggingEnabled], accessLogRecord(entry))
+	full := len(sys.records[loggingEnabled]) >= maxBucketLoggingRecords
+	sys.recordsMu.Unlock()
+
+	if full {
+		select {
+		case sys.flushCh <- struct{}{}:
+		default:
+		}
+	}
+	return nil
+}
+
+// flush - writes all buffered access log records to their target
+// buckets, one object per target named <prefix><time>-<uuid>.
+// Records which could not be written are kept for the next flush.
+func (sys *BucketLoggingSys) flush(objAPI ObjectLayer) {
+	sys.recordsMu.Lock()
+	records := sys.records
+	sys.records = make(map[LoggingEnabled][]string)
+	sys.recordsMu.Unlock()
+
+	for target, lines := range records {
+		reqInfo := (&logger.ReqInfo{}).AppendTags("targetBucket", target.TargetBucket)
+		ctx := logger.SetReqInfo(GlobalContext, reqInfo)
+		if err := writeAccessLogRecords(ctx, objAPI, target, lines); err != nil {
+			logger.LogIf(ctx, err)
+			sys.requeue(target, lines)
+		}
+	}
+}
+
+// writeAccessLogRecords - writes access log records as one object to the target bucket.
+func writeAccessLogRecords(ctx context.Context, objAPI ObjectLayer, target LoggingEnabled, lines []string) error {
+	data := []byte(strings.Join(lines, "\n") + "\n")
+	object := target.TargetPrefix + UTCNow().Format("2006-01-02-15-04-05") + "-" + mustGetUUID()
+	reader, err := hash.NewReader(bytes.NewReader(data), int64(len(data)), "", "", int64(len(data)), globalCLIContext.StrictS3Compat)
+	if err != nil {
+		return err
+	}
+	_, err = objAPI.PutObject(ctx, target.TargetBucket, object, NewPutObjReader(reader, nil, nil), ObjectOptions{})
+	return err
+}
+
+// requeue - puts back access log records which could not be written
+// ahead of the records buffered since, keeping at most
+// maxBucketLoggingBacklog records for the target.
+func (sys *BucketLoggingSys) requeue(target LoggingEnabled, lines []string) {
+	sys.recordsMu.Lock()
+	defer sys.recordsMu.Unlock()
+
+	lines = append(lines, sys.records[target]...)
+	if dropped := len(lines) - maxBucketLoggingBacklog; dropped > 0 {
+		reqInfo := (&logger.ReqInfo{}).AppendTags("targetBucket", target.TargetBucket)
+		ctx := logger.SetReqInfo(GlobalContext, reqInfo)
+		logger.LogIf(ctx, fmt.Errorf("dropped %d access log records", dropped))
+		lines = lines[dropped:]
+	}
+	sys.records[target] = lines
+}
+
+// NewBucketLoggingSys - creates new bucket logging system.
+func NewBucketLoggingSys() *BucketLoggingSys {
+	return &BucketLoggingSys{
+		bucketLoggingMap: make(map[string]LoggingEnabled),
+		records:          make(map[LoggingEnabled][]string),
+		flushCh:          make(chan struct{}, 1),
+	}
+}
+
+// Init - initializes bucket logging system from logging.xml of all buckets.
+func (sys *BucketLoggingSys) Init(objAPI ObjectLayer) error {
+	if objAPI == nil {
+		return errServerNotInitialized
+	}
+
+	defer func() {
+		// Refresh BucketLoggingSys and flush access log records in background.
+		go func() {
+			refreshTicker := time.NewTicker(globalRefreshBucketLoggingInterval)
+			defer refreshTicker.Stop()
+			flushTicker := time.NewTicker(globalBucketLoggingFlushInterval)
+			defer flushTicker.Stop()
+			for {
+				select {
+				case <-GlobalServiceDoneCh:
+					sys.flush(objAPI)
+					return
+				case <-refreshTicker.C:
+					sys.refresh(objAPI)
+				case <-flushTicker.C:
+					sys.flush(objAPI)
+				case <-sys.flushCh:
+					sys.flush(objAPI)
+				}
+			}
+		}()
+	}()
+
+	doneCh := make(chan struct{})
+	defer close(doneCh)
+
+	// Initializing bucket logging needs a retry mechanism for
+	// the following reasons:
+	//  - Read quorum is lost just after the initialization
+	//    of the object layer.
+	for range newRetryTimerSimple(doneCh) {
+		// Load BucketLoggingSys once during boot.
+		if err := sys.refresh(objAPI); err != nil {
+			if err == errDiskNotFound ||
+				strings.Contains(err.Error(), InsufficientReadQuorum{}.Error()) ||
+				strings.Contains(err.Error(), InsufficientWriteQuorum{}.Error()) {
+				logger.Info("Waiting for bucket logging subsystem to be initialized..")
+				continue
+			}
+			return err
+		}
+		break
+	}
+	return nil
+}
+
+// Refresh BucketLoggingSys.
+func (sys *BucketLoggingSys) refresh(objAPI ObjectLayer) error {
+	buckets, err := objAPI.ListBuckets(context.Background())
+	if err != nil {
+		logger.LogIf(context.Background(), err)
+		return err
+	}
+	sys.removeDeletedBuckets(buckets)
+	for _, bucket := range buckets {
+		status, err := getBucketLoggingConfig(objAPI, bucket.Name)
+		if err != nil {
+			if err == errConfigNotFound {
+				sys.Remove(bucket.Name)
+			}
+			continue
+		}
+		if status.LoggingEnabled == nil {
+			sys.Remove(bucket.Name)
+			continue
+		}
+
+		sys.Set(bucket.Name, *status.LoggingEnabled)
+	}
+
+	return nil
+}
+
+// removeDeletedBuckets - to handle a corner case where we have cached the logging config
+// for a deleted bucket. i.e if we miss a delete-bucket notification we should delete the
+// corresponding bucket logging config during sys.refresh()
+func (sys *BucketLoggingSys) removeDeletedBuckets(bucketInfos []BucketInfo) {
+	buckets := set.NewStringSet()
+	for _, info := range bucketInfos {
+		buckets.Add(info.Name)
+	}
+	sys.Lock()
+	defer sys.Unlock()
+
+	for bucket := range sys.bucketLoggingMap {
+		if !buckets.Contains(bucket) {
+			delete(sys.bucketLoggingMap, bucket)
+		}
+	}
+}
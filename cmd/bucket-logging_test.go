@@ -0,0 +1,171 @@
+/*
+ * MinIO Cloud Storage, (C) 2019 MinIO, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/minio/minio/cmd/logger/message/audit"
+)
+
+// Tests parsing of bucket logging configuration.
+func TestParseBucketLoggingStatus(t *testing.T) {
+	testCases := []struct {
+		input          string
+		loggingEnabled *LoggingEnabled
+		expectErr      bool
+	}{
+		{
+			input: `<BucketLoggingStatus xmlns="http://s3.amazonaws.com/doc/2006-03-01/"/>`,
+		},
+		{
+			input:          `<BucketLoggingStatus><LoggingEnabled><TargetBucket>logs</TargetBucket><TargetPrefix>access/</TargetPrefix></LoggingEnabled></BucketLoggingStatus>`,
+			loggingEnabled: &LoggingEnabled{TargetBucket: "logs", TargetPrefix: "access/"},
+		},
+		{
+			input:     `<BucketLoggingStatus><LoggingEnabled><TargetBucket>in</TargetBucket></LoggingEnabled></BucketLoggingStatus>`,
+			expectErr: true,
+		},
+		{
+			input:     `<BucketLoggingStatus>`,
+			expectErr: true,
+		},
+	}
+
+	for i, testCase := range testCases {
+		status, err := parseBucketLoggingStatus(strings.NewReader(testCase.input))
+		if testCase.expectErr {
+			if err == nil {
+				t.Errorf("Test %d: Expected error, got nil", i+1)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("Test %d: Unexpected error %v", i+1, err)
+			continue
+		}
+		if (status.LoggingEnabled == nil) != (testCase.loggingEnabled == nil) ||
+			(status.LoggingEnabled != nil && *status.LoggingEnabled != *testCase.loggingEnabled) {
+			t.Errorf("Test %d: Expected %v, got %v", i+1, testCase.loggingEnabled, status.LoggingEnabled)
+		}
+	}
+}
+
+// Tests that access log records are buffered and written to the target bucket.
+func TestBucketLoggingSysFlush(t *testing.T) {
+	objLayer, fsDir, err := prepareFS()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer removeRoots([]string{fsDir})
+
+	for _, bucket := range []string{"source", "logs"} {
+		if err = objLayer.MakeBucketWithLocation(context.Background(), bucket, ""); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	sys := NewBucketLoggingSys()
+	sys.Set("source", LoggingEnabled{TargetBucket: "logs", TargetPrefix: "access/"})
+
+	var entry audit.Entry
+	entry.API.Name = "GetObject"
+	entry.API.Bucket = "source"
+	entry.API.Object = "object"
+	entry.API.StatusCode = 200
+	if err = sys.Send(entry); err != nil {
+		t.Fatal(err)
+	}
+	// Requests to buckets without logging are not recorded.
+	entry.API.Bucket = "logs"
+	if err = sys.Send(entry); err != nil {
+		t.Fatal(err)
+	}
+
+	sys.flush(objLayer)
+
+	result, err := objLayer.ListObjects(context.Background(), "logs", "access/", "", "", 10)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(result.Objects) != 1 {
+		t.Fatalf("Expected 1 access log object, got %d", len(result.Objects))
+	}
+
+	var buffer bytes.Buffer
+	if err = objLayer.GetObject(context.Background(), "logs", result.Objects[0].Name, 0, -1, &buffer, "", ObjectOptions{}); err != nil {
+		t.Fatal(err)
+	}
+	lines := strings.Split(strings.TrimSpace(buffer.String()), "\n")
+	if len(lines) != 1 || !strings.HasPrefix(lines[0], "source [") || !strings.Contains(lines[0], " GetObject object 200 ") {
+		t.Fatalf("Unexpected access log records %q", buffer.String())
+	}
+}
+
+// Tests that access log records are kept while the target bucket is not writable.
+func TestBucketLoggingSysFlushRetry(t *testing.T) {
+	objLayer, fsDir, err := prepareFS()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer removeRoots([]string{fsDir})
+
+	if err = objLayer.MakeBucketWithLocation(context.Background(), "source", ""); err != nil {
+		t.Fatal(err)
+	}
+
+	sys := NewBucketLoggingSys()
+	target := LoggingEnabled{TargetBucket: "logs", TargetPrefix: "access/"}
+	sys.Set("source", target)
+
+	var entry audit.Entry
+	entry.API.Name = "GetObject"
+	entry.API.Bucket = "source"
+	if err = sys.Send(entry); err != nil {
+		t.Fatal(err)
+	}
+
+	// Target bucket does not exist yet, records must be kept.
+	sys.flush(objLayer)
+	if n := len(sys.records[target]); n != 1 {
+		t.Fatalf("Expected 1 buffered access log record, got %d", n)
+	}
+
+	if err = objLayer.MakeBucketWithLocation(context.Background(), "logs", ""); err != nil {
+		t.Fatal(err)
+	}
+	sys.flush(objLayer)
+	if n := len(sys.records[target]); n != 0 {
+		t.Fatalf("Expected no buffered access log records, got %d", n)
+	}
+	result, err := objLayer.ListObjects(context.Background(), "logs", "access/", "", "", 10)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(result.Objects) != 1 {
+		t.Fatalf("Expected 1 access log object, got %d", len(result.Objects))
+	}
+
+	// Backlog is bounded, oldest records are dropped.
+	sys.requeue(target, make([]string, maxBucketLoggingBacklog+1))
+	if n := len(sys.records[target]); n != maxBucketLoggingBacklog {
+		t.Fatalf("Expected %d buffered access log records, got %d", maxBucketLoggingBacklog, n)
+	}
+}
@@ -0,0 +1,305 @@
+/*
+ * MinIO Cloud Storage, (C) 2020 MinIO, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"path"
+	"strings"
+
+	humanize "github.com/dustin/go-humanize"
+	"github.com/minio/minio/cmd/logger"
+	"github.com/minio/minio/pkg/cors"
+	"github.com/minio/minio/pkg/event"
+	"github.com/minio/minio/pkg/lifecycle"
+	"github.com/minio/minio/pkg/madmin"
+	"github.com/minio/minio/pkg/policy"
+	"github.com/minio/minio/pkg/website"
+)
+
+// A bucket metadata bundle holds the configuration of a bucket as JSON,
+// to be applied to another bucket, possibly of another deployment, e.g.
+// when promoting a bucket from a staging to a production deployment.
+// The bucket name is rewritten in the policy and logging configuration
+// on import. Snapshot configuration is left out since it holds
+// credentials, and so are bucket tags, which are not stored.
+
+const (
+	bucketMetadataBundleVersion = "1"
+
+	// Maximum size of a bucket metadata bundle.
+	maxBucketMetadataBundleSize = 4 * humanize.MiByte
+)
+
+var errUnknownBucketMetadataBundleVersion = errors.New("Unknown bucket metadata bundle version")
+
+// bucketMetadataBundleFiles - metadata files of a bucket included in
+// bucket metadata bundles.
+var bucketMetadataBundleFiles = []string{
+	bucketPolicyConfig,
+	bucketNotificationConfig,
+	bucketLifecycleConfig,
+	bucketLoggingConfig,
+	bucketCORSConfig,
+	bucketWebsiteConfig,
+	bucketTrashConfig,
+	bucketAnonymousUploadConfig,
+	bucketContentTypeConfig,
+}
+
+// exportBucketMetadata - returns the metadata bundle of bucket.
+func exportBucketMetadata(ctx context.Context, objAPI ObjectLayer, bucket string) (bundle madmin.BucketMetadataBundle, err error) {
+	bundle = madmin.BucketMetadataBundle{
+		Version:          bucketMetadataBundleVersion,
+		Bucket:           bucket,
+		Created:          UTCNow(),
+		KeyNormalization: globalBucketKeyNormalizationSys.Get(bucket),
+		Files:            make(map[string]string),
+	}
+	for _, file := range bucketMetadataBundleFiles {
+		data, err := readConfig(ctx, objAPI, path.Join(bucketConfigPrefix, bucket, file))
+		if err != nil {
+			if err == errConfigNotFound {
+				continue
+			}
+			return bundle, err
+		}
+		bundle.Files[file] = string(data)
+	}
+	return bundle, nil
+}
+
+// rewriteBucketPolicy - rewrites the resources of bucketPolicy of the
+// source bucket to refer to bucket, resources of other buckets are
+// kept.
+func rewriteBucketPolicy(bucketPolicy *policy.Policy, source, bucket string) {
+	for i, statement := range bucketPolicy.Statements {
+		resources := policy.NewResourceSet()
+		for resource := range statement.Resources {
+			if resource.BucketName == source {
+				resource = policy.NewResource(bucket, strings.TrimPrefix(resource.Pattern, source))
+			}
+			resources.Add(resource)
+		}
+		bucketPolicy.Statements[i].Resources = resources
+	}
+}
+
+// prepareBucketMetadataImport - validates bundle against bucket and
+// returns the functions applying each of its files, so that nothing is
+// applied unless the whole bundle is valid. Files missing from the
+// bundle are left unchanged.
+func prepareBucketMetadataImport(ctx context.Context, objAPI ObjectLayer, bucket string, bundle madmin.BucketMetadataBundle) ([]func() error, error) {
+	if bundle.Version != bucketMetadataBundleVersion {
+		return nil, errUnknownBucketMetadataBundleVersion
+	}
+	if bundle.KeyNormalization != "" && !bundle.KeyNormalization.IsValid() {
+		return nil, fmt.Errorf("unknown key normalization form %s", bundle.KeyNormalization)
+	}
+
+	for file := range bundle.Files {
+		if !contains(bucketMetadataBundleFiles, file) {
+			return nil, fmt.Errorf("%s: unknown bucket metadata file", file)
+		}
+	}
+
+	var appliers []func() error
+	for _, file := range bucketMetadataBundleFiles {
+		data, ok := bundle.Files[file]
+		if !ok {
+			continue
+		}
+		apply, err := prepareBucketMetadataFile(ctx, objAPI, bucket, bundle.Bucket, file, []byte(data))
+		if err != nil {
+			return nil, fmt.Errorf("%s: %v", file, err)
+		}
+		appliers = append(appliers, apply)
+	}
+
+	if bundle.KeyNormalization != "" {
+		appliers = append(appliers, func() error {
+			if err := saveBucketKeyNormalization(ctx, objAPI, bucket, bundle.KeyNormalization); err != nil {
+				return err
+			}
+			if err := globalBucketKeyNormalizationSys.Load(objAPI); err != nil {
+				return err
+			}
+			for _, nerr := range globalNotificationSys.LoadBucketKeyNormalization() {
+				if nerr.Err != nil {
+					logger.GetReqInfo(ctx).SetTags("peerAddress", nerr.Host.String())
+					logger.LogIf(ctx, nerr.Err)
+				}
+			}
+			return nil
+		})
+	}
+	return appliers, nil
+}
+
+// prepareBucketMetadataFile - parses a metadata file of the source
+// bucket and returns the function applying it to bucket, as the S3 or
+// web handler setting it would.
+func prepareBucketMetadataFile(ctx context.Context, objAPI ObjectLayer, bucket, source, file string, data []byte) (func() error, error) {
+	switch file {
+	case bucketPolicyConfig:
+		bucketPolicy, err := policy.ParseConfig(bytes.NewReader(data), source)
+		if err != nil {
+			return nil, err
+		}
+		if bucketPolicy.Version == "" {
+			return nil, errors.New("policy version is missing")
+		}
+		rewriteBucketPolicy(bucketPolicy, source, bucket)
+		return func() error {
+			if err := objAPI.SetBucketPolicy(ctx, bucket, bucketPolicy); err != nil {
+				return err
+			}
+			globalPolicySys.Set(bucket, *bucketPolicy)
+			globalNotificationSys.SetBucketPolicy(ctx, bucket, bucketPolicy)
+			return nil
+		}, nil
+
+	case bucketNotificationConfig:
+		config, err := event.ParseConfig(bytes.NewReader(data), globalServerConfig.GetRegion(), globalNotificationSys.targetList)
+		if err != nil {
+			// Targets may be configured after the import.
+			if _, ok := err.(*event.ErrARNNotFound); !ok {
+				return nil, err
+			}
+		}
+		return func() error {
+			if err := saveNotificationConfig(ctx, objAPI, bucket, config); err != nil {
+				return err
+			}
+			rulesMap := config.ToRulesMap()
+			globalNotificationSys.AddRulesMap(bucket, rulesMap)
+			globalNotificationSys.PutBucketNotification(ctx, bucket, rulesMap)
+			return nil
+		}, nil
+
+	case bucketLifecycleConfig:
+		bucketLifecycle, err := lifecycle.ParseLifecycleConfig(bytes.NewReader(data))
+		if err != nil {
+			return nil, err
+		}
+		if err = checkLifecycleArchiveBuckets(ctx, objAPI, bucket, bucketLifecycle); err != nil {
+			return nil, err
+		}
+		return func() error {
+			if err := objAPI.SetBucketLifecycle(ctx, bucket, bucketLifecycle); err != nil {
+				return err
+			}
+			globalLifecycleSys.Set(bucket, *bucketLifecycle)
+			globalNotificationSys.SetBucketLifecycle(ctx, bucket, bucketLifecycle)
+			return nil
+		}, nil
+
+	case bucketLoggingConfig:
+		status, err := parseBucketLoggingStatus(bytes.NewReader(data))
+		if err != nil {
+			return nil, err
+		}
+		if status.LoggingEnabled == nil {
+			return func() error {
+				if err := removeBucketLoggingConfig(ctx, objAPI, bucket); err != nil {
+					return err
+				}
+				globalBucketLoggingSys.Remove(bucket)
+				globalNotificationSys.RemoveBucketLogging(ctx, bucket)
+				return nil
+			}, nil
+		}
+		// A bucket logging to itself keeps doing so.
+		if status.LoggingEnabled.TargetBucket == source {
+			status.LoggingEnabled.TargetBucket = bucket
+		}
+		if _, err = objAPI.GetBucketInfo(ctx, status.LoggingEnabled.TargetBucket); err != nil {
+			return nil, err
+		}
+		return func() error {
+			if err := saveBucketLoggingConfig(ctx, objAPI, bucket, status); err != nil {
+				return err
+			}
+			globalBucketLoggingSys.Set(bucket, *status.LoggingEnabled)
+			globalNotificationSys.SetBucketLogging(ctx, bucket, *status.LoggingEnabled)
+			return nil
+		}, nil
+
+	case bucketCORSConfig:
+		config, err := cors.ParseConfig(bytes.NewReader(data))
+		if err != nil {
+			return nil, err
+		}
+		return func() error {
+			if err := saveBucketCORSConfig(ctx, objAPI, bucket, config); err != nil {
+				return err
+			}
+			globalBucketCORSSys.Set(bucket, *config)
+			globalNotificationSys.SetBucketCORS(ctx, bucket, *config)
+			return nil
+		}, nil
+
+	case bucketWebsiteConfig:
+		config, err := website.ParseConfig(bytes.NewReader(data))
+		if err != nil {
+			return nil, err
+		}
+		return func() error {
+			if err := saveBucketWebsiteConfig(ctx, objAPI, bucket, config); err != nil {
+				return err
+			}
+			globalBucketWebsiteSys.Set(bucket, *config)
+			globalNotificationSys.SetBucketWebsite(ctx, bucket, *config)
+			return nil
+		}, nil
+
+	case bucketTrashConfig:
+		var config BucketTrashConfig
+		if err := json.Unmarshal(data, &config); err != nil {
+			return nil, err
+		}
+		return func() error {
+			return saveBucketTrashConfig(ctx, objAPI, bucket, config)
+		}, nil
+
+	case bucketAnonymousUploadConfig:
+		var config BucketAnonymousUploadConfig
+		if err := json.Unmarshal(data, &config); err != nil {
+			return nil, err
+		}
+		return func() error {
+			return saveBucketAnonymousUploadConfig(ctx, objAPI, bucket, config)
+		}, nil
+
+	case bucketContentTypeConfig:
+		var config BucketContentTypeConfig
+		if err := json.Unmarshal(data, &config); err != nil {
+			return nil, err
+		}
+		if err := config.Validate(); err != nil {
+			return nil, err
+		}
+		return func() error {
+			return saveBucketContentTypeConfig(ctx, objAPI, bucket, config)
+		}, nil
+	}
+	return nil, errors.New("unknown bucket metadata file")
+}
@@ -0,0 +1,136 @@
+/*
+ * MinIO Cloud Storage, (C) 2020 MinIO, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"context"
+	"os"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/minio/minio/pkg/policy"
+)
+
+func TestRewriteBucketPolicy(t *testing.T) {
+	bucketPolicy, err := policy.ParseConfig(strings.NewReader(`{
+	"Version": "2012-10-17",
+	"Statement": [{
+		"Effect": "Allow",
+		"Principal": {"AWS": ["*"]},
+		"Action": ["s3:GetBucketLocation", "s3:GetObject"],
+		"Resource": ["arn:aws:s3:::staging", "arn:aws:s3:::staging/public/*", "arn:aws:s3:::*"]
+	}]
+}`), "staging")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	rewriteBucketPolicy(bucketPolicy, "staging", "production")
+	expected := policy.NewResourceSet(
+		policy.NewResource("production", ""),
+		policy.NewResource("production", "public/*"),
+		policy.NewResource("*", ""),
+	)
+	if resources := bucketPolicy.Statements[0].Resources; !reflect.DeepEqual(resources, expected) {
+		t.Fatalf("Expected resources %v, got %v", expected, resources)
+	}
+	if err = bucketPolicy.Validate("production"); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// Tests that the metadata bundle of a bucket is applied to another
+// bucket, and that invalid bundles are not applied at all.
+func TestBucketMetadataBundle(t *testing.T) {
+	obj, fsDir, err := prepareFS()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(fsDir)
+
+	globalBucketKeyNormalizationSys = NewBucketKeyNormalizationSys()
+	defer func() { globalBucketKeyNormalizationSys = nil }()
+
+	ctx := context.Background()
+	for _, bucket := range []string{"staging", "production"} {
+		if err = obj.MakeBucketWithLocation(ctx, bucket, ""); err != nil {
+			t.Fatal(err)
+		}
+	}
+	trashConfig := BucketTrashConfig{RetentionDays: 7}
+	if err = saveBucketTrashConfig(ctx, obj, "staging", trashConfig); err != nil {
+		t.Fatal(err)
+	}
+	contentTypeConfig := BucketContentTypeConfig{ContentTypes: []string{"image/*"}}
+	if err = saveBucketContentTypeConfig(ctx, obj, "staging", contentTypeConfig); err != nil {
+		t.Fatal(err)
+	}
+
+	bundle, err := exportBucketMetadata(ctx, obj, "staging")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if bundle.Bucket != "staging" || len(bundle.Files) != 2 {
+		t.Fatalf("Unexpected bundle %v", bundle)
+	}
+	// Key normalization forms are applied on all servers.
+	bundle.KeyNormalization = ""
+
+	invalid := bundle
+	invalid.Files = map[string]string{
+		bucketTrashConfig:       bundle.Files[bucketTrashConfig],
+		bucketContentTypeConfig: `{"contentTypes": ["*/png"]}`,
+	}
+	if _, err = prepareBucketMetadataImport(ctx, obj, "production", invalid); err == nil {
+		t.Fatal("Expected an invalid content type config to be rejected")
+	}
+	invalid.Files = map[string]string{"snapshot.json": "{}"}
+	if _, err = prepareBucketMetadataImport(ctx, obj, "production", invalid); err == nil {
+		t.Fatal("Expected an unknown file to be rejected")
+	}
+	invalid = bundle
+	invalid.Version = "2"
+	if _, err = prepareBucketMetadataImport(ctx, obj, "production", invalid); err != errUnknownBucketMetadataBundleVersion {
+		t.Fatalf("Expected %v, got %v", errUnknownBucketMetadataBundleVersion, err)
+	}
+
+	appliers, err := prepareBucketMetadataImport(ctx, obj, "production", bundle)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, apply := range appliers {
+		if err = apply(); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	gotTrashConfig, err := getBucketTrashConfig(ctx, obj, "production")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if *gotTrashConfig != trashConfig {
+		t.Fatalf("Expected trash config %v, got %v", trashConfig, *gotTrashConfig)
+	}
+	gotContentTypeConfig, err := getBucketContentTypeConfig(ctx, obj, "production")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(*gotContentTypeConfig, contentTypeConfig) {
+		t.Fatalf("Expected content type config %v, got %v", contentTypeConfig, *gotContentTypeConfig)
+	}
+}
@@ -0,0 +1,207 @@
+/*
+ * MinIO Cloud Storage, (C) 2020 MinIO, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"path"
+	"strings"
+	"sync"
+
+	"github.com/minio/minio/pkg/madmin"
+)
+
+const (
+	// Bucket modes config file.
+	bucketModesConfigFile = "bucket-modes.json"
+)
+
+// BucketModeSys - holds the modes of the buckets which are not in
+// read-write mode, used during migrations and incident response.
+type BucketModeSys struct {
+	sync.RWMutex
+	modes map[string]madmin.BucketMode
+}
+
+// Get - returns the mode of bucket.
+func (sys *BucketModeSys) Get(bucket string) madmin.BucketMode {
+	sys.RLock()
+	defer sys.RUnlock()
+
+	if mode, ok := sys.modes[bucket]; ok {
+		return mode
+	}
+	return madmin.BucketModeReadWrite
+}
+
+// GetAll - returns the modes of the buckets which are not in read-write
+// mode.
+func (sys *BucketModeSys) GetAll() map[string]madmin.BucketMode {
+	sys.RLock()
+	defer sys.RUnlock()
+
+	modes := make(map[string]madmin.BucketMode, len(sys.modes))
+	for bucket, mode := range sys.modes {
+		modes[bucket] = mode
+	}
+	return modes
+}
+
+// Set - replaces the modes of the buckets.
+func (sys *BucketModeSys) Set(modes map[string]madmin.BucketMode) {
+	sys.Lock()
+	defer sys.Unlock()
+
+	sys.modes = make(map[string]madmin.BucketMode, len(modes))
+	for bucket, mode := range modes {
+		if mode != madmin.BucketModeReadWrite {
+			sys.modes[bucket] = mode
+		}
+	}
+}
+
+// Check - returns an error if the mode of bucket does not allow the
+// request, write is true for requests modifying the bucket.
+func (sys *BucketModeSys) Check(bucket string, write bool) error {
+	if sys == nil || bucket == "" {
+		return nil
+	}
+	switch sys.Get(bucket) {
+	case madmin.BucketModeSuspended:
+		return BucketSuspended{Bucket: bucket}
+	case madmin.BucketModeReadOnly:
+		if write {
+			return BucketReadOnly{Bucket: bucket}
+		}
+	}
+	return nil
+}
+
+// Load - loads the bucket modes from the backend.
+func (sys *BucketModeSys) Load(objAPI ObjectLayer) error {
+	if objAPI == nil {
+		return errServerNotInitialized
+	}
+
+	modes, err := readBucketModesConfig(context.Background(), objAPI)
+	if err != nil {
+		return err
+	}
+	sys.Set(modes)
+	return nil
+}
+
+// NewBucketModeSys - creates new bucket mode system, all buckets are in
+// read-write mode.
+func NewBucketModeSys() *BucketModeSys {
+	return &BucketModeSys{
+		modes: make(map[string]madmin.BucketMode),
+	}
+}
+
+// isWriteRequest - returns true if the S3 request modifies its bucket.
+func isWriteRequest(r *http.Request) bool {
+	switch r.Method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions:
+		return false
+	case http.MethodPost:
+		// SelectObjectContent only reads the object.
+		_, isSelect := r.URL.Query()["select"]
+		return !isSelect
+	}
+	return true
+}
+
+func readBucketModesConfig(ctx context.Context, objAPI ObjectLayer) (map[string]madmin.BucketMode, error) {
+	configFile := path.Join(minioConfigPrefix, bucketModesConfigFile)
+	data, err := readConfig(ctx, objAPI, configFile)
+	if err != nil {
+		if err == errConfigNotFound {
+			// All buckets are in read-write mode.
+			return nil, nil
+		}
+		return nil, err
+	}
+	var modes map[string]madmin.BucketMode
+	err = json.Unmarshal(data, &modes)
+	return modes, err
+}
+
+// saveBucketMode - saves the mode of bucket along with the modes of
+// the other buckets.
+func saveBucketMode(ctx context.Context, objAPI ObjectLayer, bucket string, mode madmin.BucketMode) error {
+	configFile := path.Join(minioConfigPrefix, bucketModesConfigFile)
+	modesLock := globalNSMutex.NewNSLock(ctx, minioMetaBucket, configFile)
+	if err := modesLock.GetLock(globalOperationTimeout); err != nil {
+		return err
+	}
+	defer modesLock.Unlock()
+
+	modes, err := readBucketModesConfig(ctx, objAPI)
+	if err != nil {
+		return err
+	}
+	if modes == nil {
+		modes = make(map[string]madmin.BucketMode)
+	}
+	if mode == madmin.BucketModeReadWrite {
+		delete(modes, bucket)
+	} else {
+		modes[bucket] = mode
+	}
+
+	data, err := json.Marshal(modes)
+	if err != nil {
+		return err
+	}
+	return saveConfig(ctx, objAPI, configFile, data)
+}
+
+// setBucketModeHandler - rejects the S3 requests which are not allowed
+// by the mode of their bucket. Browser requests are checked by the web
+// handlers.
+func setBucketModeHandler(h http.Handler) http.Handler {
+	return bucketModeHandler{h}
+}
+
+type bucketModeHandler struct {
+	handler http.Handler
+}
+
+func (h bucketModeHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if globalBucketModeSys == nil || guessIsHealthCheckReq(r) || guessIsMetricsReq(r) ||
+		guessIsRPCReq(r) || isAdminReq(r) || strings.HasPrefix(r.URL.Path, minioReservedBucketPath) {
+		h.handler.ServeHTTP(w, r)
+		return
+	}
+
+	var bucket string
+	if websiteBucket, ok := getWebsiteBucket(r.Host); ok {
+		bucket = websiteBucket
+	} else if resource, err := getResource(r.URL.Path, r.Host, globalDomainNames); err == nil {
+		bucket, _ = urlPath2BucketObjectName(resource)
+	}
+
+	if err := globalBucketModeSys.Check(bucket, isWriteRequest(r)); err != nil {
+		ctx := newContext(r, w, "BucketMode")
+		writeErrorResponse(ctx, w, toAPIError(ctx, err), r.URL, guessIsBrowserReq(r))
+		return
+	}
+	h.handler.ServeHTTP(w, r)
+}
@@ -0,0 +1,85 @@
+/*
+ * MinIO Cloud Storage, (C) 2020 MinIO, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/minio/minio/pkg/madmin"
+)
+
+func TestBucketModeSysCheck(t *testing.T) {
+	sys := NewBucketModeSys()
+	sys.Set(map[string]madmin.BucketMode{
+		"readonly":  madmin.BucketModeReadOnly,
+		"suspended": madmin.BucketModeSuspended,
+		"readwrite": madmin.BucketModeReadWrite,
+	})
+
+	if modes := sys.GetAll(); len(modes) != 2 {
+		t.Fatalf("Expected 2 bucket modes, got %v", modes)
+	}
+
+	testCases := []struct {
+		bucket      string
+		write       bool
+		expectedErr error
+	}{
+		{"readwrite", false, nil},
+		{"readwrite", true, nil},
+		{"unknown", true, nil},
+		{"readonly", false, nil},
+		{"readonly", true, BucketReadOnly{Bucket: "readonly"}},
+		{"suspended", false, BucketSuspended{Bucket: "suspended"}},
+		{"suspended", true, BucketSuspended{Bucket: "suspended"}},
+		{"", true, nil},
+	}
+	for i, testCase := range testCases {
+		if err := sys.Check(testCase.bucket, testCase.write); err != testCase.expectedErr {
+			t.Errorf("Test %d: expected %v, got %v", i+1, testCase.expectedErr, err)
+		}
+	}
+
+	var nilSys *BucketModeSys
+	if err := nilSys.Check("readonly", true); err != nil {
+		t.Errorf("Expected no error without bucket modes, got %v", err)
+	}
+}
+
+func TestIsWriteRequest(t *testing.T) {
+	testCases := []struct {
+		method   string
+		url      string
+		expected bool
+	}{
+		{http.MethodGet, "/bucket/object", false},
+		{http.MethodHead, "/bucket/object", false},
+		{http.MethodOptions, "/bucket/object", false},
+		{http.MethodPut, "/bucket/object", true},
+		{http.MethodDelete, "/bucket/object", true},
+		{http.MethodPost, "/bucket?delete", true},
+		{http.MethodPost, "/bucket/object?select&select-type=2", false},
+	}
+	for i, testCase := range testCases {
+		r := httptest.NewRequest(testCase.method, testCase.url, nil)
+		if got := isWriteRequest(r); got != testCase.expected {
+			t.Errorf("Test %d: expected %v, got %v", i+1, testCase.expected, got)
+		}
+	}
+}
@@ -0,0 +1,114 @@
+/*
+ * MinIO Cloud Storage, (C) 2020 MinIO, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+// PolicyTemplateParameter - a parameter of a policy template, its
+// value is substituted for {{name}} in the template policy.
+type PolicyTemplateParameter struct {
+	Name        string `json:"name"`
+	Description string `json:"description"`
+}
+
+// PolicyTemplate - a named bucket policy, the policy is applied with
+// SetBucketPolicyJSON once its parameters are substituted.
+type PolicyTemplate struct {
+	Name        string                    `json:"name"`
+	Description string                    `json:"description"`
+	Parameters  []PolicyTemplateParameter `json:"parameters"`
+	Policy      string                    `json:"policy"`
+}
+
+var (
+	policyTemplateBucket = PolicyTemplateParameter{
+		Name:        "bucket",
+		Description: "Name of the bucket",
+	}
+	policyTemplatePrefix = PolicyTemplateParameter{
+		Name:        "prefix",
+		Description: "Prefix of the objects, empty for the whole bucket",
+	}
+	policyTemplateExpiry = PolicyTemplateParameter{
+		Name:        "expiry",
+		Description: "Date after which access is denied in ISO 8601 format, such as 2020-12-31T00:00:00Z",
+	}
+)
+
+// policyTemplates - policy templates returned by ListPolicyTemplates.
+var policyTemplates = []PolicyTemplate{
+	{
+		Name:        "readonly-prefix",
+		Description: "Anyone can list and download the objects of a prefix",
+		Parameters:  []PolicyTemplateParameter{policyTemplateBucket, policyTemplatePrefix},
+		Policy: `{
+  "Version": "2012-10-17",
+  "Statement": [
+    {
+      "Effect": "Allow",
+      "Principal": {"AWS": ["*"]},
+      "Action": ["s3:GetBucketLocation"],
+      "Resource": ["arn:aws:s3:::{{bucket}}"]
+    },
+    {
+      "Effect": "Allow",
+      "Principal": {"AWS": ["*"]},
+      "Action": ["s3:ListBucket"],
+      "Resource": ["arn:aws:s3:::{{bucket}}"],
+      "Condition": {"StringLike": {"s3:prefix": ["{{prefix}}*"]}}
+    },
+    {
+      "Effect": "Allow",
+      "Principal": {"AWS": ["*"]},
+      "Action": ["s3:GetObject"],
+      "Resource": ["arn:aws:s3:::{{bucket}}/{{prefix}}*"]
+    }
+  ]
+}`,
+	},
+	{
+		Name:        "upload-only",
+		Description: "Anyone can upload objects to a prefix but not list or download them, like a drop box",
+		Parameters:  []PolicyTemplateParameter{policyTemplateBucket, policyTemplatePrefix},
+		Policy: `{
+  "Version": "2012-10-17",
+  "Statement": [
+    {
+      "Effect": "Allow",
+      "Principal": {"AWS": ["*"]},
+      "Action": ["s3:PutObject"],
+      "Resource": ["arn:aws:s3:::{{bucket}}/{{prefix}}*"]
+    }
+  ]
+}`,
+	},
+	{
+		Name:        "public-read-until",
+		Description: "Anyone can download the objects of a prefix until a date",
+		Parameters:  []PolicyTemplateParameter{policyTemplateBucket, policyTemplatePrefix, policyTemplateExpiry},
+		Policy: `{
+  "Version": "2012-10-17",
+  "Statement": [
+    {
+      "Effect": "Allow",
+      "Principal": {"AWS": ["*"]},
+      "Action": ["s3:GetObject"],
+      "Resource": ["arn:aws:s3:::{{bucket}}/{{prefix}}*"],
+      "Condition": {"DateLessThan": {"aws:CurrentTime": ["{{expiry}}"]}}
+    }
+  ]
+}`,
+	},
+}
@@ -0,0 +1,89 @@
+/*
+ * MinIO Cloud Storage, (C) 2020 MinIO, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/minio/minio/pkg/policy"
+)
+
+// Tests that the policy templates are valid bucket policies once their
+// parameters are substituted.
+func TestPolicyTemplates(t *testing.T) {
+	expiry := UTCNow().Add(time.Hour)
+	values := map[string]string{
+		"bucket": "testbucket",
+		"prefix": "public/",
+		"expiry": expiry.Format(time.RFC3339),
+	}
+	r := httptest.NewRequest("GET", "/testbucket/public/object", nil)
+
+	for _, template := range policyTemplates {
+		p := template.Policy
+		for _, param := range template.Parameters {
+			p = strings.Replace(p, "{{"+param.Name+"}}", values[param.Name], -1)
+		}
+		if strings.Contains(p, "{{") {
+			t.Fatalf("%s: unsubstituted parameters in %s", template.Name, p)
+		}
+		bucketPolicy, err := policy.ParseConfig(strings.NewReader(p), "testbucket")
+		if err != nil {
+			t.Fatalf("%s: %v", template.Name, err)
+		}
+
+		args := policy.Args{
+			Action:          policy.GetObjectAction,
+			BucketName:      "testbucket",
+			ObjectName:      "public/object",
+			ConditionValues: getConditionValues(r, "", ""),
+		}
+		expected := template.Name != "upload-only"
+		if allowed := bucketPolicy.IsAllowed(args); allowed != expected {
+			t.Errorf("%s: expected GetObject allowed %v, got %v", template.Name, expected, allowed)
+		}
+		args.ObjectName = "private/object"
+		if bucketPolicy.IsAllowed(args) {
+			t.Errorf("%s: expected GetObject outside of the prefix to be denied", template.Name)
+		}
+	}
+}
+
+// Tests that a public read policy expires.
+func TestPolicyTemplateExpiry(t *testing.T) {
+	p := policyTemplates[2].Policy
+	p = strings.Replace(p, "{{bucket}}", "testbucket", -1)
+	p = strings.Replace(p, "{{prefix}}", "", -1)
+	p = strings.Replace(p, "{{expiry}}", UTCNow().Add(-time.Hour).Format(time.RFC3339), -1)
+	bucketPolicy, err := policy.ParseConfig(strings.NewReader(p), "testbucket")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	r := httptest.NewRequest("GET", "/testbucket/object", nil)
+	if bucketPolicy.IsAllowed(policy.Args{
+		Action:          policy.GetObjectAction,
+		BucketName:      "testbucket",
+		ObjectName:      "object",
+		ConditionValues: getConditionValues(r, "", ""),
+	}) {
+		t.Fatal("Expected GetObject to be denied once the policy expired")
+	}
+}
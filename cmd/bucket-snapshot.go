@@ -0,0 +1,624 @@
+/*
+ * MinIO Cloud Storage, (C) 2019 MinIO, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"path"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/minio/minio-go/pkg/set"
+	miniogo "github.com/minio/minio-go/v6"
+	"github.com/minio/minio/cmd/crypto"
+	"github.com/minio/minio/cmd/logger"
+	"github.com/minio/minio/pkg/cron"
+	"github.com/minio/minio/pkg/hash"
+	"github.com/minio/minio/pkg/madmin"
+)
+
+const (
+	// Bucket snapshot configuration file.
+	bucketSnapshotConfig = "snapshot.json"
+
+	// Directory below the bucket config directory holding one
+	// sub-directory per snapshot with its info and manifest.
+	bucketSnapshotsPrefix      = "snapshots"
+	bucketSnapshotInfoFile     = "info.json"
+	bucketSnapshotManifestFile = "manifest.json"
+
+	// Snapshots are named after the scheduled minute.
+	bucketSnapshotNameFormat = "20060102T1504Z"
+
+	bucketSnapshotManifestVersion = "1"
+)
+
+var (
+	errBucketSnapshotNotFound       = errors.New("Specified bucket snapshot does not exist or is incomplete")
+	errBucketSnapshotConfigNotFound = errors.New("Bucket snapshot configuration does not exist")
+)
+
+// bucketSnapshotEntry - an object of a snapshot, the object data
+// is stored by the snapshot named in Snapshot, which is an earlier
+// snapshot for objects unchanged by an incremental snapshot.
+type bucketSnapshotEntry struct {
+	Name     string            `json:"name"`
+	ETag     string            `json:"etag"`
+	Size     int64             `json:"size"`
+	ModTime  time.Time         `json:"modTime"`
+	Metadata map[string]string `json:"metadata,omitempty"`
+	Snapshot string            `json:"snapshot"`
+}
+
+// bucketSnapshotManifest - lists all objects of a snapshot along with
+// the target holding their data.
+type bucketSnapshotManifest struct {
+	Version string                      `json:"version"`
+	Target  madmin.BucketSnapshotTarget `json:"target"`
+	Entries []bucketSnapshotEntry       `json:"entries"`
+}
+
+// parseBucketSnapshotConfig - parses and validates the snapshot
+// configuration of bucket.
+func parseBucketSnapshotConfig(bucket string, data []byte) (*madmin.BucketSnapshotConfig, error) {
+	var config madmin.BucketSnapshotConfig
+	if err := json.Unmarshal(data, &config); err != nil {
+		return nil, err
+	}
+	if _, err := cron.Parse(config.Schedule); err != nil {
+		return nil, err
+	}
+	target := config.Target
+	if !IsValidBucketName(target.Bucket) {
+		return nil, BucketNameInvalid{Bucket: target.Bucket}
+	}
+	if target.Endpoint == "" && target.Bucket == bucket {
+		return nil, errors.New("snapshot target bucket must differ from the bucket")
+	}
+	if target.Endpoint != "" && (target.AccessKey == "" || target.SecretKey == "") {
+		return nil, errors.New("remote snapshot target requires access and secret keys")
+	}
+	return &config, nil
+}
+
+func saveBucketSnapshotConfig(ctx context.Context, objAPI ObjectLayer, bucketName string, config *madmin.BucketSnapshotConfig) error {
+	data, err := json.Marshal(config)
+	if err != nil {
+		return err
+	}
+
+	// Construct path to snapshot.json for the given bucket.
+	configFile := path.Join(bucketConfigPrefix, bucketName, bucketSnapshotConfig)
+	return saveConfig(ctx, objAPI, configFile, data)
+}
+
+// getBucketSnapshotConfig - get bucket snapshot config for given bucket name.
+func getBucketSnapshotConfig(objAPI ObjectLayer, bucketName string) (*madmin.BucketSnapshotConfig, error) {
+	// Construct path to snapshot.json for the given bucket.
+	configFile := path.Join(bucketConfigPrefix, bucketName, bucketSnapshotConfig)
+	configData, err := readConfig(context.Background(), objAPI, configFile)
+	if err != nil {
+		if err == errConfigNotFound {
+			err = errBucketSnapshotConfigNotFound
+		}
+		return nil, err
+	}
+
+	return parseBucketSnapshotConfig(bucketName, configData)
+}
+
+func removeBucketSnapshotConfig(ctx context.Context, objAPI ObjectLayer, bucketName string) error {
+	// Construct path to snapshot.json for the given bucket.
+	configFile := path.Join(bucketConfigPrefix, bucketName, bucketSnapshotConfig)
+
+	if err := objAPI.DeleteObject(ctx, minioMetaBucket, configFile); err != nil {
+		if _, ok := err.(ObjectNotFound); !ok {
+			return err
+		}
+	}
+	return nil
+}
+
+// bucketSnapshotPath - returns the path of file of the given snapshot.
+func bucketSnapshotPath(bucket, snapshot, file string) string {
+	return path.Join(bucketConfigPrefix, bucket, bucketSnapshotsPrefix, snapshot, file)
+}
+
+// readBucketSnapshotFile - reads and decodes a file of a snapshot.
+func readBucketSnapshotFile(ctx context.Context, objAPI ObjectLayer, bucket, snapshot, file string, v interface{}) error {
+	data, err := readConfig(ctx, objAPI, bucketSnapshotPath(bucket, snapshot, file))
+	if err != nil {
+		if err == errConfigNotFound {
+			err = errBucketSnapshotNotFound
+		}
+		return err
+	}
+	return json.Unmarshal(data, v)
+}
+
+// saveBucketSnapshotFile - encodes and saves a file of a snapshot.
+func saveBucketSnapshotFile(ctx context.Context, objAPI ObjectLayer, bucket, snapshot, file string, v interface{}) error {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+	return saveConfig(ctx, objAPI, bucketSnapshotPath(bucket, snapshot, file), data)
+}
+
+// listBucketSnapshots - returns all snapshots of bucket, oldest first.
+// Snapshots are kept after the bucket is deleted, so that it can be
+// restored from them.
+func listBucketSnapshots(ctx context.Context, objAPI ObjectLayer, bucket string) ([]madmin.BucketSnapshot, error) {
+	prefix := path.Join(bucketConfigPrefix, bucket, bucketSnapshotsPrefix) + SlashSeparator
+	snapshots := []madmin.BucketSnapshot{}
+	marker := ""
+	for {
+		result, err := objAPI.ListObjects(ctx, minioMetaBucket, prefix, marker, SlashSeparator, maxObjectList)
+		if err != nil {
+			return nil, err
+		}
+		for _, p := range result.Prefixes {
+			name := strings.TrimSuffix(strings.TrimPrefix(p, prefix), SlashSeparator)
+			var snapshot madmin.BucketSnapshot
+			if err = readBucketSnapshotFile(ctx, objAPI, bucket, name, bucketSnapshotInfoFile, &snapshot); err != nil {
+				if err == errBucketSnapshotNotFound {
+					continue
+				}
+				return nil, err
+			}
+			snapshots = append(snapshots, snapshot)
+		}
+		if !result.IsTruncated {
+			return snapshots, nil
+		}
+		marker = result.NextMarker
+	}
+}
+
+// bucketSnapshotTarget - destination of the object data of snapshots.
+type bucketSnapshotTarget interface {
+	Put(ctx context.Context, object string, r io.Reader, size int64, metadata map[string]string) error
+	Get(ctx context.Context, object string) (io.ReadCloser, error)
+}
+
+// localSnapshotTarget - stores snapshots in a bucket of this deployment.
+type localSnapshotTarget struct {
+	objAPI ObjectLayer
+	bucket string
+}
+
+func (t localSnapshotTarget) Put(ctx context.Context, object string, r io.Reader, size int64, metadata map[string]string) error {
+	reader, err := hash.NewReader(r, size, "", "", size, globalCLIContext.StrictS3Compat)
+	if err != nil {
+		return err
+	}
+	_, err = t.objAPI.PutObject(ctx, t.bucket, object, NewPutObjReader(reader, nil, nil), ObjectOptions{UserDefined: metadata})
+	return err
+}
+
+func (t localSnapshotTarget) Get(ctx context.Context, object string) (io.ReadCloser, error) {
+	return t.objAPI.GetObjectNInfo(ctx, t.bucket, object, nil, http.Header{}, readLock, ObjectOptions{})
+}
+
+// remoteSnapshotTarget - stores snapshots in a bucket of an S3 endpoint.
+type remoteSnapshotTarget struct {
+	client *miniogo.Client
+	bucket string
+}
+
+func (t remoteSnapshotTarget) Put(ctx context.Context, object string, r io.Reader, size int64, metadata map[string]string) error {
+	opts := miniogo.PutObjectOptions{
+		ContentType:  metadata["content-type"],
+		UserMetadata: make(map[string]string),
+	}
+	for k, v := range metadata {
+		if k != "content-type" {
+			opts.UserMetadata[k] = v
+		}
+	}
+	_, err := t.client.PutObjectWithContext(ctx, t.bucket, object, r, size, opts)
+	return err
+}
+
+func (t remoteSnapshotTarget) Get(ctx context.Context, object string) (io.ReadCloser, error) {
+	return t.client.GetObjectWithContext(ctx, t.bucket, object, miniogo.GetObjectOptions{})
+}
+
+func newBucketSnapshotTarget(objAPI ObjectLayer, target madmin.BucketSnapshotTarget) (bucketSnapshotTarget, error) {
+	if target.Endpoint == "" {
+		return localSnapshotTarget{objAPI: objAPI, bucket: target.Bucket}, nil
+	}
+	client, err := miniogo.New(target.Endpoint, target.AccessKey, target.SecretKey, target.Secure)
+	if err != nil {
+		return nil, err
+	}
+	client.SetCustomTransport(NewCustomHTTPTransport())
+	return remoteSnapshotTarget{client: client, bucket: target.Bucket}, nil
+}
+
+// bucketSnapshotObject - returns the name of the object holding the
+// data of object in the given snapshot of bucket.
+func bucketSnapshotObject(target madmin.BucketSnapshotTarget, bucket, snapshot, object string) string {
+	return target.Prefix + path.Join(bucket, snapshot, object)
+}
+
+// bucketSnapshotMetadata - returns the content type and user metadata
+// of an object, which are kept by snapshots.
+func bucketSnapshotMetadata(objInfo ObjectInfo) map[string]string {
+	metadata := make(map[string]string)
+	if objInfo.ContentType != "" {
+		metadata["content-type"] = objInfo.ContentType
+	}
+	for k, v := range objInfo.UserDefined {
+		if strings.HasPrefix(strings.ToLower(k), "x-amz-meta-") {
+			metadata[k] = v
+		}
+	}
+	return metadata
+}
+
+// latestBucketSnapshotManifest - returns the manifest of the latest
+// completed snapshot of bucket, nil if there is none.
+func latestBucketSnapshotManifest(ctx context.Context, objAPI ObjectLayer, bucket string) (*bucketSnapshotManifest, error) {
+	snapshots, err := listBucketSnapshots(ctx, objAPI, bucket)
+	if err != nil {
+		return nil, err
+	}
+	for i := len(snapshots) - 1; i >= 0; i-- {
+		if snapshots[i].EndTime.IsZero() || snapshots[i].Error != "" {
+			continue
+		}
+		var manifest bucketSnapshotManifest
+		if err = readBucketSnapshotFile(ctx, objAPI, bucket, snapshots[i].Name, bucketSnapshotManifestFile, &manifest); err != nil {
+			return nil, err
+		}
+		return &manifest, nil
+	}
+	return nil, nil
+}
+
+// takeBucketSnapshot - copies the current objects of bucket to the
+// snapshot target, objects unchanged since the latest snapshot are
+// not copied again by incremental snapshots. SSE encrypted objects
+// are bound to their bucket and name, they are skipped.
+func takeBucketSnapshot(ctx context.Context, objAPI ObjectLayer, bucket, name string, config madmin.BucketSnapshotConfig) (snapshot madmin.BucketSnapshot, err error) {
+	snapshot = madmin.BucketSnapshot{
+		Name:        name,
+		Bucket:      bucket,
+		StartTime:   UTCNow(),
+		Incremental: config.Incremental,
+	}
+	// Record the snapshot right away, so that it is not
+	// taken again by another server.
+	if err = saveBucketSnapshotFile(ctx, objAPI, bucket, name, bucketSnapshotInfoFile, snapshot); err != nil {
+		return snapshot, err
+	}
+	defer func() {
+		snapshot.EndTime = UTCNow()
+		if err != nil {
+			snapshot.Error = err.Error()
+		}
+		if serr := saveBucketSnapshotFile(ctx, objAPI, bucket, name, bucketSnapshotInfoFile, snapshot); serr != nil && err == nil {
+			err = serr
+		}
+	}()
+
+	target, err := newBucketSnapshotTarget(objAPI, config.Target)
+	if err != nil {
+		return snapshot, err
+	}
+
+	previous := make(map[string]bucketSnapshotEntry)
+	if config.Incremental {
+		manifest, err := latestBucketSnapshotManifest(ctx, objAPI, bucket)
+		if err != nil {
+			return snapshot, err
+		}
+		// Data of a previous target can not be referenced.
+		if manifest != nil && manifest.Target == config.Target {
+			for _, entry := range manifest.Entries {
+				previous[entry.Name] = entry
+			}
+		}
+	}
+
+	manifest := bucketSnapshotManifest{
+		Version: bucketSnapshotManifestVersion,
+		Target:  config.Target,
+		Entries: []bucketSnapshotEntry{},
+	}
+	marker := ""
+	for {
+		result, err := objAPI.ListObjects(ctx, bucket, "", marker, "", maxObjectList)
+		if err != nil {
+			return snapshot, err
+		}
+		for _, obj := range result.Objects {
+			if crypto.IsEncrypted(obj.UserDefined) {
+				snapshot.Skipped++
+				continue
+			}
+			entry := bucketSnapshotEntry{
+				Name:     obj.Name,
+				ETag:     obj.ETag,
+				Size:     obj.GetActualSize(),
+				ModTime:  obj.ModTime,
+				Metadata: bucketSnapshotMetadata(obj),
+				Snapshot: name,
+			}
+			if prev, ok := previous[obj.Name]; ok && prev.ETag == entry.ETag && prev.Size == entry.Size {
+				manifest.Entries = append(manifest.Entries, prev)
+				continue
+			}
+			if err = copyToBucketSnapshot(ctx, objAPI, target, config.Target, bucket, entry); err != nil {
+				if isErrObjectNotFound(err) {
+					// Deleted since listed.
+					continue
+				}
+				return snapshot, err
+			}
+			manifest.Entries = append(manifest.Entries, entry)
+			snapshot.Copied++
+			snapshot.Size += entry.Size
+		}
+		if !result.IsTruncated {
+			break
+		}
+		marker = result.NextMarker
+	}
+	snapshot.Objects = int64(len(manifest.Entries))
+
+	err = saveBucketSnapshotFile(ctx, objAPI, bucket, name, bucketSnapshotManifestFile, manifest)
+	return snapshot, err
+}
+
+// copyToBucketSnapshot - copies the data of an object to the target.
+func copyToBucketSnapshot(ctx context.Context, objAPI ObjectLayer, target bucketSnapshotTarget, config madmin.BucketSnapshotTarget, bucket string, entry bucketSnapshotEntry) error {
+	gr, err := objAPI.GetObjectNInfo(ctx, bucket, entry.Name, nil, http.Header{}, readLock, ObjectOptions{})
+	if err != nil {
+		return err
+	}
+	defer gr.Close()
+
+	reader := globalBandwidthSys.NewReader(ctx, bucket, gr)
+	object := bucketSnapshotObject(config, bucket, entry.Snapshot, entry.Name)
+	return target.Put(ctx, object, reader, entry.Size, entry.Metadata)
+}
+
+// restoreBucketSnapshot - copies all objects of a snapshot of bucket
+// to targetBucket, existing objects of the same name are overwritten.
+func restoreBucketSnapshot(ctx context.Context, objAPI ObjectLayer, bucket, name, targetBucket string) (restore madmin.BucketSnapshotRestore, err error) {
+	var manifest bucketSnapshotManifest
+	if err = readBucketSnapshotFile(ctx, objAPI, bucket, name, bucketSnapshotManifestFile, &manifest); err != nil {
+		return restore, err
+	}
+	if _, err = objAPI.GetBucketInfo(ctx, targetBucket); err != nil {
+		return restore, err
+	}
+
+	source, err := newBucketSnapshotTarget(objAPI, manifest.Target)
+	if err != nil {
+		return restore, err
+	}
+	dest := localSnapshotTarget{objAPI: objAPI, bucket: targetBucket}
+	for _, entry := range manifest.Entries {
+		if err = ctx.Err(); err != nil {
+			return restore, err
+		}
+		rc, err := source.Get(ctx, bucketSnapshotObject(manifest.Target, bucket, entry.Snapshot, entry.Name))
+		if err != nil {
+			return restore, err
+		}
+		err = dest.Put(ctx, entry.Name, globalBandwidthSys.NewReader(ctx, targetBucket, rc), entry.Size, entry.Metadata)
+		rc.Close()
+		if err != nil {
+			return restore, err
+		}
+		restore.Objects++
+		restore.Size += entry.Size
+	}
+	return restore, nil
+}
+
+// BucketSnapshotSys - Bucket snapshot subsystem. Takes the snapshots
+// of buckets at the minutes matching their schedules, a single server
+// takes a snapshot.
+type BucketSnapshotSys struct {
+	sync.RWMutex
+	bucketSnapshotMap map[string]madmin.BucketSnapshotConfig
+}
+
+// Set - sets snapshot config to given bucket name.
+func (sys *BucketSnapshotSys) Set(bucketName string, config madmin.BucketSnapshotConfig) {
+	sys.Lock()
+	defer sys.Unlock()
+
+	sys.bucketSnapshotMap[bucketName] = config
+}
+
+// Get - gets snapshot config associated to a given bucket name.
+func (sys *BucketSnapshotSys) Get(bucketName string) (config madmin.BucketSnapshotConfig, ok bool) {
+	sys.RLock()
+	defer sys.RUnlock()
+
+	config, ok = sys.bucketSnapshotMap[bucketName]
+	return config, ok
+}
+
+// Remove - removes snapshot config for given bucket name.
+func (sys *BucketSnapshotSys) Remove(bucketName string) {
+	sys.Lock()
+	defer sys.Unlock()
+
+	delete(sys.bucketSnapshotMap, bucketName)
+}
+
+// NewBucketSnapshotSys - creates new bucket snapshot system.
+func NewBucketSnapshotSys() *BucketSnapshotSys {
+	return &BucketSnapshotSys{
+		bucketSnapshotMap: make(map[string]madmin.BucketSnapshotConfig),
+	}
+}
+
+// Init - initializes bucket snapshot system from snapshot.json of all buckets.
+func (sys *BucketSnapshotSys) Init(objAPI ObjectLayer) error {
+	if objAPI == nil {
+		return errServerNotInitialized
+	}
+
+	defer func() {
+		// Refresh BucketSnapshotSys and take scheduled snapshots in background.
+		go func() {
+			refreshTicker := time.NewTicker(globalRefreshBucketSnapshotInterval)
+			defer refreshTicker.Stop()
+			scheduleTicker := time.NewTicker(time.Minute)
+			defer scheduleTicker.Stop()
+			for {
+				select {
+				case <-GlobalServiceDoneCh:
+					return
+				case <-refreshTicker.C:
+					sys.refresh(objAPI)
+				case t := <-scheduleTicker.C:
+					sys.schedule(objAPI, t.UTC().Truncate(time.Minute))
+				}
+			}
+		}()
+	}()
+
+	doneCh := make(chan struct{})
+	defer close(doneCh)
+
+	// Initializing bucket snapshot needs a retry mechanism for
+	// the following reasons:
+	//  - Read quorum is lost just after the initialization
+	//    of the object layer.
+	for range newRetryTimerSimple(doneCh) {
+		// Load BucketSnapshotSys once during boot.
+		if err := sys.refresh(objAPI); err != nil {
+			if err == errDiskNotFound ||
+				strings.Contains(err.Error(), InsufficientReadQuorum{}.Error()) ||
+				strings.Contains(err.Error(), InsufficientWriteQuorum{}.Error()) {
+				logger.Info("Waiting for bucket snapshot subsystem to be initialized..")
+				continue
+			}
+			return err
+		}
+		break
+	}
+	return nil
+}
+
+// schedule - starts the snapshots of all buckets whose schedule
+// matches the minute at.
+func (sys *BucketSnapshotSys) schedule(objAPI ObjectLayer, at time.Time) {
+	sys.RLock()
+	defer sys.RUnlock()
+
+	for bucket, config := range sys.bucketSnapshotMap {
+		if s, err := cron.Parse(config.Schedule); err == nil && s.Matches(at) {
+			go sys.snapshot(objAPI, bucket, at)
+		}
+	}
+}
+
+// snapshot - takes the snapshot of bucket scheduled at the minute at,
+// unless another server does.
+func (sys *BucketSnapshotSys) snapshot(objAPI ObjectLayer, bucket string, at time.Time) {
+	reqInfo := (&logger.ReqInfo{}).AppendTags("bucket", bucket)
+	ctx := logger.SetReqInfo(GlobalContext, reqInfo)
+
+	zeroDuration := time.Millisecond
+	zeroDynamicTimeout := newDynamicTimeout(zeroDuration, zeroDuration)
+
+	// Lock to avoid concurrent snapshots of the bucket from other nodes
+	snapshotLock := globalNSMutex.NewNSLock(ctx, "system", path.Join("bucket-snapshot", bucket))
+	if err := snapshotLock.GetLock(zeroDynamicTimeout); err != nil {
+		return
+	}
+	defer snapshotLock.Unlock()
+
+	// The cached config may be stale, take the snapshot only if the
+	// current schedule is due and the snapshot was not taken yet.
+	config, err := getBucketSnapshotConfig(objAPI, bucket)
+	if err != nil {
+		if err == errBucketSnapshotConfigNotFound {
+			sys.Remove(bucket)
+			return
+		}
+		logger.LogIf(ctx, err)
+		return
+	}
+	if s, err := cron.Parse(config.Schedule); err != nil || !s.Matches(at) {
+		return
+	}
+	name := at.Format(bucketSnapshotNameFormat)
+	var snapshot madmin.BucketSnapshot
+	if err = readBucketSnapshotFile(ctx, objAPI, bucket, name, bucketSnapshotInfoFile, &snapshot); err != errBucketSnapshotNotFound {
+		logger.LogIf(ctx, err)
+		return
+	}
+
+	_, err = takeBucketSnapshot(ctx, objAPI, bucket, name, *config)
+	logger.LogIf(ctx, err)
+}
+
+// Refresh BucketSnapshotSys.
+func (sys *BucketSnapshotSys) refresh(objAPI ObjectLayer) error {
+	buckets, err := objAPI.ListBuckets(context.Background())
+	if err != nil {
+		logger.LogIf(context.Background(), err)
+		return err
+	}
+	sys.removeDeletedBuckets(buckets)
+	for _, bucket := range buckets {
+		config, err := getBucketSnapshotConfig(objAPI, bucket.Name)
+		if err != nil {
+			if err == errBucketSnapshotConfigNotFound {
+				sys.Remove(bucket.Name)
+			}
+			continue
+		}
+
+		sys.Set(bucket.Name, *config)
+	}
+
+	return nil
+}
+
+// removeDeletedBuckets - to handle a corner case where we have cached the snapshot config
+// for a deleted bucket. i.e if we miss a delete-bucket notification we should delete the
+// corresponding bucket snapshot config during sys.refresh()
+func (sys *BucketSnapshotSys) removeDeletedBuckets(bucketInfos []BucketInfo) {
+	buckets := set.NewStringSet()
+	for _, info := range bucketInfos {
+		buckets.Add(info.Name)
+	}
+	sys.Lock()
+	defer sys.Unlock()
+
+	for bucket := range sys.bucketSnapshotMap {
+		if !buckets.Contains(bucket) {
+			delete(sys.bucketSnapshotMap, bucket)
+		}
+	}
+}
@@ -0,0 +1,169 @@
+/*
+ * MinIO Cloud Storage, (C) 2019 MinIO, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"testing"
+
+	"github.com/minio/minio/cmd/crypto"
+	"github.com/minio/minio/pkg/madmin"
+)
+
+func TestParseBucketSnapshotConfig(t *testing.T) {
+	testCases := []struct {
+		config  string
+		success bool
+	}{
+		{`{"schedule": "0 2 * * *", "target": {"bucket": "backups"}}`, true},
+		{`{"schedule": "0 2 * * *", "incremental": true, "target": {"bucket": "backups", "prefix": "snapshots/"}}`, true},
+		{`{"schedule": "0 2 * * *", "target": {"endpoint": "s3.amazonaws.com", "accessKey": "access", "secretKey": "secret", "bucket": "bucket"}}`, true},
+		// Invalid schedule.
+		{`{"schedule": "0 2 * *", "target": {"bucket": "backups"}}`, false},
+		// Invalid target bucket.
+		{`{"schedule": "0 2 * * *", "target": {"bucket": "b"}}`, false},
+		// Snapshot into the bucket itself.
+		{`{"schedule": "0 2 * * *", "target": {"bucket": "bucket"}}`, false},
+		// Remote target without credentials.
+		{`{"schedule": "0 2 * * *", "target": {"endpoint": "s3.amazonaws.com", "bucket": "bucket"}}`, false},
+		{`{"schedule": 1}`, false},
+	}
+	for i, testCase := range testCases {
+		_, err := parseBucketSnapshotConfig("bucket", []byte(testCase.config))
+		if testCase.success != (err == nil) {
+			t.Errorf("Test %d: expected success %v, got %v", i+1, testCase.success, err)
+		}
+	}
+}
+
+// Tests taking full and incremental snapshots and restoring them.
+func TestBucketSnapshot(t *testing.T) {
+	prevKMS := GlobalKMS
+	prevBandwidthSys := globalBandwidthSys
+	defer func() {
+		GlobalKMS = prevKMS
+		globalBandwidthSys = prevBandwidthSys
+	}()
+	GlobalKMS = crypto.NewKMS([32]byte{1})
+	globalBandwidthSys = NewBandwidthSys()
+
+	obj, fsDir, err := prepareFS()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(fsDir)
+
+	ctx := context.Background()
+	bucket, targetBucket, restoreBucket := "bucket", "backups", "restored"
+	for _, b := range []string{bucket, targetBucket, restoreBucket} {
+		if err = obj.MakeBucketWithLocation(ctx, b, ""); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	putObject := func(object, data string, metadata map[string]string) {
+		t.Helper()
+		if _, err := obj.PutObject(ctx, bucket, object, mustGetPutObjReader(t, bytes.NewReader([]byte(data)), int64(len(data)), "", ""), ObjectOptions{UserDefined: metadata}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	putObject("a", "hello", map[string]string{"content-type": "text/plain", "X-Amz-Meta-Color": "blue"})
+	putObject("dir/b", "world", nil)
+	metadata := map[string]string{}
+	if _, err = newEncryptMetadata(nil, bucket, "encrypted", metadata, true); err != nil {
+		t.Fatal(err)
+	}
+	putObject("encrypted", "secret", metadata)
+
+	config := madmin.BucketSnapshotConfig{
+		Schedule:    "* * * * *",
+		Incremental: true,
+		Target:      madmin.BucketSnapshotTarget{Bucket: targetBucket, Prefix: "snapshots/"},
+	}
+	snapshot, err := takeBucketSnapshot(ctx, obj, bucket, "20191030T0200Z", config)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if snapshot.Objects != 2 || snapshot.Copied != 2 || snapshot.Skipped != 1 || snapshot.EndTime.IsZero() {
+		t.Fatalf("Unexpected snapshot %v", snapshot)
+	}
+
+	putObject("a", "hello again", nil)
+	snapshot, err = takeBucketSnapshot(ctx, obj, bucket, "20191031T0200Z", config)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if snapshot.Objects != 2 || snapshot.Copied != 1 {
+		t.Fatalf("Expected only the changed object to be copied, got %v", snapshot)
+	}
+
+	// Metadata is kept by snapshots.
+	if _, err = restoreBucketSnapshot(ctx, obj, bucket, "20191030T0200Z", restoreBucket); err != nil {
+		t.Fatal(err)
+	}
+	objInfo, err := obj.GetObjectInfo(ctx, restoreBucket, "a", ObjectOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if objInfo.ContentType != "text/plain" || objInfo.UserDefined["X-Amz-Meta-Color"] != "blue" {
+		t.Fatalf("Unexpected restored metadata %v", objInfo.UserDefined)
+	}
+
+	snapshots, err := listBucketSnapshots(ctx, obj, bucket)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(snapshots) != 2 || snapshots[0].Name != "20191030T0200Z" || snapshots[1].Name != "20191031T0200Z" {
+		t.Fatalf("Unexpected snapshots %v", snapshots)
+	}
+
+	if _, err = restoreBucketSnapshot(ctx, obj, bucket, "20191101T0200Z", restoreBucket); err != errBucketSnapshotNotFound {
+		t.Fatalf("Expected %v, got %v", errBucketSnapshotNotFound, err)
+	}
+
+	testCases := []struct {
+		snapshot string
+		objects  map[string]string
+	}{
+		{"20191030T0200Z", map[string]string{"a": "hello", "dir/b": "world"}},
+		{"20191031T0200Z", map[string]string{"a": "hello again", "dir/b": "world"}},
+	}
+	for i, testCase := range testCases {
+		restore, err := restoreBucketSnapshot(ctx, obj, bucket, testCase.snapshot, restoreBucket)
+		if err != nil {
+			t.Fatalf("Test %d: %v", i+1, err)
+		}
+		if restore.Objects != 2 {
+			t.Fatalf("Test %d: expected 2 restored objects, got %d", i+1, restore.Objects)
+		}
+		for object, data := range testCase.objects {
+			var buffer bytes.Buffer
+			if err = obj.GetObject(ctx, restoreBucket, object, 0, -1, &buffer, "", ObjectOptions{}); err != nil {
+				t.Fatalf("Test %d: %v", i+1, err)
+			}
+			if buffer.String() != data {
+				t.Fatalf("Test %d: expected %s to be %q, got %q", i+1, object, data, buffer.String())
+			}
+		}
+	}
+
+	if _, err = obj.GetObjectInfo(ctx, restoreBucket, "encrypted", ObjectOptions{}); !isErrObjectNotFound(err) {
+		t.Fatalf("Expected encrypted object not to be restored, got %v", err)
+	}
+}
@@ -0,0 +1,235 @@
+/*
+ * MinIO Cloud Storage, (C) 2019 MinIO, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"path"
+	"strings"
+	"time"
+
+	"github.com/minio/minio/cmd/crypto"
+	"github.com/minio/minio/cmd/logger"
+	"github.com/minio/minio/pkg/hash"
+)
+
+// Objects removed through the browser from a bucket with the trash
+// enabled are moved to .minio.sys/trash/<bucket>/<object>, they may be
+// restored until they expire after the retention period of the bucket.
+// The trash keeps the last removed copy of an object.
+
+const (
+	// Bucket trash configuration file.
+	bucketTrashConfig = "trash.json"
+
+	// Prefix of the trash in the meta bucket.
+	trashPrefix = "trash"
+
+	// Interval at which expired objects are purged from the trash.
+	trashPurgeInterval = time.Hour
+)
+
+var errTrashEncryptedObject = errors.New("Encrypted objects cannot be moved to the trash")
+
+// BucketTrashConfig - trash configuration of a bucket.
+type BucketTrashConfig struct {
+	// Number of days removed objects are kept in the trash.
+	RetentionDays int `json:"retentionDays"`
+}
+
+// trashEntry - an object in the trash of a bucket.
+type trashEntry struct {
+	ObjectInfo
+	// Time the object was removed.
+	DeletedAt time.Time
+}
+
+func saveBucketTrashConfig(ctx context.Context, objAPI ObjectLayer, bucketName string, config BucketTrashConfig) error {
+	data, err := json.Marshal(config)
+	if err != nil {
+		return err
+	}
+
+	// Construct path to trash.json for the given bucket.
+	configFile := path.Join(bucketConfigPrefix, bucketName, bucketTrashConfig)
+	return saveConfig(ctx, objAPI, configFile, data)
+}
+
+// getBucketTrashConfig - get bucket trash config for given bucket name,
+// returns errConfigNotFound if the trash is not enabled.
+func getBucketTrashConfig(ctx context.Context, objAPI ObjectLayer, bucketName string) (*BucketTrashConfig, error) {
+	// Construct path to trash.json for the given bucket.
+	configFile := path.Join(bucketConfigPrefix, bucketName, bucketTrashConfig)
+	configData, err := readConfig(ctx, objAPI, configFile)
+	if err != nil {
+		return nil, err
+	}
+
+	var config BucketTrashConfig
+	if err = json.Unmarshal(configData, &config); err != nil {
+		return nil, err
+	}
+	return &config, nil
+}
+
+func removeBucketTrashConfig(ctx context.Context, objAPI ObjectLayer, bucketName string) error {
+	// Construct path to trash.json for the given bucket.
+	configFile := path.Join(bucketConfigPrefix, bucketName, bucketTrashConfig)
+
+	if err := objAPI.DeleteObject(ctx, minioMetaBucket, configFile); err != nil {
+		if _, ok := err.(ObjectNotFound); !ok {
+			return err
+		}
+	}
+	return nil
+}
+
+// Returns the prefix of the trash of a bucket in the meta bucket.
+func getTrashBucketPrefix(bucket string) string {
+	return trashPrefix + SlashSeparator + bucket + SlashSeparator
+}
+
+// copyObjectData - copies the data, content type and user metadata of
+// an unencrypted object to another bucket.
+func copyObjectData(ctx context.Context, objAPI ObjectLayer, srcBucket, srcObject, dstBucket, dstObject string) (ObjectInfo, error) {
+	gr, err := objAPI.GetObjectNInfo(ctx, srcBucket, srcObject, nil, http.Header{}, readLock, ObjectOptions{})
+	if err != nil {
+		return ObjectInfo{}, err
+	}
+	defer gr.Close()
+
+	size := gr.ObjInfo.GetActualSize()
+	reader, err := hash.NewReader(gr, size, "", "", size, globalCLIContext.StrictS3Compat)
+	if err != nil {
+		return ObjectInfo{}, err
+	}
+	metadata := bucketSnapshotMetadata(gr.ObjInfo)
+	return objAPI.PutObject(ctx, dstBucket, dstObject, NewPutObjReader(reader, nil, nil), ObjectOptions{UserDefined: metadata})
+}
+
+// moveToTrash - copies an object to the trash of its bucket before it
+// is removed, encrypted objects are not copied as their keys are bound
+// to their names.
+func moveToTrash(ctx context.Context, objAPI ObjectLayer, bucket, object string) error {
+	objInfo, err := objAPI.GetObjectInfo(ctx, bucket, object, ObjectOptions{})
+	if err != nil {
+		return err
+	}
+	if crypto.IsEncrypted(objInfo.UserDefined) {
+		return errTrashEncryptedObject
+	}
+	_, err = copyObjectData(ctx, objAPI, bucket, object, minioMetaBucket, getTrashBucketPrefix(bucket)+object)
+	return err
+}
+
+// restoreFromTrash - moves an object from the trash back to its bucket,
+// an object of the same name in the bucket is overwritten.
+func restoreFromTrash(ctx context.Context, objAPI ObjectLayer, bucket, object string) (ObjectInfo, error) {
+	trashObject := getTrashBucketPrefix(bucket) + object
+	objInfo, err := copyObjectData(ctx, objAPI, minioMetaBucket, trashObject, bucket, object)
+	if err != nil {
+		if isErrObjectNotFound(err) {
+			return objInfo, ObjectNotFound{Bucket: bucket, Object: object}
+		}
+		return objInfo, err
+	}
+	if err = objAPI.DeleteObject(ctx, minioMetaBucket, trashObject); err != nil && !isErrObjectNotFound(err) {
+		return objInfo, err
+	}
+	return objInfo, nil
+}
+
+// listTrash - lists up to maxKeys objects below prefix in the trash of a
+// bucket, starting after marker.
+func listTrash(ctx context.Context, objAPI ObjectLayer, bucket, prefix, marker string, maxKeys int) (entries []trashEntry, nextMarker string, err error) {
+	bucketPrefix := getTrashBucketPrefix(bucket)
+	if marker != "" {
+		marker = bucketPrefix + marker
+	}
+	result, err := objAPI.ListObjects(ctx, minioMetaBucket, bucketPrefix+prefix, marker, "", maxKeys)
+	if err != nil {
+		return nil, "", err
+	}
+	for _, obj := range result.Objects {
+		entry := trashEntry{ObjectInfo: obj, DeletedAt: obj.ModTime}
+		entry.Bucket = bucket
+		entry.Name = strings.TrimPrefix(obj.Name, bucketPrefix)
+		entries = append(entries, entry)
+	}
+	if result.IsTruncated {
+		nextMarker = strings.TrimPrefix(result.NextMarker, bucketPrefix)
+	}
+	return entries, nextMarker, nil
+}
+
+// purgeTrash - removes the objects kept in the trash of all buckets
+// past their retention period. Objects left in the trash of buckets
+// which were removed or whose trash was disabled are removed.
+func purgeTrash(ctx context.Context, objAPI ObjectLayer) error {
+	var buckets []string
+	marker := ""
+	for {
+		result, err := objAPI.ListObjects(ctx, minioMetaBucket, trashPrefix+SlashSeparator, marker, SlashSeparator, maxObjectList)
+		if err != nil {
+			return err
+		}
+		for _, prefix := range result.Prefixes {
+			buckets = append(buckets, strings.TrimSuffix(strings.TrimPrefix(prefix, trashPrefix+SlashSeparator), SlashSeparator))
+		}
+		if !result.IsTruncated {
+			break
+		}
+		marker = result.NextMarker
+	}
+
+	for _, bucket := range buckets {
+		retentionDays := 0
+		config, err := getBucketTrashConfig(ctx, objAPI, bucket)
+		if err == nil {
+			retentionDays = config.RetentionDays
+		} else if err != errConfigNotFound {
+			logger.LogIf(ctx, err)
+			continue
+		}
+		expiry := UTCNow().AddDate(0, 0, -retentionDays)
+
+		marker = ""
+		for {
+			var entries []trashEntry
+			entries, marker, err = listTrash(ctx, objAPI, bucket, "", marker, maxObjectList)
+			if err != nil {
+				return err
+			}
+			for _, entry := range entries {
+				if entry.DeletedAt.After(expiry) {
+					continue
+				}
+				err = objAPI.DeleteObject(ctx, minioMetaBucket, getTrashBucketPrefix(bucket)+entry.Name)
+				if err != nil && !isErrObjectNotFound(err) {
+					return err
+				}
+			}
+			if marker == "" {
+				break
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,321 @@
+/*
+ * MinIO Cloud Storage, (C) 2019 MinIO, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"context"
+	"encoding/xml"
+	"io"
+	"net/http"
+
+	"github.com/gorilla/mux"
+	xhttp "github.com/minio/minio/cmd/http"
+	"github.com/minio/minio/cmd/logger"
+	"github.com/minio/minio/pkg/handlers"
+	"github.com/minio/minio/pkg/policy"
+	"github.com/minio/minio/pkg/website"
+)
+
+// PutBucketWebsiteHandler - This HTTP handler stores given bucket website configuration as per
+// https://docs.aws.amazon.com/AmazonS3/latest/API/RESTBucketPUTwebsite.html
+func (api objectAPIHandlers) PutBucketWebsiteHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := newContext(r, w, "PutBucketWebsite")
+
+	defer logger.AuditLog(w, r, "PutBucketWebsite", mustGetClaimsFromToken(r))
+
+	objAPI := api.ObjectAPI()
+	if objAPI == nil {
+		writeErrorResponse(ctx, w, errorCodes.ToAPIErr(ErrServerNotInitialized), r.URL, guessIsBrowserReq(r))
+		return
+	}
+
+	// Bucket website configuration is stored on the backend
+	// which is not available in gateway mode.
+	if globalIsGateway {
+		writeErrorResponse(ctx, w, errorCodes.ToAPIErr(ErrNotImplemented), r.URL, guessIsBrowserReq(r))
+		return
+	}
+
+	vars := mux.Vars(r)
+	bucket := vars["bucket"]
+
+	if s3Error := checkRequestAuthType(ctx, r, policy.PutBucketWebsiteAction, bucket, ""); s3Error != ErrNone {
+		writeErrorResponse(ctx, w, errorCodes.ToAPIErr(s3Error), r.URL, guessIsBrowserReq(r))
+		return
+	}
+
+	// Check if bucket exists.
+	if _, err := objAPI.GetBucketInfo(ctx, bucket); err != nil {
+		writeErrorResponse(ctx, w, toAPIError(ctx, err), r.URL, guessIsBrowserReq(r))
+		return
+	}
+
+	config, err := website.ParseConfig(io.LimitReader(r.Body, r.ContentLength))
+	if err != nil {
+		writeErrorResponse(ctx, w, errorCodes.ToAPIErr(ErrMalformedXML), r.URL, guessIsBrowserReq(r))
+		return
+	}
+
+	if err = saveBucketWebsiteConfig(ctx, objAPI, bucket, config); err != nil {
+		writeErrorResponse(ctx, w, toAPIError(ctx, err), r.URL, guessIsBrowserReq(r))
+		return
+	}
+
+	globalBucketWebsiteSys.Set(bucket, *config)
+	globalNotificationSys.SetBucketWebsite(ctx, bucket, *config)
+
+	// Success.
+	writeSuccessResponseHeadersOnly(w)
+}
+
+// GetBucketWebsiteHandler - This HTTP handler returns bucket website configuration.
+func (api objectAPIHandlers) GetBucketWebsiteHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := newContext(r, w, "GetBucketWebsite")
+
+	defer logger.AuditLog(w, r, "GetBucketWebsite", mustGetClaimsFromToken(r))
+
+	objAPI := api.ObjectAPI()
+	if objAPI == nil {
+		writeErrorResponse(ctx, w, errorCodes.ToAPIErr(ErrServerNotInitialized), r.URL, guessIsBrowserReq(r))
+		return
+	}
+
+	vars := mux.Vars(r)
+	bucket := vars["bucket"]
+
+	if s3Error := checkRequestAuthType(ctx, r, policy.GetBucketWebsiteAction, bucket, ""); s3Error != ErrNone {
+		writeErrorResponse(ctx, w, errorCodes.ToAPIErr(s3Error), r.URL, guessIsBrowserReq(r))
+		return
+	}
+
+	// Check if bucket exists.
+	if _, err := objAPI.GetBucketInfo(ctx, bucket); err != nil {
+		writeErrorResponse(ctx, w, toAPIError(ctx, err), r.URL, guessIsBrowserReq(r))
+		return
+	}
+
+	if globalIsGateway {
+		writeErrorResponse(ctx, w, errorCodes.ToAPIErr(ErrNoSuchWebsiteConfiguration), r.URL, guessIsBrowserReq(r))
+		return
+	}
+
+	config, err := getBucketWebsiteConfig(objAPI, bucket)
+	if err != nil {
+		if err == errConfigNotFound {
+			writeErrorResponse(ctx, w, errorCodes.ToAPIErr(ErrNoSuchWebsiteConfiguration), r.URL, guessIsBrowserReq(r))
+			return
+		}
+		writeErrorResponse(ctx, w, toAPIError(ctx, err), r.URL, guessIsBrowserReq(r))
+		return
+	}
+	config.XMLNS = "http://s3.amazonaws.com/doc/2006-03-01/"
+
+	websiteData, err := xml.Marshal(config)
+	if err != nil {
+		writeErrorResponse(ctx, w, toAPIError(ctx, err), r.URL, guessIsBrowserReq(r))
+		return
+	}
+
+	// Write website configuration to client.
+	writeSuccessResponseXML(w, websiteData)
+}
+
+// DeleteBucketWebsiteHandler - This HTTP handler removes bucket website configuration.
+func (api objectAPIHandlers) DeleteBucketWebsiteHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := newContext(r, w, "DeleteBucketWebsite")
+
+	defer logger.AuditLog(w, r, "DeleteBucketWebsite", mustGetClaimsFromToken(r))
+
+	objAPI := api.ObjectAPI()
+	if objAPI == nil {
+		writeErrorResponse(ctx, w, errorCodes.ToAPIErr(ErrServerNotInitialized), r.URL, guessIsBrowserReq(r))
+		return
+	}
+
+	vars := mux.Vars(r)
+	bucket := vars["bucket"]
+
+	if s3Error := checkRequestAuthType(ctx, r, policy.DeleteBucketWebsiteAction, bucket, ""); s3Error != ErrNone {
+		writeErrorResponse(ctx, w, errorCodes.ToAPIErr(s3Error), r.URL, guessIsBrowserReq(r))
+		return
+	}
+
+	// Check if bucket exists.
+	if _, err := objAPI.GetBucketInfo(ctx, bucket); err != nil {
+		writeErrorResponse(ctx, w, toAPIError(ctx, err), r.URL, guessIsBrowserReq(r))
+		return
+	}
+
+	if !globalIsGateway {
+		if err := removeBucketWebsiteConfig(ctx, objAPI, bucket); err != nil {
+			writeErrorResponse(ctx, w, toAPIError(ctx, err), r.URL, guessIsBrowserReq(r))
+			return
+		}
+		globalBucketWebsiteSys.Remove(bucket)
+		globalNotificationSys.RemoveBucketWebsite(ctx, bucket)
+	}
+
+	// Success.
+	writeSuccessNoContent(w)
+}
+
+// isWebsiteObjectPublic - returns whether the bucket policy allows
+// anonymous reads of the object, website endpoints only serve such
+// objects.
+func isWebsiteObjectPublic(r *http.Request, bucket, object string) bool {
+	return globalPolicySys.IsAllowed(policy.Args{
+		Action:          policy.GetObjectAction,
+		BucketName:      bucket,
+		ConditionValues: getConditionValues(r, "", ""),
+		IsOwner:         false,
+		ObjectName:      object,
+	})
+}
+
+// WebsiteHandler - serves the objects of a bucket on its website
+// endpoint as per its website configuration. Only anonymous GET and
+// HEAD requests are served, objects are served by the GetObject and
+// HeadObject handlers once the website configuration is applied.
+func (api objectAPIHandlers) WebsiteHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := newContext(r, w, "Website")
+
+	// Served objects are audited by the object handlers.
+	served := false
+	defer func() {
+		if !served {
+			logger.AuditLog(w, r, "Website", nil)
+		}
+	}()
+
+	objAPI := api.ObjectAPI()
+	if objAPI == nil {
+		writeWebsiteErrorResponse(ctx, w, r, errorCodes.ToAPIErr(ErrServerNotInitialized))
+		return
+	}
+
+	vars := mux.Vars(r)
+	bucket := vars["bucket"]
+	key := vars["object"]
+
+	if getRequestAuthType(r) != authTypeAnonymous {
+		writeWebsiteErrorResponse(ctx, w, r, errorCodes.ToAPIErr(ErrAccessDenied))
+		return
+	}
+
+	config, ok := globalBucketWebsiteSys.Get(bucket)
+	if !ok {
+		writeWebsiteErrorResponse(ctx, w, r, errorCodes.ToAPIErr(ErrNoSuchWebsiteConfiguration))
+		return
+	}
+
+	scheme := handlers.GetSourceScheme(r)
+	if scheme == "" {
+		scheme = getURLScheme(globalIsSSL)
+	}
+
+	if redirect := config.RedirectAllRequestsTo; redirect != nil {
+		http.Redirect(w, r, redirect.Location(key, scheme), http.StatusMovedPermanently)
+		return
+	}
+	if rule, ok := config.Match(key, 0); ok {
+		http.Redirect(w, r, rule.Location(key, scheme, r.Host), rule.StatusCode())
+		return
+	}
+
+	getObjectInfo := objAPI.GetObjectInfo
+	if api.CacheAPI() != nil {
+		getObjectInfo = api.CacheAPI().GetObjectInfo
+	}
+
+	object := key
+	if object == "" || hasSuffix(object, SlashSeparator) {
+		object += config.IndexDocument.Suffix
+	}
+
+	apiErr := noError
+	if !isWebsiteObjectPublic(r, bucket, object) {
+		apiErr = errorCodes.ToAPIErr(ErrAccessDenied)
+	} else if _, err := getObjectInfo(ctx, bucket, object, ObjectOptions{}); err != nil {
+		apiErr = toAPIError(ctx, err)
+		if _, ok := err.(ObjectNotFound); ok && object == key {
+			// Redirect requests for a directory without
+			// trailing slash to its index document.
+			index := object + SlashSeparator + config.IndexDocument.Suffix
+			if isWebsiteObjectPublic(r, bucket, index) {
+				if _, err = getObjectInfo(ctx, bucket, index, ObjectOptions{}); err == nil {
+					http.Redirect(w, r, SlashSeparator+object+SlashSeparator, http.StatusFound)
+					return
+				}
+			}
+		}
+	}
+	if apiErr != noError {
+		api.serveWebsiteError(ctx, w, r, bucket, key, scheme, config, apiErr)
+		return
+	}
+
+	served = true
+	vars["object"] = object
+	if r.Method == http.MethodHead {
+		api.HeadObjectHandler(w, r)
+		return
+	}
+	api.GetObjectHandler(w, r)
+}
+
+// serveWebsiteError - responds to a failed website request for key
+// with the redirect of the first routing rule matching the error, or
+// the error document of the bucket if it is public.
+func (api objectAPIHandlers) serveWebsiteError(ctx context.Context, w http.ResponseWriter, r *http.Request,
+	bucket, key, scheme string, config website.Config, apiErr APIError) {
+	if rule, ok := config.Match(key, apiErr.HTTPStatusCode); ok {
+		http.Redirect(w, r, rule.Location(key, scheme, r.Host), rule.StatusCode())
+		return
+	}
+
+	if config.ErrorDocument == nil || !isWebsiteObjectPublic(r, bucket, config.ErrorDocument.Key) {
+		writeWebsiteErrorResponse(ctx, w, r, apiErr)
+		return
+	}
+
+	getObjectNInfo := api.ObjectAPI().GetObjectNInfo
+	if api.CacheAPI() != nil {
+		getObjectNInfo = api.CacheAPI().GetObjectNInfo
+	}
+	gr, err := getObjectNInfo(ctx, bucket, config.ErrorDocument.Key, nil, http.Header{}, readLock, ObjectOptions{})
+	if err != nil {
+		writeWebsiteErrorResponse(ctx, w, r, apiErr)
+		return
+	}
+	defer gr.Close()
+
+	w.Header().Set(xhttp.ContentType, gr.ObjInfo.ContentType)
+	w.WriteHeader(apiErr.HTTPStatusCode)
+	if r.Method != http.MethodHead {
+		io.Copy(w, gr)
+	}
+}
+
+// writeWebsiteErrorResponse - writes the error of a website request,
+// HEAD requests get no response body.
+func writeWebsiteErrorResponse(ctx context.Context, w http.ResponseWriter, r *http.Request, apiErr APIError) {
+	if r.Method == http.MethodHead {
+		writeErrorResponseHeadersOnly(w, apiErr)
+		return
+	}
+	writeErrorResponse(ctx, w, apiErr, r.URL, false)
+}
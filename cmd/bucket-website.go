@@ -0,0 +1,196 @@
+/*
+ * MinIO Cloud Storage, (C) 2019 MinIO, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"encoding/xml"
+	"path"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/minio/minio-go/pkg/set"
+	"github.com/minio/minio/cmd/logger"
+	"github.com/minio/minio/pkg/website"
+)
+
+const (
+	// Bucket website configuration file.
+	bucketWebsiteConfig = "website.xml"
+)
+
+func saveBucketWebsiteConfig(ctx context.Context, objAPI ObjectLayer, bucketName string, config *website.Config) error {
+	data, err := xml.Marshal(config)
+	if err != nil {
+		return err
+	}
+
+	// Construct path to website.xml for the given bucket.
+	configFile := path.Join(bucketConfigPrefix, bucketName, bucketWebsiteConfig)
+	return saveConfig(ctx, objAPI, configFile, data)
+}
+
+// getBucketWebsiteConfig - get bucket website config for given bucket name.
+func getBucketWebsiteConfig(objAPI ObjectLayer, bucketName string) (*website.Config, error) {
+	// Construct path to website.xml for the given bucket.
+	configFile := path.Join(bucketConfigPrefix, bucketName, bucketWebsiteConfig)
+	configData, err := readConfig(context.Background(), objAPI, configFile)
+	if err != nil {
+		return nil, err
+	}
+
+	return website.ParseConfig(bytes.NewReader(configData))
+}
+
+func removeBucketWebsiteConfig(ctx context.Context, objAPI ObjectLayer, bucketName string) error {
+	// Construct path to website.xml for the given bucket.
+	configFile := path.Join(bucketConfigPrefix, bucketName, bucketWebsiteConfig)
+
+	if err := objAPI.DeleteObject(ctx, minioMetaBucket, configFile); err != nil {
+		if _, ok := err.(ObjectNotFound); !ok {
+			return err
+		}
+	}
+	return nil
+}
+
+// BucketWebsiteSys - Bucket website subsystem, holds the website
+// configuration of the buckets served by the website handler.
+type BucketWebsiteSys struct {
+	sync.RWMutex
+	bucketWebsiteMap map[string]website.Config
+}
+
+// Set - sets website config to given bucket name.
+func (sys *BucketWebsiteSys) Set(bucketName string, config website.Config) {
+	sys.Lock()
+	defer sys.Unlock()
+
+	sys.bucketWebsiteMap[bucketName] = config
+}
+
+// Get - gets website config associated to a given bucket name.
+func (sys *BucketWebsiteSys) Get(bucketName string) (config website.Config, ok bool) {
+	sys.RLock()
+	defer sys.RUnlock()
+
+	c, ok := sys.bucketWebsiteMap[bucketName]
+	return c, ok
+}
+
+// Remove - removes website config for given bucket name.
+func (sys *BucketWebsiteSys) Remove(bucketName string) {
+	sys.Lock()
+	defer sys.Unlock()
+
+	delete(sys.bucketWebsiteMap, bucketName)
+}
+
+// NewBucketWebsiteSys - creates new bucket website system.
+func NewBucketWebsiteSys() *BucketWebsiteSys {
+	return &BucketWebsiteSys{
+		bucketWebsiteMap: make(map[string]website.Config),
+	}
+}
+
+// Init - initializes bucket website system from website.xml of all buckets.
+func (sys *BucketWebsiteSys) Init(objAPI ObjectLayer) error {
+	if objAPI == nil {
+		return errServerNotInitialized
+	}
+
+	defer func() {
+		// Refresh BucketWebsiteSys in background.
+		go func() {
+			ticker := time.NewTicker(globalRefreshBucketWebsiteInterval)
+			defer ticker.Stop()
+			for {
+				select {
+				case <-GlobalServiceDoneCh:
+					return
+				case <-ticker.C:
+					sys.refresh(objAPI)
+				}
+			}
+		}()
+	}()
+
+	doneCh := make(chan struct{})
+	defer close(doneCh)
+
+	// Initializing bucket website needs a retry mechanism for
+	// the following reasons:
+	//  - Read quorum is lost just after the initialization
+	//    of the object layer.
+	for range newRetryTimerSimple(doneCh) {
+		// Load BucketWebsiteSys once during boot.
+		if err := sys.refresh(objAPI); err != nil {
+			if err == errDiskNotFound ||
+				strings.Contains(err.Error(), InsufficientReadQuorum{}.Error()) ||
+				strings.Contains(err.Error(), InsufficientWriteQuorum{}.Error()) {
+				logger.Info("Waiting for bucket website subsystem to be initialized..")
+				continue
+			}
+			return err
+		}
+		break
+	}
+	return nil
+}
+
+// Refresh BucketWebsiteSys.
+func (sys *BucketWebsiteSys) refresh(objAPI ObjectLayer) error {
+	buckets, err := objAPI.ListBuckets(context.Background())
+	if err != nil {
+		logger.LogIf(context.Background(), err)
+		return err
+	}
+	sys.removeDeletedBuckets(buckets)
+	for _, bucket := range buckets {
+		config, err := getBucketWebsiteConfig(objAPI, bucket.Name)
+		if err != nil {
+			if err == errConfigNotFound {
+				sys.Remove(bucket.Name)
+			}
+			continue
+		}
+
+		sys.Set(bucket.Name, *config)
+	}
+
+	return nil
+}
+
+// removeDeletedBuckets - to handle a corner case where we have cached the website config
+// for a deleted bucket. i.e if we miss a delete-bucket notification we should delete the
+// corresponding bucket website config during sys.refresh()
+func (sys *BucketWebsiteSys) removeDeletedBuckets(bucketInfos []BucketInfo) {
+	buckets := set.NewStringSet()
+	for _, info := range bucketInfos {
+		buckets.Add(info.Name)
+	}
+	sys.Lock()
+	defer sys.Unlock()
+
+	for bucket := range sys.bucketWebsiteMap {
+		if !buckets.Contains(bucket) {
+			delete(sys.bucketWebsiteMap, bucket)
+		}
+	}
+}
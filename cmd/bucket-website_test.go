@@ -0,0 +1,149 @@
+/*
+ * MinIO Cloud Storage, (C) 2019 MinIO, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gorilla/mux"
+	"github.com/minio/minio/pkg/auth"
+	"github.com/minio/minio/pkg/website"
+)
+
+// Tests that bucket website configuration is saved, loaded by refresh and removed.
+func TestBucketWebsiteSysRefresh(t *testing.T) {
+	objLayer, fsDir, err := prepareFS()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer removeRoots([]string{fsDir})
+
+	if err = objLayer.MakeBucketWithLocation(context.Background(), "bucket", ""); err != nil {
+		t.Fatal(err)
+	}
+
+	config := &website.Config{IndexDocument: &website.IndexDocument{Suffix: "index.html"}}
+	if err = saveBucketWebsiteConfig(context.Background(), objLayer, "bucket", config); err != nil {
+		t.Fatal(err)
+	}
+
+	sys := NewBucketWebsiteSys()
+	if err = sys.refresh(objLayer); err != nil {
+		t.Fatal(err)
+	}
+	if c, ok := sys.Get("bucket"); !ok || c.IndexDocument == nil || c.IndexDocument.Suffix != "index.html" {
+		t.Fatalf("Expected the saved website config, got %v (found %t)", c, ok)
+	}
+
+	if err = removeBucketWebsiteConfig(context.Background(), objLayer, "bucket"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err = getBucketWebsiteConfig(objLayer, "bucket"); err != errConfigNotFound {
+		t.Fatalf("Expected errConfigNotFound, got %v", err)
+	}
+	if err = sys.refresh(objLayer); err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := sys.Get("bucket"); ok {
+		t.Fatal("Expected the removed website config to be dropped")
+	}
+}
+
+// Tests that website endpoints serve public objects as per the website
+// configuration of their bucket.
+func TestWebsiteHandler(t *testing.T) {
+	defer func() { globalWebsiteDomainNames = nil }()
+	globalWebsiteDomainNames = []string{"website.local"}
+
+	ExecObjectLayerAPITest(t, testWebsiteHandler, []string{"GetObject"})
+}
+
+func testWebsiteHandler(obj ObjectLayer, instanceType, bucketName string, apiRouter http.Handler,
+	credentials auth.Credentials, t *testing.T) {
+	objects := map[string]string{
+		"site/index.html":      "site index",
+		"site/docs/index.html": "docs index",
+		"site/error.html":      "site error",
+		"private.html":         "private",
+	}
+	for object, data := range objects {
+		_, err := obj.PutObject(context.Background(), bucketName, object,
+			mustGetPutObjReader(t, bytes.NewReader([]byte(data)), int64(len(data)), "", ""), ObjectOptions{})
+		if err != nil {
+			t.Fatalf("%s: %v", instanceType, err)
+		}
+	}
+
+	globalPolicySys.Set(bucketName, *getAnonReadOnlyObjectPolicy(bucketName, "site/*"))
+	globalBucketWebsiteSys = NewBucketWebsiteSys()
+	globalBucketWebsiteSys.Set(bucketName, website.Config{
+		IndexDocument: &website.IndexDocument{Suffix: "index.html"},
+		ErrorDocument: &website.ErrorDocument{Key: "site/error.html"},
+		RoutingRules: []website.RoutingRule{{
+			Condition: &website.Condition{KeyPrefixEquals: "old/"},
+			Redirect:  website.Redirect{ReplaceKeyPrefixWith: "site/"},
+		}},
+	})
+
+	// Website routes are only registered by the complete API router.
+	globalObjLayerMutex.Lock()
+	globalObjectAPI = obj
+	globalObjLayerMutex.Unlock()
+	router := mux.NewRouter().SkipClean(true)
+	registerAPIRouter(router, true, false)
+
+	host := bucketName + ".website.local"
+	testCases := []struct {
+		method         string
+		path           string
+		expectedStatus int
+		expectedBody   string
+		location       string
+	}{
+		// Index document of a directory.
+		{http.MethodGet, "/site/", http.StatusOK, "site index", ""},
+		{http.MethodHead, "/site/", http.StatusOK, "", ""},
+		// Directory without trailing slash.
+		{http.MethodGet, "/site/docs", http.StatusFound, "", "/site/docs/"},
+		// Missing object is answered with the error document.
+		{http.MethodGet, "/site/missing.html", http.StatusNotFound, "site error", ""},
+		// Object which is not public.
+		{http.MethodGet, "/private.html", http.StatusForbidden, "site error", ""},
+		// Routing rule.
+		{http.MethodGet, "/old/index.html", http.StatusMovedPermanently, "", "http://" + host + "/site/index.html"},
+		// Website endpoints only serve objects.
+		{http.MethodPut, "/site/index.html", http.StatusMethodNotAllowed, "", ""},
+	}
+	for i, testCase := range testCases {
+		req := httptest.NewRequest(testCase.method, "http://"+host+testCase.path, nil)
+		rec := httptest.NewRecorder()
+		router.ServeHTTP(rec, req)
+		if rec.Code != testCase.expectedStatus {
+			t.Errorf("%s: Test %d: expected status %d, got %d", instanceType, i+1, testCase.expectedStatus, rec.Code)
+		}
+		if testCase.expectedBody != "" && rec.Body.String() != testCase.expectedBody {
+			t.Errorf("%s: Test %d: expected body %q, got %q", instanceType, i+1, testCase.expectedBody, rec.Body.String())
+		}
+		if location := rec.Header().Get("Location"); location != testCase.location {
+			t.Errorf("%s: Test %d: expected location %q, got %q", instanceType, i+1, testCase.location, location)
+		}
+	}
+}
@@ -0,0 +1,109 @@
+/*
+ * MinIO Cloud Storage, (C) 2020 MinIO, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"context"
+	"errors"
+
+	"github.com/minio/cli"
+	"github.com/minio/mc/pkg/console"
+	"github.com/minio/minio/cmd/logger"
+)
+
+var cacheMigrateCmd = cli.Command{
+	Name:   "cache-migrate",
+	Usage:  "migrate cache drives to the latest cache format",
+	Action: mainCacheMigrate,
+	Flags: []cli.Flag{
+		cli.BoolFlag{
+			Name:  "offline",
+			Usage: "migrate the cache drives, the servers using them must be stopped",
+		},
+	},
+	CustomHelpTemplate: `NAME:
+   {{.HelpName}} - {{.Usage}}
+
+USAGE:
+   {{.HelpName}} {{if .VisibleFlags}}[FLAGS] {{end}}DRIVE1 [DRIVE2...]
+
+DRIVE:
+   Cache drives in the order of the cache configuration of the server,
+   ellipses are supported. Without --offline, the format version of each
+   drive is printed and no drive is migrated.
+{{if .VisibleFlags}}
+FLAGS:
+  {{range .VisibleFlags}}{{.}}
+  {{end}}{{end}}
+EXAMPLES:
+   1. Print the format version of four cache drives:
+      {{.Prompt}} {{.HelpName}} /mnt/cache{1...4}
+
+   2. Migrate four cache drives while the server is stopped:
+      {{.Prompt}} {{.HelpName}} --offline /mnt/cache{1...4}
+`,
+}
+
+// mainCacheMigrate - migrates cache drives to the latest cache format
+// while no server uses them, so that a server started afterwards does
+// not migrate them in the background with its cache disabled.
+func mainCacheMigrate(ctx *cli.Context) {
+	if len(ctx.Args()) == 0 {
+		cli.ShowCommandHelpAndExit(ctx, "cache-migrate", 1)
+	}
+
+	drives, err := parseCacheDrives(ctx.Args())
+	logger.FatalIf(err, "Invalid cache drives")
+	if cacheDrivesUnformatted(drives) {
+		logger.Fatal(errors.New("no cache format found"), "Unable to migrate the cache drives")
+	}
+
+	rctx := logger.SetReqInfo(context.Background(), &logger.ReqInfo{})
+	formats, migrating, err := loadFormatCache(rctx, drives)
+	logger.FatalIf(err, "Unable to load the cache format")
+	logger.FatalIf(validateCacheFormats(rctx, migrating, formats), "Invalid cache format")
+
+	caches := make([]*diskCache, len(drives))
+	for i, drive := range drives {
+		if formats[i] == nil {
+			console.Println(drive + ": not formatted")
+			continue
+		}
+		version := formats[i].Cache.Version
+		if version == formatCacheVersionLatest {
+			console.Println(drive + ": format " + version)
+			continue
+		}
+		console.Println(drive + ": format " + version + ", migration to format " + formatCacheVersionLatest + " required")
+		caches[i], err = newdiskCache(drive, 0, 0)
+		logger.FatalIf(err, "Unable to initialize the cache drive %s", drive)
+	}
+
+	if !migrating {
+		return
+	}
+	if !ctx.Bool("offline") {
+		console.Println("Stop the servers using the cache drives and run with --offline to migrate them.")
+		return
+	}
+
+	console.Println("Cache migration initiated ....")
+	for _, err := range migrateCacheDrives(rctx, caches) {
+		logger.FatalIf(err, "Unable to migrate the cache drives")
+	}
+	console.Println("Cache migration completed successfully.")
+}
@@ -142,20 +142,25 @@ func loadX509KeyPair(certFile, keyFile string) (tls.Certificate, error) {
 	return cert, nil
 }
 
-func getTLSConfig() (x509Certs []*x509.Certificate, c *certs.Certs, secureConn bool, err error) {
-	if !(isFile(getPublicCertFile()) && isFile(getPrivateKeyFile())) {
+// getTLSConfig loads the certificate and key pair of the certs directory
+// and the pairs of its subdirectories, which are served to clients
+// requesting a server name held by their certificate.
+func getTLSConfig() (x509Certs []*x509.Certificate, m *certs.Manager, secureConn bool, err error) {
+	if !certs.HasCertificates(getPublicCertFile(), getPrivateKeyFile()) {
 		return nil, nil, false, nil
 	}
 
-	if x509Certs, err = parsePublicCertFile(getPublicCertFile()); err != nil {
-		return nil, nil, false, err
+	if isFile(getPublicCertFile()) && isFile(getPrivateKeyFile()) {
+		if x509Certs, err = parsePublicCertFile(getPublicCertFile()); err != nil {
+			return nil, nil, false, err
+		}
 	}
 
-	c, err = certs.New(getPublicCertFile(), getPrivateKeyFile(), loadX509KeyPair)
+	m, err = certs.NewManager(getPublicCertFile(), getPrivateKeyFile(), loadX509KeyPair)
 	if err != nil {
 		return nil, nil, false, err
 	}
 
 	secureConn = true
-	return x509Certs, c, secureConn, nil
+	return x509Certs, m, secureConn, nil
 }
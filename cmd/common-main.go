@@ -79,7 +79,11 @@ func loadLoggers() {
 	auditEndpoint, ok := os.LookupEnv("MINIO_AUDIT_LOGGER_HTTP_ENDPOINT")
 	if ok {
 		// Enable audit HTTP logging through ENV.
-		logger.AddAuditTarget(http.New(auditEndpoint, loggerUserAgent, NewCustomHTTPTransport()))
+		var auditTarget logger.Target = http.New(auditEndpoint, loggerUserAgent, NewCustomHTTPTransport())
+		if globalAuditChainEnabled {
+			auditTarget = logger.NewChainTarget(auditTarget, mustGetUUID())
+		}
+		logger.AddAuditTarget(auditTarget)
 	}
 
 	loggerEndpoint, ok := os.LookupEnv("MINIO_LOGGER_HTTP_ENDPOINT")
@@ -223,6 +227,14 @@ func handleCommonEnvVars() {
 		globalActiveCred = cred
 	}
 
+	if gracePeriodStr := os.Getenv("MINIO_CREDENTIAL_GRACE_PERIOD"); gracePeriodStr != "" {
+		gracePeriod, err := time.ParseDuration(gracePeriodStr)
+		if err != nil || gracePeriod < 0 {
+			logger.Fatal(uiErrInvalidCredentialGracePeriod(err), "Unable to parse MINIO_CREDENTIAL_GRACE_PERIOD value (`%s`)", gracePeriodStr)
+		}
+		globalCredentialGracePeriod = gracePeriod
+	}
+
 	if browser := os.Getenv("MINIO_BROWSER"); browser != "" {
 		browserFlag, err := ParseBoolFlag(browser)
 		if err != nil {
@@ -235,6 +247,17 @@ func handleCommonEnvVars() {
 		globalIsBrowserEnabled = bool(browserFlag)
 	}
 
+	// The browser checks for new releases only if enabled.
+	if updateCheck := os.Getenv("MINIO_BROWSER_UPDATE_CHECK"); updateCheck != "" {
+		updateCheckFlag, err := ParseBoolFlag(updateCheck)
+		if err != nil {
+			logger.Fatal(uiErrInvalidBrowserValue(nil).Msg("Unknown value `%s`", updateCheck), "Invalid MINIO_BROWSER_UPDATE_CHECK value in environment variable")
+		}
+		if updateCheckFlag {
+			globalUpdateChecker = newUpdateChecker(updateCheckInterval)
+		}
+	}
+
 	etcdEndpointsEnv, ok := os.LookupEnv("MINIO_ETCD_ENDPOINTS")
 	if ok {
 		etcdEndpoints := strings.Split(etcdEndpointsEnv, ",")
@@ -293,6 +316,26 @@ func handleCommonEnvVars() {
 		}
 	}
 
+	if v, ok = os.LookupEnv("MINIO_WEBSITE_DOMAIN"); ok {
+		for _, domainName := range strings.Split(v, ",") {
+			if _, ok = dns2.IsDomainName(domainName); !ok || contains(globalDomainNames, domainName) {
+				logger.Fatal(uiErrInvalidDomainValue(nil).Msg("`%s` must be a domain name distinct from MINIO_DOMAIN", domainName),
+					"Invalid MINIO_WEBSITE_DOMAIN value in environment variable")
+			}
+			globalWebsiteDomainNames = append(globalWebsiteDomainNames, domainName)
+		}
+	}
+
+	if acmeDomains := os.Getenv("MINIO_ACME_DOMAINS"); acmeDomains != "" {
+		domains, err := parseACMEDomains(acmeDomains)
+		if err != nil {
+			logger.Fatal(err, "Unable to parse MINIO_ACME_DOMAINS value (`%s`)", acmeDomains)
+		}
+		globalACMEDomains = domains
+		globalACMEEmail = os.Getenv("MINIO_ACME_EMAIL")
+		globalACMEDirectory = os.Getenv("MINIO_ACME_DIRECTORY")
+	}
+
 	minioEndpointsEnv, ok := os.LookupEnv("MINIO_PUBLIC_IPS")
 	if ok {
 		minioEndpoints := strings.Split(minioEndpointsEnv, ",")
@@ -341,6 +384,46 @@ func handleCommonEnvVars() {
 		globalCacheExcludes = excludeList
 	}
 
+	if affinity := os.Getenv("MINIO_CACHE_AFFINITY"); affinity != "" {
+		affinityRules, err := parseCacheAffinityEnv(affinity)
+		if err != nil {
+			logger.Fatal(err, "Unable to parse MINIO_CACHE_AFFINITY value (`%s`)", affinity)
+		}
+		globalCacheAffinity = affinityRules
+	}
+
+	if storageClass := os.Getenv("MINIO_CACHE_STORAGE_CLASS"); storageClass != "" {
+		policies, err := parseCacheStorageClassEnv(storageClass)
+		if err != nil {
+			logger.Fatal(err, "Unable to parse MINIO_CACHE_STORAGE_CLASS value (`%s`)", storageClass)
+		}
+		globalCacheStorageClass = policies
+	}
+
+	if quotaEnv := os.Getenv("MINIO_CACHE_QUOTA"); quotaEnv != "" {
+		quota, err := parseCacheQuotaEnv(quotaEnv)
+		if err != nil {
+			logger.Fatal(err, "Unable to parse MINIO_CACHE_QUOTA value (`%s`)", quotaEnv)
+		}
+		globalCacheQuota = quota
+	}
+
+	if parityEnv := os.Getenv("MINIO_CACHE_PARITY"); parityEnv != "" {
+		parity, err := parseCacheParityEnv(parityEnv, globalCacheDrives, globalCacheAffinity)
+		if err != nil {
+			logger.Fatal(err, "Unable to parse MINIO_CACHE_PARITY value (`%s`)", parityEnv)
+		}
+		globalCacheParity = parity
+	}
+
+	if routingEnv := os.Getenv("MINIO_CACHE_ROUTING"); routingEnv != "" {
+		routing, err := parseCacheRoutingEnv(routingEnv)
+		if err != nil {
+			logger.Fatal(err, "Unable to parse MINIO_CACHE_ROUTING value (`%s`)", routingEnv)
+		}
+		globalCacheRouting = routing
+	}
+
 	if expiryStr := os.Getenv("MINIO_CACHE_EXPIRY"); expiryStr != "" {
 		expiry, err := strconv.Atoi(expiryStr)
 		if err != nil {
@@ -392,6 +475,13 @@ func handleCommonEnvVars() {
 			logger.FatalIf(err, "Invalid value set in environment variable %s", standardStorageClassEnv)
 			globalIsStorageClass = true
 		}
+
+		if sizesc := os.Getenv(sizeStorageClassEnv); sizesc != "" {
+			globalSizeStorageClass, err = parseSizeStorageClass(sizesc)
+			logger.FatalIf(err, "Invalid value set in environment variable %s", sizeStorageClassEnv)
+			err = validateSizeParity(globalSizeStorageClass)
+			logger.FatalIf(err, "Invalid value set in environment variable %s", sizeStorageClassEnv)
+		}
 	}
 
 	// Get WORM environment variable.
@@ -407,10 +497,112 @@ func handleCommonEnvVars() {
 		globalWORMEnabled = bool(wormFlag)
 	}
 
+	if chain := os.Getenv("MINIO_AUDIT_LOGGER_CHAIN"); chain != "" {
+		chainFlag, err := ParseBoolFlag(chain)
+		if err != nil {
+			logger.Fatal(err, "Invalid MINIO_AUDIT_LOGGER_CHAIN value in environment variable")
+		}
+		globalAuditChainEnabled = bool(chainFlag)
+	}
+
+	if intervalStr := os.Getenv("MINIO_AUDIT_LOGGER_ANCHOR_INTERVAL"); intervalStr != "" {
+		interval, err := time.ParseDuration(intervalStr)
+		if err != nil || interval <= 0 {
+			logger.Fatal(err, "Unable to parse MINIO_AUDIT_LOGGER_ANCHOR_INTERVAL value (`%s`)", intervalStr)
+		}
+		globalAuditAnchorInterval = interval
+	}
+
+	if cpuStr, goroutinesStr := os.Getenv("MINIO_PROFILING_WATCHDOG_CPU"), os.Getenv("MINIO_PROFILING_WATCHDOG_GOROUTINES"); cpuStr != "" || goroutinesStr != "" {
+		w := newProfilingWatchdog()
+		if cpuStr != "" {
+			cpuThreshold, err := strconv.ParseFloat(cpuStr, 64)
+			if err != nil || cpuThreshold <= 0 {
+				logger.Fatal(err, "Unable to parse MINIO_PROFILING_WATCHDOG_CPU value (`%s`)", cpuStr)
+			}
+			w.cpuThreshold = cpuThreshold
+		}
+		if goroutinesStr != "" {
+			goroutineThreshold, err := strconv.Atoi(goroutinesStr)
+			if err != nil || goroutineThreshold <= 0 {
+				logger.Fatal(err, "Unable to parse MINIO_PROFILING_WATCHDOG_GOROUTINES value (`%s`)", goroutinesStr)
+			}
+			w.goroutineThreshold = goroutineThreshold
+		}
+		if profiler := os.Getenv("MINIO_PROFILING_WATCHDOG_PROFILER"); profiler != "" {
+			switch profiler {
+			case "cpu", "mem", "block", "mutex", "trace":
+			default:
+				logger.Fatal(errors.New("profiler type unknown"), "Invalid MINIO_PROFILING_WATCHDOG_PROFILER value (`%s`)", profiler)
+			}
+			w.profiler = profiler
+		}
+		if durationStr := os.Getenv("MINIO_PROFILING_WATCHDOG_DURATION"); durationStr != "" {
+			duration, err := time.ParseDuration(durationStr)
+			if err != nil || duration <= 0 {
+				logger.Fatal(err, "Unable to parse MINIO_PROFILING_WATCHDOG_DURATION value (`%s`)", durationStr)
+			}
+			w.duration = duration
+		}
+		if cooldownStr := os.Getenv("MINIO_PROFILING_WATCHDOG_COOLDOWN"); cooldownStr != "" {
+			cooldown, err := time.ParseDuration(cooldownStr)
+			if err != nil || cooldown <= 0 {
+				logger.Fatal(err, "Unable to parse MINIO_PROFILING_WATCHDOG_COOLDOWN value (`%s`)", cooldownStr)
+			}
+			w.cooldown = cooldown
+		}
+		globalProfilingWatchdog = w
+	}
+
+	if expiryStr := os.Getenv("MINIO_MULTIPART_EXPIRY"); expiryStr != "" {
+		expiry, err := strconv.Atoi(expiryStr)
+		if err != nil || expiry <= 0 {
+			logger.Fatal(err, "Unable to parse MINIO_MULTIPART_EXPIRY value (`%s`)", expiryStr)
+		}
+		GlobalMultipartExpiry = time.Duration(expiry) * 24 * time.Hour
+	}
+
+	if signingKeyFile := os.Getenv("MINIO_JWT_SIGNING_KEY_FILE"); signingKeyFile != "" {
+		var verifyKeyFiles []string
+		if verifyKeys := os.Getenv("MINIO_JWT_VERIFY_KEY_FILES"); verifyKeys != "" {
+			verifyKeyFiles = strings.Split(verifyKeys, ",")
+		}
+		keys, err := loadJWTKeys(signingKeyFile, verifyKeyFiles)
+		if err != nil {
+			logger.Fatal(err, "Unable to load the JWT signing keys")
+		}
+		globalJWTKeys = keys
+	}
+
+	if dedup := os.Getenv("MINIO_FS_DEDUP"); dedup != "" {
+		dedupFlag, err := ParseBoolFlag(dedup)
+		if err != nil {
+			logger.Fatal(err, "Invalid MINIO_FS_DEDUP value in environment variable")
+		}
+		globalFSDedupEnabled = bool(dedupFlag)
+	}
+
+	if searchIndex := os.Getenv("MINIO_SEARCH_INDEX"); searchIndex != "" {
+		searchIndexFlag, err := ParseBoolFlag(searchIndex)
+		if err != nil {
+			logger.Fatal(err, "Invalid MINIO_SEARCH_INDEX value in environment variable")
+		}
+		globalSearchIndexEnabled = bool(searchIndexFlag)
+	}
+
 	if compress := os.Getenv("MINIO_COMPRESS"); compress != "" {
 		globalIsCompressionEnabled = strings.EqualFold(compress, "true")
 	}
 
+	if compressAlgorithm := os.Getenv("MINIO_COMPRESS_ALGORITHM"); compressAlgorithm != "" {
+		algorithm, err := parseCompressionAlgorithm(compressAlgorithm)
+		if err != nil {
+			logger.Fatal(err, "Invalid MINIO_COMPRESS_ALGORITHM value (`%s`)", compressAlgorithm)
+		}
+		globalIsEnvCompressAlgorithm = true
+		globalCompressAlgorithm = algorithm
+	}
+
 	compressExtensions := os.Getenv("MINIO_COMPRESS_EXTENSIONS")
 	compressMimeTypes := os.Getenv("MINIO_COMPRESS_MIMETYPES")
 	if compressExtensions != "" || compressMimeTypes != "" {
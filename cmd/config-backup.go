@@ -0,0 +1,370 @@
+/*
+ * MinIO Cloud Storage, (C) 2019 MinIO, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"io/ioutil"
+	"path"
+	"sort"
+	"strings"
+	"time"
+
+	etcd "github.com/coreos/etcd/clientv3"
+	humanize "github.com/dustin/go-humanize"
+	"github.com/minio/minio/pkg/madmin"
+	"github.com/minio/minio/pkg/quick"
+)
+
+// A configuration backup archives all a deployment needs to be rebuilt
+// apart from the objects: the config files, the IAM users, groups and
+// policies, read from etcd when it is configured, and the metadata of
+// all buckets. Temporary STS credentials, bucket listeners and bucket
+// snapshots are left out. The archive is a zip file named after the
+// backend paths of its entries, the admin handlers encrypt it with the
+// secret key of the server.
+//
+// IAM config items are archived as stored, encrypted with the IAM data
+// key, which is restored along with them and can only be unsealed with
+// the same root credentials or KMS.
+
+const (
+	configBackupManifestFile = "manifest.json"
+	configBackupVersion      = "1"
+
+	// Maximum size of an encrypted configuration backup.
+	maxConfigBackupSize = 64 * humanize.MiByte
+)
+
+var errInvalidConfigBackup = errors.New("Configuration backup is invalid")
+
+// configBackupBucketFiles - metadata files of each bucket included in
+// configuration backups.
+var configBackupBucketFiles = []string{
+	bucketPolicyConfig,
+	bucketNotificationConfig,
+	bucketLifecycleConfig,
+	bucketLoggingConfig,
+	bucketCORSConfig,
+	bucketWebsiteConfig,
+	bucketSnapshotConfig,
+	bucketTrashConfig,
+	bucketAnonymousUploadConfig,
+}
+
+// configBackupManifest - describes a configuration backup, the buckets
+// are created on restore if missing.
+type configBackupManifest struct {
+	Version      string    `json:"version"`
+	DeploymentID string    `json:"deploymentID"`
+	Created      time.Time `json:"created"`
+	Buckets      []string  `json:"buckets"`
+}
+
+// isConfigBackupEntry - returns whether name is the backend path of an
+// entry of configuration backups.
+func isConfigBackupEntry(name string) bool {
+	if name == "" || path.Clean(name) != name {
+		return false
+	}
+	if strings.HasPrefix(name, minioConfigPrefix+SlashSeparator) {
+		return name != path.Join(minioConfigPrefix, minioConfigBackupFile) &&
+			!strings.HasPrefix(name, iamConfigSTSPrefix)
+	}
+	tokens := strings.Split(name, SlashSeparator)
+	if len(tokens) != 3 || tokens[0] != bucketConfigPrefix || isMinioMetaBucketName(tokens[1]) {
+		return false
+	}
+	for _, file := range configBackupBucketFiles {
+		if tokens[2] == file {
+			return true
+		}
+	}
+	return false
+}
+
+// isIAMConfigBackupEntry - returns whether name is an IAM config item,
+// stored in etcd when it is configured.
+func isIAMConfigBackupEntry(name string) bool {
+	return strings.HasPrefix(name, iamConfigPrefix+SlashSeparator)
+}
+
+// listConfigObjects - returns the names of all config objects below
+// prefix in the meta bucket.
+func listConfigObjects(ctx context.Context, objAPI ObjectLayer, prefix string) ([]string, error) {
+	var names []string
+	marker := ""
+	for {
+		lo, err := objAPI.ListObjects(ctx, minioMetaBucket, prefix, marker, "", maxObjectList)
+		if err != nil {
+			return nil, err
+		}
+		for _, obj := range lo.Objects {
+			names = append(names, obj.Name)
+		}
+		if !lo.IsTruncated {
+			return names, nil
+		}
+		marker = lo.NextMarker
+	}
+}
+
+// readIAMConfigEtcd - returns all IAM config items stored in etcd.
+func readIAMConfigEtcd(ctx context.Context) (map[string][]byte, error) {
+	timeoutCtx, cancel := context.WithTimeout(ctx, defaultContextTimeout)
+	defer cancel()
+	resp, err := globalEtcdClient.Get(timeoutCtx, iamConfigPrefix+SlashSeparator, etcd.WithPrefix())
+	if err != nil {
+		return nil, etcdErrToErr(err, globalEtcdClient.Endpoints())
+	}
+	items := make(map[string][]byte, len(resp.Kvs))
+	for _, kv := range resp.Kvs {
+		items[string(kv.Key)] = kv.Value
+	}
+	return items, nil
+}
+
+// readConfigBackupEntries - returns the content of all configuration
+// entries of the deployment, by backend path.
+func readConfigBackupEntries(ctx context.Context, objAPI ObjectLayer, buckets []string) (map[string][]byte, error) {
+	entries := make(map[string][]byte)
+
+	names, err := listConfigObjects(ctx, objAPI, minioConfigPrefix+SlashSeparator)
+	if err != nil {
+		return nil, err
+	}
+	for _, bucket := range buckets {
+		for _, file := range configBackupBucketFiles {
+			names = append(names, path.Join(bucketConfigPrefix, bucket, file))
+		}
+	}
+	for _, name := range names {
+		if !isConfigBackupEntry(name) || (globalEtcdClient != nil && isIAMConfigBackupEntry(name)) {
+			continue
+		}
+		data, err := readConfig(ctx, objAPI, name)
+		if err != nil {
+			if err == errConfigNotFound {
+				continue
+			}
+			return nil, err
+		}
+		entries[name] = data
+	}
+
+	if globalEtcdClient != nil {
+		items, err := readIAMConfigEtcd(ctx)
+		if err != nil {
+			return nil, err
+		}
+		for name, data := range items {
+			if isConfigBackupEntry(name) {
+				entries[name] = data
+			}
+		}
+	}
+	return entries, nil
+}
+
+// backupConfig - returns an unencrypted configuration backup of the
+// deployment.
+func backupConfig(ctx context.Context, objAPI ObjectLayer) ([]byte, error) {
+	bucketsInfo, err := objAPI.ListBuckets(ctx)
+	if err != nil {
+		return nil, err
+	}
+	manifest := configBackupManifest{
+		Version:      configBackupVersion,
+		DeploymentID: globalDeploymentID,
+		Created:      UTCNow(),
+		Buckets:      []string{},
+	}
+	for _, bucketInfo := range bucketsInfo {
+		manifest.Buckets = append(manifest.Buckets, bucketInfo.Name)
+	}
+
+	entries, err := readConfigBackupEntries(ctx, objAPI, manifest.Buckets)
+	if err != nil {
+		return nil, err
+	}
+	names := make([]string, 0, len(entries))
+	for name := range entries {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	manifestData, err := json.Marshal(manifest)
+	if err != nil {
+		return nil, err
+	}
+
+	var buffer bytes.Buffer
+	zipWriter := zip.NewWriter(&buffer)
+	addFile := func(name string, data []byte) error {
+		w, err := zipWriter.CreateHeader(&zip.FileHeader{
+			Name:     name,
+			Method:   zip.Deflate,
+			Modified: manifest.Created,
+		})
+		if err != nil {
+			return err
+		}
+		_, err = w.Write(data)
+		return err
+	}
+	if err = addFile(configBackupManifestFile, manifestData); err != nil {
+		return nil, err
+	}
+	for _, name := range names {
+		if err = addFile(name, entries[name]); err != nil {
+			return nil, err
+		}
+	}
+	if err = zipWriter.Close(); err != nil {
+		return nil, err
+	}
+	return buffer.Bytes(), nil
+}
+
+// parseConfigBackup - returns the manifest and the entries of an
+// unencrypted configuration backup.
+func parseConfigBackup(data []byte) (manifest configBackupManifest, entries map[string][]byte, err error) {
+	zipReader, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		return manifest, nil, errInvalidConfigBackup
+	}
+
+	var foundManifest bool
+	entries = make(map[string][]byte, len(zipReader.File))
+	for _, file := range zipReader.File {
+		if file.Name != configBackupManifestFile && !isConfigBackupEntry(file.Name) {
+			return manifest, nil, errInvalidConfigBackup
+		}
+		reader, err := file.Open()
+		if err != nil {
+			return manifest, nil, errInvalidConfigBackup
+		}
+		content, err := ioutil.ReadAll(reader)
+		reader.Close()
+		if err != nil {
+			return manifest, nil, errInvalidConfigBackup
+		}
+		if file.Name == configBackupManifestFile {
+			if err = json.Unmarshal(content, &manifest); err != nil {
+				return manifest, nil, errInvalidConfigBackup
+			}
+			foundManifest = true
+			continue
+		}
+		entries[file.Name] = content
+	}
+	if !foundManifest || manifest.Version != configBackupVersion {
+		return manifest, nil, errInvalidConfigBackup
+	}
+
+	// Refuse to restore a server config the servers
+	// would not be able to start with.
+	if configData, ok := entries[path.Join(minioConfigPrefix, minioConfigFile)]; ok {
+		if err = quick.CheckDuplicateKeys(string(configData)); err != nil {
+			return manifest, nil, errInvalidConfigBackup
+		}
+		var config serverConfig
+		if err = json.Unmarshal(configData, &config); err != nil {
+			return manifest, nil, errInvalidConfigBackup
+		}
+	}
+	return manifest, entries, nil
+}
+
+// restoreConfig - restores an unencrypted configuration backup, the
+// IAM config of the deployment is replaced by the one of the backup
+// since its items are encrypted with the IAM data key of the backup.
+// The restored configuration takes effect once the servers restart.
+func restoreConfig(ctx context.Context, objAPI ObjectLayer, data []byte) (info madmin.ConfigRestoreInfo, err error) {
+	manifest, entries, err := parseConfigBackup(data)
+	if err != nil {
+		return info, err
+	}
+
+	info.CreatedBuckets = []string{}
+	for _, bucket := range manifest.Buckets {
+		if err = objAPI.MakeBucketWithLocation(ctx, bucket, globalServerConfig.GetRegion()); err != nil {
+			if _, ok := err.(BucketExists); ok {
+				continue
+			}
+			return info, err
+		}
+		info.CreatedBuckets = append(info.CreatedBuckets, bucket)
+	}
+
+	names := make([]string, 0, len(entries))
+	for name := range entries {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		if globalEtcdClient != nil && isIAMConfigBackupEntry(name) {
+			err = saveKeyEtcd(ctx, globalEtcdClient, name, entries[name])
+		} else {
+			err = saveConfig(ctx, objAPI, name, entries[name])
+		}
+		if err != nil {
+			return info, err
+		}
+		info.Entries++
+	}
+
+	if _, ok := entries[getIAMFormatFilePath()]; !ok {
+		// Backup taken before IAM was initialized.
+		return info, nil
+	}
+
+	// Remove the IAM config items of the deployment left out of the
+	// backup, they cannot be decrypted with the restored data key.
+	if globalEtcdClient != nil {
+		items, err := readIAMConfigEtcd(ctx)
+		if err != nil {
+			return info, err
+		}
+		for name := range items {
+			if _, ok := entries[name]; !ok {
+				if err = deleteKeyEtcd(ctx, globalEtcdClient, name); err != nil {
+					return info, err
+				}
+			}
+		}
+		return info, nil
+	}
+
+	iamNames, err := listConfigObjects(ctx, objAPI, iamConfigPrefix+SlashSeparator)
+	if err != nil {
+		return info, err
+	}
+	for _, name := range iamNames {
+		if _, ok := entries[name]; !ok {
+			if err = deleteConfig(ctx, objAPI, name); err != nil && !isErrObjectNotFound(err) {
+				return info, err
+			}
+		}
+	}
+	return info, nil
+}
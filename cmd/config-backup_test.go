@@ -0,0 +1,162 @@
+/*
+ * MinIO Cloud Storage, (C) 2019 MinIO, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"os"
+	"reflect"
+	"testing"
+)
+
+func TestIsConfigBackupEntry(t *testing.T) {
+	testCases := []struct {
+		name     string
+		expected bool
+	}{
+		{"config/config.json", true},
+		{"config/iam/format.json", true},
+		{"config/iam/users/alice/identity.json", true},
+		{"buckets/photos/policy.json", true},
+		{"buckets/photos/cors.xml", true},
+		{"config/config.json.backup", false},
+		{"config/iam/sts/alice/identity.json", false},
+		{"buckets/photos/listener.json", false},
+		{"buckets/photos/snapshots/20190101T0000Z/info.json", false},
+		{"buckets/.minio.sys/policy.json", false},
+		{"config/../format.json", false},
+		{"format.json", false},
+		{"", false},
+	}
+	for _, testCase := range testCases {
+		if actual := isConfigBackupEntry(testCase.name); actual != testCase.expected {
+			t.Errorf("%s: expected %v, got %v", testCase.name, testCase.expected, actual)
+		}
+	}
+}
+
+// Tests that a configuration backup restores the config and bucket
+// metadata into another deployment, replacing its IAM config.
+func TestConfigBackupRestore(t *testing.T) {
+	ctx := context.Background()
+
+	srcObj, srcDir, err := prepareFS()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(srcDir)
+	if err = newTestConfig(globalMinioDefaultRegion, srcObj); err != nil {
+		t.Fatal(err)
+	}
+	if err = srcObj.MakeBucketWithLocation(ctx, "photos", ""); err != nil {
+		t.Fatal(err)
+	}
+	srcEntries := map[string]string{
+		"config/iam/format.json":               `{"version":1}`,
+		"config/iam/users/alice/identity.json": "alice",
+		"config/iam/sts/temp/identity.json":    "temp",
+		"buckets/photos/policy.json":           "policy",
+		"buckets/photos/listener.json":         "listener",
+	}
+	for name, data := range srcEntries {
+		if err = saveConfig(ctx, srcObj, name, []byte(data)); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	backup, err := backupConfig(ctx, srcObj)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	dstObj, dstDir, err := prepareFS()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dstDir)
+	if err = saveConfig(ctx, dstObj, "config/iam/users/bob/identity.json", []byte("bob")); err != nil {
+		t.Fatal(err)
+	}
+
+	info, err := restoreConfig(ctx, dstObj, backup)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// config.json, format.json, alice and the bucket policy.
+	if info.Entries != 4 || !reflect.DeepEqual(info.CreatedBuckets, []string{"photos"}) {
+		t.Fatalf("Unexpected restore info %v", info)
+	}
+
+	for _, name := range []string{"config/config.json", "config/iam/users/alice/identity.json", "buckets/photos/policy.json"} {
+		if _, err = readConfig(ctx, dstObj, name); err != nil {
+			t.Errorf("%s: expected to be restored, got %v", name, err)
+		}
+	}
+	for _, name := range []string{"config/iam/sts/temp/identity.json", "buckets/photos/listener.json", "config/iam/users/bob/identity.json"} {
+		if _, err = readConfig(ctx, dstObj, name); err != errConfigNotFound {
+			t.Errorf("%s: expected to be missing, got %v", name, err)
+		}
+	}
+
+	// Restoring again leaves the existing buckets as is.
+	if info, err = restoreConfig(ctx, dstObj, backup); err != nil {
+		t.Fatal(err)
+	}
+	if len(info.CreatedBuckets) != 0 {
+		t.Fatalf("Unexpected created buckets %v", info.CreatedBuckets)
+	}
+}
+
+func TestParseConfigBackupInvalid(t *testing.T) {
+	newArchive := func(files map[string]string) []byte {
+		var buffer bytes.Buffer
+		zipWriter := zip.NewWriter(&buffer)
+		for name, data := range files {
+			w, err := zipWriter.Create(name)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if _, err = w.Write([]byte(data)); err != nil {
+				t.Fatal(err)
+			}
+		}
+		if err := zipWriter.Close(); err != nil {
+			t.Fatal(err)
+		}
+		return buffer.Bytes()
+	}
+	manifest := `{"version":"1","buckets":[]}`
+
+	testCases := [][]byte{
+		[]byte("not a zip file"),
+		newArchive(map[string]string{"config/config.json": "{}"}),
+		newArchive(map[string]string{configBackupManifestFile: `{"version":"2"}`}),
+		newArchive(map[string]string{configBackupManifestFile: manifest, "format.json": "{}"}),
+		newArchive(map[string]string{configBackupManifestFile: manifest, "config/config.json": `{"version":"33","version":"33"}`}),
+	}
+	for i, data := range testCases {
+		if _, _, err := parseConfigBackup(data); err != errInvalidConfigBackup {
+			t.Errorf("Test %d: expected %v, got %v", i+1, errInvalidConfigBackup, err)
+		}
+	}
+
+	if _, _, err := parseConfigBackup(newArchive(map[string]string{configBackupManifestFile: manifest})); err != nil {
+		t.Errorf("Expected empty backup to be valid, got %v", err)
+	}
+}
@@ -135,9 +135,14 @@ func (s *serverConfig) GetWorm() bool {
 }
 
 // SetCacheConfig sets the current cache config
-func (s *serverConfig) SetCacheConfig(drives, exclude []string, expiry int, maxuse int) {
+func (s *serverConfig) SetCacheConfig(drives, exclude []string, affinity map[string][]string, storageClass map[string]string, quota map[string]int, parity int, routing []CacheRoutingRule, expiry int, maxuse int) {
 	s.Cache.Drives = drives
 	s.Cache.Exclude = exclude
+	s.Cache.Affinity = affinity
+	s.Cache.StorageClass = storageClass
+	s.Cache.Quota = quota
+	s.Cache.Parity = parity
+	s.Cache.Routing = routing
 	s.Cache.Expiry = expiry
 	s.Cache.MaxUse = maxuse
 }
@@ -146,10 +151,15 @@ func (s *serverConfig) SetCacheConfig(drives, exclude []string, expiry int, maxu
 func (s *serverConfig) GetCacheConfig() CacheConfig {
 	if globalIsDiskCacheEnabled {
 		return CacheConfig{
-			Drives:  globalCacheDrives,
-			Exclude: globalCacheExcludes,
-			Expiry:  globalCacheExpiry,
-			MaxUse:  globalCacheMaxUse,
+			Drives:       globalCacheDrives,
+			Exclude:      globalCacheExcludes,
+			Affinity:     globalCacheAffinity,
+			StorageClass: globalCacheStorageClass,
+			Quota:        globalCacheQuota,
+			Parity:       globalCacheParity,
+			Routing:      globalCacheRouting,
+			Expiry:       globalCacheExpiry,
+			MaxUse:       globalCacheMaxUse,
 		}
 	}
 	if s == nil {
@@ -175,6 +185,14 @@ func (s *serverConfig) Validate() error {
 
 	// Region: nothing to validate
 	// Worm, Cache and StorageClass values are already validated during json unmarshal
+	if err := s.Compression.Validate(); err != nil {
+		return fmt.Errorf("compress: %s", err)
+	}
+
+	if _, err := s.API.parse(); err != nil {
+		return fmt.Errorf("api: %s", err)
+	}
+
 	for _, v := range s.Notify.AMQP {
 		if err := v.Validate(); err != nil {
 			return fmt.Errorf("amqp: %s", err)
@@ -187,6 +205,12 @@ func (s *serverConfig) Validate() error {
 		}
 	}
 
+	for _, v := range s.Notify.GCPPubSub {
+		if err := v.Validate(); err != nil {
+			return fmt.Errorf("gcppubsub: %s", err)
+		}
+	}
+
 	for _, v := range s.Notify.Kafka {
 		if err := v.Validate(); err != nil {
 			return fmt.Errorf("kafka: %s", err)
@@ -269,7 +293,7 @@ func (s *serverConfig) loadFromEnvs() {
 	}
 
 	if globalIsDiskCacheEnabled {
-		s.SetCacheConfig(globalCacheDrives, globalCacheExcludes, globalCacheExpiry, globalCacheMaxUse)
+		s.SetCacheConfig(globalCacheDrives, globalCacheExcludes, globalCacheAffinity, globalCacheStorageClass, globalCacheQuota, globalCacheParity, globalCacheRouting, globalCacheExpiry, globalCacheMaxUse)
 	}
 
 	if err := Environment.LookupKMSConfig(s.KMS); err != nil {
@@ -280,6 +304,24 @@ func (s *serverConfig) loadFromEnvs() {
 		s.SetCompressionConfig(globalCompressExtensions, globalCompressMimeTypes)
 	}
 
+	if globalIsEnvCompressAlgorithm {
+		s.Compression.Algorithm = os.Getenv("MINIO_COMPRESS_ALGORITHM")
+	}
+
+	// Override the table mode and partitioning of all PostgreSQL targets.
+	if mode, ok := os.LookupEnv("MINIO_NOTIFY_POSTGRES_MODE"); ok {
+		for k, v := range s.Notify.PostgreSQL {
+			v.Mode = mode
+			s.Notify.PostgreSQL[k] = v
+		}
+	}
+	if partition, ok := os.LookupEnv("MINIO_NOTIFY_POSTGRES_PARTITION"); ok {
+		for k, v := range s.Notify.PostgreSQL {
+			v.Partition = partition
+			s.Notify.PostgreSQL[k] = v
+		}
+	}
+
 	if jwksURL, ok := os.LookupEnv("MINIO_IAM_JWKS_URL"); ok {
 		u, err := xnet.ParseURL(jwksURL)
 		if err != nil {
@@ -331,6 +373,17 @@ func (s *serverConfig) TestNotificationTargets() error {
 		t.Close()
 	}
 
+	for k, v := range s.Notify.GCPPubSub {
+		if !v.Enable {
+			continue
+		}
+		t, err := target.NewGCPPubSubTarget(k, v, GlobalServiceDoneCh)
+		if err != nil {
+			return fmt.Errorf("gcppubsub(%s): %s", k, err.Error())
+		}
+		t.Close()
+	}
+
 	for k, v := range s.Notify.Kafka {
 		if !v.Enable {
 			continue
@@ -429,6 +482,8 @@ func (s *serverConfig) ConfigDiff(t *serverConfig) string {
 		return "Cache configuration differs"
 	case !reflect.DeepEqual(s.Compression, t.Compression):
 		return "Compression configuration differs"
+	case !reflect.DeepEqual(s.API, t.API):
+		return "API configuration differs"
 	case !reflect.DeepEqual(s.Notify.AMQP, t.Notify.AMQP):
 		return "AMQP Notification configuration differs"
 	case !reflect.DeepEqual(s.Notify.NATS, t.Notify.NATS):
@@ -441,6 +496,8 @@ func (s *serverConfig) ConfigDiff(t *serverConfig) string {
 		return "Redis Notification configuration differs"
 	case !reflect.DeepEqual(s.Notify.PostgreSQL, t.Notify.PostgreSQL):
 		return "PostgreSQL Notification configuration differs"
+	case !reflect.DeepEqual(s.Notify.GCPPubSub, t.Notify.GCPPubSub):
+		return "GCP Pub/Sub Notification configuration differs"
 	case !reflect.DeepEqual(s.Notify.Kafka, t.Notify.Kafka):
 		return "Kafka Notification configuration differs"
 	case !reflect.DeepEqual(s.Notify.Webhook, t.Notify.Webhook):
@@ -506,6 +563,8 @@ func newServerConfig() *serverConfig {
 	srvCfg.Notify.PostgreSQL["1"] = target.PostgreSQLArgs{}
 	srvCfg.Notify.MySQL = make(map[string]target.MySQLArgs)
 	srvCfg.Notify.MySQL["1"] = target.MySQLArgs{}
+	srvCfg.Notify.GCPPubSub = make(map[string]target.GCPPubSubArgs)
+	srvCfg.Notify.GCPPubSub["1"] = target.GCPPubSubArgs{}
 	srvCfg.Notify.Kafka = make(map[string]target.KafkaArgs)
 	srvCfg.Notify.Kafka["1"] = target.KafkaArgs{}
 	srvCfg.Notify.Webhook = make(map[string]target.WebhookArgs)
@@ -529,6 +588,10 @@ func (s *serverConfig) loadToCachedConfigs() {
 	if !globalIsEnvCreds {
 		globalActiveCred = s.GetCredential()
 	}
+	globalPrevCredential.load(s.PrevCredential)
+	// Validated along with the rest of the configuration.
+	limits, _ := s.API.parse()
+	globalRequestRegistry.setLimits(limits)
 	if !globalIsEnvWORM {
 		globalWORMEnabled = s.GetWorm()
 	}
@@ -542,6 +605,11 @@ func (s *serverConfig) loadToCachedConfigs() {
 		cacheConf := s.GetCacheConfig()
 		globalCacheDrives = cacheConf.Drives
 		globalCacheExcludes = cacheConf.Exclude
+		globalCacheAffinity = cacheConf.Affinity
+		globalCacheStorageClass = cacheConf.StorageClass
+		globalCacheQuota = cacheConf.Quota
+		globalCacheParity = cacheConf.Parity
+		globalCacheRouting = cacheConf.Routing
 		globalCacheExpiry = cacheConf.Expiry
 		globalCacheMaxUse = cacheConf.MaxUse
 	}
@@ -555,6 +623,14 @@ func (s *serverConfig) loadToCachedConfigs() {
 		globalCompressMimeTypes = compressionConf.MimeTypes
 		globalIsCompressionEnabled = compressionConf.Enabled
 	}
+	if !globalIsEnvCompressAlgorithm {
+		// The algorithm is validated with the config.
+		globalCompressAlgorithm, _ = parseCompressionAlgorithm(s.Compression.Algorithm)
+	}
+	globalCompressRules = s.Compression.Rules
+	compressDictionaries, err := loadCompressionDictionaries(s.Compression.Dictionaries)
+	logger.FatalIf(err, "Unable to load the compression dictionaries")
+	globalCompressDictionaries = compressDictionaries
 
 	if s.OpenID.JWKS.URL != nil && s.OpenID.JWKS.URL.String() != "" {
 		logger.FatalIf(s.OpenID.JWKS.PopulatePublicKey(),
@@ -682,6 +758,20 @@ func getNotificationTargets(config *serverConfig) *event.TargetList {
 		}
 	}
 
+	for id, args := range config.Notify.GCPPubSub {
+		if args.Enable {
+			newTarget, err := target.NewGCPPubSubTarget(id, args, GlobalServiceDoneCh)
+			if err != nil {
+				logger.LogIf(context.Background(), err)
+				continue
+			}
+			if err = targetList.Add(newTarget); err != nil {
+				logger.LogIf(context.Background(), err)
+				continue
+			}
+		}
+	}
+
 	for id, args := range config.Notify.Kafka {
 		if args.Enable {
 			newTarget, err := target.NewKafkaTarget(id, args, GlobalServiceDoneCh)
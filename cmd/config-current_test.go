@@ -237,6 +237,12 @@ func TestValidateConfig(t *testing.T) {
 
 		// Test 28 - Test NSQ
 		{`{"version": "` + v + `", "credential": { "accessKey": "minio", "secretKey": "minio123" }, "region": "us-east-1", "browser": "on", "notify": { "nsq": { "1": { "enable": true, "nsqdAddress": "", "topic": "", "queueDir": "", "queueLimit": 0} }}}`, false},
+
+		// Test 29 - Test GCP Pub/Sub
+		{`{"version": "` + v + `", "credential": { "accessKey": "minio", "secretKey": "minio123" }, "region": "us-east-1", "browser": "on", "notify": { "gcppubsub": { "1": { "enable": true, "projectID": "", "topic": "", "credentialsFile": "", "orderingKey": false, "queueDir": "", "queueLimit": 0} }}}`, false},
+
+		// Test 30 - Test GCP Pub/Sub with a relative credentials file
+		{`{"version": "` + v + `", "credential": { "accessKey": "minio", "secretKey": "minio123" }, "region": "us-east-1", "browser": "on", "notify": { "gcppubsub": { "1": { "enable": true, "projectID": "myproject", "topic": "minio", "credentialsFile": "key.json", "orderingKey": false, "queueDir": "", "queueLimit": 0} }}}`, false},
 	}
 
 	for i, testCase := range testCases {
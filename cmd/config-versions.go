@@ -756,6 +756,37 @@ type compressionConfig struct {
 	Enabled    bool     `json:"enabled"`
 	Extensions []string `json:"extensions"`
 	MimeTypes  []string `json:"mime-types"`
+	// Default compression algorithm, snappy if not set.
+	Algorithm string `json:"algorithm,omitempty"`
+	// Rules selecting the compression algorithm of objects by bucket
+	// and extension or mime-type, the first matching rule applies.
+	Rules []compressionRule `json:"rules,omitempty"`
+	// Paths of the zstd dictionaries referenced by the rules, keyed
+	// by the dictionary name.
+	Dictionaries map[string]string `json:"dictionaries,omitempty"`
+}
+
+// compressionRule selects the compression algorithm and zstd dictionary
+// of the objects of the matching buckets, extensions and mime-types.
+type compressionRule struct {
+	Buckets    []string `json:"buckets,omitempty"`
+	Extensions []string `json:"extensions,omitempty"`
+	MimeTypes  []string `json:"mime-types,omitempty"`
+	Algorithm  string   `json:"algorithm"`
+	Dictionary string   `json:"dictionary,omitempty"`
+}
+
+// apiConfig represents the request deadlines and the slow request
+// log of the S3 API, durations are written as "30s" or "5m".
+type apiConfig struct {
+	// Deadline of all requests, requests are canceled once it has
+	// passed. Empty means no deadline.
+	Deadline string `json:"deadline,omitempty"`
+	// Deadlines of the requests of some APIs, keyed by API name such
+	// as "PutObject", overriding Deadline. "0s" means no deadline.
+	APIDeadlines map[string]string `json:"apiDeadlines,omitempty"`
+	// Requests taking longer are logged. Empty means no logging.
+	SlowRequestThreshold string `json:"slowRequestThreshold,omitempty"`
 }
 
 // serverConfigV30 is just like version '29', stores additionally
@@ -832,6 +863,7 @@ type serverConfigV31 struct {
 type notifier struct {
 	AMQP          map[string]target.AMQPArgs          `json:"amqp"`
 	Elasticsearch map[string]target.ElasticsearchArgs `json:"elasticsearch"`
+	GCPPubSub     map[string]target.GCPPubSubArgs     `json:"gcppubsub"`
 	Kafka         map[string]target.KafkaArgs         `json:"kafka"`
 	MQTT          map[string]target.MQTTArgs          `json:"mqtt"`
 	MySQL         map[string]target.MySQLArgs         `json:"mysql"`
@@ -926,4 +958,11 @@ type serverConfigV33 struct {
 
 		// Add new external policy enforcements here.
 	} `json:"policy"`
+
+	// Previous root credential, URLs presigned with it remain
+	// valid until its grace period expires.
+	PrevCredential *graceCredential `json:"prevCredential,omitempty"`
+
+	// Deadlines of the S3 API requests and slow request logging.
+	API apiConfig `json:"api"`
 }
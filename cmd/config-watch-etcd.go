@@ -0,0 +1,218 @@
+/*
+ * MinIO Cloud Storage, (C) 2019 MinIO, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"context"
+	"path"
+	"strings"
+	"time"
+
+	etcd "github.com/coreos/etcd/clientv3"
+	"github.com/minio/minio/cmd/logger"
+	"github.com/minio/minio/pkg/event"
+)
+
+// When etcd is configured, configuration changes are published under
+// configEventsPrefix, namespaced by deployment ID since one etcd may be
+// shared by several deployments, instead of being sent to every peer.
+// All the servers watch this prefix and reload the changed configuration
+// from the backend. IAM changes are watched by IAMEtcdStore.
+const (
+	configEventsPrefix = "config/events"
+
+	configEventBucketPolicy       = "bucket-policy"
+	configEventBucketLifecycle    = "bucket-lifecycle"
+	configEventBucketNotification = "bucket-notification"
+	configEventBucketLogging      = "bucket-logging"
+	configEventBucketCORS         = "bucket-cors"
+	configEventBucketWebsite      = "bucket-website"
+	configEventServerConfig       = "server-config"
+)
+
+// configEventOrigin identifies the config events published by this
+// server, which has already applied them locally.
+var configEventOrigin = mustGetUUID()
+
+// getConfigEventsPrefix - returns the prefix of the config events of
+// this deployment.
+func getConfigEventsPrefix() string {
+	return path.Join(configEventsPrefix, globalDeploymentID) + SlashSeparator
+}
+
+// publishConfigEvent - publishes a configuration change of the given kind
+// for name to etcd, returns false if etcd is not configured or
+// unreachable, in which case peers have to be notified directly.
+// Changes are published in the background of requests, hence the
+// global context is used instead of the request context.
+func publishConfigEvent(kind, name string) bool {
+	if globalEtcdClient == nil {
+		return false
+	}
+	ctx := GlobalContext
+	key := path.Join(getConfigEventsPrefix(), kind, name)
+	if err := saveKeyEtcd(ctx, globalEtcdClient, key, []byte(configEventOrigin)); err != nil {
+		reqInfo := (&logger.ReqInfo{}).AppendTags("configEvent", key)
+		logger.LogIf(logger.SetReqInfo(ctx, reqInfo), err)
+		return false
+	}
+	return true
+}
+
+// parseConfigEventKey - returns the kind and name of a config event key.
+func parseConfigEventKey(key string) (kind, name string, ok bool) {
+	prefix := getConfigEventsPrefix()
+	if !strings.HasPrefix(key, prefix) {
+		return "", "", false
+	}
+	tokens := strings.SplitN(strings.TrimPrefix(key, prefix), SlashSeparator, 2)
+	if len(tokens) != 2 || tokens[0] == "" || tokens[1] == "" {
+		return "", "", false
+	}
+	return tokens[0], tokens[1], true
+}
+
+// reloadServerConfig - reloads the server config from the backend along
+// with the notification targets configured in it.
+func reloadServerConfig(objAPI ObjectLayer) error {
+	if err := loadConfig(objAPI); err != nil {
+		return err
+	}
+	globalNotificationSys.ReloadTargets(globalServerConfig)
+	return nil
+}
+
+// reloadConfig - reloads the configuration of the given kind for name
+// from the backend, name is the bucket name of bucket configurations.
+func reloadConfig(ctx context.Context, objAPI ObjectLayer, kind, name string) error {
+	switch kind {
+	case configEventServerConfig:
+		return reloadServerConfig(objAPI)
+	case configEventBucketPolicy:
+		config, err := objAPI.GetBucketPolicy(ctx, name)
+		if err != nil {
+			if _, ok := err.(BucketPolicyNotFound); ok {
+				globalPolicySys.Remove(name)
+				return nil
+			}
+			return err
+		}
+		globalPolicySys.Set(name, *config)
+	case configEventBucketLifecycle:
+		config, err := getLifecycleConfig(objAPI, name)
+		if err != nil {
+			if _, ok := err.(BucketLifecycleNotFound); ok {
+				globalLifecycleSys.Remove(name)
+				return nil
+			}
+			return err
+		}
+		globalLifecycleSys.Set(name, *config)
+	case configEventBucketNotification:
+		config, err := readNotificationConfig(ctx, objAPI, name)
+		if err != nil {
+			if err == errNoSuchNotifications {
+				globalNotificationSys.AddRulesMap(name, event.RulesMap{})
+				return nil
+			}
+			return err
+		}
+		globalNotificationSys.AddRulesMap(name, config.ToRulesMap())
+	case configEventBucketLogging:
+		status, err := getBucketLoggingConfig(objAPI, name)
+		if err != nil {
+			if err == errConfigNotFound {
+				globalBucketLoggingSys.Remove(name)
+				return nil
+			}
+			return err
+		}
+		if status.LoggingEnabled == nil {
+			globalBucketLoggingSys.Remove(name)
+			return nil
+		}
+		globalBucketLoggingSys.Set(name, *status.LoggingEnabled)
+	case configEventBucketCORS:
+		config, err := getBucketCORSConfig(objAPI, name)
+		if err != nil {
+			if err == errConfigNotFound {
+				globalBucketCORSSys.Remove(name)
+				return nil
+			}
+			return err
+		}
+		globalBucketCORSSys.Set(name, *config)
+	case configEventBucketWebsite:
+		config, err := getBucketWebsiteConfig(objAPI, name)
+		if err != nil {
+			if err == errConfigNotFound {
+				globalBucketWebsiteSys.Remove(name)
+				return nil
+			}
+			return err
+		}
+		globalBucketWebsiteSys.Set(name, *config)
+	}
+	return nil
+}
+
+// watchConfigEvents - watches etcd for configuration changes published
+// by other servers and reloads them until the server is stopped.
+func watchConfigEvents(objAPI ObjectLayer) {
+	ctx := GlobalContext
+	prefix := getConfigEventsPrefix()
+	// Revision of the last processed events, watching is resumed
+	// right after it so that no event is missed on retries.
+	var lastRev int64
+	for {
+		opts := []etcd.OpOption{etcd.WithPrefix()}
+		if lastRev > 0 {
+			opts = append(opts, etcd.WithRev(lastRev+1))
+		}
+		watchCtx, cancel := context.WithCancel(ctx)
+		watchCh := globalEtcdClient.Watch(watchCtx, prefix, opts...)
+		for watchResp := range watchCh {
+			if watchResp.CompactRevision != 0 {
+				// Events up to the compacted revision are lost,
+				// resume from the oldest available one.
+				lastRev = watchResp.CompactRevision - 1
+			}
+			if err := watchResp.Err(); err != nil {
+				logger.LogIf(ctx, err)
+				break
+			}
+			for _, ev := range watchResp.Events {
+				lastRev = ev.Kv.ModRevision
+				if !(ev.IsCreate() || ev.IsModify()) || string(ev.Kv.Value) == configEventOrigin {
+					continue
+				}
+				kind, name, ok := parseConfigEventKey(string(ev.Kv.Key))
+				if !ok {
+					continue
+				}
+				reqInfo := (&logger.ReqInfo{}).AppendTags("configEvent", string(ev.Kv.Key))
+				logger.LogIf(logger.SetReqInfo(ctx, reqInfo), reloadConfig(ctx, objAPI, kind, name))
+			}
+		}
+		cancel()
+		// Watch channel is closed on errors and on shutdown,
+		// retry after a second unless shutting down.
+		if !sleepContext(ctx, time.Second) {
+			return
+		}
+	}
+}
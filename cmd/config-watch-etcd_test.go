@@ -0,0 +1,126 @@
+/*
+ * MinIO Cloud Storage, (C) 2019 MinIO, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"context"
+	"os"
+	"testing"
+
+	"github.com/minio/minio/pkg/policy"
+)
+
+func TestParseConfigEventKey(t *testing.T) {
+	prevDeploymentID := globalDeploymentID
+	defer func() {
+		globalDeploymentID = prevDeploymentID
+	}()
+	globalDeploymentID = "c7d8a1a3-2a0e-4f7b-9d6c-4b8f5ad8d0e1"
+
+	testCases := []struct {
+		key          string
+		expectedKind string
+		expectedName string
+		expectedOk   bool
+	}{
+		{"config/events/c7d8a1a3-2a0e-4f7b-9d6c-4b8f5ad8d0e1/bucket-policy/mybucket", configEventBucketPolicy, "mybucket", true},
+		{"config/events/c7d8a1a3-2a0e-4f7b-9d6c-4b8f5ad8d0e1/bucket-lifecycle/mybucket", configEventBucketLifecycle, "mybucket", true},
+		{"config/events/c7d8a1a3-2a0e-4f7b-9d6c-4b8f5ad8d0e1/server-config/config.json", configEventServerConfig, "config.json", true},
+		{"config/events/c7d8a1a3-2a0e-4f7b-9d6c-4b8f5ad8d0e1/bucket-policy/", "", "", false},
+		{"config/events/c7d8a1a3-2a0e-4f7b-9d6c-4b8f5ad8d0e1/bucket-policy", "", "", false},
+		// Event of another deployment sharing etcd.
+		{"config/events/0b5d3c8e-6f1a-4d2b-8e7c-9a4f3b2d1c0e/bucket-policy/mybucket", "", "", false},
+		{"config/events/bucket-policy/mybucket", "", "", false},
+		{"config/iam/users/foo", "", "", false},
+	}
+
+	for i, testCase := range testCases {
+		kind, name, ok := parseConfigEventKey(testCase.key)
+		if ok != testCase.expectedOk {
+			t.Fatalf("Test %d: expected %v, got %v", i+1, testCase.expectedOk, ok)
+		}
+		if kind != testCase.expectedKind || name != testCase.expectedName {
+			t.Errorf("Test %d: expected %s/%s, got %s/%s", i+1, testCase.expectedKind, testCase.expectedName, kind, name)
+		}
+	}
+}
+
+func TestReloadConfig(t *testing.T) {
+	objLayer, fsDir, err := prepareFS()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(fsDir)
+	if err = newTestConfig(globalMinioDefaultRegion, objLayer); err != nil {
+		t.Fatal(err)
+	}
+	globalPolicySys = NewPolicySys()
+
+	ctx := context.Background()
+	bucketName := "mybucket"
+	if err = objLayer.MakeBucketWithLocation(ctx, bucketName, ""); err != nil {
+		t.Fatal(err)
+	}
+
+	args := policy.Args{
+		Action:     policy.ListBucketAction,
+		BucketName: bucketName,
+	}
+
+	// Policy saved by another server.
+	if err = objLayer.SetBucketPolicy(ctx, bucketName, getAnonReadOnlyBucketPolicy(bucketName)); err != nil {
+		t.Fatal(err)
+	}
+	if err = reloadConfig(ctx, objLayer, configEventBucketPolicy, bucketName); err != nil {
+		t.Fatal(err)
+	}
+	if !globalPolicySys.IsAllowed(args) {
+		t.Fatalf("Expected reloaded bucket policy to allow anonymous listing")
+	}
+
+	// Policy removed by another server.
+	if err = objLayer.DeleteBucketPolicy(ctx, bucketName); err != nil {
+		t.Fatal(err)
+	}
+	if err = reloadConfig(ctx, objLayer, configEventBucketPolicy, bucketName); err != nil {
+		t.Fatal(err)
+	}
+	if globalPolicySys.IsAllowed(args) {
+		t.Fatalf("Expected removed bucket policy to deny anonymous listing")
+	}
+
+	// Server config changed by another server.
+	prevRegion, prevNotificationSys := globalServerRegion, globalNotificationSys
+	defer func() {
+		globalServerRegion, globalNotificationSys = prevRegion, prevNotificationSys
+	}()
+	globalNotificationSys = NewNotificationSys(globalServerConfig, EndpointList{})
+	config, err := readServerConfig(ctx, objLayer)
+	if err != nil {
+		t.Fatal(err)
+	}
+	config.SetRegion("eu-central-1")
+	if err = saveServerConfig(ctx, objLayer, config); err != nil {
+		t.Fatal(err)
+	}
+	if err = reloadConfig(ctx, objLayer, configEventServerConfig, minioConfigFile); err != nil {
+		t.Fatal(err)
+	}
+	if region := globalServerConfig.GetRegion(); region != "eu-central-1" {
+		t.Fatalf("Expected reloaded server config region eu-central-1, got %s", region)
+	}
+}
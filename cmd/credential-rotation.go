@@ -0,0 +1,100 @@
+/*
+ * MinIO Cloud Storage, (C) 2019 MinIO, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/minio/minio/pkg/auth"
+)
+
+// graceCredential - the root credential replaced by the last rotation
+// and the end of its grace period, persisted with the server config so
+// that all servers honor it, also across restarts.
+type graceCredential struct {
+	Credential auth.Credentials `json:"credential"`
+	Expiry     time.Time        `json:"expiry"`
+}
+
+// newGraceCredential - returns cred valid for presigned URLs during
+// globalCredentialGracePeriod, nil if the period is zero.
+func newGraceCredential(cred auth.Credentials) *graceCredential {
+	if globalCredentialGracePeriod <= 0 {
+		return nil
+	}
+	return &graceCredential{
+		Credential: cred,
+		Expiry:     UTCNow().Add(globalCredentialGracePeriod),
+	}
+}
+
+// prevCredential - the root credential replaced by the last rotation,
+// URLs presigned with it remain valid until expiry so that rotating
+// the credentials does not break them right away.
+type prevCredential struct {
+	mu     sync.RWMutex
+	cred   auth.Credentials
+	expiry time.Time
+}
+
+var globalPrevCredential = &prevCredential{}
+
+// load - sets the previous root credential from the server config,
+// a nil grace credential clears it.
+func (p *prevCredential) load(g *graceCredential) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if g == nil {
+		p.cred, p.expiry = auth.Credentials{}, time.Time{}
+		return
+	}
+	p.cred, p.expiry = g.Credential, g.Expiry
+}
+
+// get - returns the previous root credential if its access key is
+// accessKey and it is still within the grace period.
+func (p *prevCredential) get(accessKey string) (auth.Credentials, bool) {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+
+	if p.cred.AccessKey == "" || p.cred.AccessKey != accessKey || UTCNow().After(p.expiry) {
+		return auth.Credentials{}, false
+	}
+	return p.cred, true
+}
+
+// reloadCredentials - reloads the root credential and the previous one
+// still within its grace period from the server config.
+func reloadCredentials(ctx context.Context, objAPI ObjectLayer) error {
+	config, err := readServerConfig(ctx, objAPI)
+	if err != nil {
+		return err
+	}
+
+	globalServerConfigMu.Lock()
+	defer globalServerConfigMu.Unlock()
+
+	if !globalIsEnvCreds {
+		globalServerConfig.SetCredential(config.Credential)
+	}
+	globalServerConfig.PrevCredential = config.PrevCredential
+	globalPrevCredential.load(config.PrevCredential)
+	return nil
+}
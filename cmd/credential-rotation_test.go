@@ -0,0 +1,189 @@
+/*
+ * MinIO Cloud Storage, (C) 2019 MinIO, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"context"
+	"net/http"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/minio/minio/pkg/auth"
+	"github.com/minio/minio/pkg/madmin"
+)
+
+func TestPresignedCredentialGracePeriod(t *testing.T) {
+	obj, fsDir, err := prepareFS()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(fsDir)
+	if err = newTestConfig(globalMinioDefaultRegion, obj); err != nil {
+		t.Fatal(err)
+	}
+
+	prevGracePeriod := globalCredentialGracePeriod
+	defer func() {
+		globalCredentialGracePeriod = prevGracePeriod
+		globalPrevCredential = &prevCredential{}
+	}()
+
+	region := globalServerConfig.GetRegion()
+	prevCred := globalServerConfig.GetCredential()
+
+	presign := func(v2 bool) *http.Request {
+		req, err := newTestRequest(http.MethodGet, "http://127.0.0.1:9000/bucket/object", 0, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if v2 {
+			err = preSignV2(req, prevCred.AccessKey, prevCred.SecretKey, 600)
+		} else {
+			err = preSignV4(req, prevCred.AccessKey, prevCred.SecretKey, 600)
+		}
+		if err != nil {
+			t.Fatal(err)
+		}
+		req.RequestURI = req.URL.RequestURI()
+		return req
+	}
+	verify := func(req *http.Request, v2 bool) APIErrorCode {
+		if v2 {
+			if _, _, s3Err := getReqAccessKeyV2(req); s3Err != ErrNone {
+				return s3Err
+			}
+			return doesPresignV2SignatureMatch(req)
+		}
+		if _, _, s3Err := getReqAccessKeyV4(req, region, serviceS3); s3Err != ErrNone {
+			return s3Err
+		}
+		return isReqAuthenticated(context.Background(), req, region, serviceS3)
+	}
+
+	testCases := []struct {
+		newAccessKey string
+		gracePeriod  time.Duration
+		expired      bool
+		expected     APIErrorCode
+	}{
+		// Only the secret key changed.
+		{prevCred.AccessKey, time.Hour, false, ErrNone},
+		{prevCred.AccessKey, 0, false, ErrSignatureDoesNotMatch},
+		{prevCred.AccessKey, time.Hour, true, ErrSignatureDoesNotMatch},
+		// Both keys changed.
+		{"newaccesskey", time.Hour, false, ErrNone},
+		{"newaccesskey", 0, false, ErrInvalidAccessKeyID},
+		{"newaccesskey", time.Hour, true, ErrInvalidAccessKeyID},
+	}
+	for i, testCase := range testCases {
+		for _, v2 := range []bool{false, true} {
+			globalServerConfig.SetCredential(prevCred)
+			globalPrevCredential = &prevCredential{}
+			globalCredentialGracePeriod = testCase.gracePeriod
+
+			req := presign(v2)
+
+			creds, err := auth.CreateCredentials(testCase.newAccessKey, "newsecretkey")
+			if err != nil {
+				t.Fatal(err)
+			}
+			globalPrevCredential.load(newGraceCredential(globalServerConfig.SetCredential(creds)))
+			if testCase.expired {
+				globalPrevCredential.expiry = UTCNow().Add(-time.Second)
+			}
+
+			if s3Err := verify(req, v2); s3Err != testCase.expected {
+				t.Errorf("Test %d (v2: %t): expected %v, got %v", i+1, v2, testCase.expected, s3Err)
+			}
+		}
+	}
+
+	// An IAM user created with the previous root access key does
+	// not match URLs presigned with the previous root secret key.
+	globalIAMSys = NewIAMSys()
+	globalIAMSys.Init(obj)
+	globalCredentialGracePeriod = time.Hour
+	globalServerConfig.SetCredential(prevCred)
+	for _, v2 := range []bool{false, true} {
+		req := presign(v2)
+		creds, err := auth.CreateCredentials("newaccesskey", "newsecretkey")
+		if err != nil {
+			t.Fatal(err)
+		}
+		globalPrevCredential.load(newGraceCredential(globalServerConfig.SetCredential(creds)))
+		if err = globalIAMSys.SetUser(prevCred.AccessKey, madmin.UserInfo{
+			SecretKey: "iamusersecretkey",
+			Status:    madmin.AccountEnabled,
+		}); err != nil {
+			t.Fatal(err)
+		}
+		if s3Err := verify(req, v2); s3Err != ErrSignatureDoesNotMatch {
+			t.Errorf("IAM user (v2: %t): expected %v, got %v", v2, ErrSignatureDoesNotMatch, s3Err)
+		}
+		if err = globalIAMSys.DeleteUser(prevCred.AccessKey); err != nil {
+			t.Fatal(err)
+		}
+		globalServerConfig.SetCredential(prevCred)
+	}
+	globalServerConfig.SetCredential(prevCred)
+}
+
+func TestReloadCredentials(t *testing.T) {
+	obj, fsDir, err := prepareFS()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(fsDir)
+	if err = newTestConfig(globalMinioDefaultRegion, obj); err != nil {
+		t.Fatal(err)
+	}
+
+	prevGracePeriod := globalCredentialGracePeriod
+	defer func() {
+		globalCredentialGracePeriod = prevGracePeriod
+		globalPrevCredential = &prevCredential{}
+	}()
+	globalCredentialGracePeriod = time.Hour
+
+	prevCred := globalServerConfig.GetCredential()
+	defer globalServerConfig.SetCredential(prevCred)
+
+	// Persist rotated credentials as SetAuth does.
+	config := *globalServerConfig
+	config.Credential, err = auth.CreateCredentials("newaccesskey", "newsecretkey")
+	if err != nil {
+		t.Fatal(err)
+	}
+	config.PrevCredential = newGraceCredential(prevCred)
+	if err = saveServerConfig(context.Background(), obj, &config); err != nil {
+		t.Fatal(err)
+	}
+
+	// A peer or restarted server picks up the grace credential.
+	globalPrevCredential = &prevCredential{}
+	if err = reloadCredentials(context.Background(), obj); err != nil {
+		t.Fatal(err)
+	}
+	if cred := globalServerConfig.GetCredential(); cred.AccessKey != "newaccesskey" {
+		t.Errorf("Expected root access key newaccesskey, got %s", cred.AccessKey)
+	}
+	if cred, ok := globalPrevCredential.get(prevCred.AccessKey); !ok || cred.SecretKey != prevCred.SecretKey {
+		t.Errorf("Expected previous root credential to be valid after reload")
+	}
+	globalServerConfig.PrevCredential = nil
+}
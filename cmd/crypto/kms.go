@@ -101,6 +101,16 @@ type KMS interface {
 	UpdateKey(keyID string, sealedKey []byte, context Context) (rotatedKey []byte, err error)
 }
 
+// KeyRotator is implemented by KMS implementations which are able
+// to rotate a master key. Data keys sealed with a previous version
+// of the master key can still be unsealed and are re-wrapped with
+// the latest version by UpdateKey.
+type KeyRotator interface {
+	// RotateKey creates a new version of the master key
+	// referenced by keyID.
+	RotateKey(keyID string) error
+}
+
 type masterKeyKMS struct {
 	masterKey [32]byte
 }
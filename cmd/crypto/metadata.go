@@ -58,6 +58,7 @@ func RemoveInternalEntries(metadata map[string]string) {
 	delete(metadata, S3SealedKey)
 	delete(metadata, S3KMSKeyID)
 	delete(metadata, S3KMSSealedKey)
+	delete(metadata, S3KMSRequested)
 }
 
 // IsEncrypted returns true if the object metadata indicates
@@ -99,6 +100,14 @@ func (s3) IsEncrypted(metadata map[string]string) bool {
 	return false
 }
 
+// IsEncrypted returns true if the object metadata indicates
+// that the object was uploaded using SSE-KMS. Such objects are
+// also SSE-S3 encrypted objects.
+func (s3KMS) IsEncrypted(metadata map[string]string) bool {
+	_, ok := metadata[S3KMSRequested]
+	return ok
+}
+
 // IsEncrypted returns true if the object metadata indicates
 // that the object was uploaded using SSE-C.
 func (ssec) IsEncrypted(metadata map[string]string) bool {
@@ -138,6 +147,16 @@ func (s3) CreateMetadata(metadata map[string]string, keyID string, kmsKey []byte
 	return metadata
 }
 
+// CreateMetadata encodes the keyID requested by the client, the sealed
+// kms data key and the sealed key into the metadata like SSE-S3 and
+// marks the object as uploaded using SSE-KMS. It allocates a new
+// metadata map if metadata is nil.
+func (s3KMS) CreateMetadata(metadata map[string]string, keyID string, kmsKey []byte, sealedKey SealedKey) map[string]string {
+	metadata = S3.CreateMetadata(metadata, keyID, kmsKey, sealedKey)
+	metadata[S3KMSRequested] = ""
+	return metadata
+}
+
 // ParseMetadata extracts all SSE-S3 related values from the object metadata
 // and checks whether they are well-formed. It returns the KMS key-ID, the
 // sealed KMS key and the sealed object key on success.
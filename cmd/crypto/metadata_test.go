@@ -327,6 +327,29 @@ func TestS3CreateMetadata(t *testing.T) {
 	_ = S3.CreateMetadata(nil, "", []byte{}, SealedKey{Algorithm: InsecureSealAlgorithm})
 }
 
+func TestS3KMSCreateMetadata(t *testing.T) {
+	if S3KMS.IsEncrypted(S3.CreateMetadata(nil, "my-key", make([]byte, 48), SealedKey{Algorithm: SealAlgorithm})) {
+		t.Fatal("SSE-S3 metadata must not be SSE-KMS metadata")
+	}
+
+	metadata := S3KMS.CreateMetadata(nil, "my-key", make([]byte, 48), SealedKey{Algorithm: SealAlgorithm})
+	if !S3KMS.IsEncrypted(metadata) || !S3.IsEncrypted(metadata) {
+		t.Fatalf("Expected SSE-KMS metadata to be SSE-S3 and SSE-KMS metadata: %v", metadata)
+	}
+	keyID, _, _, err := S3.ParseMetadata(metadata)
+	if err != nil {
+		t.Fatalf("Failed to parse metadata: %v", err)
+	}
+	if keyID != "my-key" {
+		t.Fatalf("Key-ID mismatch: got '%s' - want '%s'", keyID, "my-key")
+	}
+
+	RemoveInternalEntries(metadata)
+	if S3KMS.IsEncrypted(metadata) || len(metadata) != 0 {
+		t.Fatalf("Expected internal entries to be removed, got %v", metadata)
+	}
+}
+
 var ssecCreateMetadataTests = []struct {
 	KeyID         string
 	SealedDataKey []byte
@@ -53,6 +53,11 @@ const (
 	// S3KMSSealedKey is the metadata key referencing the encrypted key generated
 	// by KMS. It is only used for SSE-S3 + KMS.
 	S3KMSSealedKey = "X-Minio-Internal-Server-Side-Encryption-S3-Kms-Sealed-Key"
+
+	// S3KMSRequested is the metadata key marking objects uploaded using
+	// SSE-KMS. They are sealed like SSE-S3 objects, under the KMS key-id
+	// requested by the client which is kept in S3KMSKeyID.
+	S3KMSRequested = "X-Minio-Internal-Server-Side-Encryption-S3-Kms-Requested"
 )
 
 const (
@@ -277,3 +277,11 @@ func (v *vaultService) UpdateKey(keyID string, sealedKey []byte, ctx Context) (r
 	rotatedKey = []byte(ciphertext.(string))
 	return rotatedKey, nil
 }
+
+// RotateKey creates a new version of the named key referenced by
+// keyID. Keys sealed with previous versions can still be unsealed
+// until the KMS operator retires those versions.
+func (v *vaultService) RotateKey(keyID string) error {
+	_, err := v.client.Logical().Write(fmt.Sprintf("/transit/keys/%s/rotate", keyID), nil)
+	return err
+}
@@ -17,7 +17,6 @@
 package cmd
 
 import (
-	"context"
 	"sync"
 	"time"
 
@@ -34,7 +33,7 @@ const (
 func newBgHealSequence(numDisks int) *healSequence {
 
 	reqInfo := &logger.ReqInfo{API: "BackgroundHeal"}
-	ctx := logger.SetReqInfo(context.Background(), reqInfo)
+	ctx := logger.SetReqInfo(GlobalContext, reqInfo)
 
 	hs := madmin.HealOpts{
 		// Remove objects that do not have read-quorum
@@ -78,13 +77,15 @@ func initDailyHeal() {
 
 func startDailyHeal() {
 	var objAPI ObjectLayer
-	var ctx = context.Background()
+	var ctx = GlobalContext
 
 	// Wait until the object API is ready
 	for {
 		objAPI = newObjectLayerFn()
 		if objAPI == nil {
-			time.Sleep(time.Second)
+			if !sleepContext(ctx, time.Second) {
+				return
+			}
 			continue
 		}
 		break
@@ -21,12 +21,16 @@ import (
 	"time"
 
 	"github.com/minio/minio/cmd/logger"
+	"github.com/minio/minio/pkg/event"
 	"github.com/minio/minio/pkg/lifecycle"
 )
 
 const (
 	bgLifecycleInterval = 24 * time.Hour
 	bgLifecycleTick     = time.Hour
+
+	// User agent of the events and audit entries of lifecycle expirations.
+	lifecycleExpiryUserAgent = "Internal: [ILM-EXPIRY]"
 )
 
 type lifecycleOps struct {
@@ -42,27 +46,10 @@ func getLocalBgLifecycleOpsStatus() BgLifecycleOpsStatus {
 	}
 }
 
-// initDailyLifecycle starts the routine that receives the daily
-// listing of all objects and applies any matching bucket lifecycle
-// rules.
-func initDailyLifecycle() {
-	go startDailyLifecycle()
-}
-
-func startDailyLifecycle() {
-	var objAPI ObjectLayer
-	var ctx = context.Background()
-
-	// Wait until the object API is ready
-	for {
-		objAPI = newObjectLayerFn()
-		if objAPI == nil {
-			time.Sleep(time.Second)
-			continue
-		}
-		break
-	}
-
+// runDailyLifecycle applies the matching bucket lifecycle rules to
+// all objects if no server of the cluster did in the last day. It is
+// run every bgLifecycleTick by the background job scheduler.
+func runDailyLifecycle(ctx context.Context, objAPI ObjectLayer, runNow bool) error {
 	// Calculate the time of the last lifecycle operation in all peers node of the cluster
 	computeLastLifecycleActivity := func(status []BgOpsStatus) time.Time {
 		var lastAct time.Time
@@ -74,8 +61,8 @@ func startDailyLifecycle() {
 		return lastAct
 	}
 
-	for {
-		// Check if we should perform lifecycle ops based on the last lifecycle activity, sleep one hour otherwise
+	// Check if we should perform lifecycle ops based on the last lifecycle activity
+	if !runNow {
 		allLifecycleStatus := []BgOpsStatus{
 			{LifecycleOps: getLocalBgLifecycleOpsStatus()},
 		}
@@ -84,23 +71,18 @@ func startDailyLifecycle() {
 		}
 		lastAct := computeLastLifecycleActivity(allLifecycleStatus)
 		if !lastAct.IsZero() && time.Since(lastAct) < bgLifecycleInterval {
-			time.Sleep(bgLifecycleTick)
+			return nil
 		}
+	}
 
-		// Perform one lifecycle operation
-		err := lifecycleRound(ctx, objAPI)
-		switch err.(type) {
+	// Perform one lifecycle operation
+	err := lifecycleRound(ctx, objAPI)
+	if _, ok := err.(OperationTimedOut); ok {
 		// Unable to hold a lock means there is another
-		// instance doing the lifecycle round round
-		case OperationTimedOut:
-			time.Sleep(bgLifecycleTick)
-		default:
-			logger.LogIf(ctx, err)
-			time.Sleep(time.Minute)
-			continue
-		}
-
+		// instance doing the lifecycle round
+		return nil
 	}
+	return err
 }
 
 func lifecycleRound(ctx context.Context, objAPI ObjectLayer) error {
@@ -121,6 +103,11 @@ func lifecycleRound(ctx context.Context, objAPI ObjectLayer) error {
 	}
 
 	for _, bucket := range buckets {
+		// Quit early if the server is shutting down.
+		if err = ctx.Err(); err != nil {
+			return err
+		}
+
 		// Check if the current bucket has a configured lifecycle policy, skip otherwise
 		l, ok := globalLifecycleSys.Get(bucket.Name)
 		if !ok {
@@ -137,19 +124,36 @@ func lifecycleRound(ctx context.Context, objAPI ObjectLayer) error {
 		// List all objects and calculate lifecycle action based on object name & object modtime
 		marker := ""
 		for {
+			if err = ctx.Err(); err != nil {
+				return err
+			}
 			res, err := objAPI.ListObjects(ctx, bucket.Name, commonPrefix, marker, "", 1000)
 			if err != nil {
-				continue
+				return err
 			}
 			for _, obj := range res.Objects {
 				// Find the action that need to be executed
 				action := l.ComputeAction(obj.Name, obj.ModTime)
 				switch action {
 				case lifecycle.DeleteAction:
-					objAPI.DeleteObject(ctx, bucket.Name, obj.Name)
+					err = objAPI.DeleteObject(ctx, bucket.Name, obj.Name)
+				case lifecycle.ArchiveAction:
+					// Objects which cannot be archived are kept.
+					err = archiveLifecycleObject(ctx, objAPI, bucket.Name, obj, *l.ArchiveTarget(obj.Name))
+					if err != nil && !isErrObjectNotFound(err) {
+						logger.LogIf(ctx, err)
+					}
 				default:
-					// Nothing
-
+					continue
+				}
+				if err == nil {
+					notifyLifecycleExpiration(bucket.Name, obj)
+				}
+				// Pace expiry by the expired bytes.
+				if globalBandwidthSys != nil {
+					if err = globalBandwidthSys.Wait(ctx, bucket.Name, obj.Size); err != nil {
+						return err
+					}
 				}
 			}
 			if !res.IsTruncated {
@@ -158,7 +162,65 @@ func lifecycleRound(ctx context.Context, objAPI ObjectLayer) error {
 				marker = res.NextMarker
 			}
 		}
+
+		if err = abortIncompleteMultipartUploads(ctx, objAPI, bucket.Name, l, UTCNow()); err != nil {
+			return err
+		}
 	}
 
 	return nil
 }
+
+// abortIncompleteMultipartUploads - aborts the incomplete multipart
+// uploads of bucket initiated longer ago than the days of the matching
+// AbortIncompleteMultipartUpload rule of its lifecycle. Uploads are
+// also aborted once older than GlobalMultipartExpiry regardless of the
+// rules, which only take effect when they are shorter.
+func abortIncompleteMultipartUploads(ctx context.Context, objAPI ObjectLayer, bucket string, l lifecycle.Lifecycle, now time.Time) error {
+	hasRule := false
+	for _, rule := range l.Rules {
+		if rule.AbortIncompleteMultipartUpload != nil {
+			hasRule = true
+			break
+		}
+	}
+	if !hasRule {
+		return nil
+	}
+
+	uploads, err := listIncompleteUploads(ctx, objAPI, bucket, "")
+	if err != nil {
+		// Gateways cannot list the uploads of a bucket.
+		if _, ok := err.(NotImplemented); ok {
+			return nil
+		}
+		return err
+	}
+	for _, upload := range uploads {
+		days := l.AbortIncompleteMultipartUploadDays(upload.Object)
+		if days == 0 || now.Sub(upload.Initiated) < time.Duration(days)*24*time.Hour {
+			continue
+		}
+		if err = objAPI.AbortMultipartUpload(ctx, bucket, upload.Object, upload.UploadID); err != nil {
+			// Upload completed or aborted meanwhile.
+			if _, ok := err.(InvalidUploadID); !ok {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// notifyLifecycleExpiration - sends the event notification and audit
+// entry of an object removed by a lifecycle rule.
+func notifyLifecycleExpiration(bucket string, obj ObjectInfo) {
+	sendEvent(eventArgs{
+		EventName:  event.ObjectRemovedLifecycleExpiration,
+		BucketName: bucket,
+		Object: ObjectInfo{
+			Name: obj.Name,
+		},
+		UserAgent: lifecycleExpiryUserAgent,
+	})
+	logger.AuditLogInternal("LifecycleExpiration", bucket, obj.Name, lifecycleExpiryUserAgent)
+}
@@ -0,0 +1,271 @@
+/*
+ * MinIO Cloud Storage, (C) 2019 MinIO, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/minio/minio/cmd/logger"
+	"github.com/minio/minio/cmd/logger/message/audit"
+	"github.com/minio/minio/pkg/event"
+	"github.com/minio/minio/pkg/lifecycle"
+)
+
+type auditEntriesTarget struct {
+	mu      sync.Mutex
+	entries []audit.Entry
+}
+
+func (t *auditEntriesTarget) Send(e interface{}) error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.entries = append(t.entries, e.(audit.Entry))
+	return nil
+}
+
+// eventsTarget - records the events sent to it.
+type eventsTarget struct {
+	id     event.TargetID
+	mu     sync.Mutex
+	events []event.Event
+}
+
+func (t *eventsTarget) ID() event.TargetID { return t.id }
+func (t *eventsTarget) Send(string) error  { return nil }
+func (t *eventsTarget) Close() error       { return nil }
+
+func (t *eventsTarget) Save(e event.Event) error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.events = append(t.events, e)
+	return nil
+}
+
+// wait - returns the n events sent to t sorted by object key, events
+// are sent asynchronously.
+func (t *eventsTarget) wait(tb testing.TB, n int) []event.Event {
+	tb.Helper()
+	for i := 0; i < 100; i++ {
+		t.mu.Lock()
+		events := append([]event.Event(nil), t.events...)
+		t.mu.Unlock()
+		if len(events) >= n {
+			sort.Slice(events, func(i, j int) bool { return events[i].S3.Object.Key < events[j].S3.Object.Key })
+			return events
+		}
+		time.Sleep(100 * time.Millisecond)
+	}
+	tb.Fatalf("Expected %d events", n)
+	return nil
+}
+
+// Tests that objects removed by lifecycle are notified and audited.
+func TestLifecycleRoundNotify(t *testing.T) {
+	obj, fsDir, err := prepareFS()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(fsDir)
+	if err = newTestConfig(globalMinioDefaultRegion, obj); err != nil {
+		t.Fatal(err)
+	}
+
+	prevLifecycleSys, prevNotificationSys := globalLifecycleSys, globalNotificationSys
+	prevAuditTargets := logger.AuditTargets
+	defer func() {
+		globalLifecycleSys, globalNotificationSys = prevLifecycleSys, prevNotificationSys
+		logger.AuditTargets = prevAuditTargets
+	}()
+
+	ctx := context.Background()
+	bucket := "bucket"
+	if err = obj.MakeBucketWithLocation(ctx, bucket, ""); err != nil {
+		t.Fatal(err)
+	}
+	for _, object := range []string{"logs/a", "logs/b", "keep"} {
+		if _, err = obj.PutObject(ctx, bucket, object, mustGetPutObjReader(t, bytes.NewReader([]byte("data")), 4, "", ""), ObjectOptions{}); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	lc, err := lifecycle.ParseLifecycleConfig(strings.NewReader(`<LifecycleConfiguration><Rule><ID>logs</ID><Filter><Prefix>logs/</Prefix></Filter><Status>Enabled</Status><Expiration><Date>2019-01-01T00:00:00.000Z</Date></Expiration></Rule></LifecycleConfiguration>`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	globalLifecycleSys = NewLifecycleSys()
+	globalLifecycleSys.Set(bucket, *lc)
+
+	globalNotificationSys = NewNotificationSys(globalServerConfig, globalEndpoints)
+	if err = globalNotificationSys.Init(obj); err != nil {
+		t.Fatal(err)
+	}
+	targetID := event.TargetID{ID: "1", Name: "test"}
+	target := &eventsTarget{id: targetID}
+	if err = globalNotificationSys.targetList.Add(target); err != nil {
+		t.Fatal(err)
+	}
+	globalNotificationSys.AddRulesMap(bucket, event.NewRulesMap([]event.Name{event.ObjectRemovedAll}, "*", targetID))
+
+	auditTarget := &auditEntriesTarget{}
+	logger.AuditTargets = []logger.Target{auditTarget}
+
+	if err = lifecycleRound(ctx, obj); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err = obj.GetObjectInfo(ctx, bucket, "keep", ObjectOptions{}); err != nil {
+		t.Fatal(err)
+	}
+
+	events := target.wait(t, 2)
+	for i, object := range []string{"logs/a", "logs/b"} {
+		if events[i].EventName != event.ObjectRemovedLifecycleExpiration || events[i].S3.Object.Key != strings.Replace(object, "/", "%2F", -1) {
+			t.Errorf("Unexpected event %d: %v %s", i+1, events[i].EventName, events[i].S3.Object.Key)
+		}
+		if events[i].Source.UserAgent != lifecycleExpiryUserAgent {
+			t.Errorf("Unexpected user agent %q", events[i].Source.UserAgent)
+		}
+	}
+
+	auditTarget.mu.Lock()
+	defer auditTarget.mu.Unlock()
+	if len(auditTarget.entries) != 2 {
+		t.Fatalf("Expected 2 audit entries, got %d", len(auditTarget.entries))
+	}
+	for i, object := range []string{"logs/a", "logs/b"} {
+		entry := auditTarget.entries[i]
+		if entry.API.Name != "LifecycleExpiration" || entry.API.Bucket != bucket || entry.API.Object != object {
+			t.Errorf("Unexpected audit entry %d: %v", i+1, entry.API)
+		}
+	}
+}
+
+func TestAbortIncompleteMultipartUploads(t *testing.T) {
+	obj, fsDir, err := prepareFS()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(fsDir)
+
+	ctx := context.Background()
+	bucket := "bucket"
+	if err = obj.MakeBucketWithLocation(ctx, bucket, ""); err != nil {
+		t.Fatal(err)
+	}
+	uploadIDs := make(map[string]string)
+	for _, object := range []string{"tmp/a", "keep"} {
+		if uploadIDs[object], err = obj.NewMultipartUpload(ctx, bucket, object, ObjectOptions{}); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	lc, err := lifecycle.ParseLifecycleConfig(strings.NewReader(`<LifecycleConfiguration><Rule><Filter><Prefix>tmp/</Prefix></Filter><Status>Enabled</Status><AbortIncompleteMultipartUpload><DaysAfterInitiation>7</DaysAfterInitiation></AbortIncompleteMultipartUpload></Rule></LifecycleConfiguration>`))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Uploads are kept until they are old enough.
+	if err = abortIncompleteMultipartUploads(ctx, obj, bucket, *lc, UTCNow().Add(6*24*time.Hour)); err != nil {
+		t.Fatal(err)
+	}
+	if uploads, err := listIncompleteUploads(ctx, obj, bucket, ""); err != nil || len(uploads) != 2 {
+		t.Fatalf("Expected 2 uploads, got %v, %v", uploads, err)
+	}
+
+	if err = abortIncompleteMultipartUploads(ctx, obj, bucket, *lc, UTCNow().Add(8*24*time.Hour)); err != nil {
+		t.Fatal(err)
+	}
+	uploads, err := listIncompleteUploads(ctx, obj, bucket, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(uploads) != 1 || uploads[0].Object != "keep" || uploads[0].UploadID != uploadIDs["keep"] {
+		t.Fatalf("Expected only the upload of keep to be left, got %v", uploads)
+	}
+}
+
+// Tests that expired objects of rules with an archive bucket are moved
+// to the archive bucket instead of being removed.
+func TestLifecycleRoundArchive(t *testing.T) {
+	obj, fsDir, err := prepareFS()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(fsDir)
+
+	prevLifecycleSys := globalLifecycleSys
+	defer func() { globalLifecycleSys = prevLifecycleSys }()
+
+	ctx := context.Background()
+	for _, bucket := range []string{"bucket", "archive"} {
+		if err = obj.MakeBucketWithLocation(ctx, bucket, ""); err != nil {
+			t.Fatal(err)
+		}
+	}
+	data := []byte("hello")
+	opts := ObjectOptions{UserDefined: map[string]string{"content-type": "text/plain", "X-Amz-Meta-Owner": "alice"}}
+	for _, object := range []string{"logs/a", "keep"} {
+		if _, err = obj.PutObject(ctx, "bucket", object, mustGetPutObjReader(t, bytes.NewReader(data), int64(len(data)), "", ""), opts); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	lc, err := lifecycle.ParseLifecycleConfig(strings.NewReader(`<LifecycleConfiguration><Rule><Filter><Prefix>logs/</Prefix></Filter><Status>Enabled</Status><Expiration><Date>2019-01-01T00:00:00.000Z</Date></Expiration><ArchiveTo><Bucket>archive</Bucket></ArchiveTo></Rule></LifecycleConfiguration>`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err = checkLifecycleArchiveBuckets(ctx, obj, "archive", lc); err != errLifecycleArchiveSameBucket {
+		t.Fatalf("Expected %v, got %v", errLifecycleArchiveSameBucket, err)
+	}
+	if err = checkLifecycleArchiveBuckets(ctx, obj, "bucket", lc); err != nil {
+		t.Fatal(err)
+	}
+	globalLifecycleSys = NewLifecycleSys()
+	globalLifecycleSys.Set("bucket", *lc)
+
+	if err = lifecycleRound(ctx, obj); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err = obj.GetObjectInfo(ctx, "bucket", "logs/a", ObjectOptions{}); !isErrObjectNotFound(err) {
+		t.Fatalf("Expected logs/a to be removed, got %v", err)
+	}
+	if _, err = obj.GetObjectInfo(ctx, "bucket", "keep", ObjectOptions{}); err != nil {
+		t.Fatal(err)
+	}
+	var buf bytes.Buffer
+	if err = obj.GetObject(ctx, "archive", "logs/a", 0, -1, &buf, "", ObjectOptions{}); err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(buf.Bytes(), data) {
+		t.Fatalf("Unexpected archived content %q", buf.Bytes())
+	}
+	objInfo, err := obj.GetObjectInfo(ctx, "archive", "logs/a", ObjectOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if objInfo.ContentType != "text/plain" || objInfo.UserDefined["X-Amz-Meta-Owner"] != "alice" {
+		t.Fatalf("Unexpected archived metadata %v", objInfo.UserDefined)
+	}
+}
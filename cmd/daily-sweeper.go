@@ -68,21 +68,36 @@ func sweepRound(ctx context.Context, objAPI ObjectLayer) error {
 
 	// List all objects, having read quorum or not in all buckets
 	// and send them to all the registered sweep listeners
+	// Send an entry to all listeners, quits if the server is shutting down.
+	notifyListeners := func(entry string) error {
+		for _, l := range copyDailySweepListeners() {
+			select {
+			case l <- entry:
+			case <-ctx.Done():
+				return ctx.Err()
+			}
+		}
+		return nil
+	}
+
 	for _, bucket := range buckets {
 		// Send bucket names to all listeners
-		for _, l := range copyDailySweepListeners() {
-			l <- bucket.Name
+		if err = notifyListeners(bucket.Name); err != nil {
+			return err
 		}
 
 		marker := ""
 		for {
+			if err = ctx.Err(); err != nil {
+				return err
+			}
 			res, err := objAPI.ListObjectsHeal(ctx, bucket.Name, "", marker, "", 1000)
 			if err != nil {
-				continue
+				return err
 			}
 			for _, obj := range res.Objects {
-				for _, l := range copyDailySweepListeners() {
-					l <- pathJoin(bucket.Name, obj.Name)
+				if err = notifyListeners(pathJoin(bucket.Name, obj.Name)); err != nil {
+					return err
 				}
 			}
 			if !res.IsTruncated {
@@ -107,13 +122,15 @@ func dailySweeper() {
 	var lastSweepTime time.Time
 	var objAPI ObjectLayer
 
-	var ctx = context.Background()
+	var ctx = GlobalContext
 
 	// Wait until the object layer is ready
 	for {
 		objAPI = newObjectLayerFn()
 		if objAPI == nil {
-			time.Sleep(time.Second)
+			if !sleepContext(ctx, time.Second) {
+				return
+			}
 			continue
 		}
 		break
@@ -122,11 +139,17 @@ func dailySweeper() {
 	// Perform a sweep round each month
 	for {
 		if time.Since(lastSweepTime) < 30*24*time.Hour {
-			time.Sleep(time.Hour)
+			if !sleepContext(ctx, time.Hour) {
+				return
+			}
 			continue
 		}
 
 		err := sweepRound(ctx, objAPI)
+		if ctx.Err() != nil {
+			// Server is shutting down.
+			return
+		}
 		if err != nil {
 			switch err.(type) {
 			// Unable to hold a lock means there is another
@@ -135,7 +158,9 @@ func dailySweeper() {
 				lastSweepTime = time.Now()
 			default:
 				logger.LogIf(ctx, err)
-				time.Sleep(time.Minute)
+				if !sleepContext(ctx, time.Minute) {
+					return
+				}
 				continue
 			}
 		} else {
@@ -0,0 +1,64 @@
+/*
+ * MinIO Cloud Storage, (C) 2020 MinIO, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"archive/zip"
+	"bytes"
+	"errors"
+	"runtime/pprof"
+)
+
+var errDiagnosticsUnknownNode = errors.New("Node is not part of the cluster")
+
+// Snapshots of the runtime state of a server returned by the
+// diagnostics peer and admin APIs, with the debug level passed to
+// pprof, 0 writes the binary format read by `go tool pprof`.
+var diagnosticsProfiles = []struct {
+	name  string
+	file  string
+	debug int
+}{
+	{"goroutine", "goroutines.txt", 2},
+	{"heap", "heap.pprof", 0},
+	{"threadcreate", "threadcreate.txt", 1},
+}
+
+// getDiagnosticsData - returns a zip archive of goroutine, heap and
+// thread creation snapshots of this server. Unlike profiling no
+// session has to be started first, the snapshots are taken at once.
+func getDiagnosticsData() ([]byte, error) {
+	var buf bytes.Buffer
+	zipWriter := zip.NewWriter(&buf)
+	for _, profile := range diagnosticsProfiles {
+		p := pprof.Lookup(profile.name)
+		if p == nil {
+			continue
+		}
+		zwriter, err := zipWriter.Create(profile.file)
+		if err != nil {
+			return nil, err
+		}
+		if err = p.WriteTo(zwriter, profile.debug); err != nil {
+			return nil, err
+		}
+	}
+	if err := zipWriter.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
@@ -29,12 +29,15 @@ import (
 	"os"
 	"path"
 	"reflect"
+	"sort"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/djherbis/atime"
 	"github.com/minio/minio/cmd/logger"
 	"github.com/minio/minio/pkg/disk"
+	"github.com/minio/minio/pkg/madmin"
 	"github.com/ncw/directio"
 )
 
@@ -47,6 +50,15 @@ const (
 	cacheEnvDelimiter = ";"
 )
 
+// Reasons of cache entry removals sent to cache event listeners.
+const (
+	cacheEventReasonExpired     = "expired"     // not accessed within the cache expiry.
+	cacheEventReasonStale       = "stale"       // stale as per its Cache-Control metadata.
+	cacheEventReasonIncomplete  = "incomplete"  // partially filled entry left behind.
+	cacheEventReasonInvalidated = "invalidated" // object changed or deleted at the backend.
+	cacheEventReasonQuota       = "quota"       // bucket above its cache quota.
+)
+
 // CacheChecksumInfoV1 - carries checksums of individual blocks on disk.
 type CacheChecksumInfoV1 struct {
 	Algorithm string `json:"algorithm"`
@@ -62,6 +74,12 @@ type cacheMeta struct {
 	Checksum CacheChecksumInfoV1 `json:"checksum,omitempty"`
 	// Metadata map for current object.
 	Meta map[string]string `json:"meta,omitempty"`
+	// Bucket of the current object, empty for entries cached
+	// before bucket quotas were supported.
+	Bucket string `json:"bucket,omitempty"`
+	// Erasure layout of entries striped across cache drives, the
+	// data file then only holds one shard of the object.
+	Erasure *cacheErasureInfo `json:"erasure,omitempty"`
 }
 
 func (m *cacheMeta) ToObjectInfo(bucket, object string) (o ObjectInfo) {
@@ -103,6 +121,14 @@ func (m *cacheMeta) ToObjectInfo(bucket, object string) (o ObjectInfo) {
 
 // represents disk cache struct
 type diskCache struct {
+	// cache drive statistics, updated atomically, kept first for
+	// the 64-bit alignment of atomic operations
+	hits      uint64
+	misses    uint64
+	fills     uint64
+	evictions uint64
+	ioErrors  uint64
+
 	dir             string // caching directory
 	maxDiskUsagePct int    // max usage in %
 	expiry          int    // cache expiry in days
@@ -113,6 +139,13 @@ type diskCache struct {
 	// purge() listens on this channel to start the cache-purge process
 	purgeChan chan struct{}
 	pool      sync.Pool
+	// purge() quits once ctx is canceled by close()
+	ctx    context.Context
+	cancel context.CancelFunc
+	// maximum percentage of the cache space used by the objects
+	// of a bucket, keyed by bucket
+	quota   map[string]int
+	quotaMu sync.RWMutex
 }
 
 // Inits the disk cache dir if it is not initialized already.
@@ -124,7 +157,10 @@ func newdiskCache(dir string, expiry int, maxDiskUsagePct int) (*diskCache, erro
 	if expiry == 0 {
 		expiry = globalCacheExpiry
 	}
+	ctx, cancel := context.WithCancel(GlobalContext)
 	cache := diskCache{
+		ctx:             ctx,
+		cancel:          cancel,
 		dir:             dir,
 		expiry:          expiry,
 		maxDiskUsagePct: maxDiskUsagePct,
@@ -186,12 +222,34 @@ func (c *diskCache) diskAvailable(size int64) bool {
 	return int(usedPercent) < c.maxDiskUsagePct
 }
 
+// setQuota - sets the cache quotas of buckets, applied by the next purge.
+func (c *diskCache) setQuota(quota map[string]int) {
+	c.quotaMu.Lock()
+	defer c.quotaMu.Unlock()
+	c.quota = quota
+}
+
+func (c *diskCache) getQuota() map[string]int {
+	c.quotaMu.RLock()
+	defer c.quotaMu.RUnlock()
+	return c.quota
+}
+
 // Purge cache entries that were not accessed.
 func (c *diskCache) purge() {
-	ctx := context.Background()
+	ctx := c.ctx
 	for {
+		// Buckets above their quota are purged first, so that
+		// they do not take the space of other buckets.
+		c.purgeOverQuota(ctx)
+
 		olderThan := c.expiry
 		for !c.diskUsageLow() {
+			// Quit if the server is shutting down or the drive
+			// was removed from the cache.
+			if ctx.Err() != nil {
+				return
+			}
 			// delete unaccessed objects older than expiry duration
 			expiry := UTCNow().AddDate(0, 0, -1*olderThan)
 			olderThan /= 2
@@ -206,6 +264,9 @@ func (c *diskCache) purge() {
 			}
 
 			for _, obj := range objDirs {
+				if ctx.Err() != nil {
+					return
+				}
 				if obj.Name() == minioMetaBucket {
 					continue
 				}
@@ -220,14 +281,21 @@ func (c *diskCache) purge() {
 				if err != nil {
 					// delete any partially filled cache entry left behind.
 					removeAll(pathJoin(c.dir, obj.Name()))
+					c.publishEvent(madmin.CacheEventPurge, cacheEventReasonIncomplete, obj.Name(), "", "", fi.Size())
 					continue
 				}
 				cc := cacheControlOpts(objInfo)
-				if atime.Get(fi).Before(expiry) ||
-					cc.isStale(objInfo.ModTime) {
+				expired := atime.Get(fi).Before(expiry)
+				if expired || cc.isStale(objInfo.ModTime) {
 					if err = removeAll(pathJoin(c.dir, obj.Name())); err != nil {
 						logger.LogIf(ctx, err)
 					}
+					reason := cacheEventReasonStale
+					if expired {
+						reason = cacheEventReasonExpired
+					}
+					atomic.AddUint64(&c.evictions, 1)
+					c.publishEvent(madmin.CacheEventEvict, reason, obj.Name(), "", "", objInfo.Size)
 					deletedCount++
 					// break early if sufficient disk space reclaimed.
 					if !c.diskUsageLow() {
@@ -241,7 +309,11 @@ func (c *diskCache) purge() {
 		}
 		lastRunTime := time.Now()
 		for {
-			<-c.purgeChan
+			select {
+			case <-c.purgeChan:
+			case <-ctx.Done():
+				return
+			}
 			timeElapsed := time.Since(lastRunTime)
 			if timeElapsed > time.Hour {
 				break
@@ -250,6 +322,122 @@ func (c *diskCache) purge() {
 	}
 }
 
+// purgeOverQuota - evicts the least recently accessed entries of the
+// buckets using more of the cache space than their quota, until they
+// are within their quota.
+func (c *diskCache) purgeOverQuota(ctx context.Context) {
+	quota := c.getQuota()
+	if len(quota) == 0 {
+		return
+	}
+	di, err := disk.GetInfo(c.dir)
+	if err != nil {
+		reqInfo := (&logger.ReqInfo{}).AppendTags("cachePath", c.dir)
+		logger.LogIf(logger.SetReqInfo(ctx, reqInfo), err)
+		return
+	}
+	cacheSpace := int64(di.Total) * int64(c.maxDiskUsagePct) / 100
+
+	type cacheEntry struct {
+		name  string
+		size  int64
+		atime time.Time
+	}
+	entries := make(map[string][]cacheEntry)
+	usage := make(map[string]int64)
+
+	objDirs, err := ioutil.ReadDir(c.dir)
+	if err != nil {
+		logger.LogIf(ctx, err)
+		return
+	}
+	for _, obj := range objDirs {
+		if ctx.Err() != nil {
+			return
+		}
+		if obj.Name() == minioMetaBucket {
+			continue
+		}
+		fi, err := os.Stat(pathJoin(c.dir, obj.Name(), cacheDataFile))
+		if err != nil {
+			continue
+		}
+		// Partially filled entries are left to purge().
+		objInfo, err := c.statCache(ctx, pathJoin(c.dir, obj.Name()))
+		if err != nil {
+			continue
+		}
+		if _, ok := quota[objInfo.Bucket]; !ok {
+			continue
+		}
+		entries[objInfo.Bucket] = append(entries[objInfo.Bucket], cacheEntry{obj.Name(), fi.Size(), objInfo.ModTime})
+		usage[objInfo.Bucket] += fi.Size()
+	}
+
+	for bucket, bucketEntries := range entries {
+		limit := cacheSpace * int64(quota[bucket]) / 100
+		if usage[bucket] <= limit {
+			continue
+		}
+		sort.Slice(bucketEntries, func(i, j int) bool {
+			return bucketEntries[i].atime.Before(bucketEntries[j].atime)
+		})
+		for _, entry := range bucketEntries {
+			if usage[bucket] <= limit || ctx.Err() != nil {
+				break
+			}
+			if err = removeAll(pathJoin(c.dir, entry.name)); err != nil {
+				logger.LogIf(ctx, err)
+				continue
+			}
+			usage[bucket] -= entry.size
+			atomic.AddUint64(&c.evictions, 1)
+			c.publishEvent(madmin.CacheEventEvict, cacheEventReasonQuota, entry.name, bucket, "", entry.size)
+		}
+	}
+}
+
+// triggerPurge - wakes up purge() unless it is already running.
+func (c *diskCache) triggerPurge() {
+	select {
+	case c.purgeChan <- struct{}{}:
+	default:
+	}
+}
+
+// close - stops the purge of the cache drive once it is no longer
+// used, the cached entries are left on the drive.
+func (c *diskCache) close() {
+	c.cancel()
+}
+
+// publishEvent - sends the removal of a cache entry to the cache
+// event listeners, if any.
+func (c *diskCache) publishEvent(eventType, reason, entry, bucket, object string, size int64) {
+	if !globalCacheEvents.HasSubscribers() {
+		return
+	}
+	globalCacheEvents.Publish(madmin.CacheEvent{
+		Node:   GetLocalPeer(globalEndpoints),
+		Time:   UTCNow(),
+		Type:   eventType,
+		Drive:  c.dir,
+		Entry:  entry,
+		Bucket: bucket,
+		Object: object,
+		Size:   size,
+		Reason: reason,
+	})
+}
+
+// recordReadError - counts err as an I/O error of the drive, reads
+// stopped by the reader closing the pipe are not.
+func (c *diskCache) recordReadError(err error) {
+	if err != nil && err != io.ErrClosedPipe {
+		atomic.AddUint64(&c.ioErrors, 1)
+	}
+}
+
 // sets cache drive status
 func (c *diskCache) setOnline(status bool) {
 	c.onlineMutex.Lock()
@@ -267,39 +455,52 @@ func (c *diskCache) IsOnline() bool {
 // Stat returns ObjectInfo from disk cache
 func (c *diskCache) Stat(ctx context.Context, bucket, object string) (oi ObjectInfo, err error) {
 	cacheObjPath := getCacheSHADir(c.dir, bucket, object)
-	oi, err = c.statCache(ctx, cacheObjPath)
+	meta, err := c.statCacheMeta(ctx, cacheObjPath)
 	if err != nil {
 		return
 	}
-	oi.Bucket = bucket
-	oi.Name = object
-	return
+	// A shard of an erasure coded entry is not the object.
+	if meta.Erasure != nil {
+		return oi, errFileNotFound
+	}
+	return meta.ToObjectInfo(bucket, object), nil
 }
 
 // statCache is a convenience function for purge() to get ObjectInfo for cached object
 func (c *diskCache) statCache(ctx context.Context, cacheObjPath string) (oi ObjectInfo, e error) {
+	meta, err := c.statCacheMeta(ctx, cacheObjPath)
+	if err != nil {
+		return oi, err
+	}
+	return meta.ToObjectInfo(meta.Bucket, ""), nil
+}
+
+// statCacheMeta returns the cache metadata of the cached entry, with
+// the access time of its data file as modification time.
+func (c *diskCache) statCacheMeta(ctx context.Context, cacheObjPath string) (*cacheMeta, error) {
 	// Stat the file to get file size.
 	metaPath := path.Join(cacheObjPath, cacheMetaJSONFile)
 	f, err := os.Open(metaPath)
 	if err != nil {
-		return oi, err
+		return nil, err
 	}
 	defer f.Close()
 
 	meta := &cacheMeta{Version: cacheMetaVersion}
 	if err := jsonLoad(f, meta); err != nil {
-		return oi, err
+		return nil, err
 	}
 	fi, err := os.Stat(pathJoin(cacheObjPath, cacheDataFile))
 	if err != nil {
-		return oi, err
+		return nil, err
 	}
 	meta.Stat.ModTime = atime.Get(fi)
-	return meta.ToObjectInfo("", ""), nil
+	return meta, nil
 }
 
-// saves object metadata to disk cache
-func (c *diskCache) saveMetadata(ctx context.Context, bucket, object string, meta map[string]string, actualSize int64) error {
+// saves object metadata to disk cache, erasure is the layout of the
+// shard of the object cached on this drive, if any.
+func (c *diskCache) saveMetadata(ctx context.Context, bucket, object string, meta map[string]string, actualSize int64, erasure *cacheErasureInfo) error {
 	fileName := getCacheSHADir(c.dir, bucket, object)
 	metaPath := pathJoin(fileName, cacheMetaJSONFile)
 
@@ -309,7 +510,7 @@ func (c *diskCache) saveMetadata(ctx context.Context, bucket, object string, met
 	}
 	defer f.Close()
 
-	m := cacheMeta{Meta: meta, Version: cacheMetaVersion}
+	m := cacheMeta{Meta: meta, Version: cacheMetaVersion, Bucket: bucket, Erasure: erasure}
 	m.Stat.Size = actualSize
 	m.Stat.ModTime = UTCNow()
 	m.Checksum = CacheChecksumInfoV1{Algorithm: HighwayHash256S.String(), Blocksize: cacheBlkSize}
@@ -327,7 +528,7 @@ func (c *diskCache) updateMetadataIfChanged(ctx context.Context, bucket, object
 		bkObjectInfo.ETag != cacheObjInfo.ETag ||
 		bkObjectInfo.ContentType != cacheObjInfo.ContentType ||
 		bkObjectInfo.Expires != cacheObjInfo.Expires {
-		return c.saveMetadata(ctx, bucket, object, getMetadata(bkObjectInfo), bkObjectInfo.Size)
+		return c.saveMetadata(ctx, bucket, object, getMetadata(bkObjectInfo), bkObjectInfo.Size, nil)
 	}
 	return nil
 }
@@ -399,10 +600,7 @@ func (c *diskCache) bitrotWriteToCache(ctx context.Context, cachePath string, re
 // Caches the object to disk
 func (c *diskCache) Put(ctx context.Context, bucket, object string, data io.Reader, size int64, opts ObjectOptions) error {
 	if c.diskUsageHigh() {
-		select {
-		case c.purgeChan <- struct{}{}:
-		default:
-		}
+		c.triggerPurge()
 		return errDiskFull
 	}
 	if !c.diskAvailable(size) {
@@ -419,12 +617,17 @@ func (c *diskCache) Put(ctx context.Context, bucket, object string, data io.Read
 
 	n, err := c.bitrotWriteToCache(ctx, cachePath, data, size)
 	if IsErr(err, baseErrs...) {
+		atomic.AddUint64(&c.ioErrors, 1)
 		c.setOnline(false)
 	}
 	if err != nil {
 		return err
 	}
-	return c.saveMetadata(ctx, bucket, object, opts.UserDefined, n)
+	if err = c.saveMetadata(ctx, bucket, object, opts.UserDefined, n, nil); err != nil {
+		return err
+	}
+	atomic.AddUint64(&c.fills, 1)
+	return nil
 }
 
 // checks streaming bitrot checksum of cached object before returning data
@@ -527,7 +730,7 @@ func (c *diskCache) Get(ctx context.Context, bucket, object string, rs *HTTPRang
 	var objInfo ObjectInfo
 	cacheObjPath := getCacheSHADir(c.dir, bucket, object)
 
-	if objInfo, err = c.statCache(ctx, cacheObjPath); err != nil {
+	if objInfo, err = c.Stat(ctx, bucket, object); err != nil {
 		return nil, toObjectErr(err, bucket, object)
 	}
 
@@ -547,7 +750,9 @@ func (c *diskCache) Get(ctx context.Context, bucket, object string, rs *HTTPRang
 	filePath := path.Join(cacheObjPath, cacheDataFile)
 	pr, pw := io.Pipe()
 	go func() {
-		pw.CloseWithError(c.bitrotReadFromCache(ctx, filePath, off, length, pw))
+		err := c.bitrotReadFromCache(ctx, filePath, off, length, pw)
+		c.recordReadError(err)
+		pw.CloseWithError(err)
 	}()
 	// Cleanup function to cause the go routine above to exit, in
 	// case of incomplete read.
@@ -560,7 +765,15 @@ func (c *diskCache) Get(ctx context.Context, bucket, object string, rs *HTTPRang
 // Deletes the cached object
 func (c *diskCache) Delete(ctx context.Context, bucket, object string) (err error) {
 	cachePath := getCacheSHADir(c.dir, bucket, object)
-	return removeAll(cachePath)
+	if !globalCacheEvents.HasSubscribers() {
+		return removeAll(cachePath)
+	}
+
+	objInfo, serr := c.statCache(ctx, cachePath)
+	if err = removeAll(cachePath); err == nil && serr == nil {
+		c.publishEvent(madmin.CacheEventPurge, cacheEventReasonInvalidated, path.Base(cachePath), bucket, object, objInfo.Size)
+	}
+	return err
 
 }
 
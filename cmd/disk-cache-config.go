@@ -20,19 +20,98 @@ import (
 	"encoding/json"
 	"errors"
 	"path/filepath"
+	"strconv"
 	"strings"
 
+	humanize "github.com/dustin/go-humanize"
 	"github.com/minio/minio/pkg/ellipses"
+	"github.com/minio/minio/pkg/madmin"
+)
+
+var (
+	errCacheNotInitialized = errors.New("Disk caching was not enabled at server startup")
+	errCacheConfigFromEnv  = errors.New("Cache configuration is set through environment variables")
+	errCacheMigrating      = errors.New("Cache migration is in progress")
 )
 
 // CacheConfig represents cache config settings
 type CacheConfig struct {
-	Drives  []string `json:"drives"`
-	Expiry  int      `json:"expiry"`
-	MaxUse  int      `json:"maxuse"`
-	Exclude []string `json:"exclude"`
+	Drives   []string            `json:"drives"`
+	Expiry   int                 `json:"expiry"`
+	MaxUse   int                 `json:"maxuse"`
+	Exclude  []string            `json:"exclude"`
+	Affinity map[string][]string `json:"affinity,omitempty"`
+	// Cache admission policy of objects keyed by storage class.
+	StorageClass map[string]string `json:"storageClass,omitempty"`
+	// Maximum percentage of the cache space of each cache drive
+	// used by the objects of a bucket, keyed by bucket.
+	Quota map[string]int `json:"quota,omitempty"`
+	// Number of parity shards of cached objects striped across the
+	// cache drives of their bucket, zero caches objects whole on a
+	// single cache drive.
+	Parity int `json:"parity,omitempty"`
+	// Rules routing objects to cache drives by their metadata, the
+	// first matching rule overrides the cache drive hinted by the
+	// hash of the object.
+	Routing []CacheRoutingRule `json:"routing,omitempty"`
 }
 
+// CacheRoutingRule - routes the objects matching all its conditions to
+// its cache drives.
+type CacheRoutingRule struct {
+	// Content type patterns, objects must match one of them.
+	ContentType []string `json:"contentType,omitempty"`
+	// Size range of objects in bytes, zero is no limit.
+	MinSize int64 `json:"minSize,omitempty"`
+	MaxSize int64 `json:"maxSize,omitempty"`
+	// User metadata values of objects keyed by x-amz-meta- header.
+	Metadata map[string]string `json:"metadata,omitempty"`
+	Drives   []string          `json:"drives"`
+}
+
+// Returns true if the object matches all conditions of the rule.
+func (rule CacheRoutingRule) matches(objInfo ObjectInfo) bool {
+	if len(rule.ContentType) > 0 && !hasPattern(rule.ContentType, objInfo.ContentType) {
+		return false
+	}
+	if rule.MinSize > 0 && objInfo.Size < rule.MinSize {
+		return false
+	}
+	if rule.MaxSize > 0 && objInfo.Size > rule.MaxSize {
+		return false
+	}
+	for key, value := range rule.Metadata {
+		found := false
+		for k, v := range objInfo.UserDefined {
+			if strings.EqualFold(k, key) {
+				found = v == value
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	return true
+}
+
+// cacheRoute - a cache routing rule along with the indices of its
+// cache drives.
+type cacheRoute struct {
+	CacheRoutingRule
+	drives []int
+}
+
+// Cache admission policies of storage classes.
+const (
+	// Objects of the storage class are never added to the cache.
+	cacheStorageClassExclude = "exclude"
+	// Objects of the storage class are still added to the cache when
+	// the cache usage is high, other objects are only added when the
+	// cache usage is low.
+	cacheStorageClassPriority = "priority"
+)
+
 // UnmarshalJSON - implements JSON unmarshal interface for unmarshalling
 // json entries for CacheConfig.
 func (cfg *CacheConfig) UnmarshalJSON(data []byte) (err error) {
@@ -54,10 +133,25 @@ func (cfg *CacheConfig) UnmarshalJSON(data []byte) (err error) {
 		return errors.New("config max use value should not be null or negative")
 	}
 
+	if _, err = parseCacheExcludes(_cfg.Exclude); err != nil {
+		return err
+	}
 	if _, err = parseCacheDrives(_cfg.Drives); err != nil {
 		return err
 	}
-	if _, err = parseCacheExcludes(_cfg.Exclude); err != nil {
+	if _, _, err = parseCacheAffinity(_cfg.Affinity, _cfg.Drives); err != nil {
+		return err
+	}
+	if _, err = parseCacheStorageClass(_cfg.StorageClass); err != nil {
+		return err
+	}
+	if _, err = parseCacheQuota(_cfg.Quota); err != nil {
+		return err
+	}
+	if _, err = parseCacheParity(_cfg.Parity, _cfg.Drives, _cfg.Affinity); err != nil {
+		return err
+	}
+	if _, err = parseCacheRouting(_cfg.Routing, _cfg.Drives); err != nil {
 		return err
 	}
 	return nil
@@ -115,3 +209,363 @@ func parseCacheExcludes(excludes []string) ([]string, error) {
 	}
 	return excludes, nil
 }
+
+// Parses given cacheAffinityEnv of the form "bucket1=drive1,drive2;bucket2=drive3"
+// and returns a map of bucket names to the cache drives dedicated to them.
+func parseCacheAffinityEnv(affinityEnv string) (map[string][]string, error) {
+	affinity := make(map[string][]string)
+	for _, rule := range strings.Split(affinityEnv, cacheEnvDelimiter) {
+		kv := strings.SplitN(rule, "=", 2)
+		if len(kv) != 2 || kv[0] == "" || kv[1] == "" {
+			return nil, uiErrInvalidCacheAffinityValue(nil).Msg("cache affinity rule (%s) should be of the form bucket=drive1,drive2", rule)
+		}
+		if !IsValidBucketName(kv[0]) {
+			return nil, uiErrInvalidCacheAffinityValue(nil).Msg("cache affinity bucket name (%s) is not a valid bucket name", kv[0])
+		}
+		if _, ok := affinity[kv[0]]; ok {
+			return nil, uiErrInvalidCacheAffinityValue(nil).Msg("cache affinity for bucket %s specified more than once", kv[0])
+		}
+		affinity[kv[0]] = strings.Split(kv[1], ",")
+	}
+	return affinity, nil
+}
+
+// Parses given cache affinity rules against the list of cache drives, returns
+// the cache drive indices dedicated to each bucket and the indices of the
+// remaining drives shared by all other buckets. If every drive is dedicated
+// to a bucket, all drives are shared by the other buckets. Cache drives are
+// expanded the same way as by parseCacheDrives.
+func parseCacheAffinity(affinity map[string][]string, drives []string) (map[string][]int, []int, error) {
+	drives, err := parseCacheDrives(drives)
+	if err != nil {
+		return nil, nil, err
+	}
+	driveIndex := make(map[string]int, len(drives))
+	for i, d := range drives {
+		driveIndex[d] = i
+	}
+
+	dedicated := make([]bool, len(drives))
+	bucketDrives := make(map[string][]int, len(affinity))
+	for bucket, bucketAffinity := range affinity {
+		if !IsValidBucketName(bucket) {
+			return nil, nil, uiErrInvalidCacheAffinityValue(nil).Msg("cache affinity bucket name (%s) is not a valid bucket name", bucket)
+		}
+		paths, err := parseCacheDrives(bucketAffinity)
+		if err != nil {
+			return nil, nil, err
+		}
+		if len(paths) == 0 {
+			return nil, nil, uiErrInvalidCacheAffinityValue(nil).Msg("cache affinity for bucket %s has no drives", bucket)
+		}
+		for _, d := range paths {
+			i, ok := driveIndex[d]
+			if !ok {
+				return nil, nil, uiErrInvalidCacheAffinityValue(nil).Msg("cache affinity drive %s for bucket %s is not a cache drive", d, bucket)
+			}
+			bucketDrives[bucket] = append(bucketDrives[bucket], i)
+			dedicated[i] = true
+		}
+	}
+
+	var shared []int
+	for i := range drives {
+		if !dedicated[i] {
+			shared = append(shared, i)
+		}
+	}
+	if len(shared) == 0 {
+		for i := range drives {
+			shared = append(shared, i)
+		}
+	}
+	return bucketDrives, shared, nil
+}
+
+// Applies the cache drives and exclude patterns added and removed by
+// update to cfg, removing entries not in cfg and adding entries already
+// in cfg are no-ops. Removed drives are also removed from the cache
+// affinity and routing rules, buckets left without drives lose their
+// affinity and routing rules left without drives are dropped.
+func updateCacheConfig(cfg CacheConfig, update madmin.CacheConfigUpdate) CacheConfig {
+	apply := func(entries, add, remove []string) []string {
+		removed := make(map[string]bool, len(remove))
+		for _, entry := range remove {
+			removed[entry] = true
+		}
+		var result []string
+		present := make(map[string]bool, len(entries))
+		for _, entry := range entries {
+			if !removed[entry] {
+				result = append(result, entry)
+				present[entry] = true
+			}
+		}
+		for _, entry := range add {
+			if !present[entry] {
+				result = append(result, entry)
+				present[entry] = true
+			}
+		}
+		return result
+	}
+	cfg.Drives = apply(cfg.Drives, update.AddDrives, update.RemoveDrives)
+	cfg.Exclude = apply(cfg.Exclude, update.AddExclude, update.RemoveExclude)
+	cfg.Affinity = pruneCacheAffinity(cfg.Affinity, update.RemoveDrives)
+	cfg.Routing = pruneCacheRouting(cfg.Routing, update.RemoveDrives)
+	return cfg
+}
+
+// Returns a copy of the cache affinity rules without the given removed
+// drives, drives are compared after being expanded as by parseCacheDrives.
+func pruneCacheAffinity(affinity map[string][]string, remove []string) map[string][]string {
+	if len(affinity) == 0 || len(remove) == 0 {
+		return affinity
+	}
+	expand := func(drives []string) []string {
+		if paths, err := parseCacheDrives(drives); err == nil {
+			return paths
+		}
+		return drives
+	}
+	removed := make(map[string]bool, len(remove))
+	for _, d := range expand(remove) {
+		removed[d] = true
+	}
+	pruned := make(map[string][]string, len(affinity))
+	for bucket, drives := range affinity {
+		var kept []string
+		for _, d := range expand(drives) {
+			if !removed[d] {
+				kept = append(kept, d)
+			}
+		}
+		if len(kept) > 0 {
+			pruned[bucket] = kept
+		}
+	}
+	return pruned
+}
+
+// Returns a copy of the cache routing rules without the given removed
+// drives, rules left without drives are dropped.
+func pruneCacheRouting(routing []CacheRoutingRule, remove []string) []CacheRoutingRule {
+	if len(routing) == 0 || len(remove) == 0 {
+		return routing
+	}
+	expand := func(drives []string) []string {
+		if paths, err := parseCacheDrives(drives); err == nil {
+			return paths
+		}
+		return drives
+	}
+	removed := make(map[string]bool, len(remove))
+	for _, d := range expand(remove) {
+		removed[d] = true
+	}
+	var pruned []CacheRoutingRule
+	for _, rule := range routing {
+		var kept []string
+		for _, d := range expand(rule.Drives) {
+			if !removed[d] {
+				kept = append(kept, d)
+			}
+		}
+		if len(kept) > 0 {
+			rule.Drives = kept
+			pruned = append(pruned, rule)
+		}
+	}
+	return pruned
+}
+
+// Parses given cacheStorageClassEnv of the form "REDUCED_REDUNDANCY=exclude;STANDARD=priority"
+// and returns a map of storage classes to their cache admission policy.
+func parseCacheStorageClassEnv(storageClassEnv string) (map[string]string, error) {
+	policies := make(map[string]string)
+	for _, rule := range strings.Split(storageClassEnv, cacheEnvDelimiter) {
+		kv := strings.SplitN(rule, "=", 2)
+		if len(kv) != 2 || kv[0] == "" || kv[1] == "" {
+			return nil, uiErrInvalidCacheStorageClassValue(nil).Msg("cache storage class rule (%s) should be of the form class=policy", rule)
+		}
+		if _, ok := policies[kv[0]]; ok {
+			return nil, uiErrInvalidCacheStorageClassValue(nil).Msg("cache policy for storage class %s specified more than once", kv[0])
+		}
+		policies[kv[0]] = kv[1]
+	}
+	return parseCacheStorageClass(policies)
+}
+
+// Validates the cache admission policies of storage classes.
+func parseCacheStorageClass(policies map[string]string) (map[string]string, error) {
+	for class, policy := range policies {
+		if class == "" || strings.ToUpper(class) != class {
+			return nil, uiErrInvalidCacheStorageClassValue(nil).Msg("cache storage class (%s) should be a non empty upper case name", class)
+		}
+		if policy != cacheStorageClassExclude && policy != cacheStorageClassPriority {
+			return nil, uiErrInvalidCacheStorageClassValue(nil).Msg("cache policy (%s) of storage class %s should be either %s or %s",
+				policy, class, cacheStorageClassExclude, cacheStorageClassPriority)
+		}
+	}
+	return policies, nil
+}
+
+// Parses given cacheQuotaEnv of the form "bucket1=20;bucket2=10" and
+// returns a map of bucket names to their cache quota in percentage.
+func parseCacheQuotaEnv(quotaEnv string) (map[string]int, error) {
+	quota := make(map[string]int)
+	for _, rule := range strings.Split(quotaEnv, cacheEnvDelimiter) {
+		kv := strings.SplitN(rule, "=", 2)
+		if len(kv) != 2 || kv[0] == "" || kv[1] == "" {
+			return nil, uiErrInvalidCacheQuotaValue(nil).Msg("cache quota rule (%s) should be of the form bucket=percent", rule)
+		}
+		if _, ok := quota[kv[0]]; ok {
+			return nil, uiErrInvalidCacheQuotaValue(nil).Msg("cache quota for bucket %s specified more than once", kv[0])
+		}
+		percent, err := strconv.Atoi(kv[1])
+		if err != nil {
+			return nil, uiErrInvalidCacheQuotaValue(err).Msg("cache quota (%s) of bucket %s should be a number", kv[1], kv[0])
+		}
+		quota[kv[0]] = percent
+	}
+	return parseCacheQuota(quota)
+}
+
+// Validates the cache quotas of buckets.
+func parseCacheQuota(quota map[string]int) (map[string]int, error) {
+	for bucket, percent := range quota {
+		if !IsValidBucketName(bucket) {
+			return nil, uiErrInvalidCacheQuotaValue(nil).Msg("cache quota bucket name (%s) is not a valid bucket name", bucket)
+		}
+		if percent <= 0 || percent > 100 {
+			return nil, uiErrInvalidCacheQuotaValue(nil).Msg("cache quota (%d) of bucket %s should be between 1 and 100", percent, bucket)
+		}
+	}
+	return quota, nil
+}
+
+// Parses given cacheParityEnv and returns the number of parity shards
+// of cached objects.
+func parseCacheParityEnv(parityEnv string, drives []string, affinity map[string][]string) (int, error) {
+	parity, err := strconv.Atoi(parityEnv)
+	if err != nil {
+		return 0, uiErrInvalidCacheParityValue(err).Msg("cache parity (%s) should be a number", parityEnv)
+	}
+	return parseCacheParity(parity, drives, affinity)
+}
+
+// Validates the cache parity against the cache drives, objects are
+// striped across the cache drives of their bucket, which need at least
+// as many data shards as parity shards.
+func parseCacheParity(parity int, drives []string, affinity map[string][]string) (int, error) {
+	if parity < 0 {
+		return 0, uiErrInvalidCacheParityValue(nil).Msg("cache parity (%d) should not be negative", parity)
+	}
+	if parity == 0 {
+		return 0, nil
+	}
+	bucketDrives, shared, err := parseCacheAffinity(affinity, drives)
+	if err != nil {
+		return 0, err
+	}
+	if len(shared) < 2*parity {
+		return 0, uiErrInvalidCacheParityValue(nil).Msg("cache parity (%d) needs at least %d cache drives", parity, 2*parity)
+	}
+	for bucket, indices := range bucketDrives {
+		if len(indices) < 2*parity {
+			return 0, uiErrInvalidCacheParityValue(nil).Msg("cache parity (%d) needs at least %d cache drives for bucket %s", parity, 2*parity, bucket)
+		}
+	}
+	return parity, nil
+}
+
+// Parses given cacheRoutingEnv of the form
+// "content-type:video/*,min-size:64MiB=drive1,drive2;x-amz-meta-kind:manifest=drive3"
+// and returns the list of cache routing rules.
+func parseCacheRoutingEnv(routingEnv string) ([]CacheRoutingRule, error) {
+	var routing []CacheRoutingRule
+	for _, rule := range strings.Split(routingEnv, cacheEnvDelimiter) {
+		kv := strings.SplitN(rule, "=", 2)
+		if len(kv) != 2 || kv[0] == "" || kv[1] == "" {
+			return nil, uiErrInvalidCacheRoutingValue(nil).Msg("cache routing rule (%s) should be of the form condition1,condition2=drive1,drive2", rule)
+		}
+		var r CacheRoutingRule
+		for _, condition := range strings.Split(kv[0], ",") {
+			nv := strings.SplitN(condition, ":", 2)
+			if len(nv) != 2 || nv[1] == "" {
+				return nil, uiErrInvalidCacheRoutingValue(nil).Msg("cache routing condition (%s) should be of the form name:value", condition)
+			}
+			name := strings.ToLower(nv[0])
+			switch {
+			case name == "content-type":
+				r.ContentType = append(r.ContentType, nv[1])
+			case name == "min-size" || name == "max-size":
+				size, err := humanize.ParseBytes(nv[1])
+				if err != nil {
+					return nil, uiErrInvalidCacheRoutingValue(err).Msg("cache routing %s (%s) should be a size", name, nv[1])
+				}
+				if name == "min-size" {
+					r.MinSize = int64(size)
+				} else {
+					r.MaxSize = int64(size)
+				}
+			case strings.HasPrefix(name, "x-amz-meta-"):
+				if r.Metadata == nil {
+					r.Metadata = make(map[string]string)
+				}
+				r.Metadata[name] = nv[1]
+			default:
+				return nil, uiErrInvalidCacheRoutingValue(nil).Msg("cache routing condition (%s) should be one of content-type, min-size, max-size or x-amz-meta-*", nv[0])
+			}
+		}
+		r.Drives = strings.Split(kv[1], ",")
+		routing = append(routing, r)
+	}
+	return routing, nil
+}
+
+// Parses given cache routing rules against the list of cache drives,
+// returns the rules along with the indices of their cache drives. Cache
+// drives are expanded the same way as by parseCacheDrives.
+func parseCacheRouting(routing []CacheRoutingRule, drives []string) ([]cacheRoute, error) {
+	drives, err := parseCacheDrives(drives)
+	if err != nil {
+		return nil, err
+	}
+	driveIndex := make(map[string]int, len(drives))
+	for i, d := range drives {
+		driveIndex[d] = i
+	}
+
+	routes := make([]cacheRoute, 0, len(routing))
+	for n, rule := range routing {
+		if len(rule.ContentType) == 0 && rule.MinSize == 0 && rule.MaxSize == 0 && len(rule.Metadata) == 0 {
+			return nil, uiErrInvalidCacheRoutingValue(nil).Msg("cache routing rule %d has no conditions", n+1)
+		}
+		if rule.MinSize < 0 || rule.MaxSize < 0 || (rule.MaxSize > 0 && rule.MinSize > rule.MaxSize) {
+			return nil, uiErrInvalidCacheRoutingValue(nil).Msg("cache routing rule %d has an invalid size range", n+1)
+		}
+		for key := range rule.Metadata {
+			if !strings.HasPrefix(strings.ToLower(key), "x-amz-meta-") {
+				return nil, uiErrInvalidCacheRoutingValue(nil).Msg("cache routing metadata (%s) of rule %d should be an x-amz-meta- header", key, n+1)
+			}
+		}
+		paths, err := parseCacheDrives(rule.Drives)
+		if err != nil {
+			return nil, err
+		}
+		if len(paths) == 0 {
+			return nil, uiErrInvalidCacheRoutingValue(nil).Msg("cache routing rule %d has no drives", n+1)
+		}
+		route := cacheRoute{CacheRoutingRule: rule}
+		for _, d := range paths {
+			i, ok := driveIndex[d]
+			if !ok {
+				return nil, uiErrInvalidCacheRoutingValue(nil).Msg("cache routing drive %s of rule %d is not a cache drive", d, n+1)
+			}
+			route.drives = append(route.drives, i)
+		}
+		routes = append(routes, route)
+	}
+	return routes, nil
+}
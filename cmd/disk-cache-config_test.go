@@ -17,10 +17,13 @@
 package cmd
 
 import (
+	"encoding/json"
 	"reflect"
 	"runtime"
 	"strings"
 	"testing"
+
+	"github.com/minio/minio/pkg/madmin"
 )
 
 // Tests cache drive parsing.
@@ -112,3 +115,277 @@ func TestParseCacheExclude(t *testing.T) {
 		}
 	}
 }
+
+// Tests cache affinity parsing.
+func TestParseCacheAffinity(t *testing.T) {
+	if runtime.GOOS == globalWindowsOSName {
+		t.Skip()
+	}
+	drives := []string{"/mnt/drive1", "/mnt/drive2", "/mnt/drive3"}
+	testCases := []struct {
+		affinityStr      string
+		expectedAffinity map[string][]int
+		expectedShared   []int
+		success          bool
+	}{
+		{"bucket1=/mnt/drive1", map[string][]int{"bucket1": {0}}, []int{1, 2}, true},
+		{"bucket1=/mnt/drive1,/mnt/drive2;bucket2=/mnt/drive2", map[string][]int{"bucket1": {0, 1}, "bucket2": {1}}, []int{2}, true},
+		{"bucket1=/mnt/drive{1...3}", map[string][]int{"bucket1": {0, 1, 2}}, []int{0, 1, 2}, true},
+		// invalid input
+		{"bucket1", nil, nil, false},
+		{"=/mnt/drive1", nil, nil, false},
+		{"bucket1=", nil, nil, false},
+		{"bucket1=/mnt/drive1;bucket1=/mnt/drive2", nil, nil, false},
+		{"bucket1=/mnt/drive4", nil, nil, false},
+		{"bucket1=drive1", nil, nil, false},
+		{"Bucket_1=/mnt/drive1", nil, nil, false},
+		{"b1=/mnt/drive1", nil, nil, false},
+	}
+
+	for i, testCase := range testCases {
+		affinity, shared, err := func() (map[string][]int, []int, error) {
+			rules, err := parseCacheAffinityEnv(testCase.affinityStr)
+			if err != nil {
+				return nil, nil, err
+			}
+			return parseCacheAffinity(rules, drives)
+		}()
+		if err != nil && testCase.success {
+			t.Errorf("Test %d: Expected success but failed instead %s", i+1, err)
+		}
+		if err == nil && !testCase.success {
+			t.Errorf("Test %d: Expected failure but passed instead", i+1)
+		}
+		if err == nil {
+			if !reflect.DeepEqual(affinity, testCase.expectedAffinity) {
+				t.Errorf("Test %d: Expected %v, got %v", i+1, testCase.expectedAffinity, affinity)
+			}
+			if !reflect.DeepEqual(shared, testCase.expectedShared) {
+				t.Errorf("Test %d: Expected %v, got %v", i+1, testCase.expectedShared, shared)
+			}
+		}
+	}
+}
+
+// Tests cache storage class policies parsing.
+func TestParseCacheStorageClass(t *testing.T) {
+	testCases := []struct {
+		storageClassStr  string
+		expectedPolicies map[string]string
+		success          bool
+	}{
+		{"REDUCED_REDUNDANCY=exclude", map[string]string{"REDUCED_REDUNDANCY": "exclude"}, true},
+		{"GLACIER=exclude;STANDARD=priority", map[string]string{"GLACIER": "exclude", "STANDARD": "priority"}, true},
+		{"STANDARD=priority;STANDARD=exclude", nil, false},
+		{"STANDARD", nil, false},
+		{"=exclude", nil, false},
+		{"standard=exclude", nil, false},
+		{"STANDARD=always", nil, false},
+	}
+	for i, testCase := range testCases {
+		policies, err := parseCacheStorageClassEnv(testCase.storageClassStr)
+		if err != nil && testCase.success {
+			t.Errorf("Test %d: Expected success but failed instead %s", i+1, err)
+		}
+		if err == nil && !testCase.success {
+			t.Errorf("Test %d: Expected failure but passed instead", i+1)
+		}
+		if err == nil && !reflect.DeepEqual(policies, testCase.expectedPolicies) {
+			t.Errorf("Test %d: Expected %v, got %v", i+1, testCase.expectedPolicies, policies)
+		}
+	}
+}
+
+func TestParseCacheQuota(t *testing.T) {
+	testCases := []struct {
+		quotaStr      string
+		expectedQuota map[string]int
+		success       bool
+	}{
+		{"noisy=20", map[string]int{"noisy": 20}, true},
+		{"noisy=20;other=100", map[string]int{"noisy": 20, "other": 100}, true},
+		{"noisy=20;noisy=10", nil, false},
+		{"noisy", nil, false},
+		{"=20", nil, false},
+		{"Noisy_Bucket=20", nil, false},
+		{"noisy=twenty", nil, false},
+		{"noisy=0", nil, false},
+		{"noisy=101", nil, false},
+	}
+	for i, testCase := range testCases {
+		quota, err := parseCacheQuotaEnv(testCase.quotaStr)
+		if err != nil && testCase.success {
+			t.Errorf("Test %d: Expected success but failed instead %s", i+1, err)
+		}
+		if err == nil && !testCase.success {
+			t.Errorf("Test %d: Expected failure but passed instead", i+1)
+		}
+		if err == nil && !reflect.DeepEqual(quota, testCase.expectedQuota) {
+			t.Errorf("Test %d: Expected %v, got %v", i+1, testCase.expectedQuota, quota)
+		}
+	}
+}
+
+// Tests cache routing rules parsing against the cache drives.
+func TestParseCacheRouting(t *testing.T) {
+	drives := []string{"/mnt/ssd1", "/mnt/hdd1", "/mnt/hdd2"}
+	testCases := []struct {
+		routingStr     string
+		expectedRoutes []cacheRoute
+		success        bool
+	}{
+		{"content-type:video/*,min-size:1MiB=/mnt/hdd1,/mnt/hdd2", []cacheRoute{{
+			CacheRoutingRule: CacheRoutingRule{
+				ContentType: []string{"video/*"},
+				MinSize:     1 << 20,
+				Drives:      []string{"/mnt/hdd1", "/mnt/hdd2"},
+			},
+			drives: []int{1, 2},
+		}}, true},
+		{"X-Amz-Meta-Kind:manifest=/mnt/ssd1;max-size:4KiB=/mnt/ssd1", []cacheRoute{{
+			CacheRoutingRule: CacheRoutingRule{
+				Metadata: map[string]string{"x-amz-meta-kind": "manifest"},
+				Drives:   []string{"/mnt/ssd1"},
+			},
+			drives: []int{0},
+		}, {
+			CacheRoutingRule: CacheRoutingRule{
+				MaxSize: 4 << 10,
+				Drives:  []string{"/mnt/ssd1"},
+			},
+			drives: []int{0},
+		}}, true},
+		{"content-type:video/*", nil, false},
+		{"content-type=/mnt/hdd1", nil, false},
+		{"owner:alice=/mnt/hdd1", nil, false},
+		{"min-size:big=/mnt/hdd1", nil, false},
+		{"min-size:2MiB,max-size:1MiB=/mnt/hdd1", nil, false},
+		{"content-type:video/*=/mnt/hdd3", nil, false},
+	}
+	for i, testCase := range testCases {
+		routes, err := func() ([]cacheRoute, error) {
+			routing, err := parseCacheRoutingEnv(testCase.routingStr)
+			if err != nil {
+				return nil, err
+			}
+			return parseCacheRouting(routing, drives)
+		}()
+		if err != nil && testCase.success {
+			t.Errorf("Test %d: Expected success but failed instead %s", i+1, err)
+		}
+		if err == nil && !testCase.success {
+			t.Errorf("Test %d: Expected failure but passed instead", i+1)
+		}
+		if err == nil && !reflect.DeepEqual(routes, testCase.expectedRoutes) {
+			t.Errorf("Test %d: Expected %v, got %v", i+1, testCase.expectedRoutes, routes)
+		}
+	}
+}
+
+// Tests that affinity rules of a cache config are validated
+// against the expanded cache drives.
+func TestParseCacheParity(t *testing.T) {
+	drives := []string{"/mnt/cache1", "/mnt/cache2", "/mnt/cache3", "/mnt/cache4"}
+	testCases := []struct {
+		parityStr      string
+		affinity       map[string][]string
+		expectedParity int
+		success        bool
+	}{
+		{"0", nil, 0, true},
+		{"1", nil, 1, true},
+		{"2", nil, 2, true},
+		{"3", nil, 0, false},
+		{"-1", nil, 0, false},
+		{"one", nil, 0, false},
+		{"1", map[string][]string{"fast": {"/mnt/cache1", "/mnt/cache2"}}, 1, true},
+		{"1", map[string][]string{"fast": {"/mnt/cache1"}}, 0, false},
+		{"1", map[string][]string{"fast": {"/mnt/cache1", "/mnt/cache2", "/mnt/cache3"}}, 0, false},
+	}
+	for i, testCase := range testCases {
+		parity, err := parseCacheParityEnv(testCase.parityStr, drives, testCase.affinity)
+		if err != nil && testCase.success {
+			t.Errorf("Test %d: Expected success but failed instead %s", i+1, err)
+		}
+		if err == nil && !testCase.success {
+			t.Errorf("Test %d: Expected failure but passed instead", i+1)
+		}
+		if err == nil && parity != testCase.expectedParity {
+			t.Errorf("Test %d: Expected %d, got %d", i+1, testCase.expectedParity, parity)
+		}
+	}
+}
+
+func TestCacheConfigAffinity(t *testing.T) {
+	if runtime.GOOS == globalWindowsOSName {
+		t.Skip()
+	}
+	testCases := []struct {
+		config  string
+		success bool
+	}{
+		{`{"drives":["/mnt/drive{1...3}"],"expiry":90,"maxuse":80,"exclude":[],"affinity":{"bucket1":["/mnt/drive2"]}}`, true},
+		{`{"drives":["/mnt/drive{1...3}"],"expiry":90,"maxuse":80,"exclude":[],"affinity":{"bucket1":["/mnt/drive{2...3}"]}}`, true},
+		{`{"drives":["/mnt/drive{1...3}"],"expiry":90,"maxuse":80,"exclude":[],"affinity":{"bucket1":["/mnt/drive4"]}}`, false},
+		{`{"drives":["/mnt/drive{1...3}"],"expiry":90,"maxuse":80,"exclude":[],"affinity":{"Bucket_1":["/mnt/drive1"]}}`, false},
+		{`{"drives":["/mnt/drive{1...3}"],"expiry":90,"maxuse":80,"exclude":[],"storageClass":{"GLACIER":"exclude"}}`, true},
+		{`{"drives":["/mnt/drive{1...3}"],"expiry":90,"maxuse":80,"exclude":[],"storageClass":{"GLACIER":"never"}}`, false},
+	}
+	for i, testCase := range testCases {
+		var config CacheConfig
+		err := json.Unmarshal([]byte(testCase.config), &config)
+		if err != nil && testCase.success {
+			t.Errorf("Test %d: Expected success but failed instead %s", i+1, err)
+		}
+		if err == nil && !testCase.success {
+			t.Errorf("Test %d: Expected failure but passed instead", i+1)
+		}
+	}
+}
+
+// Tests that removing cache drives at runtime prunes them from the
+// affinity rules and that the result validates against the new drives.
+func TestUpdateCacheConfigAffinity(t *testing.T) {
+	if runtime.GOOS == globalWindowsOSName {
+		t.Skip()
+	}
+	testCases := []struct {
+		affinity map[string][]string
+		remove   []string
+		expected map[string][]string
+	}{
+		{
+			map[string][]string{"bucket1": {"/mnt/drive2"}},
+			nil,
+			map[string][]string{"bucket1": {"/mnt/drive2"}},
+		},
+		{
+			map[string][]string{"bucket1": {"/mnt/drive2"}, "bucket2": {"/mnt/drive3"}},
+			[]string{"/mnt/drive3"},
+			map[string][]string{"bucket1": {"/mnt/drive2"}},
+		},
+		{
+			map[string][]string{"bucket1": {"/mnt/drive{2...3}"}},
+			[]string{"/mnt/drive3"},
+			map[string][]string{"bucket1": {"/mnt/drive2"}},
+		},
+		{
+			map[string][]string{"bucket1": {"/mnt/drive{2...3}"}},
+			[]string{"/mnt/drive{2...3}"},
+			map[string][]string{},
+		},
+	}
+	for i, testCase := range testCases {
+		cfg := CacheConfig{
+			Drives:   []string{"/mnt/drive1", "/mnt/drive2", "/mnt/drive3"},
+			Affinity: testCase.affinity,
+		}
+		cfg = updateCacheConfig(cfg, madmin.CacheConfigUpdate{RemoveDrives: testCase.remove})
+		if !reflect.DeepEqual(cfg.Affinity, testCase.expected) {
+			t.Errorf("Test %d: Expected affinity %v, got %v", i+1, testCase.expected, cfg.Affinity)
+		}
+		if _, _, err := parseCacheAffinity(cfg.Affinity, cfg.Drives); err != nil {
+			t.Errorf("Test %d: Expected pruned affinity to be valid, got %s", i+1, err)
+		}
+	}
+}
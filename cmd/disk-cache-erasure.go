@@ -0,0 +1,440 @@
+/*
+ * MinIO Cloud Storage, (C) 2020 MinIO, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"net/http"
+	"path"
+	"reflect"
+	"sync/atomic"
+)
+
+// cacheErasureInfo - erasure layout of a cache entry striped across
+// cache drives. Objects are erasure coded in blocks of cacheBlkSize,
+// the data file of the entry on each drive holds the shards of index
+// Index of all the blocks.
+type cacheErasureInfo struct {
+	DataBlocks   int `json:"data"`
+	ParityBlocks int `json:"parity"`
+	Index        int `json:"index"`
+}
+
+// cacheStore - stores the cache entries of objects, either a single
+// cache drive or an erasure set of cache drives.
+type cacheStore interface {
+	Get(ctx context.Context, bucket, object string, rs *HTTPRangeSpec, h http.Header, opts ObjectOptions) (*GetObjectReader, error)
+	Put(ctx context.Context, bucket, object string, data io.Reader, size int64, opts ObjectOptions) error
+	Stat(ctx context.Context, bucket, object string) (ObjectInfo, error)
+	Delete(ctx context.Context, bucket, object string) error
+	Exists(ctx context.Context, bucket, object string) bool
+	updateMetadataIfChanged(ctx context.Context, bucket, object string, bkObjectInfo, cacheObjInfo ObjectInfo) error
+	diskUsageLow() bool
+	diskAvailable(size int64) bool
+	triggerPurge()
+}
+
+// cacheErasureSet - stripes cached objects across cache drives with
+// parity, so that losing up to parityBlocks drives does not lose the
+// cached objects. Drives are in the order of the shards of the objects
+// cached by the set, offline drives are nil.
+type cacheErasureSet struct {
+	drives       []*diskCache
+	dataBlocks   int
+	parityBlocks int
+}
+
+// newCacheErasureSet - returns the erasure set of the given cache
+// drives, shards are placed starting at the drive of index start so
+// that the data shards of objects are spread over all drives.
+func newCacheErasureSet(caches []*diskCache, start, parity int) *cacheErasureSet {
+	drives := make([]*diskCache, len(caches))
+	for i := range drives {
+		dcache := caches[(start+i)%len(caches)]
+		if dcache != nil && dcache.IsOnline() {
+			drives[i] = dcache
+		}
+	}
+	return &cacheErasureSet{
+		drives:       drives,
+		dataBlocks:   len(drives) - parity,
+		parityBlocks: parity,
+	}
+}
+
+// onlineDrives - returns the number of online drives of the set.
+func (s *cacheErasureSet) onlineDrives() int {
+	var n int
+	for _, dcache := range s.drives {
+		if dcache != nil {
+			n++
+		}
+	}
+	return n
+}
+
+// statShards - returns the object info of the cached entry and the
+// drive of each shard of it, nil for missing shards. Shards are found
+// by their index rather than by their drive, since drives may change
+// places when cache drives are added or removed. Shards of other
+// versions of the object than the one with the most shards are ignored.
+func (s *cacheErasureSet) statShards(ctx context.Context, bucket, object string) (ObjectInfo, []*diskCache, error) {
+	type shard struct {
+		dcache *diskCache
+		meta   *cacheMeta
+	}
+	versions := make(map[string][]shard)
+	var etag string
+	for _, dcache := range s.drives {
+		if dcache == nil {
+			continue
+		}
+		meta, err := dcache.statCacheMeta(ctx, getCacheSHADir(dcache.dir, bucket, object))
+		if err != nil || meta.Erasure == nil ||
+			meta.Erasure.DataBlocks != s.dataBlocks || meta.Erasure.ParityBlocks != s.parityBlocks ||
+			meta.Erasure.Index < 0 || meta.Erasure.Index >= len(s.drives) {
+			continue
+		}
+		tag := extractETag(meta.Meta)
+		versions[tag] = append(versions[tag], shard{dcache, meta})
+		if len(versions[tag]) > len(versions[etag]) {
+			etag = tag
+		}
+	}
+	if len(versions[etag]) < s.dataBlocks {
+		return ObjectInfo{}, nil, errFileNotFound
+	}
+
+	drives := make([]*diskCache, len(s.drives))
+	var objInfo ObjectInfo
+	for _, sh := range versions[etag] {
+		drives[sh.meta.Erasure.Index] = sh.dcache
+		// Entries are accessed through all their shards, the
+		// latest access time is the access time of the entry.
+		if oi := sh.meta.ToObjectInfo(bucket, object); oi.ModTime.After(objInfo.ModTime) {
+			objInfo = oi
+		}
+	}
+	return objInfo, drives, nil
+}
+
+// Stat returns ObjectInfo of the object cached in the set.
+func (s *cacheErasureSet) Stat(ctx context.Context, bucket, object string) (ObjectInfo, error) {
+	objInfo, _, err := s.statShards(ctx, bucket, object)
+	return objInfo, err
+}
+
+// Put erasure codes the object to the drives of the set, the cached
+// entry is kept if at least the data shards were written.
+func (s *cacheErasureSet) Put(ctx context.Context, bucket, object string, data io.Reader, size int64, opts ObjectOptions) error {
+	if s.onlineDrives() < s.dataBlocks {
+		return errDiskNotFound
+	}
+	erasure, err := NewErasure(ctx, s.dataBlocks, s.parityBlocks, cacheBlkSize)
+	if err != nil {
+		return err
+	}
+	for _, dcache := range s.drives {
+		if dcache != nil && dcache.diskUsageHigh() {
+			dcache.triggerPurge()
+			return errDiskFull
+		}
+	}
+	if !s.diskAvailable(size) {
+		return errDiskFull
+	}
+
+	// Shards of a previous version of the object left on drives not
+	// written to now must not be mistaken for the new version.
+	s.Delete(ctx, bucket, object)
+
+	shardSize := erasure.ShardFileSize(size)
+	writers := make([]*io.PipeWriter, len(s.drives))
+	errs := make([]chan error, len(s.drives))
+	for i, dcache := range s.drives {
+		if dcache == nil {
+			continue
+		}
+		pr, pw := io.Pipe()
+		writers[i] = pw
+		errs[i] = make(chan error, 1)
+		go func(i int, dcache *diskCache) {
+			cachePath := getCacheSHADir(dcache.dir, bucket, object)
+			_, err := dcache.bitrotWriteToCache(ctx, cachePath, pr, shardSize)
+			if IsErr(err, baseErrs...) {
+				atomic.AddUint64(&dcache.ioErrors, 1)
+				dcache.setOnline(false)
+			}
+			// Unblock the encoding of the remaining blocks if
+			// the shard could not be written.
+			pr.CloseWithError(err)
+			errs[i] <- err
+		}(i, dcache)
+	}
+
+	buf := make([]byte, cacheBlkSize)
+	for {
+		n, rerr := io.ReadFull(data, buf)
+		if rerr != nil && rerr != io.EOF && rerr != io.ErrUnexpectedEOF {
+			err = rerr
+			break
+		}
+		if n > 0 {
+			var shards [][]byte
+			if shards, err = erasure.EncodeData(ctx, buf[:n]); err != nil {
+				break
+			}
+			for i, w := range writers {
+				if w == nil {
+					continue
+				}
+				if _, werr := w.Write(shards[i]); werr != nil {
+					writers[i] = nil
+				}
+			}
+		}
+		if rerr != nil {
+			break
+		}
+	}
+
+	written := 0
+	for i, w := range writers {
+		if errs[i] == nil {
+			continue
+		}
+		if w != nil {
+			w.CloseWithError(err)
+		}
+		werr := <-errs[i]
+		if err == nil && werr == nil && w != nil {
+			info := &cacheErasureInfo{DataBlocks: s.dataBlocks, ParityBlocks: s.parityBlocks, Index: i}
+			if werr = s.drives[i].saveMetadata(ctx, bucket, object, opts.UserDefined, size, info); werr == nil {
+				atomic.AddUint64(&s.drives[i].fills, 1)
+				written++
+			}
+		}
+	}
+	if err == nil && written < s.dataBlocks {
+		err = errDiskNotFound
+	}
+	if err != nil {
+		s.Delete(ctx, bucket, object)
+	}
+	return err
+}
+
+// Get returns ObjectInfo and reader for the object cached in the set,
+// missing and corrupted shards are reconstructed from the parity.
+func (s *cacheErasureSet) Get(ctx context.Context, bucket, object string, rs *HTTPRangeSpec, h http.Header, opts ObjectOptions) (gr *GetObjectReader, err error) {
+	objInfo, drives, err := s.statShards(ctx, bucket, object)
+	if err != nil {
+		return nil, toObjectErr(err, bucket, object)
+	}
+	erasure, err := NewErasure(ctx, s.dataBlocks, s.parityBlocks, cacheBlkSize)
+	if err != nil {
+		return nil, err
+	}
+
+	var nsUnlocker = func() {}
+	fn, off, length, nErr := NewGetObjectReader(rs, objInfo, opts.CheckCopyPrecondFn, nsUnlocker)
+	if nErr != nil {
+		return nil, nErr
+	}
+	if length == 0 {
+		return fn(bytes.NewReader(nil), h, opts.CheckCopyPrecondFn)
+	}
+
+	pr, pw := io.Pipe()
+	go func() {
+		pw.CloseWithError(s.readShards(ctx, erasure, drives, bucket, object, objInfo.Size, off, length, pw))
+	}()
+	// Cleanup function to cause the go routine above to exit, in
+	// case of incomplete read.
+	pipeCloser := func() { pr.Close() }
+
+	return fn(pr, h, opts.CheckCopyPrecondFn, pipeCloser)
+}
+
+// readShards - decodes the blocks of the object spanning the range to
+// writer, reading the shards in parallel from the drives. Shards failing
+// their bitrot check are dropped and reconstructed from the parity,
+// the bitrot error is returned once there are not enough shards left.
+func (s *cacheErasureSet) readShards(ctx context.Context, erasure Erasure, drives []*diskCache, bucket, object string, size, offset, length int64, writer io.Writer) error {
+	startBlock := offset / cacheBlkSize
+	endBlock := (offset + length - 1) / cacheBlkSize
+	shardOffset := startBlock * erasure.ShardSize()
+	shardLength := erasure.ShardFileTillOffset(offset, length, size) - shardOffset
+
+	readers := make([]*io.PipeReader, len(drives))
+	for i, dcache := range drives {
+		if dcache == nil {
+			continue
+		}
+		pr, pw := io.Pipe()
+		readers[i] = pr
+		filePath := path.Join(getCacheSHADir(dcache.dir, bucket, object), cacheDataFile)
+		go func(dcache *diskCache) {
+			err := dcache.bitrotReadFromCache(ctx, filePath, shardOffset, shardLength, pw)
+			dcache.recordReadError(err)
+			pw.CloseWithError(err)
+		}(dcache)
+	}
+	defer func() {
+		for _, r := range readers {
+			if r != nil {
+				r.Close()
+			}
+		}
+	}()
+
+	shards := make([][]byte, len(drives))
+	bufs := make([][]byte, len(drives))
+	var readErr error
+	for block := startBlock; block <= endBlock; block++ {
+		blockSize := cacheBlkSize
+		if rest := size - block*cacheBlkSize; rest < blockSize {
+			blockSize = rest
+		}
+		shardLen := ceilFrac(blockSize, int64(s.dataBlocks))
+
+		available := 0
+		for i, r := range readers {
+			shards[i] = nil
+			if r == nil {
+				continue
+			}
+			if bufs[i] == nil {
+				bufs[i] = make([]byte, erasure.ShardSize())
+			}
+			if _, err := io.ReadFull(r, bufs[i][:shardLen]); err != nil {
+				readErr = err
+				r.Close()
+				readers[i] = nil
+				continue
+			}
+			shards[i] = bufs[i][:shardLen]
+			available++
+		}
+		if available < s.dataBlocks {
+			if readErr == nil {
+				readErr = errFileNotFound
+			}
+			return readErr
+		}
+		if err := erasure.DecodeDataBlocks(shards); err != nil {
+			return err
+		}
+
+		var blockData []byte
+		for _, shard := range shards[:s.dataBlocks] {
+			blockData = append(blockData, shard...)
+		}
+		blockData = blockData[:blockSize]
+		if block == endBlock {
+			blockData = blockData[:(offset+length-1)%cacheBlkSize+1]
+		}
+		if block == startBlock {
+			blockData = blockData[offset%cacheBlkSize:]
+		}
+		if _, err := writer.Write(blockData); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Delete removes the shards of the object from all drives of the set.
+func (s *cacheErasureSet) Delete(ctx context.Context, bucket, object string) (err error) {
+	for _, dcache := range s.drives {
+		if dcache == nil {
+			continue
+		}
+		if derr := dcache.Delete(ctx, bucket, object); derr != nil {
+			err = derr
+		}
+	}
+	return err
+}
+
+// Exists returns true if a shard of the object is cached in the set.
+func (s *cacheErasureSet) Exists(ctx context.Context, bucket, object string) bool {
+	for _, dcache := range s.drives {
+		if dcache != nil && dcache.Exists(ctx, bucket, object) {
+			return true
+		}
+	}
+	return false
+}
+
+// updateMetadataIfChanged resets the metadata of all shards of the object
+// if the backend metadata changed, shards keep their erasure layout.
+func (s *cacheErasureSet) updateMetadataIfChanged(ctx context.Context, bucket, object string, bkObjectInfo, cacheObjInfo ObjectInfo) error {
+	if reflect.DeepEqual(bkObjectInfo.UserDefined, cacheObjInfo.UserDefined) &&
+		bkObjectInfo.ETag == cacheObjInfo.ETag &&
+		bkObjectInfo.ContentType == cacheObjInfo.ContentType &&
+		bkObjectInfo.Expires == cacheObjInfo.Expires {
+		return nil
+	}
+	_, drives, err := s.statShards(ctx, bucket, object)
+	if err != nil {
+		return err
+	}
+	for i, dcache := range drives {
+		if dcache == nil {
+			continue
+		}
+		info := &cacheErasureInfo{DataBlocks: s.dataBlocks, ParityBlocks: s.parityBlocks, Index: i}
+		if err = dcache.saveMetadata(ctx, bucket, object, getMetadata(bkObjectInfo), bkObjectInfo.Size, info); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Returns if the disk usage of all online drives of the set is low.
+func (s *cacheErasureSet) diskUsageLow() bool {
+	for _, dcache := range s.drives {
+		if dcache != nil && !dcache.diskUsageLow() {
+			return false
+		}
+	}
+	return true
+}
+
+// Returns if the shards of an object of size can be written to all
+// online drives of the set without exceeding their max usage.
+func (s *cacheErasureSet) diskAvailable(size int64) bool {
+	shardSize := ceilFrac(size, int64(s.dataBlocks))
+	for _, dcache := range s.drives {
+		if dcache != nil && !dcache.diskAvailable(shardSize) {
+			return false
+		}
+	}
+	return true
+}
+
+// triggerPurge wakes up the purge of all online drives of the set.
+func (s *cacheErasureSet) triggerPurge() {
+	for _, dcache := range s.drives {
+		if dcache != nil {
+			dcache.triggerPurge()
+		}
+	}
+}
@@ -0,0 +1,131 @@
+/*
+ * MinIO Cloud Storage, (C) 2020 MinIO, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"testing"
+)
+
+// Tests that objects striped across the cache drives are read back
+// whole and by range, with missing and corrupted shards reconstructed
+// from the parity.
+func TestCacheErasureSet(t *testing.T) {
+	fsDirs, err := getRandomDisks(4)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer removeRoots(fsDirs)
+	d, err := initDiskCaches(fsDirs, 100, t)
+	if err != nil {
+		t.Fatal(err)
+	}
+	c := cacheObjects{cache: d, parity: 2}
+
+	ctx := context.Background()
+	bucket, object := "bucket", "object"
+	data := make([]byte, 2*cacheBlkSize+cacheBlkSize/3)
+	if _, err = rand.Read(data); err != nil {
+		t.Fatal(err)
+	}
+	set, ok := c.getCacheErasureSet(bucket, object)
+	if !ok {
+		t.Fatal("expected an erasure set with cache parity")
+	}
+	if set.dataBlocks != 2 || set.parityBlocks != 2 {
+		t.Fatalf("expected 2 data and 2 parity shards, got %d and %d", set.dataBlocks, set.parityBlocks)
+	}
+	metadata := map[string]string{"etag": "abc", "content-type": "application/octet-stream"}
+	if err = set.Put(ctx, bucket, object, bytes.NewReader(data), int64(len(data)), ObjectOptions{UserDefined: metadata}); err != nil {
+		t.Fatal(err)
+	}
+
+	// Shards are not served as objects by single drives.
+	for _, dcache := range d {
+		if _, err = dcache.Stat(ctx, bucket, object); err == nil {
+			t.Fatalf("expected the shard on %s not to be an object", dcache.dir)
+		}
+	}
+
+	read := func(rs *HTTPRangeSpec) ([]byte, error) {
+		gr, err := set.Get(ctx, bucket, object, rs, http.Header{}, ObjectOptions{})
+		if err != nil {
+			return nil, err
+		}
+		defer gr.Close()
+		if gr.ObjInfo.Size != int64(len(data)) || gr.ObjInfo.ETag != "abc" {
+			t.Fatalf("unexpected object info %v", gr.ObjInfo)
+		}
+		return ioutil.ReadAll(gr)
+	}
+	check := func() {
+		t.Helper()
+		ranges := []struct {
+			rs       *HTTPRangeSpec
+			expected []byte
+		}{
+			{nil, data},
+			{&HTTPRangeSpec{Start: 10, End: 20}, data[10:21]},
+			{&HTTPRangeSpec{Start: cacheBlkSize - 5, End: 2*cacheBlkSize + 5}, data[cacheBlkSize-5 : 2*cacheBlkSize+6]},
+			{&HTTPRangeSpec{Start: cacheBlkSize, End: 2*cacheBlkSize - 1}, data[cacheBlkSize : 2*cacheBlkSize]},
+			{&HTTPRangeSpec{IsSuffixLength: true, Start: -7}, data[len(data)-7:]},
+		}
+		for i, r := range ranges {
+			got, err := read(r.rs)
+			if err != nil {
+				t.Fatalf("range %d: %v", i+1, err)
+			}
+			if !bytes.Equal(got, r.expected) {
+				t.Fatalf("range %d: unexpected data of length %d", i+1, len(got))
+			}
+		}
+	}
+	check()
+
+	// A lost drive and a corrupted shard are reconstructed.
+	if err = d[0].Delete(ctx, bucket, object); err != nil {
+		t.Fatal(err)
+	}
+	f, err := os.OpenFile(pathJoin(getCacheSHADir(d[1].dir, bucket, object), cacheDataFile), os.O_WRONLY, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err = f.WriteAt([]byte("corrupted"), 100); err != nil {
+		t.Fatal(err)
+	}
+	f.Close()
+	check()
+
+	// Reads fail once there are less shards than data shards left.
+	if err = d[2].Delete(ctx, bucket, object); err != nil {
+		t.Fatal(err)
+	}
+	if _, err = read(nil); err == nil {
+		t.Fatal("expected reading a corrupted shard to fail")
+	}
+	if err = d[1].Delete(ctx, bucket, object); err != nil {
+		t.Fatal(err)
+	}
+	if _, err = set.Stat(ctx, bucket, object); err == nil {
+		t.Fatal("expected the object to be lost")
+	}
+}
@@ -0,0 +1,143 @@
+/*
+ * MinIO Cloud Storage, (C) 2020 MinIO, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"sync"
+
+	"github.com/minio/minio/cmd/logger"
+)
+
+// Cache drives are migrated one format version at a time, from the
+// version in their format.json to formatCacheVersionLatest. The format
+// version is updated once a drive is migrated to the next version, an
+// interrupted migration resumes from the last version reached. A new
+// cache layout adds its format version and a migration from the
+// previous latest version to cacheMigrations.
+
+// formatCacheVersionLatest - format version of the current cache layout.
+const formatCacheVersionLatest = formatCacheVersionV2
+
+// cacheMigration - migrates the contents of a cache drive from one
+// format version to the next.
+type cacheMigration struct {
+	from    string
+	to      string
+	migrate func(ctx context.Context, c *diskCache) error
+}
+
+// cacheMigrations - migrations of cache drives from older formats.
+var cacheMigrations = []cacheMigration{
+	{from: formatCacheVersionV1, to: formatCacheVersionV2, migrate: migrateOldCache},
+}
+
+// getCacheMigration - returns the migration of a cache drive of format
+// version to the next version.
+func getCacheMigration(version string) (cacheMigration, bool) {
+	for _, m := range cacheMigrations {
+		if m.from == version {
+			return m, true
+		}
+	}
+	return cacheMigration{}, false
+}
+
+// isCacheFormatVersionSupported - returns true if cache drives of format
+// version can be used, possibly after they are migrated.
+func isCacheFormatVersionSupported(version string) bool {
+	if version == formatCacheVersionLatest {
+		return true
+	}
+	_, ok := getCacheMigration(version)
+	return ok
+}
+
+// readCacheFormatVersion - returns the format version of a cache drive.
+func readCacheFormatVersion(drive string) (string, error) {
+	f, err := os.Open(pathJoin(drive, minioMetaBucket, formatConfigFile))
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+	return formatCacheGetVersion(f)
+}
+
+// setCacheFormatVersion - updates the format version of a cache drive.
+func setCacheFormatVersion(drive, version string) error {
+	f, err := os.OpenFile(pathJoin(drive, minioMetaBucket, formatConfigFile), os.O_RDWR, 0)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	format, err := formatMetaCacheV1(f)
+	if err != nil {
+		return err
+	}
+	format.Version = formatMetaVersion1
+	format.Cache.Version = version
+	return jsonSave(f, format)
+}
+
+// migrateCacheDrive - migrates a cache drive to the latest format.
+func migrateCacheDrive(ctx context.Context, c *diskCache) error {
+	for {
+		version, err := readCacheFormatVersion(c.dir)
+		if err != nil {
+			return err
+		}
+		if version == formatCacheVersionLatest {
+			return nil
+		}
+		m, ok := getCacheMigration(version)
+		if !ok {
+			return fmt.Errorf("Unsupported Cache backend format found [%s]", version)
+		}
+		if err = m.migrate(ctx, c); err != nil {
+			return err
+		}
+		if err = setCacheFormatVersion(c.dir, m.to); err != nil {
+			return err
+		}
+	}
+}
+
+// migrateCacheDrives - migrates the cache drives to the latest format
+// concurrently, returns the error of each drive. Drives which are nil
+// are skipped.
+func migrateCacheDrives(ctx context.Context, caches []*diskCache) []error {
+	var wg sync.WaitGroup
+	errs := make([]error, len(caches))
+	for i, dc := range caches {
+		if dc == nil {
+			continue
+		}
+		wg.Add(1)
+		go func(idx int, dc *diskCache) {
+			defer wg.Done()
+			if err := migrateCacheDrive(ctx, dc); err != nil {
+				reqInfo := (&logger.ReqInfo{}).AppendTags("cachePath", dc.dir)
+				logger.LogIf(logger.SetReqInfo(ctx, reqInfo), err)
+				errs[idx] = err
+			}
+		}(i, dc)
+	}
+	wg.Wait()
+	return errs
+}
@@ -0,0 +1,87 @@
+/*
+ * MinIO Cloud Storage, (C) 2020 MinIO, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"context"
+	"testing"
+)
+
+// Tests that cache drives are migrated one format version at a time
+// up to the latest format.
+func TestMigrateCacheDrive(t *testing.T) {
+	ctx := context.Background()
+	fsDirs, err := getRandomDisks(1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer removeRoots(fsDirs)
+
+	if _, err = initFormatCache(ctx, fsDirs); err != nil {
+		t.Fatal(err)
+	}
+	dc, err := newdiskCache(fsDirs[0], 0, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// A drive of the oldest format is migrated through each version.
+	var migrated []string
+	defer func(migrations []cacheMigration) {
+		cacheMigrations = migrations
+	}(cacheMigrations)
+	cacheMigrations = []cacheMigration{
+		{from: "0", to: formatCacheVersionV1, migrate: func(ctx context.Context, c *diskCache) error {
+			migrated = append(migrated, "0")
+			return nil
+		}},
+		{from: formatCacheVersionV1, to: formatCacheVersionV2, migrate: func(ctx context.Context, c *diskCache) error {
+			migrated = append(migrated, formatCacheVersionV1)
+			return migrateOldCache(ctx, c)
+		}},
+	}
+	if err = setCacheFormatVersion(fsDirs[0], "0"); err != nil {
+		t.Fatal(err)
+	}
+	if !isCacheFormatVersionSupported("0") || isCacheFormatVersionSupported("3") {
+		t.Fatal("Unexpected supported cache format versions")
+	}
+	if err = migrateCacheDrive(ctx, dc); err != nil {
+		t.Fatal(err)
+	}
+	if len(migrated) != 2 || migrated[0] != "0" || migrated[1] != formatCacheVersionV1 {
+		t.Fatalf("Unexpected migrations %v", migrated)
+	}
+	version, err := readCacheFormatVersion(fsDirs[0])
+	if err != nil {
+		t.Fatal(err)
+	}
+	if version != formatCacheVersionLatest {
+		t.Fatalf("Expected format %s, got %s", formatCacheVersionLatest, version)
+	}
+	if _, _, err = loadAndValidateCacheFormat(ctx, fsDirs); err != nil {
+		t.Fatal(err)
+	}
+
+	// Drives of unknown formats are not migrated.
+	if err = setCacheFormatVersion(fsDirs[0], "3"); err != nil {
+		t.Fatal(err)
+	}
+	if errs := migrateCacheDrives(ctx, []*diskCache{nil, dc}); errs[0] != nil || errs[1] == nil {
+		t.Fatalf("Expected the drive of an unknown format not to be migrated, got %v", errs)
+	}
+}
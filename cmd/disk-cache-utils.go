@@ -18,12 +18,14 @@ package cmd
 
 import (
 	"io"
+	"net/http"
 	"os"
 	"strconv"
 	"strings"
 	"time"
 
 	"github.com/minio/minio/cmd/crypto"
+	xhttp "github.com/minio/minio/cmd/http"
 )
 
 type cacheControl struct {
@@ -122,11 +124,48 @@ func backendDownError(err error) bool {
 	return backendDown || IsErr(err, baseErrs...)
 }
 
+// cacheControlAdmits - returns false if the cache control of the object
+// forbids shared caches to store it, i.e. holds no-store or private.
+func cacheControlAdmits(o ObjectInfo) bool {
+	for k, v := range o.UserDefined {
+		if strings.ToLower(k) != "cache-control" {
+			continue
+		}
+		for _, val := range strings.Split(strings.ToLower(v), ",") {
+			val = strings.TrimSpace(val)
+			if val == "no-store" || val == "private" {
+				return false
+			}
+		}
+	}
+	return true
+}
+
 // IsCacheable returns if the object should be saved in the cache.
 func (o ObjectInfo) IsCacheable() bool {
 	return !crypto.IsEncrypted(o.UserDefined)
 }
 
+// hasStrictPreconditions - returns true if the request headers hold
+// preconditions clients rely on for optimistic concurrency, they are
+// evaluated against the backend object instead of a cached copy still
+// fresh as per its cache control.
+func hasStrictPreconditions(h http.Header) bool {
+	for _, hdr := range []string{
+		xhttp.IfMatch,
+		xhttp.IfUnmodifiedSince,
+		xhttp.AmzCopySourceIfMatch,
+		xhttp.AmzCopySourceIfNoneMatch,
+		xhttp.AmzCopySourceIfModifiedSince,
+		xhttp.AmzCopySourceIfUnmodifiedSince,
+	} {
+		if h.Get(hdr) != "" {
+			return true
+		}
+	}
+	return false
+}
+
 // reads file cached on disk from offset upto length
 func readCacheFileStream(filePath string, offset, length int64) (io.ReadCloser, error) {
 	if filePath == "" || offset < 0 {
@@ -58,3 +58,23 @@ func TestGetCacheControlOpts(t *testing.T) {
 
 	}
 }
+
+func TestCacheControlAdmits(t *testing.T) {
+	testCases := []struct {
+		cacheControl string
+		admits       bool
+	}{
+		{"", true},
+		{"max-age=2592000, public", true},
+		{"max-age=2592000, no-store", false},
+		{"No-Store", false},
+		{"private, max-age=600", false},
+		{"no-cache", true},
+	}
+	for i, testCase := range testCases {
+		o := ObjectInfo{UserDefined: map[string]string{"Cache-Control": testCase.cacheControl}}
+		if admits := cacheControlAdmits(o); admits != testCase.admits {
+			t.Errorf("Test %d: expected %v, got %v", i+1, testCase.admits, admits)
+		}
+	}
+}
@@ -10,6 +10,7 @@ import (
 	"os"
 	"strings"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/djherbis/atime"
@@ -21,6 +22,22 @@ const (
 	cacheBlkSize = int64(1 * 1024 * 1024)
 )
 
+// Cache controls of a request, set with the x-minio-cache-control
+// header.
+const (
+	// Cached entries are revalidated against the backend, and are
+	// not served when the backend is down.
+	cacheControlNoCache = "no-cache"
+	// Objects are only served from the cache, requests for objects
+	// which are not cached fail with ObjectNotCached.
+	cacheControlOnlyIfCached = "only-if-cached"
+)
+
+// getRequestCacheControl - returns the cache control of the request.
+func getRequestCacheControl(opts ObjectOptions) string {
+	return strings.ToLower(strings.TrimSpace(opts.CacheControl))
+}
+
 // CacheStorageInfo - represents total, free capacity of
 // underlying cache storage.
 type CacheStorageInfo struct {
@@ -33,26 +50,84 @@ type CacheObjectLayer interface {
 	// Object operations.
 	GetObjectNInfo(ctx context.Context, bucket, object string, rs *HTTPRangeSpec, h http.Header, lockType LockType, opts ObjectOptions) (gr *GetObjectReader, err error)
 	GetObjectInfo(ctx context.Context, bucket, object string, opts ObjectOptions) (objInfo ObjectInfo, err error)
+	GetObjectNRanges(ctx context.Context, bucket, object, etag string, ranges []*HTTPRangeSpec, h http.Header, opts ObjectOptions) (readers []*GetObjectReader, err error)
 	DeleteObject(ctx context.Context, bucket, object string) error
 	DeleteObjects(ctx context.Context, bucket string, objects []string) ([]error, error)
+	// Transformed object operations.
+	GetObjectTransform(ctx context.Context, bucket, object, etag, params string) (gr *GetObjectReader, err error)
+	PutObjectTransform(ctx context.Context, bucket, object, etag, params string, data io.Reader, size int64, contentType string) error
 	// Storage operations.
 	StorageInfo(ctx context.Context) CacheStorageInfo
+	Stats() CacheStats
+	DriveStats(ctx context.Context) []CacheDriveStats
+}
+
+// CacheStats - represents the cache read statistics of the server.
+type CacheStats struct {
+	Corrupted uint64 // Cached entries which failed their bitrot check on read.
+	Healed    uint64 // Reads of corrupted entries completed from the backend.
+	Coalesced uint64 // Cache misses served by the cache fill of a concurrent miss.
+}
+
+// CacheDriveStats - represents the statistics of a cache drive since
+// the server started.
+type CacheDriveStats struct {
+	Drive        string  // Path of the cache drive.
+	UsagePercent float64 // Used space of the drive in percent.
+	Hits         uint64  // Reads served from the drive.
+	Misses       uint64  // Reads of objects not cached on the drive.
+	Fills        uint64  // Objects added to the drive.
+	Evictions    uint64  // Entries evicted to free cache space.
+	IOErrors     uint64  // Failed reads and writes of the drive.
 }
 
 // Abstracts disk caching - used by the S3 layer
 type cacheObjects struct {
+	// cache read statistics, updated atomically, kept first for
+	// the 64-bit alignment of atomic operations
+	corrupted uint64
+	healed    uint64
+	coalesced uint64
+
+	// protects the cache drives, exclude patterns, affinity,
+	// storage class policies and routing rules which are updated at
+	// runtime by updateConfig()
+	mu sync.RWMutex
+	// serializes updateConfig() calls
+	updateMu sync.Mutex
+	// paths of the cache drives, in the order of cache
+	drives []string
 	// slice of cache drives
 	cache []*diskCache
 	// file path patterns to exclude from cache
 	exclude []string
+	// indices of cache drives dedicated to a bucket
+	affinity map[string][]int
+	// indices of cache drives shared by buckets without affinity,
+	// all cache drives if nil
+	shared []int
+	// cache admission policies keyed by storage class
+	storageClass map[string]string
+	// number of parity shards of objects striped across the cache
+	// drives of their bucket, objects are cached whole on a single
+	// drive if zero
+	parity int
+	// rules routing objects to cache drives, ignored with parity
+	routes []cacheRoute
 	// to manage cache namespace locks
 	nsMutex *nsLockMap
 
-	// if true migration is in progress from v1 to v2
+	// if true migration to the latest cache format is in progress
 	migrating bool
 	// mutex to protect migration bool
 	migMutex sync.Mutex
 
+	// protects fills
+	fillMu sync.Mutex
+	// cache fills in progress keyed by bucket and object, created
+	// on first use
+	fills map[string]*cacheFill
+
 	// Object functions pointing to the corresponding functions of backend implementation.
 	GetObjectNInfoFn func(ctx context.Context, bucket, object string, rs *HTTPRangeSpec, h http.Header, lockType LockType, opts ObjectOptions) (gr *GetObjectReader, err error)
 	GetObjectInfoFn  func(ctx context.Context, bucket, object string, opts ObjectOptions) (objInfo ObjectInfo, err error)
@@ -60,7 +135,7 @@ type cacheObjects struct {
 	DeleteObjectsFn  func(ctx context.Context, bucket string, objects []string) ([]error, error)
 }
 
-func (c *cacheObjects) delete(ctx context.Context, dcache *diskCache, bucket, object string) (err error) {
+func (c *cacheObjects) delete(ctx context.Context, dcache cacheStore, bucket, object string) (err error) {
 	cLock := c.nsMutex.NewNSLock(ctx, bucket, object)
 	if err := cLock.GetLock(globalObjectTimeout); err != nil {
 		return err
@@ -69,7 +144,7 @@ func (c *cacheObjects) delete(ctx context.Context, dcache *diskCache, bucket, ob
 	return dcache.Delete(ctx, bucket, object)
 }
 
-func (c *cacheObjects) put(ctx context.Context, dcache *diskCache, bucket, object string, data io.Reader, size int64, opts ObjectOptions) error {
+func (c *cacheObjects) put(ctx context.Context, dcache cacheStore, bucket, object string, data io.Reader, size int64, opts ObjectOptions) error {
 	cLock := c.nsMutex.NewNSLock(ctx, bucket, object)
 	if err := cLock.GetLock(globalObjectTimeout); err != nil {
 		return err
@@ -78,7 +153,7 @@ func (c *cacheObjects) put(ctx context.Context, dcache *diskCache, bucket, objec
 	return dcache.Put(ctx, bucket, object, data, size, opts)
 }
 
-func (c *cacheObjects) get(ctx context.Context, dcache *diskCache, bucket, object string, rs *HTTPRangeSpec, h http.Header, opts ObjectOptions) (gr *GetObjectReader, err error) {
+func (c *cacheObjects) get(ctx context.Context, dcache cacheStore, bucket, object string, rs *HTTPRangeSpec, h http.Header, opts ObjectOptions) (gr *GetObjectReader, err error) {
 	cLock := c.nsMutex.NewNSLock(ctx, bucket, object)
 	if err := cLock.GetRLock(globalObjectTimeout); err != nil {
 		return nil, err
@@ -88,7 +163,7 @@ func (c *cacheObjects) get(ctx context.Context, dcache *diskCache, bucket, objec
 	return dcache.Get(ctx, bucket, object, rs, h, opts)
 }
 
-func (c *cacheObjects) stat(ctx context.Context, dcache *diskCache, bucket, object string) (oi ObjectInfo, err error) {
+func (c *cacheObjects) stat(ctx context.Context, dcache cacheStore, bucket, object string) (oi ObjectInfo, err error) {
 	cLock := c.nsMutex.NewNSLock(ctx, bucket, object)
 	if err := cLock.GetRLock(globalObjectTimeout); err != nil {
 		return oi, err
@@ -107,7 +182,9 @@ func (c *cacheObjects) DeleteObject(ctx context.Context, bucket, object string)
 		return
 	}
 
-	dcache, cerr := c.getCacheLoc(ctx, bucket, object)
+	// The object may be cached on another drive than the hinted
+	// one if cache drives were offline or added and removed.
+	dcache, cerr := c.getCacheStore(ctx, bucket, object)
 	if cerr != nil {
 		return
 	}
@@ -144,32 +221,51 @@ func getMetadata(objInfo ObjectInfo) map[string]string {
 }
 
 func (c *cacheObjects) GetObjectNInfo(ctx context.Context, bucket, object string, rs *HTTPRangeSpec, h http.Header, lockType LockType, opts ObjectOptions) (gr *GetObjectReader, err error) {
+	requestCC := getRequestCacheControl(opts)
 	if c.isCacheExclude(bucket, object) || c.skipCache() {
+		if requestCC == cacheControlOnlyIfCached {
+			return nil, ObjectNotCached{Bucket: bucket, Object: object}
+		}
 		return c.GetObjectNInfoFn(ctx, bucket, object, rs, h, lockType, opts)
 	}
 	var cc cacheControl
 
 	// fetch diskCache if object is currently cached or nearest available cache drive
-	dcache, err := c.getCacheToLoc(ctx, bucket, object)
+	dcache, err := c.getCacheStore(ctx, bucket, object)
 	if err != nil {
+		if requestCC == cacheControlOnlyIfCached {
+			return nil, ObjectNotCached{Bucket: bucket, Object: object}
+		}
 		return c.GetObjectNInfoFn(ctx, bucket, object, rs, h, lockType, opts)
 	}
 
+	// Cached entries are revalidated against the backend for strict
+	// preconditions and no-cache requests, and are not served when
+	// the backend is down.
+	strict := hasStrictPreconditions(h) || requestCC == cacheControlNoCache
+
 	cacheReader, cacheErr := c.get(ctx, dcache, bucket, object, rs, h, opts)
-	if cacheErr == nil {
+	recordCacheRead(dcache, cacheErr == nil)
+	if requestCC == cacheControlOnlyIfCached {
+		if cacheErr != nil {
+			return nil, ObjectNotCached{Bucket: bucket, Object: object}
+		}
+		return c.healOnRead(ctx, dcache, bucket, object, rs, h, lockType, opts, cacheReader)
+	}
+	if cacheErr == nil && !strict {
 		cc = cacheControlOpts(cacheReader.ObjInfo)
 		if !cc.isEmpty() && !cc.isStale(cacheReader.ObjInfo.ModTime) {
-			return cacheReader, nil
+			return c.healOnRead(ctx, dcache, bucket, object, rs, h, lockType, opts, cacheReader)
 		}
 	}
 
 	objInfo, err := c.GetObjectInfoFn(ctx, bucket, object, opts)
-	if backendDownError(err) && cacheErr == nil {
-		return cacheReader, nil
+	if backendDownError(err) && cacheErr == nil && !strict {
+		return c.healOnRead(ctx, dcache, bucket, object, rs, h, lockType, opts, cacheReader)
 	} else if err != nil {
-		if _, ok := err.(ObjectNotFound); ok {
-			if cacheErr == nil {
-				cacheReader.Close()
+		if cacheErr == nil {
+			cacheReader.Close()
+			if _, ok := err.(ObjectNotFound); ok {
 				// Delete cached entry if backend object
 				// was deleted.
 				dcache.Delete(ctx, bucket, object)
@@ -187,44 +283,68 @@ func (c *cacheObjects) GetObjectNInfo(ctx context.Context, bucket, object string
 		if cacheReader.ObjInfo.ETag == objInfo.ETag {
 			// Update metadata in case server-side copy might have changed object metadata
 			dcache.updateMetadataIfChanged(ctx, bucket, object, objInfo, cacheReader.ObjInfo)
-			return cacheReader, nil
+			return c.healOnRead(ctx, dcache, bucket, object, rs, h, lockType, opts, cacheReader)
 		}
 		cacheReader.Close()
 		// Object is stale, so delete from cache
 		c.delete(ctx, dcache, bucket, object)
 	}
 
+	// The object content is not read when the request is answered
+	// with 304 or 412, do not add the object to the cache then.
+	if getPreconditionStatus(h, objInfo) != 0 {
+		return c.GetObjectNInfoFn(ctx, bucket, object, rs, h, lockType, opts)
+	}
+
+	// Objects of excluded storage classes, and objects which shared
+	// caches must not store as per their cache control, are not added
+	// to the cache.
+	policy := c.storageClassPolicy(objInfo)
+	if policy == cacheStorageClassExclude || !cacheControlAdmits(objInfo) {
+		return c.GetObjectNInfoFn(ctx, bucket, object, rs, h, lockType, opts)
+	}
+
+	// Routing rules override the cache drive hinted by the hash.
+	if routed := c.routeCacheDrive(bucket, object, objInfo); routed != nil {
+		dcache = routed
+	}
+
 	// Since we got here, we are serving the request from backend,
 	// and also adding the object to the cache.
 	if !dcache.diskUsageLow() {
-		select {
-		case dcache.purgeChan <- struct{}{}:
-		default:
+		dcache.triggerPurge()
+		// Once the cache usage is high, only objects of priority
+		// storage classes are added to the cache, if any.
+		if policy != cacheStorageClassPriority && c.hasPriorityStorageClass() {
+			return c.GetObjectNInfoFn(ctx, bucket, object, rs, h, lockType, opts)
 		}
 	}
 	if !dcache.diskAvailable(objInfo.Size) {
 		return c.GetObjectNInfoFn(ctx, bucket, object, rs, h, lockType, opts)
 	}
 
-	if rs != nil {
-		go func() {
-			// fill cache in the background for range GET requests
-			bReader, bErr := c.GetObjectNInfoFn(ctx, bucket, object, nil, h, lockType, opts)
-			if bErr != nil {
-				return
-			}
-			defer bReader.Close()
-			oi, err := c.stat(ctx, dcache, bucket, object)
-			// avoid cache overwrite if another background routine filled cache
-			if err != nil || oi.ETag != bReader.ObjInfo.ETag {
-				c.put(ctx, dcache, bucket, object, bReader, bReader.ObjInfo.Size, ObjectOptions{UserDefined: getMetadata(bReader.ObjInfo)})
-			}
-		}()
+	// Fill cache in the background for range GET requests, and when
+	// background transfers are throttled since filling the cache along
+	// with the client download would throttle the client too.
+	if rs != nil || (globalBandwidthSys != nil && globalBandwidthSys.IsLimited(bucket)) {
+		go c.fillCache(GlobalContext, dcache, bucket, object, h, opts)
+		return c.GetObjectNInfoFn(ctx, bucket, object, rs, h, lockType, opts)
+	}
+
+	// Concurrent misses on the object wait for the cache fill of the
+	// first one and are served from the cache, a single backend read
+	// fills the cache.
+	fill, leader := c.startFill(dcache, bucket, object)
+	if !leader {
+		if gr, err := c.waitFill(ctx, fill, bucket, object, objInfo.ETag, h, lockType, opts); err == nil {
+			return gr, nil
+		}
 		return c.GetObjectNInfoFn(ctx, bucket, object, rs, h, lockType, opts)
 	}
 
 	bkReader, bkErr := c.GetObjectNInfoFn(ctx, bucket, object, rs, h, lockType, opts)
 	if bkErr != nil {
+		c.endFill(bucket, object, fill, bkErr)
 		return nil, bkErr
 	}
 	// Initialize pipe.
@@ -232,6 +352,7 @@ func (c *cacheObjects) GetObjectNInfo(ctx context.Context, bucket, object string
 	teeReader := io.TeeReader(bkReader, pipeWriter)
 	go func() {
 		putErr := dcache.Put(ctx, bucket, object, io.LimitReader(pipeReader, bkReader.ObjInfo.Size), bkReader.ObjInfo.Size, ObjectOptions{UserDefined: getMetadata(bkReader.ObjInfo)})
+		c.endFill(bucket, object, fill, putErr)
 		// close the write end of the pipe, so the error gets
 		// propagated to getObjReader
 		pipeWriter.CloseWithError(putErr)
@@ -241,23 +362,311 @@ func (c *cacheObjects) GetObjectNInfo(ctx context.Context, bucket, object string
 	return NewGetObjectReaderFromReader(teeReader, bkReader.ObjInfo, opts.CheckCopyPrecondFn, cleanupBackend, cleanupPipe)
 }
 
+// fillCache - adds the object to the cache in the background, it
+// outlives the client request so ctx is expected to be GlobalContext.
+// The transfer is throttled by the bandwidth limits, hence the backend
+// is read without holding the object lock, the cached copy is dropped
+// if the object was replaced meanwhile.
+// getCacheTransformName - returns the name of the cache entry of the
+// object transformed with the parameters.
+func getCacheTransformName(object, params string) string {
+	return object + "?" + params
+}
+
+// GetObjectTransform - returns the cached transformation of the object
+// with the parameters, transformations cached for another ETag of the
+// object are invalidated.
+func (c *cacheObjects) GetObjectTransform(ctx context.Context, bucket, object, etag, params string) (gr *GetObjectReader, err error) {
+	if c.isCacheExclude(bucket, object) || c.skipCache() {
+		return nil, ObjectNotFound{Bucket: bucket, Object: object}
+	}
+	name := getCacheTransformName(object, params)
+	dcache, err := c.getCacheStoreLoc(ctx, bucket, name)
+	if err != nil {
+		return nil, err
+	}
+	if gr, err = c.get(ctx, dcache, bucket, name, nil, http.Header{}, ObjectOptions{}); err != nil {
+		return nil, err
+	}
+	// The ETag of the cache entry is the ETag of the object it
+	// was transformed from.
+	if gr.ObjInfo.ETag != etag {
+		gr.Close()
+		c.delete(ctx, dcache, bucket, name)
+		return nil, ObjectNotFound{Bucket: bucket, Object: object}
+	}
+	return gr, nil
+}
+
+// PutObjectTransform - caches the transformation of the object with
+// the given ETag with the parameters.
+func (c *cacheObjects) PutObjectTransform(ctx context.Context, bucket, object, etag, params string, data io.Reader, size int64, contentType string) error {
+	if c.isCacheExclude(bucket, object) || c.skipCache() {
+		return nil
+	}
+	name := getCacheTransformName(object, params)
+	dcache, err := c.getCacheStoreLoc(ctx, bucket, name)
+	if err != nil {
+		return err
+	}
+	metadata := map[string]string{"etag": etag, "content-type": contentType}
+	return c.put(ctx, dcache, bucket, name, data, size, ObjectOptions{UserDefined: metadata})
+}
+
+func (c *cacheObjects) fillCache(ctx context.Context, dcache cacheStore, bucket, object string, h http.Header, opts ObjectOptions) {
+	// The object is being added to the cache already.
+	fill, leader := c.startFill(dcache, bucket, object)
+	if !leader {
+		return
+	}
+	var err error
+	defer func() {
+		c.endFill(bucket, object, fill, err)
+	}()
+
+	bReader, err := c.GetObjectNInfoFn(ctx, bucket, object, nil, h, noLock, opts)
+	if err != nil {
+		return
+	}
+	defer bReader.Close()
+
+	// avoid cache overwrite if another background routine filled cache
+	if oi, serr := c.stat(ctx, dcache, bucket, object); serr == nil && oi.ETag == bReader.ObjInfo.ETag {
+		return
+	}
+	var data io.Reader = bReader
+	if globalBandwidthSys != nil {
+		data = globalBandwidthSys.NewReader(ctx, bucket, bReader)
+	}
+	if err = c.put(ctx, dcache, bucket, object, data, bReader.ObjInfo.Size, ObjectOptions{UserDefined: getMetadata(bReader.ObjInfo)}); err != nil {
+		return
+	}
+	if objInfo, err := c.GetObjectInfoFn(ctx, bucket, object, opts); err != nil || objInfo.ETag != bReader.ObjInfo.ETag {
+		c.delete(ctx, dcache, bucket, object)
+	}
+}
+
+// cacheFill - a cache fill of an object in progress, err is set once
+// done is closed.
+type cacheFill struct {
+	dcache cacheStore
+	done   chan struct{}
+	err    error
+}
+
+// startFill - registers a cache fill of the object to dcache, returns
+// the fill in progress and false if the object is being added to the
+// cache already.
+func (c *cacheObjects) startFill(dcache cacheStore, bucket, object string) (*cacheFill, bool) {
+	c.fillMu.Lock()
+	defer c.fillMu.Unlock()
+
+	key := pathJoin(bucket, object)
+	if fill, ok := c.fills[key]; ok {
+		return fill, false
+	}
+	if c.fills == nil {
+		c.fills = make(map[string]*cacheFill)
+	}
+	fill := &cacheFill{dcache: dcache, done: make(chan struct{})}
+	c.fills[key] = fill
+	return fill, true
+}
+
+// endFill - records the outcome of a cache fill registered by
+// startFill and wakes up its waiters.
+func (c *cacheObjects) endFill(bucket, object string, fill *cacheFill, err error) {
+	c.fillMu.Lock()
+	delete(c.fills, pathJoin(bucket, object))
+	c.fillMu.Unlock()
+
+	fill.err = err
+	close(fill.done)
+}
+
+// waitFill - waits for a cache fill in progress and serves the object
+// from the cache, fails if the fill failed or cached another version
+// than the one with the given ETag.
+func (c *cacheObjects) waitFill(ctx context.Context, fill *cacheFill, bucket, object, etag string, h http.Header, lockType LockType, opts ObjectOptions) (*GetObjectReader, error) {
+	select {
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	case <-fill.done:
+	}
+	if fill.err != nil {
+		return nil, fill.err
+	}
+	gr, err := c.get(ctx, fill.dcache, bucket, object, nil, h, opts)
+	if err != nil {
+		return nil, err
+	}
+	if gr.ObjInfo.ETag != etag {
+		gr.Close()
+		return nil, ObjectNotFound{Bucket: bucket, Object: object}
+	}
+	atomic.AddUint64(&c.coalesced, 1)
+	return c.healOnRead(ctx, fill.dcache, bucket, object, nil, h, lockType, opts, gr)
+}
+
+// healOnRead - wraps a reader of a cached entry, if the entry fails its
+// bitrot check while being read, it is invalidated and re-cached in the
+// background, and the rest of the range is read from the backend so
+// the client does not see the corruption.
+func (c *cacheObjects) healOnRead(ctx context.Context, dcache cacheStore, bucket, object string, rs *HTTPRangeSpec, h http.Header, lockType LockType, opts ObjectOptions, cacheReader *GetObjectReader) (*GetObjectReader, error) {
+	// Directories and empty objects have no data to be corrupted.
+	if cacheReader.ObjInfo.Size == 0 || hasSuffix(object, SlashSeparator) {
+		return cacheReader, nil
+	}
+	off, length, err := rs.GetOffsetLength(cacheReader.ObjInfo.Size)
+	if err != nil {
+		return cacheReader, nil
+	}
+	hr := &cacheHealReader{
+		cacheReader: cacheReader,
+		heal: func(read int64, cacheErr error) (*GetObjectReader, error) {
+			atomic.AddUint64(&c.corrupted, 1)
+			c.delete(ctx, dcache, bucket, object)
+			go c.fillCache(GlobalContext, dcache, bucket, object, h, opts)
+			if read == length {
+				return nil, nil
+			}
+			bkReader, err := c.GetObjectNInfoFn(ctx, bucket, object, &HTTPRangeSpec{Start: off + read, End: off + length - 1}, h, lockType, opts)
+			if err != nil {
+				return nil, cacheErr
+			}
+			// The remaining data must be of the same object version.
+			if bkReader.ObjInfo.ETag != cacheReader.ObjInfo.ETag {
+				bkReader.Close()
+				return nil, cacheErr
+			}
+			atomic.AddUint64(&c.healed, 1)
+			return bkReader, nil
+		},
+	}
+	return NewGetObjectReaderFromReader(hr, cacheReader.ObjInfo, nil, hr.close)
+}
+
+// cacheHealReader reads a cached entry and switches to reading the
+// rest of the data from the backend when the entry is corrupted.
+type cacheHealReader struct {
+	cacheReader *GetObjectReader
+	bkReader    *GetObjectReader
+	read        int64
+	heal        func(read int64, cacheErr error) (*GetObjectReader, error)
+}
+
+func (r *cacheHealReader) Read(p []byte) (int, error) {
+	if r.bkReader != nil {
+		return r.bkReader.Read(p)
+	}
+	n, err := r.cacheReader.Read(p)
+	r.read += int64(n)
+	if _, ok := err.(HashMismatchError); !ok {
+		return n, err
+	}
+	r.cacheReader.Close()
+	bkReader, err := r.heal(r.read, err)
+	if err != nil {
+		return n, err
+	}
+	if bkReader == nil {
+		return n, io.EOF
+	}
+	r.bkReader = bkReader
+	if n > 0 {
+		return n, nil
+	}
+	return r.bkReader.Read(p)
+}
+
+func (r *cacheHealReader) close() {
+	r.cacheReader.Close()
+	if r.bkReader != nil {
+		r.bkReader.Close()
+	}
+}
+
+// GetObjectNRanges - opens a reader for each of the given ranges of the
+// object version with the given ETag. The first range is served like a
+// single range GET, which validates the cache entry and fills the cache
+// if needed. Remaining ranges are read in parallel from the cache if the
+// object is cached, or from the backend otherwise.
+func (c *cacheObjects) GetObjectNRanges(ctx context.Context, bucket, object, etag string, ranges []*HTTPRangeSpec, h http.Header, opts ObjectOptions) (readers []*GetObjectReader, err error) {
+	onlyIfCached := getRequestCacheControl(opts) == cacheControlOnlyIfCached
+	if c.isCacheExclude(bucket, object) || c.skipCache() {
+		if onlyIfCached {
+			return nil, ObjectNotCached{Bucket: bucket, Object: object}
+		}
+		return getObjectNRanges(ctx, c.GetObjectNInfoFn, bucket, object, etag, ranges, h, opts)
+	}
+
+	dcache, err := c.getCacheStore(ctx, bucket, object)
+	if err != nil {
+		if onlyIfCached {
+			return nil, ObjectNotCached{Bucket: bucket, Object: object}
+		}
+		return getObjectNRanges(ctx, c.GetObjectNInfoFn, bucket, object, etag, ranges, h, opts)
+	}
+
+	first, err := getObjectNRanges(ctx, c.GetObjectNInfo, bucket, object, etag, ranges[:1], h, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	getObjectNInfo := c.GetObjectNInfoFn
+	if onlyIfCached {
+		getObjectNInfo = func(ctx context.Context, bucket, object string, rs *HTTPRangeSpec, h http.Header, lockType LockType, opts ObjectOptions) (*GetObjectReader, error) {
+			return nil, ObjectNotCached{Bucket: bucket, Object: object}
+		}
+	}
+	if oi, err := c.stat(ctx, dcache, bucket, object); err == nil && oi.ETag == etag {
+		getObjectNInfo = func(ctx context.Context, bucket, object string, rs *HTTPRangeSpec, h http.Header, lockType LockType, opts ObjectOptions) (*GetObjectReader, error) {
+			gr, err := c.get(ctx, dcache, bucket, object, rs, h, opts)
+			if err != nil {
+				return nil, err
+			}
+			return c.healOnRead(ctx, dcache, bucket, object, rs, h, lockType, opts, gr)
+		}
+	}
+
+	rest, err := getObjectNRanges(ctx, getObjectNInfo, bucket, object, etag, ranges[1:], h, opts)
+	if err != nil {
+		first[0].Close()
+		return nil, err
+	}
+	return append(first, rest...), nil
+}
+
 // Returns ObjectInfo from cache if available.
 func (c *cacheObjects) GetObjectInfo(ctx context.Context, bucket, object string, opts ObjectOptions) (ObjectInfo, error) {
 	getObjectInfoFn := c.GetObjectInfoFn
+	requestCC := getRequestCacheControl(opts)
 
 	if c.isCacheExclude(bucket, object) || c.skipCache() {
+		if requestCC == cacheControlOnlyIfCached {
+			return ObjectInfo{}, ObjectNotCached{Bucket: bucket, Object: object}
+		}
 		return getObjectInfoFn(ctx, bucket, object, opts)
 	}
 
 	// fetch diskCache if object is currently cached or nearest available cache drive
-	dcache, err := c.getCacheToLoc(ctx, bucket, object)
+	dcache, err := c.getCacheStore(ctx, bucket, object)
 	if err != nil {
+		if requestCC == cacheControlOnlyIfCached {
+			return ObjectInfo{}, ObjectNotCached{Bucket: bucket, Object: object}
+		}
 		return getObjectInfoFn(ctx, bucket, object, opts)
 	}
 	var cc cacheControl
 	// if cache control setting is valid, avoid HEAD operation to backend
 	cachedObjInfo, cerr := c.stat(ctx, dcache, bucket, object)
-	if cerr == nil {
+	if requestCC == cacheControlOnlyIfCached {
+		if cerr != nil {
+			return ObjectInfo{}, ObjectNotCached{Bucket: bucket, Object: object}
+		}
+		return cachedObjInfo, nil
+	}
+	if cerr == nil && requestCC != cacheControlNoCache {
 		cc = cacheControlOpts(cachedObjInfo)
 		if !cc.isEmpty() && !cc.isStale(cachedObjInfo.ModTime) {
 			return cachedObjInfo, nil
@@ -274,7 +683,7 @@ func (c *cacheObjects) GetObjectInfo(ctx context.Context, bucket, object string,
 		if !backendDownError(err) {
 			return ObjectInfo{}, err
 		}
-		if cerr == nil {
+		if cerr == nil && requestCC != cacheControlNoCache {
 			return cachedObjInfo, nil
 		}
 		return ObjectInfo{}, BackendDown{}
@@ -293,6 +702,9 @@ func (c *cacheObjects) GetObjectInfo(ctx context.Context, bucket, object string,
 
 // StorageInfo - returns underlying storage statistics.
 func (c *cacheObjects) StorageInfo(ctx context.Context) (cInfo CacheStorageInfo) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
 	var total, free uint64
 	for _, cache := range c.cache {
 		if cache == nil {
@@ -310,6 +722,67 @@ func (c *cacheObjects) StorageInfo(ctx context.Context) (cInfo CacheStorageInfo)
 	}
 }
 
+// Stats - returns the cache read statistics.
+func (c *cacheObjects) Stats() CacheStats {
+	return CacheStats{
+		Corrupted: atomic.LoadUint64(&c.corrupted),
+		Healed:    atomic.LoadUint64(&c.healed),
+		Coalesced: atomic.LoadUint64(&c.coalesced),
+	}
+}
+
+// DriveStats - returns the statistics of each online cache drive.
+func (c *cacheObjects) DriveStats(ctx context.Context) []CacheDriveStats {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	var stats []CacheDriveStats
+	for _, cache := range c.cache {
+		if cache == nil {
+			continue
+		}
+		st := CacheDriveStats{
+			Drive:     cache.dir,
+			Hits:      atomic.LoadUint64(&cache.hits),
+			Misses:    atomic.LoadUint64(&cache.misses),
+			Fills:     atomic.LoadUint64(&cache.fills),
+			Evictions: atomic.LoadUint64(&cache.evictions),
+			IOErrors:  atomic.LoadUint64(&cache.ioErrors),
+		}
+		info, err := getDiskInfo(cache.dir)
+		if err != nil {
+			logger.GetReqInfo(ctx).AppendTags("cachePath", cache.dir)
+			logger.LogIf(ctx, err)
+		} else if info.Total > 0 {
+			st.UsagePercent = float64(info.Total-info.Free) * 100 / float64(info.Total)
+		}
+		stats = append(stats, st)
+	}
+	return stats
+}
+
+// recordCacheRead - counts a read of dcache as a hit or a miss of its
+// drives.
+func recordCacheRead(dcache cacheStore, hit bool) {
+	var drives []*diskCache
+	switch s := dcache.(type) {
+	case *diskCache:
+		drives = []*diskCache{s}
+	case *cacheErasureSet:
+		drives = s.drives
+	}
+	for _, d := range drives {
+		if d == nil {
+			continue
+		}
+		if hit {
+			atomic.AddUint64(&d.hits, 1)
+		} else {
+			atomic.AddUint64(&d.misses, 1)
+		}
+	}
+}
+
 // skipCache() returns true if cache migration is in progress
 func (c *cacheObjects) skipCache() bool {
 	c.migMutex.Lock()
@@ -323,6 +796,8 @@ func (c *cacheObjects) isCacheExclude(bucket, object string) bool {
 	if strings.HasSuffix(object, SlashSeparator) {
 		return true
 	}
+	c.mu.RLock()
+	defer c.mu.RUnlock()
 	for _, pattern := range c.exclude {
 		matchStr := fmt.Sprintf("%s/%s", bucket, object)
 		if ok := wildcard.MatchSimple(pattern, matchStr); ok {
@@ -332,14 +807,46 @@ func (c *cacheObjects) isCacheExclude(bucket, object string) bool {
 	return false
 }
 
+// Returns the cache admission policy of the storage class of the object,
+// empty if no policy is set for it.
+func (c *cacheObjects) storageClassPolicy(objInfo ObjectInfo) string {
+	storageClass := objInfo.StorageClass
+	if storageClass == "" {
+		storageClass = objInfo.UserDefined[amzStorageClass]
+	}
+	if storageClass == "" {
+		storageClass = globalMinioDefaultStorageClass
+	}
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.storageClass[storageClass]
+}
+
+// Returns true if a storage class has priority for caching.
+func (c *cacheObjects) hasPriorityStorageClass() bool {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	for _, policy := range c.storageClass {
+		if policy == cacheStorageClassPriority {
+			return true
+		}
+	}
+	return false
+}
+
 // choose a cache deterministically based on hash of bucket,object. The hash index is treated as
 // a hint. In the event that the cache drive at hash index is offline, treat the list of cache drives
 // as a circular buffer and walk through them starting at hash index until an online drive is found.
+// Buckets with cache affinity only use the cache drives dedicated to them.
 func (c *cacheObjects) getCacheLoc(ctx context.Context, bucket, object string) (*diskCache, error) {
-	index := c.hashIndex(bucket, object)
-	numDisks := len(c.cache)
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	drives := c.cacheDrives(bucket)
+	index := crcHashMod(pathJoin(bucket, object), len(drives))
+	numDisks := len(drives)
 	for k := 0; k < numDisks; k++ {
-		i := (index + k) % numDisks
+		i := drives[(index+k)%numDisks]
 		if c.cache[i] == nil {
 			continue
 		}
@@ -350,18 +857,50 @@ func (c *cacheObjects) getCacheLoc(ctx context.Context, bucket, object string) (
 	return nil, errDiskNotFound
 }
 
+// routeCacheDrive - returns the cache drive of the first routing rule
+// matching the object, the drives of the rule are walked from the hash
+// index of the object until an online drive is found. Returns nil if no
+// rule matches, if no drive of the matching rule is online or if
+// objects are striped across the cache drives with parity.
+func (c *cacheObjects) routeCacheDrive(bucket, object string, objInfo ObjectInfo) *diskCache {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	if c.parity > 0 {
+		return nil
+	}
+	for _, route := range c.routes {
+		if !route.matches(objInfo) {
+			continue
+		}
+		index := crcHashMod(pathJoin(bucket, object), len(route.drives))
+		for k := range route.drives {
+			i := route.drives[(index+k)%len(route.drives)]
+			if c.cache[i] != nil && c.cache[i].IsOnline() {
+				return c.cache[i]
+			}
+		}
+		return nil
+	}
+	return nil
+}
+
 // get cache disk where object is currently cached for a GET operation. If object does not exist at that location,
 // treat the list of cache drives as a circular buffer and walk through them starting at hash index
 // until an online drive is found.If object is not found, fall back to the first online cache drive
 // closest to the hash index, so that object can be re-cached.
 func (c *cacheObjects) getCacheToLoc(ctx context.Context, bucket, object string) (*diskCache, error) {
-	index := c.hashIndex(bucket, object)
+	c.mu.RLock()
+	defer c.mu.RUnlock()
 
-	numDisks := len(c.cache)
+	drives := c.cacheDrives(bucket)
+	index := crcHashMod(pathJoin(bucket, object), len(drives))
+
+	numDisks := len(drives)
 	// save first online cache disk closest to the hint index
 	var firstOnlineDisk *diskCache
 	for k := 0; k < numDisks; k++ {
-		i := (index + k) % numDisks
+		i := drives[(index+k)%numDisks]
 		if c.cache[i] == nil {
 			continue
 		}
@@ -375,15 +914,111 @@ func (c *cacheObjects) getCacheToLoc(ctx context.Context, bucket, object string)
 		}
 	}
 
+	// Objects routed by their metadata may be cached on other drives
+	// than the drives of their bucket.
+	if len(c.routes) > 0 {
+		seen := make(map[int]bool, numDisks)
+		for _, i := range drives {
+			seen[i] = true
+		}
+		for _, route := range c.routes {
+			for _, i := range route.drives {
+				if seen[i] || c.cache[i] == nil {
+					continue
+				}
+				seen[i] = true
+				if c.cache[i].IsOnline() && c.cache[i].Exists(ctx, bucket, object) {
+					return c.cache[i], nil
+				}
+			}
+		}
+	}
+
 	if firstOnlineDisk != nil {
 		return firstOnlineDisk, nil
 	}
 	return nil, errDiskNotFound
 }
 
-// Compute a unique hash sum for bucket and object
+// getCacheStore - returns the cache store of the object for a GET
+// operation. With cache parity, objects are striped across the erasure
+// set of the cache drives of their bucket, otherwise they are cached on
+// the drive returned by getCacheToLoc.
+func (c *cacheObjects) getCacheStore(ctx context.Context, bucket, object string) (cacheStore, error) {
+	if set, ok := c.getCacheErasureSet(bucket, object); ok {
+		if set.onlineDrives() < set.dataBlocks {
+			return nil, errDiskNotFound
+		}
+		return set, nil
+	}
+	dcache, err := c.getCacheToLoc(ctx, bucket, object)
+	if err != nil {
+		return nil, err
+	}
+	return dcache, nil
+}
+
+// getCacheStoreLoc - same as getCacheStore, without cache parity the
+// object is cached on the drive returned by getCacheLoc.
+func (c *cacheObjects) getCacheStoreLoc(ctx context.Context, bucket, object string) (cacheStore, error) {
+	if set, ok := c.getCacheErasureSet(bucket, object); ok {
+		if set.onlineDrives() < set.dataBlocks {
+			return nil, errDiskNotFound
+		}
+		return set, nil
+	}
+	dcache, err := c.getCacheLoc(ctx, bucket, object)
+	if err != nil {
+		return nil, err
+	}
+	return dcache, nil
+}
+
+// getCacheErasureSet - returns the erasure set of the cache drives of
+// the bucket, with the shards of the object placed starting at its
+// hinted drive. Returns false if cache parity is disabled.
+func (c *cacheObjects) getCacheErasureSet(bucket, object string) (*cacheErasureSet, bool) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	drives := c.cacheDrives(bucket)
+	if c.parity == 0 || len(drives) == 0 {
+		return nil, false
+	}
+	caches := make([]*diskCache, len(drives))
+	for i, d := range drives {
+		caches[i] = c.cache[d]
+	}
+	return newCacheErasureSet(caches, crcHashMod(pathJoin(bucket, object), len(drives)), c.parity), true
+}
+
+// Compute a unique hash sum for bucket and object, returns the index
+// of the cache drive hinted for the object.
 func (c *cacheObjects) hashIndex(bucket, object string) int {
-	return crcHashMod(pathJoin(bucket, object), len(c.cache))
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	drives := c.cacheDrives(bucket)
+	if len(drives) == 0 {
+		return -1
+	}
+	return drives[crcHashMod(pathJoin(bucket, object), len(drives))]
+}
+
+// Returns the indices of the cache drives eligible to cache objects
+// of the given bucket, the caller must hold c.mu.
+func (c *cacheObjects) cacheDrives(bucket string) []int {
+	if drives, ok := c.affinity[bucket]; ok {
+		return drives
+	}
+	if c.shared != nil {
+		return c.shared
+	}
+	drives := make([]int, len(c.cache))
+	for i := range drives {
+		drives[i] = i
+	}
+	return drives
 }
 
 // newCache initializes the cacheFSObjects for the "drives" specified in config.json
@@ -409,6 +1044,7 @@ func newCache(config CacheConfig) ([]*diskCache, bool, error) {
 		if err != nil {
 			return nil, false, err
 		}
+		cache.setQuota(config.Quota)
 		// Start the purging go-routine for entries that have expired if no migration in progress
 		if !migrating {
 			go cache.purge()
@@ -444,38 +1080,24 @@ func checkAtimeSupport(dir string) (err error) {
 	}
 	return
 }
-func (c *cacheObjects) migrateCacheFromV1toV2(ctx context.Context) {
+
+// migrateCacheFormats - migrates the cache drives to the latest cache
+// format in the background, the cache is skipped until all drives are
+// migrated.
+func (c *cacheObjects) migrateCacheFormats(ctx context.Context) {
 	logger.StartupMessage(colorBlue("Cache migration initiated ...."))
-	var wg = &sync.WaitGroup{}
-	errs := make([]error, len(c.cache))
+	errs := migrateCacheDrives(ctx, c.cache)
 	for i, dc := range c.cache {
-		if dc == nil {
-			continue
-		}
-		wg.Add(1)
-		// start migration from V1 to V2
-		go func(ctx context.Context, dc *diskCache, errs []error, idx int) {
-			defer wg.Done()
-			if err := migrateOldCache(ctx, dc); err != nil {
-				errs[idx] = err
-				logger.LogIf(ctx, err)
-				return
-			}
-			// start purge routine after migration completes.
+		// start purge routine after migration completes.
+		if dc != nil && errs[i] == nil {
 			go dc.purge()
-		}(ctx, dc, errs, i)
+		}
 	}
-	wg.Wait()
-
-	errCnt := 0
 	for _, err := range errs {
 		if err != nil {
-			errCnt++
+			return
 		}
 	}
-	if errCnt > 0 {
-		return
-	}
 	// update migration status
 	c.migMutex.Lock()
 	defer c.migMutex.Unlock()
@@ -485,18 +1107,44 @@ func (c *cacheObjects) migrateCacheFromV1toV2(ctx context.Context) {
 
 // Returns cacheObjects for use by Server.
 func newServerCacheObjects(ctx context.Context, config CacheConfig) (CacheObjectLayer, error) {
+	// Drives in config.json may use ellipses, expand them once so
+	// that cache drives and affinity rules refer to the same list.
+	drives, err := parseCacheDrives(config.Drives)
+	if err != nil {
+		return nil, err
+	}
+	config.Drives = drives
+
 	// list of disk caches for cache "drives" specified in config.json or MINIO_CACHE_DRIVES env var.
 	cache, migrateSw, err := newCache(config)
 	if err != nil {
 		return nil, err
 	}
+	affinity, shared, err := parseCacheAffinity(config.Affinity, config.Drives)
+	if err != nil {
+		return nil, err
+	}
+	parity, err := parseCacheParity(config.Parity, config.Drives, config.Affinity)
+	if err != nil {
+		return nil, err
+	}
+	routes, err := parseCacheRouting(config.Routing, config.Drives)
+	if err != nil {
+		return nil, err
+	}
 
 	c := &cacheObjects{
-		cache:     cache,
-		exclude:   config.Exclude,
-		nsMutex:   newNSLock(false),
-		migrating: migrateSw,
-		migMutex:  sync.Mutex{},
+		drives:       config.Drives,
+		cache:        cache,
+		exclude:      config.Exclude,
+		affinity:     affinity,
+		shared:       shared,
+		storageClass: config.StorageClass,
+		parity:       parity,
+		routes:       routes,
+		nsMutex:      newNSLock(false),
+		migrating:    migrateSw,
+		migMutex:     sync.Mutex{},
 		GetObjectInfoFn: func(ctx context.Context, bucket, object string, opts ObjectOptions) (ObjectInfo, error) {
 			return newObjectLayerFn().GetObjectInfo(ctx, bucket, object, opts)
 		},
@@ -515,7 +1163,158 @@ func newServerCacheObjects(ctx context.Context, config CacheConfig) (CacheObject
 		},
 	}
 	if migrateSw {
-		go c.migrateCacheFromV1toV2(ctx)
+		go c.migrateCacheFormats(ctx)
+	}
+	return c, nil
+}
+
+// updateConfig - applies the cache drives, exclude patterns, affinity,
+// storage class policies, bucket quotas, parity and routing rules of config at runtime, expiry
+// and max use only apply to added drives. Objects are rehashed over the new list of
+// drives, objects cached on the remaining drives are still found by the
+// linear lookup of getCacheToLoc. Removed drives are drained, they are
+// no longer used by new requests while requests in progress complete,
+// and their cached entries are left on the drives. Entries cached with
+// another parity are no longer found and are cached again.
+func (c *cacheObjects) updateConfig(ctx context.Context, config CacheConfig) error {
+	drives, err := parseCacheDrives(config.Drives)
+	if err != nil {
+		return err
+	}
+	exclude, err := parseCacheExcludes(config.Exclude)
+	if err != nil {
+		return err
+	}
+	affinity, shared, err := parseCacheAffinity(config.Affinity, drives)
+	if err != nil {
+		return err
+	}
+	storageClass, err := parseCacheStorageClass(config.StorageClass)
+	if err != nil {
+		return err
+	}
+	quota, err := parseCacheQuota(config.Quota)
+	if err != nil {
+		return err
+	}
+	parity, err := parseCacheParity(config.Parity, drives, config.Affinity)
+	if err != nil {
+		return err
+	}
+	routes, err := parseCacheRouting(config.Routing, drives)
+	if err != nil {
+		return err
+	}
+
+	c.updateMu.Lock()
+	defer c.updateMu.Unlock()
+
+	if c.skipCache() {
+		return errCacheMigrating
+	}
+
+	c.mu.RLock()
+	current := make(map[string]*diskCache, len(c.drives))
+	for i, drive := range c.drives {
+		current[drive] = c.cache[i]
+	}
+	c.mu.RUnlock()
+
+	caches := make([]*diskCache, len(drives))
+	added := make(map[string]bool)
+	closeAdded := func() {
+		for i, drive := range drives {
+			if added[drive] {
+				caches[i].close()
+			}
+		}
+	}
+	for i, drive := range drives {
+		if dcache, ok := current[drive]; ok {
+			caches[i] = dcache
+			delete(current, drive)
+			continue
+		}
+		if err = os.MkdirAll(drive, 0777); err == nil {
+			if err = checkAtimeSupport(drive); err != nil {
+				err = errors.New("Atime support required for disk caching")
+			}
+		}
+		if err == nil {
+			caches[i], err = newdiskCache(drive, config.Expiry, config.MaxUse)
+		}
+		if err != nil {
+			closeAdded()
+			return err
+		}
+		added[drive] = true
+	}
+	if len(added) > 0 || len(current) > 0 {
+		if err = reformatCache(ctx, drives, added); err != nil {
+			closeAdded()
+			return err
+		}
+	}
+
+	c.mu.Lock()
+	c.drives = drives
+	c.cache = caches
+	c.exclude = exclude
+	c.affinity = affinity
+	c.shared = shared
+	c.storageClass = storageClass
+	c.parity = parity
+	c.routes = routes
+	c.mu.Unlock()
+
+	for i, drive := range drives {
+		if caches[i] != nil {
+			caches[i].setQuota(quota)
+		}
+		if added[drive] {
+			go caches[i].purge()
+		}
+	}
+	for _, dcache := range current {
+		if dcache != nil {
+			dcache.close()
+		}
+	}
+	return nil
+}
+
+// Returns the cache of this server to be updated at runtime.
+func getUpdatableCache() (*cacheObjects, error) {
+	if globalIsDiskCacheEnabled {
+		return nil, errCacheConfigFromEnv
+	}
+	c, ok := globalCacheObjectAPI.(*cacheObjects)
+	if !ok || c == nil {
+		return nil, errCacheNotInitialized
 	}
 	return c, nil
 }
+
+// reloadCacheConfig - applies the cache configuration persisted in the
+// backend to the cache of this server.
+func reloadCacheConfig(ctx context.Context, objAPI ObjectLayer) error {
+	c, err := getUpdatableCache()
+	if err != nil {
+		return err
+	}
+	config, err := readServerConfig(ctx, objAPI)
+	if err != nil {
+		return err
+	}
+	if err = c.updateConfig(ctx, config.Cache); err != nil {
+		return err
+	}
+	setGlobalCacheConfig(config.Cache)
+	return nil
+}
+
+func setGlobalCacheConfig(config CacheConfig) {
+	globalServerConfigMu.Lock()
+	globalServerConfig.SetCacheConfig(config.Drives, config.Exclude, config.Affinity, config.StorageClass, config.Quota, config.Parity, config.Routing, config.Expiry, config.MaxUse)
+	globalServerConfigMu.Unlock()
+}
@@ -19,11 +19,21 @@ package cmd
 import (
 	"bytes"
 	"context"
+	"fmt"
 	"io"
+	"io/ioutil"
 	"net/http"
+	"os"
+	"reflect"
+	"runtime"
+	"sync"
+	"sync/atomic"
 	"testing"
+	"time"
 
+	"github.com/minio/minio/pkg/disk"
 	"github.com/minio/minio/pkg/hash"
+	"github.com/minio/minio/pkg/madmin"
 )
 
 // Initialize cache objects.
@@ -102,6 +112,217 @@ func TestGetCachedLoc(t *testing.T) {
 	}
 }
 
+// test whether buckets with cache affinity are only cached
+// on their dedicated drives and other buckets never use them.
+func TestGetCacheLocAffinity(t *testing.T) {
+	fsDirs, err := getRandomDisks(4)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer removeRoots(fsDirs)
+	d, err := initDiskCaches(fsDirs, 100, t)
+	if err != nil {
+		t.Fatal(err)
+	}
+	affinity, shared, err := parseCacheAffinity(map[string][]string{
+		"fastbucket": {fsDirs[0], fsDirs[1]},
+	}, fsDirs)
+	if err != nil {
+		t.Fatal(err)
+	}
+	c := cacheObjects{cache: d, affinity: affinity, shared: shared}
+	ctx := context.Background()
+	for i := 0; i < 20; i++ {
+		object := fmt.Sprintf("object%d", i)
+		dcache, err := c.getCacheLoc(ctx, "fastbucket", object)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if dcache != d[0] && dcache != d[1] {
+			t.Fatalf("expected %s to be cached on a dedicated drive, got %s", object, dcache.dir)
+		}
+		dcache, err = c.getCacheLoc(ctx, "otherbucket", object)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if dcache == d[0] || dcache == d[1] {
+			t.Fatalf("expected %s not to be cached on a dedicated drive, got %s", object, dcache.dir)
+		}
+	}
+
+	// dedicated drives going offline bypasses the cache for the bucket.
+	d[0].online = false
+	d[1].online = false
+	if _, err = c.getCacheLoc(ctx, "fastbucket", "object"); err != errDiskNotFound {
+		t.Fatalf("expected %v, got %v", errDiskNotFound, err)
+	}
+}
+
+// test whether objects matching a routing rule are cached on the
+// drives of the rule and are found there again.
+func TestCacheRouting(t *testing.T) {
+	fsDirs, err := getRandomDisks(4)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer removeRoots(fsDirs)
+	d, err := initDiskCaches(fsDirs, 100, t)
+	if err != nil {
+		t.Fatal(err)
+	}
+	affinity, shared, err := parseCacheAffinity(map[string][]string{
+		"videos": {fsDirs[0], fsDirs[1]},
+	}, fsDirs)
+	if err != nil {
+		t.Fatal(err)
+	}
+	routes, err := parseCacheRouting([]CacheRoutingRule{
+		{ContentType: []string{"video/*"}, MinSize: 10, Drives: []string{fsDirs[3]}},
+		{Metadata: map[string]string{"x-amz-meta-kind": "manifest"}, Drives: []string{fsDirs[2]}},
+	}, fsDirs)
+	if err != nil {
+		t.Fatal(err)
+	}
+	c := cacheObjects{cache: d, affinity: affinity, shared: shared, routes: routes}
+
+	testCases := []struct {
+		objInfo  ObjectInfo
+		expected *diskCache
+	}{
+		{ObjectInfo{ContentType: "video/mp4", Size: 100}, d[3]},
+		{ObjectInfo{ContentType: "video/mp4", Size: 1}, nil},
+		{ObjectInfo{ContentType: "text/plain", UserDefined: map[string]string{"X-Amz-Meta-Kind": "manifest"}}, d[2]},
+		{ObjectInfo{ContentType: "text/plain", UserDefined: map[string]string{"X-Amz-Meta-Kind": "chunk"}}, nil},
+	}
+	for i, testCase := range testCases {
+		if dcache := c.routeCacheDrive("videos", "object", testCase.objInfo); dcache != testCase.expected {
+			t.Errorf("Test %d: unexpected cache drive %v", i+1, dcache)
+		}
+	}
+
+	// Routed objects are found outside of the drives of their bucket.
+	ctx := context.Background()
+	data := []byte("video")
+	if err = d[3].Put(ctx, "videos", "object", bytes.NewReader(data), int64(len(data)), ObjectOptions{}); err != nil {
+		t.Fatal(err)
+	}
+	if dcache, err := c.getCacheToLoc(ctx, "videos", "object"); err != nil || dcache != d[3] {
+		t.Fatalf("expected the object to be found on the routed drive, got %v, %v", dcache, err)
+	}
+
+	// Offline routed drives fall back to the drive hinted by the hash.
+	d[3].online = false
+	if dcache := c.routeCacheDrive("videos", "object", testCases[0].objInfo); dcache != nil {
+		t.Fatalf("expected no routed drive, got %s", dcache.dir)
+	}
+}
+
+// test whether cache drives and affinity rules given with
+// ellipses refer to the same drives.
+func TestNewServerCacheObjectsEllipses(t *testing.T) {
+	if runtime.GOOS == globalWindowsOSName {
+		t.Skip()
+	}
+	dir, err := ioutil.TempDir(globalTestTmpDir, "minio-")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	c, err := newServerCacheObjects(context.Background(), CacheConfig{
+		Drives:   []string{pathJoin(dir, "cache{1...3}")},
+		Affinity: map[string][]string{"fastbucket": {pathJoin(dir, "cache3")}},
+		MaxUse:   80,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	cache := c.(*cacheObjects)
+	defer func() {
+		for _, dcache := range cache.cache {
+			dcache.close()
+		}
+	}()
+	if len(cache.cache) != 3 {
+		t.Fatalf("expected 3 cache drives, got %d", len(cache.cache))
+	}
+	if !reflect.DeepEqual(cache.affinity["fastbucket"], []int{2}) || !reflect.DeepEqual(cache.shared, []int{0, 1}) {
+		t.Fatalf("unexpected affinity %v, shared drives %v", cache.affinity, cache.shared)
+	}
+	for i, dcache := range cache.cache {
+		if dcache == nil || dcache.dir != pathJoin(dir, fmt.Sprintf("cache%d", i+1)) {
+			t.Fatalf("unexpected cache drive %d", i)
+		}
+	}
+}
+
+// Tests adding and removing cache drives and exclude patterns at runtime.
+func TestCacheUpdateConfig(t *testing.T) {
+	if runtime.GOOS == globalWindowsOSName {
+		t.Skip()
+	}
+	dir, err := ioutil.TempDir(globalTestTmpDir, "minio-")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	ctx := context.Background()
+	config := CacheConfig{
+		Drives: []string{pathJoin(dir, "cache1"), pathJoin(dir, "cache2")},
+		MaxUse: 80,
+	}
+	c, err := newServerCacheObjects(ctx, config)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cache := c.(*cacheObjects)
+	// Stop the purge of the cache drives before they are removed.
+	defer func() {
+		for _, dcache := range cache.cache {
+			dcache.close()
+		}
+	}()
+	kept := cache.cache[1]
+
+	config = updateCacheConfig(config, madmin.CacheConfigUpdate{
+		AddDrives:    []string{pathJoin(dir, "cache3"), pathJoin(dir, "cache2")},
+		RemoveDrives: []string{pathJoin(dir, "cache1")},
+		AddExclude:   []string{"*.tmp"},
+	})
+	if !reflect.DeepEqual(config.Drives, []string{pathJoin(dir, "cache2"), pathJoin(dir, "cache3")}) {
+		t.Fatalf("unexpected cache drives %v", config.Drives)
+	}
+	if err = cache.updateConfig(ctx, config); err != nil {
+		t.Fatal(err)
+	}
+	if len(cache.cache) != 2 || cache.cache[0] != kept || cache.cache[1].dir != pathJoin(dir, "cache3") {
+		t.Fatalf("unexpected cache drives after update")
+	}
+	if !cache.isCacheExclude("bucket", "object.tmp") {
+		t.Fatal("expected added exclude pattern to be applied")
+	}
+	if _, _, err = loadAndValidateCacheFormat(ctx, config.Drives); err != nil {
+		t.Fatalf("expected consistent cache format after update, got %v", err)
+	}
+
+	config = updateCacheConfig(config, madmin.CacheConfigUpdate{RemoveExclude: []string{"*.tmp"}})
+	if err = cache.updateConfig(ctx, config); err != nil {
+		t.Fatal(err)
+	}
+	if cache.isCacheExclude("bucket", "object.tmp") {
+		t.Fatal("expected removed exclude pattern to no longer apply")
+	}
+
+	config.Drives = append(config.Drives, "relative/path")
+	if err = cache.updateConfig(ctx, config); err == nil {
+		t.Fatal("expected invalid cache drive to be rejected")
+	}
+	if len(cache.cache) != 2 {
+		t.Fatalf("expected cache drives to be unchanged on failure")
+	}
+}
+
 // test whether a drive being offline causes
 // getCachedLoc to fetch next online drive
 func TestGetCacheMaxUse(t *testing.T) {
@@ -254,6 +475,619 @@ func TestDiskCache(t *testing.T) {
 	}
 }
 
+// Test that removing a cached object sends a purge event to listeners.
+func TestDiskCachePurgeEvent(t *testing.T) {
+	fsDirs, err := getRandomDisks(1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	d, err := initDiskCaches(fsDirs, 100, t)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cache := d[0]
+	ctx := context.Background()
+
+	content := []byte("hello")
+	hashReader, err := hash.NewReader(bytes.NewReader(content), int64(len(content)), "", "", int64(len(content)), globalCLIContext.StrictS3Compat)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err = cache.Put(ctx, "testbucket", "testobject", hashReader, hashReader.Size(), ObjectOptions{}); err != nil {
+		t.Fatal(err)
+	}
+
+	doneCh := make(chan struct{})
+	defer close(doneCh)
+	eventCh := make(chan interface{}, 1)
+	globalCacheEvents.Subscribe(eventCh, doneCh, func(entry interface{}) bool {
+		return true
+	})
+
+	if err = cache.Delete(ctx, "testbucket", "testobject"); err != nil {
+		t.Fatal(err)
+	}
+
+	select {
+	case entry := <-eventCh:
+		event := entry.(madmin.CacheEvent)
+		if event.Type != madmin.CacheEventPurge || event.Reason != cacheEventReasonInvalidated ||
+			event.Bucket != "testbucket" || event.Object != "testobject" ||
+			event.Drive != cache.dir || event.Size != int64(len(content)) {
+			t.Fatalf("Unexpected cache event %v", event)
+		}
+	default:
+		t.Fatal("Expected a cache event")
+	}
+}
+
+// Tests that the entries of buckets above their quota are evicted, and
+// that the entries of other buckets are kept.
+func TestDiskCachePurgeOverQuota(t *testing.T) {
+	fsDirs, err := getRandomDisks(1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	d, err := initDiskCaches(fsDirs, 100, t)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cache := d[0]
+	ctx := context.Background()
+
+	content := []byte("hello")
+	for _, bucket := range []string{"noisy", "other"} {
+		hashReader, err := hash.NewReader(bytes.NewReader(content), int64(len(content)), "", "", int64(len(content)), globalCLIContext.StrictS3Compat)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err = cache.Put(ctx, bucket, "testobject", hashReader, hashReader.Size(), ObjectOptions{}); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// No cache space is left to buckets with a quota.
+	cache.maxDiskUsagePct = 0
+	cache.setQuota(map[string]int{"noisy": 10})
+
+	doneCh := make(chan struct{})
+	defer close(doneCh)
+	eventCh := make(chan interface{}, 1)
+	globalCacheEvents.Subscribe(eventCh, doneCh, func(entry interface{}) bool {
+		return true
+	})
+
+	cache.purgeOverQuota(ctx)
+
+	if cache.Exists(ctx, "noisy", "testobject") {
+		t.Fatal("Expected the entry of the bucket above its quota to be evicted")
+	}
+	if !cache.Exists(ctx, "other", "testobject") {
+		t.Fatal("Expected the entry of the bucket without quota to be kept")
+	}
+	select {
+	case entry := <-eventCh:
+		event := entry.(madmin.CacheEvent)
+		if event.Type != madmin.CacheEventEvict || event.Reason != cacheEventReasonQuota || event.Bucket != "noisy" {
+			t.Fatalf("Unexpected cache event %v", event)
+		}
+	default:
+		t.Fatal("Expected a cache event")
+	}
+}
+
+// Tests that cached entries still fresh as per their cache control are
+// revalidated against the backend for strict preconditions, and that
+// the cache is not filled for requests failing their preconditions.
+func TestCacheConditionalGet(t *testing.T) {
+	fsDirs, err := getRandomDisks(1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	d, err := initDiskCaches(fsDirs, 100, t)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx := context.Background()
+	bucket, object := "testbucket", "testobject"
+
+	backendInfo := ObjectInfo{Bucket: bucket, Name: object, ETag: "new", Size: 3, ModTime: UTCNow()}
+	var backendErr error
+	c := cacheObjects{
+		cache:   d,
+		nsMutex: newNSLock(false),
+		GetObjectInfoFn: func(ctx context.Context, bucket, object string, opts ObjectOptions) (ObjectInfo, error) {
+			return backendInfo, backendErr
+		},
+		GetObjectNInfoFn: func(ctx context.Context, bucket, object string, rs *HTTPRangeSpec, h http.Header, lockType LockType, opts ObjectOptions) (*GetObjectReader, error) {
+			return NewGetObjectReaderFromReader(bytes.NewReader([]byte("new")), backendInfo, opts.CheckCopyPrecondFn)
+		},
+	}
+	cacheObject := func() {
+		content := []byte("old")
+		hashReader, err := hash.NewReader(bytes.NewReader(content), int64(len(content)), "", "", int64(len(content)), globalCLIContext.StrictS3Compat)
+		if err != nil {
+			t.Fatal(err)
+		}
+		meta := map[string]string{"etag": "old", "cache-control": "max-age=3600"}
+		if err = d[0].Put(ctx, bucket, object, hashReader, hashReader.Size(), ObjectOptions{UserDefined: meta}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	getETag := func(h http.Header) (string, error) {
+		gr, err := c.GetObjectNInfo(ctx, bucket, object, nil, h, readLock, ObjectOptions{})
+		if err != nil {
+			return "", err
+		}
+		defer gr.Close()
+		return gr.ObjInfo.ETag, nil
+	}
+
+	cacheObject()
+	if etag, err := getETag(http.Header{"If-None-Match": []string{"old"}}); err != nil || etag != "old" {
+		t.Fatalf("Expected the fresh cached entry to be served, got %s, %v", etag, err)
+	}
+
+	// The stale cached entry is removed, the cache is not filled
+	// since the request fails with 412.
+	if etag, err := getETag(http.Header{"If-Match": []string{"old"}}); err != nil || etag != "new" {
+		t.Fatalf("Expected the backend object to be served, got %s, %v", etag, err)
+	}
+	if d[0].Exists(ctx, bucket, object) {
+		t.Fatal("Expected object not to be cached")
+	}
+
+	cacheObject()
+	backendErr = BackendDown{}
+	if etag, err := getETag(nil); err != nil || etag != "old" {
+		t.Fatalf("Expected the cached entry to be served, got %s, %v", etag, err)
+	}
+	if _, err = getETag(http.Header{"If-Match": []string{"old"}}); err != backendErr {
+		t.Fatalf("Expected %v, got %v", backendErr, err)
+	}
+}
+
+// Tests the no-cache and only-if-cached cache controls of requests.
+func TestCacheRequestCacheControl(t *testing.T) {
+	fsDirs, err := getRandomDisks(1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	d, err := initDiskCaches(fsDirs, 100, t)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx := context.Background()
+	bucket, object := "testbucket", "testobject"
+
+	backendInfo := ObjectInfo{Bucket: bucket, Name: object, ETag: "new", Size: 3, ModTime: UTCNow()}
+	var backendErr error
+	c := cacheObjects{
+		cache:   d,
+		nsMutex: newNSLock(false),
+		GetObjectInfoFn: func(ctx context.Context, bucket, object string, opts ObjectOptions) (ObjectInfo, error) {
+			return backendInfo, backendErr
+		},
+		GetObjectNInfoFn: func(ctx context.Context, bucket, object string, rs *HTTPRangeSpec, h http.Header, lockType LockType, opts ObjectOptions) (*GetObjectReader, error) {
+			if backendErr != nil {
+				return nil, backendErr
+			}
+			return NewGetObjectReaderFromReader(bytes.NewReader([]byte("new")), backendInfo, opts.CheckCopyPrecondFn)
+		},
+	}
+	cacheObject := func() {
+		content := []byte("old")
+		hashReader, err := hash.NewReader(bytes.NewReader(content), int64(len(content)), "", "", int64(len(content)), globalCLIContext.StrictS3Compat)
+		if err != nil {
+			t.Fatal(err)
+		}
+		meta := map[string]string{"etag": "old", "cache-control": "max-age=3600"}
+		if err = d[0].Put(ctx, bucket, object, hashReader, hashReader.Size(), ObjectOptions{UserDefined: meta}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	getETag := func(cacheControl string) (string, error) {
+		gr, err := c.GetObjectNInfo(ctx, bucket, object, nil, nil, readLock, ObjectOptions{CacheControl: cacheControl})
+		if err != nil {
+			return "", err
+		}
+		defer gr.Close()
+		return gr.ObjInfo.ETag, nil
+	}
+	headETag := func(cacheControl string) (string, error) {
+		oi, err := c.GetObjectInfo(ctx, bucket, object, ObjectOptions{CacheControl: cacheControl})
+		return oi.ETag, err
+	}
+
+	notCached := ObjectNotCached{Bucket: bucket, Object: object}
+	if _, err = getETag("only-if-cached"); err != notCached {
+		t.Fatalf("Expected %v, got %v", notCached, err)
+	}
+	if _, err = headETag("only-if-cached"); err != notCached {
+		t.Fatalf("Expected %v, got %v", notCached, err)
+	}
+
+	// Cached objects are served without reading the backend.
+	cacheObject()
+	backendErr = errDiskNotFound
+	if etag, err := getETag("Only-If-Cached"); err != nil || etag != "old" {
+		t.Fatalf("Expected the cached entry to be served, got %s, %v", etag, err)
+	}
+	if etag, err := headETag("only-if-cached"); err != nil || etag != "old" {
+		t.Fatalf("Expected the cached entry to be served, got %s, %v", etag, err)
+	}
+
+	// The cached entry is not served when the backend is down.
+	backendErr = BackendDown{}
+	if _, err = getETag("no-cache"); err != backendErr {
+		t.Fatalf("Expected %v, got %v", backendErr, err)
+	}
+	if _, err = headETag("no-cache"); err != backendErr {
+		t.Fatalf("Expected %v, got %v", backendErr, err)
+	}
+	if etag, err := getETag(""); err != nil || etag != "old" {
+		t.Fatalf("Expected the cached entry to be served, got %s, %v", etag, err)
+	}
+
+	// The fresh cached entry is revalidated against the backend.
+	backendErr = nil
+	if etag, err := headETag("no-cache"); err != nil || etag != "new" {
+		t.Fatalf("Expected the backend object to be served, got %s, %v", etag, err)
+	}
+	cacheObject()
+	if etag, err := getETag("no-cache"); err != nil || etag != "new" {
+		t.Fatalf("Expected the backend object to be served, got %s, %v", etag, err)
+	}
+}
+
+func TestCacheGetObjectNRanges(t *testing.T) {
+	fsDirs, err := getRandomDisks(1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	d, err := initDiskCaches(fsDirs, 100, t)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx := context.Background()
+	bucket, object := "testbucket", "testobject"
+
+	backendInfo := ObjectInfo{Bucket: bucket, Name: object, ETag: "new", Size: 10, ModTime: UTCNow()}
+	c := cacheObjects{
+		cache:   d,
+		nsMutex: newNSLock(false),
+		GetObjectInfoFn: func(ctx context.Context, bucket, object string, opts ObjectOptions) (ObjectInfo, error) {
+			return backendInfo, nil
+		},
+		GetObjectNInfoFn: func(ctx context.Context, bucket, object string, rs *HTTPRangeSpec, h http.Header, lockType LockType, opts ObjectOptions) (*GetObjectReader, error) {
+			return NewGetObjectReaderFromReader(bytes.NewReader([]byte("9876543210")), backendInfo, opts.CheckCopyPrecondFn)
+		},
+	}
+
+	content := []byte("0123456789")
+	hashReader, err := hash.NewReader(bytes.NewReader(content), int64(len(content)), "", "", int64(len(content)), globalCLIContext.StrictS3Compat)
+	if err != nil {
+		t.Fatal(err)
+	}
+	meta := map[string]string{"etag": "old", "cache-control": "max-age=3600"}
+	if err = d[0].Put(ctx, bucket, object, hashReader, hashReader.Size(), ObjectOptions{UserDefined: meta}); err != nil {
+		t.Fatal(err)
+	}
+
+	ranges := []*HTTPRangeSpec{{false, 0, 1}, {false, 5, 6}, {true, -2, -1}}
+	readers, err := c.GetObjectNRanges(ctx, bucket, object, "old", ranges, nil, ObjectOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i, expected := range []string{"01", "56", "89"} {
+		data, err := ioutil.ReadAll(readers[i])
+		readers[i].Close()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if string(data) != expected {
+			t.Errorf("Range %d: expected %s, got %s", i, expected, string(data))
+		}
+	}
+
+	// Neither the cached entry nor the backend object match the
+	// requested version.
+	if _, err = c.GetObjectNRanges(ctx, bucket, object, "other", ranges, nil, ObjectOptions{}); err != (PreConditionFailed{}) {
+		t.Fatalf("Expected %v, got %v", PreConditionFailed{}, err)
+	}
+}
+
+// Tests that a cached entry failing its bitrot check while being read
+// is completed from the backend and counted as corrupted.
+func TestCacheHealOnRead(t *testing.T) {
+	fsDirs, err := getRandomDisks(1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	d, err := initDiskCaches(fsDirs, 100, t)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx := context.Background()
+	bucket, object := "testbucket", "testobject"
+
+	content := bytes.Repeat([]byte("0123456789"), int(2*cacheBlkSize/10))
+	backendInfo := ObjectInfo{Bucket: bucket, Name: object, ETag: "etag", Size: int64(len(content)), ModTime: UTCNow()}
+	c := &cacheObjects{
+		cache:   d,
+		nsMutex: newNSLock(false),
+		GetObjectInfoFn: func(ctx context.Context, bucket, object string, opts ObjectOptions) (ObjectInfo, error) {
+			return backendInfo, nil
+		},
+		GetObjectNInfoFn: func(ctx context.Context, bucket, object string, rs *HTTPRangeSpec, h http.Header, lockType LockType, opts ObjectOptions) (*GetObjectReader, error) {
+			off, length, err := rs.GetOffsetLength(backendInfo.Size)
+			if err != nil {
+				return nil, err
+			}
+			return NewGetObjectReaderFromReader(bytes.NewReader(content[off:off+length]), backendInfo, opts.CheckCopyPrecondFn)
+		},
+	}
+
+	hashReader, err := hash.NewReader(bytes.NewReader(content), int64(len(content)), "", "", int64(len(content)), globalCLIContext.StrictS3Compat)
+	if err != nil {
+		t.Fatal(err)
+	}
+	meta := map[string]string{"etag": "etag", "cache-control": "max-age=3600"}
+	if err = d[0].Put(ctx, bucket, object, hashReader, hashReader.Size(), ObjectOptions{UserDefined: meta}); err != nil {
+		t.Fatal(err)
+	}
+
+	// Corrupt the second block of the cached entry.
+	dataFile := pathJoin(getCacheSHADir(d[0].dir, bucket, object), cacheDataFile)
+	f, err := os.OpenFile(dataFile, os.O_RDWR, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	hashSize := int64(HighwayHash256S.New().Size())
+	if _, err = f.WriteAt([]byte("x"), 2*hashSize+cacheBlkSize+10); err != nil {
+		t.Fatal(err)
+	}
+	f.Close()
+
+	gr, err := c.GetObjectNInfo(ctx, bucket, object, nil, nil, readLock, ObjectOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	data, err := ioutil.ReadAll(gr)
+	gr.Close()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(data, content) {
+		t.Fatal("expected the object content to be completed from the backend")
+	}
+	if stats := c.Stats(); stats.Corrupted != 1 || stats.Healed != 1 {
+		t.Fatalf("unexpected cache stats %+v", stats)
+	}
+}
+
+// Tests that reads and fills are counted by the statistics of the
+// cache drive.
+func TestCacheDriveStats(t *testing.T) {
+	fsDirs, err := getRandomDisks(1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	d, err := initDiskCaches(fsDirs, 100, t)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx := context.Background()
+	bucket, object := "testbucket", "testobject"
+
+	c := &cacheObjects{
+		cache:   d,
+		nsMutex: newNSLock(false),
+		GetObjectInfoFn: func(ctx context.Context, bucket, object string, opts ObjectOptions) (ObjectInfo, error) {
+			return ObjectInfo{}, ObjectNotFound{Bucket: bucket, Object: object}
+		},
+	}
+
+	content := []byte("content")
+	hashReader, err := hash.NewReader(bytes.NewReader(content), int64(len(content)), "", "", int64(len(content)), globalCLIContext.StrictS3Compat)
+	if err != nil {
+		t.Fatal(err)
+	}
+	meta := map[string]string{"etag": "etag", "cache-control": "max-age=3600"}
+	if err = d[0].Put(ctx, bucket, object, hashReader, hashReader.Size(), ObjectOptions{UserDefined: meta}); err != nil {
+		t.Fatal(err)
+	}
+
+	gr, err := c.GetObjectNInfo(ctx, bucket, object, nil, nil, readLock, ObjectOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err = ioutil.ReadAll(gr); err != nil {
+		t.Fatal(err)
+	}
+	gr.Close()
+	if _, err = c.GetObjectNInfo(ctx, bucket, "missing", nil, nil, readLock, ObjectOptions{}); !isErrObjectNotFound(err) {
+		t.Fatalf("Expected object not found, got %v", err)
+	}
+
+	stats := c.DriveStats(ctx)
+	if len(stats) != 1 {
+		t.Fatalf("Expected the statistics of 1 drive, got %d", len(stats))
+	}
+	st := stats[0]
+	if st.Drive != d[0].dir || st.Hits != 1 || st.Misses != 1 || st.Fills != 1 || st.Evictions != 0 || st.IOErrors != 0 {
+		t.Fatalf("Unexpected cache drive stats %+v", st)
+	}
+	if st.UsagePercent <= 0 || st.UsagePercent > 100 {
+		t.Fatalf("Unexpected cache drive usage %f", st.UsagePercent)
+	}
+}
+
+// Tests that concurrent cache misses on an object are served by a
+// single backend read which fills the cache.
+func TestCacheFillCoalescing(t *testing.T) {
+	fsDirs, err := getRandomDisks(1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	d, err := initDiskCaches(fsDirs, 100, t)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx := context.Background()
+	bucket, object := "testbucket", "testobject"
+
+	content := []byte("hello world")
+	backendInfo := ObjectInfo{Bucket: bucket, Name: object, ETag: "etag", Size: int64(len(content)), ModTime: UTCNow()}
+	var backendReads int32
+	c := &cacheObjects{
+		cache:   d,
+		nsMutex: newNSLock(false),
+		GetObjectInfoFn: func(ctx context.Context, bucket, object string, opts ObjectOptions) (ObjectInfo, error) {
+			return backendInfo, nil
+		},
+		GetObjectNInfoFn: func(ctx context.Context, bucket, object string, rs *HTTPRangeSpec, h http.Header, lockType LockType, opts ObjectOptions) (*GetObjectReader, error) {
+			atomic.AddInt32(&backendReads, 1)
+			return NewGetObjectReaderFromReader(bytes.NewReader(content), backendInfo, opts.CheckCopyPrecondFn)
+		},
+	}
+	read := func(gr *GetObjectReader) error {
+		defer gr.Close()
+		data, err := ioutil.ReadAll(gr)
+		if err != nil {
+			return err
+		}
+		if !bytes.Equal(data, content) {
+			return fmt.Errorf("unexpected content %q", data)
+		}
+		return nil
+	}
+
+	// The first miss holds the cache fill until its reader is read.
+	gr, err := c.GetObjectNInfo(ctx, bucket, object, nil, nil, readLock, ObjectOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var wg sync.WaitGroup
+	errs := make([]error, 4)
+	for i := range errs {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			wgr, err := c.GetObjectNInfo(ctx, bucket, object, nil, nil, readLock, ObjectOptions{})
+			if err == nil {
+				err = read(wgr)
+			}
+			errs[i] = err
+		}(i)
+	}
+	time.Sleep(100 * time.Millisecond)
+
+	if err = read(gr); err != nil {
+		t.Fatal(err)
+	}
+	wg.Wait()
+	for _, err := range errs {
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+	if n := atomic.LoadInt32(&backendReads); n != 1 {
+		t.Fatalf("expected a single backend read, got %d", n)
+	}
+	if !d[0].Exists(ctx, bucket, object) {
+		t.Fatal("expected object to be cached")
+	}
+}
+
+// Tests that objects are admitted to the cache as per the policy of
+// their storage class and their cache control.
+func TestCacheStorageClassAdmission(t *testing.T) {
+	fsDirs, err := getRandomDisks(1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer removeRoots(fsDirs)
+	d, err := initDiskCaches(fsDirs, 100, t)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx := context.Background()
+	bucket := "testbucket"
+
+	backendInfo := make(map[string]ObjectInfo)
+	c := cacheObjects{
+		cache:   d,
+		nsMutex: newNSLock(false),
+		storageClass: map[string]string{
+			"REDUCED_REDUNDANCY": cacheStorageClassExclude,
+			"GLACIER":            cacheStorageClassPriority,
+		},
+		GetObjectInfoFn: func(ctx context.Context, bucket, object string, opts ObjectOptions) (ObjectInfo, error) {
+			return backendInfo[object], nil
+		},
+		GetObjectNInfoFn: func(ctx context.Context, bucket, object string, rs *HTTPRangeSpec, h http.Header, lockType LockType, opts ObjectOptions) (*GetObjectReader, error) {
+			return NewGetObjectReaderFromReader(bytes.NewReader([]byte("data")), backendInfo[object], opts.CheckCopyPrecondFn)
+		},
+	}
+	getObject := func(object, storageClass string, meta map[string]string) {
+		backendInfo[object] = ObjectInfo{Bucket: bucket, Name: object, ETag: object, Size: 4, ModTime: UTCNow(),
+			StorageClass: storageClass, UserDefined: meta}
+		gr, err := c.GetObjectNInfo(ctx, bucket, object, nil, nil, readLock, ObjectOptions{})
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer gr.Close()
+		if _, err = ioutil.ReadAll(gr); err != nil {
+			t.Fatal(err)
+		}
+	}
+	// Objects are added to the cache in the background.
+	isCached := func(object string) bool {
+		for i := 0; i < 100; i++ {
+			if d[0].Exists(ctx, bucket, object) {
+				return true
+			}
+			time.Sleep(10 * time.Millisecond)
+		}
+		return false
+	}
+
+	getObject("rrs", "REDUCED_REDUNDANCY", nil)
+	getObject("nostore", "STANDARD", map[string]string{"Cache-Control": "max-age=600, no-store"})
+	getObject("private", "", map[string]string{"cache-control": "Private"})
+	for _, object := range []string{"rrs", "nostore", "private"} {
+		if d[0].Exists(ctx, bucket, object) {
+			t.Fatalf("Expected %s not to be cached", object)
+		}
+	}
+	getObject("standard", "STANDARD", map[string]string{"cache-control": "max-age=600"})
+	if !isCached("standard") {
+		t.Fatal("Expected standard object to be cached")
+	}
+
+	// Once the cache usage is high, only objects of the priority
+	// storage class are added to the cache.
+	di, err := disk.GetInfo(fsDirs[0])
+	if err != nil {
+		t.Fatal(err)
+	}
+	usedPercent := int((di.Total - di.Free) * 100 / di.Total)
+	if usedPercent < 10 || usedPercent > 90 {
+		t.Skipf("Disk usage %d%% does not allow testing high cache usage", usedPercent)
+	}
+	d[0].maxDiskUsagePct = usedPercent + 2
+	getObject("other", "STANDARD", nil)
+	if d[0].Exists(ctx, bucket, "other") {
+		t.Fatal("Expected object not to be cached when the cache usage is high")
+	}
+	getObject("archive", "GLACIER", nil)
+	if !isCached("archive") {
+		t.Fatal("Expected priority object to be cached")
+	}
+}
+
 // Test diskCache with upper bound on max cache use.
 func TestDiskCacheMaxUse(t *testing.T) {
 	fsDirs, err := getRandomDisks(1)
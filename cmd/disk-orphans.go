@@ -0,0 +1,263 @@
+/*
+ * MinIO Cloud Storage, (C) 2020 MinIO, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"context"
+	"path"
+	"sync"
+	"time"
+
+	"github.com/minio/minio/pkg/madmin"
+)
+
+const (
+	// Files modified more recently are not reported by default, they
+	// may belong to requests in progress.
+	defaultOrphanAge = 24 * time.Hour
+
+	// Maximum number of orphaned files reported per disk.
+	maxOrphanEntries = 10000
+)
+
+// orphanInspector - finds the orphaned files of the .minio.sys volume
+// of a disk, optionally removing them.
+type orphanInspector struct {
+	ctx    context.Context
+	objAPI ObjectLayer
+	disk   StorageAPI
+	remove bool
+	// Only files modified before cutoff are orphans.
+	cutoff time.Time
+
+	result madmin.OrphanDiskResult
+}
+
+// inspectLocalOrphans - inspects all the local disks of this server,
+// the result of each disk is sent on resultCh once the disk is done.
+func inspectLocalOrphans(ctx context.Context, objAPI ObjectLayer, remove bool, olderThan time.Duration, resultCh chan<- madmin.OrphanDiskResult) {
+	node := GetLocalPeer(globalEndpoints)
+	cutoff := UTCNow().Add(-olderThan)
+
+	var wg sync.WaitGroup
+	for _, endpoint := range globalEndpoints {
+		if !endpoint.IsLocal {
+			continue
+		}
+		wg.Add(1)
+		go func(endpoint Endpoint) {
+			defer wg.Done()
+
+			result := madmin.OrphanDiskResult{Node: node, Disk: endpoint.String()}
+			disk, err := newPosix(endpoint.Path)
+			if err != nil {
+				result.Error = err.Error()
+			} else {
+				o := &orphanInspector{
+					ctx:    ctx,
+					objAPI: objAPI,
+					disk:   disk,
+					remove: remove,
+					cutoff: cutoff,
+					result: result,
+				}
+				result = o.inspect()
+				disk.Close()
+			}
+
+			select {
+			case resultCh <- result:
+			case <-ctx.Done():
+			}
+		}(endpoint)
+	}
+	wg.Wait()
+}
+
+// inspect - inspects the temporary files, the multipart uploads and the
+// bucket configurations of the disk.
+func (o *orphanInspector) inspect() madmin.OrphanDiskResult {
+	for _, inspect := range []func() (bool, error){o.inspectTmp, o.inspectMultipart, o.inspectBucketConfigs} {
+		more, err := inspect()
+		if err != nil {
+			o.result.Error = err.Error()
+		}
+		if !more || err != nil {
+			break
+		}
+	}
+	return o.result
+}
+
+// inspectTmp - all the temporary files are orphans once old enough.
+func (o *orphanInspector) inspectTmp() (bool, error) {
+	return o.walk(minioMetaTmpBucket, "", func(filePath string) bool {
+		return o.found(madmin.OrphanTmp, minioMetaTmpBucket, filePath)
+	})
+}
+
+// inspectMultipart - the files of the uploads without metadata and the
+// parts not referenced by the metadata of their upload are orphans.
+func (o *orphanInspector) inspectMultipart() (bool, error) {
+	shaDirs, err := o.listDir(minioMetaMultipartBucket, "")
+	if err != nil {
+		return true, err
+	}
+	for _, shaDir := range shaDirs {
+		uploadIDDirs, err := o.listDir(minioMetaMultipartBucket, shaDir)
+		if err != nil {
+			return true, err
+		}
+		for _, uploadIDDir := range uploadIDDirs {
+			more, err := o.inspectUpload(pathJoin(shaDir, uploadIDDir))
+			if !more || err != nil {
+				return more, err
+			}
+		}
+	}
+	return true, nil
+}
+
+func (o *orphanInspector) inspectUpload(uploadIDPath string) (bool, error) {
+	// Parts of FS uploads are found by listing their directory.
+	if _, err := o.disk.StatFile(minioMetaMultipartBucket, pathJoin(uploadIDPath, fsMetaJSONFile)); err == nil {
+		return true, nil
+	}
+
+	kind := madmin.OrphanUpload
+	referenced := map[string]bool{xlMetaJSONFile: true}
+	xlMetaBuf, err := o.disk.ReadAll(minioMetaMultipartBucket, pathJoin(uploadIDPath, xlMetaJSONFile))
+	switch err {
+	case nil:
+		xlMeta, err := xlMetaV1UnmarshalJSON(o.ctx, xlMetaBuf)
+		if err != nil {
+			// Left to the healing of the upload.
+			return true, nil
+		}
+		kind = madmin.OrphanPart
+		for _, part := range xlMeta.Parts {
+			referenced[part.Name] = true
+		}
+	case errFileNotFound:
+	default:
+		return true, err
+	}
+
+	return o.walk(minioMetaMultipartBucket, uploadIDPath, func(filePath string) bool {
+		if referenced[path.Base(filePath)] {
+			return true
+		}
+		return o.found(kind, minioMetaMultipartBucket, filePath)
+	})
+}
+
+// inspectBucketConfigs - the configuration files of buckets which no
+// longer exist are orphans.
+func (o *orphanInspector) inspectBucketConfigs() (bool, error) {
+	entries, err := o.listDir(minioMetaBucket, bucketConfigPrefix)
+	if err != nil {
+		return true, err
+	}
+	for _, entry := range entries {
+		if !hasSuffix(entry, SlashSeparator) {
+			continue
+		}
+		bucket := entry[:len(entry)-1]
+		if isReservedOrInvalidBucket(bucket, false) {
+			continue
+		}
+		_, err = o.objAPI.GetBucketInfo(o.ctx, bucket)
+		if err == nil {
+			continue
+		}
+		if _, ok := err.(BucketNotFound); !ok {
+			return true, err
+		}
+		more, err := o.walk(minioMetaBucket, pathJoin(bucketConfigPrefix, entry), func(filePath string) bool {
+			return o.found(madmin.OrphanConfig, minioMetaBucket, filePath)
+		})
+		if !more || err != nil {
+			return more, err
+		}
+	}
+	return true, nil
+}
+
+// listDir - lists a directory of the disk, missing directories are
+// empty.
+func (o *orphanInspector) listDir(volume, dirPath string) ([]string, error) {
+	entries, err := o.disk.ListDir(volume, dirPath, -1, "")
+	if err == errFileNotFound || err == errVolumeNotFound {
+		return nil, nil
+	}
+	return entries, err
+}
+
+// walk - calls fn with every file under dirPath, stops as soon as fn
+// returns false. Returns false if the walk was stopped.
+func (o *orphanInspector) walk(volume, dirPath string, fn func(filePath string) bool) (bool, error) {
+	entries, err := o.listDir(volume, dirPath)
+	if err != nil {
+		return true, err
+	}
+	for _, entry := range entries {
+		entryPath := pathJoin(dirPath, entry)
+		if hasSuffix(entry, SlashSeparator) {
+			if more, err := o.walk(volume, entryPath, fn); !more || err != nil {
+				return more, err
+			}
+			continue
+		}
+		if !fn(entryPath) {
+			return false, nil
+		}
+	}
+	return true, nil
+}
+
+// found - reports an orphaned file if it is old enough, removing it if
+// requested. Returns false once no more files should be reported.
+func (o *orphanInspector) found(kind, volume, filePath string) bool {
+	if o.ctx.Err() != nil {
+		return false
+	}
+	fi, err := o.disk.StatFile(volume, filePath)
+	if err != nil || fi.ModTime.After(o.cutoff) {
+		return true
+	}
+	if len(o.result.Entries) == maxOrphanEntries {
+		o.result.Truncated = true
+		return false
+	}
+
+	entry := madmin.OrphanEntry{
+		Kind:    kind,
+		Path:    pathJoin(volume, filePath),
+		Size:    fi.Size,
+		ModTime: fi.ModTime,
+	}
+	if o.remove {
+		if err = o.disk.DeleteFile(volume, filePath); err != nil {
+			entry.Error = err.Error()
+		} else {
+			entry.Removed = true
+		}
+	}
+	o.result.Entries = append(o.result.Entries, entry)
+	o.result.Size += fi.Size
+	return true
+}
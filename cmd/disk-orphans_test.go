@@ -0,0 +1,109 @@
+/*
+ * MinIO Cloud Storage, (C) 2020 MinIO, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/minio/minio/pkg/madmin"
+)
+
+// Tests that orphaned temporary files, multipart files and bucket
+// configurations are found and removed, referenced files are kept.
+func TestOrphanInspector(t *testing.T) {
+	obj, fsDir, err := prepareFS()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(fsDir)
+
+	ctx := context.Background()
+	if err = obj.MakeBucketWithLocation(ctx, "bucket", ""); err != nil {
+		t.Fatal(err)
+	}
+
+	disk, err := newPosix(fsDir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer disk.Close()
+	if err = disk.MakeVol(minioMetaMultipartBucket); err != nil && err != errVolumeExists {
+		t.Fatal(err)
+	}
+
+	xlMeta, err := json.Marshal(xlMetaV1{Parts: []ObjectPartInfo{{Number: 1, Name: "part.1"}}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	files := []struct {
+		volume, path string
+		data         []byte
+	}{
+		{minioMetaTmpBucket, "interrupted/part.1", []byte("tmp")},
+		{minioMetaMultipartBucket, "sha/dangling/part.1", []byte("dangling")},
+		{minioMetaMultipartBucket, "sha/xl/" + xlMetaJSONFile, xlMeta},
+		{minioMetaMultipartBucket, "sha/xl/part.1", []byte("referenced")},
+		{minioMetaMultipartBucket, "sha/xl/part.2", []byte("orphan")},
+		{minioMetaMultipartBucket, "sha/fs/" + fsMetaJSONFile, []byte("{}")},
+		{minioMetaMultipartBucket, "sha/fs/00001.etag.3", []byte("abc")},
+		{minioMetaBucket, "buckets/bucket/policy.json", []byte("{}")},
+		{minioMetaBucket, "buckets/removed/policy.json", []byte("{}")},
+	}
+	for _, f := range files {
+		if err = disk.WriteAll(f.volume, f.path, bytes.NewReader(f.data)); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	expected := map[string]string{
+		minioMetaTmpBucket + "/interrupted/part.1":        madmin.OrphanTmp,
+		minioMetaMultipartBucket + "/sha/dangling/part.1": madmin.OrphanUpload,
+		minioMetaMultipartBucket + "/sha/xl/part.2":       madmin.OrphanPart,
+		minioMetaBucket + "/buckets/removed/policy.json":  madmin.OrphanConfig,
+	}
+
+	// Recently modified files are not orphans yet.
+	o := &orphanInspector{ctx: ctx, objAPI: obj, disk: disk, cutoff: UTCNow().Add(-time.Hour)}
+	if result := o.inspect(); len(result.Entries) != 0 || result.Error != "" {
+		t.Fatalf("Expected no orphans, got %v", result)
+	}
+
+	for _, remove := range []bool{false, true} {
+		o = &orphanInspector{ctx: ctx, objAPI: obj, disk: disk, remove: remove, cutoff: UTCNow().Add(time.Minute)}
+		result := o.inspect()
+		if result.Error != "" || len(result.Entries) != len(expected) {
+			t.Fatalf("Expected %d orphans, got %v", len(expected), result)
+		}
+		for _, entry := range result.Entries {
+			if kind, ok := expected[entry.Path]; !ok || kind != entry.Kind || entry.Removed != remove {
+				t.Errorf("Unexpected orphan %v", entry)
+			}
+		}
+	}
+
+	for _, f := range files {
+		_, err = disk.StatFile(f.volume, f.path)
+		if _, orphan := expected[pathJoin(f.volume, f.path)]; orphan != (err == errFileNotFound) {
+			t.Errorf("%s/%s: unexpected stat error %v", f.volume, f.path, err)
+		}
+	}
+}
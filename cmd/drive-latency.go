@@ -0,0 +1,94 @@
+/*
+ * MinIO Cloud Storage, (C) 2019 MinIO, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"io"
+	"sync"
+	"time"
+
+	"github.com/minio/minio/pkg/disk"
+)
+
+// driveLatency - rolling read and write latencies of a local drive.
+type driveLatency struct {
+	read  disk.RollingLatency
+	write disk.RollingLatency
+}
+
+func (d *driveLatency) readSince(start time.Time) {
+	d.read.Add(time.Since(start))
+}
+
+// driveLatencySys - latencies of the local drives by path, shared by
+// all posix instances of a drive so that they survive reconnects.
+type driveLatencySys struct {
+	mu     sync.Mutex
+	drives map[string]*driveLatency
+}
+
+var globalDriveLatency = &driveLatencySys{drives: make(map[string]*driveLatency)}
+
+// get - returns the latencies of the drive at diskPath, tracking them
+// from now on if they are not yet.
+func (sys *driveLatencySys) get(diskPath string) *driveLatency {
+	sys.mu.Lock()
+	defer sys.mu.Unlock()
+
+	d, ok := sys.drives[diskPath]
+	if !ok {
+		d = &driveLatency{}
+		sys.drives[diskPath] = d
+	}
+	return d
+}
+
+// lookup - returns the latencies of the drive at diskPath, if tracked.
+func (sys *driveLatencySys) lookup(diskPath string) (*driveLatency, bool) {
+	sys.mu.Lock()
+	defer sys.mu.Unlock()
+
+	d, ok := sys.drives[diskPath]
+	return d, ok
+}
+
+// latencyReader - records the latency of each read of a drive.
+type latencyReader struct {
+	io.Reader
+	latency *disk.RollingLatency
+}
+
+func (r latencyReader) Read(p []byte) (n int, err error) {
+	start := time.Now()
+	n, err = r.Reader.Read(p)
+	r.latency.Add(time.Since(start))
+	return n, err
+}
+
+// waitReader - sums the time spent waiting on reads of the data being
+// written to a drive, to tell it apart from the time spent writing.
+type waitReader struct {
+	io.Reader
+	wait time.Duration
+}
+
+func (r *waitReader) Read(p []byte) (n int, err error) {
+	start := time.Now()
+	n, err = r.Reader.Read(p)
+	r.wait += time.Since(start)
+	return n, err
+}
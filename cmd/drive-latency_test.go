@@ -0,0 +1,75 @@
+/*
+ * MinIO Cloud Storage, (C) 2019 MinIO, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"bytes"
+	"net/http"
+	"os"
+	"testing"
+)
+
+// Tests that posix reads and writes are recorded in the latency
+// histograms of the drive.
+func TestPosixDriveLatency(t *testing.T) {
+	posixStorage, diskPath, err := newPosixTestSetup()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(diskPath)
+
+	if err = posixStorage.MakeVol("bucket"); err != nil {
+		t.Fatal(err)
+	}
+	data := []byte("hello, world")
+	if err = posixStorage.WriteAll("bucket", "a", bytes.NewReader(data)); err != nil {
+		t.Fatal(err)
+	}
+	if err = posixStorage.CreateFile("bucket", "b", int64(len(data)), bytes.NewReader(data)); err != nil {
+		t.Fatal(err)
+	}
+	if _, err = posixStorage.ReadAll("bucket", "a"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err = posixStorage.ReadFile("bucket", "b", 0, make([]byte, len(data)), nil); err != nil {
+		t.Fatal(err)
+	}
+
+	endpoints := mustGetNewEndpointList(diskPath)
+	info := localEndpointsDriveLatency(endpoints, &http.Request{Host: "localhost:9000"})
+	if len(info.Latency) != 1 || info.Latency[0].Error != "" {
+		t.Fatalf("Unexpected drive latencies %v", info.Latency)
+	}
+	count := func(counts []uint64) (n uint64) {
+		for _, c := range counts {
+			n += c
+		}
+		return n
+	}
+	if n := count(info.Latency[0].Read.Counts); n != 2 {
+		t.Errorf("Expected 2 reads, got %d", n)
+	}
+	if n := count(info.Latency[0].Write.Counts); n != 2 {
+		t.Errorf("Expected 2 writes, got %d", n)
+	}
+
+	// Drives which are not tracked report an error.
+	info = localEndpointsDriveLatency(mustGetNewEndpointList(diskPath+"-unknown"), &http.Request{Host: "localhost:9000"})
+	if len(info.Latency) != 1 || info.Latency[0].Error == "" {
+		t.Fatalf("Expected an error for an unknown drive, got %v", info.Latency)
+	}
+}
@@ -31,6 +31,7 @@ import (
 
 	"github.com/minio/minio-go/v6/pkg/encrypt"
 	"github.com/minio/minio/cmd/crypto"
+	xhttp "github.com/minio/minio/cmd/http"
 	"github.com/minio/minio/cmd/logger"
 	"github.com/minio/minio/pkg/ioutil"
 	sha256 "github.com/minio/sha256-simd"
@@ -175,12 +176,20 @@ func rotateKey(oldKey []byte, newKey []byte, bucket, object string, metadata map
 			return err
 		}
 
-		newKey, encKey, err := GlobalKMS.GenerateKey(globalKMSKeyID, crypto.Context{bucket: path.Join(bucket, object)})
+		// SSE-KMS objects keep the key-id requested by the client.
+		if !crypto.S3KMS.IsEncrypted(metadata) {
+			keyID = globalKMSKeyID
+		}
+		newKey, encKey, err := GlobalKMS.GenerateKey(keyID, crypto.Context{bucket: path.Join(bucket, object)})
 		if err != nil {
 			return err
 		}
 		sealedKey = objectKey.Seal(newKey, crypto.GenerateIV(rand.Reader), crypto.S3.String(), bucket, object)
-		crypto.S3.CreateMetadata(metadata, globalKMSKeyID, encKey, sealedKey)
+		if crypto.S3KMS.IsEncrypted(metadata) {
+			crypto.S3KMS.CreateMetadata(metadata, keyID, encKey, sealedKey)
+		} else {
+			crypto.S3.CreateMetadata(metadata, keyID, encKey, sealedKey)
+		}
 		return nil
 	}
 }
@@ -209,6 +218,44 @@ func newEncryptMetadata(key []byte, bucket, object string, metadata map[string]s
 	return objectKey[:], nil
 }
 
+// newKMSEncryptMetadata - SSE-KMS objects are sealed like SSE-S3
+// objects, with a data key generated under the key-id requested by the
+// client instead of the default key-id of the KMS.
+func newKMSEncryptMetadata(keyID, bucket, object string, metadata map[string]string) ([]byte, error) {
+	if GlobalKMS == nil {
+		return nil, errKMSNotConfigured
+	}
+	key, encKey, err := GlobalKMS.GenerateKey(keyID, crypto.Context{bucket: path.Join(bucket, object)})
+	if err != nil {
+		return nil, err
+	}
+
+	objectKey := crypto.GenerateKey(key, rand.Reader)
+	sealedKey := objectKey.Seal(key, crypto.GenerateIV(rand.Reader), crypto.S3.String(), bucket, object)
+	crypto.S3KMS.CreateMetadata(metadata, keyID, encKey, sealedKey)
+	return objectKey[:], nil
+}
+
+// parseSSEKMSKeyID - returns the KMS key-id of a SSE-KMS request, the
+// default key-id of the KMS if none is requested. Encryption contexts
+// are not supported.
+func parseSSEKMSKeyID(h http.Header) (string, error) {
+	if crypto.SSEC.IsRequested(h) {
+		return "", crypto.ErrIncompatibleEncryptionMethod
+	}
+	keyID, context, err := crypto.S3KMS.ParseHTTP(h)
+	if err != nil {
+		return "", err
+	}
+	if context != nil {
+		return "", NotImplemented{}
+	}
+	if keyID == "" {
+		keyID = globalKMSKeyID
+	}
+	return keyID, nil
+}
+
 func newEncryptReader(content io.Reader, key []byte, bucket, object string, metadata map[string]string, sseS3 bool) (r io.Reader, encKey []byte, err error) {
 	objectEncryptionKey, err := newEncryptMetadata(key, bucket, object, metadata, sseS3)
 	if err != nil {
@@ -229,6 +276,14 @@ func setEncryptionMetadata(r *http.Request, bucket, object string, metadata map[
 	var (
 		key []byte
 	)
+	if crypto.S3KMS.IsRequested(r.Header) {
+		var keyID string
+		if keyID, err = parseSSEKMSKeyID(r.Header); err != nil {
+			return
+		}
+		_, err = newKMSEncryptMetadata(keyID, bucket, object, metadata)
+		return
+	}
 	if crypto.SSEC.IsRequested(r.Header) {
 		key, err = ParseSSECustomerRequest(r)
 		if err != nil {
@@ -250,6 +305,21 @@ func EncryptRequest(content io.Reader, r *http.Request, bucket, object string, m
 	if crypto.S3.IsRequested(r.Header) && crypto.SSEC.IsRequested(r.Header) {
 		return nil, objEncKey, crypto.ErrIncompatibleEncryptionMethod
 	}
+	if crypto.S3KMS.IsRequested(r.Header) {
+		keyID, err := parseSSEKMSKeyID(r.Header)
+		if err != nil {
+			return nil, objEncKey, err
+		}
+		objEncKey, err = newKMSEncryptMetadata(keyID, bucket, object, metadata)
+		if err != nil {
+			return nil, objEncKey, err
+		}
+		reader, err = sio.EncryptReader(content, sio.Config{Key: objEncKey, MinVersion: sio.Version20})
+		if err != nil {
+			return nil, objEncKey, crypto.ErrInvalidCustomerKey
+		}
+		return reader, objEncKey, nil
+	}
 	if crypto.SSEC.IsRequested(r.Header) {
 		key, err = ParseSSECustomerRequest(r)
 		if err != nil {
@@ -335,6 +405,7 @@ func newDecryptWriterWithObjectKey(client io.Writer, objectEncryptionKey []byte,
 	delete(metadata, crypto.S3SealedKey)
 	delete(metadata, crypto.S3KMSSealedKey)
 	delete(metadata, crypto.S3KMSKeyID)
+	delete(metadata, crypto.S3KMSRequested)
 	return writer, nil
 }
 
@@ -798,6 +869,7 @@ func DecryptBlocksRequest(client io.Writer, r *http.Request, bucket, object stri
 		delete(objInfo.UserDefined, crypto.S3SealedKey)
 		delete(objInfo.UserDefined, crypto.S3KMSKeyID)
 		delete(objInfo.UserDefined, crypto.S3KMSSealedKey)
+		delete(objInfo.UserDefined, crypto.S3KMSRequested)
 	}
 	if w.copySource {
 		w.customerKeyHeader = r.Header.Get(crypto.SSECopyKey)
@@ -1220,10 +1292,12 @@ func getOpts(ctx context.Context, r *http.Request, bucket, object string) (Objec
 		derivedKey := deriveClientKey(key, bucket, object)
 		encryption, err = encrypt.NewSSEC(derivedKey[:])
 		logger.CriticalIf(ctx, err)
-		return ObjectOptions{ServerSideEncryption: encryption}, nil
+		return ObjectOptions{ServerSideEncryption: encryption, CacheControl: r.Header.Get(xhttp.MinioCacheControl)}, nil
 	}
 	// default case of passing encryption headers to backend
-	return getDefaultOpts(r.Header, false, nil)
+	opts, err := getDefaultOpts(r.Header, false, nil)
+	opts.CacheControl = r.Header.Get(xhttp.MinioCacheControl)
+	return opts, err
 }
 
 // get ObjectOptions for PUT calls from encryption headers and metadata
@@ -158,6 +158,46 @@ func TestEncryptRequest(t *testing.T) {
 	}
 }
 
+func TestEncryptRequestSSEKMS(t *testing.T) {
+	defer func(kms crypto.KMS, keyID string) { GlobalKMS, globalKMSKeyID = kms, keyID }(GlobalKMS, globalKMSKeyID)
+	GlobalKMS, globalKMSKeyID = crypto.NewKMS([32]byte{}), "default-key"
+
+	testCases := []struct {
+		header        map[string]string
+		expectedKeyID string
+		expectedErr   error
+	}{
+		{map[string]string{crypto.SSEHeader: crypto.SSEAlgorithmKMS, crypto.SSEKmsID: "my-key"}, "my-key", nil},
+		{map[string]string{crypto.SSEHeader: crypto.SSEAlgorithmKMS}, "default-key", nil},
+		{map[string]string{crypto.SSEHeader: "aws:other", crypto.SSEKmsID: "my-key"}, "", crypto.ErrInvalidEncryptionMethod},
+		{map[string]string{crypto.SSEHeader: crypto.SSEAlgorithmKMS, crypto.SSEKmsContext: `{"a":"b"}`}, "", NotImplemented{}},
+	}
+	for i, testCase := range testCases {
+		req := &http.Request{Header: http.Header{}}
+		for k, v := range testCase.header {
+			req.Header.Set(k, v)
+		}
+		metadata := map[string]string{}
+		_, objectKey, err := EncryptRequest(bytes.NewReader(make([]byte, 64)), req, "bucket", "object", metadata)
+		if err != testCase.expectedErr {
+			t.Fatalf("Test %d: expected error %v, got %v", i+1, testCase.expectedErr, err)
+		}
+		if err != nil {
+			continue
+		}
+		if !crypto.S3KMS.IsEncrypted(metadata) || !crypto.S3.IsEncrypted(metadata) || metadata[crypto.S3KMSKeyID] != testCase.expectedKeyID {
+			t.Fatalf("Test %d: unexpected metadata %v", i+1, metadata)
+		}
+		key, err := decryptObjectInfo(nil, "bucket", "object", metadata)
+		if err != nil {
+			t.Fatalf("Test %d: failed to decrypt object key: %v", i+1, err)
+		}
+		if !bytes.Equal(key, objectKey) {
+			t.Fatalf("Test %d: object key mismatch", i+1)
+		}
+	}
+}
+
 var decryptRequestTests = []struct {
 	bucket, object string
 	header         map[string]string
@@ -370,6 +370,35 @@ func localEndpointsDrivePerf(endpoints EndpointList, r *http.Request) ServerDriv
 	}
 }
 
+// localEndpointsDriveLatency - returns ServerDriveLatencyInfo for only
+// the local endpoints from given list of endpoints
+func localEndpointsDriveLatency(endpoints EndpointList, r *http.Request) ServerDriveLatencyInfo {
+	var dls []disk.Latency
+	for _, endpoint := range endpoints {
+		// Only proceed for local endpoints
+		if endpoint.IsLocal {
+			latency, ok := globalDriveLatency.lookup(endpoint.Path)
+			if !ok {
+				dls = append(dls, disk.Latency{Path: endpoint.Path, Error: errDiskNotFound.Error()})
+				continue
+			}
+			dls = append(dls, disk.Latency{
+				Path:  endpoint.Path,
+				Read:  latency.read.Histogram(),
+				Write: latency.write.Histogram(),
+			})
+		}
+	}
+	addr := r.Host
+	if globalIsDistXL {
+		addr = GetLocalPeer(endpoints)
+	}
+	return ServerDriveLatencyInfo{
+		Addr:    addr,
+		Latency: dls,
+	}
+}
+
 // NewEndpointList - returns new endpoint list based on input args.
 func NewEndpointList(args ...string) (endpoints EndpointList, err error) {
 	var endpointType EndpointType
@@ -24,6 +24,11 @@ import (
 	"github.com/minio/minio/cmd/logger"
 )
 
+// Number of erasure blocks read and decoded ahead of the block being
+// written by reads spanning several blocks, each one takes a buffer of
+// a block size more.
+const erasureReadAheadBlocks = 1
+
 // Reads in parallel from readers.
 type parallelReader struct {
 	readers       []io.ReaderAt
@@ -151,6 +156,17 @@ func (e Erasure) Decode(ctx context.Context, writer io.Writer, readers []io.Read
 	startBlock := offset / e.blockSize
 	endBlock := (offset + length) / e.blockSize
 
+	// Blocks are read in the background while the previous ones are
+	// written, unless a single block is read.
+	nextBlock := func() readAheadBlock {
+		return e.readBlock(ctx, reader, nil)
+	}
+	if lastBlock := (offset + length - 1) / e.blockSize; lastBlock > startBlock {
+		r := e.readAhead(ctx, reader, lastBlock-startBlock+1)
+		defer r.stop()
+		nextBlock = r.next
+	}
+
 	var bytesWritten int64
 	for block := startBlock; block <= endBlock; block++ {
 		var blockOffset, blockLength int64
@@ -171,15 +187,14 @@ func (e Erasure) Decode(ctx context.Context, writer io.Writer, readers []io.Read
 		if blockLength == 0 {
 			break
 		}
-		bufs, err := reader.Read()
-		if err != nil {
-			return err
+		b := nextBlock()
+		if b.err != nil {
+			return b.err
 		}
-		if err = e.DecodeDataBlocks(bufs); err != nil {
-			logger.LogIf(ctx, err)
-			return err
+		n, err := writeDataBlocks(ctx, writer, b.bufs, e.dataBlocks, blockOffset, blockLength)
+		if b.done != nil {
+			b.done()
 		}
-		n, err := writeDataBlocks(ctx, writer, bufs, e.dataBlocks, blockOffset, blockLength)
 		if err != nil {
 			return err
 		}
@@ -191,3 +206,91 @@ func (e Erasure) Decode(ctx context.Context, writer io.Writer, readers []io.Read
 	}
 	return nil
 }
+
+// readAheadBlock - an erasure block read and decoded, done is called
+// once the block is written to hand its buffers back.
+type readAheadBlock struct {
+	bufs [][]byte
+	err  error
+	done func()
+}
+
+// readBlock - reads and decodes the next block from reader into the
+// shard buffers shards, the buffers of reader if nil.
+func (e Erasure) readBlock(ctx context.Context, reader *parallelReader, shards [][]byte) readAheadBlock {
+	if shards != nil {
+		reader.buf = shards
+	}
+	bufs, err := reader.Read()
+	if err != nil {
+		return readAheadBlock{err: err}
+	}
+	if err = e.DecodeDataBlocks(bufs); err != nil {
+		logger.LogIf(ctx, err)
+		return readAheadBlock{err: err}
+	}
+	return readAheadBlock{bufs: bufs}
+}
+
+// erasureReadAhead - reads and decodes the blocks of a read in the
+// background, at most erasureReadAheadBlocks ahead of the block being
+// written.
+type erasureReadAhead struct {
+	blockCh chan readAheadBlock
+	freeCh  chan [][]byte
+	doneCh  chan struct{}
+	wg      sync.WaitGroup
+}
+
+// readAhead - starts reading count blocks from reader in the
+// background, stop must be called once the blocks are written.
+func (e Erasure) readAhead(ctx context.Context, reader *parallelReader, count int64) *erasureReadAhead {
+	r := &erasureReadAhead{
+		blockCh: make(chan readAheadBlock, erasureReadAheadBlocks),
+		freeCh:  make(chan [][]byte, erasureReadAheadBlocks+1),
+		doneCh:  make(chan struct{}),
+	}
+	// One set of shard buffers for the block being written and one for
+	// each block read ahead.
+	r.freeCh <- reader.buf
+	for i := 0; i < erasureReadAheadBlocks; i++ {
+		r.freeCh <- make([][]byte, len(reader.buf))
+	}
+
+	r.wg.Add(1)
+	go func() {
+		defer r.wg.Done()
+		for i := int64(0); i < count; i++ {
+			var shards [][]byte
+			select {
+			case shards = <-r.freeCh:
+			case <-r.doneCh:
+				return
+			}
+			b := e.readBlock(ctx, reader, shards)
+			b.done = func() { r.freeCh <- shards }
+			select {
+			case r.blockCh <- b:
+			case <-r.doneCh:
+				return
+			}
+			if b.err != nil {
+				return
+			}
+		}
+	}()
+	return r
+}
+
+// next - returns the next block of the read, no more than count
+// blocks may be requested.
+func (r *erasureReadAhead) next() readAheadBlock {
+	return <-r.blockCh
+}
+
+// stop - stops reading ahead and waits for the block being read, the
+// readers are not used anymore once it returns.
+func (r *erasureReadAhead) stop() {
+	close(r.doneCh)
+	r.wg.Wait()
+}
@@ -199,6 +199,80 @@ func TestErasureDecode(t *testing.T) {
 	}
 }
 
+// failingWriter - writer failing once n bytes are written.
+type failingWriter struct {
+	n int
+}
+
+func (w *failingWriter) Write(p []byte) (int, error) {
+	if len(p) > w.n {
+		n := w.n
+		w.n = 0
+		return n, errFaultyDisk
+	}
+	w.n -= len(p)
+	return len(p), nil
+}
+
+// Tests that blocks read ahead are written in order, and that reading
+// ahead stops when the writer fails.
+func TestErasureDecodeReadAhead(t *testing.T) {
+	blockSize := int64(64 * humanize.KiByte)
+	setup, err := newErasureTestSetup(2, 2, blockSize)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer setup.Remove()
+	erasure, err := NewErasure(context.Background(), 2, 2, blockSize)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	data := make([]byte, 5*blockSize+100)
+	if _, err = io.ReadFull(crand.Reader, data); err != nil {
+		t.Fatal(err)
+	}
+	length := int64(len(data))
+	writers := make([]io.Writer, len(setup.disks))
+	for i, disk := range setup.disks {
+		writers[i] = newBitrotWriter(disk, "testbucket", "object", erasure.ShardFileSize(length), DefaultBitrotAlgorithm, erasure.ShardSize())
+	}
+	buffer := make([]byte, blockSize, 2*blockSize)
+	_, err = erasure.Encode(context.Background(), bytes.NewReader(data), writers, buffer, erasure.dataBlocks+1)
+	closeBitrotWriters(writers)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	newReaders := func(offset, readLen int64) []io.ReaderAt {
+		readers := make([]io.ReaderAt, len(setup.disks))
+		for i, disk := range setup.disks {
+			tillOffset := erasure.ShardFileTillOffset(offset, readLen, length)
+			readers[i] = newStreamingBitrotReader(disk, "testbucket", "object", tillOffset, DefaultBitrotAlgorithm, erasure.ShardSize())
+		}
+		return readers
+	}
+
+	offset, readLen := blockSize-10, 3*blockSize+20
+	readers := newReaders(offset, readLen)
+	var buf bytes.Buffer
+	err = erasure.Decode(context.Background(), &buf, readers, offset, readLen, length)
+	closeBitrotReaders(readers)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(buf.Bytes(), data[offset:offset+readLen]) {
+		t.Fatal("read returns wrong content")
+	}
+
+	readers = newReaders(0, length)
+	err = erasure.Decode(context.Background(), &failingWriter{n: int(blockSize)}, readers, 0, length, length)
+	closeBitrotReaders(readers)
+	if err != errFaultyDisk {
+		t.Fatalf("Expected %v, got %v", errFaultyDisk, err)
+	}
+}
+
 // Test erasureDecode with random offset and lengths.
 // This test is t.Skip()ed as it a long time to run, hence should be run
 // explicitly after commenting out t.Skip()
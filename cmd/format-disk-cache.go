@@ -186,7 +186,7 @@ func loadFormatCache(ctx context.Context, drives []string) ([]*formatCacheV2, bo
 			continue
 		}
 		formatV2 = format
-		if format.Cache.Version != formatCacheVersionV2 {
+		if format.Cache.Version != formatCacheVersionLatest {
 			migrating = true
 		}
 		formats[i] = formatV2
@@ -214,7 +214,7 @@ func checkFormatCacheValue(format *formatCacheV2, migrating bool) error {
 		if format.Version != formatMetaVersion1 {
 			return fmt.Errorf("Unsupported version of cache format [%s] found", format.Version)
 		}
-		if format.Cache.Version != formatCacheVersionV2 && format.Cache.Version != formatCacheVersionV1 {
+		if !isCacheFormatVersionSupported(format.Cache.Version) {
 			return fmt.Errorf("Unsupported Cache backend format found [%s]", format.Cache.Version)
 		}
 		return nil
@@ -223,7 +223,7 @@ func checkFormatCacheValue(format *formatCacheV2, migrating bool) error {
 	if format.Version != formatMetaVersion1 {
 		return fmt.Errorf("Unsupported version of cache format [%s] found", format.Version)
 	}
-	if format.Cache.Version != formatCacheVersionV2 {
+	if format.Cache.Version != formatCacheVersionLatest {
 		return fmt.Errorf("Unsupported Cache backend format found [%s]", format.Cache.Version)
 	}
 	return nil
@@ -360,6 +360,64 @@ func loadAndValidateCacheFormat(ctx context.Context, drives []string) (formats [
 	return formats, migrating, nil
 }
 
+// reformatCache - rewrites format.json of the cache drives after cache
+// drives were added or removed at runtime, so that the new order of
+// drives is validated at the next startup. Drives keep their uuid and
+// added drives are formatted. Drives missing a format.json are not used
+// by the cache, they are left unformatted with a placeholder uuid.
+func reformatCache(ctx context.Context, drives []string, added map[string]bool) error {
+	uuids := make([]string, len(drives))
+	formatted := make([]bool, len(drives))
+	for i, drive := range drives {
+		cacheFormatPath := pathJoin(drive, minioMetaBucket, formatConfigFile)
+		f, err := os.Open(cacheFormatPath)
+		if err != nil {
+			if !os.IsNotExist(err) {
+				logger.GetReqInfo(ctx).AppendTags("drive", drive)
+				logger.LogIf(ctx, err)
+				return err
+			}
+			uuids[i] = mustGetUUID()
+			formatted[i] = added[drive]
+			continue
+		}
+		format, err := formatMetaCacheV1(f)
+		f.Close()
+		if err != nil {
+			return err
+		}
+		if err = checkFormatCacheValue(format, false); err != nil {
+			return err
+		}
+		uuids[i] = format.Cache.This
+		formatted[i] = true
+	}
+
+	for i, drive := range drives {
+		if !formatted[i] {
+			continue
+		}
+		if err := os.MkdirAll(pathJoin(drive, minioMetaBucket), 0777); err != nil {
+			logger.GetReqInfo(ctx).AppendTags("drive", drive)
+			logger.LogIf(ctx, err)
+			return err
+		}
+		format := newFormatCacheV2([]string{drive})[0]
+		format.Cache.This = uuids[i]
+		format.Cache.Disks = uuids
+		f, err := os.OpenFile(pathJoin(drive, minioMetaBucket, formatConfigFile), os.O_RDWR|os.O_CREATE, 0600)
+		if err != nil {
+			return err
+		}
+		err = jsonSave(f, format)
+		f.Close()
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 // reads cached object on disk and writes it back after adding bitrot
 // hashsum per block as per the new disk cache format.
 func migrateData(ctx context.Context, c *diskCache, oldfile, destDir string) error {
@@ -376,14 +434,13 @@ func migrateData(ctx context.Context, c *diskCache, oldfile, destDir string) err
 	return err
 }
 
-// migrate cache contents from old cacheFS format to new backend format
-// new format is flat
+// migrate cache contents from old cacheFS format (v1) to new backend
+// format (v2), new format is flat
 //  sha(bucket,object)/  <== dir name
 //      - part.1         <== data
 //      - cache.json     <== metadata
 func migrateOldCache(ctx context.Context, c *diskCache) error {
 	oldCacheBucketsPath := path.Join(c.dir, minioMetaBucket, "buckets")
-	cacheFormatPath := pathJoin(c.dir, minioMetaBucket, formatConfigFile)
 
 	if _, err := os.Stat(oldCacheBucketsPath); err != nil {
 		// remove .minio.sys sub directories
@@ -391,8 +448,8 @@ func migrateOldCache(ctx context.Context, c *diskCache) error {
 		removeAll(path.Join(c.dir, minioMetaBucket, "tmp"))
 		removeAll(path.Join(c.dir, minioMetaBucket, "trash"))
 		removeAll(path.Join(c.dir, minioMetaBucket, "buckets"))
-		// just migrate cache format
-		return migrateCacheFormatJSON(cacheFormatPath)
+		// only the cache format needs to be migrated
+		return nil
 	}
 
 	buckets, err := readDir(oldCacheBucketsPath)
@@ -476,30 +533,5 @@ func migrateOldCache(ctx context.Context, c *diskCache) error {
 	removeAll(path.Join(c.dir, minioMetaBucket, "tmp"))
 	removeAll(path.Join(c.dir, minioMetaBucket, "trash"))
 	removeAll(path.Join(c.dir, minioMetaBucket, "buckets"))
-
-	return migrateCacheFormatJSON(cacheFormatPath)
-
-}
-
-func migrateCacheFormatJSON(cacheFormatPath string) error {
-	// now migrate format.json
-	f, err := os.OpenFile(cacheFormatPath, os.O_RDWR, 0)
-	if err != nil {
-		return err
-	}
-	defer f.Close()
-	formatV1 := formatCacheV1{}
-	if err := jsonLoad(f, &formatV1); err != nil {
-		return err
-	}
-
-	formatV2 := &formatCacheV2{}
-	formatV2.formatMetaV1 = formatV1.formatMetaV1
-	formatV2.Version = formatMetaVersion1
-	formatV2.Cache = formatV1.Cache
-	formatV2.Cache.Version = formatCacheVersionV2
-	if err := jsonSave(f, formatV2); err != nil {
-		return err
-	}
 	return nil
 }
@@ -0,0 +1,240 @@
+/*
+ * MinIO Cloud Storage, (C) 2019 MinIO, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"context"
+	"encoding/hex"
+	"io"
+	"os"
+	"path"
+	"sync/atomic"
+	"time"
+
+	"github.com/minio/minio/cmd/logger"
+	sha256 "github.com/minio/sha256-simd"
+)
+
+// Content addressed dedup blobs are stored under
+// `.minio.sys/dedup/<sha256[:2]>/<sha256>`, every object sharing
+// the blob is a hard link to it. The reference count of a blob is
+// the link count of the blob file minus the blob entry itself.
+//
+// Only objects uploaded with a single PutObject are deduplicated,
+// objects assembled by CompleteMultipartUpload keep their own data.
+const (
+	fsDedupPrefix = "dedup"
+
+	// Internal metadata key holding the content hash of a deduplicated object.
+	fsDedupSHA256Key = ReservedMetadataPrefix + "dedup-sha256"
+)
+
+// dedupBlobPath returns the path of the blob holding content with the given hash.
+func (fs *FSObjects) dedupBlobPath(sum string) string {
+	return pathJoin(fs.fsPath, minioMetaBucket, fsDedupPrefix, sum[:2], sum)
+}
+
+// fsHardLinkSupported returns true if hard links can be created
+// under the given directory, this is not the case for FAT and many
+// network mounted filesystems.
+func fsHardLinkSupported(dirPath string) bool {
+	srcPath := pathJoin(dirPath, mustGetUUID())
+	f, err := os.Create(srcPath)
+	if err != nil {
+		return false
+	}
+	f.Close()
+	defer os.Remove(srcPath)
+
+	dstPath := pathJoin(dirPath, mustGetUUID())
+	if err = os.Link(srcPath, dstPath); err != nil {
+		return false
+	}
+	os.Remove(dstPath)
+	return true
+}
+
+// dedupLink makes the temporary object at tmpPath share its data
+// with a previously stored blob of identical content, or registers
+// tmpPath as a new blob when no such content exists yet. Returns
+// false if the object could not be deduplicated, the temporary
+// object is left untouched in that case and is stored as is.
+func (fs *FSObjects) dedupLink(ctx context.Context, tmpPath, sum string, size int64) (bool, error) {
+	blobLock := fs.nsMutex.NewNSLock(ctx, minioMetaBucket, pathJoin(fsDedupPrefix, sum))
+	if err := blobLock.GetLock(globalOperationTimeout); err != nil {
+		return false, err
+	}
+	defer blobLock.Unlock()
+
+	blobPath := fs.dedupBlobPath(sum)
+	if _, err := os.Stat(blobPath); err == nil {
+		// Identical content is already present, replace the
+		// uploaded data with a reference to the existing blob.
+		linkPath := pathJoin(fs.fsPath, minioMetaTmpBucket, fs.fsUUID, mustGetUUID())
+		if err = os.Link(blobPath, linkPath); err != nil {
+			logger.LogIf(ctx, err)
+			return false, nil
+		}
+		if err = fsSimpleRenameFile(ctx, linkPath, tmpPath); err != nil {
+			fsRemoveFile(ctx, linkPath)
+			return false, err
+		}
+		atomic.AddUint64(&fs.dedupReferences, 1)
+		atomic.AddUint64(&fs.dedupSavedBytes, uint64(size))
+		return true, nil
+	} else if !os.IsNotExist(err) {
+		logger.LogIf(ctx, err)
+		return false, osErrToFSFileErr(err)
+	}
+
+	if err := mkdirAll(path.Dir(blobPath), 0777); err != nil {
+		logger.LogIf(ctx, err)
+		return false, err
+	}
+	if err := os.Link(tmpPath, blobPath); err != nil {
+		logger.LogIf(ctx, err)
+		return false, nil
+	}
+	atomic.AddUint64(&fs.dedupBlobs, 1)
+	atomic.AddUint64(&fs.dedupReferences, 1)
+	return true, nil
+}
+
+// dedupRelease drops one reference to the blob for the given
+// content hash, the blob is removed once no object references
+// it anymore. The object link itself must already be removed.
+func (fs *FSObjects) dedupRelease(ctx context.Context, sum string) error {
+	if len(sum) < 2 {
+		return nil
+	}
+	blobLock := fs.nsMutex.NewNSLock(ctx, minioMetaBucket, pathJoin(fsDedupPrefix, sum))
+	if err := blobLock.GetLock(globalOperationTimeout); err != nil {
+		return err
+	}
+	defer blobLock.Unlock()
+
+	blobPath := fs.dedupBlobPath(sum)
+	fi, err := os.Stat(blobPath)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil
+		}
+		return osErrToFSFileErr(err)
+	}
+	atomic.AddUint64(&fs.dedupReferences, ^uint64(0))
+	if fsLinkCount(fi) > 1 {
+		// Still referenced by other objects.
+		atomic.AddUint64(&fs.dedupSavedBytes, ^uint64(fi.Size()-1))
+		return nil
+	}
+	if err = fsDeleteFile(ctx, pathJoin(fs.fsPath, minioMetaBucket, fsDedupPrefix), blobPath); err != nil {
+		return err
+	}
+	atomic.AddUint64(&fs.dedupBlobs, ^uint64(0))
+	return nil
+}
+
+// dedupUnshare gives the object at objPath its own copy of the
+// data, this is needed before modifying a deduplicated object in
+// place. The caller must hold the object lock.
+func (fs *FSObjects) dedupUnshare(ctx context.Context, objPath string) error {
+	src, err := os.Open(objPath)
+	if err != nil {
+		return osErrToFSFileErr(err)
+	}
+	defer src.Close()
+
+	fsTmpObjPath := pathJoin(fs.fsPath, minioMetaTmpBucket, fs.fsUUID, mustGetUUID())
+	if _, err = fsCreateFile(ctx, fsTmpObjPath, src, make([]byte, readSizeV1), 0); err != nil {
+		fsRemoveFile(ctx, fsTmpObjPath)
+		return err
+	}
+	if err = fsSimpleRenameFile(ctx, fsTmpObjPath, objPath); err != nil {
+		fsRemoveFile(ctx, fsTmpObjPath)
+		return err
+	}
+	return nil
+}
+
+// dedupPurge periodically removes unreferenced blobs left behind
+// by interrupted operations and recomputes the dedup statistics.
+func (fs *FSObjects) dedupPurge(doneCh chan struct{}) {
+	fs.dedupSweep(context.Background())
+
+	ticker := time.NewTicker(globalUsageCheckInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-doneCh:
+			return
+		case <-ticker.C:
+			fs.dedupSweep(context.Background())
+		}
+	}
+}
+
+// dedupSweep walks all dedup blobs once, removing the blobs which
+// are no longer referenced.
+func (fs *FSObjects) dedupSweep(ctx context.Context) {
+	dedupDir := pathJoin(fs.fsPath, minioMetaBucket, fsDedupPrefix)
+	shards, err := readDir(dedupDir)
+	if err != nil {
+		if err != errFileNotFound {
+			logger.LogIf(ctx, err)
+		}
+		return
+	}
+
+	var blobs, references, savedBytes uint64
+	for _, shard := range shards {
+		entries, err := readDir(pathJoin(dedupDir, shard))
+		if err != nil {
+			continue
+		}
+		for _, entry := range entries {
+			if hasSuffix(entry, SlashSeparator) {
+				continue
+			}
+			fi, err := os.Stat(pathJoin(dedupDir, shard, entry))
+			if err != nil {
+				continue
+			}
+			links := fsLinkCount(fi)
+			if links <= 1 {
+				logger.LogIf(ctx, fs.dedupRelease(ctx, entry))
+				continue
+			}
+			blobs++
+			references += links - 1
+			savedBytes += uint64(fi.Size()) * (links - 2)
+		}
+	}
+	atomic.StoreUint64(&fs.dedupBlobs, blobs)
+	atomic.StoreUint64(&fs.dedupReferences, references)
+	atomic.StoreUint64(&fs.dedupSavedBytes, savedBytes)
+}
+
+// dedupObjectReader returns a reader which hashes the uploaded data
+// along the way, the hex encoded hash is available from sumFn once
+// the reader is drained.
+func dedupObjectReader(r io.Reader) (reader io.Reader, sumFn func() string) {
+	h := sha256.New()
+	return io.TeeReader(r, h), func() string {
+		return hex.EncodeToString(h.Sum(nil))
+	}
+}
@@ -0,0 +1,100 @@
+// +build !windows
+
+/*
+ * MinIO Cloud Storage, (C) 2019 MinIO, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"testing"
+)
+
+// Tests that identical objects share a single blob in FS dedup mode.
+func TestFSDedupObjects(t *testing.T) {
+	obj, disk, err := prepareFS()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(disk)
+
+	fs := obj.(*FSObjects)
+	fs.dedup = true
+
+	bucketName := "testbucket"
+	if err = obj.MakeBucketWithLocation(context.Background(), bucketName, ""); err != nil {
+		t.Fatal(err)
+	}
+
+	content := []byte("deduplicated content")
+	for _, object := range []string{"object1", "object2", "dir/object3"} {
+		_, err = obj.PutObject(context.Background(), bucketName, object,
+			mustGetPutObjReader(t, bytes.NewReader(content), int64(len(content)), "", ""), ObjectOptions{})
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	objInfo, err := obj.GetObjectInfo(context.Background(), bucketName, "object1", ObjectOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	sum := objInfo.UserDefined[fsDedupSHA256Key]
+	if sum == "" {
+		t.Fatal("Expected dedup content hash in object metadata")
+	}
+
+	info := fs.StorageInfo(context.Background())
+	if info.Dedup.Blobs != 1 {
+		t.Errorf("Expected 1 blob, got %d", info.Dedup.Blobs)
+	}
+	if info.Dedup.References != 3 {
+		t.Errorf("Expected 3 references, got %d", info.Dedup.References)
+	}
+	if info.Dedup.SavedBytes != uint64(2*len(content)) {
+		t.Errorf("Expected %d saved bytes, got %d", 2*len(content), info.Dedup.SavedBytes)
+	}
+
+	// Appending must not modify the shared blob.
+	if _, err = fs.AppendObject(context.Background(), bucketName, "object2",
+		mustGetPutObjReader(t, bytes.NewReader([]byte("tail")), 4, "", ""), ObjectOptions{}); err != nil {
+		t.Fatal(err)
+	}
+	fi, err := os.Stat(fs.dedupBlobPath(sum))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if fi.Size() != int64(len(content)) {
+		t.Fatalf("Shared blob modified by append, size %d", fi.Size())
+	}
+
+	for _, object := range []string{"object1", "dir/object3"} {
+		if err = obj.DeleteObject(context.Background(), bucketName, object); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if _, err = os.Stat(fs.dedupBlobPath(sum)); !os.IsNotExist(err) {
+		t.Fatalf("Expected blob to be removed after last reference, got %v", err)
+	}
+
+	// Counters must be kept in sync without waiting for a sweep.
+	info = fs.StorageInfo(context.Background())
+	if info.Dedup.Blobs != 0 || info.Dedup.References != 0 || info.Dedup.SavedBytes != 0 {
+		t.Fatalf("Expected empty dedup stats, got %+v", info.Dedup)
+	}
+}
@@ -0,0 +1,30 @@
+// +build windows
+
+/*
+ * MinIO Cloud Storage, (C) 2019 MinIO, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import "os"
+
+// fsDedupSupported - hard link counts are not exposed by os.FileInfo
+// on windows, dedup mode is disabled.
+const fsDedupSupported = false
+
+// fsLinkCount is not supported on windows.
+func fsLinkCount(fi os.FileInfo) uint64 {
+	return 0
+}
@@ -204,6 +204,61 @@ func (fs *FSObjects) ListMultipartUploads(ctx context.Context, bucket, object, k
 	return result, nil
 }
 
+// listBucketMultipartUploads - lists the pending multipart uploads of
+// all objects in a bucket by walking the multipart namespace, uploads
+// which did not record their object name are not listed.
+func (fs *FSObjects) listBucketMultipartUploads(ctx context.Context, bucket string) ([]MultipartInfo, error) {
+	if _, err := fs.statBucketDir(ctx, bucket); err != nil {
+		return nil, toObjectErr(err, bucket)
+	}
+
+	multipartDir := pathJoin(fs.fsPath, minioMetaMultipartBucket)
+	shaDirs, err := readDir(multipartDir)
+	if err != nil {
+		if err == errFileNotFound {
+			return nil, nil
+		}
+		logger.LogIf(ctx, err)
+		return nil, toObjectErr(err)
+	}
+
+	var uploads []MultipartInfo
+	for _, shaDir := range shaDirs {
+		shaDir = strings.TrimSuffix(shaDir, SlashSeparator)
+		uploadIDs, err := readDir(pathJoin(multipartDir, shaDir))
+		if err != nil {
+			continue
+		}
+		for _, uploadID := range uploadIDs {
+			uploadID = strings.TrimSuffix(uploadID, SlashSeparator)
+			metaFilePath := pathJoin(multipartDir, shaDir, uploadID, fs.metaJSONFile)
+			fsMetaBuf, err := ioutil.ReadFile(metaFilePath)
+			if err != nil {
+				continue
+			}
+			var fsMeta fsMetaV1
+			if err = json.Unmarshal(fsMetaBuf, &fsMeta); err != nil {
+				continue
+			}
+			object, ok := getMultipartObject(bucket, shaDir, fsMeta.Meta)
+			if !ok {
+				continue
+			}
+			// ModTime of fs.json is the creation time of the uploadID.
+			fi, err := fsStatFile(ctx, metaFilePath)
+			if err != nil {
+				continue
+			}
+			uploads = append(uploads, MultipartInfo{
+				Object:    object,
+				UploadID:  uploadID,
+				Initiated: getMultipartInitiated(fsMeta.Meta, fi.ModTime()),
+			})
+		}
+	}
+	return uploads, nil
+}
+
 // NewMultipartUpload - initialize a new multipart upload, returns a
 // unique id. The unique id returned here is of UUID form, for each
 // subsequent request each UUID is unique.
@@ -230,6 +285,10 @@ func (fs *FSObjects) NewMultipartUpload(ctx context.Context, bucket, object stri
 	// Initialize fs.json values.
 	fsMeta := newFSMetaV1()
 	fsMeta.Meta = opts.UserDefined
+	if fsMeta.Meta == nil {
+		fsMeta.Meta = make(map[string]string)
+	}
+	setMultipartUploadMeta(fsMeta.Meta, object, UTCNow())
 
 	fsMetaBytes, err := json.Marshal(fsMeta)
 	if err != nil {
@@ -669,6 +728,7 @@ func (fs *FSObjects) CompleteMultipartUpload(ctx context.Context, bucket string,
 	fsMeta.Meta["etag"] = s3MD5
 	// Save consolidated actual size.
 	fsMeta.Meta[ReservedMetadataPrefix+"actual-size"] = strconv.FormatInt(objectActualSize, 10)
+	clearMultipartUploadMeta(fsMeta.Meta)
 	if _, err = fsMeta.WriteTo(metaFile); err != nil {
 		logger.LogIf(ctx, err)
 		return oi, toObjectErr(err, bucket, object)
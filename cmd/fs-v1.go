@@ -31,6 +31,7 @@ import (
 	"time"
 
 	"github.com/minio/minio-go/v6/pkg/s3utils"
+	"github.com/minio/minio/cmd/crypto"
 	"github.com/minio/minio/cmd/logger"
 	"github.com/minio/minio/pkg/lifecycle"
 	"github.com/minio/minio/pkg/lock"
@@ -46,7 +47,18 @@ var defaultEtag = "00000000000000000000000000000000-1"
 // FSObjects - Implements fs object layer.
 type FSObjects struct {
 	// Disk usage metrics
-	totalUsed uint64 // ref: https://golang.org/pkg/sync/atomic/#pkg-note-BUG
+	totalUsed        uint64 // ref: https://golang.org/pkg/sync/atomic/#pkg-note-BUG
+	totalObjects     uint64 // ref: https://golang.org/pkg/sync/atomic/#pkg-note-BUG
+	totalObjectsSize uint64 // ref: https://golang.org/pkg/sync/atomic/#pkg-note-BUG
+
+	// Usage of each bucket as last counted by the usage crawler.
+	bucketsUsage   map[string]BucketUsageInfo
+	bucketsUsageMu sync.RWMutex
+
+	// Content addressed dedup metrics
+	dedupBlobs      uint64 // ref: https://golang.org/pkg/sync/atomic/#pkg-note-BUG
+	dedupReferences uint64 // ref: https://golang.org/pkg/sync/atomic/#pkg-note-BUG
+	dedupSavedBytes uint64 // ref: https://golang.org/pkg/sync/atomic/#pkg-note-BUG
 
 	// Path to be exported over S3 API.
 	fsPath string
@@ -67,6 +79,9 @@ type FSObjects struct {
 
 	diskMount bool
 
+	// Store objects with identical content only once.
+	dedup bool
+
 	appendFileMap   map[string]*fsAppendFile
 	appendFileMapMu sync.Mutex
 
@@ -145,6 +160,7 @@ func NewFSObjectLayer(fsPath string) (ObjectLayer, error) {
 		listPool:      NewTreeWalkPool(globalLookupTimeout),
 		appendFileMap: make(map[string]*fsAppendFile),
 		diskMount:     mountinfo.IsLikelyMountPoint(fsPath),
+		dedup:         globalFSDedupEnabled && fsDedupSupported,
 	}
 
 	// Once the filesystem has initialized hold the read lock for
@@ -159,6 +175,14 @@ func NewFSObjectLayer(fsPath string) (ObjectLayer, error) {
 
 	go fs.cleanupStaleMultipartUploads(ctx, GlobalMultipartCleanupInterval, GlobalMultipartExpiry, GlobalServiceDoneCh)
 
+	if fs.dedup && !fsHardLinkSupported(pathJoin(fsPath, minioMetaTmpBucket, fsUUID)) {
+		logger.Info("Hard links are not supported on %s, disabling content deduplication", fsPath)
+		fs.dedup = false
+	}
+	if fs.dedup {
+		go fs.dedupPurge(GlobalServiceDoneCh)
+	}
+
 	// Return successfully initialized object layer.
 	return fs, nil
 }
@@ -173,6 +197,8 @@ func (fs *FSObjects) Shutdown(ctx context.Context) error {
 
 // diskUsage returns du information for the posix path, in a continuous routine.
 func (fs *FSObjects) diskUsage(doneCh chan struct{}) {
+	index := globalSearchIndex.newIndex()
+	buckets := make(map[string]BucketUsageInfo)
 	usageFn := func(ctx context.Context, entry string) error {
 		if globalHTTPServer != nil {
 			// Wait at max 1 minute for an inprogress request
@@ -195,6 +221,12 @@ func (fs *FSObjects) diskUsage(doneCh chan struct{}) {
 				return err
 			}
 			atomic.AddUint64(&fs.totalUsed, uint64(fi.Size()))
+			if fs.isObjectEntry(entry, fi) {
+				atomic.AddUint64(&fs.totalObjects, 1)
+				atomic.AddUint64(&fs.totalObjectsSize, uint64(fi.Size()))
+				fs.addBucketUsage(buckets, entry, fi)
+				fs.indexObjectEntry(index, entry)
+			}
 		}
 		return nil
 	}
@@ -203,6 +235,9 @@ func (fs *FSObjects) diskUsage(doneCh chan struct{}) {
 	// so that we can start the routine freshly in another 12 hours.
 	if err := getDiskUsage(context.Background(), fs.fsPath, usageFn); err == errWalkAbort {
 		return
+	} else if err == nil {
+		fs.setBucketsUsage(buckets)
+		globalSearchIndex.update(fs.fsPath, index)
 	}
 
 	for {
@@ -210,7 +245,9 @@ func (fs *FSObjects) diskUsage(doneCh chan struct{}) {
 		case <-doneCh:
 			return
 		case <-time.After(globalUsageCheckInterval):
-			var usage uint64
+			var usage, objects, objectsSize uint64
+			index := globalSearchIndex.newIndex()
+			buckets := make(map[string]BucketUsageInfo)
 			usageFn = func(ctx context.Context, entry string) error {
 				if globalHTTPServer != nil {
 					// Wait at max 1 minute for an inprogress request
@@ -229,6 +266,12 @@ func (fs *FSObjects) diskUsage(doneCh chan struct{}) {
 					return err
 				}
 				usage = usage + uint64(fi.Size())
+				if fs.isObjectEntry(entry, fi) {
+					objects++
+					objectsSize = objectsSize + uint64(fi.Size())
+					fs.addBucketUsage(buckets, entry, fi)
+					fs.indexObjectEntry(index, entry)
+				}
 				return nil
 			}
 
@@ -236,10 +279,63 @@ func (fs *FSObjects) diskUsage(doneCh chan struct{}) {
 				continue
 			}
 			atomic.StoreUint64(&fs.totalUsed, usage)
+			atomic.StoreUint64(&fs.totalObjects, objects)
+			atomic.StoreUint64(&fs.totalObjectsSize, objectsSize)
+			fs.setBucketsUsage(buckets)
+			globalSearchIndex.update(fs.fsPath, index)
 		}
 	}
 }
 
+// isObjectEntry returns true if entry is an object stored
+// in a user bucket, i.e. a regular file outside the meta bucket.
+func (fs *FSObjects) isObjectEntry(entry string, fi os.FileInfo) bool {
+	if fi.IsDir() {
+		return false
+	}
+	return !hasPrefix(entry, pathJoin(fs.fsPath, minioMetaBucket)+SlashSeparator)
+}
+
+// addBucketUsage adds the object stored at entry to the usage of its
+// bucket in buckets.
+func (fs *FSObjects) addBucketUsage(buckets map[string]BucketUsageInfo, entry string, fi os.FileInfo) {
+	name := strings.TrimPrefix(entry, fs.fsPath+SlashSeparator)
+	if i := strings.Index(name, SlashSeparator); i > 0 {
+		usage := buckets[name[:i]]
+		usage.Objects++
+		usage.Size += uint64(fi.Size())
+		buckets[name[:i]] = usage
+	}
+}
+
+// getBucketsUsage - returns a copy of the usage of each bucket as last
+// counted by the usage crawler.
+func (fs *FSObjects) getBucketsUsage() map[string]BucketUsageInfo {
+	fs.bucketsUsageMu.RLock()
+	defer fs.bucketsUsageMu.RUnlock()
+	buckets := make(map[string]BucketUsageInfo, len(fs.bucketsUsage))
+	for bucket, usage := range fs.bucketsUsage {
+		buckets[bucket] = usage
+	}
+	return buckets
+}
+
+// setBucketsUsage - replaces the usage of each bucket by the usage of
+// a completed crawl.
+func (fs *FSObjects) setBucketsUsage(buckets map[string]BucketUsageInfo) {
+	fs.bucketsUsageMu.Lock()
+	defer fs.bucketsUsageMu.Unlock()
+	fs.bucketsUsage = buckets
+}
+
+// indexObjectEntry adds the object stored at entry to the search index.
+func (fs *FSObjects) indexObjectEntry(index *objectNameIndex, entry string) {
+	name := strings.TrimPrefix(entry, fs.fsPath+SlashSeparator)
+	if i := strings.Index(name, SlashSeparator); i > 0 {
+		index.add(name[:i], name[i+1:])
+	}
+}
+
 // StorageInfo - returns underlying storage statistics.
 func (fs *FSObjects) StorageInfo(ctx context.Context) StorageInfo {
 	di, err := getDiskInfo(fs.fsPath)
@@ -255,6 +351,15 @@ func (fs *FSObjects) StorageInfo(ctx context.Context) StorageInfo {
 		Total:     di.Total,
 		Available: di.Free,
 	}
+	storageInfo.Objects = atomic.LoadUint64(&fs.totalObjects)
+	storageInfo.ObjectsSize = atomic.LoadUint64(&fs.totalObjectsSize)
+	if storageInfo.Objects > 0 {
+		storageInfo.AvgObjectSize = storageInfo.ObjectsSize / storageInfo.Objects
+	}
+	storageInfo.Buckets = fs.getBucketsUsage()
+	storageInfo.Dedup.Blobs = atomic.LoadUint64(&fs.dedupBlobs)
+	storageInfo.Dedup.References = atomic.LoadUint64(&fs.dedupReferences)
+	storageInfo.Dedup.SavedBytes = atomic.LoadUint64(&fs.dedupSavedBytes)
 	storageInfo.Backend.Type = BackendFS
 	return storageInfo
 }
@@ -898,9 +1003,15 @@ func (fs *FSObjects) putObject(ctx context.Context, bucket string, object string
 		bufSize = size
 	}
 
+	var reader io.Reader = data
+	var dedupSum func() string
+	if fs.dedup && bucket != minioMetaBucket {
+		reader, dedupSum = dedupObjectReader(data)
+	}
+
 	buf := make([]byte, int(bufSize))
 	fsTmpObjPath := pathJoin(fs.fsPath, minioMetaTmpBucket, fs.fsUUID, tempObj)
-	bytesWritten, err := fsCreateFile(ctx, fsTmpObjPath, data, buf, data.Size())
+	bytesWritten, err := fsCreateFile(ctx, fsTmpObjPath, reader, buf, data.Size())
 	if err != nil {
 		fsRemoveFile(ctx, fsTmpObjPath)
 		return ObjectInfo{}, toObjectErr(err, bucket, object)
@@ -927,10 +1038,39 @@ func (fs *FSObjects) putObject(ctx context.Context, bucket string, object string
 			return ObjectInfo{}, ObjectAlreadyExists{Bucket: bucket, Object: object}
 		}
 	}
+
+	var oldDedupSum string
+	if dedupSum != nil {
+		// Remember the blob referenced by the object being overwritten.
+		oldMeta := fsMetaV1{}
+		if _, rerr := oldMeta.ReadFrom(ctx, wlk); rerr == nil {
+			oldDedupSum = oldMeta.Meta[fsDedupSHA256Key]
+		}
+
+		sum := dedupSum()
+		deduped, derr := fs.dedupLink(ctx, fsTmpObjPath, sum, bytesWritten)
+		if derr != nil {
+			return ObjectInfo{}, toObjectErr(derr, bucket, object)
+		}
+		if deduped {
+			fsMeta.Meta[fsDedupSHA256Key] = sum
+		}
+	}
+
 	if err = fsRenameFile(ctx, fsTmpObjPath, fsNSObjPath); err != nil {
+		if sum, ok := fsMeta.Meta[fsDedupSHA256Key]; ok {
+			// Drop the reference taken by the temporary object.
+			fsRemoveFile(ctx, fsTmpObjPath)
+			logger.LogIf(ctx, fs.dedupRelease(ctx, sum))
+		}
 		return ObjectInfo{}, toObjectErr(err, bucket, object)
 	}
 
+	if oldDedupSum != "" {
+		// The overwritten object no longer references its blob.
+		logger.LogIf(ctx, fs.dedupRelease(ctx, oldDedupSum))
+	}
+
 	if bucket != minioMetaBucket {
 		// Write FS metadata after a successful namespace operation.
 		if _, err = fsMeta.WriteTo(wlk); err != nil {
@@ -948,6 +1088,135 @@ func (fs *FSObjects) putObject(ctx context.Context, bucket string, object string
 	return fsMeta.ToObjectInfo(bucket, object, fi), nil
 }
 
+// AppendObject - appends data to an existing object in place, the
+// data is written at the end of the object file and `fs.json` is
+// updated afterwards. Creates the object if it does not exist yet.
+func (fs *FSObjects) AppendObject(ctx context.Context, bucket string, object string, r *PutObjReader, opts ObjectOptions) (objInfo ObjectInfo, retErr error) {
+	if err := checkPutObjectArgs(ctx, bucket, object, fs, r.Size()); err != nil {
+		return ObjectInfo{}, err
+	}
+	// Lock the object.
+	objectLock := fs.nsMutex.NewNSLock(ctx, bucket, object)
+	if err := objectLock.GetLock(globalObjectTimeout); err != nil {
+		logger.LogIf(ctx, err)
+		return objInfo, err
+	}
+	defer objectLock.Unlock()
+
+	// Append to a directory object is not allowed.
+	if hasSuffix(object, SlashSeparator) {
+		return ObjectInfo{}, toObjectErr(errIsNotRegular, bucket, object)
+	}
+
+	if _, err := fs.statBucketDir(ctx, bucket); err != nil {
+		return ObjectInfo{}, toObjectErr(err, bucket)
+	}
+
+	fsNSObjPath := pathJoin(fs.fsPath, bucket, object)
+	fi, err := fsStatFile(ctx, fsNSObjPath)
+	if err == errFileNotFound {
+		// Nothing to append to, create the object.
+		return fs.putObject(ctx, bucket, object, r, opts)
+	}
+	if err != nil {
+		return ObjectInfo{}, toObjectErr(err, bucket, object)
+	}
+
+	// Deny if WORM is enabled
+	if globalWORMEnabled {
+		return ObjectInfo{}, ObjectAlreadyExists{Bucket: bucket, Object: object}
+	}
+
+	data := r.Reader
+	if data.Size() < -1 {
+		logger.LogIf(ctx, errInvalidArgument)
+		return ObjectInfo{}, errInvalidArgument
+	}
+	if isMaxObjectSize(fi.Size() + data.Size()) {
+		return ObjectInfo{}, ObjectTooLarge{Bucket: bucket, Object: object}
+	}
+
+	fsMeta := fs.defaultFsJSON(object)
+	var wlk *lock.LockedFile
+	if bucket != minioMetaBucket {
+		fsMetaPath := pathJoin(fs.fsPath, minioMetaBucket, bucketMetaPrefix, bucket, object, fs.metaJSONFile)
+		wlk, err = fs.rwPool.Create(fsMetaPath)
+		if err != nil {
+			logger.LogIf(ctx, err)
+			return ObjectInfo{}, toObjectErr(err, bucket, object)
+		}
+		// This close will allow for locks to be synchronized on `fs.json`.
+		defer wlk.Close()
+
+		// Ignore a missing or empty `fs.json`, this is true for pre-existing data.
+		if _, err = fsMeta.ReadFrom(ctx, wlk); err != nil && err != io.EOF {
+			return ObjectInfo{}, toObjectErr(err, bucket, object)
+		}
+		if err == io.EOF {
+			fsMeta = fs.defaultFsJSON(object)
+		}
+	}
+
+	// Encrypted and compressed objects can not be extended
+	// without rewriting them.
+	if crypto.IsEncrypted(fsMeta.Meta) || fsMeta.Meta[ReservedMetadataPrefix+"compression"] != "" {
+		return ObjectInfo{}, NotImplemented{}
+	}
+
+	// Nothing to append, the object is left untouched.
+	if data.Size() == 0 {
+		return fsMeta.ToObjectInfo(bucket, object, fi), nil
+	}
+
+	if err = checkDiskFree(path.Dir(fsNSObjPath), data.Size()); err != nil {
+		return ObjectInfo{}, toObjectErr(err, bucket, object)
+	}
+
+	// Deduplicated data is shared with other objects, the
+	// object needs its own copy before it can be extended.
+	if sum, ok := fsMeta.Meta[fsDedupSHA256Key]; ok {
+		if err = fs.dedupUnshare(ctx, fsNSObjPath); err != nil {
+			return ObjectInfo{}, toObjectErr(err, bucket, object)
+		}
+		delete(fsMeta.Meta, fsDedupSHA256Key)
+		logger.LogIf(ctx, fs.dedupRelease(ctx, sum))
+	}
+
+	writer, err := lock.Open(fsNSObjPath, os.O_WRONLY|os.O_APPEND, 0666)
+	if err != nil {
+		return ObjectInfo{}, toObjectErr(osErrToFSFileErr(err), bucket, object)
+	}
+	defer writer.Close()
+
+	bytesWritten, err := io.CopyBuffer(writer, data, make([]byte, readSizeV1))
+	if err == nil && bytesWritten < data.Size() {
+		// Should return IncompleteBody{} error when reader has fewer
+		// bytes than specified in request header.
+		err = IncompleteBody{}
+	}
+	if err != nil {
+		// Roll back the partially appended data.
+		logger.LogIf(ctx, writer.Truncate(fi.Size()))
+		return ObjectInfo{}, toObjectErr(err, bucket, object)
+	}
+
+	if bucket != minioMetaBucket {
+		fsMeta.Meta["etag"] = getAppendObjectETag(fsMeta.Meta["etag"], r.MD5CurrentHexString())
+		// Write FS metadata after a successful namespace operation.
+		if _, err = fsMeta.WriteTo(wlk); err != nil {
+			return ObjectInfo{}, toObjectErr(err, bucket, object)
+		}
+	}
+
+	// Stat the file to fetch timestamp, size.
+	if fi, err = fsStatFile(ctx, fsNSObjPath); err != nil {
+		return ObjectInfo{}, toObjectErr(err, bucket, object)
+	}
+
+	// Success.
+	return fsMeta.ToObjectInfo(bucket, object, fi), nil
+}
+
 // DeleteObjects - deletes an object from a bucket, this operation is destructive
 // and there are no rollbacks supported.
 func (fs *FSObjects) DeleteObjects(ctx context.Context, bucket string, objects []string) ([]error, error) {
@@ -976,6 +1245,7 @@ func (fs *FSObjects) DeleteObject(ctx context.Context, bucket, object string) er
 		return toObjectErr(err, bucket)
 	}
 
+	var dedupSum string
 	minioMetaBucketDir := pathJoin(fs.fsPath, minioMetaBucket)
 	fsMetaPath := pathJoin(minioMetaBucketDir, bucketMetaPrefix, bucket, object, fs.metaJSONFile)
 	if bucket != minioMetaBucket {
@@ -983,6 +1253,13 @@ func (fs *FSObjects) DeleteObject(ctx context.Context, bucket, object string) er
 		if lerr == nil {
 			// This close will allow for fs locks to be synchronized on `fs.json`.
 			defer rwlk.Close()
+
+			if fs.dedup {
+				fsMeta := fsMetaV1{}
+				if _, rerr := fsMeta.ReadFrom(ctx, rwlk); rerr == nil {
+					dedupSum = fsMeta.Meta[fsDedupSHA256Key]
+				}
+			}
 		}
 		if lerr != nil && lerr != errFileNotFound {
 			logger.LogIf(ctx, lerr)
@@ -1002,6 +1279,11 @@ func (fs *FSObjects) DeleteObject(ctx context.Context, bucket, object string) er
 			return toObjectErr(err, bucket, object)
 		}
 	}
+
+	if dedupSum != "" {
+		// Drop the shared blob once the last reference is gone.
+		logger.LogIf(ctx, fs.dedupRelease(ctx, dedupSum))
+	}
 	return nil
 }
 
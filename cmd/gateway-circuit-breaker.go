@@ -0,0 +1,318 @@
+/*
+ * MinIO Cloud Storage, (C) 2019 MinIO, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"sync"
+	"time"
+)
+
+const (
+	// Consecutive backend failures after which the circuit opens.
+	defaultCircuitBreakerThreshold = 5
+
+	// Duration for which backend calls fast-fail once the circuit opens.
+	defaultCircuitBreakerCoolDown = 30 * time.Second
+)
+
+// Circuit breaker states.
+const (
+	circuitClosed   = "closed"
+	circuitOpen     = "open"
+	circuitHalfOpen = "half-open"
+)
+
+// CircuitBreakerInfo - state of the gateway backend circuit breaker.
+type CircuitBreakerInfo struct {
+	State    string    `json:"state"`
+	Failures int       `json:"failures"`
+	OpenedAt time.Time `json:"openedAt,omitempty"`
+	RetryAt  time.Time `json:"retryAt,omitempty"`
+}
+
+// circuitBreaker - fast-fails calls to the gateway backend after
+// threshold consecutive failures, for the duration of coolDown. Once
+// the cool-down has elapsed a single call is let through as a probe,
+// the circuit closes if it succeeds and opens again otherwise.
+type circuitBreaker struct {
+	mu        sync.Mutex
+	threshold int
+	coolDown  time.Duration
+	state     string
+	failures  int
+	openedAt  time.Time
+}
+
+func newCircuitBreaker(threshold int, coolDown time.Duration) *circuitBreaker {
+	return &circuitBreaker{
+		threshold: threshold,
+		coolDown:  coolDown,
+		state:     circuitClosed,
+	}
+}
+
+// allow - returns true if a backend call may be made.
+func (cb *circuitBreaker) allow() bool {
+	cb.mu.Lock()
+	defer cb.mu.Unlock()
+
+	switch cb.state {
+	case circuitOpen:
+		if time.Since(cb.openedAt) < cb.coolDown {
+			return false
+		}
+		// Let a single probe through.
+		cb.state = circuitHalfOpen
+		return true
+	case circuitHalfOpen:
+		// Probe is in flight.
+		return false
+	}
+	return true
+}
+
+// record - records the outcome of a backend call, only network
+// errors or an unreachable backend count as failures. Only a
+// successful call closes a half-open circuit.
+func (cb *circuitBreaker) record(err error) {
+	cb.mu.Lock()
+	defer cb.mu.Unlock()
+
+	if err == nil {
+		cb.state = circuitClosed
+		cb.failures = 0
+		return
+	}
+
+	if _, ok := err.(BackendDown); !ok {
+		if cb.state == circuitHalfOpen {
+			// Probe was inconclusive, let the next call probe again.
+			cb.state = circuitOpen
+			return
+		}
+		cb.failures = 0
+		return
+	}
+
+	cb.failures++
+	if cb.state == circuitHalfOpen || cb.failures >= cb.threshold {
+		cb.state = circuitOpen
+		cb.openedAt = UTCNow()
+	}
+}
+
+// Info - returns the current state of the circuit breaker.
+func (cb *circuitBreaker) Info() CircuitBreakerInfo {
+	cb.mu.Lock()
+	defer cb.mu.Unlock()
+
+	info := CircuitBreakerInfo{
+		State:    cb.state,
+		Failures: cb.failures,
+	}
+	if cb.state != circuitClosed {
+		info.OpenedAt = cb.openedAt
+		info.RetryAt = cb.openedAt.Add(cb.coolDown)
+	}
+	return info
+}
+
+// getBackendCircuitInfo - returns the state of the gateway backend
+// circuit breaker, nil if it is not enabled.
+func getBackendCircuitInfo() *CircuitBreakerInfo {
+	if globalGatewayCircuitBreaker == nil {
+		return nil
+	}
+	info := globalGatewayCircuitBreaker.Info()
+	return &info
+}
+
+// circuitBreakerObjects - wraps the gateway object layer, calls to
+// the backend fast-fail with BackendDown while the circuit is open
+// so that the disk cache, if any, serves them instead.
+type circuitBreakerObjects struct {
+	ObjectLayer
+	cb *circuitBreaker
+}
+
+func newCircuitBreakerObjects(objAPI ObjectLayer, cb *circuitBreaker) ObjectLayer {
+	return &circuitBreakerObjects{ObjectLayer: objAPI, cb: cb}
+}
+
+// call - runs fn unless the circuit is open.
+func (l *circuitBreakerObjects) call(fn func() error) error {
+	if !l.cb.allow() {
+		return BackendDown{}
+	}
+	err := fn()
+	l.cb.record(err)
+	return err
+}
+
+func (l *circuitBreakerObjects) MakeBucketWithLocation(ctx context.Context, bucket string, location string) error {
+	return l.call(func() error {
+		return l.ObjectLayer.MakeBucketWithLocation(ctx, bucket, location)
+	})
+}
+
+func (l *circuitBreakerObjects) GetBucketInfo(ctx context.Context, bucket string) (bucketInfo BucketInfo, err error) {
+	err = l.call(func() (cerr error) {
+		bucketInfo, cerr = l.ObjectLayer.GetBucketInfo(ctx, bucket)
+		return cerr
+	})
+	return bucketInfo, err
+}
+
+func (l *circuitBreakerObjects) ListBuckets(ctx context.Context) (buckets []BucketInfo, err error) {
+	err = l.call(func() (cerr error) {
+		buckets, cerr = l.ObjectLayer.ListBuckets(ctx)
+		return cerr
+	})
+	return buckets, err
+}
+
+func (l *circuitBreakerObjects) DeleteBucket(ctx context.Context, bucket string) error {
+	return l.call(func() error {
+		return l.ObjectLayer.DeleteBucket(ctx, bucket)
+	})
+}
+
+func (l *circuitBreakerObjects) ListObjects(ctx context.Context, bucket, prefix, marker, delimiter string, maxKeys int) (result ListObjectsInfo, err error) {
+	err = l.call(func() (cerr error) {
+		result, cerr = l.ObjectLayer.ListObjects(ctx, bucket, prefix, marker, delimiter, maxKeys)
+		return cerr
+	})
+	return result, err
+}
+
+func (l *circuitBreakerObjects) ListObjectsV2(ctx context.Context, bucket, prefix, continuationToken, delimiter string, maxKeys int, fetchOwner bool, startAfter string) (result ListObjectsV2Info, err error) {
+	err = l.call(func() (cerr error) {
+		result, cerr = l.ObjectLayer.ListObjectsV2(ctx, bucket, prefix, continuationToken, delimiter, maxKeys, fetchOwner, startAfter)
+		return cerr
+	})
+	return result, err
+}
+
+func (l *circuitBreakerObjects) GetObjectNInfo(ctx context.Context, bucket, object string, rs *HTTPRangeSpec, h http.Header, lockType LockType, opts ObjectOptions) (gr *GetObjectReader, err error) {
+	err = l.call(func() (cerr error) {
+		gr, cerr = l.ObjectLayer.GetObjectNInfo(ctx, bucket, object, rs, h, lockType, opts)
+		return cerr
+	})
+	return gr, err
+}
+
+func (l *circuitBreakerObjects) GetObject(ctx context.Context, bucket, object string, startOffset int64, length int64, writer io.Writer, etag string, opts ObjectOptions) error {
+	return l.call(func() error {
+		return l.ObjectLayer.GetObject(ctx, bucket, object, startOffset, length, writer, etag, opts)
+	})
+}
+
+func (l *circuitBreakerObjects) GetObjectInfo(ctx context.Context, bucket, object string, opts ObjectOptions) (objInfo ObjectInfo, err error) {
+	err = l.call(func() (cerr error) {
+		objInfo, cerr = l.ObjectLayer.GetObjectInfo(ctx, bucket, object, opts)
+		return cerr
+	})
+	return objInfo, err
+}
+
+func (l *circuitBreakerObjects) PutObject(ctx context.Context, bucket, object string, data *PutObjReader, opts ObjectOptions) (objInfo ObjectInfo, err error) {
+	err = l.call(func() (cerr error) {
+		objInfo, cerr = l.ObjectLayer.PutObject(ctx, bucket, object, data, opts)
+		return cerr
+	})
+	return objInfo, err
+}
+
+func (l *circuitBreakerObjects) CopyObject(ctx context.Context, srcBucket, srcObject, destBucket, destObject string, srcInfo ObjectInfo, srcOpts, dstOpts ObjectOptions) (objInfo ObjectInfo, err error) {
+	err = l.call(func() (cerr error) {
+		objInfo, cerr = l.ObjectLayer.CopyObject(ctx, srcBucket, srcObject, destBucket, destObject, srcInfo, srcOpts, dstOpts)
+		return cerr
+	})
+	return objInfo, err
+}
+
+func (l *circuitBreakerObjects) DeleteObject(ctx context.Context, bucket, object string) error {
+	return l.call(func() error {
+		return l.ObjectLayer.DeleteObject(ctx, bucket, object)
+	})
+}
+
+func (l *circuitBreakerObjects) DeleteObjects(ctx context.Context, bucket string, objects []string) (errs []error, err error) {
+	err = l.call(func() (cerr error) {
+		errs, cerr = l.ObjectLayer.DeleteObjects(ctx, bucket, objects)
+		return cerr
+	})
+	return errs, err
+}
+
+func (l *circuitBreakerObjects) ListMultipartUploads(ctx context.Context, bucket, prefix, keyMarker, uploadIDMarker, delimiter string, maxUploads int) (result ListMultipartsInfo, err error) {
+	err = l.call(func() (cerr error) {
+		result, cerr = l.ObjectLayer.ListMultipartUploads(ctx, bucket, prefix, keyMarker, uploadIDMarker, delimiter, maxUploads)
+		return cerr
+	})
+	return result, err
+}
+
+func (l *circuitBreakerObjects) NewMultipartUpload(ctx context.Context, bucket, object string, opts ObjectOptions) (uploadID string, err error) {
+	err = l.call(func() (cerr error) {
+		uploadID, cerr = l.ObjectLayer.NewMultipartUpload(ctx, bucket, object, opts)
+		return cerr
+	})
+	return uploadID, err
+}
+
+func (l *circuitBreakerObjects) CopyObjectPart(ctx context.Context, srcBucket, srcObject, destBucket, destObject string, uploadID string, partID int,
+	startOffset int64, length int64, srcInfo ObjectInfo, srcOpts, dstOpts ObjectOptions) (info PartInfo, err error) {
+	err = l.call(func() (cerr error) {
+		info, cerr = l.ObjectLayer.CopyObjectPart(ctx, srcBucket, srcObject, destBucket, destObject, uploadID, partID, startOffset, length, srcInfo, srcOpts, dstOpts)
+		return cerr
+	})
+	return info, err
+}
+
+func (l *circuitBreakerObjects) PutObjectPart(ctx context.Context, bucket, object, uploadID string, partID int, data *PutObjReader, opts ObjectOptions) (info PartInfo, err error) {
+	err = l.call(func() (cerr error) {
+		info, cerr = l.ObjectLayer.PutObjectPart(ctx, bucket, object, uploadID, partID, data, opts)
+		return cerr
+	})
+	return info, err
+}
+
+func (l *circuitBreakerObjects) ListObjectParts(ctx context.Context, bucket, object, uploadID string, partNumberMarker int, maxParts int, opts ObjectOptions) (result ListPartsInfo, err error) {
+	err = l.call(func() (cerr error) {
+		result, cerr = l.ObjectLayer.ListObjectParts(ctx, bucket, object, uploadID, partNumberMarker, maxParts, opts)
+		return cerr
+	})
+	return result, err
+}
+
+func (l *circuitBreakerObjects) AbortMultipartUpload(ctx context.Context, bucket, object, uploadID string) error {
+	return l.call(func() error {
+		return l.ObjectLayer.AbortMultipartUpload(ctx, bucket, object, uploadID)
+	})
+}
+
+func (l *circuitBreakerObjects) CompleteMultipartUpload(ctx context.Context, bucket, object, uploadID string, uploadedParts []CompletePart, opts ObjectOptions) (objInfo ObjectInfo, err error) {
+	err = l.call(func() (cerr error) {
+		objInfo, cerr = l.ObjectLayer.CompleteMultipartUpload(ctx, bucket, object, uploadID, uploadedParts, opts)
+		return cerr
+	})
+	return objInfo, err
+}
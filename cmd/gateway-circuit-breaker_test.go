@@ -0,0 +1,102 @@
+/*
+ * MinIO Cloud Storage, (C) 2019 MinIO, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+// backendObjects - object layer returning err for GetBucketInfo.
+type backendObjects struct {
+	ObjectLayer
+	calls int
+	err   error
+}
+
+func (l *backendObjects) GetBucketInfo(ctx context.Context, bucket string) (BucketInfo, error) {
+	l.calls++
+	return BucketInfo{Name: bucket}, l.err
+}
+
+func TestCircuitBreaker(t *testing.T) {
+	cb := newCircuitBreaker(2, 50*time.Millisecond)
+
+	cb.record(BackendDown{})
+	if !cb.allow() {
+		t.Fatal("Expected circuit to be closed below threshold")
+	}
+	// Not found errors mean the backend is reachable.
+	cb.record(BucketNotFound{})
+	cb.record(BackendDown{})
+	if !cb.allow() {
+		t.Fatal("Expected failures to be reset by a successful call")
+	}
+	cb.record(BackendDown{})
+	if cb.allow() {
+		t.Fatal("Expected circuit to be open")
+	}
+	if info := cb.Info(); info.State != circuitOpen || info.RetryAt.IsZero() {
+		t.Fatalf("Unexpected circuit breaker info %v", info)
+	}
+
+	time.Sleep(60 * time.Millisecond)
+	if !cb.allow() {
+		t.Fatal("Expected a probe after the cool-down")
+	}
+	if cb.allow() {
+		t.Fatal("Expected a single probe while half-open")
+	}
+	// Failed probe opens the circuit again.
+	cb.record(BackendDown{})
+	if cb.allow() {
+		t.Fatal("Expected circuit to be open after a failed probe")
+	}
+
+	time.Sleep(60 * time.Millisecond)
+	if !cb.allow() {
+		t.Fatal("Expected a probe after the cool-down")
+	}
+	// Only a successful probe closes the circuit.
+	cb.record(BucketNotFound{})
+	if info := cb.Info(); info.State == circuitClosed {
+		t.Fatalf("Expected circuit not to be closed by a failed call, got %v", info)
+	}
+	if !cb.allow() {
+		t.Fatal("Expected another probe after an inconclusive one")
+	}
+	cb.record(nil)
+	if info := cb.Info(); info.State != circuitClosed || info.Failures != 0 {
+		t.Fatalf("Expected circuit to be closed, got %v", info)
+	}
+}
+
+func TestCircuitBreakerObjects(t *testing.T) {
+	backend := &backendObjects{err: BackendDown{}}
+	objAPI := newCircuitBreakerObjects(backend, newCircuitBreaker(3, time.Minute))
+
+	for i := 0; i < 5; i++ {
+		if _, err := objAPI.GetBucketInfo(context.Background(), "bucket"); err != (BackendDown{}) {
+			t.Fatalf("Test %d: expected BackendDown, got %v", i+1, err)
+		}
+	}
+	// Calls after the third failure fast-fail.
+	if backend.calls != 3 {
+		t.Fatalf("Expected 3 backend calls, got %d", backend.calls)
+	}
+}
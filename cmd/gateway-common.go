@@ -19,7 +19,9 @@ package cmd
 import (
 	"net/http"
 	"os"
+	"strconv"
 	"strings"
+	"time"
 
 	xhttp "github.com/minio/minio/cmd/http"
 	"github.com/minio/minio/cmd/logger"
@@ -379,4 +381,33 @@ func handleGatewayEnvVars() {
 			logger.Fatal(err, "Unable to parse MINIO_GATEWAY_SSE value (`%s`)", gwsseVal)
 		}
 	}
+
+	if thresholdStr := os.Getenv("MINIO_GATEWAY_CIRCUIT_BREAKER_THRESHOLD"); thresholdStr != "" {
+		threshold, err := strconv.Atoi(thresholdStr)
+		if err != nil || threshold < 0 {
+			logger.Fatal(uiErrInvalidGatewayCircuitBreakerThreshold(err), "Unable to parse MINIO_GATEWAY_CIRCUIT_BREAKER_THRESHOLD value (`%s`)", thresholdStr)
+		}
+		globalGatewayCircuitBreakerThreshold = threshold
+	}
+
+	if coolDownStr := os.Getenv("MINIO_GATEWAY_CIRCUIT_BREAKER_COOLDOWN"); coolDownStr != "" {
+		coolDown, err := time.ParseDuration(coolDownStr)
+		if err != nil || coolDown <= 0 {
+			logger.Fatal(uiErrInvalidGatewayCircuitBreakerCoolDown(err), "Unable to parse MINIO_GATEWAY_CIRCUIT_BREAKER_COOLDOWN value (`%s`)", coolDownStr)
+		}
+		globalGatewayCircuitBreakerCoolDown = coolDown
+	}
+
+	if replicasStr := os.Getenv("MINIO_GATEWAY_READ_REPLICAS"); replicasStr != "" {
+		for _, endpoint := range strings.Split(replicasStr, ",") {
+			endpoint = strings.TrimSpace(endpoint)
+			if endpoint == "" {
+				continue
+			}
+			if _, _, err := ParseGatewayEndpoint(endpoint); err != nil {
+				logger.Fatal(uiErrInvalidGatewayReadReplicas(err), "Unable to parse MINIO_GATEWAY_READ_REPLICAS value (`%s`)", replicasStr)
+			}
+			globalGatewayReadReplicaEndpoints = append(globalGatewayReadReplicaEndpoints, endpoint)
+		}
+	}
 }
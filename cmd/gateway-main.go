@@ -29,7 +29,6 @@ import (
 	"github.com/minio/cli"
 	xhttp "github.com/minio/minio/cmd/http"
 	"github.com/minio/minio/cmd/logger"
-	"github.com/minio/minio/pkg/certs"
 )
 
 func init() {
@@ -139,6 +138,12 @@ func StartGateway(ctx *cli.Context, gw Gateway) {
 	// Handle gateway specific env
 	handleGatewayEnvVars()
 
+	// Obtain TLS certificates through ACME if enabled.
+	acmeManager := newACMEManager()
+	if acmeManager != nil {
+		globalIsSSL = true
+	}
+
 	// Validate if we have access, secret set through environment.
 	if !globalIsEnvCreds {
 		logger.Fatal(uiErrEnvCredentialsMissingGateway(nil), "Unable to start gateway")
@@ -177,19 +182,19 @@ func StartGateway(ctx *cli.Context, gw Gateway) {
 		logger.FatalIf(registerWebRouter(router), "Unable to configure web browser")
 	}
 
-	// Currently only NAS and S3 gateway support encryption headers.
-	encryptionEnabled := gatewayName == "s3" || gatewayName == "nas"
+	// Currently only NAS, S3 and Azure gateway support encryption headers.
+	encryptionEnabled := gatewayName == "s3" || gatewayName == "nas" || gatewayName == "azure"
 	allowSSEKMS := gatewayName == "s3" // Only S3 can support SSE-KMS (as pass-through)
 
 	// Add API router.
 	registerAPIRouter(router, encryptionEnabled, allowSSEKMS)
 
-	var getCert certs.GetCertificateFunc
-	if globalTLSCerts != nil {
-		getCert = globalTLSCerts.GetCertificate
-	}
+	getCert := getCertificateFunc(acmeManager, globalTLSCerts)
 
 	globalHTTPServer = xhttp.NewServer([]string{globalCLIContext.Addr}, criticalErrorHandler{registerHandlers(router, globalHandlers...)}, getCert)
+	if acmeManager != nil {
+		enableACMEChallenges(globalHTTPServer)
+	}
 	globalHTTPServer.UpdateBytesReadFunc = globalConnStats.incInputBytes
 	globalHTTPServer.UpdateBytesWrittenFunc = globalConnStats.incOutputBytes
 	go func() {
@@ -226,6 +231,24 @@ func StartGateway(ctx *cli.Context, gw Gateway) {
 		logger.FatalIf(err, "Unable to initialize gateway backend")
 	}
 
+	// Fast-fail backend calls while the backend is unreachable.
+	if globalGatewayCircuitBreakerThreshold > 0 {
+		globalGatewayCircuitBreaker = newCircuitBreaker(globalGatewayCircuitBreakerThreshold, globalGatewayCircuitBreakerCoolDown)
+		newObject = newCircuitBreakerObjects(newObject, globalGatewayCircuitBreaker)
+	}
+
+	// Load balance reads across the read replicas of the backend.
+	if len(globalGatewayReadReplicaEndpoints) > 0 {
+		for _, endpoint := range globalGatewayReadReplicaEndpoints {
+			logger.FatalIf(ValidateGatewayArguments(globalCLIContext.Addr, endpoint), "Invalid gateway read replica")
+		}
+		globalGatewayReadReplicas, err = getGatewayReadReplicas(gw, globalGatewayReadReplicaEndpoints, globalServerConfig.GetCredential())
+		logger.FatalIf(err, "Unable to initialize gateway read replicas")
+		replicaObjects := newReadReplicaObjects(newObject, globalGatewayReadReplicas)
+		go replicaObjects.healthCheck(readReplicaHealthCheckInterval, GlobalServiceDoneCh)
+		newObject = replicaObjects
+	}
+
 	// Populate existing buckets to the etcd backend
 	if globalDNSConfig != nil {
 		initFederatorBackend(newObject)
@@ -277,10 +300,40 @@ func StartGateway(ctx *cli.Context, gw Gateway) {
 	// Create new lifecycle system
 	globalLifecycleSys = NewLifecycleSys()
 
+	// Create new bucket logging system
+	globalBucketLoggingSys = NewBucketLoggingSys()
+
+	// Create new bucket CORS system
+	globalBucketCORSSys = NewBucketCORSSys()
+
+	// Create new bucket website system
+	globalBucketWebsiteSys = NewBucketWebsiteSys()
+
+	// Create new bucket snapshot system
+	globalBucketSnapshotSys = NewBucketSnapshotSys()
+
+	// Create new bandwidth system
+	globalBandwidthSys = NewBandwidthSys()
+
+	// Create new bucket mode system, bucket modes are only supported
+	// by gateways supporting config operations.
+	globalBucketModeSys = NewBucketModeSys()
+	if enableConfigOps {
+		logger.LogIf(context.Background(), globalBucketModeSys.Load(newObject))
+	}
+
+	// Create new bucket key normalization system, supported by the
+	// same gateways.
+	globalBucketKeyNormalizationSys = NewBucketKeyNormalizationSys()
+	if enableConfigOps {
+		logger.LogIf(context.Background(), globalBucketKeyNormalizationSys.Load(newObject))
+	}
+
 	// Create new notification system.
 	globalNotificationSys = NewNotificationSys(globalServerConfig, globalEndpoints)
 	if enableConfigOps && newObject.IsNotificationSupported() {
 		logger.LogIf(context.Background(), globalNotificationSys.Init(newObject))
+		go globalNotificationSys.startTargetHealthCheck(GlobalServiceDoneCh)
 	}
 
 	// Verify if object layer supports
@@ -0,0 +1,266 @@
+/*
+ * MinIO Cloud Storage, (C) 2020 MinIO, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/minio/minio/cmd/logger"
+	"github.com/minio/minio/pkg/auth"
+)
+
+const (
+	// Interval at which the read replicas of the gateway backend are
+	// health checked.
+	readReplicaHealthCheckInterval = 10 * time.Second
+)
+
+// ReadReplicaGateway - implemented by the gateways which can serve
+// reads from replicas of their backend.
+type ReadReplicaGateway interface {
+	// NewReadReplicaLayer returns a new ObjectLayer reading from the
+	// replica at endpoint.
+	NewReadReplicaLayer(endpoint string, creds auth.Credentials) (ObjectLayer, error)
+}
+
+// ReadReplicaInfo - state of a read replica of the gateway backend.
+type ReadReplicaInfo struct {
+	Endpoint  string    `json:"endpoint"`
+	Online    bool      `json:"online"`
+	LastCheck time.Time `json:"lastCheck,omitempty"`
+	LastError string    `json:"lastError,omitempty"`
+}
+
+// readReplica - a read replica of the gateway backend, a replica is
+// taken offline when it is unreachable and brought back online by the
+// health check.
+type readReplica struct {
+	ObjectLayer
+	endpoint string
+
+	mu        sync.Mutex
+	online    bool
+	lastCheck time.Time
+	lastError string
+}
+
+func (r *readReplica) isOnline() bool {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.online
+}
+
+// record - records the outcome of a call to the replica, only network
+// errors or an unreachable replica take it offline.
+func (r *readReplica) record(err error) {
+	if _, ok := err.(BackendDown); !ok {
+		return
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.online = false
+	r.lastError = err.Error()
+}
+
+// healthCheck - brings the replica online if it can be reached and
+// takes it offline otherwise.
+func (r *readReplica) healthCheck(ctx context.Context) {
+	_, err := r.ObjectLayer.ListBuckets(ctx)
+	_, down := err.(BackendDown)
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.lastCheck = UTCNow()
+	r.online = !down
+	r.lastError = ""
+	if down {
+		r.lastError = err.Error()
+	}
+}
+
+// Info - returns the current state of the replica.
+func (r *readReplica) Info() ReadReplicaInfo {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return ReadReplicaInfo{
+		Endpoint:  r.endpoint,
+		Online:    r.online,
+		LastCheck: r.lastCheck,
+		LastError: r.lastError,
+	}
+}
+
+// readReplicaObjects - wraps the gateway object layer, reads are load
+// balanced across the online read replicas and fail over to the next
+// replica, or to the primary backend when no replica can serve them.
+// All other calls go to the primary backend.
+type readReplicaObjects struct {
+	ObjectLayer
+	replicas []*readReplica
+	next     uint32
+}
+
+func newReadReplicaObjects(primary ObjectLayer, replicas []*readReplica) *readReplicaObjects {
+	return &readReplicaObjects{ObjectLayer: primary, replicas: replicas}
+}
+
+// getGatewayReadReplicas - returns the read replicas of the gateway
+// backend, each one is initially online.
+func getGatewayReadReplicas(gw Gateway, endpoints []string, creds auth.Credentials) ([]*readReplica, error) {
+	rgw, ok := gw.(ReadReplicaGateway)
+	if !ok {
+		return nil, NotImplemented{}
+	}
+	replicas := make([]*readReplica, 0, len(endpoints))
+	for _, endpoint := range endpoints {
+		objAPI, err := rgw.NewReadReplicaLayer(endpoint, creds)
+		if err != nil {
+			return nil, err
+		}
+		replicas = append(replicas, &readReplica{
+			ObjectLayer: objAPI,
+			endpoint:    endpoint,
+			online:      true,
+		})
+	}
+	return replicas, nil
+}
+
+// getReadReplicasInfo - returns the state of the read replicas of the
+// gateway backend, nil if there are none.
+func getReadReplicasInfo() []ReadReplicaInfo {
+	if len(globalGatewayReadReplicas) == 0 {
+		return nil
+	}
+	infos := make([]ReadReplicaInfo, len(globalGatewayReadReplicas))
+	for i, r := range globalGatewayReadReplicas {
+		infos[i] = r.Info()
+	}
+	return infos
+}
+
+// healthCheck - health checks the replicas every interval until
+// doneCh is closed.
+func (l *readReplicaObjects) healthCheck(interval time.Duration, doneCh <-chan struct{}) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-doneCh:
+			return
+		case <-ticker.C:
+			for _, r := range l.replicas {
+				wasOnline := r.isOnline()
+				r.healthCheck(GlobalContext)
+				info := r.Info()
+				switch {
+				case info.Online && !wasOnline:
+					logger.Info("Gateway read replica %s is back online", info.Endpoint)
+				case !info.Online && wasOnline:
+					logger.Info("Gateway read replica %s is offline: %s", info.Endpoint, info.LastError)
+				}
+			}
+		}
+	}
+}
+
+// read - runs fn against the online replicas in round-robin order
+// until one of them can be reached, falls back to the primary backend
+// if none can.
+func (l *readReplicaObjects) read(fn func(objAPI ObjectLayer) error) error {
+	start := int(atomic.AddUint32(&l.next, 1))
+	for i := range l.replicas {
+		r := l.replicas[(start+i)%len(l.replicas)]
+		if !r.isOnline() {
+			continue
+		}
+		err := fn(r.ObjectLayer)
+		r.record(err)
+		if _, ok := err.(BackendDown); !ok {
+			return err
+		}
+	}
+	return fn(l.ObjectLayer)
+}
+
+func (l *readReplicaObjects) GetBucketInfo(ctx context.Context, bucket string) (bucketInfo BucketInfo, err error) {
+	err = l.read(func(objAPI ObjectLayer) (rerr error) {
+		bucketInfo, rerr = objAPI.GetBucketInfo(ctx, bucket)
+		return rerr
+	})
+	return bucketInfo, err
+}
+
+func (l *readReplicaObjects) ListBuckets(ctx context.Context) (buckets []BucketInfo, err error) {
+	err = l.read(func(objAPI ObjectLayer) (rerr error) {
+		buckets, rerr = objAPI.ListBuckets(ctx)
+		return rerr
+	})
+	return buckets, err
+}
+
+func (l *readReplicaObjects) ListObjects(ctx context.Context, bucket, prefix, marker, delimiter string, maxKeys int) (result ListObjectsInfo, err error) {
+	err = l.read(func(objAPI ObjectLayer) (rerr error) {
+		result, rerr = objAPI.ListObjects(ctx, bucket, prefix, marker, delimiter, maxKeys)
+		return rerr
+	})
+	return result, err
+}
+
+func (l *readReplicaObjects) ListObjectsV2(ctx context.Context, bucket, prefix, continuationToken, delimiter string, maxKeys int, fetchOwner bool, startAfter string) (result ListObjectsV2Info, err error) {
+	err = l.read(func(objAPI ObjectLayer) (rerr error) {
+		result, rerr = objAPI.ListObjectsV2(ctx, bucket, prefix, continuationToken, delimiter, maxKeys, fetchOwner, startAfter)
+		return rerr
+	})
+	return result, err
+}
+
+func (l *readReplicaObjects) GetObjectNInfo(ctx context.Context, bucket, object string, rs *HTTPRangeSpec, h http.Header, lockType LockType, opts ObjectOptions) (gr *GetObjectReader, err error) {
+	err = l.read(func(objAPI ObjectLayer) (rerr error) {
+		gr, rerr = objAPI.GetObjectNInfo(ctx, bucket, object, rs, h, lockType, opts)
+		return rerr
+	})
+	return gr, err
+}
+
+func (l *readReplicaObjects) GetObject(ctx context.Context, bucket, object string, startOffset int64, length int64, writer io.Writer, etag string, opts ObjectOptions) error {
+	// Once data is written it cannot be read again from another
+	// backend, the call fails over only before that.
+	var written countingWriter
+	w := io.MultiWriter(writer, &written)
+	return l.read(func(objAPI ObjectLayer) error {
+		err := objAPI.GetObject(ctx, bucket, object, startOffset, length, w, etag, opts)
+		if _, ok := err.(BackendDown); ok && written > 0 {
+			return errUnexpected
+		}
+		return err
+	})
+}
+
+func (l *readReplicaObjects) GetObjectInfo(ctx context.Context, bucket, object string, opts ObjectOptions) (objInfo ObjectInfo, err error) {
+	err = l.read(func(objAPI ObjectLayer) (rerr error) {
+		objInfo, rerr = objAPI.GetObjectInfo(ctx, bucket, object, opts)
+		return rerr
+	})
+	return objInfo, err
+}
@@ -0,0 +1,97 @@
+/*
+ * MinIO Cloud Storage, (C) 2020 MinIO, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"context"
+	"testing"
+)
+
+// replicaBackendObjects - object layer returning err for GetBucketInfo
+// and ListBuckets.
+type replicaBackendObjects struct {
+	ObjectLayer
+	calls int
+	err   error
+}
+
+func (l *replicaBackendObjects) GetBucketInfo(ctx context.Context, bucket string) (BucketInfo, error) {
+	l.calls++
+	return BucketInfo{Name: bucket}, l.err
+}
+
+func (l *replicaBackendObjects) ListBuckets(ctx context.Context) ([]BucketInfo, error) {
+	return nil, l.err
+}
+
+func TestReadReplicaObjects(t *testing.T) {
+	ctx := context.Background()
+	primary := &replicaBackendObjects{}
+	replica1 := &replicaBackendObjects{}
+	replica2 := &replicaBackendObjects{err: BackendDown{}}
+	replicas := []*readReplica{
+		{ObjectLayer: replica1, endpoint: "replica1", online: true},
+		{ObjectLayer: replica2, endpoint: "replica2", online: true},
+	}
+	objAPI := newReadReplicaObjects(primary, replicas)
+
+	for i := 0; i < 4; i++ {
+		if _, err := objAPI.GetBucketInfo(ctx, "bucket"); err != nil {
+			t.Fatal(err)
+		}
+	}
+	// The unreachable replica is taken offline after its first call.
+	if primary.calls != 0 || replica1.calls != 4 || replica2.calls != 1 {
+		t.Fatalf("Unexpected calls, primary %d, replica1 %d, replica2 %d", primary.calls, replica1.calls, replica2.calls)
+	}
+	if replicas[1].isOnline() {
+		t.Fatal("Expected replica2 to be offline")
+	}
+
+	// Errors other than an unreachable replica are returned as is.
+	replica1.err = BucketNotFound{Bucket: "bucket"}
+	if _, err := objAPI.GetBucketInfo(ctx, "bucket"); err != replica1.err {
+		t.Fatalf("Expected %v, got %v", replica1.err, err)
+	}
+	if !replicas[0].isOnline() {
+		t.Fatal("Expected replica1 to be online")
+	}
+
+	// Reads fall back to the primary backend when no replica is online.
+	replica1.err = BackendDown{}
+	if _, err := objAPI.GetBucketInfo(ctx, "bucket"); err != nil {
+		t.Fatal(err)
+	}
+	if primary.calls != 1 || replicas[0].isOnline() {
+		t.Fatalf("Expected a read from the primary backend, got %d", primary.calls)
+	}
+
+	// The health check brings reachable replicas back online.
+	replica2.err = nil
+	for _, r := range replicas {
+		r.healthCheck(ctx)
+	}
+	if replicas[0].isOnline() || !replicas[1].isOnline() {
+		t.Fatalf("Unexpected replica states %v %v", replicas[0].Info(), replicas[1].Info())
+	}
+	if _, err := objAPI.GetBucketInfo(ctx, "bucket"); err != nil {
+		t.Fatal(err)
+	}
+	if replica2.calls != 2 {
+		t.Fatalf("Expected a read from replica2, got %d", replica2.calls)
+	}
+}
@@ -66,6 +66,11 @@ func (a GatewayUnsupported) CompleteMultipartUpload(ctx context.Context, bucket
 	return oi, NotImplemented{}
 }
 
+// AppendObject appends data to an object
+func (a GatewayUnsupported) AppendObject(ctx context.Context, bucket string, object string, data *PutObjReader, opts ObjectOptions) (objInfo ObjectInfo, err error) {
+	return objInfo, NotImplemented{}
+}
+
 // SetBucketPolicy sets policy on bucket
 func (a GatewayUnsupported) SetBucketPolicy(ctx context.Context, bucket string, bucketPolicy *policy.Policy) error {
 	logger.LogIf(ctx, NotImplemented{})
@@ -0,0 +1,166 @@
+/*
+ * MinIO Cloud Storage, (C) 2020 MinIO, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package azure
+
+import (
+	"encoding/base64"
+	"net/http"
+	"time"
+
+	"github.com/Azure/azure-sdk-for-go/storage"
+	"github.com/minio/minio-go/v6/pkg/encrypt"
+	"github.com/minio/minio/cmd/crypto"
+	sha256 "github.com/minio/sha256-simd"
+
+	minio "github.com/minio/minio/cmd"
+)
+
+/*
+ NOTE:
+ Server side encryption requested through the gateway is passed through
+ to Azure instead of being done by the gateway:
+
+   - SSE-C keys are sent as Azure customer-provided keys, Azure encrypts
+     the blob with the key and requires it on every read.
+   - SSE-S3 relies on the encryption at rest of the storage account, the
+     blob is written to the encryption scope set in
+     MINIO_AZURE_ENCRYPTION_SCOPE if any.
+
+ The encryption headers are only accepted by newer Azure API versions and
+ are not part of the shared key signature of the SDK, encrypted requests
+ are therefore authorized by a short lived account SAS token.
+*/
+
+const (
+	// Azure API version supporting customer-provided keys and
+	// encryption scopes.
+	azureSSEAPIVersion = "2019-07-07"
+
+	// Validity of the SAS tokens of encrypted requests.
+	azureSSETokenExpiry = 1 * time.Hour
+
+	// Blob metadata recording the server side encryption requested
+	// for the blob.
+	azureSSEMetaKey = "sse"
+	azureSSES3      = "AES256"
+	azureSSEC       = "SSE-C"
+)
+
+// azureSSEHeaders - returns the Azure headers of the requested server
+// side encryption, no headers are needed for SSE-S3 without encryption
+// scope.
+func azureSSEHeaders(sse encrypt.ServerSide, encryptionScope string) (http.Header, error) {
+	if sse == nil {
+		return nil, nil
+	}
+	switch sse.Type() {
+	case encrypt.SSEC:
+		h := make(http.Header)
+		sse.Marshal(h)
+		key, err := base64.StdEncoding.DecodeString(h.Get(crypto.SSECKey))
+		if err != nil || len(key) != 32 {
+			// Copy source keys are not supported by Azure.
+			return nil, minio.NotImplemented{}
+		}
+		keySHA256 := sha256.Sum256(key)
+		headers := make(http.Header)
+		headers.Set("x-ms-encryption-key", base64.StdEncoding.EncodeToString(key))
+		headers.Set("x-ms-encryption-key-sha256", base64.StdEncoding.EncodeToString(keySHA256[:]))
+		headers.Set("x-ms-encryption-algorithm", "AES256")
+		return headers, nil
+	case encrypt.S3:
+		if encryptionScope == "" {
+			return nil, nil
+		}
+		headers := make(http.Header)
+		headers.Set("x-ms-encryption-scope", encryptionScope)
+		return headers, nil
+	}
+	// SSE-KMS is not supported.
+	return nil, minio.NotImplemented{}
+}
+
+// azureSSEMeta - returns the value of azureSSEMetaKey for the requested
+// server side encryption.
+func azureSSEMeta(sse encrypt.ServerSide) string {
+	if sse == nil {
+		return ""
+	}
+	if sse.Type() == encrypt.SSEC {
+		return azureSSEC
+	}
+	return azureSSES3
+}
+
+// azureSSETransport - adds the encryption headers to the requests sent
+// to Azure.
+type azureSSETransport struct {
+	headers   http.Header
+	transport http.RoundTripper
+}
+
+func (t azureSSETransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	r := new(http.Request)
+	*r = *req
+	r.Header = make(http.Header, len(req.Header)+len(t.headers)+1)
+	for k, v := range req.Header {
+		r.Header[k] = v
+	}
+	for k, v := range t.headers {
+		r.Header[k] = v
+	}
+	r.Header.Set("x-ms-version", azureSSEAPIVersion)
+	return t.transport.RoundTrip(r)
+}
+
+// blobClient - returns the client for a request with the requested
+// server side encryption.
+func (a *azureObjects) blobClient(sse encrypt.ServerSide) (storage.BlobStorageClient, error) {
+	headers, err := azureSSEHeaders(sse, a.encryptionScope)
+	if err != nil || len(headers) == 0 {
+		return a.client, err
+	}
+
+	now := time.Now().UTC()
+	token, err := a.sharedKeyClient.GetAccountSASToken(storage.AccountSASTokenOptions{
+		APIVersion:    globalAzureAPIVersion,
+		Services:      storage.Services{Blob: true},
+		ResourceTypes: storage.ResourceTypes{Container: true, Object: true},
+		Permissions: storage.Permissions{
+			Read:   true,
+			Add:    true,
+			Create: true,
+			Write:  true,
+			Delete: true,
+			List:   true,
+		},
+		// Allow for clock skew with Azure.
+		Start:    now.Add(-5 * time.Minute),
+		Expiry:   now.Add(azureSSETokenExpiry),
+		UseHTTPS: true,
+	})
+	if err != nil {
+		return a.client, err
+	}
+
+	c := storage.NewAccountSASClient(a.accountName, token, a.env)
+	c.HTTPClient = &http.Client{Transport: azureSSETransport{
+		headers:   headers,
+		transport: a.transport,
+	}}
+	return c.GetBlobService(), nil
+}
@@ -26,6 +26,7 @@ import (
 	"fmt"
 	"io"
 	"net/http"
+	"os"
 	"path"
 	"sort"
 	"strconv"
@@ -36,8 +37,10 @@ import (
 	"github.com/Azure/go-autorest/autorest/azure"
 	humanize "github.com/dustin/go-humanize"
 	"github.com/minio/cli"
+	"github.com/minio/minio-go/v6/pkg/encrypt"
 	miniogopolicy "github.com/minio/minio-go/v6/pkg/policy"
 	"github.com/minio/minio/cmd"
+	"github.com/minio/minio/cmd/crypto"
 	"github.com/minio/minio/cmd/logger"
 	"github.com/minio/minio/pkg/auth"
 	"github.com/minio/minio/pkg/policy"
@@ -82,6 +85,9 @@ ENVIRONMENT VARIABLES:
   DOMAIN:
      MINIO_DOMAIN: To enable virtual-host-style requests, set this value to MinIO host domain name.
 
+  AZURE:
+     MINIO_AZURE_ENCRYPTION_SCOPE: Azure encryption scope of the objects uploaded with SSE-S3.
+
   CACHE:
      MINIO_CACHE_DRIVES: List of mounted drives or directories delimited by ";".
      MINIO_CACHE_EXCLUDE: List of cache exclusion patterns delimited by ";".
@@ -158,6 +164,7 @@ func (g *Azure) NewGatewayLayer(creds auth.Credentials) (minio.ObjectLayer, erro
 	// The default endpoint is the public cloud
 	var endpoint = azure.PublicCloud.StorageEndpointSuffix
 	var secure = true
+	var env = azure.PublicCloud
 
 	// Load the endpoint url if supplied by the user.
 	if g.host != "" {
@@ -168,9 +175,11 @@ func (g *Azure) NewGatewayLayer(creds auth.Credentials) (minio.ObjectLayer, erro
 		// Reformat the full account storage endpoint to the base format.
 		//   e.g. testazure.blob.core.windows.net => core.windows.net
 		endpoint = strings.ToLower(endpoint)
-		for _, env := range azureEnvs {
-			if strings.Contains(endpoint, env.StorageEndpointSuffix) {
-				endpoint = env.StorageEndpointSuffix
+		env = azure.Environment{StorageEndpointSuffix: endpoint}
+		for _, azureEnv := range azureEnvs {
+			if strings.Contains(endpoint, azureEnv.StorageEndpointSuffix) {
+				endpoint = azureEnv.StorageEndpointSuffix
+				env = azureEnv
 				break
 			}
 		}
@@ -182,10 +191,16 @@ func (g *Azure) NewGatewayLayer(creds auth.Credentials) (minio.ObjectLayer, erro
 	}
 
 	c.AddToUserAgent(fmt.Sprintf("APN/1.0 MinIO/1.0 MinIO/%s", minio.Version))
-	c.HTTPClient = &http.Client{Transport: minio.NewCustomHTTPTransport()}
+	transport := minio.NewCustomHTTPTransport()
+	c.HTTPClient = &http.Client{Transport: transport}
 
 	return &azureObjects{
-		client: c.GetBlobService(),
+		client:          c.GetBlobService(),
+		sharedKeyClient: c,
+		accountName:     creds.AccessKey,
+		env:             env,
+		transport:       transport,
+		encryptionScope: os.Getenv("MINIO_AZURE_ENCRYPTION_SCOPE"),
 	}, nil
 }
 
@@ -344,6 +359,14 @@ func azurePropertiesToS3Meta(meta storage.BlobMetadata, props storage.BlobProper
 type azureObjects struct {
 	minio.GatewayUnsupported
 	client storage.BlobStorageClient // Azure sdk client
+
+	// Used to authorize the requests with server side encryption,
+	// see blobClient.
+	sharedKeyClient storage.Client
+	accountName     string
+	env             azure.Environment
+	transport       http.RoundTripper
+	encryptionScope string
 }
 
 // Convert azure errors to minio object layer errors.
@@ -377,6 +400,10 @@ func azureToObjectError(err error, params ...string) error {
 		err = minio.PartTooBig{}
 	case "InvalidMetadata":
 		err = minio.UnsupportedMetadata{}
+	case "BlobUsesCustomerSpecifiedEncryption":
+		err = crypto.ErrMissingCustomerKey
+	case "BlobDoesNotUseCustomerSpecifiedEncryption":
+		err = crypto.ErrInvalidEncryptionMethod
 	default:
 		switch azureErr.StatusCode {
 		case http.StatusNotFound:
@@ -433,7 +460,7 @@ func checkAzureUploadID(ctx context.Context, uploadID string) (err error) {
 func parseAzurePart(metaPartFileName, prefix string) (partID int, err error) {
 	partStr := strings.TrimPrefix(metaPartFileName, prefix+minio.SlashSeparator)
 	if partID, err = strconv.Atoi(partStr); err != nil || partID <= 0 {
-		err = fmt.Errorf("invalid part number in block id '%s'", string(partID))
+		err = fmt.Errorf("invalid part number in block id '%s'", partStr)
 		return
 	}
 	return
@@ -695,9 +722,12 @@ func (a *azureObjects) GetObject(ctx context.Context, bucket, object string, sta
 		blobRange.End = uint64(startOffset + length - 1)
 	}
 
-	blob := a.client.GetContainerReference(bucket).GetBlobReference(object)
+	client, err := a.blobClient(opts.ServerSideEncryption)
+	if err != nil {
+		return azureToObjectError(err, bucket, object)
+	}
+	blob := client.GetContainerReference(bucket).GetBlobReference(object)
 	var rc io.ReadCloser
-	var err error
 	if startOffset == 0 && length == 0 {
 		rc, err = blob.Get(nil)
 	} else {
@@ -716,7 +746,11 @@ func (a *azureObjects) GetObject(ctx context.Context, bucket, object string, sta
 // GetObjectInfo - reads blob metadata properties and replies back minio.ObjectInfo,
 // uses zure equivalent GetBlobProperties.
 func (a *azureObjects) GetObjectInfo(ctx context.Context, bucket, object string, opts minio.ObjectOptions) (objInfo minio.ObjectInfo, err error) {
-	blob := a.client.GetContainerReference(bucket).GetBlobReference(object)
+	client, err := a.blobClient(opts.ServerSideEncryption)
+	if err != nil {
+		return objInfo, azureToObjectError(err, bucket, object)
+	}
+	blob := client.GetContainerReference(bucket).GetBlobReference(object)
 	err = blob.GetProperties(nil)
 	if err != nil {
 		return objInfo, azureToObjectError(err, bucket, object)
@@ -745,9 +779,19 @@ func (a *azureObjects) GetObjectInfo(ctx context.Context, bucket, object string,
 		delete(blob.Metadata, "md5sum")
 	}
 
+	sse := blob.Metadata[azureSSEMetaKey]
+	delete(blob.Metadata, azureSSEMetaKey)
+	userDefined := azurePropertiesToS3Meta(blob.Metadata, blob.Properties)
+	switch sse {
+	case azureSSES3:
+		userDefined[crypto.SSEHeader] = crypto.SSEAlgorithmAES256
+	case azureSSEC:
+		userDefined[crypto.SSECAlgorithm] = crypto.SSEAlgorithmAES256
+	}
+
 	return minio.ObjectInfo{
 		Bucket:          bucket,
-		UserDefined:     azurePropertiesToS3Meta(blob.Metadata, blob.Properties),
+		UserDefined:     userDefined,
 		ETag:            etag,
 		ModTime:         time.Time(blob.Properties.LastModified),
 		Name:            object,
@@ -761,12 +805,19 @@ func (a *azureObjects) GetObjectInfo(ctx context.Context, bucket, object string,
 // uses Azure equivalent CreateBlockBlobFromReader.
 func (a *azureObjects) PutObject(ctx context.Context, bucket, object string, r *minio.PutObjReader, opts minio.ObjectOptions) (objInfo minio.ObjectInfo, err error) {
 	data := r.Reader
+	client, err := a.blobClient(opts.ServerSideEncryption)
+	if err != nil {
+		return objInfo, azureToObjectError(err, bucket, object)
+	}
 	if data.Size() < azureBlockSize/10 {
-		blob := a.client.GetContainerReference(bucket).GetBlobReference(object)
+		blob := client.GetContainerReference(bucket).GetBlobReference(object)
 		blob.Metadata, blob.Properties, err = s3MetaToAzureProperties(ctx, opts.UserDefined)
 		if err != nil {
 			return objInfo, azureToObjectError(err, bucket, object)
 		}
+		if sse := azureSSEMeta(opts.ServerSideEncryption); sse != "" {
+			blob.Metadata[azureSSEMetaKey] = sse
+		}
 		if err = blob.CreateBlockBlobFromReader(data, nil); err != nil {
 			return objInfo, azureToObjectError(err, bucket, object)
 		}
@@ -775,7 +826,7 @@ func (a *azureObjects) PutObject(ctx context.Context, bucket, object string, r *
 
 	blockIDs := make(map[string]string)
 
-	blob := a.client.GetContainerReference(bucket).GetBlobReference(object)
+	blob := client.GetContainerReference(bucket).GetBlobReference(object)
 	subPartSize, subPartNumber := int64(azureBlockSize), 1
 	for remainingSize := data.Size(); remainingSize >= 0; remainingSize -= subPartSize {
 		// Allow to create zero sized part.
@@ -795,7 +846,7 @@ func (a *azureObjects) PutObject(ctx context.Context, bucket, object string, r *
 		subPartNumber++
 	}
 
-	objBlob := a.client.GetContainerReference(bucket).GetBlobReference(object)
+	objBlob := client.GetContainerReference(bucket).GetBlobReference(object)
 	resp, err := objBlob.GetBlockList(storage.BlockListTypeUncommitted, nil)
 	if err != nil {
 		return objInfo, azureToObjectError(err, bucket, object)
@@ -840,6 +891,9 @@ func (a *azureObjects) PutObject(ctx context.Context, bucket, object string, r *
 	if err != nil {
 		return objInfo, azureToObjectError(err, bucket, object)
 	}
+	if sse := azureSSEMeta(opts.ServerSideEncryption); sse != "" {
+		objBlob.Metadata[azureSSEMetaKey] = sse
+	}
 	if err = objBlob.SetProperties(nil); err != nil {
 		return objInfo, azureToObjectError(err, bucket, object)
 	}
@@ -856,12 +910,23 @@ func (a *azureObjects) CopyObject(ctx context.Context, srcBucket, srcObject, des
 	if srcOpts.CheckCopyPrecondFn != nil && srcOpts.CheckCopyPrecondFn(srcInfo, "") {
 		return minio.ObjectInfo{}, minio.PreConditionFailed{}
 	}
+	// Azure does not copy blobs encrypted with customer-provided keys.
+	if azureSSEMeta(srcOpts.ServerSideEncryption) == azureSSEC || azureSSEMeta(dstOpts.ServerSideEncryption) == azureSSEC {
+		return objInfo, minio.NotImplemented{}
+	}
+	client, err := a.blobClient(dstOpts.ServerSideEncryption)
+	if err != nil {
+		return objInfo, azureToObjectError(err, destBucket, destObject)
+	}
 	srcBlobURL := a.client.GetContainerReference(srcBucket).GetBlobReference(srcObject).GetURL()
-	destBlob := a.client.GetContainerReference(destBucket).GetBlobReference(destObject)
+	destBlob := client.GetContainerReference(destBucket).GetBlobReference(destObject)
 	azureMeta, props, err := s3MetaToAzureProperties(ctx, srcInfo.UserDefined)
 	if err != nil {
 		return objInfo, azureToObjectError(err, srcBucket, srcObject)
 	}
+	if sse := azureSSEMeta(dstOpts.ServerSideEncryption); sse != "" {
+		azureMeta[azureSSEMetaKey] = sse
+	}
 	destBlob.Metadata = azureMeta
 	err = destBlob.Copy(srcBlobURL, nil)
 	if err != nil {
@@ -930,10 +995,15 @@ func getAzureMetadataPartPrefix(uploadID, objectName string) string {
 	return fmt.Sprintf(metadataPartNamePrefix, uploadID, sha256.Sum256([]byte(objectName)))
 }
 
-func (a *azureObjects) checkUploadIDExists(ctx context.Context, bucketName, objectName, uploadID string) (err error) {
+// checkUploadIDExists - checks that the upload exists and returns the
+// server side encryption requested for it.
+func (a *azureObjects) checkUploadIDExists(ctx context.Context, bucketName, objectName, uploadID string) (sse encrypt.ServerSide, err error) {
 	blob := a.client.GetContainerReference(bucketName).GetBlobReference(
 		getAzureMetadataObjectName(objectName, uploadID))
 	err = blob.GetMetadata(nil)
+	if err == nil && blob.Metadata[azureSSEMetaKey] == azureSSES3 {
+		sse = encrypt.NewSSE()
+	}
 	err = azureToObjectError(err, bucketName, objectName)
 	oerr := minio.ObjectNotFound{
 		Bucket: bucketName,
@@ -944,11 +1014,17 @@ func (a *azureObjects) checkUploadIDExists(ctx context.Context, bucketName, obje
 			UploadID: uploadID,
 		}
 	}
-	return err
+	return sse, err
 }
 
 // NewMultipartUpload - Use Azure equivalent CreateBlockBlob.
 func (a *azureObjects) NewMultipartUpload(ctx context.Context, bucket, object string, opts minio.ObjectOptions) (uploadID string, err error) {
+	// The customer-provided key is not sent when completing the
+	// upload, Azure needs it to commit the blocks.
+	if azureSSEMeta(opts.ServerSideEncryption) == azureSSEC {
+		return "", minio.NotImplemented{}
+	}
+
 	uploadID, err = getAzureUploadID()
 	if err != nil {
 		logger.LogIf(ctx, err)
@@ -963,6 +1039,9 @@ func (a *azureObjects) NewMultipartUpload(ctx context.Context, bucket, object st
 	}
 
 	blob := a.client.GetContainerReference(bucket).GetBlobReference(metadataObject)
+	if sse := azureSSEMeta(opts.ServerSideEncryption); sse != "" {
+		blob.Metadata = storage.BlobMetadata{azureSSEMetaKey: sse}
+	}
 	err = blob.CreateBlockBlobFromReader(bytes.NewBuffer(jsonData), nil)
 	if err != nil {
 		return "", azureToObjectError(err, bucket, metadataObject)
@@ -974,7 +1053,8 @@ func (a *azureObjects) NewMultipartUpload(ctx context.Context, bucket, object st
 // PutObjectPart - Use Azure equivalent PutBlockWithLength.
 func (a *azureObjects) PutObjectPart(ctx context.Context, bucket, object, uploadID string, partID int, r *minio.PutObjReader, opts minio.ObjectOptions) (info minio.PartInfo, err error) {
 	data := r.Reader
-	if err = a.checkUploadIDExists(ctx, bucket, object, uploadID); err != nil {
+	sse, err := a.checkUploadIDExists(ctx, bucket, object, uploadID)
+	if err != nil {
 		return info, err
 	}
 
@@ -982,6 +1062,11 @@ func (a *azureObjects) PutObjectPart(ctx context.Context, bucket, object, upload
 		return info, err
 	}
 
+	client, err := a.blobClient(sse)
+	if err != nil {
+		return info, azureToObjectError(err, bucket, object)
+	}
+
 	partMetaV1 := newPartMetaV1(uploadID, partID)
 	subPartSize, subPartNumber := int64(azureBlockSize), 1
 	for remainingSize := data.Size(); remainingSize >= 0; remainingSize -= subPartSize {
@@ -997,7 +1082,7 @@ func (a *azureObjects) PutObjectPart(ctx context.Context, bucket, object, upload
 		id := base64.StdEncoding.EncodeToString([]byte(minio.MustGetUUID()))
 		partMetaV1.BlockIDs = append(partMetaV1.BlockIDs, id)
 
-		blob := a.client.GetContainerReference(bucket).GetBlobReference(object)
+		blob := client.GetContainerReference(bucket).GetBlobReference(object)
 		err = blob.PutBlockWithLength(id, uint64(subPartSize), io.LimitReader(data, subPartSize), nil)
 		if err != nil {
 			return info, azureToObjectError(err, bucket, object)
@@ -1032,7 +1117,7 @@ func (a *azureObjects) PutObjectPart(ctx context.Context, bucket, object, upload
 
 // ListObjectParts - Use Azure equivalent GetBlockList.
 func (a *azureObjects) ListObjectParts(ctx context.Context, bucket, object, uploadID string, partNumberMarker int, maxParts int, opts minio.ObjectOptions) (result minio.ListPartsInfo, err error) {
-	if err = a.checkUploadIDExists(ctx, bucket, object, uploadID); err != nil {
+	if _, err = a.checkUploadIDExists(ctx, bucket, object, uploadID); err != nil {
 		return result, err
 	}
 
@@ -1127,7 +1212,7 @@ func (a *azureObjects) ListObjectParts(ctx context.Context, bucket, object, uplo
 // There is no corresponding API in azure to abort an incomplete upload. The uncommmitted blocks
 // gets deleted after one week.
 func (a *azureObjects) AbortMultipartUpload(ctx context.Context, bucket, object, uploadID string) (err error) {
-	if err = a.checkUploadIDExists(ctx, bucket, object, uploadID); err != nil {
+	if _, err = a.checkUploadIDExists(ctx, bucket, object, uploadID); err != nil {
 		return err
 	}
 	var partNumberMarker int
@@ -1155,7 +1240,8 @@ func (a *azureObjects) AbortMultipartUpload(ctx context.Context, bucket, object,
 // CompleteMultipartUpload - Use Azure equivalent PutBlockList.
 func (a *azureObjects) CompleteMultipartUpload(ctx context.Context, bucket, object, uploadID string, uploadedParts []minio.CompletePart, opts minio.ObjectOptions) (objInfo minio.ObjectInfo, err error) {
 	metadataObject := getAzureMetadataObjectName(object, uploadID)
-	if err = a.checkUploadIDExists(ctx, bucket, object, uploadID); err != nil {
+	sse, err := a.checkUploadIDExists(ctx, bucket, object, uploadID)
+	if err != nil {
 		return objInfo, err
 	}
 
@@ -1163,6 +1249,11 @@ func (a *azureObjects) CompleteMultipartUpload(ctx context.Context, bucket, obje
 		return objInfo, err
 	}
 
+	client, err := a.blobClient(sse)
+	if err != nil {
+		return objInfo, azureToObjectError(err, bucket, object)
+	}
+
 	var metadataReader io.Reader
 	blob := a.client.GetContainerReference(bucket).GetBlobReference(metadataObject)
 	if metadataReader, err = blob.Get(nil); err != nil {
@@ -1175,7 +1266,7 @@ func (a *azureObjects) CompleteMultipartUpload(ctx context.Context, bucket, obje
 		return objInfo, azureToObjectError(err, bucket, metadataObject)
 	}
 
-	objBlob := a.client.GetContainerReference(bucket).GetBlobReference(object)
+	objBlob := client.GetContainerReference(bucket).GetBlobReference(object)
 
 	var allBlocks []storage.Block
 	for i, part := range uploadedParts {
@@ -1216,6 +1307,9 @@ func (a *azureObjects) CompleteMultipartUpload(ctx context.Context, bucket, obje
 		return objInfo, azureToObjectError(err, bucket, object)
 	}
 	objBlob.Metadata["md5sum"] = cmd.ComputeCompleteMultipartMD5(uploadedParts)
+	if sse != nil {
+		objBlob.Metadata[azureSSEMetaKey] = azureSSES3
+	}
 	err = objBlob.SetProperties(nil)
 	if err != nil {
 		return objInfo, azureToObjectError(err, bucket, object)
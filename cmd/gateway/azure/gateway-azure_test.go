@@ -18,13 +18,16 @@ package azure
 
 import (
 	"context"
+	"encoding/base64"
 	"fmt"
 	"net/http"
 	"reflect"
 	"testing"
 
 	"github.com/Azure/azure-sdk-for-go/storage"
+	"github.com/minio/minio-go/v6/pkg/encrypt"
 	minio "github.com/minio/minio/cmd"
+	sha256 "github.com/minio/sha256-simd"
 )
 
 // Test canonical metadata.
@@ -250,3 +253,38 @@ func TestCheckAzureUploadID(t *testing.T) {
 		}
 	}
 }
+
+func TestAzureSSEHeaders(t *testing.T) {
+	key := make([]byte, 32)
+	ssec, err := encrypt.NewSSEC(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	keySHA256 := sha256.Sum256(key)
+
+	testCases := []struct {
+		sse      encrypt.ServerSide
+		scope    string
+		expected http.Header
+		err      error
+	}{
+		{nil, "scope", nil, nil},
+		{encrypt.NewSSE(), "", nil, nil},
+		{encrypt.NewSSE(), "scope", http.Header{"X-Ms-Encryption-Scope": {"scope"}}, nil},
+		{ssec, "scope", http.Header{
+			"X-Ms-Encryption-Key":        {base64.StdEncoding.EncodeToString(key)},
+			"X-Ms-Encryption-Key-Sha256": {base64.StdEncoding.EncodeToString(keySHA256[:])},
+			"X-Ms-Encryption-Algorithm":  {"AES256"},
+		}, nil},
+		{encrypt.SSECopy(ssec), "", nil, minio.NotImplemented{}},
+	}
+	for i, testCase := range testCases {
+		headers, err := azureSSEHeaders(testCase.sse, testCase.scope)
+		if err != testCase.err {
+			t.Errorf("Test %d: expected error %v, got %v", i+1, testCase.err, err)
+		}
+		if !reflect.DeepEqual(headers, testCase.expected) {
+			t.Errorf("Test %d: expected headers %v, got %v", i+1, testCase.expected, headers)
+		}
+	}
+}
@@ -69,6 +69,10 @@ ENVIRONMENT VARIABLES:
   CACHE:
      MINIO_CACHE_DRIVES: List of mounted drives or directories delimited by ";".
      MINIO_CACHE_EXCLUDE: List of cache exclusion patterns delimited by ";".
+     MINIO_CACHE_STORAGE_CLASS: List of class=exclude or class=priority cache admission policies of storage classes delimited by ";".
+     MINIO_CACHE_QUOTA: List of bucket=percent maximum usage of the cache space by buckets delimited by ";".
+     MINIO_CACHE_PARITY: Number of parity shards of objects striped across the cache drives.
+     MINIO_CACHE_ROUTING: List of condition1,condition2=drive1,drive2 rules routing objects to cache drives delimited by ";".
      MINIO_CACHE_EXPIRY: Cache expiry duration in days.
      MINIO_CACHE_MAXUSE: Maximum permitted usage of the cache in percentage (0-100).
 
@@ -275,6 +279,25 @@ func (g *S3) NewGatewayLayer(creds auth.Credentials) (minio.ObjectLayer, error)
 	return &s, nil
 }
 
+// NewReadReplicaLayer returns s3 ObjectLayer reading from the replica
+// of the backend at endpoint.
+func (g *S3) NewReadReplicaLayer(endpoint string, creds auth.Credentials) (minio.ObjectLayer, error) {
+	clnt, err := newS3(endpoint)
+	if err != nil {
+		return nil, err
+	}
+
+	s := s3Objects{
+		Client: clnt,
+	}
+	// Replicas only serve reads, stale multipart uploads are cleaned
+	// up on the primary backend.
+	if minio.GlobalKMS != nil {
+		return &s3EncObjects{s}, nil
+	}
+	return &s, nil
+}
+
 // Production - s3 gateway is production ready.
 func (g *S3) Production() bool {
 	return true
@@ -19,17 +19,24 @@ package cmd
 import (
 	"bufio"
 	"context"
+	"io"
+	"mime"
 	"net"
 	"net/http"
+	"strconv"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/minio/minio-go/v6/pkg/set"
 
 	humanize "github.com/dustin/go-humanize"
+	"github.com/klauspost/compress/flate"
+	"github.com/klauspost/compress/gzip"
 	"github.com/minio/minio/cmd/crypto"
 	xhttp "github.com/minio/minio/cmd/http"
 	"github.com/minio/minio/cmd/logger"
+	bucketcors "github.com/minio/minio/pkg/cors"
 	"github.com/minio/minio/pkg/dns"
 	"github.com/minio/minio/pkg/handlers"
 	"github.com/rs/cors"
@@ -199,7 +206,18 @@ func guessIsBrowserReq(req *http.Request) bool {
 	}
 	aType := getRequestAuthType(req)
 	return strings.Contains(req.Header.Get("User-Agent"), "Mozilla") && globalIsBrowserEnabled &&
-		(aType == authTypeJWT || aType == authTypeAnonymous)
+		(aType == authTypeJWT || aType == authTypeAnonymous) && !guessIsWebsiteReq(req)
+}
+
+// guessIsWebsiteReq - returns true if the request is made to the
+// website endpoint of a bucket, such requests are never browser
+// requests even though they are made by browsers.
+func guessIsWebsiteReq(req *http.Request) bool {
+	if req == nil {
+		return false
+	}
+	_, ok := getWebsiteBucket(req.Host)
+	return ok
 }
 
 // guessIsHealthCheckReq - returns true if incoming request looks
@@ -380,7 +398,9 @@ type resourceHandler struct {
 	handler http.Handler
 }
 
-// setCorsHandler handler for CORS (Cross Origin Resource Sharing)
+// setCorsHandler handler for CORS (Cross Origin Resource Sharing),
+// requests to buckets are checked against the CORS configuration of
+// the bucket, all origins are allowed for the other requests.
 func setCorsHandler(h http.Handler) http.Handler {
 	commonS3Headers := []string{
 		xhttp.Date,
@@ -413,7 +433,89 @@ func setCorsHandler(h http.Handler) http.Handler {
 		AllowCredentials: true,
 	})
 
-	return c.Handler(h)
+	return corsHandler{handler: h, allowAllHandler: c.Handler(h)}
+}
+
+type corsHandler struct {
+	handler         http.Handler
+	allowAllHandler http.Handler
+}
+
+func (h corsHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	var bucketName string
+	switch {
+	case guessIsRPCReq(r), guessIsBrowserReq(r), guessIsHealthCheckReq(r), guessIsMetricsReq(r), isAdminReq(r):
+	default:
+		bucketName, _ = request2BucketObjectName(r)
+	}
+
+	// Bucket CORS configuration is stored on the backend
+	// which is not available in gateway mode.
+	if bucketName == "" || globalIsGateway {
+		h.allowAllHandler.ServeHTTP(w, r)
+		return
+	}
+
+	origin := r.Header.Get(xhttp.Origin)
+	if origin == "" || globalBucketCORSSys == nil {
+		h.handler.ServeHTTP(w, r)
+		return
+	}
+	config, enabled := globalBucketCORSSys.Get(bucketName)
+
+	// Preflight requests are answered from the CORS configuration
+	// of the bucket without being authenticated.
+	if method := r.Header.Get(xhttp.AccessControlRequestMethod); r.Method == http.MethodOptions && method != "" {
+		if !enabled {
+			writeErrorResponse(context.Background(), w, errorCodes.ToAPIErr(ErrCORSNotEnabled), r.URL, guessIsBrowserReq(r))
+			return
+		}
+		var headers []string
+		for _, header := range strings.Split(r.Header.Get(xhttp.AccessControlRequestHeaders), ",") {
+			if header = strings.TrimSpace(header); header != "" {
+				headers = append(headers, header)
+			}
+		}
+		rule, ok := config.Match(origin, method, headers)
+		if !ok {
+			writeErrorResponse(context.Background(), w, errorCodes.ToAPIErr(ErrCORSNotAllowed), r.URL, guessIsBrowserReq(r))
+			return
+		}
+		setBucketCORSHeaders(w, rule, origin)
+		w.Header().Set(xhttp.AccessControlAllowMethods, strings.Join(rule.AllowedMethods, ", "))
+		if len(headers) > 0 {
+			w.Header().Set(xhttp.AccessControlAllowHeaders, strings.Join(headers, ", "))
+		}
+		if rule.MaxAgeSeconds > 0 {
+			w.Header().Set(xhttp.AccessControlMaxAge, strconv.Itoa(rule.MaxAgeSeconds))
+		}
+		w.WriteHeader(http.StatusOK)
+		return
+	}
+
+	if enabled {
+		if rule, ok := config.Match(origin, r.Method, nil); ok {
+			setBucketCORSHeaders(w, rule, origin)
+		}
+	}
+	h.handler.ServeHTTP(w, r)
+}
+
+// setBucketCORSHeaders - sets the CORS response headers of a request
+// from origin allowed by rule.
+func setBucketCORSHeaders(w http.ResponseWriter, rule bucketcors.Rule, origin string) {
+	if rule.AllowsAnyOrigin() {
+		w.Header().Set(xhttp.AccessControlAllowOrigin, "*")
+	} else {
+		w.Header().Set(xhttp.AccessControlAllowOrigin, origin)
+		w.Header().Set(xhttp.AccessControlAllowCredentials, "true")
+	}
+	if len(rule.ExposeHeaders) > 0 {
+		w.Header().Set(xhttp.AccessControlExposeHeaders, strings.Join(rule.ExposeHeaders, ", "))
+	}
+	w.Header().Add(xhttp.Vary, xhttp.Origin)
+	w.Header().Add(xhttp.Vary, xhttp.AccessControlRequestHeaders)
+	w.Header().Add(xhttp.Vary, xhttp.AccessControlRequestMethod)
 }
 
 // setIgnoreResourcesHandler -
@@ -427,24 +529,19 @@ func setIgnoreResourcesHandler(h http.Handler) http.Handler {
 // Checks requests for not implemented Bucket resources
 func ignoreNotImplementedBucketResources(req *http.Request) bool {
 	for name := range req.URL.Query() {
-		// Enable GetBucketACL, GetBucketCors, GetBucketWebsite,
-		// GetBucketAcccelerate, GetBucketRequestPayment,
-		// GetBucketLogging, GetBucketLifecycle,
+		// Enable GetBucketACL, GetBucketAcccelerate,
+		// GetBucketRequestPayment, GetBucketLifecycle,
 		// GetBucketReplication, GetBucketTagging,
-		// GetBucketVersioning, DeleteBucketTagging,
-		// and DeleteBucketWebsite dummy calls specifically.
+		// GetBucketVersioning and DeleteBucketTagging
+		// dummy calls specifically.
 		if ((name == "acl" ||
-			name == "cors" ||
-			name == "website" ||
 			name == "accelerate" ||
 			name == "requestPayment" ||
-			name == "logging" ||
 			name == "lifecycle" ||
 			name == "replication" ||
 			name == "tagging" ||
 			name == "versioning") && req.Method == http.MethodGet) ||
-			((name == "tagging" ||
-				name == "website") && req.Method == http.MethodDelete) {
+			(name == "tagging" && req.Method == http.MethodDelete) {
 			return false
 		}
 
@@ -473,16 +570,13 @@ func ignoreNotImplementedObjectResources(req *http.Request) bool {
 var notimplementedBucketResourceNames = map[string]bool{
 	"accelerate":     true,
 	"acl":            true,
-	"cors":           true,
 	"inventory":      true,
-	"logging":        true,
 	"metrics":        true,
 	"replication":    true,
 	"requestPayment": true,
 	"tagging":        true,
 	"versioning":     true,
 	"versions":       true,
-	"website":        true,
 }
 
 // List of not implemented object queries
@@ -578,6 +672,36 @@ func (h httpStatsHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 	globalHTTPStats.updateStats(r, ww, durationSecs)
 }
 
+// requestRegistryHandler registers all in-flight S3 requests
+// with globalRequestRegistry.
+type requestRegistryHandler struct {
+	handler http.Handler
+}
+
+// setRequestRegistryHandler - tracks S3 requests while they are served.
+func setRequestRegistryHandler(h http.Handler) http.Handler {
+	return requestRegistryHandler{handler: h}
+}
+
+func (h requestRegistryHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	// Skip admin, browser and internal node to node requests.
+	if strings.HasPrefix(r.URL.Path, minioReservedBucketPath+SlashSeparator) {
+		h.handler.ServeHTTP(w, r)
+		return
+	}
+
+	r, done := globalRequestRegistry.add(r, w.Header().Get(xhttp.AmzRequestID))
+	defer done()
+
+	h.handler.ServeHTTP(w, r)
+
+	// Requests authenticated more than once, such as copies, are
+	// counted once.
+	if req := getActiveRequest(r.Context()); req != nil {
+		globalAccessKeyUsage.record(req.getAccessKey())
+	}
+}
+
 // requestValidityHandler validates all the incoming paths for
 // any malicious requests.
 type requestValidityHandler struct {
@@ -823,3 +947,191 @@ func (h sseTLSHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 	}
 	h.handler.ServeHTTP(w, r)
 }
+
+// Responses shorter than this are not worth compressing.
+const minCompressedResponseSize = 1024
+
+var (
+	gzipWriterPool = sync.Pool{
+		New: func() interface{} {
+			return gzip.NewWriter(nil)
+		},
+	}
+	flateWriterPool = sync.Pool{
+		New: func() interface{} {
+			w, _ := flate.NewWriter(nil, flate.DefaultCompression)
+			return w
+		},
+	}
+)
+
+// responseCompressionHandler compresses the XML and JSON responses of
+// listings, bucket sub-resources and web RPC calls with gzip or
+// deflate, as negotiated with the Accept-Encoding header. Object data
+// is never compressed, it is often compressed already and ranges and
+// checksums of objects refer to the stored data.
+type responseCompressionHandler struct {
+	handler http.Handler
+}
+
+func setResponseCompressionHandler(h http.Handler) http.Handler {
+	return responseCompressionHandler{h}
+}
+
+func (h responseCompressionHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if !isCompressibleResponseReq(r) {
+		h.handler.ServeHTTP(w, r)
+		return
+	}
+	// Caches must not serve a compressed response to other clients.
+	w.Header().Add(xhttp.Vary, xhttp.AcceptEncoding)
+	encoding := negotiateResponseEncoding(r.Header.Get(xhttp.AcceptEncoding))
+	if encoding == "" {
+		h.handler.ServeHTTP(w, r)
+		return
+	}
+
+	cw := &compressResponseWriter{ResponseWriter: w, encoding: encoding}
+	defer cw.close()
+	h.handler.ServeHTTP(cw, r)
+}
+
+// isCompressibleResponseReq - returns true if the response to the
+// request may be compressed, which are the web RPC calls and the S3
+// GET requests which do not return object data.
+func isCompressibleResponseReq(r *http.Request) bool {
+	if r.URL.Path == minioReservedBucketPath+"/webrpc" {
+		return r.Method == http.MethodPost
+	}
+	if r.Method != http.MethodGet || strings.HasPrefix(r.URL.Path, minioReservedBucketPath+SlashSeparator) ||
+		guessIsWebsiteReq(r) {
+		return false
+	}
+	if _, object := request2BucketObjectName(r); object != "" {
+		// Only the parts listing is returned for objects.
+		_, ok := r.URL.Query()["uploadId"]
+		return ok
+	}
+	return true
+}
+
+// negotiateResponseEncoding - returns the preferred encoding among gzip
+// and deflate accepted by the Accept-Encoding header, or "" if none is.
+func negotiateResponseEncoding(acceptEncoding string) string {
+	accepted := make(map[string]bool)
+	for _, e := range strings.Split(acceptEncoding, ",") {
+		params := strings.Split(e, ";")
+		name := strings.ToLower(strings.TrimSpace(params[0]))
+		ok := true
+		for _, param := range params[1:] {
+			param = strings.TrimSpace(param)
+			if strings.HasPrefix(param, "q=") {
+				q, err := strconv.ParseFloat(param[len("q="):], 64)
+				ok = err == nil && q > 0
+			}
+		}
+		accepted[name] = ok
+	}
+	for _, encoding := range []string{"gzip", "deflate"} {
+		if ok, found := accepted[encoding]; found {
+			if ok {
+				return encoding
+			}
+		} else if accepted["*"] {
+			return encoding
+		}
+	}
+	return ""
+}
+
+// compressResponseWriter compresses the response body once the headers
+// show an XML or JSON response which is long enough.
+type compressResponseWriter struct {
+	http.ResponseWriter
+	encoding    string
+	wroteHeader bool
+	cw          io.WriteCloser
+}
+
+func (w *compressResponseWriter) WriteHeader(code int) {
+	if w.wroteHeader {
+		return
+	}
+	w.wroteHeader = true
+
+	header := w.Header()
+	if isCompressibleResponse(code, header) {
+		header.Del(xhttp.ContentLength)
+		header.Set(xhttp.ContentEncoding, w.encoding)
+		switch w.encoding {
+		case "gzip":
+			gw := gzipWriterPool.Get().(*gzip.Writer)
+			gw.Reset(w.ResponseWriter)
+			w.cw = gw
+		case "deflate":
+			fw := flateWriterPool.Get().(*flate.Writer)
+			fw.Reset(w.ResponseWriter)
+			w.cw = fw
+		}
+	}
+	w.ResponseWriter.WriteHeader(code)
+}
+
+// isCompressibleResponse - returns true if the response with the given
+// status code and headers should be compressed.
+func isCompressibleResponse(code int, header http.Header) bool {
+	if code < http.StatusOK || code == http.StatusNoContent || code == http.StatusNotModified ||
+		header.Get(xhttp.ContentEncoding) != "" {
+		return false
+	}
+	if size, err := strconv.Atoi(header.Get(xhttp.ContentLength)); err == nil && size < minCompressedResponseSize {
+		return false
+	}
+	mediaType, _, err := mime.ParseMediaType(header.Get(xhttp.ContentType))
+	if err != nil {
+		return false
+	}
+	switch mediaType {
+	case "application/xml", "text/xml", "application/json":
+		return true
+	}
+	return false
+}
+
+func (w *compressResponseWriter) Write(b []byte) (int, error) {
+	if !w.wroteHeader {
+		w.WriteHeader(http.StatusOK)
+	}
+	if w.cw != nil {
+		return w.cw.Write(b)
+	}
+	return w.ResponseWriter.Write(b)
+}
+
+// Flush - flushes the data compressed so far, streamed responses such
+// as bucket notifications keep working.
+func (w *compressResponseWriter) Flush() {
+	switch cw := w.cw.(type) {
+	case *gzip.Writer:
+		cw.Flush()
+	case *flate.Writer:
+		cw.Flush()
+	}
+	if f, ok := w.ResponseWriter.(http.Flusher); ok {
+		f.Flush()
+	}
+}
+
+// close - completes the compressed response and releases the
+// compressor.
+func (w *compressResponseWriter) close() {
+	switch cw := w.cw.(type) {
+	case *gzip.Writer:
+		cw.Close()
+		gzipWriterPool.Put(cw)
+	case *flate.Writer:
+		cw.Close()
+		flateWriterPool.Put(cw)
+	}
+	w.cw = nil
+}
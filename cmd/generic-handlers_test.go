@@ -17,13 +17,18 @@
 package cmd
 
 import (
+	"bytes"
+	"io/ioutil"
 	"net/http"
 	"net/http/httptest"
 	"net/url"
 	"strconv"
+	"strings"
 	"testing"
 
+	"github.com/klauspost/compress/gzip"
 	"github.com/minio/minio/cmd/crypto"
+	xhttp "github.com/minio/minio/cmd/http"
 )
 
 // Tests getRedirectLocation function for all its criteria.
@@ -245,3 +250,98 @@ func TestSSETLSHandler(t *testing.T) {
 		}
 	}
 }
+
+func TestNegotiateResponseEncoding(t *testing.T) {
+	testCases := []struct {
+		acceptEncoding string
+		encoding       string
+	}{
+		{"", ""},
+		{"identity", ""},
+		{"gzip", "gzip"},
+		{"deflate, gzip", "gzip"},
+		{"gzip;q=0, deflate", "deflate"},
+		{"GZIP; q=0.5", "gzip"},
+		{"*", "gzip"},
+		{"*, gzip;q=0", "deflate"},
+		{"br", ""},
+	}
+	for i, testCase := range testCases {
+		if encoding := negotiateResponseEncoding(testCase.acceptEncoding); encoding != testCase.encoding {
+			t.Errorf("Test %d: expected %q, got %q", i+1, testCase.encoding, encoding)
+		}
+	}
+}
+
+func TestResponseCompressionHandler(t *testing.T) {
+	body := []byte(strings.Repeat("<Key>object</Key>", 100))
+	var xmlHandler http.HandlerFunc = func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set(xhttp.ContentType, "application/xml")
+		w.Header().Set(xhttp.ContentLength, strconv.Itoa(len(body)))
+		w.Write(body)
+	}
+
+	testCases := []struct {
+		method, path   string
+		acceptEncoding string
+		compressed     bool
+	}{
+		{http.MethodGet, "/bucket", "gzip", true},
+		{http.MethodGet, "/bucket/object?uploadId=id", "gzip", true},
+		{http.MethodPost, minioReservedBucketPath + "/webrpc", "gzip", true},
+		{http.MethodGet, "/bucket", "", false},
+		{http.MethodGet, "/bucket/object", "gzip", false},
+		{http.MethodPut, "/bucket", "gzip", false},
+		{http.MethodGet, minioReservedBucketPath + "/admin/v1/info", "gzip", false},
+	}
+	for i, testCase := range testCases {
+		w := httptest.NewRecorder()
+		r := httptest.NewRequest(testCase.method, "http://localhost:9000"+testCase.path, nil)
+		if testCase.acceptEncoding != "" {
+			r.Header.Set(xhttp.AcceptEncoding, testCase.acceptEncoding)
+		}
+		setResponseCompressionHandler(xmlHandler).ServeHTTP(w, r)
+
+		if !testCase.compressed {
+			if w.Header().Get(xhttp.ContentEncoding) != "" || !bytes.Equal(w.Body.Bytes(), body) {
+				t.Errorf("Test %d: expected an uncompressed response", i+1)
+			}
+			continue
+		}
+		if w.Header().Get(xhttp.ContentEncoding) != "gzip" || w.Header().Get(xhttp.ContentLength) != "" {
+			t.Fatalf("Test %d: expected a gzip response, got headers %v", i+1, w.Header())
+		}
+		gr, err := gzip.NewReader(w.Body)
+		if err != nil {
+			t.Fatalf("Test %d: %v", i+1, err)
+		}
+		data, err := ioutil.ReadAll(gr)
+		if err != nil {
+			t.Fatalf("Test %d: %v", i+1, err)
+		}
+		if !bytes.Equal(data, body) {
+			t.Errorf("Test %d: decompressed body does not match", i+1)
+		}
+	}
+
+	// Short and non XML/JSON responses are sent as is.
+	for _, handler := range []http.HandlerFunc{
+		func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set(xhttp.ContentType, "application/xml")
+			w.Header().Set(xhttp.ContentLength, "2")
+			w.Write([]byte("<>"))
+		},
+		func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set(xhttp.ContentType, "application/octet-stream")
+			w.Write(body)
+		},
+	} {
+		w := httptest.NewRecorder()
+		r := httptest.NewRequest(http.MethodGet, "http://localhost:9000/bucket", nil)
+		r.Header.Set(xhttp.AcceptEncoding, "gzip")
+		setResponseCompressionHandler(handler).ServeHTTP(w, r)
+		if w.Header().Get(xhttp.ContentEncoding) != "" {
+			t.Errorf("Expected an uncompressed response, got headers %v", w.Header())
+		}
+	}
+}
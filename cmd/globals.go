@@ -78,8 +78,6 @@ const (
 	// date and server date during signature verification.
 	globalMaxSkewTime = 15 * time.Minute // 15 minutes skew allowed.
 
-	// GlobalMultipartExpiry - Expiry duration after which the multipart uploads are deemed stale.
-	GlobalMultipartExpiry = time.Hour * 24 * 3 // 3 days.
 	// GlobalMultipartCleanupInterval - Cleanup interval when the stale multipart cleanup is initiated.
 	GlobalMultipartCleanupInterval = time.Hour * 24 // 24 hrs.
 
@@ -88,6 +86,16 @@ const (
 
 	// Refresh interval to update in-memory bucket lifecycle cache.
 	globalRefreshBucketLifecycleInterval = 5 * time.Minute
+	// Refresh interval to update in-memory bucket logging cache.
+	globalRefreshBucketLoggingInterval = 5 * time.Minute
+	// Interval at which batched access log records are written to target buckets.
+	globalBucketLoggingFlushInterval = 5 * time.Minute
+	// Refresh interval to update in-memory bucket CORS cache.
+	globalRefreshBucketCORSInterval = 5 * time.Minute
+	// Refresh interval to update in-memory bucket website cache.
+	globalRefreshBucketWebsiteInterval = 5 * time.Minute
+	// Refresh interval to update in-memory bucket snapshot cache.
+	globalRefreshBucketSnapshotInterval = 5 * time.Minute
 	// Refresh interval to update in-memory iam config cache.
 	globalRefreshIAMInterval = 5 * time.Minute
 
@@ -103,6 +111,10 @@ var globalCLIContext = struct {
 }{}
 
 var (
+	// GlobalMultipartExpiry - Expiry duration after which the multipart uploads are deemed stale,
+	// set by MINIO_MULTIPART_EXPIRY.
+	GlobalMultipartExpiry = time.Hour * 24 * 3 // 3 days.
+
 	// Indicates the total number of erasure coded sets configured.
 	globalXLSetCount int
 
@@ -121,6 +133,16 @@ var (
 	// Name of gateway server, e.g S3, GCS, Azure, etc
 	globalGatewayName = ""
 
+	// Gateway backend circuit breaker, nil when disabled or not in gateway mode.
+	globalGatewayCircuitBreaker          *circuitBreaker
+	globalGatewayCircuitBreakerThreshold = defaultCircuitBreakerThreshold
+	globalGatewayCircuitBreakerCoolDown  = defaultCircuitBreakerCoolDown
+
+	// Endpoints of the read replicas of the gateway backend, set
+	// through MINIO_GATEWAY_READ_REPLICAS.
+	globalGatewayReadReplicaEndpoints []string
+	globalGatewayReadReplicas         []*readReplica
+
 	// This flag is set to 'true' by default
 	globalIsBrowserEnabled = true
 
@@ -133,6 +155,10 @@ var (
 	// This flag is set to 'true' when MINIO_REGION env is set.
 	globalIsEnvRegion = false
 
+	// Checks for new releases shown in the browser, nil unless enabled
+	// through MINIO_BROWSER_UPDATE_CHECK.
+	globalUpdateChecker *updateChecker
+
 	// This flag is set to 'true' when MINIO_UPDATE env is set to 'off'. Default is false.
 	globalInplaceUpdateDisabled = false
 
@@ -158,13 +184,34 @@ var (
 
 	globalLifecycleSys *LifecycleSys
 
+	globalBucketLoggingSys *BucketLoggingSys
+
+	globalBucketCORSSys *BucketCORSSys
+
+	globalBucketWebsiteSys *BucketWebsiteSys
+
+	globalBucketModeSys *BucketModeSys
+
+	globalBucketKeyNormalizationSys *BucketKeyNormalizationSys
+
+	globalBucketSnapshotSys *BucketSnapshotSys
+
+	globalBandwidthSys *BandwidthSys
+
 	// CA root certificates, a nil value means system certs pool will be used
 	globalRootCAs *x509.CertPool
 
 	// IsSSL indicates if the server is configured with SSL.
 	globalIsSSL bool
 
-	globalTLSCerts *certs.Certs
+	globalTLSCerts *certs.Manager
+
+	// Domains whose certificates are obtained through ACME
+	globalACMEDomains []string
+	// Contact email of the ACME account
+	globalACMEEmail string
+	// Directory URL of the ACME CA, Let's Encrypt if empty
+	globalACMEDirectory string
 
 	globalHTTPServer        *xhttp.Server
 	globalHTTPServerErrorCh = make(chan error)
@@ -174,6 +221,10 @@ var (
 	// registered listeners
 	globalHTTPTrace = pubsub.New()
 
+	// global disk cache eviction and purge events sent to
+	// registered listeners
+	globalCacheEvents = pubsub.New()
+
 	globalEndpoints EndpointList
 
 	// Global server's network statistics
@@ -182,15 +233,30 @@ var (
 	// Global HTTP request statisitics
 	globalHTTPStats = newHTTPStats()
 
+	// Global registry of in-flight S3 API requests
+	globalRequestRegistry = newRequestRegistry()
+
+	// Authenticated requests made with each access key
+	globalAccessKeyUsage = newAccessKeyUsage()
+
+	// Liveness of the peers of the server
+	globalPeerMembership = newPeerMembership()
+
 	// Time when object layer was initialized on start up.
 	globalBootTime time.Time
 
 	globalActiveCred  auth.Credentials
 	globalPublicCerts []*x509.Certificate
 
+	// Duration for which URLs presigned with the previous root credential
+	// remain valid after the credentials are changed, zero disables it.
+	globalCredentialGracePeriod time.Duration
+
 	globalDomainNames []string      // Root domains for virtual host style requests
 	globalDomainIPs   set.StringSet // Root domain IP address(s) for a distributed MinIO deployment
 
+	globalWebsiteDomainNames []string // Root domains of the bucket website endpoints
+
 	globalListingTimeout   = newDynamicTimeout( /*30*/ 600*time.Second /*5*/, 600*time.Second) // timeout for listing related ops
 	globalObjectTimeout    = newDynamicTimeout( /*1*/ 10*time.Minute /*10*/, 600*time.Second)  // timeout for Object API related ops
 	globalOperationTimeout = newDynamicTimeout(10*time.Minute /*30*/, 600*time.Second)         // default timeout for general ops
@@ -203,11 +269,31 @@ var (
 	globalRRStorageClass storageClass
 	// Set to store standard storage class
 	globalStandardStorageClass storageClass
+	// Set to store object size based storage classes, sorted by size
+	globalSizeStorageClass []sizeStorageClass
 
 	globalIsEnvWORM bool
 	// Is worm enabled
 	globalWORMEnabled bool
 
+	// Are audit log entries sent to the HTTP audit target hash chained
+	globalAuditChainEnabled bool
+	// Interval at which the heads of the audit log chains are published
+	globalAuditAnchorInterval = time.Hour
+
+	// Profiles the server under high load, nil if not enabled
+	globalProfilingWatchdog *profilingWatchdog
+
+	// Dedicated keys of web tokens, web tokens are signed with
+	// the secret key of the user if not set
+	globalJWTKeys *jwtKeys
+
+	// Is content addressed dedup enabled for FS mode
+	globalFSDedupEnabled bool
+
+	// Is the object name search index built by the usage crawler
+	globalSearchIndexEnabled bool
+
 	// Is Disk Caching set up
 	globalIsDiskCacheEnabled bool
 
@@ -217,6 +303,21 @@ var (
 	// Disk cache excludes
 	globalCacheExcludes []string
 
+	// Disk cache bucket to drive affinity rules
+	globalCacheAffinity map[string][]string
+
+	// Disk cache admission policies of storage classes
+	globalCacheStorageClass map[string]string
+
+	// Disk cache quotas of buckets in percentage of the cache space
+	globalCacheQuota map[string]int
+
+	// Disk cache parity shards of objects striped across cache drives
+	globalCacheParity int
+
+	// Disk cache rules routing objects to cache drives
+	globalCacheRouting []CacheRoutingRule
+
 	// Disk cache expiry
 	globalCacheExpiry = 90
 	// Max allowed disk cache percentage
@@ -254,6 +355,15 @@ var (
 	globalCompressExtensions = []string{".txt", ".log", ".csv", ".json"}
 	globalCompressMimeTypes  = []string{"text/csv", "text/plain", "application/json"}
 
+	// Is the compression algorithm set through the environment.
+	globalIsEnvCompressAlgorithm bool
+
+	// Default compression algorithm, compression rules and the zstd
+	// dictionaries they reference.
+	globalCompressAlgorithm    = compressionAlgorithmV1
+	globalCompressRules        []compressionRule
+	globalCompressDictionaries map[string][]byte
+
 	// Some standard object extensions which we strictly dis-allow for compression.
 	standardExcludeCompressExtensions = []string{".gz", ".bz2", ".rar", ".zip", ".7z"}
 
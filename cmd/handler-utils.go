@@ -348,6 +348,20 @@ func httpTraceHdrs(f http.HandlerFunc) http.HandlerFunc {
 	}
 }
 
+// collectAPIStats - records the latency and the in-flight
+// requests of the S3 API served by f.
+func collectAPIStats(api string, f http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		stats := globalHTTPStats.apiStats(api)
+		stats.InFlight.Inc()
+		defer stats.InFlight.Dec()
+
+		tBefore := UTCNow()
+		f.ServeHTTP(w, r)
+		stats.observe(UTCNow().Sub(tBefore).Seconds())
+	}
+}
+
 // Returns "/bucketName/objectName" for path-style or virtual-host-style requests.
 func getResource(path string, host string, domains []string) (string, error) {
 	if len(domains) == 0 {
@@ -375,6 +389,20 @@ func getResource(path string, host string, domains []string) (string, error) {
 	return path, nil
 }
 
+// getWebsiteBucket - returns the bucket of a request made to the
+// website endpoint of a bucket, i.e. bucket.websitedomain.com
+func getWebsiteBucket(host string) (string, bool) {
+	if h, _, err := net.SplitHostPort(host); err == nil {
+		host = h
+	}
+	for _, domain := range globalWebsiteDomainNames {
+		if bucket := strings.TrimSuffix(host, "."+domain); bucket != host && bucket != "" {
+			return bucket, true
+		}
+	}
+	return "", false
+}
+
 // If none of the http routes match respond with MethodNotAllowed, in JSON
 func notFoundHandlerJSON(w http.ResponseWriter, r *http.Request) {
 	writeErrorResponseJSON(context.Background(), w, errorCodes.ToAPIErr(ErrMethodNotAllowed), r.URL)
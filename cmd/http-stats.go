@@ -19,6 +19,8 @@ package cmd
 import (
 	"fmt"
 	"net/http"
+	"sort"
+	"sync"
 	"time"
 
 	"github.com/prometheus/client_golang/prometheus"
@@ -73,6 +75,77 @@ type HTTPMethodStats struct {
 	Duration atomic.Float64
 }
 
+// Upper bounds in seconds of the latency buckets of S3 API requests,
+// latency percentiles are interpolated within these buckets.
+var apiLatencyBuckets = [...]float64{
+	.001, .0025, .005, .01, .025, .05, .1, .25, .5, 1, 2.5, 5, 10, 30, 60,
+}
+
+// HTTPAPIStats holds the latency distribution and the number
+// of in-flight requests of an S3 API
+type HTTPAPIStats struct {
+	InFlight atomic.Int64
+	Counter  atomic.Uint64
+	Duration atomic.Float64
+	// Number of requests per latency bucket, the last bucket
+	// counts requests slower than all apiLatencyBuckets.
+	Buckets [len(apiLatencyBuckets) + 1]atomic.Uint64
+}
+
+// observe - records a request which took durationSecs.
+func (s *HTTPAPIStats) observe(durationSecs float64) {
+	s.Counter.Inc()
+	s.Duration.Add(durationSecs)
+	i := sort.SearchFloat64s(apiLatencyBuckets[:], durationSecs)
+	s.Buckets[i].Inc()
+}
+
+// percentile - returns the estimated latency in seconds under which
+// the fraction p of the requests completed, interpolated linearly
+// within the latency bucket of the percentile.
+func (s *HTTPAPIStats) percentile(p float64) float64 {
+	var counts [len(apiLatencyBuckets) + 1]uint64
+	var total uint64
+	for i := range s.Buckets {
+		counts[i] = s.Buckets[i].Load()
+		total += counts[i]
+	}
+	if total == 0 {
+		return 0
+	}
+	rank := p * float64(total)
+	var cumulative float64
+	for i, count := range counts {
+		if count == 0 || cumulative+float64(count) < rank {
+			cumulative += float64(count)
+			continue
+		}
+		if i == len(apiLatencyBuckets) {
+			break
+		}
+		var lower float64
+		if i > 0 {
+			lower = apiLatencyBuckets[i-1]
+		}
+		return lower + (apiLatencyBuckets[i]-lower)*(rank-cumulative)/float64(count)
+	}
+	return apiLatencyBuckets[len(apiLatencyBuckets)-1]
+}
+
+// toServerHTTPAPIStats - converts the stats of an S3 API into the
+// struct sent back to the client.
+func (s *HTTPAPIStats) toServerHTTPAPIStats() ServerHTTPAPIStats {
+	count := s.Counter.Load()
+	return ServerHTTPAPIStats{
+		Count:       count,
+		InFlight:    s.InFlight.Load(),
+		AvgDuration: durationStr(s.Duration.Load(), float64(count)),
+		P50Duration: fmt.Sprint(time.Duration(s.percentile(0.50) * float64(time.Second))),
+		P95Duration: fmt.Sprint(time.Duration(s.percentile(0.95) * float64(time.Second))),
+		P99Duration: fmt.Sprint(time.Duration(s.percentile(0.99) * float64(time.Second))),
+	}
+}
+
 // HTTPStats holds statistics information about
 // HTTP requests made by all clients
 type HTTPStats struct {
@@ -95,6 +168,54 @@ type HTTPStats struct {
 	// DELETE request stats.
 	totalDELETEs   HTTPMethodStats
 	successDELETEs HTTPMethodStats
+
+	// S3 API request stats keyed by API name.
+	apis   map[string]*HTTPAPIStats
+	apisMu sync.RWMutex
+}
+
+// apiStats - returns the stats of the S3 API, created on first use.
+func (st *HTTPStats) apiStats(api string) *HTTPAPIStats {
+	st.apisMu.RLock()
+	s, ok := st.apis[api]
+	st.apisMu.RUnlock()
+	if ok {
+		return s
+	}
+
+	st.apisMu.Lock()
+	defer st.apisMu.Unlock()
+	if s, ok = st.apis[api]; !ok {
+		if st.apis == nil {
+			st.apis = make(map[string]*HTTPAPIStats)
+		}
+		s = &HTTPAPIStats{}
+		st.apis[api] = s
+	}
+	return s
+}
+
+// listAPIStats - returns the stats of all S3 APIs called so far,
+// keyed by API name.
+func (st *HTTPStats) listAPIStats() map[string]*HTTPAPIStats {
+	st.apisMu.RLock()
+	defer st.apisMu.RUnlock()
+	apis := make(map[string]*HTTPAPIStats, len(st.apis))
+	for api, s := range st.apis {
+		apis[api] = s
+	}
+	return apis
+}
+
+// toServerHTTPAPIStats - converts the stats of all S3 APIs into
+// the struct sent back to the client.
+func (st *HTTPStats) toServerHTTPAPIStats() map[string]ServerHTTPAPIStats {
+	apis := st.listAPIStats()
+	stats := make(map[string]ServerHTTPAPIStats, len(apis))
+	for api, s := range apis {
+		stats[api] = s.toServerHTTPAPIStats()
+	}
+	return stats
 }
 
 func durationStr(totalDuration, totalCount float64) string {
@@ -102,7 +223,7 @@ func durationStr(totalDuration, totalCount float64) string {
 }
 
 // Converts http stats into struct to be sent back to the client.
-func (st HTTPStats) toServerHTTPStats() ServerHTTPStats {
+func (st *HTTPStats) toServerHTTPStats() ServerHTTPStats {
 	serverStats := ServerHTTPStats{}
 	serverStats.TotalHEADStats = ServerHTTPMethodStats{
 		Count:       st.totalHEADs.Counter.Load(),
@@ -144,6 +265,7 @@ func (st HTTPStats) toServerHTTPStats() ServerHTTPStats {
 		Count:       st.successDELETEs.Counter.Load(),
 		AvgDuration: durationStr(st.successDELETEs.Duration.Load(), float64(st.successDELETEs.Counter.Load())),
 	}
+	serverStats.APIStats = st.toServerHTTPAPIStats()
 	return serverStats
 }
 
@@ -0,0 +1,84 @@
+/*
+ * MinIO Cloud Storage, (C) 2020 MinIO, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"math"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestHTTPAPIStatsPercentile(t *testing.T) {
+	var stats HTTPAPIStats
+	if p := stats.percentile(0.5); p != 0 {
+		t.Fatalf("Expected no latency without requests, got %v", p)
+	}
+
+	// 90 requests of at most 10ms, 9 of at most 1s and a request
+	// slower than all latency buckets.
+	for i := 0; i < 90; i++ {
+		stats.observe(0.007)
+	}
+	for i := 0; i < 9; i++ {
+		stats.observe(0.7)
+	}
+	stats.observe(120)
+
+	testCases := []struct {
+		p        float64
+		expected float64
+	}{
+		// Half of the requests of the (5ms, 10ms] bucket.
+		{0.45, 0.0075},
+		{0.90, 0.01},
+		// Two thirds of the requests of the (500ms, 1s] bucket.
+		{0.96, 0.5 + 0.5*6/9},
+		{1, 60},
+	}
+	for i, testCase := range testCases {
+		if p := stats.percentile(testCase.p); math.Abs(p-testCase.expected) > 1e-9 {
+			t.Errorf("Test %d: expected %v, got %v", i+1, testCase.expected, p)
+		}
+	}
+	if count := stats.Counter.Load(); count != 100 {
+		t.Errorf("Expected 100 requests, got %d", count)
+	}
+}
+
+func TestCollectAPIStats(t *testing.T) {
+	st := newHTTPStats()
+	globalHTTPStats, st = st, globalHTTPStats
+	defer func() { globalHTTPStats = st }()
+
+	var inFlight int64
+	handler := collectAPIStats("TestAPI", func(w http.ResponseWriter, r *http.Request) {
+		inFlight = globalHTTPStats.apiStats("TestAPI").InFlight.Load()
+	})
+	handler(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/bucket/object", nil))
+
+	if inFlight != 1 {
+		t.Errorf("Expected 1 request in flight, got %d", inFlight)
+	}
+	stats, ok := globalHTTPStats.toServerHTTPStats().APIStats["TestAPI"]
+	if !ok {
+		t.Fatal("Expected stats of TestAPI")
+	}
+	if stats.Count != 1 || stats.InFlight != 0 {
+		t.Errorf("Unexpected stats %v", stats)
+	}
+}
@@ -24,6 +24,7 @@ const (
 	ContentType        = "Content-Type"
 	ContentMD5         = "Content-Md5"
 	ContentEncoding    = "Content-Encoding"
+	AcceptEncoding     = "Accept-Encoding"
 	Expires            = "Expires"
 	ContentLength      = "Content-Length"
 	ContentLanguage    = "Content-Language"
@@ -46,6 +47,8 @@ const (
 	IfUnmodifiedSince = "If-Unmodified-Since"
 	IfMatch           = "If-Match"
 	IfNoneMatch       = "If-None-Match"
+	IfRange           = "If-Range"
+	Range             = "Range"
 
 	// S3 extensions
 	AmzCopySourceIfModifiedSince   = "x-amz-copy-source-if-modified-since"
@@ -78,4 +81,26 @@ const (
 
 	// Deployment id.
 	MinioDeploymentID = "x-minio-deployment-id"
+
+	// Append to the object instead of replacing it, MinIO extension.
+	MinioAppend = "x-minio-append"
+
+	// Per request cache control of the disk cache, either no-cache
+	// or only-if-cached, MinIO extension.
+	MinioCacheControl = "x-minio-cache-control"
+)
+
+// Standard CORS headers
+const (
+	Origin = "Origin"
+	Vary   = "Vary"
+
+	AccessControlRequestMethod    = "Access-Control-Request-Method"
+	AccessControlRequestHeaders   = "Access-Control-Request-Headers"
+	AccessControlAllowOrigin      = "Access-Control-Allow-Origin"
+	AccessControlAllowMethods     = "Access-Control-Allow-Methods"
+	AccessControlAllowHeaders     = "Access-Control-Allow-Headers"
+	AccessControlAllowCredentials = "Access-Control-Allow-Credentials"
+	AccessControlExposeHeaders    = "Access-Control-Expose-Headers"
+	AccessControlMaxAge           = "Access-Control-Max-Age"
 )
@@ -161,3 +161,31 @@ func parseRequestRangeSpec(rangeString string) (hrange *HTTPRangeSpec, err error
 		return nil, fmt.Errorf("'%s' does not have valid range value", rangeString)
 	}
 }
+
+// maxRequestRanges is the maximum number of byte ranges served in a
+// single multipart/byteranges response, requests with more ranges
+// are treated as regular Get requests.
+const maxRequestRanges = 32
+
+// Parses a range header value which may hold several comma separated
+// byte ranges, eg. "bytes=0-99,200-299,-50".
+func parseRequestRangeSpecs(rangeString string) (ranges []*HTTPRangeSpec, err error) {
+	// Return error if given range string doesn't start with byte range prefix.
+	if !strings.HasPrefix(rangeString, byteRangePrefix) {
+		return nil, fmt.Errorf("'%s' does not start with '%s'", rangeString, byteRangePrefix)
+	}
+
+	specs := strings.Split(strings.TrimPrefix(rangeString, byteRangePrefix), ",")
+	if len(specs) > maxRequestRanges {
+		return nil, fmt.Errorf("'%s' has more than %d ranges", rangeString, maxRequestRanges)
+	}
+
+	for _, spec := range specs {
+		rs, err := parseRequestRangeSpec(byteRangePrefix + strings.TrimSpace(spec))
+		if err != nil {
+			return nil, err
+		}
+		ranges = append(ranges, rs)
+	}
+	return ranges, nil
+}
@@ -17,6 +17,7 @@
 package cmd
 
 import (
+	"strings"
 	"testing"
 )
 
@@ -100,3 +101,53 @@ func TestHTTPRequestRangeSpec(t *testing.T) {
 		t.Errorf("Case %d: Expected errInvalidRange but: %v %v %d %d %v", i, rs, err1, o, l, err2)
 	}
 }
+
+func TestHTTPRequestRangeSpecs(t *testing.T) {
+	resourceSize := int64(10)
+	validRangeSpecs := []struct {
+		spec       string
+		expOffsets []int64
+		expLengths []int64
+	}{
+		{"bytes=0-", []int64{0}, []int64{10}},
+		{"bytes=0-1,5-", []int64{0, 5}, []int64{2, 5}},
+		{"bytes=0-1, 4-5, -2", []int64{0, 4, 8}, []int64{2, 2, 2}},
+		{"bytes=5-9,0-4", []int64{5, 0}, []int64{5, 5}},
+	}
+	for i, testCase := range validRangeSpecs {
+		ranges, err := parseRequestRangeSpecs(testCase.spec)
+		if err != nil {
+			t.Fatalf("Case %d: unexpected err: %v", i, err)
+		}
+		if len(ranges) != len(testCase.expOffsets) {
+			t.Fatalf("Case %d: expected %d ranges, got %d", i, len(testCase.expOffsets), len(ranges))
+		}
+		for j, rs := range ranges {
+			o, l, err := rs.GetOffsetLength(resourceSize)
+			if err != nil {
+				t.Errorf("Case %d: unexpected err: %v", i, err)
+			}
+			if o != testCase.expOffsets[j] || l != testCase.expLengths[j] {
+				t.Errorf("Case %d: got bad offset/length: %d,%d expected: %d,%d",
+					i, o, l, testCase.expOffsets[j], testCase.expLengths[j])
+			}
+		}
+	}
+
+	unparsableRangeSpecs := []string{
+		"bytes=0-1,",
+		"bytes=0-1,aa",
+		"bytes=,0-1",
+		"0-1,2-3",
+		"bytes=0-0" + strings.Repeat(",0-0", maxRequestRanges),
+	}
+	for i, urs := range unparsableRangeSpecs {
+		if _, err := parseRequestRangeSpecs(urs); err == nil || err == errInvalidRange {
+			t.Errorf("Case %d: Did not get an expected error - got %v", i, err)
+		}
+	}
+
+	if _, err := parseRequestRangeSpecs("bytes=0-1,5-2"); err != errInvalidRange {
+		t.Errorf("Expected errInvalidRange, got %v", err)
+	}
+}
@@ -0,0 +1,226 @@
+/*
+ * MinIO Cloud Storage, (C) 2020 MinIO, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"sync"
+)
+
+// Kinds of IAM entries tracked by the IAM change log.
+const (
+	iamChangeUser          = "user"
+	iamChangePolicy        = "policy"
+	iamChangeGroup         = "group"
+	iamChangePolicyMapping = "policy-mapping"
+)
+
+// Maximum number of IAM changes remembered by a server, peers which
+// fall further behind reload all of IAM.
+const maxIAMChanges = 10000
+
+// iamChange - an IAM entry changed on a server, the entry itself is
+// reloaded from the backend by the peers.
+type iamChange struct {
+	Version uint64
+	Kind    string
+	Name    string
+	IsSTS   bool
+	IsGroup bool
+}
+
+// iamChanges - the IAM changes made on a server since a version.
+type iamChanges struct {
+	// Epoch changes on every restart of the server, versions
+	// of different epochs are not comparable.
+	Epoch   string
+	Version uint64
+	// Full is set when the changes since the version are no
+	// longer known, all of IAM needs to be reloaded.
+	Full    bool
+	Changes []iamChange
+}
+
+// iamChangeLog - numbers the IAM changes made on this server and
+// remembers the latest ones for the peers.
+type iamChangeLog struct {
+	sync.Mutex
+	epoch   string
+	version uint64
+	// Version of the latest change dropped from changes.
+	dropped uint64
+	changes []iamChange
+}
+
+func newIAMChangeLog() *iamChangeLog {
+	return &iamChangeLog{epoch: mustGetUUID()}
+}
+
+// add - records a change of an IAM entry.
+func (l *iamChangeLog) add(kind, name string, isSTS, isGroup bool) {
+	l.Lock()
+	defer l.Unlock()
+
+	l.version++
+	l.changes = append(l.changes, iamChange{
+		Version: l.version,
+		Kind:    kind,
+		Name:    name,
+		IsSTS:   isSTS,
+		IsGroup: isGroup,
+	})
+	if len(l.changes) > maxIAMChanges {
+		n := len(l.changes) - maxIAMChanges
+		l.dropped = l.changes[n-1].Version
+		l.changes = append([]iamChange(nil), l.changes[n:]...)
+	}
+}
+
+// since - returns the changes made after version of epoch, an entry
+// changed several times is only returned once.
+func (l *iamChangeLog) since(epoch string, version uint64) iamChanges {
+	l.Lock()
+	defer l.Unlock()
+
+	changes := iamChanges{Epoch: l.epoch, Version: l.version}
+	if epoch != l.epoch || version < l.dropped || version > l.version {
+		changes.Full = true
+		return changes
+	}
+
+	seen := make(map[iamChange]bool)
+	for i := len(l.changes) - 1; i >= 0 && l.changes[i].Version > version; i-- {
+		change := l.changes[i]
+		key := change
+		key.Version = 0
+		if seen[key] {
+			continue
+		}
+		seen[key] = true
+		changes.Changes = append(changes.Changes, change)
+	}
+	// Return the changes in the order they were made.
+	for i, j := 0, len(changes.Changes)-1; i < j; i, j = i+1, j-1 {
+		changes.Changes[i], changes.Changes[j] = changes.Changes[j], changes.Changes[i]
+	}
+	return changes
+}
+
+// recordChange - records a change of an IAM entry made on this server.
+func (sys *IAMSys) recordChange(kind, name string, isSTS, isGroup bool) {
+	if sys.changes != nil {
+		sys.changes.add(kind, name, isSTS, isGroup)
+	}
+}
+
+// IAMChangesSince - returns the IAM changes made on this server after
+// version of epoch.
+func (sys *IAMSys) IAMChangesSince(epoch string, version uint64) iamChanges {
+	if sys.changes == nil {
+		return iamChanges{Full: true}
+	}
+	return sys.changes.since(epoch, version)
+}
+
+// reloadChange - reloads a changed IAM entry from the backend, entries
+// no longer found are removed.
+func (sys *IAMSys) reloadChange(objAPI ObjectLayer, change iamChange) error {
+	if change.Kind == iamChangeGroup {
+		return sys.LoadGroup(objAPI, change.Name)
+	}
+
+	sys.Lock()
+	defer sys.Unlock()
+
+	var err error
+	switch change.Kind {
+	case iamChangeUser:
+		err = sys.store.loadUser(change.Name, change.IsSTS, sys.iamUsersMap)
+		if err == nil {
+			err = sys.store.loadMappedPolicy(change.Name, change.IsSTS, false, sys.iamUserPolicyMap)
+			if err == errConfigNotFound {
+				delete(sys.iamUserPolicyMap, change.Name)
+				err = nil
+			}
+		} else if err == errConfigNotFound {
+			delete(sys.iamUsersMap, change.Name)
+			delete(sys.iamUserPolicyMap, change.Name)
+			err = nil
+		}
+	case iamChangePolicy:
+		err = sys.store.loadPolicyDoc(change.Name, sys.iamPolicyDocsMap)
+		if err == errConfigNotFound {
+			delete(sys.iamPolicyDocsMap, change.Name)
+			err = nil
+		}
+	case iamChangePolicyMapping:
+		m := sys.iamUserPolicyMap
+		if change.IsGroup {
+			m = sys.iamGroupPolicyMap
+		}
+		err = sys.store.loadMappedPolicy(change.Name, change.IsSTS, change.IsGroup, m)
+		if err == errConfigNotFound {
+			delete(m, change.Name)
+			err = nil
+		}
+	}
+	return err
+}
+
+// refreshFromPeers - brings IAM up to date with the changes made on the
+// peers since the last refresh, only the changed entries are reloaded
+// from the backend. All of IAM is reloaded with reloadAll instead on
+// the first refresh and when the changes of a peer are no longer known.
+// Unreachable peers are caught up with on a later refresh.
+func (sys *IAMSys) refreshFromPeers(objAPI ObjectLayer, reloadAll func() error) error {
+	if globalNotificationSys == nil || objAPI == nil {
+		return reloadAll()
+	}
+
+	sys.peerVersionsMu.Lock()
+	defer sys.peerVersionsMu.Unlock()
+
+	peerChanges := globalNotificationSys.GetIAMChanges(sys.peerVersions)
+
+	full := sys.peerVersions == nil
+	for _, changes := range peerChanges {
+		if changes.Full {
+			full = true
+		}
+	}
+
+	if full {
+		if err := reloadAll(); err != nil {
+			return err
+		}
+	} else {
+		for _, changes := range peerChanges {
+			for _, change := range changes.Changes {
+				if err := sys.reloadChange(objAPI, change); err != nil {
+					return err
+				}
+			}
+		}
+	}
+
+	if sys.peerVersions == nil {
+		sys.peerVersions = make(map[string]iamChanges)
+	}
+	for peer, changes := range peerChanges {
+		sys.peerVersions[peer] = iamChanges{Epoch: changes.Epoch, Version: changes.Version}
+	}
+	return nil
+}
@@ -0,0 +1,65 @@
+/*
+ * MinIO Cloud Storage, (C) 2020 MinIO, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestIAMChangeLog(t *testing.T) {
+	l := newIAMChangeLog()
+
+	// Peers without a version of this epoch reload everything.
+	changes := l.since("", 0)
+	if !changes.Full || changes.Epoch != l.epoch {
+		t.Fatalf("Expected a full reload, got %v", changes)
+	}
+
+	l.add(iamChangeUser, "alice", false, false)
+	l.add(iamChangePolicy, "readonly-alice", false, false)
+	l.add(iamChangePolicyMapping, "alice", false, false)
+	l.add(iamChangeUser, "alice", false, false)
+	l.add(iamChangeGroup, "admins", false, true)
+
+	changes = l.since(l.epoch, 1)
+	expected := []iamChange{
+		{Version: 2, Kind: iamChangePolicy, Name: "readonly-alice"},
+		{Version: 3, Kind: iamChangePolicyMapping, Name: "alice"},
+		{Version: 4, Kind: iamChangeUser, Name: "alice"},
+		{Version: 5, Kind: iamChangeGroup, Name: "admins", IsGroup: true},
+	}
+	if changes.Full || changes.Version != 5 || !reflect.DeepEqual(changes.Changes, expected) {
+		t.Fatalf("Expected changes %v, got %v", expected, changes)
+	}
+
+	if changes = l.since(l.epoch, 5); changes.Full || len(changes.Changes) != 0 {
+		t.Fatalf("Expected no changes, got %v", changes)
+	}
+
+	// Changes no longer remembered require a full reload.
+	for i := 0; i < maxIAMChanges; i++ {
+		l.add(iamChangeUser, "bob", true, false)
+	}
+	if changes = l.since(l.epoch, 1); !changes.Full {
+		t.Fatalf("Expected a full reload, got %v", changes)
+	}
+	changes = l.since(l.epoch, 5)
+	if changes.Full || len(changes.Changes) != 1 || changes.Changes[0].Name != "bob" {
+		t.Fatalf("Unexpected changes %v", changes)
+	}
+}
@@ -0,0 +1,321 @@
+/*
+ * MinIO Cloud Storage, (C) 2019 MinIO, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/json"
+	"errors"
+	"io"
+	"io/ioutil"
+	"sync"
+
+	"github.com/minio/minio/cmd/crypto"
+	"github.com/minio/minio/pkg/auth"
+	"github.com/minio/sio"
+)
+
+// IAM config is encrypted at rest with a random data key. The data
+// key is stored in the IAM format file, sealed either by the KMS or
+// by a key derived from the root credentials, so that changing the
+// root credentials only reseals the data key instead of rewriting
+// every IAM config item.
+
+var (
+	errIAMDataKeyUnseal      = errors.New("unable to unseal IAM data key, root credentials changed without resealing it")
+	errIAMConfigNotEncrypted = errors.New("IAM config is not encrypted")
+)
+
+// iamDataKey - sealed IAM data key as stored in the IAM format file.
+type iamDataKey struct {
+	// KMS master key the data key is sealed with, empty if
+	// the data key is sealed with the root credentials.
+	KMSKeyID  string `json:"kmsKeyID,omitempty"`
+	SealedKey []byte `json:"sealedKey"`
+
+	// Data key sealed with the previous root credentials, kept
+	// until the new credentials are known to be in effect.
+	PrevSealedKey []byte `json:"prevSealedKey,omitempty"`
+}
+
+// iamConfigKey - holds the unsealed IAM data key.
+type iamConfigKey struct {
+	sync.RWMutex
+	key []byte
+}
+
+// Get - returns the data key, nil if IAM config is not encrypted yet.
+func (k *iamConfigKey) Get() []byte {
+	k.RLock()
+	defer k.RUnlock()
+	return k.key
+}
+
+// Set - sets the data key.
+func (k *iamConfigKey) Set(key []byte) {
+	k.Lock()
+	defer k.Unlock()
+	k.key = key
+}
+
+var globalIAMConfigKey = &iamConfigKey{}
+
+// iamDataKeyContext - binds KMS sealed IAM data keys to the IAM config.
+var iamDataKeyContext = crypto.Context{minioMetaBucket: iamConfigPrefix}
+
+// getIAMSealingKey - derives the key sealing the IAM data key from
+// the root credentials, ok is false if cred is not valid.
+func getIAMSealingKey(cred auth.Credentials) (key []byte, ok bool) {
+	if !cred.IsValid() {
+		return nil, false
+	}
+	mac := hmac.New(sha256.New, []byte(cred.SecretKey))
+	mac.Write([]byte(iamConfigPrefix))
+	return mac.Sum(nil), true
+}
+
+// newIAMDataKey - generates a new IAM data key, sealed by the KMS if
+// one is configured or else by the root credentials.
+func newIAMDataKey(cred auth.Credentials) (key []byte, dataKey iamDataKey, err error) {
+	if GlobalKMS != nil {
+		plainKey, sealedKey, err := GlobalKMS.GenerateKey(globalKMSKeyID, iamDataKeyContext)
+		if err != nil {
+			return nil, dataKey, err
+		}
+		return plainKey[:], iamDataKey{KMSKeyID: globalKMSKeyID, SealedKey: sealedKey}, nil
+	}
+
+	key = make([]byte, 32)
+	if _, err = io.ReadFull(rand.Reader, key); err != nil {
+		return nil, dataKey, err
+	}
+	sealedKey, err := sealIAMDataKey(key, cred)
+	if err != nil {
+		return nil, dataKey, err
+	}
+	return key, iamDataKey{SealedKey: sealedKey}, nil
+}
+
+// sealIAMDataKey - seals the data key with the root credentials.
+func sealIAMDataKey(key []byte, cred auth.Credentials) ([]byte, error) {
+	sealingKey, ok := getIAMSealingKey(cred)
+	if !ok {
+		return nil, errInvalidArgument
+	}
+	var buffer bytes.Buffer
+	if _, err := sio.Encrypt(&buffer, bytes.NewReader(key), sio.Config{Key: sealingKey, MinVersion: sio.Version20}); err != nil {
+		return nil, err
+	}
+	return buffer.Bytes(), nil
+}
+
+// unsealIAMDataKey - unseals the data key with the first of creds
+// able to do so, returns the credentials which unsealed the current
+// sealed key or an invalid credential if only the previous one could
+// be unsealed.
+func unsealIAMDataKey(dataKey iamDataKey, creds ...auth.Credentials) (key []byte, cred auth.Credentials, err error) {
+	if dataKey.KMSKeyID != "" {
+		if GlobalKMS == nil {
+			return nil, cred, errors.New("IAM data key is sealed by KMS but no KMS is configured")
+		}
+		plainKey, err := GlobalKMS.UnsealKey(dataKey.KMSKeyID, dataKey.SealedKey, iamDataKeyContext)
+		if err != nil {
+			return nil, cred, err
+		}
+		return plainKey[:], cred, nil
+	}
+
+	for _, sealedKey := range [][]byte{dataKey.SealedKey, dataKey.PrevSealedKey} {
+		if len(sealedKey) == 0 {
+			continue
+		}
+		for _, c := range creds {
+			sealingKey, ok := getIAMSealingKey(c)
+			if !ok {
+				continue
+			}
+			var buffer bytes.Buffer
+			if _, err = sio.Decrypt(&buffer, bytes.NewReader(sealedKey), sio.Config{Key: sealingKey, MinVersion: sio.Version20}); err != nil {
+				continue
+			}
+			if bytes.Equal(sealedKey, dataKey.SealedKey) {
+				cred = c
+			}
+			return buffer.Bytes(), cred, nil
+		}
+	}
+	return nil, cred, errIAMDataKeyUnseal
+}
+
+// initIAMConfigKey - loads the IAM data key from the format file,
+// a new data key is generated and persisted if there is none yet.
+// The data key is resealed with the active root credentials if it
+// was sealed with the credentials stored before an environment
+// change. Must be called under the IAM config migration lock.
+func initIAMConfigKey(store IAMStorageAPI, objAPI ObjectLayer) error {
+	var iamFmt iamFormat
+	path := getIAMFormatFilePath()
+	if err := store.loadIAMConfig(&iamFmt, path); err != nil {
+		return err
+	}
+
+	if iamFmt.DataKey == nil {
+		key, dataKey, err := newIAMDataKey(globalActiveCred)
+		if err != nil {
+			return err
+		}
+		// Persist the data key before anything is encrypted with it.
+		iamFmt.DataKey = &dataKey
+		if err = store.saveIAMConfig(iamFmt, path); err != nil {
+			return err
+		}
+		globalIAMConfigKey.Set(key)
+		return nil
+	}
+
+	creds := []auth.Credentials{globalActiveCred}
+	if objAPI != nil {
+		// Credentials overridden by the environment are not
+		// persisted, the stored ones may have sealed the key.
+		if srvCfg, err := readServerConfig(context.Background(), objAPI); err == nil {
+			creds = append(creds, srvCfg.Credential)
+		}
+	}
+	key, cred, err := unsealIAMDataKey(*iamFmt.DataKey, creds...)
+	if err != nil {
+		return err
+	}
+	globalIAMConfigKey.Set(key)
+
+	if iamFmt.DataKey.KMSKeyID != "" || (globalActiveCred.Equal(cred) && len(iamFmt.DataKey.PrevSealedKey) == 0) {
+		return nil
+	}
+
+	// Active credentials are in effect, reseal the key with them
+	// and drop the previous sealing.
+	sealedKey, err := sealIAMDataKey(key, globalActiveCred)
+	if err != nil {
+		return err
+	}
+	iamFmt.DataKey = &iamDataKey{SealedKey: sealedKey}
+	return store.saveIAMConfig(iamFmt, path)
+}
+
+// resealIAMConfigKey - reseals the IAM data key with cred, must be
+// called before new root credentials are persisted. The previous
+// sealing is kept so that the data key can still be unsealed if the
+// new credentials never take effect. This is a single write of the
+// IAM format file, IAM config items are not rewritten.
+func resealIAMConfigKey(store IAMStorageAPI, cred auth.Credentials) error {
+	key := globalIAMConfigKey.Get()
+	if key == nil {
+		return nil
+	}
+
+	var iamFmt iamFormat
+	path := getIAMFormatFilePath()
+	if err := store.loadIAMConfig(&iamFmt, path); err != nil {
+		if err == errConfigNotFound {
+			// No data key is persisted yet.
+			return nil
+		}
+		return err
+	}
+	if iamFmt.DataKey == nil || iamFmt.DataKey.KMSKeyID != "" {
+		// Nothing to do.
+		return nil
+	}
+
+	sealedKey, err := sealIAMDataKey(key, cred)
+	if err != nil {
+		return err
+	}
+	iamFmt.DataKey = &iamDataKey{
+		SealedKey:     sealedKey,
+		PrevSealedKey: iamFmt.DataKey.SealedKey,
+	}
+	return store.saveIAMConfig(iamFmt, path)
+}
+
+// rotateIAMConfigKey - re-wraps a KMS sealed IAM data key with the
+// current version of its master key, the data key itself and the IAM
+// config encrypted with it are unchanged.
+func rotateIAMConfigKey(store IAMStorageAPI) error {
+	var iamFmt iamFormat
+	path := getIAMFormatFilePath()
+	if err := store.loadIAMConfig(&iamFmt, path); err != nil {
+		if err == errConfigNotFound {
+			// No data key is persisted yet.
+			return nil
+		}
+		return err
+	}
+	if iamFmt.DataKey == nil || iamFmt.DataKey.KMSKeyID == "" {
+		// Sealed with the root credentials.
+		return nil
+	}
+	if GlobalKMS == nil {
+		return errKMSNotConfigured
+	}
+
+	sealedKey, err := GlobalKMS.UpdateKey(iamFmt.DataKey.KMSKeyID, iamFmt.DataKey.SealedKey, iamDataKeyContext)
+	if err != nil {
+		return err
+	}
+	iamFmt.DataKey = &iamDataKey{KMSKeyID: iamFmt.DataKey.KMSKeyID, SealedKey: sealedKey}
+	return store.saveIAMConfig(iamFmt, path)
+}
+
+// encryptIAMConfig - encrypts IAM config data with the IAM data key,
+// the data is returned as is if the data key is not initialized yet.
+func encryptIAMConfig(data []byte) ([]byte, error) {
+	key := globalIAMConfigKey.Get()
+	if key == nil {
+		return data, nil
+	}
+	reader, err := sio.EncryptReader(bytes.NewReader(data), sio.Config{Key: key, MinVersion: sio.Version20})
+	if err != nil {
+		return nil, err
+	}
+	return ioutil.ReadAll(reader)
+}
+
+// decryptIAMConfig - decrypts IAM config data with the IAM data key.
+// Plaintext JSON written before format version 2 is returned as is
+// only until the data key is loaded by the migration to version 2,
+// which encrypts it, plaintext is rejected from then on.
+func decryptIAMConfig(data []byte) ([]byte, error) {
+	key := globalIAMConfigKey.Get()
+	if key == nil {
+		if json.Valid(data) {
+			return data, nil
+		}
+		return nil, errIAMDataKeyUnseal
+	}
+	if json.Valid(data) {
+		return nil, errIAMConfigNotEncrypted
+	}
+	reader, err := sio.DecryptReader(bytes.NewReader(data), sio.Config{Key: key, MinVersion: sio.Version20})
+	if err != nil {
+		return nil, err
+	}
+	return ioutil.ReadAll(reader)
+}
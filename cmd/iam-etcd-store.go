@@ -109,6 +109,12 @@ func (ies *IAMEtcdStore) saveIAMConfig(item interface{}, path string) error {
 	if err != nil {
 		return err
 	}
+	// The format file holds the sealed data key and is left in plaintext.
+	if path != getIAMFormatFilePath() {
+		if data, err = encryptIAMConfig(data); err != nil {
+			return err
+		}
+	}
 	return saveKeyEtcd(ies.getContext(), ies.client, path, data)
 }
 
@@ -117,6 +123,11 @@ func (ies *IAMEtcdStore) loadIAMConfig(item interface{}, path string) error {
 	if err != nil {
 		return err
 	}
+	if path != getIAMFormatFilePath() {
+		if pdata, err = decryptIAMConfig(pdata); err != nil {
+			return err
+		}
+	}
 	return json.Unmarshal(pdata, item)
 }
 
@@ -244,11 +255,60 @@ func (ies *IAMEtcdStore) migrateToV1() error {
 	return nil
 }
 
+// Encrypts all IAM config keys which are still stored in
+// plaintext, i.e. written before format version 2.
+func (ies *IAMEtcdStore) migrateToV2(objAPI ObjectLayer) error {
+	if err := initIAMConfigKey(ies, objAPI); err != nil {
+		return err
+	}
+
+	var iamFmt iamFormat
+	path := getIAMFormatFilePath()
+	if err := ies.loadIAMConfig(&iamFmt, path); err != nil {
+		return errors.New("corrupt IAM format file")
+	}
+	if iamFmt.Version >= iamFormatVersion2 {
+		// Nothing to do.
+		return nil
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), defaultContextTimeout)
+	defer cancel()
+	r, err := ies.client.Get(ctx, iamConfigPrefix+SlashSeparator, etcd.WithPrefix())
+	if err != nil {
+		return etcdErrToErr(err, ies.client.Endpoints())
+	}
+	for _, kv := range r.Kvs {
+		if string(kv.Key) == path || !json.Valid(kv.Value) {
+			// Format file or already encrypted.
+			continue
+		}
+		data, err := encryptIAMConfig(kv.Value)
+		if err != nil {
+			return err
+		}
+		if err = saveKeyEtcd(ctx, ies.client, string(kv.Key), data); err != nil {
+			return err
+		}
+	}
+
+	// Save iam format to version 2.
+	iamFmt.Version = iamFormatVersion2
+	if err := ies.saveIAMConfig(iamFmt, path); err != nil {
+		logger.LogIf(context.Background(), err)
+		return err
+	}
+	return nil
+}
+
 // Should be called under config migration lock
 func (ies *IAMEtcdStore) migrateBackendFormat(objAPI ObjectLayer) error {
 	if err := ies.migrateToV1(); err != nil {
 		return err
 	}
+	if err := ies.migrateToV2(objAPI); err != nil {
+		return err
+	}
 	return nil
 }
 
@@ -491,30 +551,41 @@ func (ies *IAMEtcdStore) deleteGroupInfo(name string) error {
 
 func (ies *IAMEtcdStore) watch(sys *IAMSys) {
 	watchEtcd := func() {
+		ctx := GlobalContext
+		// Revision of the last processed events, watching is
+		// resumed right after it so that no change is missed.
+		var lastRev int64
 		// Refresh IAMSys with etcd watch.
 		for {
-			watchCh := ies.client.Watch(context.Background(),
-				iamConfigPrefix, etcd.WithPrefix(), etcd.WithKeysOnly())
-			select {
-			case <-GlobalServiceDoneCh:
-				return
-			case watchResp, ok := <-watchCh:
-				if !ok {
-					time.Sleep(1 * time.Second)
-					continue
+			opts := []etcd.OpOption{etcd.WithPrefix(), etcd.WithKeysOnly()}
+			if lastRev > 0 {
+				opts = append(opts, etcd.WithRev(lastRev+1))
+			}
+			watchCtx, cancel := context.WithCancel(ctx)
+			watchCh := ies.client.Watch(watchCtx, iamConfigPrefix, opts...)
+			for watchResp := range watchCh {
+				if watchResp.CompactRevision != 0 {
+					// Changes up to the compacted revision are
+					// lost, resume from the oldest available one.
+					lastRev = watchResp.CompactRevision - 1
 				}
 				if err := watchResp.Err(); err != nil {
-					logger.LogIf(context.Background(), err)
-					// log and retry.
-					time.Sleep(1 * time.Second)
-					continue
+					logger.LogIf(ctx, err)
+					break
 				}
 				for _, event := range watchResp.Events {
+					lastRev = event.Kv.ModRevision
 					sys.Lock()
 					ies.reloadFromEvent(sys, event)
 					sys.Unlock()
 				}
 			}
+			cancel()
+			// Watch channel is closed on errors and on shutdown,
+			// retry after a second unless shutting down.
+			if !sleepContext(ctx, time.Second) {
+				return
+			}
 		}
 	}
 	go watchEtcd()
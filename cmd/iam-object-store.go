@@ -199,6 +199,44 @@ func (iamOS *IAMObjectStore) migrateToV1() error {
 	return nil
 }
 
+// Encrypts all IAM config objects which are still stored in
+// plaintext, i.e. written before format version 2.
+func (iamOS *IAMObjectStore) migrateToV2() error {
+	objAPI := iamOS.getObjectAPI()
+	if err := initIAMConfigKey(iamOS, objAPI); err != nil {
+		return err
+	}
+
+	var iamFmt iamFormat
+	path := getIAMFormatFilePath()
+	if err := iamOS.loadIAMConfig(&iamFmt, path); err != nil {
+		return errors.New("corrupt IAM format file")
+	}
+	if iamFmt.Version >= iamFormatVersion2 {
+		// Nothing to do.
+		return nil
+	}
+
+	err := rewriteIAMConfigObjects(objAPI, func(data []byte) ([]byte, error) {
+		if !json.Valid(data) {
+			// Already encrypted.
+			return nil, nil
+		}
+		return encryptIAMConfig(data)
+	})
+	if err != nil {
+		return err
+	}
+
+	// Save iam format to version 2.
+	iamFmt.Version = iamFormatVersion2
+	if err := iamOS.saveIAMConfig(iamFmt, path); err != nil {
+		logger.LogIf(context.Background(), err)
+		return err
+	}
+	return nil
+}
+
 // Should be called under config migration lock
 func (iamOS *IAMObjectStore) migrateBackendFormat(objAPI ObjectLayer) error {
 	iamOS.setObjectAPI(objAPI)
@@ -206,15 +244,63 @@ func (iamOS *IAMObjectStore) migrateBackendFormat(objAPI ObjectLayer) error {
 	if err := iamOS.migrateToV1(); err != nil {
 		return err
 	}
+	if err := iamOS.migrateToV2(); err != nil {
+		return err
+	}
 	return nil
 }
 
+// rewriteIAMConfigObjects - rewrites every IAM config object, except
+// the format file, with the data returned by rewriteFn. Objects for
+// which rewriteFn returns no data are left untouched.
+func rewriteIAMConfigObjects(objAPI ObjectLayer, rewriteFn func(data []byte) ([]byte, error)) error {
+	marker := ""
+	for {
+		lo, err := objAPI.ListObjects(context.Background(), minioMetaBucket, iamConfigPrefix+SlashSeparator, marker, "", 1000)
+		if err != nil {
+			return err
+		}
+		for _, obj := range lo.Objects {
+			if obj.Name == getIAMFormatFilePath() {
+				continue
+			}
+			data, err := readConfig(context.Background(), objAPI, obj.Name)
+			if err != nil {
+				if err == errConfigNotFound {
+					continue
+				}
+				return err
+			}
+			if data, err = rewriteFn(data); err != nil {
+				return err
+			}
+			if data == nil {
+				continue
+			}
+			if err = saveConfig(context.Background(), objAPI, obj.Name, data); err != nil {
+				return err
+			}
+		}
+		if !lo.IsTruncated {
+			return nil
+		}
+		marker = lo.NextMarker
+	}
+}
+
 func (iamOS *IAMObjectStore) saveIAMConfig(item interface{}, path string) error {
 	objectAPI := iamOS.getObjectAPI()
 	data, err := json.Marshal(item)
 	if err != nil {
 		return err
 	}
+	// The format file is left in plaintext, it holds no secrets
+	// and must stay readable across root credential changes.
+	if path != getIAMFormatFilePath() {
+		if data, err = encryptIAMConfig(data); err != nil {
+			return err
+		}
+	}
 	return saveConfig(context.Background(), objectAPI, path, data)
 }
 
@@ -224,6 +310,11 @@ func (iamOS *IAMObjectStore) loadIAMConfig(item interface{}, path string) error
 	if err != nil {
 		return err
 	}
+	if path != getIAMFormatFilePath() {
+		if data, err = decryptIAMConfig(data); err != nil {
+			return err
+		}
+	}
 	return json.Unmarshal(data, item)
 }
 
@@ -573,7 +664,13 @@ func (iamOS *IAMObjectStore) watch(sys *IAMSys) {
 			case <-GlobalServiceDoneCh:
 				return
 			case <-ticker.C:
-				iamOS.loadAll(sys, nil)
+				// Only reload the entries changed on the
+				// peers, rereading the entire IAM prefix
+				// is costly on large installations.
+				err := sys.refreshFromPeers(newObjectLayerFn(), func() error {
+					return iamOS.loadAll(sys, nil)
+				})
+				logger.LogIf(context.Background(), err)
 			}
 		}
 	}
@@ -0,0 +1,263 @@
+/*
+ * MinIO Cloud Storage, (C) 2019 MinIO, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/minio/minio/cmd/crypto"
+	"github.com/minio/minio/pkg/auth"
+)
+
+// Tests IAM config encryption and plaintext rejection.
+func TestIAMConfigEncryption(t *testing.T) {
+	cred, err := auth.GetNewCredentials()
+	if err != nil {
+		t.Fatal(err)
+	}
+	key, _, err := newIAMDataKey(cred)
+	if err != nil {
+		t.Fatal(err)
+	}
+	prevKey := globalIAMConfigKey.Get()
+	globalIAMConfigKey.Set(key)
+	defer globalIAMConfigKey.Set(prevKey)
+
+	data, err := json.Marshal(newUserIdentity(cred))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	edata, err := encryptIAMConfig(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if json.Valid(edata) || bytes.Contains(edata, []byte(cred.SecretKey)) {
+		t.Fatal("Expected IAM config to be encrypted")
+	}
+
+	ddata, err := decryptIAMConfig(edata)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(ddata, data) {
+		t.Fatalf("Expected %s, got %s", data, ddata)
+	}
+
+	// Plaintext config is rejected once the data key is loaded.
+	if _, err = decryptIAMConfig(data); err != errIAMConfigNotEncrypted {
+		t.Fatalf("Expected %v, got %v", errIAMConfigNotEncrypted, err)
+	}
+
+	// Plaintext config written before format version 2 is only
+	// read before the migration loads the data key.
+	globalIAMConfigKey.Set(nil)
+	ddata, err = decryptIAMConfig(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(ddata, data) {
+		t.Fatalf("Expected %s, got %s", data, ddata)
+	}
+}
+
+// Tests that a root credentials change only reseals the IAM data key.
+func TestIAMConfigKeyReseal(t *testing.T) {
+	objLayer, fsDir, err := prepareFS()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer removeRoots([]string{fsDir})
+
+	if err = newTestConfig(globalMinioDefaultRegion, objLayer); err != nil {
+		t.Fatal(err)
+	}
+
+	prevCred := globalActiveCred
+	prevKey := globalIAMConfigKey.Get()
+	defer func() {
+		globalActiveCred = prevCred
+		globalIAMConfigKey.Set(prevKey)
+	}()
+
+	store := newIAMObjectStore()
+	store.setObjectAPI(objLayer)
+
+	// Nothing to reseal without a persisted data key.
+	globalIAMConfigKey.Set(make([]byte, 32))
+	if err = resealIAMConfigKey(store, globalActiveCred); err != nil {
+		t.Fatal(err)
+	}
+
+	if err = store.saveIAMConfig(newIAMFormatVersion1(), getIAMFormatFilePath()); err != nil {
+		t.Fatal(err)
+	}
+	globalIAMConfigKey.Set(nil)
+	if err = initIAMConfigKey(store, objLayer); err != nil {
+		t.Fatal(err)
+	}
+	key := globalIAMConfigKey.Get()
+	if key == nil {
+		t.Fatal("Expected IAM data key to be initialized")
+	}
+
+	data := []byte(`{"version":1,"credentials":{"accessKey":"user","secretKey":"secret"}}`)
+	configFile := getUserIdentityPath("user", false)
+	var u UserIdentity
+	if err = json.Unmarshal(data, &u); err != nil {
+		t.Fatal(err)
+	}
+	if err = store.saveIAMConfig(u, configFile); err != nil {
+		t.Fatal(err)
+	}
+
+	newCred, err := auth.GetNewCredentials()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err = resealIAMConfigKey(store, newCred); err != nil {
+		t.Fatal(err)
+	}
+
+	// Restart with the new credentials in effect.
+	globalActiveCred = newCred
+	globalIAMConfigKey.Set(nil)
+	if err = initIAMConfigKey(store, nil); err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(globalIAMConfigKey.Get(), key) {
+		t.Fatal("Expected IAM data key to be unchanged")
+	}
+	var iamFmt iamFormat
+	if err = store.loadIAMConfig(&iamFmt, getIAMFormatFilePath()); err != nil {
+		t.Fatal(err)
+	}
+	if len(iamFmt.DataKey.PrevSealedKey) != 0 {
+		t.Fatal("Expected previous sealing to be dropped")
+	}
+
+	var lu UserIdentity
+	if err = store.loadIAMConfig(&lu, configFile); err != nil {
+		t.Fatal(err)
+	}
+	if lu.Credentials.SecretKey != "secret" {
+		t.Fatalf("Expected secret key to be decrypted, got %s", lu.Credentials.SecretKey)
+	}
+
+	// Credentials changed through the environment without
+	// resealing, the stored config credentials still apply.
+	envCred, err := auth.GetNewCredentials()
+	if err != nil {
+		t.Fatal(err)
+	}
+	globalActiveCred = envCred
+	globalIAMConfigKey.Set(nil)
+	if err = initIAMConfigKey(store, nil); err != errIAMDataKeyUnseal {
+		t.Fatalf("Expected %v, got %v", errIAMDataKeyUnseal, err)
+	}
+	globalServerConfig.SetCredential(newCred)
+	if err = saveServerConfig(context.Background(), objLayer, globalServerConfig); err != nil {
+		t.Fatal(err)
+	}
+	globalActiveCred = envCred
+	if err = initIAMConfigKey(store, objLayer); err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(globalIAMConfigKey.Get(), key) {
+		t.Fatal("Expected IAM data key to be unchanged")
+	}
+}
+
+// updateCountingKMS - KMS which counts the sealed keys it updates.
+type updateCountingKMS struct {
+	crypto.KMS
+	updates int
+}
+
+func (kms *updateCountingKMS) UpdateKey(keyID string, sealedKey []byte, ctx crypto.Context) ([]byte, error) {
+	kms.updates++
+	return kms.KMS.UpdateKey(keyID, sealedKey, ctx)
+}
+
+// Tests that a KMS sealed IAM data key is re-wrapped by the KMS.
+func TestIAMConfigKeyRotate(t *testing.T) {
+	objLayer, fsDir, err := prepareFS()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer removeRoots([]string{fsDir})
+
+	if err = newTestConfig(globalMinioDefaultRegion, objLayer); err != nil {
+		t.Fatal(err)
+	}
+
+	prevKMS, prevKMSKeyID := GlobalKMS, globalKMSKeyID
+	prevKey := globalIAMConfigKey.Get()
+	defer func() {
+		GlobalKMS, globalKMSKeyID = prevKMS, prevKMSKeyID
+		globalIAMConfigKey.Set(prevKey)
+	}()
+	globalKMSKeyID = "my-minio-key"
+
+	store := newIAMObjectStore()
+	store.setObjectAPI(objLayer)
+
+	// Data keys sealed with the root credentials are left as is.
+	kms := &updateCountingKMS{KMS: crypto.NewKMS([32]byte{1})}
+	GlobalKMS = nil
+	globalIAMConfigKey.Set(nil)
+	if err = store.saveIAMConfig(newIAMFormatVersion1(), getIAMFormatFilePath()); err != nil {
+		t.Fatal(err)
+	}
+	if err = initIAMConfigKey(store, objLayer); err != nil {
+		t.Fatal(err)
+	}
+	GlobalKMS = kms
+	if err = rotateIAMConfigKey(store); err != nil {
+		t.Fatal(err)
+	}
+	if kms.updates != 0 {
+		t.Fatalf("Expected no KMS update, got %d", kms.updates)
+	}
+
+	globalIAMConfigKey.Set(nil)
+	if err = store.saveIAMConfig(newIAMFormatVersion1(), getIAMFormatFilePath()); err != nil {
+		t.Fatal(err)
+	}
+	if err = initIAMConfigKey(store, objLayer); err != nil {
+		t.Fatal(err)
+	}
+	key := globalIAMConfigKey.Get()
+	if err = rotateIAMConfigKey(store); err != nil {
+		t.Fatal(err)
+	}
+	if kms.updates != 1 {
+		t.Fatalf("Expected one KMS update, got %d", kms.updates)
+	}
+
+	// The re-wrapped data key is unchanged.
+	globalIAMConfigKey.Set(nil)
+	if err = initIAMConfigKey(store, objLayer); err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(globalIAMConfigKey.Get(), key) {
+		t.Fatal("Expected IAM data key to be unchanged")
+	}
+}
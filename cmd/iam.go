@@ -20,6 +20,7 @@ import (
 	"bytes"
 	"context"
 	"encoding/json"
+	"fmt"
 	"strings"
 	"sync"
 
@@ -65,6 +66,9 @@ const (
 	iamFormatFile = "format.json"
 
 	iamFormatVersion1 = 1
+
+	// IAM config objects are encrypted at rest from version 2.
+	iamFormatVersion2 = 2
 )
 
 const (
@@ -74,6 +78,9 @@ const (
 
 type iamFormat struct {
 	Version int `json:"version"`
+
+	// Sealed data key encrypting IAM config, from version 2.
+	DataKey *iamDataKey `json:"dataKey,omitempty"`
 }
 
 func newIAMFormatVersion1() iamFormat {
@@ -160,6 +167,12 @@ type IAMSys struct {
 
 	// Persistence layer for IAM subsystem
 	store IAMStorageAPI
+
+	// Changes made on this server, pulled by the peers.
+	changes *iamChangeLog
+	// Versions of the changes of each peer already reloaded.
+	peerVersions   map[string]iamChanges
+	peerVersionsMu sync.Mutex
 }
 
 // IAMStorageAPI defines an interface for the IAM persistence layer
@@ -330,6 +343,54 @@ func (sys *IAMSys) doIAMConfigMigration(objAPI ObjectLayer) error {
 	return sys.store.migrateBackendFormat(objAPI)
 }
 
+// ResealConfigKey - reseals the data key encrypting IAM config with
+// new root credentials, must be called before the new credentials
+// are persisted.
+func (sys *IAMSys) ResealConfigKey(cred auth.Credentials) error {
+	if sys == nil || sys.store == nil || globalIAMConfigKey.Get() == nil {
+		// IAM config is not encrypted yet.
+		return nil
+	}
+
+	objectAPI := newObjectLayerFn()
+	if objectAPI == nil {
+		return errServerNotInitialized
+	}
+
+	// Serialize with IAM configuration migration.
+	lockPath := iamConfigPrefix + "/migration.lock"
+	objLock := globalNSMutex.NewNSLock(context.Background(), minioMetaBucket, lockPath)
+	if err := objLock.GetLock(globalOperationTimeout); err != nil {
+		return err
+	}
+	defer objLock.Unlock()
+
+	return resealIAMConfigKey(sys.store, cred)
+}
+
+// RotateConfigKey - re-wraps the data key encrypting IAM config with
+// the current version of the KMS master key it is sealed with.
+func (sys *IAMSys) RotateConfigKey() error {
+	if sys == nil || sys.store == nil || globalIAMConfigKey.Get() == nil {
+		// IAM config is not encrypted yet.
+		return nil
+	}
+
+	if newObjectLayerFn() == nil {
+		return errServerNotInitialized
+	}
+
+	// Serialize with IAM configuration migration.
+	lockPath := iamConfigPrefix + "/migration.lock"
+	objLock := globalNSMutex.NewNSLock(context.Background(), minioMetaBucket, lockPath)
+	if err := objLock.GetLock(globalOperationTimeout); err != nil {
+		return err
+	}
+	defer objLock.Unlock()
+
+	return rotateIAMConfigKey(sys.store)
+}
+
 // Init - initializes config system from iam.json
 func (sys *IAMSys) Init(objAPI ObjectLayer) error {
 	if objAPI == nil {
@@ -409,6 +470,7 @@ func (sys *IAMSys) DeletePolicy(policyName string) error {
 	defer sys.Unlock()
 
 	delete(sys.iamPolicyDocsMap, policyName)
+	sys.recordChange(iamChangePolicy, policyName, false, false)
 	return err
 }
 
@@ -453,6 +515,7 @@ func (sys *IAMSys) SetPolicy(policyName string, p iampolicy.Policy) error {
 	sys.Lock()
 	defer sys.Unlock()
 	sys.iamPolicyDocsMap[policyName] = p
+	sys.recordChange(iamChangePolicy, policyName, false, false)
 	return nil
 }
 
@@ -477,6 +540,7 @@ func (sys *IAMSys) DeleteUser(accessKey string) error {
 
 	delete(sys.iamUsersMap, accessKey)
 	delete(sys.iamUserPolicyMap, accessKey)
+	sys.recordChange(iamChangeUser, accessKey, false, false)
 
 	return err
 }
@@ -518,6 +582,7 @@ func (sys *IAMSys) SetTempUser(accessKey string, cred auth.Credentials, policyNa
 	}
 
 	sys.iamUsersMap[accessKey] = cred
+	sys.recordChange(iamChangeUser, accessKey, true, false)
 	return nil
 }
 
@@ -595,6 +660,7 @@ func (sys *IAMSys) SetUserStatus(accessKey string, status madmin.AccountStatus)
 	}
 
 	sys.iamUsersMap[accessKey] = uinfo.Credentials
+	sys.recordChange(iamChangeUser, accessKey, false, false)
 	return nil
 }
 
@@ -618,6 +684,7 @@ func (sys *IAMSys) SetUser(accessKey string, uinfo madmin.UserInfo) error {
 		return err
 	}
 	sys.iamUsersMap[accessKey] = u.Credentials
+	sys.recordChange(iamChangeUser, accessKey, false, false)
 
 	// Set policy if specified.
 	if uinfo.PolicyName != "" {
@@ -648,6 +715,7 @@ func (sys *IAMSys) SetUserSecretKey(accessKey string, secretKey string) error {
 	}
 
 	sys.iamUsersMap[accessKey] = cred
+	sys.recordChange(iamChangeUser, accessKey, false, false)
 	return nil
 }
 
@@ -699,6 +767,7 @@ func (sys *IAMSys) AddUsersToGroup(group string, members []string) error {
 	}
 
 	sys.iamGroupsMap[group] = gi
+	sys.recordChange(iamChangeGroup, group, false, true)
 
 	// update user-group membership map
 	for _, member := range members {
@@ -765,6 +834,7 @@ func (sys *IAMSys) RemoveUsersFromGroup(group string, members []string) error {
 		// Delete from server memory
 		delete(sys.iamGroupsMap, group)
 		delete(sys.iamGroupPolicyMap, group)
+		sys.recordChange(iamChangeGroup, group, false, true)
 		return nil
 	}
 
@@ -778,6 +848,7 @@ func (sys *IAMSys) RemoveUsersFromGroup(group string, members []string) error {
 		return err
 	}
 	sys.iamGroupsMap[group] = gi
+	sys.recordChange(iamChangeGroup, group, false, true)
 
 	// update user-group membership map
 	for _, member := range members {
@@ -821,6 +892,7 @@ func (sys *IAMSys) SetGroupStatus(group string, enabled bool) error {
 		return err
 	}
 	sys.iamGroupsMap[group] = gi
+	sys.recordChange(iamChangeGroup, group, false, true)
 	return nil
 }
 
@@ -908,6 +980,7 @@ func (sys *IAMSys) policyDBSet(objectAPI ObjectLayer, name, policy string, isSTS
 	} else {
 		sys.iamGroupPolicyMap[name] = mp
 	}
+	sys.recordChange(iamChangePolicyMapping, name, isSTS, isGroup)
 	return nil
 }
 
@@ -1097,6 +1170,77 @@ func (sys *IAMSys) IsAllowed(args iampolicy.Args) bool {
 	return combinedPolicy.IsAllowed(args)
 }
 
+// Simulate - checks given policy args like IsAllowed, explaining which
+// policy statement decided. Session policies of temporary credentials
+// are not known and not checked.
+func (sys *IAMSys) Simulate(args iampolicy.Args) (madmin.PolicySimulationResult, error) {
+	result := madmin.PolicySimulationResult{Source: madmin.PolicySourceIAM}
+
+	if globalPolicyOPA != nil {
+		allowed, err := globalPolicyOPA.IsAllowed(args)
+		result.Source = madmin.PolicySourceOPA
+		result.Allowed = allowed
+		result.Reason = "Decided by the Open Policy Agent."
+		return result, err
+	}
+
+	if args.IsOwner {
+		result.Source = madmin.PolicySourceOwner
+		result.Allowed = true
+		result.Reason = "The owner is allowed all actions."
+		return result, nil
+	}
+
+	sys.RLock()
+	defer sys.RUnlock()
+
+	if u, ok := sys.iamUsersMap[args.AccountName]; ok && u.Status == statusDisabled {
+		result.Reason = "The user is disabled."
+		return result, nil
+	}
+	policies, err := sys.policyDBGet(args.AccountName, false)
+	if err != nil {
+		return result, err
+	}
+
+	// Statements are combined like IsAllowed does, keeping track of the
+	// policy of each statement.
+	var combinedPolicy iampolicy.Policy
+	var statementPolicies []string
+	for _, pname := range policies {
+		p, found := sys.iamPolicyDocsMap[pname]
+		if !found {
+			continue
+		}
+		combinedPolicy.Statements = append(combinedPolicy.Statements, p.Statements...)
+		for range p.Statements {
+			statementPolicies = append(statementPolicies, pname)
+		}
+	}
+	if len(statementPolicies) == 0 {
+		result.Reason = "No policy is attached to the user or to its groups."
+		return result, nil
+	}
+
+	allowed, i := combinedPolicy.Evaluate(args)
+	result.Allowed = allowed
+	if i < 0 {
+		result.Reason = fmt.Sprintf("No statement of the policies %s allows the action.",
+			strings.Join(policies, ", "))
+		return result, nil
+	}
+	if result.Statement, err = json.Marshal(combinedPolicy.Statements[i]); err != nil {
+		return result, err
+	}
+	result.Policy = statementPolicies[i]
+	if allowed {
+		result.Reason = fmt.Sprintf("Allowed by a statement of the policy %s.", result.Policy)
+	} else {
+		result.Reason = fmt.Sprintf("Denied by a statement of the policy %s.", result.Policy)
+	}
+	return result, nil
+}
+
 // Set default canned policies only if not already overridden by users.
 func setDefaultCannedPolicies(policies map[string]iampolicy.Policy) {
 	_, ok := policies["writeonly"]
@@ -1161,5 +1305,6 @@ func NewIAMSys() *IAMSys {
 		iamUserPolicyMap:        make(map[string]MappedPolicy),
 		iamGroupsMap:            make(map[string]GroupInfo),
 		iamUserGroupMemberships: make(map[string]set.StringSet),
+		changes:                 newIAMChangeLog(),
 	}
 }
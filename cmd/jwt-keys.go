@@ -0,0 +1,185 @@
+/*
+ * MinIO Cloud Storage, (C) 2020 MinIO, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/hex"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"strings"
+
+	jwtgo "github.com/dgrijalva/jwt-go"
+)
+
+// Minimum size of RSA keys signing web tokens.
+const jwtMinRSAKeyBits = 2048
+
+var errJWTUnknownKeyID = errors.New("JWT token signed by an unknown key")
+
+// jwtKey is an asymmetric key signing or verifying web tokens.
+type jwtKey struct {
+	// ID of the key, embedded as "kid" header in the tokens.
+	ID     string
+	Method jwtgo.SigningMethod
+	// Private is nil for keys which only verify tokens.
+	Private crypto.Signer
+	Public  crypto.PublicKey
+}
+
+// jwtKeys holds the dedicated keys of web tokens. New tokens are
+// signed by the signing key, tokens signed by any of the keys are
+// accepted so that the signing key can be rotated without
+// invalidating the tokens signed by the previous key.
+type jwtKeys struct {
+	signing *jwtKey
+	keys    map[string]*jwtKey
+}
+
+// Sign signs the claims with the signing key.
+func (k *jwtKeys) Sign(claims jwtgo.Claims) (string, error) {
+	jwt := jwtgo.NewWithClaims(k.signing.Method, claims)
+	jwt.Header["kid"] = k.signing.ID
+	return jwt.SignedString(k.signing.Private)
+}
+
+// VerifyKey returns the public key verifying the given token.
+func (k *jwtKeys) VerifyKey(jwtToken *jwtgo.Token) (crypto.PublicKey, error) {
+	kid, _ := jwtToken.Header["kid"].(string)
+	key, ok := k.keys[kid]
+	if !ok {
+		return nil, errJWTUnknownKeyID
+	}
+	if jwtToken.Method.Alg() != key.Method.Alg() {
+		return nil, fmt.Errorf("Unexpected signing method: %v", jwtToken.Header["alg"])
+	}
+	return key.Public, nil
+}
+
+// loadJWTKeys loads the private key signing new web tokens and the
+// public or private keys of previous signing keys whose tokens are
+// still accepted.
+func loadJWTKeys(signingKeyFile string, verifyKeyFiles []string) (*jwtKeys, error) {
+	data, err := ioutil.ReadFile(signingKeyFile)
+	if err != nil {
+		return nil, err
+	}
+	signing, err := parseJWTKey(data)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %v", signingKeyFile, err)
+	}
+	if signing.Private == nil {
+		return nil, fmt.Errorf("%s: private key required to sign tokens", signingKeyFile)
+	}
+
+	keys := &jwtKeys{
+		signing: signing,
+		keys:    map[string]*jwtKey{signing.ID: signing},
+	}
+	for _, keyFile := range verifyKeyFiles {
+		keyFile = strings.TrimSpace(keyFile)
+		if keyFile == "" {
+			continue
+		}
+		data, err = ioutil.ReadFile(keyFile)
+		if err != nil {
+			return nil, err
+		}
+		key, err := parseJWTKey(data)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %v", keyFile, err)
+		}
+		keys.keys[key.ID] = key
+	}
+	return keys, nil
+}
+
+// parseJWTKey parses a PEM encoded RSA or ECDSA P-256 private or
+// public key. The key ID is derived from the public key.
+func parseJWTKey(data []byte) (*jwtKey, error) {
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, errors.New("no PEM encoded key found")
+	}
+
+	var key jwtKey
+	switch block.Type {
+	case "PUBLIC KEY":
+		public, err := x509.ParsePKIXPublicKey(block.Bytes)
+		if err != nil {
+			return nil, err
+		}
+		key.Public = public
+	case "RSA PRIVATE KEY":
+		private, err := x509.ParsePKCS1PrivateKey(block.Bytes)
+		if err != nil {
+			return nil, err
+		}
+		key.Private = private
+	case "EC PRIVATE KEY":
+		private, err := x509.ParseECPrivateKey(block.Bytes)
+		if err != nil {
+			return nil, err
+		}
+		key.Private = private
+	case "PRIVATE KEY":
+		private, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+		if err != nil {
+			return nil, err
+		}
+		signer, ok := private.(crypto.Signer)
+		if !ok {
+			return nil, errors.New("unsupported private key")
+		}
+		key.Private = signer
+	default:
+		return nil, fmt.Errorf("unsupported PEM block %s", block.Type)
+	}
+	if key.Private != nil {
+		key.Public = key.Private.Public()
+	}
+
+	switch public := key.Public.(type) {
+	case *rsa.PublicKey:
+		if public.N.BitLen() < jwtMinRSAKeyBits {
+			return nil, fmt.Errorf("RSA keys must have at least %d bits", jwtMinRSAKeyBits)
+		}
+		key.Method = jwtgo.SigningMethodRS256
+	case *ecdsa.PublicKey:
+		if public.Curve != elliptic.P256() {
+			return nil, fmt.Errorf("the ECDSA curve '%s' is not supported", public.Params().Name)
+		}
+		key.Method = jwtgo.SigningMethodES256
+	default:
+		return nil, errors.New("only RSA and ECDSA keys are supported")
+	}
+
+	der, err := x509.MarshalPKIXPublicKey(key.Public)
+	if err != nil {
+		return nil, err
+	}
+	sum := sha256.Sum256(der)
+	key.ID = hex.EncodeToString(sum[:8])
+	return &key, nil
+}
@@ -0,0 +1,220 @@
+/*
+ * MinIO Cloud Storage, (C) 2020 MinIO, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	jwtgo "github.com/dgrijalva/jwt-go"
+)
+
+// Writes the PEM encoded key to a file in dir and returns its path.
+func writeTestJWTKey(t *testing.T, dir, name, blockType string, der []byte) string {
+	file := filepath.Join(dir, name)
+	if err := ioutil.WriteFile(file, pem.EncodeToMemory(&pem.Block{Type: blockType, Bytes: der}), 0600); err != nil {
+		t.Fatal(err)
+	}
+	return file
+}
+
+func TestParseJWTKey(t *testing.T) {
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	smallRSAKey, err := rsa.GenerateKey(rand.Reader, 1024)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ecKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	p384Key, err := ecdsa.GenerateKey(elliptic.P384(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ecDER, err := x509.MarshalECPrivateKey(ecKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	p384DER, err := x509.MarshalECPrivateKey(p384Key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	pkcs8DER, err := x509.MarshalPKCS8PrivateKey(ecKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	publicDER, err := x509.MarshalPKIXPublicKey(rsaKey.Public())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	testCases := []struct {
+		blockType  string
+		der        []byte
+		alg        string
+		hasPrivate bool
+		shouldFail bool
+	}{
+		{"RSA PRIVATE KEY", x509.MarshalPKCS1PrivateKey(rsaKey), "RS256", true, false},
+		{"EC PRIVATE KEY", ecDER, "ES256", true, false},
+		{"PRIVATE KEY", pkcs8DER, "ES256", true, false},
+		{"PUBLIC KEY", publicDER, "RS256", false, false},
+		{"RSA PRIVATE KEY", x509.MarshalPKCS1PrivateKey(smallRSAKey), "", false, true},
+		{"EC PRIVATE KEY", p384DER, "", false, true},
+		{"CERTIFICATE", publicDER, "", false, true},
+	}
+	for i, testCase := range testCases {
+		key, err := parseJWTKey(pem.EncodeToMemory(&pem.Block{Type: testCase.blockType, Bytes: testCase.der}))
+		if testCase.shouldFail {
+			if err == nil {
+				t.Errorf("Test %d: Expected to fail", i+1)
+			}
+			continue
+		}
+		if err != nil {
+			t.Fatalf("Test %d: %v", i+1, err)
+		}
+		if key.Method.Alg() != testCase.alg {
+			t.Errorf("Test %d: Expected %s, got %s", i+1, testCase.alg, key.Method.Alg())
+		}
+		if (key.Private != nil) != testCase.hasPrivate {
+			t.Errorf("Test %d: Unexpected private key %v", i+1, key.Private)
+		}
+	}
+
+	// The key ID is derived from the public key.
+	private, err := parseJWTKey(pem.EncodeToMemory(&pem.Block{Type: "RSA PRIVATE KEY", Bytes: x509.MarshalPKCS1PrivateKey(rsaKey)}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	public, err := parseJWTKey(pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: publicDER}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if private.ID != public.ID {
+		t.Fatalf("Expected same key ID, got %s and %s", private.ID, public.ID)
+	}
+}
+
+func TestAuthenticateWebJWTKeys(t *testing.T) {
+	obj, fsDir, err := prepareFS()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(fsDir)
+	if err = newTestConfig(globalMinioDefaultRegion, obj); err != nil {
+		t.Fatal(err)
+	}
+	defer func() { globalJWTKeys = nil }()
+
+	dir, err := ioutil.TempDir(globalTestTmpDir, "minio-jwt-keys-")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ecKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ecDER, err := x509.MarshalECPrivateKey(ecKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	rsaPublicDER, err := x509.MarshalPKIXPublicKey(rsaKey.Public())
+	if err != nil {
+		t.Fatal(err)
+	}
+	rsaFile := writeTestJWTKey(t, dir, "rsa.pem", "RSA PRIVATE KEY", x509.MarshalPKCS1PrivateKey(rsaKey))
+	rsaPublicFile := writeTestJWTKey(t, dir, "rsa.pub", "PUBLIC KEY", rsaPublicDER)
+	ecFile := writeTestJWTKey(t, dir, "ec.pem", "EC PRIVATE KEY", ecDER)
+
+	cred := globalServerConfig.GetCredential()
+	hmacToken, err := authenticateWeb(cred.AccessKey, cred.SecretKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err = loadJWTKeys(rsaPublicFile, nil); err == nil {
+		t.Fatal("Expected a public signing key to be rejected")
+	}
+	if globalJWTKeys, err = loadJWTKeys(rsaFile, nil); err != nil {
+		t.Fatal(err)
+	}
+	rsaToken, err := authenticateWeb(cred.AccessKey, cred.SecretKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	header, err := jwtgo.Parse(rsaToken, func(*jwtgo.Token) (interface{}, error) { return rsaKey.Public(), nil })
+	if err != nil {
+		t.Fatal(err)
+	}
+	if header.Header["alg"] != "RS256" || header.Header["kid"] != globalJWTKeys.signing.ID {
+		t.Fatalf("Unexpected token header %v", header.Header)
+	}
+
+	// Rotate the signing key, tokens signed by the previous
+	// key are still valid.
+	if globalJWTKeys, err = loadJWTKeys(ecFile, []string{rsaPublicFile}); err != nil {
+		t.Fatal(err)
+	}
+	ecToken, err := authenticateWeb(cred.AccessKey, cred.SecretKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i, token := range []string{hmacToken, rsaToken, ecToken} {
+		if _, owner, err := webTokenAuthenticate(token); err != nil || !owner {
+			t.Fatalf("Test %d: Expected the token to be valid, got %v", i+1, err)
+		}
+	}
+
+	// Tokens signed by retired keys are rejected.
+	if globalJWTKeys, err = loadJWTKeys(ecFile, nil); err != nil {
+		t.Fatal(err)
+	}
+	if isAuthTokenValid(rsaToken) {
+		t.Fatal("Expected the token signed by the retired key to be rejected")
+	}
+
+	// Tokens of unknown users are rejected.
+	unknownToken, err := globalJWTKeys.Sign(jwtgo.StandardClaims{
+		ExpiresAt: UTCNow().Add(defaultJWTExpiry).Unix(),
+		Subject:   "unknown",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if isAuthTokenValid(unknownToken) {
+		t.Fatal("Expected the token of an unknown user to be rejected")
+	}
+}
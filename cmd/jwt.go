@@ -69,10 +69,14 @@ func authenticateJWTUsers(accessKey, secretKey string, expiry time.Duration) (st
 		return "", errAuthentication
 	}
 
-	jwt := jwtgo.NewWithClaims(jwtgo.SigningMethodHS512, jwtgo.StandardClaims{
+	claims := jwtgo.StandardClaims{
 		ExpiresAt: UTCNow().Add(expiry).Unix(),
 		Subject:   accessKey,
-	})
+	}
+	if globalJWTKeys != nil {
+		return globalJWTKeys.Sign(claims)
+	}
+	jwt := jwtgo.NewWithClaims(jwtgo.SigningMethodHS512, claims)
 	return jwt.SignedString([]byte(serverCred.SecretKey))
 }
 
@@ -113,7 +117,8 @@ func authenticateURL(accessKey, secretKey string) (string, error) {
 
 // Callback function used for parsing
 func webTokenCallback(jwtToken *jwtgo.Token) (interface{}, error) {
-	if _, ok := jwtToken.Method.(*jwtgo.SigningMethodHMAC); !ok {
+	_, isHMAC := jwtToken.Method.(*jwtgo.SigningMethodHMAC)
+	if !isHMAC && globalJWTKeys == nil {
 		return nil, fmt.Errorf("Unexpected signing method: %v", jwtToken.Header["alg"])
 	}
 
@@ -121,7 +126,22 @@ func webTokenCallback(jwtToken *jwtgo.Token) (interface{}, error) {
 		return nil, errAuthentication
 	}
 
-	if claims, ok := jwtToken.Claims.(*jwtgo.StandardClaims); ok {
+	claims, ok := jwtToken.Claims.(*jwtgo.StandardClaims)
+	if ok && !isHMAC {
+		// Tokens signed by the dedicated keys are only valid
+		// for existing users.
+		if claims.Subject != globalServerConfig.GetCredential().AccessKey {
+			if globalIAMSys == nil {
+				return nil, errInvalidAccessKeyID
+			}
+			if _, ok = globalIAMSys.GetUser(claims.Subject); !ok {
+				return nil, errInvalidAccessKeyID
+			}
+		}
+		return globalJWTKeys.VerifyKey(jwtToken)
+	}
+
+	if ok {
 		if claims.Subject == globalServerConfig.GetCredential().AccessKey {
 			return []byte(globalServerConfig.GetCredential().SecretKey), nil
 		}
@@ -175,6 +195,7 @@ func webTokenAuthenticate(token string) (jwtgo.StandardClaims, bool, error) {
 		return claims, false, errAuthentication
 	}
 	owner := claims.Subject == globalServerConfig.GetCredential().AccessKey
+	globalAccessKeyUsage.record(claims.Subject)
 	return claims, owner, nil
 }
 
@@ -198,6 +219,7 @@ func webRequestAuthenticate(req *http.Request) (jwtgo.StandardClaims, bool, erro
 		return claims, false, errAuthentication
 	}
 	owner := claims.Subject == globalServerConfig.GetCredential().AccessKey
+	globalAccessKeyUsage.record(claims.Subject)
 	return claims, owner, nil
 }
 
@@ -0,0 +1,187 @@
+/*
+ * MinIO Cloud Storage, (C) 2019 MinIO, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"context"
+	"errors"
+	"sync"
+
+	"github.com/minio/minio/cmd/crypto"
+	"github.com/minio/minio/cmd/logger"
+	"github.com/minio/minio/pkg/madmin"
+)
+
+var (
+	errKMSKeyRotationNotSupported = errors.New("KMS does not support master key rotation")
+	errKMSKeySweepInProgress      = errors.New("KMS key re-encryption sweep already in progress")
+)
+
+// rotateKMSKey - rotates the master key with the given ID at the
+// KMS, the default SSE-S3 master key if keyID is empty.
+func rotateKMSKey(keyID string) error {
+	if GlobalKMS == nil {
+		return errKMSNotConfigured
+	}
+	rotator, ok := GlobalKMS.(crypto.KeyRotator)
+	if !ok {
+		return errKMSKeyRotationNotSupported
+	}
+	if keyID == "" {
+		keyID = globalKMSKeyID
+	}
+	return rotator.RotateKey(keyID)
+}
+
+// kmsKeySweep - re-wraps the keys of all SSE-S3 encrypted objects,
+// and the IAM data key if sealed by the KMS, with the current master
+// key, so that previous versions of the master key can be retired at
+// the KMS after a rotation.
+type kmsKeySweep struct {
+	mu     sync.Mutex
+	status madmin.KMSKeySweepStatus
+}
+
+var globalKMSKeySweep = &kmsKeySweep{}
+
+// Status - returns the sweep state of this server.
+func (s *kmsKeySweep) Status() madmin.KMSKeySweepStatus {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	status := s.status
+	status.Node = GetLocalPeer(globalEndpoints)
+	status.Buckets = append([]madmin.KMSKeySweepBucket(nil), s.status.Buckets...)
+	return status
+}
+
+// Start - starts a sweep over all buckets in the background,
+// fails if a sweep is already running on this server.
+func (s *kmsKeySweep) Start(ctx context.Context, objAPI ObjectLayer) error {
+	if GlobalKMS == nil {
+		return errKMSNotConfigured
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.status.Running {
+		return errKMSKeySweepInProgress
+	}
+	s.status = madmin.KMSKeySweepStatus{
+		Running:   true,
+		StartTime: UTCNow(),
+	}
+	go s.run(ctx, objAPI)
+	return nil
+}
+
+func (s *kmsKeySweep) run(ctx context.Context, objAPI ObjectLayer) {
+	err := s.sweep(ctx, objAPI)
+	logger.LogIf(ctx, err)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.status.Running = false
+	s.status.EndTime = UTCNow()
+	if err != nil {
+		s.status.Error = err.Error()
+	}
+}
+
+func (s *kmsKeySweep) sweep(ctx context.Context, objAPI ObjectLayer) error {
+	// The IAM data key may be sealed by the KMS as well, failing
+	// to re-wrap it does not hold back the objects.
+	logger.LogIf(ctx, globalIAMSys.RotateConfigKey())
+
+	buckets, err := objAPI.ListBuckets(ctx)
+	if err != nil {
+		return err
+	}
+
+	for _, bucket := range buckets {
+		s.mu.Lock()
+		s.status.Buckets = append(s.status.Buckets, madmin.KMSKeySweepBucket{Bucket: bucket.Name})
+		s.mu.Unlock()
+
+		marker := ""
+		for {
+			result, err := objAPI.ListObjects(ctx, bucket.Name, "", marker, "", maxObjectList)
+			if err != nil {
+				return err
+			}
+			for _, obj := range result.Objects {
+				rotated, err := rotateObjectKMSKey(ctx, objAPI, bucket.Name, obj.Name)
+				if err != nil && !isErrObjectNotFound(err) {
+					logger.LogIf(ctx, err)
+				}
+				s.update(rotated, err)
+			}
+			if !result.IsTruncated {
+				break
+			}
+			marker = result.NextMarker
+		}
+	}
+	return nil
+}
+
+// update - records the outcome for an object of the bucket being swept.
+func (s *kmsKeySweep) update(rotated bool, err error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	b := &s.status.Buckets[len(s.status.Buckets)-1]
+	b.Scanned++
+	switch {
+	case rotated:
+		b.Rotated++
+	case err != nil && !isErrObjectNotFound(err):
+		b.Failed++
+	}
+}
+
+// rotateObjectKMSKey - seals the object key of an SSE-S3 encrypted
+// object with a new data key generated by the current master key,
+// only the object metadata is rewritten. Returns false if the object
+// is not SSE-S3 encrypted.
+func rotateObjectKMSKey(ctx context.Context, objAPI ObjectLayer, bucket, object string) (bool, error) {
+	objInfo, err := objAPI.GetObjectInfo(ctx, bucket, object, ObjectOptions{})
+	if err != nil {
+		return false, err
+	}
+	if !crypto.S3.IsEncrypted(objInfo.UserDefined) {
+		return false, nil
+	}
+
+	metadata := make(map[string]string, len(objInfo.UserDefined))
+	for k, v := range objInfo.UserDefined {
+		metadata[k] = v
+	}
+	if err = rotateKey(nil, nil, bucket, object, metadata); err != nil {
+		return false, err
+	}
+
+	// Same as an SSE-S3 key rotation through CopyObject.
+	objInfo.UserDefined = metadata
+	objInfo.metadataOnly = true
+	if _, err = objAPI.CopyObject(ctx, bucket, object, bucket, object, objInfo, ObjectOptions{}, ObjectOptions{}); err != nil {
+		return false, err
+	}
+	return true, nil
+}
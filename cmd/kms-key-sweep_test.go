@@ -0,0 +1,115 @@
+/*
+ * MinIO Cloud Storage, (C) 2019 MinIO, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/minio/minio/cmd/crypto"
+)
+
+// Tests that the sweep re-wraps the keys of SSE-S3 objects only.
+func TestKMSKeySweep(t *testing.T) {
+	prevKMS := GlobalKMS
+	defer func() {
+		GlobalKMS = prevKMS
+	}()
+	GlobalKMS = crypto.NewKMS([32]byte{1})
+
+	obj, fsDir, err := prepareFS()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(fsDir)
+
+	if err = rotateKMSKey(""); err != errKMSKeyRotationNotSupported {
+		t.Fatalf("Expected %v, got %v", errKMSKeyRotationNotSupported, err)
+	}
+
+	ctx := context.Background()
+	bucket := "bucket"
+	if err = obj.MakeBucketWithLocation(ctx, bucket, ""); err != nil {
+		t.Fatal(err)
+	}
+
+	data := []byte("hello")
+	metadata := map[string]string{}
+	objectKey, err := newEncryptMetadata(nil, bucket, "encrypted", metadata, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for object, meta := range map[string]map[string]string{"encrypted": metadata, "plain": nil} {
+		if _, err = obj.PutObject(ctx, bucket, object, mustGetPutObjReader(t, bytes.NewReader(data), int64(len(data)), "", ""), ObjectOptions{UserDefined: meta}); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	sweep := &kmsKeySweep{}
+	if err = sweep.Start(ctx, obj); err != nil {
+		t.Fatal(err)
+	}
+	for sweep.Status().Running {
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	status := sweep.Status()
+	if status.Error != "" || len(status.Buckets) != 1 {
+		t.Fatalf("Unexpected sweep status %v", status)
+	}
+	if b := status.Buckets[0]; b.Bucket != bucket || b.Scanned != 2 || b.Rotated != 1 || b.Failed != 0 {
+		t.Fatalf("Unexpected bucket status %v", b)
+	}
+
+	objInfo, err := obj.GetObjectInfo(ctx, bucket, "encrypted", ObjectOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if objInfo.UserDefined[crypto.S3KMSSealedKey] == metadata[crypto.S3KMSSealedKey] {
+		t.Fatal("Expected the data key to be re-wrapped")
+	}
+	key, err := crypto.S3.UnsealObjectKey(GlobalKMS, objInfo.UserDefined, bucket, "encrypted")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(key[:], objectKey) {
+		t.Fatal("Expected the object key to be unchanged")
+	}
+}
+
+// Tests that a single sweep runs at a time.
+func TestKMSKeySweepInProgress(t *testing.T) {
+	prevKMS := GlobalKMS
+	defer func() {
+		GlobalKMS = prevKMS
+	}()
+
+	GlobalKMS = nil
+	sweep := &kmsKeySweep{}
+	if err := sweep.Start(context.Background(), nil); err != errKMSNotConfigured {
+		t.Fatalf("Expected %v, got %v", errKMSNotConfigured, err)
+	}
+
+	GlobalKMS = crypto.NewKMS([32]byte{1})
+	sweep.status.Running = true
+	if err := sweep.Start(context.Background(), nil); err != errKMSKeySweepInProgress {
+		t.Fatalf("Expected %v, got %v", errKMSKeySweepInProgress, err)
+	}
+}
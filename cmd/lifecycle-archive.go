@@ -0,0 +1,112 @@
+/*
+ * MinIO Cloud Storage, (C) 2020 MinIO, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/minio/minio/cmd/crypto"
+	xhttp "github.com/minio/minio/cmd/http"
+	"github.com/minio/minio/pkg/hash"
+	"github.com/minio/minio/pkg/lifecycle"
+)
+
+var (
+	errLifecycleArchiveSameBucket = errors.New("Lifecycle rules cannot archive objects to their own bucket")
+	errLifecycleArchiveSSECObject = errors.New("Objects encrypted with SSE-C are not archived by lifecycle rules")
+)
+
+// checkLifecycleArchiveBuckets - validates the archive buckets of the
+// lifecycle rules of bucket, they must exist and differ from bucket.
+func checkLifecycleArchiveBuckets(ctx context.Context, objAPI ObjectLayer, bucket string, lc *lifecycle.Lifecycle) error {
+	for _, rule := range lc.Rules {
+		if rule.ArchiveTo == nil {
+			continue
+		}
+		if rule.ArchiveTo.Bucket == bucket {
+			return errLifecycleArchiveSameBucket
+		}
+		if _, err := objAPI.GetBucketInfo(ctx, rule.ArchiveTo.Bucket); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// archiveLifecycleObject - moves an expired object to the archive
+// bucket under the same name, along with its content type and user
+// metadata. SSE-S3 objects are re-encrypted under their new name and
+// are never compressed, SSE-C objects cannot be archived as their keys
+// are not kept by the server.
+func archiveLifecycleObject(ctx context.Context, objAPI ObjectLayer, bucket string, obj ObjectInfo, archive lifecycle.ArchiveTo) error {
+	if crypto.IsEncrypted(obj.UserDefined) && !crypto.S3.IsEncrypted(obj.UserDefined) {
+		return errLifecycleArchiveSSECObject
+	}
+
+	var err error
+	if archive.Compress && objAPI.IsCompressionSupported() && !crypto.IsEncrypted(obj.UserDefined) &&
+		!hasStringSuffixInSlice(obj.Name, standardExcludeCompressExtensions) &&
+		!hasPattern(standardExcludeCompressContentTypes, obj.ContentType) {
+		err = compressLifecycleArchiveObject(ctx, objAPI, bucket, obj.Name, archive.Bucket)
+	} else {
+		err = copyPrefixObject(ctx, objAPI, bucket, obj.Name, archive.Bucket, obj.Name, true, true)
+	}
+	if err != nil {
+		return err
+	}
+	return objAPI.DeleteObject(ctx, bucket, obj.Name)
+}
+
+// compressLifecycleArchiveObject - copies an object to the archive
+// bucket compressed with the compression algorithm of the archive
+// bucket, objects which are already compressed are recompressed.
+func compressLifecycleArchiveObject(ctx context.Context, objAPI ObjectLayer, bucket, object, archiveBucket string) error {
+	gr, err := objAPI.GetObjectNInfo(ctx, bucket, object, nil, http.Header{}, readLock, ObjectOptions{})
+	if err != nil {
+		return err
+	}
+	defer gr.Close()
+
+	metadata := bucketSnapshotMetadata(gr.ObjInfo)
+	size := gr.ObjInfo.GetActualSize()
+	actualReader, err := hash.NewReader(gr, size, "", "", size, globalCLIContext.StrictS3Compat)
+	if err != nil {
+		return err
+	}
+	reader := actualReader
+	if size > 0 {
+		header := http.Header{}
+		header.Set(xhttp.ContentType, gr.ObjInfo.ContentType)
+		setCompressionMetadata(metadata, archiveBucket, object, header)
+		metadata[ReservedMetadataPrefix+"actual-size"] = strconv.FormatInt(size, 10)
+
+		compressReader, err := newObjectCompressReader(actualReader, metadata)
+		if err != nil {
+			return err
+		}
+		// Since compressed size is un-predictable.
+		if reader, err = hash.NewReader(compressReader, -1, "", "", size, globalCLIContext.StrictS3Compat); err != nil {
+			return err
+		}
+	}
+
+	_, err = objAPI.PutObject(ctx, archiveBucket, object, NewPutObjReader(reader, nil, nil), ObjectOptions{UserDefined: metadata})
+	return err
+}
@@ -102,3 +102,18 @@ func AuditLog(w http.ResponseWriter, r *http.Request, api string, reqClaims map[
 		_ = t.Send(entry)
 	}
 }
+
+// AuditLogInternal - logs an operation performed by the server on its
+// own, e.g. a lifecycle expiration, to all audit targets.
+func AuditLogInternal(api, bucket, object, userAgent string) {
+	for _, t := range AuditTargets {
+		entry := audit.NewEntry(globalDeploymentID)
+		entry.API.Name = api
+		entry.API.Bucket = bucket
+		entry.API.Object = object
+		entry.API.Status = http.StatusText(http.StatusOK)
+		entry.API.StatusCode = http.StatusOK
+		entry.UserAgent = userAgent
+		_ = t.Send(entry)
+	}
+}
@@ -0,0 +1,96 @@
+/*
+ * MinIO Cloud Storage, (C) 2019 MinIO, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package logger
+
+import (
+	"sync"
+	"time"
+
+	"github.com/minio/minio/cmd/logger/message/audit"
+)
+
+// ChainTarget - wraps an audit target to link the audit entries sent
+// to it in a hash chain, each entry holds the hash of the entry sent
+// before it so that removed or altered entries are detected.
+type ChainTarget struct {
+	mu     sync.Mutex
+	target Target
+	stream string
+	seq    uint64
+	hash   string
+}
+
+// NewChainTarget - returns an audit target which chains the entries
+// sent to target in a new stream.
+func NewChainTarget(target Target, stream string) *ChainTarget {
+	return &ChainTarget{
+		target: target,
+		stream: stream,
+	}
+}
+
+// Send - chains an audit entry and sends it to the wrapped target,
+// an entry which could not be sent is not part of the chain.
+func (c *ChainTarget) Send(entry interface{}) error {
+	e, ok := entry.(audit.Entry)
+	if !ok {
+		return c.target.Send(entry)
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	e.Chain = &audit.Chain{
+		Stream:   c.stream,
+		Seq:      c.seq + 1,
+		PrevHash: c.hash,
+	}
+	hash, err := audit.ComputeHash(e)
+	if err != nil {
+		return err
+	}
+	e.Chain.Hash = hash
+	if err = c.target.Send(e); err != nil {
+		return err
+	}
+	c.seq++
+	c.hash = hash
+	return nil
+}
+
+// Anchor - returns the head of the chain.
+func (c *ChainTarget) Anchor() audit.Anchor {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return audit.Anchor{
+		Stream: c.stream,
+		Seq:    c.seq,
+		Hash:   c.hash,
+		Time:   time.Now().UTC(),
+	}
+}
+
+// AuditChains - returns the audit targets whose entries are chained.
+func AuditChains() []*ChainTarget {
+	var chains []*ChainTarget
+	for _, t := range AuditTargets {
+		if c, ok := t.(*ChainTarget); ok {
+			chains = append(chains, c)
+		}
+	}
+	return chains
+}
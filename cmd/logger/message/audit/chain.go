@@ -0,0 +1,112 @@
+/*
+ * MinIO Cloud Storage, (C) 2019 MinIO, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package audit
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// Chain - links an audit entry to the entry sent before it in the
+// same stream, a stream starts with sequence number 1 and an empty
+// previous hash every time the server starts.
+type Chain struct {
+	Stream   string `json:"stream"`
+	Seq      uint64 `json:"seq"`
+	PrevHash string `json:"prevHash"`
+	Hash     string `json:"hash"`
+}
+
+// Anchor - the head of a stream of chained audit entries, anchors
+// are published apart from the audit log so that entries removed
+// from the end of the log or a rewritten log are detected.
+type Anchor struct {
+	Stream string    `json:"stream"`
+	Node   string    `json:"node,omitempty"`
+	Seq    uint64    `json:"seq"`
+	Hash   string    `json:"hash"`
+	Time   time.Time `json:"time"`
+}
+
+// ComputeHash - returns the hex encoded SHA-256 hash of the JSON
+// encoding of a chained entry, its own hash excluded.
+func ComputeHash(entry Entry) (string, error) {
+	if entry.Chain == nil {
+		return "", errors.New("audit entry is not chained")
+	}
+	chain := *entry.Chain
+	chain.Hash = ""
+	entry.Chain = &chain
+	data, err := json.Marshal(entry)
+	if err != nil {
+		return "", err
+	}
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:]), nil
+}
+
+// VerifyChain - verifies consecutive entries of a stream against each
+// other and against the anchors published for the stream. The entries
+// may start anywhere in the stream, anchors before the first entry are
+// ignored.
+func VerifyChain(entries []Entry, anchors []Anchor) error {
+	hashes := make(map[uint64]string, len(entries))
+	var prev *Chain
+	for i, entry := range entries {
+		if entry.Chain == nil {
+			return fmt.Errorf("audit entry %d is not chained", i)
+		}
+		hash, err := ComputeHash(entry)
+		if err != nil {
+			return err
+		}
+		if hash != entry.Chain.Hash {
+			return fmt.Errorf("audit entry %d of stream %s was altered", entry.Chain.Seq, entry.Chain.Stream)
+		}
+		if prev != nil {
+			if entry.Chain.Stream != prev.Stream {
+				return fmt.Errorf("audit entry %d belongs to stream %s, expected %s", entry.Chain.Seq, entry.Chain.Stream, prev.Stream)
+			}
+			if entry.Chain.Seq != prev.Seq+1 || entry.Chain.PrevHash != prev.Hash {
+				return fmt.Errorf("audit entries missing between %d and %d of stream %s", prev.Seq, entry.Chain.Seq, entry.Chain.Stream)
+			}
+		}
+		hashes[entry.Chain.Seq] = entry.Chain.Hash
+		prev = entry.Chain
+	}
+	if prev == nil {
+		return nil
+	}
+
+	first := entries[0].Chain.Seq
+	for _, anchor := range anchors {
+		if anchor.Stream != prev.Stream || anchor.Seq < first {
+			continue
+		}
+		if anchor.Seq > prev.Seq {
+			return fmt.Errorf("audit entries %d to %d of stream %s are missing", prev.Seq+1, anchor.Seq, anchor.Stream)
+		}
+		if hashes[anchor.Seq] != anchor.Hash {
+			return fmt.Errorf("audit entry %d of stream %s does not match its anchor", anchor.Seq, anchor.Stream)
+		}
+	}
+	return nil
+}
@@ -49,6 +49,16 @@ type Entry struct {
 	ReqQuery   map[string]string      `json:"requestQuery,omitempty"`
 	ReqHeader  map[string]string      `json:"requestHeader,omitempty"`
 	RespHeader map[string]string      `json:"responseHeader,omitempty"`
+	Chain      *Chain                 `json:"chain,omitempty"`
+}
+
+// NewEntry - constructs an audit entry object with some fields filled
+func NewEntry(deploymentID string) Entry {
+	return Entry{
+		Version:      Version,
+		DeploymentID: deploymentID,
+		Time:         time.Now().UTC().Format(time.RFC3339Nano),
+	}
 }
 
 // ToEntry - constructs an audit entry object.
@@ -67,18 +77,14 @@ func ToEntry(w http.ResponseWriter, r *http.Request, reqClaims map[string]interf
 	}
 	respHeader[xhttp.ETag] = strings.Trim(respHeader[xhttp.ETag], `"`)
 
-	entry := Entry{
-		Version:      Version,
-		DeploymentID: deploymentID,
-		RemoteHost:   handlers.GetSourceIP(r),
-		RequestID:    w.Header().Get(xhttp.AmzRequestID),
-		UserAgent:    r.UserAgent(),
-		Time:         time.Now().UTC().Format(time.RFC3339Nano),
-		ReqQuery:     reqQuery,
-		ReqHeader:    reqHeader,
-		ReqClaims:    reqClaims,
-		RespHeader:   respHeader,
-	}
+	entry := NewEntry(deploymentID)
+	entry.RemoteHost = handlers.GetSourceIP(r)
+	entry.RequestID = w.Header().Get(xhttp.AmzRequestID)
+	entry.UserAgent = r.UserAgent()
+	entry.ReqQuery = reqQuery
+	entry.ReqHeader = reqHeader
+	entry.ReqClaims = reqClaims
+	entry.RespHeader = respHeader
 
 	return entry
 }
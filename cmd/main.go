@@ -118,6 +118,7 @@ func newApp(name string) *cli.App {
 	registerCommand(gatewayCmd)
 	registerCommand(updateCmd)
 	registerCommand(versionCmd)
+	registerCommand(cacheMigrateCmd)
 
 	// Set up app.
 	cli.HelpFlag = cli.BoolFlag{
@@ -19,6 +19,7 @@ package cmd
 import (
 	"context"
 	"net/http"
+	"time"
 
 	"github.com/minio/minio/cmd/logger"
 	"github.com/prometheus/client_golang/prometheus"
@@ -34,6 +35,36 @@ var (
 		},
 		[]string{"request_type"},
 	)
+	peerRESTRequestsDuration = prometheus.NewHistogramVec(
+		prometheus.HistogramOpts{
+			Name:    "minio_peer_rest_requests_duration_seconds",
+			Help:    "Time taken by peer REST requests sent by current MinIO server instance",
+			Buckets: []float64{.001, .003, .005, .1, .5, 1},
+		},
+		[]string{"peer", "method"},
+	)
+	peerRESTRequestsFailures = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "minio_peer_rest_requests_failures_total",
+			Help: "Total number of failed peer REST requests sent by current MinIO server instance",
+		},
+		[]string{"peer", "method"},
+	)
+	lockWaitDuration = prometheus.NewHistogramVec(
+		prometheus.HistogramOpts{
+			Name:    "minio_lock_wait_duration_seconds",
+			Help:    "Time spent waiting for namespace locks on current MinIO server instance",
+			Buckets: []float64{.001, .003, .005, .1, .5, 1, 5},
+		},
+		[]string{"lock_type", "result"},
+	)
+	lockContentions = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "minio_lock_contentions_total",
+			Help: "Total number of local namespace lock requests on resources already locked or waited on",
+		},
+		[]string{"lock_type"},
+	)
 	minioVersionInfo = prometheus.NewGaugeVec(
 		prometheus.GaugeOpts{
 			Namespace: "minio",
@@ -51,6 +82,10 @@ var (
 
 func init() {
 	prometheus.MustRegister(httpRequestsDuration)
+	prometheus.MustRegister(peerRESTRequestsDuration)
+	prometheus.MustRegister(peerRESTRequestsFailures)
+	prometheus.MustRegister(lockWaitDuration)
+	prometheus.MustRegister(lockContentions)
 	prometheus.MustRegister(newMinioCollector())
 	prometheus.MustRegister(minioVersionInfo)
 }
@@ -101,6 +136,33 @@ func (c *minioCollector) Collect(ch chan<- prometheus.Metric) {
 		float64(globalConnStats.getTotalInputBytes()),
 	)
 
+	// S3 API requests in flight and their latency percentiles
+	for api, stats := range globalHTTPStats.listAPIStats() {
+		ch <- prometheus.MustNewConstMetric(
+			prometheus.NewDesc(
+				prometheus.BuildFQName("minio", "s3", "requests_current"),
+				"Total number of S3 requests in flight on current MinIO server instance",
+				[]string{"api"}, nil),
+			prometheus.GaugeValue,
+			float64(stats.InFlight.Load()),
+			api,
+		)
+		ch <- prometheus.MustNewConstSummary(
+			prometheus.NewDesc(
+				prometheus.BuildFQName("minio", "s3", "requests_latency_seconds"),
+				"Latency of S3 requests served by current MinIO server instance",
+				[]string{"api"}, nil),
+			stats.Counter.Load(),
+			stats.Duration.Load(),
+			map[float64]float64{
+				0.5:  stats.percentile(0.5),
+				0.95: stats.percentile(0.95),
+				0.99: stats.percentile(0.99),
+			},
+			api,
+		)
+	}
+
 	// Expose cache stats only if available
 	cacheObjLayer := newCacheObjectsFn()
 	if cacheObjLayer != nil {
@@ -121,6 +183,75 @@ func (c *minioCollector) Collect(ch chan<- prometheus.Metric) {
 			prometheus.GaugeValue,
 			float64(cs.Free),
 		)
+		stats := cacheObjLayer.Stats()
+		ch <- prometheus.MustNewConstMetric(
+			prometheus.NewDesc(
+				prometheus.BuildFQName("minio", "disk", "cache_corrupted_total"),
+				"Total number of cached entries which failed their bitrot check on read on current MinIO server instance",
+				nil, nil),
+			prometheus.CounterValue,
+			float64(stats.Corrupted),
+		)
+		ch <- prometheus.MustNewConstMetric(
+			prometheus.NewDesc(
+				prometheus.BuildFQName("minio", "disk", "cache_healed_total"),
+				"Total number of reads of corrupted cached entries completed from the backend on current MinIO server instance",
+				nil, nil),
+			prometheus.CounterValue,
+			float64(stats.Healed),
+		)
+		ch <- prometheus.MustNewConstMetric(
+			prometheus.NewDesc(
+				prometheus.BuildFQName("minio", "disk", "cache_coalesced_total"),
+				"Total number of cache misses served by the cache fill of a concurrent miss on current MinIO server instance",
+				nil, nil),
+			prometheus.CounterValue,
+			float64(stats.Coalesced),
+		)
+		for _, ds := range cacheObjLayer.DriveStats(context.Background()) {
+			ch <- prometheus.MustNewConstMetric(
+				prometheus.NewDesc(
+					prometheus.BuildFQName("minio", "disk", "cache_drive_usage_percent"),
+					"Used space of the cache drive in percent",
+					[]string{"drive"}, nil),
+				prometheus.GaugeValue,
+				ds.UsagePercent,
+				ds.Drive,
+			)
+			var hitRatio float64
+			if reads := ds.Hits + ds.Misses; reads > 0 {
+				hitRatio = float64(ds.Hits) / float64(reads)
+			}
+			ch <- prometheus.MustNewConstMetric(
+				prometheus.NewDesc(
+					prometheus.BuildFQName("minio", "disk", "cache_drive_hit_ratio"),
+					"Ratio of the reads of the cache drive served from the cache since the server started",
+					[]string{"drive"}, nil),
+				prometheus.GaugeValue,
+				hitRatio,
+				ds.Drive,
+			)
+			for _, c := range []struct {
+				name, help string
+				value      uint64
+			}{
+				{"cache_drive_hits_total", "Total number of reads served from the cache drive", ds.Hits},
+				{"cache_drive_misses_total", "Total number of reads of objects not cached on the cache drive", ds.Misses},
+				{"cache_drive_fills_total", "Total number of objects added to the cache drive", ds.Fills},
+				{"cache_drive_evictions_total", "Total number of entries evicted from the cache drive to free space", ds.Evictions},
+				{"cache_drive_io_errors_total", "Total number of failed reads and writes of the cache drive", ds.IOErrors},
+			} {
+				ch <- prometheus.MustNewConstMetric(
+					prometheus.NewDesc(
+						prometheus.BuildFQName("minio", "disk", c.name),
+						c.help,
+						[]string{"drive"}, nil),
+					prometheus.CounterValue,
+					float64(c.value),
+					ds.Drive,
+				)
+			}
+		}
 	}
 
 	// Expose disk stats only if applicable
@@ -223,3 +354,21 @@ func metricsHandler() http.Handler {
 			}),
 	)
 }
+
+// Returns the lock type label of the lock metrics.
+func getLockTypeLabel(readLock bool) string {
+	if readLock {
+		return "read"
+	}
+	return "write"
+}
+
+// observeLockWait - records the time spent waiting for a namespace
+// lock since start, and whether the lock was acquired or timed out.
+func observeLockWait(readLock bool, start time.Time, acquired bool) {
+	result := "acquired"
+	if !acquired {
+		result = "timedout"
+	}
+	lockWaitDuration.WithLabelValues(getLockTypeLabel(readLock), result).Observe(UTCNow().Sub(start).Seconds())
+}
@@ -0,0 +1,124 @@
+/*
+ * MinIO Cloud Storage, (C) 2020 MinIO, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	xnet "github.com/minio/minio/pkg/net"
+)
+
+// Returns the Prometheus output of the metrics handler.
+func getMetricsOutput(t *testing.T) string {
+	rec := httptest.NewRecorder()
+	req, err := http.NewRequest(http.MethodGet, "/minio/prometheus/metrics", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	metricsHandler().ServeHTTP(rec, req)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("Expected the response status to be 200, but instead found `%d`", rec.Code)
+	}
+	return rec.Body.String()
+}
+
+func TestPeerRESTCallMetrics(t *testing.T) {
+	obj, fsDir, err := prepareFS()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(fsDir)
+	if err = newTestConfig(globalMinioDefaultRegion, obj); err != nil {
+		t.Fatal(err)
+	}
+
+	// The peer answers server info calls and rejects all others.
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if strings.HasSuffix(r.URL.Path, SlashSeparator+peerRESTMethodServerInfo) {
+			return
+		}
+		w.WriteHeader(http.StatusForbidden)
+	}))
+	defer server.Close()
+
+	host, err := xnet.ParseHost(server.Listener.Addr().String())
+	if err != nil {
+		t.Fatal(err)
+	}
+	client, err := newPeerRESTClient(host)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer client.Close()
+
+	respBody, err := client.call(peerRESTMethodServerInfo, nil, nil, -1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	respBody.Close()
+	if _, err = client.call(peerRESTMethodLoadUsers, nil, nil, -1); err == nil {
+		t.Fatal("Expected the peer call to fail")
+	}
+
+	output := getMetricsOutput(t)
+	for _, metric := range []string{
+		fmt.Sprintf(`minio_peer_rest_requests_duration_seconds_count{method="%s",peer="%s"} 1`, peerRESTMethodServerInfo, host),
+		fmt.Sprintf(`minio_peer_rest_requests_duration_seconds_count{method="%s",peer="%s"} 1`, peerRESTMethodLoadUsers, host),
+		fmt.Sprintf(`minio_peer_rest_requests_failures_total{method="%s",peer="%s"} 1`, peerRESTMethodLoadUsers, host),
+	} {
+		if !strings.Contains(output, metric+"\n") {
+			t.Errorf("Expected %q in the metrics output", metric)
+		}
+	}
+	if strings.Contains(output, fmt.Sprintf(`minio_peer_rest_requests_failures_total{method="%s",peer="%s"}`, peerRESTMethodServerInfo, host)) {
+		t.Errorf("Expected no failures of %s in the metrics output", peerRESTMethodServerInfo)
+	}
+}
+
+func TestLockMetrics(t *testing.T) {
+	ns := newNSLock(false)
+	timeout := newDynamicTimeout(100*time.Millisecond, 100*time.Millisecond)
+
+	lk := ns.NewNSLock(context.Background(), "bucket", "object")
+	if err := lk.GetLock(timeout); err != nil {
+		t.Fatal(err)
+	}
+	defer lk.Unlock()
+
+	// A second writer waits on the held lock and times out.
+	if err := ns.NewNSLock(context.Background(), "bucket", "object").GetLock(timeout); err == nil {
+		t.Fatal("Expected the lock to time out")
+	}
+
+	output := getMetricsOutput(t)
+	for _, metric := range []string{
+		`minio_lock_wait_duration_seconds_count{lock_type="write",result="acquired"}`,
+		`minio_lock_wait_duration_seconds_count{lock_type="write",result="timedout"}`,
+		`minio_lock_contentions_total{lock_type="write"}`,
+	} {
+		if !strings.Contains(output, metric+" ") {
+			t.Errorf("Expected %q in the metrics output", metric)
+		}
+	}
+}
@@ -142,6 +142,7 @@ func (n *nsLockMap) lock(ctx context.Context, volume, path string, lockSource, o
 	} else {
 		// Update ref count here to avoid multiple races.
 		nsLk.ref++
+		lockContentions.WithLabelValues(getLockTypeLabel(readLock)).Inc()
 	}
 	n.lockMapMutex.Unlock()
 
@@ -260,9 +261,11 @@ func (di *distLockInstance) GetLock(timeout *dynamicTimeout) (timedOutErr error)
 	start := UTCNow()
 
 	if !di.rwMutex.GetLock(di.opsID, lockSource, timeout.Timeout()) {
+		observeLockWait(false, start, false)
 		timeout.LogFailure()
 		return OperationTimedOut{Path: di.path}
 	}
+	observeLockWait(false, start, true)
 	timeout.LogSuccess(UTCNow().Sub(start))
 	return nil
 }
@@ -277,9 +280,11 @@ func (di *distLockInstance) GetRLock(timeout *dynamicTimeout) (timedOutErr error
 	lockSource := getSource()
 	start := UTCNow()
 	if !di.rwMutex.GetRLock(di.opsID, lockSource, timeout.Timeout()) {
+		observeLockWait(true, start, false)
 		timeout.LogFailure()
 		return OperationTimedOut{Path: di.path}
 	}
+	observeLockWait(true, start, true)
 	timeout.LogSuccess(UTCNow().Sub(start))
 	return nil
 }
@@ -313,9 +318,11 @@ func (li *localLockInstance) GetLock(timeout *dynamicTimeout) (timedOutErr error
 	start := UTCNow()
 	readLock := false
 	if !li.ns.lock(li.ctx, li.volume, li.path, lockSource, li.opsID, readLock, timeout.Timeout()) {
+		observeLockWait(readLock, start, false)
 		timeout.LogFailure()
 		return OperationTimedOut{Path: li.path}
 	}
+	observeLockWait(readLock, start, true)
 	timeout.LogSuccess(UTCNow().Sub(start))
 	return
 }
@@ -332,9 +339,11 @@ func (li *localLockInstance) GetRLock(timeout *dynamicTimeout) (timedOutErr erro
 	start := UTCNow()
 	readLock := true
 	if !li.ns.lock(li.ctx, li.volume, li.path, lockSource, li.opsID, readLock, timeout.Timeout()) {
+		observeLockWait(readLock, start, false)
 		timeout.LogFailure()
 		return OperationTimedOut{Path: li.path}
 	}
+	observeLockWait(readLock, start, true)
 	timeout.LogSuccess(UTCNow().Sub(start))
 	return
 }
@@ -0,0 +1,136 @@
+/*
+ * MinIO Cloud Storage, (C) 2020 MinIO, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/minio/minio/pkg/event"
+	"github.com/minio/minio/pkg/lifecycle"
+	"github.com/minio/minio/pkg/policy"
+)
+
+const (
+	// Bucket updates are collected for this long before being sent
+	// to the peers, bulk bucket operations then cost one call per
+	// peer instead of one per bucket.
+	bucketUpdateBatchDelay = 100 * time.Millisecond
+
+	// Maximum number of bucket updates sent in a single call.
+	maxBucketUpdateBatch = 1000
+)
+
+// bucketUpdate - an update of the policy, the lifecycle or the
+// notification configuration of a bucket, Kind is one of the bucket
+// config event kinds.
+type bucketUpdate struct {
+	Kind      string
+	Bucket    string
+	Remove    bool
+	Policy    *policy.Policy
+	Lifecycle *lifecycle.Lifecycle
+	RulesMap  event.RulesMap
+}
+
+// bucketUpdateBatcher - coalesces the bucket updates sent to the peers,
+// only the latest update of each kind for a bucket is sent. Batches are
+// sent one at a time, so peers apply the updates in order.
+type bucketUpdateBatcher struct {
+	mu      sync.Mutex
+	pending map[string]bucketUpdate
+	// Keys of pending in the order of their first update.
+	order   []string
+	running bool
+}
+
+// add - queues an update, send is called in the background with the
+// batches of updates.
+func (b *bucketUpdateBatcher) add(update bucketUpdate, send func([]bucketUpdate)) {
+	key := update.Kind + SlashSeparator + update.Bucket
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.pending == nil {
+		b.pending = make(map[string]bucketUpdate)
+	}
+	if _, ok := b.pending[key]; !ok {
+		b.order = append(b.order, key)
+	}
+	b.pending[key] = update
+
+	if !b.running {
+		b.running = true
+		go b.run(send)
+	}
+}
+
+// run - sends the pending updates until there are none left.
+func (b *bucketUpdateBatcher) run(send func([]bucketUpdate)) {
+	for {
+		time.Sleep(bucketUpdateBatchDelay)
+
+		b.mu.Lock()
+		if len(b.order) == 0 {
+			b.running = false
+			b.mu.Unlock()
+			return
+		}
+		updates := make([]bucketUpdate, 0, len(b.order))
+		for _, key := range b.order {
+			updates = append(updates, b.pending[key])
+		}
+		b.pending, b.order = nil, nil
+		b.mu.Unlock()
+
+		for len(updates) > 0 {
+			n := len(updates)
+			if n > maxBucketUpdateBatch {
+				n = maxBucketUpdateBatch
+			}
+			send(updates[:n])
+			updates = updates[n:]
+		}
+	}
+}
+
+// applyBucketUpdates - applies bucket updates sent by a peer.
+func applyBucketUpdates(updates []bucketUpdate) error {
+	for _, update := range updates {
+		switch update.Kind {
+		case configEventBucketPolicy:
+			if update.Remove || update.Policy == nil {
+				globalPolicySys.Remove(update.Bucket)
+			} else {
+				globalPolicySys.Set(update.Bucket, *update.Policy)
+			}
+		case configEventBucketLifecycle:
+			if update.Remove || update.Lifecycle == nil {
+				globalLifecycleSys.Remove(update.Bucket)
+			} else {
+				globalLifecycleSys.Set(update.Bucket, *update.Lifecycle)
+			}
+		case configEventBucketNotification:
+			globalNotificationSys.AddRulesMap(update.Bucket, update.RulesMap)
+		default:
+			return fmt.Errorf("unknown bucket update %s", update.Kind)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,88 @@
+/*
+ * MinIO Cloud Storage, (C) 2020 MinIO, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/minio/minio/pkg/policy"
+)
+
+// Tests that bucket updates made in a burst are sent in a single batch,
+// keeping only the latest update of each kind for a bucket.
+func TestBucketUpdateBatcher(t *testing.T) {
+	batchCh := make(chan []bucketUpdate, 10)
+	send := func(updates []bucketUpdate) {
+		batchCh <- updates
+	}
+
+	var b bucketUpdateBatcher
+	p := &policy.Policy{Version: policy.DefaultVersion}
+	b.add(bucketUpdate{Kind: configEventBucketPolicy, Bucket: "a", Policy: p}, send)
+	b.add(bucketUpdate{Kind: configEventBucketLifecycle, Bucket: "a", Remove: true}, send)
+	b.add(bucketUpdate{Kind: configEventBucketPolicy, Bucket: "b", Policy: p}, send)
+	b.add(bucketUpdate{Kind: configEventBucketPolicy, Bucket: "a", Remove: true}, send)
+
+	var updates []bucketUpdate
+	select {
+	case updates = <-batchCh:
+	case <-time.After(10 * time.Second):
+		t.Fatal("Expected a batch of bucket updates")
+	}
+	expected := []bucketUpdate{
+		{Kind: configEventBucketPolicy, Bucket: "a", Remove: true},
+		{Kind: configEventBucketLifecycle, Bucket: "a", Remove: true},
+		{Kind: configEventBucketPolicy, Bucket: "b", Policy: p},
+	}
+	if fmt.Sprint(updates) != fmt.Sprint(expected) {
+		t.Fatalf("Expected %v, got %v", expected, updates)
+	}
+
+	// Updates made after a batch was sent go in a new batch.
+	b.add(bucketUpdate{Kind: configEventBucketNotification, Bucket: "c"}, send)
+	select {
+	case updates = <-batchCh:
+	case <-time.After(10 * time.Second):
+		t.Fatal("Expected a batch of bucket updates")
+	}
+	if len(updates) != 1 || updates[0].Bucket != "c" {
+		t.Fatalf("Unexpected bucket updates %v", updates)
+	}
+
+	// Large batches are split.
+	for running := true; running; {
+		time.Sleep(bucketUpdateBatchDelay)
+		b.mu.Lock()
+		running = b.running
+		b.mu.Unlock()
+	}
+	for i := 0; i < maxBucketUpdateBatch+1; i++ {
+		b.add(bucketUpdate{Kind: configEventBucketPolicy, Bucket: fmt.Sprintf("bucket%d", i), Remove: true}, send)
+	}
+	for _, n := range []int{maxBucketUpdateBatch, 1} {
+		select {
+		case updates = <-batchCh:
+		case <-time.After(10 * time.Second):
+			t.Fatal("Expected a batch of bucket updates")
+		}
+		if len(updates) != n {
+			t.Fatalf("Expected %d bucket updates, got %d", n, len(updates))
+		}
+	}
+}
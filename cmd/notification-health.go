@@ -0,0 +1,169 @@
+/*
+ * MinIO Cloud Storage, (C) 2019 MinIO, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"errors"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/minio/minio/pkg/event"
+	"github.com/minio/minio/pkg/madmin"
+)
+
+// Interval between two health checks of the notification targets.
+const targetHealthCheckInterval = time.Minute
+
+// Key of the object of the test events sent to notification targets,
+// test events are also marked by the x-minio-test-event response
+// element.
+const notificationTestObject = "minio-test-event"
+
+var errNotificationTargetNotFound = errors.New("Notification target not found")
+
+// checkTargetsHealth - pings all external notification targets which
+// support it and records whether they are up.
+func (sys *NotificationSys) checkTargetsHealth() {
+	region := globalServerConfig.GetRegion()
+
+	checked := make(map[event.TargetID]struct{})
+	var wg sync.WaitGroup
+	for _, target := range sys.targetList.Targets() {
+		id := target.ID()
+		// ListenBucketNotification targets are not external.
+		if strings.HasPrefix(id.ID, "httpclient+") {
+			continue
+		}
+		checked[id] = struct{}{}
+		wg.Add(1)
+		go func(id event.TargetID, target event.Target) {
+			defer wg.Done()
+
+			status, err := madmin.TargetStatusUnknown, error(nil)
+			if pinger, ok := target.(event.Pinger); ok {
+				status = madmin.TargetStatusUp
+				if err = pinger.Ping(); err != nil {
+					status = madmin.TargetStatusDown
+				}
+			}
+
+			sys.targetHealthMu.Lock()
+			defer sys.targetHealthMu.Unlock()
+
+			// The last error is kept once the target is up again.
+			health := sys.targetHealth[id]
+			health.ARN = id.ToARN(region).String()
+			health.Status = status
+			health.LastCheck = UTCNow()
+			if err != nil {
+				health.LastError = err.Error()
+				health.LastErrorTime = health.LastCheck
+			}
+			sys.targetHealth[id] = health
+		}(id, target)
+	}
+	wg.Wait()
+
+	// Forget targets which were removed.
+	sys.targetHealthMu.Lock()
+	defer sys.targetHealthMu.Unlock()
+	for id := range sys.targetHealth {
+		if _, ok := checked[id]; !ok {
+			delete(sys.targetHealth, id)
+		}
+	}
+}
+
+// startTargetHealthCheck - checks the health of the notification
+// targets right away and then every targetHealthCheckInterval until
+// doneCh is closed.
+func (sys *NotificationSys) startTargetHealthCheck(doneCh <-chan struct{}) {
+	ticker := time.NewTicker(targetHealthCheckInterval)
+	defer ticker.Stop()
+
+	for {
+		sys.checkTargetsHealth()
+		select {
+		case <-doneCh:
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// TargetsHealth - returns the health of the notification targets as
+// last checked by this server, sorted by ARN.
+func (sys *NotificationSys) TargetsHealth() []madmin.TargetHealth {
+	sys.targetHealthMu.RLock()
+	defer sys.targetHealthMu.RUnlock()
+
+	targets := make([]madmin.TargetHealth, 0, len(sys.targetHealth))
+	for _, health := range sys.targetHealth {
+		targets = append(targets, health)
+	}
+	sort.Slice(targets, func(i, j int) bool { return targets[i].ARN < targets[j].ARN })
+	return targets
+}
+
+// GetTarget - returns the external notification target with the given
+// ARN.
+func (sys *NotificationSys) GetTarget(arn string) (event.Target, error) {
+	region := globalServerConfig.GetRegion()
+	for _, target := range sys.targetList.Targets() {
+		id := target.ID()
+		if !strings.HasPrefix(id.ID, "httpclient+") && id.ToARN(region).String() == arn {
+			return target, nil
+		}
+	}
+	return nil, errNotificationTargetNotFound
+}
+
+// testNotificationTarget - sends a test event to target and returns
+// how long it took. Targets with a queue store only save the event to
+// be sent later, the targets which support it are pinged first so that
+// unreachable targets are reported nonetheless.
+func testNotificationTarget(target event.Target, args eventArgs) (time.Duration, error) {
+	args.EventName = event.ObjectCreatedPut
+	args.Object = ObjectInfo{Bucket: args.BucketName, Name: notificationTestObject}
+	eventData := args.ToEvent()
+	eventData.ResponseElements["x-minio-test-event"] = "true"
+
+	start := time.Now()
+	if pinger, ok := target.(event.Pinger); ok {
+		if err := pinger.Ping(); err != nil {
+			return time.Since(start), err
+		}
+	}
+	err := target.Save(eventData)
+	return time.Since(start), err
+}
+
+// localTargetsHealth - returns the health of the notification targets
+// as last checked by this server.
+func localTargetsHealth(r *http.Request) madmin.ServerTargetsHealth {
+	addr := r.Host
+	if globalIsDistXL {
+		addr = GetLocalPeer(globalEndpoints)
+	}
+	return madmin.ServerTargetsHealth{
+		Addr:    addr,
+		Targets: globalNotificationSys.TargetsHealth(),
+	}
+}
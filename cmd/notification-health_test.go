@@ -0,0 +1,149 @@
+/*
+ * MinIO Cloud Storage, (C) 2019 MinIO, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"errors"
+	"os"
+	"testing"
+
+	"github.com/minio/minio/pkg/event"
+	"github.com/minio/minio/pkg/madmin"
+)
+
+// pingTarget - a target whose health is set by the test.
+type pingTarget struct {
+	eventsTarget
+	err error
+}
+
+func (t *pingTarget) Ping() error { return t.err }
+
+func TestNotificationSysTargetsHealth(t *testing.T) {
+	obj, fsDir, err := prepareFS()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(fsDir)
+	if err = newTestConfig("us-east-1", obj); err != nil {
+		t.Fatal(err)
+	}
+
+	errDown := errors.New("connection refused")
+	up := &pingTarget{eventsTarget: eventsTarget{id: event.TargetID{ID: "1", Name: "webhook"}}}
+	down := &pingTarget{eventsTarget: eventsTarget{id: event.TargetID{ID: "2", Name: "webhook"}}, err: errDown}
+	unknown := &eventsTarget{id: event.TargetID{ID: "1", Name: "nats"}}
+	listener := &eventsTarget{id: event.TargetID{ID: "httpclient+1+127.0.0.1:9000", Name: "httpclient"}}
+
+	sys := &NotificationSys{
+		targetList:   event.NewTargetList(),
+		targetHealth: make(map[event.TargetID]madmin.TargetHealth),
+	}
+	for _, target := range []event.Target{up, down, unknown, listener} {
+		if err = sys.targetList.Add(target); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	sys.checkTargetsHealth()
+	health := sys.TargetsHealth()
+	if len(health) != 3 {
+		t.Fatalf("Expected 3 targets, got %v", health)
+	}
+	expected := []struct {
+		arn    string
+		status madmin.TargetStatus
+		err    string
+	}{
+		{"arn:minio:sqs:us-east-1:1:nats", madmin.TargetStatusUnknown, ""},
+		{"arn:minio:sqs:us-east-1:1:webhook", madmin.TargetStatusUp, ""},
+		{"arn:minio:sqs:us-east-1:2:webhook", madmin.TargetStatusDown, errDown.Error()},
+	}
+	for i, e := range expected {
+		if health[i].ARN != e.arn || health[i].Status != e.status || health[i].LastError != e.err || health[i].LastCheck.IsZero() {
+			t.Errorf("Test %d: unexpected health %v", i+1, health[i])
+		}
+	}
+
+	// The last error is kept once the target is up again.
+	down.err = nil
+	sys.checkTargetsHealth()
+	health = sys.TargetsHealth()
+	if health[2].Status != madmin.TargetStatusUp || health[2].LastError != errDown.Error() || health[2].LastErrorTime.IsZero() {
+		t.Errorf("Unexpected health %v", health[2])
+	}
+
+	// Removed targets are forgotten.
+	for range sys.targetList.Remove(down.ID()) {
+	}
+	sys.checkTargetsHealth()
+	if health = sys.TargetsHealth(); len(health) != 2 {
+		t.Errorf("Expected 2 targets, got %v", health)
+	}
+}
+
+func TestNotificationSysTestTarget(t *testing.T) {
+	obj, fsDir, err := prepareFS()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(fsDir)
+	if err = newTestConfig("us-east-1", obj); err != nil {
+		t.Fatal(err)
+	}
+
+	errDown := errors.New("connection refused")
+	up := &pingTarget{eventsTarget: eventsTarget{id: event.TargetID{ID: "1", Name: "webhook"}}}
+	down := &pingTarget{eventsTarget: eventsTarget{id: event.TargetID{ID: "2", Name: "webhook"}}, err: errDown}
+	listener := &eventsTarget{id: event.TargetID{ID: "httpclient+1+127.0.0.1:9000", Name: "httpclient"}}
+
+	sys := &NotificationSys{targetList: event.NewTargetList()}
+	for _, target := range []event.Target{up, down, listener} {
+		if err = sys.targetList.Add(target); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	target, err := sys.GetTarget("arn:minio:sqs:us-east-1:1:webhook")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err = testNotificationTarget(target, eventArgs{BucketName: "bucket"}); err != nil {
+		t.Fatal(err)
+	}
+	events := up.wait(t, 1)
+	if events[0].S3.Bucket.Name != "bucket" || events[0].S3.Object.Key != notificationTestObject || events[0].ResponseElements["x-minio-test-event"] != "true" {
+		t.Fatalf("Unexpected test event %v", events[0])
+	}
+
+	// Unreachable targets are reported without saving the event.
+	if target, err = sys.GetTarget("arn:minio:sqs:us-east-1:2:webhook"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err = testNotificationTarget(target, eventArgs{BucketName: "bucket"}); err != errDown {
+		t.Fatalf("Expected %v, got %v", errDown, err)
+	}
+	if len(down.events) != 0 {
+		t.Fatalf("Expected no event to be saved, got %v", down.events)
+	}
+
+	for _, arn := range []string{"arn:minio:sqs:us-east-1:3:webhook", listener.ID().ToARN("us-east-1").String()} {
+		if _, err = sys.GetTarget(arn); err != errNotificationTargetNotFound {
+			t.Errorf("%s: expected %v, got %v", arn, errNotificationTargetNotFound, err)
+		}
+	}
+}
@@ -33,11 +33,13 @@ import (
 
 	"github.com/minio/minio/cmd/crypto"
 	"github.com/minio/minio/cmd/logger"
+	"github.com/minio/minio/pkg/cors"
 	"github.com/minio/minio/pkg/event"
 	"github.com/minio/minio/pkg/lifecycle"
 	"github.com/minio/minio/pkg/madmin"
 	xnet "github.com/minio/minio/pkg/net"
 	"github.com/minio/minio/pkg/policy"
+	"github.com/minio/minio/pkg/website"
 )
 
 // NotificationSys - notification system.
@@ -47,6 +49,13 @@ type NotificationSys struct {
 	bucketRulesMap             map[string]event.RulesMap
 	bucketRemoteTargetRulesMap map[string]map[event.TargetID]event.RulesMap
 	peerClients                []*peerRESTClient
+
+	// Health of the targets, refreshed by startTargetHealthCheck.
+	targetHealthMu sync.RWMutex
+	targetHealth   map[event.TargetID]madmin.TargetHealth
+
+	// Bucket updates sent to the peers in batches.
+	bucketUpdates bucketUpdateBatcher
 }
 
 // GetARNList - returns available ARNs.
@@ -247,6 +256,72 @@ func (sys *NotificationSys) LoadUsers() []NotificationPeerErr {
 	return ng.Wait()
 }
 
+// LoadBandwidthLimits - calls LoadBandwidthLimits RPC call on all peers.
+func (sys *NotificationSys) LoadBandwidthLimits() []NotificationPeerErr {
+	ng := WithNPeers(len(sys.peerClients))
+	for idx, client := range sys.peerClients {
+		if client == nil {
+			continue
+		}
+		client := client
+		ng.Go(context.Background(), client.LoadBandwidthLimits, idx, *client.host)
+	}
+	return ng.Wait()
+}
+
+// LoadBucketModes - calls LoadBucketModes RPC call on all peers.
+func (sys *NotificationSys) LoadBucketModes() []NotificationPeerErr {
+	ng := WithNPeers(len(sys.peerClients))
+	for idx, client := range sys.peerClients {
+		if client == nil {
+			continue
+		}
+		client := client
+		ng.Go(context.Background(), client.LoadBucketModes, idx, *client.host)
+	}
+	return ng.Wait()
+}
+
+// LoadBucketKeyNormalization - calls LoadBucketKeyNormalization RPC
+// call on all peers.
+func (sys *NotificationSys) LoadBucketKeyNormalization() []NotificationPeerErr {
+	ng := WithNPeers(len(sys.peerClients))
+	for idx, client := range sys.peerClients {
+		if client == nil {
+			continue
+		}
+		client := client
+		ng.Go(context.Background(), client.LoadBucketKeyNormalization, idx, *client.host)
+	}
+	return ng.Wait()
+}
+
+// LoadCacheConfig - calls LoadCacheConfig RPC call on all peers.
+func (sys *NotificationSys) LoadCacheConfig() []NotificationPeerErr {
+	ng := WithNPeers(len(sys.peerClients))
+	for idx, client := range sys.peerClients {
+		if client == nil {
+			continue
+		}
+		client := client
+		ng.Go(context.Background(), client.LoadCacheConfig, idx, *client.host)
+	}
+	return ng.Wait()
+}
+
+// LoadCredentials - calls LoadCredentials RPC call on all peers.
+func (sys *NotificationSys) LoadCredentials() []NotificationPeerErr {
+	ng := WithNPeers(len(sys.peerClients))
+	for idx, client := range sys.peerClients {
+		if client == nil {
+			continue
+		}
+		client := client
+		ng.Go(context.Background(), client.LoadCredentials, idx, *client.host)
+	}
+	return ng.Wait()
+}
+
 // LoadGroup - loads a specific group on all peers.
 func (sys *NotificationSys) LoadGroup(group string) []NotificationPeerErr {
 	ng := WithNPeers(len(sys.peerClients))
@@ -405,6 +480,20 @@ func (sys *NotificationSys) DownloadProfilingData(ctx context.Context, writer io
 	return profilingDataFound
 }
 
+// Diagnostics - returns goroutine, heap and thread creation snapshots
+// of the node with the given address, this node if it is empty.
+func (sys *NotificationSys) Diagnostics(node string) ([]byte, error) {
+	if node == "" || node == GetLocalPeer(globalEndpoints) {
+		return getDiagnosticsData()
+	}
+	for _, client := range sys.peerClients {
+		if client != nil && client.host.String() == node {
+			return client.Diagnostics()
+		}
+	}
+	return nil, errDiagnosticsUnknownNode
+}
+
 // SignalService - calls signal service RPC call on all peers.
 func (sys *NotificationSys) SignalService(sig serviceSignal) []NotificationPeerErr {
 	ng := WithNPeers(len(sys.peerClients))
@@ -503,9 +592,372 @@ func (sys *NotificationSys) GetLocks(ctx context.Context) []*PeerLocks {
 	return locksResp
 }
 
-// SetBucketPolicy - calls SetBucketPolicy RPC call on all peers.
+// ListRequests - makes ListRequests RPC call on all peers.
+func (sys *NotificationSys) ListRequests(ctx context.Context) []madmin.RequestEntry {
+	requests := make([][]madmin.RequestEntry, len(sys.peerClients))
+	var wg sync.WaitGroup
+	for index, client := range sys.peerClients {
+		if client == nil {
+			continue
+		}
+		wg.Add(1)
+		go func(idx int, client *peerRESTClient) {
+			defer wg.Done()
+			peerRequests, err := client.ListRequests()
+			if err != nil {
+				reqInfo := (&logger.ReqInfo{}).AppendTags("peerAddress", client.host.String())
+				ctx := logger.SetReqInfo(ctx, reqInfo)
+				logger.LogOnceIf(ctx, err, client.host.String())
+				return
+			}
+			requests[idx] = peerRequests
+		}(index, client)
+	}
+	wg.Wait()
+
+	var allRequests []madmin.RequestEntry
+	for _, peerRequests := range requests {
+		allRequests = append(allRequests, peerRequests...)
+	}
+	return allRequests
+}
+
+// AccessKeyUsage - makes AccessKeyUsage RPC call on all peers.
+func (sys *NotificationSys) AccessKeyUsage(ctx context.Context) []madmin.AccessKeyUsage {
+	usages := make([][]madmin.AccessKeyUsage, len(sys.peerClients))
+	var wg sync.WaitGroup
+	for index, client := range sys.peerClients {
+		if client == nil {
+			continue
+		}
+		wg.Add(1)
+		go func(idx int, client *peerRESTClient) {
+			defer wg.Done()
+			peerUsages, err := client.AccessKeyUsage()
+			if err != nil {
+				reqInfo := (&logger.ReqInfo{}).AppendTags("peerAddress", client.host.String())
+				ctx := logger.SetReqInfo(ctx, reqInfo)
+				logger.LogOnceIf(ctx, err, client.host.String())
+				return
+			}
+			usages[idx] = peerUsages
+		}(index, client)
+	}
+	wg.Wait()
+
+	var allUsages []madmin.AccessKeyUsage
+	for _, peerUsages := range usages {
+		allUsages = append(allUsages, peerUsages...)
+	}
+	return allUsages
+}
+
+// GetIAMChanges - fetches the IAM changes made on all peers after the
+// versions of since, keyed by peer address. Unreachable peers are left
+// out.
+func (sys *NotificationSys) GetIAMChanges(since map[string]iamChanges) map[string]iamChanges {
+	changes := make([]*iamChanges, len(sys.peerClients))
+	var wg sync.WaitGroup
+	for index, client := range sys.peerClients {
+		if client == nil {
+			continue
+		}
+		wg.Add(1)
+		go func(idx int, client *peerRESTClient) {
+			defer wg.Done()
+			version := since[client.host.String()]
+			peerChanges, err := client.GetIAMChanges(version.Epoch, version.Version)
+			if err != nil {
+				reqInfo := (&logger.ReqInfo{}).AppendTags("peerAddress", client.host.String())
+				ctx := logger.SetReqInfo(context.Background(), reqInfo)
+				logger.LogOnceIf(ctx, err, client.host.String())
+				return
+			}
+			changes[idx] = &peerChanges
+		}(index, client)
+	}
+	wg.Wait()
+
+	peerChanges := make(map[string]iamChanges)
+	for index, client := range sys.peerClients {
+		if changes[index] != nil {
+			peerChanges[client.host.String()] = *changes[index]
+		}
+	}
+	return peerChanges
+}
+
+// KMSKeySweepStatus - returns the KMS key re-encryption sweep state of all peers.
+func (sys *NotificationSys) KMSKeySweepStatus(ctx context.Context) []madmin.KMSKeySweepStatus {
+	statuses := make([]*madmin.KMSKeySweepStatus, len(sys.peerClients))
+	var wg sync.WaitGroup
+	for index, client := range sys.peerClients {
+		if client == nil {
+			continue
+		}
+		wg.Add(1)
+		go func(idx int, client *peerRESTClient) {
+			defer wg.Done()
+			status, err := client.KMSKeySweepStatus()
+			if err != nil {
+				reqInfo := (&logger.ReqInfo{}).AppendTags("peerAddress", client.host.String())
+				ctx := logger.SetReqInfo(ctx, reqInfo)
+				logger.LogOnceIf(ctx, err, client.host.String())
+				return
+			}
+			statuses[idx] = &status
+		}(index, client)
+	}
+	wg.Wait()
+
+	var allStatuses []madmin.KMSKeySweepStatus
+	for _, status := range statuses {
+		if status != nil {
+			allStatuses = append(allStatuses, *status)
+		}
+	}
+	return allStatuses
+}
+
+// BatchJobsStatus - returns the state of the batch jobs of all peers.
+func (sys *NotificationSys) BatchJobsStatus(ctx context.Context) []madmin.BatchJobStatus {
+	statuses := make([][]madmin.BatchJobStatus, len(sys.peerClients))
+	var wg sync.WaitGroup
+	for index, client := range sys.peerClients {
+		if client == nil {
+			continue
+		}
+		wg.Add(1)
+		go func(idx int, client *peerRESTClient) {
+			defer wg.Done()
+			peerStatuses, err := client.BatchJobsStatus()
+			if err != nil {
+				reqInfo := (&logger.ReqInfo{}).AppendTags("peerAddress", client.host.String())
+				ctx := logger.SetReqInfo(ctx, reqInfo)
+				logger.LogOnceIf(ctx, err, client.host.String())
+				return
+			}
+			statuses[idx] = peerStatuses
+		}(index, client)
+	}
+	wg.Wait()
+
+	var allStatuses []madmin.BatchJobStatus
+	for _, peerStatuses := range statuses {
+		allStatuses = append(allStatuses, peerStatuses...)
+	}
+	return allStatuses
+}
+
+// StartBatchOperationJob - starts a batch operation job on all peers,
+// each peer processes the share of the objects at its index.
+func (sys *NotificationSys) StartBatchOperationJob(id string, job madmin.BatchOperationJob, shares [][]madmin.BatchObject) []NotificationPeerErr {
+	ng := WithNPeers(len(sys.peerClients))
+	for idx, client := range sys.peerClients {
+		if client == nil {
+			continue
+		}
+		client := client
+		peerJob := job
+		peerJob.Objects = shares[idx]
+		ng.Go(context.Background(), func() error { return client.StartBatchOperationJob(id, peerJob) }, idx, *client.host)
+	}
+	return ng.Wait()
+}
+
+// CancelBatchJob - makes CancelBatchJob RPC call on all peers, returns
+// true if any of the peers was running the job.
+func (sys *NotificationSys) CancelBatchJob(ctx context.Context, id string) bool {
+	canceled := make([]bool, len(sys.peerClients))
+	var wg sync.WaitGroup
+	for index, client := range sys.peerClients {
+		if client == nil {
+			continue
+		}
+		wg.Add(1)
+		go func(idx int, client *peerRESTClient) {
+			defer wg.Done()
+			ok, err := client.CancelBatchJob(id)
+			if err != nil {
+				reqInfo := (&logger.ReqInfo{}).AppendTags("peerAddress", client.host.String())
+				ctx := logger.SetReqInfo(ctx, reqInfo)
+				logger.LogIf(ctx, err)
+				return
+			}
+			canceled[idx] = ok
+		}(index, client)
+	}
+	wg.Wait()
+
+	for _, ok := range canceled {
+		if ok {
+			return true
+		}
+	}
+	return false
+}
+
+// BackgroundJobsStatus - returns the state of the background jobs of
+// all peers.
+func (sys *NotificationSys) BackgroundJobsStatus(ctx context.Context) []madmin.BackgroundJobStatus {
+	statuses := make([][]madmin.BackgroundJobStatus, len(sys.peerClients))
+	var wg sync.WaitGroup
+	for index, client := range sys.peerClients {
+		if client == nil {
+			continue
+		}
+		wg.Add(1)
+		go func(idx int, client *peerRESTClient) {
+			defer wg.Done()
+			peerStatuses, err := client.BackgroundJobsStatus()
+			if err != nil {
+				reqInfo := (&logger.ReqInfo{}).AppendTags("peerAddress", client.host.String())
+				ctx := logger.SetReqInfo(ctx, reqInfo)
+				logger.LogOnceIf(ctx, err, client.host.String())
+				return
+			}
+			statuses[idx] = peerStatuses
+		}(index, client)
+	}
+	wg.Wait()
+
+	var allStatuses []madmin.BackgroundJobStatus
+	for _, peerStatuses := range statuses {
+		allStatuses = append(allStatuses, peerStatuses...)
+	}
+	return allStatuses
+}
+
+// BackgroundJobAction - makes BackgroundJobAction RPC call on all
+// peers.
+func (sys *NotificationSys) BackgroundJobAction(name, action string) []NotificationPeerErr {
+	ng := WithNPeers(len(sys.peerClients))
+	for idx, client := range sys.peerClients {
+		if client == nil {
+			continue
+		}
+		client := client
+		ng.Go(context.Background(), func() error { return client.BackgroundJobAction(name, action) }, idx, *client.host)
+	}
+	return ng.Wait()
+}
+
+// InspectOrphans - inspects the disks of all peers, the result of each
+// disk is sent on resultCh as soon as a peer streams it. A peer which
+// cannot be inspected is reported with an error.
+func (sys *NotificationSys) InspectOrphans(ctx context.Context, remove bool, olderThan time.Duration, resultCh chan<- madmin.OrphanDiskResult) {
+	var wg sync.WaitGroup
+	for _, client := range sys.peerClients {
+		if client == nil {
+			continue
+		}
+		wg.Add(1)
+		go func(client *peerRESTClient) {
+			defer wg.Done()
+			err := client.InspectOrphans(ctx, remove, olderThan, resultCh)
+			if err == nil || err == context.Canceled {
+				return
+			}
+			reqInfo := (&logger.ReqInfo{}).AppendTags("peerAddress", client.host.String())
+			logger.LogIf(logger.SetReqInfo(ctx, reqInfo), err)
+			select {
+			case resultCh <- madmin.OrphanDiskResult{Node: client.host.String(), Error: err.Error()}:
+			case <-ctx.Done():
+			}
+		}(client)
+	}
+	wg.Wait()
+}
+
+// SearchObjects - searches the object name indexes of all peers,
+// returns the bucket/object keys found by any peer.
+func (sys *NotificationSys) SearchObjects(ctx context.Context, query, bucket string) []string {
+	keys := make([][]string, len(sys.peerClients))
+	var wg sync.WaitGroup
+	for index, client := range sys.peerClients {
+		if client == nil {
+			continue
+		}
+		wg.Add(1)
+		go func(idx int, client *peerRESTClient) {
+			defer wg.Done()
+			peerKeys, err := client.SearchObjects(query, bucket)
+			if err != nil {
+				reqInfo := (&logger.ReqInfo{}).AppendTags("peerAddress", client.host.String())
+				ctx := logger.SetReqInfo(ctx, reqInfo)
+				logger.LogOnceIf(ctx, err, client.host.String())
+				return
+			}
+			keys[idx] = peerKeys
+		}(index, client)
+	}
+	wg.Wait()
+
+	var allKeys []string
+	for _, peerKeys := range keys {
+		allKeys = append(allKeys, peerKeys...)
+	}
+	return allKeys
+}
+
+// CancelRequest - makes CancelRequest RPC call on all peers, returns
+// true if any of the peers was serving the request.
+func (sys *NotificationSys) CancelRequest(ctx context.Context, id string) bool {
+	canceled := make([]bool, len(sys.peerClients))
+	var wg sync.WaitGroup
+	for index, client := range sys.peerClients {
+		if client == nil {
+			continue
+		}
+		wg.Add(1)
+		go func(idx int, client *peerRESTClient) {
+			defer wg.Done()
+			ok, err := client.CancelRequest(id)
+			if err != nil {
+				reqInfo := (&logger.ReqInfo{}).AppendTags("peerAddress", client.host.String())
+				ctx := logger.SetReqInfo(ctx, reqInfo)
+				logger.LogIf(ctx, err)
+				return
+			}
+			canceled[idx] = ok
+		}(index, client)
+	}
+	wg.Wait()
+
+	for _, ok := range canceled {
+		if ok {
+			return true
+		}
+	}
+	return false
+}
+
+// SetBucketPolicy - sets the bucket policy on all peers.
 func (sys *NotificationSys) SetBucketPolicy(ctx context.Context, bucketName string, bucketPolicy *policy.Policy) {
+	sys.updateBucket(bucketUpdate{Kind: configEventBucketPolicy, Bucket: bucketName, Policy: bucketPolicy})
+}
+
+// RemoveBucketPolicy - removes the bucket policy on all peers.
+func (sys *NotificationSys) RemoveBucketPolicy(ctx context.Context, bucketName string) {
+	sys.updateBucket(bucketUpdate{Kind: configEventBucketPolicy, Bucket: bucketName, Remove: true})
+}
+
+// SetBucketLifecycle - sets the bucket lifecycle on all peers.
+func (sys *NotificationSys) SetBucketLifecycle(ctx context.Context, bucketName string, bucketLifecycle *lifecycle.Lifecycle) {
+	sys.updateBucket(bucketUpdate{Kind: configEventBucketLifecycle, Bucket: bucketName, Lifecycle: bucketLifecycle})
+}
+
+// RemoveBucketLifecycle - removes the bucket lifecycle on all peers.
+func (sys *NotificationSys) RemoveBucketLifecycle(ctx context.Context, bucketName string) {
+	sys.updateBucket(bucketUpdate{Kind: configEventBucketLifecycle, Bucket: bucketName, Remove: true})
+}
+
+// SetBucketLogging - calls SetBucketLogging on all peers.
+func (sys *NotificationSys) SetBucketLogging(ctx context.Context, bucketName string, loggingEnabled LoggingEnabled) {
 	go func() {
+		if publishConfigEvent(configEventBucketLogging, bucketName) {
+			return
+		}
 		var wg sync.WaitGroup
 		for _, client := range sys.peerClients {
 			if client == nil {
@@ -514,7 +966,7 @@ func (sys *NotificationSys) SetBucketPolicy(ctx context.Context, bucketName stri
 			wg.Add(1)
 			go func(client *peerRESTClient) {
 				defer wg.Done()
-				if err := client.SetBucketPolicy(bucketName, bucketPolicy); err != nil {
+				if err := client.SetBucketLogging(bucketName, loggingEnabled); err != nil {
 					logger.GetReqInfo(ctx).AppendTags("remotePeer", client.host.Name)
 					logger.LogIf(ctx, err)
 				}
@@ -524,9 +976,12 @@ func (sys *NotificationSys) SetBucketPolicy(ctx context.Context, bucketName stri
 	}()
 }
 
-// RemoveBucketPolicy - calls RemoveBucketPolicy RPC call on all peers.
-func (sys *NotificationSys) RemoveBucketPolicy(ctx context.Context, bucketName string) {
+// RemoveBucketLogging - calls RemoveBucketLogging on all peers.
+func (sys *NotificationSys) RemoveBucketLogging(ctx context.Context, bucketName string) {
 	go func() {
+		if publishConfigEvent(configEventBucketLogging, bucketName) {
+			return
+		}
 		var wg sync.WaitGroup
 		for _, client := range sys.peerClients {
 			if client == nil {
@@ -535,7 +990,7 @@ func (sys *NotificationSys) RemoveBucketPolicy(ctx context.Context, bucketName s
 			wg.Add(1)
 			go func(client *peerRESTClient) {
 				defer wg.Done()
-				if err := client.RemoveBucketPolicy(bucketName); err != nil {
+				if err := client.RemoveBucketLogging(bucketName); err != nil {
 					logger.GetReqInfo(ctx).AppendTags("remotePeer", client.host.Name)
 					logger.LogIf(ctx, err)
 				}
@@ -545,9 +1000,12 @@ func (sys *NotificationSys) RemoveBucketPolicy(ctx context.Context, bucketName s
 	}()
 }
 
-// SetBucketLifecycle - calls SetBucketLifecycle on all peers.
-func (sys *NotificationSys) SetBucketLifecycle(ctx context.Context, bucketName string, bucketLifecycle *lifecycle.Lifecycle) {
+// SetBucketCORS - calls SetBucketCORS on all peers.
+func (sys *NotificationSys) SetBucketCORS(ctx context.Context, bucketName string, config cors.Config) {
 	go func() {
+		if publishConfigEvent(configEventBucketCORS, bucketName) {
+			return
+		}
 		var wg sync.WaitGroup
 		for _, client := range sys.peerClients {
 			if client == nil {
@@ -556,7 +1014,7 @@ func (sys *NotificationSys) SetBucketLifecycle(ctx context.Context, bucketName s
 			wg.Add(1)
 			go func(client *peerRESTClient) {
 				defer wg.Done()
-				if err := client.SetBucketLifecycle(bucketName, bucketLifecycle); err != nil {
+				if err := client.SetBucketCORS(bucketName, config); err != nil {
 					logger.GetReqInfo(ctx).AppendTags("remotePeer", client.host.Name)
 					logger.LogIf(ctx, err)
 				}
@@ -566,9 +1024,12 @@ func (sys *NotificationSys) SetBucketLifecycle(ctx context.Context, bucketName s
 	}()
 }
 
-// RemoveBucketLifecycle - calls RemoveLifecycle on all peers.
-func (sys *NotificationSys) RemoveBucketLifecycle(ctx context.Context, bucketName string) {
+// RemoveBucketCORS - calls RemoveBucketCORS on all peers.
+func (sys *NotificationSys) RemoveBucketCORS(ctx context.Context, bucketName string) {
 	go func() {
+		if publishConfigEvent(configEventBucketCORS, bucketName) {
+			return
+		}
 		var wg sync.WaitGroup
 		for _, client := range sys.peerClients {
 			if client == nil {
@@ -577,7 +1038,7 @@ func (sys *NotificationSys) RemoveBucketLifecycle(ctx context.Context, bucketNam
 			wg.Add(1)
 			go func(client *peerRESTClient) {
 				defer wg.Done()
-				if err := client.RemoveBucketLifecycle(bucketName); err != nil {
+				if err := client.RemoveBucketCORS(bucketName); err != nil {
 					logger.GetReqInfo(ctx).AppendTags("remotePeer", client.host.Name)
 					logger.LogIf(ctx, err)
 				}
@@ -587,27 +1048,99 @@ func (sys *NotificationSys) RemoveBucketLifecycle(ctx context.Context, bucketNam
 	}()
 }
 
-// PutBucketNotification - calls PutBucketNotification RPC call on all peers.
-func (sys *NotificationSys) PutBucketNotification(ctx context.Context, bucketName string, rulesMap event.RulesMap) {
+// SetBucketWebsite - calls SetBucketWebsite on all peers.
+func (sys *NotificationSys) SetBucketWebsite(ctx context.Context, bucketName string, config website.Config) {
 	go func() {
+		if publishConfigEvent(configEventBucketWebsite, bucketName) {
+			return
+		}
 		var wg sync.WaitGroup
 		for _, client := range sys.peerClients {
 			if client == nil {
 				continue
 			}
 			wg.Add(1)
-			go func(client *peerRESTClient, rulesMap event.RulesMap) {
+			go func(client *peerRESTClient) {
 				defer wg.Done()
-				if err := client.PutBucketNotification(bucketName, rulesMap); err != nil {
+				if err := client.SetBucketWebsite(bucketName, config); err != nil {
 					logger.GetReqInfo(ctx).AppendTags("remotePeer", client.host.Name)
 					logger.LogIf(ctx, err)
 				}
-			}(client, rulesMap.Clone())
+			}(client)
 		}
 		wg.Wait()
 	}()
 }
 
+// RemoveBucketWebsite - calls RemoveBucketWebsite on all peers.
+func (sys *NotificationSys) RemoveBucketWebsite(ctx context.Context, bucketName string) {
+	go func() {
+		if publishConfigEvent(configEventBucketWebsite, bucketName) {
+			return
+		}
+		var wg sync.WaitGroup
+		for _, client := range sys.peerClients {
+			if client == nil {
+				continue
+			}
+			wg.Add(1)
+			go func(client *peerRESTClient) {
+				defer wg.Done()
+				if err := client.RemoveBucketWebsite(bucketName); err != nil {
+					logger.GetReqInfo(ctx).AppendTags("remotePeer", client.host.Name)
+					logger.LogIf(ctx, err)
+				}
+			}(client)
+		}
+		wg.Wait()
+	}()
+}
+
+// PutBucketNotification - sets the bucket notification rules on all peers.
+func (sys *NotificationSys) PutBucketNotification(ctx context.Context, bucketName string, rulesMap event.RulesMap) {
+	sys.updateBucket(bucketUpdate{Kind: configEventBucketNotification, Bucket: bucketName, RulesMap: rulesMap.Clone()})
+}
+
+// updateBucket - sends a bucket update to all peers in the background,
+// through etcd if configured. Updates are batched with the other bucket
+// updates made meanwhile.
+func (sys *NotificationSys) updateBucket(update bucketUpdate) {
+	if len(sys.peerClients) == 0 && globalEtcdClient == nil {
+		return
+	}
+	sys.bucketUpdates.add(update, sys.sendBucketUpdates)
+}
+
+// sendBucketUpdates - sends a batch of bucket updates to all peers.
+func (sys *NotificationSys) sendBucketUpdates(updates []bucketUpdate) {
+	// With etcd, peers reload the configurations from the backend.
+	var peerUpdates []bucketUpdate
+	for _, update := range updates {
+		if !publishConfigEvent(update.Kind, update.Bucket) {
+			peerUpdates = append(peerUpdates, update)
+		}
+	}
+	if len(peerUpdates) == 0 {
+		return
+	}
+
+	var wg sync.WaitGroup
+	for _, client := range sys.peerClients {
+		if client == nil {
+			continue
+		}
+		wg.Add(1)
+		go func(client *peerRESTClient) {
+			defer wg.Done()
+			if err := client.UpdateBuckets(peerUpdates); err != nil {
+				reqInfo := (&logger.ReqInfo{}).AppendTags("remotePeer", client.host.Name)
+				logger.LogIf(logger.SetReqInfo(GlobalContext, reqInfo), err)
+			}
+		}(client)
+	}
+	wg.Wait()
+}
+
 // ListenBucketNotification - calls ListenBucketNotification RPC call on all peers.
 func (sys *NotificationSys) ListenBucketNotification(ctx context.Context, bucketName string, eventNames []event.Name, pattern string,
 	targetID event.TargetID, localPeer xnet.Host) {
@@ -655,6 +1188,41 @@ func (sys *NotificationSys) AddRemoteTarget(bucketName string, target event.Targ
 	return nil
 }
 
+// ReloadTargets - replaces the targets configured in the server config
+// with the enabled targets of config, HTTP/PeerRPC client targets are kept.
+func (sys *NotificationSys) ReloadTargets(config *serverConfig) {
+	targetList := getNotificationTargets(config)
+
+	sys.RLock()
+	remoteTargetIDs := make(map[event.TargetID]struct{})
+	for _, targetMap := range sys.bucketRemoteTargetRulesMap {
+		for targetID := range targetMap {
+			remoteTargetIDs[targetID] = struct{}{}
+		}
+	}
+	sys.RUnlock()
+
+	var targetIDs []event.TargetID
+	for _, targetID := range sys.targetList.List() {
+		if _, ok := remoteTargetIDs[targetID]; !ok {
+			targetIDs = append(targetIDs, targetID)
+		}
+	}
+	for terr := range sys.targetList.Remove(targetIDs...) {
+		reqInfo := (&logger.ReqInfo{}).AppendTags("targetID", terr.ID.Name)
+		ctx := logger.SetReqInfo(context.Background(), reqInfo)
+		logger.LogIf(ctx, terr.Err)
+	}
+
+	for _, target := range targetList.Targets() {
+		if err := sys.targetList.Add(target); err != nil {
+			reqInfo := (&logger.ReqInfo{}).AppendTags("targetID", target.ID().Name)
+			ctx := logger.SetReqInfo(context.Background(), reqInfo)
+			logger.LogIf(ctx, err)
+		}
+	}
+}
+
 // RemoteTargetExist - checks whether given target ID is a HTTP/PeerRPC client target or not.
 func (sys *NotificationSys) RemoteTargetExist(bucketName string, targetID event.TargetID) bool {
 	sys.Lock()
@@ -982,6 +1550,58 @@ func (sys *NotificationSys) DrivePerfInfo() []ServerDrivesPerfInfo {
 	return reply
 }
 
+// DriveLatency - Drive read and write latency histograms
+func (sys *NotificationSys) DriveLatency() []ServerDriveLatencyInfo {
+	reply := make([]ServerDriveLatencyInfo, len(sys.peerClients))
+	var wg sync.WaitGroup
+	for i, client := range sys.peerClients {
+		if client == nil {
+			continue
+		}
+		wg.Add(1)
+		go func(client *peerRESTClient, idx int) {
+			defer wg.Done()
+			di, err := client.DriveLatency()
+			if err != nil {
+				reqInfo := (&logger.ReqInfo{}).AppendTags("remotePeer", client.host.String())
+				ctx := logger.SetReqInfo(context.Background(), reqInfo)
+				logger.LogIf(ctx, err)
+				di.Addr = client.host.String()
+				di.Error = err.Error()
+			}
+			reply[idx] = di
+		}(client, i)
+	}
+	wg.Wait()
+	return reply
+}
+
+// TargetsHealthInfo - Health of the notification targets as seen by peers
+func (sys *NotificationSys) TargetsHealthInfo() []madmin.ServerTargetsHealth {
+	reply := make([]madmin.ServerTargetsHealth, len(sys.peerClients))
+	var wg sync.WaitGroup
+	for i, client := range sys.peerClients {
+		if client == nil {
+			continue
+		}
+		wg.Add(1)
+		go func(client *peerRESTClient, idx int) {
+			defer wg.Done()
+			th, err := client.TargetsHealth()
+			if err != nil {
+				reqInfo := (&logger.ReqInfo{}).AppendTags("remotePeer", client.host.String())
+				ctx := logger.SetReqInfo(context.Background(), reqInfo)
+				logger.LogIf(ctx, err)
+				th.Addr = client.host.String()
+				th.Error = err.Error()
+			}
+			reply[idx] = th
+		}(client, i)
+	}
+	wg.Wait()
+	return reply
+}
+
 // MemUsageInfo - Mem utilization information
 func (sys *NotificationSys) MemUsageInfo() []ServerMemUsageInfo {
 	reply := make([]ServerMemUsageInfo, len(sys.peerClients))
@@ -1042,6 +1662,9 @@ func NewNotificationSys(config *serverConfig, endpoints EndpointList) *Notificat
 	if err != nil {
 		logger.FatalIf(err, "Unable to start notification sub system")
 	}
+	for _, host := range remoteHosts {
+		globalPeerMembership.add(host.String())
+	}
 
 	// bucketRulesMap/bucketRemoteTargetRulesMap are initialized by NotificationSys.Init()
 	return &NotificationSys{
@@ -1049,6 +1672,7 @@ func NewNotificationSys(config *serverConfig, endpoints EndpointList) *Notificat
 		bucketRulesMap:             make(map[string]event.RulesMap),
 		bucketRemoteTargetRulesMap: make(map[string]map[event.TargetID]event.RulesMap),
 		peerClients:                remoteClients,
+		targetHealth:               make(map[event.TargetID]madmin.TargetHealth),
 	}
 }
 
@@ -1117,7 +1741,7 @@ func (args eventArgs) ToEvent() event.Event {
 		},
 	}
 
-	if args.EventName != event.ObjectRemovedDelete {
+	if !args.EventName.IsRemoval() {
 		newEvent.S3.Object.ETag = args.Object.ETag
 		newEvent.S3.Object.Size = args.Object.Size
 		if args.Object.IsCompressed() {
@@ -20,6 +20,7 @@ import (
 	"context"
 	"path"
 	"sync"
+	"time"
 
 	"strings"
 
@@ -39,6 +40,11 @@ const (
 
 	// ETag (hex encoded md5sum) of empty string.
 	emptyETag = "d41d8cd98f00b204e9800998ecf8427e"
+
+	// Internal metadata keys of an ongoing multipart upload, the
+	// multipart namespace is hashed and does not keep object names.
+	multipartObjectKey    = ReservedMetadataPrefix + "multipart-object"
+	multipartInitiatedKey = ReservedMetadataPrefix + "multipart-initiated"
 )
 
 // Global object layer mutex, used for safely updating object layer.
@@ -446,3 +452,39 @@ func listObjects(ctx context.Context, obj ObjectLayer, bucket, prefix, marker, d
 	// Success.
 	return result, nil
 }
+
+// setMultipartUploadMeta - records the object name and the initiation
+// time of a new multipart upload in its metadata.
+func setMultipartUploadMeta(meta map[string]string, object string, initiated time.Time) {
+	meta[multipartObjectKey] = object
+	meta[multipartInitiatedKey] = initiated.Format(time.RFC3339Nano)
+}
+
+// getMultipartInitiated - returns the initiation time recorded in
+// the metadata of a multipart upload, modTime is returned for uploads
+// created before it was recorded.
+func getMultipartInitiated(meta map[string]string, modTime time.Time) time.Time {
+	if initiated, err := time.Parse(time.RFC3339Nano, meta[multipartInitiatedKey]); err == nil {
+		return initiated
+	}
+	return modTime
+}
+
+// getMultipartObject - returns the object name recorded in the
+// metadata of a multipart upload under the given hashed directory of
+// the multipart namespace, ok is false if the upload does not belong
+// to bucket or was created before object names were recorded.
+func getMultipartObject(bucket, shaDir string, meta map[string]string) (object string, ok bool) {
+	object = meta[multipartObjectKey]
+	if object == "" || getSHA256Hash([]byte(pathJoin(bucket, object))) != shaDir {
+		return "", false
+	}
+	return object, true
+}
+
+// clearMultipartUploadMeta - removes the multipart upload keys from
+// the metadata before it is saved with the completed object.
+func clearMultipartUploadMeta(meta map[string]string) {
+	delete(meta, multipartObjectKey)
+	delete(meta, multipartInitiatedKey)
+}
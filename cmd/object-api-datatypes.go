@@ -37,6 +37,12 @@ const (
 	// Add your own backend.
 )
 
+// BucketUsageInfo - represents the usage of a bucket.
+type BucketUsageInfo struct {
+	Objects uint64 // Number of objects in the bucket.
+	Size    uint64 // Estimated total logical size of the objects.
+}
+
 // StorageInfo - represents total capacity of underlying storage.
 type StorageInfo struct {
 	Used uint64 // Used total used per tenant.
@@ -45,6 +51,21 @@ type StorageInfo struct {
 
 	Available uint64 // Total disk space available.
 
+	Objects       uint64 // Total number of objects, as counted by the usage crawler.
+	ObjectsSize   uint64 // Estimated total logical size of all objects.
+	AvgObjectSize uint64 // Average object size.
+
+	// Usage of each bucket, as counted by the usage crawler.
+	Buckets map[string]BucketUsageInfo
+
+	// Content addressed deduplication statistics, this is
+	// only meaningful if FS dedup mode is enabled.
+	Dedup struct {
+		Blobs      uint64 // Unique data blobs stored.
+		References uint64 // Objects referencing a stored blob.
+		SavedBytes uint64 // Bytes saved by sharing blobs.
+	}
+
 	// Backend type.
 	Backend struct {
 		// Represents various backend types, currently on FS and Erasure.
@@ -60,6 +81,10 @@ type StorageInfo struct {
 
 		// List of all disk status, this is only meaningful if BackendType is Erasure.
 		Sets [][]madmin.DriveInfo
+
+		// Estimated number of object copies pending heal per erasure set,
+		// this is only meaningful if BackendType is Erasure.
+		HealBacklog []uint64
 	}
 }
 
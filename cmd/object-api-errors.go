@@ -338,6 +338,53 @@ func (e ObjectTooSmall) Error() string {
 	return "size of the object less than what is expected"
 }
 
+// ObjectTooManyAppends error returned when an object can not be
+// appended to anymore without being rewritten.
+type ObjectTooManyAppends GenericError
+
+func (e ObjectTooManyAppends) Error() string {
+	return "Object " + e.Bucket + "/" + e.Object + " has reached the maximum number of appends"
+}
+
+// ObjectNotCached error returned when an object requested to be read
+// from the disk cache only is not cached.
+type ObjectNotCached GenericError
+
+func (e ObjectNotCached) Error() string {
+	return "Object " + e.Bucket + "/" + e.Object + " is not cached"
+}
+
+// BucketReadOnly error returned when a request modifies a bucket in
+// read-only mode.
+type BucketReadOnly GenericError
+
+func (e BucketReadOnly) Error() string {
+	return "Bucket " + e.Bucket + " is in read-only mode"
+}
+
+// BucketSuspended error returned for requests on a suspended bucket.
+type BucketSuspended GenericError
+
+func (e BucketSuspended) Error() string {
+	return "Bucket " + e.Bucket + " is suspended"
+}
+
+// AnonymousUploadQuotaExceeded error returned when an anonymous upload
+// exceeds the daily anonymous upload quota of its bucket.
+type AnonymousUploadQuotaExceeded GenericError
+
+func (e AnonymousUploadQuotaExceeded) Error() string {
+	return "Daily anonymous upload quota of bucket " + e.Bucket + " exceeded"
+}
+
+// ContentTypeNotAllowed error returned when the content type or the
+// extension of an upload is not allowed by its bucket.
+type ContentTypeNotAllowed GenericError
+
+func (e ContentTypeNotAllowed) Error() string {
+	return "Content type of object " + e.Object + " is not allowed in bucket " + e.Bucket
+}
+
 // OperationTimedOut - a timeout occurred.
 type OperationTimedOut struct {
 	Path string
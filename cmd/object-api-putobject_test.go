@@ -24,6 +24,7 @@ import (
 	"io/ioutil"
 	"os"
 	"path"
+	"strings"
 	"testing"
 
 	humanize "github.com/dustin/go-humanize"
@@ -193,6 +194,92 @@ func testObjectAPIPutObject(obj ObjectLayer, instanceType string, t TestErrHandl
 	}
 }
 
+// Wrapper for calling AppendObject tests for both XL multiple disks and single node setup.
+func TestObjectAPIAppendObject(t *testing.T) {
+	ExecObjectLayerTest(t, testObjectAPIAppendObject)
+}
+
+// Tests validate correctness of AppendObject.
+func testObjectAPIAppendObject(obj ObjectLayer, instanceType string, t TestErrHandler) {
+	bucket := "minio-bucket"
+	object := "minio-object"
+
+	err := obj.MakeBucketWithLocation(context.Background(), bucket, "")
+	if err != nil {
+		t.Fatalf("%s : %s", instanceType, err.Error())
+	}
+
+	chunks := [][]byte{
+		[]byte("first line\n"),
+		bytes.Repeat([]byte("a"), int(blockSizeV1)+1),
+		{},
+		[]byte("last line\n"),
+	}
+
+	var expected []byte
+	var etag string
+	for i, chunk := range chunks {
+		md5hex := getMD5Hash(chunk)
+		objInfo, err := obj.AppendObject(context.Background(), bucket, object,
+			mustGetPutObjReader(t, bytes.NewReader(chunk), int64(len(chunk)), md5hex, ""), ObjectOptions{})
+		if err != nil {
+			t.Fatalf("%s: Test %d: Unexpected error %s", instanceType, i+1, err)
+		}
+		expected = append(expected, chunk...)
+		if objInfo.Size != int64(len(expected)) {
+			t.Errorf("%s: Test %d: Expected size %d, got %d", instanceType, i+1, len(expected), objInfo.Size)
+		}
+		switch {
+		case i == 0:
+			etag = md5hex
+		case len(chunk) > 0:
+			// Empty appends leave the object untouched.
+			etag = getAppendObjectETag(etag, md5hex)
+		}
+		if objInfo.ETag != etag {
+			t.Errorf("%s: Test %d: Expected ETag %s, got %s", instanceType, i+1, etag, objInfo.ETag)
+		}
+	}
+
+	var buffer bytes.Buffer
+	if err = obj.GetObject(context.Background(), bucket, object, 0, int64(len(expected)), &buffer, "", ObjectOptions{}); err != nil {
+		t.Fatalf("%s: Unexpected error %s", instanceType, err)
+	}
+	if !bytes.Equal(buffer.Bytes(), expected) {
+		t.Errorf("%s: Appended object content mismatch", instanceType)
+	}
+
+	// Appending less data than announced must not modify the object.
+	_, err = obj.AppendObject(context.Background(), bucket, object,
+		mustGetPutObjReader(t, bytes.NewReader([]byte("abc")), 4, "", ""), ObjectOptions{})
+	if _, ok := err.(IncompleteBody); !ok {
+		t.Errorf("%s: Expected IncompleteBody, got %v", instanceType, err)
+	}
+	objInfo, err := obj.GetObjectInfo(context.Background(), bucket, object, ObjectOptions{})
+	if err != nil {
+		t.Fatalf("%s: Unexpected error %s", instanceType, err)
+	}
+	if objInfo.Size != int64(len(expected)) || objInfo.ETag != etag {
+		t.Errorf("%s: Expected object to be unchanged, got size %d etag %s", instanceType, objInfo.Size, objInfo.ETag)
+	}
+}
+
+func TestGetAppendObjectETag(t *testing.T) {
+	testCases := []struct {
+		prevETag    string
+		appendedMD5 string
+		expected    string
+	}{
+		{"d41d8cd98f00b204e9800998ecf8427e", "900150983cd24fb0d6963f7d28e17f72", getCompleteMultipartMD5([]CompletePart{{ETag: "d41d8cd98f00b204e9800998ecf8427e"}, {ETag: "900150983cd24fb0d6963f7d28e17f72"}})},
+		{"d41d8cd98f00b204e9800998ecf8427e-3", "900150983cd24fb0d6963f7d28e17f72", strings.TrimSuffix(getCompleteMultipartMD5([]CompletePart{{ETag: "d41d8cd98f00b204e9800998ecf8427e"}, {ETag: "900150983cd24fb0d6963f7d28e17f72"}}), "-2") + "-4"},
+	}
+	for i, testCase := range testCases {
+		if etag := getAppendObjectETag(testCase.prevETag, testCase.appendedMD5); etag != testCase.expected {
+			t.Errorf("Test %d: Expected %s, got %s", i+1, testCase.expected, etag)
+		}
+	}
+}
+
 // Wrapper for calling PutObject tests for both XL multiple disks case
 // when quorum is not available.
 func TestObjectAPIPutObjectDiskNotFound(t *testing.T) {
@@ -221,6 +221,21 @@ func getCompleteMultipartMD5(parts []CompletePart) string {
 	return s3MD5
 }
 
+// getAppendObjectETag - returns the ETag of an object after data with
+// the md5sum appendedMD5 has been appended to an object having the
+// ETag prevETag. The result follows the multipart ETag format where
+// the suffix counts the number of appended chunks.
+func getAppendObjectETag(prevETag, appendedMD5 string) string {
+	count := 1
+	if i := strings.LastIndex(prevETag, "-"); i != -1 {
+		if n, err := strconv.Atoi(prevETag[i+1:]); err == nil {
+			prevETag, count = prevETag[:i], n
+		}
+	}
+	s3MD5 := getCompleteMultipartMD5([]CompletePart{{ETag: prevETag}, {ETag: appendedMD5}})
+	return fmt.Sprintf("%s-%d", strings.TrimSuffix(s3MD5, "-2"), count+1)
+}
+
 // Clean unwanted fields from metadata
 func cleanMetadata(metadata map[string]string) map[string]string {
 	// Remove STANDARD StorageClass
@@ -354,7 +369,7 @@ func (o ObjectInfo) GetActualSize() int64 {
 // Using compression and encryption together enables room for side channel attacks.
 // Eliminate non-compressible objects by extensions/content-types.
 func isCompressible(header http.Header, object string) bool {
-	if hasServerSideEncryptionHeader(header) || excludeForCompression(header, object) {
+	if hasServerSideEncryptionHeader(header) || crypto.S3KMS.IsRequested(header) || excludeForCompression(header, object) {
 		return false
 	}
 	return true
@@ -602,10 +617,20 @@ func NewGetObjectReader(rs *HTTPRangeSpec, oi ObjectInfo, pcfn CheckCopyPrecondi
 				}
 			}
 			// Decompression reader.
-			snappyReader := snappy.NewReader(inputReader)
+			decompressReader, err := newObjectDecompressReader(inputReader, oi.UserDefined)
+			if err != nil {
+				// Call the cleanup funcs
+				for i := len(cFns) - 1; i >= 0; i-- {
+					cFns[i]()
+				}
+				return nil, err
+			}
+			cFns = append(cFns, func() {
+				decompressReader.Close()
+			})
 			// Apply the skipLen and limit on the
 			// decompressed stream
-			decReader := io.LimitReader(ioutil.NewSkipReader(snappyReader, decOff), decLength)
+			decReader := io.LimitReader(ioutil.NewSkipReader(decompressReader, decOff), decLength)
 			oi.Size = decLength
 
 			// Assemble the GetObjectReader
@@ -760,32 +785,32 @@ func CleanMinioInternalMetadataKeys(metadata map[string]string) map[string]strin
 	return newMeta
 }
 
-// snappyCompressReader compresses data as it reads
+// compressReader compresses data as it reads
 // from the underlying io.Reader.
-type snappyCompressReader struct {
+type compressReader struct {
 	r      io.Reader
-	w      *snappy.Writer
+	w      io.WriteCloser
 	closed bool
 	buf    bytes.Buffer
 }
 
-func newSnappyCompressReader(r io.Reader) *snappyCompressReader {
-	cr := &snappyCompressReader{r: r}
+func newSnappyCompressReader(r io.Reader) *compressReader {
+	cr := &compressReader{r: r}
 	cr.w = snappy.NewBufferedWriter(&cr.buf)
 	return cr
 }
 
-func (cr *snappyCompressReader) Read(p []byte) (int, error) {
+func (cr *compressReader) Read(p []byte) (int, error) {
 	if cr.closed {
-		// if snappy writer is closed r has been completely read,
-		// return any remaining data in buf.
+		// if the compress writer is closed r has been completely
+		// read, return any remaining data in buf.
 		return cr.buf.Read(p)
 	}
 
 	// read from original using p as buffer
 	nr, readErr := cr.r.Read(p)
 
-	// write read bytes to snappy writer
+	// write read bytes to the compress writer
 	nw, err := cr.w.Write(p[:nr])
 	if err != nil {
 		return 0, err
@@ -794,7 +819,7 @@ func (cr *snappyCompressReader) Read(p []byte) (int, error) {
 		return 0, io.ErrShortWrite
 	}
 
-	// if last of data from reader, close snappy writer to flush
+	// if last of data from reader, close the compress writer to flush
 	if readErr == io.EOF {
 		err := cr.w.Close()
 		cr.closed = true
@@ -0,0 +1,174 @@
+/*
+ * MinIO Cloud Storage, (C) 2020 MinIO, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"context"
+	"encoding/xml"
+	"errors"
+	"net/http"
+
+	"github.com/minio/minio/cmd/crypto"
+	"github.com/minio/minio/cmd/logger"
+	"github.com/minio/minio/pkg/hash"
+)
+
+const (
+	// Maximum number of source objects of a compose request.
+	maxComposeSources = 32
+
+	// Maximum size of the XML body of a compose request.
+	maxComposeRequestSize = 64 * 1024
+)
+
+var (
+	errComposeInvalidSources  = errors.New("A compose request must have between 1 and 32 source objects")
+	errComposeEncryptedSource = errors.New("Encrypted objects cannot be composed")
+)
+
+// ComposeSource - a source object of a compose request, the source is
+// only composed if its ETag matches when given.
+type ComposeSource struct {
+	Object string `xml:"Key" json:"object"`
+	ETag   string `xml:"ETag,omitempty" json:"etag,omitempty"`
+}
+
+// ComposeObjectRequest - the XML body of a compose request.
+type ComposeObjectRequest struct {
+	XMLName xml.Name        `xml:"ComposeObjectRequest" json:"-"`
+	Sources []ComposeSource `xml:"Source"`
+}
+
+// ComposeObjectResponse - the XML response of a compose request.
+type ComposeObjectResponse struct {
+	XMLName      xml.Name `xml:"http://s3.amazonaws.com/doc/2006-03-01/ ComposeObjectResult" json:"-"`
+	LastModified string   // time string of format "2006-01-02T15:04:05.000Z"
+	ETag         string   // ETag of the composed object.
+}
+
+// composeObject - concatenates the source objects of bucket, in order,
+// into object of the same bucket. The object is written by a multipart
+// upload each source is copied to as one or more parts, hence all the
+// sources but the last one must be at least globalMinPartSize bytes.
+// Encrypted sources cannot be composed. The metadata of the object is
+// the given metadata, its content type is the one of the first source
+// if not given.
+func composeObject(ctx context.Context, objAPI ObjectLayer, bucket, object string, sources []ComposeSource, metadata map[string]string) (ObjectInfo, error) {
+	if len(sources) == 0 || len(sources) > maxComposeSources {
+		return ObjectInfo{}, errComposeInvalidSources
+	}
+
+	infos := make([]ObjectInfo, len(sources))
+	var totalSize int64
+	for i, src := range sources {
+		info, err := objAPI.GetObjectInfo(ctx, bucket, src.Object, ObjectOptions{})
+		if err != nil {
+			return ObjectInfo{}, err
+		}
+		if src.ETag != "" && canonicalizeETag(src.ETag) != info.ETag {
+			return ObjectInfo{}, PreConditionFailed{}
+		}
+		if crypto.IsEncrypted(info.UserDefined) {
+			return ObjectInfo{}, errComposeEncryptedSource
+		}
+		size := info.GetActualSize()
+		if i < len(sources)-1 && size < globalMinPartSize {
+			return ObjectInfo{}, PartTooSmall{PartNumber: i + 1, PartSize: size, PartETag: info.ETag}
+		}
+		infos[i] = info
+		totalSize += size
+	}
+	if totalSize > globalMaxObjectSize {
+		return ObjectInfo{}, ObjectTooLarge{Bucket: bucket, Object: object}
+	}
+
+	userDefined := make(map[string]string, len(metadata)+1)
+	for k, v := range metadata {
+		userDefined[k] = v
+	}
+	if _, ok := userDefined["content-type"]; !ok && infos[0].ContentType != "" {
+		userDefined["content-type"] = infos[0].ContentType
+	}
+
+	uploadID, err := objAPI.NewMultipartUpload(ctx, bucket, object, ObjectOptions{UserDefined: userDefined})
+	if err != nil {
+		return ObjectInfo{}, err
+	}
+
+	var parts []CompletePart
+	for i, src := range sources {
+		size := infos[i].GetActualSize()
+		// Sources larger than the maximum part size are copied in
+		// several parts, empty sources are copied as an empty part.
+		for offset := int64(0); offset < size || offset == 0; offset += globalMaxPartSize {
+			length := size - offset
+			if length > globalMaxPartSize {
+				length = globalMaxPartSize
+			}
+			var part PartInfo
+			part, err = composeObjectPart(ctx, objAPI, bucket, src.Object, infos[i].ETag, object, uploadID, len(parts)+1, offset, length)
+			if err != nil {
+				break
+			}
+			parts = append(parts, CompletePart{PartNumber: part.PartNumber, ETag: part.ETag})
+			if size == 0 {
+				break
+			}
+		}
+		if err != nil {
+			break
+		}
+	}
+
+	var objInfo ObjectInfo
+	if err == nil {
+		objInfo, err = objAPI.CompleteMultipartUpload(ctx, bucket, object, uploadID, parts, ObjectOptions{})
+	}
+	if err != nil {
+		// The request context may be canceled already.
+		logger.LogIf(ctx, objAPI.AbortMultipartUpload(context.Background(), bucket, object, uploadID))
+		return ObjectInfo{}, err
+	}
+	return objInfo, nil
+}
+
+// composeObjectPart - copies length bytes of the source object at
+// offset as a part of the multipart upload, fails if the source no
+// longer has the given ETag.
+func composeObjectPart(ctx context.Context, objAPI ObjectLayer, bucket, srcObject, srcETag, object, uploadID string,
+	partID int, offset, length int64) (PartInfo, error) {
+	var rs *HTTPRangeSpec
+	if length > 0 {
+		rs = &HTTPRangeSpec{Start: offset, End: offset + length - 1}
+	}
+	gr, err := objAPI.GetObjectNInfo(ctx, bucket, srcObject, rs, http.Header{}, readLock, ObjectOptions{})
+	if err != nil {
+		return PartInfo{}, err
+	}
+	defer gr.Close()
+	if gr.ObjInfo.ETag != srcETag {
+		return PartInfo{}, PreConditionFailed{}
+	}
+
+	srcInfo := gr.ObjInfo
+	srcInfo.Reader, err = hash.NewReader(gr, length, "", "", length, globalCLIContext.StrictS3Compat)
+	if err != nil {
+		return PartInfo{}, err
+	}
+	srcInfo.PutObjReader = NewPutObjReader(srcInfo.Reader, nil, nil)
+	return objAPI.CopyObjectPart(ctx, bucket, srcObject, bucket, object, uploadID, partID, offset, length, srcInfo, ObjectOptions{}, ObjectOptions{})
+}
@@ -0,0 +1,98 @@
+/*
+ * MinIO Cloud Storage, (C) 2020 MinIO, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"testing"
+)
+
+// Tests that source objects are concatenated in order, and that
+// sources which cannot be composed are rejected.
+func TestComposeObject(t *testing.T) {
+	obj, fsDir, err := prepareFS()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(fsDir)
+
+	ctx := context.Background()
+	if err = obj.MakeBucketWithLocation(ctx, "bucket", ""); err != nil {
+		t.Fatal(err)
+	}
+
+	data := map[string][]byte{
+		"a":     bytes.Repeat([]byte("a"), globalMinPartSize),
+		"b":     bytes.Repeat([]byte("b"), globalMinPartSize+1),
+		"c":     []byte("c"),
+		"empty": nil,
+	}
+	etags := make(map[string]string)
+	for object, content := range data {
+		info, err := obj.PutObject(ctx, "bucket", object, mustGetPutObjReader(t, bytes.NewReader(content), int64(len(content)), "", ""), ObjectOptions{UserDefined: map[string]string{"content-type": "text/plain"}})
+		if err != nil {
+			t.Fatal(err)
+		}
+		etags[object] = info.ETag
+	}
+
+	sources := []ComposeSource{{Object: "a", ETag: etags["a"]}, {Object: "b"}, {Object: "c"}}
+	if _, err = composeObject(ctx, obj, "bucket", "composed", sources, nil); err != nil {
+		t.Fatal(err)
+	}
+	var buf bytes.Buffer
+	if err = obj.GetObject(ctx, "bucket", "composed", 0, -1, &buf, "", ObjectOptions{}); err != nil {
+		t.Fatal(err)
+	}
+	expected := append(append(append([]byte{}, data["a"]...), data["b"]...), data["c"]...)
+	if !bytes.Equal(buf.Bytes(), expected) {
+		t.Fatalf("Expected %d bytes of composed data, got %d", len(expected), buf.Len())
+	}
+	info, err := obj.GetObjectInfo(ctx, "bucket", "composed", ObjectOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if info.ContentType != "text/plain" {
+		t.Fatalf("Expected the content type of the first source, got %s", info.ContentType)
+	}
+
+	// An empty last source is composed too.
+	if _, err = composeObject(ctx, obj, "bucket", "composed", []ComposeSource{{Object: "a"}, {Object: "empty"}}, nil); err != nil {
+		t.Fatal(err)
+	}
+
+	testCases := []struct {
+		sources []ComposeSource
+		check   func(err error) bool
+	}{
+		{nil, func(err error) bool { return err == errComposeInvalidSources }},
+		{make([]ComposeSource, maxComposeSources+1), func(err error) bool { return err == errComposeInvalidSources }},
+		{[]ComposeSource{{Object: "c"}, {Object: "a"}}, func(err error) bool { _, ok := err.(PartTooSmall); return ok }},
+		{[]ComposeSource{{Object: "a", ETag: etags["b"]}}, isErrPreconditionFailed},
+		{[]ComposeSource{{Object: "missing"}}, isErrObjectNotFound},
+	}
+	for i, testCase := range testCases {
+		if _, err = composeObject(ctx, obj, "bucket", "failed", testCase.sources, nil); !testCase.check(err) {
+			t.Errorf("Test %d: unexpected error %v", i+1, err)
+		}
+	}
+	if _, err = obj.GetObjectInfo(ctx, "bucket", "failed", ObjectOptions{}); !isErrObjectNotFound(err) {
+		t.Fatalf("Expected failed compose requests not to write the object, got %v", err)
+	}
+}
@@ -0,0 +1,204 @@
+/*
+ * MinIO Cloud Storage, (C) 2019 MinIO, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+
+	snappy "github.com/golang/snappy"
+	"github.com/klauspost/compress/s2"
+	"github.com/klauspost/compress/zstd"
+	xhttp "github.com/minio/minio/cmd/http"
+	"github.com/pierrec/lz4"
+)
+
+// Compression algorithms recorded in the metadata of compressed
+// objects, objects are decompressed with the algorithm they were
+// compressed with regardless of the current configuration.
+const (
+	compressionAlgorithmV1   = "golang/snappy/LZ77"
+	compressionAlgorithmS2   = "klauspost/compress/s2"
+	compressionAlgorithmZstd = "klauspost/compress/zstd"
+	compressionAlgorithmLZ4  = "pierrec/lz4"
+)
+
+// Metadata key of the name of the zstd dictionary an object was
+// compressed with.
+const compressionDictionaryKey = ReservedMetadataPrefix + "compression-dictionary"
+
+// compressionAlgorithms maps the configurable compression algorithm
+// names to the algorithms recorded in the object metadata.
+var compressionAlgorithms = map[string]string{
+	"snappy": compressionAlgorithmV1,
+	"s2":     compressionAlgorithmS2,
+	"zstd":   compressionAlgorithmZstd,
+	"lz4":    compressionAlgorithmLZ4,
+}
+
+var (
+	errUnsupportedCompressionAlgorithm = errors.New("Unsupported compression algorithm")
+	errCompressionDictionaryNotFound   = errors.New("Compression dictionary not found")
+)
+
+// Returns the compression algorithm recorded in the metadata for the
+// configurable algorithm name, an empty name selects snappy.
+func parseCompressionAlgorithm(name string) (string, error) {
+	if name == "" {
+		return compressionAlgorithmV1, nil
+	}
+	algorithm, ok := compressionAlgorithms[name]
+	if !ok {
+		return "", uiErrInvalidCompressionAlgorithmValue(nil).Msg("compression algorithm (%s) must be one of snappy, s2, zstd or lz4", name)
+	}
+	return algorithm, nil
+}
+
+// Validate - validates the compression algorithms and the zstd
+// dictionaries referenced by the compression rules.
+func (c compressionConfig) Validate() error {
+	if _, err := parseCompressionAlgorithm(c.Algorithm); err != nil {
+		return err
+	}
+	for i, rule := range c.Rules {
+		algorithm, err := parseCompressionAlgorithm(rule.Algorithm)
+		if err != nil {
+			return err
+		}
+		if rule.Dictionary == "" {
+			continue
+		}
+		if algorithm != compressionAlgorithmZstd {
+			return fmt.Errorf("rule %d: dictionaries are only supported by zstd", i+1)
+		}
+		if _, ok := c.Dictionaries[rule.Dictionary]; !ok {
+			return fmt.Errorf("rule %d: dictionary %s is not defined", i+1, rule.Dictionary)
+		}
+	}
+	return nil
+}
+
+// Reads the zstd dictionaries of the compression config from the
+// local files they are defined by.
+func loadCompressionDictionaries(dictionaries map[string]string) (map[string][]byte, error) {
+	dicts := make(map[string][]byte, len(dictionaries))
+	for name, path := range dictionaries {
+		dict, err := ioutil.ReadFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("Unable to read compression dictionary %s: %v", name, err)
+		}
+		dicts[name] = dict
+	}
+	return dicts, nil
+}
+
+// Returns true if the compression rule applies to the object.
+func (rule compressionRule) matches(bucket, object, contentType string) bool {
+	if len(rule.Buckets) > 0 && !hasPattern(rule.Buckets, bucket) {
+		return false
+	}
+	if len(rule.Extensions) == 0 && len(rule.MimeTypes) == 0 {
+		return true
+	}
+	return hasStringSuffixInSlice(object, rule.Extensions) || hasPattern(rule.MimeTypes, contentType)
+}
+
+// setCompressionMetadata - records in metadata the compression
+// algorithm of the first compression rule matching the object, or the
+// default algorithm if no rule matches, along with its dictionary.
+func setCompressionMetadata(metadata map[string]string, bucket, object string, header http.Header) {
+	algorithm, dictionary := globalCompressAlgorithm, ""
+	contentType := header.Get(xhttp.ContentType)
+	for _, rule := range globalCompressRules {
+		if rule.matches(bucket, object, contentType) {
+			// Rules are validated when the config is loaded.
+			algorithm, _ = parseCompressionAlgorithm(rule.Algorithm)
+			dictionary = rule.Dictionary
+			break
+		}
+	}
+	metadata[ReservedMetadataPrefix+"compression"] = algorithm
+	if dictionary != "" {
+		metadata[compressionDictionaryKey] = dictionary
+	} else {
+		delete(metadata, compressionDictionaryKey)
+	}
+}
+
+// newObjectCompressReader - returns a reader compressing r with the
+// compression algorithm and dictionary recorded in metadata.
+func newObjectCompressReader(r io.Reader, metadata map[string]string) (io.Reader, error) {
+	cr := &compressReader{r: r}
+	switch metadata[ReservedMetadataPrefix+"compression"] {
+	case compressionAlgorithmV1:
+		cr.w = snappy.NewBufferedWriter(&cr.buf)
+	case compressionAlgorithmS2:
+		cr.w = s2.NewWriter(&cr.buf)
+	case compressionAlgorithmZstd:
+		opts := []zstd.EOption{zstd.WithEncoderConcurrency(1)}
+		if name := metadata[compressionDictionaryKey]; name != "" {
+			dict, ok := globalCompressDictionaries[name]
+			if !ok {
+				return nil, errCompressionDictionaryNotFound
+			}
+			opts = append(opts, zstd.WithEncoderDict(dict))
+		}
+		w, err := zstd.NewWriter(&cr.buf, opts...)
+		if err != nil {
+			return nil, err
+		}
+		cr.w = w
+	case compressionAlgorithmLZ4:
+		cr.w = lz4.NewWriter(&cr.buf)
+	default:
+		return nil, errUnsupportedCompressionAlgorithm
+	}
+	return cr, nil
+}
+
+// newObjectDecompressReader - returns a reader decompressing r with
+// the compression algorithm and dictionary recorded in metadata. The
+// reader must be closed to release the decompression resources.
+func newObjectDecompressReader(r io.Reader, metadata map[string]string) (io.ReadCloser, error) {
+	switch metadata[ReservedMetadataPrefix+"compression"] {
+	case compressionAlgorithmV1:
+		return ioutil.NopCloser(snappy.NewReader(r)), nil
+	case compressionAlgorithmS2:
+		return ioutil.NopCloser(s2.NewReader(r)), nil
+	case compressionAlgorithmZstd:
+		var dicts [][]byte
+		if name := metadata[compressionDictionaryKey]; name != "" {
+			dict, ok := globalCompressDictionaries[name]
+			if !ok {
+				return nil, errCompressionDictionaryNotFound
+			}
+			dicts = append(dicts, dict)
+		}
+		dec, err := zstd.NewReader(r, zstd.WithDecoderConcurrency(1), zstd.WithDecoderDicts(dicts...))
+		if err != nil {
+			return nil, err
+		}
+		return dec.IOReadCloser(), nil
+	case compressionAlgorithmLZ4:
+		return ioutil.NopCloser(lz4.NewReader(r)), nil
+	default:
+		return nil, errUnsupportedCompressionAlgorithm
+	}
+}
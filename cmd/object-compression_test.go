@@ -0,0 +1,148 @@
+/*
+ * MinIO Cloud Storage, (C) 2019 MinIO, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"bytes"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"testing"
+)
+
+// Tests that objects compressed with every algorithm decompress with
+// the algorithm recorded in their metadata, including the concatenated
+// streams of multipart objects.
+func TestObjectCompressionRoundTrip(t *testing.T) {
+	data := bytes.Repeat([]byte("hello, world"), 10000)
+	for name, algorithm := range compressionAlgorithms {
+		metadata := map[string]string{ReservedMetadataPrefix + "compression": algorithm}
+
+		var compressed bytes.Buffer
+		for i := 0; i < 2; i++ {
+			r, err := newObjectCompressReader(bytes.NewReader(data), metadata)
+			if err != nil {
+				t.Fatalf("%s: %v", name, err)
+			}
+			// Use a small buffer so multiple reads are required.
+			if _, err = io.CopyBuffer(&compressed, r, make([]byte, 100)); err != nil {
+				t.Fatalf("%s: %v", name, err)
+			}
+		}
+		if compressed.Len() >= 2*len(data) {
+			t.Errorf("%s: expected data to be compressed", name)
+		}
+
+		r, err := newObjectDecompressReader(&compressed, metadata)
+		if err != nil {
+			t.Fatalf("%s: %v", name, err)
+		}
+		decompressed, err := ioutil.ReadAll(r)
+		r.Close()
+		if err != nil {
+			t.Fatalf("%s: %v", name, err)
+		}
+		if !bytes.Equal(decompressed, append(data, data...)) {
+			t.Errorf("%s: roundtrip failed", name)
+		}
+	}
+
+	metadata := map[string]string{ReservedMetadataPrefix + "compression": "unknown"}
+	if _, err := newObjectCompressReader(bytes.NewReader(data), metadata); err != errUnsupportedCompressionAlgorithm {
+		t.Errorf("expected %v, got %v", errUnsupportedCompressionAlgorithm, err)
+	}
+	if _, err := newObjectDecompressReader(bytes.NewReader(data), metadata); err != errUnsupportedCompressionAlgorithm {
+		t.Errorf("expected %v, got %v", errUnsupportedCompressionAlgorithm, err)
+	}
+
+	metadata = map[string]string{
+		ReservedMetadataPrefix + "compression": compressionAlgorithmZstd,
+		compressionDictionaryKey:               "missing",
+	}
+	if _, err := newObjectDecompressReader(bytes.NewReader(data), metadata); err != errCompressionDictionaryNotFound {
+		t.Errorf("expected %v, got %v", errCompressionDictionaryNotFound, err)
+	}
+}
+
+// Tests the selection of the compression algorithm by the compression rules.
+func TestSetCompressionMetadata(t *testing.T) {
+	defer func(algorithm string, rules []compressionRule) {
+		globalCompressAlgorithm, globalCompressRules = algorithm, rules
+	}(globalCompressAlgorithm, globalCompressRules)
+
+	globalCompressAlgorithm = compressionAlgorithmS2
+	globalCompressRules = []compressionRule{
+		{Buckets: []string{"logs-*"}, Algorithm: "zstd", Dictionary: "logs"},
+		{MimeTypes: []string{"text/*"}, Algorithm: "lz4"},
+		{Buckets: []string{"archive"}, Extensions: []string{".json"}, Algorithm: "zstd"},
+	}
+
+	testCases := []struct {
+		bucket, object, contentType string
+		algorithm, dictionary       string
+	}{
+		{"logs-2019", "app.log", "", compressionAlgorithmZstd, "logs"},
+		{"bucket", "object.txt", "text/plain", compressionAlgorithmLZ4, ""},
+		{"archive", "object.json", "application/json", compressionAlgorithmZstd, ""},
+		{"archive", "object.csv", "application/csv", compressionAlgorithmS2, ""},
+		{"bucket", "object.json", "application/json", compressionAlgorithmS2, ""},
+	}
+	for i, testCase := range testCases {
+		header := http.Header{}
+		header.Set("Content-Type", testCase.contentType)
+		// Start with a stale dictionary to check it is replaced.
+		metadata := map[string]string{compressionDictionaryKey: "stale"}
+		setCompressionMetadata(metadata, testCase.bucket, testCase.object, header)
+		if algorithm := metadata[ReservedMetadataPrefix+"compression"]; algorithm != testCase.algorithm {
+			t.Errorf("Test %d: expected algorithm %s, got %s", i+1, testCase.algorithm, algorithm)
+		}
+		if dictionary := metadata[compressionDictionaryKey]; dictionary != testCase.dictionary {
+			t.Errorf("Test %d: expected dictionary %q, got %q", i+1, testCase.dictionary, dictionary)
+		}
+	}
+}
+
+// Tests the validation of the compression config.
+func TestCompressionConfigValidate(t *testing.T) {
+	testCases := []struct {
+		config  compressionConfig
+		success bool
+	}{
+		{compressionConfig{}, true},
+		{compressionConfig{Algorithm: "zstd"}, true},
+		{compressionConfig{Algorithm: "gzip"}, false},
+		{compressionConfig{Rules: []compressionRule{{Algorithm: "brotli"}}}, false},
+		{compressionConfig{
+			Rules:        []compressionRule{{Algorithm: "zstd", Dictionary: "logs"}},
+			Dictionaries: map[string]string{"logs": "/etc/minio/logs.dict"},
+		}, true},
+		{compressionConfig{Rules: []compressionRule{{Algorithm: "zstd", Dictionary: "logs"}}}, false},
+		{compressionConfig{
+			Rules:        []compressionRule{{Algorithm: "s2", Dictionary: "logs"}},
+			Dictionaries: map[string]string{"logs": "/etc/minio/logs.dict"},
+		}, false},
+	}
+	for i, testCase := range testCases {
+		err := testCase.config.Validate()
+		if err != nil && testCase.success {
+			t.Errorf("Test %d: Expected success but failed instead %s", i+1, err)
+		}
+		if err == nil && !testCase.success {
+			t.Errorf("Test %d: Expected failure but passed instead", i+1)
+		}
+	}
+}
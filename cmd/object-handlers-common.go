@@ -20,6 +20,7 @@ import (
 	"context"
 	"net/http"
 	"regexp"
+	"strings"
 	"time"
 
 	"github.com/minio/minio/cmd/crypto"
@@ -151,78 +152,105 @@ func checkPreconditions(ctx context.Context, w http.ResponseWriter, r *http.Requ
 	if r.Method != http.MethodGet && r.Method != http.MethodHead {
 		return false
 	}
-	// If the object doesn't have a modtime (IsZero), or the modtime
-	// is obviously garbage (Unix time == 0), then ignore modtimes
-	// and don't process the If-Modified-Since header.
-	if objInfo.ModTime.IsZero() || objInfo.ModTime.Equal(time.Unix(0, 0)) {
+
+	statusCode := getPreconditionStatus(r.Header, objInfo)
+	if statusCode == 0 {
+		// Object content should be written to http.ResponseWriter
 		return false
 	}
 
-	// Headers to be set of object content is not going to be written to the client.
-	writeHeaders := func() {
-		// set common headers
-		setCommonHeaders(w)
+	// Object content is not going to be written to the client,
+	// set common headers
+	setCommonHeaders(w)
 
-		// set object-related metadata headers
-		w.Header().Set(xhttp.LastModified, objInfo.ModTime.UTC().Format(http.TimeFormat))
+	// set object-related metadata headers
+	w.Header().Set(xhttp.LastModified, objInfo.ModTime.UTC().Format(http.TimeFormat))
 
-		if objInfo.ETag != "" {
-			w.Header()[xhttp.ETag] = []string{"\"" + objInfo.ETag + "\""}
-		}
+	if objInfo.ETag != "" {
+		w.Header()[xhttp.ETag] = []string{"\"" + objInfo.ETag + "\""}
+	}
+
+	if statusCode == http.StatusNotModified {
+		w.WriteHeader(http.StatusNotModified)
+	} else {
+		writeErrorResponse(ctx, w, errorCodes.ToAPIErr(ErrPreconditionFailed), r.URL, guessIsBrowserReq(r))
+	}
+	return true
+}
+
+// getPreconditionStatus - evaluates the GET/HEAD preconditions of the
+// request headers h against objInfo. Returns http.StatusNotModified or
+// http.StatusPreconditionFailed if the object content should not be
+// returned, zero otherwise.
+func getPreconditionStatus(h http.Header, objInfo ObjectInfo) int {
+	// If the object doesn't have a modtime (IsZero), or the modtime
+	// is obviously garbage (Unix time == 0), then ignore modtimes
+	// and don't process the If-Modified-Since header.
+	if objInfo.ModTime.IsZero() || objInfo.ModTime.Equal(time.Unix(0, 0)) {
+		return 0
 	}
+
 	// If-Modified-Since : Return the object only if it has been modified since the specified time,
 	// otherwise return a 304 (not modified).
-	ifModifiedSinceHeader := r.Header.Get(xhttp.IfModifiedSince)
+	ifModifiedSinceHeader := h.Get(xhttp.IfModifiedSince)
 	if ifModifiedSinceHeader != "" {
 		if givenTime, err := time.Parse(http.TimeFormat, ifModifiedSinceHeader); err == nil {
 			if !ifModifiedSince(objInfo.ModTime, givenTime) {
 				// If the object is not modified since the specified time.
-				writeHeaders()
-				w.WriteHeader(http.StatusNotModified)
-				return true
+				return http.StatusNotModified
 			}
 		}
 	}
 
 	// If-Unmodified-Since : Return the object only if it has not been modified since the specified
 	// time, otherwise return a 412 (precondition failed).
-	ifUnmodifiedSinceHeader := r.Header.Get(xhttp.IfUnmodifiedSince)
+	ifUnmodifiedSinceHeader := h.Get(xhttp.IfUnmodifiedSince)
 	if ifUnmodifiedSinceHeader != "" {
 		if givenTime, err := time.Parse(http.TimeFormat, ifUnmodifiedSinceHeader); err == nil {
 			if ifModifiedSince(objInfo.ModTime, givenTime) {
 				// If the object is modified since the specified time.
-				writeHeaders()
-				writeErrorResponse(ctx, w, errorCodes.ToAPIErr(ErrPreconditionFailed), r.URL, guessIsBrowserReq(r))
-				return true
+				return http.StatusPreconditionFailed
 			}
 		}
 	}
 
 	// If-Match : Return the object only if its entity tag (ETag) is the same as the one specified;
 	// otherwise return a 412 (precondition failed).
-	ifMatchETagHeader := r.Header.Get(xhttp.IfMatch)
+	ifMatchETagHeader := h.Get(xhttp.IfMatch)
 	if ifMatchETagHeader != "" {
 		if !isETagEqual(objInfo.ETag, ifMatchETagHeader) {
 			// If the object ETag does not match with the specified ETag.
-			writeHeaders()
-			writeErrorResponse(ctx, w, errorCodes.ToAPIErr(ErrPreconditionFailed), r.URL, guessIsBrowserReq(r))
-			return true
+			return http.StatusPreconditionFailed
 		}
 	}
 
 	// If-None-Match : Return the object only if its entity tag (ETag) is different from the
 	// one specified otherwise, return a 304 (not modified).
-	ifNoneMatchETagHeader := r.Header.Get(xhttp.IfNoneMatch)
+	ifNoneMatchETagHeader := h.Get(xhttp.IfNoneMatch)
 	if ifNoneMatchETagHeader != "" {
 		if isETagEqual(objInfo.ETag, ifNoneMatchETagHeader) {
 			// If the object ETag matches with the specified ETag.
-			writeHeaders()
-			w.WriteHeader(http.StatusNotModified)
-			return true
+			return http.StatusNotModified
 		}
 	}
-	// Object content should be written to http.ResponseWriter
-	return false
+	return 0
+}
+
+// ifRangeMatches - returns true if the range of the request headers h
+// should be served, that is if there is no If-Range header or if its
+// ETag or date matches objInfo. Weak ETags never match.
+func ifRangeMatches(h http.Header, objInfo ObjectInfo) bool {
+	ifRange := h.Get(xhttp.IfRange)
+	if ifRange == "" {
+		return true
+	}
+	if givenTime, err := time.Parse(http.TimeFormat, ifRange); err == nil {
+		return objInfo.ModTime.UTC().Truncate(time.Second).Equal(givenTime)
+	}
+	if strings.HasPrefix(ifRange, "W/") {
+		return false
+	}
+	return isETagEqual(objInfo.ETag, ifRange)
 }
 
 // returns true if object was modified after givenTime.
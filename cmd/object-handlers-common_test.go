@@ -17,7 +17,9 @@
 package cmd
 
 import (
+	"net/http"
 	"testing"
+	"time"
 )
 
 // Tests - canonicalizeETag()
@@ -51,3 +53,30 @@ func TestCanonicalizeETag(t *testing.T) {
 		}
 	}
 }
+
+// Tests - ifRangeMatches()
+func TestIfRangeMatches(t *testing.T) {
+	modTime := time.Date(2020, 3, 1, 10, 0, 0, 500, time.UTC)
+	objInfo := ObjectInfo{ETag: "abcd", ModTime: modTime}
+	testCases := []struct {
+		ifRange string
+		matches bool
+	}{
+		{"", true},
+		{"\"abcd\"", true},
+		{"abcd", true},
+		{"W/\"abcd\"", false},
+		{"\"efgh\"", false},
+		{modTime.Format(http.TimeFormat), true},
+		{modTime.Add(-time.Second).Format(http.TimeFormat), false},
+	}
+	for i, test := range testCases {
+		h := http.Header{}
+		if test.ifRange != "" {
+			h.Set("If-Range", test.ifRange)
+		}
+		if matches := ifRangeMatches(h, objInfo); matches != test.matches {
+			t.Errorf("Test %d: expected %v, got %v", i+1, test.matches, matches)
+		}
+	}
+}
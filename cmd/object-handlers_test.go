@@ -29,6 +29,8 @@ import (
 	"strings"
 
 	"io/ioutil"
+	"mime"
+	"mime/multipart"
 	"net/http"
 	"net/http/httptest"
 	"net/url"
@@ -645,6 +647,126 @@ func testAPIGetObjectHandler(obj ObjectLayer, instanceType, bucketName string, a
 	ExecObjectLayerAPINilTest(t, nilBucket, nilObject, instanceType, apiRouter, nilReq)
 }
 
+// Wrapper for calling GetObject API handler tests with multiple byte ranges.
+func TestAPIGetObjectMultiRangeHandler(t *testing.T) {
+	defer DetectTestLeak(t)()
+	ExecObjectLayerAPITest(t, testAPIGetObjectMultiRangeHandler, []string{"GetObject"})
+}
+
+func testAPIGetObjectMultiRangeHandler(obj ObjectLayer, instanceType, bucketName string, apiRouter http.Handler,
+	credentials auth.Credentials, t *testing.T) {
+
+	objectName := "test-object"
+	data := generateBytesData(1 * humanize.MiByte)
+	_, err := obj.PutObject(context.Background(), bucketName, objectName, mustGetPutObjReader(t, bytes.NewReader(data), int64(len(data)), "", ""),
+		ObjectOptions{UserDefined: map[string]string{"content-type": "application/x-parquet"}})
+	if err != nil {
+		t.Fatalf("%s: Error uploading object: <ERROR> %v", instanceType, err)
+	}
+
+	type part struct {
+		contentRange string
+		content      []byte
+	}
+	size := len(data)
+	testCases := []struct {
+		byteRange          string
+		expectedRespStatus int
+		expectedParts      []part
+	}{
+		// Test case - 1.
+		// Scattered ranges, including a suffix range.
+		{
+			byteRange:          "bytes=0-9,1000-1999, -100",
+			expectedRespStatus: http.StatusPartialContent,
+			expectedParts: []part{
+				{fmt.Sprintf("bytes 0-9/%d", size), data[:10]},
+				{fmt.Sprintf("bytes 1000-1999/%d", size), data[1000:2000]},
+				{fmt.Sprintf("bytes %d-%d/%d", size-100, size-1, size), data[size-100:]},
+			},
+		},
+		// Test case - 2.
+		// Unsatisfiable ranges are left out of the response.
+		{
+			byteRange:          fmt.Sprintf("bytes=%d-,5-9", size),
+			expectedRespStatus: http.StatusPartialContent,
+			expectedParts: []part{
+				{fmt.Sprintf("bytes 5-9/%d", size), data[5:10]},
+			},
+		},
+		// Test case - 3.
+		// No satisfiable range.
+		{
+			byteRange:          fmt.Sprintf("bytes=%d-,%d-", size, size+10),
+			expectedRespStatus: http.StatusRequestedRangeNotSatisfiable,
+		},
+		// Test case - 4.
+		// Too many ranges, the whole object is returned.
+		{
+			byteRange:          "bytes=0-0" + strings.Repeat(",0-0", maxRequestRanges),
+			expectedRespStatus: http.StatusOK,
+			expectedParts:      []part{{"", data}},
+		},
+	}
+
+	for i, testCase := range testCases {
+		rec := httptest.NewRecorder()
+		req, err := newTestSignedRequestV4("GET", getGetObjectURL("", bucketName, objectName),
+			0, nil, credentials.AccessKey, credentials.SecretKey, nil)
+		if err != nil {
+			t.Fatalf("Test %d: %s: Failed to create HTTP request for GetObject: <ERROR> %v", i+1, instanceType, err)
+		}
+		req.Header.Set("Range", testCase.byteRange)
+
+		apiRouter.ServeHTTP(rec, req)
+		if rec.Code != testCase.expectedRespStatus {
+			t.Fatalf("Test %d: %s: Expected the response status to be `%d`, but instead found `%d`", i+1, instanceType, testCase.expectedRespStatus, rec.Code)
+		}
+
+		switch rec.Code {
+		case http.StatusOK:
+			if !bytes.Equal(rec.Body.Bytes(), testCase.expectedParts[0].content) {
+				t.Errorf("Test %d: %s: Object content differs from expected value.", i+1, instanceType)
+			}
+			continue
+		case http.StatusPartialContent:
+		default:
+			continue
+		}
+
+		if rec.Header().Get("Content-Length") != strconv.Itoa(rec.Body.Len()) {
+			t.Errorf("Test %d: %s: Expected Content-Length %d, got %s", i+1, instanceType, rec.Body.Len(), rec.Header().Get("Content-Length"))
+		}
+		mediaType, params, err := mime.ParseMediaType(rec.Header().Get("Content-Type"))
+		if err != nil || mediaType != "multipart/byteranges" {
+			t.Fatalf("Test %d: %s: Unexpected Content-Type %s", i+1, instanceType, rec.Header().Get("Content-Type"))
+		}
+		mr := multipart.NewReader(rec.Body, params["boundary"])
+		for j, expectedPart := range testCase.expectedParts {
+			p, err := mr.NextPart()
+			if err != nil {
+				t.Fatalf("Test %d: %s: Failed to read part %d: <ERROR> %v", i+1, instanceType, j+1, err)
+			}
+			if p.Header.Get("Content-Type") != "application/x-parquet" {
+				t.Errorf("Test %d: %s: Part %d: Unexpected Content-Type %s", i+1, instanceType, j+1, p.Header.Get("Content-Type"))
+			}
+			if p.Header.Get("Content-Range") != expectedPart.contentRange {
+				t.Errorf("Test %d: %s: Part %d: Expected Content-Range %s, got %s", i+1, instanceType, j+1, expectedPart.contentRange, p.Header.Get("Content-Range"))
+			}
+			content, err := ioutil.ReadAll(p)
+			if err != nil {
+				t.Fatalf("Test %d: %s: Failed to read part %d: <ERROR> %v", i+1, instanceType, j+1, err)
+			}
+			if !bytes.Equal(content, expectedPart.content) {
+				t.Errorf("Test %d: %s: Part %d: Content differs from expected value.", i+1, instanceType, j+1)
+			}
+		}
+		if _, err = mr.NextPart(); err != io.EOF {
+			t.Errorf("Test %d: %s: Expected no more parts, got %v", i+1, instanceType, err)
+		}
+	}
+}
+
 // Wrapper for calling GetObject API handler tests for both XL multiple disks and FS single drive setup.
 func TestAPIGetObjectWithMPHandler(t *testing.T) {
 	globalPolicySys = NewPolicySys()
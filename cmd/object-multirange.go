@@ -0,0 +1,251 @@
+/*
+ * MinIO Cloud Storage, (C) 2020 MinIO, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"net/textproto"
+	"strconv"
+
+	"github.com/minio/minio/cmd/crypto"
+	xhttp "github.com/minio/minio/cmd/http"
+	"github.com/minio/minio/pkg/event"
+	"github.com/minio/minio/pkg/handlers"
+	"github.com/minio/minio/pkg/sync/errgroup"
+)
+
+// getObjectNInfoFn is the signature of ObjectLayer.GetObjectNInfo.
+type getObjectNInfoFn func(ctx context.Context, bucket, object string, rs *HTTPRangeSpec, h http.Header, lockType LockType, opts ObjectOptions) (*GetObjectReader, error)
+
+// getObjectNRanges opens a reader for each of the given ranges in
+// parallel. Readers which do not serve the object version with the
+// given ETag fail with PreConditionFailed, so that all parts of a
+// multi range response belong to the same object version.
+func getObjectNRanges(ctx context.Context, getObjectNInfo getObjectNInfoFn, bucket, object, etag string, ranges []*HTTPRangeSpec, h http.Header, opts ObjectOptions) ([]*GetObjectReader, error) {
+	opts.CheckCopyPrecondFn = func(oi ObjectInfo, encETag string) bool {
+		return oi.ETag != etag
+	}
+
+	readers := make([]*GetObjectReader, len(ranges))
+	g := errgroup.WithNErrs(len(ranges))
+	for index := range ranges {
+		index := index
+		g.Go(func() (err error) {
+			readers[index], err = getObjectNInfo(ctx, bucket, object, ranges[index], h, readLock, opts)
+			return err
+		}, index)
+	}
+
+	for _, err := range g.Wait() {
+		if err != nil {
+			for _, gr := range readers {
+				if gr != nil {
+					gr.Close()
+				}
+			}
+			return nil, err
+		}
+	}
+	return readers, nil
+}
+
+// Describes a single part of a multipart/byteranges response.
+type objectRangePart struct {
+	header textproto.MIMEHeader
+	length int64
+}
+
+// getObjectRangeParts returns the parts for all ranges satisfiable
+// for an object of the given size, ranges which are not satisfiable
+// are removed from the returned ranges.
+func getObjectRangeParts(ranges []*HTTPRangeSpec, contentType string, totalSize int64) ([]*HTTPRangeSpec, []objectRangePart) {
+	var satisfiable []*HTTPRangeSpec
+	var parts []objectRangePart
+	for _, rs := range ranges {
+		start, length, err := rs.GetOffsetLength(totalSize)
+		if err != nil {
+			continue
+		}
+		satisfiable = append(satisfiable, rs)
+		parts = append(parts, objectRangePart{
+			header: textproto.MIMEHeader{
+				xhttp.ContentType:  {contentType},
+				xhttp.ContentRange: {fmt.Sprintf("bytes %d-%d/%d", start, start+length-1, totalSize)},
+			},
+			length: length,
+		})
+	}
+	return satisfiable, parts
+}
+
+// countingWriter counts the bytes written to it.
+type countingWriter int64
+
+func (w *countingWriter) Write(p []byte) (int, error) {
+	*w += countingWriter(len(p))
+	return len(p), nil
+}
+
+// getMultiRangeSize returns the size of the multipart/byteranges
+// response body with the given boundary and parts.
+func getMultiRangeSize(boundary string, parts []objectRangePart) (int64, error) {
+	var w countingWriter
+	mw := multipart.NewWriter(&w)
+	if err := mw.SetBoundary(boundary); err != nil {
+		return 0, err
+	}
+	var size int64
+	for _, part := range parts {
+		if _, err := mw.CreatePart(part.header); err != nil {
+			return 0, err
+		}
+		size += part.length
+	}
+	if err := mw.Close(); err != nil {
+		return 0, err
+	}
+	return int64(w) + size, nil
+}
+
+// getObjectMultiRange answers a GetObject request with more than one
+// byte range with a multipart/byteranges response. The readers of all
+// ranges are opened in parallel, and their content is streamed to the
+// client in the requested order.
+func (api objectAPIHandlers) getObjectMultiRange(ctx context.Context, w http.ResponseWriter, r *http.Request, bucket, object string, ranges []*HTTPRangeSpec, opts ObjectOptions) {
+	objectAPI := api.ObjectAPI()
+
+	getObjectInfo := objectAPI.GetObjectInfo
+	getObjectNRangesFn := func(ctx context.Context, bucket, object, etag string, ranges []*HTTPRangeSpec, h http.Header, opts ObjectOptions) ([]*GetObjectReader, error) {
+		return getObjectNRanges(ctx, objectAPI.GetObjectNInfo, bucket, object, etag, ranges, h, opts)
+	}
+	if api.CacheAPI() != nil {
+		getObjectInfo = api.CacheAPI().GetObjectInfo
+		getObjectNRangesFn = api.CacheAPI().GetObjectNRanges
+	}
+
+	objInfo, err := getObjectInfo(ctx, bucket, object, opts)
+	if err != nil {
+		writeErrorResponse(ctx, w, toAPIError(ctx, err), r.URL, guessIsBrowserReq(r))
+		return
+	}
+	// The ETag of the stored object, which is replaced below by the
+	// decrypted ETag for encrypted objects.
+	etag := objInfo.ETag
+
+	if objectAPI.IsEncryptionSupported() {
+		objInfo.UserDefined = CleanMinioInternalMetadataKeys(objInfo.UserDefined)
+		if _, err = DecryptObjectInfo(&objInfo, r.Header); err != nil {
+			writeErrorResponse(ctx, w, toAPIError(ctx, err), r.URL, guessIsBrowserReq(r))
+			return
+		}
+	}
+
+	// Validate pre-conditions if any.
+	if checkPreconditions(ctx, w, r, objInfo) {
+		return
+	}
+
+	totalSize, err := getObjectTotalSize(objInfo)
+	if err != nil {
+		writeErrorResponse(ctx, w, toAPIError(ctx, err), r.URL, guessIsBrowserReq(r))
+		return
+	}
+
+	contentType := objInfo.ContentType
+	if contentType == "" {
+		contentType = "application/octet-stream"
+	}
+	ranges, parts := getObjectRangeParts(ranges, contentType, totalSize)
+	if len(ranges) == 0 {
+		writeErrorResponse(ctx, w, errorCodes.ToAPIErr(ErrInvalidRange), r.URL, guessIsBrowserReq(r))
+		return
+	}
+
+	readers, err := getObjectNRangesFn(ctx, bucket, object, etag, ranges, r.Header, opts)
+	if err != nil {
+		writeErrorResponse(ctx, w, toAPIError(ctx, err), r.URL, guessIsBrowserReq(r))
+		return
+	}
+	defer func() {
+		for _, gr := range readers {
+			gr.Close()
+		}
+	}()
+
+	// Set encryption response headers
+	if objectAPI.IsEncryptionSupported() {
+		if crypto.IsEncrypted(objInfo.UserDefined) {
+			switch {
+			case crypto.S3KMS.IsEncrypted(objInfo.UserDefined):
+				w.Header().Set(crypto.SSEHeader, crypto.SSEAlgorithmKMS)
+				w.Header().Set(crypto.SSEKmsID, objInfo.UserDefined[crypto.S3KMSKeyID])
+			case crypto.S3.IsEncrypted(objInfo.UserDefined):
+				w.Header().Set(crypto.SSEHeader, crypto.SSEAlgorithmAES256)
+			case crypto.SSEC.IsEncrypted(objInfo.UserDefined):
+				w.Header().Set(crypto.SSECAlgorithm, r.Header.Get(crypto.SSECAlgorithm))
+				w.Header().Set(crypto.SSECKeyMD5, r.Header.Get(crypto.SSECKeyMD5))
+			}
+		}
+	}
+
+	if err = setObjectHeaders(w, objInfo, nil); err != nil {
+		writeErrorResponse(ctx, w, toAPIError(ctx, err), r.URL, guessIsBrowserReq(r))
+		return
+	}
+
+	setHeadGetRespHeaders(w, r.URL.Query())
+
+	mw := multipart.NewWriter(w)
+	size, err := getMultiRangeSize(mw.Boundary(), parts)
+	if err != nil {
+		writeErrorResponse(ctx, w, toAPIError(ctx, err), r.URL, guessIsBrowserReq(r))
+		return
+	}
+	w.Header().Set(xhttp.ContentType, "multipart/byteranges; boundary="+mw.Boundary())
+	w.Header().Set(xhttp.ContentLength, strconv.FormatInt(size, 10))
+	w.WriteHeader(http.StatusPartialContent)
+
+	// Write the content of all ranges to the response body, stops
+	// if the request is canceled by an operator.
+	for i, gr := range readers {
+		pw, err := mw.CreatePart(parts[i].header)
+		if err != nil {
+			return
+		}
+		if _, err = io.Copy(pw, cancelableReadCloser{ReadCloser: gr, ctx: ctx}); err != nil {
+			return
+		}
+	}
+	if err = mw.Close(); err != nil {
+		return
+	}
+
+	// Notify object accessed via a GET request.
+	sendEvent(eventArgs{
+		EventName:    event.ObjectAccessedGet,
+		BucketName:   bucket,
+		Object:       objInfo,
+		ReqParams:    extractReqParams(r),
+		RespElements: extractRespElements(w),
+		UserAgent:    r.UserAgent(),
+		Host:         handlers.GetSourceIP(r),
+	})
+}
@@ -0,0 +1,204 @@
+/*
+ * MinIO Cloud Storage, (C) 2020 MinIO, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"errors"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/minio/minio/cmd/rest"
+	"github.com/minio/minio/pkg/madmin"
+)
+
+const (
+	// Interval at which the peers are sent heartbeats.
+	peerHeartbeatInterval = 5 * time.Second
+
+	// Peers which failed their last call and were not heard of,
+	// directly or through other peers, for peerDeadTimeout are dead.
+	peerDeadTimeout = 30 * time.Second
+)
+
+var errPeerDead = errors.New("Peer is not reachable")
+
+// peerMember - liveness of a peer as seen by this server.
+type peerMember struct {
+	// Last time the peer was heard of, directly or through the
+	// heartbeats of other peers.
+	lastSeen time.Time
+	// Whether the last call to the peer failed with a network error.
+	failed    bool
+	lastError string
+}
+
+// peerMembership - tracks the liveness of the peers of this server.
+// Every call to a peer records its outcome, and the peers exchange
+// the times they last heard of each other in periodic heartbeats, so
+// that a peer unreachable from this server only but seen by others
+// is suspected and not declared dead. Calls to dead peers fail fast
+// with errPeerDead, only heartbeats are sent to them until they are
+// reachable again.
+type peerMembership struct {
+	mu      sync.RWMutex
+	members map[string]*peerMember
+}
+
+func newPeerMembership() *peerMembership {
+	return &peerMembership{members: make(map[string]*peerMember)}
+}
+
+// getMember - returns the member of host, created if missing. Peers
+// are given peerDeadTimeout from their first use to be heard of.
+func (m *peerMembership) getMember(host string) *peerMember {
+	member, ok := m.members[host]
+	if !ok {
+		member = &peerMember{lastSeen: UTCNow()}
+		m.members[host] = member
+	}
+	return member
+}
+
+// add - adds the peers at hosts to the membership.
+func (m *peerMembership) add(hosts ...string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for _, host := range hosts {
+		m.getMember(host)
+	}
+}
+
+// record - records the outcome of a call to the peer at host, only
+// errors reaching the peer are failures as it answered otherwise.
+func (m *peerMembership) record(host string, err error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	member := m.getMember(host)
+	if _, ok := err.(*rest.NetworkError); ok {
+		member.failed = true
+		member.lastError = err.Error()
+		return
+	}
+	member.failed = false
+	member.lastError = ""
+	member.lastSeen = UTCNow()
+}
+
+// merge - merges the times a peer last heard of other peers, as
+// returned by its view.
+func (m *peerMembership) merge(view map[string]time.Time) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for host, lastSeen := range view {
+		if member, ok := m.members[host]; ok && lastSeen.After(member.lastSeen) {
+			member.lastSeen = lastSeen
+		}
+	}
+}
+
+// view - returns the times this server last heard of its peers,
+// local is this server which is seen now.
+func (m *peerMembership) view(local string) map[string]time.Time {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	view := make(map[string]time.Time, len(m.members)+1)
+	for host, member := range m.members {
+		view[host] = member.lastSeen
+	}
+	view[local] = UTCNow()
+	return view
+}
+
+func (member *peerMember) state(now time.Time) string {
+	switch {
+	case !member.failed:
+		return madmin.PeerAlive
+	case now.Sub(member.lastSeen) < peerDeadTimeout:
+		return madmin.PeerSuspect
+	default:
+		return madmin.PeerDead
+	}
+}
+
+// isDead - returns true if the peer at host is dead, peers not in the
+// membership are not.
+func (m *peerMembership) isDead(host string) bool {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	member, ok := m.members[host]
+	return ok && member.state(UTCNow()) == madmin.PeerDead
+}
+
+// Members - returns the membership view of this server, local is this
+// server which is alive.
+func (m *peerMembership) Members(local string) []madmin.PeerMember {
+	now := UTCNow()
+	members := []madmin.PeerMember{{Host: local, State: madmin.PeerAlive, LastSeen: now}}
+
+	m.mu.RLock()
+	for host, member := range m.members {
+		members = append(members, madmin.PeerMember{
+			Host:      host,
+			State:     member.state(now),
+			LastSeen:  member.lastSeen,
+			LastError: member.lastError,
+		})
+	}
+	m.mu.RUnlock()
+
+	sort.Slice(members, func(i, j int) bool { return members[i].Host < members[j].Host })
+	return members
+}
+
+// sendHeartbeats - sends a heartbeat with the view of this server to
+// every peer and merges their views.
+func (sys *NotificationSys) sendHeartbeats() {
+	local := GetLocalPeer(globalEndpoints)
+	var wg sync.WaitGroup
+	for _, client := range sys.peerClients {
+		if client == nil {
+			continue
+		}
+		wg.Add(1)
+		go func(client *peerRESTClient) {
+			defer wg.Done()
+			view, err := client.Heartbeat(globalPeerMembership.view(local))
+			if err == nil {
+				globalPeerMembership.merge(view)
+			}
+		}(client)
+	}
+	wg.Wait()
+}
+
+// startPeerHeartbeat - sends heartbeats to the peers every
+// peerHeartbeatInterval until doneCh is closed.
+func (sys *NotificationSys) startPeerHeartbeat(doneCh <-chan struct{}) {
+	ticker := time.NewTicker(peerHeartbeatInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-doneCh:
+			return
+		case <-ticker.C:
+			sys.sendHeartbeats()
+		}
+	}
+}
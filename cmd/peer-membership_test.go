@@ -0,0 +1,79 @@
+/*
+ * MinIO Cloud Storage, (C) 2020 MinIO, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"errors"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/minio/minio/cmd/rest"
+	"github.com/minio/minio/pkg/madmin"
+)
+
+func TestPeerMembership(t *testing.T) {
+	m := newPeerMembership()
+	m.add("node2:9000", "node3:9000")
+
+	getState := func(host string) string {
+		for _, member := range m.Members("node1:9000") {
+			if member.Host == host {
+				return member.State
+			}
+		}
+		t.Fatalf("%s is not a member", host)
+		return ""
+	}
+
+	// Errors answered by the peer are not failures.
+	m.record("node2:9000", errors.New("Bucket not found"))
+	if state := getState("node2:9000"); state != madmin.PeerAlive {
+		t.Fatalf("Expected node2 to be alive, got %s", state)
+	}
+
+	// A peer failing a call is suspected until it was not heard of
+	// for peerDeadTimeout.
+	netErr := &rest.NetworkError{Err: &net.OpError{Op: "dial", Net: "tcp", Err: errors.New("connection refused")}}
+	m.record("node3:9000", netErr)
+	if state := getState("node3:9000"); state != madmin.PeerSuspect || m.isDead("node3:9000") {
+		t.Fatalf("Expected node3 to be suspected, got %s", state)
+	}
+	m.members["node3:9000"].lastSeen = UTCNow().Add(-2 * peerDeadTimeout)
+	if !m.isDead("node3:9000") {
+		t.Fatal("Expected node3 to be dead")
+	}
+
+	// Another peer which heard of it recently brings it back to
+	// suspected, and a successful call back to alive.
+	m.merge(map[string]time.Time{"node3:9000": UTCNow(), "node4:9000": UTCNow()})
+	if state := getState("node3:9000"); state != madmin.PeerSuspect {
+		t.Fatalf("Expected node3 to be suspected, got %s", state)
+	}
+	m.record("node3:9000", nil)
+	if state := getState("node3:9000"); state != madmin.PeerAlive {
+		t.Fatalf("Expected node3 to be alive, got %s", state)
+	}
+
+	// Unknown peers of other servers are not added.
+	if members := m.Members("node1:9000"); len(members) != 3 {
+		t.Fatalf("Expected 3 members, got %v", members)
+	}
+	if m.isDead("node4:9000") {
+		t.Fatal("Expected unknown peers not to be dead")
+	}
+}
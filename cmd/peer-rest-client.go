@@ -30,12 +30,14 @@ import (
 	"github.com/minio/minio/cmd/http"
 	"github.com/minio/minio/cmd/logger"
 	"github.com/minio/minio/cmd/rest"
+	"github.com/minio/minio/pkg/cors"
 	"github.com/minio/minio/pkg/event"
 	"github.com/minio/minio/pkg/lifecycle"
 	"github.com/minio/minio/pkg/madmin"
 	xnet "github.com/minio/minio/pkg/net"
 	"github.com/minio/minio/pkg/policy"
 	trace "github.com/minio/minio/pkg/trace"
+	"github.com/minio/minio/pkg/website"
 )
 
 // client to talk to peer Nodes.
@@ -45,46 +47,35 @@ type peerRESTClient struct {
 	connected  bool
 }
 
-// Reconnect to a peer rest server.
-func (client *peerRESTClient) reConnect() error {
-	// correct (intelligent) retry logic will be
-	// implemented in subsequent PRs.
-	client.connected = true
-	return nil
-}
-
-// Wrapper to restClient.Call to handle network errors, in case of network error the connection is marked disconnected
-// permanently. The only way to restore the connection is at the xl-sets layer by xlsets.monitorAndConnectEndpoints()
-// after verifying format.json
+// Wrapper to restClient.Call, see callWithContext.
 func (client *peerRESTClient) call(method string, values url.Values, body io.Reader, length int64) (respBody io.ReadCloser, err error) {
 	return client.callWithContext(context.Background(), method, values, body, length)
 }
 
-// Wrapper to restClient.Call to handle network errors, in case of network error the connection is marked disconnected
-// permanently. The only way to restore the connection is at the xl-sets layer by xlsets.monitorAndConnectEndpoints()
-// after verifying format.json
+// Wrapper to restClient.Call recording the outcome of the call in the
+// peer membership, calls to peers known to be dead fail fast with
+// errPeerDead except heartbeats which detect them coming back.
 func (client *peerRESTClient) callWithContext(ctx context.Context, method string, values url.Values, body io.Reader, length int64) (respBody io.ReadCloser, err error) {
-	if !client.connected {
-		err := client.reConnect()
-		logger.LogIf(ctx, err)
-		if err != nil {
-			return nil, err
-		}
+	if !client.connected || (method != peerRESTMethodHeartbeat && globalPeerMembership.isDead(client.host.String())) {
+		peerRESTRequestsFailures.WithLabelValues(client.host.String(), method).Inc()
+		return nil, errPeerDead
 	}
 
 	if values == nil {
 		values = make(url.Values)
 	}
 
+	start := UTCNow()
 	respBody, err = client.restClient.CallWithContext(ctx, method, values, body, length)
+	peerRESTRequestsDuration.WithLabelValues(client.host.String(), method).Observe(UTCNow().Sub(start).Seconds())
+	// Calls canceled by the caller say nothing of the peer.
+	if ctx.Err() == nil {
+		globalPeerMembership.record(client.host.String(), err)
+	}
 	if err == nil {
 		return respBody, nil
 	}
-
-	if isNetworkError(err) {
-		client.connected = false
-	}
-
+	peerRESTRequestsFailures.WithLabelValues(client.host.String(), method).Inc()
 	return nil, err
 }
 
@@ -93,9 +84,9 @@ func (client *peerRESTClient) String() string {
 	return client.host.String()
 }
 
-// IsOnline - returns whether RPC client failed to connect or not.
+// IsOnline - returns false if the client is closed or the peer is dead.
 func (client *peerRESTClient) IsOnline() bool {
-	return client.connected
+	return client.connected && !globalPeerMembership.isDead(client.host.String())
 }
 
 // Close - marks the client as closed.
@@ -150,6 +141,193 @@ func (client *peerRESTClient) GetLocks() (locks GetLocksResp, err error) {
 	return locks, err
 }
 
+// ListRequests - fetch in-flight S3 requests of a remote node.
+func (client *peerRESTClient) ListRequests() (requests []madmin.RequestEntry, err error) {
+	respBody, err := client.call(peerRESTMethodListRequests, nil, nil, -1)
+	if err != nil {
+		return
+	}
+	defer http.DrainBody(respBody)
+	err = gob.NewDecoder(respBody).Decode(&requests)
+	return requests, err
+}
+
+// Heartbeat - sends the times this server last heard of its peers to
+// a remote node, returns the times the node last heard of them.
+func (client *peerRESTClient) Heartbeat(view map[string]time.Time) (map[string]time.Time, error) {
+	var reader bytes.Buffer
+	if err := gob.NewEncoder(&reader).Encode(view); err != nil {
+		return nil, err
+	}
+
+	respBody, err := client.call(peerRESTMethodHeartbeat, nil, &reader, -1)
+	if err != nil {
+		return nil, err
+	}
+	defer http.DrainBody(respBody)
+	var peerView map[string]time.Time
+	err = gob.NewDecoder(respBody).Decode(&peerView)
+	return peerView, err
+}
+
+// AccessKeyUsage - fetch the usage of the access keys on a remote node.
+func (client *peerRESTClient) AccessKeyUsage() (usages []madmin.AccessKeyUsage, err error) {
+	respBody, err := client.call(peerRESTMethodAccessKeyUsage, nil, nil, -1)
+	if err != nil {
+		return
+	}
+	defer http.DrainBody(respBody)
+	err = gob.NewDecoder(respBody).Decode(&usages)
+	return usages, err
+}
+
+// KMSKeySweepStatus - fetch the KMS key re-encryption sweep state of a remote node.
+func (client *peerRESTClient) KMSKeySweepStatus() (status madmin.KMSKeySweepStatus, err error) {
+	respBody, err := client.call(peerRESTMethodKMSKeySweepStatus, nil, nil, -1)
+	if err != nil {
+		return
+	}
+	defer http.DrainBody(respBody)
+	err = gob.NewDecoder(respBody).Decode(&status)
+	return status, err
+}
+
+// BatchJobsStatus - fetch the state of the batch jobs of a remote node.
+func (client *peerRESTClient) BatchJobsStatus() (statuses []madmin.BatchJobStatus, err error) {
+	respBody, err := client.call(peerRESTMethodBatchJobsStatus, nil, nil, -1)
+	if err != nil {
+		return
+	}
+	defer http.DrainBody(respBody)
+	err = gob.NewDecoder(respBody).Decode(&statuses)
+	return statuses, err
+}
+
+// StartBatchOperationJob - start a batch operation job on the share of
+// the objects assigned to a remote node.
+func (client *peerRESTClient) StartBatchOperationJob(id string, job madmin.BatchOperationJob) error {
+	values := make(url.Values)
+	values.Set(peerRESTBatchJobID, id)
+
+	var reader bytes.Buffer
+	err := gob.NewEncoder(&reader).Encode(job)
+	if err != nil {
+		return err
+	}
+
+	respBody, err := client.call(peerRESTMethodStartBatchOperationJob, values, &reader, -1)
+	if err != nil {
+		return err
+	}
+	defer http.DrainBody(respBody)
+	return nil
+}
+
+// CancelBatchJob - stop a running batch job on a remote node, returns
+// true if the job was running on that node.
+func (client *peerRESTClient) CancelBatchJob(id string) (bool, error) {
+	values := make(url.Values)
+	values.Set(peerRESTBatchJobID, id)
+	respBody, err := client.call(peerRESTMethodCancelBatchJob, values, nil, -1)
+	if err != nil {
+		return false, err
+	}
+	defer http.DrainBody(respBody)
+	var resp cancelRequestResp
+	err = gob.NewDecoder(respBody).Decode(&resp)
+	return resp.Canceled, err
+}
+
+// BackgroundJobsStatus - fetch the state of the background jobs of a
+// remote node.
+func (client *peerRESTClient) BackgroundJobsStatus() (statuses []madmin.BackgroundJobStatus, err error) {
+	respBody, err := client.call(peerRESTMethodBackgroundJobsStatus, nil, nil, -1)
+	if err != nil {
+		return
+	}
+	defer http.DrainBody(respBody)
+	err = gob.NewDecoder(respBody).Decode(&statuses)
+	return statuses, err
+}
+
+// BackgroundJobAction - pause, resume or run a background job on a
+// remote node.
+func (client *peerRESTClient) BackgroundJobAction(name, action string) error {
+	values := make(url.Values)
+	values.Set(peerRESTJobName, name)
+	values.Set(peerRESTJobAction, action)
+	respBody, err := client.call(peerRESTMethodBackgroundJobAction, values, nil, -1)
+	if err != nil {
+		return err
+	}
+	defer http.DrainBody(respBody)
+	return nil
+}
+
+// SearchObjects - search the object name index of a remote node,
+// returns bucket/object keys.
+func (client *peerRESTClient) SearchObjects(query, bucket string) (keys []string, err error) {
+	values := make(url.Values)
+	values.Set(peerRESTSearchQuery, query)
+	values.Set(peerRESTBucket, bucket)
+	respBody, err := client.call(peerRESTMethodSearchObjects, values, nil, -1)
+	if err != nil {
+		return nil, err
+	}
+	defer http.DrainBody(respBody)
+	err = gob.NewDecoder(respBody).Decode(&keys)
+	return keys, err
+}
+
+// InspectOrphans - inspect the local disks of a remote node, the result
+// of each disk is sent on resultCh as soon as the node streams it.
+func (client *peerRESTClient) InspectOrphans(ctx context.Context, remove bool, olderThan time.Duration, resultCh chan<- madmin.OrphanDiskResult) error {
+	values := make(url.Values)
+	values.Set(peerRESTRemove, strconv.FormatBool(remove))
+	values.Set(peerRESTOlderThan, olderThan.String())
+	respBody, err := client.callWithContext(ctx, peerRESTMethodInspectOrphans, values, nil, -1)
+	if err != nil {
+		return err
+	}
+	defer http.DrainBody(respBody)
+
+	dec := gob.NewDecoder(respBody)
+	for {
+		var result madmin.OrphanDiskResult
+		if err = dec.Decode(&result); err != nil {
+			if err == io.EOF {
+				return nil
+			}
+			return err
+		}
+		select {
+		case resultCh <- result:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+}
+
+// cancelRequestResp is the response of CancelRequest peer call.
+type cancelRequestResp struct {
+	Canceled bool
+}
+
+// CancelRequest - cancel an in-flight S3 request on a remote node,
+// returns true if the request was served by that node.
+func (client *peerRESTClient) CancelRequest(id string) (bool, error) {
+	values := make(url.Values)
+	values.Set(peerRESTRequestID, id)
+	respBody, err := client.call(peerRESTMethodCancelRequest, values, nil, -1)
+	if err != nil {
+		return false, err
+	}
+	defer http.DrainBody(respBody)
+	var resp cancelRequestResp
+	err = gob.NewDecoder(respBody).Decode(&resp)
+	return resp.Canceled, err
+}
+
 // ServerInfo - fetch server information for a remote node.
 func (client *peerRESTClient) ServerInfo() (info ServerInfoData, err error) {
 	respBody, err := client.call(peerRESTMethodServerInfo, nil, nil, -1)
@@ -183,6 +361,29 @@ func (client *peerRESTClient) DrivePerfInfo() (info ServerDrivesPerfInfo, err er
 	return info, err
 }
 
+// DriveLatency - fetch the latency histograms of the drives of a remote node.
+func (client *peerRESTClient) DriveLatency() (info ServerDriveLatencyInfo, err error) {
+	respBody, err := client.call(peerRESTMethodDriveLatency, nil, nil, -1)
+	if err != nil {
+		return
+	}
+	defer http.DrainBody(respBody)
+	err = gob.NewDecoder(respBody).Decode(&info)
+	return info, err
+}
+
+// TargetsHealth - fetch the health of the notification targets as seen
+// by a remote node.
+func (client *peerRESTClient) TargetsHealth() (info madmin.ServerTargetsHealth, err error) {
+	respBody, err := client.call(peerRESTMethodTargetsHealth, nil, nil, -1)
+	if err != nil {
+		return
+	}
+	defer http.DrainBody(respBody)
+	err = gob.NewDecoder(respBody).Decode(&info)
+	return info, err
+}
+
 // MemUsageInfo - fetch memory usage information for a remote node.
 func (client *peerRESTClient) MemUsageInfo() (info ServerMemUsageInfo, err error) {
 	respBody, err := client.call(peerRESTMethodMemUsageInfo, nil, nil, -1)
@@ -217,6 +418,18 @@ func (client *peerRESTClient) DownloadProfileData() (data []byte, err error) {
 	return data, err
 }
 
+// Diagnostics - returns goroutine, heap and thread creation snapshots
+// of a remote node.
+func (client *peerRESTClient) Diagnostics() (data []byte, err error) {
+	respBody, err := client.call(peerRESTMethodDiagnostics, nil, nil, -1)
+	if err != nil {
+		return
+	}
+	defer http.DrainBody(respBody)
+	err = gob.NewDecoder(respBody).Decode(&data)
+	return data, err
+}
+
 // DeleteBucket - Delete notification and policies related to the bucket.
 func (client *peerRESTClient) DeleteBucket(bucket string) error {
 	values := make(url.Values)
@@ -393,6 +606,99 @@ func (client *peerRESTClient) SetBucketLifecycle(bucket string, bucketLifecycle
 	return nil
 }
 
+// RemoveBucketLogging - Remove bucket logging configuration on the peer node
+func (client *peerRESTClient) RemoveBucketLogging(bucket string) error {
+	values := make(url.Values)
+	values.Set(peerRESTBucket, bucket)
+	respBody, err := client.call(peerRESTMethodBucketLoggingRemove, values, nil, -1)
+	if err != nil {
+		return err
+	}
+	defer http.DrainBody(respBody)
+	return nil
+}
+
+// SetBucketLogging - Set bucket logging configuration on the peer node
+func (client *peerRESTClient) SetBucketLogging(bucket string, loggingEnabled LoggingEnabled) error {
+	values := make(url.Values)
+	values.Set(peerRESTBucket, bucket)
+
+	var reader bytes.Buffer
+	err := gob.NewEncoder(&reader).Encode(loggingEnabled)
+	if err != nil {
+		return err
+	}
+
+	respBody, err := client.call(peerRESTMethodBucketLoggingSet, values, &reader, -1)
+	if err != nil {
+		return err
+	}
+	defer http.DrainBody(respBody)
+	return nil
+}
+
+// RemoveBucketCORS - Remove bucket CORS configuration on the peer node
+func (client *peerRESTClient) RemoveBucketCORS(bucket string) error {
+	values := make(url.Values)
+	values.Set(peerRESTBucket, bucket)
+	respBody, err := client.call(peerRESTMethodBucketCORSRemove, values, nil, -1)
+	if err != nil {
+		return err
+	}
+	defer http.DrainBody(respBody)
+	return nil
+}
+
+// SetBucketCORS - Set bucket CORS configuration on the peer node
+func (client *peerRESTClient) SetBucketCORS(bucket string, config cors.Config) error {
+	values := make(url.Values)
+	values.Set(peerRESTBucket, bucket)
+
+	var reader bytes.Buffer
+	err := gob.NewEncoder(&reader).Encode(config)
+	if err != nil {
+		return err
+	}
+
+	respBody, err := client.call(peerRESTMethodBucketCORSSet, values, &reader, -1)
+	if err != nil {
+		return err
+	}
+	defer http.DrainBody(respBody)
+	return nil
+}
+
+// RemoveBucketWebsite - Remove bucket Website configuration on the peer node
+func (client *peerRESTClient) RemoveBucketWebsite(bucket string) error {
+	values := make(url.Values)
+	values.Set(peerRESTBucket, bucket)
+	respBody, err := client.call(peerRESTMethodBucketWebsiteRemove, values, nil, -1)
+	if err != nil {
+		return err
+	}
+	defer http.DrainBody(respBody)
+	return nil
+}
+
+// SetBucketWebsite - Set bucket Website configuration on the peer node
+func (client *peerRESTClient) SetBucketWebsite(bucket string, config website.Config) error {
+	values := make(url.Values)
+	values.Set(peerRESTBucket, bucket)
+
+	var reader bytes.Buffer
+	err := gob.NewEncoder(&reader).Encode(config)
+	if err != nil {
+		return err
+	}
+
+	respBody, err := client.call(peerRESTMethodBucketWebsiteSet, values, &reader, -1)
+	if err != nil {
+		return err
+	}
+	defer http.DrainBody(respBody)
+	return nil
+}
+
 // PutBucketNotification - Put bucket notification on the peer node.
 func (client *peerRESTClient) PutBucketNotification(bucket string, rulesMap event.RulesMap) error {
 	values := make(url.Values)
@@ -412,6 +718,23 @@ func (client *peerRESTClient) PutBucketNotification(bucket string, rulesMap even
 	return nil
 }
 
+// UpdateBuckets - applies a batch of bucket policy, lifecycle and
+// notification updates on the peer node.
+func (client *peerRESTClient) UpdateBuckets(updates []bucketUpdate) error {
+	var reader bytes.Buffer
+	err := gob.NewEncoder(&reader).Encode(updates)
+	if err != nil {
+		return err
+	}
+
+	respBody, err := client.call(peerRESTMethodUpdateBuckets, nil, &reader, -1)
+	if err != nil {
+		return err
+	}
+	defer http.DrainBody(respBody)
+	return nil
+}
+
 // DeletePolicy - delete a specific canned policy.
 func (client *peerRESTClient) DeletePolicy(policyName string) (err error) {
 	values := make(url.Values)
@@ -481,6 +804,22 @@ func (client *peerRESTClient) LoadUser(accessKey string, temp bool) (err error)
 	return nil
 }
 
+// GetIAMChanges - fetch the IAM changes made on a remote node after
+// version of epoch.
+func (client *peerRESTClient) GetIAMChanges(epoch string, version uint64) (changes iamChanges, err error) {
+	values := make(url.Values)
+	values.Set(peerRESTIAMEpoch, epoch)
+	values.Set(peerRESTIAMVersion, strconv.FormatUint(version, 10))
+
+	respBody, err := client.call(peerRESTMethodGetIAMChanges, values, nil, -1)
+	if err != nil {
+		return
+	}
+	defer http.DrainBody(respBody)
+	err = gob.NewDecoder(respBody).Decode(&changes)
+	return changes, err
+}
+
 // LoadUsers - send load users command to peer nodes.
 func (client *peerRESTClient) LoadUsers() (err error) {
 	respBody, err := client.call(peerRESTMethodLoadUsers, nil, nil, -1)
@@ -491,6 +830,57 @@ func (client *peerRESTClient) LoadUsers() (err error) {
 	return nil
 }
 
+// LoadBandwidthLimits - send load bandwidth limits command to peer nodes.
+func (client *peerRESTClient) LoadBandwidthLimits() (err error) {
+	respBody, err := client.call(peerRESTMethodLoadBandwidthLimits, nil, nil, -1)
+	if err != nil {
+		return
+	}
+	defer http.DrainBody(respBody)
+	return nil
+}
+
+// LoadBucketModes - send load bucket modes command to peer nodes.
+func (client *peerRESTClient) LoadBucketModes() (err error) {
+	respBody, err := client.call(peerRESTMethodLoadBucketModes, nil, nil, -1)
+	if err != nil {
+		return
+	}
+	defer http.DrainBody(respBody)
+	return nil
+}
+
+// LoadBucketKeyNormalization - send load bucket key normalization
+// command to peer nodes.
+func (client *peerRESTClient) LoadBucketKeyNormalization() (err error) {
+	respBody, err := client.call(peerRESTMethodLoadKeyNormalization, nil, nil, -1)
+	if err != nil {
+		return
+	}
+	defer http.DrainBody(respBody)
+	return nil
+}
+
+// LoadCacheConfig - send load cache config command to peer nodes.
+func (client *peerRESTClient) LoadCacheConfig() (err error) {
+	respBody, err := client.call(peerRESTMethodLoadCacheConfig, nil, nil, -1)
+	if err != nil {
+		return
+	}
+	defer http.DrainBody(respBody)
+	return nil
+}
+
+// LoadCredentials - send load credentials command to peer nodes.
+func (client *peerRESTClient) LoadCredentials() (err error) {
+	respBody, err := client.call(peerRESTMethodLoadCredentials, nil, nil, -1)
+	if err != nil {
+		return
+	}
+	defer http.DrainBody(respBody)
+	return nil
+}
+
 // LoadGroup - send load group command to peers.
 func (client *peerRESTClient) LoadGroup(group string) error {
 	values := make(url.Values)
@@ -611,6 +1001,61 @@ func (client *peerRESTClient) Trace(traceCh chan interface{}, doneCh chan struct
 	}()
 }
 
+func (client *peerRESTClient) doCacheEvents(eventCh chan interface{}, doneCh chan struct{}) {
+	// To cancel the REST request in case doneCh gets closed.
+	ctx, cancel := context.WithCancel(context.Background())
+
+	cancelCh := make(chan struct{})
+	defer close(cancelCh)
+	go func() {
+		select {
+		case <-doneCh:
+		case <-cancelCh:
+			// There was an error in the REST request.
+		}
+		cancel()
+	}()
+
+	respBody, err := client.callWithContext(ctx, peerRESTMethodCacheEvents, nil, nil, -1)
+	defer http.DrainBody(respBody)
+
+	if err != nil {
+		return
+	}
+
+	dec := gob.NewDecoder(respBody)
+	for {
+		var event madmin.CacheEvent
+		if err = dec.Decode(&event); err != nil {
+			return
+		}
+		// Skip keep alive events.
+		if len(event.Node) > 0 {
+			select {
+			case eventCh <- event:
+			default:
+				// Do not block on slow receivers.
+			}
+		}
+	}
+}
+
+// CacheEvents - listen on disk cache events of peer nodes
+func (client *peerRESTClient) CacheEvents(eventCh chan interface{}, doneCh chan struct{}) {
+	go func() {
+		for {
+			client.doCacheEvents(eventCh, doneCh)
+			select {
+			case <-doneCh:
+				return
+			default:
+				// There was error in the REST request, retry after sometime as probably the peer is down.
+				time.Sleep(5 * time.Second)
+			}
+		}
+	}()
+}
+
 func getRemoteHosts(endpoints EndpointList) []*xnet.Host {
 	var remoteHosts []*xnet.Host
 	for _, hostStr := range GetRemotePeers(endpoints) {
@@ -657,8 +1102,15 @@ func newPeerRESTClient(peer *xnet.Host) (*peerRESTClient, error) {
 		}
 	}
 
-	restClient, err := rest.NewClient(serverURL, tlsConfig, rest.DefaultRESTTimeout, newAuthToken)
-
+	var restClient *rest.Client
+	var err error
+	if globalIsSSL {
+		// Peer calls are small and frequent, multiplex them all
+		// over a single connection per peer.
+		restClient, err = rest.NewHTTP2Client(serverURL, tlsConfig, rest.DefaultRESTTimeout, newAuthToken)
+	} else {
+		restClient, err = rest.NewClient(serverURL, tlsConfig, rest.DefaultRESTTimeout, newAuthToken)
+	}
 	if err != nil {
 		return &peerRESTClient{host: peer, restClient: restClient, connected: false}, err
 	}
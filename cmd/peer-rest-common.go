@@ -16,7 +16,7 @@
 
 package cmd
 
-const peerRESTVersion = "v4"
+const peerRESTVersion = "v15"
 const peerRESTPath = minioReservedBucketPath + "/peer/" + peerRESTVersion
 
 const (
@@ -26,6 +26,8 @@ const (
 	peerRESTMethodCPULoadInfo              = "cpuloadinfo"
 	peerRESTMethodMemUsageInfo             = "memusageinfo"
 	peerRESTMethodDrivePerfInfo            = "driveperfinfo"
+	peerRESTMethodDriveLatency             = "drivelatency"
+	peerRESTMethodTargetsHealth            = "targetshealth"
 	peerRESTMethodDeleteBucket             = "deletebucket"
 	peerRESTMethodSignalService            = "signalservice"
 	peerRESTMethodBackgroundHealStatus     = "backgroundhealstatus"
@@ -48,8 +50,35 @@ const (
 	peerRESTMethodTargetExists             = "targetexists"
 	peerRESTMethodSendEvent                = "sendevent"
 	peerRESTMethodTrace                    = "trace"
+	peerRESTMethodCacheEvents              = "cacheevents"
 	peerRESTMethodBucketLifecycleSet       = "setbucketlifecycle"
 	peerRESTMethodBucketLifecycleRemove    = "removebucketlifecycle"
+	peerRESTMethodBucketLoggingSet         = "setbucketlogging"
+	peerRESTMethodBucketLoggingRemove      = "removebucketlogging"
+	peerRESTMethodBucketCORSSet            = "setbucketcors"
+	peerRESTMethodBucketCORSRemove         = "removebucketcors"
+	peerRESTMethodBucketWebsiteSet         = "setbucketwebsite"
+	peerRESTMethodBucketWebsiteRemove      = "removebucketwebsite"
+	peerRESTMethodListRequests             = "listrequests"
+	peerRESTMethodCancelRequest            = "cancelrequest"
+	peerRESTMethodLoadBandwidthLimits      = "loadbandwidthlimits"
+	peerRESTMethodKMSKeySweepStatus        = "kmskeysweepstatus"
+	peerRESTMethodSearchObjects            = "searchobjects"
+	peerRESTMethodBatchJobsStatus          = "batchjobsstatus"
+	peerRESTMethodCancelBatchJob           = "cancelbatchjob"
+	peerRESTMethodStartBatchOperationJob   = "startbatchoperationjob"
+	peerRESTMethodLoadCacheConfig          = "loadcacheconfig"
+	peerRESTMethodLoadCredentials          = "loadcredentials"
+	peerRESTMethodInspectOrphans           = "inspectorphans"
+	peerRESTMethodUpdateBuckets            = "updatebuckets"
+	peerRESTMethodGetIAMChanges            = "getiamchanges"
+	peerRESTMethodLoadBucketModes          = "loadbucketmodes"
+	peerRESTMethodDiagnostics              = "diagnostics"
+	peerRESTMethodAccessKeyUsage           = "accesskeyusage"
+	peerRESTMethodHeartbeat                = "heartbeat"
+	peerRESTMethodLoadKeyNormalization     = "loadkeynormalization"
+	peerRESTMethodBackgroundJobsStatus     = "backgroundjobsstatus"
+	peerRESTMethodBackgroundJobAction      = "backgroundjobaction"
 )
 
 const (
@@ -66,4 +95,13 @@ const (
 	peerRESTDryRun      = "dry-run"
 	peerRESTTraceAll    = "all"
 	peerRESTTraceErr    = "err"
+	peerRESTRequestID   = "request-id"
+	peerRESTSearchQuery = "query"
+	peerRESTBatchJobID  = "job-id"
+	peerRESTJobName     = "job-name"
+	peerRESTJobAction   = "job-action"
+	peerRESTRemove      = "remove"
+	peerRESTOlderThan   = "older-than"
+	peerRESTIAMEpoch    = "iam-epoch"
+	peerRESTIAMVersion  = "iam-version"
 )
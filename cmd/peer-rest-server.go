@@ -31,11 +31,14 @@ import (
 
 	"github.com/gorilla/mux"
 	"github.com/minio/minio/cmd/logger"
+	"github.com/minio/minio/pkg/cors"
 	"github.com/minio/minio/pkg/event"
 	"github.com/minio/minio/pkg/lifecycle"
+	"github.com/minio/minio/pkg/madmin"
 	xnet "github.com/minio/minio/pkg/net"
 	"github.com/minio/minio/pkg/policy"
 	trace "github.com/minio/minio/pkg/trace"
+	"github.com/minio/minio/pkg/website"
 )
 
 // To abstract a node over network.
@@ -63,6 +66,8 @@ func getServerInfo() (*ServerInfoData, error) {
 			DeploymentID: globalDeploymentID,
 			SQSARN:       globalNotificationSys.GetARNList(),
 			Region:       globalServerConfig.GetRegion(),
+			Backend:      getBackendCircuitInfo(),
+			ReadReplicas: getReadReplicasInfo(),
 		},
 	}, nil
 }
@@ -199,6 +204,238 @@ func (s *peerRESTServer) GetLocksHandler(w http.ResponseWriter, r *http.Request)
 
 }
 
+// ListRequestsHandler - returns in-flight S3 requests of the server.
+func (s *peerRESTServer) ListRequestsHandler(w http.ResponseWriter, r *http.Request) {
+	if !s.IsValid(w, r) {
+		s.writeErrorResponse(w, errors.New("Invalid request"))
+		return
+	}
+
+	ctx := newContext(r, w, "ListRequests")
+	requests := globalRequestRegistry.List(GetLocalPeer(globalEndpoints))
+	defer w.(http.Flusher).Flush()
+	logger.LogIf(ctx, gob.NewEncoder(w).Encode(requests))
+}
+
+// HeartbeatHandler - merges the times the sending peer last heard of
+// the servers, returns the times this server last heard of them.
+func (s *peerRESTServer) HeartbeatHandler(w http.ResponseWriter, r *http.Request) {
+	if !s.IsValid(w, r) {
+		s.writeErrorResponse(w, errors.New("Invalid request"))
+		return
+	}
+
+	var view map[string]time.Time
+	if err := gob.NewDecoder(r.Body).Decode(&view); err != nil {
+		s.writeErrorResponse(w, err)
+		return
+	}
+	globalPeerMembership.merge(view)
+
+	ctx := newContext(r, w, "Heartbeat")
+	defer w.(http.Flusher).Flush()
+	logger.LogIf(ctx, gob.NewEncoder(w).Encode(globalPeerMembership.view(GetLocalPeer(globalEndpoints))))
+}
+
+// AccessKeyUsageHandler - returns the usage of the access keys on the server.
+func (s *peerRESTServer) AccessKeyUsageHandler(w http.ResponseWriter, r *http.Request) {
+	if !s.IsValid(w, r) {
+		s.writeErrorResponse(w, errors.New("Invalid request"))
+		return
+	}
+
+	ctx := newContext(r, w, "AccessKeyUsage")
+	usages := globalAccessKeyUsage.List()
+	defer w.(http.Flusher).Flush()
+	logger.LogIf(ctx, gob.NewEncoder(w).Encode(usages))
+}
+
+// KMSKeySweepStatusHandler - returns the KMS key re-encryption sweep state of the server.
+func (s *peerRESTServer) KMSKeySweepStatusHandler(w http.ResponseWriter, r *http.Request) {
+	if !s.IsValid(w, r) {
+		s.writeErrorResponse(w, errors.New("Invalid request"))
+		return
+	}
+
+	ctx := newContext(r, w, "KMSKeySweepStatus")
+	status := globalKMSKeySweep.Status()
+	defer w.(http.Flusher).Flush()
+	logger.LogIf(ctx, gob.NewEncoder(w).Encode(status))
+}
+
+// BatchJobsStatusHandler - returns the state of the batch jobs of the server.
+func (s *peerRESTServer) BatchJobsStatusHandler(w http.ResponseWriter, r *http.Request) {
+	if !s.IsValid(w, r) {
+		s.writeErrorResponse(w, errors.New("Invalid request"))
+		return
+	}
+
+	ctx := newContext(r, w, "BatchJobsStatus")
+	statuses := globalBatchJobs.Status()
+	defer w.(http.Flusher).Flush()
+	logger.LogIf(ctx, gob.NewEncoder(w).Encode(statuses))
+}
+
+// StartBatchOperationJobHandler - starts a batch operation job on the
+// share of the objects assigned to the server.
+func (s *peerRESTServer) StartBatchOperationJobHandler(w http.ResponseWriter, r *http.Request) {
+	if !s.IsValid(w, r) {
+		s.writeErrorResponse(w, errors.New("Invalid request"))
+		return
+	}
+
+	objAPI := newObjectLayerFn()
+	if objAPI == nil {
+		s.writeErrorResponse(w, errServerNotInitialized)
+		return
+	}
+
+	id := mux.Vars(r)[peerRESTBatchJobID]
+	if id == "" {
+		s.writeErrorResponse(w, errors.New("Batch job ID is missing"))
+		return
+	}
+	if r.ContentLength < 0 {
+		s.writeErrorResponse(w, errInvalidArgument)
+		return
+	}
+
+	var job madmin.BatchOperationJob
+	if err := gob.NewDecoder(r.Body).Decode(&job); err != nil {
+		s.writeErrorResponse(w, err)
+		return
+	}
+	globalBatchJobs.StartOperation(GlobalContext, objAPI, id, job)
+	w.(http.Flusher).Flush()
+}
+
+// CancelBatchJobHandler - stops a running batch job of the server.
+func (s *peerRESTServer) CancelBatchJobHandler(w http.ResponseWriter, r *http.Request) {
+	if !s.IsValid(w, r) {
+		s.writeErrorResponse(w, errors.New("Invalid request"))
+		return
+	}
+
+	ctx := newContext(r, w, "CancelBatchJob")
+	resp := cancelRequestResp{
+		Canceled: globalBatchJobs.Cancel(mux.Vars(r)[peerRESTBatchJobID]),
+	}
+	defer w.(http.Flusher).Flush()
+	logger.LogIf(ctx, gob.NewEncoder(w).Encode(resp))
+}
+
+// BackgroundJobsStatusHandler - returns the state of the background
+// jobs of the server.
+func (s *peerRESTServer) BackgroundJobsStatusHandler(w http.ResponseWriter, r *http.Request) {
+	if !s.IsValid(w, r) {
+		s.writeErrorResponse(w, errors.New("Invalid request"))
+		return
+	}
+
+	ctx := newContext(r, w, "BackgroundJobsStatus")
+	statuses := globalBackgroundJobs.Status()
+	defer w.(http.Flusher).Flush()
+	logger.LogIf(ctx, gob.NewEncoder(w).Encode(statuses))
+}
+
+// BackgroundJobActionHandler - pauses, resumes or runs a background
+// job of the server.
+func (s *peerRESTServer) BackgroundJobActionHandler(w http.ResponseWriter, r *http.Request) {
+	if !s.IsValid(w, r) {
+		s.writeErrorResponse(w, errors.New("Invalid request"))
+		return
+	}
+
+	vars := mux.Vars(r)
+	if err := globalBackgroundJobs.Do(vars[peerRESTJobName], vars[peerRESTJobAction]); err != nil {
+		s.writeErrorResponse(w, err)
+		return
+	}
+	w.(http.Flusher).Flush()
+}
+
+// SearchObjectsHandler - searches the object name index of the server.
+func (s *peerRESTServer) SearchObjectsHandler(w http.ResponseWriter, r *http.Request) {
+	if !s.IsValid(w, r) {
+		s.writeErrorResponse(w, errors.New("Invalid request"))
+		return
+	}
+
+	vars := mux.Vars(r)
+	keys, err := globalSearchIndex.Search(vars[peerRESTSearchQuery], vars[peerRESTBucket], maxSearchResults)
+	if err != nil {
+		s.writeErrorResponse(w, err)
+		return
+	}
+
+	ctx := newContext(r, w, "SearchObjects")
+	defer w.(http.Flusher).Flush()
+	logger.LogIf(ctx, gob.NewEncoder(w).Encode(keys))
+}
+
+// InspectOrphansHandler - streams the orphaned files found on each
+// local disk of the server as soon as the disk is inspected.
+func (s *peerRESTServer) InspectOrphansHandler(w http.ResponseWriter, r *http.Request) {
+	if !s.IsValid(w, r) {
+		s.writeErrorResponse(w, errors.New("Invalid request"))
+		return
+	}
+
+	objAPI := newObjectLayerFn()
+	if objAPI == nil {
+		s.writeErrorResponse(w, errServerNotInitialized)
+		return
+	}
+
+	vars := mux.Vars(r)
+	remove := vars[peerRESTRemove] == "true"
+	olderThan, err := time.ParseDuration(vars[peerRESTOlderThan])
+	if err != nil {
+		s.writeErrorResponse(w, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+	w.(http.Flusher).Flush()
+
+	resultCh := make(chan madmin.OrphanDiskResult)
+	go func() {
+		inspectLocalOrphans(r.Context(), objAPI, remove, olderThan, resultCh)
+		close(resultCh)
+	}()
+
+	enc := gob.NewEncoder(w)
+	for result := range resultCh {
+		// Results are dropped once the caller is gone, until the
+		// inspection notices the canceled request.
+		if err = enc.Encode(result); err == nil {
+			w.(http.Flusher).Flush()
+		}
+	}
+}
+
+// CancelRequestHandler - cancels an in-flight S3 request on the server.
+func (s *peerRESTServer) CancelRequestHandler(w http.ResponseWriter, r *http.Request) {
+	if !s.IsValid(w, r) {
+		s.writeErrorResponse(w, errors.New("Invalid request"))
+		return
+	}
+
+	vars := mux.Vars(r)
+	id := vars[peerRESTRequestID]
+	if id == "" {
+		s.writeErrorResponse(w, errors.New("request id is missing"))
+		return
+	}
+
+	ctx := newContext(r, w, "CancelRequest")
+	resp := cancelRequestResp{
+		Canceled: globalRequestRegistry.Cancel(id),
+	}
+	defer w.(http.Flusher).Flush()
+	logger.LogIf(ctx, gob.NewEncoder(w).Encode(&resp))
+}
+
 // DeletePolicyHandler - deletes a policy on the server.
 func (s *peerRESTServer) DeletePolicyHandler(w http.ResponseWriter, r *http.Request) {
 	if !s.IsValid(w, r) {
@@ -362,6 +599,131 @@ func (s *peerRESTServer) LoadUsersHandler(w http.ResponseWriter, r *http.Request
 	w.(http.Flusher).Flush()
 }
 
+// GetIAMChangesHandler - returns the IAM changes made on the server
+// after the given version.
+func (s *peerRESTServer) GetIAMChangesHandler(w http.ResponseWriter, r *http.Request) {
+	if !s.IsValid(w, r) {
+		s.writeErrorResponse(w, errors.New("Invalid request"))
+		return
+	}
+
+	vars := mux.Vars(r)
+	version, err := strconv.ParseUint(vars[peerRESTIAMVersion], 10, 64)
+	if err != nil {
+		s.writeErrorResponse(w, err)
+		return
+	}
+
+	ctx := newContext(r, w, "GetIAMChanges")
+	changes := globalIAMSys.IAMChangesSince(vars[peerRESTIAMEpoch], version)
+	defer w.(http.Flusher).Flush()
+	logger.LogIf(ctx, gob.NewEncoder(w).Encode(changes))
+}
+
+// LoadBandwidthLimitsHandler - reloads the bandwidth limits.
+func (s *peerRESTServer) LoadBandwidthLimitsHandler(w http.ResponseWriter, r *http.Request) {
+	if !s.IsValid(w, r) {
+		s.writeErrorResponse(w, errors.New("Invalid request"))
+		return
+	}
+
+	if globalBandwidthSys == nil {
+		s.writeErrorResponse(w, errServerNotInitialized)
+		return
+	}
+
+	if err := globalBandwidthSys.Load(newObjectLayerFn()); err != nil {
+		s.writeErrorResponse(w, err)
+		return
+	}
+
+	w.(http.Flusher).Flush()
+}
+
+// LoadBucketModesHandler - reloads the bucket modes.
+func (s *peerRESTServer) LoadBucketModesHandler(w http.ResponseWriter, r *http.Request) {
+	if !s.IsValid(w, r) {
+		s.writeErrorResponse(w, errors.New("Invalid request"))
+		return
+	}
+
+	if globalBucketModeSys == nil {
+		s.writeErrorResponse(w, errServerNotInitialized)
+		return
+	}
+
+	if err := globalBucketModeSys.Load(newObjectLayerFn()); err != nil {
+		s.writeErrorResponse(w, err)
+		return
+	}
+
+	w.(http.Flusher).Flush()
+}
+
+// LoadBucketKeyNormalizationHandler - reloads the bucket key
+// normalization forms.
+func (s *peerRESTServer) LoadBucketKeyNormalizationHandler(w http.ResponseWriter, r *http.Request) {
+	if !s.IsValid(w, r) {
+		s.writeErrorResponse(w, errors.New("Invalid request"))
+		return
+	}
+
+	if globalBucketKeyNormalizationSys == nil {
+		s.writeErrorResponse(w, errServerNotInitialized)
+		return
+	}
+
+	if err := globalBucketKeyNormalizationSys.Load(newObjectLayerFn()); err != nil {
+		s.writeErrorResponse(w, err)
+		return
+	}
+
+	w.(http.Flusher).Flush()
+}
+
+// LoadCacheConfigHandler - reloads the cache drives and exclude patterns.
+func (s *peerRESTServer) LoadCacheConfigHandler(w http.ResponseWriter, r *http.Request) {
+	if !s.IsValid(w, r) {
+		s.writeErrorResponse(w, errors.New("Invalid request"))
+		return
+	}
+
+	objAPI := newObjectLayerFn()
+	if objAPI == nil {
+		s.writeErrorResponse(w, errServerNotInitialized)
+		return
+	}
+
+	if err := reloadCacheConfig(newContext(r, w, "LoadCacheConfig"), objAPI); err != nil {
+		s.writeErrorResponse(w, err)
+		return
+	}
+
+	w.(http.Flusher).Flush()
+}
+
+// LoadCredentialsHandler - reloads the root credential and the previous
+// one kept for its grace period.
+func (s *peerRESTServer) LoadCredentialsHandler(w http.ResponseWriter, r *http.Request) {
+	if !s.IsValid(w, r) {
+		s.writeErrorResponse(w, errors.New("Invalid request"))
+		return
+	}
+
+	objAPI := newObjectLayerFn()
+	if objAPI == nil {
+		s.writeErrorResponse(w, errServerNotInitialized)
+		return
+	}
+
+	if err := reloadCredentials(newContext(r, w, "LoadCredentials"), objAPI); err != nil {
+		s.writeErrorResponse(w, err)
+		return
+	}
+
+	w.(http.Flusher).Flush()
+}
+
 // LoadGroupHandler - reloads group along with members list.
 func (s *peerRESTServer) LoadGroupHandler(w http.ResponseWriter, r *http.Request) {
 	if !s.IsValid(w, r) {
@@ -450,6 +812,25 @@ func (s *peerRESTServer) DownloadProflingDataHandler(w http.ResponseWriter, r *h
 	logger.LogIf(ctx, gob.NewEncoder(w).Encode(profileData))
 }
 
+// DiagnosticsHandler - returns goroutine, heap and thread creation
+// snapshots of this server.
+func (s *peerRESTServer) DiagnosticsHandler(w http.ResponseWriter, r *http.Request) {
+	if !s.IsValid(w, r) {
+		s.writeErrorResponse(w, errors.New("Invalid request"))
+		return
+	}
+
+	ctx := newContext(r, w, "Diagnostics")
+	data, err := getDiagnosticsData()
+	if err != nil {
+		s.writeErrorResponse(w, err)
+		return
+	}
+
+	defer w.(http.Flusher).Flush()
+	logger.LogIf(ctx, gob.NewEncoder(w).Encode(data))
+}
+
 // CPULoadInfoHandler - returns CPU Load info.
 func (s *peerRESTServer) CPULoadInfoHandler(w http.ResponseWriter, r *http.Request) {
 	if !s.IsValid(w, r) {
@@ -478,6 +859,34 @@ func (s *peerRESTServer) DrivePerfInfoHandler(w http.ResponseWriter, r *http.Req
 	logger.LogIf(ctx, gob.NewEncoder(w).Encode(info))
 }
 
+// DriveLatencyHandler - returns the latency histograms of the local drives.
+func (s *peerRESTServer) DriveLatencyHandler(w http.ResponseWriter, r *http.Request) {
+	if !s.IsValid(w, r) {
+		s.writeErrorResponse(w, errors.New("Invalid request"))
+		return
+	}
+
+	ctx := newContext(r, w, "DriveLatency")
+	info := localEndpointsDriveLatency(globalEndpoints, r)
+
+	defer w.(http.Flusher).Flush()
+	logger.LogIf(ctx, gob.NewEncoder(w).Encode(info))
+}
+
+// TargetsHealthHandler - returns the health of the notification targets.
+func (s *peerRESTServer) TargetsHealthHandler(w http.ResponseWriter, r *http.Request) {
+	if !s.IsValid(w, r) {
+		s.writeErrorResponse(w, errors.New("Invalid request"))
+		return
+	}
+
+	ctx := newContext(r, w, "TargetsHealth")
+	info := localTargetsHealth(r)
+
+	defer w.(http.Flusher).Flush()
+	logger.LogIf(ctx, gob.NewEncoder(w).Encode(info))
+}
+
 // MemUsageInfoHandler - returns Memory Usage info.
 func (s *peerRESTServer) MemUsageInfoHandler(w http.ResponseWriter, r *http.Request) {
 	if !s.IsValid(w, r) {
@@ -636,6 +1045,144 @@ func (s *peerRESTServer) SetBucketLifecycleHandler(w http.ResponseWriter, r *htt
 	w.(http.Flusher).Flush()
 }
 
+// RemoveBucketLoggingHandler - Remove bucket logging.
+func (s *peerRESTServer) RemoveBucketLoggingHandler(w http.ResponseWriter, r *http.Request) {
+	if !s.IsValid(w, r) {
+		s.writeErrorResponse(w, errors.New("Invalid request"))
+		return
+	}
+
+	vars := mux.Vars(r)
+	bucketName := vars[peerRESTBucket]
+	if bucketName == "" {
+		s.writeErrorResponse(w, errors.New("Bucket name is missing"))
+		return
+	}
+
+	globalBucketLoggingSys.Remove(bucketName)
+	w.(http.Flusher).Flush()
+}
+
+// SetBucketLoggingHandler - Set bucket logging.
+func (s *peerRESTServer) SetBucketLoggingHandler(w http.ResponseWriter, r *http.Request) {
+	if !s.IsValid(w, r) {
+		s.writeErrorResponse(w, errors.New("Invalid request"))
+		return
+	}
+
+	vars := mux.Vars(r)
+	bucketName := vars[peerRESTBucket]
+	if bucketName == "" {
+		s.writeErrorResponse(w, errors.New("Bucket name is missing"))
+		return
+	}
+	var loggingEnabled LoggingEnabled
+	if r.ContentLength < 0 {
+		s.writeErrorResponse(w, errInvalidArgument)
+		return
+	}
+
+	err := gob.NewDecoder(r.Body).Decode(&loggingEnabled)
+	if err != nil {
+		s.writeErrorResponse(w, err)
+		return
+	}
+	globalBucketLoggingSys.Set(bucketName, loggingEnabled)
+	w.(http.Flusher).Flush()
+}
+
+// RemoveBucketCORSHandler - Remove bucket CORS.
+func (s *peerRESTServer) RemoveBucketCORSHandler(w http.ResponseWriter, r *http.Request) {
+	if !s.IsValid(w, r) {
+		s.writeErrorResponse(w, errors.New("Invalid request"))
+		return
+	}
+
+	vars := mux.Vars(r)
+	bucketName := vars[peerRESTBucket]
+	if bucketName == "" {
+		s.writeErrorResponse(w, errors.New("Bucket name is missing"))
+		return
+	}
+
+	globalBucketCORSSys.Remove(bucketName)
+	w.(http.Flusher).Flush()
+}
+
+// SetBucketCORSHandler - Set bucket CORS.
+func (s *peerRESTServer) SetBucketCORSHandler(w http.ResponseWriter, r *http.Request) {
+	if !s.IsValid(w, r) {
+		s.writeErrorResponse(w, errors.New("Invalid request"))
+		return
+	}
+
+	vars := mux.Vars(r)
+	bucketName := vars[peerRESTBucket]
+	if bucketName == "" {
+		s.writeErrorResponse(w, errors.New("Bucket name is missing"))
+		return
+	}
+	var config cors.Config
+	if r.ContentLength < 0 {
+		s.writeErrorResponse(w, errInvalidArgument)
+		return
+	}
+
+	err := gob.NewDecoder(r.Body).Decode(&config)
+	if err != nil {
+		s.writeErrorResponse(w, err)
+		return
+	}
+	globalBucketCORSSys.Set(bucketName, config)
+	w.(http.Flusher).Flush()
+}
+
+// RemoveBucketWebsiteHandler - Remove bucket Website.
+func (s *peerRESTServer) RemoveBucketWebsiteHandler(w http.ResponseWriter, r *http.Request) {
+	if !s.IsValid(w, r) {
+		s.writeErrorResponse(w, errors.New("Invalid request"))
+		return
+	}
+
+	vars := mux.Vars(r)
+	bucketName := vars[peerRESTBucket]
+	if bucketName == "" {
+		s.writeErrorResponse(w, errors.New("Bucket name is missing"))
+		return
+	}
+
+	globalBucketWebsiteSys.Remove(bucketName)
+	w.(http.Flusher).Flush()
+}
+
+// SetBucketWebsiteHandler - Set bucket Website.
+func (s *peerRESTServer) SetBucketWebsiteHandler(w http.ResponseWriter, r *http.Request) {
+	if !s.IsValid(w, r) {
+		s.writeErrorResponse(w, errors.New("Invalid request"))
+		return
+	}
+
+	vars := mux.Vars(r)
+	bucketName := vars[peerRESTBucket]
+	if bucketName == "" {
+		s.writeErrorResponse(w, errors.New("Bucket name is missing"))
+		return
+	}
+	var config website.Config
+	if r.ContentLength < 0 {
+		s.writeErrorResponse(w, errInvalidArgument)
+		return
+	}
+
+	err := gob.NewDecoder(r.Body).Decode(&config)
+	if err != nil {
+		s.writeErrorResponse(w, err)
+		return
+	}
+	globalBucketWebsiteSys.Set(bucketName, config)
+	w.(http.Flusher).Flush()
+}
+
 type remoteTargetExistsResp struct {
 	Exists bool
 }
@@ -757,6 +1304,32 @@ func (s *peerRESTServer) PutBucketNotificationHandler(w http.ResponseWriter, r *
 	w.(http.Flusher).Flush()
 }
 
+// UpdateBucketsHandler - applies a batch of bucket updates.
+func (s *peerRESTServer) UpdateBucketsHandler(w http.ResponseWriter, r *http.Request) {
+	if !s.IsValid(w, r) {
+		s.writeErrorResponse(w, errors.New("Invalid request"))
+		return
+	}
+
+	if r.ContentLength < 0 {
+		s.writeErrorResponse(w, errInvalidArgument)
+		return
+	}
+
+	var updates []bucketUpdate
+	err := gob.NewDecoder(r.Body).Decode(&updates)
+	if err != nil {
+		s.writeErrorResponse(w, err)
+		return
+	}
+
+	if err = applyBucketUpdates(updates); err != nil {
+		s.writeErrorResponse(w, err)
+		return
+	}
+	w.(http.Flusher).Flush()
+}
+
 type listenBucketNotificationReq struct {
 	EventNames []event.Name   `json:"eventNames"`
 	Pattern    string         `json:"pattern"`
@@ -878,6 +1451,47 @@ func (s *peerRESTServer) TraceHandler(w http.ResponseWriter, r *http.Request) {
 	}
 }
 
+// CacheEventsHandler sends disk cache events back to peer rest client
+func (s *peerRESTServer) CacheEventsHandler(w http.ResponseWriter, r *http.Request) {
+	if !s.IsValid(w, r) {
+		s.writeErrorResponse(w, errors.New("Invalid request"))
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+	w.(http.Flusher).Flush()
+
+	doneCh := make(chan struct{})
+	defer close(doneCh)
+
+	// Cache events publisher uses nonblocking publish and hence does not wait for slow subscribers.
+	// Use buffered channel to take care of burst sends or slow w.Write()
+	ch := make(chan interface{}, 2000)
+
+	globalCacheEvents.Subscribe(ch, doneCh, func(entry interface{}) bool {
+		return true
+	})
+
+	keepAliveTicker := time.NewTicker(500 * time.Millisecond)
+	defer keepAliveTicker.Stop()
+
+	enc := gob.NewEncoder(w)
+	for {
+		select {
+		case entry := <-ch:
+			if err := enc.Encode(entry); err != nil {
+				return
+			}
+			w.(http.Flusher).Flush()
+		case <-keepAliveTicker.C:
+			if err := enc.Encode(&madmin.CacheEvent{}); err != nil {
+				return
+			}
+			w.(http.Flusher).Flush()
+		}
+	}
+}
+
 func (s *peerRESTServer) BackgroundHealStatusHandler(w http.ResponseWriter, r *http.Request) {
 	if !s.IsValid(w, r) {
 		s.writeErrorResponse(w, errors.New("invalid request"))
@@ -929,10 +1543,24 @@ func registerPeerRESTHandlers(router *mux.Router) {
 	subrouter.Methods(http.MethodPost).Path(SlashSeparator + peerRESTMethodNetReadPerfInfo).HandlerFunc(httpTraceHdrs(server.NetReadPerfInfoHandler))
 	subrouter.Methods(http.MethodPost).Path(SlashSeparator + peerRESTMethodCollectNetPerfInfo).HandlerFunc(httpTraceHdrs(server.CollectNetPerfInfoHandler))
 	subrouter.Methods(http.MethodPost).Path(SlashSeparator + peerRESTMethodGetLocks).HandlerFunc(httpTraceHdrs(server.GetLocksHandler))
+	subrouter.Methods(http.MethodPost).Path(SlashSeparator + peerRESTMethodListRequests).HandlerFunc(httpTraceHdrs(server.ListRequestsHandler))
+	subrouter.Methods(http.MethodPost).Path(SlashSeparator + peerRESTMethodAccessKeyUsage).HandlerFunc(httpTraceHdrs(server.AccessKeyUsageHandler))
+	subrouter.Methods(http.MethodPost).Path(SlashSeparator + peerRESTMethodHeartbeat).HandlerFunc(httpTraceHdrs(server.HeartbeatHandler))
+	subrouter.Methods(http.MethodPost).Path(SlashSeparator + peerRESTMethodKMSKeySweepStatus).HandlerFunc(httpTraceHdrs(server.KMSKeySweepStatusHandler))
+	subrouter.Methods(http.MethodPost).Path(SlashSeparator + peerRESTMethodCancelRequest).HandlerFunc(httpTraceHdrs(server.CancelRequestHandler)).Queries(restQueries(peerRESTRequestID)...)
+	subrouter.Methods(http.MethodPost).Path(SlashSeparator + peerRESTMethodBatchJobsStatus).HandlerFunc(httpTraceHdrs(server.BatchJobsStatusHandler))
+	subrouter.Methods(http.MethodPost).Path(SlashSeparator + peerRESTMethodCancelBatchJob).HandlerFunc(httpTraceHdrs(server.CancelBatchJobHandler)).Queries(restQueries(peerRESTBatchJobID)...)
+	subrouter.Methods(http.MethodPost).Path(SlashSeparator + peerRESTMethodBackgroundJobsStatus).HandlerFunc(httpTraceHdrs(server.BackgroundJobsStatusHandler))
+	subrouter.Methods(http.MethodPost).Path(SlashSeparator + peerRESTMethodBackgroundJobAction).HandlerFunc(httpTraceHdrs(server.BackgroundJobActionHandler)).Queries(restQueries(peerRESTJobName, peerRESTJobAction)...)
+	subrouter.Methods(http.MethodPost).Path(SlashSeparator + peerRESTMethodStartBatchOperationJob).HandlerFunc(httpTraceHdrs(server.StartBatchOperationJobHandler)).Queries(restQueries(peerRESTBatchJobID)...)
+	subrouter.Methods(http.MethodPost).Path(SlashSeparator + peerRESTMethodSearchObjects).HandlerFunc(httpTraceHdrs(server.SearchObjectsHandler)).Queries(restQueries(peerRESTSearchQuery, peerRESTBucket)...)
+	subrouter.Methods(http.MethodPost).Path(SlashSeparator + peerRESTMethodInspectOrphans).HandlerFunc(httpTraceHdrs(server.InspectOrphansHandler)).Queries(restQueries(peerRESTRemove, peerRESTOlderThan)...)
 	subrouter.Methods(http.MethodPost).Path(SlashSeparator + peerRESTMethodServerInfo).HandlerFunc(httpTraceHdrs(server.ServerInfoHandler))
 	subrouter.Methods(http.MethodPost).Path(SlashSeparator + peerRESTMethodCPULoadInfo).HandlerFunc(httpTraceHdrs(server.CPULoadInfoHandler))
 	subrouter.Methods(http.MethodPost).Path(SlashSeparator + peerRESTMethodMemUsageInfo).HandlerFunc(httpTraceHdrs(server.MemUsageInfoHandler))
 	subrouter.Methods(http.MethodPost).Path(SlashSeparator + peerRESTMethodDrivePerfInfo).HandlerFunc(httpTraceHdrs(server.DrivePerfInfoHandler))
+	subrouter.Methods(http.MethodPost).Path(SlashSeparator + peerRESTMethodDriveLatency).HandlerFunc(httpTraceHdrs(server.DriveLatencyHandler))
+	subrouter.Methods(http.MethodPost).Path(SlashSeparator + peerRESTMethodTargetsHealth).HandlerFunc(httpTraceHdrs(server.TargetsHealthHandler))
 	subrouter.Methods(http.MethodPost).Path(SlashSeparator + peerRESTMethodDeleteBucket).HandlerFunc(httpTraceHdrs(server.DeleteBucketHandler)).Queries(restQueries(peerRESTBucket)...)
 	subrouter.Methods(http.MethodPost).Path(SlashSeparator + peerRESTMethodSignalService).HandlerFunc(httpTraceHdrs(server.SignalServiceHandler)).Queries(restQueries(peerRESTSignal)...)
 
@@ -946,21 +1574,36 @@ func registerPeerRESTHandlers(router *mux.Router) {
 	subrouter.Methods(http.MethodPost).Path(SlashSeparator + peerRESTMethodLoadUser).HandlerFunc(httpTraceAll(server.LoadUserHandler)).Queries(restQueries(peerRESTUser, peerRESTUserTemp)...)
 	subrouter.Methods(http.MethodPost).Path(SlashSeparator + peerRESTMethodLoadUsers).HandlerFunc(httpTraceAll(server.LoadUsersHandler))
 	subrouter.Methods(http.MethodPost).Path(SlashSeparator + peerRESTMethodLoadGroup).HandlerFunc(httpTraceAll(server.LoadGroupHandler)).Queries(restQueries(peerRESTGroup)...)
+	subrouter.Methods(http.MethodPost).Path(SlashSeparator + peerRESTMethodGetIAMChanges).HandlerFunc(httpTraceAll(server.GetIAMChangesHandler)).Queries(restQueries(peerRESTIAMEpoch, peerRESTIAMVersion)...)
+	subrouter.Methods(http.MethodPost).Path(SlashSeparator + peerRESTMethodLoadBandwidthLimits).HandlerFunc(httpTraceAll(server.LoadBandwidthLimitsHandler))
+	subrouter.Methods(http.MethodPost).Path(SlashSeparator + peerRESTMethodLoadBucketModes).HandlerFunc(httpTraceAll(server.LoadBucketModesHandler))
+	subrouter.Methods(http.MethodPost).Path(SlashSeparator + peerRESTMethodLoadKeyNormalization).HandlerFunc(httpTraceAll(server.LoadBucketKeyNormalizationHandler))
+	subrouter.Methods(http.MethodPost).Path(SlashSeparator + peerRESTMethodLoadCacheConfig).HandlerFunc(httpTraceAll(server.LoadCacheConfigHandler))
+	subrouter.Methods(http.MethodPost).Path(SlashSeparator + peerRESTMethodLoadCredentials).HandlerFunc(httpTraceAll(server.LoadCredentialsHandler))
 
 	subrouter.Methods(http.MethodPost).Path(SlashSeparator + peerRESTMethodStartProfiling).HandlerFunc(httpTraceAll(server.StartProfilingHandler)).Queries(restQueries(peerRESTProfiler)...)
 	subrouter.Methods(http.MethodPost).Path(SlashSeparator + peerRESTMethodDownloadProfilingData).HandlerFunc(httpTraceHdrs(server.DownloadProflingDataHandler))
+	subrouter.Methods(http.MethodPost).Path(SlashSeparator + peerRESTMethodDiagnostics).HandlerFunc(httpTraceHdrs(server.DiagnosticsHandler))
 
 	subrouter.Methods(http.MethodPost).Path(SlashSeparator + peerRESTMethodTargetExists).HandlerFunc(httpTraceHdrs(server.TargetExistsHandler)).Queries(restQueries(peerRESTBucket)...)
 	subrouter.Methods(http.MethodPost).Path(SlashSeparator + peerRESTMethodSendEvent).HandlerFunc(httpTraceHdrs(server.SendEventHandler)).Queries(restQueries(peerRESTBucket)...)
 	subrouter.Methods(http.MethodPost).Path(SlashSeparator + peerRESTMethodBucketNotificationPut).HandlerFunc(httpTraceHdrs(server.PutBucketNotificationHandler)).Queries(restQueries(peerRESTBucket)...)
+	subrouter.Methods(http.MethodPost).Path(SlashSeparator + peerRESTMethodUpdateBuckets).HandlerFunc(httpTraceHdrs(server.UpdateBucketsHandler))
 	subrouter.Methods(http.MethodPost).Path(SlashSeparator + peerRESTMethodBucketNotificationListen).HandlerFunc(httpTraceHdrs(server.ListenBucketNotificationHandler)).Queries(restQueries(peerRESTBucket)...)
 
 	subrouter.Methods(http.MethodPost).Path(SlashSeparator + peerRESTMethodReloadFormat).HandlerFunc(httpTraceHdrs(server.ReloadFormatHandler)).Queries(restQueries(peerRESTDryRun)...)
 	subrouter.Methods(http.MethodPost).Path(SlashSeparator + peerRESTMethodBucketLifecycleSet).HandlerFunc(httpTraceHdrs(server.SetBucketLifecycleHandler)).Queries(restQueries(peerRESTBucket)...)
 	subrouter.Methods(http.MethodPost).Path(SlashSeparator + peerRESTMethodBucketLifecycleRemove).HandlerFunc(httpTraceHdrs(server.RemoveBucketLifecycleHandler)).Queries(restQueries(peerRESTBucket)...)
+	subrouter.Methods(http.MethodPost).Path(SlashSeparator + peerRESTMethodBucketLoggingSet).HandlerFunc(httpTraceHdrs(server.SetBucketLoggingHandler)).Queries(restQueries(peerRESTBucket)...)
+	subrouter.Methods(http.MethodPost).Path(SlashSeparator + peerRESTMethodBucketLoggingRemove).HandlerFunc(httpTraceHdrs(server.RemoveBucketLoggingHandler)).Queries(restQueries(peerRESTBucket)...)
+	subrouter.Methods(http.MethodPost).Path(SlashSeparator + peerRESTMethodBucketCORSSet).HandlerFunc(httpTraceHdrs(server.SetBucketCORSHandler)).Queries(restQueries(peerRESTBucket)...)
+	subrouter.Methods(http.MethodPost).Path(SlashSeparator + peerRESTMethodBucketCORSRemove).HandlerFunc(httpTraceHdrs(server.RemoveBucketCORSHandler)).Queries(restQueries(peerRESTBucket)...)
+	subrouter.Methods(http.MethodPost).Path(SlashSeparator + peerRESTMethodBucketWebsiteSet).HandlerFunc(httpTraceHdrs(server.SetBucketWebsiteHandler)).Queries(restQueries(peerRESTBucket)...)
+	subrouter.Methods(http.MethodPost).Path(SlashSeparator + peerRESTMethodBucketWebsiteRemove).HandlerFunc(httpTraceHdrs(server.RemoveBucketWebsiteHandler)).Queries(restQueries(peerRESTBucket)...)
 	subrouter.Methods(http.MethodPost).Path(SlashSeparator + peerRESTMethodBackgroundOpsStatus).HandlerFunc(server.BackgroundOpsStatusHandler)
 
 	subrouter.Methods(http.MethodPost).Path(SlashSeparator + peerRESTMethodTrace).HandlerFunc(server.TraceHandler)
+	subrouter.Methods(http.MethodPost).Path(SlashSeparator + peerRESTMethodCacheEvents).HandlerFunc(server.CacheEventsHandler)
 	subrouter.Methods(http.MethodPost).Path(SlashSeparator + peerRESTMethodBackgroundHealStatus).HandlerFunc(server.BackgroundHealStatusHandler)
 
 	router.NotFoundHandler = http.HandlerFunc(httpTraceAll(notFoundHandler))
@@ -20,7 +20,9 @@ import (
 	"bytes"
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"net"
 	"net/http"
 	"path"
 	"strings"
@@ -31,6 +33,8 @@ import (
 	"github.com/minio/minio/cmd/logger"
 	"github.com/minio/minio/pkg/event"
 	"github.com/minio/minio/pkg/handlers"
+	iampolicy "github.com/minio/minio/pkg/iam/policy"
+	"github.com/minio/minio/pkg/madmin"
 	"github.com/minio/minio/pkg/policy"
 )
 
@@ -75,6 +79,16 @@ func (sys *PolicySys) Set(bucketName string, policy policy.Policy) {
 	}
 }
 
+// Get - returns the cached policy of the given bucket, no policy is
+// cached under gateway mode.
+func (sys *PolicySys) Get(bucketName string) (policy.Policy, bool) {
+	sys.RLock()
+	defer sys.RUnlock()
+
+	p, found := sys.bucketPolicyMap[bucketName]
+	return p, found
+}
+
 // Remove - removes policy for given bucket name.
 func (sys *PolicySys) Remove(bucketName string) {
 	sys.Lock()
@@ -110,6 +124,57 @@ func (sys *PolicySys) IsAllowed(args policy.Args) bool {
 	return args.IsOwner
 }
 
+// Simulate - checks given policy args like IsAllowed, explaining which
+// bucket policy statement decided.
+func (sys *PolicySys) Simulate(args policy.Args) (madmin.PolicySimulationResult, error) {
+	result := madmin.PolicySimulationResult{Source: madmin.PolicySourceBucket}
+
+	var p policy.Policy
+	var found bool
+	if globalIsGateway {
+		objAPI := newObjectLayerFn()
+		if objAPI == nil {
+			return result, errServerNotInitialized
+		}
+		config, err := objAPI.GetBucketPolicy(context.Background(), args.BucketName)
+		if err != nil {
+			if _, ok := err.(BucketPolicyNotFound); !ok {
+				return result, err
+			}
+		} else {
+			p, found = *config, true
+		}
+	} else {
+		sys.RLock()
+		p, found = sys.bucketPolicyMap[args.BucketName]
+		sys.RUnlock()
+	}
+
+	if !found {
+		result.Allowed = args.IsOwner
+		result.Reason = "The bucket has no policy, only the owner is allowed."
+		return result, nil
+	}
+
+	allowed, i := p.Evaluate(args)
+	result.Allowed = allowed
+	switch {
+	case i < 0 && allowed:
+		result.Reason = "The owner is allowed all actions not denied by the bucket policy."
+		return result, nil
+	case i < 0:
+		result.Reason = "No statement of the bucket policy allows the action."
+		return result, nil
+	case allowed:
+		result.Reason = "Allowed by a statement of the bucket policy."
+	default:
+		result.Reason = "Denied by a statement of the bucket policy."
+	}
+	var err error
+	result.Statement, err = json.Marshal(p.Statements[i])
+	return result, err
+}
+
 // Refresh PolicySys.
 func (sys *PolicySys) refresh(objAPI ObjectLayer) error {
 	buckets, err := objAPI.ListBuckets(context.Background())
@@ -195,7 +260,7 @@ func getConditionValues(request *http.Request, locationConstraint string, userna
 		return "Anonymous"
 	}()
 	args := map[string][]string{
-		"CurrenTime":      {currTime.Format(event.AMZTimeFormat)},
+		"CurrentTime":     {currTime.Format(event.AMZTimeFormat)},
 		"EpochTime":       {fmt.Sprintf("%d", currTime.Unix())},
 		"principaltype":   {principalType},
 		"SecureTransport": {fmt.Sprintf("%t", request.TLS != nil)},
@@ -229,6 +294,57 @@ func getConditionValues(request *http.Request, locationConstraint string, userna
 	return args
 }
 
+var errPolicySimulationInvalidAction = errors.New("The action to simulate is not supported")
+
+// simulatePolicy - evaluates the request described by sim against the
+// current policies like checkRequestAuthType does, requests of users
+// are checked against IAM policies and anonymous requests against the
+// bucket policy.
+func simulatePolicy(sim madmin.PolicySimulation) (madmin.PolicySimulationResult, error) {
+	// Default condition values of a request, as if sent without TLS
+	// from an unknown address.
+	currTime := UTCNow()
+	principalType := "Anonymous"
+	if sim.User != "" {
+		principalType = "User"
+	}
+	conditionValues := map[string][]string{
+		"CurrentTime":     {currTime.Format(event.AMZTimeFormat)},
+		"EpochTime":       {fmt.Sprintf("%d", currTime.Unix())},
+		"principaltype":   {principalType},
+		"SecureTransport": {"false"},
+		"userid":          {sim.User},
+		"username":        {sim.User},
+	}
+	for key, values := range sim.Conditions {
+		conditionValues[key] = values
+	}
+
+	if sim.User == "" {
+		if !policy.Action(sim.Action).IsValid() {
+			return madmin.PolicySimulationResult{}, errPolicySimulationInvalidAction
+		}
+		return globalPolicySys.Simulate(policy.Args{
+			Action:          policy.Action(sim.Action),
+			BucketName:      sim.Bucket,
+			ConditionValues: conditionValues,
+			ObjectName:      sim.Object,
+		})
+	}
+
+	if !iampolicy.Action(sim.Action).IsValid() {
+		return madmin.PolicySimulationResult{}, errPolicySimulationInvalidAction
+	}
+	return globalIAMSys.Simulate(iampolicy.Args{
+		AccountName:     sim.User,
+		Action:          iampolicy.Action(sim.Action),
+		BucketName:      sim.Bucket,
+		ConditionValues: conditionValues,
+		IsOwner:         sim.User == globalActiveCred.AccessKey,
+		ObjectName:      sim.Object,
+	})
+}
+
 // getPolicyConfig - get policy config for given bucket name.
 func getPolicyConfig(objAPI ObjectLayer, bucketName string) (*policy.Policy, error) {
 	// Construct path to policy.json for the given bucket.
@@ -311,3 +427,128 @@ func BucketAccessPolicyToPolicy(policyInfo *miniogopolicy.BucketAccessPolicy) (*
 
 	return &bucketPolicy, nil
 }
+
+// Statement IDs of the bucket policy statements restricting anonymous
+// access to a bucket by client IP address, set from the browser.
+const (
+	allowedIPsStatementSID = "MinIOBrowserAllowedIPs"
+	deniedIPsStatementSID  = "MinIOBrowserDeniedIPs"
+)
+
+// ipRestrictedActions - actions granted by canned policies, which are
+// denied to restricted client IP addresses.
+var ipRestrictedActions = []string{
+	"s3:GetBucketLocation",
+	"s3:ListBucket",
+	"s3:ListBucketMultipartUploads",
+	"s3:GetObject",
+	"s3:PutObject",
+	"s3:DeleteObject",
+	"s3:AbortMultipartUpload",
+	"s3:ListMultipartUploadParts",
+}
+
+// bucketIPRestrictions - client IP networks anonymous access to a bucket
+// is allowed from and denied from. Empty AllowedIPs allow all clients.
+type bucketIPRestrictions struct {
+	AllowedIPs []string
+	DeniedIPs  []string
+}
+
+// parseIPNetworks - parses IP addresses and CIDR networks, IP addresses
+// are converted to single host networks.
+func parseIPNetworks(values []string) ([]string, error) {
+	var networks []string
+	for _, value := range values {
+		if ip := net.ParseIP(value); ip != nil {
+			bits := 8 * net.IPv6len
+			if ip4 := ip.To4(); ip4 != nil {
+				ip, bits = ip4, 8*net.IPv4len
+			}
+			value = (&net.IPNet{IP: ip, Mask: net.CIDRMask(bits, bits)}).String()
+		}
+		_, ipNet, err := net.ParseCIDR(value)
+		if err != nil {
+			return nil, fmt.Errorf("invalid IP address or network '%s'", value)
+		}
+		networks = append(networks, ipNet.String())
+	}
+	return networks, nil
+}
+
+// newIPRestrictionStatements - returns bucket policy statements denying
+// anonymous access to the bucket from clients outside of allowed IP
+// networks and within denied IP networks.
+func newIPRestrictionStatements(bucketName string, restrictions bucketIPRestrictions) []miniogopolicy.Statement {
+	newStatement := func(sid, conditionName string, networks []string) miniogopolicy.Statement {
+		return miniogopolicy.Statement{
+			Sid:       sid,
+			Effect:    string(policy.Deny),
+			Principal: miniogopolicy.User{AWS: set.CreateStringSet("*")},
+			Actions:   set.CreateStringSet(ipRestrictedActions...),
+			Resources: set.CreateStringSet(
+				policy.NewResource(bucketName, "").String(),
+				policy.NewResource(bucketName, "*").String(),
+			),
+			Conditions: miniogopolicy.ConditionMap{
+				conditionName: miniogopolicy.ConditionKeyMap{
+					"aws:SourceIp": set.CreateStringSet(networks...),
+				},
+			},
+		}
+	}
+
+	var statements []miniogopolicy.Statement
+	if len(restrictions.AllowedIPs) != 0 {
+		statements = append(statements, newStatement(allowedIPsStatementSID, "NotIpAddress", restrictions.AllowedIPs))
+	}
+	if len(restrictions.DeniedIPs) != 0 {
+		statements = append(statements, newStatement(deniedIPsStatementSID, "IpAddress", restrictions.DeniedIPs))
+	}
+	return statements
+}
+
+// splitIPRestrictionStatements - separates the IP restriction statements
+// set from the browser from the canned policy statements.
+func splitIPRestrictionStatements(statements []miniogopolicy.Statement) (canned, restrictions []miniogopolicy.Statement) {
+	for _, statement := range statements {
+		switch statement.Sid {
+		case allowedIPsStatementSID, deniedIPsStatementSID:
+			restrictions = append(restrictions, statement)
+		default:
+			canned = append(canned, statement)
+		}
+	}
+	return canned, restrictions
+}
+
+// getBucketIPRestrictions - returns the IP networks of the IP restriction
+// statements.
+func getBucketIPRestrictions(statements []miniogopolicy.Statement) (restrictions bucketIPRestrictions) {
+	for _, statement := range statements {
+		switch statement.Sid {
+		case allowedIPsStatementSID:
+			restrictions.AllowedIPs = statement.Conditions["NotIpAddress"]["aws:SourceIp"].ToSlice()
+		case deniedIPsStatementSID:
+			restrictions.DeniedIPs = statement.Conditions["IpAddress"]["aws:SourceIp"].ToSlice()
+		}
+	}
+	return restrictions
+}
+
+// setWebBucketPolicy - sets the canned policy of the prefix in the bucket
+// policy statements. IP restriction statements are replaced if
+// restrictions are given, they are kept otherwise and removed along with
+// the last canned policy.
+func setWebBucketPolicy(statements []miniogopolicy.Statement, policyType miniogopolicy.BucketPolicy,
+	bucketName, prefix string, restrictions *bucketIPRestrictions) []miniogopolicy.Statement {
+	canned, restricted := splitIPRestrictionStatements(statements)
+	canned = miniogopolicy.SetPolicy(canned, policyType, bucketName, prefix)
+	if len(canned) == 0 {
+		return canned
+	}
+	if restrictions != nil {
+		restricted = newIPRestrictionStatements(bucketName, *restrictions)
+	}
+	return append(canned, restricted...)
+}
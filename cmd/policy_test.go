@@ -17,11 +17,13 @@
 package cmd
 
 import (
+	"encoding/json"
 	"reflect"
 	"testing"
 
 	miniogopolicy "github.com/minio/minio-go/v6/pkg/policy"
 	"github.com/minio/minio-go/v6/pkg/set"
+	"github.com/minio/minio/pkg/madmin"
 	"github.com/minio/minio/pkg/policy"
 	"github.com/minio/minio/pkg/policy/condition"
 )
@@ -281,6 +283,58 @@ func getReadOnlyStatement(bucketName, prefix string) []miniogopolicy.Statement {
 	}
 }
 
+func TestPolicySysSimulate(t *testing.T) {
+	policySys := NewPolicySys()
+	policySys.Set("mybucket", policy.Policy{
+		Version: policy.DefaultVersion,
+		Statements: []policy.Statement{
+			policy.NewStatement(
+				policy.Allow,
+				policy.NewPrincipal("*"),
+				policy.NewActionSet(policy.GetObjectAction),
+				policy.NewResourceSet(policy.NewResource("mybucket", "*")),
+				condition.NewFunctions(),
+			),
+			policy.NewStatement(
+				policy.Deny,
+				policy.NewPrincipal("*"),
+				policy.NewActionSet(policy.GetObjectAction),
+				policy.NewResourceSet(policy.NewResource("mybucket", "/private/*")),
+				condition.NewFunctions(),
+			),
+		},
+	})
+
+	testCases := []struct {
+		args           policy.Args
+		expectedResult bool
+		expectedEffect string
+	}{
+		{policy.Args{Action: policy.GetObjectAction, BucketName: "mybucket", ObjectName: "public/obj"}, true, "Allow"},
+		{policy.Args{Action: policy.GetObjectAction, BucketName: "mybucket", ObjectName: "private/obj"}, false, "Deny"},
+		{policy.Args{Action: policy.PutObjectAction, BucketName: "mybucket", ObjectName: "public/obj"}, false, ""},
+		{policy.Args{Action: policy.GetObjectAction, BucketName: "yourbucket", ObjectName: "obj"}, false, ""},
+	}
+
+	for i, testCase := range testCases {
+		result, err := policySys.Simulate(testCase.args)
+		if err != nil {
+			t.Fatalf("case %v: %v", i+1, err)
+		}
+		if result.Allowed != testCase.expectedResult || result.Source != madmin.PolicySourceBucket || result.Reason == "" {
+			t.Errorf("case %v: unexpected result %v", i+1, result)
+		}
+		var statement policy.Statement
+		if testCase.expectedEffect == "" {
+			if len(result.Statement) != 0 {
+				t.Errorf("case %v: expected no statement, got %s", i+1, result.Statement)
+			}
+		} else if err = json.Unmarshal(result.Statement, &statement); err != nil || string(statement.Effect) != testCase.expectedEffect {
+			t.Errorf("case %v: expected a statement with effect %s, got %s", i+1, testCase.expectedEffect, result.Statement)
+		}
+	}
+}
+
 func TestPolicyToBucketAccessPolicy(t *testing.T) {
 	case1Policy := &policy.Policy{
 		Version: policy.DefaultVersion,
@@ -422,3 +476,30 @@ func TestBucketAccessPolicyToPolicy(t *testing.T) {
 		}
 	}
 }
+
+func TestParseIPNetworks(t *testing.T) {
+	testCases := []struct {
+		values         []string
+		expectedResult []string
+		expectErr      bool
+	}{
+		{nil, nil, false},
+		{[]string{"10.1.2.3", "192.168.1.10/24"}, []string{"10.1.2.3/32", "192.168.1.0/24"}, false},
+		{[]string{"2001:db8::1", "2001:db8::/32"}, []string{"2001:db8::1/128", "2001:db8::/32"}, false},
+		{[]string{"10.1.2.300"}, nil, true},
+		{[]string{"10.1.2.3/33"}, nil, true},
+	}
+
+	for i, testCase := range testCases {
+		result, err := parseIPNetworks(testCase.values)
+		expectErr := (err != nil)
+
+		if expectErr != testCase.expectErr {
+			t.Fatalf("case %v: error: expected: %v, got: %v\n", i+1, testCase.expectErr, expectErr)
+		}
+
+		if !testCase.expectErr && !reflect.DeepEqual(result, testCase.expectedResult) {
+			t.Fatalf("case %v: result: expected: %v, got: %v\n", i+1, testCase.expectedResult, result)
+		}
+	}
+}
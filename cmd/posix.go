@@ -68,8 +68,11 @@ func isValidVolname(volname string) bool {
 // posix - implements StorageAPI interface.
 type posix struct {
 	// Disk usage metrics
-	totalUsed  uint64 // ref: https://golang.org/pkg/sync/atomic/#pkg-note-BUG
-	ioErrCount int32  // ref: https://golang.org/pkg/sync/atomic/#pkg-note-BUG
+	totalUsed        uint64 // ref: https://golang.org/pkg/sync/atomic/#pkg-note-BUG
+	totalObjects     uint64 // ref: https://golang.org/pkg/sync/atomic/#pkg-note-BUG
+	totalObjectParts uint64 // ref: https://golang.org/pkg/sync/atomic/#pkg-note-BUG
+	totalObjectsSize uint64 // ref: https://golang.org/pkg/sync/atomic/#pkg-note-BUG
+	ioErrCount       int32  // ref: https://golang.org/pkg/sync/atomic/#pkg-note-BUG
 
 	diskPath  string
 	pool      sync.Pool
@@ -80,6 +83,12 @@ type posix struct {
 	diskFileInfo os.FileInfo
 	// Disk usage metrics
 	stopUsageCh chan struct{}
+	// Usage of each bucket as last counted by the usage crawler.
+	bucketsUsage   map[string]DiskBucketUsage
+	bucketsUsageMu sync.RWMutex
+
+	// Read and write latencies of the disk.
+	latency *driveLatency
 }
 
 // checkPathLength - returns error if given path name length more than 255
@@ -178,6 +187,8 @@ func isDirEmpty(dirname string) bool {
 
 // Initialize a new storage disk.
 func newPosix(path string) (*posix, error) {
+	latency := globalDriveLatency.get(path)
+
 	var err error
 	if path, err = getValidPath(path); err != nil {
 		return nil, err
@@ -198,6 +209,7 @@ func newPosix(path string) (*posix, error) {
 		stopUsageCh:  make(chan struct{}),
 		diskFileInfo: fi,
 		diskMount:    mountinfo.IsLikelyMountPoint(path),
+		latency:      latency,
 	}
 
 	if !p.diskMount {
@@ -313,6 +325,26 @@ type DiskInfo struct {
 	Free     uint64
 	Used     uint64
 	RootDisk bool
+
+	// Objects is the number of objects, ObjectParts the number of
+	// their erasure coded parts and ObjectsSize the total size of
+	// those parts, bitrot checksums included, stored on this disk
+	// as last counted by the usage crawler.
+	Objects     uint64
+	ObjectParts uint64
+	ObjectsSize uint64
+
+	// Usage of each bucket on this disk, as last counted by the
+	// usage crawler.
+	Buckets map[string]DiskBucketUsage
+}
+
+// DiskBucketUsage - usage of a bucket on a disk, counted the same
+// way as the object statistics of DiskInfo.
+type DiskBucketUsage struct {
+	Objects     uint64
+	ObjectParts uint64
+	ObjectsSize uint64
 }
 
 // DiskInfo provides current information about disk space usage,
@@ -348,9 +380,34 @@ func (s *posix) DiskInfo() (info DiskInfo, err error) {
 		Free:     di.Free,
 		Used:     used,
 		RootDisk: rootDisk,
+
+		Objects:     atomic.LoadUint64(&s.totalObjects),
+		ObjectParts: atomic.LoadUint64(&s.totalObjectParts),
+		ObjectsSize: atomic.LoadUint64(&s.totalObjectsSize),
+		Buckets:     s.getBucketsUsage(),
 	}, nil
 }
 
+// getBucketsUsage - returns a copy of the usage of each bucket as last
+// counted by the usage crawler.
+func (s *posix) getBucketsUsage() map[string]DiskBucketUsage {
+	s.bucketsUsageMu.RLock()
+	defer s.bucketsUsageMu.RUnlock()
+	buckets := make(map[string]DiskBucketUsage, len(s.bucketsUsage))
+	for bucket, usage := range s.bucketsUsage {
+		buckets[bucket] = usage
+	}
+	return buckets
+}
+
+// setBucketsUsage - replaces the usage of each bucket by the usage of
+// a completed crawl.
+func (s *posix) setBucketsUsage(buckets map[string]DiskBucketUsage) {
+	s.bucketsUsageMu.Lock()
+	defer s.bucketsUsageMu.Unlock()
+	s.bucketsUsage = buckets
+}
+
 // getVolDir - will convert incoming volume names to
 // corresponding valid volume names on the backend in a platform
 // compatible way for all operating systems. If volume is not found
@@ -394,6 +451,8 @@ func (s *posix) diskUsage(doneCh chan struct{}) {
 	ticker := time.NewTicker(globalUsageCheckInterval)
 	defer ticker.Stop()
 
+	index := globalSearchIndex.newIndex()
+	buckets := make(map[string]DiskBucketUsage)
 	usageFn := func(ctx context.Context, entry string) error {
 		if globalHTTPServer != nil {
 			// Wait at max 1 minute for an inprogress request
@@ -418,6 +477,14 @@ func (s *posix) diskUsage(doneCh chan struct{}) {
 				return err
 			}
 			atomic.AddUint64(&s.totalUsed, uint64(fi.Size()))
+			objects, parts, size := s.objectUsage(entry, fi)
+			atomic.AddUint64(&s.totalObjects, objects)
+			atomic.AddUint64(&s.totalObjectParts, parts)
+			atomic.AddUint64(&s.totalObjectsSize, size)
+			s.addBucketUsage(buckets, entry, objects, parts, size)
+			if objects > 0 {
+				s.indexObjectEntry(index, entry)
+			}
 			return nil
 		}
 	}
@@ -426,6 +493,9 @@ func (s *posix) diskUsage(doneCh chan struct{}) {
 	// so that we can start the routine freshly in another 12 hours.
 	if err := getDiskUsage(context.Background(), s.diskPath, usageFn); err == errWalkAbort {
 		return
+	} else if err == nil {
+		s.setBucketsUsage(buckets)
+		globalSearchIndex.update(s.diskPath, index)
 	}
 
 	for {
@@ -435,7 +505,9 @@ func (s *posix) diskUsage(doneCh chan struct{}) {
 		case <-doneCh:
 			return
 		case <-time.After(globalUsageCheckInterval):
-			var usage uint64
+			var usage, objects, objectParts, objectsSize uint64
+			index := globalSearchIndex.newIndex()
+			buckets := make(map[string]DiskBucketUsage)
 			usageFn = func(ctx context.Context, entry string) error {
 				if globalHTTPServer != nil {
 					// Wait at max 1 minute for an inprogress request
@@ -458,6 +530,14 @@ func (s *posix) diskUsage(doneCh chan struct{}) {
 						return err
 					}
 					usage = usage + uint64(fi.Size())
+					entryObjects, entryParts, entrySize := s.objectUsage(entry, fi)
+					objects = objects + entryObjects
+					objectParts = objectParts + entryParts
+					objectsSize = objectsSize + entrySize
+					s.addBucketUsage(buckets, entry, entryObjects, entryParts, entrySize)
+					if entryObjects > 0 {
+						s.indexObjectEntry(index, entry)
+					}
 					return nil
 				}
 			}
@@ -467,10 +547,61 @@ func (s *posix) diskUsage(doneCh chan struct{}) {
 			}
 
 			atomic.StoreUint64(&s.totalUsed, usage)
+			atomic.StoreUint64(&s.totalObjects, objects)
+			atomic.StoreUint64(&s.totalObjectParts, objectParts)
+			atomic.StoreUint64(&s.totalObjectsSize, objectsSize)
+			s.setBucketsUsage(buckets)
+			globalSearchIndex.update(s.diskPath, index)
 		}
 	}
 }
 
+// objectUsage returns the usage contributed by entry to the object
+// statistics of the disk. Every `xl.json` in a user bucket counts as
+// one object and every erasure coded part counts as one part and adds
+// its size, entries are only classified by name so that the crawler
+// never has to read them.
+func (s *posix) objectUsage(entry string, fi os.FileInfo) (objects, parts, size uint64) {
+	if fi.IsDir() || hasPrefix(entry, pathJoin(s.diskPath, minioMetaBucket)+SlashSeparator) {
+		return 0, 0, 0
+	}
+	name := slashpath.Base(entry)
+	switch {
+	case name == xlMetaJSONFile:
+		return 1, 0, 0
+	case hasPrefix(name, "part."):
+		return 0, 1, uint64(fi.Size())
+	}
+	return 0, 0, 0
+}
+
+// addBucketUsage adds the usage contributed by entry to the usage of
+// its bucket in buckets.
+func (s *posix) addBucketUsage(buckets map[string]DiskBucketUsage, entry string, objects, parts, size uint64) {
+	if objects == 0 && parts == 0 {
+		return
+	}
+	name := strings.TrimPrefix(entry, s.diskPath+SlashSeparator)
+	i := strings.Index(name, SlashSeparator)
+	if i <= 0 {
+		return
+	}
+	usage := buckets[name[:i]]
+	usage.Objects += objects
+	usage.ObjectParts += parts
+	usage.ObjectsSize += size
+	buckets[name[:i]] = usage
+}
+
+// indexObjectEntry adds the object whose `xl.json` is entry to the
+// search index.
+func (s *posix) indexObjectEntry(index *objectNameIndex, entry string) {
+	name := strings.TrimPrefix(slashpath.Dir(entry), s.diskPath+SlashSeparator)
+	if i := strings.Index(name, SlashSeparator); i > 0 {
+		index.add(name[:i], name[i+1:])
+	}
+}
+
 // Make a volume entry.
 func (s *posix) MakeVol(volume string) (err error) {
 	defer func() {
@@ -839,7 +970,9 @@ func (s *posix) ReadAll(volume, path string) (buf []byte, err error) {
 	}
 
 	// Open the file for reading.
+	start := time.Now()
 	buf, err = ioutil.ReadFile((filePath))
+	s.latency.readSince(start)
 	if err != nil {
 		if os.IsNotExist(err) {
 			return nil, errFileNotFound
@@ -920,6 +1053,7 @@ func (s *posix) ReadFile(volume, path string, offset int64, buffer []byte, verif
 	}
 
 	// Open the file for reading.
+	defer s.latency.readSince(time.Now())
 	file, err := os.Open((filePath))
 	if err != nil {
 		switch {
@@ -1132,7 +1266,7 @@ func (s *posix) ReadFileStream(volume, path string, offset, length int64) (io.Re
 	r := struct {
 		io.Reader
 		io.Closer
-	}{Reader: io.LimitReader(file, length), Closer: file}
+	}{Reader: io.LimitReader(latencyReader{file, &s.latency.read}, length), Closer: file}
 
 	return readahead.NewReadCloser(r), nil
 }
@@ -1230,7 +1364,11 @@ func (s *posix) CreateFile(volume, path string, fileSize int64, r io.Reader) (er
 	bufp := s.pool.Get().(*[]byte)
 	defer s.pool.Put(bufp)
 
-	written, err := xioutil.CopyAligned(w, r, *bufp)
+	// Only the time spent writing counts towards the write latency.
+	src := &waitReader{Reader: r}
+	start := time.Now()
+	written, err := xioutil.CopyAligned(w, src, *bufp)
+	s.latency.write.Add(time.Since(start) - src.wait)
 	if err != nil {
 		return err
 	}
@@ -1267,7 +1405,10 @@ func (s *posix) WriteAll(volume, path string, reader io.Reader) (err error) {
 	bufp := s.pool.Get().(*[]byte)
 	defer s.pool.Put(bufp)
 
-	_, err = io.CopyBuffer(w, reader, *bufp)
+	src := &waitReader{Reader: reader}
+	start := time.Now()
+	_, err = io.CopyBuffer(w, src, *bufp)
+	s.latency.write.Add(time.Since(start) - src.wait)
 	return err
 }
 
@@ -1292,7 +1433,10 @@ func (s *posix) AppendFile(volume, path string, buf []byte) (err error) {
 		return err
 	}
 
-	if _, err = w.Write(buf); err != nil {
+	start := time.Now()
+	_, err = w.Write(buf)
+	s.latency.write.Add(time.Since(start))
+	if err != nil {
 		return err
 	}
 
@@ -0,0 +1,239 @@
+/*
+ * MinIO Cloud Storage, (C) 2020 MinIO, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path"
+	"path/filepath"
+	"runtime"
+	"runtime/pprof"
+	"strconv"
+	"time"
+
+	"github.com/minio/minio/cmd/logger"
+	"github.com/minio/minio/pkg/cpu"
+)
+
+// Profiles taken by the watchdog are saved under
+// .minio.sys/profiles/<node>/<time>.zip
+const profilingWatchdogPrefix = "profiles"
+
+// Interval at which the load of the server is checked.
+const profilingWatchdogInterval = 30 * time.Second
+
+// profilingWatchdog - profiles the server when its load crosses the
+// configured thresholds, a zero threshold is not checked.
+type profilingWatchdog struct {
+	// CPU usage in percent of a single CPU.
+	cpuThreshold       float64
+	goroutineThreshold int
+	profiler           string
+	duration           time.Duration
+	// Minimum time between two profiles.
+	cooldown time.Duration
+
+	lastProfiled time.Time
+}
+
+func newProfilingWatchdog() *profilingWatchdog {
+	return &profilingWatchdog{
+		profiler: "cpu",
+		duration: time.Minute,
+		cooldown: time.Hour,
+	}
+}
+
+// profilingEvent - describes why the watchdog profiled the server, it
+// is logged and saved in the profile archive.
+type profilingEvent struct {
+	Node               string    `json:"node"`
+	Time               time.Time `json:"time"`
+	Reason             string    `json:"reason"`
+	CPU                float64   `json:"cpu"`
+	CPUThreshold       float64   `json:"cpuThreshold,omitempty"`
+	Goroutines         int       `json:"goroutines"`
+	GoroutineThreshold int       `json:"goroutineThreshold,omitempty"`
+	Profiler           string    `json:"profiler"`
+	Duration           string    `json:"duration"`
+}
+
+// check - returns the reason to profile the server with the given
+// load, if any.
+func (w *profilingWatchdog) check(now time.Time, cpuLoad float64, goroutines int) (reason string, ok bool) {
+	if !w.lastProfiled.IsZero() && now.Sub(w.lastProfiled) < w.cooldown {
+		return "", false
+	}
+	switch {
+	case w.cpuThreshold > 0 && cpuLoad >= w.cpuThreshold:
+		reason = fmt.Sprintf("CPU usage %.2f%% crossed threshold %.2f%%", cpuLoad, w.cpuThreshold)
+	case w.goroutineThreshold > 0 && goroutines >= w.goroutineThreshold:
+		reason = fmt.Sprintf("%d goroutines crossed threshold %d", goroutines, w.goroutineThreshold)
+	default:
+		return "", false
+	}
+	w.lastProfiled = now
+	return reason, true
+}
+
+// profile - profiles the server for the configured duration and
+// returns the profile archive, the archive also holds the event and a
+// dump of all goroutines at the time of the event. Profiling started
+// through the admin API takes precedence, the watchdog does not
+// profile meanwhile and stops early if profiling is started.
+func (w *profilingWatchdog) profile(event profilingEvent, doneCh <-chan struct{}) ([]byte, error) {
+	if globalProfiler != nil && !globalProfiler.Stopped() {
+		return nil, fmt.Errorf("profiler already running")
+	}
+
+	var goroutines bytes.Buffer
+	if err := pprof.Lookup("goroutine").WriteTo(&goroutines, 2); err != nil {
+		return nil, err
+	}
+
+	dirPath, err := ioutil.TempDir("", "profile")
+	if err != nil {
+		return nil, err
+	}
+	defer os.RemoveAll(dirPath)
+
+	prof, err := startProfiler(w.profiler, dirPath)
+	if err != nil {
+		return nil, err
+	}
+	globalProfiler = prof
+
+	timer := time.NewTimer(w.duration)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+	case <-doneCh:
+	}
+	prof.Stop()
+	if globalProfiler == prof {
+		globalProfiler = nil
+	}
+
+	data, err := ioutil.ReadFile(prof.Path())
+	if err != nil {
+		return nil, err
+	}
+	eventData, err := json.MarshalIndent(event, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+
+	var buf bytes.Buffer
+	zipWriter := zip.NewWriter(&buf)
+	files := []struct {
+		name string
+		data []byte
+	}{
+		{"event.json", eventData},
+		{"goroutines.txt", goroutines.Bytes()},
+		{filepath.Base(prof.Path()), data},
+	}
+	for _, file := range files {
+		zwriter, err := zipWriter.Create(file.name)
+		if err != nil {
+			return nil, err
+		}
+		if _, err = zwriter.Write(file.data); err != nil {
+			return nil, err
+		}
+	}
+	if err = zipWriter.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// getProfilePath - returns the path of a profile archive taken by the
+// watchdog, the archives of a node sort by time.
+func getProfilePath(node string, t time.Time) string {
+	return path.Join(profilingWatchdogPrefix, node, t.UTC().Format("20060102T150405Z")+".zip")
+}
+
+// startProfilingWatchdog - checks the load of the server until doneCh
+// is closed and saves a profile of the server each time it crosses the
+// thresholds of globalProfilingWatchdog.
+func startProfilingWatchdog(objAPI ObjectLayer, doneCh <-chan struct{}) {
+	w := globalProfilingWatchdog
+	if w == nil {
+		return
+	}
+
+	ticker := time.NewTicker(profilingWatchdogInterval)
+	defer ticker.Stop()
+
+	node := GetLocalPeer(globalEndpoints)
+	for {
+		select {
+		case <-doneCh:
+			return
+		case <-ticker.C:
+		}
+
+		var cpuLoad float64
+		if w.cpuThreshold > 0 {
+			cpuLoad = cpu.GetLoad().Avg
+		}
+		goroutines := runtime.NumGoroutine()
+		now := UTCNow()
+		reason, ok := w.check(now, cpuLoad, goroutines)
+		if !ok {
+			continue
+		}
+
+		event := profilingEvent{
+			Node:               node,
+			Time:               now,
+			Reason:             reason,
+			CPU:                cpuLoad,
+			CPUThreshold:       w.cpuThreshold,
+			Goroutines:         goroutines,
+			GoroutineThreshold: w.goroutineThreshold,
+			Profiler:           w.profiler,
+			Duration:           w.duration.String(),
+		}
+		profilePath := getProfilePath(node, now)
+		reqInfo := (&logger.ReqInfo{API: "ProfilingWatchdog"}).
+			AppendTags("reason", reason).
+			AppendTags("cpu", strconv.FormatFloat(cpuLoad, 'f', 2, 64)).
+			AppendTags("goroutines", strconv.Itoa(goroutines)).
+			AppendTags("profile", path.Join(minioMetaBucket, profilePath))
+		ctx := logger.SetReqInfo(context.Background(), reqInfo)
+		// Logged before profiling, for servers which do not survive
+		// the load.
+		logger.LogIf(ctx, fmt.Errorf("Profiling the server under high load: %s", reason))
+
+		data, err := w.profile(event, doneCh)
+		if err != nil {
+			logger.LogIf(ctx, fmt.Errorf("Unable to profile the server under high load: %v", err))
+			continue
+		}
+		if err = saveConfig(ctx, objAPI, profilePath, data); err != nil {
+			logger.LogIf(ctx, fmt.Errorf("Unable to save the profile of the server under high load: %v", err))
+		}
+	}
+}
@@ -0,0 +1,89 @@
+/*
+ * MinIO Cloud Storage, (C) 2020 MinIO, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"archive/zip"
+	"bytes"
+	"testing"
+	"time"
+)
+
+func TestProfilingWatchdogCheck(t *testing.T) {
+	w := newProfilingWatchdog()
+	w.cpuThreshold = 200
+	w.goroutineThreshold = 1000
+
+	now := UTCNow()
+	testCases := []struct {
+		now        time.Time
+		cpuLoad    float64
+		goroutines int
+		expected   bool
+	}{
+		{now, 150, 500, false},
+		{now, 250, 500, true},
+		// Within the cooldown of the previous profile.
+		{now.Add(time.Minute), 250, 5000, false},
+		{now.Add(w.cooldown), 150, 5000, true},
+		{now.Add(2 * w.cooldown), 150, 500, false},
+	}
+	for i, testCase := range testCases {
+		if _, ok := w.check(testCase.now, testCase.cpuLoad, testCase.goroutines); ok != testCase.expected {
+			t.Errorf("Test %d: expected %v, got %v", i+1, testCase.expected, ok)
+		}
+	}
+
+	// Thresholds which are not set are not checked.
+	w = newProfilingWatchdog()
+	w.goroutineThreshold = 1000
+	if _, ok := w.check(now, 10000, 500); ok {
+		t.Error("Expected the CPU usage not to be checked")
+	}
+}
+
+func TestProfilingWatchdogProfile(t *testing.T) {
+	w := newProfilingWatchdog()
+	w.profiler = "mem"
+	w.duration = 10 * time.Millisecond
+
+	data, err := w.profile(profilingEvent{Reason: "test"}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if globalProfiler != nil {
+		t.Fatal("Expected the profiler to be cleared")
+	}
+
+	zr, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var names []string
+	for _, f := range zr.File {
+		names = append(names, f.Name)
+	}
+	expected := []string{"event.json", "goroutines.txt", "mem.pprof"}
+	if len(names) != len(expected) {
+		t.Fatalf("Expected files %v, got %v", expected, names)
+	}
+	for i := range expected {
+		if names[i] != expected[i] {
+			t.Fatalf("Expected files %v, got %v", expected, names)
+		}
+	}
+}
@@ -0,0 +1,72 @@
+/*
+ * MinIO Cloud Storage, (C) 2020 MinIO, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"fmt"
+	"time"
+)
+
+// requestLimits - the parsed request deadlines and slow request
+// threshold of the API configuration, zero durations are disabled.
+type requestLimits struct {
+	deadline      time.Duration
+	apiDeadlines  map[string]time.Duration
+	slowThreshold time.Duration
+}
+
+// parseConfigDuration - parses a non negative duration of the API
+// configuration, empty is zero.
+func parseConfigDuration(s string) (time.Duration, error) {
+	if s == "" {
+		return 0, nil
+	}
+	d, err := time.ParseDuration(s)
+	if err != nil {
+		return 0, err
+	}
+	if d < 0 {
+		return 0, fmt.Errorf("negative duration %s", s)
+	}
+	return d, nil
+}
+
+// parse - returns the request limits of the API configuration.
+func (c apiConfig) parse() (limits requestLimits, err error) {
+	if limits.deadline, err = parseConfigDuration(c.Deadline); err != nil {
+		return limits, fmt.Errorf("deadline: %s", err)
+	}
+	if len(c.APIDeadlines) > 0 {
+		limits.apiDeadlines = make(map[string]time.Duration, len(c.APIDeadlines))
+		for api, deadline := range c.APIDeadlines {
+			if limits.apiDeadlines[api], err = parseConfigDuration(deadline); err != nil {
+				return limits, fmt.Errorf("%s deadline: %s", api, err)
+			}
+		}
+	}
+	if limits.slowThreshold, err = parseConfigDuration(c.SlowRequestThreshold); err != nil {
+		return limits, fmt.Errorf("slowRequestThreshold: %s", err)
+	}
+	return limits, nil
+}
+
+// apiDeadline - returns the deadline of the requests of api, ok is
+// false if api has no deadline of its own.
+func (l requestLimits) apiDeadline(api string) (deadline time.Duration, ok bool) {
+	deadline, ok = l.apiDeadlines[api]
+	return deadline, ok
+}
@@ -0,0 +1,258 @@
+/*
+ * MinIO Cloud Storage, (C) 2019 MinIO, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/minio/minio/cmd/logger"
+	"github.com/minio/minio/pkg/handlers"
+	"github.com/minio/minio/pkg/madmin"
+)
+
+// requestRegistryKeyType - unexported type for the active request context key.
+type requestRegistryKeyType string
+
+const requestRegistryKey = requestRegistryKeyType("active-request")
+
+// activeRequest holds information about an in-flight S3 API call.
+type activeRequest struct {
+	sync.RWMutex
+
+	id         string
+	requestID  string
+	api        string
+	bucket     string
+	object     string
+	remoteHost string
+	accessKey  string
+	startTime  time.Time
+	cancel     context.CancelFunc
+
+	// Limits applying to the request, set when it is added.
+	limits requestLimits
+	// Cancels the request once its deadline has passed.
+	deadlineTimer *time.Timer
+	expired       bool
+}
+
+// setAPI - records API, bucket and object names once the request
+// has been routed to its handler, the deadline of the API replaces
+// the deadline of all requests.
+func (a *activeRequest) setAPI(api, bucket, object string) {
+	a.Lock()
+	defer a.Unlock()
+	a.api = api
+	a.bucket = bucket
+	a.object = object
+	if deadline, ok := a.limits.apiDeadline(api); ok {
+		a.setDeadline(deadline)
+	}
+}
+
+// setDeadline - cancels the request once deadline has passed since it
+// started, zero means no deadline. Must be called with a locked.
+func (a *activeRequest) setDeadline(deadline time.Duration) {
+	if a.deadlineTimer != nil {
+		a.deadlineTimer.Stop()
+		a.deadlineTimer = nil
+	}
+	if deadline <= 0 {
+		return
+	}
+	remaining := deadline - UTCNow().Sub(a.startTime)
+	if remaining < 0 {
+		remaining = 0
+	}
+	a.deadlineTimer = time.AfterFunc(remaining, a.expire)
+}
+
+// expire - cancels the request as its deadline has passed.
+func (a *activeRequest) expire() {
+	a.Lock()
+	a.expired = true
+	a.Unlock()
+	a.cancel()
+}
+
+// finish - stops the deadline timer of the served request, logs it
+// if it was slow or canceled after its deadline.
+func (a *activeRequest) finish() {
+	a.Lock()
+	defer a.Unlock()
+
+	if a.deadlineTimer != nil {
+		a.deadlineTimer.Stop()
+	}
+	duration := UTCNow().Sub(a.startTime)
+	switch {
+	case a.expired:
+		logger.Info("Request %s %s %s/%s from %s canceled after its deadline, took %s",
+			a.requestID, a.api, a.bucket, a.object, a.remoteHost, duration)
+	case a.limits.slowThreshold > 0 && duration >= a.limits.slowThreshold:
+		logger.Info("Slow request %s %s %s/%s from %s took %s",
+			a.requestID, a.api, a.bucket, a.object, a.remoteHost, duration)
+	}
+}
+
+// setAccessKey - records the access key the request was
+// authenticated with.
+func (a *activeRequest) setAccessKey(accessKey string) {
+	a.Lock()
+	defer a.Unlock()
+	a.accessKey = accessKey
+}
+
+func (a *activeRequest) getAccessKey() string {
+	a.RLock()
+	defer a.RUnlock()
+	return a.accessKey
+}
+
+func (a *activeRequest) toEntry(node string, now time.Time) madmin.RequestEntry {
+	a.RLock()
+	defer a.RUnlock()
+	return madmin.RequestEntry{
+		ID:         a.id,
+		RequestID:  a.requestID,
+		API:        a.api,
+		Bucket:     a.bucket,
+		Object:     a.object,
+		RemoteHost: a.remoteHost,
+		Node:       node,
+		StartTime:  a.startTime,
+		Duration:   now.Sub(a.startTime),
+	}
+}
+
+// requestRegistry keeps track of all in-flight S3 API calls on this
+// server so that they can be listed and canceled by an operator.
+type requestRegistry struct {
+	sync.RWMutex
+	requests map[string]*activeRequest
+	limits   requestLimits
+}
+
+// setLimits - sets the deadlines and slow request threshold of the
+// requests added from now on.
+func (reg *requestRegistry) setLimits(limits requestLimits) {
+	reg.Lock()
+	defer reg.Unlock()
+	reg.limits = limits
+}
+
+func newRequestRegistry() *requestRegistry {
+	return &requestRegistry{
+		requests: make(map[string]*activeRequest),
+	}
+}
+
+// add - registers a new request under a unique id, requestID is the
+// x-amz-request-id of the request which is not unique by itself. The
+// returned request carries a cancelable context which is canceled by
+// Cancel(). The returned function must be called once the request
+// has been served.
+func (reg *requestRegistry) add(r *http.Request, requestID string) (*http.Request, func()) {
+	ctx, cancel := context.WithCancel(r.Context())
+	id := mustGetUUID()
+	req := &activeRequest{
+		id:         id,
+		requestID:  requestID,
+		remoteHost: handlers.GetSourceIP(r),
+		startTime:  UTCNow(),
+		cancel:     cancel,
+	}
+
+	reg.Lock()
+	reg.requests[id] = req
+	req.limits = reg.limits
+	reg.Unlock()
+
+	req.Lock()
+	req.setDeadline(req.limits.deadline)
+	req.Unlock()
+
+	r = r.WithContext(context.WithValue(ctx, requestRegistryKey, req))
+	if r.Body != nil {
+		r.Body = cancelableReadCloser{ReadCloser: r.Body, ctx: ctx}
+	}
+
+	return r, func() {
+		reg.Lock()
+		delete(reg.requests, id)
+		reg.Unlock()
+		req.finish()
+		cancel()
+	}
+}
+
+// List - returns all in-flight requests, the longest running first.
+func (reg *requestRegistry) List(node string) []madmin.RequestEntry {
+	now := UTCNow()
+
+	reg.RLock()
+	entries := make([]madmin.RequestEntry, 0, len(reg.requests))
+	for _, req := range reg.requests {
+		entries = append(entries, req.toEntry(node, now))
+	}
+	reg.RUnlock()
+
+	sort.Slice(entries, func(i, j int) bool {
+		return entries[i].StartTime.Before(entries[j].StartTime)
+	})
+	return entries
+}
+
+// Cancel - cancels the in-flight request with the given id, returns
+// false if no such request is being served by this server.
+func (reg *requestRegistry) Cancel(id string) bool {
+	reg.RLock()
+	req, ok := reg.requests[id]
+	reg.RUnlock()
+	if !ok {
+		return false
+	}
+	req.cancel()
+	return true
+}
+
+// getActiveRequest - returns the registry entry of the request
+// associated with ctx, if any.
+func getActiveRequest(ctx context.Context) *activeRequest {
+	req, _ := ctx.Value(requestRegistryKey).(*activeRequest)
+	return req
+}
+
+// cancelableReadCloser fails all reads once the request has been
+// canceled, this aborts uploads of canceled requests when wrapping
+// the request body and downloads when wrapping the object reader.
+type cancelableReadCloser struct {
+	io.ReadCloser
+	ctx context.Context
+}
+
+func (c cancelableReadCloser) Read(p []byte) (int, error) {
+	if err := c.ctx.Err(); err != nil {
+		return 0, err
+	}
+	return c.ReadCloser.Read(p)
+}
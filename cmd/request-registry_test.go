@@ -0,0 +1,126 @@
+/*
+ * MinIO Cloud Storage, (C) 2019 MinIO, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestRequestRegistry(t *testing.T) {
+	reg := newRequestRegistry()
+
+	// x-amz-request-id is not unique, requests are tracked by their own id.
+	r1 := httptest.NewRequest(http.MethodPut, "/bucket/object", bytes.NewReader([]byte("hello")))
+	r1, done1 := reg.add(r1, "reqid")
+	r2 := httptest.NewRequest(http.MethodGet, "/bucket", nil)
+	r2, done2 := reg.add(r2, "reqid")
+
+	getActiveRequest(r1.Context()).setAPI("PutObject", "bucket", "object")
+
+	entries := reg.List("node1")
+	if len(entries) != 2 {
+		t.Fatalf("Expected 2 requests, got %d", len(entries))
+	}
+	if entries[0].ID == entries[1].ID {
+		t.Fatalf("Expected unique request ids, got %s twice", entries[0].ID)
+	}
+	id1 := getActiveRequest(r1.Context()).id
+	if entries[0].ID != id1 || entries[0].RequestID != "reqid" || entries[0].API != "PutObject" || entries[0].Object != "object" {
+		t.Errorf("Unexpected first entry %#v", entries[0])
+	}
+	if entries[0].Node != "node1" {
+		t.Errorf("Expected node1, got %s", entries[0].Node)
+	}
+
+	if reg.Cancel("reqid") {
+		t.Errorf("Expected cancel by x-amz-request-id to fail")
+	}
+	if !reg.Cancel(id1) {
+		t.Fatalf("Expected cancel of %s to succeed", id1)
+	}
+	if r1.Context().Err() != context.Canceled {
+		t.Errorf("Expected request context to be canceled, got %v", r1.Context().Err())
+	}
+	if _, err := ioutil.ReadAll(r1.Body); err != context.Canceled {
+		t.Errorf("Expected body read to fail with %v, got %v", context.Canceled, err)
+	}
+	if r2.Context().Err() != nil {
+		t.Errorf("Expected second request to be still active, got %v", r2.Context().Err())
+	}
+
+	done1()
+	done2()
+	if entries = reg.List("node1"); len(entries) != 0 {
+		t.Errorf("Expected no requests, got %d", len(entries))
+	}
+}
+
+func TestRequestRegistryDeadline(t *testing.T) {
+	reg := newRequestRegistry()
+	limits, err := apiConfig{
+		Deadline:     "50ms",
+		APIDeadlines: map[string]string{"PutObject": "0s"},
+	}.parse()
+	if err != nil {
+		t.Fatal(err)
+	}
+	reg.setLimits(limits)
+
+	r1, done1 := reg.add(httptest.NewRequest(http.MethodGet, "/bucket/object", nil), "reqid")
+	defer done1()
+	r2, done2 := reg.add(httptest.NewRequest(http.MethodPut, "/bucket/object", nil), "reqid")
+	defer done2()
+
+	// Uploads have no deadline of their own.
+	getActiveRequest(r1.Context()).setAPI("GetObject", "bucket", "object")
+	getActiveRequest(r2.Context()).setAPI("PutObject", "bucket", "object")
+
+	select {
+	case <-r1.Context().Done():
+	case <-time.After(5 * time.Second):
+		t.Fatal("Expected the request to be canceled after its deadline")
+	}
+	if r2.Context().Err() != nil {
+		t.Errorf("Expected the upload to be still active, got %v", r2.Context().Err())
+	}
+}
+
+func TestAPIConfigParse(t *testing.T) {
+	testCases := []struct {
+		config     apiConfig
+		shouldPass bool
+	}{
+		{apiConfig{}, true},
+		{apiConfig{Deadline: "5m", APIDeadlines: map[string]string{"PutObject": "0s"}, SlowRequestThreshold: "10s"}, true},
+		{apiConfig{Deadline: "5"}, false},
+		{apiConfig{Deadline: "-1m"}, false},
+		{apiConfig{APIDeadlines: map[string]string{"GetObject": "1x"}}, false},
+		{apiConfig{SlowRequestThreshold: "slow"}, false},
+	}
+	for i, testCase := range testCases {
+		_, err := testCase.config.parse()
+		if (err == nil) != testCase.shouldPass {
+			t.Errorf("Test %d: expected to pass %v, got %v", i+1, testCase.shouldPass, err)
+		}
+	}
+}
@@ -28,6 +28,7 @@ import (
 	"time"
 
 	xhttp "github.com/minio/minio/cmd/http"
+	"golang.org/x/net/http2"
 )
 
 // DefaultRESTTimeout - default RPC timeout is one minute.
@@ -136,3 +137,42 @@ func NewClient(url *url.URL, tlsConfig *tls.Config, timeout time.Duration, newAu
 		newAuthToken:        newAuthToken,
 	}, nil
 }
+
+// NewHTTP2Client - returns new REST client which multiplexes all calls
+// to the server over a single HTTP/2 connection, instead of setting up
+// a connection for every concurrent call. The server must support
+// HTTP/2 over TLS.
+func NewHTTP2Client(url *url.URL, tlsConfig *tls.Config, timeout time.Duration, newAuthToken func() string) (*Client, error) {
+	if tlsConfig == nil {
+		return nil, errors.New("HTTP/2 REST client requires TLS")
+	}
+	dialContext := newCustomDialContext(timeout)
+	tr := &http2.Transport{
+		DialTLS: func(network, addr string, cfg *tls.Config) (net.Conn, error) {
+			conn, err := dialContext(context.Background(), network, addr)
+			if err != nil {
+				return nil, err
+			}
+			tlsConn := tls.Client(conn, cfg)
+			tlsConn.SetDeadline(time.Now().Add(timeout))
+			if err = tlsConn.Handshake(); err != nil {
+				conn.Close()
+				return nil, err
+			}
+			tlsConn.SetDeadline(time.Time{})
+			if p := tlsConn.ConnectionState().NegotiatedProtocol; p != http2.NextProtoTLS {
+				conn.Close()
+				return nil, errors.New("server does not support HTTP/2, negotiated protocol: " + p)
+			}
+			return tlsConn, nil
+		},
+		TLSClientConfig:    tlsConfig,
+		DisableCompression: true,
+	}
+	return &Client{
+		httpClient:          &http.Client{Transport: tr},
+		httpIdleConnsCloser: tr.CloseIdleConnections,
+		url:                 url,
+		newAuthToken:        newAuthToken,
+	}, nil
+}
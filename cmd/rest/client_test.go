@@ -0,0 +1,98 @@
+/*
+ * MinIO Cloud Storage, (C) 2019 MinIO, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package rest
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// Tests that concurrent calls of an HTTP/2 client share a single connection.
+func TestHTTP2ClientMultiplex(t *testing.T) {
+	server := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.ProtoMajor != 2 {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		w.Write([]byte(r.URL.Path))
+	}))
+	var conns int32
+	server.Config.ConnState = func(conn net.Conn, state http.ConnState) {
+		if state == http.StateNew {
+			atomic.AddInt32(&conns, 1)
+		}
+	}
+	server.EnableHTTP2 = true
+	server.StartTLS()
+	defer server.Close()
+
+	rootCAs := x509.NewCertPool()
+	rootCAs.AddCert(server.Certificate())
+	serverURL, err := url.Parse(server.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	client, err := NewHTTP2Client(serverURL, &tls.Config{RootCAs: rootCAs}, time.Minute, func() string { return "" })
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer client.Close()
+
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			respBody, err := client.Call("method", nil, nil, -1)
+			if err != nil {
+				t.Error(err)
+				return
+			}
+			defer respBody.Close()
+			b, err := ioutil.ReadAll(respBody)
+			if err != nil {
+				t.Error(err)
+				return
+			}
+			if string(b) != "/method" {
+				t.Errorf("Expected /method, got %s", b)
+			}
+		}()
+	}
+	wg.Wait()
+
+	if n := atomic.LoadInt32(&conns); n != 1 {
+		t.Fatalf("Expected a single connection, got %d", n)
+	}
+}
+
+// Tests that an HTTP/2 client is not created without TLS.
+func TestHTTP2ClientRequiresTLS(t *testing.T) {
+	serverURL := &url.URL{Scheme: "http", Host: "localhost:9000"}
+	if _, err := NewHTTP2Client(serverURL, nil, time.Minute, func() string { return "" }); err == nil {
+		t.Fatal("Expected an error without TLS config")
+	}
+}
@@ -48,14 +48,24 @@ func registerDistXLRouters(router *mux.Router, endpoints EndpointList) {
 
 // List of some generic handlers which are applied for all incoming requests.
 var globalHandlers = []HandlerFunc{
+	// Track in-flight S3 requests, runs after all the handlers
+	// below. Requests are tracked before their signature is
+	// verified by the API handler, so unauthenticated requests
+	// are listed as well.
+	setRequestRegistryHandler,
 	// set x-amz-request-id header.
 	addCustomHeaders,
 	// set HTTP security headers such as Content-Security-Policy.
 	addSecurityHeaders,
+	// Reject requests not allowed by the mode of their bucket, runs
+	// after requests for federated buckets are forwarded.
+	setBucketModeHandler,
 	// Forward path style requests to actual host in a bucket federated setup.
 	setBucketForwardingHandler,
 	// Validate all the incoming requests.
 	setRequestValidityHandler,
+	// Compress listing and web RPC responses if the client accepts it.
+	setResponseCompressionHandler,
 	// Network statistics
 	setHTTPStatsHandler,
 	// Limits all requests size to a maximum fixed limit
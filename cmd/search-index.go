@@ -0,0 +1,166 @@
+/*
+ * MinIO Cloud Storage, (C) 2019 MinIO, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"errors"
+	"sort"
+	"strings"
+	"sync"
+	"unicode"
+)
+
+// Maximum number of objects returned by a search.
+const maxSearchResults = 100
+
+var errSearchIndexDisabled = errors.New("Object name search index is not enabled")
+
+// searchTokens - splits s into lower case tokens of letters and digits,
+// e.g. "2019/Invoice-0042.pdf" into "2019", "invoice", "0042", "pdf".
+func searchTokens(s string) []string {
+	return strings.FieldsFunc(strings.ToLower(s), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+}
+
+// objectNameIndex - inverted index of the tokens of object names, keys
+// are of the form bucket/object and are referred to by their position.
+type objectNameIndex struct {
+	keys   []string
+	tokens map[string][]uint32
+}
+
+func newObjectNameIndex() *objectNameIndex {
+	return &objectNameIndex{tokens: make(map[string][]uint32)}
+}
+
+// add - indexes the object name of key, a nil index is a no-op so that
+// crawlers need not check whether indexing is enabled.
+func (idx *objectNameIndex) add(bucket, object string) {
+	if idx == nil {
+		return
+	}
+	id := uint32(len(idx.keys))
+	idx.keys = append(idx.keys, pathJoin(bucket, object))
+	for _, token := range searchTokens(object) {
+		ids := idx.tokens[token]
+		if len(ids) > 0 && ids[len(ids)-1] == id {
+			// Token repeated in the name.
+			continue
+		}
+		idx.tokens[token] = append(ids, id)
+	}
+}
+
+// search - returns up to max keys whose object names hold all tokens,
+// limited to bucket unless empty.
+func (idx *objectNameIndex) search(tokens []string, bucket string, max int) []string {
+	if len(tokens) == 0 {
+		return nil
+	}
+	lists := make([][]uint32, 0, len(tokens))
+	for _, token := range tokens {
+		ids, ok := idx.tokens[token]
+		if !ok {
+			return nil
+		}
+		lists = append(lists, ids)
+	}
+	// Walk the shortest list, ids are in ascending order.
+	sort.Slice(lists, func(i, j int) bool { return len(lists[i]) < len(lists[j]) })
+
+	var keys []string
+next:
+	for _, id := range lists[0] {
+		for _, ids := range lists[1:] {
+			i := sort.Search(len(ids), func(i int) bool { return ids[i] >= id })
+			if i == len(ids) || ids[i] != id {
+				continue next
+			}
+		}
+		key := idx.keys[id]
+		if bucket != "" && !hasPrefix(key, bucket+SlashSeparator) {
+			continue
+		}
+		keys = append(keys, key)
+		if len(keys) == max {
+			break
+		}
+	}
+	return keys
+}
+
+// searchIndexSys - holds an object name index per crawled disk, each
+// rebuilt by the usage crawler of the disk. Objects created since
+// the last crawl are not found, deleted objects are found until the
+// next crawl.
+type searchIndexSys struct {
+	mu      sync.RWMutex
+	indexes map[string]*objectNameIndex
+}
+
+var globalSearchIndex = &searchIndexSys{indexes: make(map[string]*objectNameIndex)}
+
+// newIndex - returns an empty index to be filled by a crawler, nil
+// if the search index is not enabled.
+func (sys *searchIndexSys) newIndex() *objectNameIndex {
+	if !globalSearchIndexEnabled {
+		return nil
+	}
+	return newObjectNameIndex()
+}
+
+// update - replaces the index of the disk at diskPath by a completely
+// crawled index.
+func (sys *searchIndexSys) update(diskPath string, idx *objectNameIndex) {
+	if idx == nil {
+		return
+	}
+	sys.mu.Lock()
+	defer sys.mu.Unlock()
+
+	sys.indexes[diskPath] = idx
+}
+
+// Search - returns up to max sorted bucket/object keys whose object
+// names hold all tokens of query, limited to bucket unless empty.
+func (sys *searchIndexSys) Search(query, bucket string, max int) ([]string, error) {
+	if !globalSearchIndexEnabled {
+		return nil, errSearchIndexDisabled
+	}
+	tokens := searchTokens(query)
+
+	sys.mu.RLock()
+	defer sys.mu.RUnlock()
+
+	// Objects are on multiple disks.
+	found := make(map[string]struct{})
+	for _, idx := range sys.indexes {
+		for _, key := range idx.search(tokens, bucket, max) {
+			found[key] = struct{}{}
+		}
+	}
+	keys := make([]string, 0, len(found))
+	for key := range found {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	if len(keys) > max {
+		keys = keys[:max]
+	}
+	return keys, nil
+}
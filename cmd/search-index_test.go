@@ -0,0 +1,93 @@
+/*
+ * MinIO Cloud Storage, (C) 2019 MinIO, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestSearchTokens(t *testing.T) {
+	tokens := searchTokens("2019/Invoice-0042.pdf")
+	if expected := []string{"2019", "invoice", "0042", "pdf"}; !reflect.DeepEqual(tokens, expected) {
+		t.Fatalf("Expected %v, got %v", expected, tokens)
+	}
+}
+
+func TestSearchIndex(t *testing.T) {
+	defer func() {
+		globalSearchIndexEnabled = false
+	}()
+
+	sys := &searchIndexSys{indexes: make(map[string]*objectNameIndex)}
+	if _, err := sys.Search("invoice", "", maxSearchResults); err != errSearchIndexDisabled {
+		t.Fatalf("Expected %v, got %v", errSearchIndexDisabled, err)
+	}
+	if sys.newIndex() != nil {
+		t.Fatal("Expected no index with the search index disabled")
+	}
+
+	globalSearchIndexEnabled = true
+	// The same object is indexed on two disks.
+	for _, disk := range []string{"disk1", "disk2"} {
+		index := sys.newIndex()
+		index.add("bucket", "2019/invoice-2019-invoice.pdf")
+		index.add("bucket", "2019/report.pdf")
+		index.add("photos", "invoice/scan.jpg")
+		index.add("photos", "invoice-2019.png")
+		sys.update(disk, index)
+	}
+
+	testCases := []struct {
+		query  string
+		bucket string
+		max    int
+		keys   []string
+	}{
+		{"invoice 2019", "", maxSearchResults, []string{"bucket/2019/invoice-2019-invoice.pdf", "photos/invoice-2019.png"}},
+		{"INVOICE-2019", "photos", maxSearchResults, []string{"photos/invoice-2019.png"}},
+		{"invoice", "", 1, []string{"bucket/2019/invoice-2019-invoice.pdf"}},
+		{"pdf", "", maxSearchResults, []string{"bucket/2019/invoice-2019-invoice.pdf", "bucket/2019/report.pdf"}},
+		// Bucket names are not indexed.
+		{"photos", "", maxSearchResults, []string{}},
+		{"invoice 2018", "", maxSearchResults, []string{}},
+		{"", "", maxSearchResults, []string{}},
+	}
+	for i, testCase := range testCases {
+		keys, err := sys.Search(testCase.query, testCase.bucket, testCase.max)
+		if err != nil {
+			t.Fatalf("Test %d: %v", i+1, err)
+		}
+		if !reflect.DeepEqual(keys, testCase.keys) {
+			t.Errorf("Test %d: expected %v, got %v", i+1, testCase.keys, keys)
+		}
+	}
+}
+
+// Tests that crawled entries are indexed by bucket and object name.
+func TestSearchIndexCrawledEntries(t *testing.T) {
+	index := newObjectNameIndex()
+	fs := &FSObjects{fsPath: "/export"}
+	fs.indexObjectEntry(index, "/export/bucket/2019/invoice.pdf")
+	s := &posix{diskPath: "/disk1"}
+	s.indexObjectEntry(index, "/disk1/bucket/2019/report.pdf/xl.json")
+
+	expected := []string{"bucket/2019/invoice.pdf", "bucket/2019/report.pdf"}
+	if !reflect.DeepEqual(index.keys, expected) {
+		t.Fatalf("Expected %v, got %v", expected, index.keys)
+	}
+}
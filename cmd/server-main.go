@@ -30,7 +30,6 @@ import (
 	"github.com/minio/dsync/v2"
 	xhttp "github.com/minio/minio/cmd/http"
 	"github.com/minio/minio/cmd/logger"
-	"github.com/minio/minio/pkg/certs"
 )
 
 func init() {
@@ -81,12 +80,22 @@ ENVIRONMENT VARIABLES:
   CACHE:
      MINIO_CACHE_DRIVES: List of mounted drives or directories delimited by ";".
      MINIO_CACHE_EXCLUDE: List of cache exclusion patterns delimited by ";".
+     MINIO_CACHE_AFFINITY: List of bucket=drive1,drive2 rules dedicating cache drives to buckets delimited by ";".
+     MINIO_CACHE_STORAGE_CLASS: List of class=exclude or class=priority cache admission policies of storage classes delimited by ";".
+     MINIO_CACHE_QUOTA: List of bucket=percent maximum usage of the cache space by buckets delimited by ";".
+     MINIO_CACHE_PARITY: Number of parity shards of objects striped across the cache drives.
+     MINIO_CACHE_ROUTING: List of condition1,condition2=drive1,drive2 rules routing objects to cache drives delimited by ";".
      MINIO_CACHE_EXPIRY: Cache expiry duration in days.
      MINIO_CACHE_MAXUSE: Maximum permitted usage of the cache in percentage (0-100).
 
   DOMAIN:
      MINIO_DOMAIN: To enable virtual-host-style requests, set this value to MinIO host domain name.
 
+  ACME:
+     MINIO_ACME_DOMAINS: List of domain names to obtain TLS certificates for through ACME delimited by ",".
+     MINIO_ACME_EMAIL: Contact email of the ACME account.
+     MINIO_ACME_DIRECTORY: Directory URL of the ACME CA, defaults to Let's Encrypt.
+
   WORM:
      MINIO_WORM: To turn on Write-Once-Read-Many in server, set this value to "on".
 
@@ -225,6 +234,12 @@ func serverMain(ctx *cli.Context) {
 	// Handle all server environment vars.
 	serverHandleEnvVars()
 
+	// Obtain TLS certificates through ACME if enabled.
+	acmeManager := newACMEManager()
+	if acmeManager != nil {
+		globalIsSSL = true
+	}
+
 	// Is distributed setup, error out if no certificates are found for HTTPS endpoints.
 	if globalIsDistXL {
 		if globalEndpoints.IsHTTPS() && !globalIsSSL {
@@ -296,12 +311,12 @@ func serverMain(ctx *cli.Context) {
 		logger.Fatal(uiErrUnexpectedError(err), "Unable to configure one of server's RPC services")
 	}
 
-	var getCert certs.GetCertificateFunc
-	if globalTLSCerts != nil {
-		getCert = globalTLSCerts.GetCertificate
-	}
+	getCert := getCertificateFunc(acmeManager, globalTLSCerts)
 
 	globalHTTPServer = xhttp.NewServer([]string{globalMinioAddr}, criticalErrorHandler{handler}, getCert)
+	if acmeManager != nil {
+		enableACMEChallenges(globalHTTPServer)
+	}
 	globalHTTPServer.UpdateBytesReadFunc = globalConnStats.incInputBytes
 	globalHTTPServer.UpdateBytesWrittenFunc = globalConnStats.incOutputBytes
 	go func() {
@@ -366,6 +381,63 @@ func serverMain(ctx *cli.Context) {
 		logger.Fatal(err, "Unable to initialize lifecycle system")
 	}
 
+	// Create new bucket logging system.
+	globalBucketLoggingSys = NewBucketLoggingSys()
+
+	// Initialize bucket logging system.
+	if err = globalBucketLoggingSys.Init(newObject); err != nil {
+		logger.Fatal(err, "Unable to initialize bucket logging system")
+	}
+	logger.AddAuditTarget(globalBucketLoggingSys)
+
+	// Create new bucket CORS system.
+	globalBucketCORSSys = NewBucketCORSSys()
+
+	// Initialize bucket CORS system.
+	if err = globalBucketCORSSys.Init(newObject); err != nil {
+		logger.Fatal(err, "Unable to initialize bucket CORS system")
+	}
+
+	// Create new bucket website system.
+	globalBucketWebsiteSys = NewBucketWebsiteSys()
+
+	// Initialize bucket website system.
+	if err = globalBucketWebsiteSys.Init(newObject); err != nil {
+		logger.Fatal(err, "Unable to initialize bucket website system")
+	}
+
+	// Create new bucket snapshot system.
+	globalBucketSnapshotSys = NewBucketSnapshotSys()
+
+	// Initialize bucket snapshot system.
+	if err = globalBucketSnapshotSys.Init(newObject); err != nil {
+		logger.Fatal(err, "Unable to initialize bucket snapshot system")
+	}
+
+	// Create new bandwidth system.
+	globalBandwidthSys = NewBandwidthSys()
+
+	// Initialize bandwidth system.
+	if err = globalBandwidthSys.Load(newObject); err != nil {
+		logger.Fatal(err, "Unable to initialize bandwidth system")
+	}
+
+	// Create new bucket mode system.
+	globalBucketModeSys = NewBucketModeSys()
+
+	// Initialize bucket mode system.
+	if err = globalBucketModeSys.Load(newObject); err != nil {
+		logger.Fatal(err, "Unable to initialize bucket mode system")
+	}
+
+	// Create new bucket key normalization system.
+	globalBucketKeyNormalizationSys = NewBucketKeyNormalizationSys()
+
+	// Initialize bucket key normalization system.
+	if err = globalBucketKeyNormalizationSys.Load(newObject); err != nil {
+		logger.Fatal(err, "Unable to initialize bucket key normalization system")
+	}
+
 	// Create new notification system.
 	globalNotificationSys = NewNotificationSys(globalServerConfig, globalEndpoints)
 
@@ -373,16 +445,34 @@ func serverMain(ctx *cli.Context) {
 	if err = globalNotificationSys.Init(newObject); err != nil {
 		logger.Fatal(err, "Unable to initialize notification system")
 	}
+	go globalNotificationSys.startTargetHealthCheck(GlobalServiceDoneCh)
+	if globalIsDistXL {
+		go globalNotificationSys.startPeerHeartbeat(GlobalServiceDoneCh)
+	}
+
+	// Publish the heads of the audit log chains, if any.
+	go startAuditAnchorPublisher(newObject, GlobalServiceDoneCh)
+
+	// Profile the server under high load, if enabled.
+	go startProfilingWatchdog(newObject, GlobalServiceDoneCh)
+
+	// Reload bucket configuration changes published to etcd by other servers.
+	if globalEtcdClient != nil {
+		go watchConfigEvents(newObject)
+	}
 
 	// Verify if object layer supports
 	// - encryption
 	// - compression
 	verifyObjectLayerFeatures("server", newObject)
 
-	initDailyLifecycle()
+	// Apply bucket lifecycle rules, purge the trash of the buckets
+	// and remove expired anonymous uploads periodically.
+	initBackgroundJobs(newObject)
 
 	if globalIsXL {
 		initBackgroundHealing()
+		initLocalDisksAutoHeal()
 		initDailyHeal()
 		initDailySweeper()
 	}
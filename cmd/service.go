@@ -17,6 +17,7 @@
 package cmd
 
 import (
+	"context"
 	"os"
 	"os/exec"
 	"syscall"
@@ -38,9 +39,18 @@ var globalServiceSignalCh chan serviceSignal
 // GlobalServiceDoneCh - Global service done channel.
 var GlobalServiceDoneCh chan struct{}
 
+// GlobalContext - context canceled when the service is stopped or
+// restarted, long running background routines should derive their
+// context from it so that they quit promptly.
+var GlobalContext context.Context
+
+// cancelGlobalContext - cancels GlobalContext.
+var cancelGlobalContext context.CancelFunc
+
 // Initialize service mutex once.
 func init() {
 	GlobalServiceDoneCh = make(chan struct{})
+	GlobalContext, cancelGlobalContext = context.WithCancel(context.Background())
 	globalServiceSignalCh = make(chan serviceSignal)
 }
 
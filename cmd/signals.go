@@ -49,12 +49,14 @@ func handleSignals() {
 		// Stop watching for any certificate changes.
 		globalTLSCerts.Stop()
 
+		// send signal to various go-routines that they need to quit,
+		// before waiting for the in-flight requests to be drained.
+		cancelGlobalContext()
+		close(GlobalServiceDoneCh)
+
 		err = globalHTTPServer.Shutdown()
 		logger.LogIf(context.Background(), err)
 
-		// send signal to various go-routines that they need to quit.
-		close(GlobalServiceDoneCh)
-
 		if objAPI := newObjectLayerFn(); objAPI != nil {
 			oerr = objAPI.Shutdown(context.Background())
 			logger.LogIf(context.Background(), oerr)
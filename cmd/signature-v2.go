@@ -149,7 +149,7 @@ func doesPresignV2SignatureMatch(r *http.Request) APIErrorCode {
 		return ErrInvalidQueryParams
 	}
 
-	cred, _, s3Err := checkKeyValid(accessKey)
+	cred, owner, s3Err := checkPresignKeyValid(accessKey)
 	if s3Err != ErrNone {
 		return s3Err
 	}
@@ -172,7 +172,19 @@ func doesPresignV2SignatureMatch(r *http.Request) APIErrorCode {
 
 	expectedSignature := preSignatureV2(cred, r.Method, encodedResource, strings.Join(filteredQueries, "&"), r.Header, expires)
 	if !compareSignatureV2(gotSignature, expectedSignature) {
-		return ErrSignatureDoesNotMatch
+		// The URL may have been presigned before the root secret key
+		// changed, this never applies to IAM users.
+		if !owner {
+			return ErrSignatureDoesNotMatch
+		}
+		prevCred, ok := globalPrevCredential.get(cred.AccessKey)
+		if !ok || prevCred.SecretKey == cred.SecretKey {
+			return ErrSignatureDoesNotMatch
+		}
+		expectedSignature = preSignatureV2(prevCred, r.Method, encodedResource, strings.Join(filteredQueries, "&"), r.Header, expires)
+		if !compareSignatureV2(gotSignature, expectedSignature) {
+			return ErrSignatureDoesNotMatch
+		}
 	}
 
 	return ErrNone
@@ -180,7 +192,7 @@ func doesPresignV2SignatureMatch(r *http.Request) APIErrorCode {
 
 func getReqAccessKeyV2(r *http.Request) (auth.Credentials, bool, APIErrorCode) {
 	if accessKey := r.URL.Query().Get(xhttp.AmzAccessKeyID); accessKey != "" {
-		return checkKeyValid(accessKey)
+		return checkPresignKeyValid(accessKey)
 	}
 
 	// below is V2 Signed Auth header format, splitting on `space` (after the `AWS` string).
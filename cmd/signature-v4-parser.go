@@ -49,17 +49,20 @@ func (c credentialHeader) getScope() string {
 
 func getReqAccessKeyV4(r *http.Request, region string, stype serviceType) (auth.Credentials, bool, APIErrorCode) {
 	ch, err := parseCredentialHeader("Credential="+r.URL.Query().Get("X-Amz-Credential"), region, stype)
+	if err == ErrNone {
+		// Presigned request.
+		return checkPresignKeyValid(ch.accessKey)
+	}
+
+	// Strip off the Algorithm prefix.
+	v4Auth := strings.TrimPrefix(r.Header.Get("Authorization"), signV4Algorithm)
+	authFields := strings.Split(strings.TrimSpace(v4Auth), ",")
+	if len(authFields) != 3 {
+		return auth.Credentials{}, false, ErrMissingFields
+	}
+	ch, err = parseCredentialHeader(authFields[0], region, stype)
 	if err != ErrNone {
-		// Strip off the Algorithm prefix.
-		v4Auth := strings.TrimPrefix(r.Header.Get("Authorization"), signV4Algorithm)
-		authFields := strings.Split(strings.TrimSpace(v4Auth), ",")
-		if len(authFields) != 3 {
-			return auth.Credentials{}, false, ErrMissingFields
-		}
-		ch, err = parseCredentialHeader(authFields[0], region, stype)
-		if err != ErrNone {
-			return auth.Credentials{}, false, err
-		}
+		return auth.Credentials{}, false, err
 	}
 	return checkKeyValid(ch.accessKey)
 }
@@ -138,6 +138,19 @@ func checkKeyValid(accessKey string) (auth.Credentials, bool, APIErrorCode) {
 	return cred, owner, ErrNone
 }
 
+// checkPresignKeyValid - checks the access key of a presigned request
+// like checkKeyValid, the previous root access key is also recognized
+// during the grace period after the credentials are changed.
+func checkPresignKeyValid(accessKey string) (auth.Credentials, bool, APIErrorCode) {
+	cred, owner, s3Err := checkKeyValid(accessKey)
+	if s3Err == ErrInvalidAccessKeyID {
+		if prevCred, ok := globalPrevCredential.get(accessKey); ok {
+			return prevCred, true, ErrNone
+		}
+	}
+	return cred, owner, s3Err
+}
+
 // sumHMAC calculate hmac between two input byte array.
 func sumHMAC(key []byte, data []byte) []byte {
 	hash := hmac.New(sha256.New, key)
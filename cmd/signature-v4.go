@@ -211,7 +211,7 @@ func doesPresignedSignatureMatch(hashedPayload string, r *http.Request, region s
 		return err
 	}
 
-	cred, _, s3Err := checkKeyValid(pSignValues.Credential.accessKey)
+	cred, owner, s3Err := checkPresignKeyValid(pSignValues.Credential.accessKey)
 	if s3Err != ErrNone {
 		return s3Err
 	}
@@ -304,16 +304,28 @@ func doesPresignedSignatureMatch(hashedPayload string, r *http.Request, region s
 	// Get string to sign from canonical request.
 	presignedStringToSign := getStringToSign(presignedCanonicalReq, t, pSignValues.Credential.getScope())
 
-	// Get hmac presigned signing key.
-	presignedSigningKey := getSigningKey(cred.SecretKey, pSignValues.Credential.scope.date,
-		pSignValues.Credential.scope.region, stype)
+	presignedSignature := func(secretKey string) string {
+		// Get hmac presigned signing key.
+		presignedSigningKey := getSigningKey(secretKey, pSignValues.Credential.scope.date,
+			pSignValues.Credential.scope.region, stype)
 
-	// Get new signature.
-	newSignature := getSignature(presignedSigningKey, presignedStringToSign)
+		// Get new signature.
+		return getSignature(presignedSigningKey, presignedStringToSign)
+	}
 
 	// Verify signature.
-	if !compareSignatureV4(req.URL.Query().Get(xhttp.AmzSignature), newSignature) {
-		return ErrSignatureDoesNotMatch
+	gotSignature := req.URL.Query().Get(xhttp.AmzSignature)
+	if !compareSignatureV4(gotSignature, presignedSignature(cred.SecretKey)) {
+		// The URL may have been presigned before the root secret key
+		// changed, this never applies to IAM users.
+		if !owner {
+			return ErrSignatureDoesNotMatch
+		}
+		prevCred, ok := globalPrevCredential.get(cred.AccessKey)
+		if !ok || prevCred.SecretKey == cred.SecretKey ||
+			!compareSignatureV4(gotSignature, presignedSignature(prevCred.SecretKey)) {
+			return ErrSignatureDoesNotMatch
+		}
 	}
 	return ErrNone
 }
@@ -20,8 +20,11 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"sort"
 	"strconv"
 	"strings"
+
+	humanize "github.com/dustin/go-humanize"
 )
 
 const (
@@ -37,6 +40,8 @@ const (
 	reducedRedundancyStorageClassEnv = "MINIO_STORAGE_CLASS_RRS"
 	// Standard storage class environment variable
 	standardStorageClassEnv = "MINIO_STORAGE_CLASS_STANDARD"
+	// Object size based storage class environment variable
+	sizeStorageClassEnv = "MINIO_STORAGE_CLASS_SIZE"
 	// Supported storage class scheme is EC
 	supportedStorageClassScheme = "EC"
 	// Minimum parity disks
@@ -50,6 +55,12 @@ type storageClass struct {
 	Parity int
 }
 
+// Struct to hold a storage class applied to objects up to a given size
+type sizeStorageClass struct {
+	MaxSize int64
+	storageClass
+}
+
 type storageClassConfig struct {
 	Standard storageClass `json:"standard"`
 	RRS      storageClass `json:"rrs"`
@@ -128,6 +139,70 @@ func parseStorageClass(storageClassEnv string) (sc storageClass, err error) {
 	return sc, nil
 }
 
+// Parses given sizeStorageClassEnv and returns the size classes sorted by size.
+// Supported format is a comma separated list of "Size=Scheme:Number of parity disks"
+// e.g. "1MiB=EC:6,1GiB=EC:4", objects larger than every size use the standard storage class.
+func parseSizeStorageClass(sizeStorageClassEnv string) (sizeClasses []sizeStorageClass, err error) {
+	for _, rule := range strings.Split(sizeStorageClassEnv, ",") {
+		s := strings.SplitN(strings.TrimSpace(rule), "=", 2)
+		if len(s) != 2 {
+			return nil, uiErrStorageClassValue(nil).Msg("Missing size in " + rule)
+		}
+
+		size, err := humanize.ParseBytes(s[0])
+		if err != nil {
+			return nil, uiErrStorageClassValue(err)
+		}
+		if size == 0 {
+			return nil, uiErrStorageClassValue(nil).Msg("Size should be greater than zero in " + rule)
+		}
+
+		sc, err := parseStorageClass(s[1])
+		if err != nil {
+			return nil, err
+		}
+
+		for _, sizeClass := range sizeClasses {
+			if sizeClass.MaxSize == int64(size) {
+				return nil, uiErrStorageClassValue(nil).Msg("Duplicate size in " + rule)
+			}
+		}
+		sizeClasses = append(sizeClasses, sizeStorageClass{
+			MaxSize:      int64(size),
+			storageClass: sc,
+		})
+	}
+
+	sort.Slice(sizeClasses, func(i, j int) bool {
+		return sizeClasses[i].MaxSize < sizeClasses[j].MaxSize
+	})
+	return sizeClasses, nil
+}
+
+// Validates the parity disks of object size based storage classes.
+func validateSizeParity(sizeClasses []sizeStorageClass) (err error) {
+	if len(sizeClasses) == 0 {
+		return nil
+	}
+
+	if !globalIsXL {
+		return fmt.Errorf("Setting storage class only allowed for erasure coding mode")
+	}
+
+	for _, sizeClass := range sizeClasses {
+		if sizeClass.Parity < minimumParityDisks {
+			return fmt.Errorf("Storage class parity %d for objects up to %s should be greater than or equal to %d",
+				sizeClass.Parity, humanize.IBytes(uint64(sizeClass.MaxSize)), minimumParityDisks)
+		}
+
+		if sizeClass.Parity > globalXLSetDriveCount/2 {
+			return fmt.Errorf("Storage class parity %d for objects up to %s should be less than or equal to %d",
+				sizeClass.Parity, humanize.IBytes(uint64(sizeClass.MaxSize)), globalXLSetDriveCount/2)
+		}
+	}
+	return nil
+}
+
 // Validates the parity disks.
 func validateParity(ssParity, rrsParity int) (err error) {
 	if ssParity == 0 && rrsParity == 0 {
@@ -194,6 +269,21 @@ func getRedundancyCount(sc string, totalDisks int) (data, parity int) {
 	return totalDisks - parity, parity
 }
 
+// Returns the data and parity drive count for an object of given size.
+// If the object has standard storage class and its size is known, the
+// parity of the smallest size storage class the object fits in is used,
+// otherwise this is the same as getRedundancyCount.
+func getRedundancyCountForSize(sc string, size int64, totalDisks int) (data, parity int) {
+	if (sc == standardStorageClass || sc == "") && size >= 0 {
+		for _, sizeClass := range globalSizeStorageClass {
+			if size <= sizeClass.MaxSize {
+				return totalDisks - sizeClass.Parity, sizeClass.Parity
+			}
+		}
+	}
+	return getRedundancyCount(sc, totalDisks)
+}
+
 // Returns per object readQuorum and writeQuorum
 // readQuorum is the minimum required disks to read data.
 // writeQuorum is the minimum required disks to write data.
@@ -22,6 +22,8 @@ import (
 	"errors"
 	"reflect"
 	"testing"
+
+	humanize "github.com/dustin/go-humanize"
 )
 
 func TestParseStorageClass(t *testing.T) {
@@ -75,6 +77,41 @@ func testParseStorageClass(obj ObjectLayer, instanceType string, t TestErrHandle
 	}
 }
 
+func TestParseSizeStorageClass(t *testing.T) {
+	tests := []struct {
+		sizeStorageClassEnv string
+		wantSizeClasses     []sizeStorageClass
+		success             bool
+	}{
+		{"1MiB=EC:6", []sizeStorageClass{
+			{MaxSize: humanize.MiByte, storageClass: storageClass{Scheme: "EC", Parity: 6}},
+		}, true},
+		{"1GiB=EC:4, 1MiB=EC:6", []sizeStorageClass{
+			{MaxSize: humanize.MiByte, storageClass: storageClass{Scheme: "EC", Parity: 6}},
+			{MaxSize: humanize.GiByte, storageClass: storageClass{Scheme: "EC", Parity: 4}},
+		}, true},
+		{"EC:6", nil, false},
+		{"1MiB=AB:6", nil, false},
+		{"abc=EC:6", nil, false},
+		{"0=EC:6", nil, false},
+		{"1MiB=EC:6,1MiB=EC:4", nil, false},
+	}
+	for i, tt := range tests {
+		gotSizeClasses, err := parseSizeStorageClass(tt.sizeStorageClassEnv)
+		if err != nil && tt.success {
+			t.Errorf("Test %d, Expected success, got %s", i+1, err)
+			continue
+		}
+		if err == nil && !tt.success {
+			t.Errorf("Test %d, Expected failure, got success", i+1)
+			continue
+		}
+		if !reflect.DeepEqual(gotSizeClasses, tt.wantSizeClasses) {
+			t.Errorf("Test %d, Expected %v, got %v", i+1, tt.wantSizeClasses, gotSizeClasses)
+		}
+	}
+}
+
 func TestValidateParity(t *testing.T) {
 	ExecObjectLayerTestWithDirs(t, testValidateParity)
 }
@@ -166,6 +203,42 @@ func testGetRedundancyCount(obj ObjectLayer, instanceType string, dirs []string,
 	}
 }
 
+func TestRedundancyCountForSize(t *testing.T) {
+	resetGlobalStorageEnvs()
+	defer resetGlobalStorageEnvs()
+
+	globalStandardStorageClass.Parity = 6
+	globalSizeStorageClass = []sizeStorageClass{
+		{MaxSize: humanize.MiByte, storageClass: storageClass{Scheme: "EC", Parity: 8}},
+		{MaxSize: humanize.GiByte, storageClass: storageClass{Scheme: "EC", Parity: 4}},
+	}
+
+	tests := []struct {
+		sc             string
+		size           int64
+		expectedData   int
+		expectedParity int
+	}{
+		{"", 0, 8, 8},
+		{standardStorageClass, humanize.MiByte, 8, 8},
+		{"", humanize.MiByte + 1, 12, 4},
+		{"", humanize.GiByte + 1, 10, 6},
+		// Unknown size falls back to the standard storage class.
+		{"", -1, 10, 6},
+		// Size storage classes do not apply to reduced redundancy.
+		{reducedRedundancyStorageClass, 0, 14, 2},
+	}
+	for i, tt := range tests {
+		data, parity := getRedundancyCountForSize(tt.sc, tt.size, 16)
+		if data != tt.expectedData {
+			t.Errorf("Test %d, Expected data disks %d, got %d", i+1, tt.expectedData, data)
+		}
+		if parity != tt.expectedParity {
+			t.Errorf("Test %d, Expected parity disks %d, got %d", i+1, tt.expectedParity, parity)
+		}
+	}
+}
+
 func TestObjectQuorumFromMeta(t *testing.T) {
 	ExecObjectLayerTestWithDirs(t, testObjectQuorumFromMeta)
 }
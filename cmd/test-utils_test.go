@@ -368,6 +368,20 @@ func UnstartedTestServer(t TestErrHandler, instanceType string) TestServer {
 	globalLifecycleSys = NewLifecycleSys()
 	globalLifecycleSys.Init(objLayer)
 
+	globalBucketLoggingSys = NewBucketLoggingSys()
+	globalBucketLoggingSys.Init(objLayer)
+
+	globalBucketSnapshotSys = NewBucketSnapshotSys()
+	globalBucketSnapshotSys.Init(objLayer)
+
+	globalBucketCORSSys = NewBucketCORSSys()
+	globalBucketCORSSys.Init(objLayer)
+
+	globalBucketWebsiteSys = NewBucketWebsiteSys()
+	globalBucketWebsiteSys.Init(objLayer)
+
+	globalBucketModeSys = NewBucketModeSys()
+
 	return testServer
 }
 
@@ -469,6 +483,7 @@ func resetGlobalIsEnvs() {
 func resetGlobalStorageEnvs() {
 	globalStandardStorageClass = storageClass{}
 	globalRRStorageClass = storageClass{}
+	globalSizeStorageClass = nil
 }
 
 // reset global heal state
@@ -23,6 +23,10 @@ import (
 // errInvalidArgument means that input argument is invalid.
 var errInvalidArgument = errors.New("Invalid arguments specified")
 
+// errImageNotTransformable means that the object is not an image
+// which can be transformed.
+var errImageNotTransformable = errors.New("Object is not a JPEG, PNG or GIF image which can be transformed")
+
 // errMethodNotAllowed means that method is not allowed.
 var errMethodNotAllowed = errors.New("Method not allowed")
 
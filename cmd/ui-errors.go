@@ -23,6 +23,12 @@ var (
 		"For more details, refer to https://docs.min.io/docs/minio-server-configuration-guide",
 	)
 
+	uiErrInvalidCredentialGracePeriod = newUIErrFn(
+		"Invalid credential grace period",
+		"Please check the passed value",
+		"MINIO_CREDENTIAL_GRACE_PERIOD: Valid credential grace period is a non-negative duration, for example 1h or 30m",
+	)
+
 	uiErrInvalidBrowserValue = newUIErrFn(
 		"Invalid browser value",
 		"Please check the passed value",
@@ -47,6 +53,12 @@ var (
 		"WORM can only accept `on` and `off` values. To enable WORM, set this value to `on`",
 	)
 
+	uiErrInvalidACMEDomainsValue = newUIErrFn(
+		"Invalid ACME domains value",
+		"Please check the passed value",
+		"MINIO_ACME_DOMAINS: Fully qualified domain names delimited by `,`, wildcard domains and IP addresses are not supported",
+	)
+
 	uiErrInvalidCacheDrivesValue = newUIErrFn(
 		"Invalid cache drive value",
 		"Please check the value in this ENV variable",
@@ -59,6 +71,36 @@ var (
 		"MINIO_CACHE_EXCLUDE: Cache exclusion patterns are delimited by `;`",
 	)
 
+	uiErrInvalidCacheAffinityValue = newUIErrFn(
+		"Invalid cache affinity value",
+		"Please check the passed value",
+		"MINIO_CACHE_AFFINITY: Cache affinity rules are delimited by `;` and take the form `bucket=drive1,drive2`",
+	)
+
+	uiErrInvalidCacheStorageClassValue = newUIErrFn(
+		"Invalid cache storage class value",
+		"Please check the passed value",
+		"MINIO_CACHE_STORAGE_CLASS: Cache storage class policies are delimited by `;` and take the form `class=exclude` or `class=priority`",
+	)
+
+	uiErrInvalidCacheQuotaValue = newUIErrFn(
+		"Invalid cache quota value",
+		"Please check the passed value",
+		"MINIO_CACHE_QUOTA: Cache quotas are delimited by `;` and take the form `bucket=percent`",
+	)
+
+	uiErrInvalidCacheRoutingValue = newUIErrFn(
+		"Invalid cache routing value",
+		"Please check the passed value",
+		"MINIO_CACHE_ROUTING: Cache routing rules are delimited by `;` and take the form `condition1,condition2=drive1,drive2`",
+	)
+
+	uiErrInvalidCacheParityValue = newUIErrFn(
+		"Invalid cache parity value",
+		"Please check the passed value",
+		"MINIO_CACHE_PARITY: Cache parity is the number of parity shards, at most half the cache drives of a bucket",
+	)
+
 	uiErrInvalidCacheExpiryValue = newUIErrFn(
 		"Invalid cache expiry value",
 		"Please check the passed value",
@@ -107,6 +149,7 @@ Secret key should be in between 8 and 40 characters`,
 		"Please check the value",
 		`MINIO_STORAGE_CLASS_STANDARD: Format "EC:<Default_Parity_Standard_Class>" (e.g. "EC:3"). This sets the number of parity disks for MinIO server in Standard mode. Objects are stored in Standard mode, if storage class is not defined in Put request
 MINIO_STORAGE_CLASS_RRS: Format "EC:<Default_Parity_Reduced_Redundancy_Class>" (e.g. "EC:3"). This sets the number of parity disks for MinIO server in Reduced Redundancy mode. Objects are stored in Reduced Redundancy mode, if Put request specifies RRS storage class
+MINIO_STORAGE_CLASS_SIZE: Format "<Size>=EC:<Parity>,..." (e.g. "1MiB=EC:6,1GiB=EC:4"). This sets the number of parity disks for Standard mode objects up to the given size
 Refer to the link https://github.com/minio/minio/tree/master/docs/erasure/storage-class for more information`,
 	)
 
@@ -211,12 +254,36 @@ Example 1:
 		"Compress extensions/mime-types are delimited by `,`. For eg, MINIO_COMPRESS_ATTR=\"A,B,C\"",
 	)
 
+	uiErrInvalidCompressionAlgorithmValue = newUIErrFn(
+		"Invalid compression algorithm value",
+		"Please check the passed value",
+		"Compression algorithm should be one of `snappy`, `s2`, `zstd` or `lz4`. For eg, MINIO_COMPRESS_ALGORITHM=\"s2\"",
+	)
+
 	uiErrInvalidGWSSEValue = newUIErrFn(
 		"Invalid gateway SSE value",
 		"Please check the passed value",
 		"MINIO_GATEWAY_SSE: Gateway SSE accepts only C and S3 as valid values. Delimit by `;` to set more than one value",
 	)
 
+	uiErrInvalidGatewayCircuitBreakerThreshold = newUIErrFn(
+		"Invalid gateway circuit breaker threshold",
+		"Please check the passed value",
+		"MINIO_GATEWAY_CIRCUIT_BREAKER_THRESHOLD: Valid circuit breaker threshold is a non-negative number of consecutive failures, 0 disables the circuit breaker",
+	)
+
+	uiErrInvalidGatewayCircuitBreakerCoolDown = newUIErrFn(
+		"Invalid gateway circuit breaker cool-down",
+		"Please check the passed value",
+		"MINIO_GATEWAY_CIRCUIT_BREAKER_COOLDOWN: Valid circuit breaker cool-down is a positive duration, for example 30s or 1m",
+	)
+
+	uiErrInvalidGatewayReadReplicas = newUIErrFn(
+		"Invalid gateway read replicas",
+		"Please check the passed value",
+		"MINIO_GATEWAY_READ_REPLICAS: Valid read replicas are a comma separated list of backend endpoints, for example https://replica1:9000,https://replica2:9000",
+	)
+
 	uiErrInvalidGWSSEEnvValue = newUIErrFn(
 		"Invalid gateway SSE configuration",
 		"",
@@ -0,0 +1,113 @@
+/*
+ * MinIO Cloud Storage, (C) 2020 MinIO, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"sync"
+	"time"
+)
+
+const (
+	// Interval at which the browser update check is refreshed.
+	updateCheckInterval = 24 * time.Hour
+
+	// Timeout of the download of the release information.
+	updateCheckTimeout = 10 * time.Second
+
+	// Release notes of a release tag.
+	minioReleaseNotesURL = "https://github.com/minio/minio/releases/tag/"
+)
+
+// UpdateInfo - result of an update check.
+type UpdateInfo struct {
+	// True if a newer release is available.
+	Available bool `json:"available"`
+	// Release tag and time of the latest release.
+	LatestRelease     string    `json:"latestRelease"`
+	LatestReleaseTime time.Time `json:"latestReleaseTime"`
+	ReleaseNotesURL   string    `json:"releaseNotesURL"`
+	// Where to get the latest release, set if it is newer.
+	DownloadURL string    `json:"downloadURL,omitempty"`
+	CheckedAt   time.Time `json:"checkedAt"`
+}
+
+// updateChecker - checks for new releases in the background against the
+// release information of the update URL, at most once per interval.
+type updateChecker struct {
+	mu       sync.Mutex
+	interval time.Duration
+	checking bool
+	last     time.Time
+	info     *UpdateInfo
+
+	// Returns the current and the latest release times.
+	check func() (current, latest time.Time, err error)
+}
+
+func newUpdateChecker(interval time.Duration) *updateChecker {
+	return &updateChecker{
+		interval: interval,
+		check: func() (current, latest time.Time, err error) {
+			if current, err = GetCurrentReleaseTime(); err != nil {
+				return current, latest, err
+			}
+			_, latest, err = getLatestReleaseTime(updateCheckTimeout, getMinioMode())
+			return current, latest, err
+		},
+	}
+}
+
+// Info - returns the result of the last successful check, nil if none
+// succeeded yet. A new check is started in the background once the
+// interval has elapsed.
+func (u *updateChecker) Info() *UpdateInfo {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+
+	if !u.checking && time.Since(u.last) >= u.interval {
+		u.checking = true
+		go u.refresh()
+	}
+	return u.info
+}
+
+func (u *updateChecker) refresh() {
+	current, latest, err := u.check()
+
+	u.mu.Lock()
+	defer u.mu.Unlock()
+
+	u.checking = false
+	u.last = time.Now()
+	if err != nil {
+		// Keep the result of the last successful check.
+		return
+	}
+
+	releaseTag := releaseTimeToReleaseTag(latest)
+	info := &UpdateInfo{
+		LatestRelease:     releaseTag,
+		LatestReleaseTime: latest,
+		ReleaseNotesURL:   minioReleaseNotesURL + releaseTag,
+		CheckedAt:         UTCNow(),
+	}
+	if latest.After(current) {
+		info.Available = true
+		info.DownloadURL = getDownloadURL(releaseTag)
+	}
+	u.info = info
+}
@@ -0,0 +1,73 @@
+/*
+ * MinIO Cloud Storage, (C) 2020 MinIO, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestUpdateChecker(t *testing.T) {
+	current := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	latest := current.Add(24 * time.Hour)
+	var checkErr error
+
+	u := newUpdateChecker(time.Hour)
+	u.check = func() (time.Time, time.Time, error) {
+		return current, latest, checkErr
+	}
+	// Waits for the check in the background to complete.
+	waitForCheck := func() {
+		for {
+			u.mu.Lock()
+			checking := u.checking
+			u.mu.Unlock()
+			if !checking {
+				return
+			}
+			time.Sleep(time.Millisecond)
+		}
+	}
+
+	// The first call starts a check in the background.
+	if info := u.Info(); info != nil {
+		t.Fatalf("Expected no update info before the first check, got %v", info)
+	}
+	waitForCheck()
+
+	info := u.Info()
+	if info == nil || !info.Available || info.LatestRelease != "RELEASE.2020-01-02T00-00-00Z" || info.DownloadURL == "" {
+		t.Fatalf("Unexpected update info %v", info)
+	}
+	if info.ReleaseNotesURL != minioReleaseNotesURL+info.LatestRelease {
+		t.Fatalf("Unexpected release notes URL %s", info.ReleaseNotesURL)
+	}
+
+	// Failed checks keep the last result.
+	checkErr = errors.New("network down")
+	u.mu.Lock()
+	u.last = time.Time{}
+	u.mu.Unlock()
+	if got := u.Info(); got != info {
+		t.Fatalf("Expected the last update info, got %v", got)
+	}
+	waitForCheck()
+	if got := u.Info(); got != info {
+		t.Fatalf("Expected the last update info to be kept, got %v", got)
+	}
+}
@@ -33,6 +33,7 @@ import (
 	"path/filepath"
 	"reflect"
 	"strings"
+	"sync/atomic"
 	"time"
 
 	xhttp "github.com/minio/minio/cmd/http"
@@ -72,6 +73,10 @@ func cloneHeader(h http.Header) http.Header {
 }
 
 func request2BucketObjectName(r *http.Request) (bucketName, objectName string) {
+	// Requests to bucket website endpoints are for objects of the bucket.
+	if bucket, ok := getWebsiteBucket(r.Host); ok {
+		return bucket, strings.TrimPrefix(r.URL.Path, SlashSeparator)
+	}
 	path, err := getResource(r.URL.Path, r.Host, globalDomainNames)
 	if err != nil {
 		logger.CriticalIf(context.Background(), err)
@@ -197,15 +202,21 @@ func contains(slice interface{}, elem interface{}) bool {
 // provide any API to calculate the profiler file path in the
 // disk since the name of this latter is randomly generated.
 type profilerWrapper struct {
-	stopFn func()
-	pathFn func() string
+	stopFn  func()
+	pathFn  func() string
+	stopped uint32
 }
 
-func (p profilerWrapper) Stop() {
+func (p *profilerWrapper) Stop() {
+	atomic.StoreUint32(&p.stopped, 1)
 	p.stopFn()
 }
 
-func (p profilerWrapper) Path() string {
+func (p *profilerWrapper) Stopped() bool {
+	return atomic.LoadUint32(&p.stopped) == 1
+}
+
+func (p *profilerWrapper) Path() string {
 	return p.pathFn()
 }
 
@@ -279,6 +290,8 @@ func startProfiler(profilerType, dirPath string) (minioProfiler, error) {
 type minioProfiler interface {
 	// Stop the profiler
 	Stop()
+	// Return true once the profiler is stopped
+	Stopped() bool
 	// Return the path of the profiling file
 	Path() string
 }
@@ -434,6 +447,9 @@ func newContext(r *http.Request, w http.ResponseWriter, api string) context.Cont
 		BucketName:   bucket,
 		ObjectName:   object,
 	}
+	if req := getActiveRequest(r.Context()); req != nil {
+		req.setAPI(api, bucket, object)
+	}
 	return logger.SetReqInfo(r.Context(), reqInfo)
 }
 
@@ -521,3 +537,16 @@ func getMinioMode() string {
 	}
 	return mode
 }
+
+// sleepContext - waits for the given duration, returns false without
+// waiting the full duration if ctx is canceled in the meanwhile.
+func sleepContext(ctx context.Context, d time.Duration) bool {
+	t := time.NewTimer(d)
+	defer t.Stop()
+	select {
+	case <-t.C:
+		return true
+	case <-ctx.Done():
+		return false
+	}
+}
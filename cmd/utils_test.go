@@ -18,6 +18,7 @@ package cmd
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"errors"
 	"fmt"
@@ -28,6 +29,7 @@ import (
 	"reflect"
 	"strings"
 	"testing"
+	"time"
 )
 
 // Tests http.Header clone.
@@ -522,3 +524,23 @@ func TestGetMinioMode(t *testing.T) {
 	testMinioMode(globalMinioModeGatewayPrefix + globalGatewayName)
 
 }
+
+// Tests sleepContext returning early on a canceled context.
+func TestSleepContext(t *testing.T) {
+	if !sleepContext(context.Background(), time.Millisecond) {
+		t.Fatal("Expected to sleep the full duration")
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	go func() {
+		time.Sleep(10 * time.Millisecond)
+		cancel()
+	}()
+	start := time.Now()
+	if sleepContext(ctx, time.Minute) {
+		t.Fatal("Expected to return early on cancellation")
+	}
+	if time.Since(start) > 10*time.Second {
+		t.Fatal("Expected to return promptly on cancellation")
+	}
+}
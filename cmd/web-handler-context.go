@@ -164,6 +164,14 @@ func (args *ListObjectsArgs) ToKeyValue() KeyValueMap {
 	return km
 }
 
+// ToKeyValue implementation for PrefixStatArgs
+func (args *PrefixStatArgs) ToKeyValue() KeyValueMap {
+	km := KeyValueMap{}
+	km.SetBucket(args.BucketName)
+	km.SetPrefix(args.Prefix)
+	return km
+}
+
 // ToKeyValue implementation for RemoveObjectArgs
 func (args *RemoveObjectArgs) ToKeyValue() KeyValueMap {
 	km := KeyValueMap{}
@@ -172,6 +180,29 @@ func (args *RemoveObjectArgs) ToKeyValue() KeyValueMap {
 	return km
 }
 
+// ToKeyValue implementation for SearchArgs
+func (args *SearchArgs) ToKeyValue() KeyValueMap {
+	km := KeyValueMap{}
+	km.SetBucket(args.BucketName)
+	return km
+}
+
+// ToKeyValue implementation for ListIncompleteUploadsArgs
+func (args *ListIncompleteUploadsArgs) ToKeyValue() KeyValueMap {
+	km := KeyValueMap{}
+	km.SetBucket(args.BucketName)
+	km.SetObject(args.ObjectName)
+	return km
+}
+
+// ToKeyValue implementation for AbortIncompleteUploadArgs
+func (args *AbortIncompleteUploadArgs) ToKeyValue() KeyValueMap {
+	km := KeyValueMap{}
+	km.SetBucket(args.BucketName)
+	km.SetObject(args.ObjectName)
+	return km
+}
+
 // ToKeyValue implementation for LoginArgs
 func (args *LoginArgs) ToKeyValue() KeyValueMap {
 	km := KeyValueMap{}
@@ -203,6 +234,136 @@ func (args *SetBucketPolicyWebArgs) ToKeyValue() KeyValueMap {
 	return km
 }
 
+// ToKeyValue implementation for SetBucketPolicyWithConditionsArgs
+func (args *SetBucketPolicyWithConditionsArgs) ToKeyValue() KeyValueMap {
+	return args.SetBucketPolicyWebArgs.ToKeyValue()
+}
+
+// ToKeyValue implementation for SetBucketPolicyJSONArgs
+func (args *SetBucketPolicyJSONArgs) ToKeyValue() KeyValueMap {
+	km := KeyValueMap{}
+	km.SetBucket(args.BucketName)
+	return km
+}
+
+// ToKeyValue implementation for BucketTrashArgs
+func (args *BucketTrashArgs) ToKeyValue() KeyValueMap {
+	km := KeyValueMap{}
+	km.SetBucket(args.BucketName)
+	return km
+}
+
+// ToKeyValue implementation for SetBucketTrashArgs
+func (args *SetBucketTrashArgs) ToKeyValue() KeyValueMap {
+	km := KeyValueMap{}
+	km.SetBucket(args.BucketName)
+	return km
+}
+
+// ToKeyValue implementation for BucketAnonymousUploadArgs
+func (args *BucketAnonymousUploadArgs) ToKeyValue() KeyValueMap {
+	km := KeyValueMap{}
+	km.SetBucket(args.BucketName)
+	return km
+}
+
+// ToKeyValue implementation for SetBucketAnonymousUploadArgs
+func (args *SetBucketAnonymousUploadArgs) ToKeyValue() KeyValueMap {
+	km := KeyValueMap{}
+	km.SetBucket(args.BucketName)
+	return km
+}
+
+// ToKeyValue implementation for BucketContentTypeArgs
+func (args *BucketContentTypeArgs) ToKeyValue() KeyValueMap {
+	km := KeyValueMap{}
+	km.SetBucket(args.BucketName)
+	return km
+}
+
+// ToKeyValue implementation for SetBucketContentTypeArgs
+func (args *SetBucketContentTypeArgs) ToKeyValue() KeyValueMap {
+	km := KeyValueMap{}
+	km.SetBucket(args.BucketName)
+	return km
+}
+
+// ToKeyValue implementation for ListTrashArgs
+func (args *ListTrashArgs) ToKeyValue() KeyValueMap {
+	km := KeyValueMap{}
+	km.SetBucket(args.BucketName)
+	km.SetPrefix(args.Prefix)
+	km.SetMarker(args.Marker)
+	return km
+}
+
+// ToKeyValue implementation for RestoreObjectArgs
+func (args *RestoreObjectArgs) ToKeyValue() KeyValueMap {
+	km := KeyValueMap{}
+	km.SetBucket(args.BucketName)
+	km.SetObjects(args.Objects)
+	return km
+}
+
+// ToKeyValue implementation for CopyPrefixArgs
+func (args *CopyPrefixArgs) ToKeyValue() KeyValueMap {
+	km := KeyValueMap{}
+	km.SetBucket(args.BucketName)
+	km.SetPrefix(args.Prefix)
+	return km
+}
+
+// ToKeyValue implementation for CopyPrefixStatusArgs
+func (args *CopyPrefixStatusArgs) ToKeyValue() KeyValueMap {
+	return KeyValueMap{}
+}
+
+// ToKeyValue implementation for DownloadZipStatusArgs
+func (args *DownloadZipStatusArgs) ToKeyValue() KeyValueMap {
+	km := KeyValueMap{}
+	km.SetHostname(args.HostName)
+	return km
+}
+
+// ToKeyValue implementation for ComposeObjectArgs
+func (args *ComposeObjectArgs) ToKeyValue() KeyValueMap {
+	km := KeyValueMap{}
+	km.SetBucket(args.BucketName)
+	km.SetObject(args.ObjectName)
+	return km
+}
+
+// ToKeyValue implementation for TestNotificationTargetArgs
+func (args *TestNotificationTargetArgs) ToKeyValue() KeyValueMap {
+	km := KeyValueMap{}
+	km.SetBucket(args.BucketName)
+	return km
+}
+
+// ToKeyValue implementation for SimulatePolicyArgs
+func (args *SimulatePolicyArgs) ToKeyValue() KeyValueMap {
+	km := KeyValueMap{}
+	km.SetBucket(args.BucketName)
+	km.SetObject(args.ObjectName)
+	return km
+}
+
+// ToKeyValue implementation for CreateInviteArgs
+func (args *CreateInviteArgs) ToKeyValue() KeyValueMap {
+	km := KeyValueMap{}
+	km.SetBucket(args.BucketName)
+	km.SetPolicy(args.Policy)
+	return km
+}
+
+// ToKeyValue implementation for AcceptInviteArgs
+// AcceptInviteArgs doesn't implement the ToKeyValue interface that will
+// be used by logger subsystem down the line, to avoid leaking the
+// invite token to an external log target
+func (args *AcceptInviteArgs) ToKeyValue() KeyValueMap {
+	return KeyValueMap{}
+}
+
 // ToKeyValue implementation for SetAuthArgs
 // SetAuthArgs doesn't implement the ToKeyValue interface that will be
 // used by logger subsystem down the line, to avoid leaking
@@ -221,6 +382,14 @@ func (args *PresignedGetArgs) ToKeyValue() KeyValueMap {
 	return km
 }
 
+// ToKeyValue implementation for ListObjectVersionsArgs
+func (args *ListObjectVersionsArgs) ToKeyValue() KeyValueMap {
+	km := KeyValueMap{}
+	km.SetBucket(args.BucketName)
+	km.SetObject(args.ObjectName)
+	return km
+}
+
 // newWebContext creates a context with ReqInfo values from the given
 // http request and api name.
 func newWebContext(r *http.Request, args ToKeyValuer, api string) context.Context {
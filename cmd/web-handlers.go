@@ -18,7 +18,9 @@ package cmd
 
 import (
 	"archive/zip"
+	"bytes"
 	"context"
+	"encoding/csv"
 	"encoding/json"
 	"fmt"
 	"io"
@@ -27,15 +29,16 @@ import (
 	"os"
 	"path"
 	"runtime"
+	"sort"
 	"strconv"
 	"strings"
 	"sync"
 	"time"
 
 	humanize "github.com/dustin/go-humanize"
-	snappy "github.com/golang/snappy"
 	"github.com/gorilla/mux"
 	"github.com/gorilla/rpc/v2/json2"
+	miniogo "github.com/minio/minio-go/v6"
 	miniogopolicy "github.com/minio/minio-go/v6/pkg/policy"
 	"github.com/minio/minio-go/v6/pkg/s3utils"
 	"github.com/minio/minio-go/v6/pkg/set"
@@ -49,8 +52,11 @@ import (
 	"github.com/minio/minio/pkg/handlers"
 	"github.com/minio/minio/pkg/hash"
 	iampolicy "github.com/minio/minio/pkg/iam/policy"
+	"github.com/minio/minio/pkg/imagetransform"
 	"github.com/minio/minio/pkg/ioutil"
+	"github.com/minio/minio/pkg/madmin"
 	"github.com/minio/minio/pkg/policy"
+	"github.com/minio/minio/pkg/sync/errgroup"
 )
 
 // WebGenericArgs - empty struct for calls that don't accept arguments
@@ -66,6 +72,9 @@ type WebGenericRep struct {
 // ServerInfoRep - server info reply.
 type ServerInfoRep struct {
 	MinioVersion    string
+	MinioCommitID   string
+	MinioBuildTime  string
+	MinioUpdate     *UpdateInfo `json:"MinioUpdate,omitempty"`
 	MinioMemory     string
 	MinioPlatform   string
 	MinioRuntime    string
@@ -77,7 +86,7 @@ type ServerInfoRep struct {
 // ServerInfo - get server info.
 func (web *webAPIHandlers) ServerInfo(r *http.Request, args *WebGenericArgs, reply *ServerInfoRep) error {
 	ctx := newWebContext(r, args, "webServerInfo")
-	_, owner, authErr := webRequestAuthenticate(r)
+	claims, owner, authErr := webRequestAuthenticate(r)
 	if authErr != nil {
 		return toJSONError(ctx, authErr)
 	}
@@ -99,6 +108,10 @@ func (web *webAPIHandlers) ServerInfo(r *http.Request, args *WebGenericArgs, rep
 	goruntime := fmt.Sprintf("Version: %s | CPUs: %s", runtime.Version(), strconv.Itoa(runtime.NumCPU()))
 
 	reply.MinioVersion = Version
+	reply.MinioCommitID = CommitID
+	if buildTime, err := GetCurrentReleaseTime(); err == nil {
+		reply.MinioBuildTime = buildTime.UTC().Format(time.RFC3339)
+	}
 	reply.MinioGlobalInfo = getGlobalInfo()
 
 	// if etcd is set, disallow changing credentials through UI for owner
@@ -111,17 +124,35 @@ func (web *webAPIHandlers) ServerInfo(r *http.Request, args *WebGenericArgs, rep
 		"isIAMUser": !owner,
 	}
 
+	reply.UIVersion = browser.UIVersion
+
+	// Host details are only shared with the owner and users
+	// delegated to view server info.
+	if !globalIAMSys.IsAllowed(iampolicy.Args{
+		AccountName:     claims.Subject,
+		Action:          iampolicy.ServerInfoAdminAction,
+		ConditionValues: getConditionValues(r, "", claims.Subject),
+		IsOwner:         owner,
+	}) {
+		return nil
+	}
+
 	reply.MinioMemory = mem
 	reply.MinioPlatform = platform
 	reply.MinioRuntime = goruntime
-	reply.UIVersion = browser.UIVersion
+	if globalUpdateChecker != nil {
+		reply.MinioUpdate = globalUpdateChecker.Info()
+	}
 	return nil
 }
 
 // StorageInfoRep - contains storage usage statistics.
 type StorageInfoRep struct {
-	StorageInfo StorageInfo `json:"storageInfo"`
-	UIVersion   string      `json:"uiVersion"`
+	StorageInfo   StorageInfo `json:"storageInfo"`
+	Objects       uint64      `json:"objects"`
+	AvgObjectSize uint64      `json:"avgObjectSize"`
+	HealBacklog   []uint64    `json:"healBacklog,omitempty"`
+	UIVersion     string      `json:"uiVersion"`
 }
 
 // StorageInfo - web call to gather storage usage statistics.
@@ -131,12 +162,32 @@ func (web *webAPIHandlers) StorageInfo(r *http.Request, args *WebGenericArgs, re
 	if objectAPI == nil {
 		return toJSONError(ctx, errServerNotInitialized)
 	}
-	_, _, authErr := webRequestAuthenticate(r)
+	claims, owner, authErr := webRequestAuthenticate(r)
 	if authErr != nil {
 		return toJSONError(ctx, authErr)
 	}
-	reply.StorageInfo = objectAPI.StorageInfo(ctx)
+	storageInfo := objectAPI.StorageInfo(ctx)
 	reply.UIVersion = browser.UIVersion
+
+	// Users without delegated storage info access only see the
+	// disk usage, object counts and backend details are kept for
+	// the owner.
+	if !globalIAMSys.IsAllowed(iampolicy.Args{
+		AccountName:     claims.Subject,
+		Action:          iampolicy.StorageInfoAdminAction,
+		ConditionValues: getConditionValues(r, "", claims.Subject),
+		IsOwner:         owner,
+	}) {
+		reply.StorageInfo.Used = storageInfo.Used
+		reply.StorageInfo.Total = storageInfo.Total
+		reply.StorageInfo.Available = storageInfo.Available
+		return nil
+	}
+
+	reply.StorageInfo = storageInfo
+	reply.Objects = storageInfo.Objects
+	reply.AvgObjectSize = storageInfo.AvgObjectSize
+	reply.HealBacklog = storageInfo.Backend.HealBacklog
 	return nil
 }
 
@@ -289,6 +340,57 @@ type WebBucketInfo struct {
 	Name string `json:"name"`
 	// Date the bucket was created.
 	CreationDate time.Time `json:"creationDate"`
+	// Number of objects and their total size as last counted by the
+	// usage crawler.
+	Objects uint64 `json:"objects,omitempty"`
+	Size    uint64 `json:"size,omitempty"`
+	// Access level granted to anonymous users by the bucket policy,
+	// only set for users allowed to read the policy.
+	Access string `json:"access,omitempty"`
+	// Object lock mode and default retention of the bucket, never set
+	// as object locking is not supported.
+	ObjectLockMode   string `json:"objectLockMode,omitempty"`
+	DefaultRetention string `json:"defaultRetention,omitempty"`
+	// Versioning status of the bucket.
+	Versioning string `json:"versioning,omitempty"`
+	// Whether the server runs in WORM mode, objects can then neither be
+	// overwritten nor deleted, without any retention period.
+	WORM bool `json:"worm,omitempty"`
+	// Mode of the bucket if it is not in read-write mode.
+	Mode madmin.BucketMode `json:"mode,omitempty"`
+}
+
+// Versioning status of buckets listed by the browser, versioning is
+// not supported.
+const webBucketVersioningUnsupported = "Unsupported"
+
+// setWebBucketCompliance - sets the fields of the bucket list entry
+// showing whether objects of the bucket may be modified. Per bucket
+// object locking, default retention and versioning are not supported,
+// only the server wide WORM mode and the mode of the bucket apply.
+func setWebBucketCompliance(bucketInfo *WebBucketInfo) {
+	bucketInfo.Versioning = webBucketVersioningUnsupported
+	bucketInfo.WORM = globalWORMEnabled
+	if globalBucketModeSys != nil {
+		if mode := globalBucketModeSys.Get(bucketInfo.Name); mode != madmin.BucketModeReadWrite {
+			bucketInfo.Mode = mode
+		}
+	}
+}
+
+// getWebBucketAccess - returns the access level granted by the cached
+// policy of a bucket, as shown by the browser.
+func getWebBucketAccess(bucket string) string {
+	bucketPolicy, found := globalPolicySys.Get(bucket)
+	if !found {
+		return string(miniogopolicy.BucketPolicyNone)
+	}
+	policyInfo, err := PolicyToBucketAccessPolicy(&bucketPolicy)
+	if err != nil {
+		return string(miniogopolicy.BucketPolicyNone)
+	}
+	statements, _ := splitIPRestrictionStatements(policyInfo.Statements)
+	return string(miniogopolicy.GetPolicy(statements, bucket, ""))
 }
 
 // ListBuckets - list buckets api.
@@ -344,6 +446,9 @@ func (web *webAPIHandlers) ListBuckets(r *http.Request, args *WebGenericArgs, re
 		if err != nil {
 			return toJSONError(ctx, err)
 		}
+		// The usage of all buckets is crawled in the background,
+		// the bucket list needs no per bucket queries.
+		usage := objectAPI.StorageInfo(ctx).Buckets
 		for _, bucket := range buckets {
 			if globalIAMSys.IsAllowed(iampolicy.Args{
 				AccountName:     claims.Subject,
@@ -353,10 +458,23 @@ func (web *webAPIHandlers) ListBuckets(r *http.Request, args *WebGenericArgs, re
 				IsOwner:         owner,
 				ObjectName:      "",
 			}) {
-				reply.Buckets = append(reply.Buckets, WebBucketInfo{
+				bucketInfo := WebBucketInfo{
 					Name:         bucket.Name,
 					CreationDate: bucket.Created,
-				})
+					Objects:      usage[bucket.Name].Objects,
+					Size:         usage[bucket.Name].Size,
+				}
+				setWebBucketCompliance(&bucketInfo)
+				if !globalIsGateway && globalIAMSys.IsAllowed(iampolicy.Args{
+					AccountName:     claims.Subject,
+					Action:          iampolicy.GetBucketPolicyAction,
+					BucketName:      bucket.Name,
+					ConditionValues: getConditionValues(r, "", claims.Subject),
+					IsOwner:         owner,
+				}) {
+					bucketInfo.Access = getWebBucketAccess(bucket.Name)
+				}
+				reply.Buckets = append(reply.Buckets, bucketInfo)
 			}
 		}
 	}
@@ -370,6 +488,9 @@ type ListObjectsArgs struct {
 	BucketName string `json:"bucketName"`
 	Prefix     string `json:"prefix"`
 	Marker     string `json:"marker"`
+	// When set, a single page of at most MaxKeys entries following
+	// Marker is returned instead of all the entries under Prefix.
+	MaxKeys int `json:"maxKeys"`
 }
 
 // ListObjectsRep - list objects response.
@@ -377,6 +498,9 @@ type ListObjectsRep struct {
 	Objects   []WebObjectInfo `json:"objects"`
 	Writable  bool            `json:"writable"` // Used by client to show "upload file" button.
 	UIVersion string          `json:"uiVersion"`
+	// Set for paged listings, NextMarker is the marker of the next page.
+	NextMarker  string `json:"nextMarker,omitempty"`
+	IsTruncated bool   `json:"isTruncated,omitempty"`
 }
 
 // WebObjectInfo container for list objects metadata.
@@ -417,35 +541,27 @@ func (web *webAPIHandlers) ListObjects(r *http.Request, args *ListObjectsArgs, r
 			return toJSONError(ctx, err, args.BucketName)
 		}
 
-		nextMarker := ""
-		// Fetch all the objects
-		for {
-			result, err := core.ListObjects(args.BucketName, args.Prefix, nextMarker, SlashSeparator, 1000)
+		if args.MaxKeys > 0 {
+			result, err := core.ListObjects(args.BucketName, args.Prefix, args.Marker, SlashSeparator, getWebListMaxKeys(args.MaxKeys))
 			if err != nil {
 				return toJSONError(ctx, err, args.BucketName)
 			}
+			reply.Objects, _ = remoteListResultToWebObjects(result, "")
+			reply.NextMarker = getRemoteListNextMarker(result)
+			reply.IsTruncated = result.IsTruncated
+			return nil
+		}
 
-			for _, obj := range result.Contents {
-				reply.Objects = append(reply.Objects, WebObjectInfo{
-					Key:          obj.Key,
-					LastModified: obj.LastModified,
-					Size:         obj.Size,
-					ContentType:  obj.ContentType,
-				})
-			}
-			for _, p := range result.CommonPrefixes {
-				reply.Objects = append(reply.Objects, WebObjectInfo{
-					Key: p.Prefix,
-				})
-			}
-
-			nextMarker = result.NextMarker
-
-			// Return when there are no more objects
-			if !result.IsTruncated {
-				return nil
-			}
+		objects, truncated, err := listRemoteObjects(core, args.BucketName, args.Prefix, maxRemoteObjectList)
+		if err != nil {
+			return toJSONError(ctx, err, args.BucketName)
+		}
+		reply.Objects = objects
+		if truncated {
+			reply.NextMarker = objects[len(objects)-1].Key
+			reply.IsTruncated = true
 		}
+		return nil
 	}
 
 	claims, owner, authErr := webRequestAuthenticate(r)
@@ -530,9 +646,14 @@ func (web *webAPIHandlers) ListObjects(r *http.Request, args *ListObjectsArgs, r
 	}
 
 	nextMarker := ""
+	maxKeys := maxObjectList
+	if args.MaxKeys > 0 {
+		nextMarker = args.Marker
+		maxKeys = getWebListMaxKeys(args.MaxKeys)
+	}
 	// Fetch all the objects
 	for {
-		lo, err := listObjects(ctx, args.BucketName, args.Prefix, nextMarker, SlashSeparator, 1000)
+		lo, err := listObjects(ctx, args.BucketName, args.Prefix, nextMarker, SlashSeparator, maxKeys)
 		if err != nil {
 			return &json2.Error{Message: err.Error()}
 		}
@@ -561,6 +682,13 @@ func (web *webAPIHandlers) ListObjects(r *http.Request, args *ListObjectsArgs, r
 
 		nextMarker = lo.NextMarker
 
+		// Return a single page for paged listings.
+		if args.MaxKeys > 0 {
+			reply.NextMarker = lo.NextMarker
+			reply.IsTruncated = lo.IsTruncated
+			return nil
+		}
+
 		// Return when there are no more objects
 		if !lo.IsTruncated {
 			return nil
@@ -568,6 +696,314 @@ func (web *webAPIHandlers) ListObjects(r *http.Request, args *ListObjectsArgs, r
 	}
 }
 
+// Returns the number of keys listed for a page of a paged listing.
+func getWebListMaxKeys(maxKeys int) int {
+	if maxKeys > maxObjectList {
+		return maxObjectList
+	}
+	return maxKeys
+}
+
+// remoteListBoundaries split the keys under a prefix into ranges
+// which are listed concurrently from remote federated buckets. Each
+// boundary is a single character, so all the keys grouped under a
+// common prefix fall in the same range.
+var remoteListBoundaries = []string{"", "0", "5", "A", "H", "O", "V", "a", "h", "o", "v"}
+
+// Maximum number of entries returned by an unpaged listing of a remote
+// federated bucket, larger listings are truncated and continue with
+// paged listings from the returned marker.
+const maxRemoteObjectList = 10 * maxObjectList
+
+// listRemoteObjects lists the objects and common prefixes under prefix
+// in a remote federated bucket, fetching the pages of the key ranges
+// split by remoteListBoundaries concurrently. At most maxKeys entries
+// are returned in key order, reports whether the listing was truncated.
+func listRemoteObjects(core *miniogo.Core, bucket, prefix string, maxKeys int) ([]WebObjectInfo, bool, error) {
+	ranges := make([][]WebObjectInfo, len(remoteListBoundaries))
+	g := errgroup.WithNErrs(len(remoteListBoundaries))
+	for index := range remoteListBoundaries {
+		index := index
+		g.Go(func() (err error) {
+			var marker, end string
+			if remoteListBoundaries[index] != "" {
+				marker = prefix + remoteListBoundaries[index]
+			}
+			if index+1 < len(remoteListBoundaries) {
+				end = prefix + remoteListBoundaries[index+1]
+			}
+			ranges[index], err = listRemoteObjectsRange(core, bucket, prefix, marker, end, maxKeys)
+			return err
+		}, index)
+	}
+	for _, err := range g.Wait() {
+		if err != nil {
+			return nil, false, err
+		}
+	}
+
+	var objects []WebObjectInfo
+	for _, r := range ranges {
+		objects = append(objects, r...)
+	}
+	sort.Slice(objects, func(i, j int) bool {
+		return objects[i].Key < objects[j].Key
+	})
+	if len(objects) > maxKeys {
+		return objects[:maxKeys], true, nil
+	}
+	return objects, false, nil
+}
+
+// listRemoteObjectsRange lists the entries after marker up to and
+// including end, an empty end lists all the remaining entries. Stops
+// after the page on which at least maxKeys entries have been listed.
+func listRemoteObjectsRange(core *miniogo.Core, bucket, prefix, marker, end string, maxKeys int) ([]WebObjectInfo, error) {
+	var objects []WebObjectInfo
+	for {
+		result, err := core.ListObjects(bucket, prefix, marker, SlashSeparator, maxObjectList)
+		if err != nil {
+			return nil, err
+		}
+		page, pastEnd := remoteListResultToWebObjects(result, end)
+		objects = append(objects, page...)
+
+		marker = getRemoteListNextMarker(result)
+		// Return when there are no more objects in the range
+		if pastEnd || !result.IsTruncated || marker == "" || len(objects) > maxKeys {
+			return objects, nil
+		}
+	}
+}
+
+// remoteListResultToWebObjects converts the entries of a remote
+// listing up to and including end, an empty end converts all the
+// entries. Reports whether entries after end were left out.
+func remoteListResultToWebObjects(result miniogo.ListBucketResult, end string) (objects []WebObjectInfo, pastEnd bool) {
+	for _, obj := range result.Contents {
+		if end != "" && obj.Key > end {
+			pastEnd = true
+			continue
+		}
+		objects = append(objects, WebObjectInfo{
+			Key:          obj.Key,
+			LastModified: obj.LastModified,
+			Size:         obj.Size,
+			ContentType:  obj.ContentType,
+		})
+	}
+	for _, p := range result.CommonPrefixes {
+		if end != "" && p.Prefix > end {
+			pastEnd = true
+			continue
+		}
+		objects = append(objects, WebObjectInfo{
+			Key: p.Prefix,
+		})
+	}
+	return objects, pastEnd
+}
+
+// Returns the marker of the page following a remote listing result.
+func getRemoteListNextMarker(result miniogo.ListBucketResult) string {
+	if result.NextMarker != "" {
+		return result.NextMarker
+	}
+	var marker string
+	if n := len(result.Contents); n > 0 {
+		marker = result.Contents[n-1].Key
+	}
+	if n := len(result.CommonPrefixes); n > 0 && result.CommonPrefixes[n-1].Prefix > marker {
+		marker = result.CommonPrefixes[n-1].Prefix
+	}
+	return marker
+}
+
+// Maximum number of objects counted by PrefixStat, larger prefixes
+// are reported as truncated.
+const prefixStatMaxObjects = 100000
+
+// PrefixStatArgs - prefix stat args.
+type PrefixStatArgs struct {
+	BucketName string `json:"bucketName"`
+	Prefix     string `json:"prefix"`
+}
+
+// PrefixStatRep - prefix stat response.
+type PrefixStatRep struct {
+	// Number of objects under the prefix.
+	Objects int64 `json:"objects"`
+	// Cumulative size in bytes of the objects.
+	Size int64 `json:"size"`
+	// Set if the prefix holds more than the counted objects.
+	Truncated bool   `json:"truncated"`
+	UIVersion string `json:"uiVersion"`
+}
+
+// PrefixStat - counts the objects under a prefix and their cumulative
+// size, so that large downloads can be confirmed before they start.
+// Counts at most prefixStatMaxObjects objects.
+func (web *webAPIHandlers) PrefixStat(r *http.Request, args *PrefixStatArgs, reply *PrefixStatRep) error {
+	ctx := newWebContext(r, args, "webPrefixStat")
+	reply.UIVersion = browser.UIVersion
+	objectAPI := web.ObjectAPI()
+	if objectAPI == nil {
+		return toJSONError(ctx, errServerNotInitialized)
+	}
+
+	// Set prefix value for "s3:prefix" policy conditionals.
+	r.Header.Set("prefix", args.Prefix)
+
+	claims, owner, authErr := webRequestAuthenticate(r)
+	if authErr != nil {
+		if authErr != errNoAuthToken {
+			return toJSONError(ctx, authErr)
+		}
+		// Check if anonymous (non-owner) has access to list objects.
+		if !globalPolicySys.IsAllowed(policy.Args{
+			Action:          policy.ListBucketAction,
+			BucketName:      args.BucketName,
+			ConditionValues: getConditionValues(r, "", ""),
+			IsOwner:         false,
+		}) {
+			return toJSONError(ctx, errAccessDenied)
+		}
+	}
+
+	// For authenticated users apply IAM policy.
+	if authErr == nil {
+		if !globalIAMSys.IsAllowed(iampolicy.Args{
+			AccountName:     claims.Subject,
+			Action:          iampolicy.ListBucketAction,
+			BucketName:      args.BucketName,
+			ConditionValues: getConditionValues(r, "", claims.Subject),
+			IsOwner:         owner,
+		}) {
+			return toJSONError(ctx, errAccessDenied)
+		}
+	}
+
+	// Check if bucket is a reserved bucket name or invalid.
+	if isReservedOrInvalidBucket(args.BucketName, false) {
+		return toJSONError(ctx, errInvalidBucketName)
+	}
+
+	marker := ""
+	for {
+		lo, err := objectAPI.ListObjects(ctx, args.BucketName, args.Prefix, marker, "", maxObjectList)
+		if err != nil {
+			return toJSONError(ctx, err, args.BucketName)
+		}
+		for _, obj := range lo.Objects {
+			size := obj.Size
+			if crypto.IsEncrypted(obj.UserDefined) {
+				if size, err = obj.DecryptedSize(); err != nil {
+					return toJSONError(ctx, err)
+				}
+			}
+			reply.Objects++
+			reply.Size += size
+		}
+		if !lo.IsTruncated {
+			return nil
+		}
+		if reply.Objects >= prefixStatMaxObjects {
+			reply.Truncated = true
+			return nil
+		}
+		marker = lo.NextMarker
+	}
+}
+
+// SearchArgs - search objects args.
+type SearchArgs struct {
+	Query string `json:"query"`
+	// Limits the search to the bucket if set.
+	BucketName string `json:"bucketName"`
+}
+
+// WebSearchObject - an object found by Search.
+type WebSearchObject struct {
+	BucketName string `json:"bucketName"`
+	WebObjectInfo
+}
+
+// SearchRep - search objects response.
+type SearchRep struct {
+	Objects   []WebSearchObject `json:"objects"`
+	UIVersion string            `json:"uiVersion"`
+}
+
+// Search - finds the objects whose names contain all words of the
+// query in the object name search index, across all buckets readable
+// by the user unless limited to a bucket.
+func (web *webAPIHandlers) Search(r *http.Request, args *SearchArgs, reply *SearchRep) error {
+	ctx := newWebContext(r, args, "webSearch")
+	reply.UIVersion = browser.UIVersion
+	objectAPI := web.ObjectAPI()
+	if objectAPI == nil {
+		return toJSONError(ctx, errServerNotInitialized)
+	}
+
+	claims, owner, authErr := webRequestAuthenticate(r)
+	if authErr != nil {
+		return toJSONError(ctx, authErr)
+	}
+
+	if args.BucketName != "" && isReservedOrInvalidBucket(args.BucketName, false) {
+		return toJSONError(ctx, errInvalidBucketName)
+	}
+
+	keys, err := globalSearchIndex.Search(args.Query, args.BucketName, maxSearchResults)
+	if err != nil {
+		return toJSONError(ctx, err)
+	}
+	if globalIsDistXL {
+		keys = append(keys, globalNotificationSys.SearchObjects(ctx, args.Query, args.BucketName)...)
+		sort.Strings(keys)
+	}
+
+	readable := make(map[string]bool)
+	for i, key := range keys {
+		if len(reply.Objects) == maxSearchResults {
+			break
+		}
+		if i > 0 && key == keys[i-1] {
+			continue
+		}
+		bucket, object := path2BucketAndObject(key)
+		allowed, ok := readable[bucket]
+		if !ok {
+			allowed = globalIAMSys.IsAllowed(iampolicy.Args{
+				AccountName:     claims.Subject,
+				Action:          iampolicy.ListBucketAction,
+				BucketName:      bucket,
+				ConditionValues: getConditionValues(r, "", claims.Subject),
+				IsOwner:         owner,
+			})
+			readable[bucket] = allowed
+		}
+		if !allowed {
+			continue
+		}
+		// The index is as old as the last crawl.
+		objInfo, err := objectAPI.GetObjectInfo(ctx, bucket, object, ObjectOptions{})
+		if err != nil {
+			continue
+		}
+		reply.Objects = append(reply.Objects, WebSearchObject{
+			BucketName: bucket,
+			WebObjectInfo: WebObjectInfo{
+				Key:          object,
+				LastModified: objInfo.ModTime,
+				Size:         objInfo.Size,
+				ContentType:  objInfo.ContentType,
+			},
+		})
+	}
+	return nil
+}
+
 // RemoveObjectArgs - args to remove an object, JSON will look like.
 //
 // {
@@ -583,8 +1019,55 @@ type RemoveObjectArgs struct {
 	BucketName string   `json:"bucketname"` // Contains bucket name.
 }
 
+// RemoveObjectRep - remove object reply.
+type RemoveObjectRep struct {
+	// Objects which are retained and were not removed.
+	Errors    []WebRemoveObjectError `json:"errors,omitempty"`
+	UIVersion string                 `json:"uiVersion"`
+}
+
+// WebRemoveObjectError - an object which could not be removed.
+type WebRemoveObjectError struct {
+	ObjectName string `json:"objectName"`
+	Reason     string `json:"reason"`
+	// Date until which the object is retained, not set
+	// if the object is retained indefinitely.
+	RetainUntil *time.Time `json:"retainUntil,omitempty"`
+}
+
+// Reason reported for objects protected by WORM mode.
+const wormRemoveObjectReason = "Object is WORM protected and cannot be removed"
+
+// Maximum number of objects removed at a time when removing a prefix.
+const webRemoveObjectConcurrency = 32
+
+// removeObjectsConcurrently - removes the objects with at most
+// webRemoveObjectConcurrency removals at a time. Returns the error of
+// the first object, in the order of objects, which could not be
+// removed, objects not found are ignored.
+func removeObjectsConcurrently(objects []string, removeObject func(string) error) error {
+	sem := make(chan struct{}, webRemoveObjectConcurrency)
+	g := errgroup.WithNErrs(len(objects))
+	for index := range objects {
+		index := index
+		sem <- struct{}{}
+		g.Go(func() error {
+			defer func() { <-sem }()
+			return removeObject(objects[index])
+		}, index)
+	}
+	for _, err := range g.Wait() {
+		if err != nil && !isErrObjectNotFound(err) {
+			return err
+		}
+	}
+	return nil
+}
+
 // RemoveObject - removes an object, or all the objects at a given prefix.
-func (web *webAPIHandlers) RemoveObject(r *http.Request, args *RemoveObjectArgs, reply *WebGenericRep) error {
+// Objects which are retained are skipped and reported in the reply,
+// the remaining objects are still removed.
+func (web *webAPIHandlers) RemoveObject(r *http.Request, args *RemoveObjectArgs, reply *RemoveObjectRep) error {
 	ctx := newWebContext(r, args, "webRemoveObject")
 	objectAPI := web.ObjectAPI()
 	if objectAPI == nil {
@@ -592,6 +1075,11 @@ func (web *webAPIHandlers) RemoveObject(r *http.Request, args *RemoveObjectArgs,
 	}
 	listObjects := objectAPI.ListObjects
 
+	// Object names are normalized as the S3 API does for this bucket.
+	for i := range args.Objects {
+		args.Objects[i] = globalBucketKeyNormalizationSys.Normalize(args.BucketName, args.Objects[i])
+	}
+
 	claims, owner, authErr := webRequestAuthenticate(r)
 	if authErr != nil {
 		if authErr == errNoAuthToken {
@@ -621,6 +1109,10 @@ func (web *webAPIHandlers) RemoveObject(r *http.Request, args *RemoveObjectArgs,
 		return toJSONError(ctx, errInvalidBucketName)
 	}
 
+	if err := globalBucketModeSys.Check(args.BucketName, true); err != nil {
+		return toJSONError(ctx, err, args.BucketName)
+	}
+
 	reply.UIVersion = browser.UIVersion
 	if isRemoteCallRequired(ctx, args.BucketName, objectAPI) {
 		sr, err := globalDNSConfig.Get(args.BucketName)
@@ -649,22 +1141,59 @@ func (web *webAPIHandlers) RemoveObject(r *http.Request, args *RemoveObjectArgs,
 
 		for resp := range core.RemoveObjects(args.BucketName, objectsCh) {
 			if resp.Err != nil {
+				if miniogo.ToErrorResponse(resp.Err).Code == "MethodNotAllowed" {
+					reply.Errors = append(reply.Errors, WebRemoveObjectError{
+						ObjectName: resp.ObjectName,
+						Reason:     wormRemoveObjectReason,
+					})
+					continue
+				}
 				return toJSONError(ctx, resp.Err, args.BucketName, resp.ObjectName)
 			}
 		}
 		return nil
 	}
 
+	// isRetained - returns true and records the object in the
+	// reply if it exists and may not be removed.
+	isRetained := func(objectName string) bool {
+		if !globalWORMEnabled {
+			return false
+		}
+		if _, err := objectAPI.GetObjectInfo(ctx, args.BucketName, objectName, ObjectOptions{}); err != nil {
+			return false
+		}
+		reply.Errors = append(reply.Errors, WebRemoveObjectError{
+			ObjectName: objectName,
+			Reason:     wormRemoveObjectReason,
+		})
+		return true
+	}
+
+	// Objects are moved to the trash of the bucket, if enabled,
+	// before they are removed.
+	trash, trashErr := getBucketTrashConfig(ctx, objectAPI, args.BucketName)
+	if trashErr != nil && trashErr != errConfigNotFound {
+		return toJSONError(ctx, trashErr, args.BucketName)
+	}
+	removeObject := func(objectName string) error {
+		if trash != nil {
+			err := moveToTrash(ctx, objectAPI, args.BucketName, objectName)
+			if err != nil && err != errTrashEncryptedObject && !isErrObjectNotFound(err) {
+				return err
+			}
+		}
+		return deleteObject(ctx, objectAPI, web.CacheAPI(), args.BucketName, objectName, r)
+	}
+
 	var err error
 next:
 	for _, objectName := range args.Objects {
 		// If not a directory, remove the object.
 		if !hasSuffix(objectName, SlashSeparator) && objectName != "" {
-			// Deny if WORM is enabled
-			if globalWORMEnabled {
-				if _, err = objectAPI.GetObjectInfo(ctx, args.BucketName, objectName, ObjectOptions{}); err == nil {
-					return toJSONError(ctx, errMethodNotAllowed)
-				}
+			// Skip if WORM is enabled
+			if isRetained(objectName) {
+				continue
 			}
 			// Check for permissions only in the case of
 			// non-anonymous login. For anonymous login, policy has already
@@ -682,7 +1211,7 @@ next:
 				}
 			}
 
-			if err = deleteObject(ctx, objectAPI, web.CacheAPI(), args.BucketName, objectName, r); err != nil {
+			if err = removeObject(objectName); err != nil {
 				break next
 			}
 			continue
@@ -699,7 +1228,8 @@ next:
 			return toJSONError(ctx, errAccessDenied)
 		}
 
-		// For directories, list the contents recursively and remove.
+		// For directories, list the contents recursively and remove
+		// each page of objects concurrently.
 		marker := ""
 		for {
 			var lo ListObjectsInfo
@@ -708,11 +1238,19 @@ next:
 				break next
 			}
 			marker = lo.NextMarker
+			objects := make([]string, 0, len(lo.Objects))
 			for _, obj := range lo.Objects {
-				err = deleteObject(ctx, objectAPI, web.CacheAPI(), args.BucketName, obj.Name, r)
-				if err != nil {
-					break next
+				if globalWORMEnabled {
+					reply.Errors = append(reply.Errors, WebRemoveObjectError{
+						ObjectName: obj.Name,
+						Reason:     wormRemoveObjectReason,
+					})
+					continue
 				}
+				objects = append(objects, obj.Name)
+			}
+			if err = removeObjectsConcurrently(objects, removeObject); err != nil {
+				break next
 			}
 			if !lo.IsTruncated {
 				break
@@ -728,21 +1266,244 @@ next:
 	return nil
 }
 
-// LoginArgs - login arguments.
-type LoginArgs struct {
-	Username string `json:"username" form:"username"`
-	Password string `json:"password" form:"password"`
+// ListIncompleteUploadsArgs - list incomplete uploads args.
+type ListIncompleteUploadsArgs struct {
+	BucketName string `json:"bucketName"`
+	ObjectName string `json:"objectName"`
 }
 
-// LoginRep - login reply.
-type LoginRep struct {
-	Token     string `json:"token"`
-	UIVersion string `json:"uiVersion"`
+// ListIncompleteUploadsRep - list incomplete uploads response.
+type ListIncompleteUploadsRep struct {
+	Uploads   []WebUploadInfo `json:"uploads"`
+	UIVersion string          `json:"uiVersion"`
 }
 
-// Login - user login handler.
-func (web *webAPIHandlers) Login(r *http.Request, args *LoginArgs, reply *LoginRep) error {
-	ctx := newWebContext(r, args, "webLogin")
+// WebUploadInfo container for incomplete upload metadata.
+type WebUploadInfo struct {
+	// Name of the object
+	Key string `json:"name"`
+	// Upload ID of the incomplete upload.
+	UploadID string `json:"uploadId"`
+	// Date and time the upload was initiated.
+	Initiated time.Time `json:"initiated"`
+	// Size in bytes of all the uploaded parts.
+	Size int64 `json:"size"`
+}
+
+// ListIncompleteUploads - lists incomplete multipart uploads of an object,
+// or of all objects in the bucket if no object name is given, along with
+// the space consumed by their uploaded parts.
+func (web *webAPIHandlers) ListIncompleteUploads(r *http.Request, args *ListIncompleteUploadsArgs, reply *ListIncompleteUploadsRep) error {
+	ctx := newWebContext(r, args, "webListIncompleteUploads")
+	objectAPI := web.ObjectAPI()
+	if objectAPI == nil {
+		return toJSONError(ctx, errServerNotInitialized)
+	}
+
+	claims, owner, authErr := webRequestAuthenticate(r)
+	if authErr != nil {
+		return toJSONError(ctx, authErr)
+	}
+
+	// For authenticated users apply IAM policy.
+	if !globalIAMSys.IsAllowed(iampolicy.Args{
+		AccountName:     claims.Subject,
+		Action:          iampolicy.ListBucketMultipartUploadsAction,
+		BucketName:      args.BucketName,
+		ConditionValues: getConditionValues(r, "", claims.Subject),
+		IsOwner:         owner,
+	}) {
+		return toJSONError(ctx, errAccessDenied)
+	}
+
+	// Check if bucket is a reserved bucket name or invalid.
+	if isReservedOrInvalidBucket(args.BucketName, false) {
+		return toJSONError(ctx, errInvalidBucketName)
+	}
+
+	uploads, err := listIncompleteUploads(ctx, objectAPI, args.BucketName, args.ObjectName)
+	if err != nil {
+		return toJSONError(ctx, err, args.BucketName, args.ObjectName)
+	}
+nextUpload:
+	for _, upload := range uploads {
+		var size int64
+		partNumberMarker := 0
+		for {
+			lp, err := objectAPI.ListObjectParts(ctx, args.BucketName, upload.Object, upload.UploadID, partNumberMarker, maxPartsList, ObjectOptions{})
+			if err != nil {
+				if _, ok := err.(InvalidUploadID); ok {
+					// Upload completed or aborted meanwhile.
+					continue nextUpload
+				}
+				return toJSONError(ctx, err, args.BucketName, upload.Object)
+			}
+			for _, part := range lp.Parts {
+				size += part.Size
+			}
+			partNumberMarker = lp.NextPartNumberMarker
+			if !lp.IsTruncated {
+				break
+			}
+		}
+		reply.Uploads = append(reply.Uploads, WebUploadInfo{
+			Key:       upload.Object,
+			UploadID:  upload.UploadID,
+			Initiated: upload.Initiated,
+			Size:      size,
+		})
+	}
+
+	reply.UIVersion = browser.UIVersion
+	return nil
+}
+
+// AbortIncompleteUploadArgs - abort incomplete upload args, either
+// UploadID or OlderThanDays is expected.
+type AbortIncompleteUploadArgs struct {
+	BucketName    string `json:"bucketName"`
+	ObjectName    string `json:"objectName"`
+	UploadID      string `json:"uploadId"`
+	OlderThanDays int    `json:"olderThanDays"`
+}
+
+// AbortIncompleteUploadRep - abort incomplete upload response.
+type AbortIncompleteUploadRep struct {
+	Aborted   int    `json:"aborted"`
+	UIVersion string `json:"uiVersion"`
+}
+
+// AbortIncompleteUpload - aborts an incomplete multipart upload. Owner can
+// also abort all uploads of an object, or of all objects in the bucket if
+// no object name is given, older than given number of days.
+func (web *webAPIHandlers) AbortIncompleteUpload(r *http.Request, args *AbortIncompleteUploadArgs, reply *AbortIncompleteUploadRep) error {
+	ctx := newWebContext(r, args, "webAbortIncompleteUpload")
+	objectAPI := web.ObjectAPI()
+	if objectAPI == nil {
+		return toJSONError(ctx, errServerNotInitialized)
+	}
+
+	claims, owner, authErr := webRequestAuthenticate(r)
+	if authErr != nil {
+		return toJSONError(ctx, authErr)
+	}
+
+	if args.BucketName == "" || (args.ObjectName == "" && args.UploadID != "") {
+		return toJSONError(ctx, errInvalidArgument)
+	}
+	if (args.UploadID == "") == (args.OlderThanDays <= 0) {
+		return toJSONError(ctx, errInvalidArgument)
+	}
+
+	// Aborting uploads by age is allowed only for the owner.
+	if args.OlderThanDays > 0 && !owner {
+		return toJSONError(ctx, errAccessDenied)
+	}
+
+	// For authenticated users apply IAM policy.
+	if !globalIAMSys.IsAllowed(iampolicy.Args{
+		AccountName:     claims.Subject,
+		Action:          iampolicy.AbortMultipartUploadAction,
+		BucketName:      args.BucketName,
+		ConditionValues: getConditionValues(r, "", claims.Subject),
+		IsOwner:         owner,
+		ObjectName:      args.ObjectName,
+	}) {
+		return toJSONError(ctx, errAccessDenied)
+	}
+
+	// Check if bucket is a reserved bucket name or invalid.
+	if isReservedOrInvalidBucket(args.BucketName, false) {
+		return toJSONError(ctx, errInvalidBucketName)
+	}
+
+	reply.UIVersion = browser.UIVersion
+	if args.UploadID != "" {
+		if err := objectAPI.AbortMultipartUpload(ctx, args.BucketName, args.ObjectName, args.UploadID); err != nil {
+			return toJSONError(ctx, err, args.BucketName, args.ObjectName)
+		}
+		reply.Aborted = 1
+		return nil
+	}
+
+	uploads, err := listIncompleteUploads(ctx, objectAPI, args.BucketName, args.ObjectName)
+	if err != nil {
+		return toJSONError(ctx, err, args.BucketName, args.ObjectName)
+	}
+	olderThan := UTCNow().AddDate(0, 0, -args.OlderThanDays)
+	for _, upload := range uploads {
+		if upload.Initiated.After(olderThan) {
+			continue
+		}
+		if err = objectAPI.AbortMultipartUpload(ctx, args.BucketName, upload.Object, upload.UploadID); err != nil {
+			if _, ok := err.(InvalidUploadID); ok {
+				// Upload completed or aborted meanwhile.
+				continue
+			}
+			return toJSONError(ctx, err, args.BucketName, upload.Object)
+		}
+		reply.Aborted++
+	}
+	return nil
+}
+
+// bucketMultipartUploadsLister is implemented by object layers able
+// to list the incomplete uploads of all objects in a bucket.
+type bucketMultipartUploadsLister interface {
+	listBucketMultipartUploads(ctx context.Context, bucket string) ([]MultipartInfo, error)
+}
+
+// listIncompleteUploads - returns all the incomplete uploads of an object,
+// or of all objects in the bucket if object is empty.
+func listIncompleteUploads(ctx context.Context, objectAPI ObjectLayer, bucket, object string) ([]MultipartInfo, error) {
+	if object == "" {
+		lister, ok := objectAPI.(bucketMultipartUploadsLister)
+		if !ok {
+			return nil, NotImplemented{}
+		}
+		uploads, err := lister.listBucketMultipartUploads(ctx, bucket)
+		if err != nil {
+			return nil, err
+		}
+		sort.Slice(uploads, func(i, j int) bool {
+			if uploads[i].Object != uploads[j].Object {
+				return uploads[i].Object < uploads[j].Object
+			}
+			return uploads[i].Initiated.Before(uploads[j].Initiated)
+		})
+		return uploads, nil
+	}
+
+	var uploads []MultipartInfo
+	keyMarker, uploadIDMarker := "", ""
+	for {
+		lm, err := objectAPI.ListMultipartUploads(ctx, bucket, object, keyMarker, uploadIDMarker, "", maxUploadsList)
+		if err != nil {
+			return nil, err
+		}
+		uploads = append(uploads, lm.Uploads...)
+		if !lm.IsTruncated {
+			return uploads, nil
+		}
+		keyMarker, uploadIDMarker = lm.NextKeyMarker, lm.NextUploadIDMarker
+	}
+}
+
+// LoginArgs - login arguments.
+type LoginArgs struct {
+	Username string `json:"username" form:"username"`
+	Password string `json:"password" form:"password"`
+}
+
+// LoginRep - login reply.
+type LoginRep struct {
+	Token     string `json:"token"`
+	UIVersion string `json:"uiVersion"`
+}
+
+// Login - user login handler.
+func (web *webAPIHandlers) Login(r *http.Request, args *LoginArgs, reply *LoginRep) error {
+	ctx := newWebContext(r, args, "webLogin")
 	token, err := authenticateWeb(args.Username, args.Password)
 	if err != nil {
 		return toJSONError(ctx, err)
@@ -753,6 +1514,123 @@ func (web *webAPIHandlers) Login(r *http.Request, args *LoginArgs, reply *LoginR
 	return nil
 }
 
+// CreateInviteArgs - create invite args.
+type CreateInviteArgs struct {
+	BucketName string `json:"bucketName"`
+	// Canned policy granted on the bucket, one of readonly,
+	// writeonly and readwrite.
+	Policy string `json:"policy"`
+	// Expiry of the invite in seconds, a week by default.
+	Expiry int64 `json:"expiry"`
+}
+
+// CreateInviteRep - create invite reply.
+type CreateInviteRep struct {
+	UIVersion string `json:"uiVersion"`
+	// Token to accept the invite with AcceptInvite.
+	Token string `json:"token"`
+	// Link to the browser accepting the invite.
+	URL string `json:"url"`
+}
+
+// CreateInvite - creates an invite which grants a new user access to a
+// single bucket with a canned policy. Only the owner may invite users.
+func (web *webAPIHandlers) CreateInvite(r *http.Request, args *CreateInviteArgs, reply *CreateInviteRep) error {
+	ctx := newWebContext(r, args, "webCreateInvite")
+	reply.UIVersion = browser.UIVersion
+	objectAPI := web.ObjectAPI()
+	if objectAPI == nil {
+		return toJSONError(ctx, errServerNotInitialized)
+	}
+
+	_, owner, authErr := webRequestAuthenticate(r)
+	if authErr != nil {
+		return toJSONError(ctx, authErr)
+	}
+	if !owner {
+		return toJSONError(ctx, errAccessDenied)
+	}
+
+	// Deny if WORM is enabled
+	if globalWORMEnabled {
+		return toJSONError(ctx, errMethodNotAllowed)
+	}
+
+	if isReservedOrInvalidBucket(args.BucketName, false) {
+		return toJSONError(ctx, errInvalidBucketName)
+	}
+	if _, err := objectAPI.GetBucketInfo(ctx, args.BucketName); err != nil {
+		return toJSONError(ctx, err, args.BucketName)
+	}
+
+	expiry := defaultInviteExpiry
+	if args.Expiry != 0 {
+		expiry = time.Duration(args.Expiry) * time.Second
+	}
+	token, err := newInviteToken(args.BucketName, args.Policy, expiry)
+	if err != nil {
+		if err == errInvalidInvitePolicy {
+			return &json2.Error{Message: err.Error()}
+		}
+		return toJSONError(ctx, err)
+	}
+	scheme := "http"
+	if globalIsSSL {
+		scheme = "https"
+	}
+	reply.Token = token
+	reply.URL = scheme + "://" + r.Host + minioReservedBucketPath + "/login?invite=" + url.QueryEscape(token)
+	return nil
+}
+
+// AcceptInviteArgs - accept invite args.
+type AcceptInviteArgs struct {
+	Token string `json:"token"`
+}
+
+// AcceptInviteRep - accept invite reply.
+type AcceptInviteRep struct {
+	UIVersion string `json:"uiVersion"`
+	// Login token of the new user.
+	Token string `json:"token"`
+	// Credentials of the new user, they are not returned again.
+	AccessKey string `json:"accessKey"`
+	SecretKey string `json:"secretKey"`
+}
+
+// AcceptInvite - creates the user invited by an invite token created by
+// CreateInvite and logs it in. Each invite can only be accepted once.
+func (web *webAPIHandlers) AcceptInvite(r *http.Request, args *AcceptInviteArgs, reply *AcceptInviteRep) error {
+	ctx := newWebContext(r, args, "webAcceptInvite")
+	reply.UIVersion = browser.UIVersion
+	objectAPI := web.ObjectAPI()
+	if objectAPI == nil {
+		return toJSONError(ctx, errServerNotInitialized)
+	}
+
+	// Deny if WORM is enabled
+	if globalWORMEnabled {
+		return toJSONError(ctx, errMethodNotAllowed)
+	}
+
+	cred, err := acceptInvite(ctx, objectAPI, args.Token)
+	if err != nil {
+		if err == errInvalidInvite || err == errInviteAccepted {
+			return &json2.Error{Message: err.Error()}
+		}
+		return toJSONError(ctx, err)
+	}
+
+	token, err := authenticateWeb(cred.AccessKey, cred.SecretKey)
+	if err != nil {
+		return toJSONError(ctx, err)
+	}
+	reply.Token = token
+	reply.AccessKey = cred.AccessKey
+	reply.SecretKey = cred.SecretKey
+	return nil
+}
+
 // GenerateAuthReply - reply for GenerateAuth
 type GenerateAuthReply struct {
 	AccessKey string `json:"accessKey"`
@@ -762,11 +1640,17 @@ type GenerateAuthReply struct {
 
 func (web webAPIHandlers) GenerateAuth(r *http.Request, args *WebGenericArgs, reply *GenerateAuthReply) error {
 	ctx := newWebContext(r, args, "webGenerateAuth")
-	_, owner, authErr := webRequestAuthenticate(r)
+	claims, owner, authErr := webRequestAuthenticate(r)
 	if authErr != nil {
 		return toJSONError(ctx, authErr)
 	}
-	if !owner {
+	// Generated credentials are meant for new users.
+	if !globalIAMSys.IsAllowed(iampolicy.Args{
+		AccountName:     claims.Subject,
+		Action:          iampolicy.CreateUserAdminAction,
+		ConditionValues: getConditionValues(r, "", claims.Subject),
+		IsOwner:         owner,
+	}) {
 		return toJSONError(ctx, errAccessDenied)
 	}
 	cred, err := auth.GetNewCredentials()
@@ -827,16 +1711,36 @@ func (web *webAPIHandlers) SetAuth(r *http.Request, args *SetAuthArgs, reply *Se
 		globalServerConfigMu.Lock()
 		defer globalServerConfigMu.Unlock()
 
-		// Update credentials in memory
+		// IAM config data key is sealed with the root
+		// credentials, reseal it before they change.
+		if err = globalIAMSys.ResealConfigKey(creds); err != nil {
+			logger.LogIf(ctx, err)
+			return toJSONError(ctx, err)
+		}
+
+		// Update credentials in memory, URLs presigned with the
+		// previous credentials remain valid for the grace period.
 		prevCred = globalServerConfig.SetCredential(creds)
+		prevGraceCred := globalServerConfig.PrevCredential
+		globalServerConfig.PrevCredential = newGraceCredential(prevCred)
 
 		// Persist updated credentials.
 		if err = saveServerConfig(ctx, newObjectLayerFn(), globalServerConfig); err != nil {
 			// Save the current creds when failed to update.
 			globalServerConfig.SetCredential(prevCred)
+			globalServerConfig.PrevCredential = prevGraceCred
 			logger.LogIf(ctx, err)
 			return toJSONError(ctx, err)
 		}
+		globalPrevCredential.load(globalServerConfig.PrevCredential)
+
+		// Notify all other MinIO peers to reload the credentials.
+		for _, nerr := range globalNotificationSys.LoadCredentials() {
+			if nerr.Err != nil {
+				logger.GetReqInfo(ctx).SetTags("peerAddress", nerr.Host.String())
+				logger.LogIf(ctx, nerr.Err)
+			}
+		}
 
 		reply.Token, err = authenticateWeb(args.NewAccessKey, args.NewSecretKey)
 		if err != nil {
@@ -923,7 +1827,7 @@ func (web *webAPIHandlers) Upload(w http.ResponseWriter, r *http.Request) {
 	}
 	vars := mux.Vars(r)
 	bucket := vars["bucket"]
-	object := vars["object"]
+	object := globalBucketKeyNormalizationSys.Normalize(bucket, vars["object"])
 
 	claims, owner, authErr := webRequestAuthenticate(r)
 	if authErr != nil {
@@ -966,6 +1870,11 @@ func (web *webAPIHandlers) Upload(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	if err := globalBucketModeSys.Check(bucket, true); err != nil {
+		writeWebErrorResponse(w, err)
+		return
+	}
+
 	if globalAutoEncryption && !crypto.SSEC.IsRequested(r.Header) {
 		r.Header.Add(crypto.SSEHeader, crypto.SSEAlgorithmAES256)
 	}
@@ -995,7 +1904,7 @@ func (web *webAPIHandlers) Upload(w http.ResponseWriter, r *http.Request) {
 	}
 	if objectAPI.IsCompressionSupported() && isCompressible(r.Header, object) && size > 0 {
 		// Storing the compression metadata.
-		metadata[ReservedMetadataPrefix+"compression"] = compressionAlgorithmV1
+		setCompressionMetadata(metadata, bucket, object, r.Header)
 		metadata[ReservedMetadataPrefix+"actual-size"] = strconv.FormatInt(size, 10)
 
 		actualReader, err := hash.NewReader(reader, size, "", "", actualSize, globalCLIContext.StrictS3Compat)
@@ -1006,7 +1915,11 @@ func (web *webAPIHandlers) Upload(w http.ResponseWriter, r *http.Request) {
 
 		// Set compression metrics.
 		size = -1 // Since compressed size is un-predictable.
-		reader = newSnappyCompressReader(actualReader)
+		reader, err = newObjectCompressReader(actualReader, metadata)
+		if err != nil {
+			writeWebErrorResponse(w, err)
+			return
+		}
 		hashReader, err = hash.NewReader(reader, size, "", "", actualSize, globalCLIContext.StrictS3Compat)
 		if err != nil {
 			writeWebErrorResponse(w, err)
@@ -1052,9 +1965,26 @@ func (web *webAPIHandlers) Upload(w http.ResponseWriter, r *http.Request) {
 		}
 	}
 
+	// Apply the content type restrictions of the bucket, if any.
+	if err = checkUploadContentType(ctx, objectAPI, bucket, object, metadata); err != nil {
+		writeWebErrorResponse(w, err)
+		return
+	}
+
+	// Apply the anonymous upload limits of the bucket, if any.
+	anonymousUploadDone := func(err error) {}
+	if authErr == errNoAuthToken {
+		anonymousUploadDone, err = checkAnonymousUpload(ctx, objectAPI, bucket, actualSize, metadata)
+		if err != nil {
+			writeWebErrorResponse(w, err)
+			return
+		}
+	}
+
 	putObject := objectAPI.PutObject
 
 	objInfo, err := putObject(context.Background(), bucket, object, pReader, opts)
+	anonymousUploadDone(err)
 	if err != nil {
 		writeWebErrorResponse(w, err)
 		return
@@ -1097,7 +2027,7 @@ func (web *webAPIHandlers) Download(w http.ResponseWriter, r *http.Request) {
 
 	vars := mux.Vars(r)
 	bucket := vars["bucket"]
-	object := vars["object"]
+	object := globalBucketKeyNormalizationSys.Normalize(bucket, vars["object"])
 	token := r.URL.Query().Get("token")
 
 	claims, owner, authErr := webTokenAuthenticate(token)
@@ -1141,20 +2071,57 @@ func (web *webAPIHandlers) Download(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	getObjectNInfo := objectAPI.GetObjectNInfo
-	if web.CacheAPI() != nil {
-		getObjectNInfo = web.CacheAPI().GetObjectNInfo
+	if err := globalBucketModeSys.Check(bucket, false); err != nil {
+		writeWebErrorResponse(w, err)
+		return
 	}
 
-	var opts ObjectOptions
-	gr, err := getObjectNInfo(ctx, bucket, object, nil, r.Header, readLock, opts)
+	// Images are transformed if transformation parameters are given.
+	params, err := imagetransform.ParseParams(r.URL.Query())
 	if err != nil {
-		writeWebErrorResponse(w, err)
+		writeWebErrorResponse(w, errInvalidArgument)
+		return
+	}
+	if params.IsSet() {
+		web.downloadImageTransform(ctx, w, r, bucket, object, params)
 		return
 	}
-	defer gr.Close()
 
-	objInfo := gr.ObjInfo
+	getObjectNInfo := objectAPI.GetObjectNInfo
+	if web.CacheAPI() != nil {
+		getObjectNInfo = web.CacheAPI().GetObjectNInfo
+	}
+
+	// Get request range, a single range is served with 206 so that
+	// interrupted downloads can be resumed. Other ranges, multiple
+	// ranges in particular, are ignored and the object is served.
+	var rs *HTTPRangeSpec
+	if rangeHeader := r.Header.Get(xhttp.Range); rangeHeader != "" {
+		if rs, err = parseRequestRangeSpec(rangeHeader); err == errInvalidRange {
+			writeWebErrorResponse(w, err)
+			return
+		}
+	}
+
+	var opts ObjectOptions
+	gr, err := getObjectNInfo(ctx, bucket, object, rs, r.Header, readLock, opts)
+	if err == nil && rs != nil && !ifRangeMatches(r.Header, gr.ObjInfo) {
+		// The object changed since the client got its first part,
+		// it is served whole.
+		gr.Close()
+		rs = nil
+		gr, err = getObjectNInfo(ctx, bucket, object, nil, r.Header, readLock, opts)
+	}
+	if err != nil {
+		if rerr, ok := err.(InvalidRange); ok {
+			w.Header().Set(xhttp.ContentRange, fmt.Sprintf("bytes */%d", rerr.ResourceSize))
+		}
+		writeWebErrorResponse(w, err)
+		return
+	}
+	defer gr.Close()
+
+	objInfo := gr.ObjInfo
 
 	if objectAPI.IsEncryptionSupported() {
 		if _, err = DecryptObjectInfo(&objInfo, r.Header); err != nil {
@@ -1176,7 +2143,7 @@ func (web *webAPIHandlers) Download(w http.ResponseWriter, r *http.Request) {
 		}
 	}
 
-	if err = setObjectHeaders(w, objInfo, nil); err != nil {
+	if err = setObjectHeaders(w, objInfo, rs); err != nil {
 		writeWebErrorResponse(w, err)
 		return
 	}
@@ -1186,21 +2153,105 @@ func (web *webAPIHandlers) Download(w http.ResponseWriter, r *http.Request) {
 
 	setHeadGetRespHeaders(w, r.URL.Query())
 
+	statusCodeWritten := false
 	httpWriter := ioutil.WriteOnClose(w)
+	if rs != nil {
+		statusCodeWritten = true
+		w.WriteHeader(http.StatusPartialContent)
+	}
 
 	// Write object content to response body
 	if _, err = io.Copy(httpWriter, gr); err != nil {
-		if !httpWriter.HasWritten() { // write error response only if no data or headers has been written to client yet
+		if !httpWriter.HasWritten() && !statusCodeWritten { // write error response only if no data or headers has been written to client yet
 			writeWebErrorResponse(w, err)
 		}
 		return
 	}
 
 	if err = httpWriter.Close(); err != nil {
-		if !httpWriter.HasWritten() { // write error response only if no data or headers has been written to client yet
+		if !httpWriter.HasWritten() && !statusCodeWritten { // write error response only if no data or headers has been written to client yet
+			writeWebErrorResponse(w, err)
+			return
+		}
+	}
+
+	// Notify object accessed via a GET request.
+	sendEvent(eventArgs{
+		EventName:    event.ObjectAccessedGet,
+		BucketName:   bucket,
+		Object:       objInfo,
+		ReqParams:    extractReqParams(r),
+		RespElements: extractRespElements(w),
+		UserAgent:    r.UserAgent(),
+		Host:         handlers.GetSourceIP(r),
+	})
+}
+
+// downloadImageTransform - writes the image object transformed as per
+// the parameters. Transformed images are cached in the disk cache,
+// keyed by the parameters, along with the ETag of their object.
+func (web *webAPIHandlers) downloadImageTransform(ctx context.Context, w http.ResponseWriter, r *http.Request,
+	bucket, object string, params imagetransform.Params) {
+	objectAPI := web.ObjectAPI()
+	cacheAPI := web.CacheAPI()
+	getObjectInfo := objectAPI.GetObjectInfo
+	getObjectNInfo := objectAPI.GetObjectNInfo
+	if cacheAPI != nil {
+		getObjectInfo = cacheAPI.GetObjectInfo
+		getObjectNInfo = cacheAPI.GetObjectNInfo
+	}
+
+	objInfo, err := getObjectInfo(ctx, bucket, object, ObjectOptions{})
+	if err != nil {
+		writeWebErrorResponse(w, err)
+		return
+	}
+	if !imagetransform.IsSupported(objInfo.ContentType) {
+		writeWebErrorResponse(w, errImageNotTransformable)
+		return
+	}
+
+	var reader io.Reader
+	var size int64
+	var contentType string
+	var data []byte
+	if cacheAPI != nil {
+		if gr, cerr := cacheAPI.GetObjectTransform(ctx, bucket, object, objInfo.ETag, params.String()); cerr == nil {
+			defer gr.Close()
+			reader, size, contentType = gr, gr.ObjInfo.Size, gr.ObjInfo.ContentType
+		}
+	}
+	if reader == nil {
+		gr, err := getObjectNInfo(ctx, bucket, object, nil, r.Header, readLock, ObjectOptions{})
+		if err != nil {
 			writeWebErrorResponse(w, err)
 			return
 		}
+		objInfo = gr.ObjInfo
+		data, contentType, err = imagetransform.Transform(gr, params)
+		gr.Close()
+		if err != nil {
+			writeWebErrorResponse(w, errImageNotTransformable)
+			return
+		}
+		reader, size = bytes.NewReader(data), int64(len(data))
+	}
+
+	name := path.Base(objInfo.Name)
+	if params.Format != "" {
+		name = strings.TrimSuffix(name, path.Ext(name)) + "." + params.Format
+	}
+	w.Header().Set(xhttp.ContentType, contentType)
+	w.Header().Set(xhttp.ContentLength, strconv.FormatInt(size, 10))
+	w.Header().Set(xhttp.ContentDisposition, fmt.Sprintf("attachment; filename=\"%s\"", name))
+	if _, err = io.Copy(w, reader); err != nil {
+		return
+	}
+
+	// Cache the transformed image, failures only cause it to be
+	// transformed again on the next download.
+	if cacheAPI != nil && data != nil {
+		cacheAPI.PutObjectTransform(ctx, bucket, object, objInfo.ETag, params.String(), bytes.NewReader(data), size, contentType)
 	}
 
 	// Notify object accessed via a GET request.
@@ -1222,8 +2273,26 @@ type DownloadZipArgs struct {
 	Objects    []string `json:"objects"`    // can be files or sub-directories
 	Prefix     string   `json:"prefix"`     // current directory in the browser-ui
 	BucketName string   `json:"bucketname"` // bucket name.
+	// Write the archive to an object of the bucket in the background
+	// instead of the response, a DownloadZipJobRep is returned.
+	Async bool `json:"async"`
+}
+
+// DownloadZipJobRep - reply of an async DownloadZip request, the
+// download URL of the archive is returned by DownloadZipStatus once
+// the job is done.
+type DownloadZipJobRep struct {
+	JobID string `json:"jobId"`
 }
 
+const (
+	// Number of objects DownloadZip fetches ahead of the one being zipped.
+	downloadZipPrefetchCount = 4
+
+	// Objects larger than this are not prefetched by DownloadZip.
+	downloadZipPrefetchMaxSize = 4 * humanize.MiByte
+)
+
 // Takes a list of objects and creates a zip file that sent as the response body.
 func (web *webAPIHandlers) DownloadZip(w http.ResponseWriter, r *http.Request) {
 	host := handlers.GetSourceIP(r)
@@ -1248,6 +2317,12 @@ func (web *webAPIHandlers) DownloadZip(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	// Object names are normalized as the S3 API does for this bucket.
+	args.Prefix = globalBucketKeyNormalizationSys.Normalize(args.BucketName, args.Prefix)
+	for i := range args.Objects {
+		args.Objects[i] = globalBucketKeyNormalizationSys.Normalize(args.BucketName, args.Objects[i])
+	}
+
 	token := r.URL.Query().Get("token")
 	claims, owner, authErr := webTokenAuthenticate(token)
 	if authErr != nil {
@@ -1293,6 +2368,17 @@ func (web *webAPIHandlers) DownloadZip(w http.ResponseWriter, r *http.Request) {
 		writeWebErrorResponse(w, errInvalidBucketName)
 		return
 	}
+
+	if err := globalBucketModeSys.Check(args.BucketName, false); err != nil {
+		writeWebErrorResponse(w, err)
+		return
+	}
+
+	if args.Async {
+		web.startDownloadZipJob(w, r, args, claims.Subject, owner, authErr)
+		return
+	}
+
 	getObjectNInfo := objectAPI.GetObjectNInfo
 	if web.CacheAPI() != nil {
 		getObjectNInfo = web.CacheAPI().GetObjectNInfo
@@ -1303,174 +2389,311 @@ func (web *webAPIHandlers) DownloadZip(w http.ResponseWriter, r *http.Request) {
 	archive := zip.NewWriter(w)
 	defer archive.Close()
 
+	// Fetches an object ahead of it being zipped, objects up to
+	// downloadZipPrefetchMaxSize are read into memory, larger
+	// objects are streamed once it is their turn.
+	prefetch := func(objectName string) (entry zipEntry) {
+		entry.objectName = objectName
+		var opts ObjectOptions
+		gr, err := getObjectNInfo(ctx, args.BucketName, objectName, nil, r.Header, readLock, opts)
+		if err != nil {
+			entry.err = err
+			return entry
+		}
+		defer gr.Close()
+		if gr.ObjInfo.Size > downloadZipPrefetchMaxSize {
+			return entry
+		}
+
+		var buffer bytes.Buffer
+		if _, err = buffer.ReadFrom(gr); err != nil {
+			// Retry by streaming the object.
+			logger.LogIf(ctx, err)
+			return entry
+		}
+		entry.info = gr.ObjInfo
+		entry.data = buffer.Bytes()
+		entry.prefetched = true
+		return entry
+	}
+
+	// Queue objects to be zipped in order, while up to
+	// downloadZipPrefetchCount objects are prefetched
+	// concurrently with the one being written.
+	doneCh := make(chan struct{})
+	defer close(doneCh)
+	entryCh := make(chan chan zipEntry, downloadZipPrefetchCount)
+	go func() {
+		defer close(entryCh)
+		enqueue := func(entry func() zipEntry) bool {
+			resultCh := make(chan zipEntry, 1)
+			select {
+			case entryCh <- resultCh:
+			case <-doneCh:
+				return false
+			}
+			go func() {
+				resultCh <- entry()
+			}()
+			return true
+		}
+		for _, object := range args.Objects {
+			if !hasSuffix(object, SlashSeparator) {
+				// If not a directory, compress the file and write it to response.
+				objectName := pathJoin(args.Prefix, object)
+				if !enqueue(func() zipEntry { return prefetch(objectName) }) {
+					return
+				}
+				continue
+			}
+
+			// For directories, list the contents recursively and write the objects as compressed
+			// date to the response writer.
+			marker := ""
+			for {
+				lo, err := listObjects(ctx, args.BucketName, pathJoin(args.Prefix, object), marker, "", 1000)
+				if err != nil {
+					enqueue(func() zipEntry { return zipEntry{err: err} })
+					return
+				}
+				marker = lo.NextMarker
+				for _, obj := range lo.Objects {
+					objectName := obj.Name
+					if !enqueue(func() zipEntry { return prefetch(objectName) }) {
+						return
+					}
+				}
+				if !lo.IsTruncated {
+					break
+				}
+			}
+		}
+	}()
+
 	var length int64
-	for _, object := range args.Objects {
-		// Writes compressed object file to the response.
-		zipit := func(objectName string) error {
+	// Writes compressed object file to the response.
+	zipit := func(entry zipEntry) error {
+		objectName := entry.objectName
+		info := entry.info
+		var reader io.Reader = bytes.NewReader(entry.data)
+		var err error
+		if !entry.prefetched {
 			var opts ObjectOptions
-			gr, err := getObjectNInfo(ctx, args.BucketName, objectName, nil, r.Header, readLock, opts)
+			var gr *GetObjectReader
+			gr, err = getObjectNInfo(ctx, args.BucketName, objectName, nil, r.Header, readLock, opts)
 			if err != nil {
 				return err
 			}
 			defer gr.Close()
 
-			info := gr.ObjInfo
+			info = gr.ObjInfo
+			reader = gr
+		}
 
-			length = info.Size
-			if objectAPI.IsEncryptionSupported() {
-				if _, err = DecryptObjectInfo(&info, r.Header); err != nil {
-					writeWebErrorResponse(w, err)
-					return err
-				}
-				if crypto.IsEncrypted(info.UserDefined) {
-					length, _ = info.DecryptedSize()
-				}
+		length = info.Size
+		// The SSE-C client key, if any, applies to the encrypted objects
+		// of the archive, the other objects are zipped as they are.
+		if objectAPI.IsEncryptionSupported() && crypto.IsEncrypted(info.UserDefined) {
+			if _, err = DecryptObjectInfo(&info, r.Header); err != nil {
+				writeWebErrorResponse(w, err)
+				return err
 			}
-			length = info.Size
-			var actualSize int64
-			if info.IsCompressed() {
-				// Read the decompressed size from the meta.json.
-				actualSize = info.GetActualSize()
-				// Set the info.Size to the actualSize.
-				info.Size = actualSize
-			}
-			header := &zip.FileHeader{
-				Name:               strings.TrimPrefix(objectName, args.Prefix),
-				Method:             zip.Deflate,
-				UncompressedSize64: uint64(length),
-				UncompressedSize:   uint32(length),
-			}
-			zipWriter, err := archive.CreateHeader(header)
+			length, _ = info.DecryptedSize()
+		}
+		var actualSize int64
+		if info.IsCompressed() {
+			// Read the decompressed size from the meta.json.
+			actualSize = info.GetActualSize()
+			// Set the info.Size to the actualSize.
+			info.Size = actualSize
+		}
+		header := &zip.FileHeader{
+			Name:               strings.TrimPrefix(objectName, args.Prefix),
+			Method:             zip.Deflate,
+			UncompressedSize64: uint64(length),
+			UncompressedSize:   uint32(length),
+		}
+		zipWriter, err := archive.CreateHeader(header)
+		if err != nil {
+			writeWebErrorResponse(w, errUnexpected)
+			return err
+		}
+		var startOffset int64
+		var writer io.Writer
+
+		if info.IsCompressed() {
+			// The decompress metrics are set.
+			snappyStartOffset := 0
+			snappyLength := actualSize
+
+			// Open a pipe for compression
+			// Where compressWriter is actually passed to the getObject
+			decompressReader, compressWriter := io.Pipe()
+			decReader, err := newObjectDecompressReader(decompressReader, info.UserDefined)
 			if err != nil {
-				writeWebErrorResponse(w, errUnexpected)
+				writeWebErrorResponse(w, err)
 				return err
 			}
-			var startOffset int64
-			var writer io.Writer
-
-			if info.IsCompressed() {
-				// The decompress metrics are set.
-				snappyStartOffset := 0
-				snappyLength := actualSize
-
-				// Open a pipe for compression
-				// Where compressWriter is actually passed to the getObject
-				decompressReader, compressWriter := io.Pipe()
-				snappyReader := snappy.NewReader(decompressReader)
-
-				// The limit is set to the actual size.
-				responseWriter := ioutil.LimitedWriter(zipWriter, int64(snappyStartOffset), snappyLength)
-				wg.Add(1) //For closures.
-				go func() {
-					defer wg.Done()
-					// Finally, writes to the client.
-					_, perr := io.Copy(responseWriter, snappyReader)
-
-					// Close the compressWriter if the data is read already.
-					// Closing the pipe, releases the writer passed to the getObject.
-					compressWriter.CloseWithError(perr)
-				}()
-				writer = compressWriter
-			} else {
-				writer = zipWriter
-			}
-			if objectAPI.IsEncryptionSupported() && crypto.S3.IsEncrypted(info.UserDefined) {
-				// Response writer should be limited early on for decryption upto required length,
-				// additionally also skipping mod(offset)64KiB boundaries.
-				writer = ioutil.LimitedWriter(writer, startOffset%(64*1024), length)
-				writer, _, length, err = DecryptBlocksRequest(writer, r,
-					args.BucketName, objectName, startOffset, length, info, false)
-				if err != nil {
-					writeWebErrorResponse(w, err)
-					return err
-				}
-			}
-			httpWriter := ioutil.WriteOnClose(writer)
 
-			// Write object content to response body
-			if _, err = io.Copy(httpWriter, gr); err != nil {
-				httpWriter.Close()
-				if info.IsCompressed() {
-					// Wait for decompression go-routine to retire.
-					wg.Wait()
-				}
-				if !httpWriter.HasWritten() { // write error response only if no data or headers has been written to client yet
-					writeWebErrorResponse(w, err)
-				}
+			// The limit is set to the actual size.
+			responseWriter := ioutil.LimitedWriter(zipWriter, int64(snappyStartOffset), snappyLength)
+			wg.Add(1) //For closures.
+			go func() {
+				defer wg.Done()
+				// Finally, writes to the client.
+				_, perr := io.Copy(responseWriter, decReader)
+				decReader.Close()
+
+				// Close the compressWriter if the data is read already.
+				// Closing the pipe, releases the writer passed to the getObject.
+				compressWriter.CloseWithError(perr)
+			}()
+			writer = compressWriter
+		} else {
+			writer = zipWriter
+		}
+		if objectAPI.IsEncryptionSupported() && crypto.S3.IsEncrypted(info.UserDefined) {
+			// Response writer should be limited early on for decryption upto required length,
+			// additionally also skipping mod(offset)64KiB boundaries.
+			writer = ioutil.LimitedWriter(writer, startOffset%(64*1024), length)
+			writer, _, length, err = DecryptBlocksRequest(writer, r,
+				args.BucketName, objectName, startOffset, length, info, false)
+			if err != nil {
+				writeWebErrorResponse(w, err)
 				return err
 			}
+		}
+		httpWriter := ioutil.WriteOnClose(writer)
 
-			if err = httpWriter.Close(); err != nil {
-				if !httpWriter.HasWritten() { // write error response only if no data has been written to client yet
-					writeWebErrorResponse(w, err)
-					return err
-				}
-			}
+		// Write object content to response body
+		if _, err = io.Copy(httpWriter, reader); err != nil {
+			httpWriter.Close()
 			if info.IsCompressed() {
 				// Wait for decompression go-routine to retire.
 				wg.Wait()
 			}
-
-			// Notify object accessed via a GET request.
-			sendEvent(eventArgs{
-				EventName:    event.ObjectAccessedGet,
-				BucketName:   args.BucketName,
-				Object:       info,
-				ReqParams:    extractReqParams(r),
-				RespElements: extractRespElements(w),
-				UserAgent:    r.UserAgent(),
-				Host:         host,
-			})
-
-			return nil
+			if !httpWriter.HasWritten() { // write error response only if no data or headers has been written to client yet
+				writeWebErrorResponse(w, err)
+			}
+			return err
 		}
 
-		if !hasSuffix(object, SlashSeparator) {
-			// If not a directory, compress the file and write it to response.
-			err := zipit(pathJoin(args.Prefix, object))
-			if err != nil {
-				return
+		if err = httpWriter.Close(); err != nil {
+			if !httpWriter.HasWritten() { // write error response only if no data has been written to client yet
+				writeWebErrorResponse(w, err)
+				return err
 			}
-			continue
+		}
+		if info.IsCompressed() {
+			// Wait for decompression go-routine to retire.
+			wg.Wait()
 		}
 
-		// For directories, list the contents recursively and write the objects as compressed
-		// date to the response writer.
-		marker := ""
-		for {
-			lo, err := listObjects(ctx, args.BucketName, pathJoin(args.Prefix, object), marker, "", 1000)
-			if err != nil {
-				return
-			}
-			marker = lo.NextMarker
-			for _, obj := range lo.Objects {
-				err = zipit(obj.Name)
-				if err != nil {
-					return
-				}
+		// Notify object accessed via a GET request.
+		sendEvent(eventArgs{
+			EventName:    event.ObjectAccessedGet,
+			BucketName:   args.BucketName,
+			Object:       info,
+			ReqParams:    extractReqParams(r),
+			RespElements: extractRespElements(w),
+			UserAgent:    r.UserAgent(),
+			Host:         host,
+		})
+
+		return nil
+	}
+
+	var zipped int
+	for resultCh := range entryCh {
+		entry := <-resultCh
+		if entry.err != nil {
+			if entry.objectName != "" {
+				logger.GetReqInfo(ctx).AppendTags("object", entry.objectName)
 			}
-			if !lo.IsTruncated {
-				break
+			logger.LogIf(ctx, entry.err)
+			if zipped == 0 {
+				// Nothing has been written to the client yet.
+				writeWebErrorResponse(w, entry.err)
 			}
+			return
+		}
+		if err := zipit(entry); err != nil {
+			return
 		}
+		zipped++
 	}
 }
 
-// GetBucketPolicyArgs - get bucket policy args.
-type GetBucketPolicyArgs struct {
-	BucketName string `json:"bucketName"`
-	Prefix     string `json:"prefix"`
+// startDownloadZipJob - starts a job writing the archive of a
+// DownloadZip request to an object under downloadZipJobPrefix, which
+// is downloaded through a presigned URL, avoiding timeouts of very
+// large archives. Anonymous requests cannot presign the URL and are
+// rejected.
+func (web *webAPIHandlers) startDownloadZipJob(w http.ResponseWriter, r *http.Request, args DownloadZipArgs,
+	accountName string, owner bool, authErr error) {
+	if authErr != nil {
+		writeWebErrorResponse(w, errAuthentication)
+		return
+	}
+
+	// The archive cannot be removed once expired if WORM is enabled.
+	if globalWORMEnabled {
+		writeWebErrorResponse(w, errMethodNotAllowed)
+		return
+	}
+
+	if err := globalBucketModeSys.Check(args.BucketName, true); err != nil {
+		writeWebErrorResponse(w, err)
+		return
+	}
+
+	// The archive is downloaded with the credentials of the user.
+	if !globalIAMSys.IsAllowed(iampolicy.Args{
+		AccountName:     accountName,
+		Action:          iampolicy.GetObjectAction,
+		BucketName:      args.BucketName,
+		ConditionValues: getConditionValues(r, "", accountName),
+		IsOwner:         owner,
+		ObjectName:      downloadZipJobPrefix,
+	}) {
+		writeWebErrorResponse(w, errAuthentication)
+		return
+	}
+
+	id, err := globalBatchJobs.StartZip(GlobalContext, web.ObjectAPI(), args)
+	if err != nil {
+		writeWebErrorResponse(w, err)
+		return
+	}
+	data, err := json.Marshal(DownloadZipJobRep{JobID: id})
+	if err != nil {
+		writeWebErrorResponse(w, err)
+		return
+	}
+	writeSuccessResponseJSON(w, data)
 }
 
-// GetBucketPolicyRep - get bucket policy reply.
-type GetBucketPolicyRep struct {
-	UIVersion string                     `json:"uiVersion"`
-	Policy    miniogopolicy.BucketPolicy `json:"policy"`
+// DownloadZipStatusArgs - download zip status args.
+type DownloadZipStatusArgs struct {
+	JobID string `json:"jobId"`
+	// Host header required for signed headers.
+	HostName string `json:"host"`
 }
 
-// GetBucketPolicy - get bucket policy for the requested prefix.
-func (web *webAPIHandlers) GetBucketPolicy(r *http.Request, args *GetBucketPolicyArgs, reply *GetBucketPolicyRep) error {
-	ctx := newWebContext(r, args, "webGetBucketPolicy")
+// DownloadZipStatusRep - download zip status reply.
+type DownloadZipStatusRep struct {
+	UIVersion string                `json:"uiVersion"`
+	Report    madmin.BatchJobReport `json:"report"`
+	// Presigned URL of the archive, set once it is written, until it
+	// expires.
+	URL string `json:"url,omitempty"`
+}
+
+// DownloadZipStatus - returns the progress of an async DownloadZip
+// request, along with the download URL of its archive once done.
+func (web *webAPIHandlers) DownloadZipStatus(r *http.Request, args *DownloadZipStatusArgs, reply *DownloadZipStatusRep) error {
+	ctx := newWebContext(r, args, "webDownloadZipStatus")
+	reply.UIVersion = browser.UIVersion
 	objectAPI := web.ObjectAPI()
 	if objectAPI == nil {
 		return toJSONError(ctx, errServerNotInitialized)
@@ -1481,68 +2704,306 @@ func (web *webAPIHandlers) GetBucketPolicy(r *http.Request, args *GetBucketPolic
 		return toJSONError(ctx, authErr)
 	}
 
-	// For authenticated users apply IAM policy.
+	if args.JobID == "" {
+		return toJSONError(ctx, errInvalidArgument)
+	}
+
+	statuses := globalBatchJobs.Status()
+	if globalIsDistXL {
+		statuses = append(statuses, globalNotificationSys.BatchJobsStatus(ctx)...)
+	}
+	report, ok := getBatchJobReport(args.JobID, statuses)
+	if !ok || report.Operation != madmin.BatchOperationZip {
+		return &json2.Error{Message: "The specified download zip job does not exist."}
+	}
+
+	// Only users allowed to download the archive see the job.
+	bucket := report.Nodes[0].Bucket
+	object := getDownloadZipJobObject(args.JobID)
 	if !globalIAMSys.IsAllowed(iampolicy.Args{
 		AccountName:     claims.Subject,
-		Action:          iampolicy.GetBucketPolicyAction,
-		BucketName:      args.BucketName,
+		Action:          iampolicy.GetObjectAction,
+		BucketName:      bucket,
 		ConditionValues: getConditionValues(r, "", claims.Subject),
 		IsOwner:         owner,
+		ObjectName:      object,
 	}) {
 		return toJSONError(ctx, errAccessDenied)
 	}
 
-	// Check if bucket is a reserved bucket name or invalid.
-	if isReservedOrInvalidBucket(args.BucketName, false) {
-		return toJSONError(ctx, errInvalidBucketName)
+	reply.Report = report
+	if report.Running || report.Canceled || report.Nodes[0].Error != "" {
+		return nil
+	}
+	expiry := int64(report.EndTime.Add(downloadZipJobExpiry).Sub(UTCNow()) / time.Second)
+	if expiry <= 0 {
+		return nil
 	}
 
-	var policyInfo = &miniogopolicy.BucketAccessPolicy{Version: "2012-10-17"}
-	if isRemoteCallRequired(ctx, args.BucketName, objectAPI) {
-		sr, err := globalDNSConfig.Get(args.BucketName)
-		if err != nil {
-			if err == dns.ErrNoEntriesFound {
-				return toJSONError(ctx, BucketNotFound{
-					Bucket: args.BucketName,
-				}, args.BucketName)
-			}
-			return toJSONError(ctx, err, args.BucketName)
-		}
-		client, rerr := getRemoteInstanceClient(r, getHostFromSrv(sr))
-		if rerr != nil {
-			return toJSONError(ctx, rerr, args.BucketName)
-		}
-		policyStr, err := client.GetBucketPolicy(args.BucketName)
-		if err != nil {
-			return toJSONError(ctx, rerr, args.BucketName)
-		}
-		bucketPolicy, err := policy.ParseConfig(strings.NewReader(policyStr), args.BucketName)
-		if err != nil {
-			return toJSONError(ctx, rerr, args.BucketName)
-		}
-		policyInfo, err = PolicyToBucketAccessPolicy(bucketPolicy)
-		if err != nil {
-			// This should not happen.
-			return toJSONError(ctx, err, args.BucketName)
+	var creds auth.Credentials
+	if !owner {
+		creds, ok = globalIAMSys.GetUser(claims.Subject)
+		if !ok {
+			return toJSONError(ctx, errInvalidAccessKeyID)
 		}
 	} else {
-		bucketPolicy, err := objectAPI.GetBucketPolicy(ctx, args.BucketName)
-		if err != nil {
-			if _, ok := err.(BucketPolicyNotFound); !ok {
-				return toJSONError(ctx, err, args.BucketName)
-			}
-			return err
-		}
+		creds = globalServerConfig.GetCredential()
+	}
+	reply.URL = presignedGet(args.HostName, bucket, object, expiry, nil, creds, globalServerConfig.GetRegion())
+	return nil
+}
 
-		policyInfo, err = PolicyToBucketAccessPolicy(bucketPolicy)
-		if err != nil {
+// zipEntry - an object queued to be added to a DownloadZip
+// archive, holding its content if it was prefetched.
+type zipEntry struct {
+	objectName string
+	info       ObjectInfo
+	data       []byte
+	prefetched bool
+	err        error
+}
+
+// exportRecord - an object of a listing streamed by Export.
+type exportRecord struct {
+	Key          string `json:"key"`
+	Size         int64  `json:"size"`
+	LastModified string `json:"lastModified"`
+	ETag         string `json:"etag"`
+	ContentType  string `json:"contentType"`
+}
+
+// Export - streams the listing of all objects under a prefix of a
+// bucket, as CSV by default or as newline delimited JSON with
+// format=json.
+func (web *webAPIHandlers) Export(w http.ResponseWriter, r *http.Request) {
+	ctx := newContext(r, w, "WebExport")
+
+	defer logger.AuditLog(w, r, "WebExport", mustGetClaimsFromToken(r))
+
+	objectAPI := web.ObjectAPI()
+	if objectAPI == nil {
+		writeWebErrorResponse(w, errServerNotInitialized)
+		return
+	}
+
+	vars := mux.Vars(r)
+	bucket := vars["bucket"]
+	prefix := r.URL.Query().Get("prefix")
+	format := r.URL.Query().Get("format")
+	token := r.URL.Query().Get("token")
+
+	claims, owner, authErr := webTokenAuthenticate(token)
+	if authErr != nil {
+		if authErr == errNoAuthToken {
+			// Check if anonymous (non-owner) has access to list objects.
+			if !globalPolicySys.IsAllowed(policy.Args{
+				Action:          policy.ListBucketAction,
+				BucketName:      bucket,
+				ConditionValues: getConditionValues(r, "", ""),
+				IsOwner:         false,
+			}) {
+				writeWebErrorResponse(w, errAuthentication)
+				return
+			}
+		} else {
+			writeWebErrorResponse(w, authErr)
+			return
+		}
+	}
+
+	// For authenticated users apply IAM policy.
+	if authErr == nil {
+		if !globalIAMSys.IsAllowed(iampolicy.Args{
+			AccountName:     claims.Subject,
+			Action:          iampolicy.ListBucketAction,
+			BucketName:      bucket,
+			ConditionValues: getConditionValues(r, "", claims.Subject),
+			IsOwner:         owner,
+		}) {
+			writeWebErrorResponse(w, errAuthentication)
+			return
+		}
+	}
+
+	// Check if bucket is a reserved bucket name or invalid.
+	if isReservedOrInvalidBucket(bucket, false) {
+		writeWebErrorResponse(w, errInvalidBucketName)
+		return
+	}
+
+	var contentType, extension string
+	switch format {
+	case "", "csv":
+		contentType, extension = "text/csv", "csv"
+	case "json":
+		contentType, extension = "application/x-ndjson", "json"
+	default:
+		writeWebErrorResponse(w, errInvalidArgument)
+		return
+	}
+
+	listObjects := objectAPI.ListObjects
+
+	var csvWriter *csv.Writer
+	var jsonEncoder *json.Encoder
+	writeRecord := func(record exportRecord) error {
+		if jsonEncoder != nil {
+			return jsonEncoder.Encode(record)
+		}
+		return csvWriter.Write([]string{record.Key, strconv.FormatInt(record.Size, 10), record.LastModified, record.ETag, record.ContentType})
+	}
+
+	// Headers are only written once the first page is listed, so
+	// that a listing error can still be reported to the client.
+	var written bool
+	marker := ""
+	for {
+		lo, err := listObjects(ctx, bucket, prefix, marker, "", maxObjectList)
+		if err != nil {
+			if !written {
+				writeWebErrorResponse(w, err)
+				return
+			}
+			// The listing is cut short, nothing else can be done.
+			logger.LogIf(ctx, err)
+			return
+		}
+		if !written {
+			w.Header().Set(xhttp.ContentType, contentType)
+			w.Header().Set(xhttp.ContentDisposition, fmt.Sprintf("attachment; filename=\"%s.%s\"", bucket, extension))
+			w.WriteHeader(http.StatusOK)
+			if format == "json" {
+				jsonEncoder = json.NewEncoder(w)
+			} else {
+				csvWriter = csv.NewWriter(w)
+				if err = csvWriter.Write([]string{"key", "size", "lastModified", "etag", "contentType"}); err != nil {
+					return
+				}
+			}
+			written = true
+		}
+		for _, obj := range lo.Objects {
+			if err = writeRecord(exportRecord{
+				Key:          obj.Name,
+				Size:         obj.Size,
+				LastModified: obj.ModTime.UTC().Format(time.RFC3339),
+				ETag:         obj.ETag,
+				ContentType:  obj.ContentType,
+			}); err != nil {
+				return
+			}
+		}
+		if csvWriter != nil {
+			csvWriter.Flush()
+			if err = csvWriter.Error(); err != nil {
+				return
+			}
+		}
+		if flusher, ok := w.(http.Flusher); ok {
+			flusher.Flush()
+		}
+		if !lo.IsTruncated {
+			return
+		}
+		marker = lo.NextMarker
+	}
+}
+
+// GetBucketPolicyArgs - get bucket policy args.
+type GetBucketPolicyArgs struct {
+	BucketName string `json:"bucketName"`
+	Prefix     string `json:"prefix"`
+}
+
+// GetBucketPolicyRep - get bucket policy reply.
+type GetBucketPolicyRep struct {
+	UIVersion string                     `json:"uiVersion"`
+	Policy    miniogopolicy.BucketPolicy `json:"policy"`
+	// Client IP networks anonymous access to the bucket is restricted to.
+	AllowedIPs []string `json:"allowedIPs,omitempty"`
+	DeniedIPs  []string `json:"deniedIPs,omitempty"`
+}
+
+// GetBucketPolicy - get bucket policy for the requested prefix.
+func (web *webAPIHandlers) GetBucketPolicy(r *http.Request, args *GetBucketPolicyArgs, reply *GetBucketPolicyRep) error {
+	ctx := newWebContext(r, args, "webGetBucketPolicy")
+	objectAPI := web.ObjectAPI()
+	if objectAPI == nil {
+		return toJSONError(ctx, errServerNotInitialized)
+	}
+
+	claims, owner, authErr := webRequestAuthenticate(r)
+	if authErr != nil {
+		return toJSONError(ctx, authErr)
+	}
+
+	// For authenticated users apply IAM policy.
+	if !globalIAMSys.IsAllowed(iampolicy.Args{
+		AccountName:     claims.Subject,
+		Action:          iampolicy.GetBucketPolicyAction,
+		BucketName:      args.BucketName,
+		ConditionValues: getConditionValues(r, "", claims.Subject),
+		IsOwner:         owner,
+	}) {
+		return toJSONError(ctx, errAccessDenied)
+	}
+
+	// Check if bucket is a reserved bucket name or invalid.
+	if isReservedOrInvalidBucket(args.BucketName, false) {
+		return toJSONError(ctx, errInvalidBucketName)
+	}
+
+	var policyInfo = &miniogopolicy.BucketAccessPolicy{Version: "2012-10-17"}
+	if isRemoteCallRequired(ctx, args.BucketName, objectAPI) {
+		sr, err := globalDNSConfig.Get(args.BucketName)
+		if err != nil {
+			if err == dns.ErrNoEntriesFound {
+				return toJSONError(ctx, BucketNotFound{
+					Bucket: args.BucketName,
+				}, args.BucketName)
+			}
+			return toJSONError(ctx, err, args.BucketName)
+		}
+		client, rerr := getRemoteInstanceClient(r, getHostFromSrv(sr))
+		if rerr != nil {
+			return toJSONError(ctx, rerr, args.BucketName)
+		}
+		policyStr, err := client.GetBucketPolicy(args.BucketName)
+		if err != nil {
+			return toJSONError(ctx, rerr, args.BucketName)
+		}
+		bucketPolicy, err := policy.ParseConfig(strings.NewReader(policyStr), args.BucketName)
+		if err != nil {
+			return toJSONError(ctx, rerr, args.BucketName)
+		}
+		policyInfo, err = PolicyToBucketAccessPolicy(bucketPolicy)
+		if err != nil {
+			// This should not happen.
+			return toJSONError(ctx, err, args.BucketName)
+		}
+	} else {
+		bucketPolicy, err := objectAPI.GetBucketPolicy(ctx, args.BucketName)
+		if err != nil {
+			if _, ok := err.(BucketPolicyNotFound); !ok {
+				return toJSONError(ctx, err, args.BucketName)
+			}
+			return err
+		}
+
+		policyInfo, err = PolicyToBucketAccessPolicy(bucketPolicy)
+		if err != nil {
 			// This should not happen.
 			return toJSONError(ctx, err, args.BucketName)
 		}
 	}
 
+	statements, restricted := splitIPRestrictionStatements(policyInfo.Statements)
+	restrictions := getBucketIPRestrictions(restricted)
+
 	reply.UIVersion = browser.UIVersion
-	reply.Policy = miniogopolicy.GetPolicy(policyInfo.Statements, args.BucketName, args.Prefix)
+	reply.Policy = miniogopolicy.GetPolicy(statements, args.BucketName, args.Prefix)
+	reply.AllowedIPs = restrictions.AllowedIPs
+	reply.DeniedIPs = restrictions.DeniedIPs
 
 	return nil
 }
@@ -1563,6 +3024,9 @@ type BucketAccessPolicy struct {
 type ListAllBucketPoliciesRep struct {
 	UIVersion string               `json:"uiVersion"`
 	Policies  []BucketAccessPolicy `json:"policies"`
+	// Client IP networks anonymous access to the bucket is restricted to.
+	AllowedIPs []string `json:"allowedIPs,omitempty"`
+	DeniedIPs  []string `json:"deniedIPs,omitempty"`
 }
 
 // ListAllBucketPolicies - get all bucket policy.
@@ -1632,8 +3096,13 @@ func (web *webAPIHandlers) ListAllBucketPolicies(r *http.Request, args *ListAllB
 		}
 	}
 
+	statements, restricted := splitIPRestrictionStatements(policyInfo.Statements)
+	restrictions := getBucketIPRestrictions(restricted)
+
 	reply.UIVersion = browser.UIVersion
-	for prefix, policy := range miniogopolicy.GetPolicies(policyInfo.Statements, args.BucketName, "") {
+	reply.AllowedIPs = restrictions.AllowedIPs
+	reply.DeniedIPs = restrictions.DeniedIPs
+	for prefix, policy := range miniogopolicy.GetPolicies(statements, args.BucketName, "") {
 		bucketName, objectPrefix := urlPath2BucketObjectName(prefix)
 		objectPrefix = strings.TrimSuffix(objectPrefix, "*")
 		reply.Policies = append(reply.Policies, BucketAccessPolicy{
@@ -1656,6 +3125,30 @@ type SetBucketPolicyWebArgs struct {
 // SetBucketPolicy - set bucket policy.
 func (web *webAPIHandlers) SetBucketPolicy(r *http.Request, args *SetBucketPolicyWebArgs, reply *WebGenericRep) error {
 	ctx := newWebContext(r, args, "webSetBucketPolicy")
+	return web.setBucketPolicy(ctx, r, args, nil, reply)
+}
+
+// SetBucketPolicyWithConditionsArgs - set bucket policy with client IP
+// conditions args. The conditions apply to anonymous access to the
+// whole bucket, empty lists remove them.
+type SetBucketPolicyWithConditionsArgs struct {
+	SetBucketPolicyWebArgs
+	AllowedIPs []string `json:"allowedIPs"`
+	DeniedIPs  []string `json:"deniedIPs"`
+}
+
+// SetBucketPolicyWithConditions - set bucket policy along with the client
+// IP addresses and networks anonymous access is allowed from and denied from.
+func (web *webAPIHandlers) SetBucketPolicyWithConditions(r *http.Request, args *SetBucketPolicyWithConditionsArgs, reply *WebGenericRep) error {
+	ctx := newWebContext(r, args, "webSetBucketPolicyWithConditions")
+	restrictions := &bucketIPRestrictions{AllowedIPs: args.AllowedIPs, DeniedIPs: args.DeniedIPs}
+	return web.setBucketPolicy(ctx, r, &args.SetBucketPolicyWebArgs, restrictions, reply)
+}
+
+// setBucketPolicy - sets the canned policy of the prefix, IP restrictions
+// of the bucket are replaced if given and kept otherwise.
+func (web *webAPIHandlers) setBucketPolicy(ctx context.Context, r *http.Request, args *SetBucketPolicyWebArgs,
+	restrictions *bucketIPRestrictions, reply *WebGenericRep) error {
 	objectAPI := web.ObjectAPI()
 	reply.UIVersion = browser.UIVersion
 
@@ -1691,6 +3184,16 @@ func (web *webAPIHandlers) SetBucketPolicy(r *http.Request, args *SetBucketPolic
 		}
 	}
 
+	if restrictions != nil {
+		var err error
+		if restrictions.AllowedIPs, err = parseIPNetworks(restrictions.AllowedIPs); err != nil {
+			return &json2.Error{Message: err.Error()}
+		}
+		if restrictions.DeniedIPs, err = parseIPNetworks(restrictions.DeniedIPs); err != nil {
+			return &json2.Error{Message: err.Error()}
+		}
+	}
+
 	if isRemoteCallRequired(ctx, args.BucketName, objectAPI) {
 		sr, err := globalDNSConfig.Get(args.BucketName)
 		if err != nil {
@@ -1719,7 +3222,7 @@ func (web *webAPIHandlers) SetBucketPolicy(r *http.Request, args *SetBucketPolic
 			}
 		}
 
-		policyInfo.Statements = miniogopolicy.SetPolicy(policyInfo.Statements, policyType, args.BucketName, args.Prefix)
+		policyInfo.Statements = setWebBucketPolicy(policyInfo.Statements, policyType, args.BucketName, args.Prefix, restrictions)
 		if len(policyInfo.Statements) == 0 {
 			if err = core.SetBucketPolicy(args.BucketName, ""); err != nil {
 				return toJSONError(ctx, err, args.BucketName)
@@ -1755,7 +3258,7 @@ func (web *webAPIHandlers) SetBucketPolicy(r *http.Request, args *SetBucketPolic
 			return toJSONError(ctx, err, args.BucketName)
 		}
 
-		policyInfo.Statements = miniogopolicy.SetPolicy(policyInfo.Statements, policyType, args.BucketName, args.Prefix)
+		policyInfo.Statements = setWebBucketPolicy(policyInfo.Statements, policyType, args.BucketName, args.Prefix, restrictions)
 		if len(policyInfo.Statements) == 0 {
 			if err = objectAPI.DeleteBucketPolicy(ctx, args.BucketName); err != nil {
 				return toJSONError(ctx, err, args.BucketName)
@@ -1783,51 +3286,58 @@ func (web *webAPIHandlers) SetBucketPolicy(r *http.Request, args *SetBucketPolic
 	return nil
 }
 
-// PresignedGetArgs - presigned-get API args.
-type PresignedGetArgs struct {
-	// Host header required for signed headers.
-	HostName string `json:"host"`
-
-	// Bucket name of the object to be presigned.
-	BucketName string `json:"bucket"`
+// ListPolicyTemplatesRep - list policy templates reply.
+type ListPolicyTemplatesRep struct {
+	UIVersion string           `json:"uiVersion"`
+	Templates []PolicyTemplate `json:"templates"`
+}
 
-	// Object name to be presigned.
-	ObjectName string `json:"object"`
+// ListPolicyTemplates - returns the bucket policy templates the browser
+// offers, the policy of a template is applied by SetBucketPolicyJSON
+// once its parameters are substituted.
+func (web *webAPIHandlers) ListPolicyTemplates(r *http.Request, args *WebGenericArgs, reply *ListPolicyTemplatesRep) error {
+	ctx := newWebContext(r, args, "webListPolicyTemplates")
+	if _, _, authErr := webRequestAuthenticate(r); authErr != nil {
+		return toJSONError(ctx, authErr)
+	}
 
-	// Expiry in seconds.
-	Expiry int64 `json:"expiry"`
+	reply.UIVersion = browser.UIVersion
+	reply.Templates = policyTemplates
+	return nil
 }
 
-// PresignedGetRep - presigned-get URL reply.
-type PresignedGetRep struct {
-	UIVersion string `json:"uiVersion"`
-	// Presigned URL of the object.
-	URL string `json:"url"`
+// SetBucketPolicyJSONArgs - set bucket policy JSON args.
+type SetBucketPolicyJSONArgs struct {
+	BucketName string `json:"bucketName"`
+	// Bucket policy in JSON, an empty policy removes the bucket policy.
+	Policy string `json:"policy"`
 }
 
-// PresignedGET - returns presigned-Get url.
-func (web *webAPIHandlers) PresignedGet(r *http.Request, args *PresignedGetArgs, reply *PresignedGetRep) error {
-	ctx := newWebContext(r, args, "webPresignedGet")
+// SetBucketPolicyJSON - replaces the bucket policy with the given JSON
+// policy, like the PutBucketPolicy S3 API.
+func (web *webAPIHandlers) SetBucketPolicyJSON(r *http.Request, args *SetBucketPolicyJSONArgs, reply *WebGenericRep) error {
+	ctx := newWebContext(r, args, "webSetBucketPolicyJSON")
+	objectAPI := web.ObjectAPI()
+	reply.UIVersion = browser.UIVersion
+
+	if objectAPI == nil {
+		return toJSONError(ctx, errServerNotInitialized)
+	}
+
 	claims, owner, authErr := webRequestAuthenticate(r)
 	if authErr != nil {
 		return toJSONError(ctx, authErr)
 	}
-	var creds auth.Credentials
-	if !owner {
-		var ok bool
-		creds, ok = globalIAMSys.GetUser(claims.Subject)
-		if !ok {
-			return toJSONError(ctx, errInvalidAccessKeyID)
-		}
-	} else {
-		creds = globalServerConfig.GetCredential()
-	}
 
-	region := globalServerConfig.GetRegion()
-	if args.BucketName == "" || args.ObjectName == "" {
-		return &json2.Error{
-			Message: "Bucket and Object are mandatory arguments.",
-		}
+	// For authenticated users apply IAM policy.
+	if !globalIAMSys.IsAllowed(iampolicy.Args{
+		AccountName:     claims.Subject,
+		Action:          iampolicy.PutBucketPolicyAction,
+		BucketName:      args.BucketName,
+		ConditionValues: getConditionValues(r, "", claims.Subject),
+		IsOwner:         owner,
+	}) {
+		return toJSONError(ctx, errAccessDenied)
 	}
 
 	// Check if bucket is a reserved bucket name or invalid.
@@ -1835,26 +3345,1068 @@ func (web *webAPIHandlers) PresignedGet(r *http.Request, args *PresignedGetArgs,
 		return toJSONError(ctx, errInvalidBucketName)
 	}
 
-	reply.UIVersion = browser.UIVersion
-	reply.URL = presignedGet(args.HostName, args.BucketName, args.ObjectName, args.Expiry, creds, region)
-	return nil
-}
-
-// Returns presigned url for GET method.
-func presignedGet(host, bucket, object string, expiry int64, creds auth.Credentials, region string) string {
-	accessKey := creds.AccessKey
-	secretKey := creds.SecretKey
-
-	date := UTCNow()
-	dateStr := date.Format(iso8601Format)
-	credential := fmt.Sprintf("%s/%s", accessKey, getScope(date, region))
-
-	var expiryStr = "604800" // Default set to be expire in 7days.
-	if expiry < 604800 && expiry > 0 {
-		expiryStr = strconv.FormatInt(expiry, 10)
+	if len(args.Policy) > maxBucketPolicySize {
+		return &json2.Error{Message: "Policy exceeds the maximum size of 20 KiB"}
 	}
 
-	query := url.Values{}
+	var bucketPolicy *policy.Policy
+	if args.Policy != "" {
+		var err error
+		bucketPolicy, err = policy.ParseConfig(strings.NewReader(args.Policy), args.BucketName)
+		if err != nil {
+			return &json2.Error{Message: err.Error()}
+		}
+		// Version in policy must not be empty
+		if bucketPolicy.Version == "" {
+			return &json2.Error{Message: "Policy version must not be empty"}
+		}
+	}
+
+	if isRemoteCallRequired(ctx, args.BucketName, objectAPI) {
+		sr, err := globalDNSConfig.Get(args.BucketName)
+		if err != nil {
+			if err == dns.ErrNoEntriesFound {
+				return toJSONError(ctx, BucketNotFound{
+					Bucket: args.BucketName,
+				}, args.BucketName)
+			}
+			return toJSONError(ctx, err, args.BucketName)
+		}
+		core, rerr := getRemoteInstanceClient(r, getHostFromSrv(sr))
+		if rerr != nil {
+			return toJSONError(ctx, rerr, args.BucketName)
+		}
+		if err = core.SetBucketPolicy(args.BucketName, args.Policy); err != nil {
+			return toJSONError(ctx, err, args.BucketName)
+		}
+		return nil
+	}
+
+	if _, err := objectAPI.GetBucketInfo(ctx, args.BucketName); err != nil {
+		return toJSONError(ctx, err, args.BucketName)
+	}
+
+	if bucketPolicy == nil {
+		if err := objectAPI.DeleteBucketPolicy(ctx, args.BucketName); err != nil {
+			if _, ok := err.(BucketPolicyNotFound); !ok {
+				return toJSONError(ctx, err, args.BucketName)
+			}
+		}
+		globalPolicySys.Remove(args.BucketName)
+		globalNotificationSys.RemoveBucketPolicy(ctx, args.BucketName)
+		return nil
+	}
+
+	if err := objectAPI.SetBucketPolicy(ctx, args.BucketName, bucketPolicy); err != nil {
+		return toJSONError(ctx, err, args.BucketName)
+	}
+
+	globalPolicySys.Set(args.BucketName, *bucketPolicy)
+	globalNotificationSys.SetBucketPolicy(ctx, args.BucketName, bucketPolicy)
+	return nil
+}
+
+// BucketTrashArgs - get bucket trash args.
+type BucketTrashArgs struct {
+	BucketName string `json:"bucketName"`
+}
+
+// GetBucketTrashRep - get bucket trash reply.
+type GetBucketTrashRep struct {
+	UIVersion string `json:"uiVersion"`
+	// Number of days removed objects are kept, zero if the trash is disabled.
+	RetentionDays int `json:"retentionDays"`
+}
+
+// GetBucketTrash - returns the trash configuration of a bucket.
+func (web *webAPIHandlers) GetBucketTrash(r *http.Request, args *BucketTrashArgs, reply *GetBucketTrashRep) error {
+	ctx := newWebContext(r, args, "webGetBucketTrash")
+	objectAPI := web.ObjectAPI()
+	if objectAPI == nil {
+		return toJSONError(ctx, errServerNotInitialized)
+	}
+
+	claims, owner, authErr := webRequestAuthenticate(r)
+	if authErr != nil {
+		return toJSONError(ctx, authErr)
+	}
+
+	// For authenticated users apply IAM policy.
+	if !globalIAMSys.IsAllowed(iampolicy.Args{
+		AccountName:     claims.Subject,
+		Action:          iampolicy.GetBucketLifecycleAction,
+		BucketName:      args.BucketName,
+		ConditionValues: getConditionValues(r, "", claims.Subject),
+		IsOwner:         owner,
+	}) {
+		return toJSONError(ctx, errAccessDenied)
+	}
+
+	// Check if bucket is a reserved bucket name or invalid.
+	if isReservedOrInvalidBucket(args.BucketName, false) {
+		return toJSONError(ctx, errInvalidBucketName)
+	}
+
+	if _, err := objectAPI.GetBucketInfo(ctx, args.BucketName); err != nil {
+		return toJSONError(ctx, err, args.BucketName)
+	}
+
+	reply.UIVersion = browser.UIVersion
+	config, err := getBucketTrashConfig(ctx, objectAPI, args.BucketName)
+	if err != nil {
+		if err == errConfigNotFound {
+			return nil
+		}
+		return toJSONError(ctx, err, args.BucketName)
+	}
+	reply.RetentionDays = config.RetentionDays
+	return nil
+}
+
+// SetBucketTrashArgs - set bucket trash args.
+type SetBucketTrashArgs struct {
+	BucketName string `json:"bucketName"`
+	// Number of days removed objects are kept, zero disables the trash.
+	RetentionDays int `json:"retentionDays"`
+}
+
+// SetBucketTrash - enables or disables the trash of a bucket, objects
+// removed through the browser from a bucket with the trash enabled may
+// be restored until their retention period expires.
+func (web *webAPIHandlers) SetBucketTrash(r *http.Request, args *SetBucketTrashArgs, reply *WebGenericRep) error {
+	ctx := newWebContext(r, args, "webSetBucketTrash")
+	objectAPI := web.ObjectAPI()
+	reply.UIVersion = browser.UIVersion
+
+	if objectAPI == nil {
+		return toJSONError(ctx, errServerNotInitialized)
+	}
+
+	claims, owner, authErr := webRequestAuthenticate(r)
+	if authErr != nil {
+		return toJSONError(ctx, authErr)
+	}
+
+	// For authenticated users apply IAM policy.
+	if !globalIAMSys.IsAllowed(iampolicy.Args{
+		AccountName:     claims.Subject,
+		Action:          iampolicy.PutBucketLifecycleAction,
+		BucketName:      args.BucketName,
+		ConditionValues: getConditionValues(r, "", claims.Subject),
+		IsOwner:         owner,
+	}) {
+		return toJSONError(ctx, errAccessDenied)
+	}
+
+	// Check if bucket is a reserved bucket name or invalid.
+	if isReservedOrInvalidBucket(args.BucketName, false) {
+		return toJSONError(ctx, errInvalidBucketName)
+	}
+
+	if args.RetentionDays < 0 {
+		return toJSONError(ctx, errInvalidArgument)
+	}
+
+	if _, err := objectAPI.GetBucketInfo(ctx, args.BucketName); err != nil {
+		return toJSONError(ctx, err, args.BucketName)
+	}
+
+	if args.RetentionDays == 0 {
+		if err := removeBucketTrashConfig(ctx, objectAPI, args.BucketName); err != nil {
+			return toJSONError(ctx, err, args.BucketName)
+		}
+		return nil
+	}
+
+	config := BucketTrashConfig{RetentionDays: args.RetentionDays}
+	if err := saveBucketTrashConfig(ctx, objectAPI, args.BucketName, config); err != nil {
+		return toJSONError(ctx, err, args.BucketName)
+	}
+	return nil
+}
+
+// BucketAnonymousUploadArgs - get bucket anonymous upload args.
+type BucketAnonymousUploadArgs struct {
+	BucketName string `json:"bucketName"`
+}
+
+// GetBucketAnonymousUploadRep - get bucket anonymous upload reply.
+type GetBucketAnonymousUploadRep struct {
+	UIVersion string                      `json:"uiVersion"`
+	Config    BucketAnonymousUploadConfig `json:"config"`
+}
+
+// GetBucketAnonymousUpload - returns the anonymous upload limits of a
+// bucket.
+func (web *webAPIHandlers) GetBucketAnonymousUpload(r *http.Request, args *BucketAnonymousUploadArgs, reply *GetBucketAnonymousUploadRep) error {
+	ctx := newWebContext(r, args, "webGetBucketAnonymousUpload")
+	objectAPI := web.ObjectAPI()
+	if objectAPI == nil {
+		return toJSONError(ctx, errServerNotInitialized)
+	}
+
+	claims, owner, authErr := webRequestAuthenticate(r)
+	if authErr != nil {
+		return toJSONError(ctx, authErr)
+	}
+
+	// For authenticated users apply IAM policy.
+	if !globalIAMSys.IsAllowed(iampolicy.Args{
+		AccountName:     claims.Subject,
+		Action:          iampolicy.GetBucketPolicyAction,
+		BucketName:      args.BucketName,
+		ConditionValues: getConditionValues(r, "", claims.Subject),
+		IsOwner:         owner,
+	}) {
+		return toJSONError(ctx, errAccessDenied)
+	}
+
+	// Check if bucket is a reserved bucket name or invalid.
+	if isReservedOrInvalidBucket(args.BucketName, false) {
+		return toJSONError(ctx, errInvalidBucketName)
+	}
+
+	if _, err := objectAPI.GetBucketInfo(ctx, args.BucketName); err != nil {
+		return toJSONError(ctx, err, args.BucketName)
+	}
+
+	reply.UIVersion = browser.UIVersion
+	config, err := getBucketAnonymousUploadConfig(ctx, objectAPI, args.BucketName)
+	if err != nil {
+		if err == errConfigNotFound {
+			return nil
+		}
+		return toJSONError(ctx, err, args.BucketName)
+	}
+	reply.Config = *config
+	return nil
+}
+
+// SetBucketAnonymousUploadArgs - set bucket anonymous upload args.
+type SetBucketAnonymousUploadArgs struct {
+	BucketName string                      `json:"bucketName"`
+	Config     BucketAnonymousUploadConfig `json:"config"`
+}
+
+// SetBucketAnonymousUpload - sets the anonymous upload limits of a
+// bucket, limits which are all zero remove them.
+func (web *webAPIHandlers) SetBucketAnonymousUpload(r *http.Request, args *SetBucketAnonymousUploadArgs, reply *WebGenericRep) error {
+	ctx := newWebContext(r, args, "webSetBucketAnonymousUpload")
+	objectAPI := web.ObjectAPI()
+	reply.UIVersion = browser.UIVersion
+
+	if objectAPI == nil {
+		return toJSONError(ctx, errServerNotInitialized)
+	}
+
+	claims, owner, authErr := webRequestAuthenticate(r)
+	if authErr != nil {
+		return toJSONError(ctx, authErr)
+	}
+
+	// For authenticated users apply IAM policy.
+	if !globalIAMSys.IsAllowed(iampolicy.Args{
+		AccountName:     claims.Subject,
+		Action:          iampolicy.PutBucketPolicyAction,
+		BucketName:      args.BucketName,
+		ConditionValues: getConditionValues(r, "", claims.Subject),
+		IsOwner:         owner,
+	}) {
+		return toJSONError(ctx, errAccessDenied)
+	}
+
+	// Check if bucket is a reserved bucket name or invalid.
+	if isReservedOrInvalidBucket(args.BucketName, false) {
+		return toJSONError(ctx, errInvalidBucketName)
+	}
+
+	config := args.Config
+	if config.MaxObjectSize < 0 || config.DailyQuota < 0 || config.ExpiryDays < 0 {
+		return toJSONError(ctx, errInvalidArgument)
+	}
+
+	if _, err := objectAPI.GetBucketInfo(ctx, args.BucketName); err != nil {
+		return toJSONError(ctx, err, args.BucketName)
+	}
+
+	if config == (BucketAnonymousUploadConfig{}) {
+		if err := removeBucketAnonymousUploadConfig(ctx, objectAPI, args.BucketName); err != nil {
+			return toJSONError(ctx, err, args.BucketName)
+		}
+		return nil
+	}
+
+	if err := saveBucketAnonymousUploadConfig(ctx, objectAPI, args.BucketName, config); err != nil {
+		return toJSONError(ctx, err, args.BucketName)
+	}
+	return nil
+}
+
+// BucketContentTypeArgs - get bucket content type args.
+type BucketContentTypeArgs struct {
+	BucketName string `json:"bucketName"`
+}
+
+// GetBucketContentTypeRep - get bucket content type reply.
+type GetBucketContentTypeRep struct {
+	UIVersion string                  `json:"uiVersion"`
+	Config    BucketContentTypeConfig `json:"config"`
+}
+
+// GetBucketContentType - returns the content types and extensions of
+// the objects which may be uploaded to a bucket.
+func (web *webAPIHandlers) GetBucketContentType(r *http.Request, args *BucketContentTypeArgs, reply *GetBucketContentTypeRep) error {
+	ctx := newWebContext(r, args, "webGetBucketContentType")
+	objectAPI := web.ObjectAPI()
+	if objectAPI == nil {
+		return toJSONError(ctx, errServerNotInitialized)
+	}
+
+	claims, owner, authErr := webRequestAuthenticate(r)
+	if authErr != nil {
+		return toJSONError(ctx, authErr)
+	}
+
+	// For authenticated users apply IAM policy.
+	if !globalIAMSys.IsAllowed(iampolicy.Args{
+		AccountName:     claims.Subject,
+		Action:          iampolicy.GetBucketPolicyAction,
+		BucketName:      args.BucketName,
+		ConditionValues: getConditionValues(r, "", claims.Subject),
+		IsOwner:         owner,
+	}) {
+		return toJSONError(ctx, errAccessDenied)
+	}
+
+	// Check if bucket is a reserved bucket name or invalid.
+	if isReservedOrInvalidBucket(args.BucketName, false) {
+		return toJSONError(ctx, errInvalidBucketName)
+	}
+
+	if _, err := objectAPI.GetBucketInfo(ctx, args.BucketName); err != nil {
+		return toJSONError(ctx, err, args.BucketName)
+	}
+
+	reply.UIVersion = browser.UIVersion
+	config, err := getBucketContentTypeConfig(ctx, objectAPI, args.BucketName)
+	if err != nil {
+		if err == errConfigNotFound {
+			return nil
+		}
+		return toJSONError(ctx, err, args.BucketName)
+	}
+	reply.Config = *config
+	return nil
+}
+
+// SetBucketContentTypeArgs - set bucket content type args.
+type SetBucketContentTypeArgs struct {
+	BucketName string                  `json:"bucketName"`
+	Config     BucketContentTypeConfig `json:"config"`
+}
+
+// SetBucketContentType - restricts uploads to a bucket to the given
+// content types and extensions, an empty configuration removes the
+// restriction.
+func (web *webAPIHandlers) SetBucketContentType(r *http.Request, args *SetBucketContentTypeArgs, reply *WebGenericRep) error {
+	ctx := newWebContext(r, args, "webSetBucketContentType")
+	objectAPI := web.ObjectAPI()
+	reply.UIVersion = browser.UIVersion
+
+	if objectAPI == nil {
+		return toJSONError(ctx, errServerNotInitialized)
+	}
+
+	claims, owner, authErr := webRequestAuthenticate(r)
+	if authErr != nil {
+		return toJSONError(ctx, authErr)
+	}
+
+	// For authenticated users apply IAM policy.
+	if !globalIAMSys.IsAllowed(iampolicy.Args{
+		AccountName:     claims.Subject,
+		Action:          iampolicy.PutBucketPolicyAction,
+		BucketName:      args.BucketName,
+		ConditionValues: getConditionValues(r, "", claims.Subject),
+		IsOwner:         owner,
+	}) {
+		return toJSONError(ctx, errAccessDenied)
+	}
+
+	// Check if bucket is a reserved bucket name or invalid.
+	if isReservedOrInvalidBucket(args.BucketName, false) {
+		return toJSONError(ctx, errInvalidBucketName)
+	}
+
+	if err := args.Config.Validate(); err != nil {
+		return toJSONError(ctx, err)
+	}
+
+	if _, err := objectAPI.GetBucketInfo(ctx, args.BucketName); err != nil {
+		return toJSONError(ctx, err, args.BucketName)
+	}
+
+	if args.Config.IsEmpty() {
+		if err := removeBucketContentTypeConfig(ctx, objectAPI, args.BucketName); err != nil {
+			return toJSONError(ctx, err, args.BucketName)
+		}
+		return nil
+	}
+
+	if err := saveBucketContentTypeConfig(ctx, objectAPI, args.BucketName, args.Config); err != nil {
+		return toJSONError(ctx, err, args.BucketName)
+	}
+	return nil
+}
+
+// ListTrashArgs - list trash args.
+type ListTrashArgs struct {
+	BucketName string `json:"bucketName"`
+	Prefix     string `json:"prefix"`
+	Marker     string `json:"marker"`
+}
+
+// WebTrashObject - an object in the trash of a bucket.
+type WebTrashObject struct {
+	WebObjectInfo
+	// Time the object was removed.
+	DeletedAt time.Time `json:"deletedAt"`
+	// Time after which the object is purged from the trash.
+	ExpiresAt time.Time `json:"expiresAt"`
+}
+
+// ListTrashRep - list trash response.
+type ListTrashRep struct {
+	Objects     []WebTrashObject `json:"objects"`
+	NextMarker  string           `json:"nextmarker"`
+	IsTruncated bool             `json:"istruncated"`
+	UIVersion   string           `json:"uiVersion"`
+}
+
+// ListTrash - lists the objects kept in the trash of a bucket.
+func (web *webAPIHandlers) ListTrash(r *http.Request, args *ListTrashArgs, reply *ListTrashRep) error {
+	ctx := newWebContext(r, args, "webListTrash")
+	reply.UIVersion = browser.UIVersion
+	objectAPI := web.ObjectAPI()
+	if objectAPI == nil {
+		return toJSONError(ctx, errServerNotInitialized)
+	}
+
+	claims, owner, authErr := webRequestAuthenticate(r)
+	if authErr != nil {
+		return toJSONError(ctx, authErr)
+	}
+
+	// For authenticated users apply IAM policy.
+	if !globalIAMSys.IsAllowed(iampolicy.Args{
+		AccountName:     claims.Subject,
+		Action:          iampolicy.ListBucketAction,
+		BucketName:      args.BucketName,
+		ConditionValues: getConditionValues(r, "", claims.Subject),
+		IsOwner:         owner,
+	}) {
+		return toJSONError(ctx, errAccessDenied)
+	}
+
+	// Check if bucket is a reserved bucket name or invalid.
+	if isReservedOrInvalidBucket(args.BucketName, false) {
+		return toJSONError(ctx, errInvalidBucketName)
+	}
+
+	retentionDays := 0
+	config, err := getBucketTrashConfig(ctx, objectAPI, args.BucketName)
+	if err == nil {
+		retentionDays = config.RetentionDays
+	} else if err != errConfigNotFound {
+		return toJSONError(ctx, err, args.BucketName)
+	}
+
+	entries, nextMarker, err := listTrash(ctx, objectAPI, args.BucketName, args.Prefix, args.Marker, maxObjectList)
+	if err != nil {
+		return toJSONError(ctx, err, args.BucketName)
+	}
+	for _, entry := range entries {
+		reply.Objects = append(reply.Objects, WebTrashObject{
+			WebObjectInfo: WebObjectInfo{
+				Key:          entry.Name,
+				LastModified: entry.ModTime,
+				Size:         entry.Size,
+				ContentType:  entry.ContentType,
+			},
+			DeletedAt: entry.DeletedAt,
+			ExpiresAt: entry.DeletedAt.AddDate(0, 0, retentionDays),
+		})
+	}
+	reply.NextMarker = nextMarker
+	reply.IsTruncated = nextMarker != ""
+	return nil
+}
+
+// RestoreObjectArgs - restore objects args.
+type RestoreObjectArgs struct {
+	BucketName string   `json:"bucketName"`
+	Objects    []string `json:"objects"`
+}
+
+// RestoreObject - moves objects from the trash of a bucket back to the
+// bucket, objects of the same name in the bucket are overwritten.
+func (web *webAPIHandlers) RestoreObject(r *http.Request, args *RestoreObjectArgs, reply *WebGenericRep) error {
+	ctx := newWebContext(r, args, "webRestoreObject")
+	reply.UIVersion = browser.UIVersion
+	objectAPI := web.ObjectAPI()
+	if objectAPI == nil {
+		return toJSONError(ctx, errServerNotInitialized)
+	}
+
+	claims, owner, authErr := webRequestAuthenticate(r)
+	if authErr != nil {
+		return toJSONError(ctx, authErr)
+	}
+
+	if args.BucketName == "" || len(args.Objects) == 0 {
+		return toJSONError(ctx, errInvalidArgument)
+	}
+
+	// Check if bucket is a reserved bucket name or invalid.
+	if isReservedOrInvalidBucket(args.BucketName, false) {
+		return toJSONError(ctx, errInvalidBucketName)
+	}
+
+	for _, objectName := range args.Objects {
+		if !globalIAMSys.IsAllowed(iampolicy.Args{
+			AccountName:     claims.Subject,
+			Action:          iampolicy.PutObjectAction,
+			BucketName:      args.BucketName,
+			ConditionValues: getConditionValues(r, "", claims.Subject),
+			IsOwner:         owner,
+			ObjectName:      objectName,
+		}) {
+			return toJSONError(ctx, errAccessDenied)
+		}
+
+		objInfo, err := restoreFromTrash(ctx, objectAPI, args.BucketName, objectName)
+		if err != nil {
+			return toJSONError(ctx, err, args.BucketName, objectName)
+		}
+
+		// Notify object created event.
+		sendEvent(eventArgs{
+			EventName:  event.ObjectCreatedPut,
+			BucketName: args.BucketName,
+			Object:     objInfo,
+			ReqParams:  extractReqParams(r),
+			UserAgent:  r.UserAgent(),
+			Host:       handlers.GetSourceIP(r),
+		})
+	}
+	return nil
+}
+
+// CopyPrefixArgs - copy prefix args.
+type CopyPrefixArgs struct {
+	BucketName         string `json:"bucketName"`
+	Prefix             string `json:"prefix"`
+	TargetBucket       string `json:"targetBucket"`
+	TargetPrefix       string `json:"targetPrefix"`
+	PreserveMetadata   bool   `json:"preserveMetadata"`
+	PreserveEncryption bool   `json:"preserveEncryption"`
+}
+
+// CopyPrefixRep - copy prefix reply.
+type CopyPrefixRep struct {
+	UIVersion string `json:"uiVersion"`
+	// ID of the job copying the objects, its progress is returned
+	// by CopyPrefixStatus.
+	JobID string `json:"jobId"`
+}
+
+// CopyPrefix - starts a background job which server-side copies all
+// objects under a prefix to a prefix of a target bucket.
+func (web *webAPIHandlers) CopyPrefix(r *http.Request, args *CopyPrefixArgs, reply *CopyPrefixRep) error {
+	ctx := newWebContext(r, args, "webCopyPrefix")
+	reply.UIVersion = browser.UIVersion
+	objectAPI := web.ObjectAPI()
+	if objectAPI == nil {
+		return toJSONError(ctx, errServerNotInitialized)
+	}
+
+	claims, owner, authErr := webRequestAuthenticate(r)
+	if authErr != nil {
+		return toJSONError(ctx, authErr)
+	}
+
+	// Deny if WORM is enabled
+	if globalWORMEnabled {
+		return toJSONError(ctx, errMethodNotAllowed)
+	}
+
+	// Check if bucket is a reserved bucket name or invalid.
+	if isReservedOrInvalidBucket(args.BucketName, false) || isReservedOrInvalidBucket(args.TargetBucket, false) {
+		return toJSONError(ctx, errInvalidBucketName)
+	}
+
+	for _, a := range []iampolicy.Args{
+		{Action: iampolicy.ListBucketAction, BucketName: args.BucketName, ObjectName: args.Prefix},
+		{Action: iampolicy.GetObjectAction, BucketName: args.BucketName, ObjectName: args.Prefix},
+		{Action: iampolicy.PutObjectAction, BucketName: args.TargetBucket, ObjectName: args.TargetPrefix},
+	} {
+		a.AccountName = claims.Subject
+		a.ConditionValues = getConditionValues(r, "", claims.Subject)
+		a.IsOwner = owner
+		if !globalIAMSys.IsAllowed(a) {
+			return toJSONError(ctx, errAccessDenied)
+		}
+	}
+
+	id, err := globalBatchJobs.StartCopyPrefix(GlobalContext, objectAPI, madmin.BatchCopyPrefixJob{
+		Bucket:             args.BucketName,
+		Prefix:             args.Prefix,
+		TargetBucket:       args.TargetBucket,
+		TargetPrefix:       args.TargetPrefix,
+		PreserveMetadata:   args.PreserveMetadata,
+		PreserveEncryption: args.PreserveEncryption,
+	})
+	if err != nil {
+		if isBatchJobArgumentErr(err) {
+			return &json2.Error{Message: err.Error()}
+		}
+		return toJSONError(ctx, err, args.BucketName)
+	}
+	reply.JobID = id
+	return nil
+}
+
+// CopyPrefixStatusArgs - copy prefix status args.
+type CopyPrefixStatusArgs struct {
+	JobID string `json:"jobId"`
+}
+
+// CopyPrefixStatusRep - copy prefix status reply.
+type CopyPrefixStatusRep struct {
+	UIVersion string                `json:"uiVersion"`
+	Report    madmin.BatchJobReport `json:"report"`
+}
+
+// CopyPrefixStatus - returns the progress of a copy prefix job, along
+// with the objects which could not be copied.
+func (web *webAPIHandlers) CopyPrefixStatus(r *http.Request, args *CopyPrefixStatusArgs, reply *CopyPrefixStatusRep) error {
+	ctx := newWebContext(r, args, "webCopyPrefixStatus")
+	reply.UIVersion = browser.UIVersion
+	objectAPI := web.ObjectAPI()
+	if objectAPI == nil {
+		return toJSONError(ctx, errServerNotInitialized)
+	}
+
+	claims, owner, authErr := webRequestAuthenticate(r)
+	if authErr != nil {
+		return toJSONError(ctx, authErr)
+	}
+
+	if args.JobID == "" {
+		return toJSONError(ctx, errInvalidArgument)
+	}
+
+	statuses := globalBatchJobs.Status()
+	if globalIsDistXL {
+		statuses = append(statuses, globalNotificationSys.BatchJobsStatus(ctx)...)
+	}
+	report, ok := getBatchJobReport(args.JobID, statuses)
+	if !ok || report.Nodes[0].Bucket == "" {
+		return &json2.Error{Message: "The specified copy prefix job does not exist."}
+	}
+
+	// Only users allowed to list the copied objects see the job.
+	if !globalIAMSys.IsAllowed(iampolicy.Args{
+		AccountName:     claims.Subject,
+		Action:          iampolicy.ListBucketAction,
+		BucketName:      report.Nodes[0].Bucket,
+		ConditionValues: getConditionValues(r, "", claims.Subject),
+		IsOwner:         owner,
+		ObjectName:      report.Nodes[0].Prefix,
+	}) {
+		return toJSONError(ctx, errAccessDenied)
+	}
+
+	reply.Report = report
+	return nil
+}
+
+// ComposeObjectArgs - compose object args.
+type ComposeObjectArgs struct {
+	BucketName string `json:"bucketName"`
+	// Source objects of the bucket, in order.
+	Sources []ComposeSource `json:"sources"`
+	// Object the source objects are concatenated into.
+	ObjectName string `json:"objectName"`
+}
+
+// ComposeObjectRep - compose object reply.
+type ComposeObjectRep struct {
+	UIVersion string `json:"uiVersion"`
+	ETag      string `json:"etag"`
+}
+
+// ComposeObject - concatenates the source objects of a bucket into an
+// object of the bucket server-side.
+func (web *webAPIHandlers) ComposeObject(r *http.Request, args *ComposeObjectArgs, reply *ComposeObjectRep) error {
+	ctx := newWebContext(r, args, "webComposeObject")
+	reply.UIVersion = browser.UIVersion
+	objectAPI := web.ObjectAPI()
+	if objectAPI == nil {
+		return toJSONError(ctx, errServerNotInitialized)
+	}
+
+	claims, owner, authErr := webRequestAuthenticate(r)
+	if authErr != nil {
+		return toJSONError(ctx, authErr)
+	}
+
+	// Check if bucket is a reserved bucket name or invalid.
+	if isReservedOrInvalidBucket(args.BucketName, false) {
+		return toJSONError(ctx, errInvalidBucketName)
+	}
+
+	checks := []iampolicy.Args{
+		{Action: iampolicy.PutObjectAction, BucketName: args.BucketName, ObjectName: args.ObjectName},
+	}
+	for _, src := range args.Sources {
+		checks = append(checks, iampolicy.Args{Action: iampolicy.GetObjectAction, BucketName: args.BucketName, ObjectName: src.Object})
+	}
+	for _, a := range checks {
+		a.AccountName = claims.Subject
+		a.ConditionValues = getConditionValues(r, "", claims.Subject)
+		a.IsOwner = owner
+		if !globalIAMSys.IsAllowed(a) {
+			return toJSONError(ctx, errAccessDenied)
+		}
+	}
+
+	// Deny if WORM is enabled
+	if globalWORMEnabled {
+		if _, err := objectAPI.GetObjectInfo(ctx, args.BucketName, args.ObjectName, ObjectOptions{}); err == nil {
+			return toJSONError(ctx, errMethodNotAllowed)
+		}
+	}
+
+	objInfo, err := composeObject(ctx, objectAPI, args.BucketName, args.ObjectName, args.Sources, nil)
+	if err != nil {
+		return toJSONError(ctx, err, args.BucketName, args.ObjectName)
+	}
+	reply.ETag = objInfo.ETag
+
+	// Notify object created event.
+	sendEvent(eventArgs{
+		EventName:  event.ObjectCreatedCompleteMultipartUpload,
+		BucketName: args.BucketName,
+		Object:     objInfo,
+		ReqParams:  extractReqParams(r),
+		UserAgent:  r.UserAgent(),
+		Host:       handlers.GetSourceIP(r),
+	})
+	return nil
+}
+
+// TestNotificationTargetArgs - test notification target args.
+type TestNotificationTargetArgs struct {
+	BucketName string `json:"bucketName"`
+	ARN        string `json:"arn"`
+}
+
+// TestNotificationTargetRep - test notification target reply.
+type TestNotificationTargetRep struct {
+	UIVersion string `json:"uiVersion"`
+	Delivered bool   `json:"delivered"`
+	Latency   string `json:"latency"`
+	Error     string `json:"error,omitempty"`
+}
+
+// TestNotificationTarget - sends a test event of a bucket to the
+// notification target with the given ARN and reports whether it was
+// delivered, so that the configuration of the target can be checked.
+func (web *webAPIHandlers) TestNotificationTarget(r *http.Request, args *TestNotificationTargetArgs, reply *TestNotificationTargetRep) error {
+	ctx := newWebContext(r, args, "webTestNotificationTarget")
+	reply.UIVersion = browser.UIVersion
+	objectAPI := web.ObjectAPI()
+	if objectAPI == nil {
+		return toJSONError(ctx, errServerNotInitialized)
+	}
+
+	claims, owner, authErr := webRequestAuthenticate(r)
+	if authErr != nil {
+		return toJSONError(ctx, authErr)
+	}
+
+	// For authenticated users apply IAM policy.
+	if !globalIAMSys.IsAllowed(iampolicy.Args{
+		AccountName:     claims.Subject,
+		Action:          iampolicy.PutBucketNotificationAction,
+		BucketName:      args.BucketName,
+		ConditionValues: getConditionValues(r, "", claims.Subject),
+		IsOwner:         owner,
+	}) {
+		return toJSONError(ctx, errAccessDenied)
+	}
+
+	// Check if bucket is a reserved bucket name or invalid.
+	if isReservedOrInvalidBucket(args.BucketName, false) {
+		return toJSONError(ctx, errInvalidBucketName)
+	}
+
+	if _, err := objectAPI.GetBucketInfo(ctx, args.BucketName); err != nil {
+		return toJSONError(ctx, err, args.BucketName)
+	}
+
+	target, err := globalNotificationSys.GetTarget(args.ARN)
+	if err != nil {
+		return toJSONError(ctx, err)
+	}
+
+	latency, err := testNotificationTarget(target, eventArgs{
+		BucketName: args.BucketName,
+		ReqParams:  extractReqParams(r),
+		UserAgent:  r.UserAgent(),
+		Host:       handlers.GetSourceIP(r),
+	})
+	reply.Delivered = err == nil
+	reply.Latency = latency.String()
+	if err != nil {
+		reply.Error = err.Error()
+	}
+	return nil
+}
+
+// SimulatePolicyArgs - simulate policy args.
+type SimulatePolicyArgs struct {
+	// Access key of the user, empty for anonymous requests.
+	User       string              `json:"user"`
+	Action     string              `json:"action"`
+	BucketName string              `json:"bucketName"`
+	ObjectName string              `json:"objectName"`
+	Conditions map[string][]string `json:"conditions"`
+}
+
+// SimulatePolicyRep - simulate policy reply.
+type SimulatePolicyRep struct {
+	UIVersion string                        `json:"uiVersion"`
+	Result    madmin.PolicySimulationResult `json:"result"`
+}
+
+// SimulatePolicy - evaluates whether a request would be allowed by the
+// current IAM and bucket policies, and explains which policy statement
+// decided. Only the owner may simulate requests.
+func (web *webAPIHandlers) SimulatePolicy(r *http.Request, args *SimulatePolicyArgs, reply *SimulatePolicyRep) error {
+	ctx := newWebContext(r, args, "webSimulatePolicy")
+	reply.UIVersion = browser.UIVersion
+	objectAPI := web.ObjectAPI()
+	if objectAPI == nil {
+		return toJSONError(ctx, errServerNotInitialized)
+	}
+
+	_, owner, authErr := webRequestAuthenticate(r)
+	if authErr != nil {
+		return toJSONError(ctx, authErr)
+	}
+	if !owner {
+		return toJSONError(ctx, errAccessDenied)
+	}
+
+	result, err := simulatePolicy(madmin.PolicySimulation{
+		User:       args.User,
+		Action:     args.Action,
+		Bucket:     args.BucketName,
+		Object:     args.ObjectName,
+		Conditions: args.Conditions,
+	})
+	if err != nil {
+		if err == errPolicySimulationInvalidAction {
+			return &json2.Error{Message: err.Error()}
+		}
+		return toJSONError(ctx, err)
+	}
+	reply.Result = result
+	return nil
+}
+
+// PresignedGetArgs - presigned-get API args.
+type PresignedGetArgs struct {
+	// Host header required for signed headers.
+	HostName string `json:"host"`
+
+	// Bucket name of the object to be presigned.
+	BucketName string `json:"bucket"`
+
+	// Object name to be presigned.
+	ObjectName string `json:"object"`
+
+	// Expiry in seconds.
+	Expiry int64 `json:"expiry"`
+
+	// Optional Content-Disposition override, e.g. to set the
+	// filename of the downloaded object.
+	ContentDisposition string `json:"contentDisposition"`
+
+	// Optional Content-Type override.
+	ContentType string `json:"contentType"`
+
+	// Optional version ID of the object.
+	VersionID string `json:"versionId"`
+}
+
+// PresignedGetRep - presigned-get URL reply.
+type PresignedGetRep struct {
+	UIVersion string `json:"uiVersion"`
+	// Presigned URL of the object.
+	URL string `json:"url"`
+}
+
+// PresignedGET - returns presigned-Get url.
+func (web *webAPIHandlers) PresignedGet(r *http.Request, args *PresignedGetArgs, reply *PresignedGetRep) error {
+	ctx := newWebContext(r, args, "webPresignedGet")
+	claims, owner, authErr := webRequestAuthenticate(r)
+	if authErr != nil {
+		return toJSONError(ctx, authErr)
+	}
+	var creds auth.Credentials
+	if !owner {
+		var ok bool
+		creds, ok = globalIAMSys.GetUser(claims.Subject)
+		if !ok {
+			return toJSONError(ctx, errInvalidAccessKeyID)
+		}
+	} else {
+		creds = globalServerConfig.GetCredential()
+	}
+
+	region := globalServerConfig.GetRegion()
+	if args.BucketName == "" || args.ObjectName == "" {
+		return &json2.Error{
+			Message: "Bucket and Object are mandatory arguments.",
+		}
+	}
+
+	// Check if bucket is a reserved bucket name or invalid.
+	if isReservedOrInvalidBucket(args.BucketName, false) {
+		return toJSONError(ctx, errInvalidBucketName)
+	}
+
+	// Versioning is not supported, only the "null" version exists.
+	if args.VersionID != "" && args.VersionID != "null" {
+		return &json2.Error{
+			Message: "The specified version does not exist.",
+		}
+	}
+
+	// Response overrides and version ID are part of the signed query string.
+	reqParams := url.Values{}
+	if args.ContentDisposition != "" {
+		reqParams.Set("response-content-disposition", args.ContentDisposition)
+	}
+	if args.ContentType != "" {
+		reqParams.Set("response-content-type", args.ContentType)
+	}
+	if args.VersionID != "" {
+		reqParams.Set("versionId", args.VersionID)
+	}
+
+	reply.UIVersion = browser.UIVersion
+	reply.URL = presignedGet(args.HostName, args.BucketName, args.ObjectName, args.Expiry, reqParams, creds, region)
+	return nil
+}
+
+// ListObjectVersionsArgs - list object versions args.
+type ListObjectVersionsArgs struct {
+	BucketName string `json:"bucketName"`
+	ObjectName string `json:"objectName"`
+}
+
+// WebObjectVersion - a version of an object.
+type WebObjectVersion struct {
+	VersionID    string    `json:"versionId"`
+	LastModified time.Time `json:"lastModified"`
+	// Size in bytes of the version, zero for delete markers.
+	Size           int64 `json:"size"`
+	IsDeleteMarker bool  `json:"isDeleteMarker"`
+	IsLatest       bool  `json:"isLatest"`
+}
+
+// ListObjectVersionsRep - list object versions reply.
+type ListObjectVersionsRep struct {
+	UIVersion string `json:"uiVersion"`
+	// Versions of the object, latest first.
+	Versions []WebObjectVersion `json:"versions"`
+}
+
+// ListObjectVersions - lists the versions of an object, latest first.
+// Versioning is not supported yet, an object has a single "null"
+// version and none if it does not exist.
+func (web *webAPIHandlers) ListObjectVersions(r *http.Request, args *ListObjectVersionsArgs, reply *ListObjectVersionsRep) error {
+	ctx := newWebContext(r, args, "webListObjectVersions")
+	reply.UIVersion = browser.UIVersion
+	objectAPI := web.ObjectAPI()
+	if objectAPI == nil {
+		return toJSONError(ctx, errServerNotInitialized)
+	}
+
+	claims, owner, authErr := webRequestAuthenticate(r)
+	if authErr != nil {
+		return toJSONError(ctx, authErr)
+	}
+
+	if args.BucketName == "" || args.ObjectName == "" {
+		return toJSONError(ctx, errInvalidArgument)
+	}
+
+	// Check if bucket is a reserved bucket name or invalid.
+	if isReservedOrInvalidBucket(args.BucketName, false) {
+		return toJSONError(ctx, errInvalidBucketName)
+	}
+
+	// For authenticated users apply IAM policy.
+	if !globalIAMSys.IsAllowed(iampolicy.Args{
+		AccountName:     claims.Subject,
+		Action:          iampolicy.ListBucketAction,
+		BucketName:      args.BucketName,
+		ConditionValues: getConditionValues(r, "", claims.Subject),
+		IsOwner:         owner,
+	}) {
+		return toJSONError(ctx, errAccessDenied)
+	}
+
+	objInfo, err := objectAPI.GetObjectInfo(ctx, args.BucketName, args.ObjectName, ObjectOptions{})
+	if err != nil {
+		if isErrObjectNotFound(err) {
+			return nil
+		}
+		return toJSONError(ctx, err, args.BucketName, args.ObjectName)
+	}
+	reply.Versions = []WebObjectVersion{{
+		VersionID:    "null",
+		LastModified: objInfo.ModTime,
+		Size:         objInfo.GetActualSize(),
+		IsLatest:     true,
+	}}
+	return nil
+}
+
+// Returns presigned url for GET method, reqParams are added to the
+// signed query string.
+func presignedGet(host, bucket, object string, expiry int64, reqParams url.Values, creds auth.Credentials, region string) string {
+	accessKey := creds.AccessKey
+	secretKey := creds.SecretKey
+
+	date := UTCNow()
+	dateStr := date.Format(iso8601Format)
+	credential := fmt.Sprintf("%s/%s", accessKey, getScope(date, region))
+
+	var expiryStr = "604800" // Default set to be expire in 7days.
+	if expiry < 604800 && expiry > 0 {
+		expiryStr = strconv.FormatInt(expiry, 10)
+	}
+
+	query := url.Values{}
+	for k, v := range reqParams {
+		query[k] = v
+	}
 	query.Set(xhttp.AmzAlgorithm, signV4Algorithm)
 	query.Set(xhttp.AmzCredential, credential)
 	query.Set(xhttp.AmzDate, dateStr)
@@ -1950,7 +4502,7 @@ func toWebAPIError(ctx context.Context, err error) APIError {
 			HTTPStatusCode: http.StatusBadRequest,
 			Description:    err.Error(),
 		}
-	case errInvalidArgument:
+	case errInvalidArgument, errImageNotTransformable, errNotificationTargetNotFound:
 		return APIError{
 			Code:           "InvalidArgument",
 			HTTPStatusCode: http.StatusBadRequest,
@@ -1962,8 +4514,16 @@ func toWebAPIError(ctx context.Context, err error) APIError {
 		return getAPIError(ErrInvalidEncryptionParameters)
 	case errObjectTampered:
 		return getAPIError(ErrObjectTampered)
+	case errInvalidSSEParameters, crypto.ErrInvalidCustomerAlgorithm, crypto.ErrMissingCustomerKey,
+		crypto.ErrMissingCustomerKeyMD5, crypto.ErrCustomerKeyMD5Mismatch, crypto.ErrInvalidCustomerKey,
+		crypto.ErrSecretKeyMismatch, crypto.ErrIncompatibleEncryptionMethod:
+		return toAPIError(ctx, err)
 	case errMethodNotAllowed:
 		return getAPIError(ErrMethodNotAllowed)
+	case errInvalidRange:
+		return getAPIError(ErrInvalidRange)
+	case errComposeInvalidSources, errComposeEncryptedSource:
+		return toAPIError(ctx, err)
 	}
 
 	// Convert error type to api error code.
@@ -1990,6 +4550,22 @@ func toWebAPIError(ctx context.Context, err error) APIError {
 		return getAPIError(ErrWriteQuorum)
 	case InsufficientReadQuorum:
 		return getAPIError(ErrReadQuorum)
+	case BucketReadOnly:
+		return getAPIError(ErrBucketReadOnly)
+	case BucketSuspended:
+		return getAPIError(ErrBucketSuspended)
+	case ObjectTooLarge:
+		return getAPIError(ErrEntityTooLarge)
+	case AnonymousUploadQuotaExceeded:
+		return getAPIError(ErrAnonymousUploadQuotaExceeded)
+	case ContentTypeNotAllowed:
+		return getAPIError(ErrContentTypeNotAllowed)
+	case PartTooSmall:
+		return getAPIError(ErrEntityTooSmall)
+	case PreConditionFailed:
+		return getAPIError(ErrPreconditionFailed)
+	case InvalidRange:
+		return getAPIError(ErrInvalidRange)
 	case NotImplemented:
 		return APIError{
 			Code:           "NotImplemented",
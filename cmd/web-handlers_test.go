@@ -21,22 +21,35 @@ import (
 	"bytes"
 	"context"
 	"crypto/md5"
+	"encoding/base64"
+	"encoding/csv"
 	"encoding/hex"
 	"encoding/json"
 	"fmt"
+	"image"
+	_ "image/jpeg"
+	"image/png"
 	"io"
 	"io/ioutil"
 	"net/http"
 	"net/http/httptest"
+	"net/url"
 	"reflect"
+	"sort"
 	"strconv"
 	"strings"
+	"sync"
 	"testing"
+	"time"
 
 	jwtgo "github.com/dgrijalva/jwt-go"
 	humanize "github.com/dustin/go-humanize"
+	miniogo "github.com/minio/minio-go/v6"
 	miniogopolicy "github.com/minio/minio-go/v6/pkg/policy"
+	"github.com/minio/minio/cmd/crypto"
 	"github.com/minio/minio/pkg/hash"
+	iampolicy "github.com/minio/minio/pkg/iam/policy"
+	"github.com/minio/minio/pkg/madmin"
 	"github.com/minio/minio/pkg/policy"
 	"github.com/minio/minio/pkg/policy/condition"
 )
@@ -245,6 +258,104 @@ func testServerInfoWebHandler(obj ObjectLayer, instanceType string, t TestErrHan
 	}
 }
 
+// Wrapper for calling admin web handlers as a delegated user
+func TestWebHandlerAdminActions(t *testing.T) {
+	ExecObjectLayerTest(t, testAdminActionsWebHandler)
+}
+
+// testAdminActionsWebHandler - Test admin web handlers are allowed to
+// users granted the matching admin actions only
+func testAdminActionsWebHandler(obj ObjectLayer, instanceType string, t TestErrHandler) {
+	// Register the API end points with XL/FS object layer.
+	apiRouter := initTestWebRPCEndPoint(obj)
+	globalIAMSys = NewIAMSys()
+	globalIAMSys.Init(obj)
+
+	delegatePolicy := iampolicy.Policy{
+		Version: iampolicy.DefaultVersion,
+		Statements: []iampolicy.Statement{
+			iampolicy.NewStatement(
+				policy.Allow,
+				iampolicy.NewActionSet(iampolicy.ServerInfoAdminAction, iampolicy.CreateUserAdminAction),
+				iampolicy.NewResourceSet(),
+				condition.NewFunctions(),
+			),
+		},
+	}
+	if err := globalIAMSys.SetPolicy("delegate", delegatePolicy); err != nil {
+		t.Fatal(err)
+	}
+	if err := globalIAMSys.SetUser("delegate", madmin.UserInfo{
+		SecretKey:  "delegate-secret",
+		PolicyName: "delegate",
+		Status:     madmin.AccountEnabled,
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := globalIAMSys.SetUser("readwrite-user", madmin.UserInfo{
+		SecretKey:  "readwrite-secret",
+		PolicyName: "readwrite",
+		Status:     madmin.AccountEnabled,
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	delegateAuth, err := getWebRPCToken(apiRouter, "delegate", "delegate-secret")
+	if err != nil {
+		t.Fatal("Cannot authenticate")
+	}
+	readWriteAuth, err := getWebRPCToken(apiRouter, "readwrite-user", "readwrite-secret")
+	if err != nil {
+		t.Fatal("Cannot authenticate")
+	}
+
+	testCases := []struct {
+		authorization string
+		rpcMethod     string
+		reply         interface{}
+		allowed       bool
+	}{
+		{delegateAuth, "Web.ServerInfo", &ServerInfoRep{}, true},
+		{delegateAuth, "Web.GenerateAuth", &GenerateAuthReply{}, true},
+		{delegateAuth, "Web.StorageInfo", &StorageInfoRep{}, true},
+		// Basic server and storage info stay available to users
+		// with the built-in policies.
+		{readWriteAuth, "Web.ServerInfo", &ServerInfoRep{}, true},
+		{readWriteAuth, "Web.StorageInfo", &StorageInfoRep{}, true},
+		{readWriteAuth, "Web.GenerateAuth", &GenerateAuthReply{}, false},
+	}
+	for i, testCase := range testCases {
+		rec := httptest.NewRecorder()
+		req, err := newTestWebRPCRequest(testCase.rpcMethod, testCase.authorization, &WebGenericArgs{})
+		if err != nil {
+			t.Fatalf("Test %d: Failed to create HTTP request: <ERROR> %v", i+1, err)
+		}
+		apiRouter.ServeHTTP(rec, req)
+		err = getTestWebRPCResponse(rec, testCase.reply)
+		if testCase.allowed && err != nil {
+			t.Errorf("Test %d: %s expected to be allowed, failed with %v", i+1, testCase.rpcMethod, err)
+		}
+		if !testCase.allowed && (err == nil || err.Error() != errAccessDenied.Error()) {
+			t.Errorf("Test %d: %s expected to fail with %v, got %v", i+1, testCase.rpcMethod, errAccessDenied, err)
+		}
+	}
+
+	// Owner-level details are only returned with the matching admin action.
+	if reply := testCases[0].reply.(*ServerInfoRep); reply.MinioMemory == "" {
+		t.Errorf("Expected server memory info for delegated user")
+	}
+	if reply := testCases[2].reply.(*StorageInfoRep); reply.StorageInfo.Backend.Type != Unknown {
+		t.Errorf("Expected no backend info without %s, got %v", iampolicy.StorageInfoAdminAction, reply.StorageInfo.Backend.Type)
+	}
+	if reply := testCases[3].reply.(*ServerInfoRep); reply.MinioVersion == "" || reply.MinioMemory != "" {
+		t.Errorf("Expected only basic server info for readwrite user, got %+v", reply)
+	}
+	if reply := testCases[4].reply.(*StorageInfoRep); reply.StorageInfo.Total == 0 || reply.StorageInfo.Backend.Type != Unknown {
+		t.Errorf("Expected only disk usage for readwrite user, got %+v", reply.StorageInfo)
+	}
+}
+
 // Wrapper for calling MakeBucket Web Handler
 func TestWebHandlerMakeBucket(t *testing.T) {
 	ExecObjectLayerTest(t, testMakeBucketWebHandler)
@@ -448,6 +559,30 @@ func testListBucketsWebHandler(obj ObjectLayer, instanceType string, t TestErrHa
 	if listBucketsReply.Buckets[0].Name != bucketName {
 		t.Fatalf("Found another bucket other than already created by MakeBucket")
 	}
+	if listBucketsReply.Buckets[0].Access != string(miniogopolicy.BucketPolicyNone) {
+		t.Fatalf("Expected no access to a bucket without policy, got %s", listBucketsReply.Buckets[0].Access)
+	}
+	if bucketInfo := listBucketsReply.Buckets[0]; bucketInfo.ObjectLockMode != "" || bucketInfo.DefaultRetention != "" ||
+		bucketInfo.Versioning != webBucketVersioningUnsupported || bucketInfo.WORM || bucketInfo.Mode != "" {
+		t.Fatalf("Expected a bucket without object lock nor versioning in read-write mode, got %v", bucketInfo)
+	}
+
+	// WORM mode is reported apart from object locking.
+	globalWORMEnabled = true
+	defer func() { globalWORMEnabled = false }()
+	rec = httptest.NewRecorder()
+	req, err = newTestWebRPCRequest("Web.ListBuckets", authorization, &WebGenericArgs{})
+	if err != nil {
+		t.Fatalf("Failed to create HTTP request: <ERROR> %v", err)
+	}
+	apiRouter.ServeHTTP(rec, req)
+	listBucketsReply = &ListBucketsRep{}
+	if err = getTestWebRPCResponse(rec, &listBucketsReply); err != nil {
+		t.Fatalf("Failed, %v", err)
+	}
+	if bucketInfo := listBucketsReply.Buckets[0]; !bucketInfo.WORM || bucketInfo.ObjectLockMode != "" {
+		t.Fatalf("Expected a bucket in WORM mode without object lock mode, got %v", bucketInfo)
+	}
 }
 
 // Wrapper for calling ListObjects Web Handler
@@ -555,6 +690,174 @@ func testListObjectsWebHandler(obj ObjectLayer, instanceType string, t TestErrHa
 	verifyReply(reply)
 }
 
+// Wrapper for calling paged ListObjects Web Handler
+func TestWebHandlerListObjectsPaged(t *testing.T) {
+	ExecObjectLayerTest(t, testListObjectsPagedWebHandler)
+}
+
+// testListObjectsPagedWebHandler - Test ListObjects web handler with MaxKeys
+func testListObjectsPagedWebHandler(obj ObjectLayer, instanceType string, t TestErrHandler) {
+	apiRouter := initTestWebRPCEndPoint(obj)
+	credentials := globalServerConfig.GetCredential()
+
+	authorization, err := getWebRPCToken(apiRouter, credentials.AccessKey, credentials.SecretKey)
+	if err != nil {
+		t.Fatal("Cannot authenticate")
+	}
+
+	bucketName := getRandomBucketName()
+	if err = obj.MakeBucketWithLocation(context.Background(), bucketName, ""); err != nil {
+		t.Fatalf("%s : %s", instanceType, err)
+	}
+	keys := []string{"a", "b/c", "d", "e", "f"}
+	for _, key := range keys {
+		_, err = obj.PutObject(context.Background(), bucketName, key, mustGetPutObjReader(t, bytes.NewReader([]byte("data")), 4, "", ""), ObjectOptions{})
+		if err != nil {
+			t.Fatalf("Was not able to upload an object, %v", err)
+		}
+	}
+
+	var listed []string
+	marker := ""
+	for pages := 0; ; pages++ {
+		if pages == len(keys) {
+			t.Fatalf("%s: Expected listing to finish", instanceType)
+		}
+		rec := httptest.NewRecorder()
+		req, err := newTestWebRPCRequest("Web.ListObjects", authorization, ListObjectsArgs{BucketName: bucketName, Marker: marker, MaxKeys: 2})
+		if err != nil {
+			t.Fatalf("Failed to create HTTP request: <ERROR> %v", err)
+		}
+		apiRouter.ServeHTTP(rec, req)
+		reply := &ListObjectsRep{}
+		if err = getTestWebRPCResponse(rec, &reply); err != nil {
+			t.Fatalf("%s: %v", instanceType, err)
+		}
+		if len(reply.Objects) > 2 {
+			t.Fatalf("%s: Expected at most 2 entries, got %d", instanceType, len(reply.Objects))
+		}
+		for _, o := range reply.Objects {
+			listed = append(listed, o.Key)
+		}
+		if !reply.IsTruncated {
+			break
+		}
+		marker = reply.NextMarker
+	}
+
+	sort.Strings(listed)
+	expected := []string{"a", "b/", "d", "e", "f"}
+	if !reflect.DeepEqual(listed, expected) {
+		t.Fatalf("%s: Expected %v, got %v", instanceType, expected, listed)
+	}
+}
+
+// Tests the concurrent listing of remote federated buckets.
+func TestListRemoteObjects(t *testing.T) {
+	testServer := StartTestServer(t, "FS")
+	defer testServer.Stop()
+
+	bucketName := getRandomBucketName()
+	if err := testServer.Obj.MakeBucketWithLocation(context.Background(), bucketName, ""); err != nil {
+		t.Fatal(err)
+	}
+	// Keys around and on the range boundaries.
+	keys := []string{"!", "0", "00", "4z", "5/a", "5/b", "A", "Gz", "H/x", "O", "V", "a/b/c", "h", "hh", "o", "v", "z", "~",
+		"dir/0", "dir/5", "dir/a/b", "dir/z"}
+	for _, key := range keys {
+		_, err := testServer.Obj.PutObject(context.Background(), bucketName, key, mustGetPutObjReader(t, bytes.NewReader([]byte("data")), 4, "", ""), ObjectOptions{})
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	u, err := url.Parse(testServer.Server.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	core, err := miniogo.NewCore(u.Host, testServer.AccessKey, testServer.SecretKey, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	testCases := []struct {
+		prefix    string
+		maxKeys   int
+		expected  []string
+		truncated bool
+	}{
+		{"", maxRemoteObjectList, []string{"!", "0", "00", "4z", "5/", "A", "Gz", "H/", "O", "V", "a/", "dir/", "h", "hh", "o", "v", "z", "~"}, false},
+		{"dir/", maxRemoteObjectList, []string{"dir/0", "dir/5", "dir/a/", "dir/z"}, false},
+		{"none/", maxRemoteObjectList, nil, false},
+		{"", 5, []string{"!", "0", "00", "4z", "5/"}, true},
+		{"dir/", 4, []string{"dir/0", "dir/5", "dir/a/", "dir/z"}, false},
+	}
+	for i, testCase := range testCases {
+		objects, truncated, err := listRemoteObjects(core, bucketName, testCase.prefix, testCase.maxKeys)
+		if err != nil {
+			t.Fatalf("Test %d: %v", i+1, err)
+		}
+		var listed []string
+		for _, o := range objects {
+			listed = append(listed, o.Key)
+		}
+		if !reflect.DeepEqual(listed, testCase.expected) {
+			t.Errorf("Test %d: Expected %v, got %v", i+1, testCase.expected, listed)
+		}
+		if truncated != testCase.truncated {
+			t.Errorf("Test %d: Expected truncated %v, got %v", i+1, testCase.truncated, truncated)
+		}
+	}
+}
+
+func TestRemoveObjectsConcurrently(t *testing.T) {
+	var objects []string
+	for i := 0; i < 4*webRemoveObjectConcurrency; i++ {
+		objects = append(objects, fmt.Sprintf("dir/object-%d", i))
+	}
+
+	var mu sync.Mutex
+	var running, maxRunning int
+	removed := make(map[string]bool)
+	removeObject := func(object string) error {
+		mu.Lock()
+		running++
+		if running > maxRunning {
+			maxRunning = running
+		}
+		mu.Unlock()
+
+		time.Sleep(time.Millisecond)
+
+		mu.Lock()
+		defer mu.Unlock()
+		running--
+		removed[object] = true
+		switch object {
+		case "dir/object-3":
+			return ObjectNotFound{Bucket: "bucket", Object: object}
+		case "dir/object-10", "dir/object-20":
+			return fmt.Errorf("unable to remove %s", object)
+		}
+		return nil
+	}
+
+	err := removeObjectsConcurrently(objects, removeObject)
+	if err == nil || err.Error() != "unable to remove dir/object-10" {
+		t.Fatalf("Expected the error of the first object not removed, got %v", err)
+	}
+	if len(removed) != len(objects) {
+		t.Errorf("Expected %d objects to be removed, got %d", len(objects), len(removed))
+	}
+	if maxRunning > webRemoveObjectConcurrency {
+		t.Errorf("Expected at most %d concurrent removals, got %d", webRemoveObjectConcurrency, maxRunning)
+	}
+
+	if err = removeObjectsConcurrently(objects[:5], removeObject); err != nil {
+		t.Errorf("Expected objects not found to be ignored, got %v", err)
+	}
+}
+
 // Wrapper for calling RemoveObject Web Handler
 func TestWebHandlerRemoveObject(t *testing.T) {
 	ExecObjectLayerTest(t, testRemoveObjectWebHandler)
@@ -598,7 +901,7 @@ func testRemoveObjectWebHandler(obj ObjectLayer, instanceType string, t TestErrH
 	}
 
 	removeRequest := RemoveObjectArgs{BucketName: bucketName, Objects: []string{"a/", "object"}}
-	removeReply := &WebGenericRep{}
+	removeReply := &RemoveObjectRep{}
 	req, err := newTestWebRPCRequest("Web.RemoveObject", authorization, removeRequest)
 	if err != nil {
 		t.Fatalf("Failed to create HTTP request: <ERROR> %v", err)
@@ -613,12 +916,37 @@ func testRemoveObjectWebHandler(obj ObjectLayer, instanceType string, t TestErrH
 	}
 
 	removeRequest = RemoveObjectArgs{BucketName: bucketName, Objects: []string{"a/", "object"}}
-	removeReply = &WebGenericRep{}
+	removeReply = &RemoveObjectRep{}
+	req, err = newTestWebRPCRequest("Web.RemoveObject", authorization, removeRequest)
+	if err != nil {
+		t.Fatalf("Failed to create HTTP request: <ERROR> %v", err)
+	}
+	apiRouter.ServeHTTP(rec, req)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("Expected the response status to be 200, but instead found `%d`", rec.Code)
+	}
+	err = getTestWebRPCResponse(rec, &removeReply)
+	if err != nil {
+		t.Fatalf("Failed, %v", err)
+	}
+
+	// Retained objects are reported instead of failing the whole batch.
+	for _, objectName := range []string{"worm/object", "object"} {
+		_, err = obj.PutObject(context.Background(), bucketName, objectName, mustGetPutObjReader(t, bytes.NewReader(data), int64(len(data)), metadata["etag"], ""), ObjectOptions{UserDefined: metadata})
+		if err != nil {
+			t.Fatalf("Was not able to upload an object, %v", err)
+		}
+	}
+	globalWORMEnabled = true
+	rec = httptest.NewRecorder()
+	removeRequest = RemoveObjectArgs{BucketName: bucketName, Objects: []string{"worm/", "object", "missing"}}
+	removeReply = &RemoveObjectRep{}
 	req, err = newTestWebRPCRequest("Web.RemoveObject", authorization, removeRequest)
 	if err != nil {
 		t.Fatalf("Failed to create HTTP request: <ERROR> %v", err)
 	}
 	apiRouter.ServeHTTP(rec, req)
+	globalWORMEnabled = false
 	if rec.Code != http.StatusOK {
 		t.Fatalf("Expected the response status to be 200, but instead found `%d`", rec.Code)
 	}
@@ -626,9 +954,21 @@ func testRemoveObjectWebHandler(obj ObjectLayer, instanceType string, t TestErrH
 	if err != nil {
 		t.Fatalf("Failed, %v", err)
 	}
+	if len(removeReply.Errors) != 2 {
+		t.Fatalf("Expected 2 retained objects, got %v", removeReply.Errors)
+	}
+	for i, objectName := range []string{"worm/object", "object"} {
+		if removeReply.Errors[i].ObjectName != objectName || removeReply.Errors[i].Reason != wormRemoveObjectReason {
+			t.Fatalf("Unexpected retained object %v", removeReply.Errors[i])
+		}
+	}
+	if _, err = obj.GetObjectInfo(context.Background(), bucketName, "worm/object", ObjectOptions{}); err != nil {
+		t.Fatalf("Expected retained object to exist, %v", err)
+	}
 
+	rec = httptest.NewRecorder()
 	removeRequest = RemoveObjectArgs{BucketName: bucketName}
-	removeReply = &WebGenericRep{}
+	removeReply = &RemoveObjectRep{}
 	req, err = newTestWebRPCRequest("Web.RemoveObject", authorization, removeRequest)
 	if err != nil {
 		t.Fatalf("Failed to create HTTP request: <ERROR> %v", err)
@@ -646,94 +986,605 @@ func testRemoveObjectWebHandler(obj ObjectLayer, instanceType string, t TestErrH
 	}
 }
 
-// Wrapper for calling Generate Auth Handler
-func TestWebHandlerGenerateAuth(t *testing.T) {
-	ExecObjectLayerTest(t, testGenerateAuthWebHandler)
+// Wrapper for calling the trash Web Handlers
+func TestWebHandlerTrash(t *testing.T) {
+	ExecObjectLayerTest(t, testTrashWebHandler)
 }
 
-// testGenerateAuthWebHandler - Test GenerateAuth web handler
-func testGenerateAuthWebHandler(obj ObjectLayer, instanceType string, t TestErrHandler) {
+// testTrashWebHandler - Test SetBucketTrash, ListTrash and RestoreObject web handlers
+func testTrashWebHandler(obj ObjectLayer, instanceType string, t TestErrHandler) {
 	// Register the API end points with XL/FS object layer.
 	apiRouter := initTestWebRPCEndPoint(obj)
 	credentials := globalServerConfig.GetCredential()
 
-	rec := httptest.NewRecorder()
 	authorization, err := getWebRPCToken(apiRouter, credentials.AccessKey, credentials.SecretKey)
 	if err != nil {
 		t.Fatal("Cannot authenticate")
 	}
 
-	generateAuthRequest := WebGenericArgs{}
-	generateAuthReply := &GenerateAuthReply{}
-	req, err := newTestWebRPCRequest("Web.GenerateAuth", authorization, generateAuthRequest)
-	if err != nil {
-		t.Fatalf("Failed to create HTTP request: <ERROR> %v", err)
+	call := func(method string, args interface{}, reply interface{}) error {
+		rec := httptest.NewRecorder()
+		req, err := newTestWebRPCRequest(method, authorization, args)
+		if err != nil {
+			t.Fatalf("Failed to create HTTP request: <ERROR> %v", err)
+		}
+		apiRouter.ServeHTTP(rec, req)
+		if rec.Code != http.StatusOK {
+			t.Fatalf("Expected the response status to be 200, but instead found `%d`", rec.Code)
+		}
+		return getTestWebRPCResponse(rec, reply)
 	}
-	apiRouter.ServeHTTP(rec, req)
-	if rec.Code != http.StatusOK {
-		t.Fatalf("Expected the response status to be 200, but instead found `%d`", rec.Code)
+
+	ctx := context.Background()
+	bucketName := getRandomBucketName()
+	if err = obj.MakeBucketWithLocation(ctx, bucketName, ""); err != nil {
+		t.Fatalf("%s : %s", instanceType, err)
 	}
-	err = getTestWebRPCResponse(rec, &generateAuthReply)
+	data := []byte("hello")
+	for _, objectName := range []string{"a/object", "object"} {
+		_, err = obj.PutObject(ctx, bucketName, objectName, mustGetPutObjReader(t, bytes.NewReader(data), int64(len(data)), "", ""), ObjectOptions{UserDefined: map[string]string{"content-type": "text/plain"}})
+		if err != nil {
+			t.Fatalf("Was not able to upload an object, %v", err)
+		}
+	}
+
+	if err = call("Web.SetBucketTrash", SetBucketTrashArgs{BucketName: bucketName, RetentionDays: -1}, &WebGenericRep{}); err == nil {
+		t.Fatal("Expected negative retention to be rejected")
+	}
+	if err = call("Web.SetBucketTrash", SetBucketTrashArgs{BucketName: bucketName, RetentionDays: 7}, &WebGenericRep{}); err != nil {
+		t.Fatalf("Failed, %v", err)
+	}
+	trashReply := &GetBucketTrashRep{}
+	if err = call("Web.GetBucketTrash", BucketTrashArgs{BucketName: bucketName}, trashReply); err != nil {
+		t.Fatalf("Failed, %v", err)
+	}
+	if trashReply.RetentionDays != 7 {
+		t.Fatalf("Expected 7 retention days, got %d", trashReply.RetentionDays)
+	}
+
+	if err = call("Web.RemoveObject", RemoveObjectArgs{BucketName: bucketName, Objects: []string{"a/", "object"}}, &RemoveObjectRep{}); err != nil {
+		t.Fatalf("Failed, %v", err)
+	}
+	for _, objectName := range []string{"a/object", "object"} {
+		if _, err = obj.GetObjectInfo(ctx, bucketName, objectName, ObjectOptions{}); !isErrObjectNotFound(err) {
+			t.Fatalf("Expected %s to be removed, got %v", objectName, err)
+		}
+	}
+
+	listReply := &ListTrashRep{}
+	if err = call("Web.ListTrash", ListTrashArgs{BucketName: bucketName}, listReply); err != nil {
+		t.Fatalf("Failed, %v", err)
+	}
+	if len(listReply.Objects) != 2 || listReply.Objects[0].Key != "a/object" || listReply.Objects[1].Key != "object" {
+		t.Fatalf("Unexpected trash objects %v", listReply.Objects)
+	}
+	if trashObject := listReply.Objects[1]; trashObject.Size != int64(len(data)) ||
+		!trashObject.ExpiresAt.Equal(trashObject.DeletedAt.AddDate(0, 0, 7)) {
+		t.Fatalf("Unexpected trash object %v", trashObject)
+	}
+
+	if err = call("Web.RestoreObject", RestoreObjectArgs{BucketName: bucketName, Objects: []string{"object"}}, &WebGenericRep{}); err != nil {
+		t.Fatalf("Failed, %v", err)
+	}
+	var buffer bytes.Buffer
+	if err = obj.GetObject(ctx, bucketName, "object", 0, -1, &buffer, "", ObjectOptions{}); err != nil {
+		t.Fatalf("Expected restored object, %v", err)
+	}
+	if !bytes.Equal(buffer.Bytes(), data) {
+		t.Fatalf("Unexpected restored object content %q", buffer.String())
+	}
+	if err = call("Web.RestoreObject", RestoreObjectArgs{BucketName: bucketName, Objects: []string{"object"}}, &WebGenericRep{}); err == nil {
+		t.Fatal("Expected object no longer in the trash not to be restored")
+	}
+
+	// Objects are kept within the retention period.
+	if err = purgeTrash(ctx, obj); err != nil {
+		t.Fatal(err)
+	}
+	entries, _, err := listTrash(ctx, obj, bucketName, "", "", maxObjectList)
 	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 1 {
+		t.Fatalf("Expected 1 object in the trash, got %d", len(entries))
+	}
+
+	// Objects are purged once the trash is disabled.
+	if err = call("Web.SetBucketTrash", SetBucketTrashArgs{BucketName: bucketName}, &WebGenericRep{}); err != nil {
 		t.Fatalf("Failed, %v", err)
 	}
+	if err = purgeTrash(ctx, obj); err != nil {
+		t.Fatal(err)
+	}
+	if entries, _, err = listTrash(ctx, obj, bucketName, "", "", maxObjectList); err != nil || len(entries) != 0 {
+		t.Fatalf("Expected the trash to be purged, got %v, %v", entries, err)
+	}
 
-	if generateAuthReply.AccessKey == "" || generateAuthReply.SecretKey == "" {
-		t.Fatalf("Failed to generate auth keys")
+	// Without the trash objects are removed permanently.
+	if err = call("Web.RemoveObject", RemoveObjectArgs{BucketName: bucketName, Objects: []string{"object"}}, &RemoveObjectRep{}); err != nil {
+		t.Fatalf("Failed, %v", err)
+	}
+	if entries, _, err = listTrash(ctx, obj, bucketName, "", "", maxObjectList); err != nil || len(entries) != 0 {
+		t.Fatalf("Expected the trash to be empty, got %v, %v", entries, err)
 	}
 }
 
-// Wrapper for calling Set Auth Handler
-func TestWebHandlerSetAuth(t *testing.T) {
-	ExecObjectLayerTest(t, testSetAuthWebHandler)
+// Wrapper for calling ListObjectVersions Web Handler
+func TestWebHandlerListObjectVersions(t *testing.T) {
+	ExecObjectLayerTest(t, testListObjectVersionsWebHandler)
 }
 
-// testSetAuthWebHandler - Test SetAuth web handler
-func testSetAuthWebHandler(obj ObjectLayer, instanceType string, t TestErrHandler) {
+// testListObjectVersionsWebHandler - Test ListObjectVersions web handler
+func testListObjectVersionsWebHandler(obj ObjectLayer, instanceType string, t TestErrHandler) {
 	// Register the API end points with XL/FS object layer.
 	apiRouter := initTestWebRPCEndPoint(obj)
 	credentials := globalServerConfig.GetCredential()
 
-	rec := httptest.NewRecorder()
 	authorization, err := getWebRPCToken(apiRouter, credentials.AccessKey, credentials.SecretKey)
 	if err != nil {
 		t.Fatal("Cannot authenticate")
 	}
 
-	testCases := []struct {
-		currentAccessKey string
-		currentSecretKey string
-		newAccessKey     string
-		newSecretKey     string
-		success          bool
-	}{
-		{"", "", "", "", false},
-		{"1", "1", "1", "1", false},
-		{credentials.AccessKey, credentials.SecretKey, "azerty", "bar", false},
-		{credentials.AccessKey, credentials.SecretKey, "azerty", "foooooooooooooo", true},
+	ctx := context.Background()
+	bucketName := getRandomBucketName()
+	if err = obj.MakeBucketWithLocation(ctx, bucketName, ""); err != nil {
+		t.Fatalf("%s : %s", instanceType, err)
+	}
+	data := []byte("hello")
+	objInfo, err := obj.PutObject(ctx, bucketName, "object", mustGetPutObjReader(t, bytes.NewReader(data), int64(len(data)), "", ""), ObjectOptions{})
+	if err != nil {
+		t.Fatalf("Was not able to upload an object, %v", err)
 	}
 
-	// Iterating over the test cases, calling the function under test and asserting the response.
-	for i, testCase := range testCases {
-		setAuthRequest := SetAuthArgs{CurrentAccessKey: testCase.currentAccessKey, CurrentSecretKey: testCase.currentSecretKey, NewAccessKey: testCase.newAccessKey, NewSecretKey: testCase.newSecretKey}
-		setAuthReply := &SetAuthReply{}
-		req, err := newTestWebRPCRequest("Web.SetAuth", authorization, setAuthRequest)
+	listVersions := func(token, objectName string) (*ListObjectVersionsRep, error) {
+		rec := httptest.NewRecorder()
+		req, err := newTestWebRPCRequest("Web.ListObjectVersions", token, ListObjectVersionsArgs{BucketName: bucketName, ObjectName: objectName})
 		if err != nil {
-			t.Fatalf("Test %d: Failed to create HTTP request: <ERROR> %v", i+1, err)
+			t.Fatalf("Failed to create HTTP request: <ERROR> %v", err)
 		}
 		apiRouter.ServeHTTP(rec, req)
 		if rec.Code != http.StatusOK {
-			t.Fatalf("Test %d: Expected the response status to be 200, but instead found `%d`", i+1, rec.Code)
-		}
-		err = getTestWebRPCResponse(rec, &setAuthReply)
-		if testCase.success && err != nil {
-			t.Fatalf("Test %d: Supposed to succeed but failed, %v", i+1, err)
-		}
-		if !testCase.success && err == nil {
-			t.Fatalf("Test %d: Supposed to fail but succeeded, %v", i+1, err)
-		}
-		if testCase.success && setAuthReply.Token == "" {
-			t.Fatalf("Test %d: Failed to set auth keys", i+1)
+			t.Fatalf("Expected the response status to be 200, but instead found `%d`", rec.Code)
+		}
+		reply := &ListObjectVersionsRep{}
+		return reply, getTestWebRPCResponse(rec, reply)
+	}
+
+	reply, err := listVersions(authorization, "object")
+	if err != nil {
+		t.Fatalf("Failed, %v", err)
+	}
+	if len(reply.Versions) != 1 {
+		t.Fatalf("Expected 1 version, got %v", reply.Versions)
+	}
+	if version := reply.Versions[0]; version.VersionID != "null" || version.Size != int64(len(data)) ||
+		!version.LastModified.Equal(objInfo.ModTime) || !version.IsLatest || version.IsDeleteMarker {
+		t.Fatalf("Unexpected version %v", version)
+	}
+
+	// An object which does not exist has no versions.
+	if reply, err = listVersions(authorization, "missing"); err != nil || len(reply.Versions) != 0 {
+		t.Fatalf("Expected no versions, got %v, %v", reply.Versions, err)
+	}
+
+	// Unauthenticated requests fail.
+	if _, err = listVersions("", "object"); err == nil {
+		t.Fatal("Expected an unauthenticated request to fail")
+	}
+}
+
+// Wrapper for calling Search Web Handler
+func TestWebHandlerSearch(t *testing.T) {
+	ExecObjectLayerTest(t, testSearchWebHandler)
+}
+
+// testSearchWebHandler - Test Search web handler
+func testSearchWebHandler(obj ObjectLayer, instanceType string, t TestErrHandler) {
+	prevSearchIndex := globalSearchIndex
+	defer func() {
+		globalSearchIndexEnabled = false
+		globalSearchIndex = prevSearchIndex
+	}()
+
+	// Register the API end points with XL/FS object layer.
+	apiRouter := initTestWebRPCEndPoint(obj)
+	credentials := globalServerConfig.GetCredential()
+
+	authorization, err := getWebRPCToken(apiRouter, credentials.AccessKey, credentials.SecretKey)
+	if err != nil {
+		t.Fatal("Cannot authenticate")
+	}
+
+	bucketName := getRandomBucketName()
+	if err = obj.MakeBucketWithLocation(context.Background(), bucketName, ""); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	data := []byte("hello")
+	for _, object := range []string{"2019/invoice-2019.pdf", "notes.txt"} {
+		if _, err = obj.PutObject(context.Background(), bucketName, object, mustGetPutObjReader(t, bytes.NewReader(data), int64(len(data)), "", ""), ObjectOptions{}); err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+	}
+
+	search := func(query string) (*SearchRep, error) {
+		rec := httptest.NewRecorder()
+		searchRequest := SearchArgs{Query: query}
+		searchReply := &SearchRep{}
+		req, err := newTestWebRPCRequest("Web.Search", authorization, searchRequest)
+		if err != nil {
+			t.Fatalf("Failed to create HTTP request: <ERROR> %v", err)
+		}
+		apiRouter.ServeHTTP(rec, req)
+		if rec.Code != http.StatusOK {
+			t.Fatalf("Expected the response status to be 200, but instead found `%d`", rec.Code)
+		}
+		err = getTestWebRPCResponse(rec, &searchReply)
+		return searchReply, err
+	}
+
+	if _, err = search("invoice"); err == nil {
+		t.Fatal("Expected an error with the search index disabled")
+	}
+
+	globalSearchIndexEnabled = true
+	globalSearchIndex = &searchIndexSys{indexes: make(map[string]*objectNameIndex)}
+	index := globalSearchIndex.newIndex()
+	// Deleted since indexed.
+	index.add(bucketName, "invoice-2019-old.pdf")
+	index.add(bucketName, "2019/invoice-2019.pdf")
+	index.add(bucketName, "notes.txt")
+	globalSearchIndex.update("disk", index)
+
+	testCases := []struct {
+		query   string
+		objects []string
+	}{
+		{"Invoice-2019", []string{"2019/invoice-2019.pdf"}},
+		{"notes", []string{"notes.txt"}},
+		{"invoice-2018", nil},
+		{"", nil},
+	}
+	for i, testCase := range testCases {
+		reply, err := search(testCase.query)
+		if err != nil {
+			t.Fatalf("Test %d: unexpected error: %v", i+1, err)
+		}
+		if len(reply.Objects) != len(testCase.objects) {
+			t.Fatalf("Test %d: expected %v, got %v", i+1, testCase.objects, reply.Objects)
+		}
+		for j, object := range reply.Objects {
+			if object.BucketName != bucketName || object.Key != testCase.objects[j] || object.Size != int64(len(data)) {
+				t.Fatalf("Test %d: unexpected object %v", i+1, object)
+			}
+		}
+	}
+}
+
+// Wrapper for calling PrefixStat handler
+func TestWebHandlerPrefixStat(t *testing.T) {
+	ExecObjectLayerTest(t, testPrefixStatWebHandler)
+}
+
+// testPrefixStatWebHandler - Test PrefixStat web handler
+func testPrefixStatWebHandler(obj ObjectLayer, instanceType string, t TestErrHandler) {
+	// Register the API end points with XL/FS object layer.
+	apiRouter := initTestWebRPCEndPoint(obj)
+	credentials := globalServerConfig.GetCredential()
+
+	authorization, err := getWebRPCToken(apiRouter, credentials.AccessKey, credentials.SecretKey)
+	if err != nil {
+		t.Fatal("Cannot authenticate")
+	}
+
+	bucketName := getRandomBucketName()
+	if err = obj.MakeBucketWithLocation(context.Background(), bucketName, ""); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	objects := map[string][]byte{
+		"photos/a.jpg":      []byte("hello"),
+		"photos/2019/b.jpg": []byte("hello world"),
+		"notes.txt":         []byte("notes"),
+	}
+	for object, data := range objects {
+		if _, err = obj.PutObject(context.Background(), bucketName, object, mustGetPutObjReader(t, bytes.NewReader(data), int64(len(data)), "", ""), ObjectOptions{}); err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+	}
+
+	prefixStat := func(bucket, prefix, token string) (*PrefixStatRep, error) {
+		rec := httptest.NewRecorder()
+		prefixStatRequest := PrefixStatArgs{BucketName: bucket, Prefix: prefix}
+		prefixStatReply := &PrefixStatRep{}
+		req, err := newTestWebRPCRequest("Web.PrefixStat", token, prefixStatRequest)
+		if err != nil {
+			t.Fatalf("Failed to create HTTP request: <ERROR> %v", err)
+		}
+		apiRouter.ServeHTTP(rec, req)
+		if rec.Code != http.StatusOK {
+			t.Fatalf("Expected the response status to be 200, but instead found `%d`", rec.Code)
+		}
+		err = getTestWebRPCResponse(rec, &prefixStatReply)
+		return prefixStatReply, err
+	}
+
+	testCases := []struct {
+		bucketName string
+		prefix     string
+		objects    int64
+		size       int64
+		expectErr  bool
+	}{
+		{bucketName, "photos/", 2, 16, false},
+		{bucketName, "photos/2019/", 1, 11, false},
+		{bucketName, "", 3, 21, false},
+		{bucketName, "videos/", 0, 0, false},
+		{"nonexistent-bucket", "", 0, 0, true},
+		{".minio.sys", "", 0, 0, true},
+	}
+	for i, testCase := range testCases {
+		reply, err := prefixStat(testCase.bucketName, testCase.prefix, authorization)
+		if testCase.expectErr {
+			if err == nil {
+				t.Fatalf("Test %d: expected an error", i+1)
+			}
+			continue
+		}
+		if err != nil {
+			t.Fatalf("Test %d: unexpected error: %v", i+1, err)
+		}
+		if reply.Objects != testCase.objects || reply.Size != testCase.size || reply.Truncated {
+			t.Fatalf("Test %d: expected %d objects of %d bytes, got %+v", i+1, testCase.objects, testCase.size, reply)
+		}
+	}
+
+	// Anonymous requests are denied without a bucket policy.
+	if _, err = prefixStat(bucketName, "photos/", ""); err == nil {
+		t.Fatal("Expected an error for an anonymous request")
+	}
+}
+
+// Wrapper for calling ListIncompleteUploads and AbortIncompleteUpload handlers
+func TestWebHandlerIncompleteUploads(t *testing.T) {
+	ExecObjectLayerTest(t, testIncompleteUploadsWebHandler)
+}
+
+// testIncompleteUploadsWebHandler - Test ListIncompleteUploads and AbortIncompleteUpload web handlers
+func testIncompleteUploadsWebHandler(obj ObjectLayer, instanceType string, t TestErrHandler) {
+	// Register the API end points with XL/FS object layer.
+	apiRouter := initTestWebRPCEndPoint(obj)
+	credentials := globalServerConfig.GetCredential()
+
+	authorization, err := getWebRPCToken(apiRouter, credentials.AccessKey, credentials.SecretKey)
+	if err != nil {
+		t.Fatal("Cannot authenticate")
+	}
+
+	bucketName := getRandomBucketName()
+	objectName := "object"
+	partSize := 1 * humanize.KiByte
+
+	// Create bucket.
+	err = obj.MakeBucketWithLocation(context.Background(), bucketName, "")
+	if err != nil {
+		// failed to create newbucket, abort.
+		t.Fatalf("%s : %s", instanceType, err)
+	}
+
+	var uploadIDs []string
+	for i := 0; i < 2; i++ {
+		uploadID, err := obj.NewMultipartUpload(context.Background(), bucketName, objectName, ObjectOptions{})
+		if err != nil {
+			t.Fatalf("%s : %s", instanceType, err)
+		}
+		uploadIDs = append(uploadIDs, uploadID)
+	}
+
+	// Uploads of another object and of the same object in another bucket.
+	otherUploadID, err := obj.NewMultipartUpload(context.Background(), bucketName, "dir/other", ObjectOptions{})
+	if err != nil {
+		t.Fatalf("%s : %s", instanceType, err)
+	}
+	otherBucketName := getRandomBucketName()
+	if err = obj.MakeBucketWithLocation(context.Background(), otherBucketName, ""); err != nil {
+		t.Fatalf("%s : %s", instanceType, err)
+	}
+	if _, err = obj.NewMultipartUpload(context.Background(), otherBucketName, objectName, ObjectOptions{}); err != nil {
+		t.Fatalf("%s : %s", instanceType, err)
+	}
+
+	listUploads := func(objectName string) []WebUploadInfo {
+		rec := httptest.NewRecorder()
+		listRequest := ListIncompleteUploadsArgs{BucketName: bucketName, ObjectName: objectName}
+		listReply := &ListIncompleteUploadsRep{}
+		req, err := newTestWebRPCRequest("Web.ListIncompleteUploads", authorization, listRequest)
+		if err != nil {
+			t.Fatalf("Failed to create HTTP request: <ERROR> %v", err)
+		}
+		apiRouter.ServeHTTP(rec, req)
+		if rec.Code != http.StatusOK {
+			t.Fatalf("Expected the response status to be 200, but instead found `%d`", rec.Code)
+		}
+		if err = getTestWebRPCResponse(rec, &listReply); err != nil {
+			t.Fatalf("Failed, %v", err)
+		}
+		return listReply.Uploads
+	}
+
+	abortUploads := func(args AbortIncompleteUploadArgs) (int, error) {
+		rec := httptest.NewRecorder()
+		abortReply := &AbortIncompleteUploadRep{}
+		req, err := newTestWebRPCRequest("Web.AbortIncompleteUpload", authorization, args)
+		if err != nil {
+			t.Fatalf("Failed to create HTTP request: <ERROR> %v", err)
+		}
+		apiRouter.ServeHTTP(rec, req)
+		if rec.Code != http.StatusOK {
+			t.Fatalf("Expected the response status to be 200, but instead found `%d`", rec.Code)
+		}
+		err = getTestWebRPCResponse(rec, &abortReply)
+		return abortReply.Aborted, err
+	}
+
+	var initiated time.Time
+	for _, upload := range listUploads(objectName) {
+		if upload.UploadID == uploadIDs[0] {
+			initiated = upload.Initiated
+		}
+	}
+	data := bytes.Repeat([]byte("a"), partSize)
+	_, err = obj.PutObjectPart(context.Background(), bucketName, objectName, uploadIDs[0], 1, mustGetPutObjReader(t, bytes.NewReader(data), int64(len(data)), "", ""), ObjectOptions{})
+	if err != nil {
+		t.Fatalf("%s : %s", instanceType, err)
+	}
+
+	uploads := listUploads(objectName)
+	if len(uploads) != 2 {
+		t.Fatalf("Expected 2 incomplete uploads, found %d", len(uploads))
+	}
+	for _, upload := range uploads {
+		if upload.UploadID == uploadIDs[0] && !upload.Initiated.Equal(initiated) {
+			t.Errorf("Expected upload %s initiated time to remain %s, found %s", upload.UploadID, initiated, upload.Initiated)
+		}
+		var expectedSize int64
+		if upload.UploadID == uploadIDs[0] {
+			expectedSize = int64(partSize)
+		}
+		if upload.Size != expectedSize {
+			t.Errorf("Expected upload %s size to be %d, found %d", upload.UploadID, expectedSize, upload.Size)
+		}
+		if upload.Initiated.IsZero() {
+			t.Errorf("Expected upload %s initiated time to be set", upload.UploadID)
+		}
+	}
+
+	// Both upload ID and age were specified.
+	if _, err = abortUploads(AbortIncompleteUploadArgs{BucketName: bucketName, ObjectName: objectName, UploadID: uploadIDs[0], OlderThanDays: 1}); err == nil {
+		t.Fatalf("Expected abort with both upload ID and age to fail")
+	}
+
+	aborted, err := abortUploads(AbortIncompleteUploadArgs{BucketName: bucketName, ObjectName: objectName, UploadID: uploadIDs[0]})
+	if err != nil {
+		t.Fatalf("Failed, %v", err)
+	}
+	if aborted != 1 {
+		t.Fatalf("Expected 1 aborted upload, found %d", aborted)
+	}
+
+	// Remaining upload is not old enough to be aborted.
+	aborted, err = abortUploads(AbortIncompleteUploadArgs{BucketName: bucketName, ObjectName: objectName, OlderThanDays: 1})
+	if err != nil {
+		t.Fatalf("Failed, %v", err)
+	}
+	if aborted != 0 {
+		t.Fatalf("Expected no aborted uploads, found %d", aborted)
+	}
+
+	uploads = listUploads(objectName)
+	if len(uploads) != 1 || uploads[0].UploadID != uploadIDs[1] {
+		t.Fatalf("Expected only upload %s to remain, found %v", uploadIDs[1], uploads)
+	}
+
+	// Listing without object name lists the uploads of all objects in the bucket.
+	uploads = listUploads("")
+	if len(uploads) != 2 {
+		t.Fatalf("Expected 2 incomplete uploads in the bucket, found %v", uploads)
+	}
+	if uploads[0].Key != "dir/other" || uploads[0].UploadID != otherUploadID {
+		t.Errorf("Expected upload %s of dir/other, found %v", otherUploadID, uploads[0])
+	}
+	if uploads[1].Key != objectName || uploads[1].UploadID != uploadIDs[1] {
+		t.Errorf("Expected upload %s of %s, found %v", uploadIDs[1], objectName, uploads[1])
+	}
+}
+
+// Wrapper for calling Generate Auth Handler
+func TestWebHandlerGenerateAuth(t *testing.T) {
+	ExecObjectLayerTest(t, testGenerateAuthWebHandler)
+}
+
+// testGenerateAuthWebHandler - Test GenerateAuth web handler
+func testGenerateAuthWebHandler(obj ObjectLayer, instanceType string, t TestErrHandler) {
+	// Register the API end points with XL/FS object layer.
+	apiRouter := initTestWebRPCEndPoint(obj)
+	credentials := globalServerConfig.GetCredential()
+
+	rec := httptest.NewRecorder()
+	authorization, err := getWebRPCToken(apiRouter, credentials.AccessKey, credentials.SecretKey)
+	if err != nil {
+		t.Fatal("Cannot authenticate")
+	}
+
+	generateAuthRequest := WebGenericArgs{}
+	generateAuthReply := &GenerateAuthReply{}
+	req, err := newTestWebRPCRequest("Web.GenerateAuth", authorization, generateAuthRequest)
+	if err != nil {
+		t.Fatalf("Failed to create HTTP request: <ERROR> %v", err)
+	}
+	apiRouter.ServeHTTP(rec, req)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("Expected the response status to be 200, but instead found `%d`", rec.Code)
+	}
+	err = getTestWebRPCResponse(rec, &generateAuthReply)
+	if err != nil {
+		t.Fatalf("Failed, %v", err)
+	}
+
+	if generateAuthReply.AccessKey == "" || generateAuthReply.SecretKey == "" {
+		t.Fatalf("Failed to generate auth keys")
+	}
+}
+
+// Wrapper for calling Set Auth Handler
+func TestWebHandlerSetAuth(t *testing.T) {
+	ExecObjectLayerTest(t, testSetAuthWebHandler)
+}
+
+// testSetAuthWebHandler - Test SetAuth web handler
+func testSetAuthWebHandler(obj ObjectLayer, instanceType string, t TestErrHandler) {
+	// Register the API end points with XL/FS object layer.
+	apiRouter := initTestWebRPCEndPoint(obj)
+	credentials := globalServerConfig.GetCredential()
+
+	rec := httptest.NewRecorder()
+	authorization, err := getWebRPCToken(apiRouter, credentials.AccessKey, credentials.SecretKey)
+	if err != nil {
+		t.Fatal("Cannot authenticate")
+	}
+
+	testCases := []struct {
+		currentAccessKey string
+		currentSecretKey string
+		newAccessKey     string
+		newSecretKey     string
+		success          bool
+	}{
+		{"", "", "", "", false},
+		{"1", "1", "1", "1", false},
+		{credentials.AccessKey, credentials.SecretKey, "azerty", "bar", false},
+		{credentials.AccessKey, credentials.SecretKey, "azerty", "foooooooooooooo", true},
+	}
+
+	// Iterating over the test cases, calling the function under test and asserting the response.
+	for i, testCase := range testCases {
+		setAuthRequest := SetAuthArgs{CurrentAccessKey: testCase.currentAccessKey, CurrentSecretKey: testCase.currentSecretKey, NewAccessKey: testCase.newAccessKey, NewSecretKey: testCase.newSecretKey}
+		setAuthReply := &SetAuthReply{}
+		req, err := newTestWebRPCRequest("Web.SetAuth", authorization, setAuthRequest)
+		if err != nil {
+			t.Fatalf("Test %d: Failed to create HTTP request: <ERROR> %v", i+1, err)
+		}
+		apiRouter.ServeHTTP(rec, req)
+		if rec.Code != http.StatusOK {
+			t.Fatalf("Test %d: Expected the response status to be 200, but instead found `%d`", i+1, rec.Code)
+		}
+		err = getTestWebRPCResponse(rec, &setAuthReply)
+		if testCase.success && err != nil {
+			t.Fatalf("Test %d: Supposed to succeed but failed, %v", i+1, err)
+		}
+		if !testCase.success && err == nil {
+			t.Fatalf("Test %d: Supposed to fail but succeeded, %v", i+1, err)
+		}
+		if testCase.success && setAuthReply.Token == "" {
+			t.Fatalf("Test %d: Failed to set auth keys", i+1)
 		}
 	}
 }
@@ -906,6 +1757,58 @@ func testUploadWebHandler(obj ObjectLayer, instanceType string, t TestErrHandler
 	}
 }
 
+// Wrapper for calling Upload Handler with a decomposed object name
+func TestWebHandlerUploadNormalizesKey(t *testing.T) {
+	ExecObjectLayerTest(t, testUploadNormalizesKeyWebHandler)
+}
+
+// testUploadNormalizesKeyWebHandler - Test Upload web handler stores
+// objects under the key normalization form of the bucket.
+func testUploadNormalizesKeyWebHandler(obj ObjectLayer, instanceType string, t TestErrHandler) {
+	// Register the API end points with XL/FS object layer.
+	apiRouter := initTestWebRPCEndPoint(obj)
+	credentials := globalServerConfig.GetCredential()
+
+	authorization, err := getWebRPCToken(apiRouter, credentials.AccessKey, credentials.SecretKey)
+	if err != nil {
+		t.Fatal("Cannot authenticate")
+	}
+
+	const (
+		composed   = "caf\u00e9.txt"
+		decomposed = "cafe\u0301.txt"
+	)
+	bucketName := getRandomBucketName()
+	if err = obj.MakeBucketWithLocation(context.Background(), bucketName, ""); err != nil {
+		t.Fatalf("%s : %s", instanceType, err)
+	}
+
+	globalBucketKeyNormalizationSys = NewBucketKeyNormalizationSys()
+	defer func() { globalBucketKeyNormalizationSys = nil }()
+	globalBucketKeyNormalizationSys.Set(map[string]madmin.KeyNormalization{
+		bucketName: madmin.KeyNormalizationNFC,
+	})
+
+	content := []byte("temporary file's content")
+	rec := httptest.NewRecorder()
+	req, err := http.NewRequest("PUT", "/minio/upload/"+bucketName+SlashSeparator+decomposed, bytes.NewReader(content))
+	if err != nil {
+		t.Fatalf("Cannot create upload request, %v", err)
+	}
+	req.Header.Set("Authorization", "Bearer "+authorization)
+	apiRouter.ServeHTTP(rec, req)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("Expected the response status to be 200, but instead found `%d`", rec.Code)
+	}
+
+	if _, err = obj.GetObjectInfo(context.Background(), bucketName, composed, ObjectOptions{}); err != nil {
+		t.Fatalf("%s: Expected the object to be stored as %q, %v", instanceType, composed, err)
+	}
+	if _, err = obj.GetObjectInfo(context.Background(), bucketName, decomposed, ObjectOptions{}); !isErrObjectNotFound(err) {
+		t.Fatalf("%s: Expected no object stored as %q, got %v", instanceType, decomposed, err)
+	}
+}
+
 // Wrapper for calling Download Handler
 func TestWebHandlerDownload(t *testing.T) {
 	ExecObjectLayerTest(t, testDownloadWebHandler)
@@ -969,6 +1872,43 @@ func testDownloadWebHandler(obj ObjectLayer, instanceType string, t TestErrHandl
 		t.Fatalf("The downloaded file is corrupted")
 	}
 
+	// A single range is served with 206, the object is served whole
+	// if it changed since If-Range.
+	rangeTest := func(rangeHeader, ifRange string) *httptest.ResponseRecorder {
+		rec := httptest.NewRecorder()
+		req, rerr := http.NewRequest("GET", "/minio/download/"+bucketName+SlashSeparator+objectName+"?token="+authorization, nil)
+		if rerr != nil {
+			t.Fatalf("Cannot create download request, %v", rerr)
+		}
+		req.Header.Set("Range", rangeHeader)
+		if ifRange != "" {
+			req.Header.Set("If-Range", ifRange)
+		}
+		apiRouter.ServeHTTP(rec, req)
+		return rec
+	}
+
+	rec := rangeTest("bytes=10-", "\""+metadata["etag"]+"\"")
+	if rec.Code != http.StatusPartialContent || !bytes.Equal(rec.Body.Bytes(), content[10:]) {
+		t.Fatalf("Expected the range to be served, got %d %q", rec.Code, rec.Body.Bytes())
+	}
+	if contentRange := fmt.Sprintf("bytes 10-%d/%d", len(content)-1, len(content)); rec.Header().Get("Content-Range") != contentRange {
+		t.Fatalf("Expected Content-Range %s, got %s", contentRange, rec.Header().Get("Content-Range"))
+	}
+	if rec.Header().Get("Accept-Ranges") != "bytes" {
+		t.Fatal("Expected Accept-Ranges to be set")
+	}
+
+	rec = rangeTest("bytes=10-", "\"changed\"")
+	if rec.Code != http.StatusOK || !bytes.Equal(rec.Body.Bytes(), content) {
+		t.Fatalf("Expected the object to be served whole, got %d %q", rec.Code, rec.Body.Bytes())
+	}
+
+	rec = rangeTest("bytes=100-", "")
+	if rec.Code != http.StatusRequestedRangeNotSatisfiable {
+		t.Fatalf("Expected the response status to be 416, but instead found `%d`", rec.Code)
+	}
+
 	// Temporary token should succeed.
 	tmpToken, err := authenticateURL(credentials.AccessKey, credentials.SecretKey)
 	if err != nil {
@@ -991,41 +1931,193 @@ func testDownloadWebHandler(obj ObjectLayer, instanceType string, t TestErrHandl
 		t.Fatalf("Expected the response status to be 403, but instead found `%d`", code)
 	}
 
-	if !bytes.Equal(bodyContent, bytes.NewBufferString("Authentication failed, check your access credentials").Bytes()) {
-		t.Fatalf("Expected authentication error message, got %v", bodyContent)
+	if !bytes.Equal(bodyContent, bytes.NewBufferString("Authentication failed, check your access credentials").Bytes()) {
+		t.Fatalf("Expected authentication error message, got %v", bodyContent)
+	}
+
+	// Unauthenticated download should fail.
+	code, _ = test("")
+	if code != http.StatusForbidden {
+		t.Fatalf("Expected the response status to be 403, but instead found `%d`", code)
+	}
+
+	bucketPolicy := &policy.Policy{
+		Version: policy.DefaultVersion,
+		Statements: []policy.Statement{policy.NewStatement(
+			policy.Allow,
+			policy.NewPrincipal("*"),
+			policy.NewActionSet(policy.GetObjectAction),
+			policy.NewResourceSet(policy.NewResource(bucketName, "*")),
+			condition.NewFunctions(),
+		)},
+	}
+
+	if err := obj.SetBucketPolicy(context.Background(), bucketName, bucketPolicy); err != nil {
+		t.Fatalf("unexpected error. %v", err)
+	}
+	globalPolicySys.Set(bucketName, *bucketPolicy)
+	defer globalPolicySys.Remove(bucketName)
+
+	// Unauthenticated download with READ policy should succeed.
+	code, bodyContent = test("")
+	if code != http.StatusOK {
+		t.Fatalf("Expected the response status to be 200, but instead found `%d`", code)
+	}
+
+	if !bytes.Equal(bodyContent, content) {
+		t.Fatalf("The downloaded file is corrupted")
+	}
+}
+
+// Test web.Export
+func TestWebHandlerExport(t *testing.T) {
+	ExecObjectLayerTest(t, testExportWebHandler)
+}
+
+func testExportWebHandler(obj ObjectLayer, instanceType string, t TestErrHandler) {
+	apiRouter := initTestWebRPCEndPoint(obj)
+	credentials := globalServerConfig.GetCredential()
+
+	authorization, err := getWebRPCToken(apiRouter, credentials.AccessKey, credentials.SecretKey)
+	if err != nil {
+		t.Fatal("Cannot authenticate")
+	}
+
+	bucketName := getRandomBucketName()
+	if err = obj.MakeBucketWithLocation(context.Background(), bucketName, ""); err != nil {
+		t.Fatalf("%s : %s", instanceType, err)
+	}
+
+	content := []byte("temporary file's content")
+	for _, objectName := range []string{"a/one.txt", "a/two.txt", "b.txt"} {
+		_, err = obj.PutObject(context.Background(), bucketName, objectName, mustGetPutObjReader(t, bytes.NewReader(content), int64(len(content)), "", ""), ObjectOptions{UserDefined: map[string]string{"content-type": "text/plain"}})
+		if err != nil {
+			t.Fatalf("Was not able to upload an object, %v", err)
+		}
+	}
+
+	test := func(token, query string) (int, *httptest.ResponseRecorder) {
+		rec := httptest.NewRecorder()
+		req, err := http.NewRequest("GET", "/minio/export/"+bucketName+"?token="+token+query, nil)
+		if err != nil {
+			t.Fatalf("Cannot create export request, %v", err)
+		}
+		req.Header.Set("User-Agent", "Mozilla")
+		apiRouter.ServeHTTP(rec, req)
+		return rec.Code, rec
+	}
+
+	// CSV listing of a prefix, recursively.
+	code, rec := test(authorization, "&prefix=a/")
+	if code != http.StatusOK {
+		t.Fatalf("Expected the response status to be 200, but instead found `%d`", code)
+	}
+	if contentType := rec.Header().Get("Content-Type"); contentType != "text/csv" {
+		t.Fatalf("Unexpected content type %s", contentType)
+	}
+	records, err := csv.NewReader(rec.Body).ReadAll()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(records) != 3 || records[0][0] != "key" || records[1][0] != "a/one.txt" || records[2][0] != "a/two.txt" {
+		t.Fatalf("Unexpected CSV export %v", records)
+	}
+	if records[1][1] != strconv.Itoa(len(content)) || records[1][4] != "text/plain" {
+		t.Fatalf("Unexpected CSV record %v", records[1])
+	}
+
+	// Newline delimited JSON listing of the bucket.
+	code, rec = test(authorization, "&format=json")
+	if code != http.StatusOK {
+		t.Fatalf("Expected the response status to be 200, but instead found `%d`", code)
+	}
+	var keys []string
+	decoder := json.NewDecoder(rec.Body)
+	for decoder.More() {
+		var record exportRecord
+		if err = decoder.Decode(&record); err != nil {
+			t.Fatal(err)
+		}
+		keys = append(keys, record.Key)
+	}
+	if !reflect.DeepEqual(keys, []string{"a/one.txt", "a/two.txt", "b.txt"}) {
+		t.Fatalf("Unexpected JSON export %v", keys)
+	}
+
+	if code, _ = test(authorization, "&format=xml"); code != http.StatusBadRequest {
+		t.Fatalf("Expected the response status to be 400, but instead found `%d`", code)
+	}
+
+	// Unauthenticated export should fail.
+	if code, _ = test("", ""); code != http.StatusForbidden {
+		t.Fatalf("Expected the response status to be 403, but instead found `%d`", code)
+	}
+}
+
+// Test web.Download with image transformation parameters.
+func TestWebHandlerDownloadImageTransform(t *testing.T) {
+	ExecObjectLayerTest(t, testWebHandlerDownloadImageTransform)
+}
+
+func testWebHandlerDownloadImageTransform(obj ObjectLayer, instanceType string, t TestErrHandler) {
+	apiRouter := initTestWebRPCEndPoint(obj)
+	credentials := globalServerConfig.GetCredential()
+
+	authorization, err := authenticateURL(credentials.AccessKey, credentials.SecretKey)
+	if err != nil {
+		t.Fatal("Cannot authenticate")
+	}
+
+	bucketName := getRandomBucketName()
+	if err = obj.MakeBucketWithLocation(context.Background(), bucketName, ""); err != nil {
+		t.Fatalf("%s : %s", instanceType, err)
+	}
+
+	var buf bytes.Buffer
+	if err = png.Encode(&buf, image.NewRGBA(image.Rect(0, 0, 40, 20))); err != nil {
+		t.Fatal(err)
+	}
+	opts := ObjectOptions{UserDefined: map[string]string{"content-type": "image/png"}}
+	if _, err = obj.PutObject(context.Background(), bucketName, "image.png", mustGetPutObjReader(t, bytes.NewReader(buf.Bytes()), int64(buf.Len()), "", ""), opts); err != nil {
+		t.Fatalf("Was not able to upload an object, %v", err)
+	}
+	content := []byte("not an image")
+	if _, err = obj.PutObject(context.Background(), bucketName, "file.txt", mustGetPutObjReader(t, bytes.NewReader(content), int64(len(content)), "", ""), ObjectOptions{}); err != nil {
+		t.Fatalf("Was not able to upload an object, %v", err)
+	}
+
+	test := func(object, query string) *httptest.ResponseRecorder {
+		rec := httptest.NewRecorder()
+		req, rerr := http.NewRequest("GET", "/minio/download/"+bucketName+SlashSeparator+object+"?token="+authorization+query, nil)
+		if rerr != nil {
+			t.Fatalf("Cannot create download request, %v", rerr)
+		}
+		req.Header.Set("User-Agent", "Mozilla")
+		apiRouter.ServeHTTP(rec, req)
+		return rec
+	}
+
+	rec := test("image.png", "&width=10&rotate=90&format=jpeg")
+	if rec.Code != http.StatusOK {
+		t.Fatalf("Expected the response status to be 200, but instead found `%d`", rec.Code)
 	}
-
-	// Unauthenticated download should fail.
-	code, _ = test("")
-	if code != http.StatusForbidden {
-		t.Fatalf("Expected the response status to be 403, but instead found `%d`", code)
+	if contentType := rec.Header().Get("Content-Type"); contentType != "image/jpeg" {
+		t.Fatalf("Expected content type image/jpeg, got %s", contentType)
 	}
-
-	bucketPolicy := &policy.Policy{
-		Version: policy.DefaultVersion,
-		Statements: []policy.Statement{policy.NewStatement(
-			policy.Allow,
-			policy.NewPrincipal("*"),
-			policy.NewActionSet(policy.GetObjectAction),
-			policy.NewResourceSet(policy.NewResource(bucketName, "*")),
-			condition.NewFunctions(),
-		)},
+	img, _, err := image.Decode(rec.Body)
+	if err != nil {
+		t.Fatal(err)
 	}
-
-	if err := obj.SetBucketPolicy(context.Background(), bucketName, bucketPolicy); err != nil {
-		t.Fatalf("unexpected error. %v", err)
+	if img.Bounds().Dx() != 5 || img.Bounds().Dy() != 10 {
+		t.Fatalf("Expected a 5x10 image, got %dx%d", img.Bounds().Dx(), img.Bounds().Dy())
 	}
-	globalPolicySys.Set(bucketName, *bucketPolicy)
-	defer globalPolicySys.Remove(bucketName)
 
-	// Unauthenticated download with READ policy should succeed.
-	code, bodyContent = test("")
-	if code != http.StatusOK {
-		t.Fatalf("Expected the response status to be 200, but instead found `%d`", code)
+	// Invalid parameters and objects which are not images should fail.
+	if rec = test("image.png", "&rotate=45"); rec.Code != http.StatusBadRequest {
+		t.Fatalf("Expected the response status to be 400, but instead found `%d`", rec.Code)
 	}
-
-	if !bytes.Equal(bodyContent, content) {
-		t.Fatalf("The downloaded file is corrupted")
+	if rec = test("file.txt", "&width=10"); rec.Code != http.StatusBadRequest {
+		t.Fatalf("Expected the response status to be 400, but instead found `%d`", rec.Code)
 	}
 }
 
@@ -1115,6 +2207,218 @@ func testWebHandlerDownloadZip(obj ObjectLayer, instanceType string, t TestErrHa
 	}
 }
 
+// Test web.DownloadZip with objects too large to be prefetched and missing objects.
+func TestWebHandlerDownloadZipPrefetch(t *testing.T) {
+	ExecObjectLayerTest(t, testWebHandlerDownloadZipPrefetch)
+}
+
+func testWebHandlerDownloadZipPrefetch(obj ObjectLayer, instanceType string, t TestErrHandler) {
+	apiRouter := initTestWebRPCEndPoint(obj)
+	credentials := globalServerConfig.GetCredential()
+
+	authorization, err := authenticateURL(credentials.AccessKey, credentials.SecretKey)
+	if err != nil {
+		t.Fatal("Cannot authenticate")
+	}
+
+	bucket := getRandomBucketName()
+	if err = obj.MakeBucketWithLocation(context.Background(), bucket, ""); err != nil {
+		t.Fatalf("%s : %s", instanceType, err)
+	}
+
+	objects := map[string][]byte{
+		"small": []byte("aaaaaaaaaaaaaa"),
+		"large": bytes.Repeat([]byte("b"), downloadZipPrefetchMaxSize+1),
+		"last":  []byte("cccccccccccccc"),
+	}
+	for name, data := range objects {
+		_, err = obj.PutObject(context.Background(), bucket, name, mustGetPutObjReader(t, bytes.NewReader(data), int64(len(data)), "", ""), ObjectOptions{})
+		if err != nil {
+			t.Fatalf("%s : %s", instanceType, err)
+		}
+	}
+
+	test := func(names ...string) (int, []byte) {
+		argsData, err := json.Marshal(DownloadZipArgs{
+			Objects:    names,
+			BucketName: bucket,
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		req, err := http.NewRequest("POST", "/minio/zip?token="+authorization, bytes.NewReader(argsData))
+		if err != nil {
+			t.Fatalf("Cannot create download request, %v", err)
+		}
+		req.Header.Set("User-Agent", "Mozilla")
+		rec := httptest.NewRecorder()
+		apiRouter.ServeHTTP(rec, req)
+		return rec.Code, rec.Body.Bytes()
+	}
+
+	code, data := test("small", "large", "last")
+	if code != http.StatusOK {
+		t.Fatalf("%s: Expected to succeed, got %d", instanceType, code)
+	}
+	reader, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(reader.File) != len(objects) {
+		t.Fatalf("%s: Expected %d files, got %d", instanceType, len(objects), len(reader.File))
+	}
+	for _, file := range reader.File {
+		fileReader, err := file.Open()
+		if err != nil {
+			t.Fatal(err)
+		}
+		content, err := ioutil.ReadAll(fileReader)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !bytes.Equal(content, objects[file.Name]) {
+			t.Fatalf("%s: Incorrect content of %s", instanceType, file.Name)
+		}
+	}
+
+	// A missing object is reported if nothing is written yet.
+	if code, _ = test("missing", "small"); code == http.StatusOK {
+		t.Fatalf("%s: Expected to fail for a missing object", instanceType)
+	}
+}
+
+// Test web.Download and web.DownloadZip with SSE-C encrypted objects.
+func TestWebHandlerDownloadSSEC(t *testing.T) {
+	ExecObjectLayerTest(t, testWebHandlerDownloadSSEC)
+}
+
+func testWebHandlerDownloadSSEC(obj ObjectLayer, instanceType string, t TestErrHandler) {
+	apiRouter := initTestWebRPCEndPoint(obj)
+	credentials := globalServerConfig.GetCredential()
+
+	authorization, err := authenticateURL(credentials.AccessKey, credentials.SecretKey)
+	if err != nil {
+		t.Fatal("Cannot authenticate")
+	}
+
+	bucketName := getRandomBucketName()
+	if err = obj.MakeBucketWithLocation(context.Background(), bucketName, ""); err != nil {
+		t.Fatalf("%s : %s", instanceType, err)
+	}
+
+	sseHeaders := func(key []byte) http.Header {
+		keyMD5 := md5.Sum(key)
+		h := make(http.Header)
+		h.Set(crypto.SSECAlgorithm, crypto.SSEAlgorithmAES256)
+		h.Set(crypto.SSECKey, base64.StdEncoding.EncodeToString(key))
+		h.Set(crypto.SSECKeyMD5, base64.StdEncoding.EncodeToString(keyMD5[:]))
+		return h
+	}
+	key := bytes.Repeat([]byte("k"), 32)
+
+	// Upload an SSE-C encrypted and a plain object.
+	encContent := []byte("encrypted file's content")
+	plainContent := []byte("plain file's content")
+	req := &http.Request{Header: sseHeaders(key)}
+	metadata := make(map[string]string)
+	reader, objectEncryptionKey, err := EncryptRequest(bytes.NewReader(encContent), req, bucketName, "enc", metadata)
+	if err != nil {
+		t.Fatal(err)
+	}
+	info := ObjectInfo{Size: int64(len(encContent))}
+	hashReader, err := hash.NewReader(reader, info.EncryptedSize(), "", "", info.Size, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	rawReader := mustGetPutObjReader(t, bytes.NewReader(encContent), info.Size, "", "").rawReader
+	crypto.RemoveSensitiveEntries(metadata)
+	if _, err = obj.PutObject(context.Background(), bucketName, "enc", NewPutObjReader(rawReader, hashReader, objectEncryptionKey), ObjectOptions{UserDefined: metadata}); err != nil {
+		t.Fatalf("Was not able to upload an object, %v", err)
+	}
+	if _, err = obj.PutObject(context.Background(), bucketName, "plain", mustGetPutObjReader(t, bytes.NewReader(plainContent), int64(len(plainContent)), "", ""), ObjectOptions{}); err != nil {
+		t.Fatalf("Was not able to upload an object, %v", err)
+	}
+
+	download := func(header http.Header) (int, []byte) {
+		rec := httptest.NewRecorder()
+		req, err := http.NewRequest("GET", "/minio/download/"+bucketName+"/enc?token="+authorization, nil)
+		if err != nil {
+			t.Fatalf("Cannot create download request, %v", err)
+		}
+		req.Header.Set("User-Agent", "Mozilla")
+		for k, v := range header {
+			req.Header[k] = v
+		}
+		apiRouter.ServeHTTP(rec, req)
+		return rec.Code, rec.Body.Bytes()
+	}
+
+	testCases := []struct {
+		header       http.Header
+		expectedCode int
+	}{
+		// Test 1: no client key.
+		{nil, http.StatusBadRequest},
+		// Test 2: wrong client key.
+		{sseHeaders(bytes.Repeat([]byte("x"), 32)), http.StatusForbidden},
+		// Test 3: correct client key.
+		{sseHeaders(key), http.StatusOK},
+	}
+	for i, testCase := range testCases {
+		code, data := download(testCase.header)
+		if code != testCase.expectedCode {
+			t.Fatalf("Test %d: expected status %d, got %d: %s", i+1, testCase.expectedCode, code, data)
+		}
+		if code == http.StatusOK && !bytes.Equal(data, encContent) {
+			t.Fatalf("Test %d: the downloaded file is corrupted", i+1)
+		}
+	}
+
+	// Zip an encrypted and a plain object with the client key.
+	argsData, err := json.Marshal(DownloadZipArgs{
+		Objects:    []string{"enc", "plain"},
+		BucketName: bucketName,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	rec := httptest.NewRecorder()
+	req, err = http.NewRequest("POST", "/minio/zip?token="+authorization, bytes.NewReader(argsData))
+	if err != nil {
+		t.Fatalf("Cannot create zip request, %v", err)
+	}
+	req.Header.Set("User-Agent", "Mozilla")
+	for k, v := range sseHeaders(key) {
+		req.Header[k] = v
+	}
+	apiRouter.ServeHTTP(rec, req)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("Expected the response status to be 200, but instead found `%d`", rec.Code)
+	}
+	data := rec.Body.Bytes()
+	zipReader, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := map[string][]byte{"enc": encContent, "plain": plainContent}
+	if len(zipReader.File) != len(expected) {
+		t.Fatalf("Expected %d files in zip, got %d", len(expected), len(zipReader.File))
+	}
+	for _, file := range zipReader.File {
+		fileReader, err := file.Open()
+		if err != nil {
+			t.Fatal(err)
+		}
+		content, err := ioutil.ReadAll(fileReader)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !bytes.Equal(content, expected[file.Name]) {
+			t.Fatalf("Incorrect zip contents for %s", file.Name)
+		}
+	}
+}
+
 // Wrapper for calling PresignedGet handler
 func TestWebHandlerPresignedGetHandler(t *testing.T) {
 	ExecObjectLayerTest(t, testWebPresignedGetHandler)
@@ -1193,6 +2497,49 @@ func testWebPresignedGetHandler(obj ObjectLayer, instanceType string, t TestErrH
 		t.Fatal("Read data is not equal was what was expected")
 	}
 
+	// Presigned URL with response header overrides.
+	apiRouter = initTestWebRPCEndPoint(obj)
+	presignGetReq = PresignedGetArgs{
+		HostName:           "",
+		BucketName:         bucketName,
+		ObjectName:         objectName,
+		Expiry:             1000,
+		ContentDisposition: `attachment; filename="my file.txt"`,
+		ContentType:        "text/plain",
+	}
+	presignGetRep = &PresignedGetRep{}
+	rec = httptest.NewRecorder()
+	req, err = newTestWebRPCRequest("Web.PresignedGet", authorization, presignGetReq)
+	if err != nil {
+		t.Fatalf("Failed to create HTTP request: <ERROR> %v", err)
+	}
+	apiRouter.ServeHTTP(rec, req)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("Expected the response status to be 200, but instead found `%d`", rec.Code)
+	}
+	err = getTestWebRPCResponse(rec, &presignGetRep)
+	if err != nil {
+		t.Fatalf("Failed, %v", err)
+	}
+
+	apiRouter = initTestAPIEndPoints(obj, []string{"GetObject"})
+	arec = httptest.NewRecorder()
+	req, err = newTestRequest("GET", presignGetRep.URL, 0, nil)
+	if err != nil {
+		t.Fatal("Failed to initialized a new request", err)
+	}
+	req.Header.Del("x-amz-content-sha256")
+	apiRouter.ServeHTTP(arec, req)
+	if arec.Code != http.StatusOK {
+		t.Fatalf("Expected the response status to be 200, but instead found `%d`", arec.Code)
+	}
+	if cd := arec.Header().Get("Content-Disposition"); cd != presignGetReq.ContentDisposition {
+		t.Fatalf("Expected Content-Disposition `%s`, found `%s`", presignGetReq.ContentDisposition, cd)
+	}
+	if ct := arec.Header().Get("Content-Type"); ct != presignGetReq.ContentType {
+		t.Fatalf("Expected Content-Type `%s`, found `%s`", presignGetReq.ContentType, ct)
+	}
+
 	// Register the API end points with XL/FS object layer.
 	apiRouter = initTestWebRPCEndPoint(obj)
 
@@ -1217,6 +2564,39 @@ func testWebPresignedGetHandler(obj ObjectLayer, instanceType string, t TestErrH
 	if err.Error() != "Bucket and Object are mandatory arguments." {
 		t.Fatalf("Unexpected, expected `Bucket and Object are mandatory arguments`, got %s", err)
 	}
+
+	// Only the "null" version ID can be presigned.
+	for _, versionID := range []string{"null", "3HL4kqtJvjVBH40Nrjfkd"} {
+		presignGetReq = PresignedGetArgs{
+			BucketName: bucketName,
+			ObjectName: objectName,
+			Expiry:     1000,
+			VersionID:  versionID,
+		}
+		presignGetRep = &PresignedGetRep{}
+		rec = httptest.NewRecorder()
+		req, err = newTestWebRPCRequest("Web.PresignedGet", authorization, presignGetReq)
+		if err != nil {
+			t.Fatalf("Failed to create HTTP request: <ERROR> %v", err)
+		}
+		apiRouter.ServeHTTP(rec, req)
+		if rec.Code != http.StatusOK {
+			t.Fatalf("Expected the response status to be 200, but instead found `%d`", rec.Code)
+		}
+		err = getTestWebRPCResponse(rec, &presignGetRep)
+		if versionID == "null" {
+			if err != nil {
+				t.Fatalf("Failed, %v", err)
+			}
+			if !strings.Contains(presignGetRep.URL, "versionId=null") {
+				t.Fatalf("Expected presigned URL to contain the version ID, found %s", presignGetRep.URL)
+			}
+			continue
+		}
+		if err == nil || err.Error() != "The specified version does not exist." {
+			t.Fatalf("Expected version ID %s to be rejected, got %v", versionID, err)
+		}
+	}
 }
 
 // Wrapper for calling GetBucketPolicy Handler
@@ -1464,6 +2844,104 @@ func testWebSetBucketPolicyHandler(obj ObjectLayer, instanceType string, t TestE
 	}
 }
 
+// Wrapper for calling SetBucketPolicyWithConditions Handler
+func TestWebHandlerSetBucketPolicyWithConditionsHandler(t *testing.T) {
+	ExecObjectLayerTest(t, testWebSetBucketPolicyWithConditionsHandler)
+}
+
+// testWebSetBucketPolicyWithConditionsHandler - Test SetBucketPolicyWithConditions web handler
+func testWebSetBucketPolicyWithConditionsHandler(obj ObjectLayer, instanceType string, t TestErrHandler) {
+	// Register the API end points with XL/FS object layer.
+	apiRouter := initTestWebRPCEndPoint(obj)
+	credentials := globalServerConfig.GetCredential()
+
+	authorization, err := getWebRPCToken(apiRouter, credentials.AccessKey, credentials.SecretKey)
+	if err != nil {
+		t.Fatal("Cannot authenticate")
+	}
+
+	call := func(method string, args interface{}, reply interface{}) error {
+		rec := httptest.NewRecorder()
+		req, err := newTestWebRPCRequest(method, authorization, args)
+		if err != nil {
+			t.Fatalf("Failed to create HTTP request: <ERROR> %v", err)
+		}
+		apiRouter.ServeHTTP(rec, req)
+		if rec.Code != http.StatusOK {
+			t.Fatalf("Expected the response status to be 200, but instead found `%d`", rec.Code)
+		}
+		return getTestWebRPCResponse(rec, reply)
+	}
+
+	bucketName := getRandomBucketName()
+	if err = obj.MakeBucketWithLocation(context.Background(), bucketName, ""); err != nil {
+		t.Fatal("Unexpected error: ", err)
+	}
+
+	// Invalid IP addresses are rejected.
+	args := &SetBucketPolicyWithConditionsArgs{
+		SetBucketPolicyWebArgs: SetBucketPolicyWebArgs{BucketName: bucketName, Policy: "readonly"},
+		AllowedIPs:             []string{"10.0.0.300"},
+	}
+	if err = call("Web.SetBucketPolicyWithConditions", args, &WebGenericRep{}); err == nil {
+		t.Fatal("Expected invalid IP addresses to be rejected")
+	}
+
+	args.AllowedIPs = []string{"10.0.0.0/8"}
+	args.DeniedIPs = []string{"10.1.2.3"}
+	if err = call("Web.SetBucketPolicyWithConditions", args, &WebGenericRep{}); err != nil {
+		t.Fatal(err)
+	}
+
+	isAllowed := func(sourceIP string) bool {
+		return globalPolicySys.IsAllowed(policy.Args{
+			Action:          policy.GetObjectAction,
+			BucketName:      bucketName,
+			ObjectName:      "object",
+			ConditionValues: map[string][]string{"SourceIp": {sourceIP}},
+		})
+	}
+	if !isAllowed("10.0.0.1") {
+		t.Fatal("Expected anonymous access from allowed IP addresses to be allowed")
+	}
+	if isAllowed("192.168.0.1") || isAllowed("10.1.2.3") {
+		t.Fatal("Expected anonymous access from other and denied IP addresses to be denied")
+	}
+
+	listPolicies := func() *ListAllBucketPoliciesRep {
+		reply := &ListAllBucketPoliciesRep{}
+		if err := call("Web.ListAllBucketPolicies", &ListAllBucketPoliciesArgs{BucketName: bucketName}, reply); err != nil {
+			t.Fatal(err)
+		}
+		return reply
+	}
+	reply := listPolicies()
+	if len(reply.Policies) != 1 || reply.Policies[0].Policy != miniogopolicy.BucketPolicyReadOnly {
+		t.Fatalf("Expected a readonly policy, got %v", reply.Policies)
+	}
+	if !reflect.DeepEqual(reply.AllowedIPs, []string{"10.0.0.0/8"}) || !reflect.DeepEqual(reply.DeniedIPs, []string{"10.1.2.3/32"}) {
+		t.Fatalf("Unexpected IP restrictions %v, %v", reply.AllowedIPs, reply.DeniedIPs)
+	}
+
+	// Canned policies keep the IP restrictions.
+	if err = call("Web.SetBucketPolicy", &SetBucketPolicyWebArgs{BucketName: bucketName, Prefix: "public", Policy: "readwrite"}, &WebGenericRep{}); err != nil {
+		t.Fatal(err)
+	}
+	if reply = listPolicies(); len(reply.Policies) != 2 || len(reply.AllowedIPs) != 1 || len(reply.DeniedIPs) != 1 {
+		t.Fatalf("Expected two policies with IP restrictions, got %v, %v, %v", reply.Policies, reply.AllowedIPs, reply.DeniedIPs)
+	}
+
+	// IP restrictions are removed along with the last canned policy.
+	for _, prefix := range []string{"", "public"} {
+		if err = call("Web.SetBucketPolicy", &SetBucketPolicyWebArgs{BucketName: bucketName, Prefix: prefix, Policy: "none"}, &WebGenericRep{}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if _, err = obj.GetBucketPolicy(context.Background(), bucketName); err == nil {
+		t.Fatal("Expected the bucket policy to be removed")
+	}
+}
+
 // TestWebCheckAuthorization - Test Authorization for all web handlers
 func TestWebCheckAuthorization(t *testing.T) {
 	// Prepare XL backend
@@ -1491,8 +2969,9 @@ func TestWebCheckAuthorization(t *testing.T) {
 		"ServerInfo", "StorageInfo", "MakeBucket",
 		"ListBuckets", "ListObjects", "RemoveObject",
 		"GenerateAuth", "SetAuth",
-		"GetBucketPolicy", "SetBucketPolicy", "ListAllBucketPolicies",
-		"PresignedGet",
+		"GetBucketPolicy", "SetBucketPolicy", "SetBucketPolicyWithConditions", "ListAllBucketPolicies",
+		"PresignedGet", "ListIncompleteUploads", "AbortIncompleteUpload",
+		"CopyPrefix", "CopyPrefixStatus", "SimulatePolicy", "CreateInvite",
 	}
 	for _, rpcCall := range webRPCs {
 		reply := &WebGenericRep{}
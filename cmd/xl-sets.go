@@ -305,14 +305,27 @@ func newXLSets(endpoints EndpointList, format *formatXLV3, setCount int, drivesP
 func (s *xlSets) StorageInfo(ctx context.Context) StorageInfo {
 	var storageInfo StorageInfo
 	storageInfo.Backend.Type = BackendErasure
+	storageInfo.Buckets = make(map[string]BucketUsageInfo)
 	for _, set := range s.sets {
 		lstorageInfo := set.StorageInfo(ctx)
 		storageInfo.Used = storageInfo.Used + lstorageInfo.Used
 		storageInfo.Total = storageInfo.Total + lstorageInfo.Total
 		storageInfo.Available = storageInfo.Available + lstorageInfo.Available
+		storageInfo.Objects = storageInfo.Objects + lstorageInfo.Objects
+		storageInfo.ObjectsSize = storageInfo.ObjectsSize + lstorageInfo.ObjectsSize
+		for bucket, usage := range lstorageInfo.Buckets {
+			busage := storageInfo.Buckets[bucket]
+			busage.Objects = busage.Objects + usage.Objects
+			busage.Size = busage.Size + usage.Size
+			storageInfo.Buckets[bucket] = busage
+		}
+		storageInfo.Backend.HealBacklog = append(storageInfo.Backend.HealBacklog, lstorageInfo.Backend.HealBacklog...)
 		storageInfo.Backend.OnlineDisks = storageInfo.Backend.OnlineDisks + lstorageInfo.Backend.OnlineDisks
 		storageInfo.Backend.OfflineDisks = storageInfo.Backend.OfflineDisks + lstorageInfo.Backend.OfflineDisks
 	}
+	if storageInfo.Objects > 0 {
+		storageInfo.AvgObjectSize = storageInfo.ObjectsSize / storageInfo.Objects
+	}
 
 	scData, scParity := getRedundancyCount(standardStorageClass, s.drivesPerSet)
 	storageInfo.Backend.StandardSCData = scData
@@ -635,6 +648,11 @@ func (s *xlSets) PutObject(ctx context.Context, bucket string, object string, da
 	return s.getHashedSet(object).PutObject(ctx, bucket, object, data, opts)
 }
 
+// AppendObject - appends data to an object on hashedSet based on the object name.
+func (s *xlSets) AppendObject(ctx context.Context, bucket string, object string, data *PutObjReader, opts ObjectOptions) (objInfo ObjectInfo, err error) {
+	return s.getHashedSet(object).AppendObject(ctx, bucket, object, data, opts)
+}
+
 // GetObjectInfo - reads object metadata from the hashedSet based on the object name.
 func (s *xlSets) GetObjectInfo(ctx context.Context, bucket, object string, opts ObjectOptions) (objInfo ObjectInfo, err error) {
 	return s.getHashedSet(object).GetObjectInfo(ctx, bucket, object, opts)
@@ -1152,6 +1170,20 @@ func (s *xlSets) ListMultipartUploads(ctx context.Context, bucket, prefix, keyMa
 	return s.getHashedSet(prefix).ListMultipartUploads(ctx, bucket, prefix, keyMarker, uploadIDMarker, delimiter, maxUploads)
 }
 
+// listBucketMultipartUploads - lists the pending multipart uploads of
+// all objects in a bucket across all the sets.
+func (s *xlSets) listBucketMultipartUploads(ctx context.Context, bucket string) ([]MultipartInfo, error) {
+	var uploads []MultipartInfo
+	for _, set := range s.sets {
+		setUploads, err := set.listBucketMultipartUploads(ctx, bucket)
+		if err != nil {
+			return nil, err
+		}
+		uploads = append(uploads, setUploads...)
+	}
+	return uploads, nil
+}
+
 // Initiate a new multipart upload on a hashedSet based on object name.
 func (s *xlSets) NewMultipartUpload(ctx context.Context, bucket, object string, opts ObjectOptions) (uploadID string, err error) {
 	return s.getHashedSet(object).NewMultipartUpload(ctx, bucket, object, opts)
@@ -176,7 +176,12 @@ func (xl xlObjects) ListMultipartUploads(ctx context.Context, bucket, object, ke
 			if len(result.Uploads) == maxUploads {
 				break
 			}
-			result.Uploads = append(result.Uploads, MultipartInfo{Object: object, UploadID: uploadID})
+			var initiated time.Time
+			si, meta, err := readXLMetaStat(ctx, disk, minioMetaMultipartBucket, xl.getUploadIDDir(bucket, object, uploadID))
+			if err == nil {
+				initiated = getMultipartInitiated(meta, si.ModTime)
+			}
+			result.Uploads = append(result.Uploads, MultipartInfo{Object: object, UploadID: uploadID, Initiated: initiated})
 		}
 		break
 	}
@@ -184,6 +189,50 @@ func (xl xlObjects) ListMultipartUploads(ctx context.Context, bucket, object, ke
 	return result, nil
 }
 
+// listBucketMultipartUploads - lists the pending multipart uploads of
+// all objects in a bucket by walking the multipart namespace, uploads
+// which did not record their object name are not listed.
+func (xl xlObjects) listBucketMultipartUploads(ctx context.Context, bucket string) (uploads []MultipartInfo, err error) {
+	for _, disk := range xl.getLoadBalancedDisks() {
+		if disk == nil {
+			continue
+		}
+		shaDirs, err := disk.ListDir(minioMetaMultipartBucket, "", -1, "")
+		if err != nil {
+			if err == errFileNotFound || err == errVolumeNotFound {
+				return nil, nil
+			}
+			logger.LogIf(ctx, err)
+			return nil, err
+		}
+		for _, shaDir := range shaDirs {
+			shaDir = strings.TrimSuffix(shaDir, SlashSeparator)
+			uploadIDs, err := disk.ListDir(minioMetaMultipartBucket, shaDir, -1, "")
+			if err != nil {
+				continue
+			}
+			for _, uploadID := range uploadIDs {
+				uploadID = strings.TrimSuffix(uploadID, SlashSeparator)
+				si, meta, err := readXLMetaStat(ctx, disk, minioMetaMultipartBucket, pathJoin(shaDir, uploadID))
+				if err != nil {
+					continue
+				}
+				object, ok := getMultipartObject(bucket, shaDir, meta)
+				if !ok {
+					continue
+				}
+				uploads = append(uploads, MultipartInfo{
+					Object:    object,
+					UploadID:  uploadID,
+					Initiated: getMultipartInitiated(meta, si.ModTime),
+				})
+			}
+		}
+		break
+	}
+	return uploads, nil
+}
+
 // newMultipartUpload - wrapper for initializing a new multipart
 // request; returns a unique upload id.
 //
@@ -207,6 +256,7 @@ func (xl xlObjects) newMultipartUpload(ctx context.Context, bucket string, objec
 		meta["content-type"] = contentType
 	}
 	xlMeta.Stat.ModTime = UTCNow()
+	setMultipartUploadMeta(meta, object, xlMeta.Stat.ModTime)
 	xlMeta.Meta = meta
 
 	uploadID := mustGetUUID()
@@ -727,6 +777,7 @@ func (xl xlObjects) CompleteMultipartUpload(ctx context.Context, bucket string,
 
 	// Save the consolidated actual size.
 	xlMeta.Meta[ReservedMetadataPrefix+"actual-size"] = strconv.FormatInt(objectActualSize, 10)
+	clearMultipartUploadMeta(xlMeta.Meta)
 
 	// Update all xl metadata, make sure to not modify fields like
 	// checksum which are different on each disks.
@@ -19,11 +19,14 @@ package cmd
 import (
 	"bytes"
 	"context"
+	"fmt"
 	"io"
 	"net/http"
 	"path"
+	"strconv"
 	"sync"
 
+	"github.com/minio/minio/cmd/crypto"
 	"github.com/minio/minio/cmd/logger"
 	"github.com/minio/minio/pkg/mimedb"
 )
@@ -524,8 +527,8 @@ func (xl xlObjects) putObject(ctx context.Context, bucket string, object string,
 		opts.UserDefined = make(map[string]string)
 	}
 
-	// Get parity and data drive count based on storage class metadata
-	dataDrives, parityDrives := getRedundancyCount(opts.UserDefined[amzStorageClass], len(xl.getDisks()))
+	// Get parity and data drive count based on storage class metadata and object size
+	dataDrives, parityDrives := getRedundancyCountForSize(opts.UserDefined[amzStorageClass], data.Size(), len(xl.getDisks()))
 
 	// we now know the number of blocks this object needs for data and parity.
 	// writeQuorum is dataBlocks + 1
@@ -709,6 +712,193 @@ func (xl xlObjects) putObject(ctx context.Context, bucket string, object string,
 	return objInfo, nil
 }
 
+// AppendObject - appends data to an existing object, the incoming
+// data is erasure coded as a new part of the object which is then
+// stitched to the existing parts by updating `xl.json`, existing
+// data is never read nor rewritten. Creates the object if it does
+// not exist yet.
+func (xl xlObjects) AppendObject(ctx context.Context, bucket string, object string, r *PutObjReader, opts ObjectOptions) (objInfo ObjectInfo, err error) {
+	// Validate put object input args.
+	if err = checkPutObjectArgs(ctx, bucket, object, xl, r.Size()); err != nil {
+		return ObjectInfo{}, err
+	}
+
+	// Lock the object.
+	objectLock := xl.nsMutex.NewNSLock(ctx, bucket, object)
+	if err = objectLock.GetLock(globalObjectTimeout); err != nil {
+		return objInfo, err
+	}
+	defer objectLock.Unlock()
+
+	// Append to a directory object is not allowed.
+	if hasSuffix(object, SlashSeparator) {
+		return ObjectInfo{}, toObjectErr(errIsNotRegular, bucket, object)
+	}
+
+	// Read metadata associated with the object from all disks.
+	partsMetadata, errs := readAllXLMetadata(ctx, xl.getDisks(), bucket, object)
+
+	// get Quorum for this object
+	readQuorum, writeQuorum, err := objectQuorumFromMeta(ctx, xl, partsMetadata, errs)
+	if err == errFileNotFound {
+		// Nothing to append to, create the object.
+		return xl.putObject(ctx, bucket, object, r, opts)
+	}
+	if err != nil {
+		return ObjectInfo{}, toObjectErr(err, bucket, object)
+	}
+
+	reducedErr := reduceWriteQuorumErrs(ctx, errs, objectOpIgnoredErrs, writeQuorum)
+	if reducedErr == errXLWriteQuorum {
+		return ObjectInfo{}, toObjectErr(reducedErr, bucket, object)
+	}
+
+	// Deny if WORM is enabled
+	if globalWORMEnabled {
+		return ObjectInfo{}, ObjectAlreadyExists{Bucket: bucket, Object: object}
+	}
+
+	// List all online disks.
+	onlineDisks, modTime := listOnlineDisks(xl.getDisks(), partsMetadata, errs)
+
+	// Pick latest valid metadata.
+	xlMeta, err := pickValidXLMeta(ctx, partsMetadata, modTime, readQuorum)
+	if err != nil {
+		return ObjectInfo{}, toObjectErr(err, bucket, object)
+	}
+
+	// Encrypted and compressed objects can not be extended
+	// without rewriting them.
+	if crypto.IsEncrypted(xlMeta.Meta) || xlMeta.Meta[ReservedMetadataPrefix+"compression"] != "" {
+		return ObjectInfo{}, NotImplemented{}
+	}
+
+	// Nothing to append, the object is left untouched.
+	if r.Size() == 0 {
+		return xlMeta.ToObjectInfo(bucket, object), nil
+	}
+
+	if isMaxObjectSize(xlMeta.Stat.Size + r.Size()) {
+		return ObjectInfo{}, ObjectTooLarge{Bucket: bucket, Object: object}
+	}
+	// Every append adds a part, objects are limited to the
+	// same number of parts as multipart uploads.
+	partID := 1
+	if len(xlMeta.Parts) > 0 {
+		partID = xlMeta.Parts[len(xlMeta.Parts)-1].Number + 1
+	}
+	if isMaxPartID(partID) {
+		return ObjectInfo{}, ObjectTooManyAppends{Bucket: bucket, Object: object}
+	}
+
+	// Order online disks and parts metadata in accordance with distribution order.
+	onlineDisks = shuffleDisks(onlineDisks, xlMeta.Erasure.Distribution)
+	partsMetadata = shufflePartsMetadata(partsMetadata, xlMeta.Erasure.Distribution)
+
+	data := r.Reader
+	partName := fmt.Sprintf("part.%d", partID)
+	tmpPart := mustGetUUID()
+	tmpPartPath := pathJoin(tmpPart, partName)
+
+	// Delete the temporary object part. If AppendObject succeeds there would be nothing to delete.
+	defer xl.deleteObject(ctx, minioMetaTmpBucket, tmpPart, writeQuorum, false)
+
+	erasure, err := NewErasure(ctx, xlMeta.Erasure.DataBlocks, xlMeta.Erasure.ParityBlocks, xlMeta.Erasure.BlockSize)
+	if err != nil {
+		return ObjectInfo{}, toObjectErr(err, bucket, object)
+	}
+
+	// Fetch buffer for I/O, returns from the pool if not allocates a new one and returns.
+	var buffer []byte
+	switch size := data.Size(); {
+	case size == 0:
+		buffer = make([]byte, 1) // Allocate atleast a byte to reach EOF
+	case size == -1 || size >= blockSizeV1:
+		buffer = xl.bp.Get()
+		defer xl.bp.Put(buffer)
+	case size < blockSizeV1:
+		// No need to allocate fully blockSizeV1 buffer if the incoming data is smaller.
+		buffer = make([]byte, size, 2*size)
+	}
+
+	if len(buffer) > int(xlMeta.Erasure.BlockSize) {
+		buffer = buffer[:xlMeta.Erasure.BlockSize]
+	}
+
+	writers := make([]io.Writer, len(onlineDisks))
+	for i, disk := range onlineDisks {
+		if disk == nil {
+			continue
+		}
+		writers[i] = newBitrotWriter(disk, minioMetaTmpBucket, tmpPartPath, erasure.ShardFileSize(data.Size()), DefaultBitrotAlgorithm, erasure.ShardSize())
+	}
+
+	n, err := erasure.Encode(ctx, data, writers, buffer, erasure.dataBlocks+1)
+	closeBitrotWriters(writers)
+	if err != nil {
+		return ObjectInfo{}, toObjectErr(err, bucket, object)
+	}
+
+	// Should return IncompleteBody{} error when reader has fewer bytes
+	// than specified in request header.
+	if n < data.Size() {
+		return ObjectInfo{}, IncompleteBody{}
+	}
+
+	for i := range writers {
+		if writers[i] == nil {
+			onlineDisks[i] = nil
+		}
+	}
+
+	// Move the new part next to the existing parts of the object,
+	// it is not visible until `xl.json` is updated.
+	onlineDisks, err = rename(ctx, onlineDisks, minioMetaTmpBucket, tmpPartPath, bucket, pathJoin(object, partName), false, writeQuorum, nil)
+	if err != nil {
+		return ObjectInfo{}, toObjectErr(err, bucket, object)
+	}
+
+	md5hex := r.MD5CurrentHexString()
+
+	// Stitch the new part to the object.
+	xlMeta.AddObjectPart(partID, partName, md5hex, n, data.ActualSize())
+	xlMeta.Stat.Size += n
+	xlMeta.Stat.ModTime = UTCNow()
+	xlMeta.Meta["etag"] = getAppendObjectETag(xlMeta.Meta["etag"], md5hex)
+	if actualSize, ok := xlMeta.Meta[ReservedMetadataPrefix+"actual-size"]; ok {
+		size, _ := strconv.ParseInt(actualSize, 10, 64)
+		xlMeta.Meta[ReservedMetadataPrefix+"actual-size"] = strconv.FormatInt(size+data.ActualSize(), 10)
+	}
+
+	// Update all xl metadata, make sure to not modify fields like
+	// checksum which are different on each disks.
+	for i, disk := range onlineDisks {
+		if disk == nil {
+			continue
+		}
+		partsMetadata[i].Stat = xlMeta.Stat
+		partsMetadata[i].Meta = xlMeta.Meta
+		partsMetadata[i].Parts = xlMeta.Parts
+		partsMetadata[i].Erasure.AddChecksumInfo(ChecksumInfo{partName, DefaultBitrotAlgorithm, bitrotWriterSum(writers[i])})
+	}
+
+	tempXLMetaPath := mustGetUUID()
+
+	// Cleanup in case of xl.json writing failure
+	defer xl.deleteObject(ctx, minioMetaTmpBucket, tempXLMetaPath, writeQuorum, false)
+
+	// Write unique `xl.json` for each disk.
+	if onlineDisks, err = writeUniqueXLMetadata(ctx, onlineDisks, minioMetaTmpBucket, tempXLMetaPath, partsMetadata, writeQuorum); err != nil {
+		return ObjectInfo{}, toObjectErr(err, minioMetaTmpBucket, tempXLMetaPath)
+	}
+
+	if _, err = commitXLMetadata(ctx, onlineDisks, minioMetaTmpBucket, tempXLMetaPath, bucket, object, writeQuorum); err != nil {
+		return ObjectInfo{}, toObjectErr(err, bucket, object)
+	}
+
+	return xlMeta.ToObjectInfo(bucket, object), nil
+}
+
 // deleteObject - wrapper for delete object, deletes an object from
 // all the disks in parallel, including `xl.json` associated with the
 // object.
@@ -19,6 +19,7 @@ package cmd
 import (
 	"bytes"
 	"context"
+	"fmt"
 	"io/ioutil"
 	"math/rand"
 	"os"
@@ -238,6 +239,50 @@ func TestXLDeleteObjectDiskNotFound(t *testing.T) {
 	removeRoots(fsDirs)
 }
 
+// Tests that appending to an object with the maximum number of parts fails.
+func TestXLAppendObjectTooManyParts(t *testing.T) {
+	obj, fsDirs, err := prepareXL16()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer removeRoots(fsDirs)
+	xl := obj.(*xlObjects)
+
+	bucket := "bucket"
+	object := "object"
+	if err = obj.MakeBucketWithLocation(context.Background(), bucket, ""); err != nil {
+		t.Fatal(err)
+	}
+	if _, err = obj.PutObject(context.Background(), bucket, object, mustGetPutObjReader(t, bytes.NewReader([]byte("abcd")), int64(len("abcd")), "", ""), ObjectOptions{}); err != nil {
+		t.Fatal(err)
+	}
+
+	// Pretend the object has been appended to globalMaxPartID-1 times.
+	for _, disk := range xl.getDisks() {
+		xlMeta, err := readXLMeta(context.Background(), disk, bucket, object)
+		if err != nil {
+			t.Fatal(err)
+		}
+		part := xlMeta.Parts[0]
+		for i := 2; i <= globalMaxPartID; i++ {
+			part.Number = i
+			part.Name = fmt.Sprintf("part.%d", i)
+			xlMeta.Parts = append(xlMeta.Parts, part)
+		}
+		if err = disk.DeleteFile(bucket, path.Join(object, xlMetaJSONFile)); err != nil {
+			t.Fatal(err)
+		}
+		if err = writeXLMetadata(context.Background(), disk, bucket, object, xlMeta); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	_, err = obj.AppendObject(context.Background(), bucket, object, mustGetPutObjReader(t, bytes.NewReader([]byte("efgh")), int64(len("efgh")), "", ""), ObjectOptions{})
+	if _, ok := err.(ObjectTooManyAppends); !ok {
+		t.Fatalf("Expected ObjectTooManyAppends, got %v", err)
+	}
+}
+
 func TestGetObjectNoQuorum(t *testing.T) {
 	// Create an instance of xl backend.
 	obj, fsDirs, err := prepareXL16()
@@ -109,6 +109,68 @@ func sortValidDisksInfo(disksInfo []DiskInfo) []DiskInfo {
 	return validDisksInfo
 }
 
+// getObjectsInfo - estimates the object count, total object size and
+// heal backlog of a set from the usage crawled on each of its disks.
+// Every object has an `xl.json` on each disk of the set, the disk
+// with the most objects is therefore the closest to the actual count
+// and every disk behind it, including offline ones, has objects
+// pending heal. Each disk holds one of dataBlocks data shards of an
+// object, the logical size is only an estimate from the shard sizes,
+// it assumes every object uses the data blocks of the standard storage
+// class.
+func getObjectsInfo(disksInfo []DiskInfo, dataBlocks int) (objects, objectsSize, healBacklog uint64) {
+	for _, di := range disksInfo {
+		if di.Objects > objects {
+			objects = di.Objects
+			objectsSize = getShardsSize(di, dataBlocks) * uint64(dataBlocks)
+		}
+	}
+	for _, di := range disksInfo {
+		healBacklog = healBacklog + (objects - di.Objects)
+	}
+	return objects, objectsSize, healBacklog
+}
+
+// getBucketsUsageInfo - estimates the usage of each bucket of a set
+// from the usage crawled on each of its disks, in the same way as
+// getObjectsInfo estimates the usage of the whole set.
+func getBucketsUsageInfo(disksInfo []DiskInfo, dataBlocks int) map[string]BucketUsageInfo {
+	buckets := make(map[string]BucketUsageInfo)
+	for _, di := range disksInfo {
+		for bucket, usage := range di.Buckets {
+			if usage.Objects <= buckets[bucket].Objects {
+				continue
+			}
+			shardsSize := getShardsSize(DiskInfo{
+				ObjectParts: usage.ObjectParts,
+				ObjectsSize: usage.ObjectsSize,
+			}, dataBlocks)
+			buckets[bucket] = BucketUsageInfo{
+				Objects: usage.Objects,
+				Size:    shardsSize * uint64(dataBlocks),
+			}
+		}
+	}
+	return buckets
+}
+
+// getShardsSize - estimates the size of the erasure coded shards in
+// the parts crawled on a disk without their bitrot checksums. Every
+// part holds one checksum per shard of up to the shard size of the
+// default block size, the last shard of a part is usually partial.
+func getShardsSize(di DiskInfo, dataBlocks int) uint64 {
+	if dataBlocks <= 0 {
+		return di.ObjectsSize
+	}
+	hashSize := uint64(DefaultBitrotAlgorithm.New().Size())
+	shardSize := uint64(ceilFrac(blockSizeV1, int64(dataBlocks)))
+	checksumsSize := (di.ObjectsSize/(shardSize+hashSize) + di.ObjectParts) * hashSize
+	if checksumsSize >= di.ObjectsSize {
+		return 0
+	}
+	return di.ObjectsSize - checksumsSize
+}
+
 // Get an aggregated storage info across all disks.
 func getStorageInfo(disks []StorageAPI) StorageInfo {
 	disksInfo, onlineDisks, offlineDisks := getDisksInfo(disks)
@@ -131,10 +193,18 @@ func getStorageInfo(disks []StorageAPI) StorageInfo {
 	_, sscParity := getRedundancyCount(standardStorageClass, len(disks))
 	_, rrscparity := getRedundancyCount(reducedRedundancyStorageClass, len(disks))
 
+	objects, objectsSize, healBacklog := getObjectsInfo(disksInfo, len(disks)-sscParity)
+
 	storageInfo := StorageInfo{
-		Used:      used,
-		Total:     total,
-		Available: available,
+		Used:        used,
+		Total:       total,
+		Available:   available,
+		Objects:     objects,
+		ObjectsSize: objectsSize,
+		Buckets:     getBucketsUsageInfo(disksInfo, len(disks)-sscParity),
+	}
+	if objects > 0 {
+		storageInfo.AvgObjectSize = objectsSize / objects
 	}
 
 	storageInfo.Backend.Type = BackendErasure
@@ -143,6 +213,7 @@ func getStorageInfo(disks []StorageAPI) StorageInfo {
 
 	storageInfo.Backend.StandardSCParity = sscParity
 	storageInfo.Backend.RRSCParity = rrscparity
+	storageInfo.Backend.HealBacklog = []uint64{healBacklog}
 
 	return storageInfo
 }
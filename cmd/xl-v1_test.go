@@ -19,6 +19,8 @@ package cmd
 import (
 	"reflect"
 	"testing"
+
+	humanize "github.com/dustin/go-humanize"
 )
 
 // Sort valid disks info.
@@ -65,3 +67,88 @@ func TestSortingValidDisks(t *testing.T) {
 		}
 	}
 }
+
+// Tests object count and heal backlog estimation from crawled disk usage.
+func TestGetObjectsInfo(t *testing.T) {
+	testCases := []struct {
+		disksInfo   []DiskInfo
+		dataBlocks  int
+		objects     uint64
+		objectsSize uint64
+		healBacklog uint64
+	}{
+		// No objects crawled yet.
+		{
+			disksInfo: []DiskInfo{{}, {}, {}, {}},
+		},
+		// All disks are in sync.
+		{
+			disksInfo: []DiskInfo{
+				{Objects: 10, ObjectParts: 10, ObjectsSize: 420},
+				{Objects: 10, ObjectParts: 10, ObjectsSize: 420},
+				{Objects: 10, ObjectParts: 10, ObjectsSize: 420},
+				{Objects: 10, ObjectParts: 10, ObjectsSize: 420},
+			},
+			dataBlocks:  2,
+			objects:     10,
+			objectsSize: 200,
+		},
+		// One disk is behind and one is offline.
+		{
+			disksInfo: []DiskInfo{
+				{Objects: 10, ObjectParts: 10, ObjectsSize: 420},
+				{},
+				{Objects: 7, ObjectParts: 7, ObjectsSize: 294},
+				{Objects: 10, ObjectParts: 10, ObjectsSize: 420},
+			},
+			dataBlocks:  2,
+			objects:     10,
+			objectsSize: 200,
+			healBacklog: 13,
+		},
+		// Bitrot checksums of parts spanning several shards are excluded.
+		{
+			disksInfo: []DiskInfo{
+				{Objects: 1, ObjectParts: 1, ObjectsSize: 6*humanize.MiByte + 64},
+				{Objects: 1, ObjectParts: 1, ObjectsSize: 6*humanize.MiByte + 64},
+			},
+			dataBlocks:  2,
+			objects:     1,
+			objectsSize: 12 * humanize.MiByte,
+		},
+	}
+
+	for i, testCase := range testCases {
+		objects, objectsSize, healBacklog := getObjectsInfo(testCase.disksInfo, testCase.dataBlocks)
+		if objects != testCase.objects {
+			t.Errorf("Test %d: Expected objects %d, Got %d", i+1, testCase.objects, objects)
+		}
+		if objectsSize != testCase.objectsSize {
+			t.Errorf("Test %d: Expected objects size %d, Got %d", i+1, testCase.objectsSize, objectsSize)
+		}
+		if healBacklog != testCase.healBacklog {
+			t.Errorf("Test %d: Expected heal backlog %d, Got %d", i+1, testCase.healBacklog, healBacklog)
+		}
+	}
+}
+
+func TestGetBucketsUsageInfo(t *testing.T) {
+	disksInfo := []DiskInfo{
+		{Buckets: map[string]DiskBucketUsage{
+			"a": {Objects: 10, ObjectParts: 10, ObjectsSize: 420},
+			"b": {Objects: 1, ObjectParts: 1, ObjectsSize: 42},
+		}},
+		{},
+		{Buckets: map[string]DiskBucketUsage{
+			"a": {Objects: 7, ObjectParts: 7, ObjectsSize: 294},
+			"b": {Objects: 2, ObjectParts: 2, ObjectsSize: 84},
+		}},
+	}
+	expected := map[string]BucketUsageInfo{
+		"a": {Objects: 10, Size: 200},
+		"b": {Objects: 2, Size: 40},
+	}
+	if buckets := getBucketsUsageInfo(disksInfo, 2); !reflect.DeepEqual(buckets, expected) {
+		t.Fatalf("Expected %v, got %v", expected, buckets)
+	}
+}
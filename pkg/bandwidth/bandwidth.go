@@ -0,0 +1,134 @@
+/*
+ * MinIO Cloud Storage, (C) 2019 MinIO, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+// Package bandwidth implements a token bucket limiter to throttle the
+// bandwidth used by background data transfers.
+package bandwidth
+
+import (
+	"context"
+	"io"
+	"sync"
+	"time"
+)
+
+// maxChunkSize - maximum number of bytes read at once by a Reader, this
+// keeps the throttled transfer smooth instead of bursty.
+const maxChunkSize = 32 * 1024
+
+// Limiter limits the number of bytes transferred per second, a limit of
+// zero means unlimited. Up to one second worth of unused bandwidth can
+// be accumulated for bursts.
+type Limiter struct {
+	mu     sync.Mutex
+	limit  int64
+	tokens float64
+	last   time.Time
+}
+
+// NewLimiter returns a new limiter allowing limit bytes per second.
+func NewLimiter(limit int64) *Limiter {
+	return &Limiter{
+		limit: limit,
+		last:  time.Now(),
+	}
+}
+
+// Limit returns the current limit in bytes per second.
+func (l *Limiter) Limit() int64 {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.limit
+}
+
+// SetLimit changes the limit to limit bytes per second.
+func (l *Limiter) SetLimit(limit int64) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.advance(time.Now())
+	l.limit = limit
+	if l.tokens > float64(limit) {
+		l.tokens = float64(limit)
+	}
+}
+
+// advance refills the bucket up to now, l.mu is held by the caller.
+func (l *Limiter) advance(now time.Time) {
+	if l.limit > 0 {
+		l.tokens += now.Sub(l.last).Seconds() * float64(l.limit)
+		if l.tokens > float64(l.limit) {
+			l.tokens = float64(l.limit)
+		}
+	}
+	l.last = now
+}
+
+// WaitN blocks until n bytes may be transferred or ctx is done.
+func (l *Limiter) WaitN(ctx context.Context, n int) error {
+	l.mu.Lock()
+	if l.limit <= 0 {
+		l.mu.Unlock()
+		return nil
+	}
+	l.advance(time.Now())
+	// Reserve the bytes right away, later callers wait for
+	// the bandwidth reserved by earlier ones.
+	l.tokens -= float64(n)
+	var wait time.Duration
+	if l.tokens < 0 {
+		wait = time.Duration(-l.tokens / float64(l.limit) * float64(time.Second))
+	}
+	l.mu.Unlock()
+
+	if wait == 0 {
+		return nil
+	}
+	t := time.NewTimer(wait)
+	defer t.Stop()
+	select {
+	case <-t.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+type reader struct {
+	ctx      context.Context
+	r        io.Reader
+	limiters []*Limiter
+}
+
+// NewReader returns a reader which reads from r no faster than
+// allowed by all the given limiters.
+func NewReader(ctx context.Context, r io.Reader, limiters ...*Limiter) io.Reader {
+	return &reader{ctx: ctx, r: r, limiters: limiters}
+}
+
+func (r *reader) Read(p []byte) (n int, err error) {
+	if len(p) > maxChunkSize {
+		p = p[:maxChunkSize]
+	}
+	n, err = r.r.Read(p)
+	if n > 0 {
+		for _, l := range r.limiters {
+			if werr := l.WaitN(r.ctx, n); werr != nil {
+				return n, werr
+			}
+		}
+	}
+	return n, err
+}
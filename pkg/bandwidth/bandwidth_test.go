@@ -0,0 +1,78 @@
+/*
+ * MinIO Cloud Storage, (C) 2019 MinIO, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package bandwidth
+
+import (
+	"bytes"
+	"context"
+	"io/ioutil"
+	"testing"
+	"time"
+)
+
+func TestLimiterUnlimited(t *testing.T) {
+	l := NewLimiter(0)
+	start := time.Now()
+	for i := 0; i < 100; i++ {
+		if err := l.WaitN(context.Background(), 1<<20); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if time.Since(start) > time.Second {
+		t.Fatalf("Unlimited limiter should not wait")
+	}
+}
+
+func TestReader(t *testing.T) {
+	// 64KiB at 128KiB/s needs ~500ms, the bucket starts empty.
+	l := NewLimiter(128 * 1024)
+	data := bytes.Repeat([]byte("a"), 64*1024)
+
+	start := time.Now()
+	got, err := ioutil.ReadAll(NewReader(context.Background(), bytes.NewReader(data), l))
+	if err != nil {
+		t.Fatal(err)
+	}
+	elapsed := time.Since(start)
+	if !bytes.Equal(got, data) {
+		t.Fatalf("Read data does not match")
+	}
+	if elapsed < 400*time.Millisecond {
+		t.Fatalf("Expected read to be throttled, took %s", elapsed)
+	}
+}
+
+func TestReaderCanceled(t *testing.T) {
+	l := NewLimiter(1024)
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	data := bytes.Repeat([]byte("a"), 64*1024)
+	if _, err := ioutil.ReadAll(NewReader(ctx, bytes.NewReader(data), l)); err != context.Canceled {
+		t.Fatalf("Expected %v, got %v", context.Canceled, err)
+	}
+}
+
+func TestLimiterSetLimit(t *testing.T) {
+	l := NewLimiter(1024)
+	l.SetLimit(0)
+	if l.Limit() != 0 {
+		t.Fatalf("Expected limit 0, got %d", l.Limit())
+	}
+	if err := l.WaitN(context.Background(), 1<<20); err != nil {
+		t.Fatal(err)
+	}
+}
@@ -18,6 +18,7 @@ package certs
 
 import (
 	"crypto/tls"
+	"crypto/x509"
 	"os"
 	"path/filepath"
 	"sync"
@@ -90,7 +91,7 @@ func checkSymlink(file string) (bool, error) {
 // on symbolic links.
 func (c *Certs) watchSymlinks() (err error) {
 	c.Lock()
-	c.cert, err = c.loadCert(c.certFile, c.keyFile)
+	c.cert, err = c.load()
 	c.Unlock()
 	if err != nil {
 		return err
@@ -102,7 +103,7 @@ func (c *Certs) watchSymlinks() (err error) {
 				// Once stopped exits this routine.
 				return
 			case <-time.After(24 * time.Hour):
-				cert, cerr := c.loadCert(c.certFile, c.keyFile)
+				cert, cerr := c.load()
 				if cerr != nil {
 					continue
 				}
@@ -139,7 +140,7 @@ func (c *Certs) watch() (err error) {
 		return err
 	}
 	c.Lock()
-	c.cert, err = c.loadCert(c.certFile, c.keyFile)
+	c.cert, err = c.load()
 	c.Unlock()
 	if err != nil {
 		return err
@@ -148,6 +149,21 @@ func (c *Certs) watch() (err error) {
 	return nil
 }
 
+// load loads the certificate and key pair, the leaf certificate is
+// parsed to select certificates by server name.
+func (c *Certs) load() (tls.Certificate, error) {
+	cert, err := c.loadCert(c.certFile, c.keyFile)
+	if err != nil {
+		return cert, err
+	}
+	if cert.Leaf == nil && len(cert.Certificate) > 0 {
+		if cert.Leaf, err = x509.ParseCertificate(cert.Certificate[0]); err != nil {
+			return cert, err
+		}
+	}
+	return cert, nil
+}
+
 func (c *Certs) run() {
 	for event := range c.e {
 		base := filepath.Base(event.Path())
@@ -155,7 +171,7 @@ func (c *Certs) run() {
 			certChanged := base == filepath.Base(c.certFile)
 			keyChanged := base == filepath.Base(c.keyFile)
 			if certChanged || keyChanged {
-				cert, err := c.loadCert(c.certFile, c.keyFile)
+				cert, err := c.load()
 				if err != nil {
 					// ignore the error continue to use
 					// old certificates.
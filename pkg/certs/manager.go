@@ -0,0 +1,243 @@
+/*
+ * MinIO Cloud Storage, (C) 2020 MinIO, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package certs
+
+import (
+	"crypto/tls"
+	"errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/rjeczalik/notify"
+)
+
+// ErrNoCertificates is returned when no certificate and key pair is
+// found in a certs directory.
+var ErrNoCertificates = errors.New("no certificate and key pair found")
+
+// A Manager serves the certificate matching the server name of TLS
+// client hellos. Besides the certificate and key pair of the certs
+// directory, each subdirectory holding a certificate and key pair with
+// the same file names adds a certificate, e.g. for another domain.
+// All certificates are watched for changes, and subdirectories added
+// to or removed from the certs directory are picked up at runtime.
+type Manager struct {
+	sync.RWMutex
+	// user input params.
+	dir      string
+	certFile string
+	keyFile  string
+	loadCert LoadX509KeyPairFunc
+
+	// the certificate of the certs directory, nil if there is none.
+	defaultCert *Certs
+	// certificates of the subdirectories keyed by directory name.
+	certs map[string]*Certs
+
+	// used to watch the certs directory.
+	e chan notify.EventInfo
+}
+
+// NewManager loads the certificate and key pair of the given files and
+// the pairs of the same names in the subdirectories of their directory.
+// At least one certificate and key pair has to be found.
+func NewManager(certFile, keyFile string, loadCert LoadX509KeyPairFunc) (*Manager, error) {
+	m := &Manager{
+		dir:      filepath.Dir(certFile),
+		certFile: filepath.Base(certFile),
+		keyFile:  filepath.Base(keyFile),
+		loadCert: loadCert,
+		certs:    make(map[string]*Certs),
+		e:        make(chan notify.EventInfo, 1),
+	}
+
+	if isFile(certFile) && isFile(keyFile) {
+		c, err := New(certFile, keyFile, loadCert)
+		if err != nil {
+			return nil, err
+		}
+		m.defaultCert = c
+	}
+	if err := m.scan(); err != nil {
+		m.Stop()
+		return nil, err
+	}
+	if m.defaultCert == nil && len(m.certs) == 0 {
+		return nil, ErrNoCertificates
+	}
+
+	// Subdirectories are not known in advance, watch the whole tree
+	// for the certificate files being added or removed.
+	events := append([]notify.Event{notify.Create, notify.Remove, notify.Rename}, eventWrite...)
+	if err := notify.Watch(filepath.Join(m.dir, "..."), m.e, events...); err != nil {
+		m.Stop()
+		return nil, err
+	}
+	go m.run()
+	return m, nil
+}
+
+// HasCertificates returns true if the certs directory holds a
+// certificate and key pair, or any of its subdirectories does.
+func HasCertificates(certFile, keyFile string) bool {
+	if isFile(certFile) && isFile(keyFile) {
+		return true
+	}
+	dirs, _ := certDirs(filepath.Dir(certFile), filepath.Base(certFile), filepath.Base(keyFile))
+	return len(dirs) > 0
+}
+
+// Returns the sorted names of the subdirectories of dir holding a
+// certificate and key pair.
+func certDirs(dir, certFile, keyFile string) ([]string, error) {
+	fis, err := ioutil.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+	var dirs []string
+	for _, fi := range fis {
+		if !fi.IsDir() {
+			continue
+		}
+		if isFile(filepath.Join(dir, fi.Name(), certFile)) && isFile(filepath.Join(dir, fi.Name(), keyFile)) {
+			dirs = append(dirs, fi.Name())
+		}
+	}
+	sort.Strings(dirs)
+	return dirs, nil
+}
+
+func isFile(path string) bool {
+	fi, err := os.Stat(path)
+	return err == nil && fi.Mode().IsRegular()
+}
+
+// scan loads the certificates of subdirectories added since the last
+// scan and drops the certificates of removed subdirectories. Pairs
+// which fail to load are retried on the next scan.
+func (m *Manager) scan() error {
+	dirs, err := certDirs(m.dir, m.certFile, m.keyFile)
+	if err != nil {
+		return err
+	}
+
+	m.Lock()
+	defer m.Unlock()
+	found := make(map[string]bool, len(dirs))
+	for _, dir := range dirs {
+		found[dir] = true
+		if _, ok := m.certs[dir]; ok {
+			continue
+		}
+		c, err := New(filepath.Join(m.dir, dir, m.certFile), filepath.Join(m.dir, dir, m.keyFile), m.loadCert)
+		if err != nil {
+			continue
+		}
+		m.certs[dir] = c
+	}
+	for dir, c := range m.certs {
+		if !found[dir] {
+			c.Stop()
+			delete(m.certs, dir)
+		}
+	}
+	return nil
+}
+
+func (m *Manager) run() {
+	for range m.e {
+		// Ignore errors, the certificates loaded so far
+		// continue to be used.
+		m.scan()
+	}
+}
+
+// GetCertificate returns the certificate for the server name of the
+// client hello, for use by the GetCertificate field of tls.Config.
+// Certificates holding the server name are preferred over certificates
+// matching it through a wildcard, the certificate of the certs
+// directory is returned if no certificate matches.
+func (m *Manager) GetCertificate(hello *tls.ClientHelloInfo) (*tls.Certificate, error) {
+	m.RLock()
+	defer m.RUnlock()
+
+	var serverName string
+	if hello != nil {
+		serverName = strings.ToLower(strings.TrimSuffix(hello.ServerName, "."))
+	}
+	if serverName != "" && len(m.certs) > 0 {
+		var wildcard *Certs
+		for _, dir := range sortedKeys(m.certs) {
+			c := m.certs[dir]
+			c.RLock()
+			leaf := c.cert.Leaf
+			c.RUnlock()
+			if leaf == nil {
+				continue
+			}
+			for _, name := range leaf.DNSNames {
+				if strings.ToLower(name) == serverName {
+					return c.GetCertificate(hello)
+				}
+			}
+			if wildcard == nil && leaf.VerifyHostname(serverName) == nil {
+				wildcard = c
+			}
+		}
+		if wildcard != nil {
+			return wildcard.GetCertificate(hello)
+		}
+	}
+
+	if m.defaultCert != nil {
+		return m.defaultCert.GetCertificate(hello)
+	}
+	// Without a certificate of the certs directory, the first
+	// certificate serves clients which match no certificate.
+	if dirs := sortedKeys(m.certs); len(dirs) > 0 {
+		return m.certs[dirs[0]].GetCertificate(hello)
+	}
+	return nil, ErrNoCertificates
+}
+
+func sortedKeys(certs map[string]*Certs) []string {
+	keys := make([]string, 0, len(certs))
+	for key := range certs {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// Stop stops watching the certs directory and all certificates for
+// changes.
+func (m *Manager) Stop() {
+	if m == nil {
+		return
+	}
+	notify.Stop(m.e)
+	m.Lock()
+	defer m.Unlock()
+	m.defaultCert.Stop()
+	for _, c := range m.certs {
+		c.Stop()
+	}
+}
@@ -0,0 +1,173 @@
+/*
+ * MinIO Cloud Storage, (C) 2020 MinIO, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package certs_test
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"io/ioutil"
+	"math/big"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/minio/minio/pkg/certs"
+)
+
+// writeCert writes a self-signed certificate for the given names and
+// its key to dir.
+func writeCert(t *testing.T, dir string, names ...string) {
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		t.Fatal(err)
+	}
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(time.Now().UnixNano()),
+		Subject:      pkix.Name{CommonName: names[0]},
+		DNSNames:     names,
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Write the key first, the certificate completes the pair.
+	keyPEM := pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER})
+	if err = ioutil.WriteFile(filepath.Join(dir, "private.key"), keyPEM, 0600); err != nil {
+		t.Fatal(err)
+	}
+	certPEM := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})
+	if err = ioutil.WriteFile(filepath.Join(dir, "public.crt"), certPEM, 0600); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// Returns the first DNS name of the certificate served for serverName.
+func servedName(t *testing.T, m *certs.Manager, serverName string) string {
+	cert, err := m.GetCertificate(&tls.ClientHelloInfo{ServerName: serverName})
+	if err != nil {
+		t.Fatal(err)
+	}
+	leaf, err := x509.ParseCertificate(cert.Certificate[0])
+	if err != nil {
+		t.Fatal(err)
+	}
+	return leaf.DNSNames[0]
+}
+
+func TestManagerGetCertificate(t *testing.T) {
+	dir, err := ioutil.TempDir("", "certs-")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	certFile, keyFile := filepath.Join(dir, "public.crt"), filepath.Join(dir, "private.key")
+	if certs.HasCertificates(certFile, keyFile) {
+		t.Fatal("Expected no certificates")
+	}
+	if _, err = certs.NewManager(certFile, keyFile, tls.LoadX509KeyPair); err != certs.ErrNoCertificates {
+		t.Fatalf("Expected %v, got %v", certs.ErrNoCertificates, err)
+	}
+
+	writeCert(t, dir, "minio.local")
+	writeCert(t, filepath.Join(dir, "a-wildcard"), "*.example.com")
+	writeCert(t, filepath.Join(dir, "bucket"), "bucket.example.com")
+	// Subdirectories without a certificate and key pair are skipped.
+	if err = os.MkdirAll(filepath.Join(dir, "CAs"), 0700); err != nil {
+		t.Fatal(err)
+	}
+	if !certs.HasCertificates(certFile, keyFile) {
+		t.Fatal("Expected certificates")
+	}
+
+	m, err := certs.NewManager(certFile, keyFile, tls.LoadX509KeyPair)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer m.Stop()
+
+	testCases := []struct {
+		serverName   string
+		expectedName string
+	}{
+		{"", "minio.local"},
+		{"10.0.0.1", "minio.local"},
+		{"minio.local", "minio.local"},
+		{"Bucket.Example.com.", "bucket.example.com"},
+		{"other.example.com", "*.example.com"},
+		{"a.b.example.com", "minio.local"},
+	}
+	for i, testCase := range testCases {
+		if name := servedName(t, m, testCase.serverName); name != testCase.expectedName {
+			t.Errorf("Test %d: expected %s, got %s", i+1, testCase.expectedName, name)
+		}
+	}
+
+	// Certificates of subdirectories added and removed at runtime
+	// are picked up.
+	writeCert(t, filepath.Join(dir, "photos"), "photos.example.com")
+	waitFor(t, func() bool { return servedName(t, m, "photos.example.com") == "photos.example.com" })
+	if err = os.RemoveAll(filepath.Join(dir, "bucket")); err != nil {
+		t.Fatal(err)
+	}
+	waitFor(t, func() bool { return servedName(t, m, "bucket.example.com") == "*.example.com" })
+}
+
+// Tests that a certificate of a subdirectory serves all clients
+// without a certificate in the certs directory.
+func TestManagerWithoutDefault(t *testing.T) {
+	dir, err := ioutil.TempDir("", "certs-")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	writeCert(t, filepath.Join(dir, "example"), "example.com")
+	m, err := certs.NewManager(filepath.Join(dir, "public.crt"), filepath.Join(dir, "private.key"), tls.LoadX509KeyPair)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer m.Stop()
+	if name := servedName(t, m, "other.com"); name != "example.com" {
+		t.Fatalf("Expected example.com, got %s", name)
+	}
+}
+
+func waitFor(t *testing.T, cond func() bool) {
+	for i := 0; i < 200; i++ {
+		if cond() {
+			return
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	t.Fatal("Timed out waiting for the certificates to be reloaded")
+}
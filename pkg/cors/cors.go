@@ -0,0 +1,171 @@
+/*
+ * MinIO Cloud Storage, (C) 2019 MinIO, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cors
+
+import (
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/minio/minio/pkg/wildcard"
+)
+
+// Maximum number of rules of a CORS configuration.
+const maxRules = 100
+
+var (
+	errTooManyRules     = errors.New("CORS configuration allows a maximum of 100 rules")
+	errNoRule           = errors.New("CORS configuration should have at least one rule")
+	errNoAllowedOrigin  = errors.New("CORS rule should have at least one allowed origin")
+	errNoAllowedMethod  = errors.New("CORS rule should have at least one allowed method")
+	errNegativeMaxAge   = errors.New("CORS rule max age should not be negative")
+	errTooManyWildcards = errors.New("CORS rule allowed origins and headers can contain at most one wildcard")
+)
+
+// Config - bucket CORS configuration as per
+// https://docs.aws.amazon.com/AmazonS3/latest/API/RESTBucketPUTcors.html
+type Config struct {
+	XMLName xml.Name `xml:"CORSConfiguration"`
+	XMLNS   string   `xml:"xmlns,attr,omitempty"`
+	Rules   []Rule   `xml:"CORSRule"`
+}
+
+// Rule - a CORS rule, cross-origin requests are allowed if they
+// match all of its origins, methods and headers.
+type Rule struct {
+	ID             string   `xml:"ID,omitempty"`
+	AllowedHeaders []string `xml:"AllowedHeader,omitempty"`
+	AllowedMethods []string `xml:"AllowedMethod"`
+	AllowedOrigins []string `xml:"AllowedOrigin"`
+	ExposeHeaders  []string `xml:"ExposeHeader,omitempty"`
+	MaxAgeSeconds  int      `xml:"MaxAgeSeconds,omitempty"`
+}
+
+// ParseConfig - parses data in given reader to Config.
+func ParseConfig(reader io.Reader) (*Config, error) {
+	var config Config
+	if err := xml.NewDecoder(reader).Decode(&config); err != nil {
+		return nil, err
+	}
+	if err := config.Validate(); err != nil {
+		return nil, err
+	}
+	return &config, nil
+}
+
+// Validate - validates the CORS configuration.
+func (config Config) Validate() error {
+	if len(config.Rules) > maxRules {
+		return errTooManyRules
+	}
+	if len(config.Rules) == 0 {
+		return errNoRule
+	}
+	for _, rule := range config.Rules {
+		if err := rule.Validate(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Validate - validates the CORS rule.
+func (rule Rule) Validate() error {
+	if len(rule.AllowedOrigins) == 0 {
+		return errNoAllowedOrigin
+	}
+	if len(rule.AllowedMethods) == 0 {
+		return errNoAllowedMethod
+	}
+	for _, method := range rule.AllowedMethods {
+		switch method {
+		case http.MethodGet, http.MethodPut, http.MethodHead, http.MethodPost, http.MethodDelete:
+		default:
+			return fmt.Errorf("Found unsupported HTTP method in CORS config. Unsupported method is %s", method)
+		}
+	}
+	for _, pattern := range append(rule.AllowedOrigins, rule.AllowedHeaders...) {
+		if strings.Count(pattern, "*") > 1 {
+			return errTooManyWildcards
+		}
+	}
+	if rule.MaxAgeSeconds < 0 {
+		return errNegativeMaxAge
+	}
+	return nil
+}
+
+// Match - returns the first rule which allows a cross-origin request
+// from origin with method and headers, which are the actual request
+// method and headers for preflight requests.
+func (config Config) Match(origin, method string, headers []string) (Rule, bool) {
+	for _, rule := range config.Rules {
+		if rule.matchOrigin(origin) && rule.matchMethod(method) && rule.matchHeaders(headers) {
+			return rule, true
+		}
+	}
+	return Rule{}, false
+}
+
+func (rule Rule) matchOrigin(origin string) bool {
+	for _, pattern := range rule.AllowedOrigins {
+		if wildcard.MatchSimple(pattern, origin) {
+			return true
+		}
+	}
+	return false
+}
+
+func (rule Rule) matchMethod(method string) bool {
+	for _, m := range rule.AllowedMethods {
+		if m == method {
+			return true
+		}
+	}
+	return false
+}
+
+// Header names are matched case-insensitively.
+func (rule Rule) matchHeaders(headers []string) bool {
+	for _, header := range headers {
+		header = strings.ToLower(header)
+		allowed := false
+		for _, pattern := range rule.AllowedHeaders {
+			if wildcard.MatchSimple(strings.ToLower(pattern), header) {
+				allowed = true
+				break
+			}
+		}
+		if !allowed {
+			return false
+		}
+	}
+	return true
+}
+
+// AllowsAnyOrigin - returns whether the rule allows all origins.
+func (rule Rule) AllowsAnyOrigin() bool {
+	for _, pattern := range rule.AllowedOrigins {
+		if pattern == "*" {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,91 @@
+/*
+ * MinIO Cloud Storage, (C) 2019 MinIO, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cors
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestParseConfig(t *testing.T) {
+	testCases := []struct {
+		input     string
+		expectErr bool
+	}{
+		{`<CORSConfiguration><CORSRule><AllowedOrigin>*</AllowedOrigin><AllowedMethod>GET</AllowedMethod></CORSRule></CORSConfiguration>`, false},
+		{`<CORSConfiguration><CORSRule><ID>upload</ID><AllowedOrigin>https://*.example.com</AllowedOrigin><AllowedMethod>PUT</AllowedMethod><AllowedMethod>POST</AllowedMethod><AllowedHeader>*</AllowedHeader><ExposeHeader>ETag</ExposeHeader><MaxAgeSeconds>3000</MaxAgeSeconds></CORSRule></CORSConfiguration>`, false},
+		// No rules.
+		{`<CORSConfiguration></CORSConfiguration>`, true},
+		// No allowed origin.
+		{`<CORSConfiguration><CORSRule><AllowedMethod>GET</AllowedMethod></CORSRule></CORSConfiguration>`, true},
+		// No allowed method.
+		{`<CORSConfiguration><CORSRule><AllowedOrigin>*</AllowedOrigin></CORSRule></CORSConfiguration>`, true},
+		// Unsupported method.
+		{`<CORSConfiguration><CORSRule><AllowedOrigin>*</AllowedOrigin><AllowedMethod>PATCH</AllowedMethod></CORSRule></CORSConfiguration>`, true},
+		// More than one wildcard.
+		{`<CORSConfiguration><CORSRule><AllowedOrigin>https://*.*.com</AllowedOrigin><AllowedMethod>GET</AllowedMethod></CORSRule></CORSConfiguration>`, true},
+		// Negative max age.
+		{`<CORSConfiguration><CORSRule><AllowedOrigin>*</AllowedOrigin><AllowedMethod>GET</AllowedMethod><MaxAgeSeconds>-1</MaxAgeSeconds></CORSRule></CORSConfiguration>`, true},
+		// Malformed XML.
+		{`<CORSConfiguration><CORSRule>`, true},
+	}
+	for i, testCase := range testCases {
+		_, err := ParseConfig(strings.NewReader(testCase.input))
+		if (err != nil) != testCase.expectErr {
+			t.Errorf("Test %d: expected error %t, got %v", i+1, testCase.expectErr, err)
+		}
+	}
+}
+
+func TestConfigMatch(t *testing.T) {
+	config := Config{
+		Rules: []Rule{
+			{
+				ID:             "upload",
+				AllowedOrigins: []string{"https://*.example.com"},
+				AllowedMethods: []string{"PUT", "POST"},
+				AllowedHeaders: []string{"Content-*", "x-amz-date"},
+			},
+			{
+				ID:             "read",
+				AllowedOrigins: []string{"*"},
+				AllowedMethods: []string{"GET", "HEAD"},
+			},
+		},
+	}
+	testCases := []struct {
+		origin  string
+		method  string
+		headers []string
+		ruleID  string
+		match   bool
+	}{
+		{"https://app.example.com", "PUT", []string{"content-type", "X-Amz-Date"}, "upload", true},
+		{"https://app.example.com", "PUT", nil, "upload", true},
+		{"https://app.example.com", "PUT", []string{"authorization"}, "", false},
+		{"https://app.example.org", "PUT", nil, "", false},
+		{"https://app.example.com", "DELETE", nil, "", false},
+		{"https://app.example.org", "GET", nil, "read", true},
+		{"https://app.example.org", "GET", []string{"range"}, "", false},
+	}
+	for i, testCase := range testCases {
+		rule, ok := config.Match(testCase.origin, testCase.method, testCase.headers)
+		if ok != testCase.match || rule.ID != testCase.ruleID {
+			t.Errorf("Test %d: expected rule %q (match %t), got %q (match %t)", i+1, testCase.ruleID, testCase.match, rule.ID, ok)
+		}
+	}
+}
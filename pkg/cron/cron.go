@@ -0,0 +1,143 @@
+/*
+ * MinIO Cloud Storage, (C) 2019 MinIO, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+// Package cron implements parsing of standard five field cron
+// expressions of the form
+//
+//	minute hour day-of-month month day-of-week
+//
+// where each field is a comma separated list of '*', a value,
+// a range 'a-b', optionally followed by a step '/n'.
+package cron
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// field bounds in the order of a cron expression.
+var bounds = [5]struct {
+	name     string
+	min, max int
+}{
+	{"minute", 0, 59},
+	{"hour", 0, 23},
+	{"day of month", 1, 31},
+	{"month", 1, 12},
+	{"day of week", 0, 6},
+}
+
+// Schedule - a parsed cron expression, holds the set of matching
+// values per field as a bit mask.
+type Schedule struct {
+	fields [5]uint64
+
+	// Set if the day of month or day of week field is '*', as per
+	// cron a day matches if either of the two restricted fields match.
+	domStar, dowStar bool
+}
+
+// Parse - parses a five field cron expression.
+func Parse(spec string) (*Schedule, error) {
+	fields := strings.Fields(spec)
+	if len(fields) != len(bounds) {
+		return nil, fmt.Errorf("cron: expected %d fields, found %d in %q", len(bounds), len(fields), spec)
+	}
+
+	s := &Schedule{}
+	for i, field := range fields {
+		mask, err := parseField(field, bounds[i].min, bounds[i].max)
+		if err != nil {
+			return nil, fmt.Errorf("cron: invalid %s %q: %v", bounds[i].name, field, err)
+		}
+		s.fields[i] = mask
+	}
+	s.domStar = fields[2] == "*"
+	s.dowStar = fields[4] == "*"
+	return s, nil
+}
+
+// parseField - parses a comma separated list of ranges into a bit mask.
+func parseField(field string, min, max int) (mask uint64, err error) {
+	for _, part := range strings.Split(field, ",") {
+		step := 1
+		if i := strings.Index(part, "/"); i >= 0 {
+			if step, err = strconv.Atoi(part[i+1:]); err != nil || step <= 0 {
+				return 0, fmt.Errorf("invalid step %q", part[i+1:])
+			}
+			part = part[:i]
+		}
+
+		start, end := min, max
+		switch {
+		case part == "*":
+		case strings.Contains(part, "-"):
+			r := strings.SplitN(part, "-", 2)
+			if start, err = strconv.Atoi(r[0]); err != nil {
+				return 0, err
+			}
+			if end, err = strconv.Atoi(r[1]); err != nil {
+				return 0, err
+			}
+		default:
+			if start, err = strconv.Atoi(part); err != nil {
+				return 0, err
+			}
+			end = start
+			if step > 1 {
+				// 'a/n' is short for 'a-max/n'.
+				end = max
+			}
+		}
+		if start < min || end > max || start > end {
+			return 0, fmt.Errorf("range %d-%d out of bounds %d-%d", start, end, min, max)
+		}
+		for v := start; v <= end; v += step {
+			mask |= 1 << uint(v)
+		}
+	}
+	return mask, nil
+}
+
+func (s *Schedule) has(field, value int) bool {
+	return s.fields[field]&(1<<uint(value)) != 0
+}
+
+// Matches - returns true if the minute of t matches the schedule.
+func (s *Schedule) Matches(t time.Time) bool {
+	if !s.has(0, t.Minute()) || !s.has(1, t.Hour()) || !s.has(3, int(t.Month())) {
+		return false
+	}
+	dom, dow := s.has(2, t.Day()), s.has(4, int(t.Weekday()))
+	if s.domStar || s.dowStar {
+		return dom && dow
+	}
+	return dom || dow
+}
+
+// Next - returns the first minute after t matching the schedule, a
+// zero time if there is none within the next five years.
+func (s *Schedule) Next(t time.Time) time.Time {
+	t = t.Truncate(time.Minute).Add(time.Minute)
+	for end := t.AddDate(5, 0, 0); t.Before(end); t = t.Add(time.Minute) {
+		if s.Matches(t) {
+			return t
+		}
+	}
+	return time.Time{}
+}
@@ -0,0 +1,74 @@
+/*
+ * MinIO Cloud Storage, (C) 2019 MinIO, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cron
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParse(t *testing.T) {
+	testCases := []struct {
+		spec    string
+		success bool
+	}{
+		{"* * * * *", true},
+		{"0 2 * * *", true},
+		{"*/15 0-6,22 1 1-12/2 0", true},
+		{"5/10 * * * *", true},
+		{"* * * *", false},
+		{"60 * * * *", false},
+		{"* 24 * * *", false},
+		{"* * 0 * *", false},
+		{"* * * * 7", false},
+		{"*/0 * * * *", false},
+		{"5-1 * * * *", false},
+		{"a * * * *", false},
+	}
+	for i, testCase := range testCases {
+		_, err := Parse(testCase.spec)
+		if testCase.success != (err == nil) {
+			t.Errorf("Test %d: %q expected success %v, got %v", i+1, testCase.spec, testCase.success, err)
+		}
+	}
+}
+
+func TestScheduleNext(t *testing.T) {
+	from := time.Date(2019, time.October, 30, 10, 7, 30, 0, time.UTC) // Wednesday
+	testCases := []struct {
+		spec string
+		next time.Time
+	}{
+		{"* * * * *", time.Date(2019, time.October, 30, 10, 8, 0, 0, time.UTC)},
+		{"*/15 * * * *", time.Date(2019, time.October, 30, 10, 15, 0, 0, time.UTC)},
+		{"0 2 * * *", time.Date(2019, time.October, 31, 2, 0, 0, 0, time.UTC)},
+		{"30 4 1 * *", time.Date(2019, time.November, 1, 4, 30, 0, 0, time.UTC)},
+		{"0 0 * * 0", time.Date(2019, time.November, 3, 0, 0, 0, 0, time.UTC)},
+		// Day of month or day of week.
+		{"0 0 15 * 5", time.Date(2019, time.November, 1, 0, 0, 0, 0, time.UTC)},
+		{"0 0 30 2 *", time.Time{}},
+	}
+	for i, testCase := range testCases {
+		s, err := Parse(testCase.spec)
+		if err != nil {
+			t.Fatalf("Test %d: %v", i+1, err)
+		}
+		if next := s.Next(from); !next.Equal(testCase.next) {
+			t.Errorf("Test %d: %q expected %v, got %v", i+1, testCase.spec, testCase.next, next)
+		}
+	}
+}
@@ -0,0 +1,135 @@
+/*
+ * MinIO Cloud Storage, (C) 2019 MinIO, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package disk
+
+import (
+	"sort"
+	"sync"
+	"time"
+)
+
+// LatencyBounds are the upper bounds of the buckets of latency
+// histograms, slower operations are counted in an extra last bucket.
+var LatencyBounds = [...]time.Duration{
+	time.Millisecond,
+	5 * time.Millisecond,
+	10 * time.Millisecond,
+	25 * time.Millisecond,
+	50 * time.Millisecond,
+	100 * time.Millisecond,
+	250 * time.Millisecond,
+	500 * time.Millisecond,
+	time.Second,
+	5 * time.Second,
+}
+
+// LatencyWindow is the duration covered by rolling latency histograms.
+const LatencyWindow = 10 * time.Minute
+
+// Number of one minute slots of a rolling latency histogram.
+const latencySlots = int(LatencyWindow / time.Minute)
+
+// Latency holds the read and write latency histograms of a disk
+type Latency struct {
+	Path  string           `json:"path"`
+	Error string           `json:"error,omitempty"`
+	Read  LatencyHistogram `json:"read"`
+	Write LatencyHistogram `json:"write"`
+}
+
+// LatencyHistogram holds the number of operations per latency bucket,
+// Counts[i] operations took up to Bounds[i] and the last count holds
+// the operations slower than all bounds.
+type LatencyHistogram struct {
+	Bounds []time.Duration `json:"bounds"`
+	Counts []uint64        `json:"counts"`
+	Total  time.Duration   `json:"total"` // Sum of all latencies.
+}
+
+// Mean returns the mean latency of the operations of the histogram.
+func (h LatencyHistogram) Mean() time.Duration {
+	var count uint64
+	for _, c := range h.Counts {
+		count += c
+	}
+	if count == 0 {
+		return 0
+	}
+	return h.Total / time.Duration(count)
+}
+
+type latencySlot struct {
+	minute int64
+	counts [len(LatencyBounds) + 1]uint64
+	total  time.Duration
+}
+
+// RollingLatency records the latencies of the operations of the last
+// LatencyWindow, it is safe for concurrent use.
+type RollingLatency struct {
+	mu    sync.Mutex
+	slots [latencySlots]latencySlot
+}
+
+// Add records an operation which took d.
+func (l *RollingLatency) Add(d time.Duration) {
+	l.add(time.Now(), d)
+}
+
+func (l *RollingLatency) add(now time.Time, d time.Duration) {
+	minute := now.Unix() / 60
+	bucket := sort.Search(len(LatencyBounds), func(i int) bool { return d <= LatencyBounds[i] })
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	slot := &l.slots[minute%int64(latencySlots)]
+	if slot.minute != minute {
+		*slot = latencySlot{minute: minute}
+	}
+	slot.counts[bucket]++
+	slot.total += d
+}
+
+// Histogram returns the histogram of the operations of the last
+// LatencyWindow.
+func (l *RollingLatency) Histogram() LatencyHistogram {
+	return l.histogram(time.Now())
+}
+
+func (l *RollingLatency) histogram(now time.Time) LatencyHistogram {
+	h := LatencyHistogram{
+		Bounds: append([]time.Duration(nil), LatencyBounds[:]...),
+		Counts: make([]uint64, len(LatencyBounds)+1),
+	}
+	minute := now.Unix() / 60
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	for _, slot := range l.slots {
+		if minute-slot.minute >= int64(latencySlots) {
+			// Out of the window.
+			continue
+		}
+		for i, c := range slot.counts {
+			h.Counts[i] += c
+		}
+		h.Total += slot.total
+	}
+	return h
+}
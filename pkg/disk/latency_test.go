@@ -0,0 +1,63 @@
+/*
+ * MinIO Cloud Storage, (C) 2019 MinIO, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package disk
+
+import (
+	"testing"
+	"time"
+)
+
+func TestRollingLatency(t *testing.T) {
+	var l RollingLatency
+	now := time.Date(2019, time.November, 4, 10, 0, 0, 0, time.UTC)
+
+	l.add(now, 500*time.Microsecond)
+	l.add(now, time.Millisecond)
+	l.add(now.Add(time.Minute), 30*time.Millisecond)
+	l.add(now.Add(2*time.Minute), time.Minute)
+
+	h := l.histogram(now.Add(2 * time.Minute))
+	if len(h.Counts) != len(LatencyBounds)+1 || len(h.Bounds) != len(LatencyBounds) {
+		t.Fatalf("Unexpected histogram shape %v", h)
+	}
+	if h.Counts[0] != 2 || h.Counts[4] != 1 || h.Counts[len(LatencyBounds)] != 1 {
+		t.Fatalf("Unexpected counts %v", h.Counts)
+	}
+	if total := 500*time.Microsecond + time.Millisecond + 30*time.Millisecond + time.Minute; h.Total != total {
+		t.Fatalf("Expected total %v, got %v", total, h.Total)
+	}
+	if mean := h.Total / 4; h.Mean() != mean {
+		t.Fatalf("Expected mean %v, got %v", mean, h.Mean())
+	}
+
+	// The first minute is out of the window.
+	h = l.histogram(now.Add(LatencyWindow))
+	if h.Counts[0] != 0 || h.Counts[4] != 1 {
+		t.Fatalf("Unexpected counts %v", h.Counts)
+	}
+
+	// Slots are reused once out of the window.
+	l.add(now.Add(LatencyWindow), 2*time.Millisecond)
+	h = l.histogram(now.Add(LatencyWindow))
+	if h.Counts[0] != 0 || h.Counts[1] != 1 {
+		t.Fatalf("Unexpected counts %v", h.Counts)
+	}
+
+	if mean := (LatencyHistogram{}).Mean(); mean != 0 {
+		t.Fatalf("Expected no mean, got %v", mean)
+	}
+}
@@ -37,6 +37,7 @@ const (
 	ObjectCreatedPut
 	ObjectRemovedAll
 	ObjectRemovedDelete
+	ObjectRemovedLifecycleExpiration
 )
 
 // Expand - returns expanded values of abbreviated event type.
@@ -47,12 +48,22 @@ func (name Name) Expand() []Name {
 	case ObjectCreatedAll:
 		return []Name{ObjectCreatedCompleteMultipartUpload, ObjectCreatedCopy, ObjectCreatedPost, ObjectCreatedPut}
 	case ObjectRemovedAll:
-		return []Name{ObjectRemovedDelete}
+		return []Name{ObjectRemovedDelete, ObjectRemovedLifecycleExpiration}
 	default:
 		return []Name{name}
 	}
 }
 
+// IsRemoval - returns whether the event name reports the removal of an object.
+func (name Name) IsRemoval() bool {
+	switch name {
+	case ObjectRemovedDelete, ObjectRemovedLifecycleExpiration:
+		return true
+	}
+
+	return false
+}
+
 // String - returns string representation of event type.
 func (name Name) String() string {
 	switch name {
@@ -76,6 +87,8 @@ func (name Name) String() string {
 		return "s3:ObjectRemoved:*"
 	case ObjectRemovedDelete:
 		return "s3:ObjectRemoved:Delete"
+	case ObjectRemovedLifecycleExpiration:
+		return "s3:ObjectRemoved:LifecycleExpiration"
 	}
 
 	return ""
@@ -146,6 +159,8 @@ func ParseName(s string) (Name, error) {
 		return ObjectRemovedAll, nil
 	case "s3:ObjectRemoved:Delete":
 		return ObjectRemovedDelete, nil
+	case "s3:ObjectRemoved:LifecycleExpiration":
+		return ObjectRemovedLifecycleExpiration, nil
 	default:
 		return 0, &ErrInvalidEventName{s}
 	}
@@ -30,7 +30,7 @@ func TestNameExpand(t *testing.T) {
 	}{
 		{ObjectAccessedAll, []Name{ObjectAccessedGet, ObjectAccessedHead}},
 		{ObjectCreatedAll, []Name{ObjectCreatedCompleteMultipartUpload, ObjectCreatedCopy, ObjectCreatedPost, ObjectCreatedPut}},
-		{ObjectRemovedAll, []Name{ObjectRemovedDelete}},
+		{ObjectRemovedAll, []Name{ObjectRemovedDelete, ObjectRemovedLifecycleExpiration}},
 		{ObjectAccessedHead, []Name{ObjectAccessedHead}},
 	}
 
@@ -60,6 +60,7 @@ func TestNameString(t *testing.T) {
 		{ObjectCreatedPut, "s3:ObjectCreated:Put"},
 		{ObjectRemovedAll, "s3:ObjectRemoved:*"},
 		{ObjectRemovedDelete, "s3:ObjectRemoved:Delete"},
+		{ObjectRemovedLifecycleExpiration, "s3:ObjectRemoved:LifecycleExpiration"},
 		{blankName, ""},
 	}
 
@@ -72,6 +73,26 @@ func TestNameString(t *testing.T) {
 	}
 }
 
+func TestNameIsRemoval(t *testing.T) {
+	testCases := []struct {
+		name           Name
+		expectedResult bool
+	}{
+		{ObjectRemovedDelete, true},
+		{ObjectRemovedLifecycleExpiration, true},
+		{ObjectCreatedPut, false},
+		{ObjectAccessedGet, false},
+	}
+
+	for i, testCase := range testCases {
+		result := testCase.name.IsRemoval()
+
+		if result != testCase.expectedResult {
+			t.Fatalf("test %v: result: expected: %v, got: %v", i+1, testCase.expectedResult, result)
+		}
+	}
+}
+
 func TestNameMarshalXML(t *testing.T) {
 	var blankName Name
 
@@ -200,6 +221,7 @@ func TestParseName(t *testing.T) {
 	}{
 		{"s3:ObjectAccessed:*", ObjectAccessedAll, false},
 		{"s3:ObjectRemoved:Delete", ObjectRemovedDelete, false},
+		{"s3:ObjectRemoved:LifecycleExpiration", ObjectRemovedLifecycleExpiration, false},
 		{"", blankName, true},
 	}
 
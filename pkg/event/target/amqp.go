@@ -209,6 +209,16 @@ func (target *AMQPTarget) Send(eventKey string) error {
 	return target.store.Del(eventKey)
 }
 
+// Ping - checks whether a channel can be opened on the AMQP connection,
+// reconnecting if needed.
+func (target *AMQPTarget) Ping() error {
+	ch, err := target.channel()
+	if err != nil {
+		return err
+	}
+	return ch.Close()
+}
+
 // Close - does nothing and available for interface compatibility.
 func (target *AMQPTarget) Close() error {
 	return nil
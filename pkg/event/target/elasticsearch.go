@@ -128,7 +128,7 @@ func (target *ElasticsearchTarget) send(eventData event.Event) error {
 		}
 
 		key = eventData.S3.Bucket.Name + "/" + objectName
-		if eventData.EventName == event.ObjectRemovedDelete {
+		if eventData.EventName.IsRemoval() {
 			err = remove()
 		} else {
 			err = update()
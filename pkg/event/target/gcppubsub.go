@@ -0,0 +1,249 @@
+/*
+ * MinIO Cloud Storage, (C) 2019 MinIO, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package target
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"cloud.google.com/go/pubsub"
+	"google.golang.org/api/option"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/minio/minio/pkg/event"
+)
+
+// Timeout of a publish or of a topic check.
+const gcpPubSubTimeout = 30 * time.Second
+
+// GCPPubSubArgs - Google Cloud Pub/Sub target arguments.
+type GCPPubSubArgs struct {
+	Enable    bool   `json:"enable"`
+	ProjectID string `json:"projectID"`
+	Topic     string `json:"topic"`
+	// Path of a service account JSON key file, the application
+	// default credentials are used if empty.
+	CredentialsFile string `json:"credentialsFile"`
+	// Publish events one at a time with the bucket/object key as the
+	// "key" attribute, so that events of an object are published in
+	// the order they occurred.
+	OrderingKey bool   `json:"orderingKey"`
+	QueueDir    string `json:"queueDir"`
+	QueueLimit  uint64 `json:"queueLimit"`
+}
+
+// Validate GCPPubSubArgs fields
+func (p GCPPubSubArgs) Validate() error {
+	if !p.Enable {
+		return nil
+	}
+
+	if p.ProjectID == "" {
+		return errors.New("empty projectID")
+	}
+
+	if p.Topic == "" {
+		return errors.New("empty topic")
+	}
+	if p.CredentialsFile != "" {
+		if !filepath.IsAbs(p.CredentialsFile) {
+			return errors.New("credentialsFile path should be absolute")
+		}
+	}
+	if p.QueueDir != "" {
+		if !filepath.IsAbs(p.QueueDir) {
+			return errors.New("queueDir path should be absolute")
+		}
+	}
+	if p.QueueLimit > 10000 {
+		return errors.New("queueLimit should not exceed 10000")
+	}
+
+	return nil
+}
+
+// GCPPubSubTarget - Google Cloud Pub/Sub target.
+type GCPPubSubTarget struct {
+	id     event.TargetID
+	args   GCPPubSubArgs
+	client *pubsub.Client
+	topic  *pubsub.Topic
+	store  Store
+	// Serializes publishing if events are ordered.
+	mu sync.Mutex
+}
+
+// ID - returns target ID.
+func (target *GCPPubSubTarget) ID() event.TargetID {
+	return target.id
+}
+
+// Save - saves the events to the store which will be replayed when the Pub/Sub topic is reachable.
+func (target *GCPPubSubTarget) Save(eventData event.Event) error {
+	if target.store != nil {
+		return target.store.Put(eventData)
+	}
+	return target.send(eventData)
+}
+
+// send - publishes an event to the Pub/Sub topic.
+func (target *GCPPubSubTarget) send(eventData event.Event) error {
+	objectName, err := url.QueryUnescape(eventData.S3.Object.Key)
+	if err != nil {
+		return err
+	}
+	key := eventData.S3.Bucket.Name + "/" + objectName
+
+	data, err := json.Marshal(event.Log{EventName: eventData.EventName, Key: key, Records: []event.Event{eventData}})
+	if err != nil {
+		return err
+	}
+
+	msg := &pubsub.Message{
+		Data: data,
+		Attributes: map[string]string{
+			"eventName": eventData.EventName.String(),
+		},
+	}
+	if target.args.OrderingKey {
+		msg.Attributes["key"] = key
+		target.mu.Lock()
+		defer target.mu.Unlock()
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), gcpPubSubTimeout)
+	defer cancel()
+
+	_, err = target.topic.Publish(ctx, msg).Get(ctx)
+	if isGCPPubSubConnErr(err) {
+		return errNotConnected
+	}
+	return err
+}
+
+// Send - reads an event from store and publishes it to the Pub/Sub topic.
+func (target *GCPPubSubTarget) Send(eventKey string) error {
+	eventData, eErr := target.store.Get(eventKey)
+	if eErr != nil {
+		// The last event key in a successful batch will be sent in the channel atmost once by the replayEvents()
+		// Such events will not exist and wouldve been already been sent successfully.
+		if os.IsNotExist(eErr) {
+			return nil
+		}
+		return eErr
+	}
+
+	if err := target.send(eventData); err != nil {
+		return err
+	}
+
+	// Delete the event from store.
+	return target.store.Del(eventKey)
+}
+
+// Ping - checks whether the Pub/Sub topic is reachable.
+func (target *GCPPubSubTarget) Ping() error {
+	ctx, cancel := context.WithTimeout(context.Background(), gcpPubSubTimeout)
+	defer cancel()
+
+	exists, err := target.topic.Exists(ctx)
+	if err != nil {
+		if isGCPPubSubConnErr(err) {
+			return errNotConnected
+		}
+		return err
+	}
+	if !exists {
+		return errors.New("topic " + target.args.Topic + " does not exist")
+	}
+	return nil
+}
+
+// Close - stops publishing and closes the Pub/Sub client.
+func (target *GCPPubSubTarget) Close() error {
+	// this blocks until all published events are sent.
+	target.topic.Stop()
+	return target.client.Close()
+}
+
+// isGCPPubSubConnErr - returns whether err means Pub/Sub is unreachable.
+func isGCPPubSubConnErr(err error) bool {
+	if err == nil {
+		return false
+	}
+	if err == context.DeadlineExceeded {
+		return true
+	}
+	switch status.Code(err) {
+	case codes.Unavailable, codes.DeadlineExceeded:
+		return true
+	}
+	return false
+}
+
+// NewGCPPubSubTarget - creates new Google Cloud Pub/Sub target.
+func NewGCPPubSubTarget(id string, args GCPPubSubArgs, doneCh <-chan struct{}) (*GCPPubSubTarget, error) {
+	var store Store
+
+	if args.QueueDir != "" {
+		queueDir := filepath.Join(args.QueueDir, storePrefix+"-gcppubsub-"+id)
+		store = NewQueueStore(queueDir, args.QueueLimit)
+		if oErr := store.Open(); oErr != nil {
+			return nil, oErr
+		}
+	}
+
+	var opts []option.ClientOption
+	if args.CredentialsFile != "" {
+		opts = append(opts, option.WithCredentialsFile(args.CredentialsFile))
+	}
+	client, err := pubsub.NewClient(context.Background(), args.ProjectID, opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	target := &GCPPubSubTarget{
+		id:     event.TargetID{ID: id, Name: "gcppubsub"},
+		args:   args,
+		client: client,
+		topic:  client.Topic(args.Topic),
+		store:  store,
+	}
+
+	if err = target.Ping(); err != nil {
+		if target.store == nil || err != errNotConnected {
+			target.client.Close()
+			return nil, err
+		}
+	}
+
+	if target.store != nil {
+		// Replays the events from the store.
+		eventKeyCh := replayEvents(target.store, doneCh)
+		// Start replaying events from the store.
+		go sendEvents(target, eventKeyCh, doneCh)
+	}
+
+	return target, nil
+}
@@ -0,0 +1,75 @@
+/*
+ * MinIO Cloud Storage, (C) 2019 MinIO, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package target
+
+import (
+	"errors"
+	"testing"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestGCPPubSubArgs_Validate(t *testing.T) {
+	tests := []struct {
+		name    string
+		args    GCPPubSubArgs
+		wantErr bool
+	}{
+		{
+			name:    "test1_disabled",
+			args:    GCPPubSubArgs{Enable: false},
+			wantErr: false,
+		},
+		{
+			name:    "test2_missing_project",
+			args:    GCPPubSubArgs{Enable: true, Topic: "topic"},
+			wantErr: true,
+		},
+		{
+			name:    "test3_missing_topic",
+			args:    GCPPubSubArgs{Enable: true, ProjectID: "project"},
+			wantErr: true,
+		},
+		{
+			name:    "test4_relative_credentials",
+			args:    GCPPubSubArgs{Enable: true, ProjectID: "project", Topic: "topic", CredentialsFile: "key.json"},
+			wantErr: true,
+		},
+		{
+			name:    "test5_OK",
+			args:    GCPPubSubArgs{Enable: true, ProjectID: "project", Topic: "topic", CredentialsFile: "/etc/minio/key.json", OrderingKey: true},
+			wantErr: false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.args.Validate(); (err != nil) != tt.wantErr {
+				t.Errorf("GCPPubSubArgs.Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestIsGCPPubSubConnErr(t *testing.T) {
+	if isGCPPubSubConnErr(nil) || isGCPPubSubConnErr(errors.New("permission denied")) {
+		t.Fatal("Expected not to be a connection error")
+	}
+	if !isGCPPubSubConnErr(status.Error(codes.Unavailable, "unavailable")) {
+		t.Fatal("Expected to be a connection error")
+	}
+}
@@ -168,6 +168,14 @@ func (target *KafkaTarget) Send(eventKey string) error {
 	return target.store.Del(eventKey)
 }
 
+// Ping - checks whether at least one of the Kafka brokers is reachable.
+func (target *KafkaTarget) Ping() error {
+	if !target.args.pingBrokers() {
+		return errNotConnected
+	}
+	return nil
+}
+
 // Close - closes underneath kafka connection.
 func (target *KafkaTarget) Close() error {
 	if target.producer != nil {
@@ -181,7 +181,7 @@ func (target *MySQLTarget) send(eventData event.Event) error {
 		}
 		key := eventData.S3.Bucket.Name + "/" + objectName
 
-		if eventData.EventName == event.ObjectRemovedDelete {
+		if eventData.EventName.IsRemoval() {
 			_, err = target.deleteStmt.Exec(key)
 		} else {
 			var data []byte
@@ -18,9 +18,94 @@ package target
 
 import (
 	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"sync"
 	"testing"
+	"time"
+
+	"github.com/minio/minio/pkg/event"
 )
 
+// recordingDriver - sql driver which records executed statements
+// instead of talking to a database.
+type recordingDriver struct {
+	mu    sync.Mutex
+	execs []recordedExec
+}
+
+type recordedExec struct {
+	query string
+	args  []driver.Value
+}
+
+func (d *recordingDriver) Open(name string) (driver.Conn, error) {
+	return recordingConn{d}, nil
+}
+
+func (d *recordingDriver) reset() {
+	d.mu.Lock()
+	d.execs = nil
+	d.mu.Unlock()
+}
+
+func (d *recordingDriver) recorded() []recordedExec {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return append([]recordedExec(nil), d.execs...)
+}
+
+type recordingConn struct {
+	d *recordingDriver
+}
+
+func (c recordingConn) Prepare(query string) (driver.Stmt, error) {
+	return recordingStmt{c.d, query}, nil
+}
+
+func (c recordingConn) Close() error { return nil }
+
+func (c recordingConn) Begin() (driver.Tx, error) {
+	return nil, errors.New("transactions are not supported")
+}
+
+type recordingStmt struct {
+	d     *recordingDriver
+	query string
+}
+
+func (s recordingStmt) Close() error  { return nil }
+func (s recordingStmt) NumInput() int { return -1 }
+
+func (s recordingStmt) Exec(args []driver.Value) (driver.Result, error) {
+	s.d.mu.Lock()
+	s.d.execs = append(s.d.execs, recordedExec{s.query, args})
+	s.d.mu.Unlock()
+	return driver.RowsAffected(1), nil
+}
+
+func (s recordingStmt) Query(args []driver.Value) (driver.Rows, error) {
+	return nil, errors.New("queries are not supported")
+}
+
+var testRecordingDriver = &recordingDriver{}
+
+func init() {
+	sql.Register("recording", testRecordingDriver)
+}
+
+// newTestRemovalEvent - returns an event of name for mybucket/myobject.
+func newTestRemovalEvent(name event.Name) event.Event {
+	eventData := event.Event{
+		EventName: name,
+		EventTime: time.Date(2019, time.October, 15, 10, 0, 0, 0, time.UTC).Format(event.AMZTimeFormat),
+	}
+	eventData.S3.Bucket.Name = "mybucket"
+	eventData.S3.Object.Key = "myobject"
+	return eventData
+}
+
 // TestPostgreSQLRegistration checks if sql driver
 // is registered and fails otherwise.
 func TestMySQLRegistration(t *testing.T) {
@@ -35,3 +120,44 @@ func TestMySQLRegistration(t *testing.T) {
 		t.Fatal("mysql driver not registered")
 	}
 }
+
+// TestMySQLNamespaceRemoval checks that both deletions and lifecycle
+// expirations remove the key from a namespace table.
+func TestMySQLNamespaceRemoval(t *testing.T) {
+	db, err := sql.Open("recording", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+
+	target := &MySQLTarget{
+		args: MySQLArgs{Format: event.NamespaceFormat, Table: "events"},
+		db:   db,
+	}
+	if target.updateStmt, err = db.Prepare(fmt.Sprintf(mysqlUpdateRow, "events")); err != nil {
+		t.Fatal(err)
+	}
+	if target.deleteStmt, err = db.Prepare(fmt.Sprintf(mysqlDeleteRow, "events")); err != nil {
+		t.Fatal(err)
+	}
+
+	testCases := []struct {
+		name          event.Name
+		expectedQuery string
+	}{
+		{event.ObjectCreatedPut, fmt.Sprintf(mysqlUpdateRow, "events")},
+		{event.ObjectRemovedDelete, fmt.Sprintf(mysqlDeleteRow, "events")},
+		{event.ObjectRemovedLifecycleExpiration, fmt.Sprintf(mysqlDeleteRow, "events")},
+	}
+
+	for i, testCase := range testCases {
+		testRecordingDriver.reset()
+		if err = target.send(newTestRemovalEvent(testCase.name)); err != nil {
+			t.Fatalf("test %v: unexpected error %v", i+1, err)
+		}
+		execs := testRecordingDriver.recorded()
+		if len(execs) != 1 || execs[0].query != testCase.expectedQuery {
+			t.Errorf("test %v: expected %v, got %v", i+1, testCase.expectedQuery, execs)
+		}
+	}
+}
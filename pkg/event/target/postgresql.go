@@ -50,6 +50,29 @@
 //     event_time TIMESTAMP WITH TIME ZONE NOT NULL,
 //     event_data JSONB
 // );
+//
+// * Append mode
+//
+// Setting the mode to "append" makes the namespace format keep the
+// full history of each key instead of only its latest state. Every
+// event appends a row, object removals append a row with a NULL
+// value. The table schema used for this mode is:
+//
+// CREATE TABLE myminio (
+//     key VARCHAR NOT NULL,
+//     event_time TIMESTAMP WITH TIME ZONE NOT NULL,
+//     value JSONB
+// );
+//
+// * Partitioning
+//
+// Tables which are only ever appended to, i.e. the access format and
+// the namespace format in append mode, may be partitioned by setting
+// partition to "monthly". The table is then created as a range
+// partitioned table on event_time and a partition named
+// <table>_YYYY_MM is created automatically for each month an event
+// is received in. Declarative partitioning requires PostgreSQL 10
+// or above.
 
 package target
 
@@ -63,6 +86,7 @@ import (
 	"path/filepath"
 	"strconv"
 	"strings"
+	"sync"
 	"time"
 
 	_ "github.com/lib/pq" // Register postgres driver
@@ -72,13 +96,25 @@ import (
 )
 
 const (
-	psqlTableExists          = `SELECT 1 FROM %s;`
-	psqlCreateNamespaceTable = `CREATE TABLE %s (key VARCHAR PRIMARY KEY, value JSONB);`
-	psqlCreateAccessTable    = `CREATE TABLE %s (event_time TIMESTAMP WITH TIME ZONE NOT NULL, event_data JSONB);`
+	psqlTableExists             = `SELECT 1 FROM %s;`
+	psqlCreateNamespaceTable    = `CREATE TABLE %s (key VARCHAR PRIMARY KEY, value JSONB);`
+	psqlCreateNamespaceLogTable = `CREATE TABLE %s (key VARCHAR NOT NULL, event_time TIMESTAMP WITH TIME ZONE NOT NULL, value JSONB)%s;`
+	psqlCreateAccessTable       = `CREATE TABLE %s (event_time TIMESTAMP WITH TIME ZONE NOT NULL, event_data JSONB)%s;`
+	psqlPartitionByEventTime    = ` PARTITION BY RANGE (event_time)`
+	psqlCreatePartition         = `CREATE TABLE IF NOT EXISTS %s PARTITION OF %s FOR VALUES FROM ('%s') TO ('%s');`
+
+	psqlUpdateRow          = `INSERT INTO %s (key, value) VALUES ($1, $2) ON CONFLICT (key) DO UPDATE SET value = EXCLUDED.value;`
+	psqlDeleteRow          = `DELETE FROM %s WHERE key = $1;`
+	psqlInsertRow          = `INSERT INTO %s (event_time, event_data) VALUES ($1, $2);`
+	psqlInsertNamespaceRow = `INSERT INTO %s (key, event_time, value) VALUES ($1, $2, $3);`
+)
+
+// PostgreSQL target modes and partitioning schemes.
+const (
+	PostgreSQLModeUpsert = "upsert"
+	PostgreSQLModeAppend = "append"
 
-	psqlUpdateRow = `INSERT INTO %s (key, value) VALUES ($1, $2) ON CONFLICT (key) DO UPDATE SET value = EXCLUDED.value;`
-	psqlDeleteRow = `DELETE FROM %s WHERE key = $1;`
-	psqlInsertRow = `INSERT INTO %s (event_time, event_data) VALUES ($1, $2);`
+	PostgreSQLPartitionMonthly = "monthly"
 )
 
 // PostgreSQLArgs - PostgreSQL target arguments.
@@ -87,11 +123,13 @@ type PostgreSQLArgs struct {
 	Format           string    `json:"format"`
 	ConnectionString string    `json:"connectionString"`
 	Table            string    `json:"table"`
-	Host             xnet.Host `json:"host"`     // default: localhost
-	Port             string    `json:"port"`     // default: 5432
-	User             string    `json:"user"`     // default: user running minio
-	Password         string    `json:"password"` // default: no password
-	Database         string    `json:"database"` // default: same as user
+	Host             xnet.Host `json:"host"`      // default: localhost
+	Port             string    `json:"port"`      // default: 5432
+	User             string    `json:"user"`      // default: user running minio
+	Password         string    `json:"password"`  // default: no password
+	Database         string    `json:"database"`  // default: same as user
+	Mode             string    `json:"mode"`      // default: upsert for namespace format
+	Partition        string    `json:"partition"` // default: no partitioning
 	QueueDir         string    `json:"queueDir"`
 	QueueLimit       uint64    `json:"queueLimit"`
 }
@@ -111,6 +149,26 @@ func (p PostgreSQLArgs) Validate() error {
 		}
 	}
 
+	switch strings.ToLower(p.Mode) {
+	case "", PostgreSQLModeAppend:
+	case PostgreSQLModeUpsert:
+		if strings.ToLower(p.Format) == event.AccessFormat {
+			return fmt.Errorf("upsert mode is not supported with access format")
+		}
+	default:
+		return fmt.Errorf("unrecognized mode value")
+	}
+
+	switch strings.ToLower(p.Partition) {
+	case "":
+	case PostgreSQLPartitionMonthly:
+		if !p.appendOnly() {
+			return fmt.Errorf("partitioning requires access format or append mode")
+		}
+	default:
+		return fmt.Errorf("unrecognized partition value")
+	}
+
 	if p.ConnectionString != "" {
 		// No pq API doesn't help to validate connection string
 		// prior connection, so no validation for now.
@@ -139,6 +197,11 @@ func (p PostgreSQLArgs) Validate() error {
 	return nil
 }
 
+// appendOnly - returns true if rows are only ever appended to the table.
+func (p PostgreSQLArgs) appendOnly() bool {
+	return strings.ToLower(p.Format) == event.AccessFormat || strings.ToLower(p.Mode) == PostgreSQLModeAppend
+}
+
 // PostgreSQLTarget - PostgreSQL target.
 type PostgreSQLTarget struct {
 	id         event.TargetID
@@ -149,6 +212,10 @@ type PostgreSQLTarget struct {
 	db         *sql.DB
 	store      Store
 	firstPing  bool
+
+	// Monthly partitions known to exist.
+	partitionsMu sync.Mutex
+	partitions   map[string]struct{}
 }
 
 // ID - returns target ID.
@@ -175,8 +242,66 @@ func IsConnErr(err error) bool {
 	return IsConnRefusedErr(err) || err.Error() == "sql: database is closed" || err.Error() == "sql: statement is closed" || err.Error() == "invalid connection"
 }
 
+// psqlPartition - returns the name and the bounds of the monthly
+// partition of table which holds rows for eventTime.
+func psqlPartition(table string, eventTime time.Time) (name, from, to string) {
+	eventTime = eventTime.UTC()
+	start := time.Date(eventTime.Year(), eventTime.Month(), 1, 0, 0, 0, 0, time.UTC)
+	end := start.AddDate(0, 1, 0)
+	name = fmt.Sprintf("%s_%04d_%02d", table, start.Year(), start.Month())
+	return name, start.Format(time.RFC3339), end.Format(time.RFC3339)
+}
+
+// ensurePartition - creates the partition for eventTime if it is not known to exist.
+func (target *PostgreSQLTarget) ensurePartition(eventTime time.Time) error {
+	if target.args.Partition != PostgreSQLPartitionMonthly {
+		return nil
+	}
+
+	name, from, to := psqlPartition(target.args.Table, eventTime)
+
+	target.partitionsMu.Lock()
+	defer target.partitionsMu.Unlock()
+	if _, ok := target.partitions[name]; ok {
+		return nil
+	}
+	if _, err := target.db.Exec(fmt.Sprintf(psqlCreatePartition, name, target.args.Table, from, to)); err != nil {
+		return err
+	}
+	target.partitions[name] = struct{}{}
+	return nil
+}
+
 // send - sends an event to the PostgreSQL.
 func (target *PostgreSQLTarget) send(eventData event.Event) error {
+	if target.args.Format == event.NamespaceFormat && target.args.Mode == PostgreSQLModeAppend {
+		objectName, err := url.QueryUnescape(eventData.S3.Object.Key)
+		if err != nil {
+			return err
+		}
+		key := eventData.S3.Bucket.Name + "/" + objectName
+
+		eventTime, err := time.Parse(event.AMZTimeFormat, eventData.EventTime)
+		if err != nil {
+			return err
+		}
+
+		// Removals are recorded with a NULL value.
+		var data []byte
+		if !eventData.EventName.IsRemoval() {
+			if data, err = json.Marshal(struct{ Records []event.Event }{[]event.Event{eventData}}); err != nil {
+				return err
+			}
+		}
+
+		if err = target.ensurePartition(eventTime); err != nil {
+			return err
+		}
+
+		_, err = target.insertStmt.Exec(key, eventTime, data)
+		return err
+	}
+
 	if target.args.Format == event.NamespaceFormat {
 		objectName, err := url.QueryUnescape(eventData.S3.Object.Key)
 		if err != nil {
@@ -184,7 +309,7 @@ func (target *PostgreSQLTarget) send(eventData event.Event) error {
 		}
 		key := eventData.S3.Bucket.Name + "/" + objectName
 
-		if eventData.EventName == event.ObjectRemovedDelete {
+		if eventData.EventName.IsRemoval() {
 			_, err = target.deleteStmt.Exec(key)
 		} else {
 			var data []byte
@@ -208,6 +333,10 @@ func (target *PostgreSQLTarget) send(eventData event.Event) error {
 			return err
 		}
 
+		if err = target.ensurePartition(eventTime); err != nil {
+			return err
+		}
+
 		if _, err = target.insertStmt.Exec(eventTime, data); err != nil {
 			return err
 		}
@@ -281,18 +410,32 @@ func (target *PostgreSQLTarget) executeStmts() error {
 
 	_, err := target.db.Exec(fmt.Sprintf(psqlTableExists, target.args.Table))
 	if err != nil {
-		createStmt := psqlCreateNamespaceTable
+		var partitionBy string
+		if target.args.Partition == PostgreSQLPartitionMonthly {
+			partitionBy = psqlPartitionByEventTime
+		}
+
+		createStmt := fmt.Sprintf(psqlCreateNamespaceTable, target.args.Table)
 		if target.args.Format == event.AccessFormat {
-			createStmt = psqlCreateAccessTable
+			createStmt = fmt.Sprintf(psqlCreateAccessTable, target.args.Table, partitionBy)
+		} else if target.args.Mode == PostgreSQLModeAppend {
+			createStmt = fmt.Sprintf(psqlCreateNamespaceLogTable, target.args.Table, partitionBy)
 		}
 
-		if _, dbErr := target.db.Exec(fmt.Sprintf(createStmt, target.args.Table)); dbErr != nil {
+		if _, dbErr := target.db.Exec(createStmt); dbErr != nil {
 			return dbErr
 		}
 	}
 
 	switch target.args.Format {
 	case event.NamespaceFormat:
+		if target.args.Mode == PostgreSQLModeAppend {
+			// insert statement
+			if target.insertStmt, err = target.db.Prepare(fmt.Sprintf(psqlInsertNamespaceRow, target.args.Table)); err != nil {
+				return err
+			}
+			break
+		}
 		// insert or update statement
 		if target.updateStmt, err = target.db.Prepare(fmt.Sprintf(psqlUpdateRow, target.args.Table)); err != nil {
 			return err
@@ -315,6 +458,12 @@ func (target *PostgreSQLTarget) executeStmts() error {
 func NewPostgreSQLTarget(id string, args PostgreSQLArgs, doneCh <-chan struct{}) (*PostgreSQLTarget, error) {
 	var firstPing bool
 
+	// Enumerated values are matched case insensitively by Validate,
+	// normalize them once so that they can be compared directly.
+	args.Format = strings.ToLower(args.Format)
+	args.Mode = strings.ToLower(args.Mode)
+	args.Partition = strings.ToLower(args.Partition)
+
 	params := []string{args.ConnectionString}
 	if !args.Host.IsEmpty() {
 		params = append(params, "host="+args.Host.String())
@@ -349,11 +498,12 @@ func NewPostgreSQLTarget(id string, args PostgreSQLArgs, doneCh <-chan struct{})
 	}
 
 	target := &PostgreSQLTarget{
-		id:        event.TargetID{ID: id, Name: "postgresql"},
-		args:      args,
-		db:        db,
-		store:     store,
-		firstPing: firstPing,
+		id:         event.TargetID{ID: id, Name: "postgresql"},
+		args:       args,
+		db:         db,
+		store:      store,
+		firstPing:  firstPing,
+		partitions: make(map[string]struct{}),
 	}
 
 	err = target.db.Ping()
@@ -18,7 +18,11 @@ package target
 
 import (
 	"database/sql"
+	"fmt"
 	"testing"
+	"time"
+
+	"github.com/minio/minio/pkg/event"
 )
 
 // TestPostgreSQLRegistration checks if postgres driver
@@ -35,3 +39,127 @@ func TestPostgreSQLRegistration(t *testing.T) {
 		t.Fatal("postgres driver not registered")
 	}
 }
+
+// TestPostgreSQLPartition checks the monthly partition name and bounds.
+func TestPostgreSQLPartition(t *testing.T) {
+	testCases := []struct {
+		eventTime time.Time
+		name      string
+		from      string
+		to        string
+	}{
+		{time.Date(2019, time.October, 15, 10, 0, 0, 0, time.UTC), "events_2019_10", "2019-10-01T00:00:00Z", "2019-11-01T00:00:00Z"},
+		{time.Date(2019, time.December, 31, 23, 59, 59, 0, time.UTC), "events_2019_12", "2019-12-01T00:00:00Z", "2020-01-01T00:00:00Z"},
+		{time.Date(2020, time.January, 1, 1, 0, 0, 0, time.FixedZone("CET", 3600*2)), "events_2019_12", "2019-12-01T00:00:00Z", "2020-01-01T00:00:00Z"},
+	}
+
+	for i, testCase := range testCases {
+		name, from, to := psqlPartition("events", testCase.eventTime)
+		if name != testCase.name || from != testCase.from || to != testCase.to {
+			t.Errorf("test %v: expected (%v, %v, %v), got (%v, %v, %v)", i+1,
+				testCase.name, testCase.from, testCase.to, name, from, to)
+		}
+	}
+}
+
+// TestPostgreSQLArgsValidate checks mode and partition validation.
+func TestPostgreSQLArgsValidate(t *testing.T) {
+	testCases := []struct {
+		format    string
+		mode      string
+		partition string
+		expectErr bool
+	}{
+		{"namespace", "", "", false},
+		{"namespace", "upsert", "", false},
+		{"namespace", "append", "monthly", false},
+		{"namespace", "upsert", "monthly", true},
+		{"namespace", "", "monthly", true},
+		{"access", "", "monthly", false},
+		{"access", "upsert", "", true},
+		{"access", "unknown", "", true},
+		{"access", "", "daily", true},
+		{"Namespace", "Append", "Monthly", false},
+		{"ACCESS", "UPSERT", "", true},
+	}
+
+	for i, testCase := range testCases {
+		args := PostgreSQLArgs{
+			Enable:           true,
+			Format:           testCase.format,
+			ConnectionString: "sslmode=disable",
+			Table:            "events",
+			Mode:             testCase.mode,
+			Partition:        testCase.partition,
+		}
+		err := args.Validate()
+		if testCase.expectErr && err == nil {
+			t.Errorf("test %v: expected error, got nil", i+1)
+		}
+		if !testCase.expectErr && err != nil {
+			t.Errorf("test %v: unexpected error %v", i+1, err)
+		}
+	}
+}
+
+// TestPostgreSQLNamespaceRemoval checks that both deletions and lifecycle
+// expirations remove the key in upsert mode and are appended with a NULL
+// value in append mode.
+func TestPostgreSQLNamespaceRemoval(t *testing.T) {
+	db, err := sql.Open("recording", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+
+	upsertTarget := &PostgreSQLTarget{
+		args: PostgreSQLArgs{Format: event.NamespaceFormat, Table: "events", Mode: PostgreSQLModeUpsert},
+		db:   db,
+	}
+	if upsertTarget.updateStmt, err = db.Prepare(fmt.Sprintf(psqlUpdateRow, "events")); err != nil {
+		t.Fatal(err)
+	}
+	if upsertTarget.deleteStmt, err = db.Prepare(fmt.Sprintf(psqlDeleteRow, "events")); err != nil {
+		t.Fatal(err)
+	}
+
+	appendTarget := &PostgreSQLTarget{
+		args: PostgreSQLArgs{Format: event.NamespaceFormat, Table: "events", Mode: PostgreSQLModeAppend},
+		db:   db,
+	}
+	if appendTarget.insertStmt, err = db.Prepare(fmt.Sprintf(psqlInsertNamespaceRow, "events")); err != nil {
+		t.Fatal(err)
+	}
+
+	testCases := []struct {
+		target        *PostgreSQLTarget
+		name          event.Name
+		expectedQuery string
+		expectedNull  bool
+	}{
+		{upsertTarget, event.ObjectCreatedPut, fmt.Sprintf(psqlUpdateRow, "events"), false},
+		{upsertTarget, event.ObjectRemovedDelete, fmt.Sprintf(psqlDeleteRow, "events"), false},
+		{upsertTarget, event.ObjectRemovedLifecycleExpiration, fmt.Sprintf(psqlDeleteRow, "events"), false},
+		{appendTarget, event.ObjectCreatedPut, fmt.Sprintf(psqlInsertNamespaceRow, "events"), false},
+		{appendTarget, event.ObjectRemovedDelete, fmt.Sprintf(psqlInsertNamespaceRow, "events"), true},
+		{appendTarget, event.ObjectRemovedLifecycleExpiration, fmt.Sprintf(psqlInsertNamespaceRow, "events"), true},
+	}
+
+	for i, testCase := range testCases {
+		testRecordingDriver.reset()
+		if err = testCase.target.send(newTestRemovalEvent(testCase.name)); err != nil {
+			t.Fatalf("test %v: unexpected error %v", i+1, err)
+		}
+		execs := testRecordingDriver.recorded()
+		if len(execs) != 1 || execs[0].query != testCase.expectedQuery {
+			t.Fatalf("test %v: expected %v, got %v", i+1, testCase.expectedQuery, execs)
+		}
+		if testCase.target == appendTarget {
+			data, _ := execs[0].args[2].([]byte)
+			isNull := data == nil
+			if isNull != testCase.expectedNull {
+				t.Errorf("test %v: expected NULL value %v, got %v", i+1, testCase.expectedNull, execs[0].args[2])
+			}
+		}
+	}
+}
@@ -142,7 +142,7 @@ func (target *RedisTarget) send(eventData event.Event) error {
 		}
 		key := eventData.S3.Bucket.Name + "/" + objectName
 
-		if eventData.EventName == event.ObjectRemovedDelete {
+		if eventData.EventName.IsRemoval() {
 			_, err = conn.Do("HDEL", target.args.Key, key)
 		} else {
 			var data []byte
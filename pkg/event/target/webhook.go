@@ -169,6 +169,20 @@ func (target *WebhookTarget) Send(eventKey string) error {
 	return target.store.Del(eventKey)
 }
 
+// Ping - checks whether the webhook endpoint is reachable.
+func (target *WebhookTarget) Ping() error {
+	if err := target.args.Endpoint.DialHTTP(); err != nil {
+		if urlErr, ok := err.(*url.Error); ok {
+			// To treat "connection refused" errors as errNotConnected.
+			if IsConnRefusedErr(urlErr.Err) {
+				return errNotConnected
+			}
+		}
+		return err
+	}
+	return nil
+}
+
 // Close - does nothing and available for interface compatibility.
 func (target *WebhookTarget) Close() error {
 	return nil
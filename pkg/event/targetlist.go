@@ -29,6 +29,12 @@ type Target interface {
 	Close() error
 }
 
+// Pinger - is implemented by targets which can check whether they are
+// reachable without sending an event.
+type Pinger interface {
+	Ping() error
+}
+
 // TargetList - holds list of targets indexed by target ID.
 type TargetList struct {
 	sync.RWMutex
@@ -115,6 +121,19 @@ func (list *TargetList) List() []TargetID {
 	return keys
 }
 
+// Targets - returns available targets.
+func (list *TargetList) Targets() []Target {
+	list.RLock()
+	defer list.RUnlock()
+
+	targets := []Target{}
+	for _, target := range list.targets {
+		targets = append(targets, target)
+	}
+
+	return targets
+}
+
 // Send - sends events to targets identified by target IDs.
 func (list *TargetList) Send(event Event, targetIDs ...TargetID) <-chan TargetIDErr {
 	errCh := make(chan TargetIDErr)
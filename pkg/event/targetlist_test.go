@@ -235,6 +235,22 @@ func TestTargetListList(t *testing.T) {
 	}
 }
 
+func TestTargetListTargets(t *testing.T) {
+	targetList := NewTargetList()
+	if targets := targetList.Targets(); len(targets) != 0 {
+		t.Fatalf("expected: no targets, got: %v", targets)
+	}
+
+	target := &ExampleTarget{TargetID{"1", "webhook"}, false, false}
+	if err := targetList.Add(target); err != nil {
+		panic(err)
+	}
+	targets := targetList.Targets()
+	if len(targets) != 1 || targets[0].ID() != target.ID() {
+		t.Fatalf("expected: %v, got: %v", []Target{target}, targets)
+	}
+}
+
 func TestTargetListSend(t *testing.T) {
 	targetListCase1 := NewTargetList()
 
@@ -82,6 +82,27 @@ const (
 	// GetBucketLifecycleAction - GetBucketLifecycle Rest API action.
 	GetBucketLifecycleAction = "s3:GetBucketLifecycle"
 
+	// PutBucketLoggingAction - PutBucketLogging Rest API action.
+	PutBucketLoggingAction = "s3:PutBucketLogging"
+
+	// GetBucketLoggingAction - GetBucketLogging Rest API action.
+	GetBucketLoggingAction = "s3:GetBucketLogging"
+
+	// PutBucketCORSAction - PutBucketCors Rest API action.
+	PutBucketCORSAction = "s3:PutBucketCORS"
+
+	// GetBucketCORSAction - GetBucketCors Rest API action.
+	GetBucketCORSAction = "s3:GetBucketCORS"
+
+	// PutBucketWebsiteAction - PutBucketWebsite Rest API action.
+	PutBucketWebsiteAction = "s3:PutBucketWebsite"
+
+	// GetBucketWebsiteAction - GetBucketWebsite Rest API action.
+	GetBucketWebsiteAction = "s3:GetBucketWebsite"
+
+	// DeleteBucketWebsiteAction - DeleteBucketWebsite Rest API action.
+	DeleteBucketWebsiteAction = "s3:DeleteBucketWebsite"
+
 	// PutBucketNotificationAction - PutObjectNotification Rest API action.
 	PutBucketNotificationAction = "s3:PutBucketNotification"
 
@@ -118,6 +139,13 @@ var supportedActions = map[Action]struct{}{
 	PutObjectAction:                  {},
 	GetBucketLifecycleAction:         {},
 	PutBucketLifecycleAction:         {},
+	GetBucketLoggingAction:           {},
+	PutBucketLoggingAction:           {},
+	GetBucketCORSAction:              {},
+	PutBucketCORSAction:              {},
+	GetBucketWebsiteAction:           {},
+	PutBucketWebsiteAction:           {},
+	DeleteBucketWebsiteAction:        {},
 }
 
 // isObjectAction - returns whether action is object type or not.
@@ -139,8 +167,11 @@ func (action Action) Match(a Action) bool {
 
 // IsValid - checks if action is valid or not.
 func (action Action) IsValid() bool {
-	_, ok := supportedActions[action]
-	return ok
+	if _, ok := supportedActions[action]; ok {
+		return true
+	}
+
+	return action.isAdminAction()
 }
 
 // MarshalJSON - encodes Action to JSON data.
@@ -231,6 +262,7 @@ var actionConditionKeyMap = map[Action]condition.KeySet{
 			condition.S3XAmzCopySource,
 			condition.S3XAmzServerSideEncryption,
 			condition.S3XAmzServerSideEncryptionCustomerAlgorithm,
+			condition.S3XAmzServerSideEncryptionAwsKmsKeyID,
 			condition.S3XAmzMetadataDirective,
 			condition.S3XAmzStorageClass,
 		}, condition.CommonKeys...)...),
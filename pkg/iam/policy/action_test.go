@@ -50,6 +50,9 @@ func TestActionIsValid(t *testing.T) {
 		expectedResult bool
 	}{
 		{AbortMultipartUploadAction, true},
+		{ServerInfoAdminAction, true},
+		{AllAdminActions, true},
+		{Action("admin:foo"), false},
 		{Action("foo"), false},
 	}
 
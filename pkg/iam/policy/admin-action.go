@@ -0,0 +1,62 @@
+/*
+ * MinIO Cloud Storage, (C) 2020 MinIO, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package iampolicy
+
+import (
+	"github.com/minio/minio/pkg/policy/condition"
+)
+
+// Admin actions delegate administrative capabilities of the server to
+// users other than the owner. Statements of admin actions apply to the
+// whole server and do not take resources.
+const (
+	// ServerInfoAdminAction - allow listing server info.
+	ServerInfoAdminAction Action = "admin:ServerInfo"
+
+	// StorageInfoAdminAction - allow listing storage usage info.
+	StorageInfoAdminAction = "admin:StorageInfo"
+
+	// CreateUserAdminAction - allow creating users and generating
+	// their credentials.
+	CreateUserAdminAction = "admin:CreateUser"
+
+	// AllAdminActions - all admin actions
+	AllAdminActions = "admin:*"
+)
+
+// List of all supported admin actions.
+var supportedAdminActions = map[Action]struct{}{
+	AllAdminActions:        {},
+	ServerInfoAdminAction:  {},
+	StorageInfoAdminAction: {},
+	CreateUserAdminAction:  {},
+}
+
+// isAdminAction - returns whether action is an admin action or not.
+func (action Action) isAdminAction() bool {
+	_, ok := supportedAdminActions[action]
+	return ok
+}
+
+// adminActionConditionKeyMap - holds mapping of supported condition key for an admin action.
+var adminActionConditionKeyMap = func() map[Action]condition.KeySet {
+	m := make(map[Action]condition.KeySet, len(supportedAdminActions))
+	for action := range supportedAdminActions {
+		m[action] = condition.NewKeySet(condition.CommonKeys...)
+	}
+	return m
+}()
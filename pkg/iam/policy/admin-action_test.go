@@ -0,0 +1,73 @@
+/*
+ * MinIO Cloud Storage, (C) 2020 MinIO, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package iampolicy
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestAdminPolicyIsAllowed(t *testing.T) {
+	p, err := ParseConfig(strings.NewReader(`{
+    "Version": "2012-10-17",
+    "Statement": [
+        {
+            "Effect": "Allow",
+            "Action": ["admin:*"]
+        },
+        {
+            "Effect": "Deny",
+            "Action": ["admin:StorageInfo"]
+        },
+        {
+            "Effect": "Allow",
+            "Action": ["s3:GetObject"],
+            "Resource": ["arn:aws:s3:::mybucket/*"]
+        }
+    ]
+}`))
+	if err != nil {
+		t.Fatalf("unexpected error. %v\n", err)
+	}
+
+	testCases := []struct {
+		action         Action
+		expectedResult bool
+	}{
+		{ServerInfoAdminAction, true},
+		{CreateUserAdminAction, true},
+		{StorageInfoAdminAction, false},
+		{GetObjectAction, false},
+	}
+
+	for i, testCase := range testCases {
+		result := p.IsAllowed(Args{AccountName: "Q3AM3UQ867SPQQA43P2F", Action: testCase.action})
+
+		if result != testCase.expectedResult {
+			t.Fatalf("case %v: expected: %v, got: %v", i+1, testCase.expectedResult, result)
+		}
+	}
+
+	// Admin statements are marshaled without resources.
+	data, err := p.Statements[0].MarshalJSON()
+	if err != nil {
+		t.Fatalf("unexpected error. %v\n", err)
+	}
+	if strings.Contains(string(data), "Resource") {
+		t.Fatalf("unexpected resource in %s", data)
+	}
+}
@@ -47,30 +47,37 @@ type Policy struct {
 
 // IsAllowed - checks given policy args is allowed to continue the Rest API.
 func (iamp Policy) IsAllowed(args Args) bool {
+	allowed, _ := iamp.Evaluate(args)
+	return allowed
+}
+
+// Evaluate - checks given policy args is allowed like IsAllowed, also
+// returns the index of the statement which decided, -1 if none did.
+func (iamp Policy) Evaluate(args Args) (bool, int) {
 	// Check all deny statements. If any one statement denies, return false.
-	for _, statement := range iamp.Statements {
+	for i, statement := range iamp.Statements {
 		if statement.Effect == policy.Deny {
 			if !statement.IsAllowed(args) {
-				return false
+				return false, i
 			}
 		}
 	}
 
 	// For owner, its allowed by default.
 	if args.IsOwner {
-		return true
+		return true, -1
 	}
 
 	// Check all allow statements. If any one statement allows, return true.
-	for _, statement := range iamp.Statements {
+	for i, statement := range iamp.Statements {
 		if statement.Effect == policy.Allow {
 			if statement.IsAllowed(args) {
-				return true
+				return true, i
 			}
 		}
 	}
 
-	return false
+	return false, -1
 }
 
 // IsEmpty - returns whether policy is empty or not.
@@ -98,7 +105,7 @@ func (iamp Policy) isValid() error {
 			}
 
 			resources := iamp.Statements[i].Resources.Intersection(statement.Resources)
-			if len(resources) == 0 {
+			if len(resources) == 0 && !statement.isAdmin() {
 				continue
 			}
 
@@ -178,6 +178,46 @@ func TestPolicyIsAllowed(t *testing.T) {
 	}
 }
 
+func TestPolicyEvaluate(t *testing.T) {
+	p := Policy{
+		Version: DefaultVersion,
+		Statements: []Statement{
+			NewStatement(
+				policy.Allow,
+				NewActionSet(GetObjectAction, PutObjectAction),
+				NewResourceSet(NewResource("mybucket", "*")),
+				condition.NewFunctions(),
+			),
+			NewStatement(
+				policy.Deny,
+				NewActionSet(PutObjectAction),
+				NewResourceSet(NewResource("mybucket", "/private/*")),
+				condition.NewFunctions(),
+			),
+		},
+	}
+
+	testCases := []struct {
+		args              Args
+		expectedResult    bool
+		expectedStatement int
+	}{
+		{Args{Action: GetObjectAction, BucketName: "mybucket", ObjectName: "private/obj"}, true, 0},
+		{Args{Action: PutObjectAction, BucketName: "mybucket", ObjectName: "private/obj"}, false, 1},
+		{Args{Action: PutObjectAction, BucketName: "mybucket", ObjectName: "private/obj", IsOwner: true}, false, 1},
+		{Args{Action: GetObjectAction, BucketName: "yourbucket", ObjectName: "obj"}, false, -1},
+		{Args{Action: GetObjectAction, BucketName: "yourbucket", ObjectName: "obj", IsOwner: true}, true, -1},
+	}
+
+	for i, testCase := range testCases {
+		result, statement := p.Evaluate(testCase.args)
+		if result != testCase.expectedResult || statement != testCase.expectedStatement {
+			t.Errorf("case %v: expected: %v, %v, got: %v, %v\n", i+1,
+				testCase.expectedResult, testCase.expectedStatement, result, statement)
+		}
+	}
+}
+
 func TestPolicyIsEmpty(t *testing.T) {
 	case1Policy := Policy{
 		Version: DefaultVersion,
@@ -30,7 +30,7 @@ type Statement struct {
 	SID        policy.ID           `json:"Sid,omitempty"`
 	Effect     policy.Effect       `json:"Effect"`
 	Actions    ActionSet           `json:"Action"`
-	Resources  ResourceSet         `json:"Resource"`
+	Resources  ResourceSet         `json:"Resource,omitempty"`
 	Conditions condition.Functions `json:"Condition,omitempty"`
 }
 
@@ -41,6 +41,11 @@ func (statement Statement) IsAllowed(args Args) bool {
 			return false
 		}
 
+		// Admin actions apply to the whole server.
+		if statement.isAdmin() {
+			return statement.Conditions.Evaluate(args.ConditionValues)
+		}
+
 		resource := args.BucketName
 		if args.ObjectName != "" {
 			if !strings.HasPrefix(args.ObjectName, "/") {
@@ -62,6 +67,17 @@ func (statement Statement) IsAllowed(args Args) bool {
 	return statement.Effect.IsAllowed(check())
 }
 
+// isAdmin - returns whether statement holds admin actions or not.
+func (statement Statement) isAdmin() bool {
+	for action := range statement.Actions {
+		if !action.isAdminAction() {
+			return false
+		}
+	}
+
+	return len(statement.Actions) > 0
+}
+
 // isValid - checks whether statement is valid or not.
 func (statement Statement) isValid() error {
 	if !statement.Effect.IsValid() {
@@ -72,6 +88,22 @@ func (statement Statement) isValid() error {
 		return fmt.Errorf("Action must not be empty")
 	}
 
+	if statement.isAdmin() {
+		if len(statement.Resources) != 0 {
+			return fmt.Errorf("Resource must be empty for admin actions %v", statement.Actions)
+		}
+
+		for action := range statement.Actions {
+			keys := statement.Conditions.Keys()
+			keyDiff := keys.Difference(adminActionConditionKeyMap[action])
+			if !keyDiff.IsEmpty() {
+				return fmt.Errorf("unsupported condition keys '%v' used for action '%v'", keyDiff, action)
+			}
+		}
+
+		return nil
+	}
+
 	if len(statement.Resources) == 0 {
 		return fmt.Errorf("Resource must not be empty")
 	}
@@ -81,6 +113,10 @@ func (statement Statement) isValid() error {
 	}
 
 	for action := range statement.Actions {
+		if action.isAdminAction() {
+			return fmt.Errorf("admin action %v must not be mixed with other actions", action)
+		}
+
 		if !statement.Resources.objectResourceExists() && !statement.Resources.bucketResourceExists() {
 			return fmt.Errorf("unsupported Resource found %v for action %v", statement.Resources, action)
 		}
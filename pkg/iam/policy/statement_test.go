@@ -233,6 +233,34 @@ func TestStatementIsValid(t *testing.T) {
 			NewResourceSet(NewResource("mybucket", "myobject*")),
 			condition.NewFunctions(func1),
 		), false},
+		// Admin actions without resources.
+		{NewStatement(
+			policy.Allow,
+			NewActionSet(ServerInfoAdminAction, StorageInfoAdminAction),
+			NewResourceSet(),
+			condition.NewFunctions(func1),
+		), false},
+		// Resources error for admin actions.
+		{NewStatement(
+			policy.Allow,
+			NewActionSet(ServerInfoAdminAction),
+			NewResourceSet(NewResource("*", "")),
+			condition.NewFunctions(),
+		), true},
+		// Admin actions mixed with other actions error.
+		{NewStatement(
+			policy.Allow,
+			NewActionSet(ServerInfoAdminAction, GetObjectAction),
+			NewResourceSet(NewResource("*", "")),
+			condition.NewFunctions(),
+		), true},
+		// Unsupported conditions for admin actions.
+		{NewStatement(
+			policy.Allow,
+			NewActionSet(StorageInfoAdminAction),
+			NewResourceSet(),
+			condition.NewFunctions(func2),
+		), true},
 	}
 
 	for i, testCase := range testCases {
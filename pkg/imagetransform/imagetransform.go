@@ -0,0 +1,261 @@
+/*
+ * MinIO Cloud Storage, (C) 2019 MinIO, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+// Package imagetransform resizes, rotates and converts JPEG, PNG and
+// GIF images as per transformation parameters.
+package imagetransform
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"image"
+	"image/draw"
+	"image/gif"
+	"image/jpeg"
+	"image/png"
+	"io"
+	"io/ioutil"
+	"net/url"
+	"strconv"
+)
+
+const (
+	// MaxSourceSize - maximum size of the images which can be transformed.
+	MaxSourceSize = 32 * 1024 * 1024
+
+	// Maximum number of pixels of the images which can be transformed.
+	maxSourcePixels = 50 * 1000 * 1000
+
+	// Maximum width and height of transformed images.
+	maxDimension = 4096
+)
+
+// Query parameters of the transformations.
+const (
+	widthParam  = "width"
+	heightParam = "height"
+	rotateParam = "rotate"
+	formatParam = "format"
+)
+
+var (
+	errInvalidDimension = fmt.Errorf("Image width and height should be between 1 and %d", maxDimension)
+	errInvalidRotation  = errors.New("Image rotation should be 90, 180 or 270 degrees")
+	errInvalidFormat    = errors.New("Image format should be jpeg, png or gif")
+	errSourceTooLarge   = errors.New("Image is too large to be transformed")
+)
+
+// contentTypes maps the supported image formats to their content type.
+var contentTypes = map[string]string{
+	"jpeg": "image/jpeg",
+	"png":  "image/png",
+	"gif":  "image/gif",
+}
+
+// Params - transformation parameters of an image, the image is resized
+// to fit in Width x Height keeping its aspect ratio, rotated clockwise
+// by Rotate degrees and encoded in Format. Zero values keep the
+// original size, orientation and format.
+type Params struct {
+	Width  int
+	Height int
+	Rotate int
+	Format string
+}
+
+// IsSet - returns whether any transformation is requested.
+func (p Params) IsSet() bool {
+	return p != Params{}
+}
+
+// String - returns the canonical form of the parameters, identical
+// transformations have the same canonical form.
+func (p Params) String() string {
+	return fmt.Sprintf("width=%d,height=%d,rotate=%d,format=%s", p.Width, p.Height, p.Rotate, p.Format)
+}
+
+// ParseParams - parses the transformation parameters of the query.
+func ParseParams(query url.Values) (p Params, err error) {
+	parseDimension := func(name string) (int, error) {
+		v := query.Get(name)
+		if v == "" {
+			return 0, nil
+		}
+		n, err := strconv.Atoi(v)
+		if err != nil || n < 1 || n > maxDimension {
+			return 0, errInvalidDimension
+		}
+		return n, nil
+	}
+	if p.Width, err = parseDimension(widthParam); err != nil {
+		return p, err
+	}
+	if p.Height, err = parseDimension(heightParam); err != nil {
+		return p, err
+	}
+	if v := query.Get(rotateParam); v != "" {
+		switch v {
+		case "0", "90", "180", "270":
+			p.Rotate, _ = strconv.Atoi(v)
+		default:
+			return p, errInvalidRotation
+		}
+	}
+	if p.Format = query.Get(formatParam); p.Format == "jpg" {
+		p.Format = "jpeg"
+	}
+	if _, ok := contentTypes[p.Format]; p.Format != "" && !ok {
+		return p, errInvalidFormat
+	}
+	return p, nil
+}
+
+// IsSupported - returns whether images of the content type can be
+// transformed.
+func IsSupported(contentType string) bool {
+	for _, t := range contentTypes {
+		if t == contentType {
+			return true
+		}
+	}
+	return false
+}
+
+// Transform - transforms the image read from r as per the parameters,
+// returns the encoded image and its content type.
+func Transform(r io.Reader, p Params) ([]byte, string, error) {
+	data, err := ioutil.ReadAll(io.LimitReader(r, MaxSourceSize+1))
+	if err != nil {
+		return nil, "", err
+	}
+	if len(data) > MaxSourceSize {
+		return nil, "", errSourceTooLarge
+	}
+	config, _, err := image.DecodeConfig(bytes.NewReader(data))
+	if err != nil {
+		return nil, "", err
+	}
+	if config.Width*config.Height > maxSourcePixels {
+		return nil, "", errSourceTooLarge
+	}
+	src, format, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		return nil, "", err
+	}
+
+	img := image.NewRGBA(image.Rect(0, 0, src.Bounds().Dx(), src.Bounds().Dy()))
+	draw.Draw(img, img.Bounds(), src, src.Bounds().Min, draw.Src)
+	if width, height := fitDimensions(img.Bounds().Dx(), img.Bounds().Dy(), p.Width, p.Height); width != img.Bounds().Dx() || height != img.Bounds().Dy() {
+		img = resize(img, width, height)
+	}
+	for i := 0; i < p.Rotate/90; i++ {
+		img = rotate90(img)
+	}
+
+	if p.Format != "" {
+		format = p.Format
+	}
+	var buf bytes.Buffer
+	switch format {
+	case "jpeg":
+		err = jpeg.Encode(&buf, img, &jpeg.Options{Quality: jpeg.DefaultQuality})
+	case "png":
+		err = png.Encode(&buf, img)
+	case "gif":
+		err = gif.Encode(&buf, img, nil)
+	default:
+		err = errInvalidFormat
+	}
+	if err != nil {
+		return nil, "", err
+	}
+	return buf.Bytes(), contentTypes[format], nil
+}
+
+// Returns the dimensions of an image of width x height resized to fit
+// in maxWidth x maxHeight keeping its aspect ratio, zero maximums are
+// not constrained.
+func fitDimensions(width, height, maxWidth, maxHeight int) (int, int) {
+	if maxWidth == 0 && maxHeight == 0 {
+		return width, height
+	}
+	scale := 0.0
+	if maxWidth != 0 {
+		scale = float64(maxWidth) / float64(width)
+	}
+	if maxHeight != 0 {
+		if s := float64(maxHeight) / float64(height); scale == 0 || s < scale {
+			scale = s
+		}
+	}
+	width, height = int(float64(width)*scale+0.5), int(float64(height)*scale+0.5)
+	if width < 1 {
+		width = 1
+	}
+	if height < 1 {
+		height = 1
+	}
+	return width, height
+}
+
+// Resizes the image by averaging the source pixels covered by each
+// pixel of the resized image.
+func resize(src *image.RGBA, width, height int) *image.RGBA {
+	dst := image.NewRGBA(image.Rect(0, 0, width, height))
+	sw, sh := src.Bounds().Dx(), src.Bounds().Dy()
+	for y := 0; y < height; y++ {
+		y0, y1 := y*sh/height, (y+1)*sh/height
+		if y1 == y0 {
+			y1 = y0 + 1
+		}
+		for x := 0; x < width; x++ {
+			x0, x1 := x*sw/width, (x+1)*sw/width
+			if x1 == x0 {
+				x1 = x0 + 1
+			}
+			var r, g, b, a, n int
+			for sy := y0; sy < y1; sy++ {
+				for sx := x0; sx < x1; sx++ {
+					i := src.PixOffset(sx, sy)
+					r += int(src.Pix[i])
+					g += int(src.Pix[i+1])
+					b += int(src.Pix[i+2])
+					a += int(src.Pix[i+3])
+					n++
+				}
+			}
+			i := dst.PixOffset(x, y)
+			dst.Pix[i] = uint8(r / n)
+			dst.Pix[i+1] = uint8(g / n)
+			dst.Pix[i+2] = uint8(b / n)
+			dst.Pix[i+3] = uint8(a / n)
+		}
+	}
+	return dst
+}
+
+// Rotates the image clockwise by 90 degrees.
+func rotate90(src *image.RGBA) *image.RGBA {
+	sw, sh := src.Bounds().Dx(), src.Bounds().Dy()
+	dst := image.NewRGBA(image.Rect(0, 0, sh, sw))
+	for y := 0; y < sh; y++ {
+		for x := 0; x < sw; x++ {
+			copy(dst.Pix[dst.PixOffset(sh-1-y, x):], src.Pix[src.PixOffset(x, y):src.PixOffset(x, y)+4])
+		}
+	}
+	return dst
+}
@@ -0,0 +1,117 @@
+/*
+ * MinIO Cloud Storage, (C) 2019 MinIO, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package imagetransform
+
+import (
+	"bytes"
+	"image"
+	"image/color"
+	"image/png"
+	"net/url"
+	"testing"
+)
+
+func TestParseParams(t *testing.T) {
+	testCases := []struct {
+		query     string
+		params    Params
+		expectErr bool
+	}{
+		{"", Params{}, false},
+		{"width=100", Params{Width: 100}, false},
+		{"width=100&height=50&rotate=90&format=jpg", Params{Width: 100, Height: 50, Rotate: 90, Format: "jpeg"}, false},
+		{"width=0", Params{}, true},
+		{"height=5000", Params{}, true},
+		{"width=abc", Params{}, true},
+		{"rotate=45", Params{}, true},
+		{"format=bmp", Params{}, true},
+	}
+	for i, testCase := range testCases {
+		query, err := url.ParseQuery(testCase.query)
+		if err != nil {
+			t.Fatal(err)
+		}
+		params, err := ParseParams(query)
+		if (err != nil) != testCase.expectErr {
+			t.Fatalf("Test %d: expected error %t, got %v", i+1, testCase.expectErr, err)
+		}
+		if err == nil && params != testCase.params {
+			t.Errorf("Test %d: expected %v, got %v", i+1, testCase.params, params)
+		}
+	}
+}
+
+func TestTransform(t *testing.T) {
+	// A 40x20 image, red on the left half and blue on the right half.
+	src := image.NewRGBA(image.Rect(0, 0, 40, 20))
+	for y := 0; y < 20; y++ {
+		for x := 0; x < 40; x++ {
+			c := color.RGBA{R: 255, A: 255}
+			if x >= 20 {
+				c = color.RGBA{B: 255, A: 255}
+			}
+			src.Set(x, y, c)
+		}
+	}
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, src); err != nil {
+		t.Fatal(err)
+	}
+
+	testCases := []struct {
+		params        Params
+		width, height int
+		contentType   string
+	}{
+		{Params{}, 40, 20, "image/png"},
+		{Params{Width: 20}, 20, 10, "image/png"},
+		{Params{Width: 20, Height: 5}, 10, 5, "image/png"},
+		{Params{Rotate: 90}, 20, 40, "image/png"},
+		{Params{Width: 10, Rotate: 270, Format: "jpeg"}, 5, 10, "image/jpeg"},
+		{Params{Format: "gif"}, 40, 20, "image/gif"},
+	}
+	for i, testCase := range testCases {
+		data, contentType, err := Transform(bytes.NewReader(buf.Bytes()), testCase.params)
+		if err != nil {
+			t.Fatalf("Test %d: %v", i+1, err)
+		}
+		if contentType != testCase.contentType {
+			t.Errorf("Test %d: expected content type %s, got %s", i+1, testCase.contentType, contentType)
+		}
+		img, _, err := image.Decode(bytes.NewReader(data))
+		if err != nil {
+			t.Fatalf("Test %d: %v", i+1, err)
+		}
+		if img.Bounds().Dx() != testCase.width || img.Bounds().Dy() != testCase.height {
+			t.Errorf("Test %d: expected %dx%d, got %dx%d", i+1, testCase.width, testCase.height, img.Bounds().Dx(), img.Bounds().Dy())
+		}
+	}
+
+	// Rotating clockwise moves the left half to the top.
+	data, _, err := Transform(bytes.NewReader(buf.Bytes()), Params{Rotate: 90})
+	if err != nil {
+		t.Fatal(err)
+	}
+	img, _, _ := image.Decode(bytes.NewReader(data))
+	if r, _, b, _ := img.At(10, 5).RGBA(); r != 0xffff || b != 0 {
+		t.Errorf("Expected the top of the rotated image to be red")
+	}
+
+	if _, _, err = Transform(bytes.NewReader([]byte("not an image")), Params{Width: 10}); err == nil {
+		t.Errorf("Expected transforming invalid images to fail")
+	}
+}
@@ -0,0 +1,40 @@
+/*
+ * MinIO Cloud Storage, (C) 2020 MinIO, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package lifecycle
+
+import (
+	"encoding/xml"
+	"errors"
+)
+
+var errInvalidDaysAfterInitiation = errors.New("DaysAfterInitiation must be positive integer when used with AbortIncompleteMultipartUpload")
+
+// AbortIncompleteMultipartUpload - an action for lifecycle configuration
+// rule, incomplete multipart uploads are aborted the given number of
+// days after they were initiated.
+type AbortIncompleteMultipartUpload struct {
+	XMLName             xml.Name `xml:"AbortIncompleteMultipartUpload"`
+	DaysAfterInitiation int      `xml:"DaysAfterInitiation"`
+}
+
+// Validate - validates the "AbortIncompleteMultipartUpload" element
+func (a AbortIncompleteMultipartUpload) Validate() error {
+	if a.DaysAfterInitiation <= 0 {
+		return errInvalidDaysAfterInitiation
+	}
+	return nil
+}
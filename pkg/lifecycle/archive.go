@@ -0,0 +1,44 @@
+/*
+ * MinIO Cloud Storage, (C) 2020 MinIO, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package lifecycle
+
+import (
+	"encoding/xml"
+	"errors"
+)
+
+var (
+	errArchiveToNoBucket          = errors.New("Bucket must be set when used with ArchiveTo")
+	errArchiveToWithoutExpiration = errors.New("ArchiveTo must be used with an expiration action")
+)
+
+// ArchiveTo - an action for lifecycle configuration rule, expired
+// objects are moved to the archive bucket instead of being deleted,
+// compressed if Compress is set.
+type ArchiveTo struct {
+	XMLName  xml.Name `xml:"ArchiveTo"`
+	Bucket   string   `xml:"Bucket"`
+	Compress bool     `xml:"Compress,omitempty"`
+}
+
+// Validate - validates the "ArchiveTo" element
+func (a ArchiveTo) Validate() error {
+	if a.Bucket == "" {
+		return errArchiveToNoBucket
+	}
+	return nil
+}
@@ -39,6 +39,9 @@ const (
 	NoneAction Action = iota
 	// DeleteAction means the object needs to be removed after evaluting lifecycle rules
 	DeleteAction
+	// ArchiveAction means the object needs to be moved to the archive bucket
+	// of its rule after evaluting lifecycle rules
+	ArchiveAction
 )
 
 // Lifecycle - Configuration for bucket lifecycle.
@@ -111,6 +114,35 @@ func (lc Lifecycle) FilterRuleActions(objName string) (Expiration, Transition) {
 	return Expiration{}, Transition{}
 }
 
+// AbortIncompleteMultipartUploadDays returns the number of days after
+// which the incomplete multipart uploads of the object name are aborted,
+// zero if they are not.
+func (lc Lifecycle) AbortIncompleteMultipartUploadDays(objName string) int {
+	for _, rule := range lc.Rules {
+		if strings.ToLower(rule.Status) != "enabled" || rule.AbortIncompleteMultipartUpload == nil {
+			continue
+		}
+		if strings.HasPrefix(objName, rule.Filter.Prefix) {
+			return rule.AbortIncompleteMultipartUpload.DaysAfterInitiation
+		}
+	}
+	return 0
+}
+
+// ArchiveTarget returns the archive bucket expired objects of the object
+// name are moved to, nil if they are deleted.
+func (lc Lifecycle) ArchiveTarget(objName string) *ArchiveTo {
+	for _, rule := range lc.Rules {
+		if strings.ToLower(rule.Status) != "enabled" {
+			continue
+		}
+		if strings.HasPrefix(objName, rule.Filter.Prefix) {
+			return rule.ArchiveTo
+		}
+	}
+	return nil
+}
+
 // ComputeAction returns the action to perform by evaluating all lifecycle rules
 // against the object name and its modification time.
 func (lc Lifecycle) ComputeAction(objName string, modTime time.Time) Action {
@@ -126,5 +158,8 @@ func (lc Lifecycle) ComputeAction(objName string, modTime time.Time) Action {
 			action = DeleteAction
 		}
 	}
+	if action == DeleteAction && lc.ArchiveTarget(objName) != nil {
+		action = ArchiveAction
+	}
 	return action
 }
@@ -213,6 +213,20 @@ func TestComputeActions(t *testing.T) {
 			objectModTime:  time.Now().UTC().Add(-24 * time.Hour), // Created 1 day ago
 			expectedAction: DeleteAction,
 		},
+		// Too early to archive
+		{
+			inputConfig:    `<LifecycleConfiguration><Rule><Filter><Prefix>foodir/</Prefix></Filter><Status>Enabled</Status><Expiration><Days>5</Days></Expiration><ArchiveTo><Bucket>archive</Bucket></ArchiveTo></Rule></LifecycleConfiguration>`,
+			objectName:     "foodir/fooobject",
+			objectModTime:  time.Now().UTC().Add(-4 * 24 * time.Hour), // Created 4 days ago
+			expectedAction: NoneAction,
+		},
+		// Should archive instead of remove
+		{
+			inputConfig:    `<LifecycleConfiguration><Rule><Filter><Prefix>foodir/</Prefix></Filter><Status>Enabled</Status><Expiration><Days>5</Days></Expiration><ArchiveTo><Bucket>archive</Bucket></ArchiveTo></Rule></LifecycleConfiguration>`,
+			objectName:     "foodir/fooobject",
+			objectModTime:  time.Now().UTC().Add(-6 * 24 * time.Hour), // Created 6 days ago
+			expectedAction: ArchiveAction,
+		},
 	}
 
 	for i, tc := range testCases {
@@ -228,3 +242,33 @@ func TestComputeActions(t *testing.T) {
 
 	}
 }
+
+func TestAbortIncompleteMultipartUploadDays(t *testing.T) {
+	inputConfig := `<LifecycleConfiguration><Rule><Filter><Prefix>foodir/</Prefix></Filter><Status>Enabled</Status><AbortIncompleteMultipartUpload><DaysAfterInitiation>7</DaysAfterInitiation></AbortIncompleteMultipartUpload></Rule>` +
+		`<Rule><Filter><Prefix>bardir/</Prefix></Filter><Status>Enabled</Status><Expiration><Days>5</Days></Expiration></Rule>` +
+		`<Rule><Filter><Prefix>bazdir/</Prefix></Filter><Status>Disabled</Status><AbortIncompleteMultipartUpload><DaysAfterInitiation>1</DaysAfterInitiation></AbortIncompleteMultipartUpload></Rule></LifecycleConfiguration>`
+	lc, err := ParseLifecycleConfig(bytes.NewReader([]byte(inputConfig)))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	testCases := []struct {
+		objectName   string
+		expectedDays int
+	}{
+		{"foodir/fooobject", 7},
+		{"bardir/fooobject", 0},
+		{"bazdir/fooobject", 0},
+		{"fooobject", 0},
+	}
+	for i, tc := range testCases {
+		if days := lc.AbortIncompleteMultipartUploadDays(tc.objectName); days != tc.expectedDays {
+			t.Errorf("Test %d: expected %d days, got %d", i+1, tc.expectedDays, days)
+		}
+	}
+
+	// Rules only aborting uploads do not expire objects.
+	if action := lc.ComputeAction("foodir/fooobject", time.Now().UTC().Add(-10*24*time.Hour)); action != NoneAction {
+		t.Errorf("Expected no action, got %v", action)
+	}
+}
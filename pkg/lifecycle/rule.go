@@ -29,9 +29,12 @@ type Rule struct {
 	Filter     Filter     `xml:"Filter"`
 	Expiration Expiration `xml:"Expiration,omitempty"`
 	Transition Transition `xml:"Transition,omitempty"`
-	// FIXME: add a type to catch unsupported AbortIncompleteMultipartUpload AbortIncompleteMultipartUpload `xml:"AbortIncompleteMultipartUpload,omitempty"`
-	NoncurrentVersionExpiration NoncurrentVersionExpiration `xml:"NoncurrentVersionExpiration,omitempty"`
-	NoncurrentVersionTransition NoncurrentVersionTransition `xml:"NoncurrentVersionTransition,omitempty"`
+	// Nil unless the rule aborts incomplete multipart uploads.
+	AbortIncompleteMultipartUpload *AbortIncompleteMultipartUpload `xml:"AbortIncompleteMultipartUpload,omitempty"`
+	NoncurrentVersionExpiration    NoncurrentVersionExpiration     `xml:"NoncurrentVersionExpiration,omitempty"`
+	NoncurrentVersionTransition    NoncurrentVersionTransition     `xml:"NoncurrentVersionTransition,omitempty"`
+	// Nil unless expired objects are moved to an archive bucket.
+	ArchiveTo *ArchiveTo `xml:"ArchiveTo,omitempty"`
 }
 
 var (
@@ -65,6 +68,17 @@ func (r Rule) validateStatus() error {
 }
 
 func (r Rule) validateAction() error {
+	if r.ArchiveTo != nil {
+		if err := r.ArchiveTo.Validate(); err != nil {
+			return err
+		}
+		if r.Expiration == (Expiration{}) {
+			return errArchiveToWithoutExpiration
+		}
+	}
+	if r.AbortIncompleteMultipartUpload != nil {
+		return r.AbortIncompleteMultipartUpload.Validate()
+	}
 	if r.Expiration == (Expiration{}) {
 		return errMissingExpirationAction
 	}
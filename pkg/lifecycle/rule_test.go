@@ -94,6 +94,39 @@ func TestInvalidRules(t *testing.T) {
 	                    </Rule>`,
 			expectedErr: errInvalidRuleStatus,
 		},
+		{ // Rule aborting incomplete multipart uploads without days
+			inputXML: ` <Rule>
+                              <Status>Enabled</Status>
+                              <AbortIncompleteMultipartUpload>
+                                <DaysAfterInitiation>0</DaysAfterInitiation>
+                              </AbortIncompleteMultipartUpload>
+	                    </Rule>`,
+			expectedErr: errInvalidDaysAfterInitiation,
+		},
+		{ // Rule only aborting incomplete multipart uploads
+			inputXML: ` <Rule>
+                              <Status>Enabled</Status>
+                              <AbortIncompleteMultipartUpload>
+                                <DaysAfterInitiation>7</DaysAfterInitiation>
+                              </AbortIncompleteMultipartUpload>
+	                    </Rule>`,
+			expectedErr: nil,
+		},
+		{ // Rule archiving objects without a bucket
+			inputXML: ` <Rule>
+                              <Status>Enabled</Status>
+                              <Expiration><Days>5</Days></Expiration>
+                              <ArchiveTo></ArchiveTo>
+	                    </Rule>`,
+			expectedErr: errArchiveToNoBucket,
+		},
+		{ // Rule archiving objects without expiration action
+			inputXML: ` <Rule>
+                              <Status>Enabled</Status>
+                              <ArchiveTo><Bucket>archive</Bucket></ArchiveTo>
+	                    </Rule>`,
+			expectedErr: errArchiveToWithoutExpiration,
+		},
 	}
 
 	for i, tc := range invalidTestCases {
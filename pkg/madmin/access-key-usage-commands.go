@@ -0,0 +1,66 @@
+/*
+ * MinIO Cloud Storage, (C) 2020 MinIO, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package madmin
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"time"
+)
+
+// Types of access keys.
+const (
+	AccessKeyRoot      = "root"
+	AccessKeyUser      = "user"
+	AccessKeyTemporary = "temporary"
+)
+
+// AccessKeyUsage holds the number of authenticated requests made with
+// an access key since the servers started.
+type AccessKeyUsage struct {
+	AccessKey string    `json:"accessKey"`
+	Type      string    `json:"type"`               // One of root, user or temporary.
+	Requests  uint64    `json:"requests"`           // Number of authenticated requests.
+	LastUsed  time.Time `json:"lastUsed,omitempty"` // Time of the last request, zero if unused.
+}
+
+// AccessKeyUsage - returns the usage of the root credentials, of all
+// IAM users and of the temporary credentials used since the servers
+// started, the least recently used access keys first.
+func (adm *AdminClient) AccessKeyUsage() ([]AccessKeyUsage, error) {
+	// Execute GET on /minio/admin/v1/access-key-usage
+	resp, err := adm.executeMethod("GET",
+		requestData{relPath: "/v1/access-key-usage"})
+	defer closeResponse(resp)
+	if err != nil {
+		return nil, err
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, httpRespToErrorResponse(resp)
+	}
+
+	response, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	var usages []AccessKeyUsage
+	err = json.Unmarshal(response, &usages)
+	return usages, err
+}
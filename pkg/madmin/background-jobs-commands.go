@@ -0,0 +1,114 @@
+/*
+ * MinIO Cloud Storage, (C) 2020 MinIO, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package madmin
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"time"
+)
+
+// Actions on background jobs.
+const (
+	// BackgroundJobPause stops running the job on its schedule.
+	BackgroundJobPause = "pause"
+	// BackgroundJobResume runs the job on its schedule again.
+	BackgroundJobResume = "resume"
+	// BackgroundJobRunNow runs the job once right away, even if it
+	// is paused.
+	BackgroundJobRunNow = "run"
+)
+
+// BackgroundJobStatus holds the schedule and the last run of a
+// background job on the server running it.
+type BackgroundJobStatus struct {
+	Name         string        `json:"name"`
+	Node         string        `json:"node"`
+	Interval     time.Duration `json:"interval"`
+	Paused       bool          `json:"paused"`
+	Running      bool          `json:"running"`
+	Runs         int64         `json:"runs"`
+	LastRun      time.Time     `json:"lastRun,omitempty"`
+	LastDuration time.Duration `json:"lastDuration,omitempty"`
+	LastError    string        `json:"lastError,omitempty"`
+	NextRun      time.Time     `json:"nextRun,omitempty"`
+}
+
+// BackgroundJobsStatus - returns the schedule and the last run of the
+// background jobs of all the servers.
+func (adm *AdminClient) BackgroundJobsStatus() ([]BackgroundJobStatus, error) {
+	// Execute GET on /minio/admin/v1/background-jobs
+	resp, err := adm.executeMethod("GET",
+		requestData{relPath: "/v1/background-jobs"})
+	defer closeResponse(resp)
+	if err != nil {
+		return nil, err
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, httpRespToErrorResponse(resp)
+	}
+
+	response, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	var statuses []BackgroundJobStatus
+	err = json.Unmarshal(response, &statuses)
+	return statuses, err
+}
+
+// PauseBackgroundJob - stops running the background job with the given
+// name on its schedule on all the servers, a running job is completed.
+func (adm *AdminClient) PauseBackgroundJob(name string) error {
+	return adm.backgroundJobAction(name, BackgroundJobPause)
+}
+
+// ResumeBackgroundJob - runs the paused background job with the given
+// name on its schedule again on all the servers.
+func (adm *AdminClient) ResumeBackgroundJob(name string) error {
+	return adm.backgroundJobAction(name, BackgroundJobResume)
+}
+
+// RunBackgroundJob - runs the background job with the given name once
+// right away on all the servers.
+func (adm *AdminClient) RunBackgroundJob(name string) error {
+	return adm.backgroundJobAction(name, BackgroundJobRunNow)
+}
+
+func (adm *AdminClient) backgroundJobAction(name, action string) error {
+	queryValues := url.Values{}
+	queryValues.Set("name", name)
+	queryValues.Set("action", action)
+
+	// Execute POST on /minio/admin/v1/background-jobs?name=name&action=action
+	resp, err := adm.executeMethod("POST",
+		requestData{relPath: "/v1/background-jobs", queryValues: queryValues})
+	defer closeResponse(resp)
+	if err != nil {
+		return err
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return httpRespToErrorResponse(resp)
+	}
+
+	return nil
+}
@@ -0,0 +1,77 @@
+/*
+ * MinIO Cloud Storage, (C) 2019 MinIO, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package madmin
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+)
+
+// BandwidthLimits holds the bandwidth limits in bytes per second for
+// background data transfers, zero means unlimited.
+type BandwidthLimits struct {
+	Global  int64            `json:"global"`
+	Buckets map[string]int64 `json:"buckets,omitempty"`
+}
+
+// GetBandwidthLimits - returns the bandwidth limits for background
+// data transfers.
+func (adm *AdminClient) GetBandwidthLimits() (limits BandwidthLimits, err error) {
+	// Execute GET on /minio/admin/v1/bandwidth
+	resp, err := adm.executeMethod("GET",
+		requestData{relPath: "/v1/bandwidth"})
+	defer closeResponse(resp)
+	if err != nil {
+		return limits, err
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return limits, httpRespToErrorResponse(resp)
+	}
+
+	response, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return limits, err
+	}
+
+	err = json.Unmarshal(response, &limits)
+	return limits, err
+}
+
+// SetBandwidthLimits - sets the bandwidth limits for background data
+// transfers on all the servers.
+func (adm *AdminClient) SetBandwidthLimits(limits BandwidthLimits) error {
+	data, err := json.Marshal(limits)
+	if err != nil {
+		return err
+	}
+
+	// Execute PUT on /minio/admin/v1/bandwidth
+	resp, err := adm.executeMethod("PUT",
+		requestData{relPath: "/v1/bandwidth", content: data})
+	defer closeResponse(resp)
+	if err != nil {
+		return err
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return httpRespToErrorResponse(resp)
+	}
+
+	return nil
+}
@@ -0,0 +1,378 @@
+/*
+ * MinIO Cloud Storage, (C) 2019 MinIO, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package madmin
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"time"
+)
+
+// BatchUpdateJob describes a metadata update applied to all objects
+// under a prefix of a bucket.
+type BatchUpdateJob struct {
+	Bucket string `json:"bucket"`
+	Prefix string `json:"prefix"`
+	// Metadata to set on every object, e.g. "Cache-Control" or
+	// "X-Amz-Meta-Owner", existing metadata is kept.
+	Metadata map[string]string `json:"metadata"`
+	// RateLimit is the maximum number of objects updated per second,
+	// zero means unlimited.
+	RateLimit int `json:"rateLimit,omitempty"`
+}
+
+// BatchCopyPrefixJob describes a server-side copy of all objects under
+// a prefix of a bucket to a prefix of a target bucket.
+type BatchCopyPrefixJob struct {
+	Bucket string `json:"bucket"`
+	Prefix string `json:"prefix"`
+	// Objects are copied to TargetPrefix followed by their name
+	// without Prefix.
+	TargetBucket string `json:"targetBucket"`
+	TargetPrefix string `json:"targetPrefix"`
+	// PreserveMetadata copies the user metadata of the objects, only
+	// their content type is copied otherwise.
+	PreserveMetadata bool `json:"preserveMetadata,omitempty"`
+	// PreserveEncryption re-encrypts SSE-S3 objects at the target,
+	// encrypted objects are not copied otherwise.
+	PreserveEncryption bool `json:"preserveEncryption,omitempty"`
+	// RateLimit is the maximum number of objects copied per second,
+	// zero means unlimited.
+	RateLimit int `json:"rateLimit,omitempty"`
+}
+
+// BatchEncryptJob describes the SSE-S3 encryption of all plaintext
+// objects under a prefix of a bucket.
+type BatchEncryptJob struct {
+	Bucket string `json:"bucket"`
+	Prefix string `json:"prefix"`
+	// RateLimit is the maximum number of objects encrypted per second,
+	// zero means unlimited.
+	RateLimit int `json:"rateLimit,omitempty"`
+}
+
+// Operations of batch operation jobs.
+const (
+	// BatchOperationCopy copies the objects to a target bucket.
+	BatchOperationCopy = "copy"
+	// BatchOperationTag sets tags on the objects.
+	BatchOperationTag = "tag"
+	// BatchOperationDelete deletes the objects.
+	BatchOperationDelete = "delete"
+	// BatchOperationRestore restores the objects from the trash of
+	// their bucket.
+	BatchOperationRestore = "restore"
+)
+
+// BatchOperationZip is the operation of download zip jobs started
+// through the browser, writing the objects to an archive object.
+const BatchOperationZip = "zip"
+
+// BatchOperationEncrypt is the operation of batch encrypt jobs,
+// encrypting plaintext objects with SSE-S3.
+const BatchOperationEncrypt = "encrypt"
+
+// BatchObject is an object of the manifest of a batch operation job.
+type BatchObject struct {
+	Bucket string `json:"bucket"`
+	Object string `json:"object"`
+}
+
+// BatchManifest is a CSV object listing the objects of a batch
+// operation job, one "bucket,object" row per object.
+type BatchManifest struct {
+	Bucket string `json:"bucket"`
+	Object string `json:"object"`
+}
+
+// BatchOperationJob describes an operation applied to a manifest of
+// objects, the objects are split between all the servers.
+type BatchOperationJob struct {
+	Operation string `json:"operation"`
+	// The objects are either listed in Objects or in a manifest
+	// object.
+	Objects  []BatchObject  `json:"objects,omitempty"`
+	Manifest *BatchManifest `json:"manifest,omitempty"`
+	// Target of the copy operation, objects are copied to
+	// TargetPrefix followed by their name.
+	TargetBucket string `json:"targetBucket,omitempty"`
+	TargetPrefix string `json:"targetPrefix,omitempty"`
+	// Tags set by the tag operation.
+	Tags map[string]string `json:"tags,omitempty"`
+	// RateLimit is the maximum number of objects processed per
+	// second by each server, zero means unlimited.
+	RateLimit int `json:"rateLimit,omitempty"`
+	// Workers is the number of objects processed concurrently by
+	// each server, defaults to 4.
+	Workers int `json:"workers,omitempty"`
+}
+
+// BatchJobFailure is an object a batch job failed to process.
+type BatchJobFailure struct {
+	Bucket string `json:"bucket"`
+	Object string `json:"object"`
+	Error  string `json:"error"`
+}
+
+// BatchJobStatus holds the progress of a batch job on the server
+// running it.
+type BatchJobStatus struct {
+	ID        string    `json:"id"`
+	Node      string    `json:"node"`
+	Operation string    `json:"operation,omitempty"`
+	Bucket    string    `json:"bucket"`
+	Prefix    string    `json:"prefix"`
+	Running   bool      `json:"running"`
+	Canceled  bool      `json:"canceled,omitempty"`
+	StartTime time.Time `json:"startTime"`
+	EndTime   time.Time `json:"endTime,omitempty"`
+	Total     int64     `json:"total,omitempty"` // Objects of the manifest.
+	Scanned   int64     `json:"scanned"`         // Objects looked at so far.
+	Updated   int64     `json:"updated"`         // Objects successfully processed.
+	Failed    int64     `json:"failed"`          // Objects which could not be processed.
+	Error     string    `json:"error,omitempty"`
+	// Objects which could not be processed, only the first
+	// failures are kept.
+	Failures []BatchJobFailure `json:"failures,omitempty"`
+}
+
+// BatchJobReport holds the progress of a batch job on all the
+// servers running it.
+type BatchJobReport struct {
+	ID        string            `json:"id"`
+	Operation string            `json:"operation,omitempty"`
+	Running   bool              `json:"running"`
+	Canceled  bool              `json:"canceled,omitempty"`
+	StartTime time.Time         `json:"startTime"`
+	EndTime   time.Time         `json:"endTime,omitempty"`
+	Total     int64             `json:"total,omitempty"`
+	Scanned   int64             `json:"scanned"`
+	Updated   int64             `json:"updated"`
+	Failed    int64             `json:"failed"`
+	Failures  []BatchJobFailure `json:"failures,omitempty"`
+	Nodes     []BatchJobStatus  `json:"nodes"`
+}
+
+// startBatchJobResp is the response of a start batch job request.
+type startBatchJobResp struct {
+	ID string `json:"id"`
+}
+
+// StartBatchUpdateJob - starts a background job which sets the given
+// metadata on all objects under the prefix, returns the job ID.
+func (adm *AdminClient) StartBatchUpdateJob(job BatchUpdateJob) (string, error) {
+	data, err := json.Marshal(job)
+	if err != nil {
+		return "", err
+	}
+
+	// Execute POST on /minio/admin/v1/batch/update
+	resp, err := adm.executeMethod("POST",
+		requestData{relPath: "/v1/batch/update", content: data})
+	defer closeResponse(resp)
+	if err != nil {
+		return "", err
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return "", httpRespToErrorResponse(resp)
+	}
+
+	response, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return "", err
+	}
+
+	var jobResp startBatchJobResp
+	if err = json.Unmarshal(response, &jobResp); err != nil {
+		return "", err
+	}
+	return jobResp.ID, nil
+}
+
+// StartBatchCopyPrefixJob - starts a background job which copies all
+// objects under a prefix to a prefix of a target bucket, returns the
+// job ID.
+func (adm *AdminClient) StartBatchCopyPrefixJob(job BatchCopyPrefixJob) (string, error) {
+	data, err := json.Marshal(job)
+	if err != nil {
+		return "", err
+	}
+
+	// Execute POST on /minio/admin/v1/batch/copyprefix
+	resp, err := adm.executeMethod("POST",
+		requestData{relPath: "/v1/batch/copyprefix", content: data})
+	defer closeResponse(resp)
+	if err != nil {
+		return "", err
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return "", httpRespToErrorResponse(resp)
+	}
+
+	response, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return "", err
+	}
+
+	var jobResp startBatchJobResp
+	if err = json.Unmarshal(response, &jobResp); err != nil {
+		return "", err
+	}
+	return jobResp.ID, nil
+}
+
+// StartBatchOperationJob - starts a background job which applies an
+// operation to a manifest of objects on all the servers, returns the
+// job ID.
+func (adm *AdminClient) StartBatchOperationJob(job BatchOperationJob) (string, error) {
+	data, err := json.Marshal(job)
+	if err != nil {
+		return "", err
+	}
+
+	// Execute POST on /minio/admin/v1/batch/operation
+	resp, err := adm.executeMethod("POST",
+		requestData{relPath: "/v1/batch/operation", content: data})
+	defer closeResponse(resp)
+	if err != nil {
+		return "", err
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return "", httpRespToErrorResponse(resp)
+	}
+
+	response, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return "", err
+	}
+
+	var jobResp startBatchJobResp
+	if err = json.Unmarshal(response, &jobResp); err != nil {
+		return "", err
+	}
+	return jobResp.ID, nil
+}
+
+// StartBatchEncryptJob - starts a background job which encrypts all
+// plaintext objects under the prefix with SSE-S3, returns the job ID.
+func (adm *AdminClient) StartBatchEncryptJob(job BatchEncryptJob) (string, error) {
+	data, err := json.Marshal(job)
+	if err != nil {
+		return "", err
+	}
+
+	// Execute POST on /minio/admin/v1/batch/encrypt
+	resp, err := adm.executeMethod("POST",
+		requestData{relPath: "/v1/batch/encrypt", content: data})
+	defer closeResponse(resp)
+	if err != nil {
+		return "", err
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return "", httpRespToErrorResponse(resp)
+	}
+
+	response, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return "", err
+	}
+
+	var jobResp startBatchJobResp
+	if err = json.Unmarshal(response, &jobResp); err != nil {
+		return "", err
+	}
+	return jobResp.ID, nil
+}
+
+// BatchJobReport - returns the progress of the batch job with the
+// given ID summed over all the servers running it.
+func (adm *AdminClient) BatchJobReport(id string) (report BatchJobReport, err error) {
+	queryValues := url.Values{}
+	queryValues.Set("id", id)
+
+	// Execute GET on /minio/admin/v1/batch/report?id=id
+	resp, err := adm.executeMethod("GET",
+		requestData{relPath: "/v1/batch/report", queryValues: queryValues})
+	defer closeResponse(resp)
+	if err != nil {
+		return report, err
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return report, httpRespToErrorResponse(resp)
+	}
+
+	response, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return report, err
+	}
+
+	err = json.Unmarshal(response, &report)
+	return report, err
+}
+
+// BatchJobsStatus - returns the state of the batch jobs on all the
+// servers.
+func (adm *AdminClient) BatchJobsStatus() ([]BatchJobStatus, error) {
+	// Execute GET on /minio/admin/v1/batch/jobs
+	resp, err := adm.executeMethod("GET",
+		requestData{relPath: "/v1/batch/jobs"})
+	defer closeResponse(resp)
+	if err != nil {
+		return nil, err
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, httpRespToErrorResponse(resp)
+	}
+
+	response, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	var statuses []BatchJobStatus
+	err = json.Unmarshal(response, &statuses)
+	return statuses, err
+}
+
+// CancelBatchJob - stops the running batch job with the given ID,
+// objects already updated keep their new metadata.
+func (adm *AdminClient) CancelBatchJob(id string) error {
+	queryValues := url.Values{}
+	queryValues.Set("id", id)
+
+	// Execute POST on /minio/admin/v1/batch/cancel?id=id
+	resp, err := adm.executeMethod("POST",
+		requestData{relPath: "/v1/batch/cancel", queryValues: queryValues})
+	defer closeResponse(resp)
+	if err != nil {
+		return err
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return httpRespToErrorResponse(resp)
+	}
+
+	return nil
+}
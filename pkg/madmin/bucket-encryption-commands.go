@@ -0,0 +1,71 @@
+/*
+ * MinIO Cloud Storage, (C) 2020 MinIO, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package madmin
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+)
+
+// EncryptionStats holds the number of objects encrypted one way and
+// the bytes they take on the backend.
+type EncryptionStats struct {
+	Objects int64 `json:"objects"`
+	Size    int64 `json:"size"`
+}
+
+// BucketEncryptionReport summarizes the objects under a prefix of a
+// bucket by server-side encryption type.
+type BucketEncryptionReport struct {
+	Bucket    string          `json:"bucket"`
+	Prefix    string          `json:"prefix,omitempty"`
+	Plaintext EncryptionStats `json:"plaintext"`
+	SSES3     EncryptionStats `json:"sseS3"`
+	SSEC      EncryptionStats `json:"sseC"`
+	SSEKMS    EncryptionStats `json:"sseKMS"`
+}
+
+// BucketEncryptionReport - scans the objects under a prefix of a
+// bucket and returns how many of them are plaintext, SSE-S3, SSE-C
+// or SSE-KMS encrypted.
+func (adm *AdminClient) BucketEncryptionReport(bucket, prefix string) (report BucketEncryptionReport, err error) {
+	queryValues := url.Values{}
+	queryValues.Set("bucket", bucket)
+	queryValues.Set("prefix", prefix)
+
+	// Execute GET on /minio/admin/v1/bucket-encryption-report
+	resp, err := adm.executeMethod("GET",
+		requestData{relPath: "/v1/bucket-encryption-report", queryValues: queryValues})
+	defer closeResponse(resp)
+	if err != nil {
+		return report, err
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return report, httpRespToErrorResponse(resp)
+	}
+
+	response, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return report, err
+	}
+
+	err = json.Unmarshal(response, &report)
+	return report, err
+}
@@ -0,0 +1,95 @@
+/*
+ * MinIO Cloud Storage, (C) 2020 MinIO, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package madmin
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+)
+
+// KeyNormalization - the Unicode normalization form object keys of a
+// bucket are converted to, so that clients composing characters
+// differently see the same keys.
+type KeyNormalization string
+
+const (
+	// KeyNormalizationNone - object keys are kept as sent, the default.
+	KeyNormalizationNone KeyNormalization = "none"
+	// KeyNormalizationNFC - object keys are converted to the canonical
+	// composed form, as sent by most Linux and Windows clients.
+	KeyNormalizationNFC KeyNormalization = "NFC"
+	// KeyNormalizationNFD - object keys are converted to the canonical
+	// decomposed form, as sent by some macOS clients.
+	KeyNormalizationNFD KeyNormalization = "NFD"
+)
+
+// IsValid - returns true if the key normalization form is known.
+func (n KeyNormalization) IsValid() bool {
+	switch n {
+	case KeyNormalizationNone, KeyNormalizationNFC, KeyNormalizationNFD:
+		return true
+	}
+	return false
+}
+
+// GetBucketKeyNormalization - returns the key normalization forms of
+// the buckets normalizing their object keys.
+func (adm *AdminClient) GetBucketKeyNormalization() (forms map[string]KeyNormalization, err error) {
+	// Execute GET on /minio/admin/v1/bucket-key-normalization
+	resp, err := adm.executeMethod("GET",
+		requestData{relPath: "/v1/bucket-key-normalization"})
+	defer closeResponse(resp)
+	if err != nil {
+		return nil, err
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, httpRespToErrorResponse(resp)
+	}
+
+	response, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	err = json.Unmarshal(response, &forms)
+	return forms, err
+}
+
+// SetBucketKeyNormalization - sets the key normalization form of a
+// bucket on all the servers.
+func (adm *AdminClient) SetBucketKeyNormalization(bucket string, form KeyNormalization) error {
+	queryValues := url.Values{}
+	queryValues.Set("bucket", bucket)
+	queryValues.Set("form", string(form))
+
+	// Execute PUT on /minio/admin/v1/bucket-key-normalization
+	resp, err := adm.executeMethod("PUT",
+		requestData{relPath: "/v1/bucket-key-normalization", queryValues: queryValues})
+	defer closeResponse(resp)
+	if err != nil {
+		return err
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return httpRespToErrorResponse(resp)
+	}
+
+	return nil
+}
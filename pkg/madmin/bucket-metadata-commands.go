@@ -0,0 +1,97 @@
+/*
+ * MinIO Cloud Storage, (C) 2020 MinIO, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package madmin
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"time"
+)
+
+// BucketMetadataBundle - the configuration of a bucket, portable
+// across buckets and deployments.
+type BucketMetadataBundle struct {
+	Version string `json:"version"`
+	// Bucket the bundle was exported from.
+	Bucket  string    `json:"bucket"`
+	Created time.Time `json:"created"`
+	// Key normalization form of the bucket.
+	KeyNormalization KeyNormalization `json:"keyNormalization,omitempty"`
+	// Content of the bucket metadata files, e.g. policy.json or
+	// lifecycle.xml, by file name.
+	Files map[string]string `json:"files"`
+}
+
+// ExportBucketMetadata - returns the configuration of a bucket.
+func (adm *AdminClient) ExportBucketMetadata(bucket string) (bundle BucketMetadataBundle, err error) {
+	queryValues := url.Values{}
+	queryValues.Set("bucket", bucket)
+
+	// Execute GET on /minio/admin/v1/bucket-metadata/export?bucket=bucket
+	resp, err := adm.executeMethod("GET", requestData{
+		relPath:     "/v1/bucket-metadata/export",
+		queryValues: queryValues,
+	})
+	defer closeResponse(resp)
+	if err != nil {
+		return bundle, err
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return bundle, httpRespToErrorResponse(resp)
+	}
+
+	response, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return bundle, err
+	}
+
+	err = json.Unmarshal(response, &bundle)
+	return bundle, err
+}
+
+// ImportBucketMetadata - applies a configuration returned by
+// ExportBucketMetadata to a bucket, which may be another bucket than
+// the one it was exported from.
+func (adm *AdminClient) ImportBucketMetadata(bucket string, bundle BucketMetadataBundle) error {
+	data, err := json.Marshal(bundle)
+	if err != nil {
+		return err
+	}
+
+	queryValues := url.Values{}
+	queryValues.Set("bucket", bucket)
+
+	// Execute PUT on /minio/admin/v1/bucket-metadata/import?bucket=bucket
+	resp, err := adm.executeMethod("PUT", requestData{
+		relPath:     "/v1/bucket-metadata/import",
+		queryValues: queryValues,
+		content:     data,
+	})
+	defer closeResponse(resp)
+	if err != nil {
+		return err
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return httpRespToErrorResponse(resp)
+	}
+
+	return nil
+}
@@ -0,0 +1,92 @@
+/*
+ * MinIO Cloud Storage, (C) 2020 MinIO, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package madmin
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+)
+
+// BucketMode - the mode of a bucket, the requests which are not allowed
+// by the mode of a bucket are rejected.
+type BucketMode string
+
+const (
+	// BucketModeReadWrite - all requests are allowed, the default.
+	BucketModeReadWrite BucketMode = "read-write"
+	// BucketModeReadOnly - requests writing or deleting objects or
+	// bucket configurations are rejected.
+	BucketModeReadOnly BucketMode = "read-only"
+	// BucketModeSuspended - all requests are rejected.
+	BucketModeSuspended BucketMode = "suspended"
+)
+
+// IsValid - returns true if the bucket mode is known.
+func (m BucketMode) IsValid() bool {
+	switch m {
+	case BucketModeReadWrite, BucketModeReadOnly, BucketModeSuspended:
+		return true
+	}
+	return false
+}
+
+// GetBucketModes - returns the modes of the buckets which are not in
+// read-write mode.
+func (adm *AdminClient) GetBucketModes() (modes map[string]BucketMode, err error) {
+	// Execute GET on /minio/admin/v1/bucket-mode
+	resp, err := adm.executeMethod("GET",
+		requestData{relPath: "/v1/bucket-mode"})
+	defer closeResponse(resp)
+	if err != nil {
+		return nil, err
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, httpRespToErrorResponse(resp)
+	}
+
+	response, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	err = json.Unmarshal(response, &modes)
+	return modes, err
+}
+
+// SetBucketMode - sets the mode of a bucket on all the servers.
+func (adm *AdminClient) SetBucketMode(bucket string, mode BucketMode) error {
+	queryValues := url.Values{}
+	queryValues.Set("bucket", bucket)
+	queryValues.Set("mode", string(mode))
+
+	// Execute PUT on /minio/admin/v1/bucket-mode
+	resp, err := adm.executeMethod("PUT",
+		requestData{relPath: "/v1/bucket-mode", queryValues: queryValues})
+	defer closeResponse(resp)
+	if err != nil {
+		return err
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return httpRespToErrorResponse(resp)
+	}
+
+	return nil
+}
@@ -0,0 +1,127 @@
+/*
+ * MinIO Cloud Storage, (C) 2019 MinIO, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package madmin
+
+import (
+	"encoding/json"
+	"net/http"
+	"time"
+)
+
+// Cache event types.
+const (
+	// CacheEventEvict - an entry was evicted to reclaim cache space.
+	CacheEventEvict = "evict"
+	// CacheEventPurge - an entry was removed as it is no longer valid.
+	CacheEventPurge = "purge"
+)
+
+// CacheEvent describes the removal of an entry from a cache drive,
+// bucket and object are empty for evicted entries as the cache only
+// knows them by the name of their entry.
+type CacheEvent struct {
+	Node   string    `json:"node"`
+	Time   time.Time `json:"time"`
+	Type   string    `json:"type"`
+	Drive  string    `json:"drive"`
+	Entry  string    `json:"entry"`
+	Bucket string    `json:"bucket,omitempty"`
+	Object string    `json:"object,omitempty"`
+	Size   int64     `json:"size"`
+	Reason string    `json:"reason"`
+}
+
+// CacheEventInfo holds a cache event
+type CacheEventInfo struct {
+	Event CacheEvent
+	Err   error `json:"-"`
+}
+
+// CacheEvents - listen on disk cache eviction and purge events of all
+// the servers.
+func (adm AdminClient) CacheEvents(doneCh <-chan struct{}) <-chan CacheEventInfo {
+	eventInfoCh := make(chan CacheEventInfo)
+	// Only success, start a routine to start reading line by line.
+	go func(eventInfoCh chan<- CacheEventInfo) {
+		defer close(eventInfoCh)
+		for {
+			// Execute GET to call cache events handler
+			resp, err := adm.executeMethod("GET", requestData{relPath: "/v1/cache/events"})
+			if err != nil {
+				closeResponse(resp)
+				return
+			}
+
+			if resp.StatusCode != http.StatusOK {
+				eventInfoCh <- CacheEventInfo{Err: httpRespToErrorResponse(resp)}
+				return
+			}
+
+			dec := json.NewDecoder(resp.Body)
+			for {
+				var event CacheEvent
+				if err = dec.Decode(&event); err != nil {
+					break
+				}
+				select {
+				case <-doneCh:
+					return
+				case eventInfoCh <- CacheEventInfo{Event: event}:
+				}
+			}
+		}
+	}(eventInfoCh)
+
+	// Returns the cache event channel, for caller to start reading from.
+	return eventInfoCh
+}
+
+// CacheConfigUpdate - cache drives and exclude patterns to add to or
+// remove from the cache configuration of all the servers.
+type CacheConfigUpdate struct {
+	AddDrives     []string `json:"addDrives,omitempty"`
+	RemoveDrives  []string `json:"removeDrives,omitempty"`
+	AddExclude    []string `json:"addExclude,omitempty"`
+	RemoveExclude []string `json:"removeExclude,omitempty"`
+}
+
+// UpdateCacheConfig - adds and removes cache drives and exclude patterns
+// at runtime, removed drives are drained and no longer used for caching.
+func (adm *AdminClient) UpdateCacheConfig(update CacheConfigUpdate) error {
+	data, err := json.Marshal(update)
+	if err != nil {
+		return err
+	}
+
+	reqData := requestData{
+		relPath: "/v1/cache/config",
+		content: data,
+	}
+
+	// Execute PUT on /minio/admin/v1/cache/config to update cache config.
+	resp, err := adm.executeMethod("PUT", reqData)
+	defer closeResponse(resp)
+	if err != nil {
+		return err
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return httpRespToErrorResponse(resp)
+	}
+
+	return nil
+}
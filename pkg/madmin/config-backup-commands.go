@@ -0,0 +1,83 @@
+/*
+ * MinIO Cloud Storage, (C) 2019 MinIO, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package madmin
+
+import (
+	"encoding/json"
+	"io"
+	"io/ioutil"
+	"net/http"
+)
+
+// ConfigRestoreInfo holds the outcome of a configuration restore.
+type ConfigRestoreInfo struct {
+	Entries        int      `json:"entries"`        // Configuration entries restored.
+	CreatedBuckets []string `json:"createdBuckets"` // Buckets missing from the deployment.
+}
+
+// BackupConfig - returns an archive of the server configuration, IAM
+// users, groups and policies and all bucket metadata. The archive is
+// encrypted with the secret key of the server and is returned as is,
+// it can only be restored into a deployment with the same secret key.
+func (adm *AdminClient) BackupConfig() ([]byte, error) {
+	// Execute GET on /minio/admin/v1/config/backup
+	resp, err := adm.executeMethod("GET", requestData{
+		relPath: "/v1/config/backup",
+	})
+	defer closeResponse(resp)
+	if err != nil {
+		return nil, err
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, httpRespToErrorResponse(resp)
+	}
+
+	return ioutil.ReadAll(resp.Body)
+}
+
+// RestoreConfig - restores an archive returned by BackupConfig,
+// buckets missing from the deployment are created. The servers
+// must be restarted for the restored configuration to take effect.
+func (adm *AdminClient) RestoreConfig(archive io.Reader) (info ConfigRestoreInfo, err error) {
+	data, err := ioutil.ReadAll(archive)
+	if err != nil {
+		return info, err
+	}
+
+	// Execute PUT on /minio/admin/v1/config/restore
+	resp, err := adm.executeMethod("PUT", requestData{
+		relPath: "/v1/config/restore",
+		content: data,
+	})
+	defer closeResponse(resp)
+	if err != nil {
+		return info, err
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return info, httpRespToErrorResponse(resp)
+	}
+
+	response, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return info, err
+	}
+
+	err = json.Unmarshal(response, &info)
+	return info, err
+}
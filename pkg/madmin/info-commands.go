@@ -55,6 +55,18 @@ type StorageInfo struct {
 
 	Total uint64 // Total disk space.
 
+	Objects       uint64 // Total number of objects, as counted by the usage crawler.
+	ObjectsSize   uint64 // Estimated total logical size of all objects.
+	AvgObjectSize uint64 // Average object size.
+
+	// Content addressed deduplication statistics, this is
+	// only meaningful if FS dedup mode is enabled.
+	Dedup struct {
+		Blobs      uint64 // Unique data blobs stored.
+		References uint64 // Objects referencing a stored blob.
+		SavedBytes uint64 // Bytes saved by sharing blobs.
+	}
+
 	// Backend type.
 	Backend struct {
 		// Represents various backend types, currently on FS and Erasure.
@@ -70,6 +82,10 @@ type StorageInfo struct {
 
 		// List of all disk status, this is only meaningful if BackendType is Erasure.
 		Sets [][]DriveInfo
+
+		// Estimated number of object copies pending heal per erasure set,
+		// this is only meaningful if BackendType is Erasure.
+		HealBacklog []uint64
 	}
 }
 
@@ -82,6 +98,30 @@ type ServerProperties struct {
 	DeploymentID string        `json:"deploymentID"`
 	Region       string        `json:"region"`
 	SQSARN       []string      `json:"sqsARN"`
+
+	// Backend is only set for gateways with the circuit breaker enabled.
+	Backend *CircuitBreakerInfo `json:"backend,omitempty"`
+
+	// ReadReplicas is only set for gateways with read replicas.
+	ReadReplicas []ReadReplicaInfo `json:"readReplicas,omitempty"`
+}
+
+// CircuitBreakerInfo holds the state of a gateway backend circuit
+// breaker, State is one of "closed", "open" or "half-open".
+type CircuitBreakerInfo struct {
+	State    string    `json:"state"`
+	Failures int       `json:"failures"`
+	OpenedAt time.Time `json:"openedAt,omitempty"`
+	RetryAt  time.Time `json:"retryAt,omitempty"`
+}
+
+// ReadReplicaInfo holds the state of a read replica of a gateway
+// backend, offline replicas do not serve reads.
+type ReadReplicaInfo struct {
+	Endpoint  string    `json:"endpoint"`
+	Online    bool      `json:"online"`
+	LastCheck time.Time `json:"lastCheck,omitempty"`
+	LastError string    `json:"lastError,omitempty"`
 }
 
 // ServerConnStats holds network information
@@ -97,6 +137,17 @@ type ServerHTTPMethodStats struct {
 	AvgDuration string `json:"avgDuration"`
 }
 
+// ServerHTTPAPIStats holds the number of requests to an S3 API, the
+// requests in flight and their latency percentiles.
+type ServerHTTPAPIStats struct {
+	Count       uint64 `json:"count"`
+	InFlight    int64  `json:"inFlight"`
+	AvgDuration string `json:"avgDuration"`
+	P50Duration string `json:"p50Duration"`
+	P95Duration string `json:"p95Duration"`
+	P99Duration string `json:"p99Duration"`
+}
+
 // ServerHTTPStats holds all type of http operations performed to/from the server
 // including their average execution time.
 type ServerHTTPStats struct {
@@ -110,6 +161,8 @@ type ServerHTTPStats struct {
 	SuccessPOSTStats   ServerHTTPMethodStats `json:"successPOSTs"`
 	TotalDELETEStats   ServerHTTPMethodStats `json:"totalDELETEs"`
 	SuccessDELETEStats ServerHTTPMethodStats `json:"successDELETEs"`
+	// Stats of S3 API requests keyed by API name.
+	APIStats map[string]ServerHTTPAPIStats `json:"apiStats,omitempty"`
 }
 
 // ServerInfoData holds storage, connections and other
@@ -201,6 +254,50 @@ func (adm *AdminClient) ServerDrivesPerfInfo() ([]ServerDrivesPerfInfo, error) {
 	return info, nil
 }
 
+// ServerDriveLatencyInfo holds information about address and the read
+// and write latency histograms of all drives in a single server node
+type ServerDriveLatencyInfo struct {
+	Addr    string         `json:"addr"`
+	Error   string         `json:"error,omitempty"`
+	Latency []disk.Latency `json:"latency"`
+}
+
+// ServerDriveLatencyInfo - Returns the read and write latency histograms
+// of the drives of all servers, covering the last disk.LatencyWindow
+func (adm *AdminClient) ServerDriveLatencyInfo() ([]ServerDriveLatencyInfo, error) {
+	v := url.Values{}
+	v.Set("perfType", string("drivelatency"))
+	resp, err := adm.executeMethod("GET", requestData{
+		relPath:     "/v1/performance",
+		queryValues: v,
+	})
+
+	defer closeResponse(resp)
+	if err != nil {
+		return nil, err
+	}
+
+	// Check response http status code
+	if resp.StatusCode != http.StatusOK {
+		return nil, httpRespToErrorResponse(resp)
+	}
+
+	// Unmarshal the server's json response
+	var info []ServerDriveLatencyInfo
+
+	respBytes, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	err = json.Unmarshal(respBytes, &info)
+	if err != nil {
+		return nil, err
+	}
+
+	return info, nil
+}
+
 // ServerCPULoadInfo holds information about address and cpu load of
 // a single server node
 type ServerCPULoadInfo struct {
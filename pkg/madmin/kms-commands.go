@@ -0,0 +1,110 @@
+/*
+ * MinIO Cloud Storage, (C) 2019 MinIO, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package madmin
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"time"
+)
+
+// KMSKeySweepBucket holds the re-encryption progress of a bucket.
+type KMSKeySweepBucket struct {
+	Bucket  string `json:"bucket"`
+	Scanned int64  `json:"scanned"` // Objects looked at so far.
+	Rotated int64  `json:"rotated"` // SSE-S3 objects whose keys were re-wrapped.
+	Failed  int64  `json:"failed"`  // SSE-S3 objects which could not be re-wrapped.
+}
+
+// KMSKeySweepStatus holds the state of the re-encryption sweep on a
+// server, a server which never ran a sweep reports a zero start time.
+type KMSKeySweepStatus struct {
+	Node      string              `json:"node"`
+	Running   bool                `json:"running"`
+	StartTime time.Time           `json:"startTime"`
+	EndTime   time.Time           `json:"endTime,omitempty"`
+	Error     string              `json:"error,omitempty"`
+	Buckets   []KMSKeySweepBucket `json:"buckets,omitempty"`
+}
+
+// RotateKMSKey - rotates the master key with the given ID at the KMS,
+// the default SSE-S3 master key is rotated if keyID is empty. Object
+// keys stay sealed with the previous key version until re-wrapped by
+// StartKMSKeySweep.
+func (adm *AdminClient) RotateKMSKey(keyID string) error {
+	queryValues := url.Values{}
+	queryValues.Set("key-id", keyID)
+
+	// Execute POST on /minio/admin/v1/kms/key/rotate?key-id=keyID
+	resp, err := adm.executeMethod("POST",
+		requestData{relPath: "/v1/kms/key/rotate", queryValues: queryValues})
+	defer closeResponse(resp)
+	if err != nil {
+		return err
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return httpRespToErrorResponse(resp)
+	}
+
+	return nil
+}
+
+// StartKMSKeySweep - starts a background sweep which re-wraps the
+// keys of all SSE-S3 encrypted objects with the current master key.
+func (adm *AdminClient) StartKMSKeySweep() error {
+	// Execute POST on /minio/admin/v1/kms/key/sweep
+	resp, err := adm.executeMethod("POST",
+		requestData{relPath: "/v1/kms/key/sweep"})
+	defer closeResponse(resp)
+	if err != nil {
+		return err
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return httpRespToErrorResponse(resp)
+	}
+
+	return nil
+}
+
+// KMSKeySweepStatus - returns the state of the re-encryption sweep on
+// all the servers.
+func (adm *AdminClient) KMSKeySweepStatus() ([]KMSKeySweepStatus, error) {
+	// Execute GET on /minio/admin/v1/kms/key/sweep/status
+	resp, err := adm.executeMethod("GET",
+		requestData{relPath: "/v1/kms/key/sweep/status"})
+	defer closeResponse(resp)
+	if err != nil {
+		return nil, err
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, httpRespToErrorResponse(resp)
+	}
+
+	response, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	var status []KMSKeySweepStatus
+	err = json.Unmarshal(response, &status)
+	return status, err
+}
@@ -0,0 +1,68 @@
+/*
+ * MinIO Cloud Storage, (C) 2020 MinIO, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package madmin
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"time"
+)
+
+// States of the servers of a distributed setup.
+const (
+	// The last call to the server succeeded.
+	PeerAlive = "alive"
+	// The last call to the server failed, but it was heard of
+	// recently, directly or through other servers.
+	PeerSuspect = "suspect"
+	// The server was not heard of for a while, calls to it fail fast.
+	PeerDead = "dead"
+)
+
+// PeerMember holds the liveness of a server of a distributed setup.
+type PeerMember struct {
+	Host      string    `json:"host"`
+	State     string    `json:"state"`
+	LastSeen  time.Time `json:"lastSeen"`
+	LastError string    `json:"lastError,omitempty"`
+}
+
+// Membership - returns the liveness of all servers of a distributed
+// setup, as seen by the server answering the request.
+func (adm *AdminClient) Membership() ([]PeerMember, error) {
+	// Execute GET on /minio/admin/v1/membership
+	resp, err := adm.executeMethod("GET",
+		requestData{relPath: "/v1/membership"})
+	defer closeResponse(resp)
+	if err != nil {
+		return nil, err
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, httpRespToErrorResponse(resp)
+	}
+
+	response, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	var members []PeerMember
+	err = json.Unmarshal(response, &members)
+	return members, err
+}
@@ -0,0 +1,81 @@
+/*
+ * MinIO Cloud Storage, (C) 2019 MinIO, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package madmin
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"time"
+)
+
+// TargetStatus is the outcome of the last health check of a
+// notification target.
+type TargetStatus string
+
+// Statuses of notification targets
+const (
+	TargetStatusUp   TargetStatus = "up"
+	TargetStatusDown TargetStatus = "down"
+	// The target does not support health checks.
+	TargetStatusUnknown TargetStatus = "unknown"
+)
+
+// TargetHealth holds the health of a notification target, as seen by
+// one server.
+type TargetHealth struct {
+	ARN           string       `json:"arn"`
+	Status        TargetStatus `json:"status"`
+	LastCheck     time.Time    `json:"lastCheck"`
+	LastError     string       `json:"lastError,omitempty"`
+	LastErrorTime time.Time    `json:"lastErrorTime,omitempty"`
+}
+
+// ServerTargetsHealth holds the health of the notification targets of
+// a single server node. It also reports any errors if encountered while
+// trying to reach this server.
+type ServerTargetsHealth struct {
+	Addr    string         `json:"addr"`
+	Error   string         `json:"error,omitempty"`
+	Targets []TargetHealth `json:"targets"`
+}
+
+// TargetsHealth - returns the health of the notification targets as
+// last checked by each server.
+func (adm *AdminClient) TargetsHealth() ([]ServerTargetsHealth, error) {
+	// Execute GET on /minio/admin/v1/notification/health
+	resp, err := adm.executeMethod("GET", requestData{
+		relPath: "/v1/notification/health",
+	})
+	defer closeResponse(resp)
+	if err != nil {
+		return nil, err
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, httpRespToErrorResponse(resp)
+	}
+
+	respBytes, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	var info []ServerTargetsHealth
+	err = json.Unmarshal(respBytes, &info)
+	return info, err
+}
@@ -0,0 +1,105 @@
+/*
+ * MinIO Cloud Storage, (C) 2020 MinIO, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package madmin
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+)
+
+// Kinds of orphaned files.
+const (
+	// OrphanTmp is a temporary file left behind by an interrupted
+	// request.
+	OrphanTmp = "tmp"
+	// OrphanUpload is a file of a multipart upload whose metadata
+	// is missing.
+	OrphanUpload = "upload"
+	// OrphanPart is a part of a multipart upload which is not
+	// referenced by the metadata of the upload.
+	OrphanPart = "part"
+	// OrphanConfig is a configuration file of a removed bucket.
+	OrphanConfig = "config"
+)
+
+// OrphanEntry is a file of the .minio.sys volume of a disk which is
+// not referenced by any object, upload or bucket.
+type OrphanEntry struct {
+	Kind    string    `json:"kind"`
+	Path    string    `json:"path"`
+	Size    int64     `json:"size"`
+	ModTime time.Time `json:"modTime"`
+	Removed bool      `json:"removed,omitempty"`
+	Error   string    `json:"error,omitempty"`
+}
+
+// OrphanDiskResult holds the orphaned files found on a disk.
+type OrphanDiskResult struct {
+	Node    string        `json:"node"`
+	Disk    string        `json:"disk"`
+	Entries []OrphanEntry `json:"entries,omitempty"`
+	Size    int64         `json:"size"`
+	// Truncated is set when the inspection stopped at the maximum
+	// number of entries, it can be run again to find the others.
+	Truncated bool   `json:"truncated,omitempty"`
+	Error     string `json:"error,omitempty"`
+}
+
+// InspectOrphans - finds the orphaned files of all the disks of all the
+// servers which were not modified since olderThan, zero means the
+// server default. The files are removed if remove is set. The result
+// of each disk is sent on the returned channel as soon as the disk is
+// inspected, the channel is closed when all disks are done.
+func (adm *AdminClient) InspectOrphans(remove bool, olderThan time.Duration) (<-chan OrphanDiskResult, error) {
+	queryValues := url.Values{}
+	queryValues.Set("remove", strconv.FormatBool(remove))
+	if olderThan != 0 {
+		queryValues.Set("olderThan", olderThan.String())
+	}
+
+	// Execute GET on /minio/admin/v1/orphans
+	resp, err := adm.executeMethod("GET",
+		requestData{relPath: "/v1/orphans", queryValues: queryValues})
+	if err != nil {
+		closeResponse(resp)
+		return nil, err
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		defer closeResponse(resp)
+		return nil, httpRespToErrorResponse(resp)
+	}
+
+	resultCh := make(chan OrphanDiskResult)
+	go func() {
+		defer close(resultCh)
+		defer closeResponse(resp)
+
+		dec := json.NewDecoder(resp.Body)
+		for {
+			var result OrphanDiskResult
+			if err := dec.Decode(&result); err != nil {
+				return
+			}
+			resultCh <- result
+		}
+	}()
+	return resultCh, nil
+}
@@ -134,3 +134,71 @@ func (adm *AdminClient) SetPolicy(policyName, entityName string, isGroup bool) e
 	}
 	return nil
 }
+
+// Sources of policy simulation decisions.
+const (
+	// PolicySourceOwner - the owner is allowed all actions.
+	PolicySourceOwner = "owner"
+	// PolicySourceIAM - decided by the policies of the user and of
+	// the groups the user is a member of.
+	PolicySourceIAM = "iam"
+	// PolicySourceBucket - decided by the bucket policy, which applies
+	// to anonymous requests.
+	PolicySourceBucket = "bucket"
+	// PolicySourceOPA - decided by the Open Policy Agent.
+	PolicySourceOPA = "opa"
+)
+
+// PolicySimulation describes a request to evaluate against the current
+// policies.
+type PolicySimulation struct {
+	// User is the access key of the request, empty for anonymous
+	// requests.
+	User   string `json:"user"`
+	Action string `json:"action"`
+	Bucket string `json:"bucket"`
+	Object string `json:"object,omitempty"`
+	// Conditions are the condition values of the request, such as
+	// "SourceIp" or "SecureTransport", they override the defaults.
+	Conditions map[string][]string `json:"conditions,omitempty"`
+}
+
+// PolicySimulationResult tells whether a simulated request is allowed
+// and why.
+type PolicySimulationResult struct {
+	Allowed bool   `json:"allowed"`
+	Source  string `json:"source"`
+	// Policy is the name of the policy holding Statement, it is
+	// empty for bucket policies.
+	Policy string `json:"policy,omitempty"`
+	// Statement is the statement which allowed or denied the request,
+	// it is empty if no statement applies.
+	Statement json.RawMessage `json:"statement,omitempty"`
+	Reason    string          `json:"reason"`
+}
+
+// SimulatePolicy - evaluates whether the request described by sim would
+// be allowed by the current IAM and bucket policies, and explains which
+// policy statement decided.
+func (adm *AdminClient) SimulatePolicy(sim PolicySimulation) (PolicySimulationResult, error) {
+	data, err := json.Marshal(sim)
+	if err != nil {
+		return PolicySimulationResult{}, err
+	}
+
+	// Execute POST on /minio/admin/v1/simulate-policy
+	resp, err := adm.executeMethod("POST",
+		requestData{relPath: "/v1/simulate-policy", content: data})
+	defer closeResponse(resp)
+	if err != nil {
+		return PolicySimulationResult{}, err
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return PolicySimulationResult{}, httpRespToErrorResponse(resp)
+	}
+
+	var result PolicySimulationResult
+	err = json.NewDecoder(resp.Body).Decode(&result)
+	return result, err
+}
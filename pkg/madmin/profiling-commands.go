@@ -103,3 +103,31 @@ func (adm *AdminClient) DownloadProfilingData() (io.ReadCloser, error) {
 
 	return resp.Body, nil
 }
+
+// Diagnostics makes an admin call to download goroutine, heap and thread
+// creation snapshots of the given node in a zip format, of the node
+// serving the call if node is empty.
+func (adm *AdminClient) Diagnostics(node string) (io.ReadCloser, error) {
+	v := url.Values{}
+	if node != "" {
+		v.Set("node", node)
+	}
+	resp, err := adm.executeMethod("GET", requestData{
+		relPath:     "/v1/diagnostics",
+		queryValues: v,
+	})
+	if err != nil {
+		closeResponse(resp)
+		return nil, err
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, httpRespToErrorResponse(resp)
+	}
+
+	if resp.Body == nil {
+		return nil, errors.New("body is nil")
+	}
+
+	return resp.Body, nil
+}
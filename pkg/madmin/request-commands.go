@@ -0,0 +1,84 @@
+/*
+ * MinIO Cloud Storage, (C) 2019 MinIO, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package madmin
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"time"
+)
+
+// RequestEntry holds information about an in-flight S3 API call.
+type RequestEntry struct {
+	ID         string        `json:"id"`         // Unique ID of the request, used to cancel it.
+	RequestID  string        `json:"requestid"`  // Same as x-amz-request-id, not necessarily unique.
+	API        string        `json:"api"`        // S3 API name, empty until the request is routed.
+	Bucket     string        `json:"bucket"`     // Bucket name, if any.
+	Object     string        `json:"object"`     // Object name, if any.
+	RemoteHost string        `json:"remotehost"` // Client address.
+	Node       string        `json:"node"`       // Server serving the request.
+	StartTime  time.Time     `json:"time"`       // Time when the request was received.
+	Duration   time.Duration `json:"duration"`   // Time elapsed since the request was received.
+}
+
+// ListRequests - returns all in-flight S3 API calls in a minio setup,
+// the longest running first.
+func (adm *AdminClient) ListRequests() ([]RequestEntry, error) {
+	// Execute GET on /minio/admin/v1/top/requests
+	resp, err := adm.executeMethod("GET",
+		requestData{relPath: "/v1/top/requests"})
+	defer closeResponse(resp)
+	if err != nil {
+		return nil, err
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, httpRespToErrorResponse(resp)
+	}
+
+	response, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	var requests []RequestEntry
+	err = json.Unmarshal(response, &requests)
+	return requests, err
+}
+
+// CancelRequest - cancels the in-flight S3 API call with the given
+// ID, as returned by ListRequests, on whichever server is serving it.
+func (adm *AdminClient) CancelRequest(id string) error {
+	queryValues := url.Values{}
+	queryValues.Set("id", id)
+
+	// Execute POST on /minio/admin/v1/cancel-request?id=id
+	resp, err := adm.executeMethod("POST",
+		requestData{relPath: "/v1/cancel-request", queryValues: queryValues})
+	defer closeResponse(resp)
+	if err != nil {
+		return err
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return httpRespToErrorResponse(resp)
+	}
+
+	return nil
+}
@@ -0,0 +1,213 @@
+/*
+ * MinIO Cloud Storage, (C) 2019 MinIO, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package madmin
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"time"
+)
+
+// BucketSnapshotTarget holds the bucket snapshots are written to, a
+// bucket of the same deployment unless Endpoint is set.
+type BucketSnapshotTarget struct {
+	Endpoint  string `json:"endpoint,omitempty"`
+	AccessKey string `json:"accessKey,omitempty"`
+	SecretKey string `json:"secretKey,omitempty"`
+	Secure    bool   `json:"secure,omitempty"`
+	Bucket    string `json:"bucket"`
+	Prefix    string `json:"prefix,omitempty"`
+}
+
+// BucketSnapshotConfig holds the schedule and the target of the
+// snapshots of a bucket.
+type BucketSnapshotConfig struct {
+	// Five field cron expression, evaluated in UTC.
+	Schedule string `json:"schedule"`
+	// Only copy objects changed since the latest snapshot.
+	Incremental bool                 `json:"incremental,omitempty"`
+	Target      BucketSnapshotTarget `json:"target"`
+}
+
+// BucketSnapshot describes a snapshot of a bucket, a snapshot still
+// being taken reports a zero end time.
+type BucketSnapshot struct {
+	Name        string    `json:"name"`
+	Bucket      string    `json:"bucket"`
+	StartTime   time.Time `json:"startTime"`
+	EndTime     time.Time `json:"endTime,omitempty"`
+	Incremental bool      `json:"incremental,omitempty"`
+	Objects     int64     `json:"objects"` // Objects in the snapshot.
+	Copied      int64     `json:"copied"`  // Objects copied by the snapshot.
+	Size        int64     `json:"size"`    // Bytes copied by the snapshot.
+	Skipped     int64     `json:"skipped"` // Encrypted objects left out.
+	Error       string    `json:"error,omitempty"`
+}
+
+// BucketSnapshotRestore holds the outcome of a snapshot restore.
+type BucketSnapshotRestore struct {
+	Objects int64 `json:"objects"`
+	Size    int64 `json:"size"`
+}
+
+// SetBucketSnapshotConfig - sets the snapshot schedule and target of
+// bucket.
+func (adm *AdminClient) SetBucketSnapshotConfig(bucket string, config BucketSnapshotConfig) error {
+	data, err := json.Marshal(config)
+	if err != nil {
+		return err
+	}
+	econfigBytes, err := EncryptData(adm.secretAccessKey, data)
+	if err != nil {
+		return err
+	}
+
+	queryValues := url.Values{}
+	queryValues.Set("bucket", bucket)
+
+	// Execute PUT on /minio/admin/v1/snapshot/config?bucket=bucket
+	resp, err := adm.executeMethod("PUT", requestData{
+		relPath:     "/v1/snapshot/config",
+		queryValues: queryValues,
+		content:     econfigBytes,
+	})
+	defer closeResponse(resp)
+	if err != nil {
+		return err
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return httpRespToErrorResponse(resp)
+	}
+
+	return nil
+}
+
+// GetBucketSnapshotConfig - returns the snapshot schedule and target
+// of bucket.
+func (adm *AdminClient) GetBucketSnapshotConfig(bucket string) (config BucketSnapshotConfig, err error) {
+	queryValues := url.Values{}
+	queryValues.Set("bucket", bucket)
+
+	// Execute GET on /minio/admin/v1/snapshot/config?bucket=bucket
+	resp, err := adm.executeMethod("GET", requestData{
+		relPath:     "/v1/snapshot/config",
+		queryValues: queryValues,
+	})
+	defer closeResponse(resp)
+	if err != nil {
+		return config, err
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return config, httpRespToErrorResponse(resp)
+	}
+
+	data, err := DecryptData(adm.secretAccessKey, resp.Body)
+	if err != nil {
+		return config, err
+	}
+
+	err = json.Unmarshal(data, &config)
+	return config, err
+}
+
+// RemoveBucketSnapshotConfig - stops taking snapshots of bucket, the
+// snapshots taken so far are kept.
+func (adm *AdminClient) RemoveBucketSnapshotConfig(bucket string) error {
+	queryValues := url.Values{}
+	queryValues.Set("bucket", bucket)
+
+	// Execute DELETE on /minio/admin/v1/snapshot/config?bucket=bucket
+	resp, err := adm.executeMethod("DELETE", requestData{
+		relPath:     "/v1/snapshot/config",
+		queryValues: queryValues,
+	})
+	defer closeResponse(resp)
+	if err != nil {
+		return err
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return httpRespToErrorResponse(resp)
+	}
+
+	return nil
+}
+
+// ListBucketSnapshots - returns the snapshots of bucket, oldest first.
+func (adm *AdminClient) ListBucketSnapshots(bucket string) ([]BucketSnapshot, error) {
+	queryValues := url.Values{}
+	queryValues.Set("bucket", bucket)
+
+	// Execute GET on /minio/admin/v1/snapshot/list?bucket=bucket
+	resp, err := adm.executeMethod("GET", requestData{
+		relPath:     "/v1/snapshot/list",
+		queryValues: queryValues,
+	})
+	defer closeResponse(resp)
+	if err != nil {
+		return nil, err
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, httpRespToErrorResponse(resp)
+	}
+
+	response, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	var snapshots []BucketSnapshot
+	err = json.Unmarshal(response, &snapshots)
+	return snapshots, err
+}
+
+// RestoreBucketSnapshot - copies all objects of a snapshot of bucket
+// to targetBucket, which must exist. Returns once all objects are
+// restored.
+func (adm *AdminClient) RestoreBucketSnapshot(bucket, snapshot, targetBucket string) (restore BucketSnapshotRestore, err error) {
+	queryValues := url.Values{}
+	queryValues.Set("bucket", bucket)
+	queryValues.Set("snapshot", snapshot)
+	queryValues.Set("targetBucket", targetBucket)
+
+	// Execute POST on /minio/admin/v1/snapshot/restore?bucket=bucket&snapshot=snapshot&targetBucket=targetBucket
+	resp, err := adm.executeMethod("POST", requestData{
+		relPath:     "/v1/snapshot/restore",
+		queryValues: queryValues,
+	})
+	defer closeResponse(resp)
+	if err != nil {
+		return restore, err
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return restore, httpRespToErrorResponse(resp)
+	}
+
+	response, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return restore, err
+	}
+
+	err = json.Unmarshal(response, &restore)
+	return restore, err
+}
@@ -89,6 +89,27 @@ const (
 
 	// GetBucketLifecycleAction - GetBucketLifecycle Rest API action.
 	GetBucketLifecycleAction = "s3:GetBucketLifecycle"
+
+	// PutBucketLoggingAction - PutBucketLogging Rest API action.
+	PutBucketLoggingAction = "s3:PutBucketLogging"
+
+	// GetBucketLoggingAction - GetBucketLogging Rest API action.
+	GetBucketLoggingAction = "s3:GetBucketLogging"
+
+	// PutBucketCORSAction - PutBucketCors Rest API action.
+	PutBucketCORSAction = "s3:PutBucketCORS"
+
+	// GetBucketCORSAction - GetBucketCors Rest API action.
+	GetBucketCORSAction = "s3:GetBucketCORS"
+
+	// PutBucketWebsiteAction - PutBucketWebsite Rest API action.
+	PutBucketWebsiteAction = "s3:PutBucketWebsite"
+
+	// GetBucketWebsiteAction - GetBucketWebsite Rest API action.
+	GetBucketWebsiteAction = "s3:GetBucketWebsite"
+
+	// DeleteBucketWebsiteAction - DeleteBucketWebsite Rest API action.
+	DeleteBucketWebsiteAction = "s3:DeleteBucketWebsite"
 )
 
 // isObjectAction - returns whether action is object type or not.
@@ -121,6 +142,12 @@ func (action Action) IsValid() bool {
 	case PutBucketPolicyAction, PutObjectAction:
 		fallthrough
 	case PutBucketLifecycleAction, GetBucketLifecycleAction:
+		fallthrough
+	case PutBucketLoggingAction, GetBucketLoggingAction:
+		fallthrough
+	case PutBucketCORSAction, GetBucketCORSAction:
+		fallthrough
+	case PutBucketWebsiteAction, GetBucketWebsiteAction, DeleteBucketWebsiteAction:
 		return true
 	}
 
@@ -201,6 +228,7 @@ var actionConditionKeyMap = map[Action]condition.KeySet{
 			condition.S3XAmzCopySource,
 			condition.S3XAmzServerSideEncryption,
 			condition.S3XAmzServerSideEncryptionCustomerAlgorithm,
+			condition.S3XAmzServerSideEncryptionAwsKmsKeyID,
 			condition.S3XAmzMetadataDirective,
 			condition.S3XAmzStorageClass,
 		}, condition.CommonKeys...)...),
@@ -0,0 +1,146 @@
+/*
+ * MinIO Cloud Storage, (C) 2020 MinIO, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package condition
+
+import (
+	"fmt"
+	"net/http"
+	"reflect"
+	"time"
+)
+
+// dateFunc - Date condition functions. They compare the date of Key
+// with a date in the ISO 8601 format, such as 2020-12-31T00:00:00Z.
+// https://docs.aws.amazon.com/IAM/latest/UserGuide/reference_policies_elements_condition_operators.html#Conditions_Date
+type dateFunc struct {
+	n     name
+	k     Key
+	value time.Time
+}
+
+// evaluate() - evaluates to check whether the date of Key in given
+// values compares with the date of the condition as per its name.
+func (f dateFunc) evaluate(values map[string][]string) bool {
+	requestValue, ok := values[http.CanonicalHeaderKey(f.k.Name())]
+	if !ok {
+		requestValue = values[f.k.Name()]
+	}
+	if len(requestValue) == 0 {
+		return false
+	}
+
+	t, err := time.Parse(time.RFC3339, requestValue[0])
+	if err != nil {
+		return false
+	}
+
+	switch f.n {
+	case dateEquals:
+		return t.Equal(f.value)
+	case dateNotEquals:
+		return !t.Equal(f.value)
+	case dateLessThan:
+		return t.Before(f.value)
+	case dateLessThanEquals:
+		return !t.After(f.value)
+	case dateGreaterThan:
+		return t.After(f.value)
+	case dateGreaterThanEquals:
+		return !t.Before(f.value)
+	}
+	return false
+}
+
+// key() - returns condition key which is used by this condition function.
+func (f dateFunc) key() Key {
+	return f.k
+}
+
+// name() - returns the date condition name of this function.
+func (f dateFunc) name() name {
+	return f.n
+}
+
+func (f dateFunc) String() string {
+	return fmt.Sprintf("%v:%v:%v", f.n, f.k, f.value.Format(time.RFC3339))
+}
+
+// toMap - returns map representation of this function.
+func (f dateFunc) toMap() map[Key]ValueSet {
+	if !f.k.IsValid() {
+		return nil
+	}
+
+	return map[Key]ValueSet{
+		f.k: NewValueSet(NewStringValue(f.value.Format(time.RFC3339))),
+	}
+}
+
+func newDateFunc(n name, key Key, values ValueSet) (Function, error) {
+	if key != AWSCurrentTime {
+		return nil, fmt.Errorf("only %v key is allowed for %v condition", AWSCurrentTime, n)
+	}
+
+	if len(values) != 1 {
+		return nil, fmt.Errorf("only one value is allowed for %v condition", n)
+	}
+
+	var value time.Time
+	for v := range values {
+		if v.GetType() != reflect.String {
+			return nil, fmt.Errorf("value must be a date string for %v condition", n)
+		}
+		s, err := v.GetString()
+		if err != nil {
+			return nil, err
+		}
+		if value, err = time.Parse(time.RFC3339, s); err != nil {
+			return nil, fmt.Errorf("value %v must be a date in ISO 8601 format for %v condition", s, n)
+		}
+	}
+
+	return &dateFunc{n, key, value}, nil
+}
+
+func newDateEqualsFunc(key Key, values ValueSet) (Function, error) {
+	return newDateFunc(dateEquals, key, values)
+}
+
+func newDateNotEqualsFunc(key Key, values ValueSet) (Function, error) {
+	return newDateFunc(dateNotEquals, key, values)
+}
+
+func newDateLessThanFunc(key Key, values ValueSet) (Function, error) {
+	return newDateFunc(dateLessThan, key, values)
+}
+
+func newDateLessThanEqualsFunc(key Key, values ValueSet) (Function, error) {
+	return newDateFunc(dateLessThanEquals, key, values)
+}
+
+func newDateGreaterThanFunc(key Key, values ValueSet) (Function, error) {
+	return newDateFunc(dateGreaterThan, key, values)
+}
+
+func newDateGreaterThanEqualsFunc(key Key, values ValueSet) (Function, error) {
+	return newDateFunc(dateGreaterThanEquals, key, values)
+}
+
+// NewDateLessThanFunc - returns new DateLessThan function.
+func NewDateLessThanFunc(key Key, value time.Time) (Function, error) {
+	return &dateFunc{dateLessThan, key, value}, nil
+}
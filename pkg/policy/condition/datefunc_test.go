@@ -0,0 +1,93 @@
+/*
+ * MinIO Cloud Storage, (C) 2020 MinIO, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package condition
+
+import (
+	"testing"
+)
+
+func TestDateFuncEvaluate(t *testing.T) {
+	newFunc := func(fn func(Key, ValueSet) (Function, error)) Function {
+		f, err := fn(AWSCurrentTime, NewValueSet(NewStringValue("2020-06-30T00:00:00Z")))
+		if err != nil {
+			t.Fatalf("unexpected error. %v\n", err)
+		}
+		return f
+	}
+	lessThan := newFunc(newDateLessThanFunc)
+	lessThanEquals := newFunc(newDateLessThanEqualsFunc)
+	greaterThan := newFunc(newDateGreaterThanFunc)
+	equals := newFunc(newDateEqualsFunc)
+	notEquals := newFunc(newDateNotEqualsFunc)
+
+	before := map[string][]string{"CurrentTime": {"2020-06-29T23:59:59.000Z"}}
+	at := map[string][]string{"CurrentTime": {"2020-06-30T00:00:00.000Z"}}
+	after := map[string][]string{"CurrentTime": {"2020-06-30T00:00:01.000Z"}}
+
+	testCases := []struct {
+		function       Function
+		values         map[string][]string
+		expectedResult bool
+	}{
+		{lessThan, before, true},
+		{lessThan, at, false},
+		{lessThanEquals, at, true},
+		{lessThanEquals, after, false},
+		{greaterThan, after, true},
+		{greaterThan, at, false},
+		{equals, at, true},
+		{equals, before, false},
+		{notEquals, before, true},
+		{notEquals, at, false},
+		{lessThan, map[string][]string{}, false},
+		{lessThan, map[string][]string{"CurrentTime": {"yesterday"}}, false},
+	}
+
+	for i, testCase := range testCases {
+		result := testCase.function.evaluate(testCase.values)
+
+		if result != testCase.expectedResult {
+			t.Errorf("case %v: expected: %v, got: %v\n", i+1, testCase.expectedResult, result)
+		}
+	}
+}
+
+func TestNewDateFunc(t *testing.T) {
+	testCases := []struct {
+		key       Key
+		values    ValueSet
+		expectErr bool
+	}{
+		{AWSCurrentTime, NewValueSet(NewStringValue("2020-06-30T00:00:00Z")), false},
+		// Only the current time is a date.
+		{S3Prefix, NewValueSet(NewStringValue("2020-06-30T00:00:00Z")), true},
+		// Invalid date.
+		{AWSCurrentTime, NewValueSet(NewStringValue("2020-06-30")), true},
+		// Multiple dates.
+		{AWSCurrentTime, NewValueSet(NewStringValue("2020-06-30T00:00:00Z"), NewStringValue("2020-07-30T00:00:00Z")), true},
+		{AWSCurrentTime, NewValueSet(NewIntValue(7)), true},
+	}
+
+	for i, testCase := range testCases {
+		_, err := newDateLessThanFunc(testCase.key, testCase.values)
+		expectErr := (err != nil)
+
+		if expectErr != testCase.expectErr {
+			t.Fatalf("case %v: error: expected: %v, got: %v\n", i+1, testCase.expectErr, expectErr)
+		}
+	}
+}
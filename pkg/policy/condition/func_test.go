@@ -268,7 +268,6 @@ func TestFunctionsUnmarshalJSON(t *testing.T) {
 
 	case3Data := []byte(`{}`)
 
-	// Remove this test after supporting date conditions.
 	case4Data := []byte(`{
 "DateEquals": { "aws:CurrentTime": "2013-06-30T00:00:00Z" }
 }`)
@@ -325,6 +324,11 @@ func TestFunctionsUnmarshalJSON(t *testing.T) {
 		t.Fatalf("unexpected error. %v\n", err)
 	}
 
+	func8, err := newDateEqualsFunc(AWSCurrentTime, NewValueSet(NewStringValue("2013-06-30T00:00:00Z")))
+	if err != nil {
+		t.Fatalf("unexpected error. %v\n", err)
+	}
+
 	testCases := []struct {
 		data           []byte
 		expectedResult Functions
@@ -336,8 +340,8 @@ func TestFunctionsUnmarshalJSON(t *testing.T) {
 		{case2Data, NewFunctions(func6), false},
 		// empty condition error.
 		{case3Data, nil, true},
-		// unsupported condition error.
-		{case4Data, nil, true},
+		// Success case, date condition.
+		{case4Data, NewFunctions(func8), false},
 		// Success case multiple keys, same condition.
 		{case5Data, NewFunctions(func1, func2_1, func2_2, func2_3, func3, func4, func5, func6, func7), false},
 	}
@@ -44,30 +44,37 @@ type Policy struct {
 
 // IsAllowed - checks given policy args is allowed to continue the Rest API.
 func (policy Policy) IsAllowed(args Args) bool {
+	allowed, _ := policy.Evaluate(args)
+	return allowed
+}
+
+// Evaluate - checks given policy args is allowed like IsAllowed, also
+// returns the index of the statement which decided, -1 if none did.
+func (policy Policy) Evaluate(args Args) (bool, int) {
 	// Check all deny statements. If any one statement denies, return false.
-	for _, statement := range policy.Statements {
+	for i, statement := range policy.Statements {
 		if statement.Effect == Deny {
 			if !statement.IsAllowed(args) {
-				return false
+				return false, i
 			}
 		}
 	}
 
 	// For owner, its allowed by default.
 	if args.IsOwner {
-		return true
+		return true, -1
 	}
 
 	// Check all allow statements. If any one statement allows, return true.
-	for _, statement := range policy.Statements {
+	for i, statement := range policy.Statements {
 		if statement.Effect == Allow {
 			if statement.IsAllowed(args) {
-				return true
+				return true, i
 			}
 		}
 	}
 
-	return false
+	return false, -1
 }
 
 // IsEmpty - returns whether policy is empty or not.
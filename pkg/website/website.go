@@ -0,0 +1,229 @@
+/*
+ * MinIO Cloud Storage, (C) 2019 MinIO, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package website
+
+import (
+	"encoding/xml"
+	"errors"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// Maximum number of routing rules of a website configuration.
+const maxRoutingRules = 50
+
+var (
+	errTooManyRoutingRules    = errors.New("Website configuration allows a maximum of 50 routing rules")
+	errNoIndexDocument        = errors.New("Website configuration should have an index document")
+	errInvalidIndexDocument   = errors.New("Index document suffix should not be empty or contain a slash")
+	errInvalidErrorDocument   = errors.New("Error document key should not be empty")
+	errRedirectAllExclusive   = errors.New("Website configuration redirecting all requests should not have other elements")
+	errNoRedirectHostName     = errors.New("Redirecting all requests requires a host name")
+	errInvalidProtocol        = errors.New("Redirect protocol should be http or https")
+	errNoRedirect             = errors.New("Routing rule should redirect to a different location")
+	errReplaceKeyExclusive    = errors.New("Routing rule can replace either the key or the key prefix, not both")
+	errInvalidRedirectCode    = errors.New("Routing rule redirect code should be a 3XX HTTP status code")
+	errInvalidErrorCodeReturn = errors.New("Routing rule condition error code should be a 4XX or 5XX HTTP status code")
+)
+
+// Config - bucket website configuration as per
+// https://docs.aws.amazon.com/AmazonS3/latest/API/RESTBucketPUTwebsite.html
+type Config struct {
+	XMLName               xml.Name               `xml:"WebsiteConfiguration"`
+	XMLNS                 string                 `xml:"xmlns,attr,omitempty"`
+	RedirectAllRequestsTo *RedirectAllRequestsTo `xml:"RedirectAllRequestsTo,omitempty"`
+	IndexDocument         *IndexDocument         `xml:"IndexDocument,omitempty"`
+	ErrorDocument         *ErrorDocument         `xml:"ErrorDocument,omitempty"`
+	RoutingRules          []RoutingRule          `xml:"RoutingRules>RoutingRule,omitempty"`
+}
+
+// RedirectAllRequestsTo - redirects all the requests of the website
+// endpoint of a bucket to another host.
+type RedirectAllRequestsTo struct {
+	HostName string `xml:"HostName"`
+	Protocol string `xml:"Protocol,omitempty"`
+}
+
+// IndexDocument - the object served for requests to a directory,
+// the suffix is appended to the requested key.
+type IndexDocument struct {
+	Suffix string `xml:"Suffix"`
+}
+
+// ErrorDocument - the object served when a request fails.
+type ErrorDocument struct {
+	Key string `xml:"Key"`
+}
+
+// RoutingRule - redirects the requests matching its condition.
+type RoutingRule struct {
+	Condition *Condition `xml:"Condition,omitempty"`
+	Redirect  Redirect   `xml:"Redirect"`
+}
+
+// Condition - a routing rule condition, requests match it if their key
+// has the prefix and, if set, if they failed with the HTTP error code.
+type Condition struct {
+	KeyPrefixEquals             string `xml:"KeyPrefixEquals,omitempty"`
+	HTTPErrorCodeReturnedEquals int    `xml:"HttpErrorCodeReturnedEquals,omitempty"`
+}
+
+// Redirect - the location requests matching a routing rule are
+// redirected to, unset fields are taken from the request.
+type Redirect struct {
+	HostName             string `xml:"HostName,omitempty"`
+	Protocol             string `xml:"Protocol,omitempty"`
+	ReplaceKeyPrefixWith string `xml:"ReplaceKeyPrefixWith,omitempty"`
+	ReplaceKeyWith       string `xml:"ReplaceKeyWith,omitempty"`
+	HTTPRedirectCode     int    `xml:"HttpRedirectCode,omitempty"`
+}
+
+// ParseConfig - parses data in given reader to Config.
+func ParseConfig(reader io.Reader) (*Config, error) {
+	var config Config
+	if err := xml.NewDecoder(reader).Decode(&config); err != nil {
+		return nil, err
+	}
+	if err := config.Validate(); err != nil {
+		return nil, err
+	}
+	return &config, nil
+}
+
+func validateProtocol(protocol string) error {
+	switch protocol {
+	case "", "http", "https":
+		return nil
+	}
+	return errInvalidProtocol
+}
+
+// Validate - validates the website configuration.
+func (config Config) Validate() error {
+	if redirect := config.RedirectAllRequestsTo; redirect != nil {
+		if config.IndexDocument != nil || config.ErrorDocument != nil || len(config.RoutingRules) > 0 {
+			return errRedirectAllExclusive
+		}
+		if redirect.HostName == "" {
+			return errNoRedirectHostName
+		}
+		return validateProtocol(redirect.Protocol)
+	}
+	if config.IndexDocument == nil {
+		return errNoIndexDocument
+	}
+	if suffix := config.IndexDocument.Suffix; suffix == "" || strings.Contains(suffix, "/") {
+		return errInvalidIndexDocument
+	}
+	if config.ErrorDocument != nil && config.ErrorDocument.Key == "" {
+		return errInvalidErrorDocument
+	}
+	if len(config.RoutingRules) > maxRoutingRules {
+		return errTooManyRoutingRules
+	}
+	for _, rule := range config.RoutingRules {
+		if err := rule.Validate(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Validate - validates the routing rule.
+func (rule RoutingRule) Validate() error {
+	if rule.Condition != nil {
+		if code := rule.Condition.HTTPErrorCodeReturnedEquals; code != 0 && (code < 400 || code > 599) {
+			return errInvalidErrorCodeReturn
+		}
+	}
+	redirect := rule.Redirect
+	if redirect == (Redirect{}) {
+		return errNoRedirect
+	}
+	if redirect.ReplaceKeyPrefixWith != "" && redirect.ReplaceKeyWith != "" {
+		return errReplaceKeyExclusive
+	}
+	if code := redirect.HTTPRedirectCode; code != 0 && (code < 300 || code > 399) {
+		return errInvalidRedirectCode
+	}
+	return validateProtocol(redirect.Protocol)
+}
+
+// Match - returns the first routing rule matching a request for key
+// which failed with the HTTP error code, which is zero for requests
+// not yet served. Rules without error code condition match requests
+// before they are served, the other rules match failed requests.
+func (config Config) Match(key string, errorCode int) (RoutingRule, bool) {
+	for _, rule := range config.RoutingRules {
+		condition := Condition{}
+		if rule.Condition != nil {
+			condition = *rule.Condition
+		}
+		if condition.HTTPErrorCodeReturnedEquals != errorCode {
+			continue
+		}
+		if strings.HasPrefix(key, condition.KeyPrefixEquals) {
+			return rule, true
+		}
+	}
+	return RoutingRule{}, false
+}
+
+// Location - returns the location a request for key made with scheme
+// to host is redirected to by the routing rule.
+func (rule RoutingRule) Location(key, scheme, host string) string {
+	redirect := rule.Redirect
+	switch {
+	case redirect.ReplaceKeyWith != "":
+		key = redirect.ReplaceKeyWith
+	case redirect.ReplaceKeyPrefixWith != "":
+		prefix := ""
+		if rule.Condition != nil {
+			prefix = rule.Condition.KeyPrefixEquals
+		}
+		key = redirect.ReplaceKeyPrefixWith + strings.TrimPrefix(key, prefix)
+	}
+	if redirect.Protocol != "" {
+		scheme = redirect.Protocol
+	}
+	if redirect.HostName != "" {
+		host = redirect.HostName
+	}
+	u := url.URL{Scheme: scheme, Host: host, Path: "/" + key}
+	return u.String()
+}
+
+// StatusCode - returns the HTTP status code of the redirects of the
+// routing rule, which defaults to 301.
+func (rule RoutingRule) StatusCode() int {
+	if rule.Redirect.HTTPRedirectCode != 0 {
+		return rule.Redirect.HTTPRedirectCode
+	}
+	return http.StatusMovedPermanently
+}
+
+// Location - returns the location a request for key made with scheme
+// is redirected to.
+func (redirect RedirectAllRequestsTo) Location(key, scheme string) string {
+	if redirect.Protocol != "" {
+		scheme = redirect.Protocol
+	}
+	u := url.URL{Scheme: scheme, Host: redirect.HostName, Path: "/" + key}
+	return u.String()
+}
@@ -0,0 +1,110 @@
+/*
+ * MinIO Cloud Storage, (C) 2019 MinIO, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package website
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestParseConfig(t *testing.T) {
+	testCases := []struct {
+		input     string
+		expectErr bool
+	}{
+		{`<WebsiteConfiguration><IndexDocument><Suffix>index.html</Suffix></IndexDocument></WebsiteConfiguration>`, false},
+		{`<WebsiteConfiguration><IndexDocument><Suffix>index.html</Suffix></IndexDocument><ErrorDocument><Key>error.html</Key></ErrorDocument><RoutingRules><RoutingRule><Condition><KeyPrefixEquals>docs/</KeyPrefixEquals></Condition><Redirect><ReplaceKeyPrefixWith>documents/</ReplaceKeyPrefixWith></Redirect></RoutingRule></RoutingRules></WebsiteConfiguration>`, false},
+		{`<WebsiteConfiguration><RedirectAllRequestsTo><HostName>example.com</HostName><Protocol>https</Protocol></RedirectAllRequestsTo></WebsiteConfiguration>`, false},
+		// No index document.
+		{`<WebsiteConfiguration></WebsiteConfiguration>`, true},
+		// Index document suffix with a slash.
+		{`<WebsiteConfiguration><IndexDocument><Suffix>a/index.html</Suffix></IndexDocument></WebsiteConfiguration>`, true},
+		// Empty error document key.
+		{`<WebsiteConfiguration><IndexDocument><Suffix>index.html</Suffix></IndexDocument><ErrorDocument></ErrorDocument></WebsiteConfiguration>`, true},
+		// Redirect all requests with an index document.
+		{`<WebsiteConfiguration><RedirectAllRequestsTo><HostName>example.com</HostName></RedirectAllRequestsTo><IndexDocument><Suffix>index.html</Suffix></IndexDocument></WebsiteConfiguration>`, true},
+		// Redirect all requests without host name.
+		{`<WebsiteConfiguration><RedirectAllRequestsTo><Protocol>https</Protocol></RedirectAllRequestsTo></WebsiteConfiguration>`, true},
+		// Unsupported protocol.
+		{`<WebsiteConfiguration><RedirectAllRequestsTo><HostName>example.com</HostName><Protocol>ftp</Protocol></RedirectAllRequestsTo></WebsiteConfiguration>`, true},
+		// Routing rule without redirect.
+		{`<WebsiteConfiguration><IndexDocument><Suffix>index.html</Suffix></IndexDocument><RoutingRules><RoutingRule><Redirect></Redirect></RoutingRule></RoutingRules></WebsiteConfiguration>`, true},
+		// Routing rule replacing both the key and the key prefix.
+		{`<WebsiteConfiguration><IndexDocument><Suffix>index.html</Suffix></IndexDocument><RoutingRules><RoutingRule><Redirect><ReplaceKeyWith>a</ReplaceKeyWith><ReplaceKeyPrefixWith>b</ReplaceKeyPrefixWith></Redirect></RoutingRule></RoutingRules></WebsiteConfiguration>`, true},
+		// Routing rule with an invalid redirect code.
+		{`<WebsiteConfiguration><IndexDocument><Suffix>index.html</Suffix></IndexDocument><RoutingRules><RoutingRule><Redirect><HttpRedirectCode>200</HttpRedirectCode></Redirect></RoutingRule></RoutingRules></WebsiteConfiguration>`, true},
+		// Routing rule with an invalid error code condition.
+		{`<WebsiteConfiguration><IndexDocument><Suffix>index.html</Suffix></IndexDocument><RoutingRules><RoutingRule><Condition><HttpErrorCodeReturnedEquals>200</HttpErrorCodeReturnedEquals></Condition><Redirect><HostName>example.com</HostName></Redirect></RoutingRule></RoutingRules></WebsiteConfiguration>`, true},
+		// Malformed XML.
+		{`<WebsiteConfiguration><IndexDocument>`, true},
+	}
+	for i, testCase := range testCases {
+		_, err := ParseConfig(strings.NewReader(testCase.input))
+		if (err != nil) != testCase.expectErr {
+			t.Errorf("Test %d: expected error %t, got %v", i+1, testCase.expectErr, err)
+		}
+	}
+}
+
+func TestConfigMatch(t *testing.T) {
+	config := Config{
+		IndexDocument: &IndexDocument{Suffix: "index.html"},
+		RoutingRules: []RoutingRule{
+			{
+				Condition: &Condition{KeyPrefixEquals: "docs/"},
+				Redirect:  Redirect{ReplaceKeyPrefixWith: "documents/"},
+			},
+			{
+				Condition: &Condition{HTTPErrorCodeReturnedEquals: 404},
+				Redirect:  Redirect{HostName: "example.com", Protocol: "https", ReplaceKeyWith: "404.html", HTTPRedirectCode: 302},
+			},
+		},
+	}
+
+	testCases := []struct {
+		key        string
+		errorCode  int
+		match      bool
+		location   string
+		statusCode int
+	}{
+		{"docs/a.html", 0, true, "http://bucket.website.local/documents/a.html", 301},
+		{"images/a.png", 0, false, "", 0},
+		{"images/a.png", 404, true, "https://example.com/404.html", 302},
+		{"images/a.png", 403, false, "", 0},
+	}
+	for i, testCase := range testCases {
+		rule, ok := config.Match(testCase.key, testCase.errorCode)
+		if ok != testCase.match {
+			t.Fatalf("Test %d: expected match %t, got %t", i+1, testCase.match, ok)
+		}
+		if !ok {
+			continue
+		}
+		if location := rule.Location(testCase.key, "http", "bucket.website.local"); location != testCase.location {
+			t.Errorf("Test %d: expected location %s, got %s", i+1, testCase.location, location)
+		}
+		if code := rule.StatusCode(); code != testCase.statusCode {
+			t.Errorf("Test %d: expected status code %d, got %d", i+1, testCase.statusCode, code)
+		}
+	}
+
+	redirect := RedirectAllRequestsTo{HostName: "example.com"}
+	if location := redirect.Location("a b.html", "https"); location != "https://example.com/a%20b.html" {
+		t.Errorf("Expected location https://example.com/a%%20b.html, got %s", location)
+	}
+}